@@ -11,6 +11,8 @@ import (
 )
 
 var (
+	// TaskTypeAggregate is the identifier for the Aggregate adapter.
+	TaskTypeAggregate = models.MustNewTaskType("aggregate")
 	// TaskTypeCopy is the identifier for the Copy adapter.
 	TaskTypeCopy = models.MustNewTaskType("copy")
 	// TaskTypeEthBool is the identifier for the EthBool adapter.
@@ -21,6 +23,8 @@ var (
 	TaskTypeEthInt256 = models.MustNewTaskType("ethint256")
 	// TaskTypeEthUint256 is the identifier for the EthUint256 adapter.
 	TaskTypeEthUint256 = models.MustNewTaskType("ethuint256")
+	// TaskTypeEthCall is the identifier for the EthCall adapter.
+	TaskTypeEthCall = models.MustNewTaskType("ethcall")
 	// TaskTypeEthTx is the identifier for the EthTx adapter.
 	TaskTypeEthTx = models.MustNewTaskType("ethtx")
 	// TaskTypeEthTxABIEncode is the identifier for the EthTxABIEncode adapter.
@@ -35,12 +39,16 @@ var (
 	TaskTypeHTTPPost = models.MustNewTaskType("httppost")
 	// TaskTypeJSONParse is the identifier for the JSONParse adapter.
 	TaskTypeJSONParse = models.MustNewTaskType("jsonparse")
+	// TaskTypeKafkaPublish is the identifier for the KafkaPublish adapter.
+	TaskTypeKafkaPublish = models.MustNewTaskType("kafkapublish")
 	// TaskTypeMultiply is the identifier for the Multiply adapter.
 	TaskTypeMultiply = models.MustNewTaskType("multiply")
 	// TaskTypeNoOp is the identifier for the NoOp adapter.
 	TaskTypeNoOp = models.MustNewTaskType("noop")
 	// TaskTypeNoOpPend is the identifier for the NoOpPend adapter.
 	TaskTypeNoOpPend = models.MustNewTaskType("nooppend")
+	// TaskTypeSignResult is the identifier for the SignResult adapter.
+	TaskTypeSignResult = models.MustNewTaskType("signresult")
 	// TaskTypeSleep is the identifier for the Sleep adapter.
 	TaskTypeSleep = models.MustNewTaskType("sleep")
 	// TaskTypeWasm is the wasm interpereter adapter
@@ -49,8 +57,12 @@ var (
 	TaskTypeRandom = models.MustNewTaskType("random")
 	// TaskTypeCompare is the identifier for the Compare adapter.
 	TaskTypeCompare = models.MustNewTaskType("compare")
+	// TaskTypeCompute is the identifier for the Compute adapter.
+	TaskTypeCompute = models.MustNewTaskType("compute")
 	// TaskTypeQuotient is the identifier for the Quotient adapter.
 	TaskTypeQuotient = models.MustNewTaskType("quotient")
+	// TaskTypeTransform is the identifier for the Transform adapter.
+	TaskTypeTransform = models.MustNewTaskType("transform")
 )
 
 // BaseAdapter is the minimum interface required to create an adapter. Only core
@@ -79,90 +91,97 @@ func (p PipelineAdapter) MinPayment() *assets.Link {
 
 // For determines the adapter type to use for a given task.
 func For(task models.TaskSpec, config orm.ConfigReader, orm *orm.ORM) (*PipelineAdapter, error) {
-	var ba BaseAdapter
-	var err error
 	mic := config.MinIncomingConfirmations()
 	var mp *assets.Link
 
+	ba, builtin, err := ForBuiltin(task)
+	if plugin, ok := forPlugin(task); !builtin && ok {
+		ba = plugin
+	} else if !builtin {
+		bt, e := orm.FindBridge(task.Type)
+		if e != nil {
+			return nil, fmt.Errorf("%s is not a supported adapter type", task.Type)
+		} else if bt.Mode == models.BridgeModeStream {
+			return nil, fmt.Errorf("%s is a stream bridge, which can only be used by stream initiators", task.Type)
+		}
+		b := Bridge{BridgeType: bt, Params: task.Params}
+		ba = &b
+		mp = bt.MinimumContractPayment
+		mic = b.Confirmations
+	}
+
+	pa := &PipelineAdapter{
+		BaseAdapter: ba,
+		minConfs:    mic,
+		minPayment:  mp,
+	}
+
+	return pa, err
+}
+
+// ForBuiltin returns the adapter of a task whose type is one of the node's
+// own adapters, with its params, and false if the type is not one of them,
+// the task then calling a bridge. It needs no database, so specs can be
+// checked before they are sent to a node.
+func ForBuiltin(task models.TaskSpec) (BaseAdapter, bool, error) {
+	var ba BaseAdapter
 	switch task.Type {
+	case TaskTypeAggregate:
+		ba = &Aggregate{}
 	case TaskTypeCopy:
 		ba = &Copy{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeEthBool:
 		ba = &EthBool{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeEthBytes32:
 		ba = &EthBytes32{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeEthInt256:
 		ba = &EthInt256{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeEthUint256:
 		ba = &EthUint256{}
-		err = unmarshalParams(task.Params, ba)
+	case TaskTypeEthCall:
+		ba = &EthCall{}
 	case TaskTypeEthTx:
 		ba = &EthTx{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeEthTxABIEncode:
 		ba = &EthTxABIEncode{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeHTTPGetWithUnrestrictedNetworkAccess:
 		ba = &HTTPGet{AllowUnrestrictedNetworkAccess: true}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeHTTPPostWithUnrestrictedNetworkAccess:
 		ba = &HTTPPost{AllowUnrestrictedNetworkAccess: true}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeHTTPGet:
 		ba = &HTTPGet{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeHTTPPost:
 		ba = &HTTPPost{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeJSONParse:
 		ba = &JSONParse{}
-		err = unmarshalParams(task.Params, ba)
+	case TaskTypeKafkaPublish:
+		ba = &KafkaPublish{}
 	case TaskTypeMultiply:
 		ba = &Multiply{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeNoOp:
 		ba = &NoOp{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeNoOpPend:
 		ba = &NoOpPend{}
-		err = unmarshalParams(task.Params, ba)
+	case TaskTypeSignResult:
+		ba = &SignResult{}
 	case TaskTypeSleep:
 		ba = &Sleep{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeWasm:
 		ba = &Wasm{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeRandom:
 		ba = &Random{}
-		err = unmarshalParams(task.Params, ba)
 	case TaskTypeCompare:
 		ba = &Compare{}
-		err = unmarshalParams(task.Params, ba)
+	case TaskTypeCompute:
+		ba = &Compute{}
 	case TaskTypeQuotient:
 		ba = &Quotient{}
-		err = unmarshalParams(task.Params, ba)
+	case TaskTypeTransform:
+		ba = &Transform{}
 	default:
-		bt, e := orm.FindBridge(task.Type)
-		if e != nil {
-			return nil, fmt.Errorf("%s is not a supported adapter type", task.Type)
-		}
-		b := Bridge{BridgeType: bt, Params: task.Params}
-		ba = &b
-		mp = bt.MinimumContractPayment
-		mic = b.Confirmations
+		return nil, false, nil
 	}
-
-	pa := &PipelineAdapter{
-		BaseAdapter: ba,
-		minConfs:    mic,
-		minPayment:  mp,
-	}
-
-	return pa, err
+	return ba, true, unmarshalParams(task.Params, ba)
 }
 
 func unmarshalParams(params models.JSON, dst interface{}) error {
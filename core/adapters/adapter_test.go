@@ -31,6 +31,9 @@ func TestAdapterFor(t *testing.T) {
 	_, bt := cltest.NewBridgeType(t, "rideShare", "https://dUber.eth")
 	bt.MinimumContractPayment = assets.NewLink(10)
 	assert.Nil(t, store.CreateBridgeType(bt))
+	_, streamBridge := cltest.NewBridgeType(t, "rideStream", "wss://dUber.eth")
+	streamBridge.Mode = models.BridgeModeStream
+	assert.Nil(t, store.CreateBridgeType(streamBridge))
 
 	cases := []struct {
 		name        string
@@ -43,6 +46,7 @@ func TestAdapterFor(t *testing.T) {
 		{"ethtx", "EthTx", "*adapters.EthTx", false},
 		{"bridge mixed case", "rideShare", "*adapters.Bridge", false},
 		{"bridge lower case", "rideshare", "*adapters.Bridge", false},
+		{"stream bridge", "rideStream", "<nil>", true},
 	}
 
 	for _, test := range cases {
@@ -0,0 +1,207 @@
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+)
+
+// AggregateMethod is the way the values fetched by an Aggregate task are
+// reduced to its result.
+type AggregateMethod string
+
+const (
+	// AggregateMedian takes the middle value, or the mean of the two middle
+	// values when there is an even number of them.
+	AggregateMedian = AggregateMethod("median")
+	// AggregateMean takes the mean of the values.
+	AggregateMean = AggregateMethod("mean")
+	// AggregateMode takes the most common value, or the lowest of the most
+	// common values when there is a tie.
+	AggregateMode = AggregateMethod("mode")
+)
+
+// AggregateSource is a single source of an Aggregate task, either a URL
+// fetched with a GET request or a bridge. Path picks the value out of the
+// response, as for JSONParse.
+type AggregateSource struct {
+	URL    models.WebURL   `json:"url,omitempty"`
+	Bridge models.TaskType `json:"bridge,omitempty"`
+	Path   JSONPath        `json:"path,omitempty"`
+}
+
+// String returns the URL or bridge name of the source.
+func (src AggregateSource) String() string {
+	if src.Bridge != "" {
+		return src.Bridge.String()
+	}
+	return src.URL.String()
+}
+
+// Aggregate fetches a value from each of its sources concurrently, and
+// reduces them to a single result, allowing any job to medianize across data
+// sources.
+type Aggregate struct {
+	Sources []AggregateSource `json:"sources"`
+	Method  AggregateMethod   `json:"method,omitempty"`
+	// MinResponses is the quorum of sources that must respond for the task
+	// to succeed. It defaults to a majority of the sources.
+	MinResponses uint `json:"minResponses,omitempty"`
+	// Trim is the fraction of values dropped from each end, once sorted,
+	// before aggregating, to keep outliers from skewing the result.
+	Trim decimal.Decimal `json:"trim"`
+}
+
+// TaskType returns the type of Adapter.
+func (a *Aggregate) TaskType() models.TaskType {
+	return TaskTypeAggregate
+}
+
+// Perform fetches every source, erroring if fewer than the quorum respond
+// with a number, and returns the aggregate of the values fetched.
+func (a *Aggregate) Perform(input models.RunInput, store *store.Store) models.RunOutput {
+	if len(a.Sources) == 0 {
+		return models.NewRunOutputError(errors.New("aggregate task has no sources"))
+	}
+
+	values := make([]*decimal.Decimal, len(a.Sources))
+	fetchErrors := make([]error, len(a.Sources))
+	var wg sync.WaitGroup
+	for i, src := range a.Sources {
+		wg.Add(1)
+		go func(i int, src AggregateSource) {
+			defer wg.Done()
+			value, err := a.fetch(src, input, store)
+			if err != nil {
+				fetchErrors[i] = errors.Wrapf(err, "aggregate source %s", src)
+				logger.Warnw("Unable to fetch aggregate source", "source", src.String(), "error", err)
+				return
+			}
+			values[i] = &value
+		}(i, src)
+	}
+	wg.Wait()
+
+	fetched := []decimal.Decimal{}
+	for _, v := range values {
+		if v != nil {
+			fetched = append(fetched, *v)
+		}
+	}
+	if quorum := a.quorum(); uint(len(fetched)) < quorum {
+		err := errors.Wrapf(multierr.Combine(fetchErrors...),
+			"only %d of %d aggregate sources responded, %d required", len(fetched), len(a.Sources), quorum)
+		return models.NewRunOutputError(err)
+	}
+
+	result, err := a.aggregate(fetched)
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+	return models.NewRunOutputCompleteWithResult(result.String())
+}
+
+// quorum returns the number of sources that must respond.
+func (a *Aggregate) quorum() uint {
+	if a.MinResponses > 0 {
+		return a.MinResponses
+	}
+	return uint(len(a.Sources))/2 + 1
+}
+
+// fetch runs the task for a source against the input, returning the number
+// at the source's path in the response.
+func (a *Aggregate) fetch(src AggregateSource, input models.RunInput, store *store.Store) (decimal.Decimal, error) {
+	var output models.RunOutput
+	srcInput := *models.NewRunInput(input.JobRunID(), input.Data(), models.RunStatusUnstarted)
+	if src.Bridge != "" {
+		bt, err := store.FindBridge(src.Bridge)
+		if err != nil {
+			return decimal.Decimal{}, errors.Wrap(err, "finding bridge")
+		} else if bt.Mode == models.BridgeModeStream {
+			return decimal.Decimal{}, fmt.Errorf("%s is a stream bridge, which cannot be fetched", bt.Name)
+		}
+		// The run cannot wait for a source, so the bridge is not given a
+		// response URL to call it back at.
+		bridge := Bridge{BridgeType: bt}
+		output = bridge.fetch(srcInput, store)
+	} else {
+		adapter := HTTPGet{URL: src.URL}
+		output = adapter.Perform(srcInput, store)
+	}
+
+	if output.HasError() {
+		return decimal.Decimal{}, output.Error()
+	} else if !output.Status().Completed() {
+		return decimal.Decimal{}, fmt.Errorf("source responded with status %s, only completed responses can be aggregated", output.Status())
+	}
+
+	if len(src.Path) > 0 {
+		jp := JSONParse{Path: src.Path}
+		output = jp.Perform(*models.NewRunInput(input.JobRunID(), output.Data(), output.Status()), store)
+		if output.HasError() {
+			return decimal.Decimal{}, output.Error()
+		}
+	}
+
+	value, err := decimal.NewFromString(output.Result().String())
+	if err != nil {
+		return decimal.Decimal{}, errors.Wrapf(err, "parsing %q as a number", output.Result().String())
+	}
+	return value, nil
+}
+
+// aggregate trims the outliers from the values and reduces the rest with the
+// task's method.
+func (a *Aggregate) aggregate(values []decimal.Decimal) (decimal.Decimal, error) {
+	if len(values) == 0 {
+		return decimal.Decimal{}, errors.New("no values to aggregate")
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].LessThan(values[j])
+	})
+	trim := int(a.Trim.Mul(decimal.NewFromInt(int64(len(values)))).IntPart())
+	if 2*trim >= len(values) {
+		return decimal.Decimal{}, fmt.Errorf("trimming %s of %d values from each end leaves none", a.Trim, len(values))
+	}
+	values = values[trim : len(values)-trim]
+
+	switch a.Method {
+	case AggregateMedian, "":
+		k := len(values) / 2
+		if len(values)%2 == 1 {
+			return values[k], nil
+		}
+		return values[k].Add(values[k-1]).Div(decimal.NewFromInt(2)), nil
+	case AggregateMean:
+		sum := decimal.Zero
+		for _, v := range values {
+			sum = sum.Add(v)
+		}
+		return sum.Div(decimal.NewFromInt(int64(len(values)))), nil
+	case AggregateMode:
+		mode, modeCount := values[0], 0
+		for i := 0; i < len(values); {
+			j := i
+			for j < len(values) && values[j].Equal(values[i]) {
+				j++
+			}
+			if j-i > modeCount {
+				mode, modeCount = values[i], j-i
+			}
+			i = j
+		}
+		return mode, nil
+	default:
+		return decimal.Decimal{}, fmt.Errorf("unknown aggregate method %s", a.Method)
+	}
+}
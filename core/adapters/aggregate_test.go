@@ -0,0 +1,130 @@
+package adapters_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func aggregateSources(t *testing.T, responses ...string) ([]adapters.AggregateSource, func()) {
+	sources := make([]adapters.AggregateSource, len(responses))
+	cleanups := make([]func(), len(responses))
+	for i, response := range responses {
+		status := http.StatusOK
+		if response == "" {
+			status = http.StatusInternalServerError
+		}
+		mock, cleanup := cltest.NewHTTPMockServer(t, status, "GET", response)
+		sources[i] = adapters.AggregateSource{URL: cltest.WebURL(t, mock.URL), Path: []string{"price"}}
+		cleanups[i] = cleanup
+	}
+	return sources, func() {
+		for _, cleanup := range cleanups {
+			cleanup()
+		}
+	}
+}
+
+func aggregateStore() *store.Store {
+	cfg := orm.NewConfig()
+	cfg.Set("HTTP_ALLOWED_HOSTS", "127.0.0.1")
+	cfg.Set("MAX_HTTP_ATTEMPTS", "1")
+	return &store.Store{Config: cfg}
+}
+
+func TestAggregate_Perform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		responses    []string
+		method       adapters.AggregateMethod
+		minResponses uint
+		trim         string
+		want         string
+		wantErrored  bool
+	}{
+		{"median of odd", []string{`{"price":3}`, `{"price":1}`, `{"price":2}`}, "", 0, "0", "2", false},
+		{"median of even", []string{`{"price":4}`, `{"price":1}`, `{"price":2}`, `{"price":3}`}, adapters.AggregateMedian, 0, "0", "2.5", false},
+		{"mean", []string{`{"price":1}`, `{"price":2}`, `{"price":6}`}, adapters.AggregateMean, 0, "0", "3", false},
+		{"mode", []string{`{"price":"1.5"}`, `{"price":2}`, `{"price":"2.0"}`, `{"price":1.5}`, `{"price":2}`}, adapters.AggregateMode, 0, "0", "2", false},
+		{"mode tie takes lowest", []string{`{"price":2}`, `{"price":1}`}, adapters.AggregateMode, 0, "0", "1", false},
+		{"trims outliers", []string{`{"price":1}`, `{"price":10}`, `{"price":11}`, `{"price":12}`, `{"price":1000}`}, adapters.AggregateMean, 0, "0.2", "11", false},
+		{"majority responds", []string{`{"price":1}`, ``, `{"price":3}`}, "", 0, "0", "2", false},
+		{"majority fails", []string{`{"price":1}`, ``, ``}, "", 0, "0", "", true},
+		{"quorum met", []string{`{"price":1}`, ``, ``}, "", 1, "0", "1", false},
+		{"quorum not met", []string{`{"price":1}`, `{"price":2}`, ``}, "", 3, "0", "", true},
+		{"not a number", []string{`{"price":"cheap"}`}, "", 0, "0", "", true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			sources, cleanup := aggregateSources(t, test.responses...)
+			defer cleanup()
+
+			adapter := adapters.Aggregate{
+				Sources:      sources,
+				Method:       test.method,
+				MinResponses: test.minResponses,
+				Trim:         decimal.RequireFromString(test.trim),
+			}
+			result := adapter.Perform(cltest.NewRunInputWithResult("ETH/USD"), aggregateStore())
+			if test.wantErrored {
+				assert.True(t, result.HasError())
+			} else {
+				require.NoError(t, result.Error())
+				want := decimal.RequireFromString(test.want)
+				got := decimal.RequireFromString(result.Result().String())
+				assert.True(t, want.Equal(got), "want %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestAggregate_Perform_bridgeSource(t *testing.T) {
+	s, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	s.Config.Set("HTTP_ALLOWED_HOSTS", "127.0.0.1")
+
+	bridgeMock, cleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", `{"data":{"result":"5"}}`)
+	defer cleanup()
+	_, bt := cltest.NewBridgeType(t, "pricefeed", bridgeMock.URL)
+	require.NoError(t, s.CreateBridgeType(bt))
+
+	sources, cleanup := aggregateSources(t, `{"price":1}`, `{"price":3}`)
+	defer cleanup()
+	sources = append(sources, adapters.AggregateSource{Bridge: bt.Name})
+
+	adapter := adapters.Aggregate{Sources: sources}
+	result := adapter.Perform(cltest.NewRunInputWithResult("ETH/USD"), s)
+	require.NoError(t, result.Error())
+	assert.Equal(t, "3", result.Result().String())
+}
+
+func TestAggregate_Perform_pendingBridgeSourceFails(t *testing.T) {
+	s, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	s.Config.Set("BRIDGE_RESPONSE_URL", cltest.WebURL(t, "https://chainlink.example.com"))
+
+	bridgeMock, cleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", `{"pending":true}`,
+		func(_ http.Header, body string) {
+			assert.False(t, gjson.Get(body, "responseURL").Exists(), body)
+		})
+	defer cleanup()
+	_, bt := cltest.NewBridgeType(t, "asyncfeed", bridgeMock.URL)
+	require.NoError(t, s.CreateBridgeType(bt))
+
+	adapter := adapters.Aggregate{Sources: []adapters.AggregateSource{{Bridge: bt.Name}}}
+	result := adapter.Perform(cltest.NewRunInputWithResult("ETH/USD"), s)
+	assert.True(t, result.HasError())
+}
@@ -7,12 +7,42 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
+	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/tidwall/gjson"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	promBridgeCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bridge_cache_hits_total",
+		Help: "The number of bridge requests answered from the bridge response cache",
+	},
+		[]string{"bridge"},
+	)
+	promBridgeCacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bridge_cache_misses_total",
+		Help: "The number of bridge requests not found in the bridge response cache",
+	},
+		[]string{"bridge"},
+	)
+
+	// bridgeCalls lets concurrent runs which miss the cache for the same
+	// request share a single call to the bridge.
+	bridgeCalls singleflight.Group
+
+	// pacer hands out the times runs may call bridges with a minimum
+	// interval between requests.
+	pacer = &bridgePacer{next: map[models.TaskType]time.Time{}}
 )
 
 // Bridge adapter is responsible for connecting the task pipeline to external
@@ -20,6 +50,14 @@ import (
 type Bridge struct {
 	models.BridgeType
 	Params models.JSON
+	// RequestParams, when set, are sent to the bridge in place of Params,
+	// with the secrets the job spec references resolved. Unlike Params, they
+	// are never merged into the output of the task, which is saved with the
+	// run.
+	RequestParams *models.JSON
+	// TaskRunID is the task run calling the bridge, the only one it can call
+	// back. Without it, the bridge is not sent a response URL.
+	TaskRunID *models.ID
 }
 
 // TaskType returns the bridges defined type.
@@ -34,14 +72,58 @@ func (ba *Bridge) TaskType() models.TaskType {
 //
 // If the Perform is resumed with a pending RunResult, the RunResult is marked
 // not pending and the RunResult is returned.
+//
+// When the bridge has a minimum interval between requests, a run which would
+// call it too soon after another is put to sleep until its turn, and makes
+// the request when woken up.
 func (ba *Bridge) Perform(input models.RunInput, store *store.Store) models.RunOutput {
 	if input.Status().Completed() {
 		return models.NewRunOutputComplete(input.Data())
 	} else if input.Status().PendingBridge() {
 		return models.NewRunOutputInProgress(input.Data())
+	} else if input.Status().PendingSleep() {
+		data, err := input.Data().Delete(sleepUntilKey)
+		if err != nil {
+			return models.NewRunOutputError(err)
+		}
+		input = *models.NewRunInput(input.JobRunID(), data, input.Status())
+	} else if !ba.MinInterval.IsInstant() {
+		now := store.Clock.Now()
+		if turn := pacer.reserve(ba.Name, ba.MinInterval.Duration(), now); turn.After(now) {
+			logger.Debugw("Pacing bridge request", "bridge", ba.Name.String(), "until", turn)
+			return newRunOutputPendingSleep(models.JSON{}, turn)
+		}
 	}
 	meta := getMeta(store, input.JobRunID())
-	return ba.handleNewRun(input, meta, store.Config.BridgeResponseURL())
+	return ba.handleNewRun(input, meta, store)
+}
+
+// bridgePacer spaces out the requests made to each bridge by at least the
+// bridge's minimum interval.
+type bridgePacer struct {
+	mu   sync.Mutex
+	next map[models.TaskType]time.Time
+}
+
+// reserve returns the earliest time from now that a request may be made to
+// the bridge, keeping the interval after it free for the next request. The
+// bridges whose intervals have passed are forgotten, as are deleted bridges
+// with them, so that only those with requests still to be spaced out are
+// kept.
+func (p *bridgePacer) reserve(name models.TaskType, interval time.Duration, now time.Time) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for bridge, next := range p.next {
+		if !next.After(now) {
+			delete(p.next, bridge)
+		}
+	}
+	turn := now
+	if next := p.next[name]; next.After(now) {
+		turn = next
+	}
+	p.next[name] = turn.Add(interval)
+	return turn
 }
 
 func getMeta(store *store.Store, jobRunID *models.ID) *models.JSON {
@@ -64,24 +146,76 @@ func getMeta(store *store.Store, jobRunID *models.ID) *models.JSON {
 	return &models.JSON{Result: gjson.Parse(meta)}
 }
 
-func (ba *Bridge) handleNewRun(input models.RunInput, meta *models.JSON, bridgeResponseURL *url.URL) models.RunOutput {
+func (ba *Bridge) handleNewRun(input models.RunInput, meta *models.JSON, store *store.Store) models.RunOutput {
 	data, err := models.Merge(input.Data(), ba.Params)
 	if err != nil {
 		return models.NewRunOutputError(baRunResultError("handling data param", err))
 	}
 
-	responseURL := bridgeResponseURL
-	if *responseURL != *zeroURL {
-		responseURL.Path += fmt.Sprintf("/v2/runs/%s", input.JobRunID().String())
+	responseURL := store.Config.BridgeResponseURL()
+	var callback *models.BridgeCallback
+	if *responseURL != *zeroURL && ba.TaskRunID != nil {
+		var token string
+		token, callback, err = ba.newCallback(input.JobRunID(), store)
+		if err != nil {
+			return models.NewRunOutputError(baRunResultError("creating callback", err))
+		}
+		responseURL.Path += fmt.Sprintf("/v2/runs/%s/callback", input.JobRunID().String())
+		query := responseURL.Query()
+		query.Set("token", token)
+		responseURL.RawQuery = query.Encode()
 	}
 
-	body, err := ba.postToExternalAdapter(input, meta, responseURL)
+	body, err := ba.postToExternalAdapter(input, meta, responseURL, store)
+	var output models.RunOutput
+	if err != nil {
+		output = models.NewRunOutputError(baRunResultError("post to external adapter", err))
+	} else {
+		input = *models.NewRunInput(input.JobRunID(), data, input.Status())
+		output = ba.responseToRunResult(body, input)
+	}
+
+	// The callback is only kept for a bridge which will call back.
+	if callback != nil && !output.Status().PendingBridge() {
+		if err := store.DeleteBridgeCallback(callback.ID); err != nil {
+			logger.Errorw("Failed to delete unused bridge callback", "run", input.JobRunID().String(), "error", err)
+		}
+	}
+	return output
+}
+
+// fetch posts the input to the external adapter without a response URL, for
+// the tasks taking data from the bridge, which cannot wait for the bridge to
+// respond asynchronously. A bridge which would respond later is left pending,
+// and a request which would have to be paced errors.
+func (ba *Bridge) fetch(input models.RunInput, store *store.Store) models.RunOutput {
+	if !ba.MinInterval.IsInstant() {
+		now := store.Clock.Now()
+		if turn := pacer.reserve(ba.Name, ba.MinInterval.Duration(), now); turn.After(now) {
+			return models.NewRunOutputError(fmt.Errorf("requests to bridge %s are paced until %s", ba.Name, turn))
+		}
+	}
+
+	data, err := models.Merge(input.Data(), ba.Params)
+	if err != nil {
+		return models.NewRunOutputError(baRunResultError("handling data param", err))
+	}
+	body, err := ba.postToExternalAdapter(input, getMeta(store, input.JobRunID()), nil, store)
 	if err != nil {
 		return models.NewRunOutputError(baRunResultError("post to external adapter", err))
 	}
+	return ba.responseToRunResult(body, *models.NewRunInput(input.JobRunID(), data, input.Status()))
+}
 
-	input = *models.NewRunInput(input.JobRunID(), data, input.Status())
-	return ba.responseToRunResult(body, input)
+// newCallback returns a single-use token for the bridge to call the task run
+// back with, which expires after BRIDGE_CALLBACK_TIMEOUT, and its saved
+// callback.
+func (ba *Bridge) newCallback(jobRunID *models.ID, store *store.Store) (string, *models.BridgeCallback, error) {
+	token, callback, err := models.NewBridgeCallback(jobRunID, ba.TaskRunID, store.Config.BridgeCallbackTimeout().Duration(), store.Clock.Now())
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &callback, store.CreateBridgeCallback(&callback)
 }
 
 func (ba *Bridge) responseToRunResult(body []byte, input models.RunInput) models.RunOutput {
@@ -111,12 +245,140 @@ func (ba *Bridge) responseToRunResult(body []byte, input models.RunInput) models
 	return models.NewRunOutputCompleteWithResult(brr.Data.String())
 }
 
-func (ba *Bridge) postToExternalAdapter(input models.RunInput, meta *models.JSON, bridgeResponseURL *url.URL) ([]byte, error) {
-	data, err := models.Merge(input.Data(), ba.Params)
+func (ba *Bridge) postToExternalAdapter(
+	input models.RunInput,
+	meta *models.JSON,
+	bridgeResponseURL *url.URL,
+	store *store.Store,
+) ([]byte, error) {
+	data, err := models.Merge(input.Data(), ba.requestParams())
 	if err != nil {
 		return nil, errors.Wrap(err, "error merging bridge params with input params")
 	}
 
+	post := func() ([]byte, error) {
+		healthy, err := ba.checkCircuit(store)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		body, err := ba.post(input, data, meta, bridgeResponseURL, store)
+		ba.recordHealth(time.Since(start), err, healthy, store)
+		return body, err
+	}
+	if ba.CacheTTL.IsInstant() {
+		return post()
+	}
+	return ba.postCached(models.BridgeCacheKey(ba.Name, data), post, store)
+}
+
+// requestParams returns the params to send to the bridge.
+func (ba *Bridge) requestParams() models.JSON {
+	if ba.RequestParams != nil {
+		return *ba.RequestParams
+	}
+	return ba.Params
+}
+
+// postCached returns the cached response to the request if there is one,
+// otherwise posting it and caching the response for the bridge's cache TTL.
+// Only completed responses are cached, a pending or errored response being
+// specific to the run that received it.
+func (ba *Bridge) postCached(key string, post func() ([]byte, error), store *store.Store) ([]byte, error) {
+	body, ok, err := store.BridgeCache.Get(key)
+	if err != nil {
+		logger.Warnw("Unable to read bridge response cache", "bridge", ba.Name.String(), "error", err)
+	} else if ok {
+		promBridgeCacheHits.WithLabelValues(ba.Name.String()).Inc()
+		return body, nil
+	}
+	promBridgeCacheMisses.WithLabelValues(ba.Name.String()).Inc()
+
+	type response struct {
+		body      []byte
+		cacheable bool
+	}
+	called := false
+	v, err, _ := bridgeCalls.Do(key, func() (interface{}, error) {
+		called = true
+		body, err := post()
+		if err != nil {
+			return nil, err
+		}
+
+		var brr models.BridgeRunResult
+		cacheable := json.Unmarshal(body, &brr) == nil && brr.Status.Completed()
+		if cacheable {
+			err = store.BridgeCache.Set(key, ba.Name, body, ba.CacheTTL.Duration())
+			logger.ErrorIf(err, "unable to cache bridge response")
+		}
+		return response{body: body, cacheable: cacheable}, nil
+	})
+	if err != nil {
+		return nil, err
+	} else if !v.(response).cacheable && !called {
+		// The response of the shared call only applies to the run which made
+		// it.
+		return post()
+	}
+	return v.(response).body, nil
+}
+
+// checkCircuit returns an error if the bridge's circuit is open, unless the
+// circuit has been open long enough for this request to probe the bridge. It
+// reports whether the bridge had not failed since its last success, as
+// without a circuit breaker.
+func (ba *Bridge) checkCircuit(store *store.Store) (bool, error) {
+	if store.Config.BridgeCircuitBreakerThreshold() == 0 {
+		return true, nil
+	}
+
+	health, err := store.FindBridgeHealth(ba.Name)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		return true, nil
+	} else if err != nil {
+		return false, errors.Wrap(err, "checking bridge health")
+	} else if !health.CircuitOpen() {
+		return health.ConsecutiveFailures == 0, nil
+	}
+
+	probe, err := store.ProbeBridge(ba.Name, store.Config.BridgeCircuitBreakerTimeout().Duration())
+	if err != nil {
+		return false, errors.Wrap(err, "checking bridge health")
+	} else if probe {
+		logger.Infow("Probing bridge for recovery", "bridge", ba.Name.String())
+		return false, nil
+	}
+	return false, fmt.Errorf(
+		"circuit breaker open after %d consecutive failures, last error: %s",
+		health.ConsecutiveFailures, health.LastError.ValueOrZero(),
+	)
+}
+
+// recordHealth records the outcome of a request to the bridge. The successes
+// of a healthy bridge are batched, while those closing its circuit or
+// resetting its failures are recorded at once.
+func (ba *Bridge) recordHealth(latency time.Duration, err error, healthy bool, store *store.Store) {
+	if err == nil && healthy {
+		store.BatchBridgeSuccess(ba.Name, latency)
+		return
+	} else if err == nil {
+		logger.ErrorIf(store.RecordBridgeSuccess(ba.Name, latency), "unable to record bridge health")
+		return
+	}
+	threshold := store.Config.BridgeCircuitBreakerThreshold()
+	logger.ErrorIf(store.RecordBridgeFailure(ba.Name, latency, err, threshold), "unable to record bridge health")
+}
+
+// post sends the request data to the bridge, returning the body of its
+// response.
+func (ba *Bridge) post(
+	input models.RunInput,
+	data models.JSON,
+	meta *models.JSON,
+	bridgeResponseURL *url.URL,
+	store *store.Store,
+) ([]byte, error) {
 	outgoing := bridgeOutgoing{JobRunID: input.JobRunID().String(), Data: data, Meta: meta}
 	if bridgeResponseURL != nil {
 		outgoing.ResponseURL = bridgeResponseURL.String()
@@ -126,14 +388,20 @@ func (ba *Bridge) postToExternalAdapter(input models.RunInput, meta *models.JSON
 		return nil, fmt.Errorf("marshaling request body: %v", err)
 	}
 
-	request, err := http.NewRequest("POST", ba.URL.String(), bytes.NewBuffer(in))
+	request, err := http.NewRequestWithContext(input.Context(), "POST", ba.URL.String(), bytes.NewBuffer(in))
 	if err != nil {
 		return nil, fmt.Errorf("building outgoing bridge http post: %v", err)
 	}
-	request.Header.Set("Authorization", "Bearer "+ba.BridgeType.OutgoingToken)
+	if err := SetBridgeAuthHeaders(request.Header, ba.BridgeType, store); err != nil {
+		return nil, errors.Wrap(err, "authenticating bridge request")
+	}
 	request.Header.Set("Content-Type", "application/json")
 
-	client := http.Client{}
+	transport, err := ClientTransport(ba.ClientCertificate, store)
+	if err != nil {
+		return nil, err
+	}
+	client := http.Client{Transport: transport}
 	resp, err := client.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("POST request: %v", err)
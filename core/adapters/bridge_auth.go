@@ -0,0 +1,100 @@
+package adapters
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// bridgeTokenSources caches the OAuth2 token source of each bridge, so that
+// access tokens are reused until they expire. A source is replaced once the
+// bridge's encrypted credentials change, i.e. its auth was updated.
+var bridgeTokenSources = struct {
+	sync.Mutex
+	sources map[models.TaskType]cachedTokenSource
+}{sources: make(map[models.TaskType]cachedTokenSource)}
+
+type cachedTokenSource struct {
+	fingerprint [sha256.Size]byte
+	oauth2.TokenSource
+}
+
+// nextTokenHeader carries the new outgoing token of a bridge during the grace
+// period of the rotation of its tokens, while the one it replaced is still
+// sent as the bearer token.
+const nextTokenHeader = "X-Chainlink-Next-Token"
+
+// SetBridgeAuthHeaders authenticates a request to a bridge, setting its
+// outgoing token as a bearer token unless the bridge's auth scheme takes the
+// Authorization header over. During the grace period of the rotation of its
+// tokens, the outgoing token replaced is sent instead, along with the new one
+// in the X-Chainlink-Next-Token header, so that an external adapter which
+// was not updated yet keeps accepting requests, and one being updated can
+// accept either token.
+func SetBridgeAuthHeaders(header http.Header, bt models.BridgeType, store *store.Store) error {
+	if bt.InTokenGracePeriod(time.Now()) {
+		header.Set("Authorization", "Bearer "+bt.PreviousOutgoingToken.String())
+		header.Set(nextTokenHeader, bt.OutgoingToken.String())
+	} else {
+		header.Set("Authorization", "Bearer "+bt.OutgoingToken.String())
+	}
+	if bt.AuthType == "" {
+		return nil
+	}
+
+	auth, err := bt.Auth()
+	if err != nil || auth == nil {
+		return err
+	}
+
+	switch auth.Type {
+	case models.BridgeAuthHeaders:
+		for name, value := range auth.Headers {
+			header.Set(name, value)
+		}
+	case models.BridgeAuthBasic:
+		r := http.Request{Header: header}
+		r.SetBasicAuth(auth.Username, auth.Password)
+	case models.BridgeAuthOAuth2:
+		token, err := bridgeTokenSource(bt, auth, store).Token()
+		if err != nil {
+			return errors.Wrapf(err, "fetching oauth2 token for bridge %s", bt.Name)
+		}
+		token.SetAuthHeader(&http.Request{Header: header})
+	default:
+		return fmt.Errorf("unsupported auth type %v for bridge %s", auth.Type, bt.Name)
+	}
+	return nil
+}
+
+func bridgeTokenSource(bt models.BridgeType, auth *models.BridgeAuth, store *store.Store) oauth2.TokenSource {
+	fingerprint := sha256.Sum256([]byte(bt.AuthCredentials))
+
+	bridgeTokenSources.Lock()
+	defer bridgeTokenSources.Unlock()
+
+	cached, ok := bridgeTokenSources.sources[bt.Name]
+	if !ok || cached.fingerprint != fingerprint {
+		client := &http.Client{Timeout: store.Config.DefaultHTTPTimeout().Duration()}
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+		config := clientcredentials.Config{
+			ClientID:     auth.ClientID,
+			ClientSecret: auth.ClientSecret,
+			TokenURL:     auth.TokenURL,
+			Scopes:       auth.Scopes,
+		}
+		cached = cachedTokenSource{fingerprint: fingerprint, TokenSource: config.TokenSource(ctx)}
+		bridgeTokenSources.sources[bt.Name] = cached
+	}
+	return cached.TokenSource
+}
@@ -0,0 +1,94 @@
+package adapters_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func TestSetBridgeAuthHeaders(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "client", user)
+		assert.Equal(t, "shh", pass)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"abc","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	tests := []struct {
+		name  string
+		auth  *models.BridgeAuth
+		check func(t *testing.T, bt *models.BridgeType, h http.Header)
+	}{
+		{"none", nil, func(t *testing.T, bt *models.BridgeType, h http.Header) {
+			assert.Equal(t, "Bearer "+bt.OutgoingToken.String(), h.Get("Authorization"))
+		}},
+		{"headers", &models.BridgeAuth{Type: models.BridgeAuthHeaders, Headers: map[string]string{"X-Api-Key": "key"}},
+			func(t *testing.T, bt *models.BridgeType, h http.Header) {
+				assert.Equal(t, "Bearer "+bt.OutgoingToken.String(), h.Get("Authorization"))
+				assert.Equal(t, "key", h.Get("X-Api-Key"))
+			}},
+		{"basic", &models.BridgeAuth{Type: models.BridgeAuthBasic, Username: "user", Password: "pass"},
+			func(t *testing.T, bt *models.BridgeType, h http.Header) {
+				r := http.Request{Header: h}
+				user, pass, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "user", user)
+				assert.Equal(t, "pass", pass)
+			}},
+		{"oauth2", &models.BridgeAuth{Type: models.BridgeAuthOAuth2, TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "shh"},
+			func(t *testing.T, bt *models.BridgeType, h http.Header) {
+				assert.Equal(t, "Bearer abc", h.Get("Authorization"))
+			}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, bt := cltest.NewBridgeType(t, "auth"+test.name)
+			require.NoError(t, bt.SetAuth(test.auth))
+
+			header := http.Header{}
+			require.NoError(t, adapters.SetBridgeAuthHeaders(header, *bt, store))
+			test.check(t, bt, header)
+		})
+	}
+
+	// The oauth2 token is reused until it expires
+	_, bt := cltest.NewBridgeType(t, "authoauth2")
+	require.NoError(t, bt.SetAuth(tests[3].auth))
+	require.NoError(t, adapters.SetBridgeAuthHeaders(http.Header{}, *bt, store))
+	assert.Equal(t, 1, tokenRequests)
+}
+
+func TestSetBridgeAuthHeaders_TokenGracePeriod(t *testing.T) {
+	_, bt := cltest.NewBridgeType(t)
+	previous := bt.OutgoingToken
+	_, err := bt.RotateTokens(time.Hour, time.Now())
+	require.NoError(t, err)
+
+	header := http.Header{}
+	require.NoError(t, adapters.SetBridgeAuthHeaders(header, *bt, nil))
+	assert.Equal(t, "Bearer "+previous.String(), header.Get("Authorization"))
+	assert.Equal(t, bt.OutgoingToken.String(), header.Get("X-Chainlink-Next-Token"))
+
+	bt.PreviousTokensExpireAt = null.TimeFrom(time.Now().Add(-time.Second))
+	header = http.Header{}
+	require.NoError(t, adapters.SetBridgeAuthHeaders(header, *bt, nil))
+	assert.Equal(t, "Bearer "+bt.OutgoingToken.String(), header.Get("Authorization"))
+	assert.Empty(t, header.Get("X-Chainlink-Next-Token"))
+}
@@ -0,0 +1,28 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBridgePacer_reserve(t *testing.T) {
+	t.Parallel()
+
+	p := &bridgePacer{next: map[models.TaskType]time.Time{}}
+	now := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.True(t, now.Equal(p.reserve("a", time.Minute, now)))
+	assert.True(t, now.Add(time.Minute).Equal(p.reserve("a", time.Minute, now)))
+	assert.True(t, now.Equal(p.reserve("b", time.Second, now)))
+	assert.Len(t, p.next, 2)
+
+	// Once their intervals have passed, bridges are forgotten
+	later := now.Add(time.Hour)
+	assert.True(t, later.Equal(p.reserve("c", time.Minute, later)))
+	assert.Len(t, p.next, 1)
+	assert.Contains(t, p.next, models.TaskType("c"))
+}
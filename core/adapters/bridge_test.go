@@ -4,7 +4,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
@@ -13,6 +17,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 )
 
 func TestBridge_PerformEmbedsParamsInData(t *testing.T) {
@@ -42,7 +48,7 @@ func TestBridge_PerformEmbedsParamsInData(t *testing.T) {
 	require.NoError(t, result.Error())
 	assert.Equal(t, `{"bodyParam":true,"result":"100"}`, data)
 	assert.False(t, meta)
-	assert.Equal(t, "Bearer "+bt.OutgoingToken, token)
+	assert.Equal(t, "Bearer "+bt.OutgoingToken.String(), token)
 }
 
 func setupJobRunAndStore(t *testing.T, txHash []byte, blockHash []byte) (*store.Store, *models.ID, func()) {
@@ -85,7 +91,7 @@ func TestBridge_IncludesMetaIfJobRunIsInDB(t *testing.T) {
 	require.NoError(t, result.Error())
 	assert.Equal(t, `{"bodyParam":true,"result":"100"}`, data)
 	assert.Equal(t, fmt.Sprintf(`{"initiator":{"transactionHash":"0x%s","blockHash":"0x%s"}}`, txHashHex, blockHashHex), meta)
-	assert.Equal(t, "Bearer "+bt.OutgoingToken, token)
+	assert.Equal(t, "Bearer "+bt.OutgoingToken.String(), token)
 }
 
 func TestBridge_PerformAcceptsNonJsonObjectResponses(t *testing.T) {
@@ -188,41 +194,195 @@ func TestBridge_Perform_startANewRun(t *testing.T) {
 }
 
 func TestBridge_Perform_responseURL(t *testing.T) {
-	input := cltest.NewRunInputWithResult("lot 49")
-
 	t.Parallel()
 	cases := []struct {
 		name          string
 		configuredURL models.WebURL
-		want          string
+		response      string
+		wantCallback  bool
+		wantPending   bool
 	}{
-		{
-			name:          "basic URL",
-			configuredURL: cltest.WebURL(t, "https://chain.link"),
-			want:          fmt.Sprintf(`{"id":"%s","data":{"result":"lot 49"},"responseURL":"https://chain.link/v2/runs/%s"}`, input.JobRunID().String(), input.JobRunID().String()),
-		},
-		{
-			name:          "blank URL",
-			configuredURL: cltest.WebURL(t, ""),
-			want:          fmt.Sprintf(`{"id":"%s","data":{"result":"lot 49"}}`, input.JobRunID().String()),
-		},
+		{"basic URL", cltest.WebURL(t, "https://chain.link"), `{"pending":true}`, true, true},
+		{"answered synchronously", cltest.WebURL(t, "https://chain.link"), `{"data":{"result":"lot 50"}}`, true, false},
+		{"blank URL", cltest.WebURL(t, ""), `{"pending":true}`, false, true},
 	}
 
 	for _, test := range cases {
+		test := test
 		t.Run(test.name, func(t *testing.T) {
 			store, cleanup := cltest.NewStore(t)
 			defer cleanup()
 			store.Config.Set("BRIDGE_RESPONSE_URL", test.configuredURL)
 
-			mock, ensureCalled := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", ``,
+			job := cltest.NewJobWithWebInitiator()
+			require.NoError(t, store.CreateJob(&job))
+			run := cltest.NewJobRun(job)
+			require.NoError(t, store.CreateJobRun(&run))
+			input := cltest.NewRunInputWithResultAndJobRunID("lot 49", run.ID)
+
+			var responseURL string
+			mock, ensureCalled := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", test.response,
 				func(_ http.Header, body string) {
-					assert.JSONEq(t, test.want, body)
+					responseURL = gjson.Get(body, "responseURL").String()
+					withoutURL, err := sjson.Delete(body, "responseURL")
+					require.NoError(t, err)
+					assert.JSONEq(t, fmt.Sprintf(`{"id":"%s","data":{"result":"lot 49"}}`, run.ID.String()), withoutURL)
 				})
 			defer ensureCalled()
 
 			_, bt := cltest.NewBridgeType(t, "auctionBidding", mock.URL)
-			eb := &adapters.Bridge{BridgeType: *bt}
-			eb.Perform(input, store)
+			eb := &adapters.Bridge{BridgeType: *bt, TaskRunID: run.TaskRuns[0].ID}
+			result := eb.Perform(input, store)
+			assert.Equal(t, test.wantPending, result.Status().PendingBridge())
+
+			if !test.wantCallback {
+				assert.Empty(t, responseURL)
+				return
+			}
+			u, err := url.Parse(responseURL)
+			require.NoError(t, err)
+			assert.Equal(t, "chain.link", u.Host)
+			assert.Equal(t, fmt.Sprintf("/v2/runs/%s/callback", run.ID.String()), u.Path)
+
+			token := u.Query().Get("token")
+			otherTaskRun := models.NewID()
+			ok, err := store.ConsumeBridgeCallback(run.ID, otherTaskRun, token, time.Now())
+			require.NoError(t, err)
+			assert.False(t, ok, "callback tokens only resume the task run which called the bridge")
+			ok, err = store.ConsumeBridgeCallback(run.ID, run.TaskRuns[0].ID, token, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, test.wantPending, ok, "callback tokens are removed once the bridge answers")
+			ok, err = store.ConsumeBridgeCallback(run.ID, run.TaskRuns[0].ID, token, time.Now())
+			require.NoError(t, err)
+			assert.False(t, ok, "callback tokens are single-use")
 		})
 	}
 }
+
+func TestBridge_Perform_cachesResponses(t *testing.T) {
+	for _, cacheStore := range []string{"memory", "database"} {
+		t.Run(cacheStore, func(t *testing.T) {
+			s, cleanup := cltest.NewStore(t)
+			defer cleanup()
+			s.Config.Set("BRIDGE_CACHE_STORE", cacheStore)
+			cache, err := store.NewBridgeCache(s.Config, s.ORM)
+			require.NoError(t, err)
+			s.BridgeCache = cache
+
+			var calls int32
+			mock, cleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", `{"data":{"result":"lot 49"}}`,
+				func(http.Header, string) { atomic.AddInt32(&calls, 1) })
+			defer cleanup()
+
+			_, bt := cltest.NewBridgeType(t, "cached"+cacheStore, mock.URL)
+			bt.CacheTTL = models.MustMakeDuration(time.Minute)
+			require.NoError(t, s.CreateBridgeType(bt))
+			ba := &adapters.Bridge{BridgeType: *bt}
+
+			for i := 0; i < 2; i++ {
+				result := ba.Perform(cltest.NewRunInputWithResult("100"), s)
+				require.NoError(t, result.Error())
+				assert.Equal(t, "lot 49", result.Result().String())
+			}
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+			result := ba.Perform(cltest.NewRunInputWithResult("101"), s)
+			require.NoError(t, result.Error())
+			assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestBridge_Perform_doesNotCachePendingResponses(t *testing.T) {
+	s, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	var calls int32
+	mock, cleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", `{"pending":true}`,
+		func(http.Header, string) { atomic.AddInt32(&calls, 1) })
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t, "cachedpending", mock.URL)
+	bt.CacheTTL = models.MustMakeDuration(time.Minute)
+	ba := &adapters.Bridge{BridgeType: *bt}
+
+	for i := 0; i < 2; i++ {
+		result := ba.Perform(cltest.NewRunInputWithResult("100"), s)
+		require.NoError(t, result.Error())
+		assert.True(t, result.Status().PendingBridge())
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestBridge_Perform_circuitBreaker(t *testing.T) {
+	s, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	s.Config.Set("BRIDGE_CIRCUIT_BREAKER_THRESHOLD", 2)
+
+	var calls int32
+	status := int32(http.StatusBadGateway)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		w.Write([]byte(`{"data":{"result":"lot 49"}}`))
+	}))
+	defer server.Close()
+
+	_, bt := cltest.NewBridgeType(t, "flakybridge", server.URL)
+	require.NoError(t, s.CreateBridgeType(bt))
+	ba := &adapters.Bridge{BridgeType: *bt}
+
+	for i := 0; i < 2; i++ {
+		result := ba.Perform(cltest.NewRunInputWithResult("100"), s)
+		require.Error(t, result.Error())
+		assert.Contains(t, result.Error().Error(), "502")
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	result := ba.Perform(cltest.NewRunInputWithResult("100"), s)
+	require.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "circuit breaker open after 2 consecutive failures")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "an open circuit should fail without calling the bridge")
+
+	s.Config.Set("BRIDGE_CIRCUIT_BREAKER_TIMEOUT", "0s")
+	atomic.StoreInt32(&status, http.StatusOK)
+	result = ba.Perform(cltest.NewRunInputWithResult("100"), s)
+	require.NoError(t, result.Error())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	health, err := s.FindBridgeHealth(bt.Name)
+	require.NoError(t, err)
+	assert.False(t, health.CircuitOpen(), "a successful probe should close the circuit")
+}
+
+func TestBridge_Perform_pacesRequests(t *testing.T) {
+	s, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	var calls int32
+	mock, cleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", `{"data":{"result":"lot 49"}}`,
+		func(http.Header, string) { atomic.AddInt32(&calls, 1) })
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t, "pacedbridge", mock.URL)
+	bt.MinInterval = models.MustMakeDuration(time.Hour)
+	ba := &adapters.Bridge{BridgeType: *bt}
+
+	result := ba.Perform(cltest.NewRunInputWithResult("100"), s)
+	require.NoError(t, result.Error())
+	assert.Equal(t, "lot 49", result.Result().String())
+
+	result = ba.Perform(cltest.NewRunInputWithResult("100"), s)
+	require.NoError(t, result.Error())
+	require.Equal(t, models.RunStatusPendingSleep, result.Status())
+	until, ok := adapters.SleepUntil(result.Data())
+	require.True(t, ok)
+	assert.True(t, until.After(time.Now().Add(59*time.Minute)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "a paced request should wait for its turn")
+
+	input := models.NewRunInput(models.NewID(), result.Data(), models.RunStatusPendingSleep)
+	result = ba.Perform(*input, s)
+	require.NoError(t, result.Error())
+	assert.Equal(t, "lot 49", result.Result().String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,111 @@
+package adapters
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+)
+
+// clientTLSConfigs caches the TLS configuration of each client certificate,
+// along with a transport presenting it, so that a certificate is only
+// decrypted again, and its connections only dropped, once it has been
+// replaced.
+var clientTLSConfigs = struct {
+	sync.Mutex
+	configs map[string]cachedTLSConfig
+}{configs: make(map[string]cachedTLSConfig)}
+
+type cachedTLSConfig struct {
+	updatedAt time.Time
+	config    *tls.Config
+	transport *http.Transport
+}
+
+// ClientTLSConfig returns the TLS configuration presenting the named client
+// certificate, or the default certificate if no name is given. It returns
+// nil when no name is given and no default certificate has been added.
+//
+// Certificates are looked up through the ORM's lookup cache, which drops
+// them whenever one is saved or deleted, so that a replaced certificate is
+// used from the next request on.
+func ClientTLSConfig(name string, store *store.Store) (*tls.Config, error) {
+	cached, err := clientTLS(name, store)
+	return cached.config, err
+}
+
+// ClientTransport returns the transport presenting the named client
+// certificate, or the default certificate if no name is given, shared by
+// every request presenting it so that their connections are reused. It
+// returns nil when no name is given and no default certificate has been
+// added, for the default transport to be used.
+func ClientTransport(name string, store *store.Store) (http.RoundTripper, error) {
+	cached, err := clientTLS(name, store)
+	if err != nil || cached.transport == nil {
+		return nil, err
+	}
+	return cached.transport, nil
+}
+
+// clientTLS returns the cached TLS configuration and transport of the named
+// client certificate, replacing them once the certificate has been.
+func clientTLS(name string, store *store.Store) (cachedTLSConfig, error) {
+	if name == "" {
+		// Stores without a database, as used by the adapters on their own,
+		// have no default certificate to present.
+		if store.ORM == nil {
+			return cachedTLSConfig{}, nil
+		}
+		name = models.DefaultClientCertificate
+	}
+	cc, err := store.FindClientCertificate(name)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		dropClientTLS(name)
+		if name == models.DefaultClientCertificate {
+			return cachedTLSConfig{}, nil
+		}
+	}
+	if err != nil {
+		return cachedTLSConfig{}, errors.Wrapf(err, "finding client certificate %s", name)
+	}
+
+	clientTLSConfigs.Lock()
+	defer clientTLSConfigs.Unlock()
+
+	cached, ok := clientTLSConfigs.configs[name]
+	if ok && cached.updatedAt.Equal(cc.UpdatedAt) {
+		return cached, nil
+	}
+
+	config, err := cc.TLSConfig()
+	if err != nil {
+		return cachedTLSConfig{}, err
+	}
+	if ok {
+		cached.transport.CloseIdleConnections()
+	}
+	cached = cachedTLSConfig{
+		updatedAt: cc.UpdatedAt,
+		config:    config,
+		transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config},
+	}
+	clientTLSConfigs.configs[name] = cached
+	return cached, nil
+}
+
+// dropClientTLS forgets the TLS configuration and transport of a client
+// certificate which was deleted, closing its idle connections.
+func dropClientTLS(name string) {
+	clientTLSConfigs.Lock()
+	defer clientTLSConfigs.Unlock()
+	if cached, ok := clientTLSConfigs.configs[name]; ok {
+		cached.transport.CloseIdleConnections()
+		delete(clientTLSConfigs.configs, name)
+	}
+}
@@ -0,0 +1,116 @@
+package adapters_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMutualTLSServer returns a server only accepting requests presenting one
+// of the given client certificates.
+func newMutualTLSServer(t *testing.T, ccrs ...models.ClientCertificateRequest) *httptest.Server {
+	clientCAs := x509.NewCertPool()
+	for _, ccr := range ccrs {
+		require.True(t, clientCAs.AppendCertsFromPEM([]byte(ccr.Certificate)))
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"result":"` + r.TLS.PeerCertificates[0].Subject.CommonName + `"}}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	return server
+}
+
+func serverCAPEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func saveClientCertificate(t *testing.T, ccr models.ClientCertificateRequest, s *store.Store) {
+	cc, err := models.NewClientCertificate(&ccr)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveClientCertificate(cc))
+}
+
+func TestClientTLS_HTTPGetPresentsDefaultCertificate(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	ccr := cltest.NewClientCertificateRequest(t, models.DefaultClientCertificate)
+	server := newMutualTLSServer(t, ccr)
+	defer server.Close()
+
+	hga := &adapters.HTTPGet{URL: cltest.WebURL(t, server.URL), AllowUnrestrictedNetworkAccess: true}
+	result := hga.Perform(cltest.NewRunInputWithResult("inputValue"), store)
+	require.Error(t, result.Error(), "request without a client certificate should be refused")
+
+	ccr.CACertificate = serverCAPEM(server)
+	saveClientCertificate(t, ccr, store)
+
+	result = hga.Perform(cltest.NewRunInputWithResult("inputValue"), store)
+	require.NoError(t, result.Error())
+	assert.Contains(t, result.Result().String(), models.DefaultClientCertificate)
+}
+
+func TestClientTLS_BridgePresentsItsCertificate(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	defaultCCR := cltest.NewClientCertificateRequest(t, models.DefaultClientCertificate)
+	bridgeCCR := cltest.NewClientCertificateRequest(t, "dataprovider")
+	server := newMutualTLSServer(t, defaultCCR, bridgeCCR)
+	defer server.Close()
+	defaultCCR.CACertificate = serverCAPEM(server)
+	bridgeCCR.CACertificate = serverCAPEM(server)
+	saveClientCertificate(t, defaultCCR, store)
+	saveClientCertificate(t, bridgeCCR, store)
+
+	_, bt := cltest.NewBridgeType(t, "mtlsbridge", server.URL)
+	ba := &adapters.Bridge{BridgeType: *bt}
+	result := ba.Perform(cltest.NewRunInputWithResult("100"), store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, models.DefaultClientCertificate, result.Result().String())
+
+	ba.ClientCertificate = "dataprovider"
+	result = ba.Perform(cltest.NewRunInputWithResult("100"), store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, "dataprovider", result.Result().String())
+
+	ba.ClientCertificate = "missing"
+	result = ba.Perform(cltest.NewRunInputWithResult("100"), store)
+	assert.Error(t, result.Error())
+}
+
+func TestClientTransport_SharedUntilCertificateReplaced(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	transport, err := adapters.ClientTransport("", store)
+	require.NoError(t, err)
+	assert.Nil(t, transport, "without a default certificate the default transport is used")
+
+	ccr := cltest.NewClientCertificateRequest(t, "shared")
+	saveClientCertificate(t, ccr, store)
+	first, err := adapters.ClientTransport("shared", store)
+	require.NoError(t, err)
+	second, err := adapters.ClientTransport("shared", store)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	saveClientCertificate(t, ccr, store)
+	replaced, err := adapters.ClientTransport("shared", store)
+	require.NoError(t, err)
+	assert.NotSame(t, first, replaced)
+
+	require.NoError(t, store.DeleteClientCertificate("shared"))
+	_, err = adapters.ClientTransport("shared", store)
+	assert.Error(t, err)
+}
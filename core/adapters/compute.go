@@ -0,0 +1,91 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Compute evaluates an expression over the fields of the run's data, for
+// the arithmetic and conditions jobs need between their tasks, such as
+// scaling a price only when it is under a threshold.
+//
+// Expressions are made of numbers, "strings", true and false, the fields of
+// the data named by their paths, such as result or data.price, the
+// arithmetic operators +, -, *, / and %, the comparisons <, <=, >, >=, ==
+// and !=, the logical operators &&, || and !, the conditional
+// condition ? then : otherwise, and parentheses. For example:
+//
+//   result < 100 ? result * 1.05 : result
+//
+// Numbers are exact decimals, and strings holding numbers, as prices often
+// are, can be used as numbers.
+type Compute struct {
+	Expression ComputeExpression `json:"expression"`
+}
+
+// TaskType returns the type of Adapter.
+func (c *Compute) TaskType() models.TaskType {
+	return TaskTypeCompute
+}
+
+// Perform evaluates the expression, its value becoming the result.
+func (c *Compute) Perform(input models.RunInput, _ *store.Store) models.RunOutput {
+	if c.Expression.root == nil {
+		return models.NewRunOutputError(errors.New("compute expression is empty"))
+	}
+	value, err := c.Expression.root.eval(input.Data())
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrapf(err, "evaluating compute expression %q", c.Expression.source))
+	}
+	if d, ok := value.(decimal.Decimal); ok {
+		return models.NewRunOutputCompleteWithResult(d.String())
+	}
+	return models.NewRunOutputCompleteWithResult(value)
+}
+
+// ComputeExpression is the expression of a compute task, parsed when
+// unmarshalled so that invalid expressions are rejected when a job is
+// created rather than when it runs.
+type ComputeExpression struct {
+	source string
+	root   computeNode
+}
+
+// NewComputeExpression parses the given expression.
+func NewComputeExpression(source string) (ComputeExpression, error) {
+	root, err := parseComputeExpression(source)
+	if err != nil {
+		return ComputeExpression{}, fmt.Errorf("compute expression %q is invalid: %v", source, err)
+	}
+	return ComputeExpression{source: source, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (ce ComputeExpression) String() string {
+	return ce.source
+}
+
+// MarshalJSON implements the Marshaler interface.
+func (ce ComputeExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ce.source)
+}
+
+// UnmarshalJSON implements the Unmarshaler interface.
+func (ce *ComputeExpression) UnmarshalJSON(b []byte) error {
+	var source string
+	if err := json.Unmarshal(b, &source); err != nil {
+		return err
+	}
+	expr, err := NewComputeExpression(source)
+	if err != nil {
+		return err
+	}
+	*ce = expr
+	return nil
+}
@@ -0,0 +1,414 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/tidwall/gjson"
+)
+
+// The expressions of compute tasks are parsed into a tree of computeNodes,
+// which only read the data of the run, so that evaluating them takes time
+// proportional to their size, and they cannot loop or reach outside the run.
+
+// computeMaxDepth is the deepest expressions can nest, so that parsing them
+// cannot exhaust the stack.
+const computeMaxDepth = 64
+
+// computeValue is the value of an expression: a decimal.Decimal, a bool or
+// a string.
+type computeValue interface{}
+
+type computeNode interface {
+	eval(data models.JSON) (computeValue, error)
+}
+
+type (
+	computeLiteral struct{ value computeValue }
+	computeField   struct{ path string }
+	computeUnary   struct {
+		op      string
+		operand computeNode
+	}
+	computeBinary struct {
+		op          string
+		left, right computeNode
+	}
+	computeConditional struct {
+		condition, then, otherwise computeNode
+	}
+)
+
+func (n computeLiteral) eval(models.JSON) (computeValue, error) {
+	return n.value, nil
+}
+
+func (n computeField) eval(data models.JSON) (computeValue, error) {
+	value := data.Get(n.path)
+	switch value.Type {
+	case gjson.Number:
+		return decimal.NewFromString(value.Raw)
+	case gjson.String:
+		return value.Str, nil
+	case gjson.True, gjson.False:
+		return value.Bool(), nil
+	default:
+		return nil, fmt.Errorf("field %s is not a number, string or bool", n.path)
+	}
+}
+
+func (n computeUnary) eval(data models.JSON) (computeValue, error) {
+	value, err := n.operand.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, err := computeBool(value)
+		return !b, err
+	}
+	d, err := computeDecimal(value)
+	return d.Neg(), err
+}
+
+func (n computeBinary) eval(data models.JSON) (computeValue, error) {
+	left, err := n.left.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" || n.op == "||" {
+		l, err := computeBool(left)
+		if err != nil || l == (n.op == "||") {
+			return l, err
+		}
+		right, err := n.right.eval(data)
+		if err != nil {
+			return nil, err
+		}
+		return computeBool(right)
+	}
+
+	right, err := n.right.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "==" || n.op == "!=" {
+		equal, err := computeEqual(left, right)
+		return equal == (n.op == "=="), err
+	}
+	l, err := computeDecimal(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := computeDecimal(right)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "+":
+		return l.Add(r), nil
+	case "-":
+		return l.Sub(r), nil
+	case "*":
+		return l.Mul(r), nil
+	case "/", "%":
+		if r.IsZero() {
+			return nil, errors.New("division by zero")
+		}
+		if n.op == "/" {
+			return l.Div(r), nil
+		}
+		return l.Mod(r), nil
+	case "<":
+		return l.LessThan(r), nil
+	case "<=":
+		return l.LessThanOrEqual(r), nil
+	case ">":
+		return l.GreaterThan(r), nil
+	default:
+		return l.GreaterThanOrEqual(r), nil
+	}
+}
+
+func (n computeConditional) eval(data models.JSON) (computeValue, error) {
+	value, err := n.condition.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := computeBool(value)
+	if err != nil {
+		return nil, err
+	}
+	if condition {
+		return n.then.eval(data)
+	}
+	return n.otherwise.eval(data)
+}
+
+// computeDecimal returns the value as a number, strings holding numbers
+// being parsed, as data sources often return prices as strings.
+func computeDecimal(value computeValue) (decimal.Decimal, error) {
+	switch v := value.(type) {
+	case decimal.Decimal:
+		return v, nil
+	case string:
+		d, err := decimal.NewFromString(v)
+		if err != nil {
+			return decimal.Decimal{}, fmt.Errorf("%q is not a number", v)
+		}
+		return d, nil
+	default:
+		return decimal.Decimal{}, fmt.Errorf("%v is not a number", v)
+	}
+}
+
+func computeBool(value computeValue) (bool, error) {
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%v is not a bool", value)
+	}
+	return b, nil
+}
+
+// computeEqual compares the values as numbers if either is one, and as they
+// are otherwise.
+func computeEqual(left, right computeValue) (bool, error) {
+	_, leftNumber := left.(decimal.Decimal)
+	_, rightNumber := right.(decimal.Decimal)
+	if leftNumber || rightNumber {
+		l, err := computeDecimal(left)
+		if err != nil {
+			return false, err
+		}
+		r, err := computeDecimal(right)
+		if err != nil {
+			return false, err
+		}
+		return l.Equal(r), nil
+	}
+	return left == right, nil
+}
+
+// computeToken is a token of an expression. Numbers, strings and fields
+// are told apart by kind, operators and punctuation being their own kind.
+type computeToken struct {
+	kind string
+	text string
+}
+
+const (
+	computeNumber = "number"
+	computeString = "string"
+	computeIdent  = "identifier"
+	computeEnd    = "end of expression"
+)
+
+var computeOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "?", ":", "(", ")"}
+
+func lexComputeExpression(source string) ([]computeToken, error) {
+	var tokens []computeToken
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, computeToken{computeNumber, string(runes[start:i])})
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated string")
+			}
+			tokens = append(tokens, computeToken{computeString, string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, computeToken{computeIdent, string(runes[start:i])})
+		default:
+			rest := string(runes[i:])
+			var op string
+			for _, candidate := range computeOperators {
+				if strings.HasPrefix(rest, candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q", r)
+			}
+			tokens = append(tokens, computeToken{op, op})
+			i += len(op)
+		}
+	}
+	return append(tokens, computeToken{computeEnd, computeEnd}), nil
+}
+
+// computeParser parses expressions by recursive descent, each method parsing
+// the operators of one precedence level, from the loosest to the tightest.
+type computeParser struct {
+	tokens []computeToken
+	pos    int
+	depth  int
+}
+
+func parseComputeExpression(source string) (computeNode, error) {
+	tokens, err := lexComputeExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &computeParser{tokens: tokens}
+	node, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != computeEnd {
+		return nil, fmt.Errorf("unexpected %q", next.text)
+	}
+	return node, nil
+}
+
+func (p *computeParser) peek() computeToken {
+	return p.tokens[p.pos]
+}
+
+func (p *computeParser) next() computeToken {
+	token := p.tokens[p.pos]
+	if token.kind != computeEnd {
+		p.pos++
+	}
+	return token
+}
+
+func (p *computeParser) expect(kind string) error {
+	if token := p.next(); token.kind != kind {
+		return fmt.Errorf("expected %q, got %q", kind, token.text)
+	}
+	return nil
+}
+
+func (p *computeParser) conditional() (computeNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > computeMaxDepth {
+		return nil, fmt.Errorf("expression nests deeper than %d", computeMaxDepth)
+	}
+
+	condition, err := p.binary(0)
+	if err != nil || p.peek().kind != "?" {
+		return condition, err
+	}
+	p.next()
+	then, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	return computeConditional{condition, then, otherwise}, nil
+}
+
+// computePrecedences are the binary operators, by precedence level, loosest
+// first.
+var computePrecedences = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *computeParser) binary(level int) (computeNode, error) {
+	if level == len(computePrecedences) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for containsString(computePrecedences[level], p.peek().kind) {
+		op := p.next().kind
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = computeBinary{op, left, right}
+	}
+	return left, nil
+}
+
+func (p *computeParser) unary() (computeNode, error) {
+	if kind := p.peek().kind; kind == "!" || kind == "-" {
+		p.next()
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > computeMaxDepth {
+			return nil, fmt.Errorf("expression nests deeper than %d", computeMaxDepth)
+		}
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return computeUnary{kind, operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *computeParser) primary() (computeNode, error) {
+	token := p.next()
+	switch token.kind {
+	case computeNumber:
+		d, err := decimal.NewFromString(token.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", token.text)
+		}
+		return computeLiteral{d}, nil
+	case computeString:
+		return computeLiteral{token.text}, nil
+	case computeIdent:
+		switch token.text {
+		case "true":
+			return computeLiteral{true}, nil
+		case "false":
+			return computeLiteral{false}, nil
+		}
+		return computeField{token.text}, nil
+	case "(":
+		node, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	default:
+		return nil, fmt.Errorf("unexpected %q", token.text)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute_Perform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		expression  string
+		data        string
+		want        string
+		wantErrored bool
+	}{
+		{"arithmetic", `1 + 2 * 3 - 4 / 2`, `{}`, `"5"`, false},
+		{"parentheses", `(1 + 2) * 3`, `{}`, `"9"`, false},
+		{"modulo", `10 % 4`, `{}`, `"2"`, false},
+		{"negation", `-result + 1`, `{"result":"2.5"}`, `"-1.5"`, false},
+		{"numeric string result", `result * 2`, `{"result":"11850.25"}`, `"23700.5"`, false},
+		{"nested field", `data.price / 2`, `{"data":{"price":3}}`, `"1.5"`, false},
+		{"conditional true", `result < 100 ? result * 1.05 : result`, `{"result":"80"}`, `"84"`, false},
+		{"conditional false", `result < 100 ? result * 1.05 : result`, `{"result":"120"}`, `"120"`, false},
+		{"comparison", `result >= 10`, `{"result":10}`, `true`, false},
+		{"string equality", `status == "ok"`, `{"status":"ok"}`, `true`, false},
+		{"logical", `!(a && b) || false`, `{"a":true,"b":false}`, `true`, false},
+		{"short circuit", `false && missing > 1`, `{}`, `false`, false},
+		{"string value", `a > 1 ? "high" : "low"`, `{"a":0}`, `"low"`, false},
+		{"division by zero", `result / 0`, `{"result":1}`, ``, true},
+		{"missing field", `missing + 1`, `{}`, ``, true},
+		{"non numeric string", `result + 1`, `{"result":"abc"}`, ``, true},
+		{"non bool condition", `result ? 1 : 2`, `{"result":1}`, ``, true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			expr, err := adapters.NewComputeExpression(test.expression)
+			require.NoError(t, err)
+
+			adapter := adapters.Compute{Expression: expr}
+			result := adapter.Perform(cltest.NewRunInput(cltest.JSONFromString(t, test.data)), nil)
+
+			if test.wantErrored {
+				assert.True(t, result.HasError())
+			} else {
+				require.NoError(t, result.Error())
+				assert.JSONEq(t, test.want, result.Result().Raw)
+			}
+		})
+	}
+}
+
+func TestNewComputeExpression_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		``,
+		`1 +`,
+		`(1 + 2`,
+		`1 + 2)`,
+		`"unterminated`,
+		`a ? 1`,
+		`1 $ 2`,
+		`1.2.3`,
+		strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100),
+		strings.Repeat("!", 100) + "true",
+	}
+
+	for _, expression := range tests {
+		_, err := adapters.NewComputeExpression(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestComputeExpression_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var adapter adapters.Compute
+	require.NoError(t, json.Unmarshal([]byte(`{"expression":"result * 2"}`), &adapter))
+	assert.Equal(t, "result * 2", adapter.Expression.String())
+
+	b, err := json.Marshal(adapter)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expression":"result * 2"}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"expression":"result *"}`), &adapter)
+	assert.Error(t, err)
+}
@@ -8,6 +8,17 @@
 // For example:
 //  {"id": "b8004e2989e24e1d8e4449afad2eb480", "data": {}}
 //
+// A bridge with a minInterval is called at most once per interval, runs which
+// would call it sooner sleeping until their turn.
+//
+// Aggregate
+//
+// The Aggregate adapter fetches a number from each of its sources, URLs or
+// bridges, concurrently, and takes their median, mean or mode, after trimming
+// the given fraction of outliers from each end. It errors unless at least
+// minResponses sources, by default a majority, respond.
+//  { "type": "Aggregate", "params": {"sources": [{"url": "https://example.com/price", "path": ["USD"]}, {"bridge": "pricefeed"}], "method": "median" }}
+//
 // Compare
 //
 // The Compare adapter is used to compare the previous task's result
@@ -47,6 +58,20 @@
 // The JSONParse adapter will obtain the value(s) for the given field(s).
 //  { "type": "JSONParse", "params": {"path": ["someField"] }}
 //
+// Transform
+//
+// The Transform adapter runs a jq expression against the result, for when
+// JSONParse's path is not enough to extract or reshape the value.
+//  { "type": "Transform", "params": {"expression": "[.data[].price] | max" }}
+//
+// Sleep
+//
+// The Sleep adapter pauses the run until the given time, or for the given
+// duration, plus a random jitter of up to the given duration, spreading out
+// runs triggered at the same moment. Sleeping runs are woken up when due,
+// without holding up a runner in the meantime.
+//  { "type": "Sleep", "params": {"duration": "30s", "jitter": "10s" }}
+//
 // EthBool
 //
 // The EthBool adapter will take the given values and format them for
@@ -81,6 +106,17 @@
 //     }
 //   }
 //
+// Requests expecting more than a single word can be answered by listing the
+// ABI type of each value sent, and its path in the run data, as the encoding.
+//   {
+//     "type": "EthTx", "params": {
+//       "encoding": [
+//         {"type": "uint256", "path": "result.price"},
+//         {"type": "bytes", "path": "result.proof"}
+//       ]
+//     }
+//   }
+//
 // Multiplier
 //
 // The Multiplier adapter multiplies the given input value times another specified
@@ -159,6 +195,21 @@
 // from other contracts, which could be crafted to prematurely reveal the random
 // output if someone learns a prospective input seed prior to its use in the VRF.
 //
+// EthCall
+//
+// The EthCall adapter reads from a contract with eth_call, taking the
+// arguments of the function from the result object, encoded as for
+// EthTxABIEncode below, and decoding the values returned into the result.
+//   {
+//     "type": "EthCall",
+//     "address": "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+//     "functionABI": {
+//       "name": "balanceOf",
+//       "inputs": [{"name": "owner", "type": "address"}],
+//       "outputs": [{"name": "balance", "type": "uint256"}]
+//     }
+//   }
+//
 // EthTxABIEncode
 //
 // The EthTxABIEncode adapter serializes the contents of a json object as
@@ -0,0 +1,152 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// EthCall holds the Address of a contract and the FunctionABI of the constant
+// function to call on it.
+type EthCall struct {
+	// Ethereum address of the contract this task calls
+	Address common.Address `json:"address"`
+	// ABI of contract function this task calls, including its outputs
+	FunctionABI abi.Method `json:"functionABI"`
+}
+
+// TaskType returns the type of Adapter.
+func (ec *EthCall) TaskType() models.TaskType {
+	return TaskTypeEthCall
+}
+
+// UnmarshalJSON is strict, as for EthTxABIEncode, rejecting fields of the
+// FunctionABI that are not used for encoding the call or decoding its result.
+func (ec *EthCall) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Address     common.Address
+		FunctionABI struct {
+			Name    string
+			Inputs  abi.Arguments
+			Outputs abi.Arguments
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fields); err != nil {
+		return err
+	}
+
+	ec.Address = fields.Address
+	ec.FunctionABI.Name = fields.FunctionABI.Name
+	ec.FunctionABI.RawName = fields.FunctionABI.Name
+	ec.FunctionABI.Inputs = fields.FunctionABI.Inputs
+	ec.FunctionABI.Outputs = fields.FunctionABI.Outputs
+	return nil
+}
+
+// Perform calls the function with eth_call against the latest block, encoding
+// the arguments from the input's result object as for EthTxABIEncode.
+//
+// The values returned are decoded back into the result: a single output
+// becomes the result, while several are returned as an object keyed by their
+// names, or by their positions for unnamed outputs. Integers too large for a
+// JSON number are returned as decimal strings, and bytes and addresses as hex.
+func (ec *EthCall) Perform(input models.RunInput, store *strpkg.Store) models.RunOutput {
+	if !store.TxManager.Connected() {
+		return models.NewRunOutputPendingConnection()
+	}
+
+	args := map[string]interface{}{}
+	if len(ec.FunctionABI.Inputs) > 0 {
+		var ok bool
+		args, ok = input.Result().Value().(map[string]interface{})
+		if !ok {
+			return models.NewRunOutputError(errors.New("json result is not an object"))
+		}
+	}
+	data, err := abiEncode(&ec.FunctionABI, args)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "while constructing EthCall data"))
+	}
+
+	var result string
+	err = store.TxManager.Call(&result, "eth_call", eth.CallArgs{To: ec.Address, Data: data}, "latest")
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "calling contract"))
+	}
+	returned, err := hexutil.Decode(result)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "decoding eth_call response"))
+	}
+
+	values, err := ec.FunctionABI.Outputs.UnpackValues(returned)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "unpacking eth_call response"))
+	}
+	switch len(values) {
+	case 0:
+		return models.NewRunOutputCompleteWithResult(nil)
+	case 1:
+		return models.NewRunOutputCompleteWithResult(abiValueToJSON(reflect.ValueOf(values[0])))
+	default:
+		outputs := map[string]interface{}{}
+		for i, output := range ec.FunctionABI.Outputs {
+			name := output.Name
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			outputs[name] = abiValueToJSON(reflect.ValueOf(values[i]))
+		}
+		return models.NewRunOutputCompleteWithResult(outputs)
+	}
+}
+
+var (
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+	addressType = reflect.TypeOf(common.Address{})
+)
+
+// abiValueToJSON converts a value unpacked by go-ethereum's abi package into
+// one that marshals to JSON without losing precision.
+func abiValueToJSON(v reflect.Value) interface{} {
+	switch {
+	case v.Type() == bigIntType:
+		return v.Interface().(*big.Int).String()
+	case v.Type() == addressType:
+		return v.Interface().(common.Address).Hex()
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return hexutil.Encode(v.Bytes())
+	case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+		b := make([]byte, v.Len())
+		for i := range b {
+			b[i] = byte(v.Index(i).Uint())
+		}
+		return hexutil.Encode(b)
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			values[i] = abiValueToJSON(v.Index(i))
+		}
+		return values
+	case v.Kind() == reflect.Int64:
+		// Larger than JSON numbers can hold exactly
+		return strconv.FormatInt(v.Int(), 10)
+	case v.Kind() == reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	default:
+		return v.Interface()
+	}
+}
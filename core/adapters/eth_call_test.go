@@ -0,0 +1,139 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newEthCall(t *testing.T, params string) *adapters.EthCall {
+	var adapter adapters.EthCall
+	require.NoError(t, json.Unmarshal([]byte(params), &adapter))
+	return &adapter
+}
+
+func TestEthCall_Perform(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef")
+	holder := "0x0000000000000000000000000000000000000abc"
+	tests := []struct {
+		name     string
+		params   string
+		input    string
+		wantData string
+		response string
+		want     string
+	}{
+		{
+			"single output",
+			`{"address":"` + address.Hex() + `","functionABI":{"name":"balanceOf",
+				"inputs":[{"name":"owner","type":"address"}],
+				"outputs":[{"name":"balance","type":"uint256"}]}}`,
+			`{"result":{"owner":"` + holder + `"}}`,
+			"0x70a08231" + "0000000000000000000000000000000000000000000000000000000000000abc",
+			"0x00000000000000000000000000000000000000000000003635c9adc5dea00000",
+			`"1000000000000000000000"`,
+		},
+		{
+			"several outputs",
+			`{"address":"` + address.Hex() + `","functionABI":{"name":"latestRoundData",
+				"inputs":[],
+				"outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"","type":"bool"},{"name":"oracle","type":"address"}]}}`,
+			`{"result":"ignored"}`,
+			"0xfeaf968c",
+			"0x" +
+				"0000000000000000000000000000000000000000000000000000000000000007" +
+				"fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000abc",
+			`{"roundId":"7","answer":"-2","2":true,"oracle":"` + common.HexToAddress(holder).Hex() + `"}`,
+		},
+		{
+			"bytes output",
+			`{"address":"` + address.Hex() + `","functionABI":{"name":"id",
+				"inputs":[],
+				"outputs":[{"name":"","type":"bytes4"}]}}`,
+			`{}`,
+			"0xaf640d0f",
+			"0xdeadbeef00000000000000000000000000000000000000000000000000000000",
+			`"0xdeadbeef"`,
+		},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			store, cleanup := cltest.NewStore(t)
+			defer cleanup()
+
+			txManager := new(mocks.TxManager)
+			txManager.On("Connected").Return(true)
+			callArgs := eth.CallArgs{To: address, Data: hexutil.MustDecode(test.wantData)}
+			txManager.On("Call", mock.Anything, "eth_call", callArgs, "latest").
+				Return(nil).
+				Run(func(args mock.Arguments) {
+					*args.Get(0).(*string) = test.response
+				})
+			store.TxManager = txManager
+
+			adapter := newEthCall(t, test.params)
+			result := adapter.Perform(cltest.NewRunInputWithString(t, test.input), store)
+			require.NoError(t, result.Error())
+			assert.Equal(t, models.RunStatusCompleted, result.Status())
+			assert.JSONEq(t, test.want, result.Result().Raw)
+
+			txManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEthCall_Perform_NotConnected(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	txManager := new(mocks.TxManager)
+	txManager.On("Connected").Return(false)
+	store.TxManager = txManager
+
+	adapter := newEthCall(t, `{"address":"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef","functionABI":{"name":"decimals","inputs":[],"outputs":[{"name":"","type":"uint8"}]}}`)
+	result := adapter.Perform(cltest.NewRunInputWithResult("ignored"), store)
+	assert.Equal(t, models.RunStatusPendingConnection, result.Status())
+}
+
+func TestEthCall_Perform_ArgumentsNotAnObject(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	txManager := new(mocks.TxManager)
+	txManager.On("Connected").Return(true)
+	store.TxManager = txManager
+
+	adapter := newEthCall(t, `{"address":"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef","functionABI":{"name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}}`)
+	result := adapter.Perform(cltest.NewRunInputWithResult("0xabc"), store)
+	assert.Error(t, result.Error())
+	txManager.AssertNotCalled(t, "Call", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEthCall_UnmarshalJSON_RejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	var adapter adapters.EthCall
+	err := json.Unmarshal([]byte(`{"address":"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef","functionABI":{"name":"decimals","constant":true}}`), &adapter)
+	assert.Error(t, err)
+}
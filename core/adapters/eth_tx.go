@@ -3,6 +3,7 @@ package adapters
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/smartcontractkit/chainlink/core/eth"
 	"github.com/smartcontractkit/chainlink/core/logger"
@@ -10,9 +11,11 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 	"gopkg.in/guregu/null.v3"
 )
 
@@ -29,8 +32,72 @@ type EthTx struct {
 	FunctionSelector eth.FunctionSelector `json:"functionSelector"`
 	DataPrefix       hexutil.Bytes        `json:"dataPrefix"`
 	DataFormat       string               `json:"format"`
+	Encoding         EthTxEncoding        `json:"encoding,omitempty"`
 	GasPrice         *utils.Big           `json:"gasPrice" gorm:"type:numeric"`
 	GasLimit         uint64               `json:"gasLimit"`
+	// MinOutgoingConfirmations is set from the outgoing confirmations of the
+	// task run, for jobs and tasks which need more than the node's minimum.
+	// It is never read from params, which requesters could otherwise lower
+	// it with.
+	MinOutgoingConfirmations uint32 `json:"-"`
+	// Simulate has the transaction called against the pending block before
+	// it is sent, erroring the run without sending it if it would revert.
+	// It is opt-in, as some calls only succeed once mined.
+	Simulate bool `json:"simulate"`
+}
+
+// EthTxEncoding lists the values of the run data sent by an EthTx task, for
+// requests expecting more than the single word of the result. The values are
+// ABI encoded, in order, as the arguments following the data prefix.
+type EthTxEncoding []EthTxEncodingField
+
+// EthTxEncodingField is the ABI type of a value sent by an EthTx task, and
+// the path to the value in the run data.
+type EthTxEncodingField struct {
+	Type string   `json:"type"`
+	Path JSONPath `json:"path"`
+}
+
+// Arguments returns the ABI arguments described by the encoding, erroring if
+// any of the types are invalid or cannot be encoded.
+func (ee EthTxEncoding) Arguments() (abi.Arguments, error) {
+	args := make(abi.Arguments, len(ee))
+	for i, field := range ee {
+		typ, err := abi.NewType(field.Type, "", nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "encoding field %d", i)
+		} else if !isSupportedABIType(&typ) {
+			return nil, errors.Errorf("encoding field %d has unsupported ABI type %s", i, field.Type)
+		} else if len(field.Path) == 0 {
+			return nil, errors.Errorf("encoding field %d has no path", i)
+		}
+		args[i] = abi.Argument{Name: strings.Join(field.Path, "."), Type: typ}
+	}
+	return args, nil
+}
+
+// encode ABI encodes the values at each path of the run data. headSize is
+// the size of the data prefix encoded ahead of them.
+func (ee EthTxEncoding) encode(data models.JSON, headSize int) ([]byte, error) {
+	args, err := ee.Arguments()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		value := data.Get(arg.Name)
+		switch value.Type {
+		case gjson.Null:
+			return nil, errors.Errorf("no value could be found at %s", arg.Name)
+		case gjson.Number:
+			// Passed as a string, keeping integers over 2**53 exact
+			values[arg.Name] = value.Raw
+		default:
+			values[arg.Name] = value.Value()
+		}
+	}
+	return abiEncodeArguments(args, values, headSize)
 }
 
 // TaskType returns the type of Adapter.
@@ -42,12 +109,12 @@ func (e *EthTx) TaskType() models.TaskType {
 // is not currently pending. Then it confirms the transaction was confirmed on
 // the blockchain.
 func (etx *EthTx) Perform(input models.RunInput, store *strpkg.Store) models.RunOutput {
-	if !store.TxManager.Connected() {
+	if !canBroadcast(store) {
 		return pendingConfirmationsOrConnection(input)
 	}
 
 	if input.Status().PendingConfirmations() {
-		return ensureTxRunResult(input, store)
+		return ensureTxRunResult(etx.MinOutgoingConfirmations, input, store)
 	}
 
 	value, err := getTxData(etx, input)
@@ -57,12 +124,19 @@ func (etx *EthTx) Perform(input models.RunInput, store *strpkg.Store) models.Run
 	}
 
 	data := utils.ConcatBytes(etx.FunctionSelector.Bytes(), etx.DataPrefix, value)
-	return createTxRunResult(etx.Address, etx.GasPrice, etx.GasLimit, data, input, store)
+	return createTxRunResult(etx.Address, etx.GasPrice, etx.GasLimit, etx.MinOutgoingConfirmations, etx.Simulate, data, input, store)
 }
 
 // getTxData returns the data to save against the callback encoded according to
 // the dataFormat parameter in the job spec
 func getTxData(e *EthTx, input models.RunInput) ([]byte, error) {
+	if len(e.Encoding) > 0 {
+		if e.DataFormat != "" {
+			return []byte{}, errors.New("cannot set both format and encoding")
+		}
+		return e.Encoding.encode(input.Data(), len(e.DataPrefix))
+	}
+
 	result := input.Result()
 	if e.DataFormat == "" {
 		return common.HexToHash(result.Str).Bytes(), nil
@@ -83,10 +157,24 @@ func createTxRunResult(
 	address common.Address,
 	gasPrice *utils.Big,
 	gasLimit uint64,
+	minConfirmations uint32,
+	simulate bool,
 	data []byte,
 	input models.RunInput,
 	store *strpkg.Store,
 ) models.RunOutput {
+	if err := input.Context().Err(); err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "run execution interrupted, not sending transaction"))
+	}
+	if simulate {
+		err := store.TxManager.SimulateTx(address, data, gasLimit)
+		if revert, ok := err.(*eth.RevertError); ok {
+			return models.NewRunOutputError(errors.Wrap(revert, "simulated transaction reverted, not sending it"))
+		} else if err != nil {
+			logger.Warnw("Unable to simulate transaction, sending it anyway", "address", address.Hex(), "error", err)
+		}
+	}
+
 	tx, err := store.TxManager.CreateTxWithGas(
 		null.StringFrom(input.JobRunID().String()),
 		address,
@@ -111,8 +199,8 @@ func createTxRunResult(
 
 	logger.Debugw(
 		fmt.Sprintf("Tx #0 is %s", state),
-		"txHash", txAttempt.Hash.String(),
-		"txID", txAttempt.TxID,
+		"tx_hash", txAttempt.Hash.String(),
+		"tx_id", txAttempt.TxID,
 		"receiptBlockNumber", receipt.BlockNumber.ToInt(),
 		"currentBlockNumber", tx.SentAt,
 		"receiptHash", receipt.Hash.Hex(),
@@ -124,13 +212,13 @@ func createTxRunResult(
 			err := errors.New("missing receipt for transaction")
 			return models.NewRunOutputError(err)
 		}
-		return addReceiptToResult(*receipt, input, output)
+		return confirmedTxRunResult(*receipt, minConfirmations, input, output, store)
 	}
 
 	return models.NewRunOutputPendingConfirmationsWithData(output)
 }
 
-func ensureTxRunResult(input models.RunInput, str *strpkg.Store) models.RunOutput {
+func ensureTxRunResult(minConfirmations uint32, input models.RunInput, str *strpkg.Store) models.RunOutput {
 	val, err := input.ResultString()
 	if err != nil {
 		return models.NewRunOutputError(err)
@@ -174,12 +262,44 @@ func ensureTxRunResult(input models.RunInput, str *strpkg.Store) models.RunOutpu
 			return models.NewRunOutputError(err)
 		}
 
-		return addReceiptToResult(*receipt, input, output)
+		return confirmedTxRunResult(*receipt, minConfirmations, input, output, str)
 	}
 
 	return models.NewRunOutputPendingConfirmationsWithData(output)
 }
 
+// confirmedTxRunResult adds the receipt of the safe transaction to the result
+// once it has minConfirmations, which jobs and tasks may set above the
+// MIN_OUTGOING_CONFIRMATIONS the transaction is safe at, and keeps the run
+// pending confirmations until then.
+func confirmedTxRunResult(
+	receipt eth.TxReceipt,
+	minConfirmations uint32,
+	input models.RunInput,
+	data models.JSON,
+	store *strpkg.Store,
+) models.RunOutput {
+	if minConfirmations > 0 {
+		head, err := store.ORM.LastHead()
+		if err != nil {
+			return models.NewRunOutputError(err)
+		} else if head == nil {
+			return models.NewRunOutputPendingConfirmationsWithData(data)
+		}
+
+		confirmations := head.Number - receipt.BlockNumber.ToInt().Int64() + 1
+		if confirmations < int64(minConfirmations) {
+			logger.Debugw("Tx is safe, waiting for the confirmations of the task",
+				"receiptHash", receipt.Hash.Hex(),
+				"confirmations", confirmations,
+				"minimumConfirmations", minConfirmations,
+			)
+			return models.NewRunOutputPendingConfirmationsWithData(data)
+		}
+	}
+	return addReceiptToResult(receipt, input, data)
+}
+
 func addReceiptToResult(
 	receipt eth.TxReceipt,
 	input models.RunInput,
@@ -207,6 +327,18 @@ func addReceiptToResult(
 	return models.NewRunOutputComplete(data)
 }
 
+// canBroadcast returns true if transactions can be sent: the node is
+// connected, and the broadcasting of transactions is not paused, as it is
+// while the Ethereum providers disagree on the chain. Runs wait for both as
+// pending connection, resuming on the next head once they can.
+func canBroadcast(store *strpkg.Store) bool {
+	if !store.TxManager.Connected() {
+		return false
+	}
+	_, paused := store.TxManager.BroadcastingPaused()
+	return !paused
+}
+
 func pendingConfirmationsOrConnection(input models.RunInput) models.RunOutput {
 	// If the input is not pending confirmations next time
 	// then it may submit a new transaction.
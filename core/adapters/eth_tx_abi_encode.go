@@ -29,6 +29,11 @@ type EthTxABIEncode struct {
 	FunctionABI abi.Method `json:"functionABI"`
 	GasPrice    *utils.Big `json:"gasPrice" gorm:"type:numeric"`
 	GasLimit    uint64     `json:"gasLimit"`
+	// MinOutgoingConfirmations is set from the outgoing confirmations of the
+	// task run, and never read from params, as for EthTx.
+	MinOutgoingConfirmations uint32 `json:"-"`
+	// Simulate has the transaction called before it is sent, as for EthTx.
+	Simulate bool `json:"simulate"`
 }
 
 // TaskType returns the type of Adapter.
@@ -49,6 +54,7 @@ func (etx *EthTxABIEncode) UnmarshalJSON(data []byte) error {
 		}
 		GasPrice *utils.Big
 		GasLimit uint64
+		Simulate bool
 	}
 
 	decoder := json.NewDecoder(bytes.NewReader(data))
@@ -62,6 +68,7 @@ func (etx *EthTxABIEncode) UnmarshalJSON(data []byte) error {
 	etx.FunctionABI.Inputs = fields.FunctionABI.Inputs
 	etx.GasPrice = fields.GasPrice
 	etx.GasLimit = fields.GasLimit
+	etx.Simulate = fields.Simulate
 	return nil
 }
 
@@ -69,7 +76,7 @@ func (etx *EthTxABIEncode) UnmarshalJSON(data []byte) error {
 // is not currently pending. Then it confirms the transaction was confirmed on
 // the blockchain.
 func (etx *EthTxABIEncode) Perform(input models.RunInput, store *strpkg.Store) models.RunOutput {
-	if !store.TxManager.Connected() {
+	if !canBroadcast(store) {
 		return pendingConfirmationsOrConnection(input)
 	}
 	if !input.Status().PendingConfirmations() {
@@ -78,9 +85,9 @@ func (etx *EthTxABIEncode) Perform(input models.RunInput, store *strpkg.Store) m
 			err = errors.Wrap(err, "while constructing EthTxABIEncode data")
 			return models.NewRunOutputError(err)
 		}
-		return createTxRunResult(etx.Address, etx.GasPrice, etx.GasLimit, data, input, store)
+		return createTxRunResult(etx.Address, etx.GasPrice, etx.GasLimit, etx.MinOutgoingConfirmations, etx.Simulate, data, input, store)
 	}
-	return ensureTxRunResult(input, store)
+	return ensureTxRunResult(etx.MinOutgoingConfirmations, input, store)
 }
 
 // abiEncode ABI-encodes the arguments passed in a RunResult's result field
@@ -101,15 +108,26 @@ func abiEncode(fnABI *abi.Method, args map[string]interface{}) ([]byte, error) {
 			len(fnABI.Inputs))
 	}
 
+	encoded, err := abiEncodeArguments(fnABI.Inputs, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return append(fnABI.ID(), encoded...), nil
+}
+
+// abiEncodeArguments ABI-encodes the arguments in args according to inputs,
+// without a function selector. headSize is the size of any arguments encoded
+// ahead of these, which the offsets of dynamic arguments must account for.
+func abiEncodeArguments(inputs abi.Arguments, args map[string]interface{}, headSize int) ([]byte, error) {
 	encodedStaticPartSize := 0
-	for _, input := range fnABI.Inputs {
+	for _, input := range inputs {
 		encodedStaticPartSize += staticSize(&input.Type)
 	}
 
 	encodedStaticPart := make([]byte, 0, encodedStaticPartSize)
 	encodedDynamicPart := make([]byte, 0)
-	dynamicOffset := encodedStaticPartSize
-	for _, input := range fnABI.Inputs {
+	dynamicOffset := headSize + encodedStaticPartSize
+	for _, input := range inputs {
 		name := input.Name
 		jval, ok := args[name]
 		if !ok {
@@ -143,10 +161,7 @@ func abiEncode(fnABI *abi.Method, args map[string]interface{}) ([]byte, error) {
 		panic("unexpected size of static part")
 	}
 
-	result := fnABI.ID()
-	result = append(result, encodedStaticPart...)
-	result = append(result, encodedDynamicPart...)
-	return result, nil
+	return append(encodedStaticPart, encodedDynamicPart...), nil
 }
 
 // We support every type that solidity contracts as of solc v0.5.11 can decode:
@@ -86,6 +86,7 @@ func TestEthTxAdapter_Perform(t *testing.T) {
 
 			txManager := new(mocks.TxManager)
 			txManager.On("Connected").Once().Return(true)
+			txManager.On("BroadcastingPaused").Maybe().Return("", false)
 
 			tx := &models.Tx{Attempts: []*models.TxAttempt{&models.TxAttempt{}}}
 			txData := hexutil.MustDecode(test.output)
@@ -115,6 +116,10 @@ func TestEthTxAdapter_Perform_BytesFormatWithDataPrefix(t *testing.T) {
 	txManager := new(mocks.TxManager)
 	tx := &models.Tx{Attempts: []*models.TxAttempt{&models.TxAttempt{}}}
 	txManager.On("Connected").Maybe().Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
+	// The data following the function selector is decoded by the
+	// MultiWordConsumer fixture of evm-contracts, which checks it is what a
+	// contract expecting these values receives
 	txManager.On("CreateTxWithGas", mock.Anything, mock.Anything,
 		hexutil.MustDecode("0x"+
 			"00000000"+ // function selector
@@ -144,6 +149,7 @@ func TestEthTxAdapter_Perform_FromPendingConfirmations_StillPending(t *testing.T
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("BumpGasUntilSafe", mock.Anything).Return(&eth.TxReceipt{}, strpkg.Confirmed, nil)
 	store.TxManager = txManager
 
@@ -168,6 +174,7 @@ func TestEthTxAdapter_Perform_FromPendingConfirmations_Safe(t *testing.T) {
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	receiptHash := cltest.NewHash()
 	receipt := &eth.TxReceipt{Hash: receiptHash, BlockNumber: cltest.Int(129831)}
 	txManager.On("BumpGasUntilSafe", mock.Anything).Return(receipt, strpkg.Safe, nil)
@@ -195,6 +202,47 @@ func TestEthTxAdapter_Perform_FromPendingConfirmations_Safe(t *testing.T) {
 	txManager.AssertExpectations(t)
 }
 
+func TestEthTxAdapter_Perform_FromPendingConfirmations_WaitsForMinOutgoingConfirmations(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	txManager := new(mocks.TxManager)
+	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
+	receiptHash := cltest.NewHash()
+	receipt := &eth.TxReceipt{Hash: receiptHash, BlockNumber: cltest.Int(100)}
+	txManager.On("BumpGasUntilSafe", mock.Anything).Return(receipt, strpkg.Safe, nil)
+	store.TxManager = txManager
+
+	adapter := adapters.EthTx{MinOutgoingConfirmations: 20}
+	input := *models.NewRunInputWithResult(
+		models.NewID(), cltest.NewHash(), models.RunStatusPendingConfirmations,
+	)
+
+	require.NoError(t, store.CreateHead(cltest.Head(118)))
+	output := adapter.Perform(input, store)
+	require.NoError(t, output.Error())
+	assert.Equal(t, models.RunStatusPendingConfirmations, output.Status())
+
+	require.NoError(t, store.CreateHead(cltest.Head(119)))
+	output = adapter.Perform(input, store)
+	require.NoError(t, output.Error())
+	assert.Equal(t, models.RunStatusCompleted, output.Status())
+	assert.Equal(t, receiptHash.String(), output.Result().String())
+
+	txManager.AssertExpectations(t)
+}
+
+func TestEthTxAdapter_MinOutgoingConfirmations_NotReadFromParams(t *testing.T) {
+	t.Parallel()
+
+	var adapter adapters.EthTx
+	require.NoError(t, json.Unmarshal([]byte(`{"minOutgoingConfirmations": 1}`), &adapter))
+	assert.Equal(t, uint32(0), adapter.MinOutgoingConfirmations)
+}
+
 func TestEthTxAdapter_Perform_AppendingTransactionReceipts(t *testing.T) {
 	t.Parallel()
 
@@ -203,6 +251,7 @@ func TestEthTxAdapter_Perform_AppendingTransactionReceipts(t *testing.T) {
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	receiptHash := cltest.NewHash()
 	receipt := &eth.TxReceipt{Hash: receiptHash, BlockNumber: cltest.Int(129831)}
 	txManager.On("BumpGasUntilSafe", mock.Anything).Return(receipt, strpkg.Safe, nil)
@@ -241,6 +290,7 @@ func TestEthTxAdapter_Perform_WithError(t *testing.T) {
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("CreateTxWithGas", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Cannot connect to node"))
 	store.TxManager = txManager
 
@@ -252,6 +302,32 @@ func TestEthTxAdapter_Perform_WithError(t *testing.T) {
 	txManager.AssertExpectations(t)
 }
 
+func TestEthTxAdapter_Perform_Simulate(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	txManager := new(mocks.TxManager)
+	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
+	txManager.On("SimulateTx", mock.Anything, mock.Anything, mock.Anything).Once().Return(&eth.RevertError{Reason: "request already fulfilled"})
+	store.TxManager = txManager
+
+	adapter := adapters.EthTx{Simulate: true}
+	output := adapter.Perform(cltest.NewRunInputWithResult("0x9786856756"), store)
+	require.Error(t, output.Error())
+	assert.Contains(t, output.Error().Error(), "request already fulfilled")
+	txManager.AssertNotCalled(t, "CreateTxWithGas", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	txManager.On("SimulateTx", mock.Anything, mock.Anything, mock.Anything).Once().Return(errors.New("connection refused"))
+	txManager.On("CreateTxWithGas", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().Return(nil, errors.New("Cannot connect to node"))
+	output = adapter.Perform(cltest.NewRunInputWithResult("0x9786856756"), store)
+	assert.NoError(t, output.Error(), "transactions which could not be simulated should be sent")
+
+	txManager.AssertExpectations(t)
+}
+
 func TestEthTxAdapter_Perform_PendingConfirmations_WithFatalErrorInTxManager(t *testing.T) {
 	t.Parallel()
 
@@ -260,6 +336,7 @@ func TestEthTxAdapter_Perform_PendingConfirmations_WithFatalErrorInTxManager(t *
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("BumpGasUntilSafe", mock.Anything).Return(nil, strpkg.Unknown, errors.New("Fatal"))
 	store.TxManager = txManager
 
@@ -283,6 +360,7 @@ func TestEthTxAdapter_Perform_PendingConfirmations_WithRecoverableErrorInTxManag
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("BumpGasUntilSafe", mock.Anything).Return(nil, strpkg.Confirmed, errors.New("Connection reset by peer"))
 	store.TxManager = txManager
 
@@ -339,6 +417,27 @@ func TestEthTxAdapter_Perform_NotConnected(t *testing.T) {
 	txManager.AssertExpectations(t)
 }
 
+func TestEthTxAdapter_Perform_BroadcastingPaused(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	txManager := new(mocks.TxManager)
+	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Return("providers disagree", true)
+	store.TxManager = txManager
+
+	adapter := adapters.EthTx{}
+	data := adapter.Perform(cltest.NewRunInputWithResult("0x19999990"), store)
+
+	require.NoError(t, data.Error())
+	assert.Equal(t, models.RunStatusPendingConnection, data.Status())
+
+	txManager.AssertExpectations(t)
+	txManager.AssertNotCalled(t, "CreateTxWithGas", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestEthTxAdapter_Perform_CreateTxWithGasErrorTreatsAsNotConnected(t *testing.T) {
 	t.Parallel()
 
@@ -347,6 +446,7 @@ func TestEthTxAdapter_Perform_CreateTxWithGasErrorTreatsAsNotConnected(t *testin
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("CreateTxWithGas",
 		mock.Anything,
 		mock.Anything,
@@ -373,6 +473,7 @@ func TestEthTxAdapter_Perform_CheckAttemptErrorTreatsAsNotConnected(t *testing.T
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("CreateTxWithGas",
 		mock.Anything,
 		mock.Anything,
@@ -406,6 +507,7 @@ func TestEthTxAdapter_Perform_CreateTxWithEmptyResponseErrorTreatsAsPendingConfi
 	badResponseErr := errors.New("Bad response on request: [ TransactionIndex ]. Error cause was EmptyResponse, (majority count: 94 / total: 94)")
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("CreateTxWithGas",
 		mock.Anything,
 		mock.Anything,
@@ -424,6 +526,7 @@ func TestEthTxAdapter_Perform_CreateTxWithEmptyResponseErrorTreatsAsPendingConfi
 
 	// Have a head come through with the same empty response
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("BumpGasUntilSafe", mock.Anything).Return(nil, strpkg.Unknown, badResponseErr)
 
 	input := *models.NewRunInput(models.NewID(), output.Data(), output.Status())
@@ -444,6 +547,7 @@ func TestEthTxAdapter_Perform_NoDoubleSpendOnSendTransactionFail(t *testing.T) {
 
 	txManager := new(mocks.TxManager)
 	txManager.On("Connected").Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
 	txManager.On("CreateTxWithGas",
 		mock.Anything,
 		mock.Anything,
@@ -477,3 +581,70 @@ func TestEthTxAdapter_Perform_NoDoubleSpendOnSendTransactionFail(t *testing.T) {
 
 	txManager.AssertExpectations(t)
 }
+
+func TestEthTxAdapter_Perform_WithEncoding(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	txManager := new(mocks.TxManager)
+	tx := &models.Tx{Attempts: []*models.TxAttempt{&models.TxAttempt{}}}
+	txManager.On("Connected").Maybe().Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
+	// The data following the function selector is decoded by the
+	// MultiWordConsumer fixture of evm-contracts, which checks it is what a
+	// contract expecting these values receives
+	txManager.On("CreateTxWithGas", mock.Anything, mock.Anything,
+		hexutil.MustDecode("0x"+
+			"00000000"+ // function selector
+			"8888888888888888888888888888888888888888888888888888888888888888"+ // data prefix
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"+ // price, exceeding a float64
+			"4554480000000000000000000000000000000000000000000000000000000000"+ // symbol
+			"00000000000000000000000000000000000000000000000000000000000000a0"+ // offset of name, counting the prefix
+			"0000000000000000000000000000000000000000000000000000000000000001"+ // up
+			"0000000000000000000000000000000000000000000000000000000000000005"+ // length of name
+			"6574686572000000000000000000000000000000000000000000000000000000"), // name
+		mock.Anything, mock.Anything).Return(tx, nil)
+	txManager.On("CheckAttempt", mock.Anything, mock.Anything).Return(&eth.TxReceipt{}, strpkg.Unconfirmed, nil)
+	store.TxManager = txManager
+
+	adapter := adapters.EthTx{
+		DataPrefix: hexutil.MustDecode("0x8888888888888888888888888888888888888888888888888888888888888888"),
+		Encoding: adapters.EthTxEncoding{
+			{Type: "uint256", Path: []string{"result", "price"}},
+			{Type: "bytes32", Path: []string{"result", "symbol"}},
+			{Type: "string", Path: []string{"name"}},
+			{Type: "bool", Path: []string{"result", "up"}},
+		},
+	}
+	input := cltest.NewRunInputWithString(t, `{"result":{
+		"price":115792089237316195423570985008687907853269984665640564039457584007913129639935,
+		"symbol":"0x4554480000000000000000000000000000000000000000000000000000000000",
+		"up":true
+	},"name":"ether"}`)
+	result := adapter.Perform(input, store)
+
+	assert.NoError(t, result.Error())
+	assert.Equal(t, models.RunStatusPendingConfirmations, result.Status())
+
+	txManager.AssertExpectations(t)
+}
+
+func TestEthTxAdapter_Perform_WithEncodingMissingValue(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	txManager := new(mocks.TxManager)
+	txManager.On("Connected").Maybe().Return(true)
+	txManager.On("BroadcastingPaused").Maybe().Return("", false)
+	store.TxManager = txManager
+
+	adapter := adapters.EthTx{
+		Encoding: adapters.EthTxEncoding{{Type: "uint256", Path: []string{"result", "price"}}},
+	}
+	result := adapter.Perform(cltest.NewRunInputWithString(t, `{"result":{"volume":1}}`), store)
+	assert.Error(t, result.Error())
+}
@@ -3,8 +3,8 @@ package adapters
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,6 +20,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/avast/retry-go"
+	"github.com/pkg/errors"
 )
 
 // HTTPGet requires a URL which is used for a GET request when the adapter is called.
@@ -29,6 +30,7 @@ type HTTPGet struct {
 	Headers                        http.Header     `json:"headers"`
 	QueryParams                    QueryParameters `json:"queryParams"`
 	ExtendedPath                   ExtendedPath    `json:"extPath"`
+	Timeout                        models.Duration `json:"timeout"`
 	AllowUnrestrictedNetworkAccess bool            `json:"-"`
 }
 
@@ -38,6 +40,10 @@ type HTTPRequestConfig struct {
 	maxAttempts                    uint
 	sizeLimit                      int64
 	allowUnrestrictedNetworkAccess bool
+	tlsConfig                      *tls.Config
+	maxRedirects                   uint
+	allowedHosts                   hostFilter
+	deniedHosts                    hostFilter
 }
 
 // TaskType returns the type of Adapter.
@@ -52,8 +58,14 @@ func (hga *HTTPGet) Perform(input models.RunInput, store *store.Store) models.Ru
 	if err != nil {
 		return models.NewRunOutputError(err)
 	}
-	httpConfig := defaultHTTPConfig(store)
+	httpConfig, err := defaultHTTPConfig(store)
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
 	httpConfig.allowUnrestrictedNetworkAccess = hga.AllowUnrestrictedNetworkAccess
+	if !hga.Timeout.IsInstant() {
+		httpConfig.timeout = hga.Timeout.Duration()
+	}
 	return sendRequest(input, request, httpConfig)
 }
 
@@ -85,6 +97,7 @@ type HTTPPost struct {
 	QueryParams                    QueryParameters `json:"queryParams"`
 	Body                           *string         `json:"body,omitempty"`
 	ExtendedPath                   ExtendedPath    `json:"extPath"`
+	Timeout                        models.Duration `json:"timeout"`
 	AllowUnrestrictedNetworkAccess bool            `json:"-"`
 }
 
@@ -100,8 +113,14 @@ func (hpa *HTTPPost) Perform(input models.RunInput, store *store.Store) models.R
 	if err != nil {
 		return models.NewRunOutputError(err)
 	}
-	httpConfig := defaultHTTPConfig(store)
+	httpConfig, err := defaultHTTPConfig(store)
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
 	httpConfig.allowUnrestrictedNetworkAccess = hpa.AllowUnrestrictedNetworkAccess
+	if !hpa.Timeout.IsInstant() {
+		httpConfig.timeout = hpa.Timeout.Duration()
+	}
 	return sendRequest(input, request, httpConfig)
 }
 
@@ -162,15 +181,8 @@ func setHeaders(request *http.Request, headers http.Header, contentType string)
 }
 
 func sendRequest(input models.RunInput, request *http.Request, config HTTPRequestConfig) models.RunOutput {
-	tr := &http.Transport{
-		DisableCompression: true,
-	}
-	if !config.allowUnrestrictedNetworkAccess {
-		tr.DialContext = restrictedDialContext
-	}
-	client := &http.Client{Transport: tr}
-
-	bytes, statusCode, err := withRetry(client, request, config)
+	client := newHTTPClient(config)
+	bytes, statusCode, err := withRetry(client, request.WithContext(input.Context()), config)
 	if err != nil {
 		return models.NewRunOutputError(err)
 	}
@@ -187,6 +199,7 @@ func sendRequest(input models.RunInput, request *http.Request, config HTTPReques
 }
 
 // withRetry executes the http request in a retry. Timeout is controlled with a context
+// derived from that of the request, which stops the retries once it is done.
 // Retry occurs if the request timeout, or there is any kind of connection or transport-layer error
 // Retry also occurs on remote server 5xx errors
 func withRetry(
@@ -196,7 +209,7 @@ func withRetry(
 ) (responseBody []byte, statusCode int, err error) {
 	err = retry.Do(
 		func() error {
-			ctx, cancel := context.WithTimeout(context.Background(), config.timeout)
+			ctx, cancel := context.WithTimeout(originalRequest.Context(), config.timeout)
 			defer cancel()
 			requestWithTimeout := originalRequest.Clone(ctx)
 
@@ -231,6 +244,9 @@ func withRetry(
 		},
 		retry.Attempts(config.maxAttempts),
 		retry.RetryIf(func(err error) bool {
+			if originalRequest.Context().Err() != nil {
+				return false
+			}
 			switch err.(type) {
 			// There is no point in retrying a request if the response was
 			// too large since it's likely that all retries will suffer the
@@ -395,11 +411,21 @@ func (ep *ExtendedPath) UnmarshalJSON(input []byte) error {
 	return err
 }
 
-func defaultHTTPConfig(store *store.Store) HTTPRequestConfig {
-	return HTTPRequestConfig{
-		store.Config.DefaultHTTPTimeout().Duration(),
-		store.Config.DefaultMaxHTTPAttempts(),
-		store.Config.DefaultHTTPLimit(),
-		false,
+func defaultHTTPConfig(store *store.Store) (HTTPRequestConfig, error) {
+	config := HTTPRequestConfig{
+		timeout:      store.Config.DefaultHTTPTimeout().Duration(),
+		maxAttempts:  store.Config.DefaultMaxHTTPAttempts(),
+		sizeLimit:    store.Config.DefaultHTTPLimit(),
+		maxRedirects: store.Config.HTTPMaxRedirects(),
+	}
+
+	var err error
+	if config.allowedHosts, err = newHostFilter(store.Config.HTTPAllowedHosts()); err != nil {
+		return config, errors.Wrap(err, "invalid HTTP_ALLOWED_HOSTS")
+	}
+	if config.deniedHosts, err = newHostFilter(store.Config.HTTPDeniedHosts()); err != nil {
+		return config, errors.Wrap(err, "invalid HTTP_DENIED_HOSTS")
 	}
+	config.tlsConfig, err = ClientTLSConfig("", store)
+	return config, err
 }
@@ -1,10 +1,8 @@
 package adapters
 
 import (
-	"context"
 	"fmt"
 	"net"
-	"time"
 )
 
 var privateIPBlocks []*net.IPNet
@@ -50,26 +48,3 @@ func isRestrictedIP(ip net.IP) bool {
 	}
 	return false
 }
-
-// restrictedDialContext wraps the Dialer such that after successful connection,
-// we check the IP.
-// If the resolved IP is restricted, close the connection and return an error.
-func restrictedDialContext(ctx context.Context, network, address string) (net.Conn, error) {
-	con, err := (&net.Dialer{
-		// Defaults from GoLang standard http package
-		// https://golang.org/pkg/net/http/#RoundTripper
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-		DualStack: true,
-	}).DialContext(ctx, network, address)
-	if err == nil {
-		// If a connection could be established, ensure its not local or private
-		a, _ := con.RemoteAddr().(*net.TCPAddr)
-
-		if isRestrictedIP(a.IP) {
-			defer con.Close()
-			return nil, fmt.Errorf("disallowed IP %s. Connections to local/private and multicast networks are disabled by default for security reasons. If you really want to allow this, consider using the httpgetwithunrestrictednetworkaccess or httppostwithunrestrictednetworkaccess adapter instead", a.IP.String())
-		}
-	}
-	return con, err
-}
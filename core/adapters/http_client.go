@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// hostFilter matches hosts against a list of hostnames and networks, set
+// with HTTP_ALLOWED_HOSTS or HTTP_DENIED_HOSTS.
+//
+// An entry is either a CIDR block or a single IP, matched against the IP
+// connected to, or a hostname, matched against the host of the URL. A
+// hostname starting with "*." also matches all of its subdomains.
+type hostFilter struct {
+	networks  []*net.IPNet
+	hostnames []string
+}
+
+func newHostFilter(entries []string) (hostFilter, error) {
+	var hf hostFilter
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return hf, errors.Wrapf(err, "parsing host filter %s", entry)
+			}
+			hf.networks = append(hf.networks, network)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			hf.networks = append(hf.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		} else {
+			hf.hostnames = append(hf.hostnames, entry)
+		}
+	}
+	return hf, nil
+}
+
+func (hf hostFilter) empty() bool {
+	return len(hf.networks) == 0 && len(hf.hostnames) == 0
+}
+
+func (hf hostFilter) matchIP(ip net.IP) bool {
+	for _, network := range hf.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (hf hostFilter) matchHostname(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, hostname := range hf.hostnames {
+		if strings.HasPrefix(hostname, "*.") {
+			if strings.HasSuffix(host, hostname[1:]) {
+				return true
+			}
+		} else if host == hostname {
+			return true
+		}
+	}
+	return false
+}
+
+// newHTTPClient returns the client used by the http adapters, enforcing the
+// redirect policy and the allowed and denied hosts of the given config.
+//
+// Hosts are checked when connecting rather than against the requested URL,
+// so that the checks also cover redirects and hostnames resolving to a
+// denied IP.
+func newHTTPClient(config HTTPRequestConfig) *http.Client {
+	tr := &http.Transport{
+		DisableCompression: true,
+		TLSClientConfig:    config.tlsConfig,
+		DialContext:        config.dialContext,
+	}
+	return &http.Client{
+		Transport: tr,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if uint(len(via)) > config.maxRedirects {
+				return fmt.Errorf("stopped after %d redirects, set HTTP_MAX_REDIRECTS to follow more", config.maxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// dialContext connects to the address, then checks that the host and the IP
+// connected to are allowed. Connections to local/private networks are only
+// allowed if the adapter has unrestricted network access, or the network is
+// explicitly allowed with HTTP_ALLOWED_HOSTS.
+func (config HTTPRequestConfig) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if config.deniedHosts.matchHostname(host) {
+		return nil, fmt.Errorf("disallowed host %s, denied by HTTP_DENIED_HOSTS", host)
+	}
+
+	con, err := (&net.Dialer{
+		// Defaults from GoLang standard http package
+		// https://golang.org/pkg/net/http/#RoundTripper
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		DualStack: true,
+	}).DialContext(ctx, network, address)
+	if err != nil {
+		return con, err
+	}
+
+	ip := con.RemoteAddr().(*net.TCPAddr).IP
+	if err := config.checkConnection(host, ip); err != nil {
+		con.Close()
+		return nil, err
+	}
+	return con, nil
+}
+
+func (config HTTPRequestConfig) checkConnection(host string, ip net.IP) error {
+	if config.deniedHosts.matchIP(ip) {
+		return fmt.Errorf("disallowed IP %s, denied by HTTP_DENIED_HOSTS", ip.String())
+	}
+
+	explicitlyAllowed := config.allowedHosts.matchIP(ip)
+	if !config.allowedHosts.empty() && !explicitlyAllowed && !config.allowedHosts.matchHostname(host) {
+		return fmt.Errorf("disallowed host %s, not in HTTP_ALLOWED_HOSTS", host)
+	}
+	if !config.allowUnrestrictedNetworkAccess && !explicitlyAllowed && isRestrictedIP(ip) {
+		return fmt.Errorf("disallowed IP %s. Connections to local/private and multicast networks are disabled by default for security reasons. If you really want to allow this, consider using the httpgetwithunrestrictednetworkaccess or httppostwithunrestrictednetworkaccess adapter instead", ip.String())
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package adapters
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_hostFilter(t *testing.T) {
+	t.Parallel()
+
+	hf, err := newHostFilter([]string{"10.1.0.0/16", " 203.0.113.7 ", "API.example.com", "*.data.example.com", ""})
+	require.NoError(t, err)
+
+	assert.True(t, hf.matchIP(net.ParseIP("10.1.2.3")))
+	assert.False(t, hf.matchIP(net.ParseIP("10.2.0.1")))
+	assert.True(t, hf.matchIP(net.ParseIP("203.0.113.7")))
+	assert.False(t, hf.matchIP(net.ParseIP("203.0.113.8")))
+
+	assert.True(t, hf.matchHostname("api.example.com"))
+	assert.True(t, hf.matchHostname("api.example.com."))
+	assert.False(t, hf.matchHostname("example.com"))
+	assert.True(t, hf.matchHostname("eu.data.example.com"))
+	assert.False(t, hf.matchHostname("data.example.com"))
+	assert.False(t, hf.matchHostname("evildata.example.com"))
+
+	_, err = newHostFilter([]string{"10.1.0.0/99"})
+	assert.Error(t, err)
+}
+
+func TestHTTPClient_checkConnection(t *testing.T) {
+	t.Parallel()
+
+	allowed, err := newHostFilter([]string{"10.1.0.0/16", "api.example.com"})
+	require.NoError(t, err)
+	denied, err := newHostFilter([]string{"1.1.1.1", "evil.example.com"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		config      HTTPRequestConfig
+		host        string
+		ip          string
+		wantAllowed bool
+	}{
+		{"public", HTTPRequestConfig{}, "example.com", "93.184.216.34", true},
+		{"private", HTTPRequestConfig{}, "internal", "10.1.2.3", false},
+		{"private unrestricted", HTTPRequestConfig{allowUnrestrictedNetworkAccess: true}, "internal", "10.1.2.3", true},
+		{"denied ip", HTTPRequestConfig{deniedHosts: denied}, "one.one.one.one", "1.1.1.1", false},
+		{"denied ip unrestricted", HTTPRequestConfig{deniedHosts: denied, allowUnrestrictedNetworkAccess: true}, "one.one.one.one", "1.1.1.1", false},
+		{"allowed hostname", HTTPRequestConfig{allowedHosts: allowed}, "api.example.com", "93.184.216.34", true},
+		{"allowed hostname resolving to private ip", HTTPRequestConfig{allowedHosts: allowed}, "api.example.com", "192.168.0.1", false},
+		{"allowed private network", HTTPRequestConfig{allowedHosts: allowed}, "internal", "10.1.2.3", true},
+		{"not allowed", HTTPRequestConfig{allowedHosts: allowed}, "example.com", "93.184.216.34", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.checkConnection(test.host, net.ParseIP(test.ip))
+			assert.Equal(t, test.wantAllowed, err == nil, "unexpected result %v", err)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package adapters_test
 import (
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
@@ -170,6 +172,75 @@ func TestHTTP_PerformWithRestrictedIP(t *testing.T) {
 	}
 }
 
+func TestHTTP_PerformWithDeniedHost(t *testing.T) {
+	cfg := orm.NewConfig()
+	cfg.Set("HTTP_DENIED_HOSTS", "127.0.0.0/8")
+	store := &store.Store{Config: cfg}
+
+	mock, _ := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", "")
+	defer mock.Close()
+
+	hga := &adapters.HTTPGet{URL: cltest.WebURL(t, mock.URL), AllowUnrestrictedNetworkAccess: true}
+	result := hga.Perform(cltest.NewRunInputWithResult("inputValue"), store)
+	require.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "denied by HTTP_DENIED_HOSTS")
+}
+
+func TestHTTP_PerformFollowsLimitedRedirects(t *testing.T) {
+	cfg := orm.NewConfig()
+	cfg.Set("HTTP_MAX_REDIRECTS", "1")
+	cfg.Set("MAX_HTTP_ATTEMPTS", "1")
+	store := &store.Store{Config: cfg}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/once":
+			http.Redirect(w, r, server.URL+"/done", http.StatusFound)
+		case "/twice":
+			http.Redirect(w, r, server.URL+"/once", http.StatusFound)
+		default:
+			w.Write([]byte("done"))
+		}
+	}))
+	defer server.Close()
+
+	hga := &adapters.HTTPGet{URL: cltest.WebURL(t, server.URL+"/once"), AllowUnrestrictedNetworkAccess: true}
+	result := hga.Perform(cltest.NewRunInputWithResult("inputValue"), store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, "done", result.Result().String())
+
+	hga = &adapters.HTTPGet{URL: cltest.WebURL(t, server.URL+"/twice"), AllowUnrestrictedNetworkAccess: true}
+	result = hga.Perform(cltest.NewRunInputWithResult("inputValue"), store)
+	require.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "stopped after 1 redirects")
+}
+
+func TestHTTP_PerformWithTimeoutOverride(t *testing.T) {
+	cfg := orm.NewConfig()
+	cfg.Set("MAX_HTTP_ATTEMPTS", "1")
+	store := &store.Store{Config: cfg}
+
+	chDone := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-chDone:
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(chDone)
+
+	var hpa adapters.HTTPPost
+	require.NoError(t, json.Unmarshal([]byte(`{"post":"`+server.URL+`","timeout":"10ms"}`), &hpa))
+	hpa.AllowUnrestrictedNetworkAccess = true
+	assert.Equal(t, 10*time.Millisecond, hpa.Timeout.Duration())
+
+	result := hpa.Perform(cltest.NewRunInputWithResult("inputValue"), store)
+	require.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "context deadline exceeded")
+}
+
 func stringRef(str string) *string {
 	return &str
 }
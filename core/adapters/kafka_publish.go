@@ -0,0 +1,48 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// ErrKafkaTopicNotSpecified is returned when a KafkaPublish task has no topic.
+var ErrKafkaTopicNotSpecified = errors.New("Topic not specified")
+
+// KafkaPublish adapter publishes the run's data to a kafka topic, keyed by
+// the ID of the run unless a Key is given.
+type KafkaPublish struct {
+	Topic string `json:"topic"`
+	Key   string `json:"key"`
+}
+
+// TaskType returns the type of Adapter.
+func (k *KafkaPublish) TaskType() models.TaskType {
+	return TaskTypeKafkaPublish
+}
+
+// Perform publishes the input data to the configured topic and passes it on
+// unchanged.
+func (k *KafkaPublish) Perform(input models.RunInput, store *store.Store) models.RunOutput {
+	if k.Topic == "" {
+		return models.NewRunOutputError(ErrKafkaTopicNotSpecified)
+	}
+
+	value, err := input.Data().MarshalJSON()
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+	key := k.Key
+	if key == "" {
+		key = input.JobRunID().String()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.Config.DefaultHTTPTimeout().Duration())
+	defer cancel()
+	if err := store.KafkaClient.Publish(ctx, k.Topic, []byte(key), value); err != nil {
+		return models.NewRunOutputError(err)
+	}
+	return models.NewRunOutputComplete(input.Data())
+}
@@ -0,0 +1,61 @@
+package adapters_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestKafkaPublish_Perform(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	jobRunID := models.NewID()
+	input := *models.NewRunInput(jobRunID, cltest.JSONFromString(t, `{"result":"100"}`), models.RunStatusUnstarted)
+
+	tests := []struct {
+		name        string
+		adapter     adapters.KafkaPublish
+		wantKey     string
+		publishErr  error
+		wantErrored bool
+	}{
+		{"default key", adapters.KafkaPublish{Topic: "results"}, jobRunID.String(), nil, false},
+		{"custom key", adapters.KafkaPublish{Topic: "results", Key: "eth-usd"}, "eth-usd", nil, false},
+		{"publish error", adapters.KafkaPublish{Topic: "results"}, jobRunID.String(), errors.New("broker down"), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := new(mocks.KafkaClient)
+			store.KafkaClient = client
+			client.On("Publish", mock.Anything, "results", []byte(test.wantKey), []byte(`{"result":"100"}`)).
+				Return(test.publishErr)
+
+			result := test.adapter.Perform(input, store)
+
+			assert.Equal(t, test.wantErrored, result.HasError())
+			if !test.wantErrored {
+				assert.Equal(t, "100", result.Result().String())
+			}
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestKafkaPublish_Perform_RequiresTopic(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	adapter := adapters.KafkaPublish{}
+	result := adapter.Perform(models.RunInput{}, store)
+
+	assert.Equal(t, adapters.ErrKafkaTopicNotSpecified, result.Error())
+}
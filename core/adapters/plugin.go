@@ -0,0 +1,235 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+)
+
+// PluginManifest describes the task type an adapter plugin registers, as the
+// plugin prints it when run with the describe argument.
+type PluginManifest struct {
+	TaskType       models.TaskType `json:"taskType"`
+	Description    string          `json:"description"`
+	RequiredParams []string        `json:"requiredParams"`
+}
+
+// Plugin is the adapter of a task whose type is registered by an executable
+// in ADAPTER_PLUGINS_DIR. The plugin is run with the perform argument for
+// every task, reading the request a bridge would be sent from its stdin, and
+// writing the response a bridge would give to its stdout.
+type Plugin struct {
+	PluginManifest
+	Path    string
+	Timeout time.Duration
+	Params  models.JSON
+}
+
+// pluginRequest is the request a plugin reads from its stdin.
+type pluginRequest struct {
+	ID   string      `json:"id"`
+	Data models.JSON `json:"data"`
+}
+
+var plugins = struct {
+	sync.RWMutex
+	byType map[models.TaskType]Plugin
+}{byType: map[models.TaskType]Plugin{}}
+
+// LoadPlugins registers the task types of the executables in dir, replacing
+// those registered before. The plugins which cannot describe themselves, or
+// whose task types are those of the node's own adapters, of bridges or of
+// other plugins, are logged and not registered, so that they do not stop the
+// node from starting. Only a dir which cannot be read is an error, and
+// nothing is done if dir is empty. The task types of bridges are not checked
+// if orm is nil, as when the CLI loads the plugins to validate job specs.
+func LoadPlugins(dir string, timeout time.Duration, orm *orm.ORM) error {
+	if dir == "" {
+		return nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err, "while loading adapter plugins")
+	}
+
+	loaded := map[models.TaskType]Plugin{}
+	for _, file := range files {
+		if file.IsDir() || file.Mode()&0111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		plugin, err := describePlugin(path, timeout)
+		if err == nil {
+			if _, builtin, _ := ForBuiltin(models.TaskSpec{Type: plugin.PluginManifest.TaskType}); builtin {
+				err = fmt.Errorf("task type %s is that of a core adapter", plugin.PluginManifest.TaskType)
+			} else if orm != nil && isBridge(orm, plugin.PluginManifest.TaskType) {
+				err = fmt.Errorf("task type %s is that of a bridge", plugin.PluginManifest.TaskType)
+			} else if other, ok := loaded[plugin.PluginManifest.TaskType]; ok {
+				err = fmt.Errorf("task type %s is registered by %s too", plugin.PluginManifest.TaskType, other.Path)
+			}
+		}
+		if err != nil {
+			logger.Errorw("Unable to register adapter plugin", "path", path, "error", err)
+			continue
+		}
+		logger.Infow("Registered adapter plugin", "taskType", plugin.PluginManifest.TaskType, "path", path)
+		loaded[plugin.PluginManifest.TaskType] = plugin
+	}
+
+	plugins.Lock()
+	defer plugins.Unlock()
+	plugins.byType = loaded
+	return nil
+}
+
+func isBridge(orm *orm.ORM, taskType models.TaskType) bool {
+	_, err := orm.FindBridge(taskType)
+	return err == nil
+}
+
+// IsPlugin returns whether the task type is registered by an adapter plugin.
+func IsPlugin(taskType models.TaskType) bool {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	_, ok := plugins.byType[taskType]
+	return ok
+}
+
+// Plugins returns the manifests of the registered plugins, by task type.
+func Plugins() []PluginManifest {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	manifests := make([]PluginManifest, 0, len(plugins.byType))
+	for _, plugin := range plugins.byType {
+		manifests = append(manifests, plugin.PluginManifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].TaskType.String() < manifests[j].TaskType.String()
+	})
+	return manifests
+}
+
+// forPlugin returns the adapter of a task whose type is registered by a
+// plugin, and false if it is not.
+func forPlugin(task models.TaskSpec) (*Plugin, bool) {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	plugin, ok := plugins.byType[task.Type]
+	if !ok {
+		return nil, false
+	}
+	plugin.Params = task.Params
+	return &plugin, true
+}
+
+func describePlugin(path string, timeout time.Duration) (Plugin, error) {
+	output, err := runPlugin(context.Background(), path, "describe", nil, timeout)
+	if err != nil {
+		return Plugin{}, err
+	}
+	var manifest PluginManifest
+	if err := json.Unmarshal(output, &manifest); err != nil {
+		return Plugin{}, errors.Wrap(err, "while parsing its description")
+	}
+	if manifest.TaskType.String() == "" {
+		return Plugin{}, errors.New("its description has no taskType")
+	}
+	return Plugin{PluginManifest: manifest, Path: path, Timeout: timeout}, nil
+}
+
+// runPlugin runs the plugin with the argument, writing input to its stdin,
+// and returns what it wrote to its stdout, or an error with what it wrote
+// to its stderr if it failed. It is killed after timeout, unless timeout is
+// zero. Only PATH is passed on from the environment of the node, which holds
+// its secrets.
+func runPlugin(ctx context.Context, path, arg string, input []byte, timeout time.Duration) ([]byte, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, arg)
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s timed out after %s", arg, timeout)
+		}
+		return nil, errors.Wrapf(err, "%s failed: %s", arg, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// TaskType returns the task type the plugin registers.
+func (p *Plugin) TaskType() models.TaskType {
+	return p.PluginManifest.TaskType
+}
+
+// ValidateParams returns an error if the params of the task lack one of
+// those the plugin requires.
+func (p *Plugin) ValidateParams() error {
+	var missing []string
+	for _, name := range p.RequiredParams {
+		if !p.Params.Get(name).Exists() {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s Task requires the params %s", p.PluginManifest.TaskType, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Perform runs the plugin with the run's data merged with the params of the
+// task, returning the data it responds with. Plugins cannot leave runs
+// pending.
+func (p *Plugin) Perform(input models.RunInput, _ *store.Store) models.RunOutput {
+	data, err := models.Merge(input.Data(), p.Params)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "error merging plugin params with input params"))
+	}
+	request, err := json.Marshal(pluginRequest{ID: input.JobRunID().String(), Data: data})
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+
+	output, err := runPlugin(input.Context(), p.Path, "perform", request, p.Timeout)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrapf(err, "adapter plugin %s", p.PluginManifest.TaskType))
+	}
+
+	var brr models.BridgeRunResult
+	if err := json.Unmarshal(output, &brr); err != nil {
+		return models.NewRunOutputError(errors.Wrapf(err, "adapter plugin %s responded with invalid JSON", p.PluginManifest.TaskType))
+	}
+	if brr.HasError() {
+		return models.NewRunOutputError(brr.GetError())
+	}
+	if brr.ExternalPending {
+		return models.NewRunOutputError(fmt.Errorf("adapter plugin %s cannot leave runs pending", p.PluginManifest.TaskType))
+	}
+	if brr.Data.IsObject() {
+		return models.NewRunOutputComplete(brr.Data)
+	}
+	return models.NewRunOutputCompleteWithResult(brr.Data.String())
+}
@@ -0,0 +1,89 @@
+package adapters_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin writes an adapter plugin which describes itself with
+// description, and performs tasks by running perform with the request on
+// its stdin.
+func writePlugin(t *testing.T, dir, name, description, perform string) {
+	script := "#!/bin/sh\nif [ \"$1\" = describe ]; then\n  echo '" + description + "'\nelse\n  " + perform + "\nfi\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+}
+
+func TestLoadPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	empty, err := ioutil.TempDir("", "noplugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(empty)
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	defer adapters.LoadPlugins(empty, time.Second, store.ORM)
+	_, bridge := cltest.NewBridgeType(t, "pluginbridge")
+	require.NoError(t, store.CreateBridgeType(bridge))
+
+	writePlugin(t, dir, "echo", `{"taskType":"pluginecho","requiredParams":["path"]}`,
+		`read request; echo "{\"data\":{\"result\":\"done\"}}"`)
+	writePlugin(t, dir, "failing", `{"taskType":"pluginfailing"}`, `echo "no quote" >&2; exit 1`)
+	writePlugin(t, dir, "copy", `{"taskType":"copy"}`, `exit 0`)
+	writePlugin(t, dir, "broken", `not json`, `exit 0`)
+	writePlugin(t, dir, "shadowing", `{"taskType":"pluginbridge"}`, `exit 0`)
+	writePlugin(t, dir, "env", `{"taskType":"pluginenv"}`,
+		`read request; echo "{\"data\":{\"result\":\"${PLUGIN_TEST_SECRET:-unset}\"}}"`)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0644))
+
+	// The plugins which cannot be registered are skipped without failing
+	require.NoError(t, adapters.LoadPlugins(dir, time.Second, store.ORM))
+	require.Error(t, adapters.LoadPlugins(filepath.Join(dir, "missing"), time.Second, store.ORM))
+	require.NoError(t, adapters.LoadPlugins(dir, time.Second, store.ORM))
+
+	manifests := adapters.Plugins()
+	require.Len(t, manifests, 3)
+	assert.Equal(t, "pluginecho", manifests[0].TaskType.String())
+	assert.Equal(t, "pluginenv", manifests[1].TaskType.String())
+	assert.Equal(t, "pluginfailing", manifests[2].TaskType.String())
+
+	input := cltest.NewRunInputWithResult("100")
+
+	task := models.TaskSpec{Type: models.MustNewTaskType("pluginecho"), Params: cltest.JSONFromString(t, `{}`)}
+	adapter, err := adapters.For(task, store.Config, store.ORM)
+	require.NoError(t, err)
+	plugin, ok := adapter.BaseAdapter.(*adapters.Plugin)
+	require.True(t, ok)
+	assert.EqualError(t, plugin.ValidateParams(), "pluginecho Task requires the params path")
+
+	result := adapter.Perform(input, store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, "done", result.Result().String())
+
+	task = models.TaskSpec{Type: models.MustNewTaskType("pluginfailing")}
+	adapter, err = adapters.For(task, store.Config, store.ORM)
+	require.NoError(t, err)
+	result = adapter.Perform(input, store)
+	require.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "no quote")
+
+	// The environment of the node, which holds its secrets, is not passed on
+	os.Setenv("PLUGIN_TEST_SECRET", "secret")
+	defer os.Unsetenv("PLUGIN_TEST_SECRET")
+	task = models.TaskSpec{Type: models.MustNewTaskType("pluginenv")}
+	adapter, err = adapters.For(task, store.Config, store.ORM)
+	require.NoError(t, err)
+	result = adapter.Perform(input, store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, "unset", result.Result().String())
+}
@@ -0,0 +1,194 @@
+package adapters
+
+import (
+	"fmt"
+	"math/big"
+
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// SignResultEIP191 signs the keccak256 hash of the run's ID followed by
+	// its result, as a personal message.
+	SignResultEIP191 = "eip191"
+	// SignResultEIP712 signs the run's ID and result as EIP-712 typed data.
+	SignResultEIP712 = "eip712"
+
+	// SignResultDomainName and SignResultDomainVersion are those of the
+	// EIP-712 domain results are signed in.
+	SignResultDomainName    = "Chainlink"
+	SignResultDomainVersion = "1"
+)
+
+// signResultTypes are the EIP-712 types results can be signed as.
+var signResultTypes = []string{"string", "bool", "address", "uint256", "int256", "bytes32"}
+
+// SignResult signs the result of the run with the node's account, so that
+// consumers can verify results delivered off-chain. The result is left as it
+// is, the signature, the address of the account and the hash it signed being
+// added to the data as signature, signer and signedHash, so that
+// ecrecover(signedHash, signature) is signer. Signatures are [R || S || V],
+// V being 27 or 28.
+//
+// With the eip191 scheme, the default, the hash signed is that of the
+// personal message keccak256(runId || result), the run's ID and the result
+// being taken as strings. With the eip712 scheme, it is that of the typed
+// data Result(string runId,<resultType> result), resultType defaulting to
+// string, in the domain named Chainlink, of version 1, of the node's chain,
+// and of the verifyingContract if one is given.
+type SignResult struct {
+	Scheme            string          `json:"scheme"`
+	ResultType        string          `json:"resultType"`
+	VerifyingContract *common.Address `json:"verifyingContract"`
+}
+
+// TaskType returns the type of Adapter.
+func (sr *SignResult) TaskType() models.TaskType {
+	return TaskTypeSignResult
+}
+
+// ValidateParams returns an error if the scheme is unknown, or if the params
+// of the eip712 scheme are invalid or given to the eip191 one.
+func (sr *SignResult) ValidateParams() error {
+	switch sr.Scheme {
+	case "", SignResultEIP191:
+		if sr.ResultType != "" || sr.VerifyingContract != nil {
+			return errors.New("SignResult Task can only set resultType and verifyingContract with the eip712 scheme")
+		}
+	case SignResultEIP712:
+		if sr.ResultType != "" && !containsString(signResultTypes, sr.ResultType) {
+			return fmt.Errorf("SignResult Task resultType %s is not one of %v", sr.ResultType, signResultTypes)
+		}
+	default:
+		return fmt.Errorf("SignResult Task scheme %s is not %s or %s", sr.Scheme, SignResultEIP191, SignResultEIP712)
+	}
+	return nil
+}
+
+// Perform signs the result, completing with it and its signature.
+func (sr *SignResult) Perform(input models.RunInput, store *strpkg.Store) models.RunOutput {
+	if err := sr.ValidateParams(); err != nil {
+		return models.NewRunOutputError(err)
+	}
+	account, err := store.KeyStore.GetFirstAccount()
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+
+	var signature models.Signature
+	var signedHash common.Hash
+	runID := input.JobRunID().String()
+	if sr.Scheme == SignResultEIP712 {
+		var domainSeparator, messageHash common.Hash
+		domainSeparator, messageHash, err = sr.typedDataHashes(runID, input.Result(), store.Config.ChainID())
+		if err == nil {
+			signedHash = common.BytesToHash(crypto.Keccak256(utils.ConcatBytes([]byte("\x19\x01"), domainSeparator.Bytes(), messageHash.Bytes())))
+			signature, err = store.KeyStore.SignTypedData(domainSeparator, messageHash)
+		}
+	} else {
+		messageHash := common.BytesToHash(crypto.Keccak256(append([]byte(runID), input.Result().String()...)))
+		signedHash = common.BytesToHash(crypto.Keccak256(append([]byte(strpkg.EthereumMessageHashPrefix), messageHash.Bytes()...)))
+		signature, err = store.KeyStore.SignHash(messageHash)
+	}
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "while signing result"))
+	}
+	if signature[64] < 27 {
+		signature[64] += 27
+	}
+
+	data, err := input.Data().MultiAdd(models.KV{
+		"signature":  signature.Hex(),
+		"signer":     account.Address.Hex(),
+		"signedHash": signedHash.Hex(),
+	})
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+	return models.NewRunOutputComplete(data)
+}
+
+// typedDataHashes returns the hashes of the domain and of the message of the
+// typed data the result is signed as.
+func (sr *SignResult) typedDataHashes(runID string, result gjson.Result, chainID *big.Int) (common.Hash, common.Hash, error) {
+	resultType := sr.ResultType
+	if resultType == "" {
+		resultType = "string"
+	}
+	value, err := signResultValue(resultType, result)
+	if err != nil {
+		return common.Hash{}, common.Hash{}, err
+	}
+
+	domainFields := []core.Type{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+	}
+	domain := core.TypedDataDomain{
+		Name:    SignResultDomainName,
+		Version: SignResultDomainVersion,
+		ChainId: (*math.HexOrDecimal256)(chainID),
+	}
+	if sr.VerifyingContract != nil {
+		domainFields = append(domainFields, core.Type{Name: "verifyingContract", Type: "address"})
+		domain.VerifyingContract = sr.VerifyingContract.Hex()
+	}
+	typedData := core.TypedData{
+		Types: core.Types{
+			"EIP712Domain": domainFields,
+			"Result": {
+				{Name: "runId", Type: "string"},
+				{Name: "result", Type: resultType},
+			},
+		},
+		PrimaryType: "Result",
+		Domain:      domain,
+		Message:     core.TypedDataMessage{"runId": runID, "result": value},
+	}
+
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return common.Hash{}, common.Hash{}, errors.Wrap(err, "while hashing domain")
+	}
+	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return common.Hash{}, common.Hash{}, errors.Wrap(err, "while hashing result")
+	}
+	return common.BytesToHash(domainSeparator), common.BytesToHash(messageHash), nil
+}
+
+// signResultValue returns the result as the value of typed data of the
+// given type.
+func signResultValue(resultType string, result gjson.Result) (interface{}, error) {
+	switch resultType {
+	case "bool":
+		if result.Type != gjson.True && result.Type != gjson.False {
+			return nil, fmt.Errorf("result %s is not a bool", result.Raw)
+		}
+		return result.Bool(), nil
+	case "uint256", "int256":
+		if result.Type != gjson.Number && result.Type != gjson.String {
+			return nil, fmt.Errorf("result %s is not an integer", result.Raw)
+		}
+		return result.String(), nil
+	case "bytes32":
+		b, err := hexutil.Decode(result.String())
+		if err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("result %s is not 32 bytes of hex", result.Raw)
+		}
+		return hexutil.Bytes(b), nil
+	default:
+		return result.String(), nil
+	}
+}
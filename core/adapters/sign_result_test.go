@@ -0,0 +1,76 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignResult_Perform(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	account, err := store.KeyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, store.KeyStore.Unlock(cltest.Password))
+
+	contract := cltest.NewAddress()
+	tests := []struct {
+		name        string
+		adapter     adapters.SignResult
+		data        string
+		wantErrored bool
+	}{
+		{"eip191", adapters.SignResult{}, `{"result":"11850.00"}`, false},
+		{"eip712 string", adapters.SignResult{Scheme: adapters.SignResultEIP712}, `{"result":"11850.00"}`, false},
+		{"eip712 uint256", adapters.SignResult{Scheme: adapters.SignResultEIP712, ResultType: "uint256", VerifyingContract: &contract}, `{"result":"1185000"}`, false},
+		{"eip712 bool", adapters.SignResult{Scheme: adapters.SignResultEIP712, ResultType: "bool"}, `{"result":true}`, false},
+		{"eip712 mistyped", adapters.SignResult{Scheme: adapters.SignResultEIP712, ResultType: "uint256"}, `{"result":"11850.00"}`, true},
+		{"unknown scheme", adapters.SignResult{Scheme: "eip1"}, `{"result":"11850.00"}`, true},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			input := cltest.NewRunInputWithString(t, test.data)
+			result := test.adapter.Perform(input, store)
+			if test.wantErrored {
+				assert.True(t, result.HasError())
+				return
+			}
+			require.NoError(t, result.Error())
+
+			data := result.Data()
+			assert.Equal(t, input.Result().Raw, result.Result().Raw)
+			assert.Equal(t, account.Address.Hex(), data.Get("signer").String())
+
+			signature, err := hexutil.Decode(data.Get("signature").String())
+			require.NoError(t, err)
+			require.Len(t, signature, 65)
+			assert.Contains(t, []byte{27, 28}, signature[64])
+			signature[64] -= 27
+			signedHash := common.HexToHash(data.Get("signedHash").String())
+			pubKey, err := crypto.SigToPub(signedHash.Bytes(), signature)
+			require.NoError(t, err)
+			assert.Equal(t, account.Address, crypto.PubkeyToAddress(*pubKey))
+		})
+	}
+}
+
+func TestSignResult_ValidateParams(t *testing.T) {
+	t.Parallel()
+
+	contract := cltest.NewAddress()
+	assert.NoError(t, (&adapters.SignResult{}).ValidateParams())
+	assert.NoError(t, (&adapters.SignResult{Scheme: adapters.SignResultEIP712, ResultType: "bytes32", VerifyingContract: &contract}).ValidateParams())
+	assert.Error(t, (&adapters.SignResult{Scheme: adapters.SignResultEIP191, ResultType: "bool"}).ValidateParams())
+	assert.Error(t, (&adapters.SignResult{VerifyingContract: &contract}).ValidateParams())
+	assert.Error(t, (&adapters.SignResult{Scheme: adapters.SignResultEIP712, ResultType: "uint8"}).ValidateParams())
+	assert.Error(t, (&adapters.SignResult{Scheme: "eip1"}).ValidateParams())
+}
@@ -1,17 +1,27 @@
 package adapters
 
 import (
+	"math/rand"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
-	"github.com/smartcontractkit/chainlink/core/utils"
 )
 
+// sleepUntilKey is where the time a paused task is due to wake up is kept in
+// its data, so that it survives a restart of the node.
+const sleepUntilKey = "sleepUntil"
+
 // Sleep adapter allows a job to do nothing for some amount of wall time.
+//
+// The task sleeps until the given time, or for the given duration, plus a
+// random delay of up to jitter, which spreads out runs triggered at the same
+// moment.
 type Sleep struct {
-	Until models.AnyTime `json:"until"`
+	Until    models.AnyTime  `json:"until"`
+	Duration models.Duration `json:"duration"`
+	Jitter   models.Duration `json:"jitter"`
 }
 
 // TaskType returns the type of Adapter.
@@ -19,18 +29,47 @@ func (adapter *Sleep) TaskType() models.TaskType {
 	return TaskTypeSleep
 }
 
-// Perform returns the input RunResult after waiting for the specified Until parameter.
+// Perform pauses the run with the pending_sleep status, rather than blocking,
+// until the time to wake up has passed.
 func (adapter *Sleep) Perform(input models.RunInput, str *store.Store) models.RunOutput {
-	duration := adapter.Duration()
-	if duration > 0 {
-		logger.Debugw("Task sleeping...", "duration", duration)
-		<-str.Clock.After(duration)
+	now := str.Clock.Now()
+	until, ok := SleepUntil(input.Data())
+	if !input.Status().PendingSleep() || !ok {
+		until = adapter.wakeTime(now)
 	}
 
+	if until.After(now) {
+		logger.Debugw("Task sleeping...", "until", until)
+		return newRunOutputPendingSleep(models.JSON{}, until)
+	}
 	return models.NewRunOutputComplete(models.JSON{})
 }
 
-// Duration returns the amount of sleeping this task should be paused for.
-func (adapter *Sleep) Duration() time.Duration {
-	return utils.DurationFromNow(adapter.Until.Time)
+// wakeTime returns the time a task starting now should wake up.
+func (adapter *Sleep) wakeTime(now time.Time) time.Time {
+	until := now.Add(adapter.Duration.Duration())
+	if adapter.Until.Valid {
+		until = adapter.Until.Time
+	}
+	if jitter := adapter.Jitter.Duration(); jitter > 0 {
+		until = until.Add(time.Duration(rand.Int63n(int64(jitter))))
+	}
+	return until
+}
+
+// SleepUntil returns the time a task paused with the pending_sleep status is
+// due to wake up, from the task's data.
+func SleepUntil(data models.JSON) (time.Time, bool) {
+	until, err := time.Parse(time.RFC3339Nano, data.Get(sleepUntilKey).String())
+	return until, err == nil
+}
+
+// newRunOutputPendingSleep pauses a task until the given time, keeping the
+// time in the data the task is resumed with.
+func newRunOutputPendingSleep(data models.JSON, until time.Time) models.RunOutput {
+	data, err := data.Add(sleepUntilKey, until.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+	return models.NewRunOutputPendingSleepWithData(data)
 }
@@ -3,6 +3,7 @@ package adapters_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
@@ -15,30 +16,37 @@ import (
 func TestSleep_Perform(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
-	clock := cltest.NewTriggerClock(t)
-	store.Clock = clock
 
 	adapter := adapters.Sleep{}
 	err := json.Unmarshal([]byte(`{"until": 2147483647}`), &adapter)
 	assert.NoError(t, err)
 
-	doneChan := make(chan struct{})
-	go func() {
-		result := adapter.Perform(models.RunInput{}, store)
-		assert.Equal(t, string(models.RunStatusCompleted), string(result.Status()))
-		doneChan <- struct{}{}
-	}()
-
-	select {
-	case <-doneChan:
-		t.Error("Sleep adapter did not sleep")
-	default:
-	}
+	result := adapter.Perform(models.RunInput{}, store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, string(models.RunStatusPendingSleep), string(result.Status()))
+	until, ok := adapters.SleepUntil(result.Data())
+	require.True(t, ok)
+	assert.Equal(t, int64(2147483647), until.Unix())
+
+	input := models.NewRunInput(models.NewID(), result.Data(), models.RunStatusPendingSleep)
+	result = adapter.Perform(*input, store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, string(models.RunStatusPendingSleep), string(result.Status()), "woken up too soon")
+}
 
-	clock.Trigger()
+func TestSleep_Perform_WokenUp(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	adapter := adapters.Sleep{}
+	err := json.Unmarshal([]byte(`{"until": 2147483647}`), &adapter)
+	require.NoError(t, err)
 
-	_, ok := <-doneChan
-	assert.True(t, ok)
+	data := cltest.JSONFromString(t, `{"sleepUntil":"2012-03-19T10:11:59Z"}`)
+	input := models.NewRunInput(models.NewID(), data, models.RunStatusPendingSleep)
+	result := adapter.Perform(*input, store)
+	require.NoError(t, result.Error())
+	assert.Equal(t, string(models.RunStatusCompleted), string(result.Status()))
 }
 
 func TestSleep_Perform_AlreadyElapsed(t *testing.T) {
@@ -53,3 +61,24 @@ func TestSleep_Perform_AlreadyElapsed(t *testing.T) {
 	require.NoError(t, result.Error())
 	assert.Equal(t, string(models.RunStatusCompleted), string(result.Status()))
 }
+
+func TestSleep_Perform_DurationWithJitter(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	adapter := adapters.Sleep{}
+	err := json.Unmarshal([]byte(`{"duration": "1m", "jitter": "30s"}`), &adapter)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		start := time.Now()
+		result := adapter.Perform(models.RunInput{}, store)
+		require.NoError(t, result.Error())
+		require.Equal(t, string(models.RunStatusPendingSleep), string(result.Status()))
+
+		until, ok := adapters.SleepUntil(result.Data())
+		require.True(t, ok)
+		assert.False(t, until.Before(start.Add(time.Minute)))
+		assert.True(t, until.Before(time.Now().Add(90*time.Second)))
+	}
+}
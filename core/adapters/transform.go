@@ -0,0 +1,144 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// transformTimeout is how long a transform expression can run for, so that
+// one which never terminates cannot hold up the run.
+const transformTimeout = time.Second
+
+// Transform runs a jq expression against the result of the previous task,
+// for extracting and reshaping values where JSONParse's plain path is not
+// enough.
+//
+// For example, the expression `[.data[] | select(.volume > 1000) | .price] |
+// add / length` averages the price of every entry of a bridge response with
+// enough volume.
+type Transform struct {
+	Expression TransformExpression `json:"expression"`
+}
+
+// TaskType returns the type of Adapter.
+func (t *Transform) TaskType() models.TaskType {
+	return TaskTypeTransform
+}
+
+// Perform runs the expression against the result, which must hold JSON.
+// An expression emitting a single value has it become the result, while
+// several values are collected into an array. The expression is stopped
+// once the run is cancelled or transformTimeout has passed.
+func (t *Transform) Perform(input models.RunInput, _ *store.Store) models.RunOutput {
+	if t.Expression.code == nil {
+		return models.NewRunOutputError(errors.New("transform expression is empty"))
+	}
+
+	var raw string
+	if input.Result().Type == gjson.JSON {
+		raw = input.Result().Raw
+	} else {
+		var err error
+		if raw, err = input.ResultString(); err != nil {
+			return models.NewRunOutputError(err)
+		}
+	}
+
+	value, err := decodeTransformInput(raw)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrap(err, "transform input is not valid JSON"))
+	}
+
+	ctx, cancel := context.WithTimeout(input.Context(), transformTimeout)
+	defer cancel()
+	outputs := []interface{}{}
+	iter := t.Expression.code.RunWithContext(ctx, value)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		} else if err, isErr := v.(error); isErr {
+			if err == context.DeadlineExceeded {
+				return models.NewRunOutputError(fmt.Errorf("transform expression did not finish within %s", transformTimeout))
+			}
+			return models.NewRunOutputError(errors.Wrap(err, "running transform expression"))
+		}
+		outputs = append(outputs, v)
+	}
+
+	switch len(outputs) {
+	case 0:
+		return models.NewRunOutputCompleteWithResult(nil)
+	case 1:
+		return models.NewRunOutputCompleteWithResult(outputs[0])
+	default:
+		return models.NewRunOutputCompleteWithResult(outputs)
+	}
+}
+
+// decodeTransformInput unmarshals JSON keeping numbers exact, so that large
+// integers make it through the expression untouched.
+func decodeTransformInput(raw string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// TransformExpression is a jq expression, compiled when unmarshalled so that
+// invalid expressions are rejected when a job is created rather than when it
+// runs.
+type TransformExpression struct {
+	source string
+	code   *gojq.Code
+}
+
+// NewTransformExpression parses and compiles the given jq expression.
+func NewTransformExpression(source string) (TransformExpression, error) {
+	query, err := gojq.Parse(source)
+	if err != nil {
+		return TransformExpression{}, fmt.Errorf("transform expression %q is invalid: %v", source, err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return TransformExpression{}, fmt.Errorf("transform expression %q is invalid: %v", source, err)
+	}
+	return TransformExpression{source: source, code: code}, nil
+}
+
+// String returns the source of the expression.
+func (te TransformExpression) String() string {
+	return te.source
+}
+
+// MarshalJSON implements the Marshaler interface.
+func (te TransformExpression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(te.source)
+}
+
+// UnmarshalJSON implements the Unmarshaler interface.
+func (te *TransformExpression) UnmarshalJSON(b []byte) error {
+	var source string
+	if err := json.Unmarshal(b, &source); err != nil {
+		return err
+	}
+	expr, err := NewTransformExpression(source)
+	if err != nil {
+		return err
+	}
+	*te = expr
+	return nil
+}
@@ -0,0 +1,116 @@
+package adapters_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform_Perform(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		result     string
+		expression string
+		wantData   string
+		wantStatus models.RunStatus
+	}{
+		{"field", `{"high":"11850.00","last":"11779.99"}`, `.last`,
+			`{"result":"11779.99"}`, models.RunStatusCompleted},
+		{"nested index", `{"data":[{"price":1},{"price":2}]}`, `.data[-1].price`,
+			`{"result":2}`, models.RunStatusCompleted},
+		{"missing field", `{"high":"11850.00"}`, `.last`,
+			`{"result":null}`, models.RunStatusCompleted},
+		{"filter and average", `{"data":[{"price":1,"volume":5},{"price":3,"volume":2000},{"price":5,"volume":3000}]}`,
+			`[.data[] | select(.volume > 1000) | .price] | add / length`,
+			`{"result":4}`, models.RunStatusCompleted},
+		{"reshape", `{"quote":{"USD":{"price":1.5}}}`, `{usd: .quote.USD.price}`,
+			`{"result":{"usd":1.5}}`, models.RunStatusCompleted},
+		{"many outputs", `{"data":[1,2,3]}`, `.data[]`,
+			`{"result":[1,2,3]}`, models.RunStatusCompleted},
+		{"no outputs", `{"data":[]}`, `.data[]`,
+			`{"result":null}`, models.RunStatusCompleted},
+		{"large integer", `{"value":115792089237316195423570985008687907853269984665640564039457584007913129639935}`, `.value`,
+			`{"result":115792089237316195423570985008687907853269984665640564039457584007913129639935}`, models.RunStatusCompleted},
+		{"runtime error", `{"data":1}`, `.data.price`,
+			``, models.RunStatusErrored},
+		{"input not json", `not json`, `.data`,
+			``, models.RunStatusErrored},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := adapters.NewTransformExpression(test.expression)
+			require.NoError(t, err)
+
+			adapter := adapters.Transform{Expression: expr}
+			result := adapter.Perform(cltest.NewRunInputWithResult(test.result), nil)
+			assert.Equal(t, test.wantStatus, result.Status())
+			if test.wantStatus == models.RunStatusErrored {
+				assert.Error(t, result.Error())
+			} else {
+				assert.NoError(t, result.Error())
+				assert.JSONEq(t, test.wantData, result.Data().String())
+			}
+		})
+	}
+}
+
+func TestTransform_Perform_WithPreParsedJSON(t *testing.T) {
+	var parsed models.JSON
+	err := json.Unmarshal([]byte(`{"high":"11850.00","last":"11779.99"}`), &parsed)
+	require.NoError(t, err)
+
+	expr, err := adapters.NewTransformExpression(`.high`)
+	require.NoError(t, err)
+
+	adapter := adapters.Transform{Expression: expr}
+	result := adapter.Perform(cltest.NewRunInputWithResult(parsed), nil)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, `{"result":"11850.00"}`, result.Data().String())
+}
+
+func TestTransform_Perform_NonTerminating(t *testing.T) {
+	t.Parallel()
+
+	expr, err := adapters.NewTransformExpression(`until(false; .)`)
+	require.NoError(t, err)
+
+	adapter := adapters.Transform{Expression: expr}
+	done := make(chan models.RunOutput)
+	go func() {
+		done <- adapter.Perform(cltest.NewRunInputWithResult(`{"data":1}`), nil)
+	}()
+	select {
+	case result := <-done:
+		assert.Equal(t, models.RunStatusErrored, result.Status())
+		assert.Contains(t, result.Error().Error(), "did not finish")
+	case <-time.After(10 * time.Second):
+		t.Fatal("transform expression was not stopped")
+	}
+}
+
+func TestTransformExpression_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var adapter adapters.Transform
+	require.NoError(t, json.Unmarshal([]byte(`{"expression":".data | length"}`), &adapter))
+	assert.Equal(t, ".data | length", adapter.Expression.String())
+
+	b, err := json.Marshal(adapter)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expression":".data | length"}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"expression":".data |"}`), &adapter)
+	assert.Error(t, err)
+	err = json.Unmarshal([]byte(`{"expression":"undefinedfunc(1)"}`), &adapter)
+	assert.Error(t, err)
+}
@@ -3,15 +3,92 @@
 package adapters
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/go-interpreter/wagon/disasm"
+	"github.com/go-interpreter/wagon/exec"
+	"github.com/go-interpreter/wagon/validate"
+	"github.com/go-interpreter/wagon/wasm"
+	ops "github.com/go-interpreter/wagon/wasm/operators"
+	"github.com/pkg/errors"
 )
 
-// Wasm represents a wasm binary encoded as base64 or wasm encoded as text (a lisp like language).
+// Wasm transforms the data of the run with the WebAssembly module uploaded
+// with the name of its module param. The module is given no imports, so that
+// it can only compute on the data it is given, and it is stopped if it runs
+// for longer than WASM_TIMEOUT or needs more than WASM_MAX_MEMORY_PAGES of
+// memory.
+//
+// The module must export its memory, an alloc function taking a size and
+// returning the offset of as many bytes, which the data of the run is
+// written to as JSON, and a transform function taking the offset and size of
+// the data, and returning those of the JSON it transforms it into, as the
+// high and low 32 bits of an i64.
 type Wasm struct {
-	WasmT string `json:"wasmt"`
+	Module string `json:"module"`
+}
+
+// wasmExports are the functions a module must export, with their types.
+var wasmExports = map[string]wasm.FunctionSig{
+	"alloc":     {ParamTypes: []wasm.ValueType{wasm.ValueTypeI32}, ReturnTypes: []wasm.ValueType{wasm.ValueTypeI32}},
+	"transform": {ParamTypes: []wasm.ValueType{wasm.ValueTypeI32, wasm.ValueTypeI32}, ReturnTypes: []wasm.ValueType{wasm.ValueTypeI64}},
+}
+
+const (
+	// wasmPageSize is the size of the pages of the memory of modules.
+	wasmPageSize = 65536
+	// wasmMaxCallDepth is how deeply the calls of modules can nest, as the
+	// interpreter makes them on the stack of the goroutine running them.
+	wasmMaxCallDepth = 1000
+)
+
+// The functions the "env" module provides to the instrumented modules, which
+// import them ahead of their own functions, so that runs can stop them.
+const (
+	wasmEnter = iota // called on entering a function
+	wasmLeave        // called on leaving a function
+	wasmTick         // called on every iteration of a loop
+	wasmGrow         // called with the pages memory grows by, before it does
+	wasmHostFunctions
+)
+
+// wasmEnv is the module the instrumented modules import from, whose
+// functions are bound to each run.
+var wasmEnv = func() *wasm.Module {
+	void := wasm.FunctionSig{Form: 0x60}
+	grow := wasm.FunctionSig{Form: 0x60, ParamTypes: []wasm.ValueType{wasm.ValueTypeI32}, ReturnTypes: []wasm.ValueType{wasm.ValueTypeI32}}
+	env := &wasm.Module{Export: &wasm.SectionExports{Entries: map[string]wasm.ExportEntry{}}}
+	for i, name := range []string{"enter", "leave", "tick", "grow"} {
+		sig := void
+		if i == wasmGrow {
+			sig = grow
+		}
+		env.FunctionIndexSpace = append(env.FunctionIndexSpace, wasm.Function{Sig: &sig, Body: &wasm.FunctionBody{}, Name: name})
+		env.Export.Entries[name] = wasm.ExportEntry{FieldStr: name, Kind: wasm.ExternalFunction, Index: uint32(i)}
+	}
+	return env
+}()
+
+// wasmModules caches the instrumented modules by name, with the hash of the
+// binary they were compiled from, so that runs only read and compile a
+// module again when it is replaced.
+var wasmModules = struct {
+	sync.Mutex
+	byName map[string]compiledWasmModule
+}{byName: map[string]compiledWasmModule{}}
+
+type compiledWasmModule struct {
+	hash   string
+	module *wasm.Module
 }
 
 // TaskType returns the type of Adapter.
@@ -19,8 +96,371 @@ func (wasm *Wasm) TaskType() models.TaskType {
 	return TaskTypeWasm
 }
 
-// Perform ships the wasm representation to the SGX enclave where it is evaluated.
-func (wasm *Wasm) Perform(input models.RunInput, _ *store.Store) models.RunOutput {
-	err := fmt.Errorf("Wasm is not supported without SGX")
-	return models.NewRunOutputError(err)
+// ValidateParams returns an error if the task names no module, or, unless
+// store is nil, one which has not been uploaded.
+func (wasm *Wasm) ValidateParams(store *store.Store) error {
+	if wasm.Module == "" {
+		return errors.New("wasm Task must name the module it runs")
+	}
+	if store == nil {
+		return nil
+	}
+	if _, err := store.FindWasmModuleHash(wasm.Module); err != nil {
+		return errors.Wrapf(err, "wasm Task module %s", wasm.Module)
+	}
+	return nil
+}
+
+// Perform runs the module with the data of the run, completing with the
+// object the module returns, or with the value it returns as the result.
+func (wasm *Wasm) Perform(input models.RunInput, store *store.Store) models.RunOutput {
+	module, err := findCompiledWasm(store, wasm.Module)
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+
+	output, err := runWasm(input.Context(), module, []byte(input.Data().String()), store.Config)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrapf(err, "wasm module %s", wasm.Module))
+	}
+	data, err := models.ParseJSON(output)
+	if err != nil {
+		return models.NewRunOutputError(errors.Wrapf(err, "wasm module %s returned invalid JSON", wasm.Module))
+	}
+	if data.IsObject() {
+		return models.NewRunOutputComplete(data)
+	}
+	return models.NewRunOutputCompleteWithResult(data.Result.Value())
+}
+
+// findCompiledWasm returns the module with the name, from the cache unless
+// the module of the name has changed since it was compiled.
+func findCompiledWasm(store *store.Store, name string) (*wasm.Module, error) {
+	hash, err := store.FindWasmModuleHash(name)
+	if err != nil {
+		wasmModules.Lock()
+		delete(wasmModules.byName, name)
+		wasmModules.Unlock()
+		return nil, errors.Wrapf(err, "finding wasm module %s", name)
+	}
+
+	wasmModules.Lock()
+	cached, ok := wasmModules.byName[name]
+	wasmModules.Unlock()
+	if ok && cached.hash == hash {
+		return cached.module, nil
+	}
+
+	record, err := store.FindWasmModule(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding wasm module %s", name)
+	}
+	module, err := compileWasm(record.Module)
+	if err != nil {
+		return nil, errors.Wrapf(err, "wasm module %s", name)
+	}
+	wasmModules.Lock()
+	wasmModules.byName[name] = compiledWasmModule{hash: record.Hash, module: module}
+	wasmModules.Unlock()
+	return module, nil
+}
+
+// CompileWasm returns an error if the binary is not a module wasm tasks can
+// run, because it is invalid, needs imports, lacks the exports they call, or
+// starts with more memory than WASM_MAX_MEMORY_PAGES.
+func CompileWasm(binary []byte, config orm.ConfigReader) error {
+	module, err := compileWasm(binary)
+	if err != nil {
+		return err
+	}
+	return checkWasmMemory(module, config.WasmMaxMemoryPages())
+}
+
+// RunWasm transforms input with the module in the binary, returning the
+// bytes it transforms it into.
+func RunWasm(ctx context.Context, binary, input []byte, config orm.ConfigReader) ([]byte, error) {
+	module, err := compileWasm(binary)
+	if err != nil {
+		return nil, err
+	}
+	return runWasm(ctx, module, input, config)
+}
+
+func runWasm(ctx context.Context, module *wasm.Module, input []byte, config orm.ConfigReader) ([]byte, error) {
+	if err := checkWasmMemory(module, config.WasmMaxMemoryPages()); err != nil {
+		return nil, err
+	}
+	timeout := config.WasmTimeout().Duration()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	run := &wasmRun{ctx: ctx, timeout: timeout, maxPages: config.WasmMaxMemoryPages()}
+	vm, err := run.instantiate(module)
+	if err != nil {
+		return nil, errors.Wrap(err, "instantiating")
+	}
+
+	results, err := run.call(vm, module, "alloc", uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	offset := uint32(results)
+	memory := vm.Memory()
+	if uint64(offset)+uint64(len(input)) > uint64(len(memory)) {
+		return nil, fmt.Errorf("alloc returned %d bytes at %d, which are out of memory", len(input), offset)
+	}
+	copy(memory[offset:], input)
+
+	results, err = run.call(vm, module, "transform", uint64(offset), uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	offset, size := uint32(results>>32), uint32(results)
+	memory = vm.Memory()
+	if uint64(offset)+uint64(size) > uint64(len(memory)) {
+		return nil, fmt.Errorf("transform returned %d bytes at %d, which are out of memory", size, offset)
+	}
+	return append([]byte(nil), memory[offset:offset+size]...), nil
+}
+
+// checkWasmMemory returns an error if the module starts with more memory
+// than the pages.
+func checkWasmMemory(module *wasm.Module, maxPages uint32) error {
+	pages := module.Memory.Entries[0].Limits.Initial
+	if pages > maxPages {
+		return fmt.Errorf("memory of %d pages is over limit of %d", pages, maxPages)
+	}
+	return nil
+}
+
+// wasmRun is the state of a run of a module, which the functions it imports
+// from "env" check to stop it.
+type wasmRun struct {
+	ctx      context.Context
+	timeout  time.Duration
+	maxPages uint32
+	depth    int
+	err      error
+}
+
+// instantiate returns a VM running the module, with the functions it imports
+// bound to the run.
+func (run *wasmRun) instantiate(module *wasm.Module) (vm *exec.VM, err error) {
+	instance := *module
+	instance.FunctionIndexSpace = append([]wasm.Function(nil), module.FunctionIndexSpace...)
+	for i, fn := range []interface{}{run.enter, run.leave, run.tick, run.grow} {
+		instance.FunctionIndexSpace[i].Host = reflect.ValueOf(fn)
+	}
+
+	// Starting the module runs its start function, whose traps panic.
+	defer func() {
+		if r := recover(); r != nil {
+			vm, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	vm, err = exec.NewVM(&instance)
+	if run.err != nil {
+		return nil, run.err
+	} else if err != nil {
+		return nil, err
+	}
+	vm.RecoverPanic = true
+	return vm, nil
+}
+
+// call calls the function the module exports with the name.
+func (run *wasmRun) call(vm *exec.VM, module *wasm.Module, name string, args ...uint64) (uint64, error) {
+	result, err := vm.ExecCode(int64(module.Export.Entries[name].Index), args...)
+	if run.err != nil {
+		return 0, run.err
+	} else if err != nil {
+		return 0, errors.Wrapf(err, "calling %s", name)
+	}
+	switch result := result.(type) {
+	case uint32:
+		return uint64(result), nil
+	case uint64:
+		return result, nil
+	}
+	return 0, fmt.Errorf("%s returned %v", name, result)
+}
+
+// stop terminates the run with err.
+func (run *wasmRun) stop(proc *exec.Process, err error) {
+	if run.err == nil {
+		run.err = err
+	}
+	proc.Terminate()
+}
+
+func (run *wasmRun) enter(proc *exec.Process) {
+	run.depth++
+	if run.depth > wasmMaxCallDepth {
+		run.stop(proc, fmt.Errorf("calls nested deeper than %d", wasmMaxCallDepth))
+		return
+	}
+	run.tick(proc)
+}
+
+func (run *wasmRun) leave(proc *exec.Process) {
+	run.depth--
+}
+
+func (run *wasmRun) tick(proc *exec.Process) {
+	switch err := run.ctx.Err(); err {
+	case nil:
+	case context.DeadlineExceeded:
+		run.stop(proc, fmt.Errorf("timed out after %s", run.timeout))
+	default:
+		run.stop(proc, err)
+	}
+}
+
+func (run *wasmRun) grow(proc *exec.Process, pages int32) int32 {
+	current := proc.MemSize() / wasmPageSize
+	if pages < 0 || uint64(current)+uint64(pages) > uint64(run.maxPages) {
+		run.stop(proc, fmt.Errorf("growing memory of %d pages by %d is over limit of %d", current, pages, run.maxPages))
+	}
+	return pages
+}
+
+// compileWasm returns the module in the binary, instrumented to call the
+// functions of "env" runs stop it with, erroring if it is invalid, needs
+// imports, or lacks the exports wasm tasks call.
+func compileWasm(binary []byte) (*wasm.Module, error) {
+	module, err := wasm.ReadModule(bytes.NewReader(binary), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "compiling")
+	}
+	if module.Import != nil && len(module.Import.Entries) > 0 {
+		return nil, errors.New("modules cannot import functions or memories")
+	}
+	if err := validate.VerifyModule(module); err != nil {
+		return nil, errors.Wrap(err, "compiling")
+	}
+	if module.Export == nil || module.Memory == nil || len(module.Memory.Entries) != 1 ||
+		module.Export.Entries["memory"].Kind != wasm.ExternalMemory {
+		return nil, errors.New("module does not export its memory")
+	}
+	if uint64(len(module.LinearMemoryIndexSpace[0])) > uint64(module.Memory.Entries[0].Limits.Initial)*wasmPageSize {
+		return nil, errors.New("module initializes data out of its memory")
+	}
+	for name, signature := range wasmExports {
+		export, ok := module.Export.Entries[name]
+		if !ok || export.Kind != wasm.ExternalFunction {
+			return nil, fmt.Errorf("module does not export %s", name)
+		}
+		fn := module.GetFunction(int(export.Index))
+		if fn == nil || !reflect.DeepEqual(fn.Sig.ParamTypes, signature.ParamTypes) || !reflect.DeepEqual(fn.Sig.ReturnTypes, signature.ReturnTypes) {
+			return nil, fmt.Errorf("%s exported by module has the wrong type", name)
+		}
+	}
+
+	instrumented, err := instrumentWasm(module)
+	if err != nil {
+		return nil, errors.Wrap(err, "instrumenting")
+	}
+	module, err = wasm.ReadModule(bytes.NewReader(instrumented), func(name string) (*wasm.Module, error) {
+		if name != "env" {
+			return nil, fmt.Errorf("no module %s to import", name)
+		}
+		return wasmEnv, nil
+	})
+	return module, errors.Wrap(err, "compiling instrumented module")
+}
+
+// instrumentWasm returns the binary of the module, importing the functions
+// of "env" ahead of its own. Each function of the module gets a wrapper
+// calling it between enter and leave, which its calls, table and exports are
+// redirected to. Loops call tick on every iteration, and memory.grow calls
+// grow first.
+func instrumentWasm(module *wasm.Module) ([]byte, error) {
+	functions := uint32(len(module.Function.Types))
+	wrapper := func(index uint32) uint32 {
+		return wasmHostFunctions + functions + index
+	}
+	call := func(index uint32) disasm.Instr {
+		return disasm.Instr{Op: ops.Op{Code: ops.Call}, Immediates: []interface{}{index}}
+	}
+
+	types := uint32(len(module.Types.Entries))
+	module.Types.Entries = append(module.Types.Entries, *wasmEnv.FunctionIndexSpace[wasmEnter].Sig, *wasmEnv.FunctionIndexSpace[wasmGrow].Sig)
+	module.Import = &wasm.SectionImports{}
+	for i, fn := range wasmEnv.FunctionIndexSpace {
+		typ := types
+		if i == wasmGrow {
+			typ++
+		}
+		module.Import.Entries = append(module.Import.Entries, wasm.ImportEntry{ModuleName: "env", FieldName: fn.Name, Type: wasm.FuncImport{Type: typ}})
+	}
+
+	bodies := make([]wasm.FunctionBody, 0, 2*functions)
+	for _, body := range module.Code.Bodies {
+		instrs, err := disasm.Disassemble(body.Code)
+		if err != nil {
+			return nil, err
+		}
+		instrumented := make([]disasm.Instr, 0, len(instrs))
+		for _, instr := range instrs {
+			switch instr.Op.Code {
+			case ops.Call:
+				instrumented = append(instrumented, call(wrapper(instr.Immediates[0].(uint32))))
+			case ops.Loop:
+				instrumented = append(instrumented, instr, call(wasmTick))
+			case ops.GrowMemory:
+				instrumented = append(instrumented, call(wasmGrow), instr)
+			default:
+				instrumented = append(instrumented, instr)
+			}
+		}
+		code, err := disasm.Assemble(instrumented)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, wasm.FunctionBody{Locals: body.Locals, Code: code})
+	}
+	for i, typ := range module.Function.Types {
+		instrs := []disasm.Instr{call(wasmEnter)}
+		for param := range module.Types.Entries[typ].ParamTypes {
+			instrs = append(instrs, disasm.Instr{Op: ops.Op{Code: ops.GetLocal}, Immediates: []interface{}{uint32(param)}})
+		}
+		instrs = append(instrs, call(wasmHostFunctions+uint32(i)), call(wasmLeave))
+		code, err := disasm.Assemble(instrs)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, wasm.FunctionBody{Code: code})
+	}
+	module.Code.Bodies = bodies
+	module.Function.Types = append(append([]uint32(nil), module.Function.Types...), module.Function.Types...)
+
+	for name, export := range module.Export.Entries {
+		if export.Kind == wasm.ExternalFunction {
+			export.Index = wrapper(export.Index)
+			module.Export.Entries[name] = export
+		}
+	}
+	if module.Elements != nil {
+		for _, segment := range module.Elements.Entries {
+			for i, index := range segment.Elems {
+				segment.Elems[i] = wrapper(index)
+			}
+		}
+	}
+	if module.Start != nil {
+		module.Start.Index = wrapper(module.Start.Index)
+	}
+
+	module.Sections = nil
+	for _, section := range []wasm.Section{
+		module.Types, module.Import, module.Function, module.Table, module.Memory, module.Global,
+		module.Export, module.Start, module.Elements, module.Code, module.Data,
+	} {
+		if !reflect.ValueOf(section).IsNil() {
+			module.Sections = append(module.Sections, section)
+		}
+	}
+	var binary bytes.Buffer
+	err := wasm.EncodeModule(&binary, module)
+	return binary.Bytes(), err
 }
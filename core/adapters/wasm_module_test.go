@@ -0,0 +1,142 @@
+// +build !sgx_enclave
+
+package adapters_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wasmModule returns a module whose transform function has the body, and
+// whose memory starts with the pages, for the tests. Its alloc function
+// returns offset 1024.
+func wasmModule(transform []byte, pages byte) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	binary := []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+	// (func (param i32) (result i32)) and (func (param i32 i32) (result i64))
+	binary = append(binary, section(0x01, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e)...)
+	binary = append(binary, section(0x03, 0x02, 0x00, 0x01)...)
+	binary = append(binary, section(0x05, 0x01, 0x00, pages)...)
+	binary = append(binary, section(0x07, 0x03,
+		0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+		0x05, 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+		0x09, 't', 'r', 'a', 'n', 's', 'f', 'o', 'r', 'm', 0x00, 0x01)...)
+	alloc := []byte{0x00, 0x41, 0x80, 0x08, 0x0b} // i32.const 1024
+	body := append([]byte{0x00}, transform...)
+	code := append([]byte{0x02, byte(len(alloc))}, alloc...)
+	code = append(code, byte(len(body)))
+	return append(binary, section(0x0a, append(code, body...)...)...)
+}
+
+var (
+	// Returns the data it is given: (i64.or (i64.shl (i64.extend_i32_u ptr) 32) (i64.extend_i32_u len))
+	wasmIdentity = []byte{0x20, 0x00, 0xad, 0x42, 0x20, 0x86, 0x20, 0x01, 0xad, 0x84, 0x0b}
+	// Returns the 3 bytes of the data after its first 11, 100 of {"result":"100"}
+	wasmSlice = []byte{0x20, 0x00, 0x41, 0x0b, 0x6a, 0xad, 0x42, 0x20, 0x86, 0x42, 0x03, 0x84, 0x0b}
+	// Loops forever: (loop (br 0)) unreachable
+	wasmLoop = []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x00, 0x0b}
+	// Calls itself forever: (call $transform (local.get 0) (local.get 1))
+	wasmRecurse = []byte{0x20, 0x00, 0x20, 0x01, 0x10, 0x01, 0x0b}
+	// Grows memory by 2 pages, then returns the data it is given
+	wasmGrow = append([]byte{0x41, 0x02, 0x40, 0x00, 0x1a}, wasmIdentity...)
+)
+
+func TestWasm_Perform_Module(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("WASM_TIMEOUT", "100ms")
+	store.Config.Set("WASM_MAX_MEMORY_PAGES", 2)
+
+	upload := func(name string, binary []byte) {
+		module, err := models.NewWasmModule(models.WasmModuleRequest{Name: name, Module: binary}, store.Config.WasmMaxModuleSize())
+		require.NoError(t, err)
+		require.NoError(t, store.CreateWasmModule(&module))
+	}
+	upload("identity", wasmModule(wasmIdentity, 1))
+	upload("slice", wasmModule(wasmSlice, 1))
+	upload("loop", wasmModule(wasmLoop, 1))
+	upload("large", wasmModule(wasmIdentity, 3))
+	upload("recurse", wasmModule(wasmRecurse, 1))
+	upload("grow", wasmModule(wasmGrow, 1))
+
+	input := cltest.NewRunInputWithResult("100")
+	tests := []struct {
+		module  string
+		want    string
+		wantErr string
+	}{
+		{"identity", `{"result":"100"}`, ""},
+		{"slice", `{"result":100}`, ""},
+		{"loop", "", "timed out after 100ms"},
+		{"large", "", "over limit"},
+		{"recurse", "", "calls nested deeper than"},
+		{"grow", "", "over limit"},
+		{"missing", "", "finding wasm module missing"},
+	}
+	for _, test := range tests {
+		t.Run(test.module, func(t *testing.T) {
+			adapter := adapters.Wasm{Module: test.module}
+			result := adapter.Perform(input, store)
+			if test.wantErr != "" {
+				require.Error(t, result.Error())
+				assert.Contains(t, result.Error().Error(), test.wantErr)
+				return
+			}
+			require.NoError(t, result.Error())
+			assert.JSONEq(t, test.want, result.Data().String())
+		})
+	}
+}
+
+func TestWasm_Perform_ReplacedModule(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	upload := func(binary []byte) {
+		module, err := models.NewWasmModule(models.WasmModuleRequest{Name: "replaced", Module: binary}, store.Config.WasmMaxModuleSize())
+		require.NoError(t, err)
+		require.NoError(t, store.CreateWasmModule(&module))
+	}
+	adapter := adapters.Wasm{Module: "replaced"}
+	input := cltest.NewRunInputWithResult("100")
+
+	upload(wasmModule(wasmIdentity, 1))
+	result := adapter.Perform(input, store)
+	require.NoError(t, result.Error())
+	assert.JSONEq(t, `{"result":"100"}`, result.Data().String())
+
+	require.NoError(t, store.DeleteWasmModule("replaced"))
+	upload(wasmModule(wasmSlice, 1))
+	result = adapter.Perform(input, store)
+	require.NoError(t, result.Error())
+	assert.JSONEq(t, `{"result":100}`, result.Data().String())
+}
+
+func TestCompileWasm(t *testing.T) {
+	t.Parallel()
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+
+	assert.NoError(t, adapters.CompileWasm(wasmModule(wasmIdentity, 1), config))
+	assert.Error(t, adapters.CompileWasm([]byte{0x00, 'a', 's', 'm'}, config))
+
+	// (module (import "env" "now" (func)))
+	imports, err := base64.StdEncoding.DecodeString("AGFzbQEAAAABBAFgAAACCwEDZW52A25vdwAA")
+	require.NoError(t, err)
+	assert.EqualError(t, adapters.CompileWasm(imports, config), "modules cannot import functions or memories")
+
+	_, err = adapters.RunWasm(context.Background(), wasmModule(wasmIdentity, 1), []byte(`{}`), config)
+	assert.NoError(t, err)
+}
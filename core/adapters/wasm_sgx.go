@@ -30,6 +30,11 @@ func (wasm *Wasm) TaskType() models.TaskType {
 	return TaskTypeWasm
 }
 
+// ValidateParams returns nil, the program being checked by the enclave.
+func (wasm *Wasm) ValidateParams(_ *store.Store) error {
+	return nil
+}
+
 // Perform ships the wasm representation to the SGX enclave where it is evaluated.
 func (wasm *Wasm) Perform(input models.RunInput, _ *store.Store) models.RunOutput {
 	adapterJSON, err := json.Marshal(wasm)
@@ -64,6 +64,23 @@ func NewApp(client *Client) *cli.App {
 						},
 					},
 				},
+				{
+					Name:  "profile",
+					Usage: "Save the CPU, memory and goroutine profiles of the node to a zip file",
+					Description: format(`The profiles are read by go tool pprof, but for goroutine.txt, which
+               has the stacks of every goroutine.`),
+					Action: client.ProfileNode,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "duration, d",
+							Usage: "how long to profile the CPU for, less than the node's write timeout of 10s (default: 5s)",
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "the path of the zip file to create (default: chainlink-profile-<unix time>.zip)",
+						},
+					},
+				},
 				{
 					Name:        "withdraw",
 					Usage:       "Withdraw to <address>, <amount> units of LINK from the configured Oracle Contract",
@@ -176,6 +193,66 @@ func NewApp(client *Client) *cli.App {
 			Action: client.CreateExtraKey,
 		},
 
+		cli.Command{
+			Name: "addexternalkey",
+			Usage: format(`Add a key held by a KMS or HSM, which the node signs transactions
+               with from then on`),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "ref",
+					Usage: "the reference of the key, as <scheme>://<ref>, e.g. awskms://<key ARN>",
+				},
+			},
+			Action: client.AddExternalKey,
+		},
+
+		cli.Command{
+			Name: "rotatekey",
+			Usage: format(`Replace the key with the given address by a new key, which the
+               node uses for new transactions from now on`),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "address",
+					Usage: "the address of the key to retire",
+				},
+				cli.BoolFlag{
+					Name:  "sweep",
+					Usage: "send the LINK and spare ETH of the retired key to the new key",
+				},
+			},
+			Action: client.RotateKey,
+		},
+
+		cli.Command{
+			Name: "exportkeys",
+			Usage: format(`Save the ethereum and VRF keys of the node to a file, encrypted
+               with the node's password, for importing them on another node`),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "file, f",
+					Usage: "the path of the bundle file to create",
+				},
+			},
+			Action: client.ExportKeys,
+		},
+
+		cli.Command{
+			Name: "importkeys",
+			Usage: format(`Add the keys in a bundle exported from another node, which
+               must have the same password, to the node`),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "file, f",
+					Usage: "the path of the bundle file",
+				},
+				cli.BoolFlag{
+					Name:  "dryrun",
+					Usage: "only check that the keys in the bundle can be imported",
+				},
+			},
+			Action: client.ImportKeys,
+		},
+
 		{
 			Name:  "jobs",
 			Usage: "Commands for managing Jobs",
@@ -187,8 +264,31 @@ func NewApp(client *Client) *cli.App {
 				},
 				{
 					Name:   "create",
-					Usage:  "Create Job from a Job Specification JSON",
+					Usage:  "Create Job from a Job Specification JSON or TOML",
 					Action: client.CreateJobSpec,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "format",
+							Usage: "the format of the job spec, json or toml, by default toml for .toml files and json otherwise",
+						},
+					},
+				},
+				{
+					Name:   "export",
+					Usage:  "Save a Job to a file along with the bridges, external initiators and secrets it depends on, without their credentials",
+					Action: client.ExportJobSpec,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "the path of the bundle file to create",
+						},
+					},
+				},
+				{
+					Name:        "import",
+					Usage:       "Create a Job from a bundle exported from another node, along with the dependencies the node is missing",
+					Description: "The values of the secrets missing from the node must be added to the bundle first.",
+					Action:      client.ImportJobSpec,
 				},
 				{
 					Name:   "list",
@@ -206,6 +306,29 @@ func NewApp(client *Client) *cli.App {
 					Usage:  "Show a specific Job's details",
 					Action: client.ShowJobSpec,
 				},
+				{
+					Name:        "validate",
+					Usage:       "Check a Job Specification JSON or TOML without creating it",
+					Description: "Runs without a node, so the bridges the job calls are listed rather than checked.",
+					Action:      client.ValidateJobSpec,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "format",
+							Usage: "the format of the job spec, json or toml, by default toml for .toml files and json otherwise",
+						},
+					},
+				},
+				{
+					Name:   "wizard",
+					Usage:  "Build a runlog, cron or fluxmonitor Job Specification by answering prompts",
+					Action: client.JobSpecWizard,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "the path of the JSON file to write the job spec to, instead of printing it",
+						},
+					},
+				},
 			},
 		},
 
@@ -215,6 +338,19 @@ func NewApp(client *Client) *cli.App {
 			Usage:       "Commands for admin actions that must be run locally",
 			Description: "Commands can only be run from on the same machine as the Chainlink node.",
 			Subcommands: []cli.Command{
+				{
+					Name:  "backup",
+					Usage: "Save the configuration, keys, bridges, jobs and unconfirmed transactions of the node to a file, for restoring after a disaster",
+					Description: format(`The keys stay encrypted as the node keeps them. Runs are left out.
+               Can be run while the node is running.`),
+					Action: client.Backup,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "the path of the backup file to create",
+						},
+					},
+				},
 				{
 					Name:        "deleteuser",
 					Usage:       "Erase the *local node's* user and corresponding session to force recreation on next node launch.",
@@ -227,6 +363,50 @@ func NewApp(client *Client) *cli.App {
 					Usage:   "Import a key file to use with the node",
 					Action:  client.ImportKey,
 				},
+				{
+					Name:        "migrate",
+					Usage:       "Run the pending migrations of the database",
+					Description: "Run while the node is stopped, when DATABASE_AUTO_MIGRATE=false.",
+					Action:      client.Migrate,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "run the migrations in a transaction which is rolled back, and show how long they take and the tables they lock",
+						},
+					},
+				},
+				{
+					Name:   "migrationstatus",
+					Usage:  "Show the schema version of the database and its pending migrations",
+					Action: client.MigrationStatus,
+				},
+				{
+					Name:        "restore",
+					Usage:       "Restore a backup made with the backup command to the database",
+					Description: "Run while the node is stopped. The restore is all or nothing.",
+					Action:      client.Restore,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "file, f",
+							Usage: "the path of the backup file",
+						},
+						cli.StringFlag{
+							Name:  "on-conflict",
+							Usage: "what to do with records the database already has: skip, overwrite or fail",
+							Value: string(store.ConflictSkip),
+						},
+						cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "show what would be restored without changing the database",
+						},
+					},
+				},
+				{
+					Name:        "rotatemasterkey",
+					Usage:       "Replace the master key which sensitive database columns are encrypted with, and encrypt them with the new key",
+					Description: "Run while the node is stopped.",
+					Action:      client.RotateMasterKey,
+				},
 				{
 					Name:    "start",
 					Aliases: []string{"node", "n"},
@@ -349,6 +529,12 @@ func NewApp(client *Client) *cli.App {
 					Name:   "create",
 					Usage:  "Create an authentication key for a user of External Initiators",
 					Action: client.CreateExternalInitiator,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "require-signature",
+							Usage: "require the requests of the external initiator to be signed with its signing secret",
+						},
+					},
 				},
 				{
 					Name:   "destroy",
@@ -395,6 +581,21 @@ func NewApp(client *Client) *cli.App {
 					Usage:  "Cancel a Run with a specified ID",
 					Action: client.CancelJobRun,
 				},
+				{
+					Name:   "tail",
+					Usage:  "Print the status changes and errors of Runs as they happen",
+					Action: client.TailJobRuns,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "job",
+							Usage: "only print the Runs of the Job with the given ID",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "print each change as a line of JSON",
+						},
+					},
+				},
 			},
 		},
 
@@ -422,6 +623,10 @@ func NewApp(client *Client) *cli.App {
 							Name:  "page",
 							Usage: "page of results to display",
 						},
+						cli.BoolFlag{
+							Name:  "unconfirmed",
+							Usage: "only list the Transactions which are not confirmed yet",
+						},
 					},
 				},
 				{
@@ -429,6 +634,73 @@ func NewApp(client *Client) *cli.App {
 					Usage:  "get information on a specific Ethereum Transaction",
 					Action: client.ShowTransaction,
 				},
+				{
+					Name:   "bump",
+					Usage:  "Send the unconfirmed Transaction with the given hash again at a bumped gas price",
+					Action: client.BumpTransactionGas,
+				},
+				{
+					Name:        "cancel",
+					Usage:       "Replace the unconfirmed Transaction with the given hash by one sending nothing",
+					Description: "The replacement has the Transaction's nonce and a bumped gas price. The Run the Transaction was sent for is cancelled.",
+					Action:      client.CancelTransaction,
+				},
+				{
+					Name:   "resend-range",
+					Usage:  "Send the unconfirmed Transactions in a range of nonces again, for when the ethereum node dropped them",
+					Action: client.ResendTransactions,
+					Flags: []cli.Flag{
+						cli.Uint64Flag{
+							Name:  "from-nonce",
+							Usage: "beginning of the range of nonces to resend",
+						},
+						cli.Uint64Flag{
+							Name:  "to-nonce",
+							Usage: "end of the range of nonces to resend (inclusive)",
+						},
+						cli.StringFlag{
+							Name:  "from",
+							Usage: "only resend the Transactions sent from this address",
+						},
+					},
+				},
+			},
+		},
+
+		{
+			Name:  "vrf",
+			Usage: "Commands for managing the VRF keys of a running node",
+			Subcommands: []cli.Command{
+				{
+					Name: "create",
+					Usage: format(`Create a VRF key, encrypted with password from the
+               password file, which the node uses right away.`),
+					Flags:  flags("password, p"),
+					Action: client.RemoteCreateVRFKey,
+				},
+				{
+					Name:   "delete",
+					Usage:  "Remove the key with the given public key from the node",
+					Flags:  flags("publicKey, pk"),
+					Action: client.RemoveVRFKey,
+				},
+				{
+					Name:   "export",
+					Usage:  "Export the encrypted key with the given public key to a keyfile",
+					Flags:  append(flags("file, f"), flags("publicKey, pk")...),
+					Action: client.RemoteExportVRFKey,
+				},
+				{
+					Name:   "import",
+					Usage:  "Import an encrypted key from a keyfile",
+					Flags:  append(flags("password, p"), flags("file, f")...),
+					Action: client.RemoteImportVRFKey,
+				},
+				{
+					Name:   "list",
+					Usage:  "List the public keys and key hashes of the node's VRF keys",
+					Action: client.IndexVRFKeys,
+				},
 			},
 		},
 	}...)
@@ -46,6 +46,7 @@ type Client struct {
 	PromptingSessionRequestBuilder SessionRequestBuilder
 	ChangePasswordPrompter         ChangePasswordPrompter
 	PasswordPrompter               PasswordPrompter
+	JobSpecPrompter                JobSpecPrompter
 }
 
 func (cli *Client) errorOut(err error) error {
@@ -137,7 +138,7 @@ func createServer(handler *gin.Engine, port uint16) *http.Server {
 // HTTPClient encapsulates all methods used to interact with a chainlink node API.
 type HTTPClient interface {
 	Get(string, ...map[string]string) (*http.Response, error)
-	Post(string, io.Reader) (*http.Response, error)
+	Post(string, io.Reader, ...map[string]string) (*http.Response, error)
 	Put(string, io.Reader) (*http.Response, error)
 	Patch(string, io.Reader, ...map[string]string) (*http.Response, error)
 	Delete(string) (*http.Response, error)
@@ -165,8 +166,8 @@ func (h *authenticatedHTTPClient) Get(path string, headers ...map[string]string)
 }
 
 // Post performs an HTTP Post using the authenticated HTTP client's cookie.
-func (h *authenticatedHTTPClient) Post(path string, body io.Reader) (*http.Response, error) {
-	return h.doRequest("POST", path, body)
+func (h *authenticatedHTTPClient) Post(path string, body io.Reader, headers ...map[string]string) (*http.Response, error) {
+	return h.doRequest("POST", path, body, headers...)
 }
 
 // Put performs an HTTP Put using the authenticated HTTP client's cookie.
@@ -204,7 +205,7 @@ func (h *authenticatedHTTPClient) doRequest(verb, path string, body io.Reader, h
 
 	request.Header.Set("Content-Type", "application/json")
 	for key, value := range headers {
-		request.Header.Add(key, value)
+		request.Header.Set(key, value)
 	}
 	request.AddCookie(cookie)
 	return h.client.Do(request)
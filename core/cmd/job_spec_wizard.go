@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	clipkg "github.com/urfave/cli"
+)
+
+// ValidateJobSpec checks a job spec, JSON or TOML, without a node, so that
+// mistakes are found before it is created. The bridges its tasks call are
+// listed, as only the node knows them.
+func (cli *Client) ValidateJobSpec(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass in JSON or filepath"))
+	}
+
+	format := c.String("format")
+	if format == "" && strings.HasSuffix(c.Args().First(), ".toml") {
+		format = "toml"
+	}
+
+	var jsr models.JobSpecRequest
+	var lines models.JobSpecLines
+	switch format {
+	case "", "json":
+		buf, err := getBufferFromJSON(c.Args().First())
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		if err := json.Unmarshal(buf.Bytes(), &jsr); err != nil {
+			return cli.errorOut(errors.Wrap(err, "invalid JSON"))
+		}
+	case "toml":
+		buf, err := fromFile(c.Args().First())
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		if jsr, lines, err = models.ParseJobSpecTOML(buf.Bytes()); err != nil {
+			return cli.errorOut(err)
+		}
+	default:
+		return cli.errorOut(fmt.Errorf("unknown job spec format '%s', expected json or toml", format))
+	}
+
+	return cli.errorOut(validateJobSpecOffline(jsr, cli, lines))
+}
+
+// JobSpecWizard builds a job spec from a few common kinds, asking the user
+// for the values they need. It is run by the jobs wizard command.
+func (cli *Client) JobSpecWizard(c *clipkg.Context) error {
+	spec, err := cli.JobSpecPrompter.Prompt()
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, spec.Bytes(), "", "  "); err != nil {
+		return cli.errorOut(err)
+	}
+	buf.WriteByte('\n')
+
+	if file := c.String("output"); file != "" {
+		if !noFileToOverwrite(file) {
+			return cli.errorOut(fmt.Errorf("%s already exists", file))
+		}
+		if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+			return cli.errorOut(err)
+		}
+		fmt.Printf("Wrote the job spec to %s\n", file)
+	} else {
+		fmt.Print(buf.String())
+	}
+
+	var jsr models.JobSpecRequest
+	if err := json.Unmarshal(spec.Bytes(), &jsr); err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(validateJobSpecOffline(jsr, cli, models.JobSpecLines{}))
+}
+
+// validateJobSpecOffline validates the job spec without a node, telling the
+// bridges it calls apart from the adapter plugins in ADAPTER_PLUGINS_DIR.
+func validateJobSpecOffline(jsr models.JobSpecRequest, cli *Client, lines models.JobSpecLines) error {
+	dir := cli.Config.AdapterPluginsDir()
+	if err := adapters.LoadPlugins(dir, cli.Config.AdapterPluginTimeout().Duration(), nil); err != nil {
+		return err
+	}
+	bridges, plugins, err := services.ValidateJobOffline(models.NewJobFromRequest(jsr), cli.Config, lines)
+	if err != nil {
+		return err
+	}
+	fmt.Println("The job spec is valid")
+	if len(plugins) > 0 {
+		fmt.Printf("It calls the adapter plugins %s, which must be registered on the node\n", taskTypeList(plugins))
+	}
+	if len(bridges) > 0 && dir == "" {
+		fmt.Printf("It calls the bridges or adapter plugins %s, which must exist on the node\n", taskTypeList(bridges))
+	} else if len(bridges) > 0 {
+		fmt.Printf("It calls the bridges %s, which must exist on the node\n", taskTypeList(bridges))
+	}
+	return nil
+}
+
+func taskTypeList(taskTypes []models.TaskType) string {
+	names := make([]string, len(taskTypes))
+	for i, t := range taskTypes {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+// JobSpecPrompter is an interface primarily used for DI to obtain a job spec
+// from the User.
+type JobSpecPrompter interface {
+	Prompt() (models.JSON, error)
+}
+
+// NewJobSpecPrompter returns the production job spec prompter
+func NewJobSpecPrompter(prompter Prompter) JobSpecPrompter {
+	return jobSpecPrompter{prompter: prompter}
+}
+
+type jobSpecPrompter struct {
+	prompter Prompter
+}
+
+// Job spec kinds the wizard builds.
+const (
+	wizardRunLog      = "runlog"
+	wizardCron        = "cron"
+	wizardFluxMonitor = "fluxmonitor"
+)
+
+// spec and params are the JSON objects of the job specs built, which only
+// have the fields the user gave.
+type (
+	spec   = map[string]interface{}
+	params = map[string]interface{}
+)
+
+func (p jobSpecPrompter) Prompt() (models.JSON, error) {
+	fmt.Println("Building a job spec.")
+	kind := p.prompter.Prompt("Kind of job (runlog, cron or fluxmonitor): ")
+	var js spec
+	var err error
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case wizardRunLog:
+		js, err = p.runLog()
+	case wizardCron:
+		js, err = p.cron()
+	case wizardFluxMonitor:
+		js, err = p.fluxMonitor()
+	default:
+		err = fmt.Errorf("unknown kind of job '%s', expected runlog, cron or fluxmonitor", kind)
+	}
+	if err != nil {
+		return models.JSON{}, err
+	}
+	b, err := json.Marshal(js)
+	if err != nil {
+		return models.JSON{}, err
+	}
+	return models.ParseJSON(b)
+}
+
+// runLog builds a job fulfilling the requests of an oracle contract, which
+// give the url and path of the data.
+func (p jobSpecPrompter) runLog() (spec, error) {
+	address := strings.TrimSpace(p.prompter.Prompt("Oracle contract address (blank for any): "))
+	if address != "" && !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("'%s' is not an address", address)
+	}
+	result, err := p.resultTask()
+	if err != nil {
+		return nil, err
+	}
+
+	initr := params{}
+	if address != "" {
+		initr["address"] = address
+	}
+	tasks := []interface{}{task("httpget", nil), task("jsonparse", nil)}
+	if result != "ethbytes32" && result != "ethbool" {
+		tasks = append(tasks, task("multiply", nil))
+	}
+	tasks = append(tasks, task(result, nil), task("ethtx", nil))
+	return newSpec(models.InitiatorRunLog, initr, tasks), nil
+}
+
+// cron builds a job fetching a value on a schedule and writing it to a
+// contract.
+func (p jobSpecPrompter) cron() (spec, error) {
+	schedule := strings.TrimSpace(p.prompter.Prompt("Schedule, with its time zone (e.g. CRON_TZ=UTC */5 * * * *): "))
+	if _, err := models.CronParser.Parse(schedule); err != nil {
+		return nil, fmt.Errorf("invalid schedule: %v", err)
+	}
+	url := strings.TrimSpace(p.prompter.Prompt("URL to fetch: "))
+	path := splitList(p.prompter.Prompt("JSON path of the value, comma separated (e.g. data,price): "))
+	times, err := p.times()
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.resultTask()
+	if err != nil {
+		return nil, err
+	}
+	address, err := p.address("Address of the contract to write to: ")
+	if err != nil {
+		return nil, err
+	}
+	selector := strings.TrimSpace(p.prompter.Prompt("Function selector of the contract method (e.g. 0x4ab0d190): "))
+
+	tasks := []interface{}{
+		task("httpget", params{"get": url}),
+		task("jsonparse", params{"path": path}),
+	}
+	if times != "" {
+		tasks = append(tasks, task("multiply", params{"times": times}))
+	}
+	tasks = append(tasks,
+		task(result, nil),
+		task("ethtx", params{"address": address, "functionSelector": selector}),
+	)
+	return newSpec(models.InitiatorCron, params{"schedule": schedule}, tasks), nil
+}
+
+// fluxMonitor builds a job answering the rounds of a flux aggregator
+// contract with the median of its feeds.
+func (p jobSpecPrompter) fluxMonitor() (spec, error) {
+	address, err := p.address("Flux aggregator contract address: ")
+	if err != nil {
+		return nil, err
+	}
+	feeds := splitList(p.prompter.Prompt("Feed URLs, comma separated: "))
+	requestData := strings.TrimSpace(p.prompter.Prompt(`Request data sent to the feeds (e.g. {"data":{"from":"ETH","to":"USD"}}): `))
+	if !gjson.Valid(requestData) {
+		return nil, fmt.Errorf("invalid request data '%s'", requestData)
+	}
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(p.prompter.Prompt("Deviation threshold, in percent (e.g. 0.5): ")), 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid threshold")
+	}
+	period, err := p.duration("Poll period (e.g. 1m): ")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid poll period")
+	}
+	idle, err := p.duration("Idle timer duration (e.g. 1h): ")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid idle timer duration")
+	}
+	times, err := p.times()
+	if err != nil {
+		return nil, err
+	}
+
+	initr := params{
+		"address":     address,
+		"feeds":       feeds,
+		"requestData": json.RawMessage(requestData),
+		"threshold":   threshold,
+		"pollTimer":   params{"period": period},
+		"idleTimer":   params{"duration": idle},
+	}
+	var tasks []interface{}
+	if times != "" {
+		tasks = append(tasks, task("multiply", params{"times": times}))
+	}
+	tasks = append(tasks, task("ethint256", nil), task("ethtx", nil))
+	return newSpec(models.InitiatorFluxMonitor, initr, tasks), nil
+}
+
+func (p jobSpecPrompter) address(prompt string) (string, error) {
+	address := strings.TrimSpace(p.prompter.Prompt(prompt))
+	if !common.IsHexAddress(address) {
+		return "", fmt.Errorf("'%s' is not an address", address)
+	}
+	return address, nil
+}
+
+func (p jobSpecPrompter) duration(prompt string) (string, error) {
+	d := strings.TrimSpace(p.prompter.Prompt(prompt))
+	if _, err := time.ParseDuration(d); err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
+func (p jobSpecPrompter) resultTask() (string, error) {
+	result := strings.ToLower(strings.TrimSpace(p.prompter.Prompt("Type of the result (uint256, int256, bytes32 or bool): ")))
+	switch result {
+	case "uint256", "int256", "bytes32", "bool":
+		return "eth" + result, nil
+	default:
+		return "", fmt.Errorf("unknown result type '%s', expected uint256, int256, bytes32 or bool", result)
+	}
+}
+
+func (p jobSpecPrompter) times() (string, error) {
+	times := strings.TrimSpace(p.prompter.Prompt("Multiply the value by (blank to leave it as is): "))
+	if times == "" {
+		return "", nil
+	} else if _, err := strconv.ParseFloat(times, 64); err != nil {
+		return "", fmt.Errorf("'%s' is not a number", times)
+	}
+	return times, nil
+}
+
+func newSpec(initiatorType string, initr params, tasks []interface{}) spec {
+	return spec{
+		"initiators": []interface{}{params{"type": initiatorType, "params": initr}},
+		"tasks":      tasks,
+	}
+}
+
+func task(taskType string, p params) params {
+	t := params{"type": taskType}
+	if p != nil {
+		t["params"] = p
+	}
+	return t
+}
+
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
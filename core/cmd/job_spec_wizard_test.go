@@ -0,0 +1,111 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/cmd"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func TestJobSpecPrompter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		enteredStrings []string
+		initiator      string
+		tasks          []string
+		wantError      bool
+	}{
+		{
+			"runlog",
+			[]string{"runlog", "", "uint256"},
+			models.InitiatorRunLog,
+			[]string{"httpget", "jsonparse", "multiply", "ethuint256", "ethtx"},
+			false,
+		},
+		{
+			"cron",
+			[]string{"cron", "CRON_TZ=UTC */5 * * * *", "https://example.com/price", "data,price", "100", "int256", "0x3cCad4715152693fE3BC4460591e3D3Fbd071b42", "0x4ab0d190"},
+			models.InitiatorCron,
+			[]string{"httpget", "jsonparse", "multiply", "ethint256", "ethtx"},
+			false,
+		},
+		{
+			"fluxmonitor",
+			[]string{"fluxmonitor", "0x3cCad4715152693fE3BC4460591e3D3Fbd071b42", "https://a.example.com, https://b.example.com", `{"data":{"from":"ETH","to":"USD"}}`, "0.5", "1m", "1h", ""},
+			models.InitiatorFluxMonitor,
+			[]string{"ethint256", "ethtx"},
+			false,
+		},
+		{"unknown kind", []string{"webhook"}, "", nil, true},
+		{"bad address", []string{"fluxmonitor", "0xbeef"}, "", nil, true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			prompter := &cltest.MockCountingPrompter{T: t, EnteredStrings: test.enteredStrings}
+			spec, err := cmd.NewJobSpecPrompter(prompter).Prompt()
+			if test.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, len(test.enteredStrings), prompter.Count)
+
+			var jsr models.JobSpecRequest
+			require.NoError(t, json.Unmarshal(spec.Bytes(), &jsr))
+
+			require.Len(t, jsr.Initiators, 1)
+			assert.Equal(t, test.initiator, jsr.Initiators[0].Type)
+			var tasks []string
+			for _, task := range jsr.Tasks {
+				tasks = append(tasks, task.Type.String())
+			}
+			assert.Equal(t, test.tasks, tasks)
+		})
+	}
+}
+
+func TestClient_ValidateJobSpec(t *testing.T) {
+	t.Parallel()
+
+	client := &cmd.Client{Config: orm.NewConfig()}
+	validate := func(spec string) error {
+		set := flag.NewFlagSet("test", 0)
+		set.String("format", "", "")
+		require.NoError(t, set.Parse([]string{spec}))
+		return client.ValidateJobSpec(cli.NewContext(nil, set, nil))
+	}
+
+	assert.NoError(t, validate(`{"initiators":[{"type":"web"}],"tasks":[{"type":"noop"},{"type":"randomBridge"}]}`))
+	assert.Error(t, validate(`{"initiators":[{"type":"web"}],"tasks":[]}`))
+	assert.Error(t, validate(`{"initiators":[{"type":"cron","params":{"schedule":"* * * * *"}}],"tasks":[{"type":"noop"}]}`))
+	assert.Error(t, validate(`{"initiators":[{"type":"bogus"}],"tasks":[{"type":"noop"}]}`))
+
+	dir, err := ioutil.TempDir("", "validate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "job.toml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`
+[[initiators]]
+type = "web"
+
+[[tasks]]
+type = "sleep"
+`), 0600))
+	assert.Error(t, validate(file))
+}
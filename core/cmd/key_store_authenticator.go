@@ -14,6 +14,7 @@ import (
 type KeyStoreAuthenticator interface {
 	Authenticate(*store.Store, string) (string, error)
 	AuthenticateVRFKey(*store.Store, string) error
+	AuthenticateOCRKey(*store.Store, string) error
 }
 
 // TerminalKeyStoreAuthenticator contains fields for prompting the user and an
@@ -111,3 +112,25 @@ func (auth TerminalKeyStoreAuthenticator) AuthenticateVRFKey(store *store.Store,
 			". You can add and delete VRF keys in the DB using the "+
 			"`chainlink local vrf` subcommands")
 }
+
+// AuthenticateOCRKey creates an off-chain reporting key bundle encrypted with
+// password in store's db if there is none yet, and unlocks the bundles with
+// the password, returning an error if none of them unlock.
+func (auth TerminalKeyStoreAuthenticator) AuthenticateOCRKey(store *store.Store, password string) error {
+	bundles, err := store.FindEncryptedOCRKeyBundles()
+	if err != nil {
+		return errors.Wrap(err, "while checking for extant off-chain reporting keys")
+	}
+	if len(bundles) == 0 {
+		fmt.Println("There are no off-chain reporting keys; creating a new key bundle encrypted with given password")
+		if _, err := store.OCRKeyStore.CreateKey(password); err != nil {
+			return errors.Wrap(err, "while creating a new encrypted off-chain reporting key bundle")
+		}
+		return nil
+	}
+	unlocked, err := store.OCRKeyStore.Unlock(password)
+	if len(unlocked) == 0 {
+		return errors.Wrap(err, "there are off-chain reporting keys in the DB, but the password did not unlock any of them")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -70,6 +71,11 @@ func (cli *Client) RunNode(c *clipkg.Context) error {
 			return cli.errorOut(errors.Wrapf(authErr, "while authenticating with VRF password"))
 		}
 	}
+	if cli.Config.FeatureOffchainReporting() {
+		if authErr := cli.KeyStoreAuthenticator.AuthenticateOCRKey(store, pwd); authErr != nil {
+			return cli.errorOut(errors.Wrap(authErr, "while authenticating off-chain reporting keys"))
+		}
+	}
 
 	var user models.User
 	if _, err = NewFileAPIInitializer(c.String("api")).Initialize(store); err != nil && err != errNoCredentialFile {
@@ -302,6 +308,90 @@ func (cli *Client) DeleteUser(c *clipkg.Context) error {
 	return err
 }
 
+// RotateMasterKey replaces the master key which sensitive database columns
+// are encrypted with, and encrypts them with the new key.
+func (cli *Client) RotateMasterKey(c *clipkg.Context) error {
+	logger.SetLogger(cli.Config.CreateProductionLogger())
+	app := cli.AppFactory.NewApplication(cli.Config)
+	defer app.Stop()
+	if err := app.GetStore().RotateMasterKey(); err != nil {
+		return cli.errorOut(err)
+	}
+	logger.Info("Rotated master key")
+	return nil
+}
+
+// Migrate runs the pending migrations of the database, or with --dry-run,
+// shows how long they take and the tables they lock, without keeping their
+// changes.
+func (cli *Client) Migrate(c *clipkg.Context) error {
+	logger.SetLogger(cli.Config.CreateProductionLogger())
+	impacts, err := strpkg.RunMigrations(cli.Config, c.Bool("dry-run"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("dry-run") {
+		return cli.errorOut(cli.Render(&impacts))
+	}
+	logger.Info("Ran pending migrations")
+	return nil
+}
+
+// MigrationStatus shows the schema version of the database and its pending
+// migrations.
+func (cli *Client) MigrationStatus(c *clipkg.Context) error {
+	status, err := strpkg.MigrationStatus(cli.Config)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(cli.Render(&status))
+}
+
+// Backup saves the state the node cannot be rebuilt without to a file.
+func (cli *Client) Backup(c *clipkg.Context) error {
+	if !c.IsSet("file") || !noFileToOverwrite(c.String("file")) {
+		return cli.errorOut(errors.New("must specify path to backup file which does not already exist"))
+	}
+	backup, err := strpkg.CreateBackup(cli.Config)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	b, err := json.Marshal(backup)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if err := ioutil.WriteFile(c.String("file"), b, 0600); err != nil {
+		return cli.errorOut(err)
+	}
+	fmt.Printf("Backed up the database as of migration %s to %s\n", backup.Migration, c.String("file"))
+	return nil
+}
+
+// Restore restores a backup made with Backup to the database.
+func (cli *Client) Restore(c *clipkg.Context) error {
+	logger.SetLogger(cli.Config.CreateProductionLogger())
+	if !c.IsSet("file") {
+		return cli.errorOut(errors.New("must specify path to backup file"))
+	}
+	onConflict, err := strpkg.ParseConflictPolicy(c.String("on-conflict"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	b, err := ioutil.ReadFile(c.String("file"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var backup strpkg.Backup
+	if err := json.Unmarshal(b, &backup); err != nil {
+		return cli.errorOut(errors.Wrap(err, "reading backup"))
+	}
+	restored, err := strpkg.RestoreBackup(cli.Config, &backup, onConflict, c.Bool("dry-run"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(cli.Render(&restored))
+}
+
 // ImportKey imports a key to be used with the chainlink node
 func (cli *Client) ImportKey(c *clipkg.Context) error {
 	logger.SetLogger(cli.Config.CreateProductionLogger())
@@ -73,16 +73,39 @@ func TestClient_RunNodeShowsEnv(t *testing.T) {
 	assert.Contains(t, logs, "ETH_URL: ws://")
 	assert.Contains(t, logs, "ETH_CHAIN_ID: 3\\n")
 	assert.Contains(t, logs, "CLIENT_NODE_URL: http://")
+	assert.Contains(t, logs, "CRON_CATCH_UP: none\\n")
+	assert.Contains(t, logs, "MAX_CONCURRENT_RUNS: 0\\n")
 	assert.Contains(t, logs, "MIN_OUTGOING_CONFIRMATIONS: 6\\n")
 	assert.Contains(t, logs, "MIN_INCOMING_CONFIRMATIONS: 1\\n")
 	assert.Contains(t, logs, "ETH_GAS_BUMP_THRESHOLD: 3\\n")
 	assert.Contains(t, logs, "ETH_GAS_BUMP_WEI: 5000000000\\n")
 	assert.Contains(t, logs, "ETH_GAS_PRICE_DEFAULT: 20000000000\\n")
+	assert.Contains(t, logs, "HTTP_ALLOWED_HOSTS: []\\n")
+	assert.Contains(t, logs, "HTTP_DENIED_HOSTS: []\\n")
+	assert.Contains(t, logs, "HTTP_MAX_REDIRECTS: 10\\n")
+	assert.Contains(t, logs, "KAFKA_BROKERS: []\\n")
 	assert.Contains(t, logs, "LINK_CONTRACT_ADDRESS: 0x514910771AF9Ca656af840dff83E8264EcF986CA\\n")
 	assert.Contains(t, logs, "MINIMUM_CONTRACT_PAYMENT: 0.000000000000000100\\n")
 	assert.Contains(t, logs, "ORACLE_CONTRACT_ADDRESS: \\n")
 	assert.Contains(t, logs, "ALLOW_ORIGINS: http://localhost:3000,http://localhost:6688\\n")
 	assert.Contains(t, logs, "BRIDGE_RESPONSE_URL: http://localhost:6688\\n")
+	assert.Contains(t, logs, "BRIDGE_CACHE_STORE: memory\\n")
+	assert.Contains(t, logs, "BRIDGE_CACHE_SIZE: 1000\\n")
+	assert.Contains(t, logs, "BRIDGE_CIRCUIT_BREAKER_THRESHOLD: 0\\n")
+	assert.Contains(t, logs, "BRIDGE_CIRCUIT_BREAKER_TIMEOUT: 1m0s\\n")
+	assert.Contains(t, logs, "FLUX_MONITOR_FEED_QUARANTINE_PERIOD: 5m0s\\n")
+	assert.Contains(t, logs, "FLUX_MONITOR_FEED_QUARANTINE_THRESHOLD: 5\\n")
+	assert.Contains(t, logs, "P2P_LISTEN_PORT: 6690\\n")
+	assert.Contains(t, logs, "STUCK_RUN_CHECK_INTERVAL: 5m0s\\n")
+	assert.Contains(t, logs, "STUCK_RUN_MAX_RESUMPTIONS: 3\\n")
+	assert.Contains(t, logs, "VRF_BATCH_MAX_SIZE: 1\\n")
+	assert.Contains(t, logs, "VRF_BATCH_MAX_WAIT: 10s\\n")
+	assert.Contains(t, logs, "VRF_BATCH_MULTICALL_ADDRESS: \\n")
+	assert.Contains(t, logs, "VRF_FULFILLMENT_RETRY_BACKOFF: 1m0s\\n")
+	assert.Contains(t, logs, "VRF_MAX_FULFILLMENT_ATTEMPTS: 5\\n")
+	assert.Contains(t, logs, "VRF_MIN_CONFIRMATIONS: 6\\n")
+	assert.Contains(t, logs, "VRF_PROOF_QUEUE_SIZE: 100\\n")
+	assert.Contains(t, logs, "VRF_PROOF_WORKERS: 2\\n")
 
 	app.AssertExpectations(t)
 }
@@ -9,9 +9,9 @@ import (
 	"github.com/pkg/errors"
 	clipkg "github.com/urfave/cli"
 
-	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
 	"github.com/smartcontractkit/chainlink/core/utils"
 )
 
@@ -203,25 +203,22 @@ func getPublicKey(c *clipkg.Context) (*vrfkey.PublicKey, error) {
 	return publicKey, nil
 }
 
-// ListKeys Lists the keys in the db
+// ListKeys Lists the keys in the db, with their uncompressed public keys and
+// key hashes
 func (cli *Client) ListKeys(c *clipkg.Context) error {
-	keys, err := vRFKeyStore(cli).ListKeys()
+	keys, err := vRFKeyStore(cli).Get(nil)
 	if err != nil {
 		return err
 	}
-	// TODO(alx) Figure out how to make a nice box out of this, like the other
-	// commands do.
-	fmt.Println(
-		`********************************************************************
-Public keys of encrypted keys in database
-********************************************************************`)
-	for _, key := range keys {
-		fmt.Println(key)
-	}
-	fmt.Println(
-		"********************************************************************")
-	logger.Infow("keys", "keys", keys)
-	return nil
+	pkeys := make([]presenters.VRFKey, len(keys))
+	for i, key := range keys {
+		pkey, err := presenters.NewVRFKey(key)
+		if err != nil {
+			return errors.Wrapf(err, "while presenting key %s", key.PublicKey)
+		}
+		pkeys[i] = *pkey
+	}
+	return cli.errorOut(cli.Render(&pkeys))
 }
 
 func noFileToOverwrite(path string) bool {
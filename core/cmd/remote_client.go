@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -10,14 +11,19 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 	"github.com/smartcontractkit/chainlink/core/utils"
 	"github.com/smartcontractkit/chainlink/core/web"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
 	"github.com/manyminds/api2go/jsonapi"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
@@ -74,6 +80,7 @@ func (cli *Client) CreateExternalInitiator(c *clipkg.Context) error {
 
 	var request models.ExternalInitiatorRequest
 	request.Name = c.Args().Get(0)
+	request.RequireSignature = c.Bool("require-signature")
 
 	// process optional URL
 	if c.NArg() == 2 {
@@ -164,12 +171,28 @@ func (cli *Client) CreateJobSpec(c *clipkg.Context) error {
 		return cli.errorOut(errors.New("Must pass in JSON or filepath"))
 	}
 
-	buf, err := getBufferFromJSON(c.Args().First())
+	format := c.String("format")
+	if format == "" && strings.HasSuffix(c.Args().First(), ".toml") {
+		format = "toml"
+	}
+
+	var buf *bytes.Buffer
+	var err error
+	headers := map[string]string{}
+	switch format {
+	case "", "json":
+		buf, err = getBufferFromJSON(c.Args().First())
+	case "toml":
+		buf, err = fromFile(c.Args().First())
+		headers["Content-Type"] = models.JobSpecTOMLContentType
+	default:
+		err = fmt.Errorf("unknown job spec format '%s', expected json or toml", format)
+	}
 	if err != nil {
 		return cli.errorOut(err)
 	}
 
-	resp, err := cli.HTTP.Post("/v2/specs", buf)
+	resp, err := cli.HTTP.Post("/v2/specs", buf, headers)
 	if err != nil {
 		return cli.errorOut(err)
 	}
@@ -179,6 +202,49 @@ func (cli *Client) CreateJobSpec(c *clipkg.Context) error {
 	return cli.renderAPIResponse(resp, &js)
 }
 
+// ExportJobSpec saves the bundle of a job, with the bridges, external
+// initiators and secrets it depends on, to the requested file path.
+func (cli *Client) ExportJobSpec(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the job id to be exported"))
+	}
+	if !c.IsSet("file") || !noFileToOverwrite(c.String("file")) {
+		return cli.errorOut(errors.New("must specify path to bundle file which does not already exist"))
+	}
+
+	resp, err := cli.HTTP.Get("/v2/specs/" + c.Args().First() + "/bundle")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	b, err := cli.parseResponse(resp)
+	if err != nil {
+		return err
+	}
+	return cli.errorOut(ioutil.WriteFile(c.String("file"), b, 0600))
+}
+
+// ImportJobSpec creates the job of a bundle exported from another node,
+// along with the dependencies the node is missing.
+func (cli *Client) ImportJobSpec(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the path of the bundle file"))
+	}
+	buf, err := fromFile(c.Args().First())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	resp, err := cli.HTTP.Post("/v2/job_bundles/import", buf)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+
+	var imported presenters.ImportedJobBundle
+	return cli.renderAPIResponse(resp, &imported)
+}
+
 // ArchiveJobSpec soft deletes a job and its associated runs.
 func (cli *Client) ArchiveJobSpec(c *clipkg.Context) error {
 	if !c.Args().Present() {
@@ -450,6 +516,9 @@ func (cli *Client) ChangePassword(c *clipkg.Context) error {
 // IndexTransactions returns the list of transactions in descending order,
 // taking an optional page parameter
 func (cli *Client) IndexTransactions(c *clipkg.Context) error {
+	if c.Bool("unconfirmed") {
+		return cli.getPage("/v2/transactions?unconfirmed=true", c.Int("page"), &[]presenters.Tx{})
+	}
 	return cli.getPage("/v2/transactions", c.Int("page"), &[]presenters.Tx{})
 }
 
@@ -468,6 +537,126 @@ func (cli *Client) ShowTransaction(c *clipkg.Context) error {
 	return cli.renderAPIResponse(resp, &tx)
 }
 
+// BumpTransactionGas makes a new attempt at the unconfirmed transaction with
+// the given hash, at a bumped gas price.
+func (cli *Client) BumpTransactionGas(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the hash of the transaction"))
+	}
+	return cli.putTransaction(c.Args().First(), "gas_bump")
+}
+
+// CancelTransaction replaces the unconfirmed transaction with the given hash
+// by one sending nothing with its nonce, cancelling the run it was sent for.
+func (cli *Client) CancelTransaction(c *clipkg.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the hash of the transaction"))
+	}
+	return cli.putTransaction(c.Args().First(), "cancellation")
+}
+
+func (cli *Client) putTransaction(hash, action string) error {
+	resp, err := cli.HTTP.Put("/v2/transactions/"+hash+"/"+action, nil)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	var tx presenters.Tx
+	return cli.renderAPIResponse(resp, &tx)
+}
+
+// ResendTransactions sends the node's unconfirmed transactions in a range of
+// nonces again, for when they were dropped by the ethereum node.
+func (cli *Client) ResendTransactions(c *clipkg.Context) error {
+	if !c.IsSet("from-nonce") || !c.IsSet("to-nonce") {
+		return cli.errorOut(errors.New("Must pass the range of nonces with --from-nonce and --to-nonce"))
+	}
+	request := models.TxResendRequest{
+		BeginningNonce: c.Uint64("from-nonce"),
+		EndingNonce:    c.Uint64("to-nonce"),
+	}
+	if c.IsSet("from") {
+		if !common.IsHexAddress(c.String("from")) {
+			return cli.errorOut(fmt.Errorf("'%s' is not an address", c.String("from")))
+		}
+		from := common.HexToAddress(c.String("from"))
+		request.From = &from
+	}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/transaction_resends", bytes.NewBuffer(b))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	var txs []presenters.Tx
+	return cli.renderAPIResponse(resp, &txs)
+}
+
+// profileBundle lists the profiles saved by ProfileNode, by the names of
+// their files in the bundle.
+var profileBundle = []struct {
+	file, path string
+}{
+	{"cpu.pprof", "cpu"},
+	{"heap.pprof", "heap"},
+	{"allocs.pprof", "allocs"},
+	{"goroutine.pprof", "goroutine"},
+	{"goroutine.txt", "goroutine?debug=2"},
+	{"block.pprof", "block"},
+	{"mutex.pprof", "mutex"},
+	{"threadcreate.pprof", "threadcreate"},
+}
+
+// ProfileNode fetches the CPU, memory and goroutine profiles of the node and
+// saves them in a zip file, for reports of memory growth or goroutine leaks.
+func (cli *Client) ProfileNode(c *clipkg.Context) error {
+	file := c.String("output")
+	if file == "" {
+		file = fmt.Sprintf("chainlink-profile-%d.zip", time.Now().Unix())
+	}
+	if !noFileToOverwrite(file) {
+		return cli.errorOut(fmt.Errorf("%s already exists", file))
+	}
+
+	var buf bytes.Buffer
+	bundle := zip.NewWriter(&buf)
+	for _, p := range profileBundle {
+		path := "/v2/debug/pprof/" + p.path
+		if p.path == "cpu" && c.IsSet("duration") {
+			path += "?duration=" + url.QueryEscape(c.String("duration"))
+		}
+		fmt.Printf("Fetching %s\n", p.file)
+		resp, err := cli.HTTP.Get(path)
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		b, err := cli.parseResponse(resp)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		w, err := bundle.Create(p.file)
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		if _, err := w.Write(b); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	if err := bundle.Close(); err != nil {
+		return cli.errorOut(err)
+	}
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0600); err != nil {
+		return cli.errorOut(err)
+	}
+	fmt.Printf("Saved the profiles to %s\n", file)
+	return nil
+}
+
 // IndexTxAttempts returns the list of transactions in descending order,
 // taking an optional page parameter
 func (cli *Client) IndexTxAttempts(c *clipkg.Context) error {
@@ -586,6 +775,115 @@ func (cli *Client) CreateExtraKey(c *clipkg.Context) error {
 	return cli.printResponseBody(resp)
 }
 
+// AddExternalKey adds the key held by a KMS or HSM with the given reference to
+// the keys of the node.
+func (cli *Client) AddExternalKey(c *clipkg.Context) error {
+	if !c.IsSet("ref") {
+		return cli.errorOut(errors.New("must specify the reference of the key, as <scheme>://<ref>"))
+	}
+	password := cli.PasswordPrompter.Prompt()
+	request := models.ExternalKeyRequest{
+		CurrentPassword: password,
+		KeyRef:          c.String("ref"),
+	}
+
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	buf := bytes.NewBuffer(requestData)
+	resp, err := cli.HTTP.Post("/v2/external_keys", buf)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+
+	return cli.printResponseBody(resp)
+}
+
+// RotateKey replaces the key with the given address by a new one, sweeping
+// the funds of the old key to the new one if asked to.
+func (cli *Client) RotateKey(c *clipkg.Context) error {
+	if !common.IsHexAddress(c.String("address")) {
+		return cli.errorOut(errors.New("must specify the address of the key to retire"))
+	}
+	password := cli.PasswordPrompter.Prompt()
+	request := models.RotateKeyRequest{
+		CurrentPassword: password,
+		Sweep:           c.Bool("sweep"),
+	}
+
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	buf := bytes.NewBuffer(requestData)
+	resp, err := cli.HTTP.Post(fmt.Sprintf("/v2/keys/%s/rotate", c.String("address")), buf)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+
+	var rotation presenters.KeyRotation
+	return cli.renderAPIResponse(resp, &rotation)
+}
+
+// ExportKeys saves the ethereum and VRF keys of the node to the requested
+// file path, as a bundle encrypted with the node's password.
+func (cli *Client) ExportKeys(c *clipkg.Context) error {
+	if !c.IsSet("file") || !noFileToOverwrite(c.String("file")) {
+		return cli.errorOut(errors.New("must specify path to bundle file which does not already exist"))
+	}
+	password := cli.PasswordPrompter.Prompt()
+	requestData, err := json.Marshal(models.ExportKeysRequest{CurrentPassword: password})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	resp, err := cli.HTTP.Post("/v2/key_bundles/export", bytes.NewBuffer(requestData))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	b, err := cli.parseResponse(resp)
+	if err != nil {
+		return err
+	}
+	return cli.errorOut(ioutil.WriteFile(c.String("file"), b, 0600))
+}
+
+// ImportKeys adds the keys in a bundle exported from another node to the
+// node, or only checks them if asked for a dry run.
+func (cli *Client) ImportKeys(c *clipkg.Context) error {
+	if !c.IsSet("file") {
+		return cli.errorOut(errors.New("must specify path to bundle file"))
+	}
+	bundle, err := ioutil.ReadFile(c.String("file"))
+	if err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to read file %s", c.String("file")))
+	}
+	password := cli.PasswordPrompter.Prompt()
+	requestData, err := json.Marshal(models.ImportKeysRequest{
+		CurrentPassword: password,
+		Bundle:          bundle,
+		DryRun:          c.Bool("dryrun"),
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	resp, err := cli.HTTP.Post("/v2/key_bundles/import", bytes.NewBuffer(requestData))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+
+	var keys []presenters.ImportedKey
+	return cli.renderAPIResponse(resp, &keys)
+}
+
 // SetMinimumGasPrice specifies the minimum gas price to use for outgoing transactions
 func (cli *Client) SetMinimumGasPrice(c *clipkg.Context) error {
 	if c.NArg() != 1 {
@@ -637,6 +935,62 @@ func (cli *Client) GetConfiguration(c *clipkg.Context) error {
 	return cli.renderAPIResponse(resp, &cwl)
 }
 
+// TailJobRuns prints the status changes of the node's runs, or of those of
+// the job given, as they happen, until the node closes the connection.
+func (cli *Client) TailJobRuns(c *clipkg.Context) error {
+	u, err := url.Parse(cli.Config.ClientNodeURL())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	u.Path = "/v2/run_updates"
+	if c.IsSet("job") {
+		u.RawQuery = url.Values{"jobId": {c.String("job")}}.Encode()
+	}
+
+	cookie, err := cli.CookieAuthenticator.Cookie()
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	header := http.Header{}
+	header.Add("Cookie", cookie.String())
+	conn, resp, err := websocket.DefaultDialer.Dial(u.String(), header)
+	if err == websocket.ErrBadHandshake && resp != nil {
+		_, err = cli.parseResponse(resp)
+		return err
+	} else if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() { logger.WarnIf(conn.Close()) }()
+
+	for {
+		var update presenters.RunUpdate
+		if err := conn.ReadJSON(&update); websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return nil
+		} else if err != nil {
+			return cli.errorOut(err)
+		}
+
+		if c.Bool("json") {
+			b, err := json.Marshal(update)
+			if err != nil {
+				return cli.errorOut(err)
+			}
+			fmt.Println(string(b))
+			continue
+		}
+		fmt.Printf("%s  run %s  job %s  %s", update.UpdatedAt.Format(time.RFC3339), update.RunID, update.JobID, update.Status)
+		if update.Error != "" {
+			fmt.Printf("  error: %s", update.Error)
+		}
+		fmt.Println()
+	}
+}
+
 // CancelJob cancels a running job
 func (cli *Client) CancelJobRun(c *clipkg.Context) error {
 	if !c.Args().Present() {
@@ -653,3 +1007,100 @@ func (cli *Client) CancelJobRun(c *clipkg.Context) error {
 	}
 	return nil
 }
+
+// IndexVRFKeys lists the public keys and key hashes of the node's VRF keys.
+func (cli *Client) IndexVRFKeys(c *clipkg.Context) error {
+	resp, err := cli.HTTP.Get("/v2/vrf_keys")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	var keys []presenters.VRFKey
+	return cli.renderAPIResponse(resp, &keys)
+}
+
+// RemoteCreateVRFKey creates a VRF key on the node, encrypted with the
+// password in the password file.
+func (cli *Client) RemoteCreateVRFKey(c *clipkg.Context) error {
+	password, err := getPassword(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.postVRFKey("/v2/vrf_keys", models.VRFKeyRequest{Password: string(password)})
+}
+
+// RemoteImportVRFKey imports the encrypted VRF key in the key file into the
+// node, which decrypts it with the password in the password file. It prompts
+// for the keystore password of the node.
+func (cli *Client) RemoteImportVRFKey(c *clipkg.Context) error {
+	password, keyjson, err := getPasswordAndKeyFile(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.postVRFKey("/v2/vrf_keys/import", models.VRFKeyRequest{
+		CurrentPassword: cli.PasswordPrompter.Prompt(),
+		Password:        string(password),
+		Key:             keyjson,
+	})
+}
+
+func (cli *Client) postVRFKey(path string, request models.VRFKeyRequest) error {
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post(path, bytes.NewBuffer(requestData))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	var key presenters.VRFKey
+	return cli.renderAPIResponse(resp, &key)
+}
+
+// RemoteExportVRFKey saves the node's encrypted copy of the VRF key with the
+// given public key to the requested file path. It prompts for the keystore
+// password of the node.
+func (cli *Client) RemoteExportVRFKey(c *clipkg.Context) error {
+	publicKey, err := getPublicKey(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if !c.IsSet("file") || !noFileToOverwrite(c.String("file")) {
+		return cli.errorOut(errors.New("must specify path to key file which does not already exist"))
+	}
+	password := cli.PasswordPrompter.Prompt()
+	requestData, err := json.Marshal(models.ExportKeysRequest{CurrentPassword: password})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post(fmt.Sprintf("/v2/vrf_keys/%s/export", publicKey), bytes.NewBuffer(requestData))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	b, err := cli.parseResponse(resp)
+	if err != nil {
+		return err
+	}
+	var key vrfkey.EncryptedSecretKey
+	if err := json.Unmarshal(b, &key); err != nil {
+		return cli.errorOut(errors.Wrap(err, "while parsing exported key"))
+	}
+	return cli.errorOut(key.WriteToDisk(c.String("file")))
+}
+
+// RemoveVRFKey deletes the VRF key with the given public key from the node.
+func (cli *Client) RemoveVRFKey(c *clipkg.Context) error {
+	publicKey, err := getPublicKey(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Delete(fmt.Sprintf("/v2/vrf_keys/%s", publicKey))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer resp.Body.Close()
+	var key presenters.VRFKey
+	return cli.renderAPIResponse(resp, &key)
+}
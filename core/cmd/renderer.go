@@ -5,8 +5,11 @@ import (
 	"io"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/migrations"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
@@ -77,6 +80,22 @@ func (rt RendererTable) Render(v interface{}) error {
 		return rt.renderConfigPatchResponse(typed)
 	case *presenters.ConfigWhitelist:
 		return rt.renderConfiguration(*typed)
+	case *[]presenters.VRFKey:
+		return rt.renderVRFKeys(*typed)
+	case *presenters.VRFKey:
+		return rt.renderVRFKeys([]presenters.VRFKey{*typed})
+	case *presenters.KeyRotation:
+		return rt.renderKeyRotation(*typed)
+	case *[]presenters.ImportedKey:
+		return rt.renderImportedKeys(*typed)
+	case *presenters.ImportedJobBundle:
+		return rt.renderImportedJobBundle(*typed)
+	case *migrations.Status:
+		return rt.renderMigrationStatus(*typed)
+	case *[]migrations.LockImpact:
+		return rt.renderLockImpacts(*typed)
+	case *[]store.RestoredRecord:
+		return rt.renderRestoredRecords(*typed)
 	default:
 		return fmt.Errorf("Unable to render object of type %T: %v", typed, typed)
 	}
@@ -179,7 +198,7 @@ func (rt RendererTable) renderBridge(bridge models.BridgeType) error {
 		bridge.Name.String(),
 		bridge.URL.String(),
 		strconv.FormatUint(uint64(bridge.Confirmations), 10),
-		bridge.OutgoingToken,
+		bridge.OutgoingToken.String(),
 	})
 	render("Bridge", table)
 	return nil
@@ -211,6 +230,30 @@ func (rt RendererTable) renderJob(job presenters.JobSpec) error {
 	return err
 }
 
+func (rt RendererTable) renderImportedJobBundle(imported presenters.ImportedJobBundle) error {
+	if err := rt.renderJob(imported.Job); err != nil {
+		return err
+	}
+	for _, bta := range imported.Bridges {
+		if err := rt.renderBridgeAuthentication(*bta); err != nil {
+			return err
+		}
+	}
+	for _, eia := range imported.ExternalInitiators {
+		if err := rt.renderExternalInitiatorAuthentication(*eia); err != nil {
+			return err
+		}
+	}
+	if len(imported.Secrets) > 0 {
+		table := rt.newTable([]string{"Name"})
+		for _, name := range imported.Secrets {
+			table.Append([]string{name})
+		}
+		render("Secrets Created", table)
+	}
+	return nil
+}
+
 func (rt RendererTable) renderJobRun(run presenters.JobRun) error {
 	err := rt.renderJobRuns([]presenters.JobRun{run})
 	return err
@@ -304,7 +347,7 @@ func (rt RendererTable) renderServiceAgreement(sa presenters.ServiceAgreement) e
 }
 
 func (rt RendererTable) renderExternalInitiatorAuthentication(eia presenters.ExternalInitiatorAuthentication) error {
-	table := rt.newTable([]string{"Name", "URL", "AccessKey", "Secret", "OutgoingToken", "OutgoingSecret"})
+	table := rt.newTable([]string{"Name", "URL", "AccessKey", "Secret", "OutgoingToken", "OutgoingSecret", "RequireSignature", "SigningSecret"})
 	table.Append([]string{
 		eia.Name,
 		eia.URL.String(),
@@ -312,6 +355,8 @@ func (rt RendererTable) renderExternalInitiatorAuthentication(eia presenters.Ext
 		eia.Secret,
 		eia.OutgoingToken,
 		eia.OutgoingSecret,
+		strconv.FormatBool(eia.RequireSignature),
+		eia.SigningSecret,
 	})
 	render("External Initiator Credentials:", table)
 	return nil
@@ -371,11 +416,108 @@ func (rt RendererTable) renderTxs(txs []presenters.Tx) error {
 
 func (rt RendererTable) renderConfigPatchResponse(config *web.ConfigPatchResponse) error {
 	table := rt.newTable([]string{"Config", "Old Value", "New Value"})
+	changes := []struct {
+		name   string
+		change *web.Change
+	}{
+		{"EthGasPriceDefault", config.EthGasPriceDefault},
+		{"EthMaxGasPriceWei", config.EthMaxGasPriceWei},
+		{"ExternalInitiatorHealthInterval", config.ExternalInitiatorHealthInterval},
+		{"LogLevel", config.LogLevel},
+		{"LogModuleLevels", config.LogModuleLevels},
+		{"SessionTimeout", config.SessionTimeout},
+		{"StuckRunCheckInterval", config.StuckRunCheckInterval},
+	}
+	for _, c := range changes {
+		if c.change != nil {
+			table.Append([]string{c.name, c.change.From, c.change.To})
+		}
+	}
+	render("Configuration Changes", table)
+	return nil
+}
+
+func (rt RendererTable) renderVRFKeys(keys []presenters.VRFKey) error {
+	table := rt.newTable([]string{"Compressed", "Uncompressed", "Hash", "Created At"})
+	for _, key := range keys {
+		table.Append([]string{
+			key.Compressed,
+			key.Uncompressed,
+			key.Hash.Hex(),
+			utils.ISO8601UTC(key.CreatedAt),
+		})
+	}
+
+	render("VRF Keys", table)
+	return nil
+}
+
+func (rt RendererTable) renderKeyRotation(rotation presenters.KeyRotation) error {
+	table := rt.newTable([]string{"Retired", "Replacement", "Sweep Transactions"})
+	hashes := make([]string, len(rotation.SweepTxHashes))
+	for i, hash := range rotation.SweepTxHashes {
+		hashes[i] = hash.Hex()
+	}
 	table.Append([]string{
-		"EthGasPriceDefault",
-		config.EthGasPriceDefault.From,
-		config.EthGasPriceDefault.To,
+		rotation.Retired.Hex(),
+		rotation.Replacement.Hex(),
+		strings.Join(hashes, "\n"),
 	})
-	render("Configuration Changes", table)
+
+	render("Key Rotation", table)
+	return nil
+}
+
+func (rt RendererTable) renderImportedKeys(keys []presenters.ImportedKey) error {
+	table := rt.newTable([]string{"Type", "Key", "Status"})
+	for _, k := range keys {
+		table.Append([]string{k.Type, k.Key, k.Status})
+	}
+
+	render("Imported Keys", table)
+	return nil
+}
+
+func (rt RendererTable) renderMigrationStatus(status migrations.Status) error {
+	table := rt.newTable([]string{"Current", "Latest", "Applied", "Pending"})
+	table.Append([]string{
+		status.Current,
+		status.Latest,
+		strconv.Itoa(status.Applied),
+		strings.Join(status.Pending, "\n"),
+	})
+
+	render("Migrations", table)
+	return nil
+}
+
+func (rt RendererTable) renderLockImpacts(impacts []migrations.LockImpact) error {
+	table := rt.newTable([]string{"Migration", "Duration", "Table", "Lock", "Estimated Rows"})
+	for _, impact := range impacts {
+		if len(impact.Locks) == 0 {
+			table.Append([]string{impact.ID, impact.Duration.String(), "", "", ""})
+		}
+		for _, lock := range impact.Locks {
+			table.Append([]string{
+				impact.ID,
+				impact.Duration.String(),
+				lock.Table,
+				lock.Mode,
+				strconv.FormatInt(lock.EstimatedRows, 10),
+			})
+		}
+	}
+
+	render("Migration Lock Impact", table)
+	return nil
+}
+
+func (rt RendererTable) renderRestoredRecords(records []store.RestoredRecord) error {
+	table := rt.newTable([]string{"Table", "Key", "Action"})
+	for _, r := range records {
+		table.Append([]string{r.Table, r.Key, r.Action})
+	}
+
+	render("Restored", table)
 	return nil
 }
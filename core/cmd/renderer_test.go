@@ -109,7 +109,7 @@ func TestRendererTable_RenderBridgeShow(t *testing.T) {
 		name, content string
 	}{
 		{"name", bridge.Name.String()},
-		{"outgoing token", bridge.OutgoingToken},
+		{"outgoing token", bridge.OutgoingToken.String()},
 	}
 
 	for _, test := range tests {
@@ -157,7 +157,7 @@ func TestRendererTable_RenderBridgeList(t *testing.T) {
 		wantFound     bool
 	}{
 		{"name", bridge.Name.String(), true},
-		{"outgoing token", bridge.OutgoingToken, false},
+		{"outgoing token", bridge.OutgoingToken.String(), false},
 	}
 
 	for _, test := range tests {
@@ -313,7 +313,7 @@ func TestRendererTable_PatchResponse(t *testing.T) {
 	r := cmd.RendererTable{Writer: buffer}
 
 	patchResponse := web.ConfigPatchResponse{
-		EthGasPriceDefault: web.Change{
+		EthGasPriceDefault: &web.Change{
 			From: "98721",
 			To:   "53276",
 		},
@@ -0,0 +1,87 @@
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// errorSelector is the selector of Error(string), which the data of a revert
+// with a reason is encoded as.
+var errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// RevertError is the error of a call which reverted, with its reason if the
+// node gave one.
+type RevertError struct {
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return fmt.Sprintf("execution reverted: %s", e.Reason)
+}
+
+// RevertReason decodes the reason from the data returned by a call which
+// reverted, returning false if the data is not an encoded Error(string).
+func RevertReason(data []byte) (string, bool) {
+	if len(data) < len(errorSelector) || !bytes.Equal(data[:len(errorSelector)], errorSelector) {
+		return "", false
+	}
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return "", false
+	}
+	values, err := abi.Arguments{{Type: stringType}}.UnpackValues(data[len(errorSelector):])
+	if err != nil || len(values) != 1 {
+		return "", false
+	}
+	reason, ok := values[0].(string)
+	return reason, ok
+}
+
+// The JSON-RPC error codes of calls which reverted, as geth and Parity give
+// them.
+const (
+	revertErrorCode       = 3
+	parityRevertErrorCode = -32015
+)
+
+// dataError is an error of the node carrying the data of its JSON-RPC error,
+// which for reverted calls is what they returned.
+type dataError interface {
+	ErrorData() interface{}
+}
+
+// AsRevertError returns the RevertError of an error returned by the node for
+// a call which reverted, as told by the code of its JSON-RPC error, and false
+// for any other error, such as that of a node which could not be reached. The
+// reason is decoded from the data of the error, if it has any.
+func AsRevertError(err error) (*RevertError, bool) {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return nil, false
+	}
+	if code := rpcErr.ErrorCode(); code != revertErrorCode && code != parityRevertErrorCode {
+		return nil, false
+	}
+	revert := &RevertError{}
+	var withData dataError
+	if !errors.As(err, &withData) {
+		return revert, true
+	}
+	if encoded, ok := withData.ErrorData().(string); ok {
+		// Parity prefixes the data with "Reverted "
+		encoded = strings.TrimPrefix(encoded, "Reverted ")
+		if data, err := hexutil.Decode(encoded); err == nil {
+			revert.Reason, _ = RevertReason(data)
+		}
+	}
+	return revert, true
+}
@@ -0,0 +1,71 @@
+package eth_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevertReason(t *testing.T) {
+	t.Parallel()
+
+	data := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000f" +
+		"4e6f7420656e6f756768204c494e4b0000000000000000000000000000000000")
+	reason, ok := eth.RevertReason(data)
+	require.True(t, ok)
+	assert.Equal(t, "Not enough LINK", reason)
+
+	_, ok = eth.RevertReason(hexutil.MustDecode("0x0000000000000000000000000000000000000000000000000000000000000001"))
+	assert.False(t, ok)
+}
+
+// rpcError is a JSON-RPC error of the node, with its data.
+type rpcError struct {
+	code    int
+	message string
+	data    interface{}
+}
+
+func (e rpcError) Error() string          { return e.message }
+func (e rpcError) ErrorCode() int         { return e.code }
+func (e rpcError) ErrorData() interface{} { return e.data }
+
+func TestAsRevertError(t *testing.T) {
+	t.Parallel()
+
+	reasonData := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000f" +
+		"4e6f7420656e6f756768204c494e4b0000000000000000000000000000000000"
+
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+		revert bool
+	}{
+		{"geth with reason", rpcError{3, "execution reverted: Not enough LINK", reasonData}, "Not enough LINK", true},
+		{"geth without reason", rpcError{3, "execution reverted", nil}, "", true},
+		{"parity with reason", rpcError{-32015, "VM execution error.", "Reverted " + reasonData}, "Not enough LINK", true},
+		{"other error code", rpcError{-32000, "execution reverted by a proxy", nil}, "", false},
+		{"not a JSON-RPC error", errors.New("dial tcp 127.0.0.1:8546: connect: connection refused"), "", false},
+		{"no error", nil, "", false},
+	}
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			revert, ok := eth.AsRevertError(test.err)
+			require.Equal(t, test.revert, ok)
+			if ok {
+				assert.Equal(t, test.reason, revert.Reason)
+			}
+		})
+	}
+}
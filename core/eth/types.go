@@ -64,10 +64,16 @@ func (l Log) GetIndex() uint {
 	return l.Index
 }
 
+// GetBlockNumber returns the number of the log's block
+func (l Log) GetBlockNumber() uint64 {
+	return l.BlockNumber
+}
+
 // The RawLog interface provides a consistent interface for
 // different log types around the app
 type RawLog interface {
 	GetBlockHash() common.Hash
+	GetBlockNumber() uint64
 	GetIndex() uint
 }
 
@@ -149,8 +155,13 @@ type Transaction struct {
 // Block represents a full block
 // See: https://github.com/ethereum/go-ethereum/blob/0e6ea9199ca701ee4c96220e873884327c8d18ff/core/types/block.go#L147
 type Block struct {
-	Number       hexutil.Uint64 `json:"number"`
-	Transactions []Transaction  `json:"transactions"`
+	Number        hexutil.Uint64 `json:"number"`
+	Hash          common.Hash    `json:"hash"`
+	Timestamp     hexutil.Uint64 `json:"timestamp"`
+	GasLimit      hexutil.Uint64 `json:"gasLimit"`
+	GasUsed       hexutil.Uint64 `json:"gasUsed"`
+	BaseFeePerGas *hexutil.Big   `json:"baseFeePerGas"`
+	Transactions  []Transaction  `json:"transactions"`
 }
 
 var emptyHash = common.Hash{}
@@ -170,6 +181,10 @@ type TxReceipt struct {
 	BlockHash   *common.Hash `json:"blockHash"`
 	Hash        common.Hash  `json:"transactionHash"`
 	Logs        []Log        `json:"logs"`
+	// GasUsed is the gas the transaction used, and EffectiveGasPrice the
+	// price paid for it, which nodes predating EIP-1559 do not return.
+	GasUsed           *hexutil.Uint64 `json:"gasUsed,omitempty"`
+	EffectiveGasPrice *utils.Big      `json:"effectiveGasPrice,omitempty"`
 }
 
 // Unconfirmed returns true if the transaction is not confirmed.
@@ -120,6 +120,7 @@ func NewTestConfig(t testing.TB) *TestConfig {
 	rawConfig.Set("ROOT", rootdir)
 	rawConfig.Set("SESSION_TIMEOUT", "2m")
 	rawConfig.SecretGenerator = mockSecretGenerator{}
+	rawConfig.MasterKeyGenerator = mockSecretGenerator{}
 	config := TestConfig{t: t, Config: rawConfig}
 	return &config
 }
@@ -475,8 +476,8 @@ func (r *HTTPClientCleaner) Get(path string, headers ...map[string]string) (*htt
 	return bodyCleaner(r.t, resp, err)
 }
 
-func (r *HTTPClientCleaner) Post(path string, body io.Reader) (*http.Response, func()) {
-	resp, err := r.HTTPClient.Post(path, body)
+func (r *HTTPClientCleaner) Post(path string, body io.Reader, headers ...map[string]string) (*http.Response, func()) {
+	resp, err := r.HTTPClient.Post(path, body, headers...)
 	return bodyCleaner(r.t, resp, err)
 }
 
@@ -2,11 +2,17 @@ package cltest
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/binary"
 	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
+	"math"
 	"math/big"
 	"net/http"
 	"net/url"
@@ -94,6 +100,35 @@ func NewJobWithWebInitiator() models.JobSpec {
 	return j
 }
 
+// NewJobWithKeeperInitiator creates a new Job with a keeper initiator of the
+// upkeep contract at address
+func NewJobWithKeeperInitiator(address common.Address) models.JobSpec {
+	j := NewJob()
+	j.Tasks = nil
+	j.Initiators = []models.Initiator{{
+		JobSpecID: j.ID,
+		Type:      models.InitiatorKeeper,
+		InitiatorParams: models.InitiatorParams{
+			Address: address,
+			Keeper:  models.KeeperConfig{CheckData: []byte{1}, CooldownBlocks: 3},
+		},
+	}}
+	return j
+}
+
+// NewJobWithKafkaInitiator create new Job with kafka initiator
+func NewJobWithKafkaInitiator(topics ...string) models.JobSpec {
+	j := NewJob()
+	j.Initiators = []models.Initiator{{
+		JobSpecID: j.ID,
+		Type:      models.InitiatorKafka,
+		InitiatorParams: models.InitiatorParams{
+			KafkaTopics: topics,
+		},
+	}}
+	return j
+}
+
 // NewJobWithLogInitiator create new Job with ethlog initiator
 func NewJobWithLogInitiator() models.JobSpec {
 	j := NewJob()
@@ -128,6 +163,49 @@ func NewJobWithSALogInitiator() models.JobSpec {
 	return j
 }
 
+// NewJobWithRandomnessLogInitiator creates a new JobSpec with the
+// RandomnessLog initiator
+func NewJobWithRandomnessLogInitiator() models.JobSpec {
+	j := NewJobWithRunLogInitiator()
+	j.Initiators[0].Type = models.InitiatorRandomnessLog
+	return j
+}
+
+// CreateVRFRequest creates a pending VRF request received by the job's first
+// initiator in the given block.
+func CreateVRFRequest(t *testing.T, store *strpkg.Store, job models.JobSpec, blockNumber uint64) *models.VRFRequest {
+	return CreateVRFRequestWithKeyHash(t, store, job, blockNumber, NewHash())
+}
+
+// CreateVRFRequestWithKeyHash creates a pending VRF request for randomness
+// from the key with the given hash, received by the job's first initiator in
+// the given block.
+func CreateVRFRequestWithKeyHash(t *testing.T, store *strpkg.Store, job models.JobSpec, blockNumber uint64, keyHash common.Hash) *models.VRFRequest {
+	seed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+	require.NoError(t, err)
+	r := vrf.RandomnessRequestLog{
+		KeyHash: keyHash,
+		Seed:    seed,
+		JobID:   models.IDToTopic(job.ID),
+		Sender:  NewAddress(),
+		Fee:     assets.NewLink(100),
+	}
+	log := NewRandomnessRequestLog(t, r, job.Initiators[0].Address, int(blockNumber))
+	request, err := models.NewVRFRequest(r.RequestID(), job.Initiators[0], log)
+	require.NoError(t, err)
+	require.NoError(t, store.CreateVRFRequest(request))
+
+	requests, err := store.VRFRequestsWithStatus(models.VRFRequestPending)
+	require.NoError(t, err)
+	for _, r := range requests {
+		if r.RequestID == request.RequestID {
+			return &r
+		}
+	}
+	t.Fatal("VRF request not created")
+	return nil
+}
+
 // NewJobWithRunAtInitiator create new Job with RunAt initiator
 func NewJobWithRunAtInitiator(t time.Time) models.JobSpec {
 	j := NewJob()
@@ -343,6 +421,31 @@ func NewBridgeType(t testing.TB, info ...string) (*models.BridgeTypeAuthenticati
 	return bta, bt
 }
 
+// NewClientCertificateRequest returns a request to add a new self signed
+// client certificate with the given name.
+func NewClientCertificateRequest(t testing.TB, name string) models.ClientCertificateRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return models.ClientCertificateRequest{
+		Name:        name,
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
 // WebURL parses a url into a models.WebURL
 func WebURL(t testing.TB, unparsed string) models.WebURL {
 	parsed, err := url.Parse(unparsed)
@@ -509,6 +509,10 @@ func (a CallbackAuthenticator) AuthenticateVRFKey(*store.Store, string) error {
 	return nil
 }
 
+func (a CallbackAuthenticator) AuthenticateOCRKey(*store.Store, string) error {
+	return nil
+}
+
 var _ cmd.KeyStoreAuthenticator = CallbackAuthenticator{}
 
 // BlockedRunner is a Runner that blocks until its channel is posted to
@@ -617,14 +621,25 @@ func (*MockCron) Stop() context.Context {
 
 // AddFunc appends a schedule to mockcron entries
 func (mc *MockCron) AddFunc(schd string, fn func()) (cron.EntryID, error) {
+	mc.nextID++
 	mc.Entries = append(mc.Entries, MockCronEntry{
+		ID:       mc.nextID,
 		Schedule: schd,
 		Function: fn,
 	})
-	mc.nextID++
 	return mc.nextID, nil
 }
 
+// Remove deletes the mockcron entry with the given ID
+func (mc *MockCron) Remove(id cron.EntryID) {
+	for i, entry := range mc.Entries {
+		if entry.ID == id {
+			mc.Entries = append(mc.Entries[:i], mc.Entries[i+1:]...)
+			return
+		}
+	}
+}
+
 // RunEntries run every function for each mockcron entry
 func (mc *MockCron) RunEntries() {
 	for _, entry := range mc.Entries {
@@ -634,6 +649,7 @@ func (mc *MockCron) RunEntries() {
 
 // MockCronEntry a cron schedule and function
 type MockCronEntry struct {
+	ID       cron.EntryID
 	Schedule string
 	Function func()
 }
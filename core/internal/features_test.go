@@ -785,7 +785,7 @@ func TestIntegration_ExternalInitiator(t *testing.T) {
 	require.Equal(t, eiCreate["url"], ei.URL.String())
 	require.Equal(t, strings.ToLower(eiCreate["name"]), ei.Name)
 	require.Equal(t, eip.AccessKey, ei.AccessKey)
-	require.Equal(t, eip.OutgoingSecret, ei.OutgoingSecret)
+	require.Equal(t, eip.OutgoingSecret, ei.OutgoingSecret.String())
 
 	jobSpec := cltest.FixtureCreateJobViaWeb(t, app, "./testdata/external_initiator_job.json")
 	assert.Equal(t,
@@ -832,7 +832,7 @@ func TestIntegration_ExternalInitiator_WithoutURL(t *testing.T) {
 
 	require.Equal(t, strings.ToLower(eiCreate["name"]), ei.Name)
 	require.Equal(t, eip.AccessKey, ei.AccessKey)
-	require.Equal(t, eip.OutgoingSecret, ei.OutgoingSecret)
+	require.Equal(t, eip.OutgoingSecret, ei.OutgoingSecret.String())
 
 	jobSpec := cltest.FixtureCreateJobViaWeb(t, app, "./testdata/external_initiator_job.json")
 
@@ -14,6 +14,8 @@ import (
 	store "github.com/smartcontractkit/chainlink/core/store"
 
 	synchronization "github.com/smartcontractkit/chainlink/core/services/synchronization"
+
+	time "time"
 )
 
 // Application is an autogenerated mock type for the Application type
@@ -35,6 +37,34 @@ func (_m *Application) AddJob(job models.JobSpec) error {
 	return r0
 }
 
+// AddJobs provides a mock function with given fields: jobs
+func (_m *Application) AddJobs(jobs []models.JobSpec) error {
+	ret := _m.Called(jobs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]models.JobSpec) error); ok {
+		r0 = rf(jobs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddJobWithDependencies provides a mock function with given fields: job, deps
+func (_m *Application) AddJobWithDependencies(job models.JobSpec, deps models.JobDependencies) error {
+	ret := _m.Called(job, deps)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(models.JobSpec, models.JobDependencies) error); ok {
+		r0 = rf(job, deps)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddServiceAgreement provides a mock function with given fields: _a0
 func (_m *Application) AddServiceAgreement(_a0 *models.ServiceAgreement) error {
 	ret := _m.Called(_a0)
@@ -63,6 +93,20 @@ func (_m *Application) ArchiveJob(_a0 *models.ID) error {
 	return r0
 }
 
+// ArchiveJobs provides a mock function with given fields: _a0
+func (_m *Application) ArchiveJobs(_a0 []*models.ID) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*models.ID) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Cancel provides a mock function with given fields: runID
 func (_m *Application) Cancel(runID *models.ID) (*models.JobRun, error) {
 	ret := _m.Called(runID)
@@ -178,6 +222,27 @@ func (_m *Application) NewBox() packr.Box {
 	return r0
 }
 
+// ReplayLogs provides a mock function with given fields: jobID, fromBlock, toBlock
+func (_m *Application) ReplayLogs(jobID *models.ID, fromBlock uint64, toBlock uint64) (int, error) {
+	ret := _m.Called(jobID, fromBlock, toBlock)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*models.ID, uint64, uint64) int); ok {
+		r0 = rf(jobID, fromBlock, toBlock)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*models.ID, uint64, uint64) error); ok {
+		r1 = rf(jobID, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResumeAllConfirming provides a mock function with given fields: currentBlockHeight
 func (_m *Application) ResumeAllConfirming(currentBlockHeight *big.Int) error {
 	ret := _m.Called(currentBlockHeight)
@@ -220,6 +285,41 @@ func (_m *Application) ResumeAllInProgress() error {
 	return r0
 }
 
+// ResumeAllParked provides a mock function with given fields:
+func (_m *Application) ResumeAllParked() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeAllSleeping provides a mock function with given fields:
+func (_m *Application) ResumeAllSleeping() (time.Time, error) {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResumePending provides a mock function with given fields: runID, input
 func (_m *Application) ResumePending(runID *models.ID, input models.BridgeRunResult) error {
 	ret := _m.Called(runID, input)
@@ -234,6 +334,55 @@ func (_m *Application) ResumePending(runID *models.ID, input models.BridgeRunRes
 	return r0
 }
 
+// ResumePendingManually provides a mock function with given fields: runID, input, resumedBy
+func (_m *Application) ResumePendingManually(runID *models.ID, input models.BridgeRunResult, resumedBy string) error {
+	ret := _m.Called(runID, input, resumedBy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ID, models.BridgeRunResult, string) error); ok {
+		r0 = rf(runID, input, resumedBy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeStuck provides a mock function with given fields: runID, stuckBefore
+func (_m *Application) ResumeStuck(runID *models.ID, stuckBefore time.Time) error {
+	ret := _m.Called(runID, stuckBefore)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ID, time.Time) error); ok {
+		r0 = rf(runID, stuckBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RollbackJob provides a mock function with given fields: ID, version
+func (_m *Application) RollbackJob(ID *models.ID, version uint32) (models.JobSpec, error) {
+	ret := _m.Called(ID, version)
+
+	var r0 models.JobSpec
+	if rf, ok := ret.Get(0).(func(*models.ID, uint32) models.JobSpec); ok {
+		r0 = rf(ID, version)
+	} else {
+		r0 = ret.Get(0).(models.JobSpec)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*models.ID, uint32) error); ok {
+		r1 = rf(ID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Application) Start() error {
 	ret := _m.Called()
@@ -262,6 +411,55 @@ func (_m *Application) Stop() error {
 	return r0
 }
 
+// TerminateServiceAgreement provides a mock function with given fields: id
+func (_m *Application) TerminateServiceAgreement(id string) (models.ServiceAgreement, error) {
+	ret := _m.Called(id)
+
+	var r0 models.ServiceAgreement
+	if rf, ok := ret.Get(0).(func(string) models.ServiceAgreement); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(models.ServiceAgreement)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnarchiveJobs provides a mock function with given fields: _a0
+func (_m *Application) UnarchiveJobs(_a0 []*models.ID) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*models.ID) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateJob provides a mock function with given fields: job
+func (_m *Application) UpdateJob(job models.JobSpec) error {
+	ret := _m.Called(job)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(models.JobSpec) error); ok {
+		r0 = rf(job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WakeSessionReaper provides a mock function with given fields:
 func (_m *Application) WakeSessionReaper() {
 	_m.Called()
@@ -13,6 +13,27 @@ type LogBroadcast struct {
 	mock.Mock
 }
 
+// ConsumeOnce provides a mock function with given fields: process
+func (_m *LogBroadcast) ConsumeOnce(process func()) (bool, error) {
+	ret := _m.Called(process)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(func()) bool); ok {
+		r0 = rf(process)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(func()) error); ok {
+		r1 = rf(process)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Log provides a mock function with given fields:
 func (_m *LogBroadcast) Log() interface{} {
 	ret := _m.Called()
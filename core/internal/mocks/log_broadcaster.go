@@ -6,6 +6,8 @@ import (
 	common "github.com/ethereum/go-ethereum/common"
 	eth "github.com/smartcontractkit/chainlink/core/services/eth"
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/smartcontractkit/chainlink/core/store/models"
 )
 
 // LogBroadcaster is an autogenerated mock type for the LogBroadcaster type
@@ -53,6 +55,27 @@ func (_m *LogBroadcaster) Register(address common.Address, listener eth.LogListe
 	return r0
 }
 
+// Replay provides a mock function with given fields: jobID, fromBlock, toBlock
+func (_m *LogBroadcaster) Replay(jobID *models.ID, fromBlock uint64, toBlock uint64) (int, error) {
+	ret := _m.Called(jobID, fromBlock, toBlock)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*models.ID, uint64, uint64) int); ok {
+		r0 = rf(jobID, fromBlock, toBlock)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*models.ID, uint64, uint64) error); ok {
+		r1 = rf(jobID, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *LogBroadcaster) Start() {
 	_m.Called()
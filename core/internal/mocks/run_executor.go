@@ -25,3 +25,17 @@ func (_m *RunExecutor) Execute(_a0 *models.ID) error {
 
 	return r0
 }
+
+// Interrupt provides a mock function with given fields: _a0
+func (_m *RunExecutor) Interrupt(_a0 *models.ID) bool {
+	ret := _m.Called(_a0)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*models.ID) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
@@ -7,6 +7,8 @@ import (
 
 	models "github.com/smartcontractkit/chainlink/core/store/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // RunManager is an autogenerated mock type for the RunManager type
@@ -125,6 +127,41 @@ func (_m *RunManager) ResumeAllInProgress() error {
 	return r0
 }
 
+// ResumeAllParked provides a mock function with given fields:
+func (_m *RunManager) ResumeAllParked() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeAllSleeping provides a mock function with given fields:
+func (_m *RunManager) ResumeAllSleeping() (time.Time, error) {
+	ret := _m.Called()
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResumePending provides a mock function with given fields: runID, input
 func (_m *RunManager) ResumePending(runID *models.ID, input models.BridgeRunResult) error {
 	ret := _m.Called(runID, input)
@@ -138,3 +175,31 @@ func (_m *RunManager) ResumePending(runID *models.ID, input models.BridgeRunResu
 
 	return r0
 }
+
+// ResumePendingManually provides a mock function with given fields: runID, input, resumedBy
+func (_m *RunManager) ResumePendingManually(runID *models.ID, input models.BridgeRunResult, resumedBy string) error {
+	ret := _m.Called(runID, input, resumedBy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ID, models.BridgeRunResult, string) error); ok {
+		r0 = rf(runID, input, resumedBy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeStuck provides a mock function with given fields: runID, stuckBefore
+func (_m *RunManager) ResumeStuck(runID *models.ID, stuckBefore time.Time) error {
+	ret := _m.Called(runID, stuckBefore)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*models.ID, time.Time) error); ok {
+		r0 = rf(runID, stuckBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
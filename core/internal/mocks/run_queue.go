@@ -12,6 +12,34 @@ type RunQueue struct {
 	mock.Mock
 }
 
+// Full provides a mock function with given fields:
+func (_m *RunQueue) Full() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Interrupt provides a mock function with given fields: _a0
+func (_m *RunQueue) Interrupt(_a0 *models.ID) bool {
+	ret := _m.Called(_a0)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*models.ID) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Run provides a mock function with given fields: _a0
 func (_m *RunQueue) Run(_a0 *models.JobRun) {
 	_m.Called(_a0)
@@ -31,6 +31,27 @@ func (_m *Service) RemoveJob(_a0 *models.ID) {
 	_m.Called(_a0)
 }
 
+// ReplayLogs provides a mock function with given fields: jobID, fromBlock, toBlock
+func (_m *Service) ReplayLogs(jobID *models.ID, fromBlock uint64, toBlock uint64) (int, error) {
+	ret := _m.Called(jobID, fromBlock, toBlock)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(*models.ID, uint64, uint64) int); ok {
+		r0 = rf(jobID, fromBlock, toBlock)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*models.ID, uint64, uint64) error); ok {
+		r1 = rf(jobID, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Service) Start() error {
 	ret := _m.Called()
@@ -30,6 +30,64 @@ type TxManager struct {
 	mock.Mock
 }
 
+// ActivateAccount provides a mock function with given fields: account
+func (_m *TxManager) ActivateAccount(account accounts.Account) error {
+	ret := _m.Called(account)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(accounts.Account) error); ok {
+		r0 = rf(account)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BroadcastingPaused provides a mock function with given fields:
+func (_m *TxManager) BroadcastingPaused() (string, bool) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// BumpGas provides a mock function with given fields: hash
+func (_m *TxManager) BumpGas(hash common.Hash) (*models.TxAttempt, error) {
+	ret := _m.Called(hash)
+
+	var r0 *models.TxAttempt
+	if rf, ok := ret.Get(0).(func(common.Hash) *models.TxAttempt); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TxAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BumpGasUntilSafe provides a mock function with given fields: hash
 func (_m *TxManager) BumpGasUntilSafe(hash common.Hash) (*eth.TxReceipt, store.AttemptState, error) {
 	ret := _m.Called(hash)
@@ -77,6 +135,29 @@ func (_m *TxManager) Call(result interface{}, method string, args ...interface{}
 	return r0
 }
 
+// CancelTx provides a mock function with given fields: hash
+func (_m *TxManager) CancelTx(hash common.Hash) (*models.TxAttempt, error) {
+	ret := _m.Called(hash)
+
+	var r0 *models.TxAttempt
+	if rf, ok := ret.Get(0).(func(common.Hash) *models.TxAttempt); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TxAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CheckAttempt provides a mock function with given fields: txAttempt, blockHeight
 func (_m *TxManager) CheckAttempt(txAttempt *models.TxAttempt, blockHeight uint64) (*eth.TxReceipt, store.AttemptState, error) {
 	ret := _m.Called(txAttempt, blockHeight)
@@ -179,6 +260,29 @@ func (_m *TxManager) CreateTx(to common.Address, data []byte) (*models.Tx, error
 	return r0, r1
 }
 
+// CreateTxFrom provides a mock function with given fields: from, to, data
+func (_m *TxManager) CreateTxFrom(from common.Address, to common.Address, data []byte) (*models.Tx, error) {
+	ret := _m.Called(from, to, data)
+
+	var r0 *models.Tx
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, []byte) *models.Tx); ok {
+		r0 = rf(from, to, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Tx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, []byte) error); ok {
+		r1 = rf(from, to, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTxWithEth provides a mock function with given fields: from, to, value
 func (_m *TxManager) CreateTxWithEth(from common.Address, to common.Address, value *assets.Eth) (*models.Tx, error) {
 	ret := _m.Called(from, to, value)
@@ -473,11 +577,65 @@ func (_m *TxManager) OnNewHead(_a0 *models.Head) {
 	_m.Called(_a0)
 }
 
+// PauseBroadcasting provides a mock function with given fields: reason
+func (_m *TxManager) PauseBroadcasting(reason string) {
+	_m.Called(reason)
+}
+
+// QuorumClient provides a mock function with given fields:
+func (_m *TxManager) QuorumClient() eth.Client {
+	ret := _m.Called()
+
+	var r0 eth.Client
+	if rf, ok := ret.Get(0).(func() eth.Client); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(eth.Client)
+		}
+	}
+
+	return r0
+}
+
 // Register provides a mock function with given fields: _a0
 func (_m *TxManager) Register(_a0 []accounts.Account) {
 	_m.Called(_a0)
 }
 
+// ResendTxs provides a mock function with given fields: from, beginningNonce, endingNonce
+func (_m *TxManager) ResendTxs(from *common.Address, beginningNonce uint64, endingNonce uint64) ([]models.Tx, error) {
+	ret := _m.Called(from, beginningNonce, endingNonce)
+
+	var r0 []models.Tx
+	if rf, ok := ret.Get(0).(func(*common.Address, uint64, uint64) []models.Tx); ok {
+		r0 = rf(from, beginningNonce, endingNonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Tx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*common.Address, uint64, uint64) error); ok {
+		r1 = rf(from, beginningNonce, endingNonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResumeBroadcasting provides a mock function with given fields:
+func (_m *TxManager) ResumeBroadcasting() {
+	_m.Called()
+}
+
+// RetireAccount provides a mock function with given fields: address
+func (_m *TxManager) RetireAccount(address common.Address) {
+	_m.Called(address)
+}
+
 // SendRawTx provides a mock function with given fields: bytes
 func (_m *TxManager) SendRawTx(bytes []byte) (common.Hash, error) {
 	ret := _m.Called(bytes)
@@ -524,6 +682,20 @@ func (_m *TxManager) SignedRawTxWithBumpedGas(originalTx models.Tx, gasLimit uin
 	return r0, r1
 }
 
+// SimulateTx provides a mock function with given fields: to, data, gasLimit
+func (_m *TxManager) SimulateTx(to common.Address, data []byte, gasLimit uint64) error {
+	ret := _m.Called(to, data, gasLimit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, []byte, uint64) error); ok {
+		r0 = rf(to, data, gasLimit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Subscribe provides a mock function with given fields: _a0, _a1, _a2
 func (_m *TxManager) Subscribe(_a0 context.Context, _a1 interface{}, _a2 ...interface{}) (eth.Subscription, error) {
 	var _ca []interface{}
@@ -0,0 +1,185 @@
+// Package testutils provides a simulated ethereum chain for integration tests
+// of the node's services against real contracts.
+package testutils
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethEth "github.com/ethereum/go-ethereum/eth"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+)
+
+// OneEth is the balance each account starts a SimulatedChain with.
+var OneEth = big.NewInt(1000000000000000000)
+
+// SimulatedChain is an in-memory ethereum blockchain, in which blocks are
+// only mined when asked for.
+type SimulatedChain struct {
+	Backend *backends.SimulatedBackend
+
+	t        *testing.T
+	mu       sync.Mutex
+	stopOnce sync.Once
+	stop     chan struct{} // Closed to stop mining in the background
+	done     chan struct{}
+}
+
+// NewIdentity returns a go-ethereum abstraction of an ethereum account for
+// interacting with contract golang wrappers
+func NewIdentity(t *testing.T) *bind.TransactOpts {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "failed to generate ethereum identity")
+	return bind.NewKeyedTransactor(key)
+}
+
+// NewSimulatedChain returns a SimulatedChain in which each of the accounts
+// starts with OneEth.
+func NewSimulatedChain(t *testing.T, accounts ...*bind.TransactOpts) *SimulatedChain {
+	genesisData := core.GenesisAlloc{}
+	for _, account := range accounts {
+		genesisData[account.From] = core.GenesisAccount{Balance: OneEth}
+	}
+	gasLimit := gethEth.DefaultConfig.Miner.GasCeil
+	return &SimulatedChain{
+		Backend: backends.NewSimulatedBackend(genesisData, gasLimit),
+		t:       t,
+	}
+}
+
+// Commit mines a block with the transactions sent since the last one.
+func (c *SimulatedChain) Commit() {
+	c.Backend.Commit()
+}
+
+// MineEvery mines a block each interval in the background, like a live chain,
+// until Stop is called.
+func (c *SimulatedChain) MineEvery(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Nil(c.t, c.stop, "already mining blocks in the background")
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.Commit()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops mining blocks in the background, and closes the chain.
+func (c *SimulatedChain) Stop() {
+	c.stopOnce.Do(func() {
+		c.mu.Lock()
+		stop, done := c.stop, c.done
+		c.mu.Unlock()
+		if stop != nil {
+			close(stop)
+			<-done
+		}
+		require.NoError(c.t, c.Backend.Close())
+	})
+}
+
+// BlockNumber returns the number of the latest block mined.
+func (c *SimulatedChain) BlockNumber() uint64 {
+	header, err := c.Backend.HeaderByNumber(context.Background(), nil)
+	require.NoError(c.t, err, "failed to get latest block header")
+	return header.Number.Uint64()
+}
+
+// SubscribeLogs delivers to callback, in the order they are mined, the logs
+// emitted by the given contracts, as the node would receive them. Logs of the
+// block mined just before subscribing may be delivered too. The returned
+// function ends the subscription.
+func (c *SimulatedChain) SubscribeLogs(callback func(eth.Log),
+	addresses ...common.Address) (unsubscribe func()) {
+	logs := make(chan gethTypes.Log)
+	query := ethereum.FilterQuery{Addresses: addresses}
+	sub, err := c.Backend.SubscribeFilterLogs(context.Background(), query, logs)
+	require.NoError(c.t, err, "failed to subscribe to logs")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case log := <-logs:
+				callback(ToCLEthLog(log))
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return func() {
+		sub.Unsubscribe()
+		<-done
+	}
+}
+
+// FilterLogs returns the logs already emitted by the given contracts, from
+// fromBlock on.
+func (c *SimulatedChain) FilterLogs(fromBlock uint64,
+	addresses ...common.Address) []eth.Log {
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		Addresses: addresses,
+	}
+	gethLogs, err := c.Backend.FilterLogs(context.Background(), query)
+	require.NoError(c.t, err, "failed to filter logs")
+	logs := make([]eth.Log, len(gethLogs))
+	for i, log := range gethLogs {
+		logs[i] = ToCLEthLog(log)
+	}
+	return logs
+}
+
+// EstimateGas returns the estimated gas cost of running the given method on
+// the contract at address to, on the simulated chain, with the given
+// arguments.
+func (c *SimulatedChain) EstimateGas(from, to common.Address, abi *abi.ABI,
+	method string, args ...interface{}) uint64 {
+	rawData, err := abi.Pack(method, args...)
+	require.NoError(c.t, err, "failed to construct raw %s transaction with args %s",
+		method, args)
+	callMsg := ethereum.CallMsg{From: from, To: &to, Data: rawData}
+	estimate, err := c.Backend.EstimateGas(context.Background(), callMsg)
+	require.NoError(c.t, err, "failed to estimate gas from %s call with args %s",
+		method, args)
+	return estimate
+}
+
+// ToCLEthLog returns the node's representation of log.
+func ToCLEthLog(log gethTypes.Log) eth.Log {
+	return eth.Log{
+		Address:     log.Address,
+		Topics:      log.Topics,
+		Data:        eth.UntrustedBytes(log.Data),
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		TxIndex:     log.TxIndex,
+		BlockHash:   log.BlockHash,
+		Index:       log.Index,
+		Removed:     log.Removed,
+	}
+}
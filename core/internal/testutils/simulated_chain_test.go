@@ -0,0 +1,51 @@
+package testutils_test
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/testutils"
+)
+
+func TestSimulatedChain_MinesAndDeliversLogs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	universe := testutils.DeployVRFUniverse(t)
+	defer universe.Stop()
+
+	deployed := universe.FilterLogs(0, universe.LinkContractAddress)
+	require.Len(t, deployed, 1, "expected the LINK transfer to the consumer contract")
+
+	var mu sync.Mutex
+	var delivered []eth.Log
+	unsubscribe := universe.SubscribeLogs(func(log eth.Log) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, log)
+	}, universe.LinkContractAddress)
+	defer unsubscribe()
+
+	startBlock := universe.BlockNumber()
+	universe.MineEvery(10 * time.Millisecond)
+	tx, err := universe.LinkContract.Transfer(universe.Sergey, universe.Carol.From, big.NewInt(1))
+	require.NoError(t, err)
+
+	g.Eventually(func() *eth.Log {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, log := range delivered {
+			if log.TxHash == tx.Hash() {
+				return &log
+			}
+		}
+		return nil
+	}).Should(gomega.WithTransform(func(log *eth.Log) bool {
+		return log != nil && log.Address == universe.LinkContractAddress &&
+			log.BlockNumber > startBlock
+	}, gomega.BeTrue()))
+}
@@ -0,0 +1,87 @@
+package testutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/services/vrf/generated/link_token_interface"
+	"github.com/smartcontractkit/chainlink/core/services/vrf/generated/solidity_request_id"
+	"github.com/smartcontractkit/chainlink/core/services/vrf/generated/solidity_vrf_consumer_interface"
+	"github.com/smartcontractkit/chainlink/core/services/vrf/generated/solidity_vrf_coordinator_interface"
+)
+
+// VRFUniverse represents the universe in which a randomness request occurs and
+// is fulfilled.
+type VRFUniverse struct {
+	*SimulatedChain
+	// Golang wrappers of solidity contracts
+	RootContract            *solidity_vrf_coordinator_interface.VRFCoordinator
+	LinkContract            *link_token_interface.LinkToken
+	ConsumerContract        *solidity_vrf_consumer_interface.VRFConsumer
+	RequestIDBase           *solidity_request_id.VRFRequestIDBaseTestHelper
+	RootContractAddress     common.Address
+	LinkContractAddress     common.Address
+	ConsumerContractAddress common.Address
+	CoordinatorABI          *abi.ABI
+	ConsumerABI             *abi.ABI
+	// Cast of participants
+	Sergey *bind.TransactOpts // Owns all the LINK initially
+	Neil   *bind.TransactOpts // Node operator running VRF service
+	Carol  *bind.TransactOpts // Author of consuming contract which requests randomness
+}
+
+// DeployVRFUniverse sets up all identities and contracts associated with
+// testing the solidity VRF contracts involved in randomness request workflow.
+// Carol's consumer contract is given OneEth worth of LINK to pay for its
+// requests.
+func DeployVRFUniverse(t *testing.T) *VRFUniverse {
+	var (
+		sergey = NewIdentity(t)
+		neil   = NewIdentity(t)
+		carol  = NewIdentity(t)
+	)
+	chain := NewSimulatedChain(t, sergey, neil, carol)
+	consumerABI, err := abi.JSON(strings.NewReader(
+		solidity_vrf_consumer_interface.VRFConsumerABI))
+	require.NoError(t, err)
+	coordinatorABI, err := abi.JSON(strings.NewReader(
+		solidity_vrf_coordinator_interface.VRFCoordinatorABI))
+	require.NoError(t, err)
+	linkAddress, _, linkContract, err := link_token_interface.DeployLinkToken(
+		sergey, chain.Backend)
+	require.NoError(t, err, "failed to deploy link contract to simulated ethereum blockchain")
+	coordinatorAddress, _, coordinatorContract, err :=
+		solidity_vrf_coordinator_interface.DeployVRFCoordinator(
+			neil, chain.Backend, linkAddress)
+	require.NoError(t, err, "failed to deploy VRFCoordinator contract to simulated ethereum blockchain")
+	consumerContractAddress, _, consumerContract, err :=
+		solidity_vrf_consumer_interface.DeployVRFConsumer(
+			carol, chain.Backend, coordinatorAddress, linkAddress)
+	require.NoError(t, err, "failed to deploy VRFConsumer contract to simulated ethereum blockchain")
+	_, _, requestIDBase, err :=
+		solidity_request_id.DeployVRFRequestIDBaseTestHelper(neil, chain.Backend)
+	require.NoError(t, err, "failed to deploy VRFRequestIDBaseTestHelper contract to simulated ethereum blockchain")
+	_, err = linkContract.Transfer(sergey, consumerContractAddress, OneEth) // Actually, LINK
+	require.NoError(t, err, "failed to send LINK to VRFConsumer contract on simulated ethereum blockchain")
+	chain.Commit()
+	return &VRFUniverse{
+		SimulatedChain:          chain,
+		RootContract:            coordinatorContract,
+		RootContractAddress:     coordinatorAddress,
+		LinkContract:            linkContract,
+		LinkContractAddress:     linkAddress,
+		ConsumerContract:        consumerContract,
+		RequestIDBase:           requestIDBase,
+		ConsumerContractAddress: consumerContractAddress,
+		CoordinatorABI:          &coordinatorABI,
+		ConsumerABI:             &consumerABI,
+		Sergey:                  sergey,
+		Neil:                    neil,
+		Carol:                   carol,
+	}
+}
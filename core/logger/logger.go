@@ -15,6 +15,10 @@ import (
 
 var logger *Logger
 
+// productionLevel is the level of the loggers created by
+// CreateProductionLogger, which can be changed while they are in use.
+var productionLevel = zap.NewAtomicLevel()
+
 func init() {
 	err := zap.RegisterSink("pretty", prettyConsoleSink(os.Stderr))
 	if err != nil {
@@ -60,10 +64,12 @@ func SetLogger(zl *zap.Logger) {
 		defer logger.Sync()
 	}
 	logger = &Logger{zl.Sugar()}
+	resetModuleLoggers()
 }
 
 // CreateProductionLogger returns a log config for the passed directory
-// with the given LogLevel and customizes stdout for pretty printing.
+// with the given LogLevel and customizes stdout for pretty printing. The
+// modules of the logger can log at levels of their own.
 func CreateProductionLogger(
 	dir string, jsonConsole bool, lvl zapcore.Level, toDisk bool) *zap.Logger {
 	config := zap.NewProductionConfig()
@@ -75,15 +81,24 @@ func CreateProductionLogger(
 		config.OutputPaths = append(config.OutputPaths, destination)
 		config.ErrorOutputPaths = append(config.ErrorOutputPaths, destination)
 	}
-	config.Level.SetLevel(lvl)
+	productionLevel.SetLevel(lvl)
+	config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
 
-	zl, err := config.Build(zap.AddCallerSkip(1))
+	zl, err := config.Build(zap.AddCallerSkip(1), zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return moduleCore{Core: core, fallback: productionLevel}
+	}))
 	if err != nil {
 		log.Fatal(err)
 	}
 	return zl
 }
 
+// SetLevel changes the level of the loggers created by CreateProductionLogger
+// while they are in use.
+func SetLevel(lvl zapcore.Level) {
+	productionLevel.SetLevel(lvl)
+}
+
 // Infow logs an info message and any additional given information.
 func Infow(msg string, keysAndValues ...interface{}) {
 	logger.Infow(msg, keysAndValues...)
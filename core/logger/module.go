@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Module is the logger of a module of the node, whose level can be set apart
+// from the level of the rest of the node with SetModuleLevels. Its log lines
+// carry the name of the module in the module field.
+type Module string
+
+const (
+	// ORM logs the database queries and the errors of the ORM.
+	ORM Module = "orm"
+	// TxManager logs the transactions sent to and confirmed on the chain.
+	TxManager Module = "txmanager"
+	// FluxMonitor logs the polling and submissions of the flux monitor.
+	FluxMonitor Module = "fluxmonitor"
+	// VRF logs the fulfillment of VRF requests.
+	VRF Module = "vrf"
+	// Keeper logs the checks and performs of upkeep contracts.
+	Keeper Module = "keeper"
+	// BlockCondition logs the conditions evaluated on new heads.
+	BlockCondition Module = "blockcondition"
+	// Web logs the requests to the web server and API.
+	Web Module = "web"
+)
+
+// Modules are the modules whose level can be set apart.
+var Modules = []Module{ORM, TxManager, FluxMonitor, VRF, Keeper, BlockCondition, Web}
+
+var modules = struct {
+	sync.RWMutex
+	levels  map[Module]zapcore.Level
+	loggers map[Module]*Logger
+}{
+	levels:  make(map[Module]zapcore.Level),
+	loggers: make(map[Module]*Logger),
+}
+
+// ParseModule returns the module of the given name.
+func ParseModule(name string) (Module, error) {
+	for _, m := range Modules {
+		if string(m) == name {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown log module '%s'", name)
+}
+
+// ModuleLevels are the levels of the modules which log at a level of their
+// own, written as comma separated module=level pairs, such as
+// "orm=debug,web=warn".
+type ModuleLevels map[Module]zapcore.Level
+
+// String returns the levels as comma separated module=level pairs, in the
+// order of the modules.
+func (l ModuleLevels) String() string {
+	var pairs []string
+	for _, m := range Modules {
+		if lvl, ok := l[m]; ok {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", m, lvl))
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (l ModuleLevels) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (l *ModuleLevels) UnmarshalText(text []byte) error {
+	levels := make(ModuleLevels)
+	for _, pair := range strings.Split(string(text), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("expected module=level, got '%s'", pair)
+		}
+		m, err := ParseModule(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return err
+		}
+		var lvl zapcore.Level
+		if err := lvl.Set(strings.TrimSpace(parts[1])); err != nil {
+			return errors.Wrapf(err, "level of module '%s'", m)
+		}
+		levels[m] = lvl
+	}
+	*l = levels
+	return nil
+}
+
+// SetModuleLevels makes the modules with a level log at that level, whatever
+// the level of the rest of the node, and the others log at the level of the
+// rest of the node.
+func SetModuleLevels(levels ModuleLevels) {
+	modules.Lock()
+	defer modules.Unlock()
+	modules.levels = make(map[Module]zapcore.Level, len(levels))
+	for m, lvl := range levels {
+		modules.levels[m] = lvl
+	}
+}
+
+func moduleLevel(m Module) (zapcore.Level, bool) {
+	modules.RLock()
+	defer modules.RUnlock()
+	lvl, ok := modules.levels[m]
+	return lvl, ok
+}
+
+// resetModuleLoggers forgets the loggers of the modules, so that they are
+// derived again from the logger set with SetLogger.
+func resetModuleLoggers() {
+	modules.Lock()
+	defer modules.Unlock()
+	modules.loggers = make(map[Module]*Logger)
+}
+
+func (m Module) logger() *Logger {
+	modules.RLock()
+	l, ok := modules.loggers[m]
+	modules.RUnlock()
+	if ok {
+		return l
+	}
+
+	modules.Lock()
+	defer modules.Unlock()
+	zl := logger.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		fallback := zapcore.LevelEnabler(core)
+		if mc, ok := core.(moduleCore); ok {
+			core, fallback = mc.Core, mc.fallback
+		}
+		return moduleCore{Core: core, module: m, fallback: fallback}
+	})).With(zap.String("module", string(m)))
+	l = &Logger{zl.Sugar()}
+	modules.loggers[m] = l
+	return l
+}
+
+// moduleCore logs the entries of a module which are enabled by the level of
+// the module, if it has been set apart, or by its fallback otherwise.
+type moduleCore struct {
+	zapcore.Core
+	module   Module
+	fallback zapcore.LevelEnabler
+}
+
+func (c moduleCore) Enabled(lvl zapcore.Level) bool {
+	if moduleLvl, ok := moduleLevel(c.module); ok {
+		return moduleLvl.Enabled(lvl)
+	}
+	return c.fallback.Enabled(lvl)
+}
+
+func (c moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	return moduleCore{Core: c.Core.With(fields), module: c.module, fallback: c.fallback}
+}
+
+func (c moduleCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// Debugw logs a debug message and any additional given information.
+func (m Module) Debugw(msg string, keysAndValues ...interface{}) {
+	m.logger().Debugw(msg, keysAndValues...)
+}
+
+// Infow logs an info message and any additional given information.
+func (m Module) Infow(msg string, keysAndValues ...interface{}) {
+	m.logger().Infow(msg, keysAndValues...)
+}
+
+// Warnw logs a warning message and any additional given information.
+func (m Module) Warnw(msg string, keysAndValues ...interface{}) {
+	m.logger().Warnw(msg, keysAndValues...)
+}
+
+// Errorw logs an error message and any additional given information.
+func (m Module) Errorw(msg string, keysAndValues ...interface{}) {
+	m.logger().Errorw(msg, keysAndValues...)
+}
+
+// Debugf formats and then logs the message.
+func (m Module) Debugf(format string, values ...interface{}) {
+	m.logger().Debug(fmt.Sprintf(format, values...))
+}
+
+// Infof formats and then logs the message.
+func (m Module) Infof(format string, values ...interface{}) {
+	m.logger().Info(fmt.Sprintf(format, values...))
+}
+
+// Warnf formats and then logs the message as Warn.
+func (m Module) Warnf(format string, values ...interface{}) {
+	m.logger().Warn(fmt.Sprintf(format, values...))
+}
+
+// Errorf formats and then logs the message as Error.
+func (m Module) Errorf(format string, values ...interface{}) {
+	m.logger().Error(fmt.Sprintf(format, values...))
+}
+
+// Debug logs a debug message.
+func (m Module) Debug(args ...interface{}) {
+	m.logger().Debug(args...)
+}
+
+// Info logs an info message.
+func (m Module) Info(args ...interface{}) {
+	m.logger().Info(args...)
+}
+
+// Warn logs a message at the warn level.
+func (m Module) Warn(args ...interface{}) {
+	m.logger().Warn(args...)
+}
+
+// Error logs an error message.
+func (m Module) Error(args ...interface{}) {
+	m.logger().Error(args...)
+}
+
+// Panic logs a panic message then panics.
+func (m Module) Panic(args ...interface{}) {
+	m.logger().Panic(args...)
+}
+
+// ErrorIf logs the error if present.
+func (m Module) ErrorIf(err error, optionalMsg ...string) {
+	if err != nil {
+		if len(optionalMsg) > 0 {
+			m.logger().Error(errors.Wrap(err, optionalMsg[0]))
+		} else {
+			m.logger().Error(err)
+		}
+	}
+}
+
+// Sugared returns the zap logger of the module.
+func (m Module) Sugared() *zap.SugaredLogger {
+	return m.logger().SugaredLogger
+}
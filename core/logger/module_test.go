@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestModuleLevels_UnmarshalText(t *testing.T) {
+	var levels ModuleLevels
+	require.NoError(t, levels.UnmarshalText([]byte("web=warn, orm=debug")))
+	assert.Equal(t, ModuleLevels{ORM: zapcore.DebugLevel, Web: zapcore.WarnLevel}, levels)
+	assert.Equal(t, "orm=debug,web=warn", levels.String())
+
+	require.NoError(t, levels.UnmarshalText([]byte("")))
+	assert.Empty(t, levels)
+
+	assert.Error(t, levels.UnmarshalText([]byte("orm")))
+	assert.Error(t, levels.UnmarshalText([]byte("nosuchmodule=info")))
+	assert.Error(t, levels.UnmarshalText([]byte("orm=loud")))
+}
+
+func TestModule_LevelsSetApart(t *testing.T) {
+	previous := logger
+	defer func() {
+		SetLogger(previous.Desugar())
+		SetModuleLevels(nil)
+	}()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	productionLevel.SetLevel(zapcore.InfoLevel)
+	SetLogger(zap.New(moduleCore{Core: core, fallback: productionLevel}))
+	SetModuleLevels(ModuleLevels{ORM: zapcore.DebugLevel, Web: zapcore.ErrorLevel})
+
+	Debug("node debug")
+	ORM.Debug("orm debug")
+	Web.Warn("web warn")
+	Web.Error("web error")
+	TxManager.Debug("txmanager debug")
+	TxManager.Info("txmanager info")
+
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"orm debug", "web error", "txmanager info"}, messages)
+	assert.Equal(t, "orm", logs.All()[0].ContextMap()["module"])
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/smartcontractkit/chainlink/core/cmd"
 	"github.com/smartcontractkit/chainlink/core/logger"
+	_ "github.com/smartcontractkit/chainlink/core/store/awskms"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 )
 
@@ -37,5 +38,6 @@ func NewProductionClient() *cmd.Client {
 		PromptingSessionRequestBuilder: cmd.NewPromptingSessionRequestBuilder(prompter),
 		ChangePasswordPrompter:         cmd.NewChangePasswordPrompter(),
 		PasswordPrompter:               cmd.NewPasswordPrompter(),
+		JobSpecPrompter:                cmd.NewJobSpecPrompter(prompter),
 	}
 }
@@ -0,0 +1,308 @@
+package blockcondition
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// maxCatchUpBlocks is the most blocks before a head, received since the last
+// head evaluated, the conditions are evaluated on along with it. Those of a
+// longer gap, such as after the node was down, are not.
+const maxCatchUpBlocks = 100
+
+// Service is the interface encapsulating all functionality needed to run
+// jobs on the new heads the conditions of their blockcondition initiators
+// hold on.
+type Service interface {
+	store.HeadTrackable
+	AddJob(models.JobSpec) error
+	RemoveJob(*models.ID)
+	Start() error
+	Stop()
+}
+
+type condition struct {
+	jobID     models.ID
+	initiator models.Initiator
+	// held is whether the condition held on the last head it was evaluated
+	// on, for edge triggered conditions.
+	held     bool
+	lastHead int64
+}
+
+type concreteService struct {
+	store      *store.Store
+	runManager services.RunManager
+	disabled   bool
+
+	mu         sync.Mutex
+	conditions map[uint32]*condition
+	jobs       map[models.ID][]uint32
+	// lastHead is the number of the last head evaluated, only accessed by
+	// the goroutine evaluating heads.
+	lastHead int64
+
+	chHead   chan models.Head
+	chStop   chan struct{}
+	chDone   chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a service that evaluates the condition of each initiator of
+// type InitiatorBlockCondition of added jobs against each new head, creating
+// a run of the job when it holds. The block of the head is only fetched if a
+// condition needs more than its number.
+func New(store *store.Store, runManager services.RunManager) Service {
+	if store.Config.EthereumDisabled() {
+		return &concreteService{disabled: true}
+	}
+	return &concreteService{
+		store:      store,
+		runManager: runManager,
+		conditions: make(map[uint32]*condition),
+		jobs:       make(map[models.ID][]uint32),
+		chHead:     make(chan models.Head, 1),
+		chStop:     make(chan struct{}),
+		chDone:     make(chan struct{}),
+	}
+}
+
+// Start starts evaluating the conditions of existing jobs.
+func (s *concreteService) Start() error {
+	if s.disabled {
+		logger.BlockCondition.Info("Block conditions disabled: skipping start")
+		return nil
+	}
+
+	go s.run()
+
+	return s.store.Jobs(func(j *models.JobSpec) bool {
+		if j == nil {
+			logger.BlockCondition.Error("received nil job")
+			return true
+		}
+		if err := s.AddJob(*j); err != nil {
+			logger.BlockCondition.Errorf("error adding block condition job: %v", err)
+		}
+		return true
+	}, models.InitiatorBlockCondition)
+}
+
+// Stop stops evaluating conditions, waiting for any evaluation in progress.
+// Stopping it again does nothing.
+func (s *concreteService) Stop() {
+	if s.disabled {
+		logger.BlockCondition.Info("Block conditions disabled: cannot stop")
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.chStop)
+		<-s.chDone
+	})
+}
+
+// AddJob registers the conditions of any job initiators of type
+// InitiatorBlockCondition.
+func (s *concreteService) AddJob(job models.JobSpec) error {
+	if s.disabled {
+		return nil
+	}
+	if job.ID == nil {
+		err := errors.New("received job with nil ID")
+		logger.BlockCondition.Error(err)
+		return err
+	}
+	initrs := job.InitiatorsFor(models.InitiatorBlockCondition)
+	if len(initrs) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uint32, len(initrs))
+	for i, initr := range initrs {
+		logger.BlockCondition.Debugw("Adding block condition", "job_id", job.ID.String(), "initr", initr.ID, "expression", initr.BlockCondition.Expression)
+		s.conditions[initr.ID] = &condition{jobID: *job.ID, initiator: initr}
+		ids[i] = initr.ID
+	}
+	s.jobs[*job.ID] = ids
+	return nil
+}
+
+// RemoveJob stops evaluating the conditions of the job with the given ID.
+func (s *concreteService) RemoveJob(id *models.ID) {
+	if s.disabled {
+		return
+	}
+	if id == nil {
+		logger.BlockCondition.Warn("nil job ID passed to BlockCondition#RemoveJob")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, initrID := range s.jobs[*id] {
+		delete(s.conditions, initrID)
+	}
+	delete(s.jobs, *id)
+}
+
+// Connect does nothing, conditions being evaluated on the next head.
+func (s *concreteService) Connect(*models.Head) error {
+	return nil
+}
+
+// Disconnect does nothing.
+func (s *concreteService) Disconnect() {}
+
+// OnNewHead evaluates the conditions in the background. Of the heads received
+// while an evaluation is in progress, only the latest is kept, the blocks of
+// the others being caught up on with it.
+func (s *concreteService) OnNewHead(head *models.Head) {
+	if s.disabled || head == nil {
+		return
+	}
+	select {
+	case <-s.chHead:
+	default:
+	}
+	select {
+	case s.chHead <- *head:
+	default:
+	}
+}
+
+func (s *concreteService) run() {
+	defer close(s.chDone)
+	for {
+		select {
+		case head := <-s.chHead:
+			s.evaluate(head)
+		case <-s.chStop:
+			return
+		}
+	}
+}
+
+// evaluate evaluates the conditions on the head, and first on the blocks
+// since the last head evaluated, which were skipped.
+func (s *concreteService) evaluate(head models.Head) {
+	from := head.Number
+	if s.lastHead > 0 && head.Number > s.lastHead && head.Number-s.lastHead <= maxCatchUpBlocks {
+		from = s.lastHead + 1
+	}
+	for number := from; number < head.Number; number++ {
+		block, err := s.store.TxManager.GetBlockByNumber(hexutil.EncodeBig(big.NewInt(number)))
+		if err != nil {
+			logger.BlockCondition.Warnw("Unable to fetch skipped block, not evaluating block conditions on it", "block", number, "error", err)
+			continue
+		}
+		s.evaluateBlock(number, block.Hash, &block)
+	}
+	s.evaluateBlock(head.Number, head.Hash, nil)
+	if head.Number > s.lastHead {
+		s.lastHead = head.Number
+	}
+}
+
+// evaluateBlock creates a run of the job of each condition holding on the
+// block, unless it already held on the previous one if it is edge triggered.
+// A condition is evaluated once per block number, so that the heads of a
+// reorg do not run its job again. The block is fetched if it is not given
+// and a condition needs more than its number.
+func (s *concreteService) evaluateBlock(number int64, hash common.Hash, block *eth.Block) {
+	s.mu.Lock()
+	var conditions []*condition
+	needsBlock := false
+	for _, c := range s.conditions {
+		if c.lastHead >= number {
+			continue
+		}
+		conditions = append(conditions, c)
+		needsBlock = needsBlock || c.initiator.BlockCondition.Expression.NeedsBlock()
+	}
+	s.mu.Unlock()
+	if len(conditions) == 0 {
+		return
+	}
+
+	vars, err := s.blockVariables(number, needsBlock, block)
+	if err != nil {
+		logger.BlockCondition.Warnw("Unable to fetch block, not evaluating block conditions", "head", number, "error", err)
+		return
+	}
+
+	for _, c := range conditions {
+		holds, err := c.initiator.BlockCondition.Expression.Eval(vars)
+		if err != nil {
+			logger.BlockCondition.Warnw("Unable to evaluate block condition", "job_id", c.jobID.String(), "head", number, "error", err)
+		}
+		trigger := holds && !(c.initiator.BlockCondition.EdgeTriggered && c.held)
+
+		s.mu.Lock()
+		c.held = holds
+		c.lastHead = number
+		s.mu.Unlock()
+
+		if trigger {
+			s.createRun(c, number, hash, vars)
+		}
+	}
+}
+
+// blockVariables returns the values of the variables of block conditions for
+// the block number, fetching its block if need be and not given.
+func (s *concreteService) blockVariables(number int64, needsBlock bool, block *eth.Block) (map[string]*big.Int, error) {
+	vars := map[string]*big.Int{"number": big.NewInt(number)}
+	if !needsBlock {
+		return vars, nil
+	}
+	if block == nil {
+		fetched, err := s.store.TxManager.GetBlockByNumber(hexutil.EncodeBig(big.NewInt(number)))
+		if err != nil {
+			return nil, err
+		}
+		block = &fetched
+	}
+	vars["timestamp"] = new(big.Int).SetUint64(uint64(block.Timestamp))
+	vars["gasused"] = new(big.Int).SetUint64(uint64(block.GasUsed))
+	vars["gaslimit"] = new(big.Int).SetUint64(uint64(block.GasLimit))
+	if block.BaseFeePerGas != nil {
+		vars["basefee"] = block.BaseFeePerGas.ToInt()
+	}
+	return vars, nil
+}
+
+// createRun creates a run of the condition's job, with the block it held on
+// and the values of its variables as its request parameters.
+func (s *concreteService) createRun(c *condition, number int64, hash common.Hash, vars map[string]*big.Int) {
+	params := models.KV{"blockHash": hash.Hex()}
+	for name, v := range vars {
+		params[name] = v.String()
+	}
+	requestParams, err := models.JSON{}.MultiAdd(params)
+	if err != nil {
+		logger.BlockCondition.Errorw("Unable to build run request", "job_id", c.jobID.String(), "error", err)
+		return
+	}
+
+	logger.BlockCondition.Infow("Block condition holds, creating run", "job_id", c.jobID.String(), "head", number)
+	jobID := c.jobID
+	_, err = s.runManager.Create(&jobID, &c.initiator, big.NewInt(number), &models.RunRequest{
+		BlockHash:     &hash,
+		RequestParams: requestParams,
+	})
+	if err != nil {
+		logger.BlockCondition.Errorw("Unable to create run", "job_id", c.jobID.String(), "error", err)
+	}
+}
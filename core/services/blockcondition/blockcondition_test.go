@@ -0,0 +1,130 @@
+package blockcondition_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/blockcondition"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// runsCreated returns the numbers of the blocks runs of the job are created
+// at by the mocked run manager.
+func runsCreated(runManager *mocks.RunManager, job models.JobSpec) func() []string {
+	var mu sync.Mutex
+	var numbers []string
+	runManager.On("Create", job.ID, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Run(func(args mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			numbers = append(numbers, args.Get(3).(*models.RunRequest).RequestParams.Get("number").String())
+		})
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, numbers...)
+	}
+}
+
+func newBlockConditionJob(config models.BlockConditionConfig) models.JobSpec {
+	job := cltest.NewJobWithWebInitiator()
+	job.Initiators = []models.Initiator{{
+		JobSpecID:       job.ID,
+		Type:            models.InitiatorBlockCondition,
+		InitiatorParams: models.InitiatorParams{BlockCondition: config},
+	}}
+	return job
+}
+
+func TestBlockCondition_OnNewHead_CreatesRunsWhenConditionHolds(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	job := newBlockConditionJob(models.BlockConditionConfig{Expression: "number % 10 == 0"})
+	require.NoError(t, store.CreateJob(&job))
+
+	txm := new(mocks.TxManager)
+	txm.On("GetBlockByNumber", mock.Anything).Maybe().Return(eth.Block{Hash: cltest.NewHash()}, nil)
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+	created := runsCreated(runManager, job)
+
+	service := blockcondition.New(store, runManager)
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	for _, number := range []int64{9, 10, 10, 11} {
+		service.OnNewHead(cltest.Head(number))
+	}
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(created).Should(gomega.Equal([]string{"10"}))
+	g.Consistently(created, 500*time.Millisecond).Should(gomega.Equal([]string{"10"}), "condition evaluated once per head number")
+}
+
+func TestBlockCondition_OnNewHead_CatchesUpOnSkippedBlocks(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	job := newBlockConditionJob(models.BlockConditionConfig{Expression: "number % 10 == 0"})
+	require.NoError(t, store.CreateJob(&job))
+
+	txm := new(mocks.TxManager)
+	txm.On("GetBlockByNumber", "0xa").Return(eth.Block{Number: 10, Hash: cltest.NewHash()}, nil).Once()
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+	created := runsCreated(runManager, job)
+
+	service := blockcondition.New(store, runManager)
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	g := gomega.NewGomegaWithT(t)
+	service.OnNewHead(cltest.Head(9))
+	// The head of block 10 never arrives
+	service.OnNewHead(cltest.Head(11))
+	g.Eventually(created).Should(gomega.Equal([]string{"10"}))
+	txm.AssertExpectations(t)
+}
+
+func TestBlockCondition_OnNewHead_EdgeTriggered(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	job := newBlockConditionJob(models.BlockConditionConfig{Expression: "number >= 10", EdgeTriggered: true})
+	require.NoError(t, store.CreateJob(&job))
+
+	txm := new(mocks.TxManager)
+	txm.On("GetBlockByNumber", mock.Anything).Maybe().Return(eth.Block{Hash: cltest.NewHash()}, nil)
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+	created := runsCreated(runManager, job)
+
+	service := blockcondition.New(store, runManager)
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	for _, number := range []int64{9, 10, 11, 12} {
+		service.OnNewHead(cltest.Head(number))
+	}
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(created).Should(gomega.Equal([]string{"10"}))
+	g.Consistently(created, 500*time.Millisecond).Should(gomega.Equal([]string{"10"}), "runs only when the condition starts to hold")
+}
+
+func TestBlockCondition_Stop_Twice(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	service := blockcondition.New(store, new(mocks.RunManager))
+	require.NoError(t, service.Start())
+	service.Stop()
+	service.Stop()
+}
@@ -5,16 +5,25 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
+	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/gracefulpanic"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/services/blockcondition"
 	"github.com/smartcontractkit/chainlink/core/services/fluxmonitor"
+	"github.com/smartcontractkit/chainlink/core/services/kafka"
+	"github.com/smartcontractkit/chainlink/core/services/keeper"
+	"github.com/smartcontractkit/chainlink/core/services/mqtt"
+	"github.com/smartcontractkit/chainlink/core/services/offchainreporting"
+	"github.com/smartcontractkit/chainlink/core/services/stream"
 	"github.com/smartcontractkit/chainlink/core/services/synchronization"
 	"github.com/smartcontractkit/chainlink/core/store"
 	strpkg "github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/gobuffalo/packr"
 	"go.uber.org/multierr"
@@ -43,8 +52,16 @@ type Application interface {
 	GetStatsPusher() synchronization.StatsPusher
 	WakeSessionReaper()
 	AddJob(job models.JobSpec) error
+	AddJobs(jobs []models.JobSpec) error
+	AddJobWithDependencies(job models.JobSpec, deps models.JobDependencies) error
+	UpdateJob(job models.JobSpec) error
+	RollbackJob(ID *models.ID, version uint32) (models.JobSpec, error)
 	ArchiveJob(*models.ID) error
+	ArchiveJobs([]*models.ID) error
+	UnarchiveJobs([]*models.ID) error
 	AddServiceAgreement(*models.ServiceAgreement) error
+	TerminateServiceAgreement(id string) (models.ServiceAgreement, error)
+	ReplayLogs(jobID *models.ID, fromBlock, toBlock uint64) (int, error)
 	NewBox() packr.Box
 	services.RunManager
 }
@@ -57,15 +74,35 @@ type ChainlinkApplication struct {
 	HeadTracker *services.HeadTracker
 	StatsPusher synchronization.StatsPusher
 	services.RunManager
+	SyncEventExporter        *synchronization.SyncEventExporter
 	RunQueue                 services.RunQueue
 	JobSubscriber            services.JobSubscriber
 	GasUpdater               services.GasUpdater
 	FluxMonitor              fluxmonitor.Service
+	Keeper                   keeper.Service
+	BlockCondition           blockcondition.Service
+	Kafka                    kafka.Service
+	MQTT                     mqtt.Service
+	Stream                   stream.Service
+	OffchainReporting        offchainreporting.Service
 	Scheduler                *services.Scheduler
+	VRFRequestQueue          *services.VRFRequestQueue
 	Store                    *store.Store
 	SessionReaper            services.SleeperTask
+	StuckRunJanitor          *services.StuckRunJanitor
+	KeyFunder                *services.KeyFunder
+	RunStatsRollup           *services.RunStatsRollup
+	LeakWatchdog             *services.LeakWatchdog
+	RunResultArchiver        *services.RunResultArchiver
+	PartitionManager         *services.PartitionManager
+	EIHealthChecker          *services.ExternalInitiatorHealthChecker
+	EINotifier               *services.ExternalInitiatorNotifier
+	SAExpirer                *services.ServiceAgreementExpirer
+	SAEscrowTracker          *services.ServiceAgreementEscrowTracker
 	pendingConnectionResumer *pendingConnectionResumer
+	sleepingRunResumer       *sleepingRunResumer
 	shutdownOnce             sync.Once
+	stopServicesOnce         sync.Once
 	shutdownSignal           gracefulpanic.Signal
 }
 
@@ -77,16 +114,24 @@ func NewApplication(config *orm.Config, onConnectCallbacks ...func(Application))
 	shutdownSignal := gracefulpanic.NewSignal()
 	store := store.NewStore(config, shutdownSignal)
 	config.SetRuntimeStore(store.ORM)
+	logger.SetLevel(config.LogLevel().Level)
+	logger.SetModuleLevels(config.LogModuleLevels())
 
 	statsPusher := synchronization.NewStatsPusher(
 		store.ORM, config.ExplorerURL(), config.ExplorerAccessKey(), config.ExplorerSecret(),
 	)
-	runExecutor := services.NewRunExecutor(store, statsPusher)
-	runQueue := services.NewRunQueue(runExecutor)
+	runExecutor := &parkedRunResumer{RunExecutor: services.NewRunExecutor(store, statsPusher)}
+	runQueue := services.NewRunQueue(runExecutor, config, store.ORM)
 	runManager := services.NewRunManager(runQueue, config, store.ORM, statsPusher, store.TxManager, store.Clock)
+	runExecutor.runManager = runManager
+	sleepingRunResumer := newSleepingRunResumer(runManager, store.Clock)
+	runExecutor.sleepingRunResumer = sleepingRunResumer
 	jobSubscriber := services.NewJobSubscriber(store, runManager)
 	gasUpdater := services.NewGasUpdater(store)
 	fluxMonitor := fluxmonitor.New(store, runManager)
+	keeperService := keeper.New(store)
+	blockConditionService := blockcondition.New(store, runManager)
+	vrfRequestQueue := services.NewVRFRequestQueue(store, runManager)
 
 	pendingConnectionResumer := newPendingConnectionResumer(runManager)
 
@@ -94,22 +139,52 @@ func NewApplication(config *orm.Config, onConnectCallbacks ...func(Application))
 		JobSubscriber:            jobSubscriber,
 		GasUpdater:               gasUpdater,
 		FluxMonitor:              fluxMonitor,
+		Keeper:                   keeperService,
+		BlockCondition:           blockConditionService,
+		Kafka:                    kafka.New(store, runManager),
+		MQTT:                     mqtt.New(store, runManager),
+		Stream:                   stream.New(store, runManager),
+		OffchainReporting:        offchainreporting.New(store, runManager),
 		StatsPusher:              statsPusher,
+		SyncEventExporter:        synchronization.NewSyncEventExporter(store.ORM, config),
 		RunManager:               runManager,
 		RunQueue:                 runQueue,
 		Scheduler:                services.NewScheduler(store, runManager),
+		VRFRequestQueue:          vrfRequestQueue,
 		Store:                    store,
 		SessionReaper:            services.NewStoreReaper(store),
+		StuckRunJanitor:          services.NewStuckRunJanitor(store, runManager, services.NewLoggingStuckRunEscalator()),
+		KeyFunder:                services.NewKeyFunder(store),
+		RunStatsRollup:           services.NewRunStatsRollup(store),
+		LeakWatchdog:             services.NewLeakWatchdog(store),
+		RunResultArchiver:        services.NewRunResultArchiver(store),
+		PartitionManager:         services.NewPartitionManager(store),
+		EIHealthChecker:          services.NewExternalInitiatorHealthChecker(store),
+		EINotifier:               services.NewExternalInitiatorNotifier(store),
 		Exiter:                   os.Exit,
 		pendingConnectionResumer: pendingConnectionResumer,
+		sleepingRunResumer:       sleepingRunResumer,
 		shutdownSignal:           shutdownSignal,
 	}
 
+	app.SAEscrowTracker = services.NewServiceAgreementEscrowTracker(store)
+
 	headTrackables := []strpkg.HeadTrackable{
 		gasUpdater,
 		store.TxManager,
 		jobSubscriber,
 		pendingConnectionResumer,
+		vrfRequestQueue,
+		keeperService,
+		blockConditionService,
+		services.NewRunMetricsReporter(store),
+		services.NewLogConsumptionTrimmer(store),
+		app.SAEscrowTracker,
+	}
+	if store.TxManager.QuorumClient() != nil {
+		// Cross-checked first, so that no transaction is sent for a head
+		// the providers disagree on.
+		headTrackables = append([]strpkg.HeadTrackable{services.NewProviderQuorum(store)}, headTrackables...)
 	}
 	for _, onConnectCallback := range onConnectCallbacks {
 		headTrackable := &headTrackableCallback{func() {
@@ -118,6 +193,7 @@ func NewApplication(config *orm.Config, onConnectCallbacks ...func(Application))
 		headTrackables = append(headTrackables, headTrackable)
 	}
 	app.HeadTracker = services.NewHeadTracker(store, headTrackables)
+	app.SAExpirer = services.NewServiceAgreementExpirer(store, app)
 
 	return app
 }
@@ -131,21 +207,51 @@ func (app *ChainlinkApplication) Start() error {
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
-		select {
-		case <-sigs:
-		case <-app.shutdownSignal.Wait():
+		lockLost := app.shutdownSignal.Wait()
+		for {
+			select {
+			case <-sigs:
+				logger.ErrorIf(app.Stop())
+				app.Exiter(0)
+				return
+			case <-lockLost:
+				// Another node may hold the lock now, so only it may schedule
+				// runs and write to the database, while this one keeps
+				// answering reads until it is restarted.
+				logger.Error("Lost the lock on the database, stopping the services writing to it. The API stays up, read only, until the node is restarted")
+				logger.ErrorIf(app.stopServices())
+				lockLost = nil
+			}
 		}
-		logger.ErrorIf(app.Stop())
-		app.Exiter(0)
 	}()
 
 	// XXX: Change to exit on first encountered error.
 	return multierr.Combine(
+		adapters.LoadPlugins(app.Store.Config.AdapterPluginsDir(), app.Store.Config.AdapterPluginTimeout().Duration(), app.Store.ORM),
 		app.Store.Start(),
 		app.StatsPusher.Start(),
+		app.SyncEventExporter.Start(),
 		app.RunQueue.Start(),
 		app.RunManager.ResumeAllInProgress(),
+		app.RunManager.ResumeAllParked(),
+		app.sleepingRunResumer.Start(),
+		app.StuckRunJanitor.Start(),
+		app.KeyFunder.Start(),
+		app.RunStatsRollup.Start(),
+		app.LeakWatchdog.Start(),
+		app.RunResultArchiver.Start(),
+		app.PartitionManager.Start(),
+		app.EIHealthChecker.Start(),
+		app.EINotifier.Start(),
+		app.SAExpirer.Start(),
+		app.SAEscrowTracker.Start(),
 		app.FluxMonitor.Start(),
+		app.Keeper.Start(),
+		app.BlockCondition.Start(),
+		app.Kafka.Start(),
+		app.MQTT.Start(),
+		app.Stream.Start(),
+		app.OffchainReporting.Start(),
 
 		// HeadTracker deliberately started after
 		// RunManager.ResumeAllInProgress since it Connects JobSubscriber
@@ -165,14 +271,45 @@ func (app *ChainlinkApplication) Stop() error {
 		defer logger.Sync()
 		logger.Info("Gracefully exiting...")
 
+		merr = multierr.Combine(
+			app.stopServices(),
+			app.Store.Close(),
+		)
+	})
+	return merr
+}
+
+// stopServices halts the services which schedule runs or write to the
+// database, leaving the store open for the API to read from.
+func (app *ChainlinkApplication) stopServices() error {
+	var merr error
+	app.stopServicesOnce.Do(func() {
 		app.Scheduler.Stop()
 		merr = multierr.Append(merr, app.HeadTracker.Stop())
 		app.JobSubscriber.Stop()
+		merr = multierr.Append(merr, app.VRFRequestQueue.Stop())
 		app.FluxMonitor.Stop()
+		app.Keeper.Stop()
+		app.BlockCondition.Stop()
+		app.Kafka.Stop()
+		app.MQTT.Stop()
+		app.Stream.Stop()
+		app.OffchainReporting.Stop()
+		app.sleepingRunResumer.Stop()
+		app.StuckRunJanitor.Stop()
+		app.KeyFunder.Stop()
+		app.RunStatsRollup.Stop()
+		app.LeakWatchdog.Stop()
+		app.RunResultArchiver.Stop()
+		app.PartitionManager.Stop()
+		app.EIHealthChecker.Stop()
+		app.EINotifier.Stop()
+		app.SAExpirer.Stop()
+		app.SAEscrowTracker.Stop()
 		app.RunQueue.Stop()
 		app.StatsPusher.Close()
+		app.SyncEventExporter.Stop()
 		merr = multierr.Append(merr, app.SessionReaper.Stop())
-		merr = multierr.Append(merr, app.Store.Close())
 	})
 	return merr
 }
@@ -186,6 +323,13 @@ func (app *ChainlinkApplication) GetStatsPusher() synchronization.StatsPusher {
 	return app.StatsPusher
 }
 
+// ReplayLogs delivers the logs of the given blocks again to the services of
+// the job which consume logs, which process those the job has not consumed
+// yet, returning the number of logs delivered.
+func (app *ChainlinkApplication) ReplayLogs(jobID *models.ID, fromBlock, toBlock uint64) (int, error) {
+	return app.FluxMonitor.ReplayLogs(jobID, fromBlock, toBlock)
+}
+
 // WakeSessionReaper wakes up the reaper to do its reaping.
 func (app *ChainlinkApplication) WakeSessionReaper() {
 	app.SessionReaper.WakeUp()
@@ -200,21 +344,133 @@ func (app *ChainlinkApplication) AddJob(job models.JobSpec) error {
 		return err
 	}
 
+	app.startJob(job)
+	return nil
+}
+
+// AddJobs saves the jobs in a single transaction, so that either all of them
+// are added or none are, then starts them.
+func (app *ChainlinkApplication) AddJobs(jobs []models.JobSpec) error {
+	if err := app.Store.CreateJobs(jobs); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		app.startJob(job)
+	}
+	return nil
+}
+
+// AddJobWithDependencies saves the job along with the bridges, external
+// initiators and secrets it depends on, in a single transaction, then starts
+// it. The job is validated against the dependencies before the transaction
+// commits, returning the *models.JSONAPIErrors of ValidateJob if invalid.
+func (app *ChainlinkApplication) AddJobWithDependencies(job models.JobSpec, deps models.JobDependencies) error {
+	err := app.Store.CreateJobWithDependencies(&job, deps, func(txORM *orm.ORM) error {
+		txStore := *app.Store
+		txStore.ORM = txORM
+		return services.ValidateJob(job, &txStore)
+	})
+	if err != nil {
+		return err
+	}
+	app.startJob(job)
+	return nil
+}
+
+// startJob hands a job to the scheduler and the services which watch for its
+// initiators.
+func (app *ChainlinkApplication) startJob(job models.JobSpec) {
 	app.Scheduler.AddJob(job)
 
 	// XXX: Add mechanism to asynchronously communicate when a job spec has
 	// an ethereum interaction error.
 	// https://www.pivotaltracker.com/story/show/170349568
 	logger.ErrorIf(app.FluxMonitor.AddJob(job))
+	logger.ErrorIf(app.Keeper.AddJob(job))
+	logger.ErrorIf(app.BlockCondition.AddJob(job))
+	logger.ErrorIf(app.Kafka.AddJob(job))
+	logger.ErrorIf(app.MQTT.AddJob(job))
+	logger.ErrorIf(app.Stream.AddJob(job))
+	logger.ErrorIf(app.OffchainReporting.AddJob(job))
 	logger.ErrorIf(app.JobSubscriber.AddJob(job, nil))
-	return nil
+}
+
+// UpdateJob replaces the definition of an existing job, keeping the prior
+// definition as a version that can later be rolled back to.
+func (app *ChainlinkApplication) UpdateJob(job models.JobSpec) error {
+	return app.replaceJob(job.ID, func() error {
+		return app.Store.UpdateJob(&job)
+	})
+}
+
+// RollbackJob restores the definition a job had at a prior version.
+func (app *ChainlinkApplication) RollbackJob(ID *models.ID, version uint32) (models.JobSpec, error) {
+	var job models.JobSpec
+	err := app.replaceJob(ID, func() error {
+		var err error
+		job, err = app.Store.RollbackJob(ID, version)
+		return err
+	})
+	return job, err
+}
+
+// replaceJob stops the services watching a job while its definition is
+// replaced, then starts them again with whichever definition is current.
+func (app *ChainlinkApplication) replaceJob(ID *models.ID, replace func() error) error {
+	app.stopJob(ID)
+	app.Scheduler.RemoveJob(ID)
+
+	replaceErr := replace()
+	job, err := app.Store.FindJob(ID)
+	if err != nil {
+		return multierr.Combine(replaceErr, err)
+	}
+
+	app.startJob(job)
+	return replaceErr
 }
 
 // ArchiveJob silences the job from the system, preventing future job runs.
 func (app *ChainlinkApplication) ArchiveJob(ID *models.ID) error {
+	app.stopJob(ID)
+	return app.Store.ArchiveJob(ID)
+}
+
+// ArchiveJobs archives the jobs in a single transaction, so that either all
+// of them are archived or none are.
+func (app *ChainlinkApplication) ArchiveJobs(IDs []*models.ID) error {
+	for _, ID := range IDs {
+		app.stopJob(ID)
+	}
+	return app.Store.ArchiveJobs(IDs)
+}
+
+// UnarchiveJobs brings archived jobs back in a single transaction, so that
+// either all of them are brought back or none are, then starts them.
+func (app *ChainlinkApplication) UnarchiveJobs(IDs []*models.ID) error {
+	if err := app.Store.UnarchiveJobs(IDs); err != nil {
+		return err
+	}
+	for _, ID := range IDs {
+		job, err := app.Store.FindJob(ID)
+		if err != nil {
+			return err
+		}
+		app.startJob(job)
+	}
+	return nil
+}
+
+// stopJob stops the services which watch for the initiators of a job.
+func (app *ChainlinkApplication) stopJob(ID *models.ID) {
 	_ = app.JobSubscriber.RemoveJob(ID)
 	app.FluxMonitor.RemoveJob(ID)
-	return app.Store.ArchiveJob(ID)
+	app.Keeper.RemoveJob(ID)
+	app.BlockCondition.RemoveJob(ID)
+	app.Kafka.RemoveJob(ID)
+	app.MQTT.RemoveJob(ID)
+	app.Stream.RemoveJob(ID)
+	app.OffchainReporting.RemoveJob(ID)
 }
 
 // AddServiceAgreement adds a Service Agreement which includes a job that needs
@@ -231,10 +487,27 @@ func (app *ChainlinkApplication) AddServiceAgreement(sa *models.ServiceAgreement
 	// an ethereum interaction error.
 	// https://www.pivotaltracker.com/story/show/170349568
 	logger.ErrorIf(app.FluxMonitor.AddJob(sa.JobSpec))
+	logger.ErrorIf(app.Kafka.AddJob(sa.JobSpec))
+	logger.ErrorIf(app.MQTT.AddJob(sa.JobSpec))
+	logger.ErrorIf(app.Stream.AddJob(sa.JobSpec))
+	logger.ErrorIf(app.OffchainReporting.AddJob(sa.JobSpec))
 	logger.ErrorIf(app.JobSubscriber.AddJob(sa.JobSpec, nil))
 	return nil
 }
 
+// TerminateServiceAgreement archives the job of a Service Agreement and
+// records that it was terminated, returning the terminated agreement.
+func (app *ChainlinkApplication) TerminateServiceAgreement(id string) (models.ServiceAgreement, error) {
+	sa, err := app.Store.FindServiceAgreement(id)
+	if err != nil {
+		return sa, err
+	}
+	if err := services.TerminateServiceAgreement(app.Store, app, sa); err != nil {
+		return sa, err
+	}
+	return app.Store.FindServiceAgreement(id)
+}
+
 // NewBox returns the packr.Box instance that holds the static assets to
 // be delivered by the router.
 func (app *ChainlinkApplication) NewBox() packr.Box {
@@ -255,3 +528,88 @@ func (p *pendingConnectionResumer) Connect(head *models.Head) error {
 
 func (p *pendingConnectionResumer) Disconnect()            {}
 func (p *pendingConnectionResumer) OnNewHead(*models.Head) {}
+
+// parkedRunResumer gives the runs parked by the concurrency limits or by a
+// full run queue a chance to start whenever the execution of another run
+// yields its slot, and lets the sleepingRunResumer know when a run may have
+// gone to sleep.
+type parkedRunResumer struct {
+	services.RunExecutor
+	runManager         services.RunManager
+	sleepingRunResumer *sleepingRunResumer
+}
+
+func (p *parkedRunResumer) Execute(runID *models.ID) error {
+	err := p.RunExecutor.Execute(runID)
+	logger.ErrorIf(p.runManager.ResumeAllParked())
+	p.sleepingRunResumer.WakeUp()
+	return err
+}
+
+// sleepingRunPollInterval is the longest the sleepingRunResumer waits before
+// checking for sleeping runs again, in case it missed a run going to sleep.
+const sleepingRunPollInterval = time.Minute
+
+// sleepingRunResumer wakes up the runs paused by sleeping tasks when they are
+// due, so that a sleeping run does not hold up a goroutine of the RunQueue.
+type sleepingRunResumer struct {
+	runManager services.RunManager
+	clock      utils.AfterNower
+	wakeUp     chan struct{}
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func newSleepingRunResumer(runManager services.RunManager, clock utils.AfterNower) *sleepingRunResumer {
+	return &sleepingRunResumer{
+		runManager: runManager,
+		clock:      clock,
+		wakeUp:     make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start resumes the runs that fell due while the node was down, and keeps
+// resuming runs as they fall due until stopped.
+func (s *sleepingRunResumer) Start() error {
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop stops resuming runs, waiting for any being resumed.
+func (s *sleepingRunResumer) Stop() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+// WakeUp has the sleepingRunResumer check for sleeping runs again, without
+// blocking.
+func (s *sleepingRunResumer) WakeUp() {
+	select {
+	case s.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+func (s *sleepingRunResumer) run() {
+	defer s.wg.Done()
+	for {
+		next, err := s.runManager.ResumeAllSleeping()
+		logger.ErrorIf(err, "failed to resume sleeping runs")
+
+		wait := sleepingRunPollInterval
+		if !next.IsZero() {
+			if untilNext := next.Sub(s.clock.Now()); untilNext < wait {
+				wait = untilNext
+			}
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-s.wakeUp:
+		case <-s.clock.After(wait):
+		}
+	}
+}
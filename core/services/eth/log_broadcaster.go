@@ -14,6 +14,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 )
 
 //go:generate mockery -name LogBroadcaster -output ../../internal/mocks/ -case=underscore
@@ -29,6 +30,7 @@ type LogBroadcaster interface {
 	Start()
 	Register(address common.Address, listener LogListener) (connected bool)
 	Unregister(address common.Address, listener LogListener)
+	Replay(jobID *models.ID, fromBlock, toBlock uint64) (int, error)
 	Stop()
 }
 
@@ -50,6 +52,8 @@ type logBroadcaster struct {
 	listeners        map[common.Address]map[LogListener]struct{}
 	chAddListener    chan registration
 	chRemoveListener chan registration
+	chReplay         chan replayRequest
+	chReplayedLogs   chan replayedLog
 
 	utils.DependentAwaiter
 	chStop chan struct{}
@@ -65,6 +69,8 @@ func NewLogBroadcaster(ethClient eth.Client, orm *orm.ORM, backfillDepth uint64)
 		listeners:        make(map[common.Address]map[LogListener]struct{}),
 		chAddListener:    make(chan registration),
 		chRemoveListener: make(chan registration),
+		chReplay:         make(chan replayRequest),
+		chReplayedLogs:   make(chan replayedLog),
 		chStop:           make(chan struct{}),
 		chDone:           make(chan struct{}),
 		DependentAwaiter: utils.NewDependentAwaiter(),
@@ -79,6 +85,7 @@ type LogBroadcast interface {
 	UpdateLog(eth.RawLog)
 	WasAlreadyConsumed() (bool, error)
 	MarkConsumed() error
+	ConsumeOnce(process func()) (bool, error)
 }
 
 type logBroadcast struct {
@@ -99,9 +106,26 @@ func (lb *logBroadcast) WasAlreadyConsumed() (bool, error) {
 	return lb.orm.HasConsumedLog(lb.log, lb.consumerID)
 }
 
+// MarkConsumed records that the log was consumed, doing nothing if it was
+// recorded already.
 func (lb *logBroadcast) MarkConsumed() error {
 	lc := models.NewLogConsumption(lb.log, lb.consumerID)
-	return lb.orm.CreateLogConsumption(&lc)
+	created, err := lb.orm.CreateLogConsumption(&lc)
+	if err == nil && !created {
+		logger.Debugw("Log was already marked consumed", "block_hash", lb.log.GetBlockHash().Hex(), "log_index", lb.log.GetIndex(), "job_id", lb.consumerID)
+	}
+	return err
+}
+
+// ConsumeOnce processes the log unless it was consumed already, reporting
+// whether it did. Unlike checking WasAlreadyConsumed before processing the
+// log and marking it consumed after, the consumption is recorded in a
+// transaction committed once the log is processed, so that another delivery
+// of the log is not processed as well, and that the log is not lost should
+// the node stop while processing it.
+func (lb *logBroadcast) ConsumeOnce(process func()) (bool, error) {
+	lc := models.NewLogConsumption(lb.log, lb.consumerID)
+	return lb.orm.ConsumeLog(&lc, process)
 }
 
 type registration struct {
@@ -109,6 +133,22 @@ type registration struct {
 	listener LogListener
 }
 
+// replayRequest asks for the addresses of the contracts a job listens to.
+type replayRequest struct {
+	jobID       models.ID
+	chAddresses chan []common.Address
+}
+
+// replayedLog is a log delivered again to the listeners of a job only.
+type replayedLog struct {
+	jobID models.ID
+	log   eth.Log
+}
+
+// replayTimeout is how long a replay waits for the broadcaster to be
+// connected.
+const replayTimeout = 10 * time.Second
+
 // A ManagedSubscription acts as wrapper for the eth.Subscription. Specifically, the
 // ManagedSubscription closes the log channel as soon as the unsubscribe request is made
 type ManagedSubscription interface {
@@ -186,6 +226,46 @@ func (b *logBroadcaster) Unregister(address common.Address, listener LogListener
 	}
 }
 
+// ErrNotListening is returned when replaying the logs of a job which listens
+// to no logs.
+var ErrNotListening = errors.New("job listens to no logs")
+
+// Replay delivers the logs of the contracts the job listens to, from and to
+// the given blocks, again to the job's listeners alone. They skip the logs
+// the job has already consumed, so that only those whose consumption was
+// deleted are processed again. It returns the number of logs delivered.
+func (b *logBroadcaster) Replay(jobID *models.ID, fromBlock, toBlock uint64) (int, error) {
+	req := replayRequest{jobID: *jobID, chAddresses: make(chan []common.Address, 1)}
+	select {
+	case b.chReplay <- req:
+	case <-b.chStop:
+		return 0, errors.New("log broadcaster is stopped")
+	case <-time.After(replayTimeout):
+		return 0, errors.New("log broadcaster is not connected")
+	}
+	addresses := <-req.chAddresses
+	if len(addresses) == 0 {
+		return 0, errors.Wrapf(ErrNotListening, "job %s", jobID)
+	}
+
+	logs, err := b.ethClient.GetLogs(ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: addresses,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "fetching logs to replay")
+	}
+	for i, log := range logs {
+		select {
+		case b.chReplayedLogs <- replayedLog{jobID: *jobID, log: log}:
+		case <-b.chStop:
+			return i, errors.New("log broadcaster is stopped")
+		}
+	}
+	return len(logs), nil
+}
+
 // The subscription is closed in two cases:
 //   - intentionally, when the set of contracts we're listening to changes
 //   - on a connection error
@@ -319,6 +399,12 @@ func (b *logBroadcaster) process(subscription eth.Subscription, chRawLogs <-chan
 		case r := <-b.chRemoveListener:
 			needsResubscribe = b.onRemoveListener(r) || needsResubscribe
 
+		case r := <-b.chReplay:
+			r.chAddresses <- b.addressesFor(r.jobID)
+
+		case r := <-b.chReplayedLogs:
+			b.onReplayedLog(r)
+
 		case <-debounceResubscribe.C:
 			if needsResubscribe {
 				return true, nil
@@ -346,6 +432,34 @@ func (b *logBroadcaster) onRawLog(rawLog eth.Log) {
 	}
 }
 
+// addressesFor returns the addresses of the contracts the job listens to.
+func (b *logBroadcaster) addressesFor(jobID models.ID) []common.Address {
+	var addresses []common.Address
+	for address, listeners := range b.listeners {
+		for listener := range listeners {
+			if id := listener.JobID(); id != nil && *id == jobID {
+				addresses = append(addresses, address)
+				break
+			}
+		}
+	}
+	return addresses
+}
+
+func (b *logBroadcaster) onReplayedLog(r replayedLog) {
+	if r.log.Removed {
+		return
+	}
+	for listener := range b.listeners[r.log.Address] {
+		if id := listener.JobID(); id == nil || *id != r.jobID {
+			continue
+		}
+		rawLogCopy := r.log.Copy()
+		lb := logBroadcast{b.orm, &rawLogCopy, listener.JobID()}
+		listener.HandleLog(&lb, nil)
+	}
+}
+
 func (b *logBroadcaster) onAddListener(r registration) (needsResubscribe bool) {
 	_, knownAddress := b.listeners[r.address]
 	if !knownAddress {
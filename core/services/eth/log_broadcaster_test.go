@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -673,6 +674,62 @@ func TestLogBroadcaster_InjectsLogConsumptionRecordFunctions(t *testing.T) {
 	requireLogConsumptionCount(t, store, 2)
 }
 
+func TestLogBroadcaster_ConsumeOnce(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ethClient := new(mocks.Client)
+	sub := new(mocks.Subscription)
+
+	chchRawLogs := make(chan chan<- eth.Log, 1)
+	ethClient.On("SubscribeToLogs", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { chchRawLogs <- args.Get(1).(chan<- eth.Log) }).
+		Return(sub, nil).
+		Once()
+	ethClient.On("GetLatestBlock").Return(eth.Block{Number: hexutil.Uint64(0)}, nil)
+	ethClient.On("GetLogs", mock.Anything).Return([]eth.Log{}, nil).Once()
+	sub.On("Err").Return(nil)
+	sub.On("Unsubscribe").Return()
+
+	lb := ethsvc.NewLogBroadcaster(ethClient, store.ORM, 10)
+	lb.Start()
+
+	chProcessed := make(chan bool, 2)
+	job := createJob(t, store)
+	logListener := simpleLogListener{
+		func(lb ethsvc.LogBroadcast, err error) {
+			// Only the first consumption of the log processes it
+			for i := 0; i < 2; i++ {
+				processed, err := lb.ConsumeOnce(func() {
+					consumed, err := lb.WasAlreadyConsumed()
+					assert.NoError(t, err)
+					assert.False(t, consumed, "the consumption is committed once the log is processed")
+				})
+				assert.NoError(t, err)
+				chProcessed <- processed
+			}
+		},
+		job.ID,
+	}
+	addr := common.Address{1}
+	lb.Register(addr, &logListener)
+
+	chRawLogs := <-chchRawLogs
+	chRawLogs <- eth.Log{Address: addr, BlockHash: cltest.NewHash(), BlockNumber: 0, Index: 0}
+
+	var processed []bool
+	for i := 0; i < 2; i++ {
+		select {
+		case p := <-chProcessed:
+			processed = append(processed, p)
+		case <-time.After(5 * time.Second):
+			t.Fatal("log was not consumed")
+		}
+	}
+	require.Equal(t, []bool{true, false}, processed)
+	requireLogConsumptionCount(t, store, 1)
+}
+
 func TestLogBroadcaster_ProcessesLogsFromReorgs(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
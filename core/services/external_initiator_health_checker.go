@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"go.uber.org/multierr"
+)
+
+// ExternalInitiatorHealthChecker checks the health of the external initiators
+// with a URL by pinging them, and records whether they answered, so that
+// unhealthy ones can be spotted.
+type ExternalInitiatorHealthChecker struct {
+	store    *store.Store
+	client   *http.Client
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewExternalInitiatorHealthChecker returns a new ExternalInitiatorHealthChecker.
+func NewExternalInitiatorHealthChecker(store *store.Store) *ExternalInitiatorHealthChecker {
+	return &ExternalInitiatorHealthChecker{
+		store:  store,
+		client: &http.Client{Timeout: store.Config.DefaultHTTPTimeout().Duration()},
+		done:   make(chan struct{}),
+	}
+}
+
+// Start checks the external initiators every
+// EXTERNAL_INITIATOR_HEALTH_CHECK_INTERVAL until stopped, or does nothing if
+// the interval is zero. Changes to the interval while the node is running
+// apply from the next check.
+func (c *ExternalInitiatorHealthChecker) Start() error {
+	if c.store.Config.ExternalInitiatorHealthInterval().IsInstant() {
+		return nil
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-c.store.Clock.After(c.store.Config.ExternalInitiatorHealthInterval().Duration()):
+				logger.ErrorIf(c.CheckAll(), "failed to check the health of external initiators")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops checking the external initiators, waiting for any check in
+// progress. Stopping it again does nothing.
+func (c *ExternalInitiatorHealthChecker) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.done)
+		c.wg.Wait()
+	})
+}
+
+// CheckAll checks the health of every external initiator with a URL.
+func (c *ExternalInitiatorHealthChecker) CheckAll() error {
+	eis, err := c.store.ExternalInitiators()
+	if err != nil {
+		return err
+	}
+
+	var merr error
+	for _, ei := range eis {
+		if ei.URL == nil {
+			continue
+		}
+		checkErr := c.check(ei)
+		if checkErr != nil && ei.Healthy() {
+			logger.Warnw("External initiator is unhealthy", "name", ei.Name, "error", checkErr)
+		}
+		err := c.store.SaveExternalInitiatorHealthCheck(ei.Name, c.store.Clock.Now(), checkErr)
+		merr = multierr.Append(merr, err)
+	}
+	return merr
+}
+
+func (c *ExternalInitiatorHealthChecker) check(ei models.ExternalInitiator) error {
+	resp, err := c.client.Get(ei.HealthCheckURL().String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health check returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package services_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalInitiatorHealthChecker_CheckAll(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	healthy := int32(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	url := cltest.WebURL(t, server.URL+"/jobs")
+	withURL, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: "bitcoin", URL: &url})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateExternalInitiator(withURL))
+	withoutURL, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: "litecoin"})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateExternalInitiator(withoutURL))
+
+	checker := services.NewExternalInitiatorHealthChecker(store)
+	require.NoError(t, checker.CheckAll())
+	ei, err := store.FindExternalInitiatorByName("bitcoin")
+	require.NoError(t, err)
+	assert.NotNil(t, ei.LastHealthCheckAt)
+	assert.True(t, ei.Healthy())
+
+	atomic.StoreInt32(&healthy, 0)
+	require.NoError(t, checker.CheckAll())
+	ei, err = store.FindExternalInitiatorByName("bitcoin")
+	require.NoError(t, err)
+	assert.False(t, ei.Healthy())
+	assert.Contains(t, ei.HealthCheckError.String, "503")
+
+	ei, err = store.FindExternalInitiatorByName("litecoin")
+	require.NoError(t, err)
+	assert.Nil(t, ei.LastHealthCheckAt)
+}
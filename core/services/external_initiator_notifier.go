@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// externalInitiatorNotifierPollInterval is how often the
+// ExternalInitiatorNotifier looks for notifications due to be retried.
+const externalInitiatorNotifierPollInterval = 10 * time.Second
+
+// ExternalInitiatorNotifier retries the notifications which could not be
+// delivered to external initiators, until they are delivered or run out of
+// attempts and are dead-lettered.
+type ExternalInitiatorNotifier struct {
+	store    *store.Store
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewExternalInitiatorNotifier returns a new ExternalInitiatorNotifier.
+func NewExternalInitiatorNotifier(store *store.Store) *ExternalInitiatorNotifier {
+	return &ExternalInitiatorNotifier{
+		store: store,
+		done:  make(chan struct{}),
+	}
+}
+
+// Start retries the notifications as they fall due until stopped.
+func (n *ExternalInitiatorNotifier) Start() error {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for {
+			select {
+			case <-n.done:
+				return
+			case <-n.store.Clock.After(externalInitiatorNotifierPollInterval):
+				logger.ErrorIf(n.RetryDue(), "failed to retry external initiator notifications")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops retrying notifications, waiting for any being delivered.
+// Stopping it again does nothing.
+func (n *ExternalInitiatorNotifier) Stop() {
+	n.stopOnce.Do(func() {
+		close(n.done)
+		n.wg.Wait()
+	})
+}
+
+// RetryDue tries to deliver the notifications which are due to be retried.
+func (n *ExternalInitiatorNotifier) RetryDue() error {
+	notifications, err := n.store.DueExternalInitiatorNotifications(n.store.Clock.Now(), orm.BatchSize)
+	if err != nil {
+		return err
+	}
+	for i := range notifications {
+		if err := DeliverExternalInitiatorNotification(n.store, &notifications[i]); err != nil {
+			logger.Warnw("Failed to deliver notification to external initiator",
+				"name", notifications[i].ExternalInitiatorName, "attempts", notifications[i].Attempts, "error", err)
+		}
+	}
+	return nil
+}
+
+// DeliverExternalInitiatorNotification tries to deliver the notification to
+// its external initiator. Delivered notifications are deleted, and those
+// which fail to deliver are retried after a backoff which doubles with each
+// attempt, until they run out of attempts and are dead-lettered.
+func DeliverExternalInitiatorNotification(store *store.Store, notification *models.ExternalInitiatorNotification) error {
+	deliveryErr := deliverExternalInitiatorNotification(store, *notification)
+	if deliveryErr == nil {
+		return store.DeleteExternalInitiatorNotification(notification.ID)
+	}
+
+	failure := &models.ExternalInitiatorDeliveryFailure{
+		ExternalInitiatorName: notification.ExternalInitiatorName,
+		JobSpecID:             notification.JobSpecID,
+		Error:                 deliveryErr.Error(),
+	}
+	logger.ErrorIf(store.CreateExternalInitiatorDeliveryFailure(failure), "failed to record external initiator delivery failure")
+
+	now := store.Clock.Now()
+	notification.Attempts++
+	notification.LastError = null.StringFrom(deliveryErr.Error())
+	if notification.Attempts >= store.Config.ExternalInitiatorMaxAttempts() {
+		notification.DeadAt = &now
+		logger.Errorw("Dead-lettering notification to external initiator after running out of attempts",
+			"name", notification.ExternalInitiatorName, "attempts", notification.Attempts, "error", deliveryErr)
+	} else {
+		backoff := store.Config.ExternalInitiatorRetryBackoff().Duration() << (notification.Attempts - 1)
+		notification.NextAttemptAt = now.Add(backoff)
+	}
+	if err := store.SaveExternalInitiatorNotification(notification); err != nil {
+		return err
+	}
+	return deliveryErr
+}
+
+func deliverExternalInitiatorNotification(store *store.Store, notification models.ExternalInitiatorNotification) error {
+	ei, err := store.FindExternalInitiatorByName(notification.ExternalInitiatorName)
+	if err != nil {
+		return errors.Wrap(err, "external initiator")
+	}
+	if ei.URL == nil {
+		return fmt.Errorf("external initiator '%s' has no URL", ei.Name)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, ei.URL.String(), bytes.NewBufferString(notification.Body.String()))
+	if err != nil {
+		return errors.Wrap(err, "creating notify HTTP request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(models.ExternalInitiatorAccessKeyHeader, ei.OutgoingToken.String())
+	req.Header.Set(models.ExternalInitiatorSecretHeader, ei.OutgoingSecret.String())
+
+	client := &http.Client{Timeout: store.Config.DefaultHTTPTimeout().Duration()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "could not notify '%s' (%s)", ei.Name, ei.URL)
+	}
+	defer resp.Body.Close()
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return fmt.Errorf(" notify '%s' (%s) received bad response '%s'", ei.Name, ei.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverExternalInitiatorNotification(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("EXTERNAL_INITIATOR_MAX_ATTEMPTS", 2)
+	store.Config.Set("EXTERNAL_INITIATOR_RETRY_BACKOFF", "1m")
+
+	up := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&up) == 0 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	url := cltest.WebURL(t, server.URL)
+	ei, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: "bitcoin", URL: &url})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateExternalInitiator(ei))
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	notification := &models.ExternalInitiatorNotification{
+		ExternalInitiatorName: "bitcoin",
+		JobSpecID:             job.ID,
+		Body:                  cltest.JSONFromString(t, `{"jobId":"abc"}`),
+		NextAttemptAt:         time.Now(),
+	}
+	require.NoError(t, store.CreateExternalInitiatorNotification(notification))
+
+	before := time.Now()
+	assert.Error(t, services.DeliverExternalInitiatorNotification(store, notification))
+	assert.Equal(t, uint(1), notification.Attempts)
+	assert.Nil(t, notification.DeadAt)
+	assert.True(t, notification.NextAttemptAt.After(before.Add(59*time.Second)))
+	assert.Contains(t, notification.LastError.String, "502")
+
+	assert.Error(t, services.DeliverExternalInitiatorNotification(store, notification))
+	require.NotNil(t, notification.DeadAt)
+	dead, err := store.DeadExternalInitiatorNotifications()
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	failures, err := store.ExternalInitiatorDeliveryFailures("bitcoin", 10)
+	require.NoError(t, err)
+	assert.Len(t, failures, 2)
+
+	retried, err := store.RetryExternalInitiatorNotification(notification.ID, time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, retried.DeadAt)
+	assert.Equal(t, uint(0), retried.Attempts)
+
+	atomic.StoreInt32(&up, 1)
+	require.NoError(t, services.DeliverExternalInitiatorNotification(store, &retried))
+	_, err = store.RetryExternalInitiatorNotification(notification.ID, time.Now())
+	assert.Equal(t, orm.ErrorNotFound, err)
+	due, err := store.DueExternalInitiatorNotifications(time.Now(), orm.BatchSize)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
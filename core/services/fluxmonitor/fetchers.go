@@ -1,15 +1,20 @@
 package fluxmonitor
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 
 	"github.com/guregu/null"
 	"github.com/pkg/errors"
@@ -50,31 +55,35 @@ func newHTTPFetcher(
 }
 
 func (p *httpFetcher) Fetch() (decimal.Decimal, error) {
+	feed := redactURL(p.url)
 	r, err := p.client.Post(p.url.String(), "application/json", strings.NewReader(p.requestData))
 	if err != nil {
-		return decimal.Decimal{}, errors.Wrap(err, fmt.Sprintf("unable to fetch price from %s with payload '%s'", p.url.String(), p.requestData))
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = feed
+		}
+		return decimal.Decimal{}, errors.Wrap(err, fmt.Sprintf("unable to fetch price from %s with payload '%s'", feed, p.requestData))
 	}
 
 	defer r.Body.Close()
 	target := adapterResponse{}
 	if err = json.NewDecoder(r.Body).Decode(&target); err != nil {
-		return decimal.Decimal{}, errors.Wrap(err, fmt.Sprintf("unable to decode price from %s", p.url.String()))
+		return decimal.Decimal{}, errors.Wrap(err, fmt.Sprintf("unable to decode price from %s", feed))
 	}
 	if target.ErrorMessage.Valid {
-		return decimal.Decimal{}, errors.Wrap(errors.New(target.ErrorMessage.String), fmt.Sprintf("price fetcher %s returned error", p.url.String()))
+		return decimal.Decimal{}, errors.Wrap(errors.New(target.ErrorMessage.String), fmt.Sprintf("price fetcher %s returned error", feed))
 	}
 	if r.StatusCode >= 400 {
-		return decimal.Decimal{}, fmt.Errorf("status code: %d, no error message; unable to retrieve price from %s", r.StatusCode, p.url.String())
+		return decimal.Decimal{}, fmt.Errorf("status code: %d, no error message; unable to retrieve price from %s", r.StatusCode, feed)
 	}
 
 	result := target.Result()
 	if result == nil {
-		return decimal.Decimal{}, errors.Wrap(errors.New("no result returned"), fmt.Sprintf("unable to fetch price from %s", p.url.String()))
+		return decimal.Decimal{}, errors.Wrap(errors.New("no result returned"), fmt.Sprintf("unable to fetch price from %s", feed))
 	}
 
 	resultFloat, _ := result.Float64()
 	promFMIndividualReportedValue.WithLabelValues(p.url.String()).Set(resultFloat)
-	logger.Debugw(
+	logger.FluxMonitor.Debugw(
 		fmt.Sprintf("fetched price %v from %s", *result, p.url.String()),
 		"price", result,
 		"url", p.url.String(),
@@ -83,7 +92,23 @@ func (p *httpFetcher) Fetch() (decimal.Decimal, error) {
 }
 
 func (p *httpFetcher) String() string {
-	return fmt.Sprintf("http price fetcher: %s", p.url.String())
+	return fmt.Sprintf("http price fetcher: %s", redactURL(p.url))
+}
+
+// redactURL returns the URL without its credentials, query or fragment, any
+// of which may hold the API key of a feed, for it to be shown in metrics and
+// errors.
+func redactURL(u *url.URL) string {
+	redacted := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path, RawPath: u.RawPath}
+	return redacted.String()
+}
+
+// feedName returns the name the health of the feed at the URL is recorded
+// under: its redacted URL, followed by the start of the SHA-256 hash of the
+// full URL to tell apart feeds differing only in their query or credentials.
+func feedName(u *url.URL) string {
+	sum := sha256.Sum256([]byte(u.String()))
+	return fmt.Sprintf("%s#%x", redactURL(u), sum[:4])
 }
 
 type adapterResponseData struct {
@@ -101,93 +126,316 @@ func (pr adapterResponse) Result() *decimal.Decimal {
 	return pr.Data.Result
 }
 
-// medianFetcher fetches from all fetchers, and returns the median value, or
-// average if even number of results.
-type medianFetcher struct {
+// errFeedQuarantined is returned instead of polling a quarantined feed.
+var errFeedQuarantined = errors.New("feed is quarantined")
+
+// feedHealthFetcher records the health of the feed it fetches from, and skips
+// polling the feed while it is quarantined, other than to probe it once every
+// quarantine period. The feed is recorded under its name, so that its API key
+// is not kept.
+type feedHealthFetcher struct {
+	Fetcher
+	feed  string
+	store *store.Store
+}
+
+func newFeedHealthFetcher(fetcher Fetcher, feedURL *url.URL, store *store.Store) Fetcher {
+	return &feedHealthFetcher{Fetcher: fetcher, feed: feedName(feedURL), store: store}
+}
+
+func (f *feedHealthFetcher) Fetch() (decimal.Decimal, error) {
+	threshold := f.store.Config.FluxMonitorFeedQuarantineThreshold()
+	if threshold > 0 {
+		health, err := f.store.FindFeedHealth(f.feed)
+		if err != nil && errors.Cause(err) != orm.ErrorNotFound {
+			return decimal.Decimal{}, errors.Wrap(err, "checking feed health")
+		} else if err == nil && health.Quarantined() {
+			probe, err := f.store.ProbeFeed(f.feed, f.store.Config.FluxMonitorFeedQuarantinePeriod().Duration())
+			if err != nil {
+				return decimal.Decimal{}, errors.Wrap(err, "checking feed health")
+			} else if !probe {
+				return decimal.Decimal{}, errors.Wrapf(errFeedQuarantined, "skipping %s after %d consecutive failures", f.feed, health.ConsecutiveFailures)
+			}
+			logger.FluxMonitor.Infow("Probing quarantined feed for recovery", "feed", f.feed)
+		}
+	}
+
+	start := time.Now()
+	price, err := f.Fetcher.Fetch()
+	if err != nil {
+		logger.FluxMonitor.ErrorIf(f.store.RecordFeedFailure(f.feed, time.Since(start), err, threshold), "unable to record feed failure")
+	} else {
+		logger.FluxMonitor.ErrorIf(f.store.RecordFeedSuccess(f.feed, time.Since(start)), "unable to record feed success")
+	}
+	return price, err
+}
+
+func (f *feedHealthFetcher) String() string {
+	return fmt.Sprintf("%s", f.Fetcher)
+}
+
+// reliabilityDecay is the weight given to the latest poll of a feed when
+// updating its reliability.
+const reliabilityDecay = 0.1
+
+// aggregateFetcher fetches from all fetchers, excludes the values deviating
+// too far from the consensus, and aggregates the rest as configured, by
+// default taking the median value, or average if even number of results.
+type aggregateFetcher struct {
 	fetchers []Fetcher
+	config   models.FluxAggregationConfig
+
+	// reliability is the moving average of how often each feed's value has
+	// been included in a round, used to weigh the values.
+	reliability   []float64
+	reliabilityMu sync.Mutex
 }
 
-// newMedianFetcherFromURLs creates a median fetcher that retrieves a price
-// from all passed URLs using httpFetcher, and returns the median.
-func newMedianFetcherFromURLs(
+// newAggregateFetcherFromURLs creates an aggregate fetcher that retrieves a
+// price from all passed URLs using httpFetcher, tracking the health of each
+// feed in the store if one is given.
+func newAggregateFetcherFromURLs(
 	timeout models.Duration,
 	requestData string,
 	priceURLs []*url.URL,
+	config models.FluxAggregationConfig,
+	store *store.Store,
 ) (Fetcher, error) {
 	fetchers := []Fetcher{}
 	for _, url := range priceURLs {
 		ps := newHTTPFetcher(timeout, requestData, url)
+		if store != nil {
+			ps = newFeedHealthFetcher(ps, url, store)
+		}
 		fetchers = append(fetchers, ps)
 	}
 
-	medianFetcher, err := newMedianFetcher(fetchers...)
+	aggregateFetcher, err := newAggregateFetcher(config, fetchers...)
 	if err != nil {
 		return nil, err
 	}
 
-	return medianFetcher, nil
+	return aggregateFetcher, nil
+}
+
+// NewFetcher creates a fetcher for the feeds of the given initiator, for use
+// by services polling the same feeds as the flux monitor.
+func NewFetcher(initr models.Initiator, store *store.Store) (Fetcher, error) {
+	urls, err := ExtractFeedURLs(initr.Feeds, store.ORM)
+	if err != nil {
+		return nil, err
+	}
+	return newAggregateFetcherFromURLs(
+		store.Config.DefaultHTTPTimeout(),
+		initr.RequestData.String(),
+		urls,
+		initr.Aggregation,
+		store)
 }
 
-func newMedianFetcher(fetchers ...Fetcher) (Fetcher, error) {
+func newAggregateFetcher(config models.FluxAggregationConfig, fetchers ...Fetcher) (Fetcher, error) {
 	if len(fetchers) == 0 {
-		return nil, errors.New("must pass in at least one price fetcher to newMedianFetcher")
+		return nil, errors.New("must pass in at least one price fetcher to newAggregateFetcher")
+	}
+	reliability := make([]float64, len(fetchers))
+	for i := range reliability {
+		reliability[i] = 1
 	}
-	return &medianFetcher{
-		fetchers: fetchers,
+	return &aggregateFetcher{
+		fetchers:    fetchers,
+		config:      config,
+		reliability: reliability,
 	}, nil
 }
 
-func (m *medianFetcher) Fetch() (decimal.Decimal, error) {
-	prices := []decimal.Decimal{}
+func (m *aggregateFetcher) Fetch() (decimal.Decimal, error) {
+	prices := make([]*decimal.Decimal, len(m.fetchers))
 	fetchErrors := []error{}
 
 	type result struct {
+		index int
 		price decimal.Decimal
 		err   error
 	}
 
 	chResults := make(chan result)
-	for _, fetcher := range m.fetchers {
-		fetcher := fetcher
+	for i, fetcher := range m.fetchers {
+		i, fetcher := i, fetcher
 		go func() {
 			price, err := fetcher.Fetch()
 			if err != nil {
-				logger.Error(err)
-				chResults <- result{err: err}
+				logger.FluxMonitor.Error(err)
+				chResults <- result{index: i, err: err}
 			} else {
-				chResults <- result{price: price}
+				chResults <- result{index: i, price: price}
 			}
 		}()
 	}
 
 	for i := 0; i < len(m.fetchers); i++ {
 		r := <-chResults
-		if r.err != nil {
+		if errors.Cause(r.err) == errFeedQuarantined {
+			fetchErrors = append(fetchErrors, r.err)
+			m.exclude(r.index, "quarantined", r.err.Error())
+		} else if r.err != nil {
 			fetchErrors = append(fetchErrors, r.err)
+			m.exclude(r.index, "error", r.err.Error())
 		} else {
-			prices = append(prices, r.price)
+			price := r.price
+			prices[r.index] = &price
 		}
 	}
 
 	errorRate := float64(len(fetchErrors)) / float64(len(m.fetchers))
 	if errorRate >= 0.5 {
-		return decimal.Decimal{}, errors.Wrap(multierr.Combine(fetchErrors...), "majority of fetchers in median failed")
+		m.updateReliability(prices)
+		return decimal.Decimal{}, errors.Wrap(multierr.Combine(fetchErrors...), "majority of fetchers failed")
 	}
 
-	sort.Slice(prices, func(i, j int) bool {
-		return prices[i].LessThan(prices[j])
-	})
-	k := len(prices) / 2
-	if len(prices)%2 == 1 {
-		return prices[k], nil
+	m.excludeOutliers(prices)
+	weights := m.updateReliability(prices)
+
+	included := 0
+	for _, price := range prices {
+		if price != nil {
+			included++
+		}
 	}
-	return prices[k].Add(prices[k-1]).Div(decimal.NewFromInt(2)), nil
+	if float64(included)/float64(len(m.fetchers)) <= 0.5 {
+		return decimal.Decimal{}, fmt.Errorf("only %d of %d feeds agree within %v%% of the consensus", included, len(m.fetchers), m.config.MaxDeviation)
+	}
+
+	return m.aggregate(prices, weights)
 }
 
-func (m *medianFetcher) String() string {
+// excludeOutliers drops the prices deviating from the median of all prices
+// by more than the configured maximum deviation.
+func (m *aggregateFetcher) excludeOutliers(prices []*decimal.Decimal) {
+	if m.config.MaxDeviation <= 0 {
+		return
+	}
+
+	fetched := []decimal.Decimal{}
+	for _, price := range prices {
+		if price != nil {
+			fetched = append(fetched, *price)
+		}
+	}
+	consensus := median(fetched)
+	if consensus.IsZero() {
+		return
+	}
+
+	maxDeviation := decimal.NewFromFloat(m.config.MaxDeviation)
+	for i, price := range prices {
+		if price == nil {
+			continue
+		}
+		deviation := price.Sub(consensus).Div(consensus).Abs().Mul(decimal.NewFromInt(100))
+		if deviation.GreaterThan(maxDeviation) {
+			prices[i] = nil
+			m.exclude(i, "deviation", fmt.Sprintf(
+				"price %s deviates %s%% from the consensus %s, more than the %s%% allowed",
+				price, deviation.StringFixed(2), consensus, maxDeviation))
+		}
+	}
+}
+
+// exclude records that a feed was left out of the round, and why.
+func (m *aggregateFetcher) exclude(index int, reason, details string) {
+	feed := fmt.Sprintf("%s", m.fetchers[index])
+	promFMFeedExclusions.WithLabelValues(feed, reason).Inc()
+	logger.FluxMonitor.Warnw("Excluding feed from flux monitor round",
+		"feed", feed,
+		"reason", reason,
+		"details", details,
+	)
+}
+
+// updateReliability updates the reliability of each feed with whether its
+// price was included in this round, returning the new reliabilities.
+func (m *aggregateFetcher) updateReliability(prices []*decimal.Decimal) []float64 {
+	m.reliabilityMu.Lock()
+	defer m.reliabilityMu.Unlock()
+
+	weights := make([]float64, len(prices))
+	for i, price := range prices {
+		included := 0.0
+		if price != nil {
+			included = 1
+		}
+		m.reliability[i] = (1-reliabilityDecay)*m.reliability[i] + reliabilityDecay*included
+		weights[i] = m.reliability[i]
+	}
+	return weights
+}
+
+// aggregate reduces the included prices with the configured method.
+func (m *aggregateFetcher) aggregate(prices []*decimal.Decimal, weights []float64) (decimal.Decimal, error) {
+	included := []decimal.Decimal{}
+	for _, price := range prices {
+		if price != nil {
+			included = append(included, *price)
+		}
+	}
+
+	switch m.config.Method {
+	case models.FluxAggregationMedian, "":
+		return median(included), nil
+	case models.FluxAggregationTrimmedMean:
+		sortPrices(included)
+		trim := int(float64(len(included)) * m.config.Trim)
+		if 2*trim >= len(included) {
+			return decimal.Decimal{}, fmt.Errorf("trimming %v of %d prices from each end leaves none", m.config.Trim, len(included))
+		}
+		included = included[trim : len(included)-trim]
+		sum := decimal.Zero
+		for _, price := range included {
+			sum = sum.Add(price)
+		}
+		return sum.Div(decimal.NewFromInt(int64(len(included)))), nil
+	case models.FluxAggregationWeighted:
+		sum, totalWeight := decimal.Zero, decimal.Zero
+		for i, price := range prices {
+			if price == nil {
+				continue
+			}
+			weight := decimal.NewFromFloat(weights[i])
+			sum = sum.Add(price.Mul(weight))
+			totalWeight = totalWeight.Add(weight)
+		}
+		return sum.Div(totalWeight), nil
+	default:
+		return decimal.Decimal{}, fmt.Errorf("unknown aggregation method %s", m.config.Method)
+	}
+}
+
+func (m *aggregateFetcher) String() string {
 	fetcherDescriptions := make([]string, len(m.fetchers))
 	for i, fetcher := range m.fetchers {
 		fetcherDescriptions[i] = fmt.Sprintf("%s", fetcher)
 	}
-	return fmt.Sprintf("median fetcher: %s", strings.Join(fetcherDescriptions, ","))
+	method := m.config.Method
+	if method == "" {
+		method = models.FluxAggregationMedian
+	}
+	return fmt.Sprintf("%s fetcher: %s", method, strings.Join(fetcherDescriptions, ","))
+}
+
+func sortPrices(prices []decimal.Decimal) {
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].LessThan(prices[j])
+	})
+}
+
+// median returns the median of the prices, or the average of the middle two
+// if there is an even number of them.
+func median(prices []decimal.Decimal) decimal.Decimal {
+	sorted := append([]decimal.Decimal{}, prices...)
+	sortPrices(sorted)
+	k := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[k]
+	}
+	return sorted[k].Add(sorted[k-1]).Div(decimal.NewFromInt(2))
 }
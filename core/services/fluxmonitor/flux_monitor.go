@@ -42,6 +42,7 @@ type RunManager interface {
 type Service interface {
 	AddJob(models.JobSpec) error
 	RemoveJob(*models.ID)
+	ReplayLogs(jobID *models.ID, fromBlock, toBlock uint64) (int, error)
 	Start() error
 	Stop()
 }
@@ -75,7 +76,7 @@ func New(
 		return &concreteFluxMonitor{disabled: true}
 	}
 
-	logBroadcaster := eth.NewLogBroadcaster(store.TxManager, store.ORM, 10)
+	logBroadcaster := eth.NewLogBroadcaster(store.TxManager, store.ORM, store.Config.FinalityDepth())
 	return &concreteFluxMonitor{
 		store:          store,
 		runManager:     runManager,
@@ -95,7 +96,7 @@ func New(
 
 func (fm *concreteFluxMonitor) Start() error {
 	if fm.disabled {
-		logger.Info("Flux monitor disabled: skipping start")
+		logger.FluxMonitor.Info("Flux monitor disabled: skipping start")
 		return nil
 	}
 
@@ -105,7 +106,7 @@ func (fm *concreteFluxMonitor) Start() error {
 	err := fm.store.Jobs(func(j *models.JobSpec) bool {
 		if j == nil {
 			err := errors.New("received nil job")
-			logger.Error(err)
+			logger.FluxMonitor.Error(err)
 			return true
 		}
 		job := *j
@@ -116,7 +117,7 @@ func (fm *concreteFluxMonitor) Start() error {
 
 			err := fm.AddJob(job)
 			if err != nil {
-				logger.Errorf("error adding FluxMonitor job: %v", err)
+				logger.FluxMonitor.Errorf("error adding FluxMonitor job: %v", err)
 			}
 		}()
 		return true
@@ -131,7 +132,7 @@ func (fm *concreteFluxMonitor) Start() error {
 // Disconnect cleans up running deviation checkers.
 func (fm *concreteFluxMonitor) Stop() {
 	if fm.disabled {
-		logger.Info("Flux monitor disabled: cannot stop")
+		logger.FluxMonitor.Info("Flux monitor disabled: cannot stop")
 		return
 	}
 
@@ -140,6 +141,15 @@ func (fm *concreteFluxMonitor) Stop() {
 	<-fm.chDone
 }
 
+// ReplayLogs delivers the logs of the given blocks again to the deviation
+// checkers of the job, which process those they have not consumed yet.
+func (fm *concreteFluxMonitor) ReplayLogs(jobID *models.ID, fromBlock, toBlock uint64) (int, error) {
+	if fm.disabled {
+		return 0, errors.New("flux monitor is disabled")
+	}
+	return fm.logBroadcaster.Replay(jobID, fromBlock, toBlock)
+}
+
 // serveInternalRequests handles internal requests for state change via
 // channels.  Inspired by the ideas of Communicating Sequential Processes, or
 // CSP.
@@ -152,7 +162,7 @@ func (fm *concreteFluxMonitor) serveInternalRequests() {
 		select {
 		case entry := <-fm.chAdd:
 			if _, ok := jobMap[entry.jobID]; ok {
-				logger.Errorf("job '%s' has already been added to flux monitor", entry.jobID.String())
+				logger.FluxMonitor.Errorf("job '%s' has already been added to flux monitor", entry.jobID.String())
 				continue
 			}
 			for _, checker := range entry.checkers {
@@ -163,7 +173,7 @@ func (fm *concreteFluxMonitor) serveInternalRequests() {
 		case jobID := <-fm.chRemove:
 			checkers, ok := jobMap[jobID]
 			if !ok {
-				logger.Debugf("job '%s' is missing from the flux monitor", jobID.String())
+				logger.FluxMonitor.Debugf("job '%s' is missing from the flux monitor", jobID.String())
 				continue
 			}
 			for _, checker := range checkers {
@@ -187,14 +197,14 @@ func (fm *concreteFluxMonitor) serveInternalRequests() {
 func (fm *concreteFluxMonitor) AddJob(job models.JobSpec) error {
 	if job.ID == nil {
 		err := errors.New("received job with nil ID")
-		logger.Error(err)
+		logger.FluxMonitor.Error(err)
 		return err
 	}
 
 	var validCheckers []DeviationChecker
 	for _, initr := range job.InitiatorsFor(models.InitiatorFluxMonitor) {
-		logger.Debugw("Adding job to flux monitor",
-			"job", job.ID.String(),
+		logger.FluxMonitor.Debugw("Adding job to flux monitor",
+			"job_id", job.ID.String(),
 			"initr", initr.ID,
 		)
 
@@ -218,7 +228,7 @@ func (fm *concreteFluxMonitor) AddJob(job models.JobSpec) error {
 // to the passed job ID.
 func (fm *concreteFluxMonitor) RemoveJob(id *models.ID) {
 	if id == nil {
-		logger.Warn("nil job ID passed to FluxMonitor#RemoveJob")
+		logger.FluxMonitor.Warn("nil job ID passed to FluxMonitor#RemoveJob")
 		return
 	}
 	fm.chRemove <- *id
@@ -253,10 +263,12 @@ func (f pollingDeviationCheckerFactory) New(
 		return nil, err
 	}
 
-	fetcher, err := newMedianFetcherFromURLs(
+	fetcher, err := newAggregateFetcherFromURLs(
 		timeout,
 		initr.RequestData.String(),
-		urls)
+		urls,
+		initr.Aggregation,
+		f.store)
 	if err != nil {
 		return nil, err
 	}
@@ -403,8 +415,8 @@ const (
 // Start begins the CSP consumer in a single goroutine to
 // poll the price adapters and listen to NewRound events.
 func (p *PollingDeviationChecker) Start() {
-	logger.Debugw("Starting checker for job",
-		"job", p.initr.JobSpecID.String(),
+	logger.FluxMonitor.Debugw("Starting checker for job",
+		"job_id", p.initr.JobSpecID.String(),
 		"initr", p.initr.ID)
 
 	go p.consume()
@@ -417,14 +429,14 @@ func (p *PollingDeviationChecker) Stop() {
 }
 
 func (p *PollingDeviationChecker) OnConnect() {
-	logger.Debugw("PollingDeviationChecker connected to Ethereum node",
+	logger.FluxMonitor.Debugw("PollingDeviationChecker connected to Ethereum node",
 		"address", p.initr.Address.Hex(),
 	)
 	p.connected.Set()
 }
 
 func (p *PollingDeviationChecker) OnDisconnect() {
-	logger.Debugw("PollingDeviationChecker disconnected from Ethereum node",
+	logger.FluxMonitor.Debugw("PollingDeviationChecker disconnected from Ethereum node",
 		"address", p.initr.Address.Hex(),
 	)
 	p.connected.UnSet()
@@ -433,7 +445,7 @@ func (p *PollingDeviationChecker) OnDisconnect() {
 func (p *PollingDeviationChecker) HandleLog(lb eth.LogBroadcast, err error) {
 	rawLog := lb.Log()
 	if rawLog == nil || reflect.ValueOf(rawLog).IsNil() {
-		logger.Error("HandleLog: ignoring nil value")
+		logger.FluxMonitor.Error("HandleLog: ignoring nil value")
 		return
 	}
 
@@ -445,7 +457,7 @@ func (p *PollingDeviationChecker) HandleLog(lb eth.LogBroadcast, err error) {
 		p.backlog.Add(priorityAnswerUpdatedLog, maybeLog{lb, err})
 
 	default:
-		logger.Warnf("unexpected log type %T", log)
+		logger.FluxMonitor.Warnf("unexpected log type %T", log)
 		return
 	}
 
@@ -492,7 +504,7 @@ func (p *PollingDeviationChecker) consume() {
 			p.processLogs()
 
 		case <-p.pollTicker:
-			logger.Debugw("Poll ticker fired",
+			logger.FluxMonitor.Debugw("Poll ticker fired",
 				"pollPeriod", p.initr.PollTimer.Period,
 				"idleDuration", p.initr.IdleTimer.Duration,
 				"mostRecentSubmittedRoundID", p.mostRecentSubmittedRoundID,
@@ -502,7 +514,7 @@ func (p *PollingDeviationChecker) consume() {
 			p.pollIfEligible(float64(p.initr.Threshold))
 
 		case <-p.idleTimer:
-			logger.Debugw("Idle ticker fired",
+			logger.FluxMonitor.Debugw("Idle ticker fired",
 				"pollPeriod", p.initr.PollTimer.Period,
 				"idleDuration", p.initr.IdleTimer.Duration,
 				"mostRecentSubmittedRoundID", p.mostRecentSubmittedRoundID,
@@ -512,7 +524,7 @@ func (p *PollingDeviationChecker) consume() {
 			p.pollIfEligible(0)
 
 		case <-p.roundTimer:
-			logger.Debugw("Round timeout ticker fired",
+			logger.FluxMonitor.Debugw("Round timeout ticker fired",
 				"pollPeriod", p.initr.PollTimer.Period,
 				"idleDuration", p.initr.IdleTimer.Duration,
 				"mostRecentSubmittedRoundID", p.mostRecentSubmittedRoundID,
@@ -527,7 +539,7 @@ func (p *PollingDeviationChecker) consume() {
 func (p *PollingDeviationChecker) determineMostRecentSubmittedRoundID() {
 	myAccount, err := p.store.KeyStore.GetFirstAccount()
 	if err != nil {
-		logger.Error("error determining most recent submitted round ID: ", err)
+		logger.FluxMonitor.Error("error determining most recent submitted round ID: ", err)
 		return
 	}
 
@@ -537,15 +549,15 @@ func (p *PollingDeviationChecker) determineMostRecentSubmittedRoundID() {
 	// that we avoid re-polling for a given round when our tx takes a while to confirm.
 	txs, err := p.store.ORM.FindTxsBySenderAndRecipient(myAccount.Address, p.initr.Address, 0, 5)
 	if err != nil && !gorm.IsRecordNotFoundError(err) {
-		logger.Error("error determining most recent submitted round ID: ", err)
+		logger.FluxMonitor.Error("error determining most recent submitted round ID: ", err)
 		return
 	}
 
 	// Parse the round IDs from the transaction data
 	for _, tx := range txs {
 		if len(tx.Data) != 68 {
-			logger.Warnw("found Flux Monitor tx with bad data payload",
-				"txID", tx.ID,
+			logger.FluxMonitor.Warnw("found Flux Monitor tx with bad data payload",
+				"tx_id", tx.ID,
 			)
 			continue
 		}
@@ -556,8 +568,8 @@ func (p *PollingDeviationChecker) determineMostRecentSubmittedRoundID() {
 			p.mostRecentSubmittedRoundID = roundID
 		}
 	}
-	logger.Infow(fmt.Sprintf("roundID of most recent submission is %v", p.mostRecentSubmittedRoundID),
-		"jobID", p.initr.JobSpecID,
+	logger.FluxMonitor.Infow(fmt.Sprintf("roundID of most recent submission is %v", p.mostRecentSubmittedRoundID),
+		"job_id", p.initr.JobSpecID,
 		"aggregator", p.initr.Address.Hex(),
 	)
 }
@@ -567,35 +579,37 @@ func (p *PollingDeviationChecker) processLogs() {
 		maybeLog := p.backlog.Take().(maybeLog)
 
 		if maybeLog.Err != nil {
-			logger.Errorf("error received from log broadcaster: %v", maybeLog.Err)
+			logger.FluxMonitor.Errorf("error received from log broadcaster: %v", maybeLog.Err)
 			continue
 		}
 
 		switch log := maybeLog.LogBroadcast.Log().(type) {
 		case *contracts.LogNewRound:
-			logger.Debugw("NewRound log", p.loggerFieldsForNewRound(*log)...)
+			logger.FluxMonitor.Debugw("NewRound log", p.loggerFieldsForNewRound(*log)...)
 			consumeLogBroadcast(maybeLog.LogBroadcast, func() { p.respondToNewRoundLog(*log) })
 
 		case *contracts.LogAnswerUpdated:
-			logger.Debugw("AnswerUpdated log", p.loggerFieldsForAnswerUpdated(*log)...)
+			logger.FluxMonitor.Debugw("AnswerUpdated log", p.loggerFieldsForAnswerUpdated(*log)...)
 			consumeLogBroadcast(maybeLog.LogBroadcast, func() { p.respondToAnswerUpdatedLog(*log) })
 
 		default:
-			logger.Errorf("unknown log %v of type %T", log, log)
+			logger.FluxMonitor.Errorf("unknown log %v of type %T", log, log)
 		}
 	}
 }
 
+// consumeLogBroadcast calls back unless the log was consumed already, the
+// consumption being recorded along with the processing of the log, so that it
+// is only processed once however many times it is delivered. If the
+// consumption cannot be recorded before processing, the log is processed
+// anyway.
 func consumeLogBroadcast(lb eth.LogBroadcast, callback func()) {
-	consumed, err := lb.WasAlreadyConsumed()
+	processed, err := lb.ConsumeOnce(callback)
 	if err != nil {
-		logger.Errorf("Error determining if log was already consumed: %v", err)
-	} else if consumed {
-		return
-	}
-	callback()
-	if err = lb.MarkConsumed(); err != nil {
-		logger.Errorf("Error marking log as consumed: %v", err)
+		logger.FluxMonitor.Errorf("Error marking log as consumed: %v", err)
+		if !processed {
+			callback()
+		}
 	}
 }
 
@@ -605,7 +619,7 @@ func consumeLogBroadcast(lb eth.LogBroadcast, callback func()) {
 // Only invoked by the CSP consumer on the single goroutine for thread safety.
 func (p *PollingDeviationChecker) respondToAnswerUpdatedLog(log contracts.LogAnswerUpdated) {
 	if p.reportableRoundID != nil && log.RoundId.Cmp(p.reportableRoundID) < 0 {
-		logger.Debugw("Received stale AnswerUpdated log", p.loggerFieldsForAnswerUpdated(log)...)
+		logger.FluxMonitor.Debugw("Received stale AnswerUpdated log", p.loggerFieldsForAnswerUpdated(log)...)
 	}
 }
 
@@ -625,10 +639,10 @@ func (p *PollingDeviationChecker) respondToNewRoundLog(log contracts.LogNewRound
 	// Ignore rounds we started
 	acct, err := p.store.KeyStore.GetFirstAccount()
 	if err != nil {
-		logger.Errorw(fmt.Sprintf("error fetching account from keystore: %v", err), p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Errorw(fmt.Sprintf("error fetching account from keystore: %v", err), p.loggerFieldsForNewRound(log)...)
 		return
 	} else if log.StartedBy == acct.Address {
-		logger.Infow("Ignoring new round request: we started this round", p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Infow("Ignoring new round request: we started this round", p.loggerFieldsForNewRound(log)...)
 		return
 	}
 
@@ -637,39 +651,45 @@ func (p *PollingDeviationChecker) respondToNewRoundLog(log contracts.LogNewRound
 	// from RoundState() over the one in the log, and record it as the current ReportableRoundID.
 	roundState, err := p.roundState()
 	if err != nil {
-		logger.Errorw(fmt.Sprintf("Ignoring new round request: error fetching eligibility from contract: %v", err), p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Errorw(fmt.Sprintf("Ignoring new round request: error fetching eligibility from contract: %v", err), p.loggerFieldsForNewRound(log)...)
 		return
 	}
 
 	err = p.checkEligibilityAndAggregatorFunding(roundState)
 	if errors.Cause(err) == ErrAlreadySubmitted {
-		logger.Infow(fmt.Sprintf("Ignoring new round request: %v, possible chain reorg", err), p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Infow(fmt.Sprintf("Ignoring new round request: %v, possible chain reorg", err), p.loggerFieldsForNewRound(log)...)
 		return
 	} else if err != nil {
-		logger.Infow(fmt.Sprintf("Ignoring new round request: %v", err), p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Infow(fmt.Sprintf("Ignoring new round request: %v", err), p.loggerFieldsForNewRound(log)...)
 		return
 	}
 
 	// Ignore old rounds
 	if log.RoundId.Cmp(p.reportableRoundID) < 0 {
-		logger.Infow("Ignoring new round request: new < current", p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Infow("Ignoring new round request: new < current", p.loggerFieldsForNewRound(log)...)
 		return
 	} else if log.RoundId.Uint64() <= p.mostRecentSubmittedRoundID {
-		logger.Infow("Ignoring new round request: already submitted for this round", p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Infow("Ignoring new round request: already submitted for this round", p.loggerFieldsForNewRound(log)...)
 		return
 	}
 
-	logger.Infow("Responding to new round request: new > current", p.loggerFieldsForNewRound(log)...)
+	logger.FluxMonitor.Infow("Responding to new round request: new > current", p.loggerFieldsForNewRound(log)...)
 
 	polledAnswer, err := p.fetcher.Fetch()
 	if err != nil {
-		logger.Errorw(fmt.Sprintf("unable to fetch median price: %v", err), p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Errorw(fmt.Sprintf("unable to fetch median price: %v", err), p.loggerFieldsForNewRound(log)...)
+		return
+	}
+
+	if p.initr.DryRun {
+		latestAnswer := decimal.NewFromBigInt(roundState.LatestAnswer, -p.precision)
+		p.recordDryRunSubmission(latestAnswer, polledAnswer, submissionReasonNewRound, p.loggerFieldsForNewRound(log))
 		return
 	}
 
 	err = p.createJobRun(polledAnswer, p.reportableRoundID)
 	if err != nil {
-		logger.Errorw(fmt.Sprintf("unable to create job run: %v", err), p.loggerFieldsForNewRound(log)...)
+		logger.FluxMonitor.Errorw(fmt.Sprintf("unable to create job run: %v", err), p.loggerFieldsForNewRound(log)...)
 		return
 	}
 }
@@ -681,8 +701,11 @@ var (
 	ErrAlreadySubmitted = errors.Errorf("already submitted for round")
 )
 
+// checkEligibilityAndAggregatorFunding returns an error if the node should
+// not submit an answer for the reportable round. A node in dry run mode need
+// not be eligible to submit, as it need not be an oracle of the aggregator.
 func (p *PollingDeviationChecker) checkEligibilityAndAggregatorFunding(roundState contracts.FluxAggregatorRoundState) error {
-	if !roundState.EligibleToSubmit {
+	if !roundState.EligibleToSubmit && !p.initr.DryRun {
 		return ErrNotEligible
 	} else if !p.SufficientFunds(roundState) {
 		return ErrUnderfunded
@@ -713,29 +736,29 @@ func (p *PollingDeviationChecker) pollIfEligible(threshold float64) (createdJobR
 	loggerFields := p.loggerFields("threshold", threshold)
 
 	if p.connected.IsSet() == false {
-		logger.Warnw("not connected to Ethereum node, skipping poll", loggerFields...)
+		logger.FluxMonitor.Warnw("not connected to Ethereum node, skipping poll", loggerFields...)
 		return false
 	}
 
 	roundState, err := p.roundState()
 	if err != nil {
-		logger.Errorw(fmt.Sprintf("unable to determine eligibility to submit from FluxAggregator contract: %v", err), loggerFields...)
+		logger.FluxMonitor.Errorw(fmt.Sprintf("unable to determine eligibility to submit from FluxAggregator contract: %v", err), loggerFields...)
 		return false
 	}
 	loggerFields = append(loggerFields, "reportableRound", roundState.ReportableRoundID)
 
 	err = p.checkEligibilityAndAggregatorFunding(roundState)
 	if errors.Cause(err) == ErrAlreadySubmitted {
-		logger.Infow(fmt.Sprintf("skipping poll: %v, tx is pending", err), loggerFields...)
+		logger.FluxMonitor.Infow(fmt.Sprintf("skipping poll: %v, tx is pending", err), loggerFields...)
 		return false
 	} else if err != nil {
-		logger.Infow(fmt.Sprintf("skipping poll: %v", err), loggerFields...)
+		logger.FluxMonitor.Infow(fmt.Sprintf("skipping poll: %v", err), loggerFields...)
 		return false
 	}
 
 	polledAnswer, err := p.fetcher.Fetch()
 	if err != nil {
-		logger.Errorw(fmt.Sprintf("can't fetch answer: %v", err), loggerFields...)
+		logger.FluxMonitor.Errorw(fmt.Sprintf("can't fetch answer: %v", err), loggerFields...)
 		return false
 	}
 
@@ -748,19 +771,33 @@ func (p *PollingDeviationChecker) pollIfEligible(threshold float64) (createdJobR
 		"polledAnswer", polledAnswer,
 	)
 	if roundState.ReportableRoundID > 1 && !OutsideDeviation(latestAnswer, polledAnswer, threshold) {
-		logger.Debugw("deviation < threshold, not submitting", loggerFields...)
+		logger.FluxMonitor.Debugw("deviation < threshold, not submitting", loggerFields...)
+		return false
+	}
+	if roundState.ReportableRoundID > 1 && threshold > 0 && p.gasThrottled(latestAnswer, polledAnswer, loggerFields) {
+		return false
+	}
+
+	reason := submissionReasonDeviation
+	if roundState.ReportableRoundID <= 1 {
+		reason = submissionReasonFirstRound
+	} else if threshold == 0 {
+		reason = submissionReasonIdleTimer
+	}
+	if p.initr.DryRun {
+		p.recordDryRunSubmission(latestAnswer, polledAnswer, reason, loggerFields)
 		return false
 	}
 
 	if roundState.ReportableRoundID > 1 {
-		logger.Infow("deviation > threshold, starting new round", loggerFields...)
+		logger.FluxMonitor.Infow("deviation > threshold, starting new round", loggerFields...)
 	} else {
-		logger.Infow("starting first round", loggerFields...)
+		logger.FluxMonitor.Infow("starting first round", loggerFields...)
 	}
 
 	err = p.createJobRun(polledAnswer, p.reportableRoundID)
 	if err != nil {
-		logger.Errorw(fmt.Sprintf("can't create job run: %v", err), loggerFields...)
+		logger.FluxMonitor.Errorw(fmt.Sprintf("can't create job run: %v", err), loggerFields...)
 		return false
 	}
 
@@ -769,6 +806,62 @@ func (p *PollingDeviationChecker) pollIfEligible(threshold float64) (createdJobR
 	return true
 }
 
+// gasThrottled returns true if the gas price is over the ceiling set by the
+// initiator's gas throttle, and the answer has not deviated by enough for an
+// emergency round. Rounds started by the idle timer are never throttled.
+func (p *PollingDeviationChecker) gasThrottled(latestAnswer, polledAnswer decimal.Decimal, loggerFields []interface{}) bool {
+	throttle := p.initr.GasThrottle
+	if !throttle.Enabled() {
+		return false
+	}
+	gasPrice := p.store.Config.EthGasPriceDefault()
+	if gasPrice.Cmp(throttle.GasPriceCeiling.ToInt()) <= 0 {
+		return false
+	}
+
+	loggerFields = append(loggerFields,
+		"gasPrice", gasPrice,
+		"gasPriceCeiling", throttle.GasPriceCeiling,
+		"emergencyThreshold", throttle.EmergencyThreshold,
+	)
+	if OutsideDeviation(latestAnswer, polledAnswer, float64(throttle.EmergencyThreshold)) {
+		logger.FluxMonitor.Infow("gas price > ceiling, but deviation > emergency threshold", loggerFields...)
+		return false
+	}
+	logger.FluxMonitor.Infow("gas price > ceiling and deviation < emergency threshold, not submitting", loggerFields...)
+	promFMGasThrottledRounds.WithLabelValues(p.initr.JobSpecID.String()).Inc()
+	return true
+}
+
+// The reasons for submitting an answer, as recorded in dry run mode.
+const (
+	submissionReasonFirstRound = "first round"
+	submissionReasonDeviation  = "deviation"
+	submissionReasonIdleTimer  = "idle timer"
+	submissionReasonNewRound   = "new round"
+)
+
+// recordDryRunSubmission records the answer a flux monitor in dry run mode
+// would have submitted for the reportable round, in place of submitting it.
+func (p *PollingDeviationChecker) recordDryRunSubmission(latestAnswer, polledAnswer decimal.Decimal, reason string, loggerFields []interface{}) {
+	loggerFields = append(loggerFields, "reason", reason)
+	logger.FluxMonitor.Infow("dry run, not submitting answer", loggerFields...)
+
+	err := p.store.CreateFluxDryRunSubmission(&models.FluxDryRunSubmission{
+		JobSpecID:    p.initr.JobSpecID,
+		Aggregator:   p.initr.Address,
+		RoundID:      p.reportableRoundID.Uint64(),
+		LatestAnswer: latestAnswer,
+		Answer:       polledAnswer,
+		Reason:       reason,
+	})
+	if err != nil {
+		logger.FluxMonitor.Errorw(fmt.Sprintf("unable to record dry run submission: %v", err), loggerFields...)
+		return
+	}
+	p.mostRecentSubmittedRoundID = p.reportableRoundID.Uint64()
+}
+
 func (p *PollingDeviationChecker) roundState() (contracts.FluxAggregatorRoundState, error) {
 	acct, err := p.store.KeyStore.GetFirstAccount()
 	if err != nil {
@@ -794,7 +887,7 @@ func (p *PollingDeviationChecker) resetRoundTimeoutTicker(roundState contracts.F
 
 	if roundState.TimesOutAt() == 0 {
 		p.roundTimer = nil
-		logger.Debugw("disabling roundTimer, no active round", loggerFields...)
+		logger.FluxMonitor.Debugw("disabling roundTimer, no active round", loggerFields...)
 
 	} else {
 		timesOutAt := time.Unix(int64(roundState.TimesOutAt()), 0)
@@ -802,11 +895,11 @@ func (p *PollingDeviationChecker) resetRoundTimeoutTicker(roundState contracts.F
 
 		if timeUntilTimeout <= 0 {
 			p.roundTimer = nil
-			logger.Debugw("roundTimer has run down; disabling", loggerFields...)
+			logger.FluxMonitor.Debugw("roundTimer has run down; disabling", loggerFields...)
 		} else {
 			p.roundTimer = time.After(timeUntilTimeout)
 			loggerFields = append(loggerFields, "value", roundState.TimesOutAt())
-			logger.Debugw("updating roundState.TimesOutAt", loggerFields...)
+			logger.FluxMonitor.Debugw("updating roundState.TimesOutAt", loggerFields...)
 		}
 	}
 }
@@ -829,11 +922,11 @@ func (p *PollingDeviationChecker) resetIdleTicker(roundStartedAtUTC uint64) {
 	)
 
 	if timeUntilIdleDeadline <= 0 {
-		logger.Debugw("not resetting idleTimer, negative duration", loggerFields...)
+		logger.FluxMonitor.Debugw("not resetting idleTimer, negative duration", loggerFields...)
 		return
 	}
 	p.idleTimer = time.After(timeUntilIdleDeadline)
-	logger.Debugw("resetting idleTimer", loggerFields...)
+	logger.FluxMonitor.Debugw("resetting idleTimer", loggerFields...)
 }
 
 // jobRunRequest is the request used to trigger a Job Run by the Flux Monitor.
@@ -887,7 +980,7 @@ func (p *PollingDeviationChecker) loggerFields(added ...interface{}) []interface
 		"mostRecentSubmittedRoundID", p.mostRecentSubmittedRoundID,
 		"reportableRoundID", p.reportableRoundID,
 		"contract", p.initr.Address.Hex(),
-		"jobID", p.initr.JobSpecID.String(),
+		"job_id", p.initr.JobSpecID.String(),
 	}...)
 }
 
@@ -898,7 +991,7 @@ func (p *PollingDeviationChecker) loggerFieldsForNewRound(log contracts.LogNewRo
 		"startedBy", log.StartedBy.Hex(),
 		"startedAt", log.StartedAt.String(),
 		"contract", log.Address.Hex(),
-		"jobID", p.initr.JobSpecID,
+		"job_id", p.initr.JobSpecID,
 	}
 }
 
@@ -908,7 +1001,7 @@ func (p *PollingDeviationChecker) loggerFieldsForAnswerUpdated(log contracts.Log
 		"answer", log.Current.String(),
 		"timestamp", log.Timestamp.String(),
 		"contract", log.Address.Hex(),
-		"job", p.initr.JobSpecID,
+		"job_id", p.initr.JobSpecID,
 	}
 }
 
@@ -926,17 +1019,17 @@ func OutsideDeviation(curAnswer, nextAnswer decimal.Decimal, threshold float64)
 	}
 
 	if threshold == 0 {
-		logger.Debugw("Deviation threshold always met at 0", loggerFields...)
+		logger.FluxMonitor.Debugw("Deviation threshold always met at 0", loggerFields...)
 		return true
 	}
 
 	if curAnswer.IsZero() {
 		if nextAnswer.IsZero() {
-			logger.Debugw("Deviation threshold not met", loggerFields...)
+			logger.FluxMonitor.Debugw("Deviation threshold not met", loggerFields...)
 			return false
 		}
 
-		logger.Infow("Deviation threshold met", loggerFields...)
+		logger.FluxMonitor.Infow("Deviation threshold met", loggerFields...)
 		return true
 	}
 
@@ -946,10 +1039,10 @@ func OutsideDeviation(curAnswer, nextAnswer decimal.Decimal, threshold float64)
 	loggerFields = append(loggerFields, "percentage", percentage)
 
 	if percentage.LessThan(decimal.NewFromFloat(threshold)) {
-		logger.Debugw("Deviation threshold not met", loggerFields...)
+		logger.FluxMonitor.Debugw("Deviation threshold not met", loggerFields...)
 		return false
 	}
-	logger.Infow("Deviation threshold met", loggerFields...)
+	logger.FluxMonitor.Infow("Deviation threshold met", loggerFields...)
 	return true
 }
 
@@ -969,18 +1062,18 @@ func MakeIdleTimer(log contracts.LogNewRound, idleThreshold models.Duration, clo
 		return defaultIdleTimer(idleThreshold, clock)
 	}
 	if !log.StartedAt.IsInt64() {
-		logger.Errorf("Value for log.StartedAt %s would overflow int64, using default idle timer instead.", log.StartedAt.String())
+		logger.FluxMonitor.Errorf("Value for log.StartedAt %s would overflow int64, using default idle timer instead.", log.StartedAt.String())
 		return defaultIdleTimer(idleThreshold, clock)
 	}
 	roundStarted := time.Unix(log.StartedAt.Int64(), 0)
 	if roundStarted.After(timeNow) {
-		logger.Warnf("Round started time of %s is later than current system time of %s, setting idle timer to %s from now. Most likely scenario is that this machine's clock is running slow. This is suboptimal! Please ensure your system clock is accurate.", roundStarted.String(), timeNow.String(), idleThreshold.Duration().String())
+		logger.FluxMonitor.Warnf("Round started time of %s is later than current system time of %s, setting idle timer to %s from now. Most likely scenario is that this machine's clock is running slow. This is suboptimal! Please ensure your system clock is accurate.", roundStarted.String(), timeNow.String(), idleThreshold.Duration().String())
 		return defaultIdleTimer(idleThreshold, clock)
 	}
 	// duration from now until idle threshold = log timestamp + idle threshold - current time
 	durationUntilIdleThreshold := roundStarted.Add(idleThreshold.Duration()).Sub(timeNow)
 	if durationUntilIdleThreshold < 0 {
-		logger.Warnf("Idle threshold already passed, current time is %s and idle timer expired at %s (round started at %s with idle threshold of %s). It's possible you are processing an old round, or this machine has a fast clock. If this keeps happening, check your system clock and make sure it is accurate.", timeNow, roundStarted.Add(idleThreshold.Duration()).String(), roundStarted.String(), idleThreshold.Duration().String())
+		logger.FluxMonitor.Warnf("Idle threshold already passed, current time is %s and idle timer expired at %s (round started at %s with idle threshold of %s). It's possible you are processing an old round, or this machine has a fast clock. If this keeps happening, check your system clock and make sure it is accurate.", timeNow, roundStarted.Add(idleThreshold.Duration()).String(), roundStarted.String(), idleThreshold.Duration().String())
 	}
 	return clock.After(durationUntilIdleThreshold)
 }
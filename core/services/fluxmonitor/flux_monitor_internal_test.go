@@ -1,7 +1,11 @@
 package fluxmonitor
 
 import (
+	"net/url"
+
 	"github.com/smartcontractkit/chainlink/core/services/eth"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -25,8 +29,19 @@ func (mlb *mockLogBroadcaster) Register(common.Address, eth.LogListener) bool {
 	return false
 }
 func (mlb *mockLogBroadcaster) Unregister(common.Address, eth.LogListener) {}
-func (mlb *mockLogBroadcaster) Stop()                                      {}
+func (mlb *mockLogBroadcaster) Replay(*models.ID, uint64, uint64) (int, error) {
+	return 0, nil
+}
+func (mlb *mockLogBroadcaster) Stop() {}
 
 type MockableLogBroadcaster interface {
 	MockLogBroadcaster() *mockLogBroadcaster
 }
+
+func NewFeedHealthFetcher(fetcher Fetcher, feedURL *url.URL, store *store.Store) Fetcher {
+	return newFeedHealthFetcher(fetcher, feedURL, store)
+}
+
+func FeedName(feedURL *url.URL) string {
+	return feedName(feedURL)
+}
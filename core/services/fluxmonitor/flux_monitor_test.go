@@ -1,6 +1,7 @@
 package fluxmonitor_test
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -276,6 +277,137 @@ func TestPollingDeviationChecker_PollIfEligible(t *testing.T) {
 	}
 }
 
+func TestPollingDeviationChecker_PollIfEligible_GasThrottle(t *testing.T) {
+	tests := []struct {
+		name             string
+		gasPrice         int64
+		threshold        float64
+		polledAnswer     int64
+		expectedToSubmit bool
+	}{
+		{"gas price under ceiling, answers deviate", 10000000000, 1, 102, true},
+		{"gas price over ceiling, answers deviate", 30000000000, 1, 102, false},
+		{"gas price over ceiling, answers deviate past emergency threshold", 30000000000, 1, 110, true},
+		{"gas price over ceiling, idle timer fired", 30000000000, 0, 100, true},
+	}
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	nodeAddr := ensureAccount(t, store)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store.Config.Set("ETH_GAS_PRICE_DEFAULT", test.gasPrice)
+
+			rm := new(mocks.RunManager)
+			fetcher := new(mocks.Fetcher)
+			fluxAggregator := new(mocks.FluxAggregator)
+
+			job := cltest.NewJobWithFluxMonitorInitiator()
+			initr := job.Initiators[0]
+			initr.ID = 1
+			initr.GasThrottle = models.FluxGasThrottleConfig{
+				GasPriceCeiling:    utils.NewBig(big.NewInt(20000000000)),
+				EmergencyThreshold: 5,
+			}
+
+			const reportableRoundID = 2
+			latestAnswer := 100 * int64(math.Pow10(int(initr.InitiatorParams.Precision)))
+			paymentAmount := store.Config.MinimumContractPayment().ToInt()
+			roundState := contracts.FluxAggregatorRoundState{
+				ReportableRoundID: reportableRoundID,
+				EligibleToSubmit:  true,
+				LatestAnswer:      big.NewInt(latestAnswer),
+				AvailableFunds:    big.NewInt(1).Mul(paymentAmount, big.NewInt(1000)),
+				PaymentAmount:     paymentAmount,
+				OracleCount:       oracleCount,
+			}
+			fluxAggregator.On("RoundState", nodeAddr).Return(roundState, nil).Maybe()
+			fetcher.On("Fetch").Return(decimal.NewFromInt(test.polledAnswer), nil)
+
+			if test.expectedToSubmit {
+				run := cltest.NewJobRun(job)
+				rm.On("Create", job.ID, &initr, mock.Anything, mock.Anything).Return(&run, nil)
+				fluxAggregator.On("GetMethodID", "submit").Return(submitSelector, nil)
+			}
+
+			checker, err := fluxmonitor.NewPollingDeviationChecker(
+				store,
+				fluxAggregator,
+				initr,
+				rm,
+				fetcher,
+				func() {},
+			)
+			require.NoError(t, err)
+			checker.OnConnect()
+
+			assert.Equal(t, test.expectedToSubmit, checker.ExportedPollIfEligible(test.threshold))
+
+			fluxAggregator.AssertExpectations(t)
+			fetcher.AssertExpectations(t)
+			rm.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPollingDeviationChecker_PollIfEligible_DryRun(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	nodeAddr := ensureAccount(t, store)
+
+	rm := new(mocks.RunManager)
+	fetcher := new(mocks.Fetcher)
+	fluxAggregator := new(mocks.FluxAggregator)
+
+	job := cltest.NewJobWithFluxMonitorInitiator()
+	job.Initiators[0].DryRun = true
+	require.NoError(t, store.CreateJob(&job))
+	initr := job.Initiators[0]
+
+	const reportableRoundID = 2
+	paymentAmount := store.Config.MinimumContractPayment().ToInt()
+	roundState := contracts.FluxAggregatorRoundState{
+		ReportableRoundID: reportableRoundID,
+		EligibleToSubmit:  false,
+		LatestAnswer:      big.NewInt(1 * int64(math.Pow10(int(initr.InitiatorParams.Precision)))),
+		AvailableFunds:    big.NewInt(1).Mul(paymentAmount, big.NewInt(1000)),
+		PaymentAmount:     paymentAmount,
+		OracleCount:       oracleCount,
+	}
+	fluxAggregator.On("RoundState", nodeAddr).Return(roundState, nil)
+	fetcher.On("Fetch").Return(decimal.NewFromInt(100), nil).Once()
+
+	checker, err := fluxmonitor.NewPollingDeviationChecker(
+		store,
+		fluxAggregator,
+		initr,
+		rm,
+		fetcher,
+		func() {},
+	)
+	require.NoError(t, err)
+	checker.OnConnect()
+
+	assert.False(t, checker.ExportedPollIfEligible(0.1))
+	assert.False(t, checker.ExportedPollIfEligible(0.1), "should not record the same round twice")
+
+	submissions, count, err := store.FluxDryRunSubmissionsFor(job.ID, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	assert.Equal(t, uint64(reportableRoundID), submissions[0].RoundID)
+	assert.Equal(t, "1", submissions[0].LatestAnswer.String())
+	assert.Equal(t, "100", submissions[0].Answer.String())
+	assert.Equal(t, "deviation", submissions[0].Reason)
+	assert.Equal(t, initr.Address, submissions[0].Aggregator)
+
+	fluxAggregator.AssertExpectations(t)
+	fetcher.AssertExpectations(t)
+	rm.AssertExpectations(t)
+}
+
 func TestPollingDeviationChecker_BuffersLogs(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
@@ -365,8 +497,7 @@ func TestPollingDeviationChecker_BuffersLogs(t *testing.T) {
 	for i := 1; i <= 4; i++ {
 		logBroadcast := new(mocks.LogBroadcast)
 		logBroadcast.On("Log").Return(&contracts.LogNewRound{RoundId: big.NewInt(int64(i))})
-		logBroadcast.On("WasAlreadyConsumed").Return(false, nil)
-		logBroadcast.On("MarkConsumed").Return(nil)
+		logBroadcast.On("ConsumeOnce", mock.Anything).Run(consumeLog).Return(true, nil)
 		logBroadcasts = append(logBroadcasts, logBroadcast)
 	}
 
@@ -458,8 +589,7 @@ func TestPollingDeviationChecker_TriggerIdleTimeThreshold(t *testing.T) {
 
 				decodedLog := contracts.LogNewRound{RoundId: big.NewInt(1)}
 				logBroadcast.On("Log").Return(&decodedLog)
-				logBroadcast.On("WasAlreadyConsumed").Return(false, nil).Once()
-				logBroadcast.On("MarkConsumed").Return(nil).Once()
+				logBroadcast.On("ConsumeOnce", mock.Anything).Run(consumeLog).Return(true, nil).Once()
 				deviationChecker.HandleLog(logBroadcast, nil)
 
 				<-chBlock
@@ -1221,3 +1351,51 @@ func TestFluxMonitor_PollingDeviationChecker_HandlesNilLogs(t *testing.T) {
 		p.HandleLog(logBroadcast, nil)
 	})
 }
+
+func TestFeedHealthFetcher_Quarantine(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("FLUX_MONITOR_FEED_QUARANTINE_THRESHOLD", 2)
+	store.Config.Set("FLUX_MONITOR_FEED_QUARANTINE_PERIOD", "1h")
+
+	feedURL, err := url.Parse("https://example.com/deadfeed?apiKey=secret")
+	require.NoError(t, err)
+	feed := fluxmonitor.FeedName(feedURL)
+	assert.NotContains(t, feed, "secret")
+	fetcher := new(mocks.Fetcher)
+	fetcher.On("Fetch").Return(decimal.Decimal{}, errors.New("connection refused")).Twice()
+	healthFetcher := fluxmonitor.NewFeedHealthFetcher(fetcher, feedURL, store)
+
+	for i := 0; i < 2; i++ {
+		_, err := healthFetcher.Fetch()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection refused")
+	}
+	health, err := store.FindFeedHealth(feed)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), health.Failures)
+	assert.True(t, health.Quarantined())
+	assert.Equal(t, "connection refused", health.LastError.ValueOrZero())
+
+	_, err = healthFetcher.Fetch()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quarantined")
+	fetcher.AssertNumberOfCalls(t, "Fetch", 2)
+
+	store.Config.Set("FLUX_MONITOR_FEED_QUARANTINE_PERIOD", "0s")
+	fetcher.On("Fetch").Return(decimal.NewFromInt(100), nil).Once()
+	price, err := healthFetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "100", price.String())
+
+	health, err = store.FindFeedHealth(feed)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), health.Successes)
+	assert.False(t, health.Quarantined(), "a successful probe should release the feed")
+	fetcher.AssertExpectations(t)
+}
+
+// consumeLog processes the log a mock LogBroadcast is asked to consume once.
+func consumeLog(args mock.Arguments) {
+	args.Get(0).(func())()
+}
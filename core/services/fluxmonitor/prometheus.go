@@ -25,6 +25,20 @@ var (
 		},
 		[]string{"url"},
 	)
+	promFMFeedExclusions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "flux_monitor_feed_exclusions_total",
+			Help: "The number of times each feed was excluded from a flux monitor round, by reason",
+		},
+		[]string{"feed", "reason"},
+	)
+	promFMGasThrottledRounds = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "flux_monitor_gas_throttled_rounds_total",
+			Help: "The number of rounds a flux monitor did not start because the gas price was over its ceiling",
+		},
+		[]string{"job_spec_id"},
+	)
 	promFMSeenValue = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "flux_monitor_seen_value",
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/eth"
@@ -23,8 +24,36 @@ var (
 		Name: "head_tracker_heads_received",
 		Help: "The total number of heads seen",
 	})
+	promCurrentHead = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "head_tracker_current_head",
+		Help: "The highest seen head number",
+	})
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "head_tracker_seconds_since_last_head",
+		Help: "How long ago the last head was received",
+	}, func() float64 {
+		lastHeadAt := LastHeadReceivedAt()
+		if lastHeadAt.IsZero() {
+			return 0
+		}
+		return time.Since(lastHeadAt).Seconds()
+	})
+
+	// lastHeadReceivedAt is when the last head was received, in nanoseconds
+	// since the epoch.
+	lastHeadReceivedAt int64
 )
 
+// LastHeadReceivedAt returns when the last head was received from the
+// Ethereum node, or the zero time if none has been received yet.
+func LastHeadReceivedAt() time.Time {
+	lastHeadAt := atomic.LoadInt64(&lastHeadReceivedAt)
+	if lastHeadAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, lastHeadAt)
+}
+
 // HeadTracker holds and stores the latest block number experienced by this particular node
 // in a thread safe manner. Reconstitutes the last block number from the data
 // store on reboot.
@@ -124,6 +153,7 @@ func (ht *HeadTracker) Save(n *models.Head) error {
 		copy := *n
 		ht.head = &copy
 		ht.headMutex.Unlock()
+		promCurrentHead.Set(float64(n.Number))
 	} else {
 		ht.headMutex.Unlock()
 		msg := fmt.Sprintf("Cannot save new head confirmation %v because it's equal to or less than current head %v with hash %s", n, ht.head, n.Hash.Hex())
@@ -162,6 +192,7 @@ func (ht *HeadTracker) disconnect() {
 
 func (ht *HeadTracker) onNewHead(head *models.Head) {
 	numberHeadsReceived.Inc()
+	atomic.StoreInt64(&lastHeadReceivedAt, time.Now().UnixNano())
 
 	ht.headMutex.Lock()
 	defer ht.headMutex.Unlock()
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+
+	"github.com/pkg/errors"
+)
+
+// HealthComponent is the name of a dependency probed by CheckReadiness.
+type HealthComponent string
+
+const (
+	// HealthDatabase is the database, which must answer a ping.
+	HealthDatabase HealthComponent = "database"
+	// HealthAdvisoryLock is the advisory lock keeping other nodes off the
+	// database, which must still be held.
+	HealthAdvisoryLock HealthComponent = "advisory_lock"
+	// HealthEthereum is the Ethereum node, which must have sent a head
+	// within HEALTH_MAX_HEAD_AGE.
+	HealthEthereum HealthComponent = "ethereum"
+	// HealthKeyStore is the keystore, whose accounts must be unlocked.
+	HealthKeyStore HealthComponent = "keystore"
+	// HealthEthereumQuorum is the agreement of the Ethereum node and the
+	// provider at ETH_QUORUM_URL on the chain, without which transactions
+	// are not broadcast.
+	HealthEthereumQuorum HealthComponent = "ethereum_quorum"
+)
+
+// HealthStatus is the outcome of probing a component. A degraded component
+// is unhealthy, but expected to recover without the node being restarted.
+type HealthStatus struct {
+	Component HealthComponent `json:"component"`
+	Healthy   bool            `json:"healthy"`
+	Degraded  bool            `json:"degraded,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// errDegraded is returned by the probes of components which are recovering.
+type errDegraded struct {
+	error
+}
+
+// CheckReadiness probes the dependencies the node needs to do its work, and
+// returns their statuses along with whether they are all healthy.
+func CheckReadiness(store *strpkg.Store) ([]HealthStatus, bool) {
+	probes := []struct {
+		component HealthComponent
+		probe     func(*strpkg.Store) error
+	}{
+		{HealthDatabase, checkDatabase},
+		{HealthAdvisoryLock, checkAdvisoryLock},
+		{HealthEthereum, checkHeadFreshness},
+		{HealthKeyStore, checkKeyStore},
+		{HealthEthereumQuorum, checkQuorum},
+	}
+
+	ready := true
+	statuses := make([]HealthStatus, 0, len(probes))
+	for _, p := range probes {
+		status := HealthStatus{Component: p.component, Healthy: true}
+		if err := p.probe(store); err != nil {
+			_, status.Degraded = err.(errDegraded)
+			status.Healthy = false
+			status.Error = err.Error()
+			ready = false
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, ready
+}
+
+func checkDatabase(store *strpkg.Store) error {
+	if store.ORM.Reconnecting() {
+		return errDegraded{errors.New("lost the connection to the database, reconnecting")}
+	}
+	return store.ORM.Ping()
+}
+
+func checkAdvisoryLock(store *strpkg.Store) error {
+	if store.ORM.ReadOnly() {
+		return errDegraded{errors.New("lost the lock on the database, the node is read only until restarted")}
+	}
+	held, err := store.ORM.AdvisoryLockHeld()
+	if err != nil {
+		return errors.Wrap(err, "checking advisory lock")
+	}
+	if !held {
+		return errors.New("advisory lock is not held")
+	}
+	return nil
+}
+
+func checkHeadFreshness(store *strpkg.Store) error {
+	maxAge := store.Config.HealthMaxHeadAge()
+	if store.Config.EthereumDisabled() || maxAge.IsInstant() {
+		return nil
+	}
+	lastHeadAt := LastHeadReceivedAt()
+	if lastHeadAt.IsZero() {
+		return errors.New("no head received yet")
+	}
+	if age := time.Since(lastHeadAt); age > maxAge.Duration() {
+		return fmt.Errorf("last head received %s ago, over %s", age.Round(time.Second), maxAge)
+	}
+	return nil
+}
+
+func checkKeyStore(store *strpkg.Store) error {
+	return store.KeyStore.CheckUnlocked()
+}
+
+func checkQuorum(store *strpkg.Store) error {
+	if reason, paused := store.TxManager.BroadcastingPaused(); paused {
+		return errDegraded{fmt.Errorf("Ethereum providers disagree on the chain, transactions are not broadcast: %s", reason)}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+)
+
+// NewJobBundle returns the job along with the bridges, external initiators
+// and secrets it depends on, without their credentials, for it to be
+// imported on another node.
+func NewJobBundle(job models.JobSpec, store *store.Store) (models.JobBundle, error) {
+	jsr := models.NewJobSpecRequestFromJob(job)
+	jsr.Namespace = job.Namespace
+	bundle := models.JobBundle{
+		Job:                jsr,
+		Bridges:            []models.BridgeTypeRequest{},
+		ExternalInitiators: []models.ExternalInitiatorRequest{},
+		Secrets:            []models.SecretRequest{},
+	}
+
+	for _, name := range jobBridges(job) {
+		bt, err := store.FindBridge(name)
+		if err != nil {
+			return bundle, errors.Wrapf(err, "while finding bridge %s", name)
+		}
+		bundle.Bridges = append(bundle.Bridges, models.BridgeTypeRequest{
+			Name:                   bt.Name,
+			URL:                    bt.URL,
+			Mode:                   bt.Mode,
+			Confirmations:          bt.Confirmations,
+			MinimumContractPayment: bt.MinimumContractPayment,
+			CacheTTL:               bt.CacheTTL,
+			ClientCertificate:      bt.ClientCertificate,
+			MinInterval:            bt.MinInterval,
+			Namespace:              bt.Namespace,
+		})
+	}
+	for _, name := range jobExternalInitiators(job) {
+		ei, err := store.FindExternalInitiatorByName(name)
+		if err != nil {
+			return bundle, errors.Wrapf(err, "while finding external initiator %s", name)
+		}
+		bundle.ExternalInitiators = append(bundle.ExternalInitiators, models.ExternalInitiatorRequest{
+			Name:             ei.Name,
+			URL:              ei.URL,
+			RequireSignature: ei.RequireSignature,
+		})
+	}
+	for _, name := range jobSecrets(job) {
+		bundle.Secrets = append(bundle.Secrets, models.SecretRequest{Name: name, Namespace: job.Namespace})
+	}
+	return bundle, nil
+}
+
+// JobBundleImport is the job of a bundle, ready to be added along with the
+// dependencies the node is missing, and the credentials of the bridges and
+// external initiators created for it, in the same order as them.
+type JobBundleImport struct {
+	Job                     models.JobSpec
+	Dependencies            models.JobDependencies
+	BridgeAuthentications   []*models.BridgeTypeAuthentication
+	ExternalInitiatorTokens []*auth.Token
+}
+
+// PrepareJobBundleImport validates the bundle, returning its job, with the
+// variables it references replaced, along with the bridges, external
+// initiators and secrets to create for it. Those the node already has are
+// left as they are, and the values of the secrets it is missing must be given
+// in the bundle. As its dependencies do not exist yet, the job is checked as
+// ValidateJobOffline does, with the bridges, external initiators and secrets
+// it uses looked up in the node and the bundle. It is only fully validated
+// once its dependencies are created, before they are committed.
+func PrepareJobBundleImport(bundle models.JobBundle, store *store.Store) (JobBundleImport, error) {
+	var imported JobBundleImport
+	jsr, err := bundle.JobWithVariables()
+	if err != nil {
+		return imported, models.NewJSONAPIErrorsWith(err.Error())
+	}
+	imported.Job = models.NewJobFromRequest(jsr)
+	fe := models.NewJSONAPIErrors()
+
+	bridgeNamespaces := map[models.TaskType]string{}
+	for i := range bundle.Bridges {
+		btr := &bundle.Bridges[i]
+		if bt, err := store.FindBridge(btr.Name); err == nil {
+			bridgeNamespaces[btr.Name] = bt.Namespace
+			continue
+		} else if errors.Cause(err) != orm.ErrorNotFound {
+			return imported, errors.Wrapf(err, "while finding bridge %s", btr.Name)
+		}
+		bridgeNamespaces[btr.Name] = btr.Namespace
+		if err := ValidateBridgeType(btr, store); err != nil {
+			fe.Merge(err)
+			continue
+		}
+		if err := ValidateBridgeTypeNotExist(btr, store); err != nil {
+			fe.Merge(err)
+			continue
+		}
+		bta, bt, err := models.NewBridgeType(btr)
+		if err != nil {
+			return imported, err
+		}
+		if btr.Auth != nil {
+			if err := bt.SetAuth(btr.Auth); err != nil {
+				return imported, err
+			}
+			bta.AuthType = bt.AuthType
+		}
+		imported.Dependencies.Bridges = append(imported.Dependencies.Bridges, bt)
+		imported.BridgeAuthentications = append(imported.BridgeAuthentications, bta)
+	}
+
+	externalInitiators := map[string]bool{}
+	for i := range bundle.ExternalInitiators {
+		eir := &bundle.ExternalInitiators[i]
+		externalInitiators[strings.ToLower(eir.Name)] = true
+		if _, err := store.FindExternalInitiatorByName(eir.Name); err == nil {
+			continue
+		} else if errors.Cause(err) != orm.ErrorNotFound {
+			return imported, errors.Wrapf(err, "while finding external initiator %s", eir.Name)
+		}
+		if !store.Config.Dev() && !store.Config.FeatureExternalInitiators() {
+			fe.Add(fmt.Sprintf("External initiator %s cannot be created, as the External Initiator feature is disabled by configuration", eir.Name))
+			continue
+		}
+		if err := ValidateExternalInitiator(eir, store); err != nil {
+			fe.Merge(err)
+			continue
+		}
+		token := auth.NewToken()
+		ei, err := models.NewExternalInitiator(token, eir)
+		if err != nil {
+			return imported, err
+		}
+		imported.Dependencies.ExternalInitiators = append(imported.Dependencies.ExternalInitiators, ei)
+		imported.ExternalInitiatorTokens = append(imported.ExternalInitiatorTokens, token)
+	}
+
+	secretValues := map[string]string{}
+	for _, sr := range bundle.Secrets {
+		secretValues[sr.Name] = sr.Value
+	}
+	for _, name := range jobSecrets(imported.Job) {
+		if namespace, err := store.SecretNamespace(name); err == nil {
+			if namespace != imported.Job.Namespace {
+				fe.Add(fmt.Sprintf("Secret %s is not in namespace %q", name, imported.Job.Namespace))
+			}
+			continue
+		} else if errors.Cause(err) != orm.ErrorNotFound {
+			return imported, errors.Wrapf(err, "while finding secret %s", name)
+		}
+		if secretValues[name] == "" {
+			fe.Add(fmt.Sprintf("Secret %s does not exist, and the bundle gives no value for it", name))
+			continue
+		}
+		secret, err := models.NewSecret(models.SecretRequest{
+			Name:      name,
+			Namespace: imported.Job.Namespace,
+			Value:     secretValues[name],
+		})
+		if err != nil {
+			fe.Merge(err)
+			continue
+		}
+		imported.Dependencies.Secrets = append(imported.Dependencies.Secrets, &secret)
+	}
+
+	job := imported.Job
+	if _, _, err := ValidateJobOffline(job, store.Config, models.JobSpecLines{}); err != nil {
+		fe.Merge(err)
+	}
+	for _, name := range jobBridges(job) {
+		namespace, ok := bridgeNamespaces[name]
+		if !ok {
+			bt, err := store.FindBridge(name)
+			if err != nil {
+				fe.Add(fmt.Sprintf("Bridge %s does not exist, and is not in the bundle", name))
+				continue
+			}
+			namespace = bt.Namespace
+		}
+		if namespace != "" && namespace != job.Namespace {
+			fe.Add(fmt.Sprintf("Bridge %s is not in namespace %q", name, job.Namespace))
+		}
+	}
+	for _, name := range jobExternalInitiators(job) {
+		if externalInitiators[strings.ToLower(name)] {
+			continue
+		}
+		if _, err := store.FindExternalInitiatorByName(name); err != nil {
+			fe.Add(fmt.Sprintf("External initiator %s does not exist, and is not in the bundle", name))
+		}
+	}
+	return imported, fe.CoerceEmptyToNil()
+}
+
+// jobBridges returns the names of the bridges the job calls, from its tasks,
+// the sources of its aggregate tasks and its stream initiators.
+func jobBridges(job models.JobSpec) []models.TaskType {
+	var names []models.TaskType
+	seen := map[models.TaskType]bool{}
+	add := func(name models.TaskType) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, initr := range job.InitiatorsFor(models.InitiatorStream) {
+		add(initr.BridgeName)
+	}
+	for _, task := range job.Tasks {
+		ba, builtin, _ := adapters.ForBuiltin(task)
+		if !builtin {
+			add(task.Type)
+			continue
+		}
+		if aggregate, ok := ba.(*adapters.Aggregate); ok {
+			for _, src := range aggregate.Sources {
+				add(src.Bridge)
+			}
+		}
+	}
+	return names
+}
+
+// jobExternalInitiators returns the names of the external initiators which
+// run the job.
+func jobExternalInitiators(job models.JobSpec) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, initr := range job.InitiatorsFor(models.InitiatorExternal) {
+		if name := strings.ToLower(initr.Name); name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, initr.Name)
+		}
+	}
+	return names
+}
+
+// jobSecrets returns the names of the secrets the tasks of the job
+// reference, sorted.
+func jobSecrets(job models.JobSpec) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, task := range job.Tasks {
+		for _, name := range models.SecretReferences(task.Params) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
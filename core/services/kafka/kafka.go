@@ -0,0 +1,224 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Service is the interface encapsulating all functionality needed to trigger
+// job runs from messages published to kafka topics.
+type Service interface {
+	AddJob(models.JobSpec) error
+	RemoveJob(*models.ID)
+	Start() error
+	Stop()
+}
+
+type concreteService struct {
+	store      *store.Store
+	runManager services.RunManager
+	jobs       map[models.ID]*jobConsumers
+	jobsMu     sync.Mutex
+}
+
+// jobConsumers are the goroutines consuming the topics of a single job.
+type jobConsumers struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a service that consumes the topics of every kafka initiator,
+// creating a run of the initiator's job for each message read.
+func New(store *store.Store, runManager services.RunManager) Service {
+	return &concreteService{
+		store:      store,
+		runManager: runManager,
+		jobs:       make(map[models.ID]*jobConsumers),
+	}
+}
+
+func (s *concreteService) Start() error {
+	return s.store.Jobs(func(j *models.JobSpec) bool {
+		if j == nil {
+			logger.Error("received nil job")
+			return true
+		}
+		logger.ErrorIf(s.AddJob(*j), "error adding kafka job")
+		return true
+	}, models.InitiatorKafka)
+}
+
+func (s *concreteService) Stop() {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	for id, consumers := range s.jobs {
+		consumers.cancel()
+		consumers.wg.Wait()
+		delete(s.jobs, id)
+	}
+}
+
+// AddJob starts consuming the topics of the job's kafka initiators, resuming
+// from the offsets recorded the last time they were consumed.
+func (s *concreteService) AddJob(job models.JobSpec) error {
+	initrs := job.InitiatorsFor(models.InitiatorKafka)
+	if len(initrs) == 0 {
+		return nil
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if _, ok := s.jobs[*job.ID]; ok {
+		return errors.Errorf("kafka topics of job %s are already being consumed", job.ID.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumers := &jobConsumers{cancel: cancel}
+	for _, initr := range initrs {
+		offsets, err := s.store.KafkaOffsets(initr.ID)
+		if err != nil {
+			cancel()
+			consumers.wg.Wait()
+			return errors.Wrapf(err, "loading kafka offsets of job %s", job.ID.String())
+		}
+
+		for _, topic := range initr.KafkaTopics {
+			consumers.wg.Add(1)
+			go s.consumeTopic(ctx, &consumers.wg, initr, topic, offsets)
+		}
+	}
+	s.jobs[*job.ID] = consumers
+	return nil
+}
+
+// RemoveJob stops consuming the topics of the job, waiting for any message
+// being handled to finish. Recorded offsets are kept.
+func (s *concreteService) RemoveJob(id *models.ID) {
+	s.jobsMu.Lock()
+	consumers, ok := s.jobs[*id]
+	delete(s.jobs, *id)
+	s.jobsMu.Unlock()
+
+	if ok {
+		consumers.cancel()
+		consumers.wg.Wait()
+	}
+}
+
+// consumeTopic looks up the partitions of a topic, retrying until the brokers
+// can be reached, then consumes each of them.
+func (s *concreteService) consumeTopic(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	initr models.Initiator,
+	topic string,
+	offsets []models.KafkaOffset,
+) {
+	defer wg.Done()
+
+	var partitions []int
+	sleeper := utils.NewBackoffSleeper()
+	for {
+		var err error
+		partitions, err = s.store.KafkaClient.Partitions(topic)
+		if err == nil {
+			break
+		}
+		logger.Warnw("Unable to read partitions of kafka topic", "topic", topic, "job_id", initr.JobSpecID.String(), "error", err)
+		if !sleep(ctx, sleeper) {
+			return
+		}
+	}
+
+	for _, partition := range partitions {
+		offset := store.KafkaLastOffset
+		for _, o := range offsets {
+			if o.Topic == topic && o.Partition == partition {
+				offset = o.Offset
+			}
+		}
+
+		wg.Add(1)
+		go s.consumePartition(ctx, wg, initr, topic, partition, offset)
+	}
+}
+
+// consumePartition creates a run for every message of a topic partition,
+// reconnecting until the context is cancelled.
+//
+// The offset of a message is saved before its run is created, so that a node
+// restarted part way through never triggers a second run for the message.
+func (s *concreteService) consumePartition(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	initr models.Initiator,
+	topic string,
+	partition int,
+	offset int64,
+) {
+	defer wg.Done()
+
+	sleeper := utils.NewBackoffSleeper()
+	for {
+		err := s.store.KafkaClient.Consume(ctx, topic, partition, offset, func(msg store.KafkaMessage) error {
+			next := msg.Offset + 1
+			err := s.store.SaveKafkaOffset(&models.KafkaOffset{
+				InitiatorID: initr.ID,
+				Topic:       topic,
+				Partition:   partition,
+				Offset:      next,
+			})
+			if err != nil {
+				return errors.Wrap(err, "saving kafka offset")
+			}
+			offset = next
+			sleeper.Reset()
+
+			_, err = s.runManager.Create(initr.JobSpecID, &initr, nil, models.NewRunRequest(RequestParams(msg)))
+			logger.ErrorIf(err, "unable to create run for kafka message")
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warnw("Kafka consumer stopped, reconnecting",
+			"topic", topic, "partition", partition, "job_id", initr.JobSpecID.String(), "error", err)
+		if !sleep(ctx, sleeper) {
+			return
+		}
+	}
+}
+
+// sleep waits for the sleeper's next backoff, returning false if the context
+// was cancelled first.
+func sleep(ctx context.Context, sleeper *utils.BackoffSleeper) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(sleeper.After()):
+		return true
+	}
+}
+
+// RequestParams returns the input of the run triggered by a kafka message.
+// Messages holding a JSON object are passed through as is, any other value is
+// passed as a string under the "value" key.
+func RequestParams(msg store.KafkaMessage) models.JSON {
+	if gjson.ValidBytes(msg.Value) && gjson.ParseBytes(msg.Value).IsObject() {
+		return models.JSON{Result: gjson.ParseBytes(msg.Value)}
+	}
+	params, _ := models.JSON{}.Add("value", string(msg.Value))
+	return params
+}
@@ -0,0 +1,109 @@
+package kafka_test
+
+import (
+	"context"
+
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/kafka"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafka_AddJob_TriggersRunsAndSavesOffsets(t *testing.T) {
+	str, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	client := new(mocks.KafkaClient)
+	str.KafkaClient = client
+
+	job := cltest.NewJobWithKafkaInitiator("prices")
+	require.NoError(t, str.CreateJob(&job))
+	initr := job.Initiators[0]
+	require.NoError(t, str.SaveKafkaOffset(&models.KafkaOffset{
+		InitiatorID: initr.ID,
+		Topic:       "prices",
+		Partition:   1,
+		Offset:      41,
+	}))
+
+	client.On("Partitions", "prices").Return([]int{0, 1}, nil)
+	client.On("Consume", mock.Anything, "prices", 0, int64(store.KafkaLastOffset), mock.Anything).
+		Return(func(ctx context.Context, _ string, _ int, _ int64, _ func(store.KafkaMessage) error) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	client.On("Consume", mock.Anything, "prices", 1, int64(41), mock.Anything).
+		Return(func(ctx context.Context, _ string, _ int, _ int64, handler func(store.KafkaMessage) error) error {
+			require.NoError(t, handler(store.KafkaMessage{Topic: "prices", Partition: 1, Offset: 41, Value: []byte(`{"price":"100"}`)}))
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	runManager := new(mocks.RunManager)
+	created := make(chan *models.RunRequest, 1)
+	runManager.On("Create", job.ID, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Run(func(args mock.Arguments) {
+			created <- args.Get(3).(*models.RunRequest)
+		})
+
+	service := kafka.New(str, runManager)
+	require.NoError(t, service.AddJob(job))
+
+	var rr *models.RunRequest
+	cltest.CallbackOrTimeout(t, "run created", func() {
+		rr = <-created
+	})
+	assert.Equal(t, "100", rr.RequestParams.Get("price").String())
+
+	service.RemoveJob(job.ID)
+
+	offsets, err := str.KafkaOffsets(initr.ID)
+	require.NoError(t, err)
+	require.Len(t, offsets, 1)
+	assert.Equal(t, int64(42), offsets[0].Offset)
+
+	client.AssertExpectations(t)
+	runManager.AssertExpectations(t)
+}
+
+func TestKafka_AddJob_IgnoresOtherInitiators(t *testing.T) {
+	str, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	client := new(mocks.KafkaClient)
+	str.KafkaClient = client
+
+	service := kafka.New(str, new(mocks.RunManager))
+	require.NoError(t, service.AddJob(cltest.NewJobWithWebInitiator()))
+
+	client.AssertNotCalled(t, "Partitions", mock.Anything)
+}
+
+func TestKafka_RequestParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"json object", `{"price":"100"}`, `{"price":"100"}`},
+		{"json number", `100`, `{"value":"100"}`},
+		{"plain text", `hello`, `{"value":"hello"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := kafka.RequestParams(store.KafkaMessage{Value: []byte(test.value)})
+			assert.JSONEq(t, test.want, params.String())
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package keeper
+
+func ExportedWork(s Service, head uint64) {
+	s.(*concreteService).work(head)
+}
@@ -0,0 +1,352 @@
+package keeper
+
+import (
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// Service is the interface encapsulating all functionality needed to keep
+// the upkeep contracts of keeper initiators.
+type Service interface {
+	store.HeadTrackable
+	AddJob(models.JobSpec) error
+	RemoveJob(*models.ID)
+	Start() error
+	Stop()
+}
+
+type concreteService struct {
+	store    *store.Store
+	disabled bool
+
+	mu      sync.Mutex
+	upkeeps map[uint32]models.Upkeep
+	jobs    map[models.ID][]uint32
+
+	chHead   chan uint64
+	chStop   chan struct{}
+	chDone   chan struct{}
+	stopOnce sync.Once
+}
+
+// New creates a service that keeps the upkeep contract of each initiator of
+// type InitiatorKeeper of added jobs. On each head, it calls the checkUpkeep
+// method of each contract, and sends a performUpkeep transaction with the
+// data returned to those needing upkeep. A contract is not checked again
+// until its last performUpkeep transaction is confirmed, and its cooldown
+// blocks have passed.
+func New(store *store.Store) Service {
+	if store.Config.EthereumDisabled() {
+		return &concreteService{disabled: true}
+	}
+	return &concreteService{
+		store:   store,
+		upkeeps: make(map[uint32]models.Upkeep),
+		jobs:    make(map[models.ID][]uint32),
+		chHead:  make(chan uint64, 1),
+		chStop:  make(chan struct{}),
+		chDone:  make(chan struct{}),
+	}
+}
+
+// Start starts keeping the upkeeps of existing jobs.
+func (s *concreteService) Start() error {
+	if s.disabled {
+		logger.Keeper.Info("Keeper disabled: skipping start")
+		return nil
+	}
+
+	go s.run()
+
+	return s.store.Jobs(func(j *models.JobSpec) bool {
+		if j == nil {
+			logger.Keeper.Error("received nil job")
+			return true
+		}
+		if err := s.AddJob(*j); err != nil {
+			logger.Keeper.Errorf("error adding keeper job: %v", err)
+		}
+		return true
+	}, models.InitiatorKeeper)
+}
+
+// Stop stops keeping upkeeps, waiting for any check in progress. Stopping it
+// again does nothing.
+func (s *concreteService) Stop() {
+	if s.disabled {
+		logger.Keeper.Info("Keeper disabled: cannot stop")
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.chStop)
+		<-s.chDone
+	})
+}
+
+// AddJob registers the upkeeps of any job initiators of type InitiatorKeeper.
+func (s *concreteService) AddJob(job models.JobSpec) error {
+	if s.disabled {
+		return nil
+	}
+	if job.ID == nil {
+		err := errors.New("received job with nil ID")
+		logger.Keeper.Error(err)
+		return err
+	}
+	initrs := job.InitiatorsFor(models.InitiatorKeeper)
+	if len(initrs) == 0 {
+		return nil
+	}
+
+	var upkeeps []models.Upkeep
+	for _, initr := range initrs {
+		u := models.NewUpkeep(initr, s.store.Config.EthGasLimitDefault())
+		if err := s.store.RegisterUpkeep(&u); err != nil {
+			return errors.Wrap(err, "unable to register upkeep")
+		}
+		upkeeps = append(upkeeps, u)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uint32, len(upkeeps))
+	for i, u := range upkeeps {
+		logger.Keeper.Debugw("Adding upkeep to keeper", "job_id", job.ID.String(), "initr", u.InitiatorID, "address", u.Address.Hex())
+		s.upkeeps[u.InitiatorID] = u
+		ids[i] = u.InitiatorID
+	}
+	s.jobs[*job.ID] = ids
+	return nil
+}
+
+// RemoveJob stops keeping the upkeeps of the job with the given ID.
+func (s *concreteService) RemoveJob(id *models.ID) {
+	if s.disabled {
+		return
+	}
+	if id == nil {
+		logger.Keeper.Warn("nil job ID passed to Keeper#RemoveJob")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, initrID := range s.jobs[*id] {
+		delete(s.upkeeps, initrID)
+	}
+	delete(s.jobs, *id)
+}
+
+// Connect checks the upkeeps at the head connected at.
+func (s *concreteService) Connect(head *models.Head) error {
+	if head != nil {
+		s.OnNewHead(head)
+	}
+	return nil
+}
+
+// Disconnect does nothing, upkeeps waiting until the next head.
+func (s *concreteService) Disconnect() {}
+
+// OnNewHead checks the upkeeps in the background, skipping the heads
+// received while a check is in progress but the latest.
+func (s *concreteService) OnNewHead(head *models.Head) {
+	if s.disabled {
+		return
+	}
+	select {
+	case <-s.chHead:
+	default:
+	}
+	select {
+	case s.chHead <- uint64(head.Number):
+	default:
+	}
+}
+
+func (s *concreteService) run() {
+	defer close(s.chDone)
+	for {
+		select {
+		case head := <-s.chHead:
+			s.work(head)
+		case <-s.chStop:
+			return
+		}
+	}
+}
+
+// work records the confirmation of the performUpkeep transactions sent, then
+// checks the upkeeps which are not waiting on one, nor cooling down.
+func (s *concreteService) work(head uint64) {
+	pending, err := s.confirmPerforms()
+	if err != nil {
+		logger.Keeper.Errorw("Unable to load performUpkeep transactions", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	upkeeps := make([]models.Upkeep, 0, len(s.upkeeps))
+	for _, u := range s.upkeeps {
+		upkeeps = append(upkeeps, u)
+	}
+	s.mu.Unlock()
+
+	for _, u := range upkeeps {
+		if pending[u.ID] || u.CoolingDown(head) || u.LastCheckedBlock >= head {
+			continue
+		}
+		s.check(u, head)
+	}
+}
+
+// confirmPerforms marks the performUpkeep transactions which are now safe as
+// confirmed, returning the IDs of the upkeeps still waiting on one.
+func (s *concreteService) confirmPerforms() (map[uint64]bool, error) {
+	performs, err := s.store.UnconfirmedUpkeepPerforms()
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[uint64]bool)
+	for _, p := range performs {
+		if p.TxHash == nil {
+			sent, err := s.recoverPerform(&p)
+			if err != nil {
+				logger.Keeper.Warnw("Unable to recover performUpkeep transaction", "upkeep", p.UpkeepID, "block", p.BlockNumber, "error", err)
+				pending[p.UpkeepID] = true
+				continue
+			} else if !sent {
+				continue
+			}
+		}
+
+		receipt, state, err := s.store.TxManager.BumpGasUntilSafe(*p.TxHash)
+		if errors.Cause(err) == orm.ErrorNotFound {
+			logger.Keeper.Warnw("performUpkeep transaction no longer exists, not waiting on it", "upkeep", p.UpkeepID, "tx_hash", p.TxHash.Hex())
+		} else if err != nil {
+			logger.Keeper.Warnw("Unable to check performUpkeep transaction", "upkeep", p.UpkeepID, "tx_hash", p.TxHash.Hex(), "error", err)
+			pending[p.UpkeepID] = true
+			continue
+		} else if state != store.Safe {
+			pending[p.UpkeepID] = true
+			continue
+		}
+
+		gasPrice := p.GasPrice
+		if receipt != nil {
+			if attempt, err := s.store.FindTxAttempt(receipt.Hash); err == nil {
+				gasPrice = attempt.GasPrice
+			}
+		}
+		logger.Keeper.ErrorIf(s.store.ConfirmUpkeepPerform(p.ID, gasPrice), "failed to confirm performUpkeep transaction")
+	}
+	return pending, nil
+}
+
+// recoverPerform records the transaction of a performUpkeep transaction
+// which was sent but not recorded, found by its surrogate ID, returning
+// false and deleting it if it was never sent.
+func (s *concreteService) recoverPerform(p *models.UpkeepPerform) (bool, error) {
+	tx, err := s.store.FindTxBySurrogateID(p.SurrogateID())
+	if errors.Cause(err) == orm.ErrorNotFound {
+		return false, s.store.DeleteUpkeepPerform(p.ID)
+	} else if err != nil {
+		return false, err
+	}
+	if err := s.recordSent(p, tx); err != nil {
+		return false, err
+	}
+	logger.Keeper.Infow("Recovered performUpkeep transaction", "upkeep", p.UpkeepID, "tx_hash", tx.Hash.Hex(), "block", p.BlockNumber)
+	return true, nil
+}
+
+// check calls the upkeep's checkUpkeep method, and sends a performUpkeep
+// transaction if it needs upkeep.
+func (s *concreteService) check(u models.Upkeep, head uint64) {
+	needed, performData, err := CheckUpkeep(s.store.TxManager, u.Address, u.CheckData)
+	if err != nil {
+		logger.Keeper.Warnw("Unable to check upkeep", "upkeep", u.ID, "address", u.Address.Hex(), "error", err)
+		return
+	}
+	u.LastCheckedBlock = head
+	if !needed {
+		logger.Keeper.ErrorIf(s.store.SetUpkeepChecked(u.ID, head), "failed to record upkeep check")
+		s.update(u)
+		return
+	}
+
+	if performData == nil {
+		performData = []byte{}
+	}
+	data, err := PerformUpkeepCalldata(performData)
+	if err != nil {
+		logger.Keeper.Errorw("Unable to perform upkeep", "upkeep", u.ID, "address", u.Address.Hex(), "error", err)
+		logger.Keeper.ErrorIf(s.store.SetUpkeepChecked(u.ID, head), "failed to record upkeep check")
+		s.update(u)
+		return
+	}
+
+	// The perform is recorded before its transaction is sent, so that the
+	// upkeep waits on it even if recording the transaction sent fails, and
+	// confirmPerforms finds the transaction by its surrogate ID then.
+	perform := models.UpkeepPerform{
+		UpkeepID:    u.ID,
+		BlockNumber: head,
+		PerformData: performData,
+		GasLimit:    u.GasLimit,
+	}
+	if err := s.store.CreateUpkeepPerform(&perform); err != nil {
+		logger.Keeper.Errorw("Unable to record performUpkeep transaction", "upkeep", u.ID, "error", err)
+		return
+	}
+	tx, err := s.store.TxManager.CreateTxWithGas(null.StringFrom(perform.SurrogateID()), u.Address, data, nil, u.GasLimit)
+	if err != nil {
+		logger.Keeper.Errorw("Unable to perform upkeep", "upkeep", u.ID, "address", u.Address.Hex(), "error", err)
+		return
+	}
+	if err := s.recordSent(&perform, tx); err != nil {
+		logger.Keeper.Errorw("Unable to record performUpkeep transaction", "upkeep", u.ID, "tx_hash", tx.Hash.Hex(), "error", err)
+		return
+	}
+	logger.Keeper.Infow("Performing upkeep", "upkeep", u.ID, "address", u.Address.Hex(), "tx_hash", tx.Hash.Hex(), "block", head)
+}
+
+// recordSent records the transaction a performUpkeep transaction was sent
+// with, counting it in the account of the upkeep kept.
+func (s *concreteService) recordSent(p *models.UpkeepPerform, tx *models.Tx) error {
+	p.TxHash = &tx.Hash
+	p.GasLimit = tx.GasLimit
+	p.GasPrice = tx.GasPrice
+	if err := s.store.SetUpkeepPerformTx(p); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for initrID, u := range s.upkeeps {
+		if u.ID == p.UpkeepID {
+			u.LastCheckedBlock = p.BlockNumber
+			u.LastPerformedBlock = p.BlockNumber
+			u.PerformCount++
+			s.upkeeps[initrID] = u
+			promKeeperUpkeepsPerformed.WithLabelValues(u.JobSpecID.String()).Inc()
+		}
+	}
+	return nil
+}
+
+// update replaces the upkeep kept, unless its job was removed meanwhile.
+func (s *concreteService) update(u models.Upkeep) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.upkeeps[u.InitiatorID]; ok {
+		s.upkeeps[u.InitiatorID] = u
+	}
+}
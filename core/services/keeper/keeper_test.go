@@ -0,0 +1,142 @@
+package keeper_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/keeper"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// newKeeper returns a keeper of a job with one keeper initiator, whose
+// upkeep always needs performing, and the upkeep.
+func newKeeper(t *testing.T, store *strpkg.Store, txm *mocks.TxManager) (keeper.Service, models.Upkeep) {
+	address := cltest.NewAddress()
+	job := cltest.NewJobWithKeeperInitiator(address)
+	require.NoError(t, store.CreateJob(&job))
+	svc := keeper.New(store)
+	require.NoError(t, svc.AddJob(job))
+	upkeep := models.NewUpkeep(job.Initiators[0], store.Config.EthGasLimitDefault())
+	require.NoError(t, store.RegisterUpkeep(&upkeep))
+
+	boolType, err := abi.NewType("bool", "", nil)
+	require.NoError(t, err)
+	bytesType, err := abi.NewType("bytes", "", nil)
+	require.NoError(t, err)
+	result, err := abi.Arguments{{Type: boolType}, {Type: bytesType}}.Pack(true, []byte{4, 5})
+	require.NoError(t, err)
+	txm.On("Call", mock.Anything, "eth_call", mock.Anything, "latest").Maybe().Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*hexutil.Bytes) = result
+	})
+	return svc, upkeep
+}
+
+func TestKeeper_Work_PerformsUpkeepOnce(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	store.TxManager = txm
+	svc, upkeep := newKeeper(t, store, txm)
+
+	tx := &models.Tx{Hash: cltest.NewHash(), GasLimit: upkeep.GasLimit, GasPrice: utils.NewBig(big.NewInt(20))}
+	txm.On("CreateTxWithGas", null.StringFrom(fmt.Sprintf("upkeep-%d-10", upkeep.ID)), upkeep.Address, mock.Anything, (*big.Int)(nil), upkeep.GasLimit).
+		Return(tx, nil).Once()
+	keeper.ExportedWork(svc, 10)
+
+	performs, err := store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	require.Len(t, performs, 1)
+	require.NotNil(t, performs[0].TxHash)
+	assert.Equal(t, tx.Hash, *performs[0].TxHash)
+	assert.Equal(t, tx.GasPrice, performs[0].GasPrice)
+
+	// The upkeep waits on the transaction, not being performed again
+	txm.On("BumpGasUntilSafe", tx.Hash).Return(nil, strpkg.Unconfirmed, nil).Once()
+	keeper.ExportedWork(svc, 11)
+
+	txm.On("BumpGasUntilSafe", tx.Hash).Return(nil, strpkg.Safe, nil).Once()
+	keeper.ExportedWork(svc, 12)
+	performs, err = store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	assert.Len(t, performs, 0)
+
+	registered := upkeep
+	require.NoError(t, store.RegisterUpkeep(&registered))
+	assert.Equal(t, uint64(1), registered.PerformCount)
+	assert.Equal(t, uint64(10), registered.LastPerformedBlock)
+	txm.AssertExpectations(t)
+}
+
+func TestKeeper_Work_RecoversPerformSentButNotRecorded(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	store.TxManager = txm
+	svc, upkeep := newKeeper(t, store, txm)
+
+	// As if the node stopped after sending the transaction, but before
+	// recording it sent
+	perform := models.UpkeepPerform{UpkeepID: upkeep.ID, BlockNumber: 10, PerformData: []byte{4, 5}, GasLimit: upkeep.GasLimit}
+	require.NoError(t, store.CreateUpkeepPerform(&perform))
+	tx := cltest.NewTransaction(0)
+	tx.SurrogateID = null.StringFrom(perform.SurrogateID())
+	_, err := store.CreateTx(tx)
+	require.NoError(t, err)
+
+	txm.On("BumpGasUntilSafe", tx.Hash).Return(nil, strpkg.Unconfirmed, nil).Once()
+	keeper.ExportedWork(svc, 11)
+
+	performs, err := store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	require.Len(t, performs, 1)
+	require.NotNil(t, performs[0].TxHash)
+	assert.Equal(t, tx.Hash, *performs[0].TxHash)
+	txm.AssertNotCalled(t, "CreateTxWithGas", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	txm.AssertExpectations(t)
+}
+
+func TestKeeper_Work_PerformsAgainWhenNeverSent(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	store.TxManager = txm
+	svc, upkeep := newKeeper(t, store, txm)
+
+	txm.On("CreateTxWithGas", null.StringFrom(fmt.Sprintf("upkeep-%d-10", upkeep.ID)), upkeep.Address, mock.Anything, (*big.Int)(nil), upkeep.GasLimit).
+		Return(nil, errors.New("no keys")).Once()
+	keeper.ExportedWork(svc, 10)
+
+	performs, err := store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	require.Len(t, performs, 1)
+	assert.Nil(t, performs[0].TxHash)
+
+	tx := &models.Tx{Hash: cltest.NewHash(), GasLimit: upkeep.GasLimit, GasPrice: utils.NewBig(big.NewInt(20))}
+	txm.On("CreateTxWithGas", null.StringFrom(fmt.Sprintf("upkeep-%d-11", upkeep.ID)), upkeep.Address, mock.Anything, (*big.Int)(nil), upkeep.GasLimit).
+		Return(tx, nil).Once()
+	keeper.ExportedWork(svc, 11)
+
+	performs, err = store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	require.Len(t, performs, 1)
+	assert.Equal(t, uint64(11), performs[0].BlockNumber)
+	require.NotNil(t, performs[0].TxHash)
+	assert.Equal(t, tx.Hash, *performs[0].TxHash)
+	txm.AssertExpectations(t)
+}
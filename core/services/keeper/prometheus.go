@@ -0,0 +1,16 @@
+package keeper
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	promKeeperUpkeepsPerformed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "keeper_upkeeps_performed_total",
+			Help: "The number of performUpkeep transactions this node has sent",
+		},
+		[]string{"job_spec_id"},
+	)
+)
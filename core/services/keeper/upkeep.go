@@ -0,0 +1,53 @@
+package keeper
+
+import (
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// upkeepABI is the ABI of the checkUpkeep and performUpkeep methods of an
+// upkeep contract.
+const upkeepABI = `[{"inputs":[{"internalType":"bytes","name":"checkData","type":"bytes"}],"name":"checkUpkeep","outputs":[{"internalType":"bool","name":"upkeepNeeded","type":"bool"},{"internalType":"bytes","name":"performData","type":"bytes"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"bytes","name":"performData","type":"bytes"}],"name":"performUpkeep","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+var parsedUpkeepABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(upkeepABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// CheckUpkeep calls the checkUpkeep method of the upkeep contract at address
+// with checkData, returning whether it needs upkeep, and the data to perform
+// it with.
+func CheckUpkeep(client eth.Client, address common.Address, checkData []byte) (bool, []byte, error) {
+	data, err := parsedUpkeepABI.Pack("checkUpkeep", checkData)
+	if err != nil {
+		return false, nil, err
+	}
+	var result hexutil.Bytes
+	err = client.Call(&result, "eth_call", eth.CallArgs{To: address, Data: data}, "latest")
+	if err != nil {
+		return false, nil, errors.Wrap(err, "unable to call checkUpkeep")
+	}
+	var check struct {
+		UpkeepNeeded bool
+		PerformData  []byte
+	}
+	if err := parsedUpkeepABI.Unpack(&check, "checkUpkeep", result); err != nil {
+		return false, nil, errors.Wrap(err, "unable to decode checkUpkeep")
+	}
+	return check.UpkeepNeeded, check.PerformData, nil
+}
+
+// PerformUpkeepCalldata returns the calldata of a call to the performUpkeep
+// method of an upkeep contract with performData.
+func PerformUpkeepCalldata(performData []byte) ([]byte, error) {
+	return parsedUpkeepABI.Pack("performUpkeep", performData)
+}
@@ -0,0 +1,49 @@
+package keeper_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/keeper"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUpkeep(t *testing.T) {
+	address := common.HexToAddress("0x3cCad4715152693fE3BC4460591e3D3Fbd071b42")
+	checkData := []byte{1, 2, 3}
+	performData := []byte{4, 5}
+
+	boolType, err := abi.NewType("bool", "", nil)
+	require.NoError(t, err)
+	bytesType, err := abi.NewType("bytes", "", nil)
+	require.NoError(t, err)
+	result, err := abi.Arguments{{Type: boolType}, {Type: bytesType}}.Pack(true, performData)
+	require.NoError(t, err)
+
+	client := new(mocks.Client)
+	client.On("Call", mock.Anything, "eth_call", mock.MatchedBy(func(args eth.CallArgs) bool {
+		return args.To == address
+	}), "latest").Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*hexutil.Bytes) = result
+	})
+
+	needed, data, err := keeper.CheckUpkeep(client, address, checkData)
+	require.NoError(t, err)
+	assert.True(t, needed)
+	assert.Equal(t, performData, data)
+	client.AssertExpectations(t)
+}
+
+func TestPerformUpkeepCalldata(t *testing.T) {
+	data, err := keeper.PerformUpkeepCalldata([]byte{4, 5})
+	require.NoError(t, err)
+	// performUpkeep(bytes)
+	assert.Equal(t, []byte{0x45, 0x85, 0xe3, 0x3b}, data[:4])
+}
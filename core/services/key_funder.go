@@ -0,0 +1,192 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// keyFundingWindow is the window KEY_FUNDING_MAX_PER_DAY_WEI limits the ETH
+// sent in.
+const keyFundingWindow = 24 * time.Hour
+
+// KeyFunder sends ETH from the key at KEY_FUNDING_ADDRESS to the node's other
+// keys when their balances drop below KEY_FUNDING_THRESHOLD_WEI, so that they
+// can keep paying for their transactions. A key is not funded again until
+// KEY_FUNDING_COOLDOWN has passed, and no more than
+// KEY_FUNDING_MAX_PER_DAY_WEI is sent in any 24 hours. Every transfer is
+// recorded in the audit log of key fundings before it is sent, and marked
+// failed there if it could not be. The funding key sends no job's
+// transactions.
+type KeyFunder struct {
+	store    *store.Store
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewKeyFunder returns a KeyFunder funding the keys of store.
+func NewKeyFunder(store *store.Store) *KeyFunder {
+	return &KeyFunder{
+		store: store,
+		done:  make(chan struct{}),
+	}
+}
+
+// Start checks the balances of the node's keys every
+// KEY_FUNDING_CHECK_INTERVAL until stopped. It does nothing if
+// KEY_FUNDING_ADDRESS is not set, or if the interval is zero.
+func (kf *KeyFunder) Start() error {
+	config := kf.store.Config
+	if config.KeyFundingAddress() == nil || config.KeyFundingCheckInterval().IsInstant() {
+		return nil
+	}
+
+	kf.wg.Add(1)
+	go func() {
+		defer kf.wg.Done()
+		for {
+			select {
+			case <-kf.done:
+				return
+			case <-kf.store.Clock.After(config.KeyFundingCheckInterval().Duration()):
+				logger.ErrorIf(kf.Fund(), "failed to fund keys")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops checking the balances of the node's keys, waiting for any check
+// in progress. Stopping it again does nothing.
+func (kf *KeyFunder) Stop() {
+	kf.stopOnce.Do(func() {
+		close(kf.done)
+		kf.wg.Wait()
+	})
+}
+
+// Fund sends ETH to the keys whose balances are below the threshold now,
+// returning errors for those it could not fund.
+func (kf *KeyFunder) Fund() error {
+	config := kf.store.Config
+	funder := config.KeyFundingAddress()
+	if funder == nil {
+		return nil
+	}
+
+	workers, err := kf.workers(*funder)
+	if err != nil {
+		return err
+	}
+
+	threshold := config.KeyFundingThresholdWei()
+	amount := config.KeyFundingAmountWei()
+	maxPerDay := config.KeyFundingMaxPerDayWei()
+	cooldown := config.KeyFundingCooldown().Duration()
+
+	// Fundings are looked back on over the cooldown too, for it to be
+	// enforced when longer than the window of the limit
+	now := kf.store.Clock.Now()
+	windowStart := now.Add(-keyFundingWindow)
+	since := windowStart
+	if now.Add(-cooldown).Before(since) {
+		since = now.Add(-cooldown)
+	}
+	recent, err := kf.store.KeyFundingsSince(since)
+	if err != nil {
+		return errors.Wrap(err, "while loading recent key fundings")
+	}
+	sent := new(big.Int)
+	lastFunded := map[common.Address]time.Time{}
+	for _, funding := range recent {
+		if !funding.CreatedAt.Before(windowStart) {
+			sent.Add(sent, funding.Amount.ToInt())
+		}
+		lastFunded[funding.To] = funding.CreatedAt
+	}
+
+	var merr error
+	for _, worker := range workers {
+		if last, ok := lastFunded[worker]; ok && now.Before(last.Add(cooldown)) {
+			continue
+		}
+		balance, err := kf.store.TxManager.GetEthBalance(worker)
+		if err != nil {
+			merr = multierr.Append(merr, errors.Wrapf(err, "while getting ETH balance of %s", worker.Hex()))
+			continue
+		}
+		if balance.ToInt().Cmp(threshold) >= 0 {
+			continue
+		}
+		if new(big.Int).Add(sent, amount).Cmp(maxPerDay) > 0 {
+			merr = multierr.Append(merr, fmt.Errorf(
+				"not funding %s, whose balance is %s ETH, as %s ETH has been sent to keys in the last %s, and KEY_FUNDING_MAX_PER_DAY_WEI is %s",
+				worker.Hex(), balance, (*assets.Eth)(sent), keyFundingWindow, maxPerDay,
+			))
+			break
+		}
+
+		// The funding is recorded before it is sent, so that ETH is never
+		// sent without counting towards the limit
+		funding := models.NewKeyFunding(*funder, worker, (*utils.Big)(amount), (*utils.Big)(balance))
+		if err := kf.store.CreateKeyFunding(&funding); err != nil {
+			return multierr.Append(merr, errors.Wrapf(err, "while recording funding of %s, not funding any more keys", worker.Hex()))
+		}
+		tx, err := kf.store.TxManager.CreateTxWithEth(*funder, worker, (*assets.Eth)(amount))
+		if err != nil {
+			merr = multierr.Append(merr, errors.Wrapf(err, "while funding %s", worker.Hex()))
+			if err := kf.store.FailKeyFunding(&funding, err); err != nil {
+				return multierr.Append(merr, errors.Wrapf(err, "while marking unsent funding of %s failed, not funding any more keys", worker.Hex()))
+			}
+			continue
+		}
+		sent.Add(sent, amount)
+		if err := kf.store.SetKeyFundingTx(&funding, tx); err != nil {
+			merr = multierr.Append(merr, errors.Wrapf(err, "while recording tx %s of funding of %s", tx.Hash.Hex(), worker.Hex()))
+		}
+		logger.Infow("Funded key",
+			"from", funder.Hex(),
+			"to", worker.Hex(),
+			"balance", balance.String(),
+			"amount", (*assets.Eth)(amount).String(),
+			"txHash", tx.Hash.Hex(),
+		)
+	}
+	return merr
+}
+
+// workers returns the addresses of the keys the funder funds, which are the
+// node's keys other than the funder itself and retired keys.
+func (kf *KeyFunder) workers(funder common.Address) ([]common.Address, error) {
+	keys, err := kf.store.Keys()
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading keys")
+	}
+
+	var workers []common.Address
+	funderFound := false
+	for _, key := range keys {
+		address := key.Address.Address()
+		if address == funder {
+			funderFound = !key.RetiredAt.Valid
+		} else if !key.RetiredAt.Valid {
+			workers = append(workers, address)
+		}
+	}
+	if !funderFound {
+		return nil, fmt.Errorf("KEY_FUNDING_ADDRESS %s is not one of the node's active keys", funder.Hex())
+	}
+	return workers, nil
+}
@@ -0,0 +1,190 @@
+package services_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupKeyFunder returns a store whose first key funds the others, which
+// are returned after it.
+func setupKeyFunder(t *testing.T, keys int) (*strpkg.Store, *mocks.TxManager, []common.Address, func()) {
+	store, cleanup := cltest.NewStore(t)
+	var addresses []common.Address
+	for i := 0; i < keys; i++ {
+		account, err := store.KeyStore.NewAccount(cltest.Password)
+		require.NoError(t, err)
+		addresses = append(addresses, account.Address)
+	}
+	require.NoError(t, store.SyncDiskKeyStoreToDB())
+
+	store.Config.Set("KEY_FUNDING_ADDRESS", addresses[0].Hex())
+	store.Config.Set("KEY_FUNDING_THRESHOLD_WEI", "100")
+	store.Config.Set("KEY_FUNDING_AMOUNT_WEI", "500")
+	store.Config.Set("KEY_FUNDING_MAX_PER_DAY_WEI", "1000")
+	store.Config.Set("KEY_FUNDING_COOLDOWN", "1h")
+
+	txm := new(mocks.TxManager)
+	store.TxManager = txm
+	return store, txm, addresses, cleanup
+}
+
+func fundingTx(t *testing.T, store *strpkg.Store, from, to common.Address, nonce uint64, amount int64) *models.Tx {
+	tx := cltest.CreateTxWithNonceGasPriceAndRecipient(t, store, from, to, 0, nonce, 1)
+	tx.Value = utils.NewBig(big.NewInt(amount))
+	return tx
+}
+
+func TestKeyFunder_Fund(t *testing.T) {
+	t.Parallel()
+
+	store, txm, addresses, cleanup := setupKeyFunder(t, 3)
+	defer cleanup()
+	funder, low, high := addresses[0], addresses[1], addresses[2]
+
+	tx := fundingTx(t, store, funder, low, 0, 500)
+	txm.On("GetEthBalance", low).Return(assets.NewEth(99), nil).Once()
+	txm.On("GetEthBalance", high).Return(assets.NewEth(100), nil).Twice()
+	txm.On("CreateTxWithEth", funder, low, assets.NewEth(500)).Return(tx, nil).Once()
+
+	kf := services.NewKeyFunder(store)
+	require.NoError(t, kf.Fund())
+
+	fundings, count, err := store.KeyFundings(0, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	assert.Equal(t, funder, fundings[0].From)
+	assert.Equal(t, low, fundings[0].To)
+	assert.Equal(t, "500", fundings[0].Amount.String())
+	assert.Equal(t, "99", fundings[0].Balance.String())
+	require.NotNil(t, fundings[0].TxHash)
+	assert.Equal(t, tx.Hash, *fundings[0].TxHash)
+
+	// The funded key is cooling down, and is not checked again
+	require.NoError(t, kf.Fund())
+	txm.AssertExpectations(t)
+}
+
+func TestKeyFunder_Fund_MaxPerDay(t *testing.T) {
+	t.Parallel()
+
+	store, txm, addresses, cleanup := setupKeyFunder(t, 4)
+	defer cleanup()
+	store.Config.Set("KEY_FUNDING_MAX_PER_DAY_WEI", "1200")
+	funder := addresses[0]
+
+	var funded []common.Address
+	for i, worker := range addresses[1:] {
+		worker := worker
+		txm.On("GetEthBalance", worker).Return(assets.NewEth(0), nil).Maybe()
+		txm.On("CreateTxWithEth", funder, worker, assets.NewEth(500)).
+			Return(fundingTx(t, store, funder, worker, uint64(i), 500), nil).
+			Run(func(_ mock.Arguments) { funded = append(funded, worker) }).
+			Maybe()
+	}
+
+	kf := services.NewKeyFunder(store)
+	err := kf.Fund()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "KEY_FUNDING_MAX_PER_DAY_WEI is 1200")
+	assert.Len(t, funded, 2)
+
+	_, count, err := store.KeyFundings(0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// The limit still applies to the key left unfunded
+	err = kf.Fund()
+	require.Error(t, err)
+	assert.Len(t, funded, 2)
+}
+
+func TestKeyFunder_Fund_CooldownLongerThanDay(t *testing.T) {
+	t.Parallel()
+
+	store, txm, addresses, cleanup := setupKeyFunder(t, 2)
+	defer cleanup()
+	store.Config.Set("KEY_FUNDING_COOLDOWN", "72h")
+	funder, worker := addresses[0], addresses[1]
+
+	funding := models.NewKeyFunding(funder, worker, utils.NewBig(big.NewInt(500)), utils.NewBig(big.NewInt(0)))
+	funding.CreatedAt = time.Now().Add(-48 * time.Hour)
+	require.NoError(t, store.CreateKeyFunding(&funding))
+
+	// The key funded two days ago is still cooling down
+	require.NoError(t, services.NewKeyFunder(store).Fund())
+	txm.AssertExpectations(t)
+}
+
+func TestKeyFunder_Fund_SendFailed(t *testing.T) {
+	t.Parallel()
+
+	store, txm, addresses, cleanup := setupKeyFunder(t, 2)
+	defer cleanup()
+	funder, worker := addresses[0], addresses[1]
+
+	txm.On("GetEthBalance", worker).Return(assets.NewEth(0), nil).Once()
+	txm.On("CreateTxWithEth", funder, worker, assets.NewEth(500)).
+		Run(func(_ mock.Arguments) {
+			// The funding is recorded before it is sent
+			_, count, err := store.KeyFundings(0, 10)
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+		}).
+		Return(nil, errors.New("boom")).Once()
+
+	err := services.NewKeyFunder(store).Fund()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+
+	fundings, count, err := store.KeyFundings(0, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	assert.True(t, fundings[0].FailedAt.Valid)
+	assert.Equal(t, "boom", fundings[0].Error.String)
+	assert.Nil(t, fundings[0].TxHash)
+
+	// Failed fundings send no ETH, so do not hold the key back
+	txm.On("GetEthBalance", worker).Return(assets.NewEth(0), nil).Once()
+	txm.On("CreateTxWithEth", funder, worker, assets.NewEth(500)).Return(nil, errors.New("boom")).Once()
+	require.Error(t, services.NewKeyFunder(store).Fund())
+	txm.AssertExpectations(t)
+}
+
+func TestKeyFunder_Fund_UnknownFunder(t *testing.T) {
+	t.Parallel()
+
+	store, txm, _, cleanup := setupKeyFunder(t, 2)
+	defer cleanup()
+	store.Config.Set("KEY_FUNDING_ADDRESS", cltest.NewAddress().Hex())
+
+	err := services.NewKeyFunder(store).Fund()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not one of the node's active keys")
+	txm.AssertExpectations(t)
+}
+
+func TestKeyFunder_Fund_Disabled(t *testing.T) {
+	t.Parallel()
+
+	store, txm, _, cleanup := setupKeyFunder(t, 2)
+	defer cleanup()
+	store.Config.Set("KEY_FUNDING_ADDRESS", "")
+
+	assert.NoError(t, services.NewKeyFunder(store).Fund())
+	txm.AssertExpectations(t)
+}
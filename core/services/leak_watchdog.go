@@ -0,0 +1,158 @@
+package services
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	promLeakWatchdogCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "leak_watchdog_resources",
+		Help: "The number of goroutines, database connections and log subscriptions last sampled, by resource",
+	},
+		[]string{"resource"},
+	)
+	promLeakWatchdogGrowth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "leak_watchdog_growth",
+		Help: "How much a resource grew over the samples of the window if it grew in every one of them, or zero, by resource",
+	},
+		[]string{"resource"},
+	)
+)
+
+// LeakWatchdogResource is a count of things the LeakWatchdog samples, which
+// should not grow without bound.
+type LeakWatchdogResource struct {
+	Name  string
+	Count func() int
+}
+
+// LeakWatchdog samples the number of goroutines, database connections and
+// log subscriptions every LEAK_WATCHDOG_INTERVAL, and warns when one of them
+// has grown at every sample of the last LEAK_WATCHDOG_WINDOW, as leaks only
+// show otherwise when the node runs out of memory. The counts and their
+// growth are exposed as the leak_watchdog_resources and leak_watchdog_growth
+// metrics.
+type LeakWatchdog struct {
+	store     *store.Store
+	resources []LeakWatchdogResource
+	samples   map[string][]int
+	warned    map[string]int
+	done      chan struct{}
+	wg        sync.WaitGroup
+	stopOnce  sync.Once
+}
+
+// NewLeakWatchdog returns a LeakWatchdog sampling the resources of the node.
+func NewLeakWatchdog(store *store.Store) *LeakWatchdog {
+	return NewLeakWatchdogWithResources(store, []LeakWatchdogResource{
+		{"goroutines", runtime.NumGoroutine},
+		{"db_connections", store.ORM.OpenConnections},
+		{"log_subscriptions", func() int {
+			return int(atomic.LoadInt64(&numberManagedSubscriptions))
+		}},
+	})
+}
+
+// NewLeakWatchdogWithResources returns a LeakWatchdog sampling the given
+// resources.
+func NewLeakWatchdogWithResources(store *store.Store, resources []LeakWatchdogResource) *LeakWatchdog {
+	return &LeakWatchdog{
+		store:     store,
+		resources: resources,
+		samples:   make(map[string][]int),
+		warned:    make(map[string]int),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start samples the resources every LEAK_WATCHDOG_INTERVAL until stopped, or
+// does nothing if the interval is zero.
+func (w *LeakWatchdog) Start() error {
+	interval := w.store.Config.LeakWatchdogInterval().Duration()
+	if interval == 0 {
+		return nil
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-w.store.Clock.After(interval):
+				w.Sample()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sampling the resources. Stopping it again does nothing.
+func (w *LeakWatchdog) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+	})
+}
+
+// Sample counts the resources, and returns the names of those which have
+// grown at every sample of the window, warning of them. A resource is warned
+// of again only once it has grown past the count it was last warned of.
+func (w *LeakWatchdog) Sample() []string {
+	var growing []string
+	window := int(w.store.Config.LeakWatchdogWindow())
+	for _, r := range w.resources {
+		count := r.Count()
+		promLeakWatchdogCount.WithLabelValues(r.Name).Set(float64(count))
+
+		samples := append(w.samples[r.Name], count)
+		if len(samples) > window+1 {
+			samples = samples[len(samples)-window-1:]
+		}
+		w.samples[r.Name] = samples
+
+		growth := growthOver(samples, window)
+		promLeakWatchdogGrowth.WithLabelValues(r.Name).Set(float64(growth))
+		if growth == 0 {
+			delete(w.warned, r.Name)
+			continue
+		}
+		growing = append(growing, r.Name)
+		if warned, ok := w.warned[r.Name]; ok && count <= warned {
+			continue
+		}
+		w.warned[r.Name] = count
+		logger.Warnw("Resource has grown at every sample, it may be leaking",
+			"resource", r.Name,
+			"count", count,
+			"growth", growth,
+			"samples", window,
+			"interval", w.store.Config.LeakWatchdogInterval(),
+		)
+	}
+	return growing
+}
+
+// growthOver returns how much the samples grew over the last window of them
+// if every one of them was more than the one before, or zero.
+func growthOver(samples []int, window int) int {
+	if window == 0 || len(samples) < window+1 {
+		return 0
+	}
+	samples = samples[len(samples)-window-1:]
+	for i := 1; i < len(samples); i++ {
+		if samples[i] <= samples[i-1] {
+			return 0
+		}
+	}
+	return samples[len(samples)-1] - samples[0]
+}
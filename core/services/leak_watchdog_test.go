@@ -0,0 +1,38 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeakWatchdog_Sample(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("LEAK_WATCHDOG_WINDOW", 3)
+
+	var leaking, steady int
+	steadyCounts := []int{5, 6, 7, 6, 7, 8, 9}
+	watchdog := services.NewLeakWatchdogWithResources(store, []services.LeakWatchdogResource{
+		{Name: "leaking", Count: func() int { leaking++; return leaking }},
+		{Name: "steady", Count: func() int { steady++; return steadyCounts[steady-1] }},
+	})
+
+	expected := [][]string{
+		nil,
+		nil,
+		nil,
+		{"leaking"},
+		{"leaking"},
+		{"leaking"},
+		{"leaking", "steady"},
+	}
+	for i, want := range expected {
+		assert.Equal(t, want, watchdog.Sample(), "sample %d", i)
+	}
+}
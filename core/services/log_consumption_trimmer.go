@@ -0,0 +1,52 @@
+package services
+
+import (
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// LogConsumptionTrimmer deletes, on every head, the records of the logs
+// consumed more than LOG_CONSUMPTION_RETENTION_DEPTH blocks behind it, which
+// no reorg is expected to deliver again.
+type LogConsumptionTrimmer struct {
+	store *store.Store
+}
+
+// NewLogConsumptionTrimmer returns a new LogConsumptionTrimmer.
+func NewLogConsumptionTrimmer(store *store.Store) *LogConsumptionTrimmer {
+	return &LogConsumptionTrimmer{store: store}
+}
+
+// Connect trims the records when the node connects to the chain.
+func (t *LogConsumptionTrimmer) Connect(head *models.Head) error {
+	if head == nil {
+		return nil
+	}
+	return t.Trim(uint64(head.Number))
+}
+
+// Disconnect does nothing.
+func (t *LogConsumptionTrimmer) Disconnect() {}
+
+// OnNewHead trims the records on every head.
+func (t *LogConsumptionTrimmer) OnNewHead(head *models.Head) {
+	logger.ErrorIf(t.Trim(uint64(head.Number)), "failed to trim log consumptions")
+}
+
+// Trim deletes the records of the logs consumed more than the retention
+// depth blocks behind the head given, unless the depth is 0.
+func (t *LogConsumptionTrimmer) Trim(head uint64) error {
+	depth := t.store.Config.LogConsumptionRetentionDepth()
+	if depth == 0 || head <= depth {
+		return nil
+	}
+	trimmed, err := t.store.TrimLogConsumptions(head - depth)
+	if err != nil {
+		return err
+	}
+	if trimmed > 0 {
+		logger.Debugw("Trimmed log consumptions", "count", trimmed, "before_block", head-depth)
+	}
+	return nil
+}
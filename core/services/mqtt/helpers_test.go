@@ -0,0 +1,10 @@
+package mqtt
+
+import (
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+func ExportedSetClientFactory(s Service, newClient func(*paho.ClientOptions) paho.Client) {
+	impl := s.(*concreteService)
+	impl.newClient = newClient
+}
@@ -0,0 +1,258 @@
+package mqtt
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// dedupeWindow is how long a consumed message is remembered for, and so
+	// how long after a message a redelivery of it is still dropped. Brokers
+	// redeliver unacknowledged messages once the node reconnects, which may
+	// be long after they were first delivered, so the window spans outages of
+	// up to an hour. Message IDs are recycled, but a message is only mistaken
+	// for a redelivery within it if its topic and payload are the same too.
+	dedupeWindow = time.Hour
+	// subscribeTimeout bounds how long to wait for the broker to acknowledge
+	// a subscription.
+	subscribeTimeout = 30 * time.Second
+	// disconnectQuiesce is how long, in milliseconds, a client is given to
+	// finish in flight work when disconnecting.
+	disconnectQuiesce = 250
+)
+
+// Service is the interface encapsulating all functionality needed to trigger
+// job runs from messages received from mqtt brokers.
+type Service interface {
+	AddJob(models.JobSpec) error
+	RemoveJob(*models.ID)
+	Start() error
+	Stop()
+}
+
+type concreteService struct {
+	store      *store.Store
+	runManager services.RunManager
+	newClient  func(*paho.ClientOptions) paho.Client
+	jobs       map[models.ID][]*subscription
+	jobsMu     sync.Mutex
+	chStop     chan struct{}
+	stopOnce   sync.Once
+	wg         sync.WaitGroup
+}
+
+// subscription is the connection of a single mqtt initiator to its broker.
+type subscription struct {
+	client paho.Client
+	chStop chan struct{}
+	chDone chan struct{}
+}
+
+// New creates a service that connects every mqtt initiator to its broker,
+// creating a run of the initiator's job for each message received.
+func New(store *store.Store, runManager services.RunManager) Service {
+	return &concreteService{
+		store:      store,
+		runManager: runManager,
+		newClient:  paho.NewClient,
+		jobs:       make(map[models.ID][]*subscription),
+		chStop:     make(chan struct{}),
+	}
+}
+
+// Start connects the mqtt initiators of every job to their brokers, and
+// forgets the messages consumed longer than the dedupe window ago every
+// window until stopped.
+func (s *concreteService) Start() error {
+	s.wg.Add(1)
+	go s.pruneConsumptions()
+
+	return s.store.Jobs(func(j *models.JobSpec) bool {
+		if j == nil {
+			logger.Error("received nil job")
+			return true
+		}
+		logger.ErrorIf(s.AddJob(*j), "error adding mqtt job")
+		return true
+	}, models.InitiatorMQTT)
+}
+
+// Stop disconnects every mqtt initiator. Stopping it again does nothing.
+func (s *concreteService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.chStop)
+		s.wg.Wait()
+	})
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	for id, subs := range s.jobs {
+		for _, sub := range subs {
+			sub.stop()
+		}
+		delete(s.jobs, id)
+	}
+}
+
+// AddJob connects each of the job's mqtt initiators to its broker. Brokers
+// that cannot be reached are retried in the background.
+func (s *concreteService) AddJob(job models.JobSpec) error {
+	initrs := job.InitiatorsFor(models.InitiatorMQTT)
+	if len(initrs) == 0 {
+		return nil
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if _, ok := s.jobs[*job.ID]; ok {
+		return errors.Errorf("mqtt topics of job %s are already subscribed to", job.ID.String())
+	}
+
+	subs := make([]*subscription, len(initrs))
+	for i, initr := range initrs {
+		subs[i] = s.subscribe(initr)
+	}
+	s.jobs[*job.ID] = subs
+	return nil
+}
+
+// RemoveJob disconnects the job's mqtt initiators from their brokers.
+func (s *concreteService) RemoveJob(id *models.ID) {
+	s.jobsMu.Lock()
+	subs := s.jobs[*id]
+	delete(s.jobs, *id)
+	s.jobsMu.Unlock()
+
+	for _, sub := range subs {
+		sub.stop()
+	}
+}
+
+func (s *concreteService) subscribe(initr models.Initiator) *subscription {
+	jobID := initr.JobSpecID.String()
+	opts := paho.NewClientOptions().
+		AddBroker(initr.BrokerURL).
+		SetClientID(fmt.Sprintf("chainlink-%s-%d", jobID, initr.ID)).
+		// A persistent session has the broker queue messages published while
+		// the node is disconnected, for subscriptions with a qos above 0.
+		SetCleanSession(initr.QoS == 0).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(time.Minute).
+		SetOnConnectHandler(func(client paho.Client) {
+			logger.Infow("Connected to mqtt broker", "broker", initr.BrokerURL, "job_id", jobID)
+			token := client.Subscribe(initr.TopicFilter, initr.QoS, s.handleMessage(initr))
+			if !token.WaitTimeout(subscribeTimeout) {
+				logger.Errorw("Timed out subscribing to mqtt topic", "topicFilter", initr.TopicFilter, "job_id", jobID)
+			} else if token.Error() != nil {
+				logger.Errorw("Unable to subscribe to mqtt topic", "topicFilter", initr.TopicFilter, "job_id", jobID, "error", token.Error())
+			}
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			logger.Warnw("Lost connection to mqtt broker, reconnecting", "broker", initr.BrokerURL, "job_id", jobID, "error", err)
+		})
+
+	sub := &subscription{
+		client: s.newClient(opts),
+		chStop: make(chan struct{}),
+		chDone: make(chan struct{}),
+	}
+	go sub.connect(initr)
+	return sub
+}
+
+// connect makes the first connection to the broker, backing off between
+// attempts. Once connected, the client reconnects by itself.
+func (sub *subscription) connect(initr models.Initiator) {
+	defer close(sub.chDone)
+
+	sleeper := utils.NewBackoffSleeper()
+	for {
+		select {
+		case <-sub.chStop:
+			return
+		case <-time.After(sleeper.After()):
+		}
+
+		token := sub.client.Connect()
+		token.Wait()
+		if token.Error() == nil {
+			return
+		}
+		logger.Warnw("Unable to connect to mqtt broker",
+			"broker", initr.BrokerURL, "job_id", initr.JobSpecID.String(), "error", token.Error())
+	}
+}
+
+func (sub *subscription) stop() {
+	close(sub.chStop)
+	<-sub.chDone
+	sub.client.Disconnect(disconnectQuiesce)
+}
+
+// handleMessage returns the handler creating runs for messages matching an
+// initiator's topic filter. Messages sent with a qos above 0 may be delivered
+// more than once, so those already consumed are dropped.
+func (s *concreteService) handleMessage(initr models.Initiator) paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		if msg.Qos() > 0 {
+			mc := models.NewMQTTConsumption(initr.ID, msg.Topic(), msg.MessageID(), msg.Payload())
+			fresh, err := s.store.CreateMQTTConsumption(&mc, dedupeWindow)
+			if err != nil {
+				logger.Errorw("Unable to record mqtt message consumption", "topic", msg.Topic(), "error", err)
+				return
+			} else if !fresh {
+				logger.Debugw("Skipping redelivered mqtt message", "topic", msg.Topic(), "messageID", msg.MessageID())
+				return
+			}
+		}
+
+		_, err := s.runManager.Create(initr.JobSpecID, &initr, nil, models.NewRunRequest(RequestParams(msg)))
+		logger.ErrorIf(err, "unable to create run for mqtt message")
+	}
+}
+
+// pruneConsumptions deletes the records of the messages consumed before the
+// dedupe window every window, as their redeliveries are no longer dropped.
+func (s *concreteService) pruneConsumptions() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.chStop:
+			return
+		case <-s.store.Clock.After(dedupeWindow):
+			err := s.store.DeleteMQTTConsumptionsBefore(time.Now().Add(-dedupeWindow))
+			logger.ErrorIf(err, "failed to prune mqtt message consumptions")
+		}
+	}
+}
+
+// RequestParams returns the input of the run triggered by an mqtt message.
+// Messages holding a JSON object are passed through as is, any other value is
+// passed as a string under the "value" key. The topic the message was
+// published to is added under the "topic" key, since a topic filter can match
+// many topics.
+func RequestParams(msg paho.Message) models.JSON {
+	params := models.JSON{}
+	if gjson.ValidBytes(msg.Payload()) && gjson.ParseBytes(msg.Payload()).IsObject() {
+		params = models.JSON{Result: gjson.ParseBytes(msg.Payload())}
+	} else {
+		params, _ = params.Add("value", string(msg.Payload()))
+	}
+	if !params.Get("topic").Exists() {
+		params, _ = params.Add("topic", msg.Topic())
+	}
+	return params
+}
@@ -0,0 +1,138 @@
+package mqtt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/mqtt"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeToken struct{ err error }
+
+func (t fakeToken) Wait() bool                     { return true }
+func (t fakeToken) WaitTimeout(time.Duration) bool { return true }
+func (t fakeToken) Error() error                   { return t.err }
+
+type fakeMessage struct {
+	qos       byte
+	topic     string
+	messageID uint16
+	payload   string
+}
+
+func (m fakeMessage) Duplicate() bool   { return false }
+func (m fakeMessage) Qos() byte         { return m.qos }
+func (m fakeMessage) Retained() bool    { return false }
+func (m fakeMessage) Topic() string     { return m.topic }
+func (m fakeMessage) MessageID() uint16 { return m.messageID }
+func (m fakeMessage) Payload() []byte   { return []byte(m.payload) }
+func (m fakeMessage) Ack()              {}
+
+// fakeClient connects immediately, and hands the handler of its subscription
+// to the test so that messages can be delivered.
+type fakeClient struct {
+	paho.Client
+	opts         *paho.ClientOptions
+	subscribed   chan paho.MessageHandler
+	disconnected chan struct{}
+}
+
+func (c *fakeClient) Connect() paho.Token {
+	c.opts.OnConnect(c)
+	return fakeToken{}
+}
+
+func (c *fakeClient) Subscribe(topic string, qos byte, callback paho.MessageHandler) paho.Token {
+	c.subscribed <- callback
+	return fakeToken{}
+}
+
+func (c *fakeClient) Disconnect(uint) {
+	close(c.disconnected)
+}
+
+func TestMQTT_AddJob_CreatesRunsAndDropsRedeliveries(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJob()
+	job.Initiators = []models.Initiator{{
+		JobSpecID: job.ID,
+		Type:      models.InitiatorMQTT,
+		InitiatorParams: models.InitiatorParams{
+			BrokerURL:   "tcp://localhost:1883",
+			TopicFilter: "sensors/+/temperature",
+			QoS:         1,
+		},
+	}}
+	require.NoError(t, store.CreateJob(&job))
+
+	client := &fakeClient{
+		subscribed:   make(chan paho.MessageHandler, 1),
+		disconnected: make(chan struct{}),
+	}
+	runManager := new(mocks.RunManager)
+	service := mqtt.New(store, runManager)
+	mqtt.ExportedSetClientFactory(service, func(opts *paho.ClientOptions) paho.Client {
+		assert.False(t, opts.CleanSession)
+		client.opts = opts
+		return client
+	})
+
+	require.NoError(t, service.AddJob(job))
+	var handler paho.MessageHandler
+	cltest.CallbackOrTimeout(t, "subscribed", func() {
+		handler = <-client.subscribed
+	})
+
+	created := make(chan *models.RunRequest, 2)
+	runManager.On("Create", job.ID, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Run(func(args mock.Arguments) {
+			created <- args.Get(3).(*models.RunRequest)
+		})
+
+	msg := fakeMessage{qos: 1, topic: "sensors/kitchen/temperature", messageID: 7, payload: "21.5"}
+	handler(client, msg)
+	handler(client, msg)
+	handler(client, fakeMessage{qos: 1, topic: "sensors/kitchen/temperature", messageID: 7, payload: "21.6"})
+
+	runManager.AssertNumberOfCalls(t, "Create", 2)
+	rr := <-created
+	assert.Equal(t, "21.5", rr.RequestParams.Get("value").String())
+	assert.Equal(t, "sensors/kitchen/temperature", rr.RequestParams.Get("topic").String())
+
+	service.RemoveJob(job.ID)
+	cltest.CallbackOrTimeout(t, "disconnected", func() {
+		<-client.disconnected
+	})
+}
+
+func TestMQTT_RequestParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{"json object", `{"celsius":21.5}`, `{"celsius":21.5,"topic":"sensors/kitchen"}`},
+		{"json object with topic", `{"topic":"kitchen"}`, `{"topic":"kitchen"}`},
+		{"plain text", `21.5`, `{"value":"21.5","topic":"sensors/kitchen"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := mqtt.RequestParams(fakeMessage{topic: "sensors/kitchen", payload: test.payload})
+			assert.JSONEq(t, test.want, params.String())
+		})
+	}
+}
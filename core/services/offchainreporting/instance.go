@@ -0,0 +1,580 @@
+package offchainreporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/fluxmonitor"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+)
+
+// transmitSignature is the aggregator function reports are transmitted to,
+// with the report and the r, s and v values of the oracles' signatures.
+const transmitSignature = "transmit(bytes,bytes32[],bytes32[],bytes32)"
+
+type oracle struct {
+	peerID         peer.ID
+	signingAddress common.Address
+}
+
+// parseOracles returns the oracles of the config, with their addresses,
+// sorted by peer ID so that every oracle agrees on their indexes.
+func parseOracles(config models.OffchainReportingConfig) ([]oracle, []ma.Multiaddr, error) {
+	type parsed struct {
+		oracle
+		addr ma.Multiaddr
+	}
+	var all []parsed
+	for _, o := range config.Oracles {
+		id, err := peer.IDB58Decode(o.PeerID)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid peer ID %s", o.PeerID)
+		}
+		addr, err := ma.NewMultiaddr(o.Addr)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid address %s for peer %s", o.Addr, o.PeerID)
+		}
+		all = append(all, parsed{oracle{id, o.SigningAddress}, addr})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].peerID < all[j].peerID })
+
+	oracles := make([]oracle, len(all))
+	addrs := make([]ma.Multiaddr, len(all))
+	for i, p := range all {
+		oracles[i] = p.oracle
+		addrs[i] = p.addr
+	}
+	return oracles, addrs, nil
+}
+
+// roundState is what an oracle knows of a round, as its leader or a follower.
+type roundState struct {
+	requested    bool
+	observed     bool
+	signed       bool
+	observations map[uint8]Observation
+	report       *Report
+	encoded      []byte
+	signatures   map[uint8][]byte
+	done         bool
+}
+
+// instance runs off-chain reporting for a single initiator. Time is divided
+// into rounds of the initiator's poll period, each led by one of the oracles
+// in turn. The leader asks every oracle for a signed observation, builds a
+// report from the first 2f+1 it receives, where up to f of the oracles may be
+// faulty, and has it signed by the oracles. Once it has f+1 signatures, the
+// leader starts a run of the job transmitting the report to the aggregator,
+// and tells the oracles, which only then take it as the last report.
+type instance struct {
+	initr      models.Initiator
+	kb         *ocrkey.KeyBundle
+	network    Network
+	fetcher    fluxmonitor.Fetcher
+	runManager fluxmonitor.RunManager
+	oracles    []oracle
+	self       uint8
+	faulty     int
+	now        func() time.Time
+
+	mu              sync.Mutex
+	rounds          map[uint64]*roundState
+	lastReport      *big.Int
+	lastReportedAt  time.Time
+	lastReportRound uint64
+
+	chStop chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newInstance(
+	initr models.Initiator,
+	kb *ocrkey.KeyBundle,
+	network Network,
+	fetcher fluxmonitor.Fetcher,
+	runManager fluxmonitor.RunManager,
+) (*instance, error) {
+	if initr.PollTimer.Period.Duration() <= 0 {
+		return nil, errors.New("offchainreporting requires a pollTimer period")
+	}
+	oracles, addrs, err := parseOracles(initr.OffchainReporting)
+	if err != nil {
+		return nil, err
+	}
+	if len(oracles) == 0 || len(oracles) > 32 {
+		return nil, fmt.Errorf("offchainreporting requires between 1 and 32 oracles, got %d", len(oracles))
+	}
+
+	i := &instance{
+		initr:      initr,
+		kb:         kb,
+		network:    network,
+		fetcher:    fetcher,
+		runManager: runManager,
+		oracles:    oracles,
+		faulty:     (len(oracles) - 1) / 3,
+		now:        time.Now,
+		rounds:     make(map[uint64]*roundState),
+		chStop:     make(chan struct{}),
+	}
+	self, ok := i.indexOf(kb.PeerID())
+	if !ok {
+		return nil, fmt.Errorf("this node's peer ID %s is not one of the oracles", kb.ID())
+	}
+	i.self = self
+	for idx, o := range oracles {
+		network.AddPeer(o.peerID, addrs[idx])
+	}
+	return i, nil
+}
+
+func (i *instance) start() {
+	i.wg.Add(1)
+	go func() {
+		defer i.wg.Done()
+		ticker := time.NewTicker(i.initr.PollTimer.Period.Duration())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				i.startRound(i.currentRound())
+			case <-i.chStop:
+				return
+			}
+		}
+	}()
+}
+
+func (i *instance) stop() {
+	close(i.chStop)
+	i.wg.Wait()
+}
+
+func (i *instance) currentRound() uint64 {
+	return uint64(i.now().UnixNano() / int64(i.initr.PollTimer.Period.Duration()))
+}
+
+// isRecent reports whether messages for the given round are still accepted,
+// which they are for the current round and the one before it, to allow for
+// some clock drift between the oracles.
+func (i *instance) isRecent(round uint64) bool {
+	current := i.currentRound()
+	return round == current || round+1 == current
+}
+
+func (i *instance) leader(round uint64) uint8 {
+	return uint8(round % uint64(len(i.oracles)))
+}
+
+func (i *instance) indexOf(id peer.ID) (uint8, bool) {
+	for idx, o := range i.oracles {
+		if o.peerID == id {
+			return uint8(idx), true
+		}
+	}
+	return 0, false
+}
+
+// round returns the state of the given round, forgetting rounds which are
+// too old to be accepted anymore. It must be called with mu held.
+func (i *instance) round(round uint64) *roundState {
+	st, ok := i.rounds[round]
+	if !ok {
+		for r := range i.rounds {
+			if r+1 < round {
+				delete(i.rounds, r)
+			}
+		}
+		st = &roundState{
+			observations: make(map[uint8]Observation),
+			signatures:   make(map[uint8][]byte),
+		}
+		i.rounds[round] = st
+	}
+	return st
+}
+
+// startRound asks every oracle for an observation, if this oracle leads the
+// round.
+func (i *instance) startRound(round uint64) {
+	if i.leader(round) != i.self {
+		return
+	}
+	i.mu.Lock()
+	st := i.round(round)
+	if st.requested {
+		i.mu.Unlock()
+		return
+	}
+	st.requested = true
+	i.mu.Unlock()
+
+	logger.Debugw("Starting off-chain reporting round", i.loggerFields("round", round)...)
+	i.broadcast(Message{Type: MessageObserveRequest, Aggregator: i.initr.Address, Round: round})
+}
+
+// handle processes a message received from another oracle, or this one.
+func (i *instance) handle(from peer.ID, msg Message) {
+	if sender, ok := i.accept(from, msg); ok {
+		i.process(sender, msg)
+	}
+}
+
+// accept returns the index of the oracle the message is from, and whether
+// the message is to be processed: it must be from one of the oracles, and for
+// a recent round. It is cheap enough to be called before handing the message
+// to a goroutine, so that messages from unknown peers cannot start any.
+func (i *instance) accept(from peer.ID, msg Message) (uint8, bool) {
+	sender, ok := i.indexOf(from)
+	if !ok {
+		logger.Warnw("Off-chain reporting message from unknown peer", i.loggerFields("peer", from.Pretty())...)
+		return 0, false
+	}
+	if !i.isRecent(msg.Round) {
+		logger.Debugw("Ignoring off-chain reporting message for old round", i.loggerFields("round", msg.Round, "type", msg.Type)...)
+		return 0, false
+	}
+	return sender, true
+}
+
+// process processes a message accepted from the oracle with index sender.
+func (i *instance) process(sender uint8, msg Message) {
+	leader := i.leader(msg.Round)
+	var err error
+	switch msg.Type {
+	case MessageObserveRequest:
+		if sender != leader {
+			err = errors.New("observe request not from leader")
+			break
+		}
+		err = i.handleObserveRequest(msg.Round)
+	case MessageObservation:
+		if i.self != leader {
+			err = errors.New("observation sent to follower")
+			break
+		}
+		err = i.handleObservation(sender, msg.Round, msg.Observation)
+	case MessageReportRequest:
+		if sender != leader {
+			err = errors.New("report request not from leader")
+			break
+		}
+		err = i.handleReportRequest(msg.Round, msg.Report)
+	case MessageReportSignature:
+		if i.self != leader {
+			err = errors.New("report signature sent to follower")
+			break
+		}
+		err = i.handleReportSignature(sender, msg.Round, msg.Signature)
+	case MessageReportTransmitted:
+		if sender != leader {
+			err = errors.New("report transmitted not from leader")
+			break
+		}
+		err = i.handleReportTransmitted(msg.Round, msg.Report)
+	default:
+		err = fmt.Errorf("unknown message type %s", msg.Type)
+	}
+	if err != nil {
+		logger.Warnw("Rejected off-chain reporting message", i.loggerFields(
+			"round", msg.Round, "type", msg.Type, "sender", sender, "error", err,
+		)...)
+	}
+}
+
+func (i *instance) handleObserveRequest(round uint64) error {
+	i.mu.Lock()
+	st := i.round(round)
+	if st.observed {
+		i.mu.Unlock()
+		return nil
+	}
+	st.observed = true
+	i.mu.Unlock()
+
+	polled, err := i.fetcher.Fetch()
+	if err != nil {
+		return errors.Wrap(err, "unable to fetch observation")
+	}
+	value := polled.Shift(i.initr.Precision).Round(0).Coefficient()
+	observation, err := signObservation(i.kb, i.self, i.initr.Address, round, value)
+	if err != nil {
+		return err
+	}
+	return i.send(i.leader(round), Message{
+		Type:        MessageObservation,
+		Aggregator:  i.initr.Address,
+		Round:       round,
+		Observation: observation,
+	})
+}
+
+func (i *instance) handleObservation(sender uint8, round uint64, observation *Observation) error {
+	if observation == nil || observation.Observer != sender {
+		return errors.New("observation not made by sender")
+	}
+	if err := observation.verify(i.oracles[sender].signingAddress, i.initr.Address, round); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	st := i.round(round)
+	if st.report != nil || st.done {
+		i.mu.Unlock()
+		return nil
+	}
+	st.observations[sender] = *observation
+	if len(st.observations) < 2*i.faulty+1 {
+		i.mu.Unlock()
+		return nil
+	}
+
+	observations := make([]Observation, 0, len(st.observations))
+	for _, o := range st.observations {
+		observations = append(observations, o)
+	}
+	report := newReport(i.initr.Address, round, observations)
+	if !i.shouldReport(report.Median()) {
+		st.done = true
+		i.mu.Unlock()
+		logger.Debugw("Off-chain reporting median within deviation threshold, not reporting",
+			i.loggerFields("round", round, "median", report.Median())...)
+		return nil
+	}
+	encoded, err := report.Encode()
+	if err != nil {
+		st.done = true
+		i.mu.Unlock()
+		return err
+	}
+	st.report = report
+	st.encoded = encoded
+	i.mu.Unlock()
+
+	i.broadcast(Message{Type: MessageReportRequest, Aggregator: i.initr.Address, Round: round, Report: report})
+	return nil
+}
+
+func (i *instance) handleReportRequest(round uint64, report *Report) error {
+	if err := i.verifyReport(round, report); err != nil {
+		return err
+	}
+	encoded, err := report.Encode()
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	st := i.round(round)
+	if st.signed {
+		i.mu.Unlock()
+		return nil
+	}
+	if !i.shouldReport(report.Median()) {
+		i.mu.Unlock()
+		return errors.New("report median within deviation threshold")
+	}
+	st.signed = true
+	i.mu.Unlock()
+
+	signature, err := i.kb.Sign(encoded)
+	if err != nil {
+		return err
+	}
+	return i.send(i.leader(round), Message{
+		Type:       MessageReportSignature,
+		Aggregator: i.initr.Address,
+		Round:      round,
+		Signature:  signature,
+	})
+}
+
+// verifyReport checks that the report is for this round and aggregator, and
+// made of 2f+1 observations by distinct oracles, sorted by value.
+func (i *instance) verifyReport(round uint64, report *Report) error {
+	if report == nil || report.Round != round || report.Aggregator != i.initr.Address {
+		return errors.New("report is not for this round")
+	}
+	if len(report.Observations) < 2*i.faulty+1 {
+		return fmt.Errorf("report has %d observations, requires %d", len(report.Observations), 2*i.faulty+1)
+	}
+	seen := make(map[uint8]bool)
+	for idx, o := range report.Observations {
+		if int(o.Observer) >= len(i.oracles) || seen[o.Observer] {
+			return fmt.Errorf("invalid observer %d", o.Observer)
+		}
+		seen[o.Observer] = true
+		if err := o.verify(i.oracles[o.Observer].signingAddress, i.initr.Address, round); err != nil {
+			return err
+		}
+		if idx > 0 && report.Observations[idx-1].Value.ToInt().Cmp(o.Value.ToInt()) > 0 {
+			return errors.New("report observations are not sorted")
+		}
+	}
+	return nil
+}
+
+func (i *instance) handleReportSignature(sender uint8, round uint64, signature []byte) error {
+	i.mu.Lock()
+	st := i.round(round)
+	if st.report == nil || st.done {
+		i.mu.Unlock()
+		return nil
+	}
+	signer, err := ocrkey.RecoverSigner(st.encoded, signature)
+	if err != nil || signer != i.oracles[sender].signingAddress {
+		i.mu.Unlock()
+		return errors.New("report signature not made by sender")
+	}
+	st.signatures[sender] = signature
+	if len(st.signatures) < i.faulty+1 {
+		i.mu.Unlock()
+		return nil
+	}
+	st.done = true
+	report, encoded := st.report, st.encoded
+	signatures := make([][]byte, 0, len(st.signatures))
+	for idx := range i.oracles {
+		if s, ok := st.signatures[uint8(idx)]; ok {
+			signatures = append(signatures, s)
+		}
+	}
+	i.mu.Unlock()
+
+	if err := i.transmit(report, encoded, signatures); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	i.setLastReport(round, report)
+	i.mu.Unlock()
+	i.broadcast(Message{Type: MessageReportTransmitted, Aggregator: i.initr.Address, Round: round, Report: report})
+	return nil
+}
+
+// handleReportTransmitted takes the report the leader transmitted as the
+// last report, which later ones must deviate from.
+func (i *instance) handleReportTransmitted(round uint64, report *Report) error {
+	if err := i.verifyReport(round, report); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.setLastReport(round, report)
+	return nil
+}
+
+// setLastReport records the report of the round as transmitted, unless one
+// of a later round already was. It must be called with mu held.
+func (i *instance) setLastReport(round uint64, report *Report) {
+	if i.lastReport != nil && round < i.lastReportRound {
+		return
+	}
+	i.lastReport = report.Median()
+	i.lastReportedAt = i.now()
+	i.lastReportRound = round
+}
+
+// shouldReport reports whether a report with the given median is due, as it
+// is the first, it deviates enough from the last one, or the idle timer has
+// elapsed since the last one. It must be called with mu held.
+func (i *instance) shouldReport(median *big.Int) bool {
+	if i.lastReport == nil {
+		return true
+	}
+	if !i.initr.IdleTimer.Disabled && i.initr.IdleTimer.Duration.Duration() > 0 &&
+		i.now().Sub(i.lastReportedAt) >= i.initr.IdleTimer.Duration.Duration() {
+		return true
+	}
+	return fluxmonitor.OutsideDeviation(
+		decimal.NewFromBigInt(i.lastReport, 0),
+		decimal.NewFromBigInt(median, 0),
+		float64(i.initr.Threshold),
+	)
+}
+
+type transmitRunRequest struct {
+	Result           decimal.Decimal `json:"result"`
+	Address          string          `json:"address"`
+	FunctionSelector string          `json:"functionSelector"`
+	Report           string          `json:"report"`
+	Rs               []string        `json:"rs"`
+	Ss               []string        `json:"ss"`
+	RawVs            string          `json:"rawVs"`
+}
+
+// transmit starts a run of the job, passing it the report and signatures as
+// the arguments of the aggregator's transmit function.
+func (i *instance) transmit(report *Report, encoded []byte, signatures [][]byte) error {
+	request := transmitRunRequest{
+		Result:           decimal.NewFromBigInt(report.Median(), -i.initr.Precision),
+		Address:          i.initr.Address.Hex(),
+		FunctionSelector: eth.BytesToFunctionSelector(crypto.Keccak256([]byte(transmitSignature))).String(),
+		Report:           hexutil.Encode(encoded),
+	}
+	var rawVs [32]byte
+	for idx, signature := range signatures {
+		if len(signature) != 65 {
+			return errors.New("invalid report signature length")
+		}
+		request.Rs = append(request.Rs, hexutil.Encode(signature[:32]))
+		request.Ss = append(request.Ss, hexutil.Encode(signature[32:64]))
+		rawVs[idx] = signature[64]
+	}
+	request.RawVs = hexutil.Encode(rawVs[:])
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode Job Run request in JSON")
+	}
+	runData, err := models.ParseJSON(payload)
+	if err != nil {
+		return errors.Wrapf(err, "unable to start chainlink run with payload %s", payload)
+	}
+
+	logger.Infow("Transmitting off-chain report", i.loggerFields(
+		"round", report.Round, "median", report.Median(), "signatures", len(signatures),
+	)...)
+	_, err = i.runManager.Create(i.initr.JobSpecID, &i.initr, nil, models.NewRunRequest(runData))
+	if err != nil {
+		return err
+	}
+	promOCRTransmittedReports.WithLabelValues(i.initr.JobSpecID.String()).Inc()
+	return nil
+}
+
+func (i *instance) send(to uint8, msg Message) error {
+	return i.network.Send(i.oracles[to].peerID, msg)
+}
+
+func (i *instance) broadcast(msg Message) {
+	for idx := range i.oracles {
+		if err := i.send(uint8(idx), msg); err != nil {
+			logger.Warnw("Unable to send off-chain reporting message", i.loggerFields(
+				"type", msg.Type, "peer", i.oracles[idx].peerID.Pretty(), "error", err,
+			)...)
+		}
+	}
+}
+
+func (i *instance) loggerFields(added ...interface{}) []interface{} {
+	return append(added, []interface{}{
+		"contract", i.initr.Address.Hex(),
+		"job_id", i.initr.JobSpecID.String(),
+		"oracle", i.self,
+	}...)
+}
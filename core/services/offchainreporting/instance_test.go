@@ -0,0 +1,335 @@
+package offchainreporting
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+)
+
+// memoryNetwork delivers messages between oracles in the same process.
+type memoryNetwork struct {
+	id     peer.ID
+	hub    *memoryHub
+	chRecv chan ReceivedMessage
+}
+
+type memoryHub struct {
+	mu    sync.Mutex
+	nodes map[peer.ID]*memoryNetwork
+}
+
+func (h *memoryHub) join(id peer.ID) *memoryNetwork {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := &memoryNetwork{id: id, hub: h, chRecv: make(chan ReceivedMessage, 100)}
+	h.nodes[id] = n
+	return n
+}
+
+func (n *memoryNetwork) PeerID() peer.ID                 { return n.id }
+func (n *memoryNetwork) AddPeer(peer.ID, ma.Multiaddr)   {}
+func (n *memoryNetwork) Receive() <-chan ReceivedMessage { return n.chRecv }
+func (n *memoryNetwork) Close() error                    { return nil }
+
+func (n *memoryNetwork) Send(to peer.ID, msg Message) error {
+	n.hub.mu.Lock()
+	dest := n.hub.nodes[to]
+	n.hub.mu.Unlock()
+	dest.chRecv <- ReceivedMessage{From: n.id, Message: msg}
+	return nil
+}
+
+type testOracles struct {
+	instances []*instance
+	fetchers  []*mocks.Fetcher
+	round     uint64
+	chStop    chan struct{}
+}
+
+// newTestOracles creates instances for n oracles, ordered by their index,
+// which report to the same aggregator over a memoryNetwork, all in the given
+// round.
+func newTestOracles(t *testing.T, n int, runManager *mocks.RunManager, round uint64) *testOracles {
+	hub := &memoryHub{nodes: make(map[peer.ID]*memoryNetwork)}
+	var bundles []*ocrkey.KeyBundle
+	var config models.OffchainReportingConfig
+	for i := 0; i < n; i++ {
+		kb, err := ocrkey.NewKeyBundle()
+		require.NoError(t, err)
+		bundles = append(bundles, kb)
+		config.Oracles = append(config.Oracles, models.OffchainReportingOracle{
+			PeerID:         kb.ID(),
+			Addr:           "/ip4/127.0.0.1/tcp/6690",
+			SigningAddress: kb.SigningAddress(),
+		})
+	}
+
+	period := time.Minute
+	initr := models.Initiator{
+		JobSpecID: models.NewID(),
+		Type:      models.InitiatorOffchainReporting,
+		InitiatorParams: models.InitiatorParams{
+			Address:           common.HexToAddress("0x3cCad4715152693fE3BC4460591e3D3Fbd071b42"),
+			Precision:         2,
+			Threshold:         1,
+			PollTimer:         models.PollTimerConfig{Period: models.MustMakeDuration(period)},
+			IdleTimer:         models.IdleTimerConfig{Disabled: true},
+			OffchainReporting: config,
+		},
+	}
+	oracles := &testOracles{
+		instances: make([]*instance, n),
+		fetchers:  make([]*mocks.Fetcher, n),
+		round:     round,
+		chStop:    make(chan struct{}),
+	}
+	now := func() time.Time {
+		return time.Unix(0, int64(atomic.LoadUint64(&oracles.round))*int64(period))
+	}
+	for _, kb := range bundles {
+		fetcher := new(mocks.Fetcher)
+		network := hub.join(kb.PeerID())
+		i, err := newInstance(initr, kb, network, fetcher, runManager)
+		require.NoError(t, err)
+		i.now = now
+		oracles.instances[i.self] = i
+		oracles.fetchers[i.self] = fetcher
+
+		go func() {
+			for {
+				select {
+				case rm := <-network.Receive():
+					i.handle(rm.From, rm.Message)
+				case <-oracles.chStop:
+					return
+				}
+			}
+		}()
+	}
+	return oracles
+}
+
+func (o *testOracles) stop() {
+	close(o.chStop)
+}
+
+func (o *testOracles) setRound(round uint64) {
+	atomic.StoreUint64(&o.round, round)
+}
+
+// awaitLastReport waits for every oracle to take the report of the round as
+// the last one transmitted.
+func (o *testOracles) awaitLastReport(t *testing.T, round uint64) {
+	require.Eventually(t, func() bool {
+		for _, i := range o.instances {
+			i.mu.Lock()
+			transmitted := i.lastReport != nil && i.lastReportRound == round
+			i.mu.Unlock()
+			if !transmitted {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestInstance_TransmitsSignedReport(t *testing.T) {
+	runManager := new(mocks.RunManager)
+	chRunRequests := make(chan *models.RunRequest, 1)
+	runManager.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { chRunRequests <- args.Get(3).(*models.RunRequest) }).
+		Return(&models.JobRun{}, nil).Once()
+
+	round := uint64(40)
+	oracles := newTestOracles(t, 4, runManager, round)
+	defer oracles.stop()
+	for idx, f := range oracles.fetchers {
+		f.On("Fetch").Return(decimal.NewFromFloat(100.01+float64(idx)), nil)
+	}
+
+	leader := oracles.instances[round%4]
+	leader.startRound(round)
+
+	var request *models.RunRequest
+	select {
+	case request = <-chRunRequests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("report was not transmitted")
+	}
+
+	data := request.RequestParams
+	assert.Equal(t, leader.initr.Address.Hex(), data.Get("address").String())
+	assert.Equal(t, "0xc9807539", data.Get("functionSelector").String())
+	rs := data.Get("rs").Array()
+	assert.Len(t, data.Get("ss").Array(), len(rs))
+	assert.GreaterOrEqual(t, len(rs), 2)
+
+	encoded, err := hexutil.Decode(data.Get("report").String())
+	require.NoError(t, err)
+	values, err := reportArguments.UnpackValues(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, leader.initr.Address, values[0])
+	assert.Equal(t, round, values[1])
+	observations := values[3].([]*big.Int)
+	require.Len(t, observations, 3)
+	for idx := 1; idx < len(observations); idx++ {
+		assert.True(t, observations[idx-1].Cmp(observations[idx]) <= 0)
+	}
+	assert.Equal(t, observations[1].String(), decimal.RequireFromString(data.Get("result").String()).Shift(2).String())
+
+	rawVs, err := hexutil.Decode(data.Get("rawVs").String())
+	require.NoError(t, err)
+	for idx, r := range rs {
+		signature := append(hexutil.MustDecode(r.String()), hexutil.MustDecode(data.Get("ss").Array()[idx].String())...)
+		signature = append(signature, rawVs[idx])
+		signer, err := ocrkey.RecoverSigner(encoded, signature)
+		require.NoError(t, err)
+		var isOracle bool
+		for _, i := range oracles.instances {
+			isOracle = isOracle || i.kb.SigningAddress() == signer
+		}
+		assert.True(t, isOracle)
+	}
+
+	// The next round's median is within the threshold of the last report, so
+	// is not transmitted once every oracle knows of it
+	oracles.awaitLastReport(t, round)
+	oracles.setRound(round + 1)
+	oracles.instances[(round+1)%4].startRound(round + 1)
+	select {
+	case <-chRunRequests:
+		t.Fatal("report within deviation threshold was transmitted")
+	case <-time.After(500 * time.Millisecond):
+	}
+	runManager.AssertExpectations(t)
+}
+
+func TestInstance_ReportsAgainAfterFailedTransmission(t *testing.T) {
+	runManager := new(mocks.RunManager)
+	chRunRequests := make(chan *models.RunRequest, 2)
+	runManager.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { chRunRequests <- args.Get(3).(*models.RunRequest) }).
+		Return(nil, errors.New("database unavailable")).Once()
+	runManager.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { chRunRequests <- args.Get(3).(*models.RunRequest) }).
+		Return(&models.JobRun{}, nil).Once()
+
+	round := uint64(40)
+	oracles := newTestOracles(t, 4, runManager, round)
+	defer oracles.stop()
+	for _, f := range oracles.fetchers {
+		f.On("Fetch").Return(decimal.NewFromFloat(100.01), nil)
+	}
+
+	oracles.instances[round%4].startRound(round)
+	select {
+	case <-chRunRequests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("report was not transmitted")
+	}
+	time.Sleep(100 * time.Millisecond)
+	for _, i := range oracles.instances {
+		i.mu.Lock()
+		assert.Nil(t, i.lastReport, "report whose transmission failed was taken as the last one")
+		i.mu.Unlock()
+	}
+
+	// The same median is reported again, as the last report never made it
+	oracles.setRound(round + 1)
+	oracles.instances[(round+1)%4].startRound(round + 1)
+	select {
+	case <-chRunRequests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("report was not transmitted again")
+	}
+	oracles.awaitLastReport(t, round+1)
+	runManager.AssertExpectations(t)
+}
+
+func TestInstance_RejectsMessagesNotFromLeader(t *testing.T) {
+	runManager := new(mocks.RunManager)
+	round := uint64(40)
+	oracles := newTestOracles(t, 4, runManager, round)
+	defer oracles.stop()
+
+	follower := oracles.instances[(round+1)%4]
+	follower.startRound(round)
+	oracles.instances[round%4].handle(follower.kb.PeerID(), Message{
+		Type:       MessageObserveRequest,
+		Aggregator: follower.initr.Address,
+		Round:      round,
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	for _, f := range oracles.fetchers {
+		f.AssertNotCalled(t, "Fetch")
+	}
+	runManager.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInstance_Accept(t *testing.T) {
+	round := uint64(40)
+	oracles := newTestOracles(t, 4, new(mocks.RunManager), round)
+	defer oracles.stop()
+	i := oracles.instances[0]
+	from := oracles.instances[2]
+	msg := Message{Type: MessageObservation, Aggregator: i.initr.Address, Round: round}
+
+	sender, ok := i.accept(from.kb.PeerID(), msg)
+	assert.True(t, ok)
+	assert.Equal(t, from.self, sender)
+
+	_, ok = i.accept(peer.ID("not an oracle"), msg)
+	assert.False(t, ok, "messages from unknown peers should not be processed")
+
+	msg.Round = round - 2
+	_, ok = i.accept(from.kb.PeerID(), msg)
+	assert.False(t, ok, "messages for old rounds should not be processed")
+}
+
+func TestInstance_VerifyReport(t *testing.T) {
+	round := uint64(40)
+	oracles := newTestOracles(t, 4, new(mocks.RunManager), round)
+	defer oracles.stop()
+	i := oracles.instances[0]
+
+	var observations []Observation
+	for idx, o := range oracles.instances[:3] {
+		observation, err := signObservation(o.kb, o.self, i.initr.Address, round, big.NewInt(int64(100-idx)))
+		require.NoError(t, err)
+		observations = append(observations, *observation)
+	}
+	report := newReport(i.initr.Address, round, observations)
+	require.NoError(t, i.verifyReport(round, report))
+
+	assert.Error(t, i.verifyReport(round+1, report), "wrong round")
+
+	short := newReport(i.initr.Address, round, observations[:2])
+	assert.Error(t, i.verifyReport(round, short), "too few observations")
+
+	repeated := newReport(i.initr.Address, round, append(observations[:2:2], observations[0]))
+	assert.Error(t, i.verifyReport(round, repeated), "repeated observer")
+
+	forged := newReport(i.initr.Address, round, observations)
+	forged.Observations[0].Observer = 3
+	assert.Error(t, i.verifyReport(round, forged), "signed by another oracle")
+
+	unsorted := &Report{Aggregator: i.initr.Address, Round: round, Observations: observations}
+	assert.Error(t, i.verifyReport(round, unsorted), "unsorted observations")
+}
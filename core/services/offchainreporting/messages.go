@@ -0,0 +1,158 @@
+package offchainreporting
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+// MessageType distinguishes the steps of an off-chain reporting round.
+type MessageType string
+
+const (
+	// MessageObserveRequest is sent by the leader of a round to ask every
+	// oracle for its observation.
+	MessageObserveRequest MessageType = "observeRequest"
+	// MessageObservation is an oracle's signed observation, sent to the leader.
+	MessageObservation MessageType = "observation"
+	// MessageReportRequest is sent by the leader with the report it built from
+	// the observations, for every oracle to check and sign.
+	MessageReportRequest MessageType = "reportRequest"
+	// MessageReportSignature is an oracle's signature of the report, sent to
+	// the leader.
+	MessageReportSignature MessageType = "reportSignature"
+	// MessageReportTransmitted is sent by the leader once it has started the
+	// run transmitting the report, which every oracle then deviates from.
+	MessageReportTransmitted MessageType = "reportTransmitted"
+)
+
+// Message is exchanged between oracles reporting to the same aggregator.
+type Message struct {
+	Type        MessageType    `json:"type"`
+	Aggregator  common.Address `json:"aggregator"`
+	Round       uint64         `json:"round"`
+	Observation *Observation   `json:"observation,omitempty"`
+	Report      *Report        `json:"report,omitempty"`
+	Signature   hexutil.Bytes  `json:"signature,omitempty"`
+}
+
+// Observation is the value an oracle polled from its feeds in a round, signed
+// by the oracle, which is given by its index in the sorted set of oracles.
+type Observation struct {
+	Observer  uint8         `json:"observer"`
+	Value     *utils.Big    `json:"value"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// observationPayload returns the bytes an oracle signs for its observation of
+// value in the given round.
+func observationPayload(aggregator common.Address, round uint64, value *big.Int) ([]byte, error) {
+	word, err := utils.EVMWordSignedBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+	payload := append(aggregator.Bytes(), utils.EVMWordUint64(round)...)
+	return append(payload, word...), nil
+}
+
+// signObservation returns the observation of value by the oracle with the
+// given index, signed with the bundle's signing key.
+func signObservation(kb *ocrkey.KeyBundle, observer uint8, aggregator common.Address, round uint64, value *big.Int) (*Observation, error) {
+	payload, err := observationPayload(aggregator, round, value)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := kb.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Observation{
+		Observer:  observer,
+		Value:     utils.NewBig(value),
+		Signature: signature,
+	}, nil
+}
+
+// verify checks that the observation was signed by the given address.
+func (o Observation) verify(signer common.Address, aggregator common.Address, round uint64) error {
+	if o.Value == nil {
+		return errors.New("observation has no value")
+	}
+	payload, err := observationPayload(aggregator, round, o.Value.ToInt())
+	if err != nil {
+		return err
+	}
+	recovered, err := ocrkey.RecoverSigner(payload, o.Signature)
+	if err != nil {
+		return err
+	}
+	if recovered != signer {
+		return errors.Errorf("observation signed by %s, expected %s", recovered.Hex(), signer.Hex())
+	}
+	return nil
+}
+
+// Report is the set of observations the leader of a round gathered, sorted by
+// value, which the oracles sign for transmission to the aggregator.
+type Report struct {
+	Aggregator   common.Address `json:"aggregator"`
+	Round        uint64         `json:"round"`
+	Observations []Observation  `json:"observations"`
+}
+
+// newReport returns the report of the given observations, sorted by value.
+func newReport(aggregator common.Address, round uint64, observations []Observation) *Report {
+	sorted := make([]Observation, len(observations))
+	copy(sorted, observations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value.ToInt().Cmp(sorted[j].Value.ToInt()) < 0
+	})
+	return &Report{Aggregator: aggregator, Round: round, Observations: sorted}
+}
+
+// Median returns the median of the report's observations.
+func (r Report) Median() *big.Int {
+	return r.Observations[len(r.Observations)/2].Value.ToInt()
+}
+
+var reportArguments = abi.Arguments{
+	{Type: mustNewType("address")},
+	{Type: mustNewType("uint64")},
+	{Type: mustNewType("bytes32")},
+	{Type: mustNewType("int256[]")},
+}
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// Encode returns the report as it is signed and transmitted to the
+// aggregator, abi encoded as (address aggregator, uint64 round,
+// bytes32 observers, int256[] observations), where the nth byte of observers
+// is the index of the oracle which made the nth observation.
+func (r Report) Encode() ([]byte, error) {
+	if len(r.Observations) > 32 {
+		return nil, errors.Errorf("report has %d observations, at most 32 can be encoded", len(r.Observations))
+	}
+	var observers [32]byte
+	values := make([]*big.Int, len(r.Observations))
+	for i, o := range r.Observations {
+		if o.Value == nil {
+			return nil, errors.New("observation has no value")
+		}
+		observers[i] = o.Observer
+		values[i] = o.Value.ToInt()
+	}
+	return reportArguments.Pack(r.Aggregator, r.Round, observers, values)
+}
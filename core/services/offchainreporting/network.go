@@ -0,0 +1,135 @@
+package offchainreporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/helpers"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+)
+
+// protocolID identifies the streams oracles open to each other to exchange
+// off-chain reporting messages.
+const protocolID = protocol.ID("/chainlink/ocr/1.0.0")
+
+// maxMessageSize is the most read from a stream for a single message.
+const maxMessageSize = 1 << 20
+
+// ReceivedMessage is a message delivered by the network, with the peer it came
+// from.
+type ReceivedMessage struct {
+	From    peer.ID
+	Message Message
+}
+
+// Network sends messages to, and receives them from, the other oracles.
+type Network interface {
+	PeerID() peer.ID
+	AddPeer(peer.ID, ma.Multiaddr)
+	Send(to peer.ID, msg Message) error
+	Receive() <-chan ReceivedMessage
+	Close() error
+}
+
+type libp2pNetwork struct {
+	host     host.Host
+	chRecv   chan ReceivedMessage
+	chClosed chan struct{}
+	closer   sync.Once
+}
+
+// NewNetwork starts a libp2p host listening on the given port, identified by
+// the p2p key of the bundle, which exchanges messages with the oracles added
+// to it.
+func NewNetwork(kb *ocrkey.KeyBundle, port uint16) (Network, error) {
+	h, err := libp2p.New(
+		context.Background(),
+		libp2p.Identity(kb.P2PKey()),
+		libp2p.ListenAddrStrings(fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", port)),
+		libp2p.DisableRelay(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not start p2p host")
+	}
+
+	n := &libp2pNetwork{
+		host:     h,
+		chRecv:   make(chan ReceivedMessage, 100),
+		chClosed: make(chan struct{}),
+	}
+	h.SetStreamHandler(protocolID, n.handleStream)
+	logger.Infow("Listening for off-chain reporting peers", "peerID", h.ID().Pretty(), "addrs", h.Addrs())
+	return n, nil
+}
+
+func (n *libp2pNetwork) PeerID() peer.ID {
+	return n.host.ID()
+}
+
+// AddPeer records the address at which a peer can be reached.
+func (n *libp2pNetwork) AddPeer(id peer.ID, addr ma.Multiaddr) {
+	if id == n.host.ID() {
+		return
+	}
+	n.host.Peerstore().AddAddr(id, addr, peerstore.PermanentAddrTTL)
+}
+
+// Send delivers msg on a new stream to the given peer, or directly if it is
+// this node.
+func (n *libp2pNetwork) Send(to peer.ID, msg Message) error {
+	if to == n.host.ID() {
+		go n.deliver(ReceivedMessage{From: to, Message: msg})
+		return nil
+	}
+
+	s, err := n.host.NewStream(context.Background(), to, protocolID)
+	if err != nil {
+		return errors.Wrapf(err, "could not open stream to %s", to.Pretty())
+	}
+	if err := json.NewEncoder(s).Encode(msg); err != nil {
+		_ = s.Reset()
+		return errors.Wrapf(err, "could not send message to %s", to.Pretty())
+	}
+	go helpers.FullClose(s)
+	return nil
+}
+
+func (n *libp2pNetwork) Receive() <-chan ReceivedMessage {
+	return n.chRecv
+}
+
+func (n *libp2pNetwork) Close() error {
+	n.closer.Do(func() { close(n.chClosed) })
+	return n.host.Close()
+}
+
+func (n *libp2pNetwork) handleStream(s network.Stream) {
+	defer helpers.FullClose(s)
+
+	var msg Message
+	if err := json.NewDecoder(io.LimitReader(s, maxMessageSize)).Decode(&msg); err != nil {
+		logger.Warnw("Invalid off-chain reporting message", "peer", s.Conn().RemotePeer().Pretty(), "error", err)
+		return
+	}
+	n.deliver(ReceivedMessage{From: s.Conn().RemotePeer(), Message: msg})
+}
+
+func (n *libp2pNetwork) deliver(rm ReceivedMessage) {
+	select {
+	case n.chRecv <- rm:
+	case <-n.chClosed:
+	}
+}
@@ -0,0 +1,16 @@
+package offchainreporting
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	promOCRTransmittedReports = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "offchain_reporting_transmitted_reports_total",
+			Help: "The number of off-chain reports this node has transmitted as the leader of a round",
+		},
+		[]string{"job_spec_id"},
+	)
+)
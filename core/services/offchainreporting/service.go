@@ -0,0 +1,203 @@
+package offchainreporting
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/fluxmonitor"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
+
+// Service is the interface encapsulating all functionality needed to report
+// to aggregators off-chain, in concert with other oracles.
+type Service interface {
+	AddJob(models.JobSpec) error
+	RemoveJob(*models.ID)
+	Start() error
+	Stop()
+}
+
+type concreteService struct {
+	store      *store.Store
+	runManager fluxmonitor.RunManager
+	network    Network
+	disabled   bool
+
+	mu        sync.Mutex
+	instances map[common.Address]*instance
+	jobs      map[models.ID][]*instance
+
+	chStop chan struct{}
+	chDone chan struct{}
+}
+
+// New creates a service that runs off-chain reporting for each initiator of
+// type InitiatorOffchainReporting of added jobs, exchanging messages with the
+// other oracles over a p2p network.
+func New(store *store.Store, runManager fluxmonitor.RunManager) Service {
+	if !store.Config.FeatureOffchainReporting() {
+		return &concreteService{disabled: true}
+	}
+	return &concreteService{
+		store:      store,
+		runManager: runManager,
+		instances:  make(map[common.Address]*instance),
+		jobs:       make(map[models.ID][]*instance),
+		chStop:     make(chan struct{}),
+		chDone:     make(chan struct{}),
+	}
+}
+
+// Start joins the p2p network, with the node's default off-chain reporting
+// key bundle as its identity, and starts reporting for existing jobs.
+func (s *concreteService) Start() error {
+	if s.disabled {
+		logger.Info("Off-chain reporting disabled: skipping start")
+		return nil
+	}
+
+	kb, err := s.store.OCRKeyStore.Default()
+	if err != nil {
+		return errors.Wrap(err, "off-chain reporting requires a key bundle")
+	}
+	s.network, err = NewNetwork(kb, s.store.Config.P2PListenPort())
+	if err != nil {
+		return err
+	}
+	go s.receive()
+
+	return s.store.Jobs(func(j *models.JobSpec) bool {
+		if j == nil {
+			logger.Error("received nil job")
+			return true
+		}
+		if err := s.AddJob(*j); err != nil {
+			logger.Errorf("error adding off-chain reporting job: %v", err)
+		}
+		return true
+	}, models.InitiatorOffchainReporting)
+}
+
+// Stop stops reporting for all jobs and leaves the p2p network.
+func (s *concreteService) Stop() {
+	if s.disabled || s.network == nil {
+		logger.Info("Off-chain reporting disabled: cannot stop")
+		return
+	}
+
+	close(s.chStop)
+	<-s.chDone
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, instances := range s.jobs {
+		for _, i := range instances {
+			i.stop()
+		}
+	}
+	s.jobs = make(map[models.ID][]*instance)
+	s.instances = make(map[common.Address]*instance)
+	if err := s.network.Close(); err != nil {
+		logger.Errorw("Error closing off-chain reporting network", "error", err)
+	}
+}
+
+// AddJob starts reporting for any job initiators of type
+// InitiatorOffchainReporting.
+func (s *concreteService) AddJob(job models.JobSpec) error {
+	if s.disabled {
+		return nil
+	}
+	if job.ID == nil {
+		err := errors.New("received job with nil ID")
+		logger.Error(err)
+		return err
+	}
+	initrs := job.InitiatorsFor(models.InitiatorOffchainReporting)
+	if len(initrs) == 0 {
+		return nil
+	}
+	kb, err := s.store.OCRKeyStore.Default()
+	if err != nil {
+		return err
+	}
+
+	var instances []*instance
+	for _, initr := range initrs {
+		fetcher, err := fluxmonitor.NewFetcher(initr, s.store)
+		if err != nil {
+			return err
+		}
+		i, err := newInstance(initr, kb, s.network, fetcher, s.runManager)
+		if err != nil {
+			return errors.Wrap(err, "unable to create off-chain reporting instance")
+		}
+		instances = append(instances, i)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, i := range instances {
+		if _, exists := s.instances[i.initr.Address]; exists {
+			return errors.Errorf("already reporting off-chain to aggregator %s", i.initr.Address.Hex())
+		}
+	}
+	for _, i := range instances {
+		logger.Debugw("Adding job to off-chain reporting", "job_id", job.ID.String(), "initr", i.initr.ID)
+		s.instances[i.initr.Address] = i
+		i.start()
+	}
+	s.jobs[*job.ID] = instances
+	return nil
+}
+
+// RemoveJob stops reporting for all initiators of the job with the given ID.
+func (s *concreteService) RemoveJob(id *models.ID) {
+	if s.disabled {
+		return
+	}
+	if id == nil {
+		logger.Warn("nil job ID passed to OffchainReporting#RemoveJob")
+		return
+	}
+
+	s.mu.Lock()
+	instances := s.jobs[*id]
+	delete(s.jobs, *id)
+	for _, i := range instances {
+		delete(s.instances, i.initr.Address)
+	}
+	s.mu.Unlock()
+
+	for _, i := range instances {
+		i.stop()
+	}
+}
+
+// receive routes messages from the network to the instance reporting to
+// their aggregator, once it has checked they are from one of its oracles.
+func (s *concreteService) receive() {
+	defer close(s.chDone)
+	for {
+		select {
+		case rm := <-s.network.Receive():
+			s.mu.Lock()
+			i, ok := s.instances[rm.Message.Aggregator]
+			s.mu.Unlock()
+			if !ok {
+				logger.Debugw("Off-chain reporting message for unknown aggregator",
+					"aggregator", rm.Message.Aggregator.Hex(), "peer", rm.From.Pretty())
+				continue
+			}
+			if sender, ok := i.accept(rm.From, rm.Message); ok {
+				go i.process(sender, rm.Message)
+			}
+		case <-s.chStop:
+			return
+		}
+	}
+}
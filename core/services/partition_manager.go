@@ -0,0 +1,111 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/multierr"
+)
+
+var numberPartitionsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "database_partitions_dropped",
+	Help: "The number of monthly partitions dropped for being older than DATABASE_PARTITION_RETENTION",
+},
+	[]string{"table"},
+)
+
+// partitionManagerInterval is how often the PartitionManager creates and
+// drops partitions.
+const partitionManagerInterval = 24 * time.Hour
+
+// PartitionManager looks after the monthly partitions of job_runs and
+// tx_attempts. It creates them DATABASE_PARTITIONS_AHEAD months ahead, and
+// drops those which ended more than DATABASE_PARTITION_RETENTION ago, so
+// that old runs and attempts go away at once rather than row by row.
+type PartitionManager struct {
+	store    *store.Store
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewPartitionManager returns a new PartitionManager.
+func NewPartitionManager(store *store.Store) *PartitionManager {
+	return &PartitionManager{
+		store: store,
+		done:  make(chan struct{}),
+	}
+}
+
+// Start creates and drops partitions now, and then daily until stopped.
+func (pm *PartitionManager) Start() error {
+	logger.ErrorIf(pm.Maintain(), "failed to maintain database partitions")
+
+	pm.wg.Add(1)
+	go func() {
+		defer pm.wg.Done()
+		for {
+			select {
+			case <-pm.done:
+				return
+			case <-pm.store.Clock.After(partitionManagerInterval):
+				logger.ErrorIf(pm.Maintain(), "failed to maintain database partitions")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops maintaining partitions, waiting for any maintenance in progress.
+// Stopping it again does nothing.
+func (pm *PartitionManager) Stop() {
+	pm.stopOnce.Do(func() {
+		close(pm.done)
+		pm.wg.Wait()
+	})
+}
+
+// Maintain creates the partitions of the coming months and drops those past
+// retention. Partitions still holding runs which have not finished, or
+// attempts of transactions which are not confirmed, are kept.
+func (pm *PartitionManager) Maintain() error {
+	now := pm.store.Clock.Now()
+	retention := pm.store.Config.DatabasePartitionRetention()
+	var merr error
+	for _, table := range orm.PartitionedTables {
+		err := pm.store.CreateMonthPartitions(table, now, pm.store.Config.DatabasePartitionsAhead())
+		merr = multierr.Append(merr, err)
+		if retention.IsInstant() {
+			continue
+		}
+
+		partitions, err := pm.store.Partitions(table)
+		if err != nil {
+			merr = multierr.Append(merr, err)
+			continue
+		}
+		for _, partition := range partitions {
+			if partition.End.After(now.Add(-retention.Duration())) {
+				break
+			}
+			dropped, err := pm.store.DropPartition(table, partition.Name)
+			if err != nil {
+				merr = multierr.Append(merr, err)
+				continue
+			}
+			if !dropped {
+				logger.Warnw("Partition is past DATABASE_PARTITION_RETENTION but still in use, keeping it", "table", table, "partition", partition.Name)
+				continue
+			}
+			numberPartitionsDropped.WithLabelValues(table).Inc()
+			logger.Infow("Dropped partition past DATABASE_PARTITION_RETENTION", "table", table, "partition", partition.Name, "end", partition.End)
+		}
+	}
+	return merr
+}
@@ -0,0 +1,38 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionManager_Maintain(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("DATABASE_PARTITIONS_AHEAD", "3")
+
+	require.NoError(t, services.NewPartitionManager(store).Maintain())
+
+	now := time.Now().UTC()
+	for _, table := range orm.PartitionedTables {
+		partitions, err := store.Partitions(table)
+		require.NoError(t, err)
+		var names []string
+		for _, p := range partitions {
+			names = append(names, p.Name)
+		}
+		for i := 1; i <= 3; i++ {
+			month := time.Date(now.Year(), now.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+			assert.Contains(t, names, fmt.Sprintf("%s_y%04dm%02d", table, month.Year(), month.Month()))
+		}
+	}
+}
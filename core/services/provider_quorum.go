@@ -0,0 +1,135 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var promQuorumDiverged = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "eth_quorum_diverged",
+	Help: "1 while the Ethereum node and the provider at ETH_QUORUM_URL disagree on the chain, and transactions are not broadcast, else 0",
+})
+
+// ProviderQuorum cross-checks the chain of the Ethereum node with that of
+// the provider at ETH_QUORUM_URL, so that a single provider feeding the node
+// a bad view of the chain cannot have it send transactions. On every head,
+// both must agree on the hash of the block ETH_QUORUM_DEPTH blocks behind
+// it, the broadcasting of transactions being paused once they have not for
+// ETH_QUORUM_DIVERGENCE_THRESHOLD heads in a row.
+type ProviderQuorum struct {
+	store *store.Store
+	// divergences is the number of heads in a row checked so far which the
+	// providers disagreed on. Heads are checked one at a time.
+	divergences uint
+}
+
+// NewProviderQuorum returns a ProviderQuorum cross-checking the chain with
+// the quorum client of the store's TxManager.
+func NewProviderQuorum(store *store.Store) *ProviderQuorum {
+	return &ProviderQuorum{store: store}
+}
+
+// Connect checks the head the node connected at.
+func (q *ProviderQuorum) Connect(head *models.Head) error {
+	if head == nil {
+		return nil
+	}
+	return q.Check(head)
+}
+
+// Disconnect does nothing.
+func (q *ProviderQuorum) Disconnect() {}
+
+// OnNewHead checks every head.
+func (q *ProviderQuorum) OnNewHead(head *models.Head) {
+	logger.ErrorIf(q.Check(head), "failed to cross-check the chain with ETH_QUORUM_URL")
+}
+
+// Check compares the hashes the two providers have for the block
+// ETH_QUORUM_DEPTH blocks behind the head, pausing the broadcasting of
+// transactions once they have differed, or the provider at ETH_QUORUM_URL
+// has not had the block, for ETH_QUORUM_DIVERGENCE_THRESHOLD heads in a row,
+// and resuming it once they agree again. Nothing changes if either provider
+// cannot be reached.
+func (q *ProviderQuorum) Check(head *models.Head) error {
+	txManager := q.store.TxManager
+	quorum := txManager.QuorumClient()
+	number := head.Number - int64(q.store.Config.EthQuorumDepth())
+	if quorum == nil || number < 0 {
+		return nil
+	}
+
+	hex := hexutil.EncodeUint64(uint64(number))
+	block, err := txManager.GetBlockByNumber(hex)
+	if err != nil {
+		return errors.Wrapf(err, "fetching block %d from ETH_URL", number)
+	}
+	quorumBlock, err := quorum.GetBlockByNumber(hex)
+	if err != nil {
+		return errors.Wrapf(err, "fetching block %d from ETH_QUORUM_URL", number)
+	}
+
+	_, paused := txManager.BroadcastingPaused()
+	if block.Hash == quorumBlock.Hash {
+		q.divergences = 0
+		if paused {
+			logger.Infow("Ethereum providers agree on the chain again, resuming the broadcasting of transactions", "blockNumber", number)
+			txManager.ResumeBroadcasting()
+		}
+		promQuorumDiverged.Set(0)
+		return nil
+	}
+
+	q.divergences++
+	threshold := q.store.Config.EthQuorumDivergenceThreshold()
+	if q.divergences < threshold {
+		logger.Warnw("Ethereum providers disagree on the chain",
+			"blockNumber", number,
+			"hash", block.Hash.Hex(),
+			"quorumHash", quorumBlock.Hash.Hex(),
+			"divergences", q.divergences,
+			"threshold", threshold,
+		)
+		return nil
+	}
+
+	reason := fmt.Sprintf("ETH_URL has %s for block %d, but ETH_QUORUM_URL has %s", block.Hash.Hex(), number, quorumBlock.Hash.Hex())
+	if !paused {
+		logger.Errorw("Ethereum providers disagree on the chain, pausing the broadcasting of transactions",
+			"blockNumber", number,
+			"hash", block.Hash.Hex(),
+			"quorumHash", quorumBlock.Hash.Hex(),
+			"divergences", q.divergences,
+		)
+	}
+	txManager.PauseBroadcasting(reason)
+	promQuorumDiverged.Set(1)
+	return nil
+}
+
+// confirmedByQuorum returns an error unless the provider at ETH_QUORUM_URL,
+// if any, has the transaction in the block with blockHash too, so that the
+// logs runs act on are not those of a single provider.
+func confirmedByQuorum(txManager store.TxManager, txHash, blockHash common.Hash) error {
+	quorum := txManager.QuorumClient()
+	if quorum == nil {
+		return nil
+	}
+	receipt, err := quorum.GetTxReceipt(txHash)
+	if err != nil {
+		return errors.Wrap(err, "fetching receipt from ETH_QUORUM_URL")
+	}
+	if receipt.Unconfirmed() || receipt.BlockHash == nil || *receipt.BlockHash != blockHash {
+		return fmt.Errorf("TxHash %s is not in block %s according to ETH_QUORUM_URL", txHash.Hex(), blockHash.Hex())
+	}
+	return nil
+}
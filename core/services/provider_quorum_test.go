@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderQuorum_Check(t *testing.T) {
+	t.Parallel()
+
+	config, cfgCleanup := cltest.NewConfig(t)
+	defer cfgCleanup()
+	config.Set("ETH_QUORUM_DEPTH", 2)
+	config.Set("ETH_QUORUM_DIVERGENCE_THRESHOLD", 2)
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+
+	primary := new(mocks.Client)
+	secondary := new(mocks.Client)
+	manager := strpkg.NewEthTxManager(primary, store.Config, store.KeyStore, store.ORM)
+	manager.SetQuorumClient(secondary)
+	store.TxManager = manager
+	quorum := services.NewProviderQuorum(store)
+
+	hash := cltest.NewHash()
+	primary.On("GetBlockByNumber", "0x8").Return(eth.Block{Hash: hash}, nil)
+	secondary.On("GetBlockByNumber", "0x8").Return(eth.Block{Hash: hash}, nil)
+	require.NoError(t, quorum.Check(cltest.Head(10)))
+	_, paused := manager.BroadcastingPaused()
+	assert.False(t, paused)
+
+	primary.On("GetBlockByNumber", "0x9").Return(eth.Block{Hash: hash}, nil)
+	secondary.On("GetBlockByNumber", "0x9").Return(eth.Block{Hash: cltest.NewHash()}, nil)
+	require.NoError(t, quorum.Check(cltest.Head(11)))
+	_, paused = manager.BroadcastingPaused()
+	assert.False(t, paused, "a single divergence is under the threshold")
+
+	primary.On("GetBlockByNumber", "0xa").Return(eth.Block{Hash: hash}, nil)
+	secondary.On("GetBlockByNumber", "0xa").Return(eth.Block{Hash: cltest.NewHash()}, nil)
+	require.NoError(t, quorum.Check(cltest.Head(12)))
+	reason, paused := manager.BroadcastingPaused()
+	assert.True(t, paused)
+	assert.Contains(t, reason, "block 10")
+
+	primary.On("GetBlockByNumber", "0xb").Return(eth.Block{Hash: hash}, nil)
+	secondary.On("GetBlockByNumber", "0xb").Return(eth.Block{}, errors.New("connection refused"))
+	require.Error(t, quorum.Check(cltest.Head(13)))
+	_, paused = manager.BroadcastingPaused()
+	assert.True(t, paused, "unreachable provider should not resume broadcasting")
+
+	primary.On("GetBlockByNumber", "0xc").Return(eth.Block{Hash: hash}, nil)
+	secondary.On("GetBlockByNumber", "0xc").Return(eth.Block{Hash: hash}, nil)
+	require.NoError(t, quorum.Check(cltest.Head(14)))
+	_, paused = manager.BroadcastingPaused()
+	assert.False(t, paused)
+
+	// Agreeing resets the count of divergences
+	primary.On("GetBlockByNumber", "0xd").Return(eth.Block{Hash: hash}, nil)
+	secondary.On("GetBlockByNumber", "0xd").Return(eth.Block{Hash: cltest.NewHash()}, nil)
+	require.NoError(t, quorum.Check(cltest.Head(15)))
+	_, paused = manager.BroadcastingPaused()
+	assert.False(t, paused)
+
+	require.NoError(t, quorum.Check(cltest.Head(1)), "heads shallower than the depth are not checked")
+}
@@ -28,4 +28,11 @@ func (sr *storeReaper) Work() {
 	if err != nil {
 		logger.Error("unable to reap stale sessions: ", err)
 	}
+
+	if retention := sr.config.AccessViolationRetention(); retention.Duration() > 0 {
+		err := sr.store.DeleteAccessViolationsBefore(retention.Before(time.Now()))
+		if err != nil {
+			logger.Error("unable to reap access violations: ", err)
+		}
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 
+	"github.com/jinzhu/gorm"
 	"github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,3 +61,32 @@ func TestStoreReaper_ReapSessions(t *testing.T) {
 		})
 	}
 }
+
+func TestStoreReaper_ReapAccessViolations(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("ACCESS_VIOLATION_RETENTION", "1h")
+
+	violation := models.AccessViolation{Endpoint: "api", RemoteIP: "10.0.0.1", Method: "GET", Path: "/v2/specs"}
+	require.NoError(t, store.RecordAccessViolation(violation))
+	violation.RemoteIP = "10.0.0.2"
+	require.NoError(t, store.RecordAccessViolation(violation))
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Exec(`UPDATE access_violations SET last_seen_at = now() - interval '2 hours' WHERE remote_ip = '10.0.0.1'`).Error
+	}))
+
+	r := services.NewStoreReaper(store)
+	defer r.Stop()
+	r.WakeUp()
+
+	gomega.NewGomegaWithT(t).Eventually(func() []models.AccessViolation {
+		violations, _, err := store.AccessViolations(0, 10)
+		assert.NoError(t, err)
+		return violations
+	}).Should(gomega.HaveLen(1))
+	violations, _, err := store.AccessViolations(0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2", violations[0].RemoteIP)
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
@@ -30,11 +32,15 @@ var (
 // RunExecutor handles the actual running of the job tasks
 type RunExecutor interface {
 	Execute(*models.ID) error
+	Interrupt(*models.ID) bool
 }
 
 type runExecutor struct {
 	store       *store.Store
 	statsPusher synchronization.StatsPusher
+
+	executingMutex sync.Mutex
+	executing      map[string]context.CancelFunc
 }
 
 // NewRunExecutor initializes a RunExecutor.
@@ -42,15 +48,67 @@ func NewRunExecutor(store *store.Store, statsPusher synchronization.StatsPusher)
 	return &runExecutor{
 		store:       store,
 		statsPusher: statsPusher,
+		executing:   make(map[string]context.CancelFunc),
+	}
+}
+
+// Interrupt cancels the context the tasks of the run are executing in, if it
+// is being executed, so that they stop at the first chance. The execution
+// then ends without saving the run or executing any further task, the run
+// being left to whoever interrupted it, such as the cancellation of the run.
+func (re *runExecutor) Interrupt(runID *models.ID) bool {
+	re.executingMutex.Lock()
+	defer re.executingMutex.Unlock()
+
+	cancel, ok := re.executing[runID.String()]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// startExecuting returns the context the run executes in until done is
+// called.
+func (re *runExecutor) startExecuting(runID *models.ID) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	re.executingMutex.Lock()
+	re.executing[runID.String()] = cancel
+	re.executingMutex.Unlock()
+
+	return ctx, func() {
+		re.executingMutex.Lock()
+		delete(re.executing, runID.String())
+		re.executingMutex.Unlock()
+		cancel()
 	}
 }
 
+// interrupted returns true, logging it, if the execution of the run was
+// interrupted.
+func interrupted(ctx context.Context, run *models.JobRun) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+	logger.Debugw("Run execution interrupted", run.ForLogger()...)
+	return true
+}
+
 // Execute performs the work associate with a job run
 func (re *runExecutor) Execute(runID *models.ID) error {
+	ctx, done := re.startExecuting(runID)
+	defer done()
+
 	run, err := re.store.Unscoped().FindJobRun(runID)
 	if err != nil {
 		return errors.Wrapf(err, "error finding run %s", runID)
 	}
+	if err := re.store.LoadRunResultBlobs(&run); err != nil {
+		return errors.Wrapf(err, "error loading oversized results of run %s", runID)
+	}
+
+	if run.IsTaskGraph() {
+		return re.executeTaskGraph(ctx, &run)
+	}
 
 	for taskIndex := range run.TaskRuns {
 		taskRun := &run.TaskRuns[taskIndex]
@@ -62,11 +120,14 @@ func (re *runExecutor) Execute(runID *models.ID) error {
 		if taskRun.Status.Completed() {
 			continue
 		}
+		if interrupted(ctx, &run) {
+			return nil
+		}
 
 		if meetsMinimumConfirmations(&run, taskRun, run.ObservedHeight) {
 			start := time.Now()
 
-			result := re.executeTask(&run, taskRun)
+			result := re.executeTask(ctx, &run, taskRun)
 
 			taskRun.ApplyOutput(result)
 			run.ApplyOutput(result)
@@ -84,6 +145,9 @@ func (re *runExecutor) Execute(runID *models.ID) error {
 
 		}
 
+		if interrupted(ctx, &run) {
+			return nil
+		}
 		if err := re.store.ORM.SaveJobRun(&run); errors.Cause(err) == orm.OptimisticUpdateConflictError {
 			logger.Debugw("Optimistic update conflict while updating run", run.ForLogger()...)
 			return nil
@@ -104,10 +168,28 @@ func (re *runExecutor) Execute(runID *models.ID) error {
 	return nil
 }
 
-func (re *runExecutor) executeTask(run *models.JobRun, taskRun *models.TaskRun) models.RunOutput {
+func (re *runExecutor) executeTask(ctx context.Context, run *models.JobRun, taskRun *models.TaskRun) models.RunOutput {
 	taskCopy := taskRun.TaskSpec // deliberately copied to keep mutations local
 
-	params, err := models.Merge(run.RunRequest.RequestParams, taskCopy.Params)
+	// Only the secrets referenced by the spec are resolved, not those in the
+	// params of the request, which requesters could otherwise read secrets
+	// with, and only from the namespace of the job. The resolved params are
+	// not saved with the run.
+	specParams, err := models.ResolveSecrets(taskCopy.Params, func(name string) (string, error) {
+		namespace, err := re.store.JobNamespace(run.JobSpecID)
+		if err != nil {
+			return "", err
+		}
+		return re.store.SecretValue(namespace, name)
+	})
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+	params, err := models.Merge(run.RunRequest.RequestParams, specParams)
+	if err != nil {
+		return models.NewRunOutputError(err)
+	}
+	unresolvedParams, err := models.Merge(run.RunRequest.RequestParams, taskCopy.Params)
 	if err != nil {
 		return models.NewRunOutputError(err)
 	}
@@ -117,12 +199,28 @@ func (re *runExecutor) executeTask(run *models.JobRun, taskRun *models.TaskRun)
 	if err != nil {
 		return models.NewRunOutputError(err)
 	}
+	// Bridges merge their params into their output, so they are only sent
+	// the params with the secrets resolved. Their callbacks resume the task
+	// run which called them.
+	if bridge, ok := adapter.BaseAdapter.(*adapters.Bridge); ok {
+		bridge.RequestParams = &params
+		bridge.Params = unresolvedParams
+		bridge.TaskRunID = taskRun.ID
+	}
+	// The outgoing confirmations come from the validated job spec alone, so
+	// they are set on the adapter rather than merged in with the params.
+	if taskRun.MinimumOutgoingConfirmations.Valid {
+		switch tx := adapter.BaseAdapter.(type) {
+		case *adapters.EthTx:
+			tx.MinOutgoingConfirmations = taskRun.MinimumOutgoingConfirmations.Uint32
+		case *adapters.EthTxABIEncode:
+			tx.MinOutgoingConfirmations = taskRun.MinimumOutgoingConfirmations.Uint32
+		}
+	}
 
-	previousTaskRun := run.PreviousTaskRun()
-
-	previousTaskInput := models.JSON{}
-	if previousTaskRun != nil {
-		previousTaskInput = previousTaskRun.Result.Data
+	previousTaskInput, err := run.TaskRunInput(taskRun)
+	if err != nil {
+		return models.NewRunOutputError(err)
 	}
 
 	data, err := models.Merge(run.RunRequest.RequestParams, previousTaskInput, taskRun.Result.Data)
@@ -130,9 +228,99 @@ func (re *runExecutor) executeTask(run *models.JobRun, taskRun *models.TaskRun)
 		return models.NewRunOutputError(err)
 	}
 
-	input := *models.NewRunInput(run.ID, data, taskRun.Status)
+	input := models.NewRunInput(run.ID, data, taskRun.Status).WithContext(ctx)
 	result := adapter.Perform(input, re.store)
 	promAdapterCallsVec.WithLabelValues(run.JobSpecID.String(), string(adapter.TaskType()), string(result.Status())).Inc()
 
 	return result
 }
+
+// executeTaskGraph runs the tasks of a run whose tasks declare their inputs.
+// The tasks whose inputs are all complete run concurrently, round after
+// round, until none are left or the run has to wait. Once a task has to wait,
+// no more tasks start, the run waiting until every bridge it waits on has
+// answered. A bridge is called by one task per round, as bridges may answer
+// asynchronously, and the answer of a bridge must resume the task which
+// called it.
+func (re *runExecutor) executeTaskGraph(ctx context.Context, run *models.JobRun) error {
+	for run.GetStatus().Runnable() {
+		if interrupted(ctx, run) {
+			return nil
+		}
+		ready := withDistinctBridges(run.ReadyTaskRuns())
+		if len(ready) == 0 {
+			break
+		}
+
+		var unconfirmed *models.TaskRun
+		for _, taskRun := range ready {
+			if !meetsMinimumConfirmations(run, taskRun, run.ObservedHeight) {
+				unconfirmed = taskRun
+				break
+			}
+		}
+		if unconfirmed != nil {
+			logger.Debugw("Pausing run pending confirmations",
+				run.ForLogger("required_height", unconfirmed.MinimumConfirmations)...,
+			)
+			unconfirmed.Status = models.RunStatusPendingConfirmations
+			run.SetStatus(models.RunStatusPendingConfirmations)
+		} else {
+			start := time.Now()
+			results := make([]models.RunOutput, len(ready))
+			var wg sync.WaitGroup
+			for i, taskRun := range ready {
+				wg.Add(1)
+				go func(i int, taskRun *models.TaskRun) {
+					defer wg.Done()
+					results[i] = re.executeTask(ctx, run, taskRun)
+				}(i, taskRun)
+			}
+			wg.Wait()
+
+			for i, taskRun := range ready {
+				taskRun.ApplyOutput(results[i])
+			}
+			run.ApplyTaskGraphOutputs(results)
+			logger.Debugw(fmt.Sprintf("Executed %d tasks", len(ready)), run.ForLogger("elapsed", time.Since(start).Seconds())...)
+		}
+
+		if interrupted(ctx, run) {
+			return nil
+		}
+		if err := re.store.ORM.SaveJobRun(run); errors.Cause(err) == orm.OptimisticUpdateConflictError {
+			logger.Debugw("Optimistic update conflict while updating run", run.ForLogger()...)
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		re.statsPusher.PushNow()
+	}
+
+	if run.GetStatus().Finished() {
+		if run.GetStatus().Errored() {
+			logger.Warnw("Task failed", run.ForLogger()...)
+		} else {
+			logger.Debugw("All tasks complete for run", run.ForLogger()...)
+		}
+	}
+	return nil
+}
+
+// withDistinctBridges returns the task runs, leaving out those of tasks which
+// call a bridge another one before them calls.
+func withDistinctBridges(taskRuns []*models.TaskRun) []*models.TaskRun {
+	var filtered []*models.TaskRun
+	bridges := map[models.TaskType]bool{}
+	for _, taskRun := range taskRuns {
+		if _, builtin, _ := adapters.ForBuiltin(taskRun.TaskSpec); !builtin {
+			if bridges[taskRun.TaskSpec.Type] {
+				continue
+			}
+			bridges[taskRun.TaskSpec.Type] = true
+		}
+		filtered = append(filtered, taskRun)
+	}
+	return filtered
+}
@@ -3,7 +3,10 @@ package services_test
 import (
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -17,7 +20,9 @@ import (
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 )
 
 func TestRunExecutor_Execute(t *testing.T) {
@@ -57,6 +62,48 @@ func TestRunExecutor_Execute(t *testing.T) {
 	assert.Equal(t, assets.NewLink(9117), actual)
 }
 
+func TestRunExecutor_Execute_TaskGraph(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+
+	runExecutor := services.NewRunExecutor(store, pusher)
+
+	j := models.NewJob()
+	j.Initiators = []models.Initiator{{Type: models.InitiatorWeb}}
+	double := cltest.NewTask(t, "multiply", `{"times":2}`)
+	double.Name = "double"
+	tenfold := cltest.NewTask(t, "multiply", `{"times":10}`)
+	tenfold.Name = "tenfold"
+	join := cltest.NewTask(t, "noop")
+	join.Name = "join"
+	join.Inputs = []string{"tenfold", "double"}
+	j.Tasks = []models.TaskSpec{double, tenfold, join}
+	require.NoError(t, services.ValidateJob(j, store))
+	require.NoError(t, store.CreateJob(&j))
+
+	found, err := store.FindJob(j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenfold", "double"}, found.Tasks[2].Inputs)
+
+	run := cltest.NewJobRun(j)
+	run.RunRequest.RequestParams = cltest.JSONFromString(t, `{"result":"3"}`)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	require.NoError(t, runExecutor.Execute(run.ID))
+
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCompleted, run.GetStatus())
+	assert.Equal(t, "6", run.TaskRuns[0].Result.Data.Get("result").String())
+	assert.Equal(t, "30", run.TaskRuns[1].Result.Data.Get("result").String())
+	assert.JSONEq(t, `["30","6"]`, run.Result.Data.Get("result").Raw)
+}
+
 func TestRunExecutor_Execute_Pending(t *testing.T) {
 	t.Parallel()
 
@@ -145,6 +192,7 @@ func TestRunExecutor_Execute_CancelActivelyRunningTask(t *testing.T) {
 	time.Sleep(1 * time.Second)
 
 	runQueue := new(mocks.RunQueue)
+	runQueue.On("Interrupt", run.ID).Return(runExecutor.Interrupt)
 	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, pusher, store.TxManager, clock)
 	runManager.Cancel(run.ID)
 
@@ -163,6 +211,61 @@ func TestRunExecutor_Execute_CancelActivelyRunningTask(t *testing.T) {
 	assert.Nil(t, actual)
 }
 
+func TestRunExecutor_Execute_InterruptBlockedTask(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+
+	runExecutor := services.NewRunExecutor(store, pusher)
+
+	requested := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.TaskSpec{
+		cltest.NewTask(t, "httpgetwithunrestrictednetworkaccess", fmt.Sprintf(`{"get": "%s"}`, server.URL)),
+		cltest.NewTask(t, "noop"),
+	}
+	require.NoError(t, store.CreateJob(&j))
+	run := cltest.NewJobRun(j)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	executed := make(chan error)
+	go func() {
+		executed <- runExecutor.Execute(run.ID)
+	}()
+	<-requested
+
+	runQueue := new(mocks.RunQueue)
+	runQueue.On("Interrupt", run.ID).Return(runExecutor.Interrupt)
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, pusher, store.TxManager, store.Clock)
+	_, err := runManager.Cancel(run.ID)
+	require.NoError(t, err)
+
+	select {
+	case err := <-executed:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution of the run was not interrupted")
+	}
+
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCancelled, run.GetStatus())
+	require.Len(t, run.TaskRuns, 2)
+	assert.Equal(t, models.RunStatusCancelled, run.TaskRuns[0].Status)
+	assert.Equal(t, models.RunStatusUnstarted, run.TaskRuns[1].Status)
+	assert.False(t, runExecutor.Interrupt(run.ID), "run should no longer be executing")
+}
+
 func TestRunExecutor_InitialTaskLacksConfirmations(t *testing.T) {
 	t.Parallel()
 
@@ -222,3 +325,213 @@ func TestJobRunner_prioritizeSpecParamsOverRequestParams(t *testing.T) {
 	expected := strconv.FormatUint(uint64(requestBase*specParameter), 10)
 	assert.Equal(t, expected, actual)
 }
+
+func TestRunExecutor_Execute_ResolvesSecrets(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+	runExecutor := services.NewRunExecutor(store, pusher)
+
+	secret, err := models.NewSecret(models.SecretRequest{Name: "api_key", Value: "s3cr3t"})
+	require.NoError(t, err)
+	require.NoError(t, store.SetSecret(&secret))
+
+	mock, assertCalled := cltest.NewHTTPMockServer(t, http.StatusOK, "GET", `{"price":"100"}`,
+		func(header http.Header, _ string) {
+			assert.Equal(t, "Bearer s3cr3t", header.Get("Authorization"))
+		})
+	defer assertCalled()
+
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.TaskSpec{{
+		Type: adapters.TaskTypeHTTPGet,
+		Params: cltest.JSONFromString(t, `{"get":"%s","headers":{"Authorization":["Bearer {{secret \"api_key\"}}"]}}`, mock.URL),
+	}}
+	require.NoError(t, store.CreateJob(&j))
+	run := cltest.NewJobRun(j)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	require.NoError(t, runExecutor.Execute(run.ID))
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCompleted, run.GetStatus())
+	assert.NotContains(t, run.Result.Data.String(), "s3cr3t")
+	assert.NotContains(t, run.TaskRuns[0].Result.Data.String(), "s3cr3t")
+}
+
+func TestRunExecutor_Execute_ResolvesSecretsForBridges(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+	runExecutor := services.NewRunExecutor(store, pusher)
+
+	secret, err := models.NewSecret(models.SecretRequest{Name: "api_key", Value: "s3cr3t"})
+	require.NoError(t, err)
+	require.NoError(t, store.SetSecret(&secret))
+
+	server, assertCalled := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", `{"data":{"price":"100"}}`,
+		func(_ http.Header, body string) {
+			assert.Equal(t, "s3cr3t", gjson.Get(body, "data.key").String())
+		})
+	defer assertCalled()
+	_, bt := cltest.NewBridgeType(t, "secretive", server.URL)
+	require.NoError(t, store.CreateBridgeType(bt))
+
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.TaskSpec{{
+		Type:   bt.Name,
+		Params: cltest.JSONFromString(t, `{"key":"{{secret \"api_key\"}}"}`),
+	}}
+	require.NoError(t, store.CreateJob(&j))
+	run := cltest.NewJobRun(j)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	require.NoError(t, runExecutor.Execute(run.ID))
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCompleted, run.GetStatus())
+	assert.Equal(t, "100", run.TaskRuns[0].Result.Data.Get("price").String())
+	assert.NotContains(t, run.Result.Data.String(), "s3cr3t")
+	assert.NotContains(t, run.TaskRuns[0].Result.Data.String(), "s3cr3t")
+}
+
+func TestRunExecutor_Execute_SecretsOfOtherNamespaces(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+	runExecutor := services.NewRunExecutor(store, pusher)
+
+	secret, err := models.NewSecret(models.SecretRequest{Name: "api_key", Namespace: "team-a", Value: "s3cr3t"})
+	require.NoError(t, err)
+	require.NoError(t, store.SetSecret(&secret))
+
+	j := cltest.NewJobWithWebInitiator()
+	j.Namespace = "team-b"
+	j.Tasks = []models.TaskSpec{{
+		Type:   adapters.TaskTypeNoOp,
+		Params: cltest.JSONFromString(t, `{"key":"{{secret \"api_key\"}}"}`),
+	}}
+	require.NoError(t, store.CreateJob(&j))
+	run := cltest.NewJobRun(j)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	require.NoError(t, runExecutor.Execute(run.ID))
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusErrored, run.GetStatus())
+	assert.NotContains(t, run.TaskRuns[0].Result.Data.String(), "s3cr3t")
+}
+
+func TestRunExecutor_Execute_TaskGraph_AsyncBridges(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+	runQueue := new(mocks.RunQueue)
+	runQueue.On("Run", mock.Anything).Maybe().Return(nil)
+	runExecutor := services.NewRunExecutor(store, pusher)
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, pusher, store.TxManager, store.Clock)
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	called := func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		copied := map[string]int{}
+		for name, count := range calls {
+			copied[name] = count
+		}
+		return copied
+	}
+	for _, name := range []string{"asynca", "asyncb"} {
+		name := name
+		server, cleanup := cltest.NewHTTPMockServer(t, http.StatusOK, "POST", `{"pending":true}`,
+			func(http.Header, string) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls[name]++
+			})
+		defer cleanup()
+		_, bt := cltest.NewBridgeType(t, name, server.URL)
+		require.NoError(t, store.CreateBridgeType(bt))
+	}
+	var tasks []models.TaskSpec
+	for _, task := range []struct{ name, bridge string }{{"a", "asynca"}, {"b", "asyncb"}, {"again", "asynca"}} {
+		spec := cltest.NewTask(t, task.bridge)
+		spec.Name = task.name
+		tasks = append(tasks, spec)
+	}
+	join := cltest.NewTask(t, "noop")
+	join.Name = "join"
+	join.Inputs = []string{"a", "b", "again"}
+
+	j := models.NewJob()
+	j.Initiators = []models.Initiator{{Type: models.InitiatorWeb}}
+	j.Tasks = append(tasks, join)
+	require.NoError(t, store.CreateJob(&j))
+	run := cltest.NewJobRun(j)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	resume := func(i int, answer string) {
+		found, err := store.FindJobRun(run.ID)
+		require.NoError(t, err)
+		result := models.BridgeRunResult{
+			Data:      cltest.JSONFromString(t, fmt.Sprintf(`{"result":"%s"}`, answer)),
+			Status:    models.RunStatusCompleted,
+			TaskRunID: found.TaskRuns[i].ID,
+		}
+		require.NoError(t, runManager.ResumePending(run.ID, result))
+	}
+
+	// Distinct bridges are called together, the task calling a bridge again
+	// waiting for the next round
+	require.NoError(t, runExecutor.Execute(run.ID))
+	found, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingBridge, found.GetStatus())
+	assert.Equal(t, models.RunStatusPendingBridge, found.TaskRuns[0].Status)
+	assert.Equal(t, models.RunStatusPendingBridge, found.TaskRuns[1].Status)
+	assert.Equal(t, map[string]int{"asynca": 1, "asyncb": 1}, called())
+
+	// The run waits until each bridge answered, whatever the order, each
+	// answer being recorded for the task which called the bridge
+	resume(1, "2")
+	found, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingBridge, found.GetStatus())
+	assert.Equal(t, models.RunStatusPendingBridge, found.TaskRuns[0].Status)
+	assert.Equal(t, "2", found.TaskRuns[1].Result.Data.Get("result").String())
+	resume(0, "1")
+	found, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusInProgress, found.GetStatus())
+	assert.Equal(t, "1", found.TaskRuns[0].Result.Data.Get("result").String())
+
+	require.NoError(t, runExecutor.Execute(run.ID))
+	found, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingBridge, found.TaskRuns[2].Status)
+	assert.Equal(t, map[string]int{"asynca": 2, "asyncb": 1}, called())
+	resume(2, "3")
+
+	require.NoError(t, runExecutor.Execute(run.ID))
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCompleted, run.GetStatus())
+	assert.JSONEq(t, `["1","2","3"]`, run.Result.Data.Get("result").Raw)
+}
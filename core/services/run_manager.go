@@ -3,6 +3,7 @@ package services
 import (
 	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
@@ -16,6 +17,17 @@ import (
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	promRunsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_runs_started_total",
+		Help: "The total number of Job Runs created for each job",
+	},
+		[]string{"job_spec_id"},
+	)
 )
 
 // RecurringScheduleJobError contains the field for the error message.
@@ -45,11 +57,18 @@ type RunManager interface {
 	ResumePending(
 		runID *models.ID,
 		input models.BridgeRunResult) error
+	ResumePendingManually(
+		runID *models.ID,
+		input models.BridgeRunResult,
+		resumedBy string) error
 	Cancel(runID *models.ID) (*models.JobRun, error)
 
 	ResumeAllInProgress() error
 	ResumeAllConfirming(currentBlockHeight *big.Int) error
 	ResumeAllConnecting() error
+	ResumeAllParked() error
+	ResumeAllSleeping() (time.Time, error)
+	ResumeStuck(runID *models.ID, stuckBefore time.Time) error
 }
 
 // runManager implements RunManager
@@ -60,6 +79,10 @@ type runManager struct {
 	txManager   store.TxManager
 	config      orm.ConfigReader
 	clock       utils.AfterNower
+
+	// admitMutex serializes checking the concurrency limits with starting
+	// the runs admitted by them.
+	admitMutex sync.Mutex
 }
 
 func runCost(job *models.JobSpec, config orm.ConfigReader, adapters []*adapters.PipelineAdapter) *assets.Link {
@@ -103,21 +126,34 @@ func NewRun(
 		}
 
 		runAdapters = append(runAdapters, adapter)
+		if sendsTransaction(task) {
+			run.TaskRuns[i].MinimumOutgoingConfirmations = clnull.Uint32From(utils.MaxUint32(
+				uint32(config.MinOutgoingConfirmations()),
+				job.OutgoingConfirmations.Uint32,
+				task.OutgoingConfirmations.Uint32,
+			))
+		}
 		if currentHeight == nil {
 			continue
 		}
 
-		run.TaskRuns[i].MinimumConfirmations = clnull.Uint32From(
-			utils.MaxUint32(
-				config.MinIncomingConfirmations(),
-				task.Confirmations.Uint32,
-				adapter.MinConfs()),
-		)
+		run.TaskRuns[i].MinimumConfirmations = clnull.Uint32From(utils.MaxUint32(
+			config.MinIncomingConfirmations(),
+			job.IncomingConfirmations.Uint32,
+			task.Confirmations.Uint32,
+			adapter.MinConfs(),
+		))
 	}
 
 	return &run, runAdapters
 }
 
+// sendsTransaction returns true if the task sends a transaction, waiting for
+// the outgoing confirmations of its job and itself.
+func sendsTransaction(task models.TaskSpec) bool {
+	return task.Type == adapters.TaskTypeEthTx || task.Type == adapters.TaskTypeEthTxABIEncode
+}
+
 // ValidateRun ensures that a run's initial preconditions have been met
 func ValidateRun(run *models.JobRun, contractCost *assets.Link) {
 
@@ -189,7 +225,7 @@ func (rm *runManager) Create(
 	runRequest *models.RunRequest,
 ) (*models.JobRun, error) {
 	logger.Debugw(fmt.Sprintf("New run triggered by %s", initiator.Type),
-		"job", jobSpecID.String(),
+		"job_id", jobSpecID.String(),
 		"creation_height", creationHeight.String(),
 	)
 
@@ -225,9 +261,28 @@ func (rm *runManager) Create(
 	runCost := runCost(&job, rm.config, adapters)
 	ValidateRun(run, runCost)
 
+	rm.admitMutex.Lock()
+	defer rm.admitMutex.Unlock()
+
+	if run.GetStatus() == models.RunStatusInProgress {
+		limited, err := rm.concurrencyLimitReached(&job)
+		if err != nil {
+			return nil, err
+		} else if limited {
+			logger.Debugw("Parking run until the concurrency limits allow it to execute", run.ForLogger()...)
+			run.SetStatus(models.RunStatusPendingConcurrency)
+		}
+	}
+
+	runID := run.ID
 	if err := rm.orm.CreateJobRun(run); err != nil {
 		return nil, errors.Wrap(err, "CreateJobRun failed")
 	}
+	if run.ID.String() != runID.String() {
+		logger.Debugw("Run already created for the log", run.ForLogger()...)
+		return run, nil
+	}
+	promRunsStarted.WithLabelValues(job.ID.String()).Inc()
 	rm.statsPusher.PushNow()
 
 	if run.GetStatus().Runnable() {
@@ -241,9 +296,11 @@ func (rm *runManager) Create(
 }
 
 // ResumeAllConfirming wakes up all jobs that were sleeping because they were
-// waiting for block confirmations.
+// waiting for block confirmations, cancelling those which have waited for
+// their incoming confirmations past INCOMING_CONFIRMATIONS_TIMEOUT.
 func (rm *runManager) ResumeAllConfirming(currentBlockHeight *big.Int) error {
-	return rm.orm.UnscopedJobRunsWithStatus(func(run *models.JobRun) {
+	expired := false
+	err := rm.orm.UnscopedJobRunsWithStatus(func(run *models.JobRun) {
 		currentTaskRun := run.NextTaskRun()
 		if currentTaskRun == nil {
 			rm.updateWithError(run, "Attempting to resume confirming run with no remaining tasks %s", run.ID)
@@ -251,6 +308,11 @@ func (rm *runManager) ResumeAllConfirming(currentBlockHeight *big.Int) error {
 		}
 
 		run.ObservedHeight = utils.NewBig(currentBlockHeight)
+		if rm.incomingConfirmationsExpired(run) {
+			expired = true
+			rm.expire(run)
+			return
+		}
 		logger.Debugw(fmt.Sprintf("New head #%s resuming run", currentBlockHeight), run.ForLogger()...)
 
 		validateMinimumConfirmations(run, currentTaskRun, run.ObservedHeight, rm.txManager)
@@ -260,6 +322,38 @@ func (rm *runManager) ResumeAllConfirming(currentBlockHeight *big.Int) error {
 			logger.Errorw("Error saving run", run.ForLogger("error", err)...)
 		}
 	}, models.RunStatusPendingConnection, models.RunStatusPendingConfirmations)
+	if err != nil || !expired {
+		return err
+	}
+	return rm.ResumeAllParked()
+}
+
+// incomingConfirmationsExpired returns true if the run has waited longer than
+// INCOMING_CONFIRMATIONS_TIMEOUT for the confirmations of the log initiating
+// it, which it will then never get, e.g. because the log was reorged away.
+// Only the time since the run was created counts: the blocks mined since the
+// log are counted as its confirmations whether or not it is still on chain.
+func (rm *runManager) incomingConfirmationsExpired(run *models.JobRun) bool {
+	timeout := rm.config.IncomingConfirmationsTimeout().Duration()
+	if timeout == 0 || run.GetStatus() != models.RunStatusPendingConfirmations {
+		return false
+	}
+	return rm.clock.Now().Sub(run.CreatedAt) > timeout
+}
+
+// expire cancels a run whose incoming confirmations have expired.
+func (rm *runManager) expire(run *models.JobRun) {
+	logger.Warnw("Cancelling run waiting too long for incoming confirmations",
+		run.ForLogger("timeout", rm.config.IncomingConfirmationsTimeout())...,
+	)
+	run.CancelWithReason(fmt.Sprintf(
+		"Run cancelled after waiting more than %v for incoming confirmations",
+		rm.config.IncomingConfirmationsTimeout(),
+	))
+	if err := rm.orm.SaveJobRun(run); err != nil {
+		logger.Errorw("Error saving run", run.ForLogger("error", err)...)
+	}
+	rm.statsPusher.PushNow()
 }
 
 // ResumeAllConnecting wakes up all tasks that have gone to sleep because they
@@ -299,8 +393,10 @@ func (rm *runManager) ResumePending(
 		return fmt.Errorf("Attempting to resume non pending run %s", run.ID)
 	}
 
-	currentTaskRun := run.NextTaskRun()
-	if currentTaskRun == nil {
+	currentTaskRun := run.PendingBridgeTaskRun(input.TaskRunID)
+	if currentTaskRun == nil && input.TaskRunID != nil {
+		return fmt.Errorf("Attempting to resume task run %s of run %s which isn't pending", input.TaskRunID, run.ID)
+	} else if currentTaskRun == nil {
 		return rm.updateWithError(&run, "Attempting to resume pending run with no remaining tasks %s", run.ID)
 	}
 
@@ -316,6 +412,57 @@ func (rm *runManager) ResumePending(
 	return rm.updateAndTrigger(&run)
 }
 
+// ResumePendingManually resumes a run pending on a bridge with the result of
+// its task supplied by an operator, for when the bridge is down for good but
+// its answer is known. The result, and who supplied it, is recorded in the
+// audit log of resumptions in the transaction resuming the run.
+func (rm *runManager) ResumePendingManually(
+	runID *models.ID,
+	input models.BridgeRunResult,
+	resumedBy string,
+) error {
+	run, err := rm.orm.Unscoped().FindJobRun(runID)
+	if err != nil {
+		return err
+	}
+	if !run.GetStatus().PendingBridge() {
+		return fmt.Errorf("Attempting to resume non pending run %s", run.ID)
+	}
+	if input.Status.PendingBridge() {
+		return fmt.Errorf("Attempting to resume run %s manually without a result", run.ID)
+	}
+	currentTaskRun := run.PendingBridgeTaskRun(nil)
+	if currentTaskRun == nil {
+		return fmt.Errorf("Attempting to resume pending run with no remaining tasks %s", run.ID)
+	}
+
+	data, err := models.Merge(run.RunRequest.RequestParams, input.Data)
+	if err != nil {
+		return errors.Wrapf(err, "Error while merging onto RequestParams for run %s", run.ID)
+	}
+	run.RunRequest.RequestParams = data
+
+	resumption := models.NewRunResumption(&run, currentTaskRun, input, resumedBy)
+	currentTaskRun.ApplyBridgeRunResult(input)
+	run.ApplyBridgeRunResult(input)
+
+	defer rm.statsPusher.PushNow()
+	if err := rm.orm.SaveJobRunResumedManually(&run, &resumption); err != nil {
+		return errors.Wrap(err, "failed to resume the run")
+	}
+	logger.Infow("Run resumed manually", run.ForLogger(
+		"bridge", resumption.Bridge,
+		"status", input.Status,
+		"input_data", input.Data,
+		"resumedBy", resumedBy,
+	)...)
+
+	if run.GetStatus() == models.RunStatusInProgress {
+		rm.runQueue.Run(&run)
+	}
+	return nil
+}
+
 // ResumeAllInProgress queries the db for job runs that should be resumed
 // since a previous node shutdown.
 //
@@ -329,8 +476,189 @@ func (rm *runManager) ResumeAllInProgress() error {
 	return rm.orm.UnscopedJobRunsWithStatus(rm.runQueue.Run, models.RunStatusInProgress, models.RunStatusPendingSleep)
 }
 
-// Cancel suspends a running task.
+// ResumeStuck gives a run which has waited for longer than expected another
+// chance: a run in progress is executed again, a run pending confirmations
+// checks them against the latest head, and a run pending a bridge sends its
+// request to the bridge again. A run which has been updated since
+// stuckBefore, or is no longer in one of those statuses, has moved on and is
+// left alone.
+func (rm *runManager) ResumeStuck(runID *models.ID, stuckBefore time.Time) error {
+	run, err := rm.orm.Unscoped().FindJobRun(runID)
+	if err != nil {
+		return err
+	}
+
+	status := run.GetStatus()
+	if status != models.RunStatusInProgress && !status.PendingConfirmations() && !status.PendingBridge() {
+		logger.Debugw("Not resuming run which is no longer stuck", run.ForLogger()...)
+		return nil
+	}
+	claimed, err := rm.orm.ClaimStuckJobRun(run.ID, status, stuckBefore)
+	if err != nil {
+		return err
+	} else if !claimed {
+		logger.Debugw("Not resuming run which moved on since it was found stuck", run.ForLogger()...)
+		return nil
+	}
+
+	logger.Debugw("Resuming stuck run", run.ForLogger()...)
+
+	switch {
+	case status == models.RunStatusInProgress:
+		rm.runQueue.Run(&run)
+		return nil
+
+	case status.PendingConfirmations():
+		currentTaskRun := run.NextTaskRun()
+		if currentTaskRun == nil {
+			return rm.updateWithError(&run, "Attempting to resume confirming run with no remaining tasks %s", run.ID)
+		}
+		head, err := rm.orm.LastHead()
+		if err != nil {
+			return err
+		} else if head == nil {
+			return fmt.Errorf("no head to check the confirmations of run %s against", run.ID)
+		}
+		run.ObservedHeight = utils.NewBig(head.ToInt())
+		validateMinimumConfirmations(&run, currentTaskRun, run.ObservedHeight, rm.txManager)
+		return rm.updateAndTrigger(&run)
+
+	case status.PendingBridge():
+		for i := range run.TaskRuns {
+			if run.TaskRuns[i].Status.PendingBridge() {
+				run.TaskRuns[i].Status = models.RunStatusInProgress
+			}
+		}
+		run.SetStatus(models.RunStatusInProgress)
+		return rm.updateAndTrigger(&run)
+	}
+	return fmt.Errorf("Attempting to resume run %s, which is %s rather than stuck", run.ID, run.GetStatus())
+}
+
+// ResumeAllParked starts the runs parked by the concurrency limits or by a
+// full run queue, oldest first, for as long as the limits and the queue
+// allow. It only looks for the runs it can start when any is parked, as it
+// is called whenever a run yields its slot.
+func (rm *runManager) ResumeAllParked() error {
+	rm.admitMutex.Lock()
+	defer rm.admitMutex.Unlock()
+
+	if parked, err := rm.orm.HasParkedJobRuns(); err != nil || !parked {
+		return err
+	}
+
+	for {
+		runs, err := rm.orm.ParkedJobRuns(orm.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		resumed := 0
+		for i := range runs {
+			run := &runs[i]
+			if rm.runQueue.Full() {
+				return nil
+			}
+			if limited, err := rm.globalConcurrencyLimitReached(); err != nil || limited {
+				return err
+			}
+
+			job, err := rm.orm.Unscoped().FindJob(run.JobSpecID)
+			if err != nil {
+				return errors.Wrap(err, "failed to find job spec")
+			}
+			if limited, err := rm.jobConcurrencyLimitReached(&job); err != nil {
+				return err
+			} else if limited {
+				continue
+			}
+
+			logger.Debugw("Resuming run parked by the concurrency limits", run.ForLogger()...)
+			run.SetStatus(models.RunStatusInProgress)
+			if err := rm.updateAndTrigger(run); err != nil {
+				return err
+			}
+			resumed++
+		}
+
+		if resumed == 0 {
+			return nil
+		}
+	}
+}
+
+// ResumeAllSleeping wakes up the runs paused by a task that is sleeping, once
+// the time the task is due to wake up has passed, and returns the earliest
+// time any of the remaining runs is due, or the zero time if there are none.
+//
+// The task is left pending_sleep so that its adapter knows it is being woken
+// up, rather than started again.
+func (rm *runManager) ResumeAllSleeping() (time.Time, error) {
+	var next time.Time
+	now := rm.clock.Now()
+	err := rm.orm.UnscopedJobRunsWithStatus(func(run *models.JobRun) {
+		currentTaskRun := run.NextTaskRun()
+		if currentTaskRun == nil {
+			rm.updateWithError(run, "Attempting to resume sleeping run with no remaining tasks %s", run.ID)
+			return
+		}
+
+		if until, ok := adapters.SleepUntil(currentTaskRun.Result.Data); ok && until.After(now) {
+			if next.IsZero() || until.Before(next) {
+				next = until
+			}
+			return
+		}
+
+		logger.Debugw("Waking up sleeping run", run.ForLogger()...)
+		run.SetStatus(models.RunStatusInProgress)
+		if err := rm.updateAndTrigger(run); err != nil {
+			logger.Errorw("Error saving run", run.ForLogger("error", err)...)
+		}
+	}, models.RunStatusPendingSleep)
+	return next, err
+}
+
+func (rm *runManager) concurrencyLimitReached(job *models.JobSpec) (bool, error) {
+	limited, err := rm.globalConcurrencyLimitReached()
+	if err != nil || limited {
+		return limited, err
+	}
+	return rm.jobConcurrencyLimitReached(job)
+}
+
+func (rm *runManager) globalConcurrencyLimitReached() (bool, error) {
+	limit := rm.config.MaxConcurrentRuns()
+	if limit == 0 {
+		return false, nil
+	}
+	active, err := rm.orm.ActiveJobRunsCount(nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to count active runs")
+	}
+	return uint(active) >= limit, nil
+}
+
+func (rm *runManager) jobConcurrencyLimitReached(job *models.JobSpec) (bool, error) {
+	if job.MaxConcurrentRuns == 0 {
+		return false, nil
+	}
+	active, err := rm.orm.ActiveJobRunsCount(job.ID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to count active runs for job %s", job.ID)
+	}
+	return uint32(active) >= job.MaxConcurrentRuns, nil
+}
+
+// Cancel suspends a running task. If the run is being executed, its
+// execution is interrupted first, the task being performed told to stop
+// through the context of its input, and no further task, such as an ethtx
+// sending a transaction, is performed.
 func (rm *runManager) Cancel(runID *models.ID) (*models.JobRun, error) {
+	if rm.runQueue.Interrupt(runID) {
+		logger.Debugw("Interrupted the execution of run to cancel it", "run", runID.String())
+	}
+
 	run, err := rm.orm.FindJobRun(runID)
 	if err != nil {
 		return nil, err
@@ -343,7 +671,11 @@ func (rm *runManager) Cancel(runID *models.ID) (*models.JobRun, error) {
 
 	run.Cancel()
 	defer rm.statsPusher.PushNow()
-	return &run, rm.orm.SaveJobRun(&run)
+	if err := rm.orm.SaveJobRun(&run); err != nil {
+		return &run, err
+	}
+	logger.ErrorIf(rm.ResumeAllParked())
+	return &run, nil
 }
 
 func (rm *runManager) updateWithError(run *models.JobRun, msg string, args ...interface{}) error {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/jinzhu/gorm"
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/eth"
@@ -178,6 +179,45 @@ func TestRunManager_ResumeAllConfirming(t *testing.T) {
 		assert.Equal(t, string(models.RunStatusInProgress), string(run.GetStatus()))
 	})
 
+	t.Run("cancel a run which has waited too long for confirmations", func(t *testing.T) {
+		store.Config.Set("INCOMING_CONFIRMATIONS_TIMEOUT", "1h")
+		runQueue.On("Full").Maybe().Return(false)
+
+		run := makeJobRunWithInitiator(t, store, cltest.NewJob())
+		run.SetStatus(models.RunStatusPendingConfirmations)
+		run.TaskRuns[0].MinimumConfirmations = clnull.Uint32From(2)
+		run.CreatedAt = time.Now().Add(-2 * time.Hour)
+		require.NoError(t, store.CreateJobRun(&run))
+
+		err := runManager.ResumeAllConfirming(big.NewInt(0))
+		require.NoError(t, err)
+
+		run, err = store.FindJobRun(run.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusCancelled, run.GetStatus())
+		assert.Equal(t, models.RunStatusCancelled, run.TaskRuns[0].Status)
+		assert.Equal(t, "Run cancelled after waiting more than 1h0m0s for incoming confirmations", run.Result.ErrorMessage.String)
+	})
+
+	t.Run("cancel a run which has waited too long for a log no longer on chain", func(t *testing.T) {
+		store.Config.Set("INCOMING_CONFIRMATIONS_TIMEOUT", "1h")
+		runQueue.On("Full").Maybe().Return(false)
+
+		run := makeJobRunWithInitiator(t, store, cltest.NewJob())
+		run.SetStatus(models.RunStatusPendingConfirmations)
+		run.TaskRuns[0].MinimumConfirmations = clnull.Uint32From(2)
+		run.CreatedAt = time.Now().Add(-2 * time.Hour)
+		require.NoError(t, store.CreateJobRun(&run))
+
+		// Enough blocks were mined since the log, but the run still waits
+		err := runManager.ResumeAllConfirming(big.NewInt(100))
+		require.NoError(t, err)
+
+		run, err = store.FindJobRun(run.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.RunStatusCancelled, run.GetStatus())
+	})
+
 	runQueue.AssertExpectations(t)
 }
 
@@ -729,6 +769,42 @@ func TestRunManager_ResumeAllInProgress(t *testing.T) {
 	}
 }
 
+func TestRunManager_ResumeStuck(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	createRun := func(status models.RunStatus) models.JobRun {
+		run := cltest.NewJobRun(job)
+		run.SetStatus(status)
+		require.NoError(t, store.CreateJobRun(&run))
+		require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+			return db.Exec("UPDATE job_runs SET updated_at = ? WHERE id = ?", time.Now().Add(-time.Hour), run.ID).Error
+		}))
+		return run
+	}
+	stuck := createRun(models.RunStatusInProgress)
+	completed := createRun(models.RunStatusCompleted)
+
+	runQueue := new(mocks.RunQueue)
+	runQueue.On("Run", mock.MatchedBy(func(run *models.JobRun) bool {
+		return run.ID.String() == stuck.ID.String()
+	})).Return(nil).Once()
+
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, new(mocks.StatsPusher), store.TxManager, store.Clock)
+	stuckBefore := time.Now().Add(-time.Minute)
+	require.NoError(t, runManager.ResumeStuck(stuck.ID, stuckBefore))
+
+	// The run has moved on since it was found stuck, by being resumed
+	require.NoError(t, runManager.ResumeStuck(stuck.ID, stuckBefore))
+	require.NoError(t, runManager.ResumeStuck(completed.ID, stuckBefore))
+
+	runQueue.AssertExpectations(t)
+}
+
 // XXX: In progress tasks that are archived should still be run as they have been paid for
 func TestRunManager_ResumeAllInProgress_Archived(t *testing.T) {
 	t.Parallel()
@@ -840,6 +916,52 @@ func TestRunManager_ResumeAllInProgress_NotInProgressAndArchived(t *testing.T) {
 	}
 }
 
+func TestRunManager_ResumeAllSleeping(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	sleepingRun := func(until time.Time) models.JobRun {
+		run := cltest.NewJobRun(job)
+		run.SetStatus(models.RunStatusPendingSleep)
+		run.TaskRuns[0].Status = models.RunStatusPendingSleep
+		run.TaskRuns[0].Result.Data = cltest.JSONFromString(t, `{"sleepUntil":%q}`, until.Format(time.RFC3339Nano))
+		require.NoError(t, store.CreateJobRun(&run))
+		return run
+	}
+	due := sleepingRun(time.Now().Add(-time.Second))
+	later := time.Now().Add(time.Hour).Truncate(time.Second)
+	sleeping := sleepingRun(later)
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+
+	runQueue := new(mocks.RunQueue)
+	runQueue.On("Run", mock.MatchedBy(func(run *models.JobRun) bool {
+		return run.ID.String() == due.ID.String()
+	})).Once().Return(nil)
+
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, pusher, store.TxManager, store.Clock)
+	next, err := runManager.ResumeAllSleeping()
+	require.NoError(t, err)
+	assert.True(t, later.Equal(next))
+
+	runQueue.AssertExpectations(t)
+
+	run, err := store.FindJobRun(due.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusInProgress, run.GetStatus())
+	assert.Equal(t, models.RunStatusPendingSleep, run.TaskRuns[0].Status)
+
+	run, err = store.FindJobRun(sleeping.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingSleep, run.GetStatus())
+}
+
 func TestRunManager_ValidateRun_PaymentAboveThreshold(t *testing.T) {
 	jobSpecID := cltest.NewJob().ID
 	run := &models.JobRun{ID: models.NewID(), JobSpecID: jobSpecID, Payment: assets.NewLink(2)}
@@ -894,4 +1016,151 @@ func TestRunManager_NewRun(t *testing.T) {
 		assert.NotNil(t, run.TaskRuns[0].ID)
 		assert.Len(t, adapters, 1)
 	})
+
+	t.Run("takes the most confirmations of the node, the job and the task", func(t *testing.T) {
+		store.Config.Set("MIN_INCOMING_CONFIRMATIONS", 2)
+		store.Config.Set("MIN_OUTGOING_CONFIRMATIONS", 3)
+		job := cltest.NewJobWithRunLogInitiator()
+		job.IncomingConfirmations = clnull.Uint32From(5)
+		job.OutgoingConfirmations = clnull.Uint32From(7)
+		job.Tasks = []models.TaskSpec{
+			{Type: adapters.TaskTypeNoOp, Confirmations: clnull.Uint32From(9)},
+			{Type: adapters.TaskTypeEthTx, OutgoingConfirmations: clnull.Uint32From(12)},
+			{Type: adapters.TaskTypeEthTx},
+		}
+
+		run, _ := services.NewRun(&job, &job.Initiators[0], big.NewInt(0), &models.RunRequest{}, store.Config, store.ORM, now)
+		require.Len(t, run.TaskRuns, 3)
+		assert.Equal(t, clnull.Uint32From(9), run.TaskRuns[0].MinimumConfirmations)
+		assert.Equal(t, clnull.Uint32From(5), run.TaskRuns[1].MinimumConfirmations)
+		assert.False(t, run.TaskRuns[0].MinimumOutgoingConfirmations.Valid)
+		assert.Equal(t, clnull.Uint32From(12), run.TaskRuns[1].MinimumOutgoingConfirmations)
+		assert.Equal(t, clnull.Uint32From(7), run.TaskRuns[2].MinimumOutgoingConfirmations)
+	})
+}
+
+func TestRunManager_Create_ParksRunsOverJobConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+
+	runQueue := new(mocks.RunQueue)
+	runQueue.On("Run", mock.Anything).Return(nil).Once()
+
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, pusher, store.TxManager, store.Clock)
+
+	job := cltest.NewJobWithWebInitiator()
+	job.MaxConcurrentRuns = 1
+	require.NoError(t, store.CreateJob(&job))
+
+	first, err := runManager.Create(job.ID, &job.Initiators[0], nil, &models.RunRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusInProgress, first.GetStatus())
+
+	second, err := runManager.Create(job.ID, &job.Initiators[0], nil, &models.RunRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingConcurrency, second.GetStatus())
+
+	runQueue.AssertExpectations(t)
+
+	first.SetStatus(models.RunStatusCompleted)
+	require.NoError(t, store.SaveJobRun(first))
+
+	runQueue.On("Full").Return(false)
+	runQueue.On("Run", mock.Anything).Return(nil).Once()
+	require.NoError(t, runManager.ResumeAllParked())
+	runQueue.AssertExpectations(t)
+
+	resumed, err := store.FindJobRun(second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusInProgress, resumed.GetStatus())
+}
+
+func TestRunManager_Create_ParksRunsOverGlobalConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("MAX_CONCURRENT_RUNS", 1)
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+
+	runQueue := new(mocks.RunQueue)
+	runQueue.On("Run", mock.Anything).Return(nil).Once()
+
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, pusher, store.TxManager, store.Clock)
+
+	jobA := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&jobA))
+	jobB := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&jobB))
+
+	runA, err := runManager.Create(jobA.ID, &jobA.Initiators[0], nil, &models.RunRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusInProgress, runA.GetStatus())
+
+	runB, err := runManager.Create(jobB.ID, &jobB.Initiators[0], nil, &models.RunRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingConcurrency, runB.GetStatus())
+
+	runQueue.On("Full").Return(false)
+	require.NoError(t, runManager.ResumeAllParked())
+	runQueue.AssertExpectations(t)
+}
+
+func TestRunManager_ResumeAllParked_NothingParked(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	runQueue := new(mocks.RunQueue)
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, new(mocks.StatsPusher), store.TxManager, store.Clock)
+
+	require.NoError(t, runManager.ResumeAllParked())
+	runQueue.AssertNotCalled(t, "Full")
+}
+
+func TestRunManager_ResumeAllParked_WaitsForRoomInRunQueue(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	pusher := new(mocks.StatsPusher)
+	pusher.On("PushNow").Return(nil)
+
+	runQueue := new(mocks.RunQueue)
+	runQueue.On("Run", mock.Anything).Return(nil).Once()
+
+	runManager := services.NewRunManager(runQueue, store.Config, store.ORM, pusher, store.TxManager, store.Clock)
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	run, err := runManager.Create(job.ID, &job.Initiators[0], nil, &models.RunRequest{})
+	require.NoError(t, err)
+	runQueue.AssertExpectations(t)
+
+	require.NoError(t, store.ParkJobRun(run.ID))
+	parked, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingConcurrency, parked.GetStatus())
+
+	runQueue.On("Full").Return(true).Once()
+	require.NoError(t, runManager.ResumeAllParked())
+	runQueue.AssertExpectations(t)
+
+	runQueue.On("Full").Return(false)
+	runQueue.On("Run", mock.Anything).Return(nil).Once()
+	require.NoError(t, runManager.ResumeAllParked())
+	runQueue.AssertExpectations(t)
+
+	resumed, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusInProgress, resumed.GetStatus())
 }
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	promRunsPending = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "job_runs_pending",
+		Help: "The number of Job Runs which have not finished yet, by status",
+	},
+		[]string{"status"},
+	)
+
+	// unfinishedRunStatuses are the statuses of the runs counted by
+	// job_runs_pending.
+	unfinishedRunStatuses = []models.RunStatus{
+		models.RunStatusUnstarted,
+		models.RunStatusInProgress,
+		models.RunStatusPendingBridge,
+		models.RunStatusPendingConcurrency,
+		models.RunStatusPendingConfirmations,
+		models.RunStatusPendingConnection,
+		models.RunStatusPendingSleep,
+	}
+)
+
+// RunMetricsReporter counts the runs which have not finished yet by status on
+// every head, for the job_runs_pending metric.
+type RunMetricsReporter struct {
+	store *store.Store
+}
+
+// NewRunMetricsReporter returns a new RunMetricsReporter.
+func NewRunMetricsReporter(store *store.Store) *RunMetricsReporter {
+	return &RunMetricsReporter{store: store}
+}
+
+// Connect counts the runs when the node connects to the chain.
+func (r *RunMetricsReporter) Connect(*models.Head) error {
+	return r.Report()
+}
+
+// Disconnect does nothing.
+func (r *RunMetricsReporter) Disconnect() {}
+
+// OnNewHead counts the runs on every head.
+func (r *RunMetricsReporter) OnNewHead(*models.Head) {
+	logger.ErrorIf(r.Report(), "failed to report the number of pending runs")
+}
+
+// Report counts the runs which have not finished yet by status.
+func (r *RunMetricsReporter) Report() error {
+	counts, err := r.store.CountJobRunsByStatus(unfinishedRunStatuses...)
+	if err != nil {
+		return err
+	}
+	for _, status := range unfinishedRunStatuses {
+		promRunsPending.WithLabelValues(string(status)).Set(float64(counts[status]))
+	}
+	return nil
+}
@@ -1,11 +1,13 @@
 package services
 
 import (
+	"container/heap"
 	"fmt"
 	"sync"
 
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -20,6 +22,14 @@ var (
 		Name: "run_queue_queue_size",
 		Help: "The size of the run queue",
 	})
+	numberRunsWaiting = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "run_queue_runs_waiting",
+		Help: "The number of runs waiting in the run queue for a worker",
+	})
+	numberRunsOverflowed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "run_queue_runs_overflowed",
+		Help: "The total number of runs parked in the database because the run queue was full",
+	})
 )
 
 //go:generate mockery -name RunQueue -output ../internal/mocks/ -case=underscore
@@ -29,24 +39,46 @@ type RunQueue interface {
 	Start() error
 	Stop()
 	Run(*models.JobRun)
+	Interrupt(*models.ID) bool
+	Full() bool
 
 	WorkerCount() int
 }
 
+//go:generate mockery -name RunParker -output ../internal/mocks/ -case=underscore
+
+// RunParker parks the runs the RunQueue has no room for, so that they are
+// resumed with the runs parked by the concurrency limits. It is satisfied by
+// the ORM.
+type RunParker interface {
+	ParkJobRun(*models.ID) error
+}
+
 type runQueue struct {
 	workersMutex  sync.RWMutex
 	workers       map[string]int
 	workersWg     sync.WaitGroup
+	waiting       waitingRuns
+	queued        map[string]bool
+	sequence      uint64
 	stopRequested bool
 
 	runExecutor RunExecutor
+	config      orm.ConfigReader
+	parker      RunParker
 }
 
-// NewRunQueue initializes a RunQueue.
-func NewRunQueue(runExecutor RunExecutor) RunQueue {
+// NewRunQueue initializes a RunQueue, which executes up to RUN_QUEUE_WORKERS
+// runs at once, keeping up to RUN_QUEUE_CAPACITY more waiting for a worker
+// by priority, and parking the runs of the lowest priority beyond that with
+// parker.
+func NewRunQueue(runExecutor RunExecutor, config orm.ConfigReader, parker RunParker) RunQueue {
 	return &runQueue{
 		workers:     make(map[string]int),
+		queued:      make(map[string]bool),
 		runExecutor: runExecutor,
+		config:      config,
+		parker:      parker,
 	}
 }
 
@@ -55,25 +87,20 @@ func (rq *runQueue) Start() error {
 	return nil
 }
 
-// Stop closes all open worker channels.
+// Stop closes all open worker channels. The runs still waiting are dropped,
+// to be resumed as in progress when the node starts again.
 func (rq *runQueue) Stop() {
 	rq.workersMutex.Lock()
 	rq.stopRequested = true
+	rq.waiting = nil
+	rq.queued = make(map[string]bool)
+	numberRunsWaiting.Set(0)
 	rq.workersMutex.Unlock()
 	rq.workersWg.Wait()
 }
 
-func (rq *runQueue) incrementQueue(runID string) bool {
-	defer rq.workersMutex.Unlock()
-	rq.workersMutex.Lock()
-	numberRunsQueued.Inc()
-
-	wasEmpty := rq.workers[runID] == 0
-	rq.workers[runID]++
-	numberRunQueueWorkers.Set(float64(len(rq.workers)))
-	return wasEmpty
-}
-
+// decrementQueue counts off an execution of the run, returning true if it is
+// not to be executed again.
 func (rq *runQueue) decrementQueue(runID string) bool {
 	defer rq.workersMutex.Unlock()
 	rq.workersMutex.Lock()
@@ -88,34 +115,146 @@ func (rq *runQueue) decrementQueue(runID string) bool {
 	return isEmpty
 }
 
-// Run tells the job runner to start executing a job
+// next takes the waiting run of the highest priority for a worker which is
+// done, returning false if no run is waiting.
+func (rq *runQueue) next() (*models.ID, bool) {
+	defer rq.workersMutex.Unlock()
+	rq.workersMutex.Lock()
+
+	if rq.stopRequested || rq.waiting.Len() == 0 {
+		return nil, false
+	}
+	wr := heap.Pop(&rq.waiting).(waitingRun)
+	runID := wr.id.String()
+	delete(rq.queued, runID)
+	rq.workers[runID]++
+	numberRunsWaiting.Set(float64(rq.waiting.Len()))
+	numberRunQueueWorkers.Set(float64(len(rq.workers)))
+	return wr.id, true
+}
+
+// Run tells the job runner to start executing a job. The run is executed
+// straight away if a worker is free, else it waits for one. If the queue is
+// full, it takes the place of a waiting run of a lower priority, which is
+// parked, or is parked itself.
 func (rq *runQueue) Run(run *models.JobRun) {
 	rq.workersMutex.Lock()
 	if rq.stopRequested {
 		rq.workersMutex.Unlock()
 		return
 	}
-	rq.workersMutex.Unlock()
 
 	runID := run.ID.String()
-	if !rq.incrementQueue(runID) {
+	numberRunsQueued.Inc()
+	switch {
+	case rq.workers[runID] > 0:
+		// The worker executing the run executes it again once done.
+		rq.workers[runID]++
+		rq.workersMutex.Unlock()
+		return
+	case rq.queued[runID]:
+		rq.workersMutex.Unlock()
+		return
+	case rq.hasFreeWorker():
+		rq.workers[runID]++
+		numberRunQueueWorkers.Set(float64(len(rq.workers)))
+		rq.workersWg.Add(1)
+		rq.workersMutex.Unlock()
+		go rq.work(run.ID)
+		return
+	case !rq.full():
+		rq.push(run)
+		rq.workersMutex.Unlock()
 		return
 	}
 
-	rq.workersWg.Add(1)
-	go func() {
-		defer rq.workersWg.Done()
+	// The queue is full: the waiting run of the lowest priority, queued
+	// last, is parked to make room for a run of a higher priority, which is
+	// parked itself otherwise.
+	parked := run.ID
+	if evicted, ok := rq.evictBelow(run.Priority()); ok {
+		rq.push(run)
+		parked = evicted
+	}
+	rq.workersMutex.Unlock()
 
-		for {
-			if err := rq.runExecutor.Execute(run.ID); err != nil {
-				logger.Errorw(fmt.Sprint("Error executing run ", runID), "error", err)
-			}
+	numberRunsOverflowed.Inc()
+	logger.Warnw("Run queue is full, parking run until there is room for it", "run", parked.String())
+	if err := rq.parker.ParkJobRun(parked); err != nil {
+		logger.Errorw(fmt.Sprint("Error parking run ", parked.String()), "error", err)
+	}
+}
 
-			if rq.decrementQueue(runID) {
-				return
-			}
+// push queues the run to wait for a worker.
+func (rq *runQueue) push(run *models.JobRun) {
+	rq.sequence++
+	heap.Push(&rq.waiting, waitingRun{id: run.ID, priority: run.Priority(), sequence: rq.sequence})
+	rq.queued[run.ID.String()] = true
+	numberRunsWaiting.Set(float64(rq.waiting.Len()))
+}
+
+// evictBelow takes out of the queue the waiting run of the lowest priority,
+// the last queued of those, if its priority is below the one given.
+func (rq *runQueue) evictBelow(priority int) (*models.ID, bool) {
+	last := -1
+	for i, wr := range rq.waiting {
+		if wr.priority >= priority {
+			continue
+		}
+		if last < 0 || rq.waiting.Less(last, i) {
+			last = i
 		}
-	}()
+	}
+	if last < 0 {
+		return nil, false
+	}
+	wr := heap.Remove(&rq.waiting, last).(waitingRun)
+	delete(rq.queued, wr.id.String())
+	return wr.id, true
+}
+
+// work executes the run, and then the waiting runs, until none are left.
+func (rq *runQueue) work(runID *models.ID) {
+	defer rq.workersWg.Done()
+
+	for {
+		if err := rq.runExecutor.Execute(runID); err != nil {
+			logger.Errorw(fmt.Sprint("Error executing run ", runID), "error", err)
+		}
+
+		if !rq.decrementQueue(runID.String()) {
+			continue
+		}
+		next, ok := rq.next()
+		if !ok {
+			return
+		}
+		runID = next
+	}
+}
+
+// Interrupt interrupts the execution of the run, returning false if it is
+// not being executed.
+func (rq *runQueue) Interrupt(runID *models.ID) bool {
+	return rq.runExecutor.Interrupt(runID)
+}
+
+// Full returns true if the runs given to the queue would be parked, as every
+// worker is busy and the most runs are waiting for one.
+func (rq *runQueue) Full() bool {
+	rq.workersMutex.RLock()
+	defer rq.workersMutex.RUnlock()
+
+	return !rq.hasFreeWorker() && rq.full()
+}
+
+func (rq *runQueue) hasFreeWorker() bool {
+	limit := rq.config.RunQueueWorkers()
+	return limit == 0 || uint(len(rq.workers)) < limit
+}
+
+func (rq *runQueue) full() bool {
+	return uint(rq.waiting.Len()) >= rq.config.RunQueueCapacity()
 }
 
 // WorkerCount returns the number of workers currently processing a job run
@@ -125,3 +264,35 @@ func (rq *runQueue) WorkerCount() int {
 
 	return len(rq.workers)
 }
+
+// waitingRun is a run waiting in the RunQueue for a worker.
+type waitingRun struct {
+	id       *models.ID
+	priority int
+	sequence uint64
+}
+
+// waitingRuns is a heap of the runs waiting for a worker, of the highest
+// priority first, and of those the first queued.
+type waitingRuns []waitingRun
+
+func (w waitingRuns) Len() int { return len(w) }
+
+func (w waitingRuns) Less(i, j int) bool {
+	if w[i].priority != w[j].priority {
+		return w[i].priority > w[j].priority
+	}
+	return w[i].sequence < w[j].sequence
+}
+
+func (w waitingRuns) Swap(i, j int) { w[i], w[j] = w[j], w[i] }
+
+func (w *waitingRuns) Push(x interface{}) { *w = append(*w, x.(waitingRun)) }
+
+func (w *waitingRuns) Pop() interface{} {
+	old := *w
+	n := len(old)
+	wr := old[n-1]
+	*w = old[:n-1]
+	return wr
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/models"
 
 	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -17,7 +18,7 @@ func TestRunQueue(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
 	runExecutor := new(mocks.RunExecutor)
-	runQueue := services.NewRunQueue(runExecutor)
+	runQueue := services.NewRunQueue(runExecutor, cltest.NewTestConfig(t), new(mocks.RunParker))
 
 	executeJobChannel := make(chan struct{})
 
@@ -52,7 +53,7 @@ func TestRunQueue_OneWorkerPerRun(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
 	runExecutor := new(mocks.RunExecutor)
-	runQueue := services.NewRunQueue(runExecutor)
+	runQueue := services.NewRunQueue(runExecutor, cltest.NewTestConfig(t), new(mocks.RunParker))
 
 	executeJobChannel := make(chan struct{})
 
@@ -89,7 +90,7 @@ func TestRunQueue_OneWorkerForSameRunTriggeredMultipleTimes(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
 	runExecutor := new(mocks.RunExecutor)
-	runQueue := services.NewRunQueue(runExecutor)
+	runQueue := services.NewRunQueue(runExecutor, cltest.NewTestConfig(t), new(mocks.RunParker))
 
 	executeJobChannel := make(chan struct{})
 
@@ -125,3 +126,110 @@ func TestRunQueue_OneWorkerForSameRunTriggeredMultipleTimes(t *testing.T) {
 		return runQueue.WorkerCount()
 	}).Should(gomega.Equal(0))
 }
+
+func TestRunQueue_PrioritizesWaitingRunsAndParksOverflow(t *testing.T) {
+	t.Parallel()
+
+	config := cltest.NewTestConfig(t)
+	config.Set("RUN_QUEUE_WORKERS", 1)
+	config.Set("RUN_QUEUE_CAPACITY", 2)
+
+	newRun := func(initiatorType string) *models.JobRun {
+		return &models.JobRun{ID: models.NewID(), Initiator: models.Initiator{Type: initiatorType}}
+	}
+	first := newRun(models.InitiatorWeb)
+	cron := newRun(models.InitiatorCron)
+	fluxMonitor := newRun(models.InitiatorFluxMonitor)
+	overflow := newRun(models.InitiatorWeb)
+
+	release := make(chan struct{})
+	executed := make(chan *models.ID, 3)
+	runExecutor := new(mocks.RunExecutor)
+	runExecutor.On("Execute", first.ID).
+		Return(nil).
+		Run(func(mock.Arguments) {
+			<-release
+			executed <- first.ID
+		})
+	runExecutor.On("Execute", mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			executed <- args.Get(0).(*models.ID)
+		})
+	parker := new(mocks.RunParker)
+	parker.On("ParkJobRun", overflow.ID).Return(nil).Once()
+
+	runQueue := services.NewRunQueue(runExecutor, config, parker)
+	runQueue.Start()
+	defer runQueue.Stop()
+
+	runQueue.Run(first)
+	runQueue.Run(cron)
+	assert.False(t, runQueue.Full())
+	runQueue.Run(fluxMonitor)
+	assert.True(t, runQueue.Full())
+	runQueue.Run(overflow)
+	parker.AssertExpectations(t)
+
+	close(release)
+	for _, id := range []*models.ID{first.ID, fluxMonitor.ID, cron.ID} {
+		cltest.CallbackOrTimeout(t, "Execute", func() {
+			assert.Equal(t, id, <-executed)
+		})
+	}
+	assert.False(t, runQueue.Full())
+}
+
+func TestRunQueue_EvictsWaitingRunsOfALowerPriority(t *testing.T) {
+	t.Parallel()
+
+	config := cltest.NewTestConfig(t)
+	config.Set("RUN_QUEUE_WORKERS", 1)
+	config.Set("RUN_QUEUE_CAPACITY", 2)
+
+	newRun := func(initiatorType string) *models.JobRun {
+		return &models.JobRun{ID: models.NewID(), Initiator: models.Initiator{Type: initiatorType}}
+	}
+	first := newRun(models.InitiatorWeb)
+	runLog := newRun(models.InitiatorRunLog)
+	cron := newRun(models.InitiatorCron)
+	fluxMonitor := newRun(models.InitiatorFluxMonitor)
+	web := newRun(models.InitiatorWeb)
+
+	release := make(chan struct{})
+	executed := make(chan *models.ID, 3)
+	runExecutor := new(mocks.RunExecutor)
+	runExecutor.On("Execute", first.ID).
+		Return(nil).
+		Run(func(mock.Arguments) {
+			<-release
+			executed <- first.ID
+		})
+	runExecutor.On("Execute", mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			executed <- args.Get(0).(*models.ID)
+		})
+	parker := new(mocks.RunParker)
+	parker.On("ParkJobRun", cron.ID).Return(nil).Once()
+	parker.On("ParkJobRun", web.ID).Return(nil).Once()
+
+	runQueue := services.NewRunQueue(runExecutor, config, parker)
+	runQueue.Start()
+	defer runQueue.Stop()
+
+	runQueue.Run(first)
+	runQueue.Run(runLog)
+	runQueue.Run(cron)
+	assert.True(t, runQueue.Full())
+	runQueue.Run(fluxMonitor)
+	runQueue.Run(web)
+	parker.AssertExpectations(t)
+
+	close(release)
+	for _, id := range []*models.ID{first.ID, fluxMonitor.ID, runLog.ID} {
+		cltest.CallbackOrTimeout(t, "Execute", func() {
+			assert.Equal(t, id, <-executed)
+		})
+	}
+}
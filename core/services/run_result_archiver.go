@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/synchronization"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var numberRunResultsArchived = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "run_results_archived",
+	Help: "The number of run results whose data was moved to archive files",
+})
+
+const (
+	// runResultArchiveInterval is how often the RunResultArchiver looks for
+	// run results to archive.
+	runResultArchiveInterval = time.Hour
+	// runResultArchiveBatchSize is the most run results archived in one file.
+	runResultArchiveBatchSize = 1000
+)
+
+// RunResultArchiver moves the data of the results of runs which finished
+// more than RUN_RESULT_ARCHIVE_AFTER ago to gzipped JSON files in an S3 or GCS
+// bucket, or a local directory, leaving the results with stubs pointing at
+// their file. This keeps run_results small without deleting the history of
+// runs, which RehydrateRunResults brings back when they are looked at.
+type RunResultArchiver struct {
+	store       *store.Store
+	destination synchronization.ExportDestination
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	stopOnce    sync.Once
+}
+
+// NewRunResultArchiver returns a new RunResultArchiver.
+func NewRunResultArchiver(store *store.Store) *RunResultArchiver {
+	return &RunResultArchiver{store: store}
+}
+
+// Start archives run results every hour until stopped, or does nothing if
+// RUN_RESULT_ARCHIVE_AFTER is zero.
+func (a *RunResultArchiver) Start() error {
+	if a.store.Config.RunResultArchiveAfter().IsInstant() {
+		return nil
+	}
+	destination, err := newRunResultArchive(a.store.Config)
+	if err != nil {
+		return err
+	}
+	if destination == nil {
+		return errors.New("RUN_RESULT_ARCHIVE_AFTER is set, but RUN_RESULT_ARCHIVE_URL is not")
+	}
+	a.destination = destination
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-a.store.Clock.After(runResultArchiveInterval):
+				logger.ErrorIf(a.Archive(ctx), "failed to archive run results")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops archiving, waiting for any archival in progress.
+// Stopping it again does nothing.
+func (a *RunResultArchiver) Stop() {
+	a.stopOnce.Do(func() {
+		if a.cancel == nil {
+			return
+		}
+		a.cancel()
+		a.wg.Wait()
+	})
+}
+
+// Archive archives the results of the runs which finished more than
+// RUN_RESULT_ARCHIVE_AFTER ago, in files of up to 1000 results. Each file is
+// named after the IDs of its first and last results, so a file archived again
+// after a crash replaces the first one.
+func (a *RunResultArchiver) Archive(ctx context.Context) error {
+	before := a.store.Clock.Now().Add(-a.store.Config.RunResultArchiveAfter().Duration())
+	for {
+		results, err := a.store.RunResultsToArchive(before, runResultArchiveBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			return nil
+		}
+
+		body, err := encodeRunResults(results)
+		if err != nil {
+			return err
+		}
+		first, last := results[0].ID, results[len(results)-1].ID
+		name := fmt.Sprintf("run_results-%010d-%010d.json.gz", first, last)
+		if err := a.destination.Put(ctx, name, body); err != nil {
+			return errors.Wrapf(err, "archiving run results %d to %d", first, last)
+		}
+		ids := make([]uint32, len(results))
+		for i, rr := range results {
+			ids[i] = rr.ID
+		}
+		if err := a.store.ArchiveRunResults(ids, name); err != nil {
+			return err
+		}
+		numberRunResultsArchived.Add(float64(len(results)))
+		logger.Debugw("Archived run results", "first_id", first, "last_id", last, "file", name)
+		if len(results) < runResultArchiveBatchSize {
+			return nil
+		}
+	}
+}
+
+// RehydrateRunResults puts back the data of the archived results of the runs,
+// fetching each archive file they point at once.
+func RehydrateRunResults(ctx context.Context, config orm.ConfigReader, runs ...*models.JobRun) error {
+	archived := map[string][]*models.RunResult{}
+	for _, run := range runs {
+		results := []*models.RunResult{&run.Result}
+		for i := range run.TaskRuns {
+			results = append(results, &run.TaskRuns[i].Result)
+		}
+		for _, rr := range results {
+			if rr.ArchivedIn.Valid {
+				archived[rr.ArchivedIn.String] = append(archived[rr.ArchivedIn.String], rr)
+			}
+		}
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+
+	destination, err := newRunResultArchive(config)
+	if err != nil {
+		return err
+	}
+	if destination == nil {
+		return errors.New("run results are archived, but RUN_RESULT_ARCHIVE_URL is not set")
+	}
+	for file, results := range archived {
+		body, err := destination.Get(ctx, file)
+		if err != nil {
+			return errors.Wrapf(err, "fetching archived run results from %s", file)
+		}
+		data, err := decodeRunResults(body)
+		if err != nil {
+			return errors.Wrapf(err, "reading archived run results from %s", file)
+		}
+		for _, rr := range results {
+			d, ok := data[strconv.FormatUint(uint64(rr.ID), 10)]
+			if !ok {
+				return fmt.Errorf("run result %d is not in %s", rr.ID, file)
+			}
+			rr.Data = d
+		}
+	}
+	return nil
+}
+
+// newRunResultArchive returns the destination of RUN_RESULT_ARCHIVE_URL, or
+// nil if it is not set.
+func newRunResultArchive(config orm.ConfigReader) (synchronization.ExportDestination, error) {
+	archiveURL := config.RunResultArchiveURL()
+	if archiveURL == nil {
+		return nil, nil
+	}
+	destination, err := synchronization.NewExportDestination(archiveURL, synchronization.ExportDestinationOptions{
+		AccessKey: config.RunResultArchiveAccessKey(),
+		Secret:    config.RunResultArchiveSecret(),
+		Region:    config.RunResultArchiveRegion(),
+		Endpoint:  config.RunResultArchiveEndpoint(),
+	})
+	return destination, errors.Wrap(err, "invalid RUN_RESULT_ARCHIVE_URL")
+}
+
+// encodeRunResults returns the gzipped JSON object of the data of the
+// results, keyed by their IDs.
+func encodeRunResults(results []models.RunResult) ([]byte, error) {
+	data := make(map[string]models.JSON, len(results))
+	for _, rr := range results {
+		data[strconv.FormatUint(uint64(rr.ID), 10)] = rr.Data
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(data); err != nil {
+		return nil, errors.Wrap(err, "encoding run results")
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeRunResults(body []byte) (map[string]models.JSON, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	b, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]models.JSON
+	return data, json.Unmarshal(b, &data)
+}
@@ -0,0 +1,61 @@
+package services_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunResultArchiver_ArchiveAndRehydrate(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	dir, err := ioutil.TempDir("", "run_results")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store.Config.Set("RUN_RESULT_ARCHIVE_AFTER", "720h")
+	store.Config.Set("RUN_RESULT_ARCHIVE_URL", "file://"+dir)
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	createRun := func(status models.RunStatus, finishedAgo time.Duration) models.JobRun {
+		run := cltest.NewJobRun(job)
+		run.SetStatus(status)
+		run.Result.Data = cltest.JSONFromString(t, `{"result": "old"}`)
+		require.NoError(t, store.CreateJobRun(&run))
+		require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+			return db.Exec("UPDATE job_runs SET finished_at = ? WHERE id = ?", time.Now().Add(-finishedAgo), run.ID).Error
+		}))
+		return run
+	}
+	old := createRun(models.RunStatusCompleted, 60*24*time.Hour)
+	recent := createRun(models.RunStatusCompleted, time.Hour)
+
+	archiver := services.NewRunResultArchiver(store)
+	require.NoError(t, archiver.Start())
+	defer archiver.Stop()
+	require.NoError(t, archiver.Archive(context.Background()))
+
+	archived, err := store.FindJobRun(old.ID)
+	require.NoError(t, err)
+	require.True(t, archived.Result.ArchivedIn.Valid)
+	assert.True(t, archived.Result.Data.Get(models.ArchivedKey).Exists())
+	notArchived, err := store.FindJobRun(recent.ID)
+	require.NoError(t, err)
+	assert.False(t, notArchived.Result.ArchivedIn.Valid)
+	assert.Equal(t, "old", notArchived.Result.Data.Get("result").String())
+
+	require.NoError(t, services.RehydrateRunResults(context.Background(), store.Config, &archived, &notArchived))
+	assert.Equal(t, "old", archived.Result.Data.Get("result").String())
+}
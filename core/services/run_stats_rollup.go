@@ -0,0 +1,73 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+)
+
+// RunStatsRollup rolls up the runs which finished into the hourly and daily
+// stats of their jobs, which the stats of runs are served from, so that they
+// need not aggregate the runs themselves.
+type RunStatsRollup struct {
+	store    *store.Store
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewRunStatsRollup returns a RunStatsRollup rolling up the runs of store.
+func NewRunStatsRollup(store *store.Store) *RunStatsRollup {
+	return &RunStatsRollup{
+		store: store,
+		done:  make(chan struct{}),
+	}
+}
+
+// Start rolls up the runs every RUN_STATS_ROLLUP_INTERVAL until stopped, or
+// does nothing if the interval is zero.
+func (r *RunStatsRollup) Start() error {
+	if r.store.Config.RunStatsRollupInterval().IsInstant() {
+		return nil
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-r.store.Clock.After(r.store.Config.RunStatsRollupInterval().Duration()):
+				logger.ErrorIf(r.RollUp(), "failed to roll up run stats")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops rolling up the runs, waiting for any rollup in progress.
+// Stopping it again does nothing.
+func (r *RunStatsRollup) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+		r.wg.Wait()
+	})
+}
+
+// RollUp rolls up the runs which finished since the hour before the latest
+// one rolled up, or all of them the first time. The hour before is rolled up
+// again for the runs which finished in it, but were saved after it ended.
+func (r *RunStatsRollup) RollUp() error {
+	latest, ok, err := r.store.LatestRunStatsBucket()
+	if err != nil {
+		return err
+	}
+	since := time.Unix(0, 0)
+	if ok {
+		since = latest.Add(-time.Hour)
+	}
+	return r.store.RollUpRunStats(since)
+}
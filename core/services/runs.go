@@ -53,7 +53,10 @@ func validateOnMainChain(run *models.JobRun, taskRun *models.TaskRun, txManager
 			run.ID.String(),
 		)
 	}
-	return nil
+	if receipt.BlockHash == nil {
+		return nil
+	}
+	return confirmedByQuorum(txManager, *txhash, *receipt.BlockHash)
 }
 
 func updateTaskRunConfirmations(currentHeight *utils.Big, jr *models.JobRun, taskRun *models.TaskRun) {
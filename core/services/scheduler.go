@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/pkg/errors"
@@ -60,11 +62,121 @@ func (s *Scheduler) Start() error {
 	s.started = true
 
 	return s.store.Jobs(func(j *models.JobSpec) bool {
+		s.catchUp(j)
 		s.addJob(j)
 		return true
 	}, models.InitiatorCron, models.InitiatorRunAt)
 }
 
+// catchUp handles the cron schedules of a job that were missed while the node
+// was not running, according to the configured CronCatchUp mode.
+func (s *Scheduler) catchUp(job *models.JobSpec) {
+	mode := s.store.Config.CronCatchUp()
+	if mode == orm.CronCatchUpNone {
+		return
+	}
+
+	now := s.store.Clock.Now()
+	for _, initr := range job.InitiatorsFor(models.InitiatorCron) {
+		since := initr.CreatedAt
+		lastRunAt, err := s.store.LastJobRunCreatedAtFor(initr.ID)
+		if err != nil {
+			logger.Errorw("Unable to find last run for cron initiator", "job_id", job.ID.String(), "error", err)
+			continue
+		} else if lastRunAt != nil {
+			since = *lastRunAt
+		}
+
+		missed, err := MissedCronSchedules(initr.CronSchedule(), since, now, s.store.Config.CronCatchUpMaxRuns())
+		if err != nil {
+			logger.Errorw("Unable to determine missed cron schedules", "job_id", job.ID.String(), "error", err)
+			continue
+		}
+
+		deferredTo := map[time.Time]bool{}
+		for _, scheduledAt := range missed {
+			if !job.Started(scheduledAt) || job.Ended(scheduledAt) {
+				continue
+			}
+
+			// Runs missed during a holiday or blackout are skipped or, when
+			// deferred, made once at its end, later on if it has not ended yet.
+			run, err := initr.CronRunFor(scheduledAt)
+			if err != nil {
+				logger.Errorw("Unable to apply cron calendar", "job_id", job.ID.String(), "error", err)
+				break
+			}
+			if run.RunAt == nil {
+				logger.Infow("Not catching up cron run scheduled during a holiday or blackout", "job_id", job.ID.String(), "scheduled_at", scheduledAt)
+				continue
+			} else if !run.RunAt.Equal(scheduledAt) {
+				if deferredTo[*run.RunAt] {
+					continue
+				}
+				deferredTo[*run.RunAt] = true
+				if run.RunAt.After(now) {
+					s.Recurring.deferRun(*job, initr, *run.RunAt)
+					continue
+				}
+			}
+
+			initiator := initr
+			switch mode {
+			case orm.CronCatchUpBackfill:
+				logger.Infow("Backfilling missed cron run", "job_id", job.ID.String(), "scheduled_at", scheduledAt)
+				_, err = s.runManager.Create(job.ID, &initiator, nil, &models.RunRequest{})
+			case orm.CronCatchUpSkip:
+				logger.Infow("Recording skipped cron run", "job_id", job.ID.String(), "scheduled_at", scheduledAt)
+				_, err = s.runManager.CreateErrored(job.ID, initiator, fmt.Errorf("skipped run scheduled at %s while the node was down", scheduledAt.Format(time.RFC3339)))
+			}
+			if err != nil && !ExpectedRecurringScheduleJobError(err) {
+				logger.Errorw(err.Error())
+			}
+		}
+	}
+}
+
+// MissedCronSchedules returns the times at which the given schedule should
+// have fired after since and before now, oldest first. At most limit of the
+// most recent times are returned.
+func MissedCronSchedules(schedule models.Cron, since, now time.Time, limit uint) ([]time.Time, error) {
+	sched, err := models.CronParser.Parse(string(schedule))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing cron schedule %s", schedule)
+	}
+
+	missed := []time.Time{}
+	if limit == 0 {
+		return missed, nil
+	}
+	for t := sched.Next(since); !t.IsZero() && t.Before(now); t = sched.Next(t) {
+		missed = append(missed, t)
+		if uint(len(missed)) > limit {
+			missed = missed[1:]
+		}
+	}
+	return missed, nil
+}
+
+// PreviewCronRuns returns the next n runs the given cron initiator schedules
+// after from, and when each is made given its calendar.
+func PreviewCronRuns(initr models.Initiator, from time.Time, n int) ([]models.CronRun, error) {
+	sched, err := models.CronParser.Parse(string(initr.CronSchedule()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing cron schedule %s", initr.CronSchedule())
+	}
+
+	runs := []models.CronRun{}
+	for t := sched.Next(from); !t.IsZero() && len(runs) < n; t = sched.Next(t) {
+		run, err := initr.CronRunFor(t)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
 // Stop is the governing function for both Recurring and OneTime
 // Stop function. Sets the started field to false.
 func (s *Scheduler) Stop() {
@@ -93,6 +205,17 @@ func (s *Scheduler) AddJob(job models.JobSpec) {
 	s.addJob(&job)
 }
 
+// RemoveJob stops scheduling runs for the job with the given ID, and only
+// does so if the Scheduler has already started.
+func (s *Scheduler) RemoveJob(ID *models.ID) {
+	s.startedMutex.RLock()
+	defer s.startedMutex.RUnlock()
+	if !s.started {
+		return
+	}
+	s.Recurring.RemoveJob(ID)
+}
+
 // Recurring is used for runs that need to execute on a schedule,
 // and is configured with cron.
 // Instances of Recurring must be initialized using NewRecurring().
@@ -100,12 +223,19 @@ type Recurring struct {
 	Cron       Cron
 	Clock      utils.Nower
 	runManager RunManager
+	entries    map[string][]cron.EntryID
+	// deferred holds the timers of the runs deferred to the end of a holiday
+	// or blackout, by job and initiator ID.
+	deferred  map[string]map[uint32]*time.Timer
+	entriesMu sync.Mutex
 }
 
 // NewRecurring create a new instance of Recurring, ready to use.
 func NewRecurring(runManager RunManager) *Recurring {
 	return &Recurring{
 		runManager: runManager,
+		entries:    make(map[string][]cron.EntryID),
+		deferred:   make(map[string]map[uint32]*time.Timer),
 	}
 }
 
@@ -117,32 +247,111 @@ func (r *Recurring) Start() error {
 	return nil
 }
 
-// Stop stops the cron scheduler and waits for running jobs to finish.
+// Stop stops the cron scheduler and waits for running jobs to finish. The
+// runs deferred to the end of a holiday or blackout are dropped.
 func (r *Recurring) Stop() {
 	ctx := r.Cron.Stop()
 	// Wait for all jobs to finish
 	<-ctx.Done()
+
+	r.entriesMu.Lock()
+	defer r.entriesMu.Unlock()
+	for jobID := range r.deferred {
+		r.stopDeferred(jobID)
+	}
 }
 
 // AddJob looks for "cron" initiators, adds them to cron's schedule
-// for execution when specified.
+// for execution when specified. Runs scheduled during a holiday or blackout
+// of the initiator's calendar are skipped, or deferred to its end.
 func (r *Recurring) AddJob(job models.JobSpec) {
 	for _, initr := range job.InitiatorsFor(models.InitiatorCron) {
-		_, err := r.Cron.AddFunc(string(initr.Schedule), func() {
+		initr := initr
+		id, err := r.Cron.AddFunc(string(initr.CronSchedule()), func() {
 			now := time.Now()
 			if !job.Started(now) || job.Ended(now) {
 				return
 			}
 
-			_, err := r.runManager.Create(job.ID, &initr, nil, &models.RunRequest{})
-			if err != nil && !ExpectedRecurringScheduleJobError(err) {
-				logger.Errorw(err.Error())
+			run, err := initr.CronRunFor(now)
+			if err != nil {
+				logger.Errorw("Unable to apply cron calendar", "job_id", job.ID.String(), "error", err)
+				return
+			}
+			switch {
+			case run.RunAt == nil:
+				logger.Infow("Skipping cron run scheduled during a holiday or blackout", "job_id", job.ID.String(), "scheduled_at", now)
+			case run.RunAt.After(now):
+				r.deferRun(job, initr, *run.RunAt)
+			default:
+				r.createRun(job, initr)
 			}
 		})
 		if err != nil {
 			logger.Error(err)
+			continue
 		}
+
+		r.entriesMu.Lock()
+		r.entries[job.ID.String()] = append(r.entries[job.ID.String()], id)
+		r.entriesMu.Unlock()
+	}
+}
+
+// RemoveJob removes the cron schedules of the job with the given ID, along
+// with its deferred runs.
+func (r *Recurring) RemoveJob(ID *models.ID) {
+	r.entriesMu.Lock()
+	defer r.entriesMu.Unlock()
+	for _, id := range r.entries[ID.String()] {
+		r.Cron.Remove(id)
 	}
+	delete(r.entries, ID.String())
+	r.stopDeferred(ID.String())
+}
+
+func (r *Recurring) createRun(job models.JobSpec, initr models.Initiator) {
+	_, err := r.runManager.Create(job.ID, &initr, nil, &models.RunRequest{})
+	if err != nil && !ExpectedRecurringScheduleJobError(err) {
+		logger.Errorw(err.Error())
+	}
+}
+
+// deferRun makes a run of the initiator at runAt, the end of a holiday or
+// blackout, unless one is already deferred, so that the runs scheduled during
+// it make a single run.
+func (r *Recurring) deferRun(job models.JobSpec, initr models.Initiator, runAt time.Time) {
+	jobID := job.ID.String()
+	r.entriesMu.Lock()
+	defer r.entriesMu.Unlock()
+	if _, ok := r.deferred[jobID][initr.ID]; ok {
+		return
+	}
+	if r.deferred[jobID] == nil {
+		r.deferred[jobID] = make(map[uint32]*time.Timer)
+	}
+
+	logger.Infow("Deferring cron run scheduled during a holiday or blackout", "job_id", jobID, "run_at", runAt)
+	r.deferred[jobID][initr.ID] = time.AfterFunc(utils.DurationFromNow(runAt), func() {
+		r.entriesMu.Lock()
+		delete(r.deferred[jobID], initr.ID)
+		r.entriesMu.Unlock()
+
+		now := time.Now()
+		if !job.Started(now) || job.Ended(now) {
+			return
+		}
+		r.createRun(job, initr)
+	})
+}
+
+// stopDeferred drops the runs deferred for the job. The caller must hold
+// entriesMu.
+func (r *Recurring) stopDeferred(jobID string) {
+	for _, timer := range r.deferred[jobID] {
+		timer.Stop()
+	}
+	delete(r.deferred, jobID)
 }
 
 // OneTime represents runs that are to be executed only once.
@@ -159,11 +368,16 @@ func (ot *OneTime) Start() error {
 	return nil
 }
 
-// AddJob runs the job at the time specified for the "runat" initiator.
+// AddJob runs the job at the time specified for the "runat" initiator, or
+// its delay after the job was created, and again every so often until the
+// job's EndAt if it recurs.
 func (ot *OneTime) AddJob(job models.JobSpec) {
 	for _, initiator := range job.InitiatorsFor(models.InitiatorRunAt) {
-		if !initiator.Time.Valid {
-			logger.Errorf("RunJobAt: JobSpec %s must have initiator with valid run at time: %v", job.ID, initiator)
+		if initiator.Ran {
+			continue
+		}
+		if !initiator.Time.Valid && initiator.Delay.IsInstant() && !initiator.NextRunAt.Valid {
+			logger.Errorf("RunJobAt: JobSpec %s must have initiator with valid run at time or delay: %v", job.ID, initiator)
 			continue
 		}
 
@@ -177,25 +391,49 @@ func (ot *OneTime) Stop() {
 }
 
 // RunJobAt wait until the Stop() function has been called on the run
-// or the specified time for the run is after the present time.
+// or the specified time for the run is after the present time. A recurring
+// initiator records when it runs next after each run, and runs again then.
 func (ot *OneTime) RunJobAt(initiator models.Initiator, job models.JobSpec) {
-	select {
-	case <-ot.done:
-	case <-ot.Clock.After(utils.DurationFromNow(initiator.Time.Time)):
+	runAt := initiator.RunAtFor(job)
+	for {
+		select {
+		case <-ot.done:
+			return
+		case <-ot.Clock.After(utils.DurationFromNow(runAt)):
+		}
+
 		now := time.Now()
-		if !job.Started(now) || job.Ended(now) {
+		if job.Ended(now) {
 			return
 		}
 
-		_, err := ot.RunManager.Create(job.ID, &initiator, nil, &models.RunRequest{})
-		if err != nil && !ExpectedRecurringScheduleJobError(err) {
-			logger.Error(err.Error())
+		// The initiator no longer exists if the job was archived or updated
+		// while waiting.
+		if _, err := ot.Store.FindInitiator(initiator.ID); errors.Cause(err) == orm.ErrorNotFound {
 			return
 		}
 
-		if err := ot.Store.MarkRan(&initiator, true); err != nil {
+		if job.Started(now) {
+			_, err := ot.RunManager.Create(job.ID, &initiator, nil, &models.RunRequest{})
+			if err != nil && !ExpectedRecurringScheduleJobError(err) {
+				logger.Error(err.Error())
+				return
+			}
+		} else if initiator.Every.IsInstant() {
+			return
+		}
+
+		next, recurs := initiator.RunAfter(runAt, now, job)
+		if !recurs {
+			if err := ot.Store.MarkRan(&initiator, true); err != nil {
+				logger.Error(err.Error())
+			}
+			return
+		}
+		if err := ot.Store.SetNextRunAt(&initiator, next); err != nil {
 			logger.Error(err.Error())
 		}
+		runAt = next
 	}
 }
 
@@ -214,4 +452,5 @@ type Cron interface {
 	Start()
 	Stop() context.Context
 	AddFunc(string, func()) (cron.EntryID, error)
+	Remove(cron.EntryID)
 }
@@ -7,7 +7,10 @@ import (
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/internal/mocks"
 	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
 
+	"github.com/jinzhu/gorm"
+	"github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -176,6 +179,56 @@ func TestOneTime_AddJob(t *testing.T) {
 	runManager.AssertExpectations(t)
 }
 
+func TestOneTime_AddJob_Recurring(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	executeJobChannel := make(chan struct{})
+	runManager := new(mocks.RunManager)
+	runManager.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Twice().
+		Run(func(mock.Arguments) {
+			executeJobChannel <- struct{}{}
+		})
+
+	clock := cltest.NewTriggerClock(t)
+
+	ot := services.OneTime{
+		Clock:      clock,
+		Store:      store,
+		RunManager: runManager,
+	}
+	require.NoError(t, ot.Start())
+
+	j := cltest.NewJobWithRunAtInitiator(time.Now())
+	j.Initiators[0].Every = models.MustMakeDuration(time.Hour)
+	j.EndAt = null.TimeFrom(time.Now().Add(90 * time.Minute))
+	require.Nil(t, store.CreateJob(&j))
+
+	ot.AddJob(j)
+
+	for i := 0; i < 2; i++ {
+		clock.Trigger()
+		cltest.CallbackOrTimeout(t, "recurring run", func() {
+			<-executeJobChannel
+		}, 3*time.Second)
+	}
+
+	// The run due next is after EndAt, which ends the recurrence.
+	gomega.NewGomegaWithT(t).Eventually(func() bool {
+		initr, err := store.FindInitiator(j.Initiators[0].ID)
+		require.NoError(t, err)
+		return initr.Ran
+	}).Should(gomega.BeTrue())
+	ot.Stop()
+
+	initr, err := store.FindInitiator(j.Initiators[0].ID)
+	require.NoError(t, err)
+	assert.True(t, initr.NextRunAt.Valid)
+	runManager.AssertExpectations(t)
+}
+
 func TestOneTime_AddJob_PastEnd(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
@@ -244,3 +297,127 @@ func TestExpectedRecurringScheduleJobError(t *testing.T) {
 	assert.True(t, services.ExpectedRecurringScheduleJobError(services.RecurringScheduleJobError{}))
 	assert.False(t, services.ExpectedRecurringScheduleJobError(errors.New("recurring scheduler job error, but wrong type")))
 }
+
+func TestMissedCronSchedules(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2020, 5, 5, 6, 0, 0, 0, time.UTC)
+	daily := models.Cron("CRON_TZ=UTC 0 0 * * *")
+
+	tests := []struct {
+		name  string
+		limit uint
+		want  []time.Time
+	}{
+		{"zero limit", 0, []time.Time{}},
+		{"under limit", 10, []time.Time{
+			time.Date(2020, 5, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 5, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 5, 5, 0, 0, 0, 0, time.UTC),
+		}},
+		{"keeps most recent", 2, []time.Time{
+			time.Date(2020, 5, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 5, 5, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			missed, err := services.MissedCronSchedules(daily, since, now, test.limit)
+			require.NoError(t, err)
+			require.Len(t, missed, len(test.want))
+			for i, want := range test.want {
+				assert.True(t, want.Equal(missed[i]), "expected %s, got %s", want, missed[i])
+			}
+		})
+	}
+}
+
+func TestPreviewCronRuns(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2020, 12, 23, 12, 0, 0, 0, time.UTC)
+	initr := models.Initiator{
+		Type: models.InitiatorCron,
+		InitiatorParams: models.InitiatorParams{
+			Schedule: "0 9 * * *",
+			Timezone: "America/New_York",
+			Calendar: models.CronCalendar{Holidays: []string{"2020-12-25"}},
+		},
+	}
+	at := func(day, hour int) time.Time {
+		return time.Date(2020, 12, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	runs, err := services.PreviewCronRuns(initr, from, 3)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.True(t, at(23, 14).Equal(runs[0].ScheduledAt))
+	assert.True(t, at(23, 14).Equal(*runs[0].RunAt))
+	assert.True(t, at(25, 14).Equal(runs[2].ScheduledAt))
+	assert.Nil(t, runs[2].RunAt)
+
+	initr.Calendar.Defer = true
+	runs, err = services.PreviewCronRuns(initr, from, 3)
+	require.NoError(t, err)
+	assert.True(t, at(26, 5).Equal(*runs[2].RunAt), "deferred to midnight in New York, got %s", runs[2].RunAt)
+}
+
+func TestScheduler_Start_CatchUpBackfill(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("CRON_CATCH_UP", "backfill")
+	store.Config.Set("CRON_CATCH_UP_MAX_RUNS", 2)
+
+	job := cltest.NewJobWithSchedule("CRON_TZ=UTC 0 0 * * *")
+	require.NoError(t, store.CreateJob(&job))
+
+	run := cltest.NewJobRun(job)
+	require.NoError(t, store.CreateJobRun(&run))
+	require.NoError(t, store.RawDB(func(db *gorm.DB) error {
+		return db.Model(&run).UpdateColumn("created_at", time.Now().Add(-72*time.Hour)).Error
+	}))
+
+	runManager := new(mocks.RunManager)
+	runManager.On("Create", job.ID, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Twice()
+
+	sched := services.NewScheduler(store, runManager)
+	require.NoError(t, sched.Start())
+	sched.Stop()
+
+	runManager.AssertExpectations(t)
+}
+
+func TestScheduler_Start_CatchUpSkip(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("CRON_CATCH_UP", "skip")
+
+	job := cltest.NewJobWithSchedule("CRON_TZ=UTC 0 0 * * *")
+	require.NoError(t, store.CreateJob(&job))
+
+	run := cltest.NewJobRun(job)
+	require.NoError(t, store.CreateJobRun(&run))
+	require.NoError(t, store.RawDB(func(db *gorm.DB) error {
+		return db.Model(&run).UpdateColumn("created_at", time.Now().Add(-72*time.Hour)).Error
+	}))
+
+	runManager := new(mocks.RunManager)
+	runManager.On("CreateErrored", job.ID, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Times(3)
+
+	sched := services.NewScheduler(store, runManager)
+	require.NoError(t, sched.Start())
+	sched.Stop()
+
+	runManager.AssertExpectations(t)
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	// escrowBackfillBlocks is how many blocks behind the first head the
+	// ServiceAgreementEscrowTracker looks for payments, when none were
+	// recorded yet.
+	escrowBackfillBlocks = 10000
+	// escrowMaxBlocks is the most blocks the ServiceAgreementEscrowTracker
+	// fetches the logs of on a head, catching up over the following heads.
+	escrowMaxBlocks = 10000
+	// escrowCursor is the consumer the ServiceAgreementEscrowTracker records
+	// the last block it fetched the logs of as.
+	escrowCursor = "service_agreement_escrow_tracker"
+)
+
+// ServiceAgreementEscrowTracker records the LINK coordinators escrow for
+// the node's service agreements, decoding their OracleRequest events into
+// deposits and CancelOracleRequest events into refunds. The logs of each
+// block are fetched once it has MIN_INCOMING_CONFIRMATIONS, in the
+// background, skipping the heads received while a fetch is in progress but
+// the latest. The last block fetched is recorded, so that the tracker
+// resumes after it when the node restarts.
+type ServiceAgreementEscrowTracker struct {
+	store    *store.Store
+	disabled bool
+	// next is the number of the next block the logs of which are fetched, or
+	// 0 until the first head.
+	next uint64
+
+	chHead   chan uint64
+	chStop   chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewServiceAgreementEscrowTracker returns a new
+// ServiceAgreementEscrowTracker.
+func NewServiceAgreementEscrowTracker(store *store.Store) *ServiceAgreementEscrowTracker {
+	return &ServiceAgreementEscrowTracker{
+		store:    store,
+		disabled: store.Config.EthereumDisabled(),
+		chHead:   make(chan uint64, 1),
+		chStop:   make(chan struct{}),
+	}
+}
+
+// Start starts recording payments on the heads received.
+func (t *ServiceAgreementEscrowTracker) Start() error {
+	if t.disabled {
+		return nil
+	}
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		for {
+			select {
+			case head := <-t.chHead:
+				logger.ErrorIf(t.Track(head), "failed to track service agreement payments")
+			case <-t.chStop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops recording payments, waiting for any fetch in progress.
+// Stopping it again does nothing.
+func (t *ServiceAgreementEscrowTracker) Stop() {
+	if t.disabled {
+		return
+	}
+	t.stopOnce.Do(func() {
+		close(t.chStop)
+		t.wg.Wait()
+	})
+}
+
+// Connect records the payments up to the head connected at.
+func (t *ServiceAgreementEscrowTracker) Connect(head *models.Head) error {
+	if head != nil {
+		t.OnNewHead(head)
+	}
+	return nil
+}
+
+// Disconnect does nothing, payments being recorded from the next head.
+func (t *ServiceAgreementEscrowTracker) Disconnect() {}
+
+// OnNewHead records the payments up to the head in the background.
+func (t *ServiceAgreementEscrowTracker) OnNewHead(head *models.Head) {
+	if t.disabled {
+		return
+	}
+	select {
+	case <-t.chHead:
+	default:
+	}
+	select {
+	case t.chHead <- uint64(head.Number):
+	default:
+	}
+}
+
+// Track records the payments of the blocks from the last one fetched up to
+// the last one confirmed at head, at most escrowMaxBlocks at once.
+func (t *ServiceAgreementEscrowTracker) Track(head uint64) error {
+	confirmations := uint64(t.store.Config.MinIncomingConfirmations())
+	if head < confirmations {
+		return nil
+	}
+	to := head - confirmations
+	if t.next == 0 {
+		from, err := t.firstBlock(to)
+		if err != nil {
+			return err
+		}
+		t.next = from
+	}
+	if t.next > to {
+		return nil
+	}
+	if to-t.next >= escrowMaxBlocks {
+		to = t.next + escrowMaxBlocks - 1
+	}
+
+	coordinators, err := t.store.ServiceAgreementCoordinators()
+	if err != nil {
+		return errors.Wrap(err, "finding the coordinators of service agreements")
+	}
+	if len(coordinators) > 0 {
+		logs, err := t.store.TxManager.GetLogs(ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(t.next),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: coordinators,
+			Topics:    [][]common.Hash{{models.RunLogTopic20190207withoutIndexes, models.CancelOracleRequestLogTopic}},
+		})
+		if err != nil {
+			return errors.Wrap(err, "fetching coordinator logs")
+		}
+		for _, log := range logs {
+			t.record(log)
+		}
+	}
+	if err := t.store.AdvanceBlockCursor(escrowCursor, to); err != nil {
+		return errors.Wrap(err, "recording the last block payments were recorded from")
+	}
+	t.next = to + 1
+	return nil
+}
+
+// firstBlock returns the block after the last one fetched before the node
+// restarted. Before the last block fetched was recorded, it was that of the
+// last payment recorded, which is fetched again, payments being recorded
+// once. If none were, the logs of the escrowBackfillBlocks before to are
+// fetched.
+func (t *ServiceAgreementEscrowTracker) firstBlock(to uint64) (uint64, error) {
+	last, err := t.store.BlockCursor(escrowCursor)
+	if err != nil {
+		return 0, errors.Wrap(err, "finding the last block payments were recorded from")
+	} else if last > 0 {
+		return last + 1, nil
+	}
+	from, err := t.store.LatestServiceAgreementPaymentBlock()
+	if err != nil {
+		return 0, errors.Wrap(err, "finding the last block payments were recorded from")
+	}
+	if from == 0 && to > escrowBackfillBlocks {
+		from = to - escrowBackfillBlocks
+	}
+	return from, nil
+}
+
+// record records the payment a coordinator log is decoded into, if it is for
+// one of the node's service agreements.
+func (t *ServiceAgreementEscrowTracker) record(log eth.Log) {
+	payment, err := models.NewServiceAgreementPaymentFromLog(log)
+	if err != nil {
+		logger.Warnw("Unable to decode coordinator payment", "tx_hash", log.TxHash.Hex(), "log_index", log.Index, "error", err)
+		return
+	}
+
+	switch payment.Kind {
+	case models.ServiceAgreementDeposit:
+		_, err = t.store.FindServiceAgreement(payment.ServiceAgreementID)
+	case models.ServiceAgreementRefund:
+		var deposit models.ServiceAgreementPayment
+		deposit, err = t.store.FindServiceAgreementDeposit(payment.RequestID)
+		payment.ServiceAgreementID = deposit.ServiceAgreementID
+		payment.Amount = deposit.Amount
+	}
+	if errors.Cause(err) == orm.ErrorNotFound {
+		return
+	} else if err != nil {
+		logger.Errorw("Unable to find the service agreement of coordinator payment", "request_id", payment.RequestID.Hex(), "error", err)
+		return
+	}
+
+	created, err := t.store.CreateServiceAgreementPayment(&payment)
+	if err != nil {
+		logger.Errorw("Unable to record service agreement payment", "request_id", payment.RequestID.Hex(), "error", err)
+		return
+	}
+	if created {
+		logger.Infow("Recorded service agreement payment", "id", payment.ServiceAgreementID, "kind", payment.Kind, "request_id", payment.RequestID.Hex(), "amount", payment.Amount.String())
+	}
+}
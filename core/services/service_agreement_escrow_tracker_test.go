@@ -0,0 +1,82 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAgreementEscrowTracker_Track(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("MIN_INCOMING_CONFIRMATIONS", 2)
+
+	coordinator := cltest.NewAddress()
+	sa, err := cltest.ServiceAgreementFromString(string(cltest.MustReadFile(t, "../internal/fixtures/web/noop_agreement.json")))
+	require.NoError(t, err)
+	sa.JobSpec.Initiators[0].Address = coordinator
+	require.NoError(t, store.CreateServiceAgreement(&sa))
+
+	var data []byte
+	data = append(data, cltest.NewAddress().Hash().Bytes()...)
+	data = append(data, common.HexToHash("0xfeed").Bytes()...)
+	data = append(data, sa.Encumbrance.Payment.ToHash().Bytes()...)
+	deposit := eth.Log{
+		Address:     coordinator,
+		Topics:      []common.Hash{models.RunLogTopic20190207withoutIndexes, common.HexToHash(sa.ID)},
+		Data:        data,
+		TxHash:      cltest.NewHash(),
+		BlockHash:   cltest.NewHash(),
+		BlockNumber: 3,
+	}
+	blocks := func(from, to uint64) interface{} {
+		return mock.MatchedBy(func(q ethereum.FilterQuery) bool {
+			return q.FromBlock.Uint64() == from && q.ToBlock.Uint64() == to && q.Addresses[0] == coordinator
+		})
+	}
+
+	txm := new(mocks.TxManager)
+	store.TxManager = txm
+	txm.On("GetLogs", blocks(0, 8)).Return([]eth.Log{deposit}, nil).Once()
+	txm.On("GetLogs", blocks(9, 9)).Return([]eth.Log{}, nil).Once()
+	tracker := services.NewServiceAgreementEscrowTracker(store)
+	require.NoError(t, tracker.Track(10))
+	require.NoError(t, tracker.Track(11))
+
+	payments, count, err := store.ServiceAgreementPaymentsFor(sa.ID, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	assert.Equal(t, models.ServiceAgreementDeposit, payments[0].Kind)
+	assert.Equal(t, uint64(3), payments[0].BlockNumber)
+
+	// Once the node restarts, the logs are fetched from after the last block
+	// fetched, not from the block of the last payment
+	txm.On("GetLogs", blocks(10, 12)).Return([]eth.Log{}, nil).Once()
+	restarted := services.NewServiceAgreementEscrowTracker(store)
+	require.NoError(t, restarted.Track(14))
+	txm.AssertExpectations(t)
+}
+
+func TestServiceAgreementEscrowTracker_StopTwice(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	tracker := services.NewServiceAgreementEscrowTracker(store)
+	require.NoError(t, tracker.Start())
+	tracker.Stop()
+	tracker.Stop()
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// serviceAgreementExpirerPollInterval is how often the ServiceAgreementExpirer
+// looks for service agreements expiring.
+const serviceAgreementExpirerPollInterval = time.Minute
+
+var promServiceAgreementsExpiring = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "service_agreements_expiring",
+	Help: "The number of service agreements in force which end within SERVICE_AGREEMENT_EXPIRY_NOTICE",
+})
+
+// ErrServiceAgreementTerminated is returned when terminating a service
+// agreement which was already terminated.
+var ErrServiceAgreementTerminated = errors.New("service agreement is already terminated")
+
+// JobArchiver archives jobs, stopping the services watching for their
+// initiators.
+type JobArchiver interface {
+	ArchiveJob(*models.ID) error
+}
+
+// ServiceAgreementExpirer notifies the operator, once, when a service
+// agreement ends within SERVICE_AGREEMENT_EXPIRY_NOTICE, and terminates the
+// agreements which have ended, archiving their jobs.
+type ServiceAgreementExpirer struct {
+	store    *store.Store
+	archiver JobArchiver
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewServiceAgreementExpirer returns a new ServiceAgreementExpirer.
+func NewServiceAgreementExpirer(store *store.Store, archiver JobArchiver) *ServiceAgreementExpirer {
+	return &ServiceAgreementExpirer{
+		store:    store,
+		archiver: archiver,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start checks the service agreements every minute until stopped.
+func (e *ServiceAgreementExpirer) Start() error {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		for {
+			select {
+			case <-e.done:
+				return
+			case <-e.store.Clock.After(serviceAgreementExpirerPollInterval):
+				logger.ErrorIf(e.Check(), "failed to check service agreements for expiry")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops checking the service agreements, waiting for any check in
+// progress. Stopping it again does nothing.
+func (e *ServiceAgreementExpirer) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.done)
+		e.wg.Wait()
+	})
+}
+
+// Check terminates the service agreements which have ended, and notifies the
+// operator of those ending within the expiry notice which it has not been
+// notified of yet.
+func (e *ServiceAgreementExpirer) Check() error {
+	now := e.store.Clock.Now()
+	notice := e.store.Config.ServiceAgreementExpiryNotice().Duration()
+	sas, err := e.store.ServiceAgreementsEndingBefore(now.Add(notice))
+	if err != nil {
+		return err
+	}
+
+	expiring := 0
+	for _, sa := range sas {
+		switch sa.Status(now, notice) {
+		case models.ServiceAgreementExpired:
+			if err := TerminateServiceAgreement(e.store, e.archiver, sa); err != nil {
+				logger.Errorw("Unable to terminate expired service agreement", "id", sa.ID, "error", err)
+				continue
+			}
+			logger.Infow("Service agreement expired, archived its job", "id", sa.ID, "job", sa.JobSpecID.String(), "endAt", sa.Encumbrance.EndAt.Time)
+		case models.ServiceAgreementExpiring:
+			expiring++
+			if sa.ExpiryNotifiedAt.Valid {
+				continue
+			}
+			logger.Warnw("Service agreement expires soon", "id", sa.ID, "job", sa.JobSpecID.String(), "endAt", sa.Encumbrance.EndAt.Time)
+			logger.ErrorIf(e.store.MarkServiceAgreementExpiryNotified(sa.ID, now), "failed to record service agreement expiry notice")
+		}
+	}
+	promServiceAgreementsExpiring.Set(float64(expiring))
+	return nil
+}
+
+// TerminateServiceAgreement archives the job of the service agreement, then
+// records that the agreement was terminated.
+func TerminateServiceAgreement(store *store.Store, archiver JobArchiver, sa models.ServiceAgreement) error {
+	if sa.TerminatedAt.Valid {
+		return ErrServiceAgreementTerminated
+	}
+	// The job may have been archived already, by hand or by an earlier
+	// attempt which failed to record the termination.
+	if err := archiver.ArchiveJob(sa.JobSpecID); err != nil && errors.Cause(err) != orm.ErrorNotFound {
+		return errors.Wrap(err, "archiving the job of the service agreement")
+	}
+	return store.TerminateServiceAgreement(sa.ID, store.Clock.Now())
+}
@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAgreementExpirer_Check(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("SERVICE_AGREEMENT_EXPIRY_NOTICE", "24h")
+
+	input := string(cltest.MustReadFile(t, "../internal/fixtures/web/noop_agreement.json"))
+	createSA := func(endAt time.Time) models.ServiceAgreement {
+		sa, err := cltest.ServiceAgreementFromString(input)
+		require.NoError(t, err)
+		sa.ID = cltest.NewHash().String()
+		sa.Encumbrance.EndAt = models.NewAnyTime(endAt)
+		require.NoError(t, store.CreateServiceAgreement(&sa))
+		return sa
+	}
+	expired := createSA(time.Now().Add(-time.Minute))
+	expiring := createSA(time.Now().Add(time.Hour))
+	active := createSA(time.Now().Add(48 * time.Hour))
+
+	expirer := services.NewServiceAgreementExpirer(store, store)
+	require.NoError(t, expirer.Check())
+
+	sa := cltest.FindServiceAgreement(t, store, expired.ID)
+	assert.True(t, sa.TerminatedAt.Valid)
+	_, err := store.FindJob(expired.JobSpecID)
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+
+	sa = cltest.FindServiceAgreement(t, store, expiring.ID)
+	assert.False(t, sa.TerminatedAt.Valid)
+	assert.True(t, sa.ExpiryNotifiedAt.Valid)
+
+	sa = cltest.FindServiceAgreement(t, store, active.ID)
+	assert.False(t, sa.TerminatedAt.Valid)
+	assert.False(t, sa.ExpiryNotifiedAt.Valid)
+
+	assert.Equal(t, services.ErrServiceAgreementTerminated,
+		services.TerminateServiceAgreement(store, store, cltest.FindServiceAgreement(t, store, expired.ID)))
+}
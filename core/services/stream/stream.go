@@ -0,0 +1,300 @@
+package stream
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+const (
+	// pingPeriod is how often the bridge is pinged to detect dead connections.
+	pingPeriod = 30 * time.Second
+	// pongWait is how long the bridge has to answer a ping, or push an
+	// update, before the connection is considered dead.
+	pongWait = 2 * pingPeriod
+	// writeWait bounds the time taken to write a message to the bridge.
+	writeWait = 10 * time.Second
+)
+
+// Service is the interface encapsulating all functionality needed to trigger
+// job runs from updates pushed by stream bridges.
+type Service interface {
+	AddJob(models.JobSpec) error
+	RemoveJob(*models.ID)
+	Start() error
+	Stop()
+}
+
+type concreteService struct {
+	store      *store.Store
+	runManager services.RunManager
+	jobs       map[models.ID][]*listener
+	jobsMu     sync.Mutex
+}
+
+// New creates a service that holds a websocket connection open with the
+// bridge of every stream initiator, creating a run of the initiator's job for
+// each update the bridge pushes.
+func New(store *store.Store, runManager services.RunManager) Service {
+	return &concreteService{
+		store:      store,
+		runManager: runManager,
+		jobs:       make(map[models.ID][]*listener),
+	}
+}
+
+func (s *concreteService) Start() error {
+	return s.store.Jobs(func(j *models.JobSpec) bool {
+		if j == nil {
+			logger.Error("received nil job")
+			return true
+		}
+		logger.ErrorIf(s.AddJob(*j), "error adding stream job")
+		return true
+	}, models.InitiatorStream)
+}
+
+func (s *concreteService) Stop() {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	for id, listeners := range s.jobs {
+		for _, l := range listeners {
+			l.stop()
+		}
+		delete(s.jobs, id)
+	}
+}
+
+// AddJob connects each of the job's stream initiators to its bridge.
+// Connections are retried in the background until they succeed.
+func (s *concreteService) AddJob(job models.JobSpec) error {
+	initrs := job.InitiatorsFor(models.InitiatorStream)
+	if len(initrs) == 0 {
+		return nil
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if _, ok := s.jobs[*job.ID]; ok {
+		return errors.Errorf("bridges of job %s are already being listened to", job.ID.String())
+	}
+
+	listeners := make([]*listener, len(initrs))
+	for i, initr := range initrs {
+		listeners[i] = &listener{
+			store:      s.store,
+			runManager: s.runManager,
+			initr:      initr,
+			chStop:     make(chan struct{}),
+			chDone:     make(chan struct{}),
+		}
+		go listeners[i].run()
+	}
+	s.jobs[*job.ID] = listeners
+	return nil
+}
+
+// RemoveJob closes the connections of the job's stream initiators.
+func (s *concreteService) RemoveJob(id *models.ID) {
+	s.jobsMu.Lock()
+	listeners := s.jobs[*id]
+	delete(s.jobs, *id)
+	s.jobsMu.Unlock()
+
+	for _, l := range listeners {
+		l.stop()
+	}
+}
+
+// subscribeMessage is sent to the bridge once connected, telling it which job
+// the updates are for and passing along the initiator's request data.
+type subscribeMessage struct {
+	ID   string      `json:"id"`
+	Data models.JSON `json:"data"`
+}
+
+// updateMessage is pushed by the bridge, in the same shape as the response of
+// an http bridge.
+type updateMessage struct {
+	Data  models.JSON `json:"data"`
+	Error string      `json:"error"`
+}
+
+// listener holds the connection of a single stream initiator to its bridge.
+type listener struct {
+	store      *store.Store
+	runManager services.RunManager
+	initr      models.Initiator
+	conn       *websocket.Conn
+	connMu     sync.Mutex
+	chStop     chan struct{}
+	chDone     chan struct{}
+}
+
+// run connects to the bridge and listens for updates, reconnecting with a
+// backoff whenever the connection drops, until stopped.
+func (l *listener) run() {
+	defer close(l.chDone)
+
+	sleeper := utils.NewBackoffSleeper()
+	for {
+		select {
+		case <-l.chStop:
+			return
+		case <-time.After(sleeper.After()):
+		}
+
+		conn, err := l.connect()
+		if err != nil {
+			logger.Warnw("Unable to connect to stream bridge",
+				"bridge", l.initr.BridgeName, "job_id", l.initr.JobSpecID.String(), "error", err)
+			continue
+		}
+		sleeper.Reset()
+
+		err = l.listen(conn)
+		select {
+		case <-l.chStop:
+			return
+		default:
+		}
+		logger.Warnw("Lost connection to stream bridge, reconnecting",
+			"bridge", l.initr.BridgeName, "job_id", l.initr.JobSpecID.String(), "error", err)
+	}
+}
+
+// connect dials the bridge, authenticating as for any bridge request, and
+// subscribes to updates for the job.
+func (l *listener) connect() (*websocket.Conn, error) {
+	bt, err := l.store.FindBridge(l.initr.BridgeName)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding stream bridge")
+	} else if bt.Mode != models.BridgeModeStream {
+		return nil, errors.Errorf("bridge %s is not a stream bridge", bt.Name)
+	}
+
+	header := http.Header{}
+	if err := adapters.SetBridgeAuthHeaders(header, bt, l.store); err != nil {
+		return nil, errors.Wrap(err, "authenticating with stream bridge")
+	}
+	tlsConfig, err := adapters.ClientTLSConfig(bt.ClientCertificate, l.store)
+	if err != nil {
+		return nil, err
+	}
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConfig
+	conn, _, err := dialer.Dial(bt.URL.String(), header)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	err = conn.WriteJSON(subscribeMessage{ID: l.initr.JobSpecID.String(), Data: l.initr.RequestData})
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "subscribing to stream bridge")
+	}
+	return conn, nil
+}
+
+// listen creates a run for every update pushed over the connection, pinging
+// the bridge to detect dead connections, until the connection fails or the
+// listener is stopped.
+func (l *listener) listen(conn *websocket.Conn) error {
+	l.connMu.Lock()
+	l.conn = conn
+	l.connMu.Unlock()
+	defer func() {
+		l.connMu.Lock()
+		l.conn = nil
+		l.connMu.Unlock()
+		conn.Close()
+	}()
+
+	// The stop channel may have been closed while connecting, before stop
+	// could close the connection.
+	select {
+	case <-l.chStop:
+		return nil
+	default:
+	}
+
+	extendDeadline := func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	}
+	extendDeadline("")
+	conn.SetPongHandler(extendDeadline)
+
+	chPingerDone := make(chan struct{})
+	defer close(chPingerDone)
+	go func() {
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-chPingerDone:
+				return
+			case <-ticker.C:
+				deadline := time.Now().Add(writeWait)
+				if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		extendDeadline("")
+		l.handleUpdate(payload)
+	}
+}
+
+func (l *listener) handleUpdate(payload []byte) {
+	var update updateMessage
+	if err := json.Unmarshal(payload, &update); err != nil {
+		logger.Errorw("Unable to parse stream bridge update", "bridge", l.initr.BridgeName, "error", err)
+		return
+	} else if update.Error != "" {
+		logger.Warnw("Stream bridge pushed an error", "bridge", l.initr.BridgeName, "error", update.Error)
+		return
+	} else if !update.Data.IsObject() {
+		logger.Errorw("Stream bridge update has no data", "bridge", l.initr.BridgeName, "update", string(payload))
+		return
+	}
+
+	_, err := l.runManager.Create(l.initr.JobSpecID, &l.initr, nil, models.NewRunRequest(update.Data))
+	logger.ErrorIf(err, "unable to create run for stream bridge update")
+}
+
+// stop closes the connection to the bridge, and waits for the listener to
+// finish.
+func (l *listener) stop() {
+	close(l.chStop)
+
+	l.connMu.Lock()
+	if l.conn != nil {
+		closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		logger.ErrorIf(l.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(writeWait)))
+		l.conn.Close()
+	}
+	l.connMu.Unlock()
+
+	<-l.chDone
+}
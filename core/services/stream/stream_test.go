@@ -0,0 +1,65 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services/stream"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_AddJob_CreatesRunsFromPushedUpdates(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	server, wsCleanup := cltest.NewEventWebSocketServer(t)
+	defer wsCleanup()
+
+	_, bt := cltest.NewBridgeType(t, "pricestream", server.URL.String())
+	bt.Mode = models.BridgeModeStream
+	require.NoError(t, store.CreateBridgeType(bt))
+
+	job := cltest.NewJob()
+	job.Initiators = []models.Initiator{{
+		JobSpecID: job.ID,
+		Type:      models.InitiatorStream,
+		InitiatorParams: models.InitiatorParams{
+			BridgeName:  bt.Name,
+			RequestData: cltest.JSONFromString(t, `{"pair":"ETH/USD"}`),
+		},
+	}}
+	require.NoError(t, store.CreateJob(&job))
+
+	runManager := new(mocks.RunManager)
+	created := make(chan *models.RunRequest, 1)
+	runManager.On("Create", job.ID, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Run(func(args mock.Arguments) {
+			created <- args.Get(3).(*models.RunRequest)
+		})
+
+	service := stream.New(store, runManager)
+	require.NoError(t, service.AddJob(job))
+	defer service.RemoveJob(job.ID)
+
+	var subscription string
+	cltest.CallbackOrTimeout(t, "subscribed", func() {
+		subscription = <-server.Received
+	})
+	assert.JSONEq(t, `{"id":"`+job.ID.String()+`","data":{"pair":"ETH/USD"}}`, subscription)
+
+	require.NoError(t, server.Broadcast(`{"error":"upstream unavailable"}`))
+	require.NoError(t, server.Broadcast(`{"data":{"price":"100"}}`))
+
+	var rr *models.RunRequest
+	cltest.CallbackOrTimeout(t, "run created", func() {
+		rr = <-created
+	})
+	assert.Equal(t, "100", rr.RequestParams.Get("price").String())
+	runManager.AssertNumberOfCalls(t, "Create", 1)
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/multierr"
+	null "gopkg.in/guregu/null.v3"
+)
+
+var numberStuckRunsEscalated = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "stuck_runs_escalated",
+	Help: "The number of stuck runs which could not be resumed",
+})
+
+// StuckRunEscalator is told about the runs which are still stuck after the
+// StuckRunJanitor tried to resume them, so that someone can look into them.
+type StuckRunEscalator interface {
+	Escalate(run *models.JobRun, err error)
+}
+
+// NewLoggingStuckRunEscalator returns a StuckRunEscalator which logs the runs
+// escalated to it as errors, and counts them in the stuck_runs_escalated
+// metric.
+func NewLoggingStuckRunEscalator() StuckRunEscalator {
+	return loggingStuckRunEscalator{}
+}
+
+type loggingStuckRunEscalator struct{}
+
+func (loggingStuckRunEscalator) Escalate(run *models.JobRun, err error) {
+	numberStuckRunsEscalated.Inc()
+	logger.Errorw("Run is stuck and could not be resumed", run.ForLogger("error", err)...)
+}
+
+// stuckRun is a run the StuckRunJanitor has found stuck before.
+type stuckRun struct {
+	id          *models.ID
+	resumptions uint
+	escalated   bool
+}
+
+// StuckRunJanitor looks for runs which have been in progress, pending a bridge
+// or pending confirmations for longer than the configured thresholds, and
+// resumes them through the run manager. Runs which are still stuck after the
+// configured number of resumptions, or which fail to resume, are escalated.
+// Runs whose bridges did not call them back before BRIDGE_CALLBACK_TIMEOUT
+// are errored.
+type StuckRunJanitor struct {
+	store      *store.Store
+	runManager RunManager
+	escalator  StuckRunEscalator
+	stuck      map[string]*stuckRun
+	done       chan struct{}
+	wg         sync.WaitGroup
+	stopOnce   sync.Once
+}
+
+// NewStuckRunJanitor returns a StuckRunJanitor which escalates to escalator.
+func NewStuckRunJanitor(store *store.Store, runManager RunManager, escalator StuckRunEscalator) *StuckRunJanitor {
+	return &StuckRunJanitor{
+		store:      store,
+		runManager: runManager,
+		escalator:  escalator,
+		stuck:      make(map[string]*stuckRun),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start looks for stuck runs every STUCK_RUN_CHECK_INTERVAL until stopped, or
+// does nothing if the interval is zero. Changes to the interval while the
+// node is running apply from the next sweep.
+func (j *StuckRunJanitor) Start() error {
+	if j.store.Config.StuckRunCheckInterval().IsInstant() {
+		return nil
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		for {
+			select {
+			case <-j.done:
+				return
+			case <-j.store.Clock.After(j.store.Config.StuckRunCheckInterval().Duration()):
+				logger.ErrorIf(j.Sweep(), "failed to look for stuck runs")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops looking for stuck runs, waiting for any sweep in progress.
+// Stopping it again does nothing.
+func (j *StuckRunJanitor) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.done)
+		j.wg.Wait()
+	})
+}
+
+// Sweep resumes or escalates the runs which are stuck now.
+func (j *StuckRunJanitor) Sweep() error {
+	config := j.store.Config
+	thresholds := []struct {
+		status    models.RunStatus
+		threshold time.Duration
+	}{
+		{models.RunStatusInProgress, config.StuckRunInProgressThreshold().Duration()},
+		{models.RunStatusPendingBridge, config.StuckRunPendingBridgeThreshold().Duration()},
+		{models.RunStatusPendingConfirmations, config.StuckRunConfirmationsThreshold().Duration()},
+	}
+
+	now := j.store.Clock.Now()
+	var merr error
+	for _, t := range thresholds {
+		if t.threshold == 0 {
+			continue
+		}
+		before := now.Add(-t.threshold)
+		err := j.store.UnscopedJobRunsWithStatusUpdatedBefore(func(run *models.JobRun) {
+			j.resume(run, before)
+		}, before, t.status)
+		merr = multierr.Append(merr, err)
+	}
+	merr = multierr.Append(merr, j.timeOutBridgeCallbacks(now))
+	return multierr.Append(merr, j.forgetUnstuck())
+}
+
+// timeOutBridgeCallbacks errors the runs pending on bridges which did not
+// call them back before their callbacks expired, and removes the expired
+// callbacks.
+func (j *StuckRunJanitor) timeOutBridgeCallbacks(now time.Time) error {
+	ids, err := j.store.ExpiredBridgeCallbackRuns(now)
+	if err != nil {
+		return err
+	}
+	var merr error
+	for _, id := range ids {
+		logger.Warnw("Bridge did not call run back in time, erroring it", "run", id.String())
+		timeout := models.BridgeRunResult{
+			Status:       models.RunStatusErrored,
+			ErrorMessage: null.StringFrom("bridge did not call back before BRIDGE_CALLBACK_TIMEOUT"),
+		}
+		merr = multierr.Append(merr, j.runManager.ResumePending(id, timeout))
+	}
+	return multierr.Append(merr, j.store.DeleteExpiredBridgeCallbacks(now))
+}
+
+func (j *StuckRunJanitor) resume(run *models.JobRun, stuckBefore time.Time) {
+	sr, ok := j.stuck[run.ID.String()]
+	if !ok {
+		sr = &stuckRun{id: run.ID}
+		j.stuck[run.ID.String()] = sr
+	}
+	if sr.escalated {
+		return
+	}
+
+	maxResumptions := j.store.Config.StuckRunMaxResumptions()
+	if sr.resumptions >= maxResumptions {
+		sr.escalated = true
+		j.escalator.Escalate(run, fmt.Errorf("still %s after %d resumptions", run.GetStatus(), sr.resumptions))
+		return
+	}
+
+	sr.resumptions++
+	logger.Warnw("Resuming stuck run", run.ForLogger("resumptions", sr.resumptions)...)
+	if err := j.runManager.ResumeStuck(run.ID, stuckBefore); err != nil {
+		sr.escalated = true
+		j.escalator.Escalate(run, err)
+	}
+}
+
+// forgetUnstuck forgets the runs which have moved on since they were stuck,
+// so that they are resumed afresh if they get stuck again.
+func (j *StuckRunJanitor) forgetUnstuck() error {
+	for key, sr := range j.stuck {
+		run, err := j.store.Unscoped().FindJobRun(sr.id)
+		if err == orm.ErrorNotFound {
+			delete(j.stuck, key)
+			continue
+		} else if err != nil {
+			return err
+		}
+		switch run.GetStatus() {
+		case models.RunStatusInProgress, models.RunStatusPendingBridge, models.RunStatusPendingConfirmations:
+		default:
+			delete(j.stuck, key)
+		}
+	}
+	return nil
+}
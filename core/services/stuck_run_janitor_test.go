@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEscalator struct {
+	escalated []*models.ID
+}
+
+func (e *recordingEscalator) Escalate(run *models.JobRun, err error) {
+	e.escalated = append(e.escalated, run.ID)
+}
+
+func TestStuckRunJanitor_Sweep(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	createRun := func(status models.RunStatus, age time.Duration) models.JobRun {
+		run := cltest.NewJobRun(job)
+		run.SetStatus(status)
+		require.NoError(t, store.CreateJobRun(&run))
+		require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+			return db.Exec("UPDATE job_runs SET updated_at = ? WHERE id = ?", time.Now().Add(-age), run.ID).Error
+		}))
+		return run
+	}
+
+	stuckInProgress := createRun(models.RunStatusInProgress, time.Hour)
+	createRun(models.RunStatusInProgress, time.Minute)
+	stuckPendingBridge := createRun(models.RunStatusPendingBridge, 48*time.Hour)
+	createRun(models.RunStatusPendingBridge, time.Hour)
+	createRun(models.RunStatusCompleted, 48*time.Hour)
+
+	runManager := new(mocks.RunManager)
+	runManager.On("ResumeStuck", stuckInProgress.ID, mock.Anything).Return(nil)
+	runManager.On("ResumeStuck", stuckPendingBridge.ID, mock.Anything).Return(errors.New("bridge is gone"))
+	escalator := &recordingEscalator{}
+	janitor := services.NewStuckRunJanitor(store, runManager, escalator)
+
+	require.NoError(t, janitor.Sweep())
+	runManager.AssertExpectations(t)
+	assert.Equal(t, []*models.ID{stuckPendingBridge.ID}, escalator.escalated)
+
+	maxResumptions := int(store.Config.StuckRunMaxResumptions())
+	for i := 1; i < maxResumptions; i++ {
+		require.NoError(t, janitor.Sweep())
+	}
+	runManager.AssertNumberOfCalls(t, "ResumeStuck", maxResumptions+1)
+	assert.Len(t, escalator.escalated, 1)
+
+	require.NoError(t, janitor.Sweep())
+	require.NoError(t, janitor.Sweep())
+	runManager.AssertNumberOfCalls(t, "ResumeStuck", maxResumptions+1)
+	assert.Equal(t, []*models.ID{stuckPendingBridge.ID, stuckInProgress.ID}, escalator.escalated)
+}
+
+func TestStuckRunJanitor_Sweep_ExpiredBridgeCallbacks(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	createRun := func(status models.RunStatus, timeout time.Duration) (models.JobRun, string) {
+		run := cltest.NewJobRunPendingBridge(job)
+		run.SetStatus(status)
+		run.TaskRuns[0].Status = status
+		require.NoError(t, store.CreateJobRun(&run))
+		token, callback, err := models.NewBridgeCallback(run.ID, run.TaskRuns[0].ID, timeout, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, store.CreateBridgeCallback(&callback))
+		return run, token
+	}
+
+	expired, _ := createRun(models.RunStatusPendingBridge, -time.Minute)
+	live, liveToken := createRun(models.RunStatusPendingBridge, time.Hour)
+	neverExpiring, neverExpiringToken := createRun(models.RunStatusPendingBridge, 0)
+	finished, finishedToken := createRun(models.RunStatusCompleted, 0)
+
+	runManager := new(mocks.RunManager)
+	runManager.On("ResumePending", expired.ID, mock.MatchedBy(func(brr models.BridgeRunResult) bool {
+		return brr.Status == models.RunStatusErrored
+	})).Return(nil).Once()
+	janitor := services.NewStuckRunJanitor(store, runManager, &recordingEscalator{})
+
+	require.NoError(t, janitor.Sweep())
+	require.NoError(t, janitor.Sweep())
+
+	// The callbacks of finished task runs are removed even if they never
+	// expire
+	ok, err := store.ConsumeBridgeCallback(finished.ID, finished.TaskRuns[0].ID, finishedToken, time.Now())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = store.ConsumeBridgeCallback(live.ID, live.TaskRuns[0].ID, liveToken, time.Now())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = store.ConsumeBridgeCallback(neverExpiring.ID, neverExpiring.TaskRuns[0].ID, neverExpiringToken, time.Now())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	runManager.AssertExpectations(t)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/eth"
@@ -15,9 +16,24 @@ import (
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/multierr"
 )
 
+var (
+	promLogsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_subscription_logs_dropped",
+		Help: "The number of logs received by subscriptions which did not trigger a run, by reason",
+	},
+		[]string{"reason"},
+	)
+
+	// numberManagedSubscriptions counts the ManagedSubscriptions which have
+	// not been unsubscribed, for the LeakWatchdog.
+	numberManagedSubscriptions int64
+)
+
 // Unsubscriber is the interface for all subscriptions, allowing one to unsubscribe.
 type Unsubscriber interface {
 	Unsubscribe()
@@ -39,13 +55,30 @@ func StartJobSubscription(job models.JobSpec, head *models.Head, store *strpkg.S
 	initrs := job.InitiatorsFor(models.LogBasedChainlinkJobInitiators...)
 
 	nextHead := head.NextInt() // Exclude current block from subscription
+	// The run logs of the blocks which may have been reorged, or missed
+	// while the node was down, are backfilled, as only one run is created
+	// for each run log.
+	runLogHead := nextHead
+	if depth := int64(store.Config.FinalityDepth()); head != nil && depth > 0 && head.Number >= depth {
+		runLogHead = big.NewInt(head.Number - depth + 1)
+	}
 	if replayFromBlock := store.Config.ReplayFromBlock(); replayFromBlock >= 0 {
 		replayFromBlockBN := big.NewInt(replayFromBlock)
 		nextHead = replayFromBlockBN
+		runLogHead = replayFromBlockBN
 	}
 
 	for _, initr := range initrs {
-		unsubscriber, err := NewInitiatorSubscription(initr, store.TxManager, runManager, nextHead, ReceiveLogRequest)
+		callback := ReceiveLogRequest
+		fromBlock := nextHead
+		switch initr.Type {
+		case models.InitiatorRandomnessLog:
+			callback = queueVRFRequest(store)
+		case models.InitiatorRunLog:
+			callback = receiveRunLogRequest(store)
+			fromBlock = runLogHead
+		}
+		unsubscriber, err := NewInitiatorSubscription(initr, store.TxManager, runManager, fromBlock, callback)
 		if err == nil {
 			unsubscribers = append(unsubscribers, unsubscriber)
 		} else {
@@ -111,7 +144,7 @@ func NewInitiatorSubscription(
 
 func (sub InitiatorSubscription) dispatchLog(log eth.Log) {
 	logger.Debugw(fmt.Sprintf("Log for %v initiator for job %s", sub.Initiator.Type, sub.Initiator.JobSpecID.String()),
-		"txHash", log.TxHash.Hex(), "logIndex", log.Index, "blockNumber", log.BlockNumber, "job", sub.Initiator.JobSpecID.String())
+		"tx_hash", log.TxHash.Hex(), "logIndex", log.Index, "blockNumber", log.BlockNumber, "job_id", sub.Initiator.JobSpecID.String())
 
 	base := models.InitiatorLogEvent{
 		Initiator: sub.Initiator,
@@ -122,26 +155,70 @@ func (sub InitiatorSubscription) dispatchLog(log eth.Log) {
 
 func loggerLogListening(initr models.Initiator, blockNumber *big.Int) {
 	msg := fmt.Sprintf("Listening for %v from block %v", initr.Type, presenters.FriendlyBigInt(blockNumber))
-	logger.Infow(msg, "address", utils.LogListeningAddress(initr.Address), "jobID", initr.JobSpecID.String())
+	logger.Infow(msg, "address", utils.LogListeningAddress(initr.Address), "job_id", initr.JobSpecID.String())
 }
 
 // ReceiveLogRequest parses the log and runs the job it indicated by its
 // GetJobSpecID method
 func ReceiveLogRequest(runManager RunManager, le models.LogRequest) {
+	if !shouldRunLogRequest(le) {
+		return
+	}
+	runJob(runManager, le)
+}
+
+// receiveRunLogRequest returns a callback for the logs of runlog initiators,
+// which only runs their jobs for the requesters on the requester allowlists.
+func receiveRunLogRequest(store *strpkg.Store) func(RunManager, models.LogRequest) {
+	return func(runManager RunManager, le models.LogRequest) {
+		if !shouldRunLogRequest(le) {
+			return
+		}
+		if err := validateAllowedRequester(store, le); err != nil {
+			promLogsDropped.WithLabelValues("requester_not_allowed").Inc()
+			if _, e := runManager.CreateErrored(le.GetJobSpecID(), le.GetInitiator(), err); e != nil {
+				logger.Errorw(e.Error())
+			}
+			logger.Errorw(err.Error(), le.ForLogger()...)
+			return
+		}
+		runJob(runManager, le)
+	}
+}
+
+func shouldRunLogRequest(le models.LogRequest) bool {
 	if !le.Validate() {
+		promLogsDropped.WithLabelValues("invalid").Inc()
 		logger.Debugw("discarding INVALID EVENT LOG", "log", le.GetLog())
-		return
+		return false
 	}
 
 	if le.GetLog().Removed {
-		logger.Debugw("Skipping run for removed log", "log", le.GetLog(), "jobId", le.GetJobSpecID().String())
-		return
+		promLogsDropped.WithLabelValues("removed").Inc()
+		logger.Debugw("Skipping run for removed log", "log", le.GetLog(), "job_id", le.GetJobSpecID().String())
+		return false
 	}
 
 	le.ToDebug()
+	return true
+}
 
-	runJob(runManager, le)
-
+func validateAllowedRequester(store *strpkg.Store, le models.LogRequest) error {
+	rle, ok := le.(models.RunLogEvent)
+	if !ok {
+		return nil
+	}
+	requester, err := rle.Requester()
+	if err != nil {
+		return err
+	}
+	allowed, err := store.RequesterAllowed(le.GetJobSpecID(), requester)
+	if err != nil {
+		return errors.Wrap(err, "while checking requester allowlists")
+	} else if !allowed {
+		return fmt.Errorf("requester %s is not on the requester allowlist", requester.Hex())
+	}
+	return nil
 }
 
 func runJob(runManager RunManager, le models.LogRequest) {
@@ -200,6 +277,7 @@ func NewManagedSubscription(
 		logs:            logs,
 		ethSubscription: es,
 	}
+	atomic.AddInt64(&numberManagedSubscriptions, 1)
 	go sub.listenToLogs(filter)
 	return sub, nil
 }
@@ -210,6 +288,7 @@ func (sub ManagedSubscription) Unsubscribe() {
 		timedUnsubscribe(sub.ethSubscription)
 	}
 	close(sub.logs)
+	atomic.AddInt64(&numberManagedSubscriptions, -1)
 }
 
 // timedUnsubscribe attempts to unsubscribe but aborts abruptly after a time delay
@@ -315,6 +315,56 @@ func TestServices_StartJobSubscription_RunlogNoTopicMatch(t *testing.T) {
 	}
 }
 
+func TestServices_StartJobSubscription_RunlogRequesterNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	eth := cltest.MockEthOnStore(t, store)
+	eth.Register("eth_getLogs", []ethpkg.Log{})
+	logChan := make(chan ethpkg.Log, 1)
+	eth.RegisterSubscription("logs", logChan)
+
+	job := cltest.NewJobWithRunLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	require.NoError(t, store.AllowRequester(job.ID, cltest.NewAddress()))
+	requester := cltest.NewAddress()
+
+	refused := make(chan error, 1)
+	runManager := new(mocks.RunManager)
+	runManager.On("CreateErrored", job.ID, mock.Anything, mock.Anything).
+		Return(nil, nil).
+		Run(func(args mock.Arguments) {
+			refused <- args.Error(2)
+		})
+
+	subscription, err := services.StartJobSubscription(job, cltest.Head(91), store, runManager)
+	require.NoError(t, err)
+	assert.NotNil(t, subscription)
+
+	logChan <- ethpkg.Log{
+		Address: job.Initiators[0].Address,
+		Data:    ethpkg.UntrustedBytes(cltest.StringToVersionedLogData20190207withoutIndexes(t, "id", requester, `{"value":"100"}`)),
+		Topics: []common.Hash{
+			models.RunLogTopic20190207withoutIndexes,
+			models.IDToTopic(job.ID),
+			cltest.NewAddress().Hash(),
+			common.BigToHash(big.NewInt(0)),
+		},
+	}
+
+	cltest.CallbackOrTimeout(t, "CreateErrored", func() {
+		err := <-refused
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "requester "+requester.Hex()+" is not on the requester allowlist")
+	})
+
+	runManager.AssertExpectations(t)
+	runManager.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	eth.EventuallyAllCalled(t)
+}
+
 func TestServices_NewInitiatorSubscription_EthLog_ReplayFromBlock(t *testing.T) {
 	t.Parallel()
 
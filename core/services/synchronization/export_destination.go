@@ -0,0 +1,184 @@
+package synchronization
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/pkg/errors"
+)
+
+// ExportDestination stores exported files, such as those of sync events and
+// archived run results. Putting a file under a name already stored replaces
+// it.
+type ExportDestination interface {
+	Put(ctx context.Context, name string, body []byte) error
+	Get(ctx context.Context, name string) ([]byte, error)
+}
+
+// ExportDestinationOptions are the credentials and endpoint of the bucket
+// files are exported to.
+type ExportDestinationOptions struct {
+	AccessKey string
+	Secret    string
+	Region    string
+	// Endpoint replaces the endpoint of S3 or GCS, for S3 compatible stores.
+	Endpoint *url.URL
+}
+
+// NewExportDestination returns the destination of the given URL:
+// s3://<bucket>/<prefix> for an S3 bucket, gs://<bucket>/<prefix> for a GCS
+// bucket, accessed with an HMAC key, or file://<directory> for a local
+// directory.
+func NewExportDestination(u *url.URL, opts ExportDestinationOptions) (ExportDestination, error) {
+	switch u.Scheme {
+	case "file":
+		return localDestination{dir: filepath.Join(u.Host, u.Path)}, nil
+	case "s3", "gs":
+		return newObjectStoreDestination(u, opts)
+	default:
+		return nil, fmt.Errorf("unsupported export URL scheme '%s', expected s3, gs or file", u.Scheme)
+	}
+}
+
+type localDestination struct {
+	dir string
+}
+
+// Put writes the file to a temporary file first and renames it, so that
+// partially written files are never seen under their name.
+func (d localDestination) Put(_ context.Context, name string, body []byte) error {
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return errors.Wrap(err, "creating export directory")
+	}
+	tmp, err := ioutil.TempFile(d.dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(d.dir, name))
+}
+
+func (d localDestination) Get(_ context.Context, name string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(d.dir, name))
+}
+
+// objectStoreDestination puts files in an S3 bucket, or any bucket with an
+// S3 compatible API such as GCS, signing requests with AWS Signature
+// Version 4.
+type objectStoreDestination struct {
+	client    *http.Client
+	base      url.URL
+	accessKey string
+	secret    string
+	region    string
+}
+
+func newObjectStoreDestination(u *url.URL, opts ExportDestinationOptions) (ExportDestination, error) {
+	bucket := u.Host
+	if bucket == "" {
+		return nil, fmt.Errorf("export URL '%s' has no bucket", u)
+	}
+	if opts.AccessKey == "" || opts.Secret == "" {
+		return nil, errors.New("exporting to a bucket needs an access key and a secret")
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	region := opts.Region
+	var base url.URL
+	switch {
+	case opts.Endpoint != nil:
+		base = *opts.Endpoint
+		base.Path = path.Join("/", base.Path, bucket, prefix)
+	case u.Scheme == "gs":
+		base = url.URL{Scheme: "https", Host: "storage.googleapis.com", Path: path.Join("/", bucket, prefix)}
+	default:
+		if region == "" {
+			region = "us-east-1"
+		}
+		base = url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, region), Path: path.Join("/", prefix)}
+	}
+	if region == "" {
+		region = "auto"
+	}
+
+	return objectStoreDestination{
+		client:    &http.Client{Timeout: time.Minute},
+		base:      base,
+		accessKey: opts.AccessKey,
+		secret:    opts.Secret,
+		region:    region,
+	}, nil
+}
+
+func (d objectStoreDestination) Put(ctx context.Context, name string, body []byte) error {
+	u := d.base
+	u.Path = path.Join(u.Path, name)
+	req, err := http.NewRequest(http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/gzip")
+	d.sign(req, body)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "putting %s", name)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("putting %s returned %s: %s", name, resp.Status, msg)
+	}
+	return nil
+}
+
+func (d objectStoreDestination) Get(ctx context.Context, name string) ([]byte, error) {
+	u := d.base
+	u.Path = path.Join(u.Path, name)
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	d.sign(req, nil)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %s", name)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %s", name)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("getting %s returned %s: %s", name, resp.Status, body)
+	}
+	return body, nil
+}
+
+// sign adds the AWS Signature Version 4 of the request to the s3 service to
+// its headers.
+func (d objectStoreDestination) sign(req *http.Request, body []byte) {
+	credentials := utils.AWSCredentials{AccessKeyID: d.accessKey, SecretAccessKey: d.secret}
+	utils.SignAWSV4(req, body, "s3", d.region, credentials, time.Now())
+}
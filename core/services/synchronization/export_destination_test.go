@@ -0,0 +1,92 @@
+package synchronization_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/services/synchronization"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDestination_ObjectStore(t *testing.T) {
+	t.Parallel()
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	exportURL, err := url.Parse("s3://telemetry/node-1")
+	require.NoError(t, err)
+	destination, err := synchronization.NewExportDestination(exportURL, synchronization.ExportDestinationOptions{
+		AccessKey: "AKID",
+		Secret:    "secret",
+		Region:    "eu-west-1",
+		Endpoint:  endpoint,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, destination.Put(context.Background(), "events.jsonl.gz", []byte("events")))
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPut, received.Method)
+	assert.Equal(t, "/telemetry/node-1/events.jsonl.gz", received.URL.Path)
+	assert.Equal(t, "events", string(body))
+	auth := received.Header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/"), auth)
+	assert.Contains(t, auth, "/eu-west-1/s3/aws4_request")
+	assert.NotEmpty(t, received.Header.Get("X-Amz-Date"))
+}
+
+func TestExportDestination_Local(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exportURL, err := url.Parse("file://" + dir)
+	require.NoError(t, err)
+	destination, err := synchronization.NewExportDestination(exportURL, synchronization.ExportDestinationOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, destination.Put(context.Background(), "events.jsonl.gz", []byte("events")))
+	body, err := destination.Get(context.Background(), "events.jsonl.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "events", string(body))
+	_, err = destination.Get(context.Background(), "missing.jsonl.gz")
+	assert.Error(t, err)
+}
+
+func TestExportDestination_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		url  string
+		opts synchronization.ExportDestinationOptions
+	}{
+		{"unsupported scheme", "ftp://bucket/prefix", synchronization.ExportDestinationOptions{}},
+		{"no credentials", "gs://bucket/prefix", synchronization.ExportDestinationOptions{}},
+		{"no bucket", "s3:///prefix", synchronization.ExportDestinationOptions{AccessKey: "a", Secret: "s"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u, err := url.Parse(test.url)
+			require.NoError(t, err)
+			_, err = synchronization.NewExportDestination(u, test.opts)
+			assert.Error(t, err)
+		})
+	}
+}
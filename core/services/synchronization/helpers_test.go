@@ -0,0 +1,5 @@
+package synchronization
+
+func ExportedUnreceivedResponses(w WebSocketClient) int {
+	return len(w.(*websocketClient).receive)
+}
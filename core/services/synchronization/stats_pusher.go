@@ -24,13 +24,27 @@ var (
 		Name: "stats_pusher_events_sent",
 		Help: "The number of events pushed up to explorer",
 	})
+	numberEventsPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "stats_pusher_events_pending",
+		Help: "The number of events waiting in the outbox to be acknowledged by explorer",
+	})
 )
 
+// errExplorerDisconnected is returned when pushing events while the
+// websocket to the explorer is not connected.
+var errExplorerDisconnected = errors.New("not connected to explorer")
+
 //go:generate mockery -name StatsPusher -output ../../internal/mocks/ -case=underscore
 
 // StatsPusher polls for events and pushes them via a WebSocketClient. Events
 // are consumed by the Explorer. Currently there is only one event type: an
 // encoding of a JobRun.
+//
+// Events are kept in the database, which acts as an outbox, until the
+// explorer acknowledges them. They are pushed one at a time, in order, from
+// the last event acknowledged, so that only one batch of events is held in
+// memory however long the explorer is away, and pushing resumes where it
+// left off once the explorer is back.
 type StatsPusher interface {
 	Start() error
 	Close() error
@@ -47,11 +61,16 @@ type statsPusher struct {
 	clock          utils.Afterer
 	backoffSleeper backoff.Backoff
 	waker          chan struct{}
+	enabled        bool
 }
 
 const (
 	createCallbackName = "sync:run_after_create"
 	updateCallbackName = "sync:run_after_update"
+
+	// explorerConsumer is the name of the cursor of the sync events pushed
+	// to the explorer.
+	explorerConsumer = "explorer"
 )
 
 // NewStatsPusher returns a new event queuer
@@ -77,13 +96,11 @@ func NewStatsPusher(orm *orm.ORM, url *url.URL, accessKey, secret string, afters
 
 	if url != nil {
 		sp.WSClient = NewWebSocketClient(url, accessKey, secret)
-		gormCallbacksMutex.Lock()
-		_ = orm.RawDB(func(db *gorm.DB) error {
-			db.Callback().Create().Register(createCallbackName, createSyncEventWithStatsPusher(sp, orm))
-			db.Callback().Update().Register(updateCallbackName, createSyncEventWithStatsPusher(sp, orm))
-			return nil
-		})
-		gormCallbacksMutex.Unlock()
+		sp.enabled = true
+		logger.ErrorIf(orm.RegisterSyncEventConsumer(explorerConsumer), "failed to register the explorer as a sync event consumer")
+		registerSyncEventCallbacks(orm)
+	} else {
+		logger.ErrorIf(orm.RemoveSyncEventConsumer(explorerConsumer), "failed to remove the explorer as a sync event consumer")
 	}
 	return sp
 }
@@ -113,13 +130,9 @@ func (sp *statsPusher) Close() error {
 	if sp.cancel != nil {
 		sp.cancel()
 	}
-	gormCallbacksMutex.Lock()
-	_ = sp.ORM.RawDB(func(db *gorm.DB) error {
-		db.Callback().Create().Remove(createCallbackName)
-		db.Callback().Update().Remove(updateCallbackName)
-		return nil
-	})
-	gormCallbacksMutex.Unlock()
+	if sp.enabled {
+		unregisterSyncEventCallbacks(sp.ORM)
+	}
 	return sp.WSClient.Close()
 }
 
@@ -156,6 +169,11 @@ func (sp *statsPusher) eventLoop(parentCtx context.Context) {
 }
 
 func (sp *statsPusher) pusherLoop(parentCtx context.Context) error {
+	// Replay the events left in the outbox straight away, rather than
+	// waiting for the next period, in case this follows a failure.
+	if err := sp.pushEvents(); err != nil {
+		return err
+	}
 	for {
 		select {
 		case <-sp.waker:
@@ -175,19 +193,55 @@ func (sp *statsPusher) pusherLoop(parentCtx context.Context) error {
 }
 
 func (sp *statsPusher) pushEvents() error {
-	err := sp.ORM.AllSyncEvents(func(event *models.SyncEvent) error {
-		return sp.syncEvent(event)
-	})
+	if !sp.enabled {
+		return nil
+	}
 
+	cursor, err := sp.ORM.SyncEventCursor(explorerConsumer)
 	if err != nil {
-		return errors.Wrap(err, "pushEvents#AllSyncEvents failed")
+		return errors.Wrap(err, "pushEvents#SyncEventCursor failed")
+	}
+	defer sp.updatePending()
+	for {
+		events, err := sp.ORM.SyncEventsAfter(cursor, orm.BatchSize)
+		if err != nil {
+			return errors.Wrap(err, "pushEvents#SyncEventsAfter failed")
+		}
+		if len(events) == 0 {
+			break
+		}
+		for i := range events {
+			if sp.WSClient.Status() != ConnectionStatusConnected {
+				return errExplorerDisconnected
+			}
+			if err := sp.syncEvent(&events[i]); err != nil {
+				logger.Warnw("Explorer did not acknowledge event", "sync_event_id", events[i].ID, "error", err)
+				return err
+			}
+			cursor.LastTxID, cursor.LastID = events[i].TxID, events[i].ID
+		}
 	}
 
 	sp.backoffSleeper.Reset()
 	return nil
 }
 
+func (sp *statsPusher) updatePending() {
+	cursor, err := sp.ORM.SyncEventCursor(explorerConsumer)
+	if err != nil {
+		logger.Warnw("Failed to count events pending for explorer", "error", err)
+		return
+	}
+	count, err := sp.ORM.CountSyncEventsAfter(cursor)
+	if err != nil {
+		logger.Warnw("Failed to count events pending for explorer", "error", err)
+		return
+	}
+	numberEventsPending.Set(float64(count))
+}
+
 func (sp *statsPusher) syncEvent(event *models.SyncEvent) error {
+	logger.Debugw("Pushing event to explorer", "sync_event_id", event.ID)
 	sp.WSClient.Send([]byte(event.Body))
 	numberEventsSent.Inc()
 
@@ -206,17 +260,16 @@ func (sp *statsPusher) syncEvent(event *models.SyncEvent) error {
 		return errors.New("event not created")
 	}
 
-	err = sp.ORM.RawDB(func(db *gorm.DB) error {
-		return db.Delete(event).Error
-	})
+	err = sp.ORM.AdvanceSyncEventCursor(explorerConsumer, *event)
 	if err != nil {
-		return errors.Wrap(err, "syncEvent#DB.Delete failed")
+		return errors.Wrap(err, "syncEvent#AdvanceSyncEventCursor failed")
 	}
 
+	logger.Debugw("Explorer acknowledged event", "sync_event_id", event.ID)
 	return nil
 }
 
-func createSyncEventWithStatsPusher(sp StatsPusher, orm *orm.ORM) func(*gorm.Scope) {
+func createSyncEvent(orm *orm.ORM) func(*gorm.Scope) {
 	return func(scope *gorm.Scope) {
 		if scope.HasError() {
 			return
@@ -232,8 +285,6 @@ func createSyncEventWithStatsPusher(sp StatsPusher, orm *orm.ORM) func(*gorm.Sco
 			return
 		}
 
-		orm.MustEnsureAdvisoryLock()
-
 		presenter := SyncJobRunPresenter{run}
 		bodyBytes, err := json.Marshal(presenter)
 		if err != nil {
@@ -254,8 +305,43 @@ func createSyncEventWithStatsPusher(sp StatsPusher, orm *orm.ORM) func(*gorm.Sco
 
 var (
 	gormCallbacksMutex *sync.Mutex
+	// gormCallbacksUsers counts the users of the sync event callbacks of
+	// each ORM, which are removed once none of them needs them anymore.
+	gormCallbacksUsers map[*orm.ORM]int
 )
 
 func init() {
 	gormCallbacksMutex = new(sync.Mutex)
+	gormCallbacksUsers = make(map[*orm.ORM]int)
+}
+
+// registerSyncEventCallbacks makes the ORM create a sync event for every job
+// run created or updated, until every caller has unregistered.
+func registerSyncEventCallbacks(orm *orm.ORM) {
+	gormCallbacksMutex.Lock()
+	defer gormCallbacksMutex.Unlock()
+	gormCallbacksUsers[orm]++
+	if gormCallbacksUsers[orm] > 1 {
+		return
+	}
+	_ = orm.RawDB(func(db *gorm.DB) error {
+		db.Callback().Create().Register(createCallbackName, createSyncEvent(orm))
+		db.Callback().Update().Register(updateCallbackName, createSyncEvent(orm))
+		return nil
+	})
+}
+
+func unregisterSyncEventCallbacks(orm *orm.ORM) {
+	gormCallbacksMutex.Lock()
+	defer gormCallbacksMutex.Unlock()
+	gormCallbacksUsers[orm]--
+	if gormCallbacksUsers[orm] > 0 {
+		return
+	}
+	delete(gormCallbacksUsers, orm)
+	_ = orm.RawDB(func(db *gorm.DB) error {
+		db.Callback().Create().Remove(createCallbackName)
+		db.Callback().Update().Remove(updateCallbackName)
+		return nil
+	})
 }
@@ -2,6 +2,7 @@ package synchronization_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/services/synchronization"
@@ -107,6 +108,33 @@ func TestStatsPusher_BadSyncLeavesEvent(t *testing.T) {
 	cltest.AssertSyncEventCountStays(t, store.ORM, 1)
 }
 
+func TestStatsPusher_ReplaysUnacknowledgedEvent(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	wsserver, wscleanup := cltest.NewEventWebSocketServer(t)
+	defer wscleanup()
+
+	clock := cltest.NewTriggerClock(t)
+	pusher := synchronization.NewStatsPusher(store.ORM, wsserver.URL, "", "", clock)
+	pusher.Start()
+	defer pusher.Close()
+
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error { return db.Create(&models.SyncEvent{}).Error }))
+	pusher.PushNow()
+
+	cltest.CallbackOrTimeout(t, "ws server receives event", func() {
+		<-wsserver.Received
+		assert.NoError(t, wsserver.Broadcast(`{"status": 500}`))
+	})
+	// After backing off, the event is pushed again without another trigger
+	cltest.CallbackOrTimeout(t, "ws server receives event again", func() {
+		<-wsserver.Received
+		assert.NoError(t, wsserver.Broadcast(`{"status": 201}`))
+	}, 5*time.Second)
+	cltest.WaitForSyncEventCount(t, store.ORM, 0)
+}
+
 func lenSyncEvents(t *testing.T, orm *orm.ORM) int {
 	count, err := orm.CountOf(&models.SyncEvent{})
 	require.NoError(t, err)
@@ -0,0 +1,175 @@
+package synchronization
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var numberEventsExported = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "sync_event_exporter_events_exported",
+	Help: "The number of sync events exported to files",
+})
+
+// exportConsumer is the name of the cursor of the sync events exported.
+const exportConsumer = "export"
+
+// SyncEventExporter exports the sync events to gzipped JSON lines files in an
+// S3 or GCS bucket, or a local directory, every SYNC_EVENT_EXPORT_INTERVAL.
+// It keeps the ID of the last event exported, so that events are exported
+// once and are not deleted before being exported. Each file is named after
+// the IDs of its first and last events, so a file exported again after a
+// crash replaces the first one rather than duplicating its events.
+type SyncEventExporter struct {
+	orm         *orm.ORM
+	config      orm.ConfigReader
+	destination ExportDestination
+	clock       utils.Afterer
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewSyncEventExporter returns a new SyncEventExporter.
+func NewSyncEventExporter(orm *orm.ORM, config orm.ConfigReader, afters ...utils.Afterer) *SyncEventExporter {
+	var clock utils.Afterer
+	if len(afters) == 0 {
+		clock = utils.Clock{}
+	} else {
+		clock = afters[0]
+	}
+	return &SyncEventExporter{
+		orm:    orm,
+		config: config,
+		clock:  clock,
+	}
+}
+
+// Start exports the sync events every SYNC_EVENT_EXPORT_INTERVAL until
+// stopped, or forgets the events left to export if SYNC_EVENT_EXPORT_URL is
+// not set.
+func (e *SyncEventExporter) Start() error {
+	exportURL := e.config.SyncEventExportURL()
+	if exportURL == nil {
+		return e.orm.RemoveSyncEventConsumer(exportConsumer)
+	}
+
+	destination, err := NewExportDestination(exportURL, ExportDestinationOptions{
+		AccessKey: e.config.SyncEventExportAccessKey(),
+		Secret:    e.config.SyncEventExportSecret(),
+		Region:    e.config.SyncEventExportRegion(),
+		Endpoint:  e.config.SyncEventExportEndpoint(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "invalid SYNC_EVENT_EXPORT_URL")
+	}
+	e.destination = destination
+	if err := e.orm.RegisterSyncEventConsumer(exportConsumer); err != nil {
+		return err
+	}
+	registerSyncEventCallbacks(e.orm)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-e.clock.After(e.config.SyncEventExportInterval().Duration()):
+				logger.ErrorIf(e.Export(ctx), "failed to export sync events")
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops exporting, waiting for any export in progress.
+func (e *SyncEventExporter) Stop() {
+	if e.cancel == nil {
+		return
+	}
+	e.cancel()
+	e.wg.Wait()
+	unregisterSyncEventCallbacks(e.orm)
+}
+
+// Export exports the sync events not exported yet, in files of up to
+// SYNC_EVENT_EXPORT_BATCH_SIZE events.
+func (e *SyncEventExporter) Export(ctx context.Context) error {
+	cursor, err := e.orm.SyncEventCursor(exportConsumer)
+	if err != nil {
+		return err
+	}
+	for {
+		events, err := e.orm.SyncEventsAfter(cursor, e.config.SyncEventExportBatchSize())
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		body, err := encodeSyncEvents(events)
+		if err != nil {
+			return err
+		}
+		first, last := events[0].ID, events[len(events)-1].ID
+		name := fmt.Sprintf("sync_events-%020d-%020d.jsonl.gz", first, last)
+		if err := e.destination.Put(ctx, name, body); err != nil {
+			return errors.Wrapf(err, "exporting sync events %d to %d", first, last)
+		}
+		if err := e.orm.AdvanceSyncEventCursor(exportConsumer, events[len(events)-1]); err != nil {
+			return err
+		}
+		numberEventsExported.Add(float64(len(events)))
+		logger.Debugw("Exported sync events", "first_id", first, "last_id", last, "file", name)
+		cursor.LastTxID, cursor.LastID = events[len(events)-1].TxID, last
+	}
+}
+
+// exportedSyncEvent is a line of an export file. Bodies which are JSON are
+// embedded as is, and any others as a string.
+type exportedSyncEvent struct {
+	ID        uint            `json:"id"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Body      json.RawMessage `json:"body"`
+}
+
+func encodeSyncEvents(events []models.SyncEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for _, event := range events {
+		body := json.RawMessage(event.Body)
+		if !json.Valid(body) {
+			quoted, err := json.Marshal(event.Body)
+			if err != nil {
+				return nil, err
+			}
+			body = quoted
+		}
+		line := exportedSyncEvent{ID: event.ID, CreatedAt: event.CreatedAt, Body: body}
+		if err := encoder.Encode(line); err != nil {
+			return nil, errors.Wrap(err, "encoding sync event")
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,108 @@
+package synchronization_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/services/synchronization"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncEventExporter_Export(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sync_events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config, cfgCleanup := cltest.NewConfig(t)
+	defer cfgCleanup()
+	config.Set("SYNC_EVENT_EXPORT_URL", "file://"+dir)
+	config.Set("SYNC_EVENT_EXPORT_BATCH_SIZE", 2)
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+
+	exporter := synchronization.NewSyncEventExporter(store.ORM, store.Config, cltest.NewTriggerClock(t))
+	require.NoError(t, exporter.Start())
+	defer exporter.Stop()
+
+	bodies := []string{`{"jobRunId":"1"}`, `{"jobRunId":"2"}`, `not json`}
+	for _, body := range bodies {
+		require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+			return db.Create(&models.SyncEvent{Body: body}).Error
+		}))
+	}
+
+	require.NoError(t, exporter.Export(context.Background()))
+	files, err := filepath.Glob(filepath.Join(dir, "sync_events-*.jsonl.gz"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	cltest.WaitForSyncEventCount(t, store.ORM, 0)
+
+	var exported []map[string]interface{}
+	for _, file := range files {
+		exported = append(exported, readExportFile(t, file)...)
+	}
+	require.Len(t, exported, 3)
+	assert.Equal(t, map[string]interface{}{"jobRunId": "1"}, exported[0]["body"])
+	assert.Equal(t, map[string]interface{}{"jobRunId": "2"}, exported[1]["body"])
+	assert.Equal(t, "not json", exported[2]["body"])
+
+	require.NoError(t, exporter.Export(context.Background()))
+	files, err = filepath.Glob(filepath.Join(dir, "sync_events-*.jsonl.gz"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestSyncEventExporter_WaitsForExplorer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sync_events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config, cfgCleanup := cltest.NewConfig(t)
+	defer cfgCleanup()
+	config.Set("SYNC_EVENT_EXPORT_URL", "file://"+dir)
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+
+	wsserver, wscleanup := cltest.NewEventWebSocketServer(t)
+	defer wscleanup()
+	pusher := synchronization.NewStatsPusher(store.ORM, wsserver.URL, "", "", cltest.NewTriggerClock(t))
+	defer pusher.Close()
+
+	exporter := synchronization.NewSyncEventExporter(store.ORM, store.Config, cltest.NewTriggerClock(t))
+	require.NoError(t, exporter.Start())
+	defer exporter.Stop()
+
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error { return db.Create(&models.SyncEvent{}).Error }))
+	require.NoError(t, exporter.Export(context.Background()))
+	cltest.AssertSyncEventCountStays(t, store.ORM, 1)
+}
+
+func readExportFile(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
@@ -72,7 +72,7 @@ func NewWebSocketClient(url *url.URL, accessKey, secret string) WebSocketClient
 	return &websocketClient{
 		url:       url,
 		send:      make(chan []byte),
-		receive:   make(chan []byte),
+		receive:   make(chan []byte, 1),
 		boot:      &sync.Mutex{},
 		sleeper:   utils.NewBackoffSleeper(),
 		status:    ConnectionStatusDisconnected,
@@ -110,11 +110,21 @@ func (w *websocketClient) Start() error {
 	return nil
 }
 
-// Send sends data asynchronously across the websocket if it's open, or
-// holds it in a small buffer until connection, throwing away messages
-// once buffer is full.
+// Send sends data across the websocket if it's open, waiting for up to
+// writeWait for a connection otherwise, after which the data is thrown away.
+// Responses to earlier messages which were not received are discarded first,
+// so that they cannot be taken for the response to this message.
 func (w *websocketClient) Send(data []byte) {
-	w.send <- data
+	select {
+	case <-w.receive:
+	default:
+	}
+
+	select {
+	case w.send <- data:
+	case <-time.After(writeWait):
+		logger.Warnw("Dropped message to explorer, which is not connected", "url", w.url.String())
+	}
 }
 
 // Receive blocks the caller while waiting for a response from the server,
@@ -279,7 +289,23 @@ func (w *websocketClient) readPump(cancel context.CancelFunc) {
 
 		switch messageType {
 		case websocket.TextMessage:
-			w.receive <- message
+			w.deliver(message)
+		}
+	}
+}
+
+// deliver hands the message to Receive, replacing any message which was not
+// received, so that the read pump never blocks on a response nobody waits for.
+func (w *websocketClient) deliver(message []byte) {
+	for {
+		select {
+		case w.receive <- message:
+			return
+		default:
+		}
+		select {
+		case <-w.receive:
+		default:
 		}
 	}
 }
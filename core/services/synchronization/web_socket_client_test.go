@@ -10,6 +10,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/services/synchronization"
 
+	"github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -131,6 +132,31 @@ func TestWebSocketClient_SendWithAckTimeout(t *testing.T) {
 	}, 300*time.Millisecond)
 }
 
+func TestWebSocketClient_SendDiscardsStaleAck(t *testing.T) {
+	wsserver, cleanup := cltest.NewEventWebSocketServer(t)
+	defer cleanup()
+
+	wsclient := synchronization.NewWebSocketClient(wsserver.URL, "", "")
+	require.NoError(t, wsclient.Start())
+	defer wsclient.Close()
+
+	wsclient.Send([]byte(`{"first": true}`))
+	cltest.CallbackOrTimeout(t, "receive first", func() {
+		<-wsserver.Received
+		assert.NoError(t, wsserver.Broadcast(`{"status": 201}`))
+	})
+	gomega.NewGomegaWithT(t).Eventually(func() int {
+		return synchronization.ExportedUnreceivedResponses(wsclient)
+	}).Should(gomega.Equal(1), "the response to the first message should arrive unreceived")
+
+	wsclient.Send([]byte(`{"second": true}`))
+	cltest.CallbackOrTimeout(t, "receive second", func() {
+		<-wsserver.Received
+	})
+	_, err := wsclient.Receive(100 * time.Millisecond)
+	assert.Equal(t, synchronization.ErrReceiveTimeout, err)
+}
+
 func TestWebSocketClient_Status_ConnectAndServerDisconnect(t *testing.T) {
 	wsserver, cleanup := cltest.NewEventWebSocketServer(t)
 	defer cleanup()
@@ -4,42 +4,266 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/assets"
+	clnull "github.com/smartcontractkit/chainlink/core/null"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"github.com/tidwall/gjson"
 )
 
 // ValidateJob checks the job and its associated Initiators and Tasks for any
 // application logic errors.
 func ValidateJob(j models.JobSpec, store *store.Store) error {
+	return ValidateJobWithLines(j, store, models.JobSpecLines{})
+}
+
+// ValidateJobWithLines validates the job like ValidateJob, prefixing the
+// errors found in its initiators and tasks with the lines of the TOML spec
+// they start on.
+func ValidateJobWithLines(j models.JobSpec, store *store.Store, lines models.JobSpecLines) error {
 	fe := models.NewJSONAPIErrors()
+	validateJobShape(j, fe)
+	for n, i := range j.Initiators {
+		if err := ValidateInitiator(i, j, store); err != nil {
+			mergeAtLine(fe, err, lines.Initiator(n))
+		}
+	}
+	for n, task := range j.Tasks {
+		if err := validateTask(task, j.Namespace, store); err != nil {
+			mergeAtLine(fe, err, lines.Task(n))
+		}
+	}
+	validateTaskGraph(j, fe)
+	validateJobNamespace(j, store, fe)
+	validateJobFinality(j, store.Config, fe)
+	return fe.CoerceEmptyToNil()
+}
+
+// ValidateJobOffline validates the job like ValidateJobWithLines without a
+// database, so that specs can be checked before they are sent to a node.
+// The checks needing the node's state are left out, such as those of the
+// kafka and stream initiators, and the bridges and the adapter plugins
+// called by the tasks are returned, to be checked to exist on the node. Only
+// the plugins registered in this process are told apart from bridges.
+func ValidateJobOffline(j models.JobSpec, config orm.ConfigReader, lines models.JobSpecLines) (bridges, plugins []models.TaskType, err error) {
+	fe := models.NewJSONAPIErrors()
+	validateJobShape(j, fe)
+	for n, i := range j.Initiators {
+		if err := validateInitiatorOffline(i, j, config); err != nil {
+			mergeAtLine(fe, err, lines.Initiator(n))
+		}
+	}
+	for n, task := range j.Tasks {
+		ba, builtin, err := adapters.ForBuiltin(task)
+		if !builtin && adapters.IsPlugin(task.Type) {
+			plugins = append(plugins, task.Type)
+			continue
+		} else if !builtin {
+			bridges = append(bridges, task.Type)
+			continue
+		} else if err == nil {
+			err = validateAdapter(ba, config, nil)
+		}
+		if err != nil {
+			mergeAtLine(fe, err, lines.Task(n))
+		}
+		if aggregate, ok := ba.(*adapters.Aggregate); ok {
+			for _, src := range aggregate.Sources {
+				if src.Bridge != "" {
+					bridges = append(bridges, src.Bridge)
+				}
+			}
+		}
+	}
+	validateTaskGraph(j, fe)
+	if err := models.ValidateNamespace(j.Namespace); err != nil {
+		fe.Merge(err)
+	}
+	validateJobFinality(j, config, fe)
+	return bridges, plugins, fe.CoerceEmptyToNil()
+}
+
+// validateJobShape checks the job's start and end, and that it has
+// initiators and tasks.
+func validateJobShape(j models.JobSpec, fe *models.JSONAPIErrors) {
 	if j.StartAt.Valid && j.EndAt.Valid && j.StartAt.Time.After(j.EndAt.Time) {
 		fe.Add("StartAt cannot be before EndAt")
 	}
-	if len(j.Initiators) < 1 || len(j.Tasks) < 1 {
+	// Keeper initiators send the transactions performing their upkeeps
+	// themselves, so jobs of keeper initiators alone need no task.
+	keeperOnly := len(j.Initiators) > 0 && len(j.InitiatorsFor(models.InitiatorKeeper)) == len(j.Initiators)
+	if len(j.Initiators) < 1 || (len(j.Tasks) < 1 && !keeperOnly) {
 		fe.Add("Must have at least one Initiator and one Task")
 	}
-	for _, i := range j.Initiators {
-		if err := ValidateInitiator(i, j, store); err != nil {
-			fe.Merge(err)
+	validateJobConfirmations(j, fe)
+}
+
+// validateJobFinality checks that the job and its tasks wait for no more
+// confirmations than FINALITY_DEPTH, past which blocks are final.
+func validateJobFinality(j models.JobSpec, config orm.ConfigReader, fe *models.JSONAPIErrors) {
+	depth := config.FinalityDepth()
+	if depth == 0 {
+		return
+	}
+	check := func(name string, confirmations clnull.Uint32) {
+		if confirmations.Valid && uint64(confirmations.Uint32) > depth {
+			fe.Add(fmt.Sprintf("%s of %d is more than FINALITY_DEPTH of %d", name, confirmations.Uint32, depth))
+		}
+	}
+	check("incomingConfirmations", j.IncomingConfirmations)
+	check("outgoingConfirmations", j.OutgoingConfirmations)
+	for _, task := range j.Tasks {
+		check(fmt.Sprintf("confirmations of task %s", task.Type), task.Confirmations)
+		check(fmt.Sprintf("outgoingConfirmations of task %s", task.Type), task.OutgoingConfirmations)
+	}
+}
+
+// validateJobConfirmations checks that the incoming confirmations are only
+// set on jobs run by logs, and the outgoing confirmations on jobs and tasks
+// sending transactions, which are the only ones waiting for them. Outgoing
+// confirmations can't be passed in task params instead.
+func validateJobConfirmations(j models.JobSpec, fe *models.JSONAPIErrors) {
+	if j.IncomingConfirmations.Valid && !j.IsLogInitiated() {
+		fe.Add("incomingConfirmations can only be set on jobs with a log initiator")
+	}
+	sending := false
+	for _, task := range j.Tasks {
+		if task.Params.Get("minOutgoingConfirmations").Exists() {
+			fe.Add(fmt.Sprintf("minOutgoingConfirmations can't be set in the params of task %s, set its outgoingConfirmations instead", task.Type))
 		}
+		if !sendsTransaction(task) {
+			if task.OutgoingConfirmations.Valid {
+				fe.Add(fmt.Sprintf("outgoingConfirmations can only be set on tasks sending transactions, not %s", task.Type))
+			}
+			continue
+		}
+		sending = true
+	}
+	if j.OutgoingConfirmations.Valid && !sending {
+		fe.Add("outgoingConfirmations can only be set on jobs with a task sending transactions")
+	}
+}
+
+// validateJobNamespace checks the job's namespace, and that the bridges its
+// tasks call are in it or in the default namespace, which is shared.
+func validateJobNamespace(j models.JobSpec, store *store.Store, fe *models.JSONAPIErrors) {
+	if err := models.ValidateNamespace(j.Namespace); err != nil {
+		fe.Merge(err)
+		return
 	}
 	for _, task := range j.Tasks {
-		if err := validateTask(task, store); err != nil {
-			fe.Merge(err)
+		bt, err := store.FindBridge(task.Type)
+		if err != nil {
+			continue
+		}
+		if bt.Namespace != "" && bt.Namespace != j.Namespace {
+			fe.Add(fmt.Sprintf("Bridge %s is not in namespace %q", bt.Name, j.Namespace))
 		}
 	}
-	return fe.CoerceEmptyToNil()
+}
+
+// mergeAtLine merges err into fe, prefixing its details with line unless it
+// is zero.
+func mergeAtLine(fe *models.JSONAPIErrors, err error, line int) {
+	if line == 0 {
+		fe.Merge(err)
+		return
+	}
+	errs := models.NewJSONAPIErrors()
+	errs.Merge(err)
+	for _, e := range errs.Errors {
+		fe.Add(fmt.Sprintf("line %d: %s", line, e.Detail))
+	}
+}
+
+// validateTaskGraph checks that the inputs of tasks name other tasks, that
+// they do not depend on themselves, and that all tasks lead to a single final
+// task, whose result is that of the run.
+func validateTaskGraph(j models.JobSpec, fe *models.JSONAPIErrors) {
+	tasks := map[string]models.TaskSpec{}
+	for _, task := range j.Tasks {
+		if task.Name == "" {
+			continue
+		} else if _, exists := tasks[task.Name]; exists {
+			fe.Add(fmt.Sprintf("Task name %s is used more than once", task.Name))
+		}
+		tasks[task.Name] = task
+	}
+	if !j.IsTaskGraph() {
+		return
+	}
+
+	outputs := map[string]int{}
+	for i, task := range j.Tasks {
+		if task.Name == "" {
+			fe.Add(fmt.Sprintf("Task %d must have a name, as tasks declare their inputs", i))
+			return
+		}
+		seen := map[string]bool{}
+		for _, input := range task.Inputs {
+			if _, exists := tasks[input]; !exists {
+				fe.Add(fmt.Sprintf("Task %s has unknown input %s", task.Name, input))
+				return
+			} else if seen[input] {
+				fe.Add(fmt.Sprintf("Task %s has input %s more than once", task.Name, input))
+				return
+			}
+			seen[input] = true
+			outputs[input]++
+		}
+	}
+
+	var final []string
+	for _, task := range j.Tasks {
+		if outputs[task.Name] == 0 {
+			final = append(final, task.Name)
+		}
+	}
+	if len(final) > 1 {
+		fe.Add(fmt.Sprintf("Tasks must lead to a single final task, not %s", strings.Join(final, ", ")))
+	}
+
+	// Take tasks out of the graph once their inputs are, until none are left,
+	// or those left depend on each other.
+	remaining := map[string]bool{}
+	for name := range tasks {
+		remaining[name] = true
+	}
+	for progress := true; progress; {
+		progress = false
+		for name := range remaining {
+			ready := true
+			for _, input := range tasks[name].Inputs {
+				ready = ready && !remaining[input]
+			}
+			if ready {
+				delete(remaining, name)
+				progress = true
+			}
+		}
+	}
+	if len(remaining) > 0 {
+		var cycle []string
+		for name := range remaining {
+			cycle = append(cycle, name)
+		}
+		sort.Strings(cycle)
+		fe.Add(fmt.Sprintf("Tasks depend on each other: %s", strings.Join(cycle, ", ")))
+	}
 }
 
 // ValidateBridgeTypeNotExist checks that a bridge has not already been created
@@ -48,6 +272,15 @@ func ValidateBridgeTypeNotExist(bt *models.BridgeTypeRequest, store *store.Store
 	ts := models.TaskSpec{Type: bt.Name}
 	if a, _ := adapters.For(ts, store.Config, store.ORM); a != nil {
 		fe.Add(fmt.Sprintf("Bridge Type %v already exists", bt.Name))
+	} else if existing, err := store.FindBridge(bt.Name); err == nil {
+		// Stream bridges cannot be used as task adapters. Bridge names are
+		// unique across namespaces, but those of other namespaces are not
+		// revealed.
+		if existing.Namespace == bt.Namespace {
+			fe.Add(fmt.Sprintf("Bridge Type %v already exists", bt.Name))
+		} else {
+			fe.Add(fmt.Sprintf("Bridge Type name %v is not available", bt.Name))
+		}
 	}
 	return fe.CoerceEmptyToNil()
 }
@@ -62,10 +295,37 @@ func ValidateBridgeType(bt *models.BridgeTypeRequest, store *store.Store) error
 	if _, err := models.NewTaskType(bt.Name.String()); err != nil {
 		fe.Merge(err)
 	}
+	for _, plugin := range adapters.Plugins() {
+		if plugin.TaskType == bt.Name {
+			fe.Add(fmt.Sprintf("Task type %s is registered by an adapter plugin", bt.Name))
+		}
+	}
 	u := bt.URL.String()
 	if len(strings.TrimSpace(u)) == 0 {
 		fe.Add("URL must be present")
 	}
+	switch bt.Mode.OrDefault() {
+	case models.BridgeModeHTTP:
+	case models.BridgeModeStream:
+		if bt.URL.Scheme != "ws" && bt.URL.Scheme != "wss" {
+			fe.Add("URL scheme of a stream bridge must be ws or wss")
+		}
+	default:
+		fe.Add(fmt.Sprintf("Mode %v must be http or stream", bt.Mode))
+	}
+	if bt.Auth != nil {
+		validateBridgeAuth(*bt.Auth, fe)
+	}
+	if err := models.ValidateNamespace(bt.Namespace); err != nil {
+		fe.Merge(err)
+	}
+	if bt.ClientCertificate != "" {
+		if _, err := store.FindClientCertificate(bt.ClientCertificate); errors.Cause(err) == orm.ErrorNotFound {
+			fe.Add(fmt.Sprintf("Client certificate %s does not exist", bt.ClientCertificate))
+		} else if err != nil {
+			return errors.Wrap(err, "finding client certificate")
+		}
+	}
 	if bt.MinimumContractPayment != nil &&
 		bt.MinimumContractPayment.Cmp(assets.NewLink(0)) < 0 {
 		fe.Add("MinimumContractPayment must be positive")
@@ -73,6 +333,51 @@ func ValidateBridgeType(bt *models.BridgeTypeRequest, store *store.Store) error
 	return fe.CoerceEmptyToNil()
 }
 
+func validateBridgeAuth(auth models.BridgeAuth, fe *models.JSONAPIErrors) {
+	switch auth.Type {
+	case models.BridgeAuthHeaders:
+		if len(auth.Headers) == 0 {
+			fe.Add("Auth headers must be present")
+		}
+		for name := range auth.Headers {
+			if !govalidator.StringMatches(name, "^[a-zA-Z0-9-_]+$") {
+				fe.Add(fmt.Sprintf("Auth header name %q is invalid", name))
+			}
+		}
+	case models.BridgeAuthBasic:
+		if auth.Username == "" {
+			fe.Add("Auth username must be present")
+		}
+	case models.BridgeAuthOAuth2:
+		if u, err := url.ParseRequestURI(auth.TokenURL); err != nil || u.Host == "" {
+			fe.Add("Auth tokenUrl must be a valid URL")
+		}
+		if auth.ClientID == "" || auth.ClientSecret == "" {
+			fe.Add("Auth clientId and clientSecret must be present")
+		}
+	default:
+		fe.Add(fmt.Sprintf("Auth type %v must be headers, basic or oauth2", auth.Type))
+	}
+}
+
+// ValidateClientCertificate checks that a client certificate is named, and
+// has both its certificate and private key.
+func ValidateClientCertificate(ccr *models.ClientCertificateRequest) error {
+	fe := models.NewJSONAPIErrors()
+	if len(ccr.Name) == 0 {
+		fe.Add("No name specified")
+	} else if !govalidator.StringMatches(ccr.Name, "^[a-zA-Z0-9-_]*$") {
+		fe.Add("Name must be alphanumeric and may contain '_' or '-'")
+	}
+	if strings.TrimSpace(ccr.Certificate) == "" {
+		fe.Add("Certificate must be present")
+	}
+	if strings.TrimSpace(ccr.PrivateKey) == "" {
+		fe.Add("Private key must be present")
+	}
+	return fe.CoerceEmptyToNil()
+}
+
 // ValidateExternalInitiator checks whether External Initiator parameters are
 // safe for processing.
 func ValidateExternalInitiator(
@@ -95,6 +400,34 @@ func ValidateExternalInitiator(
 // ValidateInitiator checks the Initiator for any application logic errors.
 func ValidateInitiator(i models.Initiator, j models.JobSpec, store *store.Store) error {
 	switch strings.ToLower(i.Type) {
+	case models.InitiatorRunLog:
+		return validateRunLogInitiator(i, j, store)
+	case models.InitiatorFluxMonitor:
+		return validateFluxMonitor(i, j, store.Config, store)
+	case models.InitiatorKafka:
+		return validateKafkaInitiator(i, store)
+	case models.InitiatorStream:
+		return validateStreamInitiator(i, store)
+	case models.InitiatorOffchainReporting:
+		return validateOffchainReportingInitiator(i, store)
+	case models.InitiatorKeeper:
+		return validateKeeperInitiator(i, store)
+	case models.InitiatorBlockCondition:
+		return validateBlockConditionInitiator(i, store)
+	default:
+		return validateInitiatorOffline(i, j, store.Config)
+	}
+}
+
+// validateInitiatorOffline checks the initiators without a store, leaving
+// out the checks of runlog and fluxmonitor ones needing it, and that the
+// others are of a known type.
+func validateInitiatorOffline(i models.Initiator, j models.JobSpec, config orm.ConfigReader) error {
+	switch strings.ToLower(i.Type) {
+	case models.InitiatorRunLog:
+		return validateRunLogInitiator(i, j, nil)
+	case models.InitiatorFluxMonitor:
+		return validateFluxMonitor(i, j, config, nil)
 	case models.InitiatorRunAt:
 		return validateRunAtInitiator(i, j)
 	case models.InitiatorCron:
@@ -103,25 +436,31 @@ func ValidateInitiator(i models.Initiator, j models.JobSpec, store *store.Store)
 		return validateExternalInitiator(i)
 	case models.InitiatorServiceAgreementExecutionLog:
 		return validateServiceAgreementInitiator(i, j)
-	case models.InitiatorRunLog:
-		return validateRunLogInitiator(i, j)
-	case models.InitiatorFluxMonitor:
-		return validateFluxMonitor(i, j, store)
 	case models.InitiatorWeb:
 		return nil
 	case models.InitiatorEthLog:
-		return nil
+		return validateEthLogInitiator(i)
 	case models.InitiatorRandomnessLog:
 		return validateRandomnessLogInitiator(i, j)
+	case models.InitiatorMQTT:
+		return validateMQTTInitiator(i)
+	case models.InitiatorKafka,
+		models.InitiatorStream,
+		models.InitiatorOffchainReporting,
+		models.InitiatorKeeper,
+		models.InitiatorBlockCondition:
+		return nil
 	default:
 		return models.NewJSONAPIErrorsWith(fmt.Sprintf("type %v does not exist", i.Type))
 	}
 }
 
-func validateFluxMonitor(i models.Initiator, j models.JobSpec, store *store.Store) error {
+// validateFluxMonitor checks a fluxmonitor initiator. The bridges of its
+// feeds are not looked up if store is nil.
+func validateFluxMonitor(i models.Initiator, j models.JobSpec, config orm.ConfigReader, store *store.Store) error {
 	fe := models.NewJSONAPIErrors()
 
-	if store.Config.EthereumDisabled() {
+	if config.EthereumDisabled() {
 		fe.Add("cannot add flux monitor jobs when ethereum is disabled")
 	}
 	if i.Address == utils.ZeroAddress {
@@ -143,7 +482,7 @@ func validateFluxMonitor(i models.Initiator, j models.JobSpec, store *store.Stor
 			fe.Add("pollTimer disabled, period must be 0")
 		}
 	} else {
-		minimumPollPeriod := models.Duration(store.Config.DefaultHTTPTimeout())
+		minimumPollPeriod := models.Duration(config.DefaultHTTPTimeout())
 
 		if i.PollTimer.Period.IsInstant() {
 			fe.Add("pollTimer enabled, but no period specified")
@@ -168,6 +507,31 @@ func validateFluxMonitor(i models.Initiator, j models.JobSpec, store *store.Stor
 		fe.Add(err.Error())
 	}
 
+	switch i.Aggregation.Method {
+	case "", models.FluxAggregationMedian, models.FluxAggregationTrimmedMean, models.FluxAggregationWeighted:
+	default:
+		fe.Add(fmt.Sprintf("aggregation method %s is not one of median, trimmedMean or weighted", i.Aggregation.Method))
+	}
+	if i.Aggregation.Trim < 0 || i.Aggregation.Trim >= 0.5 {
+		fe.Add("aggregation trim must be >= 0 and < 0.5")
+	} else if i.Aggregation.Trim > 0 && i.Aggregation.Method != models.FluxAggregationTrimmedMean {
+		fe.Add("aggregation trim only applies to the trimmedMean method")
+	}
+	if i.Aggregation.MaxDeviation < 0 {
+		fe.Add("aggregation maxDeviation must be >= 0")
+	}
+
+	if i.GasThrottle.Enabled() {
+		if i.GasThrottle.GasPriceCeiling.ToInt().Sign() <= 0 {
+			fe.Add("gasThrottle gasPriceCeiling must be > 0")
+		}
+		if i.GasThrottle.EmergencyThreshold <= i.Threshold {
+			fe.Add("gasThrottle emergencyThreshold must be > threshold")
+		}
+	} else if i.GasThrottle.EmergencyThreshold != 0 {
+		fe.Add("gasThrottle emergencyThreshold requires a gasPriceCeiling")
+	}
+
 	return fe.CoerceEmptyToNil()
 }
 
@@ -199,6 +563,9 @@ func validateFeeds(feeds models.Feeds, store *store.Store) error {
 			return errors.New("unknown feed type")
 		}
 	}
+	if store == nil {
+		return nil
+	}
 	if _, err := store.ORM.FindBridgesByNames(bridgeNames); err != nil {
 		return err
 	}
@@ -206,8 +573,25 @@ func validateFeeds(feeds models.Feeds, store *store.Store) error {
 	return nil
 }
 
-func validateRunLogInitiator(i models.Initiator, j models.JobSpec) error {
+// validateRunLogInitiator checks a runlog initiator. Its requesters are not
+// checked against the global allowlist if store is nil.
+func validateRunLogInitiator(i models.Initiator, j models.JobSpec, store *store.Store) error {
 	fe := models.NewJSONAPIErrors()
+	if len(i.Requesters) > 0 && store != nil {
+		allowlist, err := store.AllowedRequesters(nil)
+		if err != nil {
+			return errors.Wrap(err, "while loading global requester allowlist")
+		}
+		allowed := map[common.Address]bool{}
+		for _, r := range allowlist {
+			allowed[r.Address] = true
+		}
+		for _, requester := range i.Requesters {
+			if len(allowed) > 0 && !allowed[requester] {
+				fe.Add(fmt.Sprintf("Requester %s is not on the global requester allowlist", requester.Hex()))
+			}
+		}
+	}
 	ethTxCount := 0
 	for _, task := range j.Tasks {
 		if task.Type == adapters.TaskTypeEthTx {
@@ -232,12 +616,25 @@ func validateRunLogInitiator(i models.Initiator, j models.JobSpec) error {
 
 func validateRunAtInitiator(i models.Initiator, j models.JobSpec) error {
 	fe := models.NewJSONAPIErrors()
-	if !i.Time.Valid {
-		fe.Add("RunAt must have a time")
-	} else if j.StartAt.Valid && i.Time.Time.Unix() < j.StartAt.Time.Unix() {
-		fe.Add("RunAt time must be after job's StartAt")
-	} else if j.EndAt.Valid && i.Time.Time.Unix() > j.EndAt.Time.Unix() {
-		fe.Add("RunAt time must be before job's EndAt")
+	if i.Time.Valid && !i.Delay.IsInstant() {
+		fe.Add("RunAt must have either a time or a delay, not both")
+	} else if !i.Time.Valid && i.Delay.IsInstant() {
+		fe.Add("RunAt must have a time or a delay")
+	} else {
+		if j.CreatedAt.IsZero() {
+			j.CreatedAt = time.Now()
+		}
+		runAt := i.RunAtFor(j)
+		if j.StartAt.Valid && runAt.Unix() < j.StartAt.Time.Unix() {
+			fe.Add("RunAt time must be after job's StartAt")
+		} else if j.EndAt.Valid && runAt.Unix() > j.EndAt.Time.Unix() {
+			fe.Add("RunAt time must be before job's EndAt")
+		}
+	}
+	if !i.Every.IsInstant() && !j.EndAt.Valid {
+		fe.Add("RunAt can only recur for a job with an EndAt")
+	} else if !i.Every.IsInstant() && i.Every.Duration() < models.MinRunAtEvery {
+		fe.Add(fmt.Sprintf("RunAt can recur at most every %v", models.MinRunAtEvery))
 	}
 	return fe.CoerceEmptyToNil()
 }
@@ -246,7 +643,51 @@ func validateCronInitiator(i models.Initiator) error {
 	if i.Schedule == "" {
 		return models.NewJSONAPIErrorsWith("Schedule must have a cron")
 	}
-	return nil
+
+	fe := models.NewJSONAPIErrors()
+	if i.Schedule.HasTimezone() && i.Timezone != "" {
+		fe.Add("Cron must specify its time zone either using CRON_TZ or timezone, not both")
+	} else if !i.Schedule.HasTimezone() && i.Timezone == "" {
+		fe.Add("Cron: specs must specify a time zone using CRON_TZ, e.g. 'CRON_TZ=UTC 5 * * * *', or timezone, e.g. 'America/New_York'")
+	} else if _, err := i.CronSchedule().Location(); err != nil {
+		fe.Add(fmt.Sprintf("Cron has an invalid time zone: %v", err))
+	} else if _, err := models.CronParser.Parse(string(i.CronSchedule())); err != nil {
+		fe.Add(fmt.Sprintf("Cron: %v", err))
+	}
+	if err := i.Calendar.Validate(); err != nil {
+		fe.Add(fmt.Sprintf("Cron calendar: %v", err))
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+func validateEthLogInitiator(i models.Initiator) error {
+	if len(i.ABI) == 0 {
+		return nil
+	}
+	ids, err := i.ABI.EventIDs()
+	if err != nil {
+		return models.NewJSONAPIErrorsWith(fmt.Sprintf("EthLog: %v", err))
+	}
+	if len(i.Topics) == 0 {
+		return nil
+	}
+
+	fe := models.NewJSONAPIErrors()
+	for _, topic := range i.Topics[0] {
+		if !containsHash(ids, topic) {
+			fe.Add(fmt.Sprintf("EthLog: topic %s is not the ID of an event of the ABI", topic.Hex()))
+		}
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
 }
 
 func validateExternalInitiator(i models.Initiator) error {
@@ -256,6 +697,161 @@ func validateExternalInitiator(i models.Initiator) error {
 	return nil
 }
 
+func validateKafkaInitiator(i models.Initiator, store *store.Store) error {
+	fe := models.NewJSONAPIErrors()
+	if len(store.Config.KafkaBrokers()) == 0 {
+		fe.Add("cannot add kafka jobs when no kafka brokers are configured")
+	}
+	if len(i.KafkaTopics) == 0 {
+		fe.Add("Kafka must have at least one topic")
+	}
+	for _, topic := range i.KafkaTopics {
+		if !govalidator.StringMatches(topic, "^[a-zA-Z0-9._-]+$") {
+			fe.Add(fmt.Sprintf("Kafka topic %q must be alphanumeric and may contain '.', '_' or '-'", topic))
+		}
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+func validateMQTTInitiator(i models.Initiator) error {
+	fe := models.NewJSONAPIErrors()
+	if u, err := url.Parse(i.BrokerURL); err != nil || u.Host == "" {
+		fe.Add("MQTT must have a broker URL")
+	} else {
+		switch u.Scheme {
+		case "tcp", "ssl", "tls", "ws", "wss":
+		default:
+			fe.Add("MQTT broker URL scheme must be one of tcp, ssl, tls, ws or wss")
+		}
+	}
+	if err := validateMQTTTopicFilter(i.TopicFilter); err != nil {
+		fe.Add(err.Error())
+	}
+	if i.QoS > 2 {
+		fe.Add("MQTT qos must be 0, 1 or 2")
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+// validateMQTTTopicFilter checks the wildcards of a topic filter are used as
+// the MQTT spec allows: "+" as a whole level, and "#" only as the last level.
+func validateMQTTTopicFilter(filter string) error {
+	if filter == "" {
+		return errors.New("MQTT must have a topic filter")
+	}
+	levels := strings.Split(filter, "/")
+	for idx, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || idx != len(levels)-1) {
+			return errors.New("MQTT topic filter may only use '#' as its last level")
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return errors.New("MQTT topic filter must use '+' as a whole level")
+		}
+	}
+	return nil
+}
+
+func validateStreamInitiator(i models.Initiator, store *store.Store) error {
+	if i.BridgeName == "" {
+		return models.NewJSONAPIErrorsWith("Stream must have a bridge")
+	}
+	bt, err := store.FindBridge(i.BridgeName)
+	if err == orm.ErrorNotFound {
+		return models.NewJSONAPIErrorsWith(fmt.Sprintf("Bridge %v does not exist", i.BridgeName))
+	} else if err != nil {
+		return errors.Wrap(err, "validating stream initiator")
+	}
+	if bt.Mode != models.BridgeModeStream {
+		return models.NewJSONAPIErrorsWith(fmt.Sprintf("Bridge %v is not a stream bridge", i.BridgeName))
+	}
+	return nil
+}
+
+func validateKeeperInitiator(i models.Initiator, store *store.Store) error {
+	fe := models.NewJSONAPIErrors()
+	if store.Config.EthereumDisabled() {
+		fe.Add("cannot add keeper jobs when ethereum is disabled")
+	}
+	if i.Address == utils.ZeroAddress {
+		fe.Add("no address")
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+func validateBlockConditionInitiator(i models.Initiator, store *store.Store) error {
+	fe := models.NewJSONAPIErrors()
+	if store.Config.EthereumDisabled() {
+		fe.Add("cannot add block condition jobs when ethereum is disabled")
+	}
+	if err := i.BlockCondition.Expression.Validate(); err != nil {
+		fe.Add(err.Error())
+	}
+	return fe.CoerceEmptyToNil()
+}
+
+func validateOffchainReportingInitiator(i models.Initiator, store *store.Store) error {
+	fe := models.NewJSONAPIErrors()
+
+	if store.Config.EthereumDisabled() {
+		fe.Add("cannot add off-chain reporting jobs when ethereum is disabled")
+	}
+	if i.Address == utils.ZeroAddress {
+		fe.Add("no address")
+	}
+	if i.RequestData.String() == "" {
+		fe.Add("no requestdata")
+	}
+	if i.Threshold < 0 {
+		fe.Add("threshold must be >= 0")
+	}
+	if i.PollTimer.Disabled || i.PollTimer.Period.IsInstant() {
+		fe.Add("pollTimer must be enabled, with a period, for the length of a round")
+	} else if minimumPollPeriod := models.Duration(store.Config.DefaultHTTPTimeout()); i.PollTimer.Period.Shorter(minimumPollPeriod) {
+		fe.Add("pollTimer period must be equal or greater than " + minimumPollPeriod.String())
+	}
+	if !i.IdleTimer.Disabled && !i.IdleTimer.Duration.IsInstant() && i.IdleTimer.Duration.Shorter(i.PollTimer.Period) {
+		fe.Add("idleTimer.duration must be >= than pollTimer.period")
+	}
+	if err := validateFeeds(i.Feeds, store); err != nil {
+		fe.Add(err.Error())
+	}
+
+	oracles := i.OffchainReporting.Oracles
+	if len(oracles) == 0 || len(oracles) > 32 {
+		fe.Add("offchainReporting must have between 1 and 32 oracles")
+	}
+	self := ""
+	if kb, err := store.OCRKeyStore.Default(); err != nil {
+		fe.Add("no off-chain reporting key bundle is unlocked")
+	} else {
+		self = kb.ID()
+	}
+	foundSelf := false
+	peerIDs := make(map[string]bool)
+	for _, o := range oracles {
+		if _, err := peer.IDB58Decode(o.PeerID); err != nil {
+			fe.Add(fmt.Sprintf("oracle peerId %s is invalid", o.PeerID))
+		} else if peerIDs[o.PeerID] {
+			fe.Add(fmt.Sprintf("oracle peerId %s is repeated", o.PeerID))
+		}
+		peerIDs[o.PeerID] = true
+		if _, err := ma.NewMultiaddr(o.Addr); err != nil {
+			fe.Add(fmt.Sprintf("oracle %s addr %s is not a valid multiaddress", o.PeerID, o.Addr))
+		}
+		if o.SigningAddress == utils.ZeroAddress {
+			fe.Add(fmt.Sprintf("oracle %s has no signingAddress", o.PeerID))
+		}
+		if o.PeerID == self {
+			foundSelf = true
+		}
+	}
+	if self != "" && !foundSelf {
+		fe.Add(fmt.Sprintf("this node's peer ID %s is not one of the oracles", self))
+	}
+
+	return fe.CoerceEmptyToNil()
+}
+
 func validateServiceAgreementInitiator(i models.Initiator, j models.JobSpec) error {
 	fe := models.NewJSONAPIErrors()
 	if len(j.Initiators) != 1 {
@@ -275,22 +871,93 @@ func validateRandomnessLogInitiator(i models.Initiator, j models.JobSpec) error
 	return fe.CoerceEmptyToNil()
 }
 
-func validateTask(task models.TaskSpec, store *store.Store) error {
+func validateTask(task models.TaskSpec, namespace string, store *store.Store) error {
 	adapter, err := adapters.For(task, store.Config, store.ORM)
 	if err != nil {
 		return err
 	}
-	if !store.Config.EnableExperimentalAdapters() {
-		if _, ok := adapter.BaseAdapter.(*adapters.Sleep); ok {
+	for _, name := range models.SecretReferences(task.Params) {
+		if _, err := store.SecretValue(namespace, name); err != nil {
+			return errors.Wrapf(err, "%s Task references secret %s", task.Type, name)
+		}
+	}
+	return validateAdapter(adapter.BaseAdapter, store.Config, store)
+}
+
+// validateAdapter checks the params of a task's adapter. The bridges of
+// aggregate sources are not looked up if store is nil.
+func validateAdapter(ba adapters.BaseAdapter, config orm.ConfigReader, store *store.Store) error {
+	if etx, ok := ba.(*adapters.EthTx); ok && len(etx.Encoding) > 0 {
+		if etx.DataFormat != "" {
+			return errors.New("EthTx Task cannot set both format and encoding")
+		} else if _, err := etx.Encoding.Arguments(); err != nil {
+			return errors.Wrap(err, "EthTx Task encoding is invalid")
+		}
+	}
+	if aggregate, ok := ba.(*adapters.Aggregate); ok {
+		if err := validateAggregate(aggregate, store); err != nil {
+			return err
+		}
+	}
+	if compute, ok := ba.(*adapters.Compute); ok && compute.Expression.String() == "" {
+		return errors.New("Compute Task must have an expression")
+	}
+	if signResult, ok := ba.(*adapters.SignResult); ok {
+		if err := signResult.ValidateParams(); err != nil {
+			return err
+		}
+	}
+	if wasm, ok := ba.(*adapters.Wasm); ok {
+		if err := wasm.ValidateParams(store); err != nil {
+			return err
+		}
+	}
+	if plugin, ok := ba.(*adapters.Plugin); ok {
+		if err := plugin.ValidateParams(); err != nil {
+			return err
+		}
+	}
+	if !config.EnableExperimentalAdapters() {
+		if _, ok := ba.(*adapters.Sleep); ok {
 			return errors.New("Sleep Adapter is not implemented yet")
 		}
-		if _, ok := adapter.BaseAdapter.(*adapters.EthTxABIEncode); ok {
+		if _, ok := ba.(*adapters.EthTxABIEncode); ok {
 			return errors.New("EthTxABIEncode Adapter is not implemented yet")
 		}
 	}
 	return nil
 }
 
+func validateAggregate(a *adapters.Aggregate, store *store.Store) error {
+	fe := models.NewJSONAPIErrors()
+	if len(a.Sources) == 0 {
+		fe.Add("Aggregate task must have at least one source")
+	}
+	for i, src := range a.Sources {
+		if (src.Bridge == "") == (src.URL.String() == "") {
+			fe.Add(fmt.Sprintf("Aggregate source %d must have either a url or a bridge", i))
+		} else if src.Bridge != "" && store != nil {
+			if bt, err := store.FindBridge(src.Bridge); err != nil {
+				fe.Add(fmt.Sprintf("Aggregate source %d bridge %s does not exist", i, src.Bridge))
+			} else if bt.Mode == models.BridgeModeStream {
+				fe.Add(fmt.Sprintf("Aggregate source %d bridge %s is a stream bridge", i, src.Bridge))
+			}
+		}
+	}
+	if a.MinResponses > uint(len(a.Sources)) {
+		fe.Add(fmt.Sprintf("Aggregate minResponses %d is more than the %d sources", a.MinResponses, len(a.Sources)))
+	}
+	switch a.Method {
+	case "", adapters.AggregateMedian, adapters.AggregateMean, adapters.AggregateMode:
+	default:
+		fe.Add(fmt.Sprintf("Aggregate method %s is not one of median, mean or mode", a.Method))
+	}
+	if a.Trim.IsNegative() || a.Trim.GreaterThanOrEqual(decimal.NewFromFloat(0.5)) {
+		fe.Add("Aggregate trim must be at least 0 and less than 0.5")
+	}
+	return fe.CoerceEmptyToNil()
+}
+
 // ValidateServiceAgreement checks the ServiceAgreement for any application logic errors.
 func ValidateServiceAgreement(sa models.ServiceAgreement, store *store.Store) error {
 	fe := models.NewJSONAPIErrors()
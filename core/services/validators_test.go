@@ -3,14 +3,20 @@ package services_test
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	clnull "github.com/smartcontractkit/chainlink/core/null"
 	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -99,6 +105,220 @@ func TestValidateJob_RejectsSleepAdapterWhenExperimentalAdaptersAreDisabled(t *t
 	assert.Error(t, services.ValidateJob(sleepingJob, store))
 }
 
+func TestValidateJob_InvalidTransformExpression(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	var j models.JobSpec
+	require.NoError(t, json.Unmarshal(cltest.MustReadFile(t, "testdata/invalid_transform_job.json"), &j))
+
+	err := services.ValidateJob(j, store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `transform expression "[.data[] | select(.volume > 1000)" is invalid`)
+}
+
+func TestValidateJob_EthTxEncoding(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr string
+	}{
+		{"valid", `{"encoding":[{"type":"uint256","path":"result.price"},{"type":"bytes","path":["result","proof"]}]}`, ""},
+		{"with format", `{"format":"bytes","encoding":[{"type":"uint256","path":"result"}]}`, "EthTx Task cannot set both format and encoding"},
+		{"unsupported type", `{"encoding":[{"type":"string[]","path":"result"}]}`, "EthTx Task encoding is invalid: encoding field 0 has unsupported ABI type string[]"},
+		{"no path", `{"encoding":[{"type":"uint256"}]}`, "EthTx Task encoding is invalid: encoding field 0 has no path"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			j := cltest.NewJobWithWebInitiator()
+			j.Tasks = []models.TaskSpec{{Type: adapters.TaskTypeEthTx, Params: cltest.JSONFromString(t, test.params)}}
+			err := services.ValidateJob(j, store)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, models.NewJSONAPIErrorsWith(test.wantErr), err)
+			}
+		})
+	}
+}
+
+func TestValidateJob_ComputeExpression(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr string
+	}{
+		{"valid", `{"expression":"result < 100 ? result * 1.05 : result"}`, ""},
+		{"no expression", `{}`, "Compute Task must have an expression"},
+		{"invalid expression", `{"expression":"result *"}`, `compute expression "result *" is invalid`},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			j := cltest.NewJobWithWebInitiator()
+			j.Tasks = []models.TaskSpec{{Type: adapters.TaskTypeCompute, Params: cltest.JSONFromString(t, test.params)}}
+			err := services.ValidateJob(j, store)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateJob_SignResult(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr string
+	}{
+		{"eip191", `{}`, ""},
+		{"eip712", `{"scheme":"eip712","resultType":"uint256"}`, ""},
+		{"unknown scheme", `{"scheme":"eip1"}`, "SignResult Task scheme eip1 is not eip191 or eip712"},
+		{"unknown type", `{"scheme":"eip712","resultType":"uint8"}`, "SignResult Task resultType uint8 is not one of"},
+		{"eip191 with type", `{"resultType":"bool"}`, "SignResult Task can only set resultType and verifyingContract with the eip712 scheme"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			j := cltest.NewJobWithWebInitiator()
+			j.Tasks = []models.TaskSpec{{Type: adapters.TaskTypeSignResult, Params: cltest.JSONFromString(t, test.params)}}
+			err := services.ValidateJob(j, store)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateJobOffline_BridgesAndPlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugins")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	script := "#!/bin/sh\necho '{\"taskType\":\"offlineplugin\"}'\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "offline"), []byte(script), 0755))
+	require.NoError(t, adapters.LoadPlugins(dir, time.Second, nil))
+	defer adapters.LoadPlugins(dir, time.Second, nil)
+	defer os.Remove(filepath.Join(dir, "offline"))
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Tasks = []models.TaskSpec{
+		{Type: adapters.TaskTypeNoOp},
+		{Type: "offlineplugin"},
+		{Type: "offlinebridge"},
+	}
+	bridges, plugins, err := services.ValidateJobOffline(job, orm.NewConfig(), models.JobSpecLines{})
+	require.NoError(t, err)
+	assert.Equal(t, []models.TaskType{"offlinebridge"}, bridges)
+	assert.Equal(t, []models.TaskType{"offlineplugin"}, plugins)
+}
+
+func TestValidateJob_Confirmations(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("FINALITY_DEPTH", 10)
+
+	tests := []struct {
+		name     string
+		job      models.JobSpec
+		incoming clnull.Uint32
+		outgoing clnull.Uint32
+		task     models.TaskSpec
+		wantErr  string
+	}{
+		{"runlog incoming", cltest.NewJobWithRunLogInitiator(), clnull.Uint32From(3), clnull.Uint32{}, models.TaskSpec{Type: adapters.TaskTypeNoOp}, ""},
+		{"web incoming", cltest.NewJobWithWebInitiator(), clnull.Uint32From(3), clnull.Uint32{}, models.TaskSpec{Type: adapters.TaskTypeNoOp}, "incomingConfirmations can only be set on jobs with a log initiator"},
+		{"ethtx outgoing", cltest.NewJobWithWebInitiator(), clnull.Uint32{}, clnull.Uint32From(3), models.TaskSpec{Type: adapters.TaskTypeEthTx, OutgoingConfirmations: clnull.Uint32From(6)}, ""},
+		{"noop job outgoing", cltest.NewJobWithWebInitiator(), clnull.Uint32{}, clnull.Uint32From(3), models.TaskSpec{Type: adapters.TaskTypeNoOp}, "outgoingConfirmations can only be set on jobs with a task sending transactions"},
+		{"noop task outgoing", cltest.NewJobWithWebInitiator(), clnull.Uint32{}, clnull.Uint32{}, models.TaskSpec{Type: adapters.TaskTypeNoOp, OutgoingConfirmations: clnull.Uint32From(6)}, "outgoingConfirmations can only be set on tasks sending transactions, not noop"},
+		{"incoming past finality", cltest.NewJobWithRunLogInitiator(), clnull.Uint32From(11), clnull.Uint32{}, models.TaskSpec{Type: adapters.TaskTypeNoOp}, "incomingConfirmations of 11 is more than FINALITY_DEPTH of 10"},
+		{"ethtx outgoing param", cltest.NewJobWithWebInitiator(), clnull.Uint32{}, clnull.Uint32{}, models.TaskSpec{Type: adapters.TaskTypeEthTx, Params: cltest.JSONFromString(t, `{"minOutgoingConfirmations": 0}`)}, "minOutgoingConfirmations can't be set in the params of task ethtx, set its outgoingConfirmations instead"},
+		{"task outgoing past finality", cltest.NewJobWithWebInitiator(), clnull.Uint32{}, clnull.Uint32{}, models.TaskSpec{Type: adapters.TaskTypeEthTx, OutgoingConfirmations: clnull.Uint32From(12)}, "outgoingConfirmations of task ethtx of 12 is more than FINALITY_DEPTH of 10"},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			j := test.job
+			j.IncomingConfirmations = test.incoming
+			j.OutgoingConfirmations = test.outgoing
+			j.Tasks = []models.TaskSpec{test.task}
+			err := services.ValidateJob(j, store)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, models.NewJSONAPIErrorsWith(test.wantErr), err)
+			}
+		})
+	}
+}
+
+func TestValidateJob_Aggregate(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t, "aggregatedbridge", "https://bridge.example.com")
+	require.NoError(t, store.CreateBridgeType(bt))
+
+	tests := []struct {
+		name   string
+		params string
+		want   error
+	}{
+		{"valid", `{"sources":[{"url":"https://example.com"},{"bridge":"aggregatedbridge"}],"method":"mean","trim":0.1}`, nil},
+		{"no sources", `{"sources":[]}`, models.NewJSONAPIErrorsWith("Aggregate task must have at least one source")},
+		{"url and bridge", `{"sources":[{"url":"https://example.com","bridge":"aggregatedbridge"}]}`,
+			models.NewJSONAPIErrorsWith("Aggregate source 0 must have either a url or a bridge")},
+		{"missing bridge", `{"sources":[{"bridge":"idonotexist"}]}`,
+			models.NewJSONAPIErrorsWith("Aggregate source 0 bridge idonotexist does not exist")},
+		{"quorum too large", `{"sources":[{"url":"https://example.com"}],"minResponses":2}`,
+			models.NewJSONAPIErrorsWith("Aggregate minResponses 2 is more than the 1 sources")},
+		{"unknown method", `{"sources":[{"url":"https://example.com"}],"method":"max"}`,
+			models.NewJSONAPIErrorsWith("Aggregate method max is not one of median, mean or mode")},
+		{"trim too large", `{"sources":[{"url":"https://example.com"}],"trim":0.5}`,
+			models.NewJSONAPIErrorsWith("Aggregate trim must be at least 0 and less than 0.5")},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			j := cltest.NewJobWithWebInitiator()
+			j.Tasks = []models.TaskSpec{{Type: adapters.TaskTypeAggregate, Params: cltest.JSONFromString(t, test.params)}}
+			assert.Equal(t, test.want, services.ValidateJob(j, store))
+		})
+	}
+}
+
 func TestValidateBridgeType(t *testing.T) {
 	t.Parallel()
 
@@ -174,6 +394,87 @@ func TestValidateBridgeType(t *testing.T) {
 				URL:  cltest.WebURL(t, "https://denergy.eth"),
 			},
 			nil,
+		},
+		{
+			"valid stream bridge",
+			models.BridgeTypeRequest{
+				Name: "gdaxstream",
+				URL:  cltest.WebURL(t, "wss://denergy.eth"),
+				Mode: models.BridgeModeStream,
+			},
+			nil,
+		},
+		{
+			"stream bridge with http url",
+			models.BridgeTypeRequest{
+				Name: "gdaxstream",
+				URL:  cltest.WebURL(t, "https://denergy.eth"),
+				Mode: models.BridgeModeStream,
+			},
+			models.NewJSONAPIErrorsWith("URL scheme of a stream bridge must be ws or wss"),
+		},
+		{
+			"unknown mode",
+			models.BridgeTypeRequest{
+				Name: "gdaxprice",
+				URL:  cltest.WebURL(t, "https://denergy.eth"),
+				Mode: "carrierpigeon",
+			},
+			models.NewJSONAPIErrorsWith("Mode carrierpigeon must be http or stream"),
+		},
+		{
+			"valid basic auth",
+			models.BridgeTypeRequest{
+				Name: "gdaxprice",
+				URL:  cltest.WebURL(t, "https://denergy.eth"),
+				Auth: &models.BridgeAuth{Type: models.BridgeAuthBasic, Username: "user", Password: "pass"},
+			},
+			nil,
+		},
+		{
+			"basic auth without username",
+			models.BridgeTypeRequest{
+				Name: "gdaxprice",
+				URL:  cltest.WebURL(t, "https://denergy.eth"),
+				Auth: &models.BridgeAuth{Type: models.BridgeAuthBasic, Password: "pass"},
+			},
+			models.NewJSONAPIErrorsWith("Auth username must be present"),
+		},
+		{
+			"headers auth with invalid header name",
+			models.BridgeTypeRequest{
+				Name: "gdaxprice",
+				URL:  cltest.WebURL(t, "https://denergy.eth"),
+				Auth: &models.BridgeAuth{Type: models.BridgeAuthHeaders, Headers: map[string]string{"X Key": "v"}},
+			},
+			models.NewJSONAPIErrorsWith(`Auth header name "X Key" is invalid`),
+		},
+		{
+			"oauth2 auth without client secret",
+			models.BridgeTypeRequest{
+				Name: "gdaxprice",
+				URL:  cltest.WebURL(t, "https://denergy.eth"),
+				Auth: &models.BridgeAuth{Type: models.BridgeAuthOAuth2, TokenURL: "https://auth.denergy.eth/token", ClientID: "id"},
+			},
+			models.NewJSONAPIErrorsWith("Auth clientId and clientSecret must be present"),
+		},
+		{
+			"unknown auth type",
+			models.BridgeTypeRequest{
+				Name: "gdaxprice",
+				URL:  cltest.WebURL(t, "https://denergy.eth"),
+				Auth: &models.BridgeAuth{Type: "kerberos"},
+			},
+			models.NewJSONAPIErrorsWith("Auth type kerberos must be headers, basic or oauth2"),
+		},
+		{
+			"unknown client certificate",
+			models.BridgeTypeRequest{
+				Name:              "gdaxprice",
+				URL:               cltest.WebURL(t, "https://denergy.eth"),
+				ClientCertificate: "missing",
+			},
+			models.NewJSONAPIErrorsWith("Client certificate missing does not exist"),
 		}}
 
 	for _, test := range tests {
@@ -195,6 +496,10 @@ func TestValidateBridgeNotExist(t *testing.T) {
 	bt.Name = models.MustNewTaskType("solargridreporting")
 	bt.URL = cltest.WebURL(t, "https://denergy.eth")
 	assert.NoError(t, store.CreateBridgeType(&bt))
+	streamBridge := models.BridgeType{Mode: models.BridgeModeStream}
+	streamBridge.Name = models.MustNewTaskType("solargridstream")
+	streamBridge.URL = cltest.WebURL(t, "wss://denergy.eth")
+	assert.NoError(t, store.CreateBridgeType(&streamBridge))
 
 	tests := []struct {
 		description string
@@ -208,6 +513,13 @@ func TestValidateBridgeNotExist(t *testing.T) {
 			},
 			models.NewJSONAPIErrorsWith("Bridge Type solargridreporting already exists"),
 		},
+		{
+			"existing stream adapter",
+			models.BridgeTypeRequest{
+				Name: "solargridstream",
+			},
+			models.NewJSONAPIErrorsWith("Bridge Type solargridstream already exists"),
+		},
 		{
 			"existing core adapter",
 			models.BridgeTypeRequest{
@@ -285,15 +597,31 @@ func TestValidateInitiator(t *testing.T) {
 	}{
 		{"web", `{"type":"web"}`, false},
 		{"ethlog", `{"type":"ethlog"}`, false},
+		{"ethlog with abi", `{"type":"ethlog","params":{"abi":[{"type":"event","name":"Transfer","inputs":[{"name":"value","type":"uint256"}]}]}}`, false},
+		{"ethlog with invalid abi", `{"type":"ethlog","params":{"abi":[{"type":"event","name":"Transfer","inputs":[{"name":"value","type":"notatype"}]}]}}`, true},
+		{"ethlog with abi without events", `{"type":"ethlog","params":{"abi":[{"type":"function","name":"transfer","inputs":[]}]}}`, true},
+		{"ethlog with topic not in abi", `{"type":"ethlog","params":{"topics":[["0x0000000000000000000000000000000000000000000000000000000000000001"]],"abi":[{"type":"event","name":"Transfer","inputs":[{"name":"value","type":"uint256"}]}]}}`, true},
 		{"external", `{"type":"external","params":{"name":"bitcoin"}}`, false},
 		{"runlog", `{"type":"runlog"}`, false},
 		{"runat", fmt.Sprintf(`{"type":"runat","params": {"time":"%v"}}`, utils.ISO8601UTC(startAt)), false},
 		{"runat w/o time", `{"type":"runat"}`, true},
 		{"runat w time before start at", fmt.Sprintf(`{"type":"runat","params": {"time":"%v"}}`, startAt.Add(-1*time.Second).Unix()), true},
 		{"runat w time after end at", fmt.Sprintf(`{"type":"runat","params": {"time":"%v"}}`, endAt.Add(time.Second).Unix()), true},
+		{"runat w delay", `{"type":"runat","params": {"delay":"500ms"}}`, false},
+		{"runat w time and delay", fmt.Sprintf(`{"type":"runat","params": {"time":"%v","delay":"2h"}}`, utils.ISO8601UTC(startAt)), true},
+		{"runat w delay past end at", `{"type":"runat","params": {"delay":"2h"}}`, true},
+		{"runat recurring until end at", `{"type":"runat","params": {"delay":"500ms","every":"6h"}}`, false},
+		{"runat recurring too often", `{"type":"runat","params": {"delay":"500ms","every":"30s"}}`, true},
 		{"cron standard", `{"type":"cron","params": {"schedule":"CRON_TZ=UTC * * * * *"}}`, false},
 		{"cron with 6 fields", `{"type":"cron","params": {"schedule":"CRON_TZ=UTC * * * * * *"}}`, false},
 		{"cron w/o schedule", `{"type":"cron"}`, true},
+		{"cron w/o time zone", `{"type":"cron","params": {"schedule":"* * * * *"}}`, true},
+		{"cron with timezone", `{"type":"cron","params": {"schedule":"0 9 * * 1-5","timezone":"America/New_York"}}`, false},
+		{"cron with CRON_TZ and timezone", `{"type":"cron","params": {"schedule":"CRON_TZ=UTC 0 9 * * 1-5","timezone":"America/New_York"}}`, true},
+		{"cron with unknown timezone", `{"type":"cron","params": {"schedule":"0 9 * * 1-5","timezone":"Mars/Olympus_Mons"}}`, true},
+		{"cron with calendar", `{"type":"cron","params": {"schedule":"CRON_TZ=UTC 0 9 * * *","calendar":{"holidays":["2020-12-25"],"blackouts":[{"from":"2020-06-01T00:00:00Z","to":"2020-06-02T00:00:00Z"}],"defer":true}}}`, false},
+		{"cron with invalid holiday", `{"type":"cron","params": {"schedule":"CRON_TZ=UTC 0 9 * * *","calendar":{"holidays":["25/12/2020"]}}}`, true},
+		{"cron with blackout ending before it starts", `{"type":"cron","params": {"schedule":"CRON_TZ=UTC 0 9 * * *","calendar":{"blackouts":[{"from":"2020-06-02T00:00:00Z","to":"2020-06-01T00:00:00Z"}]}}}`, true},
 		{"external w/o name", `{"type":"external"}`, true},
 		{"non-existent initiator", `{"type":"doesntExist"}`, true},
 	}
@@ -309,6 +637,25 @@ func TestValidateInitiator(t *testing.T) {
 	}
 }
 
+func TestValidateInitiator_RunAtRecurrence(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	var initr models.Initiator
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"runat","params": {"delay":"2h","every":"6h"}}`), &initr))
+
+	job := cltest.NewJob()
+	assert.EqualError(t, services.ValidateInitiator(initr, job, store), "RunAt can only recur for a job with an EndAt")
+
+	job.EndAt = cltest.NullableTime(time.Now().Add(24 * time.Hour))
+	assert.NoError(t, services.ValidateInitiator(initr, job, store))
+
+	initr.Every = models.MustMakeDuration(time.Second)
+	assert.EqualError(t, services.ValidateInitiator(initr, job, store), "RunAt can recur at most every 1m0s")
+}
+
 func TestValidateServiceAgreement(t *testing.T) {
 	t.Parallel()
 
@@ -423,6 +770,13 @@ func TestValidateInitiator_FluxMonitorErrors(t *testing.T) {
 		{"pollTimer enabled, but no period specified", cltest.MustJSONDel(t, validInitiator, "params.pollTimer.period")},
 		{"period must be equal or greater than 15s", cltest.MustJSONSet(t, validInitiator, "params.pollTimer.period", "1s")},
 		{"idleTimer.duration must be >= than pollTimer.period", cltest.MustJSONSet(t, validInitiator, "params.idleTimer.duration", "30s")},
+		{"aggregation method mode is not one of", cltest.MustJSONSet(t, validInitiator, "params.aggregation.method", "mode")},
+		{"aggregation trim must be >= 0 and < 0.5", cltest.MustJSONSet(t, validInitiator, "params.aggregation", map[string]interface{}{"method": "trimmedMean", "trim": 0.5})},
+		{"aggregation trim only applies to the trimmedMean method", cltest.MustJSONSet(t, validInitiator, "params.aggregation.trim", 0.1)},
+		{"aggregation maxDeviation must be >= 0", cltest.MustJSONSet(t, validInitiator, "params.aggregation.maxDeviation", -1)},
+		{"gasThrottle gasPriceCeiling must be > 0", cltest.MustJSONSet(t, validInitiator, "params.gasThrottle", map[string]interface{}{"gasPriceCeiling": "0", "emergencyThreshold": 10})},
+		{"gasThrottle emergencyThreshold must be > threshold", cltest.MustJSONSet(t, validInitiator, "params.gasThrottle.gasPriceCeiling", "100000000000")},
+		{"gasThrottle emergencyThreshold requires a gasPriceCeiling", cltest.MustJSONSet(t, validInitiator, "params.gasThrottle.emergencyThreshold", 10)},
 	}
 	for _, test := range tests {
 		t.Run("bad "+test.Field, func(t *testing.T) {
@@ -506,3 +860,281 @@ func TestValidateInitiator_FeedsErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateInitiator_Kafka(t *testing.T) {
+	t.Parallel()
+
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	config.Config.Set("KAFKA_BROKERS", "localhost:9092")
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+
+	job := cltest.NewJob()
+	tests := []struct {
+		name    string
+		topics  models.StringCollection
+		wantErr string
+	}{
+		{"valid topics", models.StringCollection{"prices", "eth.usd_v2"}, ""},
+		{"no topics", nil, "at least one topic"},
+		{"invalid topic", models.StringCollection{"prices/eth"}, "must be alphanumeric"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			initr := models.Initiator{
+				Type:            models.InitiatorKafka,
+				InitiatorParams: models.InitiatorParams{KafkaTopics: test.topics},
+			}
+			err := services.ValidateInitiator(initr, job, store)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateInitiator_Kafka_NoBrokers(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	initr := models.Initiator{
+		Type:            models.InitiatorKafka,
+		InitiatorParams: models.InitiatorParams{KafkaTopics: models.StringCollection{"prices"}},
+	}
+	err := services.ValidateInitiator(initr, cltest.NewJob(), store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no kafka brokers")
+}
+
+func TestValidateInitiator_MQTT(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJob()
+	valid := models.InitiatorParams{BrokerURL: "tcp://localhost:1883", TopicFilter: "sensors/+/temperature", QoS: 1}
+	tests := []struct {
+		name    string
+		mutate  func(*models.InitiatorParams)
+		wantErr string
+	}{
+		{"valid", func(*models.InitiatorParams) {}, ""},
+		{"multi level wildcard", func(p *models.InitiatorParams) { p.TopicFilter = "sensors/#" }, ""},
+		{"no broker", func(p *models.InitiatorParams) { p.BrokerURL = "" }, "broker URL"},
+		{"bad scheme", func(p *models.InitiatorParams) { p.BrokerURL = "http://localhost:1883" }, "scheme"},
+		{"no topic filter", func(p *models.InitiatorParams) { p.TopicFilter = "" }, "topic filter"},
+		{"misplaced #", func(p *models.InitiatorParams) { p.TopicFilter = "sensors/#/temperature" }, "'#'"},
+		{"partial +", func(p *models.InitiatorParams) { p.TopicFilter = "sensors/kitchen+/temperature" }, "'+'"},
+		{"bad qos", func(p *models.InitiatorParams) { p.QoS = 3 }, "qos"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := valid
+			test.mutate(&params)
+			initr := models.Initiator{Type: models.InitiatorMQTT, InitiatorParams: params}
+			err := services.ValidateInitiator(initr, job, store)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateInitiator_Stream(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	_, httpBridge := cltest.NewBridgeType(t, "httpbridge")
+	require.NoError(t, store.CreateBridgeType(httpBridge))
+	_, streamBridge := cltest.NewBridgeType(t, "streambridge", "wss://bridge.example.com/stream")
+	streamBridge.Mode = models.BridgeModeStream
+	require.NoError(t, store.CreateBridgeType(streamBridge))
+
+	job := cltest.NewJob()
+	tests := []struct {
+		name    string
+		bridge  models.TaskType
+		wantErr string
+	}{
+		{"stream bridge", "streambridge", ""},
+		{"no bridge", "", "must have a bridge"},
+		{"missing bridge", "nosuchbridge", "does not exist"},
+		{"http bridge", "httpbridge", "is not a stream bridge"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			initr := models.Initiator{
+				Type:            models.InitiatorStream,
+				InitiatorParams: models.InitiatorParams{BridgeName: test.bridge},
+			}
+			err := services.ValidateInitiator(initr, job, store)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateInitiator_OffchainReporting(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJob()
+	var fluxInitr models.Initiator
+	require.NoError(t, json.Unmarshal([]byte(validInitiator), &fluxInitr))
+	validInitr := func() models.Initiator {
+		return models.Initiator{Type: models.InitiatorOffchainReporting, InitiatorParams: fluxInitr.InitiatorParams}
+	}
+
+	other, err := ocrkey.NewKeyBundle()
+	require.NoError(t, err)
+	initr := validInitr()
+	initr.OffchainReporting.Oracles = []models.OffchainReportingOracle{{
+		PeerID:         other.ID(),
+		Addr:           "/ip4/127.0.0.1/tcp/6690",
+		SigningAddress: other.SigningAddress(),
+	}}
+	err = services.ValidateInitiator(initr, job, store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no off-chain reporting key bundle is unlocked")
+
+	kb, err := store.OCRKeyStore.CreateKey(cltest.Password, ocrkey.FastScryptParams)
+	require.NoError(t, err)
+	self := models.OffchainReportingOracle{
+		PeerID:         kb.ID(),
+		Addr:           "/ip4/127.0.0.1/tcp/6690",
+		SigningAddress: kb.SigningAddress(),
+	}
+	err = services.ValidateInitiator(initr, job, store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not one of the oracles")
+
+	initr.OffchainReporting.Oracles = append(initr.OffchainReporting.Oracles, self)
+	assert.NoError(t, services.ValidateInitiator(initr, job, store))
+
+	tests := []struct {
+		name    string
+		oracle  models.OffchainReportingOracle
+		wantErr string
+	}{
+		{"bad peer ID", models.OffchainReportingOracle{PeerID: "notapeer", Addr: self.Addr, SigningAddress: cltest.NewAddress()}, "peerId notapeer is invalid"},
+		{"repeated peer ID", self, "is repeated"},
+		{"bad addr", models.OffchainReportingOracle{PeerID: self.PeerID, Addr: "127.0.0.1:6690", SigningAddress: cltest.NewAddress()}, "is not a valid multiaddress"},
+		{"no signing address", models.OffchainReportingOracle{PeerID: other.ID(), Addr: self.Addr}, "has no signingAddress"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			initr := validInitr()
+			initr.OffchainReporting.Oracles = []models.OffchainReportingOracle{self, test.oracle}
+			err := services.ValidateInitiator(initr, job, store)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.wantErr)
+		})
+	}
+
+	initr = validInitr()
+	initr.OffchainReporting.Oracles = []models.OffchainReportingOracle{self}
+	initr.PollTimer = models.PollTimerConfig{Disabled: true}
+	err = services.ValidateInitiator(initr, job, store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pollTimer must be enabled")
+}
+
+func TestValidateJob_Keeper(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	// Keeper jobs need no task
+	job := cltest.NewJobWithKeeperInitiator(cltest.NewAddress())
+	assert.NoError(t, services.ValidateJob(job, store))
+
+	job.Initiators[0].Address = utils.ZeroAddress
+	err := services.ValidateJob(job, store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no address")
+
+	// Unlike jobs with other initiators as well
+	job = cltest.NewJobWithKeeperInitiator(cltest.NewAddress())
+	job.Initiators = append(job.Initiators, models.Initiator{Type: models.InitiatorWeb})
+	err = services.ValidateJob(job, store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Must have at least one Initiator and one Task")
+}
+
+func TestValidateJob_RequesterAllowlist(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	allowed, other := cltest.NewAddress(), cltest.NewAddress()
+	job := cltest.NewJobWithRunLogInitiator()
+	job.Initiators[0].Requesters = models.AddressCollection{allowed}
+	assert.NoError(t, services.ValidateJob(job, store), "an empty global allowlist allows every requester")
+
+	require.NoError(t, store.AllowRequester(nil, allowed))
+	assert.NoError(t, services.ValidateJob(job, store))
+
+	job.Initiators[0].Requesters = models.AddressCollection{allowed, other}
+	err := services.ValidateJob(job, store)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Requester "+other.Hex()+" is not on the global requester allowlist")
+}
+
+func TestValidateJob_TaskGraph(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	task := func(name string, inputs ...string) models.TaskSpec {
+		return models.TaskSpec{Type: adapters.TaskTypeNoOp, Name: name, Inputs: inputs}
+	}
+	tests := []struct {
+		name  string
+		tasks []models.TaskSpec
+		want  string
+	}{
+		{"diamond", []models.TaskSpec{task("a"), task("b", "a"), task("c", "a"), task("d", "b", "c")}, ""},
+		{"linear", []models.TaskSpec{task(""), task("")}, ""},
+		{"duplicate name", []models.TaskSpec{task("a"), task("a"), task("b", "a")}, "Task name a is used more than once"},
+		{"unnamed task", []models.TaskSpec{task(""), task("b", "a")}, "Task 0 must have a name"},
+		{"unknown input", []models.TaskSpec{task("a"), task("b", "c")}, "Task b has unknown input c"},
+		{"two final tasks", []models.TaskSpec{task("a"), task("b", "a"), task("c", "a")}, "Tasks must lead to a single final task, not b, c"},
+		{"cycle", []models.TaskSpec{task("a", "c"), task("b", "a"), task("c", "b"), task("d", "c")}, "Tasks depend on each other: a, b, c"},
+		{"self", []models.TaskSpec{task("a", "a"), task("b", "a")}, "Tasks depend on each other: a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			j := cltest.NewJobWithWebInitiator()
+			j.Tasks = test.tasks
+			err := services.ValidateJob(j, store)
+			if test.want == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.want)
+			}
+		})
+	}
+}
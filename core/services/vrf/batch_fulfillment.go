@@ -0,0 +1,77 @@
+package vrf
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+)
+
+// multicallABI is the ABI of the tryAggregate method of a Multicall2
+// contract, which makes each of a list of calls in turn, and if
+// requireSuccess is false, carries on when one of them reverts.
+const multicallABI = `[{"inputs":[{"internalType":"bool","name":"requireSuccess","type":"bool"},{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall2.Call[]","name":"calls","type":"tuple[]"}],"name":"tryAggregate","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall2.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"nonpayable","type":"function"}]`
+
+var parsedMulticallABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(multicallABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// multicall is a call made by a Multicall2 contract.
+type multicall struct {
+	Target   common.Address
+	CallData []byte
+}
+
+// FulfillmentCalldata returns the calldata of a call to the VRFCoordinator's
+// fulfillRandomnessRequest method with the given proof.
+func FulfillmentCalldata(proof MarshaledProof) ([]byte, error) {
+	return CoordinatorABI().Pack(fulfillMethodName, proof[:])
+}
+
+// BatchFulfillmentCalldata returns the calldata of a call to a Multicall2
+// contract, which calls the coordinator's fulfillRandomnessRequest method
+// with each of the given proofs. A fulfillment which reverts, such as one
+// already made by another transaction, does not revert the others.
+func BatchFulfillmentCalldata(coordinator common.Address, proofs []MarshaledProof) ([]byte, error) {
+	calls := make([]multicall, len(proofs))
+	for i, proof := range proofs {
+		data, err := FulfillmentCalldata(proof)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = multicall{Target: coordinator, CallData: data}
+	}
+	return parsedMulticallABI.Pack("tryAggregate", false, calls)
+}
+
+// RequestPending returns true if the coordinator is still waiting for the
+// request with the given ID to be fulfilled.
+func RequestPending(client eth.Client, coordinator common.Address, requestID common.Hash) (bool, error) {
+	data, err := CoordinatorABI().Pack("callbacks", requestID)
+	if err != nil {
+		return false, err
+	}
+	var result hexutil.Bytes
+	err = client.Call(&result, "eth_call", eth.CallArgs{To: coordinator, Data: data}, "latest")
+	if err != nil {
+		return false, errors.Wrap(err, "unable to call VRFCoordinator#callbacks")
+	}
+	var callback struct {
+		CallbackContract common.Address
+		RandomnessFee    *big.Int
+		Seed             *big.Int
+	}
+	if err := CoordinatorABI().Unpack(&callback, "callbacks", result); err != nil {
+		return false, errors.Wrap(err, "unable to decode VRFCoordinator#callbacks")
+	}
+	return callback.CallbackContract != common.Address{}, nil
+}
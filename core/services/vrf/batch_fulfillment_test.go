@@ -0,0 +1,42 @@
+package vrf
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchFulfillmentCalldata(t *testing.T) {
+	coordinator := common.HexToAddress("0xecfcab0a285d3380e488a39b4bb21e777f8a4eac")
+	var proofs []MarshaledProof
+	for i := byte(1); i <= 3; i++ {
+		var proof MarshaledProof
+		proof[0], proof[ProofLength-1] = i, i
+		proofs = append(proofs, proof)
+	}
+
+	data, err := BatchFulfillmentCalldata(coordinator, proofs)
+	require.NoError(t, err)
+	method := parsedMulticallABI.Methods["tryAggregate"]
+	assert.Equal(t, method.ID(), data[:4])
+
+	var args struct {
+		RequireSuccess bool
+		Calls          []multicall
+	}
+	require.NoError(t, method.Inputs.Unpack(&args, data[4:]))
+	assert.False(t, args.RequireSuccess)
+	require.Len(t, args.Calls, 3)
+	for i, call := range args.Calls {
+		assert.Equal(t, coordinator, call.Target)
+		expected, err := FulfillmentCalldata(proofs[i])
+		require.NoError(t, err)
+		assert.Equal(t, expected, call.CallData)
+	}
+
+	single, err := FulfillmentCalldata(proofs[0])
+	require.NoError(t, err)
+	assert.Equal(t, FulfillMethod().ID(), single[:4])
+}
@@ -5,10 +5,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/internal/testutils"
 )
 
 func TestMeasureFulfillmenttGasCost(t *testing.T) {
-	coordinator := deployCoordinator(t)
+	coordinator := testutils.DeployVRFUniverse(t)
 	keyHash, _, fee := registerProvingKey(t, coordinator)
 	// Set up a request to fulfill
 	log := requestRandomness(t, coordinator, keyHash, fee, seed)
@@ -18,8 +20,8 @@ func TestMeasureFulfillmenttGasCost(t *testing.T) {
 	proofBlob, err := proof.MarshalForSolidityVerifier()
 	require.NoError(t, err, "could not marshal VRF proof for VRFCoordinator!")
 
-	estimate := estimateGas(t, coordinator.backend, coordinator.neil.From,
-		coordinator.rootContractAddress, coordinator.coordinatorABI,
+	estimate := coordinator.EstimateGas(coordinator.Neil.From,
+		coordinator.RootContractAddress, coordinator.CoordinatorABI,
 		"fulfillRandomnessRequest", proofBlob[:])
 
 	assert.Greater(t, estimate, uint64(145000),
@@ -5,14 +5,16 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/smartcontractkit/chainlink/core/internal/testutils"
 )
 
 func TestMeasureRandomnessRequestGasCost(t *testing.T) {
-	coordinator := deployCoordinator(t)
+	coordinator := testutils.DeployVRFUniverse(t)
 	keyHash_, _, fee := registerProvingKey(t, coordinator)
 
-	estimate := estimateGas(t, coordinator.backend, common.Address{},
-		coordinator.consumerContractAddress, coordinator.consumerABI,
+	estimate := coordinator.EstimateGas(common.Address{},
+		coordinator.ConsumerContractAddress, coordinator.ConsumerABI,
 		"requestRandomness", common.BytesToHash(keyHash_[:]), fee, one)
 
 	assert.Greater(t, estimate, uint64(174000),
@@ -5,6 +5,7 @@ import (
 	"math/big"
 	mrand "math/rand"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -12,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
@@ -29,25 +31,44 @@ import (
 // can help to quickly locate any disparity between the solidity and golang
 // implementations.
 
-// deployVRFContract returns the wrapper of the EVM verifier contract.
+var (
+	verifierOnce      sync.Once
+	sharedVerifier    *solidity_verifier_wrapper.VRFTestHelper
+	sharedVerifierErr error
+)
+
+// vrfTestHelper returns the wrapper of the EVM verifier contract, which
+// is deployed once and shared by all the tests in the package.
+//
+// Sharing the verifier is safe even for parallel tests, because all its
+// methods are pure, and calls with nil CallOpts run against the latest block
+// of the simulated backend, which serializes them.
 //
 // NB: For changes to the VRF solidity code to be reflected here, "go generate"
 // must be run in core/services/vrf.
-//
-// TODO(alx): This suit used to be much faster, presumably because all tests
-// were sharing a common global verifier (which is fine, because all methods are
-// pure.) Revert to that, and see if it helps.
-func deployVRFTestHelper(t *testing.T) *solidity_verifier_wrapper.VRFTestHelper {
+func vrfTestHelper(t *testing.T) *solidity_verifier_wrapper.VRFTestHelper {
+	verifierOnce.Do(func() {
+		sharedVerifier, sharedVerifierErr = newVRFTestHelper()
+	})
+	require.NoError(t, sharedVerifierErr, "failed to deploy VRF contract to simulated blockchain")
+	return sharedVerifier
+}
+
+func newVRFTestHelper() (*solidity_verifier_wrapper.VRFTestHelper, error) {
 	key, err := crypto.GenerateKey()
-	require.NoError(t, err, "failed to create root ethereum identity")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create root ethereum identity")
+	}
 	auth := bind.NewKeyedTransactor(key)
 	genesisData := core.GenesisAlloc{auth.From: {Balance: bi(1000000000)}}
 	gasLimit := eth.DefaultConfig.Miner.GasCeil
 	backend := backends.NewSimulatedBackend(genesisData, gasLimit)
 	_, _, verifier, err := solidity_verifier_wrapper.DeployVRFTestHelper(auth, backend)
-	require.NoError(t, err, "failed to deploy VRF contract to simulated blockchain")
+	if err != nil {
+		return nil, err
+	}
 	backend.Commit()
-	return verifier
+	return verifier, nil
 }
 
 // randomUint256 deterministically simulates a uniform sample of uint256's,
@@ -62,8 +83,11 @@ func randomUint256(t *testing.T, r *mrand.Rand) *big.Int {
 }
 
 // numSamples returns the number of examples which should be checked, in
-// generative tests
+// generative tests. Fewer are checked with -short.
 func numSamples() int {
+	if testing.Short() {
+		return 2
+	}
 	return 10
 }
 
@@ -76,7 +100,7 @@ func TestVRF_CompareProjectiveECAddToVerifier(t *testing.T) {
 		px, py := secp256k1.Coordinates(p)
 		qx, qy := secp256k1.Coordinates(q)
 		actualX, actualY, actualZ := ProjectiveECAdd(p, q)
-		verifier := deployVRFTestHelper(t)
+		verifier := vrfTestHelper(t)
 		expectedX, expectedY, expectedZ, err := verifier.ProjectiveECAdd(
 			nil, px, py, qx, qy)
 		require.NoError(t, err, "failed to compute secp256k1 sum in projective coords")
@@ -92,7 +116,7 @@ func TestVRF_CompareBigModExpToVerifier(t *testing.T) {
 	for j := 0; j < numSamples(); j++ {
 		base := randomUint256(t, r)
 		exponent := randomUint256(t, r)
-		actual, err := deployVRFTestHelper(t).BigModExp(nil, base, exponent)
+		actual, err := vrfTestHelper(t).BigModExp(nil, base, exponent)
 		require.NoError(t, err, "while computing bigmodexp on-chain")
 		expected := exp(base, exponent, fieldSize)
 		assert.Equal(t, expected, actual,
@@ -106,7 +130,7 @@ func TestVRF_CompareSquareRoot(t *testing.T) {
 	r := mrand.New(mrand.NewSource(1))
 	for j := 0; j < numSamples(); j++ {
 		maybeSquare := randomUint256(t, r) // Might not be square; should get same result anyway
-		squareRoot, err := deployVRFTestHelper(t).SquareRoot(nil, maybeSquare)
+		squareRoot, err := vrfTestHelper(t).SquareRoot(nil, maybeSquare)
 		require.NoError(t, err, "failed to compute square root on-chain")
 		golangSquareRoot := SquareRoot(maybeSquare)
 		assert.Equal(t, golangSquareRoot, squareRoot,
@@ -125,7 +149,7 @@ func TestVRF_CompareYSquared(t *testing.T) {
 	r := mrand.New(mrand.NewSource(2))
 	for i := 0; i < numSamples(); i++ {
 		x := randomUint256(t, r)
-		actual, err := deployVRFTestHelper(t).YSquared(nil, x)
+		actual, err := vrfTestHelper(t).YSquared(nil, x)
 		require.NoError(t, err, "failed to compute y² given x, on-chain")
 		assert.Equal(t, YSquared(x), actual,
 			"different answers for y², on-chain vs off-chain")
@@ -139,7 +163,7 @@ func TestVRF_CompareFieldHash(t *testing.T) {
 	for j := 0; j < numSamples(); j++ {
 		_, err := r.Read(msg)
 		require.NoError(t, err, "failed to randomize intended hash message")
-		actual, err := deployVRFTestHelper(t).FieldHash(nil, msg)
+		actual, err := vrfTestHelper(t).FieldHash(nil, msg)
 		require.NoError(t, err, "failed to compute fieldHash on-chain")
 		expected := fieldHash(msg)
 		require.Equal(t, expected, actual,
@@ -171,7 +195,7 @@ func TestVRF_CompareHashToCurve(t *testing.T) {
 		input := randomUint256(t, r)
 		cKey := randomKey(t, r)
 		pubKeyCoords := pair(cKey.X, cKey.Y)
-		actual, err := deployVRFTestHelper(t).HashToCurve(nil, pubKeyCoords, input)
+		actual, err := vrfTestHelper(t).HashToCurve(nil, pubKeyCoords, input)
 		require.NoError(t, err, "failed to compute hashToCurve on-chain")
 		pubKeyPoint := secp256k1.SetCoordinates(cKey.X, cKey.Y)
 		expected, err := HashToCurve(pubKeyPoint, input, func(*big.Int) {})
@@ -223,7 +247,7 @@ func TestVRF_CheckSolidityPointAddition(t *testing.T) {
 		p2 := randomPoint(t, r)
 		p1x, p1y := secp256k1.Coordinates(p1)
 		p2x, p2y := secp256k1.Coordinates(p2)
-		psx, psy, psz, err := deployVRFTestHelper(t).ProjectiveECAdd(
+		psx, psy, psz, err := vrfTestHelper(t).ProjectiveECAdd(
 			nil, p1x, p1y, p2x, p2y)
 		require.NoError(t, err, "failed to compute ProjectiveECAdd, on-chain")
 		apx, apy, apz := ProjectiveECAdd(p1, p2)
@@ -232,7 +256,7 @@ func TestVRF_CheckSolidityPointAddition(t *testing.T) {
 		zInv := i().ModInverse(psz, fieldSize)
 		require.Equal(t, mod(mul(psz, zInv), fieldSize), one,
 			"failed to calculate correct inverse of z ordinate")
-		actualSum, err := deployVRFTestHelper(t).AffineECAdd(
+		actualSum, err := vrfTestHelper(t).AffineECAdd(
 			nil, pair(p1x, p1y), pair(p2x, p2y), zInv)
 		require.NoError(t, err,
 			"failed to deploy VRF contract to simulated blockchain")
@@ -249,12 +273,12 @@ func TestVRF_CheckSolidityECMulVerify(t *testing.T) {
 		pxy := pair(secp256k1.Coordinates(p))
 		s := randomScalar(t, r)
 		product := asPair(point().Mul(s, p))
-		actual, err := deployVRFTestHelper(t).EcmulVerify(nil, pxy, secp256k1.ToInt(s),
+		actual, err := vrfTestHelper(t).EcmulVerify(nil, pxy, secp256k1.ToInt(s),
 			product)
 		require.NoError(t, err, "failed to check on-chain that s*p=product")
 		assert.True(t, actual,
 			"EcmulVerify rejected a valid secp256k1 scalar product relation")
-		shouldReject, err := deployVRFTestHelper(t).EcmulVerify(nil, pxy,
+		shouldReject, err := vrfTestHelper(t).EcmulVerify(nil, pxy,
 			add(secp256k1.ToInt(s), one), product)
 		require.NoError(t, err, "failed to check on-chain that (s+1)*p≠product")
 		assert.False(t, shouldReject,
@@ -272,13 +296,13 @@ func TestVRF_CheckSolidityVerifyLinearCombinationWithGenerator(t *testing.T) {
 		expectedPoint := point().Add(point().Mul(c, p), point().Mul(s, Generator)) // cp+sg
 		expectedAddress := secp256k1.EthereumAddress(expectedPoint)
 		pPair := asPair(p)
-		actual, err := deployVRFTestHelper(t).VerifyLinearCombinationWithGenerator(nil,
+		actual, err := vrfTestHelper(t).VerifyLinearCombinationWithGenerator(nil,
 			secp256k1.ToInt(c), pPair, secp256k1.ToInt(s), expectedAddress)
 		require.NoError(t, err,
 			"failed to check on-chain that secp256k1 linear relationship holds")
 		assert.True(t, actual,
 			"VerifyLinearCombinationWithGenerator rejected a valid secp256k1 linear relationship")
-		shouldReject, err := deployVRFTestHelper(t).VerifyLinearCombinationWithGenerator(nil,
+		shouldReject, err := vrfTestHelper(t).VerifyLinearCombinationWithGenerator(nil,
 			add(secp256k1.ToInt(c), one), pPair, secp256k1.ToInt(s), expectedAddress)
 		require.NoError(t, err,
 			"failed to check on-chain that address((c+1)*p+s*g)≠expectedAddress")
@@ -304,18 +328,18 @@ func TestVRF_CheckSolidityLinearComination(t *testing.T) {
 		expected := asPair(point().Add(cp1, sp2))
 		_, _, z := ProjectiveECAdd(cp1, sp2)
 		zInv := i().ModInverse(z, fieldSize)
-		actual, err := deployVRFTestHelper(t).LinearCombination(nil, cNum, p1Pair,
+		actual, err := vrfTestHelper(t).LinearCombination(nil, cNum, p1Pair,
 			cp1Pair, sNum, p2Pair, sp2Pair, zInv)
 		require.NoError(t, err, "failed to compute c*p1+s*p2, on-chain")
 		assert.Equal(t, expected, actual,
 			"on-chain computation of c*p1+s*p2 gave wrong answer")
-		_, err = deployVRFTestHelper(t).LinearCombination(nil, add(cNum, one),
+		_, err = vrfTestHelper(t).LinearCombination(nil, add(cNum, one),
 			p1Pair, cp1Pair, sNum, p2Pair, sp2Pair, zInv)
 		assert.Error(t, err,
 			"on-chain LinearCombination accepted a bad product relation! ((c+1)*p1)")
 		assert.Contains(t, err.Error(), "First multiplication check failed",
 			"revert message wrong.")
-		_, err = deployVRFTestHelper(t).LinearCombination(nil, cNum, p1Pair,
+		_, err = vrfTestHelper(t).LinearCombination(nil, cNum, p1Pair,
 			cp1Pair, add(sNum, one), p2Pair, sp2Pair, zInv)
 		assert.Error(t, err,
 			"on-chain LinearCombination accepted a bad product relation! ((s+1)*p2)")
@@ -336,7 +360,7 @@ func TestVRF_CompareSolidityScalarFromCurvePoints(t *testing.T) {
 			"failed to randomize uWitness")
 		v, vPair := randomPointWithPair(t, r)
 		expected := ScalarFromCurvePoints(hash, pk, gamma, uWitness, v)
-		actual, err := deployVRFTestHelper(t).ScalarFromCurvePoints(nil, hashPair, pkPair,
+		actual, err := vrfTestHelper(t).ScalarFromCurvePoints(nil, hashPair, pkPair,
 			gammaPair, uWitness, vPair)
 		require.NoError(t, err, "on-chain ScalarFromCurvePoints calculation failed")
 		assert.Equal(t, expected, actual,
@@ -356,7 +380,7 @@ func TestVRF_MarshalProof(t *testing.T) {
 		require.NoError(t, err, "failed to generate VRF proof!")
 		mproof, err := proof.MarshalForSolidityVerifier()
 		require.NoError(t, err, "failed to marshal VRF proof for on-chain verification")
-		response, err := deployVRFTestHelper(t).RandomValueFromVRFProof(nil, mproof[:])
+		response, err := vrfTestHelper(t).RandomValueFromVRFProof(nil, mproof[:])
 		require.NoError(t, err, "failed on-chain to verify VRF proof / get its output")
 		require.True(t, equal(response, proof.Output),
 			"on-chain VRF output differs from off-chain!")
@@ -365,7 +389,7 @@ func TestVRF_MarshalProof(t *testing.T) {
 		inAddressZeroBytes := func(b int64) bool { return b >= 224 && b < 236 }
 		originalByte := mproof[corruptionTargetByte]
 		mproof[corruptionTargetByte] += 1
-		_, err = deployVRFTestHelper(t).RandomValueFromVRFProof(nil, mproof[:])
+		_, err = vrfTestHelper(t).RandomValueFromVRFProof(nil, mproof[:])
 		require.True(t, inAddressZeroBytes(corruptionTargetByte) || err != nil,
 			"VRF verification accepted a bad proof! Changed byte %d from %d to %d in %s, which is of length %d",
 			corruptionTargetByte, originalByte, mproof[corruptionTargetByte],
@@ -0,0 +1,528 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jpillora/backoff"
+	pkgerrors "github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/vrf"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+)
+
+// maxVRFFulfillmentRetryBackoff caps the doubling of the wait between
+// attempts to fulfill a VRF request.
+const maxVRFFulfillmentRetryBackoff = time.Hour
+
+// queueVRFRequest returns a callback for the logs of randomnesslog
+// initiators, which queues their requests to be fulfilled by the
+// VRFRequestQueue once confirmed, rather than running their jobs right away.
+func queueVRFRequest(store *store.Store) func(RunManager, models.LogRequest) {
+	return func(runManager RunManager, le models.LogRequest) {
+		if !le.Validate() {
+			logger.VRF.Debugw("discarding INVALID EVENT LOG", "log", le.GetLog())
+			return
+		}
+		if err := le.ValidateRequester(); err != nil {
+			initiator := le.GetInitiator()
+			if _, e := runManager.CreateErrored(le.GetJobSpecID(), initiator, err); e != nil {
+				logger.VRF.Errorw(e.Error())
+			}
+			logger.VRF.Errorw(err.Error(), le.ForLogger()...)
+			return
+		}
+
+		log := le.GetLog()
+		parsed, err := vrf.ParseRandomnessRequestLog(log)
+		if err != nil {
+			logger.VRF.Errorw("Unable to parse RandomnessRequest log", le.ForLogger("error", err)...)
+			return
+		}
+		requestID := parsed.RequestID()
+
+		if log.Removed {
+			logger.VRF.Debugw("RandomnessRequest log removed", le.ForLogger("requestID", requestID.Hex())...)
+			logger.VRF.ErrorIf(store.RemoveVRFRequest(requestID, log.BlockHash), "failed to remove VRF request")
+			return
+		}
+
+		request, err := models.NewVRFRequest(requestID, le.GetInitiator(), log)
+		if err != nil {
+			logger.VRF.Errorw("Unable to queue VRF request", le.ForLogger("error", err)...)
+			return
+		}
+		if err := store.CreateVRFRequest(request); err != nil {
+			logger.VRF.Errorw("Unable to queue VRF request", le.ForLogger("error", err)...)
+			return
+		}
+		logger.VRF.Debugw("Queued VRF request", le.ForLogger("requestID", requestID.Hex())...)
+	}
+}
+
+// VRFRequestQueue fulfills the queued VRF requests once their logs have
+// VRF_MIN_CONFIRMATIONS confirmations, checking that the request is still in
+// the chain, and retries fulfilling those whose runs error with backoff, up to
+// VRF_MAX_FULFILLMENT_ATTEMPTS times.
+//
+// With VRF_BATCH_MAX_SIZE over 1 and a VRF_BATCH_MULTICALL_ADDRESS, the
+// requests of jobs which only generate a proof and send it to the coordinator
+// are instead fulfilled together, up to VRF_BATCH_MAX_SIZE at a time, by a
+// single transaction through the multicall contract. A batch smaller than
+// that is sent once its oldest request has waited VRF_BATCH_MAX_WAIT.
+type VRFRequestQueue struct {
+	store      *store.Store
+	runManager RunManager
+	worker     SleeperTask
+
+	headMu sync.Mutex
+	head   *big.Int
+
+	// batch holds the confirmed requests waiting to be fulfilled in a batch,
+	// by ID. It is only used by Work.
+	batch map[uint64]*batchedVRFRequest
+}
+
+// batchedVRFRequest is a confirmed request waiting to be fulfilled in a batch.
+type batchedVRFRequest struct {
+	request     models.VRFRequest
+	coordinator common.Address
+	proof       vrf.MarshaledProof
+	// simulate is whether the job's EthTx task opts in to the simulation of
+	// transactions, which is done when the batch is sent.
+	simulate bool
+	readyAt  time.Time
+}
+
+// NewVRFRequestQueue returns a VRFRequestQueue, which processes the queue on
+// each new head.
+func NewVRFRequestQueue(store *store.Store, runManager RunManager) *VRFRequestQueue {
+	q := &VRFRequestQueue{
+		store:      store,
+		runManager: runManager,
+		batch:      make(map[uint64]*batchedVRFRequest),
+	}
+	if store.Config.VRFBatchMaxSize() > 1 && store.Config.VRFBatchMulticallAddress() == nil {
+		logger.VRF.Warn("VRF_BATCH_MAX_SIZE is set without a VRF_BATCH_MULTICALL_ADDRESS, VRF requests will not be batched")
+	}
+	q.worker = NewSleeperTask(q)
+	return q
+}
+
+// Connect processes the requests which became due while disconnected.
+func (q *VRFRequestQueue) Connect(head *models.Head) error {
+	if head != nil {
+		q.OnNewHead(head)
+	}
+	return nil
+}
+
+// Disconnect does nothing, requests waiting until the next head.
+func (q *VRFRequestQueue) Disconnect() {}
+
+// OnNewHead processes the queue in the background.
+func (q *VRFRequestQueue) OnNewHead(head *models.Head) {
+	q.headMu.Lock()
+	q.head = head.ToInt()
+	q.headMu.Unlock()
+	q.worker.WakeUp()
+}
+
+// Stop stops processing the queue.
+func (q *VRFRequestQueue) Stop() error {
+	return q.worker.Stop()
+}
+
+// Work updates the requests being fulfilled with the outcome of their runs or
+// batch transactions, then starts fulfilling the requests which have become
+// due.
+func (q *VRFRequestQueue) Work() {
+	q.headMu.Lock()
+	head := q.head
+	q.headMu.Unlock()
+	if head == nil {
+		return
+	}
+
+	q.checkFulfilling()
+
+	confirmations := uint64(q.store.Config.VRFMinConfirmations())
+	if !head.IsUint64() || head.Uint64() < confirmations {
+		return
+	}
+	ready, err := q.store.VRFRequestsReady(head.Uint64()-confirmations, time.Now())
+	if err != nil {
+		logger.VRF.Errorw("Unable to load VRF requests", "error", err)
+		return
+	}
+
+	batching := q.batching()
+	stillReady := make(map[uint64]bool)
+	for i := range ready {
+		request := &ready[i]
+		if _, batched := q.batch[request.ID]; batched {
+			stillReady[request.ID] = true
+			continue
+		}
+		if !q.inChain(request) {
+			continue
+		}
+		if batching && q.addToBatch(request) {
+			stillReady[request.ID] = true
+			continue
+		}
+		q.fulfill(request)
+	}
+	for id := range q.batch {
+		if !stillReady[id] {
+			delete(q.batch, id)
+		}
+	}
+	if batching {
+		q.sendBatches()
+	}
+}
+
+// batching returns true if confirmed requests are to be fulfilled in batches.
+func (q *VRFRequestQueue) batching() bool {
+	return q.store.Config.VRFBatchMaxSize() > 1 && q.store.Config.VRFBatchMulticallAddress() != nil
+}
+
+// checkFulfilling marks the requests whose runs have finished as fulfilled,
+// or to be retried.
+func (q *VRFRequestQueue) checkFulfilling() {
+	fulfilling, err := q.store.VRFRequestsWithStatus(models.VRFRequestFulfilling)
+	if err != nil {
+		logger.VRF.Errorw("Unable to load VRF requests", "error", err)
+		return
+	}
+	for i := range fulfilling {
+		request := &fulfilling[i]
+		if request.FulfillmentTxHash != nil {
+			continue
+		}
+		if request.JobRunID == nil {
+			q.retry(request, errors.New("fulfilling run missing"))
+			continue
+		}
+		run, err := q.store.FindJobRun(request.JobRunID)
+		if pkgerrors.Cause(err) == orm.ErrorNotFound {
+			q.retry(request, errors.New("fulfilling run deleted"))
+			continue
+		} else if err != nil {
+			logger.VRF.Errorw("Unable to load VRF fulfillment run", "requestID", request.RequestID.Hex(), "error", err)
+			continue
+		}
+
+		switch status := run.GetStatus(); {
+		case status.Completed():
+			request.Status = models.VRFRequestFulfilled
+			request.Error = null.String{}
+			q.save(request)
+			logger.VRF.Infow("Fulfilled VRF request", "requestID", request.RequestID.Hex(), "run_id", run.ID.String())
+		case status.Errored():
+			q.retry(request, fmt.Errorf("fulfilling run errored: %s", run.ErrorString()))
+		case status.Cancelled():
+			q.fail(request, errors.New("fulfilling run cancelled"))
+		}
+	}
+	q.checkBatches(fulfilling)
+}
+
+// checkBatches marks the requests fulfilled in a batch, whose transaction has
+// been confirmed, as fulfilled, or to be retried if the coordinator is still
+// waiting for them.
+func (q *VRFRequestQueue) checkBatches(fulfilling []models.VRFRequest) {
+	batches := make(map[common.Hash][]*models.VRFRequest)
+	var hashes []common.Hash
+	for i := range fulfilling {
+		request := &fulfilling[i]
+		if request.FulfillmentTxHash == nil {
+			continue
+		}
+		hash := *request.FulfillmentTxHash
+		if _, exists := batches[hash]; !exists {
+			hashes = append(hashes, hash)
+		}
+		batches[hash] = append(batches[hash], request)
+	}
+
+	for _, hash := range hashes {
+		_, state, err := q.store.TxManager.BumpGasUntilSafe(hash)
+		if err != nil {
+			logger.VRF.Warnw("Unable to check VRF batch fulfillment", "tx_hash", hash.Hex(), "error", err)
+			continue
+		}
+		if state != store.Safe {
+			continue
+		}
+		for _, request := range batches[hash] {
+			log, err := request.EthLog()
+			if err != nil {
+				q.fail(request, err)
+				continue
+			}
+			pending, err := vrf.RequestPending(q.store.TxManager, log.Address, request.RequestID)
+			if err != nil {
+				logger.VRF.Errorw("Unable to check VRF request was fulfilled", "requestID", request.RequestID.Hex(), "error", err)
+				continue
+			}
+			if pending {
+				q.retry(request, fmt.Errorf("not fulfilled by batch transaction %s", hash.Hex()))
+				continue
+			}
+			request.Status = models.VRFRequestFulfilled
+			request.Error = null.String{}
+			q.save(request)
+			logger.VRF.Infow("Fulfilled VRF request", "requestID", request.RequestID.Hex(), "tx_hash", hash.Hex())
+		}
+	}
+}
+
+// inChain returns true if the request's log is still in the chain, marking
+// it removed otherwise.
+func (q *VRFRequestQueue) inChain(request *models.VRFRequest) bool {
+	receipt, err := q.store.TxManager.GetTxReceipt(request.TxHash)
+	if err != nil {
+		logger.VRF.Errorw("Unable to check VRF request is still in the chain", "requestID", request.RequestID.Hex(), "error", err)
+		return false
+	}
+	if receipt.Unconfirmed() || receipt.BlockHash == nil || *receipt.BlockHash != request.BlockHash {
+		logger.VRF.Warnw("VRF request no longer in the chain, its block was reorged away",
+			"requestID", request.RequestID.Hex(), "blockHash", request.BlockHash.Hex())
+		request.Status = models.VRFRequestRemoved
+		q.save(request)
+		return false
+	}
+	if err := confirmedByQuorum(q.store.TxManager, request.TxHash, request.BlockHash); err != nil {
+		logger.VRF.Warnw("VRF request not confirmed by ETH_QUORUM_URL, waiting for it", "requestID", request.RequestID.Hex(), "error", err)
+		return false
+	}
+	return true
+}
+
+// requestJob returns the job and initiator which received the request.
+func (q *VRFRequestQueue) requestJob(request *models.VRFRequest) (models.JobSpec, *models.Initiator, error) {
+	job, err := q.store.FindJob(request.JobSpecID)
+	if err != nil {
+		return job, nil, pkgerrors.Wrap(err, "unable to load job")
+	}
+	for i := range job.Initiators {
+		if job.Initiators[i].ID == request.InitiatorID {
+			return job, &job.Initiators[i], nil
+		}
+	}
+	return job, nil, errors.New("initiator no longer exists")
+}
+
+// fulfill starts a run of the request's job.
+func (q *VRFRequestQueue) fulfill(request *models.VRFRequest) {
+	_, initr, err := q.requestJob(request)
+	if err != nil {
+		q.fail(request, err)
+		return
+	}
+	log, err := request.EthLog()
+	if err != nil {
+		q.fail(request, err)
+		return
+	}
+	le := models.RandomnessLogEvent{InitiatorLogEvent: models.InitiatorLogEvent{Initiator: *initr, Log: log}}
+	rr, err := le.RunRequest()
+	if err != nil {
+		q.fail(request, err)
+		return
+	}
+
+	request.Attempts++
+	run, err := q.runManager.Create(request.JobSpecID, initr, new(big.Int).SetUint64(request.BlockNumber), &rr)
+	if err != nil {
+		q.retry(request, pkgerrors.Wrap(err, "unable to start fulfilling run"))
+		return
+	}
+	request.Status = models.VRFRequestFulfilling
+	request.JobRunID = run.ID
+	q.save(request)
+	logger.VRF.Debugw("Fulfilling VRF request", "requestID", request.RequestID.Hex(), "run_id", run.ID.String(), "attempt", request.Attempts)
+}
+
+// addToBatch generates the proof fulfilling the request, and adds it to the
+// requests waiting to be fulfilled in a batch. It returns false if the
+// request's job does more than generate a proof and send it to the
+// coordinator, so the request needs to be fulfilled by a run, or if the
+// request failed.
+func (q *VRFRequestQueue) addToBatch(request *models.VRFRequest) bool {
+	job, _, err := q.requestJob(request)
+	if err != nil {
+		q.fail(request, err)
+		return false
+	}
+	if len(job.Tasks) != 2 || job.Tasks[0].Type != adapters.TaskTypeRandom || job.Tasks[1].Type != adapters.TaskTypeEthTx {
+		return false
+	}
+
+	log, err := request.EthLog()
+	if err != nil {
+		q.fail(request, err)
+		return false
+	}
+	parsed, err := vrf.ParseRandomnessRequestLog(log)
+	if err != nil {
+		q.fail(request, err)
+		return false
+	}
+	key, err := vrfkey.NewPublicKeyFromHex(job.Tasks[0].Params.Get("publicKey").String())
+	if err != nil {
+		q.fail(request, pkgerrors.Wrap(err, "bad key for vrf task"))
+		return false
+	}
+	keyHash, err := key.Hash()
+	if err != nil {
+		q.fail(request, pkgerrors.Wrap(err, "bad key for vrf task"))
+		return false
+	} else if keyHash != parsed.KeyHash {
+		q.fail(request, fmt.Errorf("this task's keyHash %x does not match the request's %x", keyHash, parsed.KeyHash))
+		return false
+	}
+	proof, err := q.store.VRFKeyStore.GenerateProof(key, parsed.Seed)
+	if err != nil {
+		q.retry(request, err)
+		return false
+	}
+	q.batch[request.ID] = &batchedVRFRequest{
+		request:     *request,
+		coordinator: log.Address,
+		proof:       proof,
+		simulate:    job.Tasks[1].Params.Get("simulate").Bool(),
+		readyAt:     time.Now(),
+	}
+	return true
+}
+
+// simulate calls the fulfillment of the request against the pending block
+// just before its batch is sent, for the requests of jobs whose EthTx task
+// opts in to the simulation of transactions, failing the request if it would
+// revert, such as when another node has fulfilled it while it was batched.
+// It returns false if the request failed.
+func (q *VRFRequestQueue) simulate(request *models.VRFRequest, coordinator common.Address, proof vrf.MarshaledProof) bool {
+	data, err := vrf.FulfillmentCalldata(proof)
+	if err == nil {
+		err = q.store.TxManager.SimulateTx(coordinator, data, q.store.Config.EthGasLimitDefault())
+	}
+	if revert, ok := err.(*eth.RevertError); ok {
+		q.fail(request, pkgerrors.Wrap(revert, "simulated fulfillment reverted"))
+		return false
+	} else if err != nil {
+		logger.VRF.Warnw("Unable to simulate VRF fulfillment, sending it anyway", "requestID", request.RequestID.Hex(), "error", err)
+	}
+	return true
+}
+
+// sendBatches sends a transaction for each full batch of requests to the
+// same coordinator, and for each smaller batch whose oldest request has
+// waited long enough.
+func (q *VRFRequestQueue) sendBatches() {
+	maxSize := int(q.store.Config.VRFBatchMaxSize())
+	maxWait := q.store.Config.VRFBatchMaxWait().Duration()
+
+	batches := make(map[common.Address][]*batchedVRFRequest)
+	for _, b := range q.batch {
+		batches[b.coordinator] = append(batches[b.coordinator], b)
+	}
+	for coordinator, batch := range batches {
+		sort.Slice(batch, func(i, j int) bool {
+			return batch[i].request.BlockNumber < batch[j].request.BlockNumber
+		})
+		for len(batch) >= maxSize {
+			q.sendBatch(coordinator, batch[:maxSize])
+			batch = batch[maxSize:]
+		}
+		if len(batch) > 0 && time.Since(batch[0].readyAt) >= maxWait {
+			q.sendBatch(coordinator, batch)
+		}
+	}
+}
+
+// sendBatch sends a single transaction fulfilling all of the requests through
+// the multicall contract, with as much gas as a transaction for each, leaving
+// out those whose simulated fulfillment reverted.
+func (q *VRFRequestQueue) sendBatch(coordinator common.Address, batched []*batchedVRFRequest) {
+	var batch []*batchedVRFRequest
+	var proofs []vrf.MarshaledProof
+	for _, b := range batched {
+		delete(q.batch, b.request.ID)
+		if b.simulate && !q.simulate(&b.request, coordinator, b.proof) {
+			continue
+		}
+		batch = append(batch, b)
+		proofs = append(proofs, b.proof)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	data, err := vrf.BatchFulfillmentCalldata(coordinator, proofs)
+	var tx *models.Tx
+	if err == nil {
+		gasLimit := q.store.Config.EthGasLimitDefault() * uint64(len(batch))
+		tx, err = q.store.TxManager.CreateTxWithGas(null.String{}, *q.store.Config.VRFBatchMulticallAddress(), data, nil, gasLimit)
+	}
+	for _, b := range batch {
+		request := b.request
+		request.Attempts++
+		if err != nil {
+			q.retry(&request, pkgerrors.Wrap(err, "unable to send batch fulfillment"))
+			continue
+		}
+		request.Status = models.VRFRequestFulfilling
+		request.FulfillmentTxHash = &tx.Hash
+		q.save(&request)
+	}
+	if err == nil {
+		logger.VRF.Debugw("Fulfilling VRF requests in a batch", "tx_hash", tx.Hash.Hex(), "coordinator", coordinator.Hex(), "requests", len(batch))
+	}
+}
+
+// retry has the request attempted again after a backoff, unless it has been
+// attempted as many times as allowed.
+func (q *VRFRequestQueue) retry(request *models.VRFRequest, err error) {
+	if request.Attempts >= uint32(q.store.Config.VRFMaxFulfillmentAttempts()) {
+		q.fail(request, err)
+		return
+	}
+	b := backoff.Backoff{
+		Min:    q.store.Config.VRFFulfillmentRetryBackoff().Duration(),
+		Max:    maxVRFFulfillmentRetryBackoff,
+		Factor: 2,
+	}
+	wait := b.ForAttempt(float64(request.Attempts - 1))
+	logger.VRF.Warnw("Retrying VRF request", "requestID", request.RequestID.Hex(), "attempts", request.Attempts, "wait", wait, "error", err)
+	request.Status = models.VRFRequestPending
+	request.FulfillmentTxHash = nil
+	request.NextAttemptAt = time.Now().Add(wait)
+	request.Error = null.StringFrom(err.Error())
+	q.save(request)
+}
+
+func (q *VRFRequestQueue) fail(request *models.VRFRequest, err error) {
+	logger.VRF.Errorw("Giving up on VRF request", "requestID", request.RequestID.Hex(), "attempts", request.Attempts, "error", err)
+	request.Status = models.VRFRequestFailed
+	request.Error = null.StringFrom(err.Error())
+	q.save(request)
+}
+
+func (q *VRFRequestQueue) save(request *models.VRFRequest) {
+	logger.VRF.ErrorIf(q.store.SaveVRFRequest(request), "failed to save VRF request")
+}
@@ -0,0 +1,287 @@
+package services_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/services"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func vrfRequestStatus(t *testing.T, store *strpkg.Store, id uint64) func() models.VRFRequestStatus {
+	return func() models.VRFRequestStatus {
+		request, err := store.FindVRFRequest(id)
+		require.NoError(t, err)
+		return request.Status
+	}
+}
+
+func TestVRFRequestQueue_FulfillsConfirmedRequests(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	config.Set("VRF_MIN_CONFIRMATIONS", 6)
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	txm.On("QuorumClient").Return(nil).Maybe()
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+
+	job := cltest.NewJobWithRandomnessLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	request := cltest.CreateVRFRequest(t, store, job, 10)
+	run := cltest.NewJobRun(job)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	q := services.NewVRFRequestQueue(store, runManager)
+	defer q.Stop()
+
+	// Not yet confirmed
+	q.OnNewHead(cltest.Head(15))
+	g.Consistently(vrfRequestStatus(t, store, request.ID)).Should(gomega.Equal(models.VRFRequestPending))
+
+	txm.On("GetTxReceipt", request.TxHash).Return(&eth.TxReceipt{
+		BlockNumber: utils.NewBig(big.NewInt(10)),
+		BlockHash:   &request.BlockHash,
+		Hash:        request.TxHash,
+	}, nil)
+	runManager.On("Create", job.ID, mock.Anything, mock.Anything, mock.MatchedBy(func(rr *models.RunRequest) bool {
+		return *rr.RequestID == request.RequestID
+	})).Return(&run, nil).Once()
+
+	q.OnNewHead(cltest.Head(16))
+	g.Eventually(vrfRequestStatus(t, store, request.ID)).Should(gomega.Equal(models.VRFRequestFulfilling))
+
+	run.SetStatus(models.RunStatusCompleted)
+	require.NoError(t, store.SaveJobRun(&run))
+	q.OnNewHead(cltest.Head(17))
+	g.Eventually(vrfRequestStatus(t, store, request.ID)).Should(gomega.Equal(models.VRFRequestFulfilled))
+
+	found, err := store.FindVRFRequest(request.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), found.Attempts)
+	assert.Equal(t, run.ID, found.JobRunID)
+	runManager.AssertExpectations(t)
+}
+
+func TestVRFRequestQueue_RetriesErroredFulfillments(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	config.Set("VRF_MIN_CONFIRMATIONS", 0)
+	config.Set("VRF_MAX_FULFILLMENT_ATTEMPTS", 2)
+	config.Set("VRF_FULFILLMENT_RETRY_BACKOFF", "10ms")
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	txm.On("QuorumClient").Return(nil).Maybe()
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+
+	job := cltest.NewJobWithRandomnessLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	request := cltest.CreateVRFRequest(t, store, job, 10)
+	txm.On("GetTxReceipt", request.TxHash).Return(&eth.TxReceipt{
+		BlockNumber: utils.NewBig(big.NewInt(10)),
+		BlockHash:   &request.BlockHash,
+		Hash:        request.TxHash,
+	}, nil)
+	runManager.On("Create", job.ID, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(*models.ID, *models.Initiator, *big.Int, *models.RunRequest) *models.JobRun {
+			run := cltest.CreateJobRunWithStatus(t, store, job, models.RunStatusErrored)
+			return &run
+		}, nil)
+
+	q := services.NewVRFRequestQueue(store, runManager)
+	defer q.Stop()
+
+	head := int64(10)
+	g.Eventually(func() models.VRFRequestStatus {
+		head++
+		q.OnNewHead(cltest.Head(head))
+		return vrfRequestStatus(t, store, request.ID)()
+	}).Should(gomega.Equal(models.VRFRequestFailed))
+
+	found, err := store.FindVRFRequest(request.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), found.Attempts)
+	assert.Contains(t, found.Error.ValueOrZero(), "fulfilling run errored")
+	runManager.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestVRFRequestQueue_RemovesReorgedRequests(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	config.Set("VRF_MIN_CONFIRMATIONS", 0)
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	txm.On("QuorumClient").Return(nil).Maybe()
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+
+	job := cltest.NewJobWithRandomnessLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	request := cltest.CreateVRFRequest(t, store, job, 10)
+	otherBlock := cltest.NewHash()
+	txm.On("GetTxReceipt", request.TxHash).Return(&eth.TxReceipt{
+		BlockNumber: utils.NewBig(big.NewInt(11)),
+		BlockHash:   &otherBlock,
+		Hash:        request.TxHash,
+	}, nil)
+
+	q := services.NewVRFRequestQueue(store, runManager)
+	defer q.Stop()
+
+	q.OnNewHead(cltest.Head(11))
+	g.Eventually(vrfRequestStatus(t, store, request.ID)).Should(gomega.Equal(models.VRFRequestRemoved))
+	runManager.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVRFRequestQueue_FulfillsBatches(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	multicall := cltest.NewAddress()
+	config.Set("VRF_MIN_CONFIRMATIONS", 0)
+	config.Set("VRF_BATCH_MAX_SIZE", 2)
+	config.Set("VRF_BATCH_MAX_WAIT", "1h")
+	config.Set("VRF_BATCH_MULTICALL_ADDRESS", multicall.Hex())
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	txm.On("QuorumClient").Return(nil).Maybe()
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+
+	key := vrfkey.NewPrivateKeyXXXTestingOnly(big.NewInt(1))
+	store.VRFKeyStore.StoreInMemoryXXXTestingOnly(key)
+	job := cltest.NewJobWithRandomnessLogInitiator()
+	job.Tasks = []models.TaskSpec{
+		{Type: adapters.TaskTypeRandom, Params: cltest.JSONFromString(t, `{"publicKey": %q}`, key.PublicKey.String())},
+		{Type: adapters.TaskTypeEthTx},
+	}
+	require.NoError(t, store.CreateJob(&job))
+	var requests []*models.VRFRequest
+	for block := uint64(10); block < 13; block++ {
+		requests = append(requests, cltest.CreateVRFRequestWithKeyHash(t, store, job, block, key.PublicKey.MustHash()))
+	}
+
+	txm.On("GetTxReceipt", mock.Anything).Return(func(hash common.Hash) *eth.TxReceipt {
+		for _, r := range requests {
+			if r.TxHash == hash {
+				return &eth.TxReceipt{BlockNumber: utils.NewBig(big.NewInt(10)), BlockHash: &r.BlockHash, Hash: hash}
+			}
+		}
+		return &eth.TxReceipt{}
+	}, nil)
+	batchTx := &models.Tx{Hash: cltest.NewHash()}
+	gasLimit := store.Config.EthGasLimitDefault() * 2
+	txm.On("CreateTxWithGas", mock.Anything, multicall, mock.Anything, mock.Anything, gasLimit).
+		Return(batchTx, nil).Once()
+
+	q := services.NewVRFRequestQueue(store, runManager)
+	defer q.Stop()
+
+	// The two earliest requests are sent in a full batch, the last waits for
+	// another
+	q.OnNewHead(cltest.Head(12))
+	for _, r := range requests[:2] {
+		g.Eventually(vrfRequestStatus(t, store, r.ID)).Should(gomega.Equal(models.VRFRequestFulfilling))
+		found, err := store.FindVRFRequest(r.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found.FulfillmentTxHash)
+		assert.Equal(t, batchTx.Hash, *found.FulfillmentTxHash)
+	}
+
+	txm.On("BumpGasUntilSafe", batchTx.Hash).Return(&eth.TxReceipt{}, strpkg.Safe, nil)
+	txm.On("Call", mock.Anything, "eth_call", mock.Anything, "latest").
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*hexutil.Bytes) = make([]byte, 3*32)
+		}).Return(nil)
+
+	q.OnNewHead(cltest.Head(13))
+	for _, r := range requests[:2] {
+		g.Eventually(vrfRequestStatus(t, store, r.ID)).Should(gomega.Equal(models.VRFRequestFulfilled))
+	}
+	assert.Equal(t, models.VRFRequestPending, vrfRequestStatus(t, store, requests[2].ID)())
+	txm.AssertNumberOfCalls(t, "CreateTxWithGas", 1)
+	runManager.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVRFRequestQueue_SimulatesBatchesWhenSent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	config, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+	multicall := cltest.NewAddress()
+	config.Set("VRF_MIN_CONFIRMATIONS", 0)
+	config.Set("VRF_BATCH_MAX_SIZE", 2)
+	config.Set("VRF_BATCH_MAX_WAIT", "1h")
+	config.Set("VRF_BATCH_MULTICALL_ADDRESS", multicall.Hex())
+	store, cleanup := cltest.NewStoreWithConfig(config)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	txm.On("QuorumClient").Return(nil).Maybe()
+	store.TxManager = txm
+	runManager := new(mocks.RunManager)
+
+	key := vrfkey.NewPrivateKeyXXXTestingOnly(big.NewInt(1))
+	store.VRFKeyStore.StoreInMemoryXXXTestingOnly(key)
+	job := cltest.NewJobWithRandomnessLogInitiator()
+	job.Tasks = []models.TaskSpec{
+		{Type: adapters.TaskTypeRandom, Params: cltest.JSONFromString(t, `{"publicKey": %q}`, key.PublicKey.String())},
+		{Type: adapters.TaskTypeEthTx, Params: cltest.JSONFromString(t, `{"simulate": true}`)},
+	}
+	require.NoError(t, store.CreateJob(&job))
+	var requests []*models.VRFRequest
+	for block := uint64(10); block < 12; block++ {
+		requests = append(requests, cltest.CreateVRFRequestWithKeyHash(t, store, job, block, key.PublicKey.MustHash()))
+	}
+
+	txm.On("GetTxReceipt", mock.Anything).Return(func(hash common.Hash) *eth.TxReceipt {
+		for _, r := range requests {
+			if r.TxHash == hash {
+				return &eth.TxReceipt{BlockNumber: utils.NewBig(big.NewInt(10)), BlockHash: &r.BlockHash, Hash: hash}
+			}
+		}
+		return &eth.TxReceipt{}
+	}, nil)
+	// The earliest request, simulated first, has been fulfilled by another
+	// node while batched, so it is left out of the batch when it is sent
+	txm.On("SimulateTx", mock.Anything, mock.Anything, mock.Anything).
+		Return(&eth.RevertError{Reason: "request already fulfilled"}).Once()
+	txm.On("SimulateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	batchTx := &models.Tx{Hash: cltest.NewHash()}
+	gasLimit := store.Config.EthGasLimitDefault()
+	txm.On("CreateTxWithGas", mock.Anything, multicall, mock.Anything, mock.Anything, gasLimit).
+		Return(batchTx, nil).Once()
+
+	q := services.NewVRFRequestQueue(store, runManager)
+	defer q.Stop()
+
+	q.OnNewHead(cltest.Head(12))
+	g.Eventually(vrfRequestStatus(t, store, requests[0].ID)).Should(gomega.Equal(models.VRFRequestFailed))
+	g.Eventually(vrfRequestStatus(t, store, requests[1].ID)).Should(gomega.Equal(models.VRFRequestFulfilling))
+
+	failed, err := store.FindVRFRequest(requests[0].ID)
+	require.NoError(t, err)
+	assert.Contains(t, failed.Error.String, "simulated fulfillment reverted")
+	txm.AssertNumberOfCalls(t, "SimulateTx", 2)
+	txm.AssertNumberOfCalls(t, "CreateTxWithGas", 1)
+}
@@ -0,0 +1,174 @@
+// Package awskms signs transactions with secp256k1 keys held by AWS KMS. It
+// registers itself as the signer backend for key references of the form
+// "awskms://<key ARN>", and is linked into the node with a blank import.
+package awskms
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// keySpec is the KMS key spec of secp256k1 keys, the only ones which can sign
+// for ethereum accounts.
+const keySpec = "ECC_SECG_P256K1"
+
+func init() {
+	store.RegisterSignerBackend("awskms", NewSigner)
+}
+
+// Credentials are the AWS credentials requests to KMS are signed with.
+type Credentials = utils.AWSCredentials
+
+// NewSigner returns the signer for the KMS key with the given ARN. Requests
+// are signed with the credentials in AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+// and, for temporary credentials, AWS_SESSION_TOKEN, and are sent to the KMS
+// endpoint of the key's region unless AWS_KMS_ENDPOINT is set.
+func NewSigner(arn string) (store.Signer, error) {
+	credentials := Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to use keys held by AWS KMS")
+	}
+	kms, err := newClient(arn, os.Getenv("AWS_KMS_ENDPOINT"), credentials)
+	if err != nil {
+		return nil, err
+	}
+	return store.NewDigestSigner(kms)
+}
+
+// client calls the KMS API for a single key.
+type client struct {
+	arn         string
+	region      string
+	endpoint    *url.URL
+	credentials Credentials
+	http        *http.Client
+	now         func() time.Time
+}
+
+func newClient(arn, endpoint string, credentials Credentials) (*client, error) {
+	// arn:aws:kms:<region>:<account>:key/<key ID>
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "kms" || parts[3] == "" ||
+		!strings.HasPrefix(parts[5], "key/") {
+		return nil, fmt.Errorf("%s is not the ARN of a KMS key", arn)
+	}
+	region := parts[3]
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid AWS_KMS_ENDPOINT")
+	}
+	return &client{
+		arn:         arn,
+		region:      region,
+		endpoint:    u,
+		credentials: credentials,
+		http:        &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+	}, nil
+}
+
+// PublicKey returns the public key of the KMS key, which must be a secp256k1
+// key for signing.
+func (c *client) PublicKey() (*ecdsa.PublicKey, error) {
+	var response struct {
+		KeySpec   string
+		KeyUsage  string
+		PublicKey []byte
+	}
+	if err := c.call("GetPublicKey", map[string]interface{}{"KeyId": c.arn}, &response); err != nil {
+		return nil, err
+	}
+	if response.KeySpec != keySpec || response.KeyUsage != "SIGN_VERIFY" {
+		return nil, fmt.Errorf("KMS key %s is a %s key for %s, not a %s key for SIGN_VERIFY",
+			c.arn, response.KeySpec, response.KeyUsage, keySpec)
+	}
+
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(response.PublicKey, &spki); err != nil {
+		return nil, errors.Wrapf(err, "while parsing public key of KMS key %s", c.arn)
+	} else if len(rest) > 0 {
+		return nil, fmt.Errorf("trailing data after public key of KMS key %s", c.arn)
+	}
+	return crypto.UnmarshalPubkey(spki.PublicKey.Bytes)
+}
+
+// SignDigest returns the DER-encoded ECDSA signature of digest made by the
+// KMS key.
+func (c *client) SignDigest(digest []byte) ([]byte, error) {
+	var response struct {
+		Signature []byte
+	}
+	err := c.call("Sign", map[string]interface{}{
+		"KeyId":            c.arn,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}, &response)
+	return response.Signature, err
+}
+
+// call calls the KMS action with the request, decoding its response into
+// response.
+func (c *client) call(action string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	c.sign(req, body)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "while calling KMS %s", action)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "while reading KMS %s response", action)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var kmsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &kmsErr)
+		return fmt.Errorf("KMS %s failed with status %d: %s %s", action, resp.StatusCode, kmsErr.Type, kmsErr.Message)
+	}
+	return errors.Wrapf(json.Unmarshal(respBody, response), "while parsing KMS %s response", action)
+}
+
+// sign adds the AWS Signature Version 4 of the request to its headers.
+func (c *client) sign(req *http.Request, body []byte) {
+	utils.SignAWSV4(req, body, "kms", c.region, c.credentials, c.now())
+}
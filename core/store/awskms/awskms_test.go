@@ -0,0 +1,97 @@
+package awskms
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/store"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testARN = "arn:aws:kms:eu-west-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+// fakeKMS answers GetPublicKey and Sign like AWS KMS does for a secp256k1
+// key.
+func fakeKMS(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/"), r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/kms/aws4_request")
+		assert.Equal(t, "token", r.Header.Get("X-Amz-Security-Token"))
+
+		var request struct {
+			KeyId   string
+			Message []byte
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, testARN, request.KeyId)
+
+		var response interface{}
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			der, err := asn1.Marshal(struct {
+				Algorithm pkix.AlgorithmIdentifier
+				PublicKey asn1.BitString
+			}{
+				Algorithm: pkix.AlgorithmIdentifier{
+					Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+					Parameters: asn1.RawValue{FullBytes: []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}},
+				},
+				PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 65 * 8},
+			})
+			require.NoError(t, err)
+			response = map[string]interface{}{"KeySpec": keySpec, "KeyUsage": "SIGN_VERIFY", "PublicKey": der}
+		case "TrentService.Sign":
+			r, s, err := ecdsa.Sign(rand.Reader, key, request.Message)
+			require.NoError(t, err)
+			der, err := asn1.Marshal(struct{ R, S interface{} }{r, s})
+			require.NoError(t, err)
+			response = map[string]interface{}{"Signature": der}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			response = map[string]string{"__type": "UnknownOperationException"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+func TestClient_Signer(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	server := fakeKMS(t, key)
+	defer server.Close()
+
+	kms, err := newClient(testARN, server.URL, Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"})
+	require.NoError(t, err)
+	signer, err := store.NewDigestSigner(kms)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+
+	hash := crypto.Keccak256([]byte("transaction"))
+	signature, err := signer.SignHash(hash)
+	require.NoError(t, err)
+	publicKey, err := crypto.SigToPub(hash, signature)
+	require.NoError(t, err)
+	assert.Equal(t, signer.Address(), crypto.PubkeyToAddress(*publicKey))
+}
+
+func TestNewClient_InvalidARN(t *testing.T) {
+	t.Parallel()
+
+	for _, arn := range []string{"", "key/1", "arn:aws:s3:eu-west-1:111122223333:key/1", "arn:aws:kms::111122223333:key/1"} {
+		_, err := newClient(arn, "", Credentials{})
+		assert.Error(t, err, arn)
+	}
+}
@@ -0,0 +1,474 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/gracefulpanic"
+	"github.com/smartcontractkit/chainlink/core/store/migrations"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+)
+
+// BackupVersion is the version of the backup format written by
+// BackupDatabase.
+const BackupVersion = 1
+
+// Backup is a logical export of the state a node cannot be rebuilt without:
+// its runtime configuration, keys, client certificates, bridges, active jobs
+// and unconfirmed transactions. It leaves out the runs, which make up most of
+// the database and can be done without after a disaster.
+//
+// Rows are kept as Postgres writes them with row_to_json, so that no column
+// is lost to the JSON encoding of the models, and the keys and secrets stay
+// encrypted as they are in the database.
+type Backup struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Migration is the last migration run on the database backed up. A backup
+	// can only be restored to a database which has run it.
+	Migration string                    `json:"migration"`
+	Tables    map[string][]BackupRecord `json:"tables"`
+}
+
+// BackupRecord is a row of a table backed up, along with the rows of other
+// tables which belong to it, such as the tasks of a job, by table.
+type BackupRecord struct {
+	Row      json.RawMessage              `json:"row"`
+	Children map[string][]json.RawMessage `json:"children,omitempty"`
+}
+
+// ConflictPolicy is what RestoreDatabase does with a record which the
+// database already has.
+type ConflictPolicy string
+
+const (
+	// ConflictSkip keeps the record the database has.
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces the record the database has with the one
+	// backed up. Jobs and transactions cannot change under the same ID and
+	// hash, so they are skipped.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictFail aborts the restore, leaving the database as it was.
+	ConflictFail ConflictPolicy = "fail"
+)
+
+// ParseConflictPolicy returns the conflict policy of the given name.
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(name); p {
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown conflict policy '%s', expected skip, overwrite or fail", name)
+}
+
+// RestoredRecord describes what RestoreDatabase did with a record of a backup.
+type RestoredRecord struct {
+	Table string `json:"table"`
+	Key   string `json:"key"`
+	// Action is "created", "overwritten" or "skipped".
+	Action string `json:"action"`
+}
+
+// backupTable describes how the rows of a table are backed up and restored.
+type backupTable struct {
+	name string
+	// key is the column which identifies a row across databases, for
+	// detecting conflicts. Tables without one are only restored along with
+	// their parent.
+	key string
+	// serial is set for tables whose id column is a sequence, which is
+	// reassigned on restore.
+	serial bool
+	// where selects the rows backed up, given the id of the parent row for
+	// child tables.
+	where string
+	// refs are the columns which refer to the serial ids of other tables,
+	// and are updated to the ids reassigned on restore.
+	refs map[string]string
+	// nulls are the columns which refer to rows which are not backed up, and
+	// are restored as NULL.
+	nulls []string
+	// overwritable is set for tables whose rows may be replaced on
+	// conflict.
+	overwritable bool
+	children     []backupTable
+}
+
+// backupTables are the tables backed up, in the order they are restored.
+var backupTables = []backupTable{
+	{name: "configurations", key: "name", serial: true, where: "deleted_at IS NULL", overwritable: true},
+	{name: "keys", key: "address", where: "TRUE", overwritable: true},
+	{name: "encrypted_secret_keys", key: "public_key", where: "TRUE", overwritable: true},
+	{name: "encrypted_ocr_key_bundles", key: "id", where: "TRUE", overwritable: true},
+	{name: "client_certificates", key: "name", where: "TRUE", overwritable: true},
+	{name: "bridge_types", key: "name", where: "TRUE", overwritable: true},
+	{name: "external_initiators", key: "name", serial: true, where: "deleted_at IS NULL", overwritable: true},
+	{name: "wasm_modules", key: "name", serial: true, where: "TRUE", overwritable: true},
+	{name: "namespace_tokens", key: "access_key", where: "TRUE", overwritable: true},
+	{name: "secrets", key: "name", serial: true, where: "TRUE", overwritable: true},
+	{name: "run_resumptions", key: "task_run_id", serial: true, where: "TRUE"},
+	{name: "key_fundings", key: "tx_hash", serial: true, where: "TRUE", nulls: []string{"tx_id"}},
+	{name: "job_specs", key: "id", where: "deleted_at IS NULL", children: []backupTable{
+		{name: "initiators", serial: true, where: "CAST(job_spec_id AS uuid) = ? AND deleted_at IS NULL"},
+		{name: "task_specs", serial: true, where: "job_spec_id = ? AND deleted_at IS NULL"},
+		{
+			name:   "task_spec_edges",
+			serial: true,
+			where:  "task_spec_id IN (SELECT id FROM task_specs WHERE job_spec_id = ? AND deleted_at IS NULL)",
+			refs:   map[string]string{"task_spec_id": "task_specs", "input_task_spec_id": "task_specs"},
+		},
+		{name: "job_spec_versions", serial: true, where: "job_spec_id = ?"},
+		{name: "allowed_requesters", serial: true, where: "job_spec_id = ?"},
+		{name: "upkeeps", serial: true, where: "job_spec_id = ?", refs: map[string]string{"initiator_id": "initiators"}},
+		{
+			name:   "upkeep_performs",
+			serial: true,
+			where:  "upkeep_id IN (SELECT id FROM upkeeps WHERE job_spec_id = ?)",
+			refs:   map[string]string{"upkeep_id": "upkeeps"},
+		},
+		{
+			name:   "encumbrances",
+			serial: true,
+			where:  "id IN (SELECT encumbrance_id FROM service_agreements WHERE job_spec_id = ?)",
+		},
+		{name: "service_agreements", key: "id", where: "job_spec_id = ?", refs: map[string]string{"encumbrance_id": "encumbrances"}},
+		{
+			name:   "service_agreement_payments",
+			serial: true,
+			where:  "service_agreement_id IN (SELECT id FROM service_agreements WHERE job_spec_id = ?)",
+		},
+		{name: "job_run_stats", key: "bucket", where: "job_spec_id = ?"},
+	}},
+	{name: "txes", key: "hash", serial: true, where: "NOT confirmed", children: []backupTable{
+		{name: "tx_attempts", serial: true, where: "tx_id = ?", refs: map[string]string{"tx_id": "txes"}},
+	}},
+}
+
+// CreateBackup backs up the database of config. It does not take the
+// advisory lock, so that a node can be backed up while it is running.
+func CreateBackup(config *orm.Config) (*Backup, error) {
+	dialect, err := orm.DeduceDialect(config.DatabaseURL())
+	if err != nil {
+		return nil, err
+	}
+	db, err := gorm.Open(string(dialect), config.DatabaseURL())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open database")
+	}
+	defer db.Close()
+	return BackupDatabase(db)
+}
+
+// RestoreBackup restores backup to the database of config, or with dryRun,
+// reports what it would restore without changing anything. It takes the
+// advisory lock, so the node must be stopped.
+func RestoreBackup(config *orm.Config, backup *Backup, onConflict ConflictPolicy, dryRun bool) ([]RestoredRecord, error) {
+	orm, err := orm.NewORM(config.DatabaseURL(), config.DatabaseTimeout(), gracefulpanic.NewSignal(), config.DatabaseOptions())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open database")
+	}
+	defer orm.Close()
+
+	var restored []RestoredRecord
+	err = orm.RawDB(func(db *gorm.DB) error {
+		restored, err = RestoreDatabase(db, backup, onConflict, dryRun)
+		return err
+	})
+	return restored, err
+}
+
+// BackupDatabase reads the tables backed up in a single read only, repeatable
+// read transaction, so that the backup is consistent however busy the node
+// is.
+func BackupDatabase(db *gorm.DB) (*Backup, error) {
+	status, err := migrations.GetStatus(db)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer tx.Rollback()
+	if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").Error; err != nil {
+		return nil, errors.Wrap(err, "starting backup transaction")
+	}
+
+	backup := &Backup{
+		Version:   BackupVersion,
+		CreatedAt: time.Now(),
+		Migration: status.Current,
+		Tables:    make(map[string][]BackupRecord, len(backupTables)),
+	}
+	for _, table := range backupTables {
+		rows, err := selectBackupRows(tx, table)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]BackupRecord, len(rows))
+		for i, row := range rows {
+			records[i].Row = row
+			if len(table.children) == 0 {
+				continue
+			}
+			id, err := backupColumn(row, "id")
+			if err != nil {
+				return nil, errors.Wrapf(err, "backing up %s", table.name)
+			}
+			records[i].Children = make(map[string][]json.RawMessage, len(table.children))
+			for _, child := range table.children {
+				childRows, err := selectBackupRows(tx, child, id)
+				if err != nil {
+					return nil, err
+				}
+				records[i].Children[child.name] = childRows
+			}
+		}
+		backup.Tables[table.name] = records
+	}
+	return backup, nil
+}
+
+func selectBackupRows(tx *gorm.DB, table backupTable, args ...interface{}) ([]json.RawMessage, error) {
+	order := table.key
+	if table.serial {
+		order = "id"
+	}
+	query := fmt.Sprintf(`SELECT row_to_json(t) FROM %q t WHERE %s ORDER BY %q`, table.name, table.where, order)
+	rows, err := tx.Raw(query, args...).Rows()
+	if err != nil {
+		return nil, errors.Wrapf(err, "backing up %s", table.name)
+	}
+	defer rows.Close()
+
+	result := []json.RawMessage{}
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return nil, errors.Wrapf(err, "backing up %s", table.name)
+		}
+		result = append(result, json.RawMessage(row))
+	}
+	return result, rows.Err()
+}
+
+// RestoreDatabase restores backup in a single transaction, so that a restore
+// which fails leaves the database as it was. Rows with serial ids are given
+// new ones, so that a backup can be restored to a database which has rows of
+// its own.
+func RestoreDatabase(db *gorm.DB, backup *Backup, onConflict ConflictPolicy, dryRun bool) ([]RestoredRecord, error) {
+	if backup.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d, expected %d", backup.Version, BackupVersion)
+	}
+	status, err := migrations.GetStatus(db)
+	if err != nil {
+		return nil, err
+	}
+	if backup.Migration > status.Latest {
+		return nil, fmt.Errorf("backup was taken from a newer version of the database (migration %s)", backup.Migration)
+	}
+	for _, id := range status.Pending {
+		if id == backup.Migration {
+			return nil, fmt.Errorf("database has not run migration %s of the backup, run `chainlink node migrate` first", id)
+		}
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer tx.Rollback()
+
+	restorer := &backupRestorer{tx: tx, onConflict: onConflict, columns: map[string]map[string]bool{}}
+	restored := []RestoredRecord{}
+	for _, table := range backupTables {
+		for _, record := range backup.Tables[table.name] {
+			r, err := restorer.restore(table, record)
+			if err != nil {
+				return nil, err
+			}
+			restored = append(restored, r)
+		}
+	}
+
+	if dryRun {
+		return restored, nil
+	}
+	return restored, tx.Commit().Error
+}
+
+type backupRestorer struct {
+	tx         *gorm.DB
+	onConflict ConflictPolicy
+	// columns are the columns of the tables restored to, so that columns
+	// dropped since the backup was taken are left out.
+	columns map[string]map[string]bool
+}
+
+func (r *backupRestorer) restore(table backupTable, record BackupRecord) (RestoredRecord, error) {
+	key, err := backupColumn(record.Row, table.key)
+	if err != nil {
+		return RestoredRecord{}, errors.Wrapf(err, "restoring %s", table.name)
+	}
+	restored := RestoredRecord{Table: table.name, Key: key, Action: "created"}
+
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %[1]q WHERE %[2]q = (json_populate_record(NULL::%[1]q, ?)).%[2]q)`, table.name, table.key)
+	if err := r.tx.Raw(query, string(record.Row)).Row().Scan(&exists); err != nil {
+		return restored, errors.Wrapf(err, "restoring %s %s", table.name, key)
+	}
+
+	if exists {
+		switch {
+		case r.onConflict == ConflictFail:
+			return restored, fmt.Errorf("%s %s already exists", table.name, key)
+		case r.onConflict == ConflictOverwrite && table.overwritable:
+			restored.Action = "overwritten"
+			return restored, r.overwrite(table, record.Row)
+		default:
+			restored.Action = "skipped"
+			return restored, nil
+		}
+	}
+
+	ids := map[string]map[string]int64{}
+	if err := r.insert(table, record.Row, ids); err != nil {
+		return restored, errors.Wrapf(err, "restoring %s %s", table.name, key)
+	}
+	for _, child := range table.children {
+		for _, row := range record.Children[child.name] {
+			if err := r.insert(child, row, ids); err != nil {
+				return restored, errors.Wrapf(err, "restoring %s of %s %s", child.name, table.name, key)
+			}
+		}
+	}
+	return restored, nil
+}
+
+// insert inserts row into table, giving it a new id if the table is serial
+// and recording it in ids, and updating the columns which refer to the ids
+// of rows inserted before it.
+func (r *backupRestorer) insert(table backupTable, row json.RawMessage, ids map[string]map[string]int64) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(row, &values); err != nil {
+		return err
+	}
+	for column, refTable := range table.refs {
+		id, ok := ids[refTable][string(values[column])]
+		if !ok {
+			return fmt.Errorf("%s refers to a row of %s which is not in the backup", column, refTable)
+		}
+		values[column] = json.RawMessage(fmt.Sprint(id))
+	}
+	for _, column := range table.nulls {
+		values[column] = json.RawMessage("null")
+	}
+	oldID := string(values["id"])
+	if table.serial {
+		delete(values, "id")
+	}
+
+	columns, err := r.restoredColumns(table.name, values)
+	if err != nil {
+		return err
+	}
+	row, err = json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`INSERT INTO %[1]q (%[2]s) SELECT %[2]s FROM json_populate_record(NULL::%[1]q, ?)`, table.name, columns)
+	if !table.serial {
+		return r.tx.Exec(query, string(row)).Error
+	}
+
+	var id int64
+	if err := r.tx.Raw(query+" RETURNING id", string(row)).Row().Scan(&id); err != nil {
+		return err
+	}
+	if ids[table.name] == nil {
+		ids[table.name] = map[string]int64{}
+	}
+	ids[table.name][oldID] = id
+	return nil
+}
+
+// overwrite replaces the row of table which has the key of row.
+func (r *backupRestorer) overwrite(table backupTable, row json.RawMessage) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(row, &values); err != nil {
+		return err
+	}
+	if table.serial {
+		delete(values, "id")
+	}
+	columns, err := r.restoredColumns(table.name, values)
+	if err != nil {
+		return err
+	}
+	row, err = json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`UPDATE %[1]q SET (%[2]s) = (SELECT %[2]s FROM json_populate_record(NULL::%[1]q, ?))
+		WHERE %[3]q = (json_populate_record(NULL::%[1]q, ?)).%[3]q`, table.name, columns, table.key)
+	return r.tx.Exec(query, string(row), string(row)).Error
+}
+
+// restoredColumns returns the quoted columns of values which the table has.
+func (r *backupRestorer) restoredColumns(table string, values map[string]json.RawMessage) (string, error) {
+	existing, ok := r.columns[table]
+	if !ok {
+		rows, err := r.tx.Raw(`SELECT column_name FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ?`, table).Rows()
+		if err != nil {
+			return "", errors.Wrapf(err, "reading columns of %s", table)
+		}
+		defer rows.Close()
+		existing = map[string]bool{}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return "", errors.Wrapf(err, "reading columns of %s", table)
+			}
+			existing[name] = true
+		}
+		if err := rows.Err(); err != nil {
+			return "", errors.Wrapf(err, "reading columns of %s", table)
+		}
+		r.columns[table] = existing
+	}
+
+	var columns []string
+	for name := range values {
+		if existing[name] {
+			columns = append(columns, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("no columns of %s in backup", table)
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// backupColumn returns the value of column in row as text, or an empty string
+// for tables without a key.
+func backupColumn(row json.RawMessage, column string) (string, error) {
+	if column == "" {
+		return "", nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(row, &values); err != nil {
+		return "", err
+	}
+	value, ok := values[column]
+	if !ok {
+		return "", fmt.Errorf("row has no %s", column)
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,14 @@
+package store
+
+// BackedUpTables returns the names of the tables backed up, those backed up
+// along with their parents included.
+func BackedUpTables() []string {
+	var names []string
+	for _, table := range backupTables {
+		names = append(names, table.name)
+		for _, child := range table.children {
+			names = append(names, child.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+)
+
+// BridgeCache holds the responses of bridges with a cache TTL, so that runs
+// sending a bridge the same data within the TTL share a single request.
+type BridgeCache interface {
+	// Get returns the cached response with the given key, if there is one
+	// which has not expired.
+	Get(key string) ([]byte, bool, error)
+	// Set caches a response of the named bridge for ttl.
+	Set(key string, bridgeName models.TaskType, body []byte, ttl time.Duration) error
+}
+
+// NewBridgeCache returns the BridgeCache selected by BRIDGE_CACHE_STORE.
+func NewBridgeCache(config *orm.Config, db *orm.ORM) (BridgeCache, error) {
+	if config.BridgeCacheStore() == orm.BridgeCacheDatabase {
+		return &databaseBridgeCache{orm: db}, nil
+	}
+	return NewMemoryBridgeCache(int(config.BridgeCacheSize()))
+}
+
+type memoryBridgeCache struct {
+	lru *lru.Cache
+}
+
+type memoryBridgeCacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewMemoryBridgeCache returns a BridgeCache keeping the size most recently
+// used responses in memory.
+func NewMemoryBridgeCache(size int) (BridgeCache, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating bridge cache")
+	}
+	return &memoryBridgeCache{lru: cache}, nil
+}
+
+func (c *memoryBridgeCache) Get(key string) ([]byte, bool, error) {
+	value, ok := c.lru.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	entry := value.(memoryBridgeCacheEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		c.lru.Remove(key)
+		return nil, false, nil
+	}
+	return entry.body, true, nil
+}
+
+func (c *memoryBridgeCache) Set(key string, _ models.TaskType, body []byte, ttl time.Duration) error {
+	c.lru.Add(key, memoryBridgeCacheEntry{body: body, expiresAt: time.Now().Add(ttl)})
+	return nil
+}
+
+type databaseBridgeCache struct {
+	orm *orm.ORM
+}
+
+func (c *databaseBridgeCache) Get(key string) ([]byte, bool, error) {
+	br, err := c.orm.FindBridgeResponse(key)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return br.Body, true, nil
+}
+
+func (c *databaseBridgeCache) Set(key string, bridgeName models.TaskType, body []byte, ttl time.Duration) error {
+	return c.orm.SaveBridgeResponse(&models.BridgeResponse{
+		Key:        key,
+		BridgeName: bridgeName,
+		Body:       body,
+		ExpiresAt:  time.Now().Add(ttl),
+	})
+}
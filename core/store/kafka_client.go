@@ -0,0 +1,166 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/multierr"
+)
+
+//go:generate mockery -name KafkaClient -output ../internal/mocks/ -case=underscore
+
+// ErrKafkaNotConfigured is returned when kafka is used without any brokers
+// having been configured through KAFKA_BROKERS.
+var ErrKafkaNotConfigured = errors.New("no kafka brokers configured, set KAFKA_BROKERS")
+
+// KafkaLastOffset can be passed to KafkaClient.Consume to only read messages
+// published from now on.
+const KafkaLastOffset = kafka.LastOffset
+
+// KafkaMessage is a single message read from a partition of a kafka topic.
+type KafkaMessage struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// KafkaClient reads and writes messages to the kafka brokers configured for
+// the node.
+type KafkaClient interface {
+	// Partitions returns the IDs of the partitions of the given topic.
+	Partitions(topic string) ([]int, error)
+	// Consume hands each message of a topic partition, starting at offset, to
+	// handler, until the context is cancelled or handler returns an error.
+	Consume(ctx context.Context, topic string, partition int, offset int64, handler func(KafkaMessage) error) error
+	// Publish writes a message to the given topic.
+	Publish(ctx context.Context, topic string, key, value []byte) error
+	Close() error
+}
+
+type kafkaClient struct {
+	brokers   []string
+	writers   map[string]*kafka.Writer
+	writersMu sync.Mutex
+}
+
+// NewKafkaClient returns a KafkaClient connecting to the given brokers. No
+// connection is made until the client is first used.
+func NewKafkaClient(brokers []string) KafkaClient {
+	return &kafkaClient{
+		brokers: brokers,
+		writers: make(map[string]*kafka.Writer),
+	}
+}
+
+func (kc *kafkaClient) Partitions(topic string) ([]int, error) {
+	if len(kc.brokers) == 0 {
+		return nil, ErrKafkaNotConfigured
+	}
+
+	var merr error
+	for _, broker := range kc.brokers {
+		conn, err := kafka.Dial("tcp", broker)
+		if err != nil {
+			merr = multierr.Append(merr, err)
+			continue
+		}
+		partitions, err := conn.ReadPartitions(topic)
+		conn.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading partitions of kafka topic %s", topic)
+		}
+
+		ids := make([]int, len(partitions))
+		for i, p := range partitions {
+			ids[i] = p.ID
+		}
+		sort.Ints(ids)
+		return ids, nil
+	}
+	return nil, errors.Wrap(merr, "unable to connect to any kafka broker")
+}
+
+func (kc *kafkaClient) Consume(
+	ctx context.Context,
+	topic string,
+	partition int,
+	offset int64,
+	handler func(KafkaMessage) error,
+) error {
+	if len(kc.brokers) == 0 {
+		return ErrKafkaNotConfigured
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   kc.brokers,
+		Topic:     topic,
+		Partition: partition,
+		MinBytes:  1,
+		MaxBytes:  10e6,
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(offset); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return err
+		}
+		err = handler(KafkaMessage{
+			Topic:     msg.Topic,
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			Key:       msg.Key,
+			Value:     msg.Value,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (kc *kafkaClient) Publish(ctx context.Context, topic string, key, value []byte) error {
+	if len(kc.brokers) == 0 {
+		return ErrKafkaNotConfigured
+	}
+	return kc.writer(topic).WriteMessages(ctx, kafka.Message{Key: key, Value: value})
+}
+
+// writer returns the writer for a topic, creating it on first use so that
+// batching and connections are shared between publishes.
+func (kc *kafkaClient) writer(topic string) *kafka.Writer {
+	kc.writersMu.Lock()
+	defer kc.writersMu.Unlock()
+
+	w, ok := kc.writers[topic]
+	if !ok {
+		w = kafka.NewWriter(kafka.WriterConfig{
+			Brokers:      kc.brokers,
+			Topic:        topic,
+			BatchTimeout: 10 * time.Millisecond,
+		})
+		kc.writers[topic] = w
+	}
+	return w
+}
+
+func (kc *kafkaClient) Close() error {
+	kc.writersMu.Lock()
+	defer kc.writersMu.Unlock()
+
+	var merr error
+	for topic, w := range kc.writers {
+		merr = multierr.Append(merr, w.Close())
+		delete(kc.writers, topic)
+	}
+	return merr
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+)
+
+// keyBundleVersion is the version of the key bundle format written by
+// ExportKeys.
+const keyBundleVersion = 1
+
+// ErrKeyBundleDecrypt is returned when importing a key bundle which was
+// exported with a different password.
+var ErrKeyBundleDecrypt = errors.New("could not decrypt key bundle; was it exported with another password?")
+
+// EncryptedKeyBundle holds all the keys of a node, encrypted with the node's
+// password, for moving them to another node.
+type EncryptedKeyBundle struct {
+	Version int                 `json:"version"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+}
+
+// keyBundle is the plaintext of an EncryptedKeyBundle. The keys in it are
+// still encrypted as the node keeps them: the ethereum keys with the node's
+// password, the VRF keys with the VRF password.
+type keyBundle struct {
+	EthKeys []json.RawMessage            `json:"ethKeys"`
+	VRFKeys []*vrfkey.EncryptedSecretKey `json:"vrfKeys"`
+}
+
+// ImportedKey describes a key found in a key bundle.
+type ImportedKey struct {
+	Type string // "eth" or "vrf"
+	ID   string // The address of an ethereum key, or the public key of a VRF key
+	// Exists is set when the node already has the key, so it was skipped.
+	Exists bool
+}
+
+// ExportKeys returns the ethereum and VRF keys of the node, encrypted with
+// password, which must be the node's password. Retired ethereum keys are left
+// out, so that they are not used again by the node importing the bundle, as
+// are keys held by a KMS or HSM, which the node has no key material for.
+func (s *Store) ExportKeys(password string) (*EncryptedKeyBundle, error) {
+	var bundle keyBundle
+	keys, err := s.ORM.Keys()
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading ethereum keys")
+	}
+	for _, key := range keys {
+		if key.RetiredAt.Valid || key.KeyRef.Valid {
+			continue
+		}
+		bundle.EthKeys = append(bundle.EthKeys, json.RawMessage(key.JSON.Raw))
+	}
+	bundle.VRFKeys, err = s.VRFKeyStore.Get(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading VRF keys")
+	}
+
+	plain, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshaling keys")
+	}
+	cryptoJSON, err := keystore.EncryptDataV3(plain, []byte(password), s.KeyStore.scryptN, s.KeyStore.scryptP)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt key bundle")
+	}
+	return &EncryptedKeyBundle{Version: keyBundleVersion, Crypto: cryptoJSON}, nil
+}
+
+// ImportKeys adds the keys in the bundle, decrypted with password, to the
+// node, skipping those the node already has. Every ethereum key in the bundle
+// must be encrypted with password, the node's password, for the node to unlock
+// it; the imported keys are used for transactions right away. The VRF keys are
+// unlocked the next time the node starts with their password.
+//
+// If dryRun is set, the bundle is only checked, and nothing is imported.
+func (s *Store) ImportKeys(encrypted *EncryptedKeyBundle, password string, dryRun bool) ([]ImportedKey, error) {
+	if encrypted.Version != keyBundleVersion {
+		return nil, errors.Errorf("unsupported key bundle version %d", encrypted.Version)
+	}
+	plain, err := keystore.DecryptDataV3(encrypted.Crypto, password)
+	if err == keystore.ErrDecrypt {
+		return nil, ErrKeyBundleDecrypt
+	} else if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt key bundle")
+	}
+	var bundle keyBundle
+	if err := json.Unmarshal(plain, &bundle); err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling key bundle")
+	}
+
+	var imported []ImportedKey
+	for _, keyJSON := range bundle.EthKeys {
+		key, err := keystore.DecryptKey(keyJSON, password)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decrypt ethereum key with the node's password")
+		}
+		imported = append(imported, ImportedKey{
+			Type:   "eth",
+			ID:     key.Address.Hex(),
+			Exists: s.KeyStore.HasAddress(key.Address),
+		})
+	}
+	for _, key := range bundle.VRFKeys {
+		extant, err := s.VRFKeyStore.Get(&key.PublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while checking for VRF key %s", key.PublicKey.String())
+		}
+		imported = append(imported, ImportedKey{
+			Type:   "vrf",
+			ID:     key.PublicKey.String(),
+			Exists: len(extant) > 0,
+		})
+	}
+	if dryRun {
+		return imported, nil
+	}
+
+	for i, keyJSON := range bundle.EthKeys {
+		if imported[i].Exists {
+			continue
+		}
+		account, err := s.KeyStore.Import(keyJSON, password, password)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while importing ethereum key %s", imported[i].ID)
+		}
+		if err := s.KeyStore.KeyStore.Unlock(account, password); err != nil {
+			return nil, errors.Wrapf(err, "while unlocking ethereum key %s", imported[i].ID)
+		}
+		if err := s.SyncDiskKeyStoreToDB(); err != nil {
+			return nil, errors.Wrapf(err, "while saving ethereum key %s", imported[i].ID)
+		}
+		if err := s.TxManager.ActivateAccount(account); err != nil {
+			return nil, errors.Wrapf(err, "while activating ethereum key %s", imported[i].ID)
+		}
+	}
+	for i, key := range bundle.VRFKeys {
+		if imported[len(bundle.EthKeys)+i].Exists {
+			continue
+		}
+		if err := s.FirstOrCreateEncryptedSecretVRFKey(key); err != nil {
+			return nil, errors.Wrapf(err, "while saving VRF key %s", key.PublicKey.String())
+		}
+	}
+	return imported, nil
+}
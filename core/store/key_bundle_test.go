@@ -0,0 +1,58 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_ImportKeys(t *testing.T) {
+	t.Parallel()
+
+	exporter, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	account, err := exporter.KeyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, exporter.SyncDiskKeyStoreToDB())
+	bundle, err := exporter.ExportKeys(cltest.Password)
+	require.NoError(t, err)
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	txm := new(mocks.TxManager)
+	store.TxManager = txm
+	txm.On("ActivateAccount", mock.MatchedBy(func(a accounts.Account) bool {
+		return a.Address == account.Address
+	})).Return(nil).Once()
+
+	_, err = store.ImportKeys(bundle, "wrong password", false)
+	assert.Equal(t, strpkg.ErrKeyBundleDecrypt, err)
+
+	want := []strpkg.ImportedKey{{Type: "eth", ID: account.Address.Hex()}}
+	imported, err := store.ImportKeys(bundle, cltest.Password, true)
+	require.NoError(t, err)
+	assert.Equal(t, want, imported)
+	assert.False(t, store.KeyStore.HasAddress(account.Address), "a dry run must not import keys")
+
+	imported, err = store.ImportKeys(bundle, cltest.Password, false)
+	require.NoError(t, err)
+	assert.Equal(t, want, imported)
+	assert.True(t, store.KeyStore.HasAddress(account.Address))
+	keys, err := store.Keys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, account.Address, keys[0].Address.Address())
+
+	// Keys the node has are skipped
+	imported, err = store.ImportKeys(bundle, cltest.Password, false)
+	require.NoError(t, err)
+	assert.Equal(t, []strpkg.ImportedKey{{Type: "eth", ID: account.Address.Hex(), Exists: true}}, imported)
+	txm.AssertExpectations(t)
+}
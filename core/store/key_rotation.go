@@ -0,0 +1,166 @@
+package store
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+)
+
+var (
+	// ErrKeyNotFound is returned when rotating a key the node does not have.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrKeyRetired is returned when rotating a key which is already retired.
+	ErrKeyRetired = errors.New("key has already been retired")
+)
+
+// KeyRotation is the outcome of rotating an ethereum key.
+type KeyRotation struct {
+	Retired     common.Address
+	Replacement common.Address
+	// Sweeps are the transactions sending the funds of the retired key to
+	// its replacement, if asked for.
+	Sweeps []models.Tx
+}
+
+// RotateKey replaces the ethereum key with the given address by a new key,
+// encrypted with password, which is used for new transactions right away.
+//
+// The old key is retired: the node no longer sends new transactions from it,
+// but keeps bumping and confirming those it has already sent, which are
+// signed and cannot move to another key. If sweep is set, its LINK, then its
+// ETH, are sent to the new key, keeping back enough ETH to pay for its
+// pending transactions and the sweeps at EthMaxGasPriceWei.
+//
+// If sweeping fails, the key is still rotated, and the rotation is returned
+// along with the error.
+func (s *Store) RotateKey(address common.Address, password string, sweep bool) (*KeyRotation, error) {
+	keys, err := s.ORM.Keys()
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading keys")
+	}
+	var key *models.Key
+	for _, k := range keys {
+		if k.Address.Address() == address {
+			key = k
+		}
+	}
+	if key == nil {
+		return nil, ErrKeyNotFound
+	} else if key.RetiredAt.Valid {
+		return nil, ErrKeyRetired
+	}
+
+	account, err := s.KeyStore.NewAccount(password)
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating replacement key")
+	}
+	if err := s.SyncDiskKeyStoreToDB(); err != nil {
+		return nil, errors.Wrap(err, "while saving replacement key")
+	}
+	if err := s.TxManager.ActivateAccount(account); err != nil {
+		return nil, errors.Wrap(err, "while activating replacement key")
+	}
+	if err := s.ORM.RetireKey(address); err != nil {
+		return nil, errors.Wrapf(err, "while retiring key %s", address.Hex())
+	}
+	s.TxManager.RetireAccount(address)
+
+	rotation := &KeyRotation{Retired: address, Replacement: account.Address}
+	if sweep {
+		rotation.Sweeps, err = s.sweepKey(address, account.Address)
+		if err != nil {
+			return rotation, errors.Wrapf(err, "key rotated, but failed to sweep funds to %s", account.Address.Hex())
+		}
+	}
+	return rotation, nil
+}
+
+// sweepKey sends the LINK and spare ETH of the account at from to the account
+// at to.
+func (s *Store) sweepKey(from, to common.Address) ([]models.Tx, error) {
+	var sweeps []models.Tx
+
+	link, err := s.TxManager.GetLINKBalance(from)
+	if err != nil {
+		return sweeps, errors.Wrap(err, "while getting LINK balance")
+	}
+	if link.ToInt().Sign() > 0 {
+		data, err := linkTransferData(to, link.ToInt())
+		if err != nil {
+			return sweeps, err
+		}
+		linkAddress := common.HexToAddress(s.Config.LinkContractAddress())
+		tx, err := s.TxManager.CreateTxFrom(from, linkAddress, data)
+		if err != nil {
+			return sweeps, errors.Wrap(err, "while sweeping LINK")
+		}
+		sweeps = append(sweeps, *tx)
+	}
+
+	balance, err := s.TxManager.GetEthBalance(from)
+	if err != nil {
+		return sweeps, errors.Wrap(err, "while getting ETH balance")
+	}
+	reserved, err := s.reservedEth(from, len(sweeps)+1)
+	if err != nil {
+		return sweeps, err
+	}
+	value := new(big.Int).Sub(balance.ToInt(), reserved)
+	if value.Sign() > 0 {
+		tx, err := s.TxManager.CreateTxWithEth(from, to, (*assets.Eth)(value))
+		if err != nil {
+			return sweeps, errors.Wrap(err, "while sweeping ETH")
+		}
+		sweeps = append(sweeps, *tx)
+	}
+	return sweeps, nil
+}
+
+// reservedEth returns the ETH which the account at from must keep to pay for
+// its unconfirmed transactions, and for sweeps more, at the highest gas price
+// the node would bump them to.
+func (s *Store) reservedEth(from common.Address, sweeps int) (*big.Int, error) {
+	maxGasPrice := s.Config.EthMaxGasPriceWei()
+	gasLimit := new(big.Int).SetUint64(s.Config.EthGasLimitDefault())
+	reserved := new(big.Int).Mul(gasLimit, big.NewInt(int64(sweeps)))
+	reserved.Mul(reserved, maxGasPrice)
+
+	attempts, err := s.ORM.UnconfirmedTxAttempts()
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading unconfirmed transactions")
+	}
+	for _, attempt := range models.HighestPricedTxAttemptPerTx(attempts) {
+		tx := attempt.Tx
+		if tx.From != from {
+			continue
+		}
+		cost := new(big.Int).SetUint64(tx.GasLimit)
+		cost.Mul(cost, maxGasPrice)
+		if tx.Value != nil {
+			cost.Add(cost, tx.Value.ToInt())
+		}
+		reserved.Add(reserved, cost)
+	}
+	return reserved, nil
+}
+
+// linkTransferData returns the call to the LINK token transferring amount to
+// the account at to.
+func linkTransferData(to common.Address, amount *big.Int) ([]byte, error) {
+	encodedAmount, err := utils.EVMWordBigInt(amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "while encoding LINK amount")
+	}
+	functionSelector := eth.HexToFunctionSelector("0xa9059cbb") // transfer(address,uint256)
+	return utils.ConcatBytes(
+		functionSelector.Bytes(),
+		common.LeftPadBytes(to.Bytes(), utils.EVMWordByteLen),
+		encodedAmount,
+	), nil
+}
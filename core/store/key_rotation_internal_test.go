@@ -0,0 +1,13 @@
+package store
+
+import (
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SweepKey sends the LINK and spare ETH of the account at from to the
+// account at to, as rotating the key with a sweep does.
+func (s *Store) SweepKey(from, to common.Address) ([]models.Tx, error) {
+	return s.sweepKey(from, to)
+}
@@ -0,0 +1,66 @@
+package store_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_SweepKey(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	store.Config.Set("ETH_MAX_GAS_PRICE_WEI", 10)
+	store.Config.Set("ETH_GAS_LIMIT_DEFAULT", 100)
+	from := cltest.NewAddress()
+	to := common.HexToAddress("0x3cb8e3FD9d27e39a5e9e6852b0e96160061fd4ea")
+	linkAddress := common.HexToAddress(store.Config.LinkContractAddress())
+
+	// The pending transaction of from, of 250000 gas, keeps back 2500000 wei
+	// at the maximum gas price, and each sweep 1000 more
+	cltest.CreateTx(t, store, from, 1)
+
+	txm := new(mocks.TxManager)
+	store.TxManager = txm
+	txm.On("GetLINKBalance", from).Return(assets.NewLink(10), nil).Once()
+	txm.On("GetEthBalance", from).Return(assets.NewEth(2503000), nil).Once()
+	linkSweep := cltest.NewTx(from, 1)
+	txm.On("CreateTxFrom", from, linkAddress, hexutil.MustDecode("0x"+
+		"a9059cbb"+ // transfer(address,uint256)
+		"0000000000000000000000003cb8e3fd9d27e39a5e9e6852b0e96160061fd4ea"+ // to
+		"000000000000000000000000000000000000000000000000000000000000000a"), // amount
+	).Return(linkSweep, nil).Once()
+	ethSweep := cltest.NewTx(from, 1)
+	txm.On("CreateTxWithEth", from, to, mock.MatchedBy(func(value *assets.Eth) bool {
+		return value.ToInt().Cmp(big.NewInt(1000)) == 0
+	})).Return(ethSweep, nil).Once()
+
+	sweeps, err := store.SweepKey(from, to)
+	require.NoError(t, err)
+	require.Len(t, sweeps, 2)
+	assert.Equal(t, linkSweep.Hash, sweeps[0].Hash)
+	assert.Equal(t, ethSweep.Hash, sweeps[1].Hash)
+	txm.AssertExpectations(t)
+
+	// Without LINK, and with no more ETH than it keeps back for its pending
+	// transaction and a sweep, nothing is sent
+	txm.On("GetLINKBalance", from).Return(assets.NewLink(0), nil).Once()
+	txm.On("GetEthBalance", from).Return(assets.NewEth(2501000), nil).Once()
+
+	sweeps, err = store.SweepKey(from, to)
+	require.NoError(t, err)
+	assert.Empty(t, sweeps)
+	txm.AssertExpectations(t)
+	txm.AssertNumberOfCalls(t, "CreateTxFrom", 1)
+	txm.AssertNumberOfCalls(t, "CreateTxWithEth", 1)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store/models"
@@ -21,22 +22,64 @@ import (
 // For more information, see: https://github.com/ethereum/go-ethereum/issues/3731
 const EthereumMessageHashPrefix = "\x19Ethereum Signed Message:\n32"
 
-// KeyStore manages a key storage directory on disk.
+// KeyStore manages a key storage directory on disk, and the signers of the
+// accounts whose keys are held elsewhere.
 type KeyStore struct {
 	*keystore.KeyStore
+	scryptN, scryptP int
+
+	signers   map[common.Address]Signer
+	signersMu sync.RWMutex
 }
 
 // NewKeyStore creates a keystore for the given directory.
 func NewKeyStore(keyDir string) *KeyStore {
 	ks := keystore.NewKeyStore(keyDir, keystore.StandardScryptN, keystore.StandardScryptP)
-	return &KeyStore{ks}
+	return &KeyStore{
+		KeyStore: ks,
+		scryptN:  keystore.StandardScryptN,
+		scryptP:  keystore.StandardScryptP,
+		signers:  make(map[common.Address]Signer),
+	}
 }
 
 // NewInsecureKeyStore creates an *INSECURE* keystore for the given directory.
 // NOTE: Should only be used for testing!
 func NewInsecureKeyStore(keyDir string) *KeyStore {
 	ks := keystore.NewKeyStore(keyDir, keystore.LightScryptN, keystore.LightScryptP)
-	return &KeyStore{ks}
+	return &KeyStore{
+		KeyStore: ks,
+		scryptN:  keystore.LightScryptN,
+		scryptP:  keystore.LightScryptP,
+		signers:  make(map[common.Address]Signer),
+	}
+}
+
+// AddSigner adds the account of signer, whose key is held outside the
+// keystore, to the accounts of the keystore.
+func (ks *KeyStore) AddSigner(signer Signer) {
+	ks.signersMu.Lock()
+	defer ks.signersMu.Unlock()
+	ks.signers[signer.Address()] = signer
+}
+
+func (ks *KeyStore) signer(address common.Address) (Signer, bool) {
+	ks.signersMu.RLock()
+	defer ks.signersMu.RUnlock()
+	signer, ok := ks.signers[address]
+	return signer, ok
+}
+
+// Accounts returns the accounts of the keys in the keystore directory, followed
+// by those of the signers.
+func (ks *KeyStore) Accounts() []accounts.Account {
+	accts := ks.KeyStore.Accounts()
+	ks.signersMu.RLock()
+	defer ks.signersMu.RUnlock()
+	for address := range ks.signers {
+		accts = append(accts, accounts.Account{Address: address})
+	}
+	return accts
 }
 
 // HasAccounts returns true if there are accounts located at the keystore
@@ -49,7 +92,7 @@ func (ks *KeyStore) HasAccounts() bool {
 // keystore directory.
 func (ks *KeyStore) Unlock(phrase string) error {
 	var merr error
-	for _, account := range ks.Accounts() {
+	for _, account := range ks.KeyStore.Accounts() {
 		err := ks.KeyStore.Unlock(account, phrase)
 		if err != nil {
 			merr = multierr.Combine(merr, fmt.Errorf("invalid password for account %s", account.Address.Hex()), err)
@@ -60,6 +103,22 @@ func (ks *KeyStore) Unlock(phrase string) error {
 	return merr
 }
 
+// CheckUnlocked returns an error if there are no accounts, or if any of the
+// accounts in the keystore directory is still locked. The accounts of signers
+// hold their keys elsewhere and are never locked.
+func (ks *KeyStore) CheckUnlocked() error {
+	if !ks.HasAccounts() {
+		return errors.New("No Ethereum Accounts configured")
+	}
+	var merr error
+	for _, account := range ks.KeyStore.Accounts() {
+		if _, err := ks.KeyStore.SignHash(account, common.Hash{}.Bytes()); err != nil {
+			merr = multierr.Append(merr, fmt.Errorf("account %s is locked", account.Address.Hex()))
+		}
+	}
+	return merr
+}
+
 // NewAccount adds an account to the keystore
 func (ks *KeyStore) NewAccount(passphrase string) (accounts.Account, error) {
 	account, err := ks.KeyStore.NewAccount(passphrase)
@@ -77,7 +136,20 @@ func (ks *KeyStore) NewAccount(passphrase string) (accounts.Account, error) {
 
 // SignTx uses the unlocked account to sign the given transaction.
 func (ks *KeyStore) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
-	return ks.KeyStore.SignTx(account, tx, chainID)
+	signer, ok := ks.signer(account.Address)
+	if !ok {
+		return ks.KeyStore.SignTx(account, tx, chainID)
+	}
+	var txSigner types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		txSigner = types.NewEIP155Signer(chainID)
+	}
+	hash := txSigner.Hash(tx)
+	signature, err := signer.SignHash(hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(txSigner, signature)
 }
 
 // SignHash signs a precomputed digest, using the first account's private key
@@ -97,6 +169,17 @@ func (ks *KeyStore) SignHash(hash common.Hash) (models.Signature, error) {
 	return signature, nil
 }
 
+// SignTypedData signs EIP-712 typed data, given the hashes of its domain and
+// of its message, using the first account's private key. The digest signed
+// begins with \x19\x01, so that it cannot be that of an Ethereum transaction.
+func (ks *KeyStore) SignTypedData(domainSeparator, messageHash common.Hash) (models.Signature, error) {
+	digest, err := utils.Keccak256(utils.ConcatBytes([]byte("\x19\x01"), domainSeparator.Bytes(), messageHash.Bytes()))
+	if err != nil {
+		return models.Signature{}, err
+	}
+	return ks.unsafeSignHash(common.BytesToHash(digest))
+}
+
 // unsafeSignHash signs a precomputed digest, using the first account's private
 // key
 // NOTE: Do not use this method to sign arbitrary message hashes, it may be an
@@ -107,7 +190,12 @@ func (ks *KeyStore) unsafeSignHash(hash common.Hash) (models.Signature, error) {
 	if err != nil {
 		return models.Signature{}, err
 	}
-	output, err := ks.KeyStore.SignHash(account, hash.Bytes())
+	var output []byte
+	if signer, ok := ks.signer(account.Address); ok {
+		output, err = signer.SignHash(hash.Bytes())
+	} else {
+		output, err = ks.KeyStore.SignHash(account, hash.Bytes())
+	}
 	if err != nil {
 		return models.Signature{}, err
 	}
@@ -45,12 +45,518 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1587580235"
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1587975059"
 	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1588293486"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1588757164"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1588853064"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1588940000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589020000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589110000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589200000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589290000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589380000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589470000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589560000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589650000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589740000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589830000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1589920000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590010000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590100000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590190000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590280000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590370000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590460000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590550000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590640000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590730000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590820000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1590910000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591000000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591090000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591180000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591270000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591360000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591450000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591540000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591630000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591720000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591810000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591900000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1591990000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592080000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592170000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592260000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592350000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592440000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592530000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592620000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592710000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592800000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592810000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592820000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592830000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592840000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592850000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592860000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592870000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592880000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592890000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592900000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592910000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592920000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592930000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592940000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592950000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592960000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592970000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592980000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592990000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1593000000"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1593010000"
 
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	gormigrate "gopkg.in/gormigrate.v1"
 )
 
+// all are the migrations of the database, in the order they are run.
+var all = []*gormigrate.Migration{
+	{
+		ID:      "0",
+		Migrate: migration0.Migrate,
+	},
+	{
+		ID:      "1559081901",
+		Migrate: migration1559081901.Migrate,
+	},
+	{
+		ID:      "1559767166",
+		Migrate: migration1559767166.Migrate,
+	},
+	{
+		ID:      "1560433987",
+		Migrate: migration1560433987.Migrate,
+	},
+	{
+		ID:      "1560791143",
+		Migrate: migration1560791143.Migrate,
+	},
+	{
+		ID:      "1560881846",
+		Migrate: migration1560881846.Migrate,
+	},
+	{
+		ID:      "1560886530",
+		Migrate: migration1560886530.Migrate,
+	},
+	{
+		ID:      "1560924400",
+		Migrate: migration1560924400.Migrate,
+	},
+	{
+		ID:      "1560881855",
+		Migrate: migration1560881855.Migrate,
+	},
+	{
+		ID:      "1565139192",
+		Migrate: migration1565139192.Migrate,
+	},
+	{
+		ID:      "1564007745",
+		Migrate: migration1564007745.Migrate,
+	},
+	{
+		ID:      "1565210496",
+		Migrate: migration1565210496.Migrate,
+	},
+	{
+		ID:      "1566498796",
+		Migrate: migration1566498796.Migrate,
+	},
+	{
+		ID:      "1565877314",
+		Migrate: migration1565877314.Migrate,
+	},
+	{
+		ID:      "1566915476",
+		Migrate: migration1566915476.Migrate,
+	},
+	{
+		ID:      "1567029116",
+		Migrate: migration1567029116.Migrate,
+	},
+	{
+		ID:      "1568280052",
+		Migrate: migration1568280052.Migrate,
+	},
+	{
+		ID:      "1565291711",
+		Migrate: migration1565291711.Migrate,
+	},
+	{
+		ID:      "1568390387",
+		Migrate: migration1568390387.Migrate,
+	},
+	{
+		ID:      "1568833756",
+		Migrate: migration1568833756.Migrate,
+	},
+	{
+		ID:      "1570087128",
+		Migrate: migration1570087128.Migrate,
+	},
+	{
+		ID:      "1570675883",
+		Migrate: migration1570675883.Migrate,
+	},
+	{
+		ID:      "1573667511",
+		Migrate: migration1573667511.Migrate,
+	},
+	{
+		ID:      "1573812490",
+		Migrate: migration1573812490.Migrate,
+	},
+	{
+		ID:      "1575036327",
+		Migrate: migration1575036327.Migrate,
+	},
+	{
+		ID:      "1574659987",
+		Migrate: migration1574659987.Migrate,
+	},
+	{
+		ID:      "1576022702",
+		Migrate: migration1576022702.Migrate,
+	},
+	{
+		ID:      "1579700934",
+		Migrate: migration1579700934.Migrate,
+	},
+	{
+		ID:      "1580904019",
+		Migrate: migration1580904019.Migrate,
+	},
+	{
+		ID:      "1581240419",
+		Migrate: migration1581240419.Migrate,
+	},
+	{
+		ID:      "1584377646",
+		Migrate: migration1584377646.Migrate,
+	},
+	{
+		ID:      "1585908150",
+		Migrate: migration1585908150.Migrate,
+	},
+	{
+		ID:      "1585918589",
+		Migrate: migration1585918589.Migrate,
+	},
+	{
+		ID:      "1586163842",
+		Migrate: migration1586163842.Migrate,
+	},
+	{
+		ID:      "1586342453",
+		Migrate: migration1586342453.Migrate,
+	}, {
+		ID:      "1586369235",
+		Migrate: migration1586369235.Migrate,
+	},
+	{
+		ID:      "1586939705",
+		Migrate: migration1586939705.Migrate,
+	},
+	{
+		ID:      "1587027516",
+		Migrate: migration1587027516.Migrate,
+	},
+	{
+		ID:      "1587580235",
+		Migrate: migration1587580235.Migrate,
+	},
+	{
+		ID:      "1587975059",
+		Migrate: migration1587975059.Migrate,
+	},
+	{
+		ID:      "1586956053",
+		Migrate: migration1586956053.Migrate,
+	},
+	{
+		ID:      "1588293486",
+		Migrate: migration1588293486.Migrate,
+	},
+	{
+		ID:      "1588757164",
+		Migrate: migration1588757164.Migrate,
+	},
+	{
+		ID:      "1588853064",
+		Migrate: migration1588853064.Migrate,
+	},
+	{
+		ID:      "1588940000",
+		Migrate: migration1588940000.Migrate,
+	},
+	{
+		ID:      "1589020000",
+		Migrate: migration1589020000.Migrate,
+	},
+	{
+		ID:      "1589110000",
+		Migrate: migration1589110000.Migrate,
+	},
+	{
+		ID:      "1589200000",
+		Migrate: migration1589200000.Migrate,
+	},
+	{
+		ID:      "1589290000",
+		Migrate: migration1589290000.Migrate,
+	},
+	{
+		ID:      "1589380000",
+		Migrate: migration1589380000.Migrate,
+	},
+	{
+		ID:      "1589470000",
+		Migrate: migration1589470000.Migrate,
+	},
+	{
+		ID:      "1589560000",
+		Migrate: migration1589560000.Migrate,
+	},
+	{
+		ID:      "1589650000",
+		Migrate: migration1589650000.Migrate,
+	},
+	{
+		ID:      "1589740000",
+		Migrate: migration1589740000.Migrate,
+	},
+	{
+		ID:      "1589830000",
+		Migrate: migration1589830000.Migrate,
+	},
+	{
+		ID:      "1589920000",
+		Migrate: migration1589920000.Migrate,
+	},
+	{
+		ID:      "1590010000",
+		Migrate: migration1590010000.Migrate,
+	},
+	{
+		ID:      "1590100000",
+		Migrate: migration1590100000.Migrate,
+	},
+	{
+		ID:      "1590190000",
+		Migrate: migration1590190000.Migrate,
+	},
+	{
+		ID:      "1590280000",
+		Migrate: migration1590280000.Migrate,
+	},
+	{
+		ID:      "1590370000",
+		Migrate: migration1590370000.Migrate,
+	},
+	{
+		ID:      "1590460000",
+		Migrate: migration1590460000.Migrate,
+	},
+	{
+		ID:      "1590550000",
+		Migrate: migration1590550000.Migrate,
+	},
+	{
+		ID:      "1590640000",
+		Migrate: migration1590640000.Migrate,
+	},
+	{
+		ID:      "1590730000",
+		Migrate: migration1590730000.Migrate,
+	},
+	{
+		ID:      "1590820000",
+		Migrate: migration1590820000.Migrate,
+	},
+	{
+		ID:      "1590910000",
+		Migrate: migration1590910000.Migrate,
+	},
+	{
+		ID:      "1591000000",
+		Migrate: migration1591000000.Migrate,
+	},
+	{
+		ID:      "1591090000",
+		Migrate: migration1591090000.Migrate,
+	},
+	{
+		ID:      "1591180000",
+		Migrate: migration1591180000.Migrate,
+	},
+	{
+		ID:      "1591270000",
+		Migrate: migration1591270000.Migrate,
+	},
+	{
+		ID:      "1591360000",
+		Migrate: migration1591360000.Migrate,
+	},
+	{
+		ID:      "1591450000",
+		Migrate: migration1591450000.Migrate,
+	},
+	{
+		ID:      "1591540000",
+		Migrate: migration1591540000.Migrate,
+	},
+	{
+		ID:      "1591630000",
+		Migrate: migration1591630000.Migrate,
+	},
+	{
+		ID:      "1591720000",
+		Migrate: migration1591720000.Migrate,
+	},
+	{
+		ID:      "1591810000",
+		Migrate: migration1591810000.Migrate,
+	},
+	{
+		ID:      "1591900000",
+		Migrate: migration1591900000.Migrate,
+	},
+	{
+		ID:      "1591990000",
+		Migrate: migration1591990000.Migrate,
+	},
+	{
+		ID:      "1592080000",
+		Migrate: migration1592080000.Migrate,
+	},
+	{
+		ID:      "1592170000",
+		Migrate: migration1592170000.Migrate,
+	},
+	{
+		ID:      "1592260000",
+		Migrate: migration1592260000.Migrate,
+	},
+	{
+		ID:      "1592350000",
+		Migrate: migration1592350000.Migrate,
+	},
+	{
+		ID:      "1592440000",
+		Migrate: migration1592440000.Migrate,
+	},
+	{
+		ID:      "1592530000",
+		Migrate: migration1592530000.Migrate,
+	},
+	{
+		ID:      "1592620000",
+		Migrate: migration1592620000.Migrate,
+	},
+	{
+		ID:      "1592710000",
+		Migrate: migration1592710000.Migrate,
+	},
+	{
+		ID:      "1592800000",
+		Migrate: migration1592800000.Migrate,
+	},
+	{
+		ID:      "1592810000",
+		Migrate: migration1592810000.Migrate,
+	},
+	{
+		ID:      "1592820000",
+		Migrate: migration1592820000.Migrate,
+	},
+	{
+		ID:      "1592830000",
+		Migrate: migration1592830000.Migrate,
+	},
+	{
+		ID:      "1592840000",
+		Migrate: migration1592840000.Migrate,
+	},
+	{
+		ID:      "1592850000",
+		Migrate: migration1592850000.Migrate,
+	},
+	{
+		ID:      "1592860000",
+		Migrate: migration1592860000.Migrate,
+	},
+	{
+		ID:      "1592870000",
+		Migrate: migration1592870000.Migrate,
+	},
+	{
+		ID:      "1592880000",
+		Migrate: migration1592880000.Migrate,
+	},
+	{
+		ID:      "1592890000",
+		Migrate: migration1592890000.Migrate,
+	},
+	{
+		ID:      "1592900000",
+		Migrate: migration1592900000.Migrate,
+	},
+	{
+		ID:      "1592910000",
+		Migrate: migration1592910000.Migrate,
+	},
+	{
+		ID:      "1592920000",
+		Migrate: migration1592920000.Migrate,
+	},
+	{
+		ID:      "1592930000",
+		Migrate: migration1592930000.Migrate,
+	},
+	{
+		ID:      "1592940000",
+		Migrate: migration1592940000.Migrate,
+	},
+	{
+		ID:      "1592950000",
+		Migrate: migration1592950000.Migrate,
+	},
+	{
+		ID:      "1592960000",
+		Migrate: migration1592960000.Migrate,
+	},
+	{
+		ID:      "1592970000",
+		Migrate: migration1592970000.Migrate,
+	},
+	{
+		ID:      "1592980000",
+		Migrate: migration1592980000.Migrate,
+	},
+	{
+		ID:      "1592990000",
+		Migrate: migration1592990000.Migrate,
+	},
+	{
+		ID:      "1593000000",
+		Migrate: migration1593000000.Migrate,
+	},
+	{
+		ID:      "1593010000",
+		Migrate: migration1593010000.Migrate,
+	},
+}
+
 // Migrate iterates through available migrations, running and tracking
 // migrations that have not been run.
 func Migrate(db *gorm.DB) error {
@@ -62,175 +568,7 @@ func MigrateTo(db *gorm.DB, migrationID string) error {
 	options := *gormigrate.DefaultOptions
 	options.UseTransaction = true
 
-	migrations := []*gormigrate.Migration{
-		{
-			ID:      "0",
-			Migrate: migration0.Migrate,
-		},
-		{
-			ID:      "1559081901",
-			Migrate: migration1559081901.Migrate,
-		},
-		{
-			ID:      "1559767166",
-			Migrate: migration1559767166.Migrate,
-		},
-		{
-			ID:      "1560433987",
-			Migrate: migration1560433987.Migrate,
-		},
-		{
-			ID:      "1560791143",
-			Migrate: migration1560791143.Migrate,
-		},
-		{
-			ID:      "1560881846",
-			Migrate: migration1560881846.Migrate,
-		},
-		{
-			ID:      "1560886530",
-			Migrate: migration1560886530.Migrate,
-		},
-		{
-			ID:      "1560924400",
-			Migrate: migration1560924400.Migrate,
-		},
-		{
-			ID:      "1560881855",
-			Migrate: migration1560881855.Migrate,
-		},
-		{
-			ID:      "1565139192",
-			Migrate: migration1565139192.Migrate,
-		},
-		{
-			ID:      "1564007745",
-			Migrate: migration1564007745.Migrate,
-		},
-		{
-			ID:      "1565210496",
-			Migrate: migration1565210496.Migrate,
-		},
-		{
-			ID:      "1566498796",
-			Migrate: migration1566498796.Migrate,
-		},
-		{
-			ID:      "1565877314",
-			Migrate: migration1565877314.Migrate,
-		},
-		{
-			ID:      "1566915476",
-			Migrate: migration1566915476.Migrate,
-		},
-		{
-			ID:      "1567029116",
-			Migrate: migration1567029116.Migrate,
-		},
-		{
-			ID:      "1568280052",
-			Migrate: migration1568280052.Migrate,
-		},
-		{
-			ID:      "1565291711",
-			Migrate: migration1565291711.Migrate,
-		},
-		{
-			ID:      "1568390387",
-			Migrate: migration1568390387.Migrate,
-		},
-		{
-			ID:      "1568833756",
-			Migrate: migration1568833756.Migrate,
-		},
-		{
-			ID:      "1570087128",
-			Migrate: migration1570087128.Migrate,
-		},
-		{
-			ID:      "1570675883",
-			Migrate: migration1570675883.Migrate,
-		},
-		{
-			ID:      "1573667511",
-			Migrate: migration1573667511.Migrate,
-		},
-		{
-			ID:      "1573812490",
-			Migrate: migration1573812490.Migrate,
-		},
-		{
-			ID:      "1575036327",
-			Migrate: migration1575036327.Migrate,
-		},
-		{
-			ID:      "1574659987",
-			Migrate: migration1574659987.Migrate,
-		},
-		{
-			ID:      "1576022702",
-			Migrate: migration1576022702.Migrate,
-		},
-		{
-			ID:      "1579700934",
-			Migrate: migration1579700934.Migrate,
-		},
-		{
-			ID:      "1580904019",
-			Migrate: migration1580904019.Migrate,
-		},
-		{
-			ID:      "1581240419",
-			Migrate: migration1581240419.Migrate,
-		},
-		{
-			ID:      "1584377646",
-			Migrate: migration1584377646.Migrate,
-		},
-		{
-			ID:      "1585908150",
-			Migrate: migration1585908150.Migrate,
-		},
-		{
-			ID:      "1585918589",
-			Migrate: migration1585918589.Migrate,
-		},
-		{
-			ID:      "1586163842",
-			Migrate: migration1586163842.Migrate,
-		},
-		{
-			ID:      "1586342453",
-			Migrate: migration1586342453.Migrate,
-		}, {
-			ID:      "1586369235",
-			Migrate: migration1586369235.Migrate,
-		},
-		{
-			ID:      "1586939705",
-			Migrate: migration1586939705.Migrate,
-		},
-		{
-			ID:      "1587027516",
-			Migrate: migration1587027516.Migrate,
-		},
-		{
-			ID:      "1587580235",
-			Migrate: migration1587580235.Migrate,
-		},
-		{
-			ID:      "1587975059",
-			Migrate: migration1587975059.Migrate,
-		},
-		{
-			ID:      "1586956053",
-			Migrate: migration1586956053.Migrate,
-		},
-		{
-			ID:      "1588293486",
-			Migrate: migration1588293486.Migrate,
-		},
-	}
+	migrations := all
 
 	m := gormigrate.New(db, &options, migrations)
 
@@ -30,7 +30,7 @@ func bootstrapORM(t *testing.T) (*orm.ORM, func()) {
 
 	require.NoError(t, os.MkdirAll(config.RootDir(), 0700))
 	cleanupDB := cltest.PrepareTestDB(tc)
-	orm, err := orm.NewORM(config.DatabaseURL(), config.DatabaseTimeout(), gracefulpanic.NewSignal())
+	orm, err := orm.NewORM(config.DatabaseURL(), config.DatabaseTimeout(), gracefulpanic.NewSignal(), config.DatabaseOptions())
 	require.NoError(t, err)
 	orm.SetLogging(true)
 
@@ -280,3 +280,59 @@ func TestMigrate_NewerVersionGuard(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestMigrate_GetStatus(t *testing.T) {
+	orm, cleanup := bootstrapORM(t)
+	defer cleanup()
+
+	err := orm.RawDB(func(db *gorm.DB) error {
+		status, err := migrations.GetStatus(db)
+		require.NoError(t, err)
+		assert.Equal(t, "", status.Current)
+		assert.Equal(t, 0, status.Applied)
+		assert.Equal(t, "0", status.Pending[0])
+		assert.Equal(t, status.Latest, status.Pending[len(status.Pending)-1])
+
+		require.NoError(t, migrations.MigrateTo(db, "1560924400"))
+		status, err = migrations.GetStatus(db)
+		require.NoError(t, err)
+		assert.Equal(t, "1560924400", status.Current)
+		assert.NotEmpty(t, status.Pending)
+		assert.NotContains(t, status.Pending, "1560924400")
+
+		require.NoError(t, migrations.Migrate(db))
+		status, err = migrations.GetStatus(db)
+		require.NoError(t, err)
+		assert.Equal(t, status.Latest, status.Current)
+		assert.Empty(t, status.Pending)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	orm, cleanup := bootstrapORM(t)
+	defer cleanup()
+
+	err := orm.RawDB(func(db *gorm.DB) error {
+		require.NoError(t, migrations.MigrateTo(db, "1591000000"))
+
+		impacts, err := migrations.DryRun(db)
+		require.NoError(t, err)
+		require.NotEmpty(t, impacts)
+		assert.Equal(t, "1591090000", impacts[0].ID)
+		var tables []string
+		for _, lock := range impacts[0].Locks {
+			tables = append(tables, lock.Table)
+		}
+		assert.Contains(t, tables, "sync_event_cursors")
+
+		assert.False(t, db.HasTable("sync_event_cursors"))
+		status, err := migrations.GetStatus(db)
+		require.NoError(t, err)
+		assert.Equal(t, "1591090000", status.Pending[0])
+		assert.Len(t, status.Pending, len(impacts))
+		return nil
+	})
+	require.NoError(t, err)
+}
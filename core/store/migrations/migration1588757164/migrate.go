@@ -0,0 +1,20 @@
+package migration1588757164
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate creates the job_spec_versions table, which keeps a snapshot of every
+// prior definition of a job spec that has been updated in place.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	  CREATE TABLE job_spec_versions (
+		"id" BIGSERIAL PRIMARY KEY,
+		"job_spec_id" uuid REFERENCES job_specs(id) ON DELETE CASCADE NOT NULL,
+		"version" integer NOT NULL,
+		"spec" jsonb NOT NULL,
+		"created_at" timestamp with time zone NOT NULL
+	  );
+	  CREATE UNIQUE INDEX job_spec_versions_job_spec_id_version_idx ON job_spec_versions ("job_spec_id", "version");
+	`).Error
+}
@@ -0,0 +1,25 @@
+package migration1588853064
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds per job concurrency limits, and the pending_concurrency status
+// for runs parked until a slot frees up. The status enum is rebuilt rather
+// than extended with ADD VALUE, since that cannot run inside a transaction on
+// older versions of postgres.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE job_specs ADD COLUMN max_concurrent_runs integer NOT NULL DEFAULT 0;
+
+	ALTER TYPE job_run_status RENAME TO job_run_status_old;
+	CREATE TYPE job_run_status AS ENUM ('unstarted', 'in_progress', 'pending_confirmations', 'pending_connection', 'pending_bridge', 'pending_sleep', 'pending_concurrency', 'errored', 'completed', 'cancelled');
+
+	DROP INDEX idx_job_runs_status;
+	ALTER TABLE job_runs ALTER COLUMN status DROP DEFAULT;
+	ALTER TABLE job_runs ALTER COLUMN status TYPE job_run_status USING status::text::job_run_status, ALTER COLUMN status SET DEFAULT 'unstarted'::job_run_status;
+	CREATE INDEX idx_job_runs_status ON job_runs (status) WHERE status != 'completed'::job_run_status;
+
+	DROP TYPE job_run_status_old;
+	`).Error
+}
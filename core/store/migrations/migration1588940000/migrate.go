@@ -0,0 +1,22 @@
+package migration1588940000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the topics consumed by kafka initiators, and a table recording
+// how far each initiator has read into every partition of those topics.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN kafka_topics text;
+
+	CREATE TABLE kafka_offsets (
+		initiator_id integer NOT NULL REFERENCES initiators(id) ON DELETE CASCADE,
+		topic text NOT NULL,
+		partition integer NOT NULL,
+		"offset" bigint NOT NULL,
+		updated_at timestamp with time zone NOT NULL,
+		PRIMARY KEY (initiator_id, topic, partition)
+	);
+	`).Error
+}
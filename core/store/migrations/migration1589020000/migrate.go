@@ -0,0 +1,23 @@
+package migration1589020000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the parameters of mqtt initiators, and a table of the messages
+// they have consumed, used to drop messages redelivered by the broker.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN broker_url text;
+	ALTER TABLE initiators ADD COLUMN topic_filter text;
+	ALTER TABLE initiators ADD COLUMN qos smallint NOT NULL DEFAULT 0;
+
+	CREATE TABLE mqtt_consumptions (
+		id BIGSERIAL PRIMARY KEY,
+		initiator_id integer NOT NULL REFERENCES initiators(id) ON DELETE CASCADE,
+		message_key text NOT NULL,
+		created_at timestamp with time zone NOT NULL,
+		UNIQUE (initiator_id, message_key)
+	);
+	`).Error
+}
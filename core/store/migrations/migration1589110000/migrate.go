@@ -0,0 +1,14 @@
+package migration1589110000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the mode of a bridge, so that bridges can push updates over a
+// websocket, and the bridge a stream initiator listens to.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE bridge_types ADD COLUMN mode text NOT NULL DEFAULT 'http';
+	ALTER TABLE initiators ADD COLUMN bridge_name text;
+	`).Error
+}
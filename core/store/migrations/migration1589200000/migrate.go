@@ -0,0 +1,14 @@
+package migration1589200000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the authentication scheme of a bridge, with its credentials
+// stored encrypted.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE bridge_types ADD COLUMN auth_type text NOT NULL DEFAULT '';
+	ALTER TABLE bridge_types ADD COLUMN encrypted_auth bytea;
+	`).Error
+}
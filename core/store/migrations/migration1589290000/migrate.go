@@ -0,0 +1,22 @@
+package migration1589290000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the cache TTL of a bridge, and the table caching bridge
+// responses when BRIDGE_CACHE_STORE is set to database.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE bridge_types ADD COLUMN cache_ttl bigint NOT NULL DEFAULT 0;
+
+	CREATE TABLE bridge_responses (
+		key text PRIMARY KEY,
+		bridge_name text NOT NULL REFERENCES bridge_types (name) ON DELETE CASCADE,
+		body bytea NOT NULL,
+		expires_at timestamptz NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_bridge_responses_expires_at ON bridge_responses (expires_at);
+	`).Error
+}
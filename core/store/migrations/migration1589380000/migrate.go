@@ -0,0 +1,23 @@
+package migration1589380000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the table tracking the health of each bridge.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE bridge_healths (
+		bridge_name text PRIMARY KEY REFERENCES bridge_types (name) ON DELETE CASCADE,
+		successes bigint NOT NULL DEFAULT 0,
+		failures bigint NOT NULL DEFAULT 0,
+		consecutive_failures integer NOT NULL DEFAULT 0,
+		latency_total bigint NOT NULL DEFAULT 0,
+		last_error text,
+		last_success_at timestamptz,
+		last_failure_at timestamptz,
+		circuit_opened_at timestamptz,
+		updated_at timestamptz NOT NULL
+	);
+	`).Error
+}
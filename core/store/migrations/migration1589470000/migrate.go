@@ -0,0 +1,22 @@
+package migration1589470000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the client certificates presented to servers requiring
+// mutual TLS, and the certificate used by each bridge.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE client_certificates (
+		name text PRIMARY KEY,
+		subject text NOT NULL,
+		not_after timestamptz NOT NULL,
+		encrypted_pem bytea NOT NULL,
+		created_at timestamptz NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+
+	ALTER TABLE bridge_types ADD COLUMN client_certificate text NOT NULL DEFAULT '';
+	`).Error
+}
@@ -0,0 +1,12 @@
+package migration1589560000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the minimum interval between requests to a bridge.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE bridge_types ADD COLUMN min_interval bigint NOT NULL DEFAULT 0;
+	`).Error
+}
@@ -0,0 +1,12 @@
+package migration1589650000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the aggregation of flux monitor initiators.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "aggregation" jsonb;
+	`).Error
+}
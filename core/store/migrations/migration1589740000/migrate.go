@@ -0,0 +1,23 @@
+package migration1589740000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the table tracking the health of each flux monitor feed.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE feed_healths (
+		feed text PRIMARY KEY,
+		successes bigint NOT NULL DEFAULT 0,
+		failures bigint NOT NULL DEFAULT 0,
+		consecutive_failures integer NOT NULL DEFAULT 0,
+		latency_total bigint NOT NULL DEFAULT 0,
+		last_error text,
+		last_success_at timestamptz,
+		last_failure_at timestamptz,
+		quarantined_at timestamptz,
+		updated_at timestamptz NOT NULL
+	);
+	`).Error
+}
@@ -0,0 +1,12 @@
+package migration1589830000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the gas throttle of flux monitor initiators.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "gas_throttle" jsonb;
+	`).Error
+}
@@ -0,0 +1,25 @@
+package migration1589920000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the dry run mode of flux monitor initiators, and the table
+// keeping the answers they would have submitted.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "dry_run" boolean NOT NULL DEFAULT false;
+
+	CREATE TABLE flux_dry_run_submissions (
+		id BIGSERIAL PRIMARY KEY,
+		job_spec_id uuid REFERENCES job_specs(id) ON DELETE CASCADE NOT NULL,
+		aggregator bytea NOT NULL,
+		round_id bigint NOT NULL,
+		latest_answer numeric NOT NULL,
+		answer numeric NOT NULL,
+		reason text NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_flux_dry_run_submissions_job_spec_id_created_at ON flux_dry_run_submissions (job_spec_id, created_at);
+	`).Error
+}
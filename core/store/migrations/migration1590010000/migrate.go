@@ -0,0 +1,21 @@
+package migration1590010000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the table of encrypted off-chain reporting keys, and the
+// oracles of off-chain reporting initiators.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE encrypted_ocr_key_bundles (
+		id text PRIMARY KEY,
+		peer_id text NOT NULL,
+		signing_address bytea NOT NULL,
+		encrypted_keys jsonb NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+
+	ALTER TABLE initiators ADD COLUMN "offchain_reporting" jsonb;
+	`).Error
+}
@@ -0,0 +1,30 @@
+package migration1590100000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the queue of RandomnessRequest logs waiting to be fulfilled.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE vrf_requests (
+		id BIGSERIAL PRIMARY KEY,
+		request_id bytea NOT NULL,
+		job_spec_id uuid REFERENCES job_specs(id) ON DELETE CASCADE NOT NULL,
+		initiator_id integer REFERENCES initiators(id) ON DELETE CASCADE NOT NULL,
+		block_number bigint NOT NULL,
+		block_hash bytea NOT NULL,
+		tx_hash bytea NOT NULL,
+		log text NOT NULL,
+		status text NOT NULL,
+		attempts integer NOT NULL DEFAULT 0,
+		next_attempt_at timestamptz NOT NULL,
+		job_run_id uuid REFERENCES job_runs(id) ON DELETE SET NULL,
+		error text,
+		created_at timestamptz NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_vrf_requests_request_id ON vrf_requests (request_id);
+	CREATE INDEX idx_vrf_requests_status_block_number ON vrf_requests (status, block_number);
+	`).Error
+}
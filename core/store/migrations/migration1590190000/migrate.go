@@ -0,0 +1,13 @@
+package migration1590190000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate records the transaction fulfilling a VRF request as part of a
+// batch, rather than by a run.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE vrf_requests ADD COLUMN fulfillment_tx_hash bytea;
+	`).Error
+}
@@ -0,0 +1,13 @@
+package migration1590280000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate records when an ethereum key was retired by rotating it, after
+// which the node stops sending transactions from it.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE keys ADD COLUMN retired_at timestamptz;
+	`).Error
+}
@@ -0,0 +1,15 @@
+package migration1590370000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate lets an ethereum key be stored as a reference to a key held by a
+// KMS or HSM, instead of as encrypted key material.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE keys ADD COLUMN key_ref text;
+	ALTER TABLE keys ALTER COLUMN json DROP NOT NULL;
+	ALTER TABLE keys ADD CONSTRAINT chk_key_material CHECK (json IS NOT NULL OR key_ref IS NOT NULL);
+	`).Error
+}
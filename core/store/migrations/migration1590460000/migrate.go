@@ -0,0 +1,13 @@
+package migration1590460000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate removes the existing sessions, whose IDs were saved as they are,
+// now that only their hashes are. Users have to log in again.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	DELETE FROM sessions;
+	`).Error
+}
@@ -0,0 +1,20 @@
+package migration1590550000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the requester allowlists of runlog jobs. Rows without a job
+// make up the global allowlist, which applies to every runlog job.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE allowed_requesters (
+		id BIGSERIAL PRIMARY KEY,
+		job_spec_id uuid REFERENCES job_specs(id) ON DELETE CASCADE,
+		address bytea NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_allowed_requesters_job_spec_id_address ON allowed_requesters (job_spec_id, address) WHERE job_spec_id IS NOT NULL;
+	CREATE UNIQUE INDEX idx_allowed_requesters_global_address ON allowed_requesters (address) WHERE job_spec_id IS NULL;
+	`).Error
+}
@@ -0,0 +1,20 @@
+package migration1590640000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the names of tasks, and the edges between the tasks of jobs
+// whose tasks declare their inputs.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE task_specs ADD COLUMN name text NOT NULL DEFAULT '';
+	CREATE TABLE task_spec_edges (
+		id BIGSERIAL PRIMARY KEY,
+		task_spec_id integer REFERENCES task_specs(id) ON DELETE CASCADE NOT NULL,
+		input_task_spec_id integer REFERENCES task_specs(id) ON DELETE CASCADE NOT NULL
+	);
+	CREATE INDEX idx_task_spec_edges_task_spec_id ON task_spec_edges (task_spec_id);
+	CREATE INDEX idx_task_spec_edges_input_task_spec_id ON task_spec_edges (input_task_spec_id);
+	`).Error
+}
@@ -0,0 +1,14 @@
+package migration1590730000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the idempotency keys of run requests, which are unique so that
+// a request retried with the same key cannot create a second run.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE run_requests ADD COLUMN idempotency_key text;
+	CREATE UNIQUE INDEX idx_run_requests_idempotency_key ON run_requests (idempotency_key) WHERE idempotency_key IS NOT NULL;
+	`).Error
+}
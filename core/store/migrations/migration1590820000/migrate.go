@@ -0,0 +1,23 @@
+package migration1590820000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds when external initiators were last seen and last checked, and
+// the failures to deliver notices to them.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE external_initiators ADD COLUMN last_seen_at timestamptz;
+	ALTER TABLE external_initiators ADD COLUMN last_health_check_at timestamptz;
+	ALTER TABLE external_initiators ADD COLUMN health_check_error text;
+	CREATE TABLE external_initiator_delivery_failures (
+		id BIGSERIAL PRIMARY KEY,
+		external_initiator_name text NOT NULL,
+		job_spec_id uuid REFERENCES job_specs(id) ON DELETE CASCADE,
+		error text NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_external_initiator_delivery_failures_name_created_at ON external_initiator_delivery_failures (external_initiator_name, created_at);
+	`).Error
+}
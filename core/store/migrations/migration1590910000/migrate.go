@@ -0,0 +1,26 @@
+package migration1590910000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the notifications waiting to be delivered to external
+// initiators, including those dead-lettered after running out of attempts.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE external_initiator_notifications (
+		id BIGSERIAL PRIMARY KEY,
+		external_initiator_name text NOT NULL,
+		job_spec_id uuid NOT NULL REFERENCES job_specs(id) ON DELETE CASCADE,
+		body text NOT NULL,
+		attempts integer NOT NULL DEFAULT 0,
+		next_attempt_at timestamptz NOT NULL,
+		last_error text,
+		dead_at timestamptz,
+		created_at timestamptz NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_external_initiator_notifications_next_attempt_at ON external_initiator_notifications (next_attempt_at) WHERE dead_at IS NULL;
+	CREATE INDEX idx_external_initiator_notifications_dead_at ON external_initiator_notifications (dead_at) WHERE dead_at IS NOT NULL;
+	`).Error
+}
@@ -0,0 +1,21 @@
+package migration1591000000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the audit log of the configuration changed while the node is
+// running.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE configuration_changes (
+		id BIGSERIAL PRIMARY KEY,
+		name text NOT NULL,
+		from_value text NOT NULL,
+		to_value text NOT NULL,
+		changed_by text NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_configuration_changes_created_at ON configuration_changes (created_at);
+	`).Error
+}
@@ -0,0 +1,17 @@
+package migration1591090000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the cursors of the consumers of sync events, so that an event
+// is only deleted once every consumer has consumed it.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE sync_event_cursors (
+		consumer text PRIMARY KEY,
+		last_id bigint NOT NULL DEFAULT 0,
+		updated_at timestamptz NOT NULL
+	);
+	`).Error
+}
@@ -0,0 +1,21 @@
+package migration1591180000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the table holding the data of run results larger than
+// RUN_RESULT_MAX_SIZE, stored once per hash, and the column pointing a run
+// result at its data there.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE run_result_blobs (
+		hash text PRIMARY KEY,
+		data text NOT NULL,
+		size bigint NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	ALTER TABLE run_results ADD COLUMN blob_hash text;
+	CREATE INDEX idx_run_results_blob_hash ON run_results(blob_hash) WHERE blob_hash IS NOT NULL;
+	`).Error
+}
@@ -0,0 +1,13 @@
+package migration1591270000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the name of the archive file the data of a run result was
+// moved to, once its run has finished for RUN_RESULT_ARCHIVE_AFTER.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE run_results ADD COLUMN archived_in text;
+	`).Error
+}
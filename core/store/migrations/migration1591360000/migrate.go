@@ -0,0 +1,153 @@
+package migration1591360000
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Migrate partitions job_runs and tx_attempts by the month they were created
+// in, so that old runs and attempts can be removed by dropping partitions.
+//
+// The rows there are already stay where they are, in a <table>_legacy
+// partition which ends at the start of next month. After that come a
+// partition for next month, which the PartitionManager keeps adding to, and
+// a <table>_default partition catching the rows of months it has not added.
+//
+// Postgres 11 does not let foreign keys point at partitioned tables, so those
+// of task_runs and vrf_requests to job_runs are replaced by a trigger doing
+// what they did when runs are deleted.
+func Migrate(tx *gorm.DB) error {
+	now := time.Now().UTC()
+	for _, table := range []string{"job_runs", "tx_attempts"} {
+		if err := partitionByMonth(tx, table, now); err != nil {
+			return errors.Wrapf(err, "partitioning %s", table)
+		}
+	}
+	return tx.Exec(`
+	CREATE FUNCTION delete_job_run_dependents() RETURNS trigger AS $$
+	BEGIN
+		DELETE FROM task_runs WHERE job_run_id = OLD.id;
+		UPDATE vrf_requests SET job_run_id = NULL WHERE job_run_id = OLD.id;
+		RETURN OLD;
+	END;
+	$$ LANGUAGE plpgsql;
+
+	CREATE TRIGGER job_runs_delete_dependents AFTER DELETE ON job_runs
+	FOR EACH ROW EXECUTE PROCEDURE delete_job_run_dependents();
+	`).Error
+}
+
+type definition struct {
+	name string
+	def  string
+}
+
+func partitionByMonth(tx *gorm.DB, table string, now time.Time) error {
+	legacy := table + "_legacy"
+	nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+	referencing, err := definitions(tx, `
+		SELECT conname, conrelid::regclass::text FROM pg_constraint
+		WHERE contype = 'f' AND confrelid = ?::regclass`, table)
+	if err != nil {
+		return err
+	}
+	foreignKeys, err := definitions(tx, `
+		SELECT conname, pg_get_constraintdef(oid) FROM pg_constraint
+		WHERE contype = 'f' AND conrelid = ?::regclass`, table)
+	if err != nil {
+		return err
+	}
+	indexes, err := definitions(tx, `
+		SELECT i.relname, pg_get_indexdef(i.oid) FROM pg_index x
+		JOIN pg_class i ON i.oid = x.indexrelid
+		WHERE x.indrelid = ?::regclass AND NOT x.indisunique`, table)
+	if err != nil {
+		return err
+	}
+	primaryKey, err := definitions(tx, `
+		SELECT conname, pg_get_constraintdef(oid) FROM pg_constraint
+		WHERE contype = 'p' AND conrelid = ?::regclass`, table)
+	if err != nil {
+		return err
+	}
+
+	var statements []string
+	for _, fk := range referencing {
+		statements = append(statements, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %q`, fk.def, fk.name))
+	}
+	for _, fk := range foreignKeys {
+		statements = append(statements, fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %q`, table, fk.name))
+	}
+	statements = append(statements,
+		fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN created_at SET NOT NULL`, table),
+		fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, table, legacy),
+	)
+	for _, pk := range primaryKey {
+		statements = append(statements, fmt.Sprintf(`ALTER TABLE %s RENAME CONSTRAINT %q TO %q`, legacy, pk.name, legacy+"_pkey"))
+	}
+	for _, index := range indexes {
+		statements = append(statements, fmt.Sprintf(`ALTER INDEX %q RENAME TO %q`, index.name, index.name+"_legacy"))
+	}
+	statements = append(statements,
+		fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS) PARTITION BY RANGE (created_at)`, table, legacy),
+		fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s_pkey PRIMARY KEY (id, created_at)`, table, table),
+	)
+	// The definitions name the table as it was, which is now the partitioned
+	// one, so indexes equivalent to those of the legacy partition are created
+	// and attached to them.
+	for _, index := range indexes {
+		statements = append(statements, index.def)
+	}
+	for _, fk := range foreignKeys {
+		statements = append(statements, fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %q %s`, table, fk.name, fk.def))
+	}
+	statements = append(statements,
+		fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM (MINVALUE) TO ('%s')`, table, legacy, nextMonth.Format(time.RFC3339)),
+		fmt.Sprintf(`CREATE TABLE %s_default PARTITION OF %s DEFAULT`, table, table),
+		fmt.Sprintf(`CREATE TABLE %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+			monthPartition(table, nextMonth), table, nextMonth.Format(time.RFC3339), nextMonth.AddDate(0, 1, 0).Format(time.RFC3339)),
+	)
+
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return errors.Wrap(err, strings.SplitN(statement, "(", 2)[0])
+		}
+	}
+
+	var sequence *string
+	if err := tx.Raw(`SELECT pg_get_serial_sequence(?, 'id')`, legacy).Row().Scan(&sequence); err != nil {
+		return err
+	}
+	if sequence != nil {
+		return tx.Exec(fmt.Sprintf(`ALTER SEQUENCE %s OWNED BY %s.id`, *sequence, table)).Error
+	}
+	return nil
+}
+
+// monthPartition returns the name of the partition of table holding the rows
+// created in the month of t, such as job_runs_y2020m06.
+func monthPartition(table string, t time.Time) string {
+	return fmt.Sprintf("%s_y%04dm%02d", table, t.Year(), t.Month())
+}
+
+func definitions(tx *gorm.DB, query string, table string) ([]definition, error) {
+	rows, err := tx.Raw(query, table).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var defs []definition
+	for rows.Next() {
+		var d definition
+		if err := rows.Scan(&d.name, &d.def); err != nil {
+			return nil, err
+		}
+		defs = append(defs, d)
+	}
+	return defs, rows.Err()
+}
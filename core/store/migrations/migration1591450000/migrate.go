@@ -0,0 +1,20 @@
+package migration1591450000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the diagnostics table the statements taking longer than
+// DATABASE_SLOW_QUERY_THRESHOLD are recorded in.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE slow_queries (
+		id BIGSERIAL PRIMARY KEY,
+		query text NOT NULL,
+		params text NOT NULL,
+		duration_ms double precision NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_slow_queries_created_at ON slow_queries USING BRIN (created_at);
+	`).Error
+}
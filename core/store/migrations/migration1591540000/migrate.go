@@ -0,0 +1,44 @@
+package migration1591540000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the configuration of keeper initiators, the upkeeps they
+// register, and the performUpkeep transactions sent for them.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "keeper" jsonb;
+
+	CREATE TABLE upkeeps (
+		id BIGSERIAL PRIMARY KEY,
+		job_spec_id uuid REFERENCES job_specs(id) ON DELETE CASCADE NOT NULL,
+		initiator_id integer REFERENCES initiators(id) ON DELETE CASCADE NOT NULL,
+		address bytea NOT NULL,
+		check_data bytea NOT NULL,
+		gas_limit bigint NOT NULL,
+		cooldown_blocks bigint NOT NULL DEFAULT 0,
+		last_checked_block bigint NOT NULL DEFAULT 0,
+		last_performed_block bigint NOT NULL DEFAULT 0,
+		perform_count bigint NOT NULL DEFAULT 0,
+		created_at timestamptz NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_upkeeps_initiator_id ON upkeeps (initiator_id);
+
+	CREATE TABLE upkeep_performs (
+		id BIGSERIAL PRIMARY KEY,
+		upkeep_id bigint REFERENCES upkeeps(id) ON DELETE CASCADE NOT NULL,
+		block_number bigint NOT NULL,
+		perform_data bytea NOT NULL,
+		tx_hash bytea NOT NULL,
+		gas_limit bigint NOT NULL,
+		gas_price numeric(78, 0) NOT NULL,
+		confirmed boolean NOT NULL DEFAULT false,
+		created_at timestamptz NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_upkeep_performs_upkeep_id ON upkeep_performs (upkeep_id);
+	CREATE INDEX idx_upkeep_performs_unconfirmed ON upkeep_performs (upkeep_id) WHERE NOT confirmed;
+	`).Error
+}
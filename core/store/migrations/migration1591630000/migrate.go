@@ -0,0 +1,14 @@
+package migration1591630000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the time zone and the calendar of holidays and blackouts of
+// cron initiators.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "timezone" text;
+	ALTER TABLE initiators ADD COLUMN "calendar" jsonb;
+	`).Error
+}
@@ -0,0 +1,15 @@
+package migration1591720000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the delay and recurrence of runat initiators, and the time
+// their next run is due once they have run.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "delay" bigint NOT NULL DEFAULT 0;
+	ALTER TABLE initiators ADD COLUMN "every" bigint NOT NULL DEFAULT 0;
+	ALTER TABLE initiators ADD COLUMN "next_run_at" timestamptz;
+	`).Error
+}
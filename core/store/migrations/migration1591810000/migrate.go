@@ -0,0 +1,12 @@
+package migration1591810000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the ABI fragment ethlog initiators decode their logs with.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "abi" jsonb;
+	`).Error
+}
@@ -0,0 +1,12 @@
+package migration1591900000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the condition on new heads of blockcondition initiators.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE initiators ADD COLUMN "block_condition" jsonb;
+	`).Error
+}
@@ -0,0 +1,14 @@
+package migration1591990000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate records the block number of the logs consumed, so that the records
+// of those older than the reorg horizon can be trimmed.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE log_consumptions ADD COLUMN "block_number" bigint;
+	CREATE INDEX log_consumptions_block_number_idx ON log_consumptions ("block_number");
+	`).Error
+}
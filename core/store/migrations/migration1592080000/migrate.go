@@ -0,0 +1,14 @@
+package migration1592080000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds when a service agreement was terminated, by an operator or
+// on expiring, and when the operator was notified it expires soon.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE service_agreements ADD COLUMN "terminated_at" timestamptz;
+	ALTER TABLE service_agreements ADD COLUMN "expiry_notified_at" timestamptz;
+	`).Error
+}
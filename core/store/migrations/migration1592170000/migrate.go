@@ -0,0 +1,28 @@
+package migration1592170000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate creates the service_agreement_payments table, recording the LINK
+// coordinators escrow for service agreements and refund.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE service_agreement_payments (
+		id BIGSERIAL PRIMARY KEY,
+		service_agreement_id varchar(255) NOT NULL REFERENCES service_agreements (id) ON DELETE CASCADE,
+		kind varchar(255) NOT NULL,
+		request_id bytea NOT NULL,
+		amount numeric(78, 0) NOT NULL,
+		tx_hash bytea NOT NULL,
+		block_hash bytea NOT NULL,
+		block_number bigint NOT NULL,
+		log_index bigint NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+
+	CREATE UNIQUE INDEX idx_service_agreement_payments_kind_request_id ON service_agreement_payments (kind, request_id);
+	CREATE INDEX idx_service_agreement_payments_service_agreement_id ON service_agreement_payments (service_agreement_id);
+	CREATE INDEX idx_service_agreement_payments_block_number ON service_agreement_payments (block_number);
+	`).Error
+}
@@ -0,0 +1,27 @@
+package migration1592260000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate puts jobs, bridges and keys in namespaces, the default one being
+// empty, and creates the namespace_tokens table of the API tokens scoped to
+// a namespace.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE job_specs ADD COLUMN "namespace" varchar(63) NOT NULL DEFAULT '';
+	ALTER TABLE bridge_types ADD COLUMN "namespace" varchar(63) NOT NULL DEFAULT '';
+	ALTER TABLE keys ADD COLUMN "namespace" varchar(63) NOT NULL DEFAULT '';
+	CREATE INDEX idx_job_specs_namespace ON job_specs (namespace);
+	CREATE INDEX idx_bridge_types_namespace ON bridge_types (namespace);
+
+	CREATE TABLE namespace_tokens (
+		access_key varchar(255) PRIMARY KEY,
+		namespace varchar(63) NOT NULL,
+		role varchar(255) NOT NULL,
+		salt varchar(255) NOT NULL,
+		hashed_secret varchar(255) NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	`).Error
+}
@@ -0,0 +1,16 @@
+package migration1592350000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the job and log index of the run requests made by logs, which
+// are unique with the hash of the block of the log so that a log delivered
+// again cannot create a second run of the job.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE run_requests ADD COLUMN job_spec_id uuid;
+	ALTER TABLE run_requests ADD COLUMN log_index bigint;
+	CREATE UNIQUE INDEX idx_run_requests_job_spec_id_block_hash_log_index ON run_requests (job_spec_id, block_hash, log_index) WHERE log_index IS NOT NULL;
+	`).Error
+}
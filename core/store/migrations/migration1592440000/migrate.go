@@ -0,0 +1,17 @@
+package migration1592440000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the confirmations set by jobs, and by their tasks sending
+// transactions, along with the outgoing confirmations each task run waits
+// for.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE job_specs ADD COLUMN incoming_confirmations bigint;
+	ALTER TABLE job_specs ADD COLUMN outgoing_confirmations bigint;
+	ALTER TABLE task_specs ADD COLUMN outgoing_confirmations bigint;
+	ALTER TABLE task_runs ADD COLUMN minimum_outgoing_confirmations bigint;
+	`).Error
+}
@@ -0,0 +1,23 @@
+package migration1592530000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the secrets external initiators sign their requests with, and
+// the nonces of the signed requests, which are unique to each external
+// initiator so that the requests cannot be replayed.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE external_initiators ADD COLUMN require_signature boolean NOT NULL DEFAULT false;
+	ALTER TABLE external_initiators ADD COLUMN signing_secret text NOT NULL DEFAULT '';
+	CREATE TABLE external_initiator_nonces (
+		id BIGSERIAL PRIMARY KEY,
+		external_initiator_name text NOT NULL,
+		nonce text NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_external_initiator_nonces_name_nonce ON external_initiator_nonces (external_initiator_name, nonce);
+	CREATE INDEX idx_external_initiator_nonces_created_at ON external_initiator_nonces (created_at);
+	`).Error
+}
@@ -0,0 +1,21 @@
+package migration1592620000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the audit log of the requests refused because they came from
+// outside the networks allowed to make requests to the endpoint.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE access_violations (
+		id BIGSERIAL PRIMARY KEY,
+		endpoint text NOT NULL,
+		remote_ip text NOT NULL,
+		method text NOT NULL,
+		path text NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_access_violations_created_at ON access_violations (created_at);
+	`).Error
+}
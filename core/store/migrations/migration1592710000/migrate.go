@@ -0,0 +1,14 @@
+package migration1592710000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate records the gas used by transaction attempts, and the price paid
+// for it, once they are safe.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE tx_attempts ADD COLUMN gas_used bigint;
+	ALTER TABLE tx_attempts ADD COLUMN effective_gas_price varchar(78);
+	`).Error
+}
@@ -0,0 +1,25 @@
+package migration1592800000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the audit log of the runs pending on a bridge which were
+// resumed with a result supplied by an operator rather than the bridge.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE run_resumptions (
+		id BIGSERIAL PRIMARY KEY,
+		job_run_id uuid NOT NULL,
+		task_run_id uuid NOT NULL,
+		bridge text NOT NULL,
+		status text NOT NULL,
+		data text NOT NULL,
+		error_message text,
+		resumed_by text NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_run_resumptions_job_run_id ON run_resumptions (job_run_id);
+	CREATE INDEX idx_run_resumptions_created_at ON run_resumptions (created_at);
+	`).Error
+}
@@ -0,0 +1,15 @@
+package migration1592810000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate keeps the tokens of bridges which were rotated, so that they are
+// still used for a grace period alongside the new ones.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE bridge_types ADD COLUMN previous_incoming_token_hash text NOT NULL DEFAULT '';
+	ALTER TABLE bridge_types ADD COLUMN previous_outgoing_token text NOT NULL DEFAULT '';
+	ALTER TABLE bridge_types ADD COLUMN previous_tokens_expire_at timestamptz;
+	`).Error
+}
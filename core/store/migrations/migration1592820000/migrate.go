@@ -0,0 +1,21 @@
+package migration1592820000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the single-use tokens bridges call runs pending on them back
+// with.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE bridge_callbacks (
+		id BIGSERIAL PRIMARY KEY,
+		job_run_id uuid NOT NULL,
+		token_hash text NOT NULL,
+		expires_at timestamptz,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_bridge_callbacks_job_run_id ON bridge_callbacks (job_run_id);
+	CREATE INDEX idx_bridge_callbacks_expires_at ON bridge_callbacks (expires_at);
+	`).Error
+}
@@ -0,0 +1,19 @@
+package migration1592830000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the secrets job specs reference in the params of their tasks.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE secrets (
+		id BIGSERIAL PRIMARY KEY,
+		name text NOT NULL,
+		value text NOT NULL,
+		created_at timestamptz NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_secrets_name ON secrets (name);
+	`).Error
+}
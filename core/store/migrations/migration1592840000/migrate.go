@@ -0,0 +1,20 @@
+package migration1592840000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the WebAssembly modules wasm tasks run.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE wasm_modules (
+		id BIGSERIAL PRIMARY KEY,
+		name text NOT NULL,
+		hash text NOT NULL,
+		size integer NOT NULL,
+		module bytea NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE UNIQUE INDEX idx_wasm_modules_name ON wasm_modules (name);
+	`).Error
+}
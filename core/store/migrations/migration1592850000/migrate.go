@@ -0,0 +1,24 @@
+package migration1592850000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the audit log of the ETH sent from the funding key to the
+// node's other keys when their balances dropped below the threshold.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE key_fundings (
+		id BIGSERIAL PRIMARY KEY,
+		"from" bytea NOT NULL,
+		"to" bytea NOT NULL,
+		amount numeric(78, 0) NOT NULL,
+		balance numeric(78, 0) NOT NULL,
+		tx_id bigint NOT NULL REFERENCES txes (id) ON DELETE CASCADE,
+		tx_hash bytea NOT NULL,
+		created_at timestamptz NOT NULL
+	);
+	CREATE INDEX idx_key_fundings_to ON key_fundings ("to");
+	CREATE INDEX idx_key_fundings_created_at ON key_fundings (created_at);
+	`).Error
+}
@@ -0,0 +1,26 @@
+package migration1592860000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the hourly and daily rollups of the runs of each job which
+// finished, so that statistics over long ranges need not aggregate the runs.
+// Latencies and earnings are summed rather than averaged, so that buckets
+// can be added up.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE job_run_stats (
+		period text NOT NULL,
+		job_spec_id uuid NOT NULL,
+		bucket timestamptz NOT NULL,
+		runs bigint NOT NULL,
+		errored bigint NOT NULL,
+		latency_seconds double precision NOT NULL,
+		link_earned numeric(78, 0) NOT NULL,
+		updated_at timestamptz NOT NULL,
+		PRIMARY KEY (period, job_spec_id, bucket)
+	);
+	CREATE INDEX idx_job_run_stats_period_bucket ON job_run_stats (period, bucket);
+	`).Error
+}
@@ -0,0 +1,14 @@
+package migration1592870000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate lets key fundings be recorded before their transactions are sent,
+// so that a funding is never sent without being accounted for.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE key_fundings ALTER COLUMN tx_id DROP NOT NULL;
+	ALTER TABLE key_fundings ALTER COLUMN tx_hash DROP NOT NULL;
+	`).Error
+}
@@ -0,0 +1,20 @@
+package migration1592880000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate makes the idempotency keys of run requests unique per job rather
+// than across the node, so that the keys of one job's requests cannot
+// collide with another's. The job of the requests made with a key before is
+// taken from their runs.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	UPDATE run_requests SET job_spec_id = job_runs.job_spec_id
+	FROM job_runs
+	WHERE job_runs.run_request_id = run_requests.id
+	AND run_requests.idempotency_key IS NOT NULL AND run_requests.job_spec_id IS NULL;
+	DROP INDEX idx_run_requests_idempotency_key;
+	CREATE UNIQUE INDEX idx_run_requests_job_spec_id_idempotency_key ON run_requests (job_spec_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
+	`).Error
+}
@@ -0,0 +1,22 @@
+package migration1592890000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate creates the leases table of the lease locking strategy, which
+// nodes created themselves when taking the lease before, and drops any
+// lease_lock table, which it replaces.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE IF NOT EXISTS leases (
+		name text PRIMARY KEY,
+		holder_id uuid NOT NULL,
+		holder text NOT NULL,
+		acquired_at timestamptz NOT NULL,
+		heartbeat_at timestamptz NOT NULL,
+		expires_at timestamptz NOT NULL
+	);
+	DROP TABLE IF EXISTS lease_lock;
+	`).Error
+}
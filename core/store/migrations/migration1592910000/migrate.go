@@ -0,0 +1,17 @@
+package migration1592910000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the columns encrypted with the master key which the auth
+// credentials of bridges and the client certificates are kept in. Those
+// encrypted with the session secret are moved to them by the node, which
+// holds the secret, when it starts.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE bridge_types ADD COLUMN auth_credentials text NOT NULL DEFAULT '';
+	ALTER TABLE client_certificates ADD COLUMN pem text NOT NULL DEFAULT '';
+	ALTER TABLE client_certificates ALTER COLUMN encrypted_pem DROP NOT NULL;
+	`).Error
+}
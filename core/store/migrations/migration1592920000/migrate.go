@@ -0,0 +1,13 @@
+package migration1592920000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the namespaces of the secrets, which only the jobs of their
+// namespace can reference. Existing secrets are in the default namespace.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE secrets ADD COLUMN namespace text NOT NULL DEFAULT '';
+	`).Error
+}
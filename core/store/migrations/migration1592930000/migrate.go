@@ -0,0 +1,23 @@
+package migration1592930000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate ties the bridge callback tokens to the task runs which called the
+// bridges, rather than only to their runs, so that a token cannot resume
+// another task of the run. The existing tokens are tied to the task runs
+// pending on bridges, and those of runs no longer pending are removed.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE bridge_callbacks ADD COLUMN task_run_id uuid REFERENCES task_runs(id) ON DELETE CASCADE;
+	UPDATE bridge_callbacks SET task_run_id = (
+		SELECT task_runs.id FROM task_runs
+		WHERE task_runs.job_run_id = bridge_callbacks.job_run_id AND task_runs.status = 'pending_bridge'
+		LIMIT 1
+	);
+	DELETE FROM bridge_callbacks WHERE task_run_id IS NULL;
+	ALTER TABLE bridge_callbacks ALTER COLUMN task_run_id SET NOT NULL;
+	CREATE INDEX idx_bridge_callbacks_task_run_id ON bridge_callbacks (task_run_id);
+	`).Error
+}
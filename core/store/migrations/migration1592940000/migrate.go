@@ -0,0 +1,18 @@
+package migration1592940000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate records the transaction which created each sync event, so that the
+// consumers can follow them in the order their transactions started rather
+// than of their IDs, which transactions committing out of order would have
+// them skip. The existing cursors are moved past the events already created.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE sync_events ADD COLUMN txid bigint NOT NULL DEFAULT txid_current();
+	CREATE INDEX idx_sync_events_txid_id ON sync_events (txid, id);
+	ALTER TABLE sync_event_cursors ADD COLUMN last_txid bigint NOT NULL DEFAULT 0;
+	UPDATE sync_event_cursors SET last_txid = txid_current();
+	`).Error
+}
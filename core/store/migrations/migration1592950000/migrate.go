@@ -0,0 +1,33 @@
+package migration1592950000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate counts the access violations of an endpoint from the same address
+// in the same hour in one row, rather than recording each, which a flood of
+// requests would make the table grow without bound with. The violations
+// recorded already are merged.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE access_violations
+		ADD COLUMN window_start timestamptz,
+		ADD COLUMN count bigint NOT NULL DEFAULT 1,
+		ADD COLUMN last_seen_at timestamptz;
+	UPDATE access_violations SET window_start = date_trunc('hour', created_at), last_seen_at = created_at;
+	UPDATE access_violations SET count = merged.count, last_seen_at = merged.last_seen_at
+	FROM (
+		SELECT MIN(id) AS id, COUNT(*) AS count, MAX(created_at) AS last_seen_at
+		FROM access_violations
+		GROUP BY endpoint, remote_ip, window_start
+	) merged
+	WHERE access_violations.id = merged.id;
+	DELETE FROM access_violations WHERE id NOT IN (
+		SELECT MIN(id) FROM access_violations GROUP BY endpoint, remote_ip, window_start
+	);
+	ALTER TABLE access_violations
+		ALTER COLUMN window_start SET NOT NULL,
+		ALTER COLUMN last_seen_at SET NOT NULL;
+	CREATE UNIQUE INDEX idx_access_violations_endpoint_remote_ip_window_start ON access_violations (endpoint, remote_ip, window_start);
+	`).Error
+}
@@ -0,0 +1,16 @@
+package migration1592960000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate lets a performUpkeep transaction be recorded before it is sent,
+// without the hash and gas price it is sent with, so that an upkeep is not
+// performed twice when recording the transaction after sending it fails.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE upkeep_performs
+		ALTER COLUMN tx_hash DROP NOT NULL,
+		ALTER COLUMN gas_price DROP NOT NULL;
+	`).Error
+}
@@ -0,0 +1,18 @@
+package migration1592970000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate adds the cursors of the services fetching the logs of the chain
+// block after block, so that they resume from the last block they fetched
+// rather than from the last log they recorded.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE TABLE block_cursors (
+		consumer text PRIMARY KEY,
+		last_block bigint NOT NULL,
+		updated_at timestamptz NOT NULL
+	);
+	`).Error
+}
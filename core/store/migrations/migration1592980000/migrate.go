@@ -0,0 +1,14 @@
+package migration1592980000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate stops recording the parameters of slow queries, which may be
+// secrets such as tokens, and deletes the slow queries recorded with them.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	DELETE FROM slow_queries;
+	ALTER TABLE slow_queries DROP COLUMN params;
+	`).Error
+}
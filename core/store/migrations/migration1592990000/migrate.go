@@ -0,0 +1,14 @@
+package migration1592990000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate records the key fundings whose transactions could not be sent as
+// failed, rather than removing them from the audit log.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE key_fundings ADD COLUMN failed_at timestamp with time zone;
+	ALTER TABLE key_fundings ADD COLUMN error text;
+	`).Error
+}
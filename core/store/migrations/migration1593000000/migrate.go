@@ -0,0 +1,13 @@
+package migration1593000000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate indexes the consumptions of mqtt messages by when they were
+// recorded, so that those older than the dedupe window are pruned quickly.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	CREATE INDEX idx_mqtt_consumptions_created_at ON mqtt_consumptions (created_at);
+	`).Error
+}
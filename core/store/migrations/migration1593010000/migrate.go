@@ -0,0 +1,15 @@
+package migration1593010000
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// Migrate numbers job runs in the order the database creates them, as their
+// created_at is set by the node, so that paginated listings read from a
+// snapshot can tell which runs were created after it.
+func Migrate(tx *gorm.DB) error {
+	return tx.Exec(`
+	ALTER TABLE job_runs ADD COLUMN seq BIGSERIAL NOT NULL;
+	CREATE INDEX idx_job_runs_seq ON job_runs (seq);
+	`).Error
+}
@@ -0,0 +1,131 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	gormigrate "gopkg.in/gormigrate.v1"
+)
+
+// Status is the version of the schema of a database, and the migrations it
+// has yet to run.
+type Status struct {
+	// Current is the ID of the last migration run, or empty if none has run.
+	Current string `json:"current"`
+	// Latest is the ID of the last migration known to this version.
+	Latest  string   `json:"latest"`
+	Applied int      `json:"applied"`
+	Pending []string `json:"pending"`
+}
+
+// GetStatus returns the status of the migrations of the database.
+func GetStatus(db *gorm.DB) (Status, error) {
+	applied, err := appliedIDs(db)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Latest: all[len(all)-1].ID, Applied: len(applied), Pending: []string{}}
+	for _, m := range all {
+		if applied[m.ID] {
+			status.Current = m.ID
+		} else {
+			status.Pending = append(status.Pending, m.ID)
+		}
+	}
+	if len(applied) > len(all) {
+		return status, errors.New("database is newer than current chainlink version")
+	}
+	return status, nil
+}
+
+func appliedIDs(db *gorm.DB) (map[string]bool, error) {
+	options := gormigrate.DefaultOptions
+	var ids []string
+	err := db.Table(options.TableName).Pluck(options.IDColumnName, &ids).Error
+	if err != nil && noSuchTableRegex.MatchString(err.Error()) {
+		return map[string]bool{}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "error reading applied migrations")
+	}
+	applied := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		applied[id] = true
+	}
+	return applied, nil
+}
+
+// LockImpact is what running a pending migration costs: how long it took in
+// a dry run, and the tables it locked, which stay locked until every pending
+// migration has run, as they run in a single transaction.
+type LockImpact struct {
+	ID       string        `json:"id"`
+	Duration time.Duration `json:"duration"`
+	Locks    []TableLock   `json:"locks"`
+}
+
+// TableLock is a lock taken on a table by a migration.
+type TableLock struct {
+	Table string `json:"table"`
+	Mode  string `json:"mode"`
+	// EstimatedRows is the number of rows in the table estimated by
+	// postgres, which hints at how long rewriting the table would take.
+	EstimatedRows int64 `json:"estimatedRows"`
+}
+
+// DryRun runs the pending migrations in a transaction which is then rolled
+// back, and returns the locks each of them took and how long it took. The
+// locks are really taken for as long as the dry run lasts, so it should be
+// run when the node is stopped, or at a quiet time.
+func DryRun(db *gorm.DB) ([]LockImpact, error) {
+	applied, err := appliedIDs(db)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return nil, errors.Wrap(tx.Error, "error starting dry run")
+	}
+	defer tx.Rollback()
+
+	impacts := []LockImpact{}
+	seen := map[TableLock]bool{}
+	for _, m := range all {
+		if applied[m.ID] {
+			continue
+		}
+		start := time.Now()
+		if err := m.Migrate(tx); err != nil {
+			return impacts, errors.Wrapf(err, "migration %s failed in dry run", m.ID)
+		}
+		impact := LockImpact{ID: m.ID, Duration: time.Since(start), Locks: []TableLock{}}
+
+		locks, err := heldTableLocks(tx)
+		if err != nil {
+			return impacts, err
+		}
+		for _, lock := range locks {
+			key := TableLock{Table: lock.Table, Mode: lock.Mode}
+			if !seen[key] {
+				seen[key] = true
+				impact.Locks = append(impact.Locks, lock)
+			}
+		}
+		impacts = append(impacts, impact)
+	}
+	return impacts, nil
+}
+
+// heldTableLocks returns the locks on tables held by the transaction.
+func heldTableLocks(tx *gorm.DB) ([]TableLock, error) {
+	var locks []TableLock
+	err := tx.Raw(`
+		SELECT c.relname AS "table", l.mode AS mode, c.reltuples::bigint AS estimated_rows
+		FROM pg_locks l
+		JOIN pg_class c ON c.oid = l.relation
+		WHERE l.pid = pg_backend_pid() AND l.granted AND c.relkind IN ('r', 'p')
+		ORDER BY c.relname, l.mode`).Scan(&locks).Error
+	return locks, errors.Wrap(err, "error listing locks taken by migrations")
+}
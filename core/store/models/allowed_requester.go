@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AllowedRequester is an address allowed to request runs of a runlog job, or
+// of every runlog job if JobSpecID is nil. Once an allowlist has an address,
+// the runlog jobs it applies to only service the requesters on it, so that
+// operators can stop servicing abusive or unpaying requesters without
+// removing the job.
+type AllowedRequester struct {
+	ID        uint64         `json:"-" gorm:"primary_key"`
+	JobSpecID *ID            `json:"jobSpecId,omitempty"`
+	Address   common.Address `json:"address" gorm:"not null"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// AllowedRequesterRequest is the incoming record used to add an address to a
+// requester allowlist.
+type AllowedRequesterRequest struct {
+	Address common.Address `json:"address"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (r AllowedRequester) GetID() string {
+	return r.Address.Hex()
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (r AllowedRequester) GetName() string {
+	return "allowed_requesters"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (r *AllowedRequester) SetID(value string) error {
+	r.Address = common.HexToAddress(value)
+	return nil
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// BlockConditionConfig is the condition on new heads a blockcondition
+// initiator runs its job on.
+type BlockConditionConfig struct {
+	Expression BlockCondition `json:"expression"`
+	// EdgeTriggered runs the job only on the heads the condition starts to
+	// hold on, rather than on every head it holds on.
+	EdgeTriggered bool `json:"edgeTriggered,omitempty"`
+}
+
+// Value is defined so that we can store BlockConditionConfig as JSONB, as
+// for PollTimerConfig.
+func (bcc BlockConditionConfig) Value() (driver.Value, error) {
+	return json.Marshal(bcc)
+}
+
+// Scan is defined so that we can read BlockConditionConfig as JSONB, as for
+// PollTimerConfig.
+func (bcc *BlockConditionConfig) Scan(value interface{}) error {
+	if value == nil {
+		*bcc = BlockConditionConfig{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid Scan Source")
+	}
+	return json.Unmarshal(b, bcc)
+}
+
+// BlockCondition is an expression on the values of a block, made of clauses
+// joined by &&, each comparing a variable, or a variable modulo an integer,
+// to an integer with one of <, <=, >, >=, == or !=. For instance:
+//
+//   basefee < 30000000000 && number % 10 == 0
+//
+// The variables are the block's number, timestamp, gasused, gaslimit and
+// basefee, in wei. Only number is known from a head alone.
+type BlockCondition string
+
+// blockConditionVariables are the variables of a BlockCondition, and whether
+// the block must be fetched to know them.
+var blockConditionVariables = map[string]bool{
+	"number":    false,
+	"timestamp": true,
+	"gasused":   true,
+	"gaslimit":  true,
+	"basefee":   true,
+}
+
+var blockConditionClause = regexp.MustCompile(`^\s*([a-z]+)\s*(?:%\s*(\d+)\s*)?(<=|>=|==|!=|<|>)\s*(\d+)\s*$`)
+
+type blockConditionTerm struct {
+	variable string
+	modulo   *big.Int
+	op       string
+	value    *big.Int
+}
+
+func (c BlockCondition) parse() ([]blockConditionTerm, error) {
+	if strings.TrimSpace(string(c)) == "" {
+		return nil, fmt.Errorf("block condition must not be empty")
+	}
+	var terms []blockConditionTerm
+	for _, clause := range strings.Split(strings.ToLower(string(c)), "&&") {
+		match := blockConditionClause.FindStringSubmatch(clause)
+		if match == nil {
+			return nil, fmt.Errorf("invalid block condition clause %q", strings.TrimSpace(clause))
+		}
+		if _, ok := blockConditionVariables[match[1]]; !ok {
+			return nil, fmt.Errorf("unknown block condition variable %s", match[1])
+		}
+		term := blockConditionTerm{variable: match[1], op: match[3]}
+		term.value, _ = new(big.Int).SetString(match[4], 10)
+		if match[2] != "" {
+			term.modulo, _ = new(big.Int).SetString(match[2], 10)
+			if term.modulo.Sign() == 0 {
+				return nil, fmt.Errorf("invalid block condition clause %q: modulo 0", strings.TrimSpace(clause))
+			}
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// Validate returns an error if the condition is not a valid expression.
+func (c BlockCondition) Validate() error {
+	_, err := c.parse()
+	return err
+}
+
+// NeedsBlock returns true if the condition has variables which are not
+// known from a head alone.
+func (c BlockCondition) NeedsBlock() bool {
+	terms, _ := c.parse()
+	for _, term := range terms {
+		if blockConditionVariables[term.variable] {
+			return true
+		}
+	}
+	return false
+}
+
+// Eval returns whether the condition holds given the values of its
+// variables, or an error if one of them has no value, as basefee before
+// EIP-1559.
+func (c BlockCondition) Eval(vars map[string]*big.Int) (bool, error) {
+	terms, err := c.parse()
+	if err != nil {
+		return false, err
+	}
+	for _, term := range terms {
+		v, ok := vars[term.variable]
+		if !ok || v == nil {
+			return false, fmt.Errorf("block has no %s", term.variable)
+		}
+		if term.modulo != nil {
+			v = new(big.Int).Mod(v, term.modulo)
+		}
+		cmp := v.Cmp(term.value)
+		var holds bool
+		switch term.op {
+		case "<":
+			holds = cmp < 0
+		case "<=":
+			holds = cmp <= 0
+		case ">":
+			holds = cmp > 0
+		case ">=":
+			holds = cmp >= 0
+		case "==":
+			holds = cmp == 0
+		case "!=":
+			holds = cmp != 0
+		}
+		if !holds {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package models_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockCondition_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expression string
+		wantError  bool
+	}{
+		{"number % 100 == 0", false},
+		{"basefee<30000000000 && number%10==0", false},
+		{"GasUsed >= 5000000", false},
+		{"", true},
+		{"number", true},
+		{"difficulty > 1", true},
+		{"number % 0 == 0", true},
+		{"number == -1", true},
+		{"number == 1 || number == 2", true},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			err := models.BlockCondition(test.expression).Validate()
+			assert.Equal(t, test.wantError, err != nil, "got %v", err)
+		})
+	}
+}
+
+func TestBlockCondition_Eval(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]*big.Int{
+		"number":  big.NewInt(1200),
+		"basefee": big.NewInt(25000000000),
+	}
+	tests := []struct {
+		expression string
+		holds      bool
+	}{
+		{"number % 100 == 0", true},
+		{"number % 7 == 0", false},
+		{"basefee < 30000000000 && number % 10 == 0", true},
+		{"basefee < 30000000000 && number > 1200", false},
+		{"number >= 1200 && number <= 1200 && number != 1", true},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			holds, err := models.BlockCondition(test.expression).Eval(vars)
+			require.NoError(t, err)
+			assert.Equal(t, test.holds, holds)
+		})
+	}
+
+	_, err := models.BlockCondition("gasused > 0").Eval(vars)
+	assert.EqualError(t, err, "block has no gasused")
+	assert.True(t, models.BlockCondition("number > 0 && basefee > 0").NeedsBlock())
+	assert.False(t, models.BlockCondition("number > 0").NeedsBlock())
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// BridgeAuthType is a scheme used to authenticate requests to a bridge, in
+// addition to the bridge's outgoing token.
+type BridgeAuthType string
+
+const (
+	// BridgeAuthHeaders sends a fixed set of headers with every request.
+	BridgeAuthHeaders BridgeAuthType = "headers"
+	// BridgeAuthBasic sends HTTP basic auth credentials with every request.
+	BridgeAuthBasic BridgeAuthType = "basic"
+	// BridgeAuthOAuth2 fetches an access token from an OAuth2 token endpoint
+	// using the client credentials grant, refreshing it once it expires.
+	BridgeAuthOAuth2 BridgeAuthType = "oauth2"
+)
+
+// BridgeAuth holds the credentials of a bridge's authentication scheme. Only
+// the fields relevant to its Type are used.
+type BridgeAuth struct {
+	Type         BridgeAuthType    `json:"type"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Username     string            `json:"username,omitempty"`
+	Password     string            `json:"password,omitempty"`
+	TokenURL     string            `json:"tokenUrl,omitempty"`
+	ClientID     string            `json:"clientId,omitempty"`
+	ClientSecret string            `json:"clientSecret,omitempty"`
+	Scopes       []string          `json:"scopes,omitempty"`
+}
+
+// SetAuth stores the given credentials on the bridge, in a column encrypted
+// with the node's master key. A nil auth removes any credentials stored
+// before.
+func (bt *BridgeType) SetAuth(auth *BridgeAuth) error {
+	if auth == nil {
+		bt.AuthType = ""
+		bt.AuthCredentials = ""
+		return nil
+	}
+
+	plaintext, err := json.Marshal(auth)
+	if err != nil {
+		return err
+	}
+	bt.AuthType = auth.Type
+	bt.AuthCredentials = EncryptedString(plaintext)
+	return nil
+}
+
+// Auth returns the credentials stored on the bridge, or nil if the bridge has
+// none.
+func (bt BridgeType) Auth() (*BridgeAuth, error) {
+	if bt.AuthCredentials == "" {
+		return nil, nil
+	}
+
+	var auth BridgeAuth
+	err := json.Unmarshal([]byte(bt.AuthCredentials), &auth)
+	return &auth, errors.Wrapf(err, "parsing auth of bridge %s", bt.Name)
+}
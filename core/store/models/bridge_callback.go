@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	null "gopkg.in/guregu/null.v3"
+)
+
+// BridgeCallback is a single-use token a bridge calls a run pending on it
+// back with, at the response URL it was sent. It only resumes the task run
+// which called the bridge. Only the hash of the token is kept.
+type BridgeCallback struct {
+	ID        uint64 `gorm:"primary_key"`
+	JobRunID  *ID
+	TaskRunID *ID
+	TokenHash string
+	// ExpiresAt is when the run errors if the bridge has not called back by
+	// then, the token never expiring if it is null.
+	ExpiresAt null.Time
+	CreatedAt time.Time
+}
+
+// NewBridgeCallback returns a new token for the bridge the task run is sending
+// a request to, and the callback to save, which expires after timeout from
+// now unless timeout is zero.
+func NewBridgeCallback(jobRunID, taskRunID *ID, timeout time.Duration, now time.Time) (string, BridgeCallback, error) {
+	token := utils.NewSecret(24)
+	hash, err := BridgeCallbackTokenHash(token)
+	if err != nil {
+		return "", BridgeCallback{}, err
+	}
+	callback := BridgeCallback{JobRunID: jobRunID, TaskRunID: taskRunID, TokenHash: hash}
+	if timeout > 0 {
+		callback.ExpiresAt = null.TimeFrom(now.Add(timeout))
+	}
+	return token, callback, nil
+}
+
+// BridgeCallbackTokenHash returns the hash a callback token is kept as.
+func BridgeCallbackTokenHash(token string) (string, error) {
+	return utils.Sha256(token)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	null "gopkg.in/guregu/null.v3"
+)
+
+// BridgeHealth tracks the outcome of the requests made to a bridge. Once a
+// bridge has failed BRIDGE_CIRCUIT_BREAKER_THRESHOLD requests in a row its
+// circuit is opened, failing runs without calling it until a probe request
+// succeeds.
+type BridgeHealth struct {
+	BridgeName          TaskType    `json:"-" gorm:"primary_key"`
+	Successes           uint64      `json:"successes"`
+	Failures            uint64      `json:"failures"`
+	ConsecutiveFailures uint32      `json:"consecutiveFailures"`
+	LatencyTotal        Duration    `json:"-"`
+	LastError           null.String `json:"lastError"`
+	LastSuccessAt       null.Time   `json:"lastSuccessAt"`
+	LastFailureAt       null.Time   `json:"lastFailureAt"`
+	CircuitOpenedAt     null.Time   `json:"circuitOpenedAt"`
+	UpdatedAt           time.Time   `json:"updatedAt"`
+}
+
+// Requests returns the number of requests made to the bridge.
+func (h BridgeHealth) Requests() uint64 {
+	return h.Successes + h.Failures
+}
+
+// SuccessRate returns the fraction of requests to the bridge which
+// succeeded, or 1 if none were made.
+func (h BridgeHealth) SuccessRate() float64 {
+	if h.Requests() == 0 {
+		return 1
+	}
+	return float64(h.Successes) / float64(h.Requests())
+}
+
+// AverageLatency returns the mean time taken by the bridge to respond.
+func (h BridgeHealth) AverageLatency() time.Duration {
+	if h.Requests() == 0 {
+		return 0
+	}
+	return h.LatencyTotal.Duration() / time.Duration(h.Requests())
+}
+
+// CircuitOpen returns true if requests to the bridge are being refused.
+func (h BridgeHealth) CircuitOpen() bool {
+	return h.CircuitOpenedAt.Valid
+}
+
+// MarshalJSON adds the success rate, average latency and circuit state to the
+// recorded counts.
+func (h BridgeHealth) MarshalJSON() ([]byte, error) {
+	type Alias BridgeHealth
+	return json.Marshal(&struct {
+		Alias
+		SuccessRate    float64  `json:"successRate"`
+		AverageLatency Duration `json:"averageLatency"`
+		CircuitOpen    bool     `json:"circuitOpen"`
+	}{
+		Alias(h),
+		h.SuccessRate(),
+		MustMakeDuration(h.AverageLatency()),
+		h.CircuitOpen(),
+	})
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// A BridgeResponse is a response of a bridge cached in the database, shared
+// by every run sending the bridge the same data until it expires.
+type BridgeResponse struct {
+	Key        string `gorm:"primary_key"`
+	BridgeName TaskType
+	Body       []byte
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+}
+
+// BridgeCacheKey identifies the responses of a bridge to the given request
+// data. The ID and response URL of the run are left out, since they differ
+// for every request.
+func BridgeCacheKey(name TaskType, data JSON) string {
+	h := sha256.New()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(data.String()))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -14,6 +14,10 @@ type BridgeRunResult struct {
 	ErrorMessage    null.String `json:"error"`
 	ExternalPending bool        `json:"pending"`
 	AccessToken     string      `json:"accessToken"`
+	// TaskRunID is the task run the result is for, among those of a task
+	// graph waiting on different bridges, set by who authenticated the bridge
+	// rather than parsed. The first task run waiting on a bridge if nil.
+	TaskRunID *ID `json:"-"`
 }
 
 // UnmarshalJSON parses the given input and updates the BridgeRunResult in the
@@ -7,14 +7,42 @@ import (
 
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/utils"
+
+	null "gopkg.in/guregu/null.v3"
+)
+
+// BridgeMode is how the node talks to a bridge.
+type BridgeMode string
+
+const (
+	// BridgeModeHTTP bridges are sent a POST request by the node for every
+	// task they perform.
+	BridgeModeHTTP BridgeMode = "http"
+	// BridgeModeStream bridges hold a websocket connection open with the node
+	// and push updates to it, which trigger runs of stream initiated jobs.
+	BridgeModeStream BridgeMode = "stream"
 )
 
+// OrDefault returns the mode, or BridgeModeHTTP if none was given.
+func (m BridgeMode) OrDefault() BridgeMode {
+	if m == "" {
+		return BridgeModeHTTP
+	}
+	return m
+}
+
 // BridgeTypeRequest is the incoming record used to create a BridgeType
 type BridgeTypeRequest struct {
 	Name                   TaskType     `json:"name"`
 	URL                    WebURL       `json:"url"`
+	Mode                   BridgeMode   `json:"mode"`
 	Confirmations          uint32       `json:"confirmations"`
 	MinimumContractPayment *assets.Link `json:"minimumContractPayment"`
+	Auth                   *BridgeAuth  `json:"auth,omitempty"`
+	CacheTTL               Duration     `json:"cacheTTL"`
+	ClientCertificate      string       `json:"clientCertificate,omitempty"`
+	MinInterval            Duration     `json:"minInterval"`
+	Namespace              string       `json:"namespace,omitempty"`
 }
 
 // GetID returns the ID of this structure for jsonapi serialization.
@@ -36,12 +64,18 @@ func (bt *BridgeTypeRequest) SetID(value string) error {
 
 // BridgeTypeAuthentication is the record returned in response to a request to create a BridgeType
 type BridgeTypeAuthentication struct {
-	Name                   TaskType     `json:"name"`
-	URL                    WebURL       `json:"url"`
-	Confirmations          uint32       `json:"confirmations"`
-	IncomingToken          string       `json:"incomingToken"`
-	OutgoingToken          string       `json:"outgoingToken"`
-	MinimumContractPayment *assets.Link `json:"minimumContractPayment"`
+	Name                   TaskType       `json:"name"`
+	URL                    WebURL         `json:"url"`
+	Mode                   BridgeMode     `json:"mode"`
+	AuthType               BridgeAuthType `json:"authType"`
+	Confirmations          uint32         `json:"confirmations"`
+	IncomingToken          string         `json:"incomingToken"`
+	OutgoingToken          string         `json:"outgoingToken"`
+	MinimumContractPayment *assets.Link   `json:"minimumContractPayment"`
+	CacheTTL               Duration       `json:"cacheTTL"`
+	ClientCertificate      string         `json:"clientCertificate,omitempty"`
+	MinInterval            Duration       `json:"minInterval"`
+	Namespace              string         `json:"namespace,omitempty"`
 }
 
 // GetID returns the ID of this structure for jsonapi serialization.
@@ -64,15 +98,28 @@ func (bt *BridgeTypeAuthentication) SetID(value string) error {
 // BridgeType is used for external adapters and has fields for
 // the name of the adapter and its URL.
 type BridgeType struct {
-	Name                   TaskType     `json:"name" gorm:"primary_key"`
-	URL                    WebURL       `json:"url"`
-	Confirmations          uint32       `json:"confirmations"`
-	IncomingTokenHash      string       `json:"-"`
-	Salt                   string       `json:"-"`
-	OutgoingToken          string       `json:"outgoingToken"`
-	MinimumContractPayment *assets.Link `json:"minimumContractPayment" gorm:"type:varchar(255)"`
-	CreatedAt              time.Time    `json:"-"`
-	UpdatedAt              time.Time    `json:"-"`
+	Name                   TaskType        `json:"name" gorm:"primary_key"`
+	URL                    WebURL          `json:"url"`
+	Mode                   BridgeMode      `json:"mode" gorm:"not null;default:'http'"`
+	AuthType               BridgeAuthType  `json:"authType"`
+	AuthCredentials        EncryptedString `json:"-"`
+	Confirmations          uint32          `json:"confirmations"`
+	IncomingTokenHash      string          `json:"-"`
+	Salt                   string          `json:"-"`
+	OutgoingToken          EncryptedString `json:"outgoingToken"`
+	MinimumContractPayment *assets.Link    `json:"minimumContractPayment" gorm:"type:varchar(255)"`
+	CacheTTL               Duration        `json:"cacheTTL" gorm:"column:cache_ttl;not null;default:0"`
+	ClientCertificate      string          `json:"clientCertificate,omitempty"`
+	MinInterval            Duration        `json:"minInterval" gorm:"column:min_interval;not null;default:0"`
+	Namespace              string          `json:"namespace,omitempty" gorm:"not null"`
+	CreatedAt              time.Time       `json:"-"`
+	UpdatedAt              time.Time       `json:"-"`
+
+	// The tokens replaced by the last rotation, which are still used until
+	// PreviousTokensExpireAt.
+	PreviousIncomingTokenHash string          `json:"-"`
+	PreviousOutgoingToken     EncryptedString `json:"-"`
+	PreviousTokensExpireAt    null.Time       `json:"previousTokensExpireAt"`
 }
 
 // GetID returns the ID of this structure for jsonapi serialization.
@@ -108,29 +155,99 @@ func NewBridgeType(btr *BridgeTypeRequest) (*BridgeTypeAuthentication,
 	return &BridgeTypeAuthentication{
 			Name:                   btr.Name,
 			URL:                    btr.URL,
+			Mode:                   btr.Mode.OrDefault(),
 			Confirmations:          btr.Confirmations,
 			IncomingToken:          incomingToken,
 			OutgoingToken:          outgoingToken,
 			MinimumContractPayment: btr.MinimumContractPayment,
+			CacheTTL:               btr.CacheTTL,
+			ClientCertificate:      btr.ClientCertificate,
+			MinInterval:            btr.MinInterval,
+			Namespace:              btr.Namespace,
 		}, &BridgeType{
 			Name:                   btr.Name,
 			URL:                    btr.URL,
+			Mode:                   btr.Mode.OrDefault(),
 			Confirmations:          btr.Confirmations,
 			IncomingTokenHash:      hash,
 			Salt:                   salt,
-			OutgoingToken:          outgoingToken,
+			OutgoingToken:          EncryptedString(outgoingToken),
 			MinimumContractPayment: btr.MinimumContractPayment,
+			CacheTTL:               btr.CacheTTL,
+			ClientCertificate:      btr.ClientCertificate,
+			MinInterval:            btr.MinInterval,
+			Namespace:              btr.Namespace,
 		}, nil
 }
 
+// BridgeTokenRotationRequest is the request to rotate the tokens of a
+// bridge.
+type BridgeTokenRotationRequest struct {
+	// GracePeriod is how long the tokens being replaced are still used for,
+	// alongside the new ones, for the bridge to be updated in the meantime.
+	GracePeriod *Duration `json:"gracePeriod"`
+}
+
+// RotateTokens replaces the incoming and outgoing tokens of the bridge with
+// new ones, returned in plaintext as when the bridge was created. The tokens
+// replaced are kept until the grace period after now is over, none being kept
+// without a grace period.
+func (bt *BridgeType) RotateTokens(gracePeriod time.Duration, now time.Time) (*BridgeTypeAuthentication, error) {
+	incomingToken := utils.NewSecret(24)
+	outgoingToken := utils.NewSecret(24)
+	hash, err := incomingTokenHash(incomingToken, bt.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	bt.PreviousIncomingTokenHash = ""
+	bt.PreviousOutgoingToken = ""
+	bt.PreviousTokensExpireAt = null.Time{}
+	if gracePeriod > 0 {
+		bt.PreviousIncomingTokenHash = bt.IncomingTokenHash
+		bt.PreviousOutgoingToken = bt.OutgoingToken
+		bt.PreviousTokensExpireAt = null.TimeFrom(now.Add(gracePeriod))
+	}
+	bt.IncomingTokenHash = hash
+	bt.OutgoingToken = EncryptedString(outgoingToken)
+
+	return &BridgeTypeAuthentication{
+		Name:                   bt.Name,
+		URL:                    bt.URL,
+		Mode:                   bt.Mode,
+		AuthType:               bt.AuthType,
+		Confirmations:          bt.Confirmations,
+		IncomingToken:          incomingToken,
+		OutgoingToken:          outgoingToken,
+		MinimumContractPayment: bt.MinimumContractPayment,
+		CacheTTL:               bt.CacheTTL,
+		ClientCertificate:      bt.ClientCertificate,
+		MinInterval:            bt.MinInterval,
+		Namespace:              bt.Namespace,
+	}, nil
+}
+
+// InTokenGracePeriod returns true if the tokens replaced by the last rotation
+// of the bridge are still used at now.
+func (bt BridgeType) InTokenGracePeriod(now time.Time) bool {
+	return bt.PreviousTokensExpireAt.Valid && now.Before(bt.PreviousTokensExpireAt.Time)
+}
+
 // AuthenticateBridgeType returns true if the passed token matches its
-// IncomingToken, or returns false with an error.
+// IncomingToken, or the one it replaced during the grace period of its
+// rotation, or returns false with an error.
 func AuthenticateBridgeType(bt *BridgeType, token string) (bool, error) {
 	hash, err := incomingTokenHash(token, bt.Salt)
 	if err != nil {
 		return false, err
 	}
-	return subtle.ConstantTimeCompare([]byte(hash), []byte(bt.IncomingTokenHash)) == 1, nil
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(bt.IncomingTokenHash)) == 1 {
+		return true, nil
+	}
+	if bt.PreviousIncomingTokenHash == "" || !bt.InTokenGracePeriod(time.Now()) {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(bt.PreviousIncomingTokenHash)) == 1, nil
 }
 
 func incomingTokenHash(token, salt string) (string, error) {
@@ -2,12 +2,14 @@ package models_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
 )
 
 func TestBridgeType_Authenticate(t *testing.T) {
@@ -36,3 +38,39 @@ func TestBridgeType_Authenticate(t *testing.T) {
 		})
 	}
 }
+
+func TestBridgeType_RotateTokens(t *testing.T) {
+	t.Parallel()
+
+	bta, bt := cltest.NewBridgeType(t)
+	previousOutgoing := bt.OutgoingToken
+
+	rotated, err := bt.RotateTokens(time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.NotEqual(t, bta.IncomingToken, rotated.IncomingToken)
+	assert.Equal(t, rotated.OutgoingToken, bt.OutgoingToken.String())
+	assert.Equal(t, previousOutgoing, bt.PreviousOutgoingToken)
+	assert.True(t, bt.InTokenGracePeriod(time.Now()))
+
+	for _, token := range []string{bta.IncomingToken, rotated.IncomingToken} {
+		ok, err := models.AuthenticateBridgeType(bt, token)
+		require.NoError(t, err)
+		assert.True(t, ok, "both tokens should authenticate during the grace period")
+	}
+
+	bt.PreviousTokensExpireAt = null.TimeFrom(time.Now().Add(-time.Second))
+	ok, err := models.AuthenticateBridgeType(bt, bta.IncomingToken)
+	require.NoError(t, err)
+	assert.False(t, ok, "the previous token should not authenticate after the grace period")
+
+	again, err := bt.RotateTokens(0, time.Now())
+	require.NoError(t, err)
+	assert.False(t, bt.InTokenGracePeriod(time.Now()))
+	assert.Empty(t, bt.PreviousIncomingTokenHash)
+	ok, err = models.AuthenticateBridgeType(bt, rotated.IncomingToken)
+	require.NoError(t, err)
+	assert.False(t, ok, "tokens rotated without a grace period should stop authenticating at once")
+	ok, err = models.AuthenticateBridgeType(bt, again.IncomingToken)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
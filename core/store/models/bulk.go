@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -25,6 +26,45 @@ func ValidateBulkDeleteRunRequest(request *BulkDeleteRunRequest) error {
 	return nil
 }
 
+// BulkUpdateRunRequest describes the query for the runs to move to another
+// status at once, such as those pending on a bridge which was deleted.
+type BulkUpdateRunRequest struct {
+	// Status are the statuses of the runs to update.
+	Status RunStatusCollection `json:"status"`
+	// Bridge, if set, restricts the update to the runs waiting on a task of
+	// that bridge.
+	Bridge TaskType `json:"bridge"`
+	// UpdatedBefore, if set, restricts the update to the runs last updated
+	// before then.
+	UpdatedBefore time.Time `json:"updatedBefore"`
+	// NewStatus is the status the runs are moved to.
+	NewStatus RunStatus `json:"newStatus"`
+	// Reason, if set, is recorded as the error of the runs.
+	Reason string `json:"reason"`
+}
+
+// ValidateBulkUpdateRunRequest returns an error unless the request moves
+// unfinished runs to cancelled or errored. In progress runs may be executing,
+// and are cancelled one at a time so that their execution is interrupted.
+func ValidateBulkUpdateRunRequest(request *BulkUpdateRunRequest) error {
+	if request.NewStatus != RunStatusCancelled && request.NewStatus != RunStatusErrored {
+		return fmt.Errorf("cannot update Runs to status %s", request.NewStatus)
+	}
+	if len(request.Status) == 0 {
+		return errors.New("status of the Runs to update is required")
+	}
+	for _, status := range request.Status {
+		if status == RunStatusInProgress {
+			return fmt.Errorf("cannot update Runs with status %s, which may be executing: cancel them one at a time", status)
+		}
+		if !status.Pending() && status != RunStatusUnstarted {
+			return fmt.Errorf("cannot update Runs with status %s", status)
+		}
+	}
+
+	return nil
+}
+
 // RunStatusCollection is an array of RunStatus.
 type RunStatusCollection []RunStatus
 
@@ -63,3 +103,19 @@ func (r *RunStatusCollection) Scan(value interface{}) error {
 	*r = collection
 	return nil
 }
+
+// BulkJobSpecsRequest is a request to create many jobs at once.
+type BulkJobSpecsRequest struct {
+	Specs []JobSpecRequest `json:"specs"`
+	// Atomic creates all the jobs in a single transaction, or none of them if
+	// any is invalid.
+	Atomic bool `json:"atomic"`
+}
+
+// BulkJobIDsRequest is a request to archive or restore many jobs at once.
+type BulkJobIDsRequest struct {
+	IDs []string `json:"ids"`
+	// Atomic archives or restores all the jobs in a single transaction, or
+	// none of them if any cannot be.
+	Atomic bool `json:"atomic"`
+}
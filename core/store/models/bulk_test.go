@@ -27,3 +27,40 @@ func TestBulkDeleteRunTask_ValidateBulkDeleteRunRequest(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestBulkUpdateRunRequest_ValidateBulkUpdateRunRequest(t *testing.T) {
+	err := models.ValidateBulkUpdateRunRequest(&models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusPendingBridge},
+		NewStatus: models.RunStatusCancelled,
+	})
+	assert.NoError(t, err)
+
+	err = models.ValidateBulkUpdateRunRequest(&models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusUnstarted, models.RunStatusPendingSleep},
+		NewStatus: models.RunStatusErrored,
+	})
+	assert.NoError(t, err)
+
+	err = models.ValidateBulkUpdateRunRequest(&models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusInProgress},
+		NewStatus: models.RunStatusCancelled,
+	})
+	assert.Error(t, err, "in progress runs may be executing")
+
+	err = models.ValidateBulkUpdateRunRequest(&models.BulkUpdateRunRequest{
+		NewStatus: models.RunStatusCancelled,
+	})
+	assert.Error(t, err)
+
+	err = models.ValidateBulkUpdateRunRequest(&models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusCompleted},
+		NewStatus: models.RunStatusCancelled,
+	})
+	assert.Error(t, err)
+
+	err = models.ValidateBulkUpdateRunRequest(&models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusPendingBridge},
+		NewStatus: models.RunStatusCompleted,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultClientCertificate is the name of the client certificate presented
+// to servers asking for one, unless a bridge names its own.
+const DefaultClientCertificate = "default"
+
+// ClientCertificateRequest is the incoming record used to create or replace
+// a ClientCertificate. All certificates are PEM encoded.
+type ClientCertificateRequest struct {
+	Name          string `json:"name"`
+	Certificate   string `json:"certificate"`
+	PrivateKey    string `json:"privateKey"`
+	CACertificate string `json:"caCertificate,omitempty"`
+}
+
+// ClientCertificate is a certificate and private key presented by the node
+// to servers requiring mutual TLS, for outgoing bridge and http adapter
+// requests. The key material is stored in a column encrypted with the node's
+// master key.
+type ClientCertificate struct {
+	Name      string          `json:"name" gorm:"primary_key"`
+	Subject   string          `json:"subject"`
+	NotAfter  time.Time       `json:"notAfter"`
+	PEM       EncryptedString `json:"-" gorm:"column:pem"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (cc ClientCertificate) GetID() string {
+	return cc.Name
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (cc ClientCertificate) GetName() string {
+	return "client_certificates"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (cc *ClientCertificate) SetID(value string) error {
+	cc.Name = value
+	return nil
+}
+
+// NewClientCertificate checks that the requested certificate matches its
+// private key, and keeps both for them to be encrypted when saved.
+func NewClientCertificate(ccr *ClientCertificateRequest) (*ClientCertificate, error) {
+	pair, err := tls.X509KeyPair([]byte(ccr.Certificate), []byte(ccr.PrivateKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing client certificate")
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing client certificate")
+	}
+	if ccr.CACertificate != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(ccr.CACertificate)) {
+		return nil, errors.New("parsing CA certificate: no PEM encoded certificate found")
+	}
+
+	plaintext, err := json.Marshal(ccr)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientCertificate{
+		Name:     ccr.Name,
+		Subject:  leaf.Subject.String(),
+		NotAfter: leaf.NotAfter,
+		PEM:      EncryptedString(plaintext),
+	}, nil
+}
+
+// TLSConfig returns the TLS configuration presenting the certificate.
+// Servers are verified against the system's roots, and the certificate's CA
+// if it has one.
+func (cc ClientCertificate) TLSConfig() (*tls.Config, error) {
+	var ccr ClientCertificateRequest
+	if err := json.Unmarshal([]byte(cc.PEM), &ccr); err != nil {
+		return nil, errors.Wrapf(err, "parsing client certificate %s", cc.Name)
+	}
+	pair, err := tls.X509KeyPair([]byte(ccr.Certificate), []byte(ccr.PrivateKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing client certificate %s", cc.Name)
+	}
+
+	config := &tls.Config{Certificates: []tls.Certificate{pair}}
+	if ccr.CACertificate != "" {
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		roots.AppendCertsFromPEM([]byte(ccr.CACertificate))
+		config.RootCAs = roots
+	}
+	return config, nil
+}
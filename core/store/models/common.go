@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -48,6 +49,9 @@ const (
 	RunStatusPendingBridge = RunStatus("pending_bridge")
 	// RunStatusPendingSleep is used for when a run is waiting on a sleep function to finish.
 	RunStatusPendingSleep = RunStatus("pending_sleep")
+	// RunStatusPendingConcurrency is used for when a run is parked until the
+	// number of runs executing falls below the configured concurrency limits.
+	RunStatusPendingConcurrency = RunStatus("pending_concurrency")
 	// RunStatusErrored is used for when a run has errored and will not complete.
 	RunStatusErrored = RunStatus("errored")
 	// RunStatusCompleted is used for when a run has successfully completed execution.
@@ -81,6 +85,11 @@ func (s RunStatus) PendingSleep() bool {
 	return s == RunStatusPendingSleep
 }
 
+// PendingConcurrency returns true if the status is pending_concurrency.
+func (s RunStatus) PendingConcurrency() bool {
+	return s == RunStatusPendingConcurrency
+}
+
 // Completed returns true if the status is RunStatusCompleted.
 func (s RunStatus) Completed() bool {
 	return s == RunStatusCompleted
@@ -98,7 +107,7 @@ func (s RunStatus) Errored() bool {
 
 // Pending returns true if the status is pending external or confirmations.
 func (s RunStatus) Pending() bool {
-	return s.PendingBridge() || s.PendingConfirmations() || s.PendingSleep() || s.PendingConnection()
+	return s.PendingBridge() || s.PendingConfirmations() || s.PendingSleep() || s.PendingConnection() || s.PendingConcurrency()
 }
 
 // Finished returns true if the status is final and can't be changed.
@@ -409,7 +418,8 @@ func (t *AnyTime) Scan(value interface{}) error {
 type Cron string
 
 // UnmarshalJSON parses the raw spec stored in JSON-encoded
-// data and stores it to the Cron string.
+// data and stores it to the Cron string. Its time zone is checked when its
+// initiator is validated, as it may be given by the initiator instead.
 func (c *Cron) UnmarshalJSON(b []byte) error {
 	var s string
 	err := json.Unmarshal(b, &s)
@@ -420,10 +430,6 @@ func (c *Cron) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
-	if !strings.HasPrefix(s, "CRON_TZ=") {
-		return errors.New("Cron: specs must specify a time zone using CRON_TZ, e.g. 'CRON_TZ=UTC 5 * * * *'")
-	}
-
 	_, err = CronParser.Parse(s)
 	if err != nil {
 		return fmt.Errorf("Cron: %v", err)
@@ -502,6 +508,21 @@ func (d *Duration) UnmarshalJSON(input []byte) error {
 	return nil
 }
 
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *Duration) UnmarshalText(input []byte) error {
+	v, err := time.ParseDuration(string(input))
+	if err != nil {
+		return err
+	}
+	*d, err = MakeDuration(v)
+	return err
+}
+
 func (d *Duration) Scan(v interface{}) (err error) {
 	switch tv := v.(type) {
 	case int64:
@@ -531,9 +552,56 @@ type SendEtherRequest struct {
 	Amount             *assets.Eth    `json:"amount"`
 }
 
+// TxResendRequest represents a request to send the unconfirmed transactions
+// in a range of nonces again, those from an address only if it is given.
+type TxResendRequest struct {
+	From           *common.Address `json:"from,omitempty"`
+	BeginningNonce uint64          `json:"beginningNonce"`
+	EndingNonce    uint64          `json:"endingNonce"`
+}
+
 // CreateKeyRequest represents a request to add an ethereum key.
 type CreateKeyRequest struct {
 	CurrentPassword string `json:"current_password"`
+	Namespace       string `json:"namespace"`
+}
+
+// RotateKeyRequest represents a request to replace an ethereum key by a new
+// one, optionally sweeping the funds of the old key to the new one.
+type RotateKeyRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Sweep           bool   `json:"sweep"`
+}
+
+// ExternalKeyRequest represents a request to add a key held by a KMS or HSM,
+// which KeyRef refers to, to the keys of the node.
+type ExternalKeyRequest struct {
+	CurrentPassword string `json:"current_password"`
+	KeyRef          string `json:"key_ref"`
+	Namespace       string `json:"namespace"`
+}
+
+// ExportKeysRequest represents a request to export the keys of the node,
+// encrypted with its password.
+type ExportKeysRequest struct {
+	CurrentPassword string `json:"current_password"`
+}
+
+// ImportKeysRequest represents a request to import a bundle of keys exported
+// from another node, or only to check it if DryRun is set.
+type ImportKeysRequest struct {
+	CurrentPassword string          `json:"current_password"`
+	Bundle          json.RawMessage `json:"bundle"`
+	DryRun          bool            `json:"dry_run"`
+}
+
+// VRFKeyRequest represents a request to create a VRF key encrypted with the
+// password, or to import the given encrypted key. Importing a key also needs
+// the password of the node's keystore.
+type VRFKeyRequest struct {
+	CurrentPassword string          `json:"current_password,omitempty"`
+	Password        string          `json:"password"`
+	Key             json.RawMessage `json:"key,omitempty"`
 }
 
 // AddressCollection is an array of common.Address
@@ -576,6 +644,36 @@ func (r *AddressCollection) Scan(value interface{}) error {
 	return nil
 }
 
+// StringCollection is an array of strings serializable to and from a
+// database as a comma separated list.
+type StringCollection []string
+
+// Value returns the string value to be written to the database.
+func (r StringCollection) Value() (driver.Value, error) {
+	return strings.Join(r, ","), nil
+}
+
+// Scan parses the database value as a string.
+func (r *StringCollection) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("Unable to convert %v of %T to StringCollection", value, value)
+	}
+
+	if len(str) == 0 {
+		*r = nil
+		return nil
+	}
+
+	*r = strings.Split(str, ",")
+	return nil
+}
+
 // Configuration stores key value pairs for overriding global configuration
 type Configuration struct {
 	gorm.Model
@@ -583,6 +681,66 @@ type Configuration struct {
 	Value string `gorm:"not null"`
 }
 
+// ConfigurationChange records a change made to the configuration while the
+// node was running, and who made it.
+type ConfigurationChange struct {
+	ID        uint64    `json:"-" gorm:"primary_key"`
+	Name      string    `json:"name"`
+	From      string    `json:"from" gorm:"column:from_value"`
+	To        string    `json:"to" gorm:"column:to_value"`
+	ChangedBy string    `json:"changedBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (c ConfigurationChange) GetID() string {
+	return strconv.FormatUint(c.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (c ConfigurationChange) GetName() string {
+	return "configuration_changes"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (c *ConfigurationChange) SetID(value string) error {
+	id, err := strconv.ParseUint(value, 10, 64)
+	c.ID = id
+	return err
+}
+
+// AccessViolation records the requests to an endpoint refused in an hour
+// because they came from an address outside the networks allowed to make
+// requests to it, with the method and path of the last of them.
+type AccessViolation struct {
+	ID          uint64    `json:"-" gorm:"primary_key"`
+	Endpoint    string    `json:"endpoint"`
+	RemoteIP    string    `json:"remoteIp"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	Count       uint64    `json:"count"`
+	WindowStart time.Time `json:"windowStart"`
+	CreatedAt   time.Time `json:"createdAt"`
+	LastSeenAt  time.Time `json:"lastSeenAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (v AccessViolation) GetID() string {
+	return strconv.FormatUint(v.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (v AccessViolation) GetName() string {
+	return "access_violations"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (v *AccessViolation) SetID(value string) error {
+	id, err := strconv.ParseUint(value, 10, 64)
+	v.ID = id
+	return err
+}
+
 // Merge returns a new map with all keys merged from right to left
 func Merge(inputs ...JSON) (JSON, error) {
 	output := make(map[string]interface{})
@@ -386,7 +386,7 @@ func TestCron_UnmarshalJSON_Invalid(t *testing.T) {
 		input     string
 		wantError string
 	}{
-		{"5-field cron without time zone", `"0 0/5 * * *"`, "Cron: specs must specify a time zone using CRON_TZ, e.g. 'CRON_TZ=UTC 5 * * * *'"},
+		{"cron with too many fields", `"CRON_TZ=UTC 0 0 0/5 * * * *"`, "Cron: expected 5 to 6 fields, found 7: [0 0 0/5 * * * *]"},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronHolidayLayout is the layout of the days of a CronCalendar's holidays.
+const cronHolidayLayout = "2006-01-02"
+
+// HasTimezone returns true if the spec gives its time zone, with a CRON_TZ
+// or TZ prefix.
+func (c Cron) HasTimezone() bool {
+	return strings.HasPrefix(string(c), "CRON_TZ=") || strings.HasPrefix(string(c), "TZ=")
+}
+
+// WithTimezone returns the spec scheduled in the IANA time zone tz, unless
+// tz is empty.
+func (c Cron) WithTimezone(tz string) Cron {
+	if tz == "" {
+		return c
+	}
+	return Cron(fmt.Sprintf("CRON_TZ=%s %s", tz, c))
+}
+
+// Location returns the time zone the spec is scheduled in, or UTC if it
+// gives none.
+func (c Cron) Location() (*time.Location, error) {
+	if !c.HasTimezone() {
+		return time.UTC, nil
+	}
+	prefix := strings.Fields(string(c))[0]
+	return time.LoadLocation(prefix[strings.Index(prefix, "=")+1:])
+}
+
+// CronBlackout is a period, from From up to To, during which a cron
+// initiator does not run its job.
+type CronBlackout struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// CronCalendar is the holidays and blackout periods during which the runs
+// scheduled by a cron initiator are skipped or, with Defer, made once the
+// holiday or blackout ends. The runs deferred to the end of the same holiday
+// or blackout make a single run.
+type CronCalendar struct {
+	// Holidays are whole days, as YYYY-MM-DD in the initiator's time zone.
+	Holidays  []string       `json:"holidays,omitempty"`
+	Blackouts []CronBlackout `json:"blackouts,omitempty"`
+	Defer     bool           `json:"defer,omitempty"`
+}
+
+// Validate returns an error if a holiday is not a YYYY-MM-DD date, or a
+// blackout does not end after it starts.
+func (cc CronCalendar) Validate() error {
+	for _, day := range cc.Holidays {
+		if _, err := time.Parse(cronHolidayLayout, day); err != nil {
+			return fmt.Errorf("holiday %s is not a YYYY-MM-DD date", day)
+		}
+	}
+	for _, b := range cc.Blackouts {
+		if !b.To.After(b.From) {
+			return fmt.Errorf("blackout from %s must end after it starts", b.From.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// Empty returns true if the calendar has neither holidays nor blackouts.
+func (cc CronCalendar) Empty() bool {
+	return len(cc.Holidays) == 0 && len(cc.Blackouts) == 0
+}
+
+// BlackedOut returns true if t falls on a holiday, in the time zone loc, or
+// in a blackout, along with the time the holidays and blackouts t falls in,
+// one after the other, end.
+func (cc CronCalendar) BlackedOut(t time.Time, loc *time.Location) (bool, time.Time) {
+	end := t
+	for {
+		next, ok := cc.endOf(end, loc)
+		if !ok {
+			break
+		}
+		end = next
+	}
+	return end.After(t), end
+}
+
+// endOf returns the end of a holiday or blackout t falls in.
+func (cc CronCalendar) endOf(t time.Time, loc *time.Location) (time.Time, bool) {
+	local := t.In(loc)
+	day := local.Format(cronHolidayLayout)
+	for _, holiday := range cc.Holidays {
+		if holiday == day {
+			return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc), true
+		}
+	}
+	for _, b := range cc.Blackouts {
+		if !t.Before(b.From) && t.Before(b.To) {
+			return b.To, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Value is defined so that we can store CronCalendar as JSONB, as for
+// PollTimerConfig.
+func (cc CronCalendar) Value() (driver.Value, error) {
+	return json.Marshal(cc)
+}
+
+// Scan is defined so that we can read CronCalendar as JSONB, as for
+// PollTimerConfig.
+func (cc *CronCalendar) Scan(value interface{}) error {
+	if value == nil {
+		*cc = CronCalendar{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid Scan Source")
+	}
+	return json.Unmarshal(b, cc)
+}
+
+// CronRun is a run scheduled by a cron initiator, and when it is made given
+// the initiator's calendar, which is never if RunAt is nil.
+type CronRun struct {
+	ScheduledAt time.Time  `json:"scheduledAt"`
+	RunAt       *time.Time `json:"runAt"`
+}
+
+// CronSchedule returns the schedule of a cron initiator, in its time zone.
+func (i Initiator) CronSchedule() Cron {
+	return i.Schedule.WithTimezone(i.Timezone)
+}
+
+// CronRunFor returns when the run a cron initiator scheduled at t is made:
+// at t, at the end of the holiday or blackout t falls in if its calendar
+// defers runs, or never if it skips them.
+func (i Initiator) CronRunFor(t time.Time) (CronRun, error) {
+	run := CronRun{ScheduledAt: t}
+	loc, err := i.CronSchedule().Location()
+	if err != nil {
+		return run, errors.Wrap(err, "invalid time zone")
+	}
+	blackedOut, end := i.Calendar.BlackedOut(t, loc)
+	switch {
+	case !blackedOut:
+		run.RunAt = &t
+	case i.Calendar.Defer:
+		run.RunAt = &end
+	}
+	return run, nil
+}
@@ -0,0 +1,71 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron_Location(t *testing.T) {
+	t.Parallel()
+
+	loc, err := models.Cron("0 9 * * *").WithTimezone("Asia/Tokyo").Location()
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", loc.String())
+
+	loc, err = models.Cron("0 9 * * *").Location()
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	_, err = models.Cron("CRON_TZ=Mars/Olympus_Mons 0 9 * * *").Location()
+	assert.Error(t, err)
+}
+
+func TestCronCalendar_BlackedOut(t *testing.T) {
+	t.Parallel()
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2020, 6, day, hour, 0, 0, 0, time.UTC)
+	}
+	calendar := models.CronCalendar{
+		Holidays: []string{"2020-06-05"},
+		Blackouts: []models.CronBlackout{
+			{From: at(1, 12), To: at(2, 0)},
+			{From: at(2, 0), To: at(2, 6)},
+			{From: at(4, 20), To: at(5, 2)},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		blackedOut bool
+		end        time.Time
+	}{
+		{"before", at(1, 11), false, at(1, 11)},
+		{"in adjacent blackouts", at(1, 18), true, at(2, 6)},
+		{"at end of blackout", at(2, 6), false, at(2, 6)},
+		{"in blackout running into holiday", at(4, 22), true, at(6, 0)},
+		{"on holiday", at(5, 12), true, at(6, 0)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blackedOut, end := calendar.BlackedOut(test.t, time.UTC)
+			assert.Equal(t, test.blackedOut, blackedOut)
+			assert.True(t, test.end.Equal(end), "expected %s, got %s", test.end, end)
+		})
+	}
+}
+
+func TestCronCalendar_Validate(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, models.CronCalendar{Holidays: []string{"2020-12-25"}}.Validate())
+	assert.Error(t, models.CronCalendar{Holidays: []string{"Christmas"}}.Validate())
+	assert.Error(t, models.CronCalendar{Blackouts: []models.CronBlackout{{From: from, To: from}}}.Validate())
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+// EarningsGrouping is what the runs are grouped by when accounting for the
+// LINK they earned.
+type EarningsGrouping string
+
+const (
+	// EarningsByJob accounts for the earnings of each job.
+	EarningsByJob EarningsGrouping = "job"
+	// EarningsByRequester accounts for the earnings from each requester.
+	EarningsByRequester EarningsGrouping = "requester"
+)
+
+// EarningsQuery selects the completed runs to account for: those which
+// finished at or after From, and before To.
+type EarningsQuery struct {
+	GroupBy EarningsGrouping
+	From    time.Time
+	To      time.Time
+}
+
+// Earnings is the LINK paid for the completed runs of a job, or requested by
+// a requester, along with what their transactions cost in gas. GasCost is
+// the gas used by their safe attempts at the price paid, or for attempts
+// whose gas used was not recorded, what they could have cost at most: their
+// gas limit at their gas price.
+type Earnings struct {
+	JobSpecID *ID             `json:"jobId,omitempty"`
+	Requester *common.Address `json:"requester,omitempty"`
+	Runs      int64           `json:"runs"`
+	Earned    *assets.Link    `json:"earned"`
+	GasCost   *assets.Eth     `json:"gasCost"`
+	// Net is the LINK earned less the gas cost, which is only known given
+	// the price of LINK in ETH.
+	Net *assets.Link `json:"net,omitempty"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (e Earnings) GetID() string {
+	if e.JobSpecID != nil {
+		return e.JobSpecID.String()
+	} else if e.Requester != nil {
+		return e.Requester.Hex()
+	}
+	return ""
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (e Earnings) GetName() string {
+	return "earnings"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (e *Earnings) SetID(value string) error {
+	if common.IsHexAddress(value) {
+		requester := common.HexToAddress(value)
+		e.Requester = &requester
+		return nil
+	}
+	id, err := NewIDFromString(value)
+	e.JobSpecID = id
+	return err
+}
+
+// SetNet sets Net to the LINK earned less the gas cost, converted to LINK at
+// ethPerLink, the price of one LINK in ETH, which must be positive. The gas
+// cost is rounded up.
+func (e *Earnings) SetNet(ethPerLink decimal.Decimal) {
+	price, _ := new(big.Rat).SetString(ethPerLink.String())
+	gasCostInLink := new(big.Int).Mul(e.GasCost.ToInt(), price.Denom())
+	gasCostInLink.Add(gasCostInLink, new(big.Int).Sub(price.Num(), big.NewInt(1)))
+	gasCostInLink.Quo(gasCostInLink, price.Num())
+	e.Net = (*assets.Link)(new(big.Int).Sub(e.Earned.ToInt(), gasCostInLink))
+}
@@ -0,0 +1,21 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEarnings_SetNet(t *testing.T) {
+	t.Parallel()
+
+	e := models.Earnings{Earned: assets.NewLink(100), GasCost: assets.NewEth(15)}
+	e.SetNet(decimal.RequireFromString("0.5"))
+	assert.Equal(t, assets.NewLink(70), e.Net)
+	e.SetNet(decimal.RequireFromString("4"))
+	assert.Equal(t, assets.NewLink(96), e.Net, "the gas cost is rounded up")
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/pkg/errors"
+)
+
+// EncryptedStringPrefix marks the values of EncryptedString columns which are
+// encrypted, as opposed to those written before they were.
+const EncryptedStringPrefix = "encrypted:"
+
+var columnKeys struct {
+	sync.RWMutex
+	encryptWith []byte
+	decryptWith [][]byte
+}
+
+// SetColumnEncryptionKeys sets the key EncryptedString columns are encrypted
+// with, and the keys which are tried in turn to decrypt them. It must be
+// called before any EncryptedString is read from or written to the database.
+func SetColumnEncryptionKeys(encryptWith []byte, decryptWith ...[]byte) {
+	columnKeys.Lock()
+	defer columnKeys.Unlock()
+	columnKeys.encryptWith = encryptWith
+	columnKeys.decryptWith = decryptWith
+}
+
+// EncryptedString is a string which is encrypted with the node's master key
+// when saved to the database, so that it is not readable from there.
+// Values saved before their column was encrypted are read as they are, and
+// encrypted the next time they are saved.
+type EncryptedString string
+
+// Value returns the encrypted string for the database.
+func (s EncryptedString) Value() (driver.Value, error) {
+	columnKeys.RLock()
+	defer columnKeys.RUnlock()
+	if len(columnKeys.encryptWith) == 0 {
+		return nil, errors.New("no master key to encrypt column with")
+	}
+	ciphertext, err := utils.Encrypt(columnKeys.encryptWith, []byte(s))
+	if err != nil {
+		return nil, errors.Wrap(err, "while encrypting column")
+	}
+	return EncryptedStringPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Scan decrypts the value read from the database.
+func (s *EncryptedString) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		return fmt.Errorf("unable to convert %v of %T to EncryptedString", value, value)
+	}
+	if !strings.HasPrefix(str, EncryptedStringPrefix) {
+		*s = EncryptedString(str)
+		return nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(str, EncryptedStringPrefix))
+	if err != nil {
+		return errors.Wrap(err, "while decoding encrypted column")
+	}
+	columnKeys.RLock()
+	defer columnKeys.RUnlock()
+	for _, key := range columnKeys.decryptWith {
+		if plaintext, err := utils.Decrypt(key, ciphertext); err == nil {
+			*s = EncryptedString(plaintext)
+			return nil
+		}
+	}
+	return errors.New("unable to decrypt column with the master key")
+}
+
+// String returns the decrypted string.
+func (s EncryptedString) String() string {
+	return string(s)
+}
@@ -0,0 +1,38 @@
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedString_ValueScan(t *testing.T) {
+	oldKey, newKey := []byte(cltest.SessionSecret), []byte("0123456789abcdef0123456789abcdef")
+	defer models.SetColumnEncryptionKeys(oldKey, oldKey)
+
+	models.SetColumnEncryptionKeys(oldKey, oldKey)
+	value, err := models.EncryptedString("token").Value()
+	require.NoError(t, err)
+	encrypted := value.(string)
+	assert.True(t, strings.HasPrefix(encrypted, models.EncryptedStringPrefix))
+	assert.NotContains(t, encrypted, "token")
+
+	var s models.EncryptedString
+	require.NoError(t, s.Scan(encrypted))
+	assert.Equal(t, "token", s.String())
+
+	require.NoError(t, s.Scan([]byte("plaintext")))
+	assert.Equal(t, "plaintext", s.String(), "values written before encryption are read as they are")
+
+	models.SetColumnEncryptionKeys(newKey, newKey, oldKey)
+	require.NoError(t, s.Scan(encrypted))
+	assert.Equal(t, "token", s.String(), "columns are read with any of the decryption keys")
+
+	models.SetColumnEncryptionKeys(newKey, newKey)
+	assert.Error(t, s.Scan(encrypted))
+}
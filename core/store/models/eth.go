@@ -5,11 +5,11 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/smartcontractkit/chainlink/core/eth"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/jinzhu/gorm"
 	null "gopkg.in/guregu/null.v3"
 )
 
@@ -83,6 +83,25 @@ type TxAttempt struct {
 	SentAt      uint64      `gorm:"not null"`
 	SignedRawTx []byte      `gorm:"not null"`
 	UpdatedAt   time.Time   `json:"-"`
+
+	// GasUsed and EffectiveGasPrice are recorded from the receipt of the
+	// attempt once it is safe, for accounting for the ETH spent on gas.
+	GasUsed           null.Int   `json:"gasUsed"`
+	EffectiveGasPrice *utils.Big `json:"effectiveGasPrice" gorm:"type:varchar(78)"`
+}
+
+// RecordGasUsed records the gas used by the attempt from its receipt, at the
+// effective gas price of the receipt, or else at the gas price of the
+// attempt.
+func (txa *TxAttempt) RecordGasUsed(receipt *eth.TxReceipt) {
+	if receipt == nil || receipt.GasUsed == nil {
+		return
+	}
+	txa.GasUsed = null.IntFrom(int64(*receipt.GasUsed))
+	txa.EffectiveGasPrice = txa.GasPrice
+	if receipt.EffectiveGasPrice != nil {
+		txa.EffectiveGasPrice = receipt.EffectiveGasPrice
+	}
 }
 
 // String implements Stringer for TxAttempt
@@ -138,25 +157,6 @@ type Head struct {
 	Number int64       `gorm:"index;not null"`
 }
 
-// AfterCreate is a gorm hook that trims heads after its creation
-func (h Head) AfterCreate(scope *gorm.Scope) (err error) {
-	scope.DB().Exec(`
-	DELETE FROM heads
-	WHERE id <= (
-	  SELECT id
-	  FROM (
-		SELECT id
-		FROM heads
-		ORDER BY id DESC
-		LIMIT 1 OFFSET 100
-	  ) foo
-	)`)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 // NewHead returns a Head instance with a BlockNumber and BlockHash.
 func NewHead(bigint *big.Int, hash common.Hash) *Head {
 	if bigint == nil {
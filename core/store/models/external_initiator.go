@@ -1,7 +1,12 @@
 package models
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,28 +14,102 @@ import (
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
+)
+
+const (
+	// ExternalInitiatorAccessKeyHeader is the header name for the access key
+	// used by external initiators and the node to authenticate to each other
+	ExternalInitiatorAccessKeyHeader = "X-Chainlink-EA-AccessKey"
+	// ExternalInitiatorSecretHeader is the header name for the secret used by
+	// external initiators and the node to authenticate to each other
+	ExternalInitiatorSecretHeader = "X-Chainlink-EA-Secret"
+	// ExternalInitiatorTimestampHeader is the header name for the unix time
+	// at which an external initiator signed its request
+	ExternalInitiatorTimestampHeader = "X-Chainlink-EA-Timestamp"
+	// ExternalInitiatorNonceHeader is the header name for the nonce an
+	// external initiator signed its request with, used once only
+	ExternalInitiatorNonceHeader = "X-Chainlink-EA-Nonce"
+	// ExternalInitiatorSignatureHeader is the header name for the signature
+	// of an external initiator's request
+	ExternalInitiatorSignatureHeader = "X-Chainlink-EA-Signature"
+
+	// ExternalInitiatorSignatureMaxAge is how far the timestamp of a signed
+	// request may be from the node's time. The nonces of requests are kept
+	// for twice as long, so a request cannot be replayed while it is valid.
+	ExternalInitiatorSignatureMaxAge = 5 * time.Minute
+
+	// ExternalInitiatorDeliveryFailureRetention is how long the failures to
+	// deliver to an external initiator are kept for.
+	ExternalInitiatorDeliveryFailureRetention = 7 * 24 * time.Hour
 )
 
 // ExternalInitiatorRequest is the incoming record used to create an ExternalInitiator.
 type ExternalInitiatorRequest struct {
 	Name string  `json:"name"`
 	URL  *WebURL `json:"url,omitempty"`
+	// RequireSignature requires the requests of the external initiator to
+	// be signed, on top of the access key and secret.
+	RequireSignature bool `json:"requireSignature,omitempty"`
 }
 
 // ExternalInitiator represents a user that can initiate runs remotely
 type ExternalInitiator struct {
-	Name           string  `gorm:"not null;unique"`
-	URL            *WebURL `gorm:"url,omitempty"`
-	AccessKey      string  `gorm:"not null"`
-	Salt           string  `gorm:"not null"`
-	HashedSecret   string  `gorm:"not null"`
-	OutgoingSecret string  `gorm:"not null"`
-	OutgoingToken  string  `gorm:"not null"`
+	Name           string          `gorm:"not null;unique"`
+	URL            *WebURL         `gorm:"url,omitempty"`
+	AccessKey      string          `gorm:"not null"`
+	Salt           string          `gorm:"not null"`
+	HashedSecret   string          `gorm:"not null"`
+	OutgoingSecret EncryptedString `gorm:"not null"`
+	OutgoingToken  EncryptedString `gorm:"not null"`
+
+	// RequireSignature rejects the requests of the external initiator which
+	// are not signed with its SigningSecret, see VerifySignature.
+	RequireSignature bool            `gorm:"not null"`
+	SigningSecret    EncryptedString `gorm:"not null"`
+
+	// LastSeenAt is when the external initiator last authenticated to the node.
+	LastSeenAt *time.Time
+	// LastHealthCheckAt is when the node last checked the health of the
+	// external initiator, and HealthCheckError why it found it unhealthy.
+	LastHealthCheckAt *time.Time
+	HealthCheckError  null.String
+
+	// DeliveryFailures are the failures to deliver to the external
+	// initiator, newest first. They are only loaded with the external
+	// initiators listed.
+	DeliveryFailures []ExternalInitiatorDeliveryFailure `gorm:"foreignkey:ExternalInitiatorName;association_foreignkey:Name;save_associations:false"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Healthy returns false if the last health check of the external initiator
+// failed.
+func (ei ExternalInitiator) Healthy() bool {
+	return !ei.HealthCheckError.Valid
+}
+
+// HealthCheckURL returns the URL the health of the external initiator is
+// checked at, the /health path of the host of its URL, or nil if it has none.
+func (ei ExternalInitiator) HealthCheckURL() *url.URL {
+	if ei.URL == nil {
+		return nil
+	}
+	u := url.URL(*ei.URL)
+	return u.ResolveReference(&url.URL{Path: "/health"})
+}
+
+// ExternalInitiatorDeliveryFailure records a notice the node failed to
+// deliver to an external initiator.
+type ExternalInitiatorDeliveryFailure struct {
+	ID                    uint64    `json:"-" gorm:"primary_key"`
+	ExternalInitiatorName string    `json:"-"`
+	JobSpecID             *ID       `json:"jobSpecId"`
+	Error                 string    `json:"error"`
+	CreatedAt             time.Time `json:"createdAt"`
+}
+
 // NewExternalInitiator generates an ExternalInitiator from an
 // auth.Token, hashing the password for storage
 func NewExternalInitiator(
@@ -49,8 +128,11 @@ func NewExternalInitiator(
 		AccessKey:      eia.AccessKey,
 		HashedSecret:   hashedSecret,
 		Salt:           salt,
-		OutgoingToken:  utils.NewSecret(utils.DefaultSecretSize),
-		OutgoingSecret: utils.NewSecret(utils.DefaultSecretSize),
+		OutgoingToken:  EncryptedString(utils.NewSecret(utils.DefaultSecretSize)),
+		OutgoingSecret: EncryptedString(utils.NewSecret(utils.DefaultSecretSize)),
+
+		RequireSignature: eir.RequireSignature,
+		SigningSecret:    EncryptedString(utils.NewSecret(utils.DefaultSecretSize)),
 	}, nil
 }
 
@@ -63,3 +145,79 @@ func AuthenticateExternalInitiator(eia *auth.Token, ea *ExternalInitiator) (bool
 	}
 	return subtle.ConstantTimeCompare([]byte(hashedSecret), []byte(ea.HashedSecret)) == 1, nil
 }
+
+// ExternalInitiatorSignature returns the hex encoded HMAC-SHA256 an external
+// initiator signs its requests with: that of the method, the path with its
+// query, the timestamp, the nonce and the body, separated by newlines, keyed
+// with its signing secret.
+func ExternalInitiatorSignature(secret, method, path, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature of a request of the external
+// initiator to path with method, and that its timestamp is within
+// ExternalInitiatorSignatureMaxAge of now. The nonce is left for the caller
+// to check it was not used before.
+func (ei ExternalInitiator) VerifySignature(method, path, timestamp, nonce, signature string, body []byte, now time.Time) error {
+	if timestamp == "" || nonce == "" || signature == "" {
+		return errors.New("request is not signed")
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid timestamp")
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > ExternalInitiatorSignatureMaxAge || age < -ExternalInitiatorSignatureMaxAge {
+		return errors.Errorf("timestamp is more than %v from the node's time", ExternalInitiatorSignatureMaxAge)
+	}
+	expected := ExternalInitiatorSignature(ei.SigningSecret.String(), method, path, timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// ExternalInitiatorNonce records a nonce an external initiator signed a
+// request with, so that the request cannot be replayed.
+type ExternalInitiatorNonce struct {
+	ID                    uint64 `gorm:"primary_key"`
+	ExternalInitiatorName string `gorm:"not null"`
+	Nonce                 string `gorm:"not null"`
+	CreatedAt             time.Time
+}
+
+// ExternalInitiatorNotification is a notification waiting to be delivered to
+// an external initiator, which is retried until it is delivered or runs out
+// of attempts, when it is dead-lettered.
+type ExternalInitiatorNotification struct {
+	ID                    uint64      `json:"-" gorm:"primary_key"`
+	ExternalInitiatorName string      `json:"externalInitiator"`
+	JobSpecID             *ID         `json:"jobSpecId"`
+	Body                  JSON        `json:"body"`
+	Attempts              uint        `json:"attempts"`
+	NextAttemptAt         time.Time   `json:"nextAttemptAt"`
+	LastError             null.String `json:"lastError"`
+	DeadAt                *time.Time  `json:"deadAt"`
+	CreatedAt             time.Time   `json:"createdAt"`
+	UpdatedAt             time.Time   `json:"updatedAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (n ExternalInitiatorNotification) GetID() string {
+	return strconv.FormatUint(n.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (n ExternalInitiatorNotification) GetName() string {
+	return "external_initiator_notifications"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (n *ExternalInitiatorNotification) SetID(value string) error {
+	id, err := strconv.ParseUint(value, 10, 64)
+	n.ID = id
+	return err
+}
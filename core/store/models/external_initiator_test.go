@@ -1,13 +1,16 @@
 package models_test
 
 import (
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/auth"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewExternalInitiator(t *testing.T) {
@@ -25,3 +28,25 @@ func TestNewExternalInitiator(t *testing.T) {
 	assert.NotEqual(t, ei.HashedSecret, eia.Secret)
 	assert.Equal(t, ei.AccessKey, eia.AccessKey)
 }
+
+func TestExternalInitiator_VerifySignature(t *testing.T) {
+	ei, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: "bitcoin", RequireSignature: true})
+	require.NoError(t, err)
+	assert.True(t, ei.RequireSignature)
+	assert.NotEmpty(t, ei.SigningSecret)
+
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"result":"100"}`)
+	path := "/v2/specs/1/runs"
+	signature := models.ExternalInitiatorSignature(ei.SigningSecret.String(), "POST", path, timestamp, "nonce", body)
+
+	assert.NoError(t, ei.VerifySignature("POST", path, timestamp, "nonce", signature, body, now))
+	assert.EqualError(t, ei.VerifySignature("POST", path, timestamp, "nonce", "", body, now), "request is not signed")
+	assert.EqualError(t, ei.VerifySignature("POST", path, timestamp, "other", signature, body, now), "invalid signature")
+	assert.EqualError(t, ei.VerifySignature("POST", path, timestamp, "nonce", signature, []byte(`{"result":"200"}`), now), "invalid signature")
+	assert.EqualError(t, ei.VerifySignature("POST", "/v2/specs/2/runs", timestamp, "nonce", signature, body, now), "invalid signature")
+	assert.EqualError(t, ei.VerifySignature("PATCH", path, timestamp, "nonce", signature, body, now), "invalid signature")
+	assert.EqualError(t, ei.VerifySignature("POST", path, timestamp, "nonce", signature, body, now.Add(10*time.Minute)), "timestamp is more than 5m0s from the node's time")
+	assert.Error(t, ei.VerifySignature("POST", path, "yesterday", "nonce", signature, body, now))
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	null "gopkg.in/guregu/null.v3"
+)
+
+// FeedHealth tracks the outcome of the polls a flux monitor makes of a feed,
+// identified by its URL without the credentials or query which may hold its
+// API key, followed by the start of the hash of its full URL. Once a feed
+// has failed FLUX_MONITOR_FEED_QUARANTINE_THRESHOLD polls in a row it is
+// quarantined, being left out of rounds without being polled until a probe
+// poll succeeds.
+type FeedHealth struct {
+	Feed                string      `json:"feed" gorm:"primary_key"`
+	Successes           uint64      `json:"successes"`
+	Failures            uint64      `json:"failures"`
+	ConsecutiveFailures uint32      `json:"consecutiveFailures"`
+	LatencyTotal        Duration    `json:"-"`
+	LastError           null.String `json:"lastError"`
+	LastSuccessAt       null.Time   `json:"lastSuccessAt"`
+	LastFailureAt       null.Time   `json:"lastFailureAt"`
+	QuarantinedAt       null.Time   `json:"quarantinedAt"`
+	UpdatedAt           time.Time   `json:"updatedAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (h FeedHealth) GetID() string {
+	return h.Feed
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (h FeedHealth) GetName() string {
+	return "feed_healths"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (h *FeedHealth) SetID(value string) error {
+	h.Feed = value
+	return nil
+}
+
+// Polls returns the number of times the feed was polled.
+func (h FeedHealth) Polls() uint64 {
+	return h.Successes + h.Failures
+}
+
+// ErrorRate returns the fraction of polls of the feed which failed.
+func (h FeedHealth) ErrorRate() float64 {
+	if h.Polls() == 0 {
+		return 0
+	}
+	return float64(h.Failures) / float64(h.Polls())
+}
+
+// AverageLatency returns the mean time taken by the feed to respond.
+func (h FeedHealth) AverageLatency() time.Duration {
+	if h.Polls() == 0 {
+		return 0
+	}
+	return h.LatencyTotal.Duration() / time.Duration(h.Polls())
+}
+
+// Quarantined returns true if the feed is being left out of rounds.
+func (h FeedHealth) Quarantined() bool {
+	return h.QuarantinedAt.Valid
+}
+
+// MarshalJSON adds the error rate, average latency and quarantine state to
+// the recorded counts.
+func (h FeedHealth) MarshalJSON() ([]byte, error) {
+	type Alias FeedHealth
+	return json.Marshal(&struct {
+		Alias
+		ErrorRate      float64  `json:"errorRate"`
+		AverageLatency Duration `json:"averageLatency"`
+		Quarantined    bool     `json:"quarantined"`
+	}{
+		Alias(h),
+		h.ErrorRate(),
+		MustMakeDuration(h.AverageLatency()),
+		h.Quarantined(),
+	})
+}
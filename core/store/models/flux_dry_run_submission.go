@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+// FluxDryRunSubmission is an answer a flux monitor in dry run mode would
+// have submitted to its aggregator, had it not been in dry run mode. They let
+// operators check the configuration of a feed against a live aggregator
+// without sending transactions.
+type FluxDryRunSubmission struct {
+	ID           uint64          `json:"-" gorm:"primary_key"`
+	JobSpecID    *ID             `json:"jobSpecId" gorm:"not null"`
+	Aggregator   common.Address  `json:"aggregator" gorm:"not null"`
+	RoundID      uint64          `json:"roundId" gorm:"not null"`
+	LatestAnswer decimal.Decimal `json:"latestAnswer" gorm:"type:numeric;not null"`
+	Answer       decimal.Decimal `json:"answer" gorm:"type:numeric;not null"`
+	Reason       string          `json:"reason" gorm:"not null"`
+	CreatedAt    time.Time       `json:"createdAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (s FluxDryRunSubmission) GetID() string {
+	return strconv.FormatUint(s.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (s FluxDryRunSubmission) GetName() string {
+	return "flux_dry_run_submissions"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (s *FluxDryRunSubmission) SetID(value string) error {
+	ID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	s.ID = ID
+	return nil
+}
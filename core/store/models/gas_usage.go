@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+)
+
+// GasUsageGrouping is what the transactions are grouped by when accounting
+// for the gas they used.
+type GasUsageGrouping string
+
+const (
+	// GasUsageByJob accounts for the gas used by the runs of each job.
+	GasUsageByJob GasUsageGrouping = "job"
+	// GasUsageByRun accounts for the gas used by each run.
+	GasUsageByRun GasUsageGrouping = "run"
+)
+
+// GasUsageQuery selects the transactions to account for: those whose gas
+// used was recorded, as they became safe, at or after From, and before To.
+type GasUsageQuery struct {
+	GroupBy GasUsageGrouping
+	From    time.Time
+	To      time.Time
+}
+
+// GasUsage is the gas used by the safe transactions of the runs of a job, or
+// of a single run, and the ETH it cost. The transactions not sent by a run,
+// such as batched VRF fulfillments and withdrawals, are accounted for
+// together, without a job.
+type GasUsage struct {
+	JobSpecID    *ID         `json:"jobId,omitempty"`
+	JobRunID     *ID         `json:"runId,omitempty"`
+	Transactions int64       `json:"transactions"`
+	GasUsed      uint64      `json:"gasUsed"`
+	Cost         *assets.Eth `json:"cost"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (g GasUsage) GetID() string {
+	if g.JobRunID != nil {
+		return g.JobRunID.String()
+	} else if g.JobSpecID != nil {
+		return g.JobSpecID.String()
+	}
+	return ""
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (g GasUsage) GetName() string {
+	return "gas_usages"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (g *GasUsage) SetID(value string) error {
+	if value == "" {
+		return nil
+	}
+	id, err := NewIDFromString(value)
+	g.JobSpecID = id
+	return err
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// JobBundle is the definition of a job along with the bridges, external
+// initiators and secrets it depends on, as a single artifact for moving the
+// job from one node to another. It carries no credentials: the bridges leave
+// out their auth, the external initiators their keys and secrets, and the
+// secrets their values, which are only given when importing a bundle to
+// create the secrets missing from the node.
+//
+// The values which differ between nodes, such as the addresses of contracts,
+// can be left out of the job as {{variable "name"}} references in its
+// strings, which are replaced by the Variables given when importing it. Unlike
+// secrets, variables are saved in the job.
+type JobBundle struct {
+	Job                JobSpecRequest             `json:"job"`
+	Bridges            []BridgeTypeRequest        `json:"bridges"`
+	ExternalInitiators []ExternalInitiatorRequest `json:"externalInitiators"`
+	Secrets            []SecretRequest            `json:"secrets"`
+	Variables          map[string]string          `json:"variables,omitempty"`
+}
+
+var variableReference = regexp.MustCompile(`\{\{\s*variable\s+"([^"]*)"\s*\}\}`)
+
+// JobWithVariables returns the job of the bundle with the variables it
+// references replaced by their values, erroring with the names of those the
+// bundle gives no value for.
+func (b JobBundle) JobWithVariables() (JobSpecRequest, error) {
+	raw, err := json.Marshal(b.Job)
+	if err != nil {
+		return JobSpecRequest{}, errors.Wrap(err, "while resolving variables")
+	}
+	job, err := ParseJSON(raw)
+	if err != nil {
+		return JobSpecRequest{}, errors.Wrap(err, "while resolving variables")
+	}
+
+	missing := map[string]bool{}
+	job, err = resolveReferences(job, variableReference, func(name string) (string, error) {
+		value, ok := b.Variables[name]
+		if !ok {
+			missing[name] = true
+		}
+		return value, nil
+	})
+	if err != nil {
+		return JobSpecRequest{}, err
+	}
+	if len(missing) > 0 {
+		var names []string
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return JobSpecRequest{}, fmt.Errorf("the bundle gives no value for the variables %v", names)
+	}
+
+	var jsr JobSpecRequest
+	if err := json.Unmarshal([]byte(job.String()), &jsr); err != nil {
+		return JobSpecRequest{}, errors.Wrap(err, "while resolving variables")
+	}
+	return jsr, nil
+}
+
+// JobDependencies are the bridges, external initiators and secrets missing
+// from the node which are created along with a job imported from a bundle.
+type JobDependencies struct {
+	Bridges            []*BridgeType
+	ExternalInitiators []*ExternalInitiator
+	Secrets            []*Secret
+}
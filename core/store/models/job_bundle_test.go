@@ -0,0 +1,40 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobBundle_JobWithVariables(t *testing.T) {
+	t.Parallel()
+
+	address := cltest.NewAddress()
+	bundle := models.JobBundle{
+		Job: models.JobSpecRequest{
+			Initiators: []models.InitiatorRequest{{
+				Type:            models.InitiatorRunLog,
+				InitiatorParams: models.InitiatorParams{Address: address},
+			}},
+			Tasks: []models.TaskSpecRequest{{
+				Type:   models.MustNewTaskType("httpget"),
+				Params: cltest.JSONFromString(t, `{"get":"{{variable \"host\"}}/price","key":"{{secret \"apikey\"}}"}`),
+			}},
+		},
+		Variables: map[string]string{"host": `https://prod.example.com`},
+	}
+
+	jsr, err := bundle.JobWithVariables()
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com/price", jsr.Tasks[0].Params.Get("get").String())
+	assert.Equal(t, `{{secret "apikey"}}`, jsr.Tasks[0].Params.Get("key").String(), "secrets are resolved when the job runs")
+	assert.Equal(t, address, jsr.Initiators[0].Address)
+
+	bundle.Variables = nil
+	_, err = bundle.JobWithVariables()
+	assert.EqualError(t, err, "the bundle gives no value for the variables [host]")
+}
@@ -22,6 +22,19 @@ var (
 	},
 		[]string{"job_spec_id", "from_status", "status"},
 	)
+	promRunsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_runs_finished_total",
+		Help: "The total number of Job Runs which completed, errored or were cancelled",
+	},
+		[]string{"job_spec_id", "status"},
+	)
+	promRunDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "job_run_duration_seconds",
+		Help:    "How long Job Runs take from their creation until they finish",
+		Buckets: []float64{1, 5, 15, 30, 60, 300, 900, 3600, 21600, 86400},
+	},
+		[]string{"status"},
+	)
 )
 
 // JobRun tracks the status of a job by holding its TaskRuns and the
@@ -95,6 +108,14 @@ func (jr *JobRun) SetStatus(status RunStatus) {
 		jr.FinishedAt = null.TimeFrom(time.Now())
 	}
 	promTotalRunUpdates.WithLabelValues(jr.JobSpecID.String(), string(oldStatus), string(status)).Inc()
+	// A task completing with tasks remaining leaves the run in progress, so
+	// the status the run ends up with is counted rather than the one set
+	if jr.Status.Finished() && !oldStatus.Finished() {
+		promRunsFinished.WithLabelValues(jr.JobSpecID.String(), string(jr.Status)).Inc()
+		if !jr.CreatedAt.IsZero() {
+			promRunDuration.WithLabelValues(string(jr.Status)).Observe(time.Since(jr.CreatedAt).Seconds())
+		}
+	}
 }
 
 // GetStatus returns the JobRun's RunStatus
@@ -110,8 +131,8 @@ func (jr *JobRun) SetID(value string) error {
 // ForLogger formats the JobRun for a common formatting in the log.
 func (jr JobRun) ForLogger(kvs ...interface{}) []interface{} {
 	output := []interface{}{
-		"job", jr.JobSpecID.String(),
-		"run", jr.ID.String(),
+		"job_id", jr.JobSpecID.String(),
+		"run_id", jr.ID.String(),
 		"status", jr.Status,
 	}
 
@@ -140,13 +161,53 @@ func (jr JobRun) ForLogger(kvs ...interface{}) []interface{} {
 	return append(kvs, output...)
 }
 
+// Priorities of runs waiting to be executed, the runs of a higher priority
+// being executed first.
+const (
+	RunPriorityLow = iota
+	RunPriorityNormal
+	RunPriorityHigh
+)
+
+var (
+	// HighPriorityInitiators are the types of the initiators of the runs of
+	// a high priority: the answers of flux monitors and the fulfillments of
+	// randomness requests, which are due by a deadline.
+	HighPriorityInitiators = []string{InitiatorFluxMonitor, InitiatorRandomnessLog}
+	// LowPriorityInitiators are the types of the initiators of the runs of a
+	// low priority: cron and web runs, which nothing on chain waits for.
+	LowPriorityInitiators = []string{InitiatorCron, InitiatorWeb}
+)
+
+// Priority returns the priority of the run by its initiator, the runs of
+// other jobs than those of a high or low priority being of a normal one.
+func (jr JobRun) Priority() int {
+	for _, t := range HighPriorityInitiators {
+		if jr.Initiator.Type == t {
+			return RunPriorityHigh
+		}
+	}
+	for _, t := range LowPriorityInitiators {
+		if jr.Initiator.Type == t {
+			return RunPriorityLow
+		}
+	}
+	return RunPriorityNormal
+}
+
 // HasError returns true if this JobRun has errored
 func (jr JobRun) HasError() bool {
 	return jr.Status.Errored()
 }
 
-// NextTaskRunIndex returns the position of the next unfinished task
+// NextTaskRunIndex returns the position of the next unfinished task: the
+// task the run is waiting on if there is one, or else the first unstarted one
 func (jr *JobRun) NextTaskRunIndex() (int, bool) {
+	for index, tr := range jr.TaskRuns {
+		if tr.Status.Pending() {
+			return index, true
+		}
+	}
 	for index, tr := range jr.TaskRuns {
 		if tr.Status.CanStart() {
 			return index, true
@@ -165,6 +226,38 @@ func (jr *JobRun) NextTaskRun() *TaskRun {
 	return nil
 }
 
+// PendingBridgeTaskRun returns the task run waiting on a bridge, which in a
+// task graph is not necessarily the first unfinished one, or the next task
+// run if none is. If id is given, it returns the task run waiting on a bridge
+// with that ID, or nil if there is none.
+func (jr *JobRun) PendingBridgeTaskRun(id *ID) *TaskRun {
+	pending := jr.PendingBridgeTaskRuns()
+	if id != nil {
+		for _, tr := range pending {
+			if *tr.ID == *id {
+				return tr
+			}
+		}
+		return nil
+	}
+	if len(pending) > 0 {
+		return pending[0]
+	}
+	return jr.NextTaskRun()
+}
+
+// PendingBridgeTaskRuns returns the task runs waiting on a bridge, of which a
+// task graph may have one per bridge.
+func (jr *JobRun) PendingBridgeTaskRuns() []*TaskRun {
+	var pending []*TaskRun
+	for i := range jr.TaskRuns {
+		if jr.TaskRuns[i].Status.PendingBridge() {
+			pending = append(pending, &jr.TaskRuns[i])
+		}
+	}
+	return pending
+}
+
 // PreviousTaskRun returns the last task to be processed, if it exists
 func (jr *JobRun) PreviousTaskRun() *TaskRun {
 	index, runnable := jr.NextTaskRunIndex()
@@ -174,6 +267,80 @@ func (jr *JobRun) PreviousTaskRun() *TaskRun {
 	return nil
 }
 
+// IsTaskGraph returns whether the tasks of the run declare their inputs,
+// rather than each taking the result of the task before.
+func (jr *JobRun) IsTaskGraph() bool {
+	for _, tr := range jr.TaskRuns {
+		if len(tr.TaskSpec.Edges) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadyTaskRuns returns the unfinished task runs of a task graph whose inputs
+// are all complete.
+func (jr *JobRun) ReadyTaskRuns() []*TaskRun {
+	var ready []*TaskRun
+	for i := range jr.TaskRuns {
+		tr := &jr.TaskRuns[i]
+		if !tr.Status.CanStart() {
+			continue
+		}
+		complete := true
+		for _, input := range jr.inputTaskRuns(tr) {
+			complete = complete && input.Status.Completed()
+		}
+		if complete {
+			ready = append(ready, tr)
+		}
+	}
+	return ready
+}
+
+// TaskRunInput returns the data the task run takes as input: the result of
+// the task run before it, or, in a task graph, that of its inputs. The data
+// of several inputs is merged, with the list of their results, in the order
+// the inputs were declared in, as result.
+func (jr *JobRun) TaskRunInput(tr *TaskRun) (JSON, error) {
+	if !jr.IsTaskGraph() {
+		if previous := jr.PreviousTaskRun(); previous != nil {
+			return previous.Result.Data, nil
+		}
+		return JSON{}, nil
+	}
+
+	inputs := jr.inputTaskRuns(tr)
+	switch len(inputs) {
+	case 0:
+		return JSON{}, nil
+	case 1:
+		return inputs[0].Result.Data, nil
+	}
+	data := JSON{}
+	results := make([]interface{}, len(inputs))
+	for i, input := range inputs {
+		var err error
+		if data, err = Merge(data, input.Result.Data); err != nil {
+			return JSON{}, err
+		}
+		results[i] = input.Result.Data.Get("result").Value()
+	}
+	return data.Add("result", results)
+}
+
+func (jr *JobRun) inputTaskRuns(tr *TaskRun) []*TaskRun {
+	inputs := make([]*TaskRun, 0, len(tr.TaskSpec.Edges))
+	for _, edge := range tr.TaskSpec.Edges {
+		for i := range jr.TaskRuns {
+			if jr.TaskRuns[i].TaskSpecID == edge.InputTaskSpecID {
+				inputs = append(inputs, &jr.TaskRuns[i])
+			}
+		}
+	}
+	return inputs
+}
+
 // TasksRemain returns true if there are unfinished tasks left for this job run
 func (jr *JobRun) TasksRemain() bool {
 	_, runnable := jr.NextTaskRunIndex()
@@ -195,6 +362,15 @@ func (jr *JobRun) Cancel() {
 	jr.SetStatus(RunStatusCancelled)
 }
 
+// CancelWithReason cancels the run like Cancel, recording why in its result.
+func (jr *JobRun) CancelWithReason(reason string) {
+	if currentTaskRun := jr.NextTaskRun(); currentTaskRun != nil {
+		currentTaskRun.Result.ErrorMessage = null.StringFrom(reason)
+	}
+	jr.Result.ErrorMessage = null.StringFrom(reason)
+	jr.Cancel()
+}
+
 // ApplyOutput updates the JobRun's Result and Status
 func (jr *JobRun) ApplyOutput(result RunOutput) {
 	if result.HasError() {
@@ -205,13 +381,38 @@ func (jr *JobRun) ApplyOutput(result RunOutput) {
 	jr.SetStatus(result.Status())
 }
 
-// ApplyBridgeRunResult saves the input from a BridgeAdapter
+// ApplyTaskGraphOutputs updates the JobRun's Result and Status with the
+// outputs of task runs of a task graph which ran together. Any error errors
+// the run, and otherwise the run waits if any of them has to.
+func (jr *JobRun) ApplyTaskGraphOutputs(results []RunOutput) {
+	for _, result := range results {
+		if result.HasError() {
+			jr.SetError(result.Error())
+			return
+		}
+	}
+	for _, result := range results {
+		if !result.Status().Completed() {
+			jr.ApplyOutput(result)
+			return
+		}
+	}
+	jr.ApplyOutput(results[len(results)-1])
+}
+
+// ApplyBridgeRunResult saves the input from a BridgeAdapter, applied to its
+// task run before. The run keeps waiting while other bridges of a task graph
+// have yet to answer.
 func (jr *JobRun) ApplyBridgeRunResult(result BridgeRunResult) {
 	if result.HasError() {
 		jr.SetError(result.GetError())
 	}
 	jr.Result.Data = result.Data
-	jr.SetStatus(result.Status)
+	status := result.Status
+	if status.Completed() && len(jr.PendingBridgeTaskRuns()) > 0 {
+		status = RunStatusPendingBridge
+	}
+	jr.SetStatus(status)
 }
 
 // ErrorString returns the error as a string if present, otherwise "".
@@ -221,14 +422,20 @@ func (jr *JobRun) ErrorString() string {
 
 // RunRequest stores the fields used to initiate the parent job run.
 type RunRequest struct {
-	ID            uint32 `gorm:"primary_key"`
-	RequestID     *common.Hash
-	TxHash        *common.Hash
-	BlockHash     *common.Hash
-	Requester     *common.Address
-	CreatedAt     time.Time
-	Payment       *assets.Link
-	RequestParams JSON `gorm:"default: '{}';not null"`
+	ID             uint32 `gorm:"primary_key"`
+	RequestID      *common.Hash
+	TxHash         *common.Hash
+	BlockHash      *common.Hash
+	Requester      *common.Address
+	CreatedAt      time.Time
+	Payment        *assets.Link
+	RequestParams  JSON `gorm:"default: '{}';not null"`
+	IdempotencyKey null.String
+	// JobSpecID and LogIndex are set for the requests made by run logs, for
+	// which only one run is created per log. JobSpecID is also set for the
+	// requests with an idempotency key, which is unique per job.
+	JobSpecID *ID
+	LogIndex  null.Int
 }
 
 // NewRunRequest returns a new RunRequest instance.
@@ -239,17 +446,18 @@ func NewRunRequest(requestParams JSON) *RunRequest {
 // TaskRun stores the Task and represents the status of the
 // Task to be ran.
 type TaskRun struct {
-	ID                   *ID           `json:"id" gorm:"primary_key;not null"`
-	JobRunID             *ID           `json:"-"`
-	Result               RunResult     `json:"result"`
-	ResultID             clnull.Uint32 `json:"-"`
-	Status               RunStatus     `json:"status" gorm:"default:'unstarted'"`
-	TaskSpec             TaskSpec      `json:"task" gorm:"association_autoupdate:false;association_autocreate:false"`
-	TaskSpecID           uint          `json:"-"`
-	MinimumConfirmations clnull.Uint32 `json:"minimumConfirmations"`
-	Confirmations        clnull.Uint32 `json:"confirmations"`
-	CreatedAt            time.Time     `json:"-"`
-	UpdatedAt            time.Time     `json:"-"`
+	ID                           *ID           `json:"id" gorm:"primary_key;not null"`
+	JobRunID                     *ID           `json:"-"`
+	Result                       RunResult     `json:"result"`
+	ResultID                     clnull.Uint32 `json:"-"`
+	Status                       RunStatus     `json:"status" gorm:"default:'unstarted'"`
+	TaskSpec                     TaskSpec      `json:"task" gorm:"association_autoupdate:false;association_autocreate:false"`
+	TaskSpecID                   uint          `json:"-"`
+	MinimumConfirmations         clnull.Uint32 `json:"minimumConfirmations"`
+	Confirmations                clnull.Uint32 `json:"confirmations"`
+	MinimumOutgoingConfirmations clnull.Uint32 `json:"minimumOutgoingConfirmations"`
+	CreatedAt                    time.Time     `json:"-"`
+	UpdatedAt                    time.Time     `json:"-"`
 }
 
 // String returns info on the TaskRun as "ID,Type,Status,Result".
@@ -283,11 +491,15 @@ func (tr *TaskRun) ApplyOutput(result RunOutput) {
 }
 
 // RunResult keeps track of the outcome of a TaskRun or JobRun. It stores the
-// Data and ErrorMessage.
+// Data and ErrorMessage. BlobHash points at the data of a result too large to
+// be kept with it, which is replaced by a Truncation marker, and ArchivedIn at
+// the archive file the data of an old result was moved to.
 type RunResult struct {
 	ID           uint32      `json:"-" gorm:"primary_key;auto_increment"`
 	Data         JSON        `json:"data" gorm:"type:text"`
 	ErrorMessage null.String `json:"error"`
+	BlobHash     null.String `json:"-"`
+	ArchivedIn   null.String `json:"-"`
 	CreatedAt    time.Time   `json:"-"`
 	UpdatedAt    time.Time   `json:"-"`
 }
@@ -137,9 +137,9 @@ func TestJobRun_ForLogger(t *testing.T) {
 	jr.Payment = linkReward
 	logsBeforeCompletion := jr.ForLogger()
 	require.Len(t, logsBeforeCompletion, 8)
-	assert.Equal(t, logsBeforeCompletion[0], "job")
+	assert.Equal(t, logsBeforeCompletion[0], "job_id")
 	assert.Equal(t, logsBeforeCompletion[1], jr.JobSpecID.String())
-	assert.Equal(t, logsBeforeCompletion[2], "run")
+	assert.Equal(t, logsBeforeCompletion[2], "run_id")
 	assert.Equal(t, logsBeforeCompletion[3], jr.ID.String())
 	assert.Equal(t, logsBeforeCompletion[4], "status")
 	assert.Equal(t, logsBeforeCompletion[5], jr.GetStatus())
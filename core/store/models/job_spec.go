@@ -25,6 +25,12 @@ type JobSpecRequest struct {
 	StartAt    null.Time          `json:"startAt"`
 	EndAt      null.Time          `json:"endAt"`
 	MinPayment *assets.Link       `json:"minPayment,omitempty"`
+
+	MaxConcurrentRuns uint32 `json:"maxConcurrentRuns,omitempty"`
+	Namespace         string `json:"namespace,omitempty"`
+
+	IncomingConfirmations clnull.Uint32 `json:"incomingConfirmations"`
+	OutgoingConfirmations clnull.Uint32 `json:"outgoingConfirmations"`
 }
 
 // InitiatorRequest represents a schema for incoming initiator requests as used by the API.
@@ -35,9 +41,12 @@ type InitiatorRequest struct {
 
 // TaskSpecRequest represents a schema for incoming TaskSpec requests as used by the API.
 type TaskSpecRequest struct {
-	Type          TaskType      `json:"type"`
-	Confirmations clnull.Uint32 `json:"confirmations"`
-	Params        JSON          `json:"params"`
+	Type                  TaskType      `json:"type"`
+	Confirmations         clnull.Uint32 `json:"confirmations"`
+	OutgoingConfirmations clnull.Uint32 `json:"outgoingConfirmations"`
+	Params                JSON          `json:"params"`
+	Name                  string        `json:"name,omitempty"`
+	Inputs                []string      `json:"inputs,omitempty"`
 }
 
 // JobSpec is the definition for all the work to be carried out by the node
@@ -53,6 +62,19 @@ type JobSpec struct {
 	EndAt      null.Time    `json:"endAt" gorm:"index"`
 	DeletedAt  null.Time    `json:"-" gorm:"index"`
 	UpdatedAt  time.Time    `json:"-"`
+	// MaxConcurrentRuns limits how many runs of the job may execute at once,
+	// zero meaning no limit beyond the node wide one.
+	MaxConcurrentRuns uint32 `json:"maxConcurrentRuns,omitempty" gorm:"not null"`
+	// Namespace isolates the job from the namespace tokens of other
+	// namespaces, the default namespace being empty.
+	Namespace string `json:"namespace,omitempty" gorm:"not null"`
+	// IncomingConfirmations is how many confirmations the logs initiating
+	// the job's runs need before its tasks start, and OutgoingConfirmations
+	// how many the transactions of its tasks need before they complete.
+	// Neither can be below the node's MIN_INCOMING_CONFIRMATIONS and
+	// MIN_OUTGOING_CONFIRMATIONS, and tasks may set more of either.
+	IncomingConfirmations clnull.Uint32 `json:"incomingConfirmations"`
+	OutgoingConfirmations clnull.Uint32 `json:"outgoingConfirmations"`
 }
 
 // GetID returns the ID of this structure for jsonapi serialization.
@@ -89,16 +111,23 @@ func NewJobFromRequest(jsr JobSpecRequest) JobSpec {
 	}
 	for _, task := range jsr.Tasks {
 		jobSpec.Tasks = append(jobSpec.Tasks, TaskSpec{
-			JobSpecID:     jobSpec.ID,
-			Type:          task.Type,
-			Confirmations: task.Confirmations,
-			Params:        task.Params,
+			JobSpecID:             jobSpec.ID,
+			Type:                  task.Type,
+			Confirmations:         task.Confirmations,
+			OutgoingConfirmations: task.OutgoingConfirmations,
+			Params:                task.Params,
+			Name:                  task.Name,
+			Inputs:                task.Inputs,
 		})
 	}
 
 	jobSpec.EndAt = jsr.EndAt
 	jobSpec.StartAt = jsr.StartAt
 	jobSpec.MinPayment = jsr.MinPayment
+	jobSpec.MaxConcurrentRuns = jsr.MaxConcurrentRuns
+	jobSpec.Namespace = jsr.Namespace
+	jobSpec.IncomingConfirmations = jsr.IncomingConfirmations
+	jobSpec.OutgoingConfirmations = jsr.OutgoingConfirmations
 	return jobSpec
 }
 
@@ -185,6 +214,24 @@ const (
 	InitiatorFluxMonitor = "fluxmonitor"
 	// InitiatorRandomnessLog for tasks from a VRF specific contract
 	InitiatorRandomnessLog = "randomnesslog"
+	// InitiatorKafka for tasks in a job to be run on messages published to
+	// kafka topics.
+	InitiatorKafka = "kafka"
+	// InitiatorMQTT for tasks in a job to be run on messages received from an
+	// mqtt broker.
+	InitiatorMQTT = "mqtt"
+	// InitiatorStream for tasks in a job to be run on updates pushed by a
+	// stream bridge.
+	InitiatorStream = "stream"
+	// InitiatorOffchainReporting for tasks in a job to be run on reports
+	// agreed by a set of oracles exchanging observations off-chain.
+	InitiatorOffchainReporting = "offchainreporting"
+	// InitiatorKeeper for upkeep contracts to be checked on each head, and
+	// performed when they need it.
+	InitiatorKeeper = "keeper"
+	// InitiatorBlockCondition for tasks in a job to be run on the new heads
+	// a condition on the values of their block holds on.
+	InitiatorBlockCondition = "blockcondition"
 )
 
 // Initiator could be thought of as a trigger, defines how a Job can be
@@ -201,14 +248,20 @@ type Initiator struct {
 	InitiatorParams `json:"params,omitempty"`
 	DeletedAt       null.Time `json:"-" gorm:"index"`
 	UpdatedAt       time.Time `json:"-"`
+	// NextRunAt is when a recurring runat initiator runs next, once it has run.
+	NextRunAt null.Time `json:"-"`
 }
 
 // InitiatorParams is a collection of the possible parameters that different
 // Initiators may require.
 type InitiatorParams struct {
 	Schedule   Cron              `json:"schedule,omitempty"`
+	Timezone   string            `json:"timezone,omitempty"`
+	Calendar   CronCalendar      `json:"calendar,omitempty" gorm:"type:jsonb"`
 	Time       AnyTime           `json:"time,omitempty"`
 	Ran        bool              `json:"ran,omitempty"`
+	Delay      Duration          `json:"delay,omitempty"`
+	Every      Duration          `json:"every,omitempty"`
 	Address    common.Address    `json:"address,omitempty" gorm:"index"`
 	Requesters AddressCollection `json:"requesters,omitempty" gorm:"type:text"`
 	Name       string            `json:"name,omitempty"`
@@ -216,6 +269,7 @@ type InitiatorParams struct {
 	FromBlock  *utils.Big        `json:"fromBlock,omitempty" gorm:"type:varchar(255)"`
 	ToBlock    *utils.Big        `json:"toBlock,omitempty" gorm:"type:varchar(255)"`
 	Topics     Topics            `json:"topics,omitempty"`
+	ABI        LogABI            `json:"abi,omitempty" gorm:"column:abi;type:jsonb"`
 
 	RequestData JSON            `json:"requestData,omitempty" gorm:"type:text"`
 	Feeds       Feeds           `json:"feeds,omitempty" gorm:"type:text"`
@@ -223,6 +277,24 @@ type InitiatorParams struct {
 	Threshold   float32         `json:"threshold,omitempty"`
 	PollTimer   PollTimerConfig `json:"pollTimer,omitempty" gorm:"type:jsonb"`
 	IdleTimer   IdleTimerConfig `json:"idleTimer,omitempty" gorm:"type:jsonb"`
+
+	Aggregation FluxAggregationConfig `json:"aggregation,omitempty" gorm:"type:jsonb"`
+	GasThrottle FluxGasThrottleConfig `json:"gasThrottle,omitempty" gorm:"type:jsonb"`
+	DryRun      bool                  `json:"dryRun,omitempty"`
+
+	OffchainReporting OffchainReportingConfig `json:"offchainReporting,omitempty" gorm:"type:jsonb"`
+
+	Keeper KeeperConfig `json:"keeper,omitempty" gorm:"type:jsonb"`
+
+	BlockCondition BlockConditionConfig `json:"blockCondition,omitempty" gorm:"type:jsonb"`
+
+	KafkaTopics StringCollection `json:"kafkaTopics,omitempty" gorm:"type:text"`
+
+	BrokerURL   string `json:"brokerUrl,omitempty"`
+	TopicFilter string `json:"topicFilter,omitempty"`
+	QoS         byte   `json:"qos,omitempty" gorm:"column:qos;type:smallint"`
+
+	BridgeName TaskType `json:"bridge,omitempty"`
 }
 
 type PollTimerConfig struct {
@@ -287,6 +359,120 @@ func (itc *IdleTimerConfig) Scan(value interface{}) error {
 	return json.Unmarshal(b, itc)
 }
 
+// FluxAggregationMethod is the way a flux monitor reduces the values polled
+// from its feeds to the one it reports.
+type FluxAggregationMethod string
+
+const (
+	// FluxAggregationMedian takes the median of the values.
+	FluxAggregationMedian = FluxAggregationMethod("median")
+	// FluxAggregationTrimmedMean takes the mean of the values, after dropping
+	// a fraction of them from each end.
+	FluxAggregationTrimmedMean = FluxAggregationMethod("trimmedMean")
+	// FluxAggregationWeighted takes the mean of the values, weighted by the
+	// reliability of the feeds they came from.
+	FluxAggregationWeighted = FluxAggregationMethod("weighted")
+)
+
+// FluxAggregationConfig is how a flux monitor aggregates the values polled
+// from its feeds. MaxDeviation is the percentage by which a value may differ
+// from the median of all values before its feed is excluded from the round.
+type FluxAggregationConfig struct {
+	Method       FluxAggregationMethod `json:"method,omitempty"`
+	Trim         float64               `json:"trim,omitempty"`
+	MaxDeviation float64               `json:"maxDeviation,omitempty"`
+}
+
+// Value is defined so that we can store FluxAggregationConfig as JSONB,
+// as for PollTimerConfig.
+func (fac FluxAggregationConfig) Value() (driver.Value, error) {
+	return json.Marshal(fac)
+}
+
+// Scan is defined so that we can read FluxAggregationConfig as JSONB, as for
+// PollTimerConfig.
+func (fac *FluxAggregationConfig) Scan(value interface{}) error {
+	if value == nil {
+		*fac = FluxAggregationConfig{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid Scan Source")
+	}
+	return json.Unmarshal(b, fac)
+}
+
+// FluxGasThrottleConfig holds back a flux monitor from starting new rounds
+// while gas is expensive. When the gas price exceeds GasPriceCeiling, in wei,
+// a new round is only started if the answer has deviated by more than the
+// EmergencyThreshold percentage, rather than the usual threshold.
+type FluxGasThrottleConfig struct {
+	GasPriceCeiling    *utils.Big `json:"gasPriceCeiling,omitempty"`
+	EmergencyThreshold float32    `json:"emergencyThreshold,omitempty"`
+}
+
+// Enabled returns true if a gas price ceiling has been set.
+func (gtc FluxGasThrottleConfig) Enabled() bool {
+	return gtc.GasPriceCeiling != nil
+}
+
+// Value is defined so that we can store FluxGasThrottleConfig as JSONB, as
+// for PollTimerConfig.
+func (gtc FluxGasThrottleConfig) Value() (driver.Value, error) {
+	return json.Marshal(gtc)
+}
+
+// Scan is defined so that we can read FluxGasThrottleConfig as JSONB, as for
+// PollTimerConfig.
+func (gtc *FluxGasThrottleConfig) Scan(value interface{}) error {
+	if value == nil {
+		*gtc = FluxGasThrottleConfig{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid Scan Source")
+	}
+	return json.Unmarshal(b, gtc)
+}
+
+// OffchainReportingOracle is one of the nodes taking part in off-chain
+// reporting for an aggregator, given by its ID and multiaddress on the p2p
+// network, and the address recovered from its signatures.
+type OffchainReportingOracle struct {
+	PeerID         string         `json:"peerId"`
+	Addr           string         `json:"addr"`
+	SigningAddress common.Address `json:"signingAddress"`
+}
+
+// OffchainReportingConfig is the set of oracles which exchange observations
+// to agree on the reports transmitted to an aggregator. Every oracle is
+// configured with the same set, including itself.
+type OffchainReportingConfig struct {
+	Oracles []OffchainReportingOracle `json:"oracles,omitempty"`
+}
+
+// Value is defined so that we can store OffchainReportingConfig as JSONB, as
+// for PollTimerConfig.
+func (orc OffchainReportingConfig) Value() (driver.Value, error) {
+	return json.Marshal(orc)
+}
+
+// Scan is defined so that we can read OffchainReportingConfig as JSONB, as
+// for PollTimerConfig.
+func (orc *OffchainReportingConfig) Scan(value interface{}) error {
+	if value == nil {
+		*orc = OffchainReportingConfig{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid Scan Source")
+	}
+	return json.Unmarshal(b, orc)
+}
+
 // Topics handle the serialization of ethereum log topics to and from the data store.
 type Topics [][]common.Hash
 
@@ -349,12 +535,63 @@ type Feeds = JSON
 // TaskSpec is the definition of work to be carried out. The
 // Type will be an adapter, and the Params will contain any
 // additional information that adapter would need to operate.
+//
+// Tasks run one after the other, each taking the result of the one before,
+// unless they declare their Inputs: the names of the tasks whose results they
+// take. The tasks of such a job make up a graph, in which tasks run as soon as
+// their inputs are complete, alongside any others which are, and tasks
+// without inputs take the parameters of the run request only.
+//
+// Confirmations is how many confirmations the log initiating the run needs
+// before the task starts, and OutgoingConfirmations, of tasks sending
+// transactions, how many their transaction needs before the task completes.
 type TaskSpec struct {
 	gorm.Model
-	JobSpecID     *ID           `json:"-"`
-	Type          TaskType      `json:"type" gorm:"index;not null"`
-	Confirmations clnull.Uint32 `json:"confirmations"`
-	Params        JSON          `json:"params" gorm:"type:text"`
+	JobSpecID             *ID            `json:"-"`
+	Type                  TaskType       `json:"type" gorm:"index;not null"`
+	Confirmations         clnull.Uint32  `json:"confirmations"`
+	OutgoingConfirmations clnull.Uint32  `json:"outgoingConfirmations"`
+	Params                JSON           `json:"params" gorm:"type:text"`
+	Name                  string         `json:"name,omitempty" gorm:"not null"`
+	Inputs                []string       `json:"inputs,omitempty" gorm:"-"`
+	Edges                 []TaskSpecEdge `json:"-" gorm:"foreignkey:TaskSpecID"`
+}
+
+// TaskSpecEdge records that a task takes the result of another as input.
+type TaskSpecEdge struct {
+	ID              uint64 `gorm:"primary_key"`
+	TaskSpecID      uint   `gorm:"not null"`
+	InputTaskSpecID uint   `gorm:"not null"`
+}
+
+// IsTaskGraph returns whether the tasks of the job declare their inputs,
+// rather than each taking the result of the task before.
+func (j JobSpec) IsTaskGraph() bool {
+	for _, task := range j.Tasks {
+		if len(task.Inputs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AfterFind sets the Inputs of the tasks from their edges, which refer to
+// their inputs by ID rather than by name.
+func (j *JobSpec) AfterFind() error {
+	names := map[uint]string{}
+	for _, task := range j.Tasks {
+		names[task.ID] = task.Name
+	}
+	for i, task := range j.Tasks {
+		if len(task.Edges) == 0 {
+			continue
+		}
+		j.Tasks[i].Inputs = make([]string, len(task.Edges))
+		for k, edge := range task.Edges {
+			j.Tasks[i].Inputs[k] = names[edge.InputTaskSpecID]
+		}
+	}
+	return nil
 }
 
 // TaskType defines what Adapter a TaskSpec will use.
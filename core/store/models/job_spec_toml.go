@@ -0,0 +1,177 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+)
+
+// JobSpecTOMLContentType is the content type of job specs written in TOML,
+// which POST /v2/specs accepts as well as JSON.
+const JobSpecTOMLContentType = "application/toml"
+
+// JobSpecLines are the lines of a TOML job spec which its initiators and
+// tasks start on, so that the errors found validating them can point at them.
+type JobSpecLines struct {
+	Initiators []int
+	Tasks      []int
+}
+
+// Initiator returns the line initiator i starts on, or zero if unknown.
+func (l JobSpecLines) Initiator(i int) int {
+	if i < len(l.Initiators) {
+		return l.Initiators[i]
+	}
+	return 0
+}
+
+// Task returns the line task i starts on, or zero if unknown.
+func (l JobSpecLines) Task(i int) int {
+	if i < len(l.Tasks) {
+		return l.Tasks[i]
+	}
+	return 0
+}
+
+// ParseJobSpecTOML parses a job spec written in TOML, which has the same
+// fields as one written in JSON, with the initiators and tasks as arrays of
+// tables:
+//
+//	minPayment = "1000000000000000000"
+//
+//	[[initiators]]
+//	type = "cron"
+//	params = { schedule = "CRON_TZ=UTC 0 0 * * *" }
+//
+//	[[tasks]]
+//	type = "httpget"
+//	[tasks.params]
+//	get = "https://example.com/api"
+//
+// Keys the schema does not have are rejected, and all errors carry the line
+// they were found on.
+func ParseJobSpecTOML(b []byte) (JobSpecRequest, JobSpecLines, error) {
+	var jsr JobSpecRequest
+	var lines JobSpecLines
+
+	tree, err := toml.LoadBytes(b)
+	if err != nil {
+		return jsr, lines, errors.Wrap(err, "invalid TOML")
+	}
+	if err := checkTOMLKeys(tree, "job spec", "startAt", "endAt", "minPayment", "maxConcurrentRuns", "incomingConfirmations", "outgoingConfirmations", "initiators", "tasks"); err != nil {
+		return jsr, lines, err
+	}
+
+	fields := map[string]interface{}{
+		"startAt":               &jsr.StartAt,
+		"endAt":                 &jsr.EndAt,
+		"minPayment":            &jsr.MinPayment,
+		"maxConcurrentRuns":     &jsr.MaxConcurrentRuns,
+		"incomingConfirmations": &jsr.IncomingConfirmations,
+		"outgoingConfirmations": &jsr.OutgoingConfirmations,
+	}
+	for key, field := range fields {
+		if tree.Has(key) {
+			if err := decodeTOMLValue(tree.Get(key), field); err != nil {
+				return jsr, lines, tomlError(tomlPosition(tree, key), "%s: %v", key, err)
+			}
+		}
+	}
+
+	initiators, err := tomlTables(tree, "initiators")
+	if err != nil {
+		return jsr, lines, err
+	}
+	for _, t := range initiators {
+		if err := checkTOMLKeys(t, "initiator", "type", "params"); err != nil {
+			return jsr, lines, err
+		}
+		var ir InitiatorRequest
+		if err := decodeTOMLValue(t.ToMap(), &ir); err != nil {
+			return jsr, lines, tomlError(t.Position(), "initiator: %v", err)
+		}
+		jsr.Initiators = append(jsr.Initiators, ir)
+		lines.Initiators = append(lines.Initiators, t.Position().Line)
+	}
+
+	tasks, err := tomlTables(tree, "tasks")
+	if err != nil {
+		return jsr, lines, err
+	}
+	for _, t := range tasks {
+		if err := checkTOMLKeys(t, "task", "type", "name", "confirmations", "outgoingConfirmations", "inputs", "params"); err != nil {
+			return jsr, lines, err
+		}
+		var tr TaskSpecRequest
+		if err := decodeTOMLValue(t.ToMap(), &tr); err != nil {
+			return jsr, lines, tomlError(t.Position(), "task: %v", err)
+		}
+		jsr.Tasks = append(jsr.Tasks, tr)
+		lines.Tasks = append(lines.Tasks, t.Position().Line)
+	}
+	return jsr, lines, nil
+}
+
+// checkTOMLKeys returns an error pointing at the first key of tree which is
+// not one of keys.
+func checkTOMLKeys(tree *toml.Tree, name string, keys ...string) error {
+	allowed := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		allowed[k] = true
+	}
+	var unknown []string
+	for _, k := range tree.Keys() {
+		if !allowed[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Slice(unknown, func(i, j int) bool {
+		return tomlPosition(tree, unknown[i]).Line < tomlPosition(tree, unknown[j]).Line
+	})
+	return tomlError(tomlPosition(tree, unknown[0]), "unknown key '%s' in %s", unknown[0], name)
+}
+
+// tomlPosition returns the position of key in tree, or that of tree for the
+// keys of inline tables, which have none.
+func tomlPosition(tree *toml.Tree, key string) toml.Position {
+	if pos := tree.GetPosition(key); !pos.Invalid() {
+		return pos
+	}
+	return tree.Position()
+}
+
+// tomlTables returns the tables of the array of tables key, such as
+// [[tasks]].
+func tomlTables(tree *toml.Tree, key string) ([]*toml.Tree, error) {
+	if !tree.Has(key) {
+		return nil, nil
+	}
+	tables, ok := tree.Get(key).([]*toml.Tree)
+	if !ok {
+		return nil, tomlError(tomlPosition(tree, key), "%s must be an array of tables, written [[%s]]", key, key)
+	}
+	return tables, nil
+}
+
+// decodeTOMLValue decodes a value of a TOML document into field, the way it
+// would be decoded from the same value in a JSON job spec.
+func decodeTOMLValue(value interface{}, field interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, field)
+}
+
+func tomlError(pos toml.Position, format string, args ...interface{}) error {
+	if pos.Invalid() {
+		return fmt.Errorf(format, args...)
+	}
+	return fmt.Errorf("line %d: %s", pos.Line, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,73 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJobSpecTOML(t *testing.T) {
+	t.Parallel()
+
+	spec := `# Fetches the price every day at midnight
+startAt = 2020-01-01T00:00:00Z
+minPayment = "1000000000000000000"
+
+[[initiators]]
+type = "cron"
+params = { schedule = "CRON_TZ=UTC 0 0 * * *" }
+
+[[tasks]]
+type = "httpget"
+confirmations = 3
+[tasks.params]
+get = "https://example.com/api"
+
+[[tasks]]
+type = "jsonparse"
+params = { path = ["USD"] }
+`
+	jsr, lines, err := models.ParseJobSpecTOML([]byte(spec))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), jsr.StartAt.Time.UTC())
+	assert.Equal(t, assets.NewLink(1000000000000000000), jsr.MinPayment)
+	require.Len(t, jsr.Initiators, 1)
+	assert.Equal(t, models.InitiatorCron, jsr.Initiators[0].Type)
+	assert.Equal(t, "CRON_TZ=UTC 0 0 * * *", string(jsr.Initiators[0].Schedule))
+	require.Len(t, jsr.Tasks, 2)
+	assert.Equal(t, models.MustNewTaskType("httpget"), jsr.Tasks[0].Type)
+	assert.Equal(t, uint32(3), jsr.Tasks[0].Confirmations.Uint32)
+	assert.Equal(t, "https://example.com/api", jsr.Tasks[0].Params.Get("get").String())
+	assert.Equal(t, "USD", jsr.Tasks[1].Params.Get("path.0").String())
+	assert.Equal(t, models.JobSpecLines{Initiators: []int{5}, Tasks: []int{9, 15}}, lines)
+}
+
+func TestParseJobSpecTOML_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{"invalid TOML", "[[tasks]]\ntype = \n", "invalid TOML: (3, 1)"},
+		{"unknown key", "minPayment = \"1\"\nminPaymnet = \"2\"\n", "line 2: unknown key 'minPaymnet' in job spec"},
+		{"unknown task key", "[[tasks]]\ntype = \"noop\"\nparam = 1\n", "line 3: unknown key 'param' in task"},
+		{"wrong type", "maxConcurrentRuns = \"two\"\n", "line 1: maxConcurrentRuns"},
+		{"not an array of tables", "tasks = \"noop\"\n", "line 1: tasks must be an array of tables"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := models.ParseJobSpecTOML([]byte(test.spec))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.want)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// JobSpecVersion is a snapshot of a prior definition of a JobSpec, recorded
+// whenever the JobSpec is updated in place.
+type JobSpecVersion struct {
+	ID        uint64    `json:"-" gorm:"primary_key"`
+	JobSpecID *ID       `json:"jobSpecId" gorm:"not null"`
+	Version   uint32    `json:"version" gorm:"not null"`
+	Spec      JSON      `json:"spec" gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NewJobSpecVersion returns a snapshot of the given JobSpec, labelled with the
+// passed version number.
+func NewJobSpecVersion(job JobSpec, version uint32) (JobSpecVersion, error) {
+	b, err := json.Marshal(NewJobSpecRequestFromJob(job))
+	if err != nil {
+		return JobSpecVersion{}, err
+	}
+	spec, err := ParseJSON(b)
+	if err != nil {
+		return JobSpecVersion{}, err
+	}
+	return JobSpecVersion{
+		JobSpecID: job.ID,
+		Version:   version,
+		Spec:      spec,
+	}, nil
+}
+
+// GetID returns the version number of this snapshot for jsonapi serialization.
+func (v JobSpecVersion) GetID() string {
+	return strconv.FormatUint(uint64(v.Version), 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (v JobSpecVersion) GetName() string {
+	return "job_spec_versions"
+}
+
+// SetID is used to set the version number of this structure when deserializing
+// from jsonapi documents.
+func (v *JobSpecVersion) SetID(value string) error {
+	version, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return err
+	}
+	v.Version = uint32(version)
+	return nil
+}
+
+// JobSpecRequest returns the definition captured by this snapshot, in the
+// form accepted by the API when creating or updating a JobSpec.
+func (v JobSpecVersion) JobSpecRequest() (JobSpecRequest, error) {
+	var jsr JobSpecRequest
+	if err := json.Unmarshal([]byte(v.Spec.Raw), &jsr); err != nil {
+		return jsr, errors.Wrapf(err, "unable to parse version %d of job %s", v.Version, v.JobSpecID)
+	}
+	return jsr, nil
+}
+
+// NewJobSpecRequestFromJob returns the API representation of a JobSpec's
+// definition, leaving out identifiers and timestamps assigned by the node.
+func NewJobSpecRequestFromJob(job JobSpec) JobSpecRequest {
+	jsr := JobSpecRequest{
+		Initiators:        make([]InitiatorRequest, len(job.Initiators)),
+		Tasks:             make([]TaskSpecRequest, len(job.Tasks)),
+		StartAt:           job.StartAt,
+		EndAt:             job.EndAt,
+		MinPayment:        job.MinPayment,
+		MaxConcurrentRuns: job.MaxConcurrentRuns,
+
+		IncomingConfirmations: job.IncomingConfirmations,
+		OutgoingConfirmations: job.OutgoingConfirmations,
+	}
+	for i, initr := range job.Initiators {
+		jsr.Initiators[i] = InitiatorRequest{
+			Type:            initr.Type,
+			InitiatorParams: initr.InitiatorParams,
+		}
+	}
+	for i, task := range job.Tasks {
+		jsr.Tasks[i] = TaskSpecRequest{
+			Type:                  task.Type,
+			Confirmations:         task.Confirmations,
+			OutgoingConfirmations: task.OutgoingConfirmations,
+			Params:                task.Params,
+			Name:                  task.Name,
+			Inputs:                task.Inputs,
+		}
+	}
+	return jsr
+}
+
+// JobSpecChange describes a single value that differs between two job
+// definitions. Path is a dot separated path to the value, e.g.
+// "tasks.1.params.url". From or To is nil when the value was added or removed.
+type JobSpecChange struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// DiffJobSpecRequests returns the changes required to turn the from job
+// definition into the to job definition, sorted by path.
+func DiffJobSpecRequests(from, to JobSpecRequest) ([]JobSpecChange, error) {
+	fromValue, err := genericJSON(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := genericJSON(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []JobSpecChange{}
+	diffJSONValues("", fromValue, toValue, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func genericJSON(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	return generic, json.Unmarshal(b, &generic)
+}
+
+func diffJSONValues(path string, from, to interface{}, changes *[]JobSpecChange) {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		if toValue, ok := to.(map[string]interface{}); ok {
+			for key, value := range fromValue {
+				diffJSONValues(joinJSONPath(path, key), value, toValue[key], changes)
+			}
+			for key, value := range toValue {
+				if _, ok := fromValue[key]; !ok {
+					diffJSONValues(joinJSONPath(path, key), nil, value, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if toValue, ok := to.([]interface{}); ok {
+			for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+				var fromElem, toElem interface{}
+				if i < len(fromValue) {
+					fromElem = fromValue[i]
+				}
+				if i < len(toValue) {
+					toElem = toValue[i]
+				}
+				diffJSONValues(joinJSONPath(path, strconv.Itoa(i)), fromElem, toElem, changes)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, JobSpecChange{Path: path, From: from, To: to})
+	}
+}
+
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return fmt.Sprintf("%s.%s", path, key)
+}
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJobSpecVersion_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	job := cltest.NewJobWithSchedule("CRON_TZ=UTC 0 0 * * *")
+	job.Tasks = []models.TaskSpec{cltest.NewTask(t, "httpget", `{"get":"https://example.com"}`)}
+
+	jsv, err := models.NewJobSpecVersion(job, 3)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, jsv.JobSpecID)
+	assert.Equal(t, "3", jsv.GetID())
+
+	jsr, err := jsv.JobSpecRequest()
+	require.NoError(t, err)
+	require.Len(t, jsr.Initiators, 1)
+	assert.Equal(t, models.InitiatorCron, jsr.Initiators[0].Type)
+	assert.Equal(t, job.Initiators[0].Schedule, jsr.Initiators[0].Schedule)
+	require.Len(t, jsr.Tasks, 1)
+	assert.Equal(t, "https://example.com", jsr.Tasks[0].Params.Get("get").String())
+}
+
+func TestDiffJobSpecRequests(t *testing.T) {
+	t.Parallel()
+
+	from := cltest.NewJobWithSchedule("CRON_TZ=UTC 0 0 * * *")
+	from.Tasks = []models.TaskSpec{cltest.NewTask(t, "httpget", `{"get":"https://example.com"}`)}
+
+	to := cltest.NewJobWithSchedule("CRON_TZ=UTC 0 12 * * *")
+	to.Tasks = []models.TaskSpec{
+		cltest.NewTask(t, "httpget", `{"get":"https://example.com"}`),
+		cltest.NewTask(t, "noop"),
+	}
+
+	changes, err := models.DiffJobSpecRequests(
+		models.NewJobSpecRequestFromJob(from),
+		models.NewJobSpecRequestFromJob(to),
+	)
+	require.NoError(t, err)
+
+	changed := map[string]models.JobSpecChange{}
+	for _, change := range changes {
+		changed[change.Path] = change
+	}
+	require.Contains(t, changed, "initiators.0.params.schedule")
+	assert.Equal(t, "CRON_TZ=UTC 0 0 * * *", changed["initiators.0.params.schedule"].From)
+	assert.Equal(t, "CRON_TZ=UTC 0 12 * * *", changed["initiators.0.params.schedule"].To)
+	require.Contains(t, changed, "tasks.1")
+	assert.Nil(t, changed["tasks.1"].From)
+	assert.NotContains(t, changed, "tasks.0.params.get")
+
+	unchanged, err := models.DiffJobSpecRequests(
+		models.NewJobSpecRequestFromJob(from),
+		models.NewJobSpecRequestFromJob(from),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, unchanged)
+}
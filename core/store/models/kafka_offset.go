@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// KafkaOffset records the next offset a kafka initiator will read from a
+// partition of one of its topics, so that a restarted node resumes where it
+// left off instead of triggering runs for messages it has already seen.
+type KafkaOffset struct {
+	InitiatorID uint32    `gorm:"primary_key;auto_increment:false"`
+	Topic       string    `gorm:"primary_key"`
+	Partition   int       `gorm:"primary_key;auto_increment:false"`
+	Offset      int64     `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
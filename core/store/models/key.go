@@ -8,7 +8,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/tidwall/gjson"
 	"go.uber.org/multierr"
+	"gopkg.in/guregu/null.v3"
 
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
 	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
 )
 
@@ -16,15 +18,27 @@ import (
 // said key when given a password.
 //
 // By default, a key is assumed to represent an ethereum account.
+//
+// A retired key, replaced by rotating it, is no longer used to send new
+// transactions, but those it already sent are still tracked until confirmed.
 type Key struct {
 	Address   EIP55Address `gorm:"primary_key;type:varchar(64)"`
 	JSON      JSON         `gorm:"type:text"`
 	CreatedAt time.Time    `json:"-"`
 	UpdatedAt time.Time    `json:"-"`
+	RetiredAt null.Time    `json:"-"`
+	// KeyRef is set instead of JSON for keys held by a KMS or HSM, and
+	// refers to the key there, e.g. "awskms://arn:aws:kms:...".
+	KeyRef null.String `json:"-"`
+	// Namespace scopes which namespace tokens see the key's balances, the
+	// default namespace being empty, and reserves the key for the
+	// transactions of the jobs in it.
+	Namespace string `json:"-" gorm:"not null"`
 }
 
 type EncryptedSecretVRFKey = vrfkey.EncryptedSecretKey
 type PublicKey = vrfkey.PublicKey
+type EncryptedOCRKeyBundle = ocrkey.EncryptedKeyBundle
 
 // NewKeyFromFile creates an instance in memory from a key file on disk.
 func NewKeyFromFile(path string) (*Key, error) {
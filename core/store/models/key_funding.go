@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// KeyFunding records ETH sent from the funding key to another of the node's
+// keys whose balance had dropped below KEY_FUNDING_THRESHOLD_WEI. It is
+// recorded before its transaction is sent, which it is then given, or is
+// marked failed with the error if the transaction could not be sent.
+type KeyFunding struct {
+	ID     uint64         `json:"-" gorm:"primary_key"`
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+	Amount *utils.Big     `json:"amount"`
+	// Balance is that of the funded key when it was found below the
+	// threshold.
+	Balance   *utils.Big   `json:"balance"`
+	TxID      null.Int     `json:"-"`
+	TxHash    *common.Hash `json:"txHash"`
+	FailedAt  null.Time    `json:"failedAt"`
+	Error     null.String  `json:"error"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// NewKeyFunding returns the record of amount about to be sent from the
+// funding key to a key whose balance is balance.
+func NewKeyFunding(from, to common.Address, amount, balance *utils.Big) KeyFunding {
+	return KeyFunding{
+		From:    from,
+		To:      to,
+		Amount:  amount,
+		Balance: balance,
+	}
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (f KeyFunding) GetID() string {
+	return strconv.FormatUint(f.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (f KeyFunding) GetName() string {
+	return "key_fundings"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (f *KeyFunding) SetID(value string) error {
+	id, err := strconv.ParseUint(value, 10, 64)
+	f.ID = id
+	return err
+}
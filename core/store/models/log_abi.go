@@ -0,0 +1,195 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// LogABI is the ABI fragment, in the JSON format of solc, of the events
+// whose logs an ethlog initiator decodes into named fields. A single event
+// may be given as an object rather than an array.
+type LogABI []byte
+
+// Parse returns the ABI of the fragment, which must have at least one event
+// which is not anonymous.
+func (a LogABI) Parse() (abi.ABI, error) {
+	fragment := bytes.TrimSpace(a)
+	if len(fragment) > 0 && fragment[0] == '{' {
+		fragment = append(append([]byte{'['}, fragment...), ']')
+	}
+	parsed, err := abi.JSON(bytes.NewReader(fragment))
+	if err != nil {
+		return abi.ABI{}, errors.Wrap(err, "invalid ABI")
+	}
+	for _, event := range parsed.Events {
+		if !event.Anonymous {
+			return parsed, nil
+		}
+	}
+	return abi.ABI{}, errors.New("ABI must have an event which is not anonymous")
+}
+
+// EventIDs returns the topics identifying the events of the fragment.
+func (a LogABI) EventIDs() ([]common.Hash, error) {
+	parsed, err := a.Parse()
+	if err != nil {
+		return nil, err
+	}
+	var ids []common.Hash
+	for _, event := range parsed.Events {
+		if !event.Anonymous {
+			ids = append(ids, event.ID())
+		}
+	}
+	return ids, nil
+}
+
+// Decode returns the name of the event of the fragment the log was emitted
+// for, and its arguments by name. Integers are given as decimal strings, to
+// keep their precision, and addresses and bytes in hex. Indexed arguments of
+// dynamic types are only logged as their hash, which is given instead. The
+// name is empty if the log is for none of its events.
+func (a LogABI) Decode(log eth.Log) (string, map[string]interface{}, error) {
+	parsed, err := a.Parse()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(log.Topics) == 0 {
+		return "", nil, nil
+	}
+	event, err := parsed.EventByID(log.Topics[0])
+	if err != nil {
+		return "", nil, nil
+	}
+
+	args := make(map[string]interface{}, len(event.Inputs))
+	values, err := event.Inputs.NonIndexed().UnpackValues(log.Data)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "decoding %s log", event.RawName)
+	}
+	topics := log.Topics[1:]
+	nonIndexed := 0
+	for i, input := range event.Inputs {
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		if !input.Indexed {
+			args[name] = abiValueToJSON(values[nonIndexed])
+			nonIndexed++
+			continue
+		}
+		if len(topics) == 0 {
+			return "", nil, fmt.Errorf("decoding %s log: missing topic for %s", event.RawName, name)
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		switch input.Type.T {
+		case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			args[name] = topic.Hex()
+		default:
+			value, err := abi.Arguments{{Type: input.Type}}.UnpackValues(topic.Bytes())
+			if err != nil {
+				return "", nil, errors.Wrapf(err, "decoding %s log", event.RawName)
+			}
+			args[name] = abiValueToJSON(value[0])
+		}
+	}
+	return event.RawName, args, nil
+}
+
+// abiValueToJSON returns a value decoded from an ABI encoding as it is given
+// in run data.
+func abiValueToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case bool, string:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(value)
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		values := make([]interface{}, rv.Len())
+		for i := range values {
+			values[i] = abiValueToJSON(rv.Index(i).Interface())
+		}
+		return values
+	case reflect.Struct:
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			fields[rv.Type().Field(i).Name] = abiValueToJSON(rv.Field(i).Interface())
+		}
+		return fields
+	}
+	return value
+}
+
+// MarshalJSON returns the fragment as given.
+func (a LogABI) MarshalJSON() ([]byte, error) {
+	if len(a) == 0 {
+		return []byte("null"), nil
+	}
+	return a, nil
+}
+
+// UnmarshalJSON keeps the fragment as given, which is checked when its
+// initiator is validated.
+func (a *LogABI) UnmarshalJSON(input []byte) error {
+	if string(input) == "null" {
+		*a = nil
+		return nil
+	}
+	*a = append(LogABI{}, input...)
+	return nil
+}
+
+// Value returns the fragment for storage as JSONB.
+func (a LogABI) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	return string(a), nil
+}
+
+// Scan reads the fragment stored as JSONB.
+func (a *LogABI) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+	case []byte:
+		*a = append(LogABI{}, v...)
+	case string:
+		*a = LogABI(v)
+	default:
+		return fmt.Errorf("Unable to convert %v of %T to LogABI", value, value)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package models_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const transferABI = `{"type":"event","name":"Transfer","inputs":[
+	{"name":"from","type":"address","indexed":true},
+	{"name":"to","type":"address","indexed":true},
+	{"name":"value","type":"uint256","indexed":false},
+	{"name":"memo","type":"string","indexed":true}]}`
+
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256,string)"))
+
+func transferLog() eth.Log {
+	return eth.Log{
+		Address: common.HexToAddress("0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"),
+		Topics: []common.Hash{
+			transferTopic,
+			common.HexToHash("0x000000000000000000000000000000000000000000000000000000000000000a"),
+			common.HexToHash("0x000000000000000000000000000000000000000000000000000000000000000b"),
+			crypto.Keccak256Hash([]byte("rent")),
+		},
+		Data: common.LeftPadBytes(new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil).Bytes(), 32),
+	}
+}
+
+func TestLogABI_Parse(t *testing.T) {
+	t.Parallel()
+
+	_, err := models.LogABI(transferABI).Parse()
+	assert.NoError(t, err)
+	_, err = models.LogABI("[" + transferABI + "]").Parse()
+	assert.NoError(t, err)
+	_, err = models.LogABI(`[{"type":"function","name":"transfer","inputs":[]}]`).Parse()
+	assert.EqualError(t, err, "ABI must have an event which is not anonymous")
+	_, err = models.LogABI(`[{"type":"event","name":"Bad","inputs":[{"name":"x","type":"notatype"}]}]`).Parse()
+	assert.Error(t, err)
+
+	ids, err := models.LogABI(transferABI).EventIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{transferTopic}, ids)
+}
+
+func TestLogABI_Decode(t *testing.T) {
+	t.Parallel()
+
+	event, args, err := models.LogABI(transferABI).Decode(transferLog())
+	require.NoError(t, err)
+	assert.Equal(t, "Transfer", event)
+	assert.Equal(t, map[string]interface{}{
+		"from":  "0x000000000000000000000000000000000000000A",
+		"to":    "0x000000000000000000000000000000000000000b",
+		"value": "100000000000000000000",
+		"memo":  crypto.Keccak256Hash([]byte("rent")).Hex(),
+	}, args)
+
+	other := transferLog()
+	other.Topics[0] = common.HexToHash("0x01")
+	event, _, err = models.LogABI(transferABI).Decode(other)
+	require.NoError(t, err)
+	assert.Empty(t, event)
+}
+
+func TestEthLogEvent_JSON_WithABI(t *testing.T) {
+	t.Parallel()
+
+	initr := models.Initiator{
+		Type:            models.InitiatorEthLog,
+		InitiatorParams: models.InitiatorParams{ABI: models.LogABI(transferABI)},
+	}
+	le := models.InitiatorLogEvent{Initiator: initr, Log: transferLog()}.LogRequest()
+	rr, err := le.RunRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "Transfer", rr.RequestParams.Get("event").String())
+	assert.Equal(t, "100000000000000000000", rr.RequestParams.Get("args.value").String())
+	assert.Equal(t, transferTopic.Hex(), rr.RequestParams.Get("topics.0").String())
+}
+
+func TestFilterQueryFactory_InitiatorEthLogWithABI(t *testing.T) {
+	t.Parallel()
+
+	i := models.Initiator{
+		Type:            models.InitiatorEthLog,
+		InitiatorParams: models.InitiatorParams{ABI: models.LogABI(transferABI)},
+	}
+	filter, err := models.FilterQueryFactory(i, big.NewInt(42))
+	require.NoError(t, err)
+	assert.Equal(t, [][]common.Hash{{transferTopic}}, filter.Topics)
+}
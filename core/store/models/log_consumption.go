@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -11,18 +12,40 @@ import (
 // already consumed a particular log. This record can be used to prevent consumers
 // from re-processing duplicate logs
 type LogConsumption struct {
-	ID        uint
-	BlockHash common.Hash
-	LogIndex  uint
-	JobID     *ID
-	CreatedAt time.Time
+	ID          uint        `json:"id"`
+	BlockHash   common.Hash `json:"blockHash"`
+	BlockNumber uint64      `json:"blockNumber"`
+	LogIndex    uint        `json:"logIndex"`
+	JobID       *ID         `json:"jobId"`
+	CreatedAt   time.Time   `json:"createdAt"`
 }
 
 // NewLogConsumption creates a new LogConsumption
 func NewLogConsumption(log eth.RawLog, jobID *ID) LogConsumption {
 	return LogConsumption{
-		BlockHash: log.GetBlockHash(),
-		LogIndex:  log.GetIndex(),
-		JobID:     jobID,
+		BlockHash:   log.GetBlockHash(),
+		BlockNumber: log.GetBlockNumber(),
+		LogIndex:    log.GetIndex(),
+		JobID:       jobID,
 	}
 }
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (lc LogConsumption) GetID() string {
+	return strconv.FormatUint(uint64(lc.ID), 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (lc LogConsumption) GetName() string {
+	return "log_consumptions"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (lc *LogConsumption) SetID(value string) error {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return err
+	}
+	lc.ID = uint(id)
+	return nil
+}
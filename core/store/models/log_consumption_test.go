@@ -28,8 +28,9 @@ func TestCreateLogConsumption_Happy(t *testing.T) {
 		JobID:     job1.ID,
 	}
 
-	err = store.ORM.CreateLogConsumption(&logConsumption1)
+	created, err := store.ORM.CreateLogConsumption(&logConsumption1)
 	require.NoError(t, err)
+	require.True(t, created)
 
 	tests := []struct {
 		description string
@@ -49,8 +50,9 @@ func TestCreateLogConsumption_Happy(t *testing.T) {
 				JobID:     test.JobID,
 			}
 
-			err = store.ORM.CreateLogConsumption(&logConsumption2)
+			created, err := store.ORM.CreateLogConsumption(&logConsumption2)
 			require.NoError(t, err)
+			require.True(t, created)
 		})
 	}
 
@@ -75,8 +77,9 @@ func TestCreateLogConsumption_Errors(t *testing.T) {
 		JobID:     job1.ID,
 	}
 
-	err = store.ORM.CreateLogConsumption(&logConsumption1)
+	created, err := store.ORM.CreateLogConsumption(&logConsumption1)
 	require.NoError(t, err)
+	require.True(t, created)
 
 	tests := []struct {
 		description string
@@ -85,7 +88,6 @@ func TestCreateLogConsumption_Errors(t *testing.T) {
 		JobID       *models.ID
 	}{
 		{"non existant job", cltest.NewHash(), 0, models.NewID()},
-		{"duplicate record", logConsumption1.BlockHash, logConsumption1.LogIndex, logConsumption1.JobID},
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
@@ -94,8 +96,34 @@ func TestCreateLogConsumption_Errors(t *testing.T) {
 				LogIndex:  test.LogIndex,
 				JobID:     test.JobID,
 			}
-			err = store.ORM.CreateLogConsumption(&logConsumption2)
+			_, err = store.ORM.CreateLogConsumption(&logConsumption2)
 			require.Error(t, err)
 		})
 	}
 }
+
+func TestCreateLogConsumption_Duplicate(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJob()
+	require.NoError(t, store.ORM.CreateJob(&job))
+
+	logConsumption1 := models.LogConsumption{
+		BlockHash: cltest.NewHash(),
+		LogIndex:  0,
+		JobID:     job.ID,
+	}
+	created, err := store.ORM.CreateLogConsumption(&logConsumption1)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	logConsumption2 := logConsumption1
+	logConsumption2.ID = 0
+	created, err = store.ORM.CreateLogConsumption(&logConsumption2)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Zero(t, logConsumption2.ID)
+}
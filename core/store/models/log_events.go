@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/whisper/whisperv6"
 	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
 )
 
 // Descriptive indices of a RunLog's Topic array
@@ -138,6 +139,15 @@ func FilterQueryFactory(i Initiator, from *big.Int) (q ethereum.FilterQuery, err
 		// [][]common.Hash) clarifies their type for reflect.DeepEqual
 		q.Topics = make([][]common.Hash, len(i.Topics))
 		copy(q.Topics, i.Topics)
+
+		// Without topics, only the logs of the events of the ABI are matched.
+		if len(q.Topics) == 0 && len(i.ABI) > 0 {
+			ids, err := i.ABI.EventIDs()
+			if err != nil {
+				return ethereum.FilterQuery{}, err
+			}
+			q.Topics = [][]common.Hash{ids}
+		}
 	case initiationRequiresJobSpecID(i.Type):
 		q.Topics = [][]common.Hash{
 			TopicsForInitiatorsWhichRequireJobSpecIDTopic[i.Type],
@@ -210,7 +220,7 @@ func (le InitiatorLogEvent) GetInitiator() Initiator {
 // formatting in logs (trace statements, not ethereum events).
 func (le InitiatorLogEvent) ForLogger(kvs ...interface{}) []interface{} {
 	output := []interface{}{
-		"job", le.Initiator.JobSpecID.String(),
+		"job_id", le.Initiator.JobSpecID.String(),
 		"log", le.Log.BlockNumber,
 		"initiator", le.Initiator,
 	}
@@ -274,6 +284,35 @@ type EthLogEvent struct {
 	InitiatorLogEvent
 }
 
+// JSON returns the eth log as JSON, along with the name of its event and its
+// arguments by name, as "event" and "args", if the initiator has an ABI
+// with its event.
+func (le EthLogEvent) JSON() (JSON, error) {
+	out, err := le.InitiatorLogEvent.JSON()
+	if err != nil || len(le.Initiator.ABI) == 0 {
+		return out, err
+	}
+	event, args, err := le.Initiator.ABI.Decode(le.Log)
+	if err != nil || event == "" {
+		return out, err
+	}
+	if out, err = out.Add("event", event); err != nil {
+		return out, err
+	}
+	return out.Add("args", args)
+}
+
+// RunRequest returns a run request instance with the transaction hash, and
+// the log decoded with the initiator's ABI as its parameters.
+func (le EthLogEvent) RunRequest() (RunRequest, error) {
+	requestParams, err := le.JSON()
+	if err != nil {
+		return RunRequest{}, err
+	}
+	return RunRequest{BlockHash: &le.Log.BlockHash, TxHash: &le.Log.TxHash,
+		RequestParams: requestParams}, nil
+}
+
 // RunLogEvent provides functionality specific to a log event emitted
 // for a run log initiator.
 type RunLogEvent struct {
@@ -395,6 +434,8 @@ func (le RunLogEvent) RunRequest() (RunRequest, error) {
 		Requester:     &requester,
 		Payment:       payment,
 		RequestParams: requestParams,
+		JobSpecID:     le.GetJobSpecID(),
+		LogIndex:      null.IntFrom(int64(le.Log.Index)),
 	}, nil
 }
 
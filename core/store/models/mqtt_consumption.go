@@ -0,0 +1,38 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// An MQTTConsumption records that an mqtt initiator has already triggered a
+// run for a message, so that the same message redelivered by the broker does
+// not trigger a second run.
+type MQTTConsumption struct {
+	ID          uint64
+	InitiatorID uint32
+	MessageKey  string
+	CreatedAt   time.Time
+}
+
+// NewMQTTConsumption creates a new MQTTConsumption for a message received by
+// the given initiator.
+func NewMQTTConsumption(initiatorID uint32, topic string, messageID uint16, payload []byte) MQTTConsumption {
+	return MQTTConsumption{
+		InitiatorID: initiatorID,
+		MessageKey:  MQTTMessageKey(topic, messageID, payload),
+	}
+}
+
+// MQTTMessageKey identifies a message received from an mqtt broker. Message
+// IDs are recycled by brokers, so the key also covers the topic and payload.
+func MQTTMessageKey(topic string, messageID uint16, payload []byte) string {
+	h := sha256.New()
+	h.Write([]byte(topic))
+	h.Write([]byte{0})
+	binary.Write(h, binary.BigEndian, messageID)
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/pkg/errors"
+)
+
+// namespaceRegexp matches the names of namespaces, which are DNS labels.
+var namespaceRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidateNamespace returns an error if ns is neither empty, the default
+// namespace, nor a lowercase DNS label.
+func ValidateNamespace(ns string) error {
+	if ns == "" || namespaceRegexp.MatchString(ns) {
+		return nil
+	}
+	return fmt.Errorf("namespace %q must be lowercase letters, digits and dashes, at most 63 characters", ns)
+}
+
+// NamespaceRole is what a namespace token may do in its namespace.
+type NamespaceRole string
+
+const (
+	// NamespaceViewer may see the jobs, bridges and key balances in its
+	// namespace.
+	NamespaceViewer = NamespaceRole("viewer")
+	// NamespaceEditor may also create, change and delete them.
+	NamespaceEditor = NamespaceRole("editor")
+)
+
+// CanMutate returns true if the role may create, change and delete records.
+func (r NamespaceRole) CanMutate() bool {
+	return r == NamespaceEditor
+}
+
+// NamespaceTokenRequest is the incoming record used to create a
+// NamespaceToken.
+type NamespaceTokenRequest struct {
+	Namespace string        `json:"namespace"`
+	Role      NamespaceRole `json:"role"`
+}
+
+// Validate returns an error if the namespace is not a valid, non default
+// one, or the role is unknown.
+func (r NamespaceTokenRequest) Validate() error {
+	if r.Namespace == "" {
+		return errors.New("namespace is required")
+	}
+	if err := ValidateNamespace(r.Namespace); err != nil {
+		return err
+	}
+	if r.Role != NamespaceViewer && r.Role != NamespaceEditor {
+		return fmt.Errorf("role must be %s or %s", NamespaceViewer, NamespaceEditor)
+	}
+	return nil
+}
+
+// NamespaceToken is an API token which only gives access to the jobs,
+// bridges and keys of one namespace, with the rights of its role, so that
+// teams sharing a node neither see nor change each other's.
+type NamespaceToken struct {
+	AccessKey    string        `json:"accessKey" gorm:"primary_key"`
+	Namespace    string        `json:"namespace" gorm:"not null"`
+	Role         NamespaceRole `json:"role" gorm:"not null"`
+	Salt         string        `json:"-" gorm:"not null"`
+	HashedSecret string        `json:"-" gorm:"not null"`
+	CreatedAt    time.Time     `json:"createdAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (nt NamespaceToken) GetID() string {
+	return nt.AccessKey
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (nt NamespaceToken) GetName() string {
+	return "namespace_tokens"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (nt *NamespaceToken) SetID(value string) error {
+	nt.AccessKey = value
+	return nil
+}
+
+// NewNamespaceToken returns the NamespaceToken of an auth.Token, hashing
+// its secret for storage.
+func NewNamespaceToken(token *auth.Token, ntr NamespaceTokenRequest) (*NamespaceToken, error) {
+	salt := utils.NewSecret(utils.DefaultSecretSize)
+	hashedSecret, err := auth.HashedSecret(token, salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "error hashing secret for namespace token")
+	}
+	return &NamespaceToken{
+		AccessKey:    token.AccessKey,
+		Namespace:    ntr.Namespace,
+		Role:         ntr.Role,
+		Salt:         salt,
+		HashedSecret: hashedSecret,
+	}, nil
+}
+
+// AuthenticateNamespaceToken returns true if the secret of token matches the
+// namespace token's.
+func AuthenticateNamespaceToken(token *auth.Token, nt *NamespaceToken) (bool, error) {
+	hashedSecret, err := auth.HashedSecret(token, nt.Salt)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare([]byte(hashedSecret), []byte(nt.HashedSecret)) == 1, nil
+}
+
+// NamespaceTokenAuthentication is the record returned in response to a
+// request to create a NamespaceToken, the only time its secret is shown.
+type NamespaceTokenAuthentication struct {
+	AccessKey string        `json:"accessKey"`
+	Secret    string        `json:"secret"`
+	Namespace string        `json:"namespace"`
+	Role      NamespaceRole `json:"role"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (nta NamespaceTokenAuthentication) GetID() string {
+	return nta.AccessKey
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (nta NamespaceTokenAuthentication) GetName() string {
+	return "namespace_tokens"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (nta *NamespaceTokenAuthentication) SetID(value string) error {
+	nta.AccessKey = value
+	return nil
+}
@@ -0,0 +1,65 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNamespace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		namespace string
+		valid     bool
+	}{
+		{"", true},
+		{"team-a", true},
+		{"a1", true},
+		{"Team-A", false},
+		{"-team", false},
+		{"team-", false},
+		{"team a", false},
+		{"a234567890123456789012345678901234567890123456789012345678901234", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.namespace, func(t *testing.T) {
+			err := models.ValidateNamespace(test.namespace)
+			assert.Equal(t, test.valid, err == nil)
+		})
+	}
+}
+
+func TestNamespaceTokenRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, models.NamespaceTokenRequest{Namespace: "team-a", Role: models.NamespaceViewer}.Validate())
+	assert.NoError(t, models.NamespaceTokenRequest{Namespace: "team-a", Role: models.NamespaceEditor}.Validate())
+	assert.Error(t, models.NamespaceTokenRequest{Namespace: "", Role: models.NamespaceViewer}.Validate())
+	assert.Error(t, models.NamespaceTokenRequest{Namespace: "Team A", Role: models.NamespaceViewer}.Validate())
+	assert.Error(t, models.NamespaceTokenRequest{Namespace: "team-a", Role: "admin"}.Validate())
+}
+
+func TestAuthenticateNamespaceToken(t *testing.T) {
+	t.Parallel()
+
+	token := auth.NewToken()
+	nt, err := models.NewNamespaceToken(token, models.NamespaceTokenRequest{Namespace: "team-a", Role: models.NamespaceEditor})
+	require.NoError(t, err)
+	assert.Equal(t, token.AccessKey, nt.AccessKey)
+	assert.NotEqual(t, token.Secret, nt.HashedSecret)
+	assert.True(t, nt.Role.CanMutate())
+
+	ok, err := models.AuthenticateNamespaceToken(token, nt)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = models.AuthenticateNamespaceToken(&auth.Token{AccessKey: token.AccessKey, Secret: "wrong"}, nt)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,80 @@
+// Package ocrkey holds the keys used for off-chain reporting, and their
+// encrypted form kept in the database.
+package ocrkey
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+)
+
+// KeyBundle holds the keys a node uses for off-chain reporting: an ed25519
+// key identifying the node on the p2p network, and a secp256k1 key signing
+// its observations and the reports it agrees to.
+type KeyBundle struct {
+	p2pKey     p2pcrypto.PrivKey
+	signingKey *ecdsa.PrivateKey
+	peerID     peer.ID
+}
+
+// NewKeyBundle generates a new bundle of off-chain reporting keys.
+func NewKeyBundle() (*KeyBundle, error) {
+	p2pKey, _, err := p2pcrypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "while generating p2p key")
+	}
+	signingKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "while generating signing key")
+	}
+	return newKeyBundle(p2pKey, signingKey)
+}
+
+func newKeyBundle(p2pKey p2pcrypto.PrivKey, signingKey *ecdsa.PrivateKey) (*KeyBundle, error) {
+	peerID, err := peer.IDFromPrivateKey(p2pKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "while deriving peer ID")
+	}
+	return &KeyBundle{p2pKey: p2pKey, signingKey: signingKey, peerID: peerID}, nil
+}
+
+// ID returns the ID of the bundle, the base58 encoding of its peer ID.
+func (kb *KeyBundle) ID() string {
+	return peer.IDB58Encode(kb.peerID)
+}
+
+// PeerID returns the ID identifying the node on the p2p network.
+func (kb *KeyBundle) PeerID() peer.ID {
+	return kb.peerID
+}
+
+// P2PKey returns the private key identifying the node on the p2p network.
+func (kb *KeyBundle) P2PKey() p2pcrypto.PrivKey {
+	return kb.p2pKey
+}
+
+// SigningAddress returns the address recovered from the signatures made by
+// the bundle, by which aggregators identify the node.
+func (kb *KeyBundle) SigningAddress() common.Address {
+	return crypto.PubkeyToAddress(kb.signingKey.PublicKey)
+}
+
+// Sign signs the keccak256 hash of msg with the signing key, returning a 65
+// byte [R || S || V] signature.
+func (kb *KeyBundle) Sign(msg []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(msg), kb.signingKey)
+}
+
+// RecoverSigner returns the signing address of the bundle which signed msg.
+func RecoverSigner(msg, signature []byte) (common.Address, error) {
+	pubKey, err := crypto.SigToPub(crypto.Keccak256(msg), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
@@ -0,0 +1,49 @@
+package ocrkey_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyBundle_EncryptDecryptRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	kb, err := ocrkey.NewKeyBundle()
+	require.NoError(t, err)
+	encrypted, err := kb.Encrypt("p4SsW0rD", ocrkey.FastScryptParams)
+	require.NoError(t, err)
+	assert.Equal(t, kb.ID(), encrypted.PeerID)
+	assert.Equal(t, kb.SigningAddress(), encrypted.SigningAddress)
+
+	_, err = encrypted.Decrypt("wrong password")
+	assert.Error(t, err)
+
+	decrypted, err := encrypted.Decrypt("p4SsW0rD")
+	require.NoError(t, err)
+	assert.Equal(t, kb.PeerID(), decrypted.PeerID())
+	assert.Equal(t, kb.SigningAddress(), decrypted.SigningAddress())
+	assert.True(t, decrypted.PeerID().MatchesPrivateKey(decrypted.P2PKey()))
+}
+
+func TestKeyBundle_SignRecover(t *testing.T) {
+	t.Parallel()
+
+	kb, err := ocrkey.NewKeyBundle()
+	require.NoError(t, err)
+	msg := []byte("observation")
+	signature, err := kb.Sign(msg)
+	require.NoError(t, err)
+	assert.Len(t, signature, 65)
+
+	signer, err := ocrkey.RecoverSigner(msg, signature)
+	require.NoError(t, err)
+	assert.Equal(t, kb.SigningAddress(), signer)
+
+	signer, err = ocrkey.RecoverSigner([]byte("tampered"), signature)
+	require.NoError(t, err)
+	assert.NotEqual(t, kb.SigningAddress(), signer)
+}
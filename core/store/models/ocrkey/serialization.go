@@ -0,0 +1,146 @@
+package ocrkey
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/pkg/errors"
+)
+
+// EncryptedKeyBundle is a KeyBundle as kept in the database, its private keys
+// encrypted with the node's password.
+type EncryptedKeyBundle struct {
+	ID             string         `json:"-" gorm:"primary_key"`
+	PeerID         string         `json:"peerId"`
+	SigningAddress common.Address `json:"signingAddress"`
+	EncryptedKeys  encryptedKeys  `json:"-" gorm:"type:jsonb"`
+	CreatedAt      time.Time      `json:"createdAt"`
+}
+
+// TableName is the table in which the bundles are kept.
+func (EncryptedKeyBundle) TableName() string {
+	return "encrypted_ocr_key_bundles"
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (e EncryptedKeyBundle) GetID() string {
+	return e.ID
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (e EncryptedKeyBundle) GetName() string {
+	return "off_chain_reporting_keys"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (e *EncryptedKeyBundle) SetID(value string) error {
+	e.ID = value
+	return nil
+}
+
+// encryptedKeys is the scrypt encrypted JSON of the private keys of a bundle.
+type encryptedKeys keystore.CryptoJSON
+
+// Value stores the encrypted keys as JSONB.
+func (ek encryptedKeys) Value() (driver.Value, error) {
+	return json.Marshal(ek)
+}
+
+// Scan reads the encrypted keys from JSONB.
+func (ek *encryptedKeys) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid Scan Source")
+	}
+	return json.Unmarshal(b, ek)
+}
+
+// plainKeys is the JSON which is encrypted to keep a bundle's private keys.
+type plainKeys struct {
+	P2PKey     []byte `json:"p2pKey"`
+	SigningKey []byte `json:"signingKey"`
+}
+
+// passwordPrefix is added to the beginning of the passwords encrypting
+// bundles, so that their keys can't casually be used as ethereum keys, and
+// vice-versa.
+const passwordPrefix = "don't mix off-chain reporting and Ethereum keys!"
+
+// ScryptParams are the parameters of the key derivation from the password.
+type ScryptParams struct{ N, P int }
+
+var defaultScryptParams = ScryptParams{
+	N: keystore.StandardScryptN, P: keystore.StandardScryptP}
+
+// FastScryptParams is for use in tests, where you don't want to wear out your
+// CPU with expensive key derivations. Do not use it in production, or your
+// encrypted keys will be easy to brute-force!
+var FastScryptParams = ScryptParams{N: 2, P: 1}
+
+// Encrypt returns the bundle with its private keys encrypted with auth.
+func (kb *KeyBundle) Encrypt(auth string, p ...ScryptParams) (*EncryptedKeyBundle, error) {
+	scryptParams := defaultScryptParams
+	switch len(p) {
+	case 0:
+	case 1:
+		scryptParams = p[0]
+	default:
+		return nil, fmt.Errorf("can take at most one set of ScryptParams")
+	}
+
+	p2pKey, err := p2pcrypto.MarshalPrivateKey(kb.p2pKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshaling p2p key")
+	}
+	plain, err := json.Marshal(plainKeys{
+		P2PKey:     p2pKey,
+		SigningKey: crypto.FromECDSA(kb.signingKey),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "while marshaling keys")
+	}
+	cryptoJSON, err := keystore.EncryptDataV3(plain, []byte(passwordPrefix+auth), scryptParams.N, scryptParams.P)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt off-chain reporting keys")
+	}
+	return &EncryptedKeyBundle{
+		ID:             kb.ID(),
+		PeerID:         kb.ID(),
+		SigningAddress: kb.SigningAddress(),
+		EncryptedKeys:  encryptedKeys(cryptoJSON),
+	}, nil
+}
+
+// Decrypt returns the KeyBundle in e, decrypted with auth.
+func (e *EncryptedKeyBundle) Decrypt(auth string) (*KeyBundle, error) {
+	plain, err := keystore.DecryptDataV3(keystore.CryptoJSON(e.EncryptedKeys), passwordPrefix+auth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decrypt off-chain reporting key bundle %s", e.ID)
+	}
+	var keys plainKeys
+	if err := json.Unmarshal(plain, &keys); err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling keys")
+	}
+	p2pKey, err := p2pcrypto.UnmarshalPrivateKey(keys.P2PKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling p2p key")
+	}
+	signingKey, err := crypto.ToECDSA(keys.SigningKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "while unmarshaling signing key")
+	}
+	kb, err := newKeyBundle(p2pKey, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	if kb.ID() != e.ID || kb.SigningAddress() != e.SigningAddress {
+		return nil, errors.Errorf("keys of off-chain reporting key bundle %s do not match its public keys", e.ID)
+	}
+	return kb, nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// MinRunAtEvery is the shortest period a runat initiator can recur at, so
+// that a recurring job cannot flood the node with runs.
+const MinRunAtEvery = time.Minute
+
+// RunAtFor returns when a runat initiator of the job runs next: at the time
+// recorded once a recurring one has run, or else at its time, or its delay
+// after the job was created.
+func (i Initiator) RunAtFor(job JobSpec) time.Time {
+	switch {
+	case i.NextRunAt.Valid:
+		return i.NextRunAt.Time
+	case i.Time.Valid:
+		return i.Time.Time
+	default:
+		return job.CreatedAt.Add(i.Delay.Duration())
+	}
+}
+
+// RunAfter returns when a recurring runat initiator of the job runs after
+// its run at t, skipping the runs due before now, and whether it does so
+// before the job's EndAt, which ends its recurrence.
+func (i Initiator) RunAfter(t, now time.Time, job JobSpec) (time.Time, bool) {
+	every := i.Every.Duration()
+	if every == 0 {
+		return time.Time{}, false
+	}
+	next := t.Add(every)
+	if next.Before(now) {
+		next = next.Add((now.Sub(next)/every + 1) * every)
+	}
+	return next, !job.Ended(next)
+}
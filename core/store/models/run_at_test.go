@@ -0,0 +1,58 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func TestInitiator_RunAtFor(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	job := models.JobSpec{CreatedAt: createdAt}
+
+	initr := models.Initiator{InitiatorParams: models.InitiatorParams{Delay: models.MustMakeDuration(2 * time.Hour)}}
+	assert.Equal(t, createdAt.Add(2*time.Hour), initr.RunAtFor(job))
+
+	initr = models.Initiator{InitiatorParams: models.InitiatorParams{Time: models.NewAnyTime(createdAt.Add(time.Hour))}}
+	assert.Equal(t, createdAt.Add(time.Hour), initr.RunAtFor(job))
+
+	initr.NextRunAt = null.TimeFrom(createdAt.Add(7 * time.Hour))
+	assert.Equal(t, createdAt.Add(7*time.Hour), initr.RunAtFor(job))
+}
+
+func TestInitiator_RunAfter(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour int) time.Time {
+		return time.Date(2020, 6, 1, hour, 0, 0, 0, time.UTC)
+	}
+	job := models.JobSpec{EndAt: null.TimeFrom(at(20))}
+	initr := models.Initiator{InitiatorParams: models.InitiatorParams{Every: models.MustMakeDuration(6 * time.Hour)}}
+
+	tests := []struct {
+		name   string
+		t, now time.Time
+		next   time.Time
+		recurs bool
+	}{
+		{"next", at(2), at(2), at(8), true},
+		{"skips missed runs", at(2), at(9), at(14), true},
+		{"past end at", at(16), at(16), at(22), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next, recurs := initr.RunAfter(test.t, test.now, job)
+			assert.Equal(t, test.next, next)
+			assert.Equal(t, test.recurs, recurs)
+		})
+	}
+
+	_, recurs := models.Initiator{}.RunAfter(at(2), at(2), job)
+	assert.False(t, recurs)
+}
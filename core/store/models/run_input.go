@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/tidwall/gjson"
@@ -11,6 +12,7 @@ type RunInput struct {
 	jobRunID ID
 	data     JSON
 	status   RunStatus
+	ctx      context.Context
 }
 
 // NewRunInput creates a new RunInput with arbitrary data
@@ -63,3 +65,19 @@ func (ri RunInput) Data() JSON {
 func (ri RunInput) JobRunID() *ID {
 	return &ri.jobRunID
 }
+
+// Context returns the context the task is performed in, which is done once
+// the execution of the run is interrupted, such as by its cancellation.
+// Tasks which block, on a request or otherwise, should stop once it is done.
+func (ri RunInput) Context() context.Context {
+	if ri.ctx == nil {
+		return context.Background()
+	}
+	return ri.ctx
+}
+
+// WithContext returns a copy of the RunInput performed in ctx.
+func (ri RunInput) WithContext(ctx context.Context) RunInput {
+	ri.ctx = ctx
+	return ri
+}
@@ -57,6 +57,12 @@ func NewRunOutputPendingConnectionWithData(data JSON) RunOutput {
 	return RunOutput{status: RunStatusPendingConnection, data: data}
 }
 
+// NewRunOutputPendingSleepWithData returns a new RunOutput that indicates the
+// task is sleeping, with data that needs to be fed in on next invocation
+func NewRunOutputPendingSleepWithData(data JSON) RunOutput {
+	return RunOutput{status: RunStatusPendingSleep, data: data}
+}
+
 // NewRunOutputInProgress returns a new RunOutput that indicates the
 // task is still in progress
 func NewRunOutputInProgress(data JSON) RunOutput {
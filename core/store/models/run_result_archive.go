@@ -0,0 +1,13 @@
+package models
+
+// ArchivedKey is the key of the stub which replaces the data of a run result
+// moved to an archive file:
+//
+//	{"archived": {"file": "run_results-0000000001-0000001000.json.gz"}}
+const ArchivedKey = "archived"
+
+// ArchivedRunResultData returns the stub the data of a run result archived in
+// file is replaced with.
+func ArchivedRunResultData(file string) (JSON, error) {
+	return JSON{}.Add(ArchivedKey, map[string]string{"file": file})
+}
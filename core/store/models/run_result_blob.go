@@ -0,0 +1,66 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TruncatedKey is the key of the marker which replaces the data of a run
+// result larger than RUN_RESULT_MAX_SIZE.
+const TruncatedKey = "truncated"
+
+// Truncation is the marker which replaces the data of a run result larger
+// than RUN_RESULT_MAX_SIZE, under TruncatedKey:
+//
+//	{"truncated": {"size": 2097152, "hash": "9f86d0...", "stored": true}}
+//
+// Hash is the SHA-256 of the data, which is kept in run_result_blobs if
+// Stored, and was dropped otherwise.
+type Truncation struct {
+	Size   int    `json:"size"`
+	Hash   string `json:"hash"`
+	Stored bool   `json:"stored"`
+}
+
+// RunResultBlob is the data of a run result too large to be kept in
+// run_results, stored once however many results have it.
+type RunResultBlob struct {
+	Hash      string `gorm:"primary_key"`
+	Data      string
+	Size      int
+	CreatedAt time.Time
+}
+
+// NewRunResultBlob returns the blob of data, keyed by its hash.
+func NewRunResultBlob(data JSON) RunResultBlob {
+	b := data.Bytes()
+	sum := sha256.Sum256(b)
+	return RunResultBlob{
+		Hash: hex.EncodeToString(sum[:]),
+		Data: string(b),
+		Size: len(b),
+	}
+}
+
+// Truncation returns the marker the data of the blob is replaced with.
+func (b RunResultBlob) Truncation(stored bool) (JSON, error) {
+	return JSON{}.Add(TruncatedKey, Truncation{Size: b.Size, Hash: b.Hash, Stored: stored})
+}
+
+// Truncation returns the marker which replaced the data of the result, if
+// it was larger than RUN_RESULT_MAX_SIZE.
+func (rr RunResult) Truncation() (Truncation, bool, error) {
+	var t Truncation
+	marker := rr.Data.Get(TruncatedKey)
+	if !marker.IsObject() || len(rr.Data.Map()) != 1 {
+		return t, false, nil
+	}
+	if err := json.Unmarshal([]byte(marker.Raw), &t); err != nil {
+		return t, false, errors.Wrap(err, "invalid truncation marker")
+	}
+	return t, t.Hash != "", nil
+}
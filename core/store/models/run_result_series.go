@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RunResultValue is the value of a field of the result of a run, one point
+// of the series of that field across the runs of a job.
+type RunResultValue struct {
+	RunID     *ID       `json:"runId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Value     JSON      `json:"value"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (v RunResultValue) GetID() string {
+	return v.RunID.String()
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (v RunResultValue) GetName() string {
+	return "run_result_values"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (v *RunResultValue) SetID(value string) error {
+	id, err := NewIDFromString(value)
+	if err != nil {
+		return err
+	}
+	v.RunID = id
+	return nil
+}
+
+// ParseRunResultPath returns the keys, within the data of a run result, of
+// a dot separated path to a field of the result, such as data.result or
+// data.prices.0. The leading data is optional.
+func ParseRunResultPath(path string) ([]string, error) {
+	keys := strings.Split(path, ".")
+	if keys[0] == "data" {
+		keys = keys[1:]
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("path %q must name a field of the result data", path)
+	}
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("path %q has an empty key", path)
+		}
+	}
+	return keys, nil
+}
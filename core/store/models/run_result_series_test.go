@@ -0,0 +1,39 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRunResultPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{"data.result", []string{"result"}, false},
+		{"result", []string{"result"}, false},
+		{"data.prices.0", []string{"prices", "0"}, false},
+		{"data", nil, true},
+		{"data..result", nil, true},
+		{"result.", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			keys, err := models.ParseRunResultPath(test.path)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, keys)
+		})
+	}
+}
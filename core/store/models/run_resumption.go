@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	null "gopkg.in/guregu/null.v3"
+)
+
+// RunResumption records a run pending on a bridge which was resumed with the
+// result of its task supplied by an operator, rather than by the bridge.
+type RunResumption struct {
+	ID           uint64      `json:"-" gorm:"primary_key"`
+	JobRunID     *ID         `json:"jobRunId"`
+	TaskRunID    *ID         `json:"taskRunId"`
+	Bridge       TaskType    `json:"bridge"`
+	Status       RunStatus   `json:"status"`
+	Data         JSON        `json:"data" gorm:"type:text"`
+	ErrorMessage null.String `json:"error"`
+	ResumedBy    string      `json:"resumedBy"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+// NewRunResumption returns the record of the task run of the run being
+// resumed with input by resumedBy.
+func NewRunResumption(run *JobRun, taskRun *TaskRun, input BridgeRunResult, resumedBy string) RunResumption {
+	return RunResumption{
+		JobRunID:     run.ID,
+		TaskRunID:    taskRun.ID,
+		Bridge:       taskRun.TaskSpec.Type,
+		Status:       input.Status,
+		Data:         input.Data,
+		ErrorMessage: input.ErrorMessage,
+		ResumedBy:    resumedBy,
+	}
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (r RunResumption) GetID() string {
+	return strconv.FormatUint(r.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (r RunResumption) GetName() string {
+	return "run_resumptions"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (r *RunResumption) SetID(value string) error {
+	id, err := strconv.ParseUint(value, 10, 64)
+	r.ID = id
+	return err
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+)
+
+// RunStatsPeriod is the length of the buckets the runs of jobs are rolled up
+// into.
+type RunStatsPeriod string
+
+const (
+	// RunStatsHourly rolls up the runs which finished in each hour.
+	RunStatsHourly RunStatsPeriod = "hour"
+	// RunStatsDaily rolls up the runs which finished in each day.
+	RunStatsDaily RunStatsPeriod = "day"
+)
+
+// RunStatsQuery selects the rollups of the given period whose buckets begin
+// at or after From, and before To, of the job with JobSpecID, or of every
+// job if it is nil.
+type RunStatsQuery struct {
+	Period    RunStatsPeriod
+	JobSpecID *ID
+	From      time.Time
+	To        time.Time
+}
+
+// RunStats rolls up the runs of a job which finished, completed, errored or
+// cancelled, in the bucket of the period beginning at Bucket. The latency of
+// a run is the time from its creation until it finished, and LinkEarned is
+// the LINK paid for the runs which completed.
+type RunStats struct {
+	JobSpecID             *ID            `json:"jobId"`
+	Period                RunStatsPeriod `json:"period"`
+	Bucket                time.Time      `json:"bucket"`
+	Runs                  int64          `json:"runs"`
+	Errored               int64          `json:"errored"`
+	ErrorRate             float64        `json:"errorRate"`
+	AverageLatencySeconds float64        `json:"averageLatencySeconds"`
+	LinkEarned            *assets.Link   `json:"linkEarned"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (s RunStats) GetID() string {
+	return s.JobSpecID.String() + "-" + s.Bucket.UTC().Format(time.RFC3339)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (s RunStats) GetName() string {
+	return "run_stats"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (s *RunStats) SetID(string) error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Secret is a value, such as the API key of a data provider, which job specs
+// reference by name in the params of their tasks as {{secret "name"}},
+// rather than embedding it. It is encrypted in the database, and injected
+// into the params of the tasks when they run, never being saved with the run.
+// Only the jobs of the namespace of a secret can reference it.
+type Secret struct {
+	ID        uint64          `json:"-" gorm:"primary_key"`
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace,omitempty" gorm:"not null"`
+	Value     EncryptedString `json:"-"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// SecretRequest is the name, namespace and value of a secret to set.
+type SecretRequest struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Value     string `json:"value"`
+}
+
+var secretNameFormat = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// NewSecret returns the secret the request sets, erroring if its name is not
+// made of letters, digits, underscores and hyphens, if its namespace is
+// invalid, or if it has no value.
+func NewSecret(request SecretRequest) (Secret, error) {
+	if !secretNameFormat.MatchString(request.Name) {
+		return Secret{}, fmt.Errorf("secret name %q must only contain letters, digits, underscores and hyphens", request.Name)
+	}
+	if err := ValidateNamespace(request.Namespace); err != nil {
+		return Secret{}, err
+	}
+	if request.Value == "" {
+		return Secret{}, fmt.Errorf("secret %s must have a value", request.Name)
+	}
+	return Secret{Name: request.Name, Namespace: request.Namespace, Value: EncryptedString(request.Value)}, nil
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (s Secret) GetID() string {
+	return s.Name
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (s Secret) GetName() string {
+	return "secrets"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (s *Secret) SetID(value string) error {
+	s.Name = value
+	return nil
+}
+
+var secretReference = regexp.MustCompile(`\{\{\s*secret\s+"([^"]*)"\s*\}\}`)
+
+// SecretReferences returns the names of the secrets the params reference.
+func SecretReferences(params JSON) []string {
+	var names []string
+	_, _ = ResolveSecrets(params, func(name string) (string, error) {
+		names = append(names, name)
+		return "", nil
+	})
+	return names
+}
+
+// ResolveSecrets returns the params with the secrets referenced in their
+// strings replaced by the values lookup returns for them, erroring if lookup
+// does.
+func ResolveSecrets(params JSON, lookup func(name string) (string, error)) (JSON, error) {
+	return resolveReferences(params, secretReference, func(name string) (string, error) {
+		secret, err := lookup(name)
+		return secret, errors.Wrapf(err, "secret %s", name)
+	})
+}
+
+// resolveReferences returns the params with the strings matching reference,
+// whose first group is the name referenced, replaced by the values lookup
+// returns for those names, erroring if lookup does.
+func resolveReferences(params JSON, reference *regexp.Regexp, lookup func(name string) (string, error)) (JSON, error) {
+	if !strings.Contains(params.String(), "{{") {
+		return params, nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(params.String())))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return JSON{}, errors.Wrap(err, "while resolving references")
+	}
+	resolved, err := resolveReferencesIn(value, reference, lookup)
+	if err != nil {
+		return JSON{}, err
+	}
+	b, err := json.Marshal(resolved)
+	if err != nil {
+		return JSON{}, errors.Wrap(err, "while resolving references")
+	}
+	return ParseJSON(b)
+}
+
+func resolveReferencesIn(value interface{}, reference *regexp.Regexp, lookup func(name string) (string, error)) (interface{}, error) {
+	var err error
+	switch v := value.(type) {
+	case string:
+		resolved := reference.ReplaceAllStringFunc(v, func(match string) string {
+			name := reference.FindStringSubmatch(match)[1]
+			resolved, lookupErr := lookup(name)
+			if lookupErr != nil && err == nil {
+				err = lookupErr
+			}
+			return resolved
+		})
+		return resolved, err
+	case map[string]interface{}:
+		for key, child := range v {
+			if v[key], err = resolveReferencesIn(child, reference, lookup); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if v[i], err = resolveReferencesIn(child, reference, lookup); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,61 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSecret(t *testing.T) {
+	t.Parallel()
+
+	secret, err := models.NewSecret(models.SecretRequest{Name: "coinapi_key", Value: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "coinapi_key", secret.Name)
+	assert.Equal(t, models.EncryptedString("abc"), secret.Value)
+
+	_, err = models.NewSecret(models.SecretRequest{Name: "coinapi key", Value: "abc"})
+	assert.Error(t, err)
+	_, err = models.NewSecret(models.SecretRequest{Name: "coinapi_key"})
+	assert.Error(t, err)
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Parallel()
+
+	secrets := map[string]string{"coinapi_key": `a"b`, "user": "oracle"}
+	lookup := func(name string) (string, error) {
+		value, ok := secrets[name]
+		if !ok {
+			return "", errors.New("not found")
+		}
+		return value, nil
+	}
+
+	params := cltest.JSONFromString(t, `{
+		"get": "https://example.com?key={{secret \"coinapi_key\"}}",
+		"headers": {"X-User": ["{{ secret \"user\" }}"]},
+		"times": 1000000000000000000000
+	}`)
+	resolved, err := models.ResolveSecrets(params, lookup)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"get": "https://example.com?key=a\"b",
+		"headers": {"X-User": ["oracle"]},
+		"times": 1000000000000000000000
+	}`, resolved.String())
+	assert.ElementsMatch(t, []string{"coinapi_key", "user"}, models.SecretReferences(params))
+
+	unchanged := cltest.JSONFromString(t, `{"get": "https://example.com"}`)
+	resolved, err = models.ResolveSecrets(unchanged, lookup)
+	require.NoError(t, err)
+	assert.Equal(t, unchanged, resolved)
+
+	_, err = models.ResolveSecrets(cltest.JSONFromString(t, `{"key": "{{secret \"missing\"}}"}`), lookup)
+	assert.EqualError(t, err, "secret missing: not found")
+}
@@ -58,6 +58,42 @@ type ServiceAgreement struct {
 	JobSpec       JobSpec     `gorm:"foreignkey:JobSpecID"`
 	JobSpecID     *ID         `json:"jobSpecId"`
 	UpdatedAt     time.Time   `json:"-"`
+	// TerminatedAt is when the agreement was terminated, and its job archived,
+	// by an operator or on expiring.
+	TerminatedAt     null.Time `json:"terminatedAt"`
+	ExpiryNotifiedAt null.Time `json:"-"`
+}
+
+// ServiceAgreementStatus is where a service agreement is in its lifecycle.
+type ServiceAgreementStatus string
+
+const (
+	// ServiceAgreementActive is the status of agreements which are in force.
+	ServiceAgreementActive = ServiceAgreementStatus("active")
+	// ServiceAgreementExpiring is the status of agreements in force which end
+	// within the expiry notice.
+	ServiceAgreementExpiring = ServiceAgreementStatus("expiring")
+	// ServiceAgreementExpired is the status of agreements which have ended.
+	ServiceAgreementExpired = ServiceAgreementStatus("expired")
+	// ServiceAgreementTerminated is the status of agreements an operator
+	// terminated before they ended.
+	ServiceAgreementTerminated = ServiceAgreementStatus("terminated")
+)
+
+// Status returns the status of the agreement at now, which is expiring if it
+// ends within notice.
+func (sa ServiceAgreement) Status(now time.Time, notice time.Duration) ServiceAgreementStatus {
+	endAt := sa.Encumbrance.EndAt
+	switch {
+	case sa.TerminatedAt.Valid && (!endAt.Valid || sa.TerminatedAt.Time.Before(endAt.Time)):
+		return ServiceAgreementTerminated
+	case endAt.Valid && !endAt.Time.After(now):
+		return ServiceAgreementExpired
+	case endAt.Valid && endAt.Time.Before(now.Add(notice)):
+		return ServiceAgreementExpiring
+	default:
+		return ServiceAgreementActive
+	}
 }
 
 // ServiceAgreementRequest encodes external ServiceAgreement json representation.
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// CancelOracleRequestLogTopic is the signature of the event a coordinator
+// emits when a request is cancelled, and its payment refunded to the
+// requester.
+var CancelOracleRequestLogTopic = utils.MustHash("CancelOracleRequest(bytes32)")
+
+// ServiceAgreementPaymentKind is whether a service agreement payment put
+// LINK in escrow, or took it out.
+type ServiceAgreementPaymentKind string
+
+const (
+	// ServiceAgreementDeposit is the payment escrowed by the coordinator when
+	// a request is made under the agreement.
+	ServiceAgreementDeposit = ServiceAgreementPaymentKind("deposit")
+	// ServiceAgreementRefund is the payment of a cancelled request, returned
+	// to its requester.
+	ServiceAgreementRefund = ServiceAgreementPaymentKind("refund")
+)
+
+// ServiceAgreementPayment is a movement of the LINK a coordinator holds in
+// escrow for a service agreement, decoded from the coordinator's events.
+type ServiceAgreementPayment struct {
+	ID                 uint64                      `json:"id" gorm:"primary_key"`
+	ServiceAgreementID string                      `json:"serviceAgreementId" gorm:"not null"`
+	Kind               ServiceAgreementPaymentKind `json:"kind" gorm:"not null"`
+	RequestID          common.Hash                 `json:"requestId" gorm:"not null"`
+	Amount             *assets.Link                `json:"amount" gorm:"type:numeric(78, 0);not null"`
+	TxHash             common.Hash                 `json:"txHash" gorm:"not null"`
+	BlockHash          common.Hash                 `json:"blockHash" gorm:"not null"`
+	BlockNumber        uint64                      `json:"blockNumber" gorm:"not null"`
+	LogIndex           uint                        `json:"logIndex" gorm:"not null"`
+	CreatedAt          time.Time                   `json:"createdAt"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (p ServiceAgreementPayment) GetID() string {
+	return strconv.FormatUint(p.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (p ServiceAgreementPayment) GetName() string {
+	return "service_agreement_payments"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (p *ServiceAgreementPayment) SetID(value string) error {
+	id, err := strconv.ParseUint(value, 10, 64)
+	p.ID = id
+	return err
+}
+
+// NewServiceAgreementPaymentFromLog decodes a coordinator's OracleRequest
+// event into the deposit of the request's payment, or its
+// CancelOracleRequest event into a refund. The service agreement and amount
+// of a refund are those of the request's deposit, which the event does not
+// give, and are left for the caller to fill in.
+func NewServiceAgreementPaymentFromLog(log eth.Log) (ServiceAgreementPayment, error) {
+	payment := ServiceAgreementPayment{
+		TxHash:      log.TxHash,
+		BlockHash:   log.BlockHash,
+		BlockNumber: log.BlockNumber,
+		LogIndex:    log.Index,
+	}
+	topic, err := log.GetTopic(0)
+	if err != nil {
+		return payment, err
+	}
+
+	switch topic {
+	case RunLogTopic20190207withoutIndexes:
+		saID, err := log.GetTopic(1)
+		if err != nil {
+			return payment, errors.Wrap(err, "missing service agreement ID")
+		}
+		requestID, err := parseRunLog20190207withoutIndexes{}.parseRequestID(log)
+		if err != nil {
+			return payment, errors.Wrap(err, "decoding request ID")
+		}
+		amount, err := contractPayment(log)
+		if err != nil {
+			return payment, errors.Wrap(err, "decoding payment")
+		}
+		payment.Kind = ServiceAgreementDeposit
+		payment.ServiceAgreementID = saID.Hex()
+		payment.RequestID = requestID
+		payment.Amount = amount
+	case CancelOracleRequestLogTopic:
+		requestID, err := log.Data.SafeByteSlice(0, idSize)
+		if err != nil {
+			return payment, errors.Wrap(err, "decoding request ID")
+		}
+		payment.Kind = ServiceAgreementRefund
+		payment.RequestID = common.BytesToHash(requestID)
+	default:
+		return payment, fmt.Errorf("log topic %s is not a coordinator payment event", topic.Hex())
+	}
+	return payment, nil
+}
+
+// ServiceAgreementReconciliation compares the LINK a service agreement's
+// requests were expected to pay, at the encumbrance's payment, with what the
+// coordinator received for them, and accounts for where the received LINK
+// went. Released is the deposits of the requests the node fulfilled, which
+// the coordinator pays out on fulfillment without emitting an event.
+type ServiceAgreementReconciliation struct {
+	ServiceAgreementID string       `json:"-"`
+	Requests           int64        `json:"requests"`
+	Underpaid          int64        `json:"underpaid"`
+	Expected           *assets.Link `json:"expected"`
+	Deposited          *assets.Link `json:"deposited"`
+	Refunded           *assets.Link `json:"refunded"`
+	Released           *assets.Link `json:"released"`
+	Outstanding        *assets.Link `json:"outstanding"`
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (r ServiceAgreementReconciliation) GetID() string {
+	return r.ServiceAgreementID
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (r ServiceAgreementReconciliation) GetName() string {
+	return "service_agreement_reconciliations"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (r *ServiceAgreementReconciliation) SetID(value string) error {
+	r.ServiceAgreementID = value
+	return nil
+}
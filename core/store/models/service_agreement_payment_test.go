@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServiceAgreementPaymentFromLog(t *testing.T) {
+	t.Parallel()
+
+	saID := common.HexToHash("0x5e5a")
+	requestID := common.HexToHash("0xfeed")
+	requester := common.HexToAddress("0xbeef")
+	amount := assets.NewLink(1000000000000000000)
+
+	var requestData []byte
+	requestData = append(requestData, requester.Hash().Bytes()...)
+	requestData = append(requestData, requestID.Bytes()...)
+	requestData = append(requestData, amount.ToHash().Bytes()...)
+
+	request := eth.Log{
+		Topics:      []common.Hash{models.RunLogTopic20190207withoutIndexes, saID},
+		Data:        requestData,
+		TxHash:      common.HexToHash("0x01"),
+		BlockHash:   common.HexToHash("0x02"),
+		BlockNumber: 10,
+		Index:       3,
+	}
+	deposit, err := models.NewServiceAgreementPaymentFromLog(request)
+	require.NoError(t, err)
+	assert.Equal(t, models.ServiceAgreementDeposit, deposit.Kind)
+	assert.Equal(t, saID.Hex(), deposit.ServiceAgreementID)
+	assert.Equal(t, requestID, deposit.RequestID)
+	assert.Equal(t, amount.String(), deposit.Amount.String())
+	assert.Equal(t, request.TxHash, deposit.TxHash)
+	assert.Equal(t, request.BlockHash, deposit.BlockHash)
+	assert.Equal(t, uint64(10), deposit.BlockNumber)
+	assert.Equal(t, uint(3), deposit.LogIndex)
+
+	cancel := eth.Log{
+		Topics: []common.Hash{models.CancelOracleRequestLogTopic},
+		Data:   requestID.Bytes(),
+	}
+	refund, err := models.NewServiceAgreementPaymentFromLog(cancel)
+	require.NoError(t, err)
+	assert.Equal(t, models.ServiceAgreementRefund, refund.Kind)
+	assert.Equal(t, requestID, refund.RequestID)
+	assert.Empty(t, refund.ServiceAgreementID)
+	assert.Nil(t, refund.Amount)
+
+	_, err = models.NewServiceAgreementPaymentFromLog(eth.Log{Topics: []common.Hash{models.RunLogTopic20190207withoutIndexes}})
+	assert.Error(t, err)
+	_, err = models.NewServiceAgreementPaymentFromLog(eth.Log{Topics: []common.Hash{models.CancelOracleRequestLogTopic}})
+	assert.Error(t, err)
+	_, err = models.NewServiceAgreementPaymentFromLog(eth.Log{Topics: []common.Hash{models.ServiceAgreementExecutionLogTopic}})
+	assert.Error(t, err)
+}
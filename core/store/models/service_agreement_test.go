@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/eth"
@@ -14,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
 )
 
 func TestNewUnsignedServiceAgreementFromRequest(t *testing.T) {
@@ -238,3 +240,35 @@ func TestServiceAgreementRequest_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAgreement_Status(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	notice := 24 * time.Hour
+
+	tests := []struct {
+		name         string
+		endAt        models.AnyTime
+		terminatedAt null.Time
+		want         models.ServiceAgreementStatus
+	}{
+		{"no end", models.AnyTime{}, null.Time{}, models.ServiceAgreementActive},
+		{"ends later", models.NewAnyTime(now.Add(48 * time.Hour)), null.Time{}, models.ServiceAgreementActive},
+		{"ends within notice", models.NewAnyTime(now.Add(time.Hour)), null.Time{}, models.ServiceAgreementExpiring},
+		{"ended", models.NewAnyTime(now.Add(-time.Hour)), null.Time{}, models.ServiceAgreementExpired},
+		{"expired and terminated", models.NewAnyTime(now.Add(-time.Hour)), null.TimeFrom(now), models.ServiceAgreementExpired},
+		{"terminated early", models.NewAnyTime(now.Add(time.Hour)), null.TimeFrom(now), models.ServiceAgreementTerminated},
+		{"terminated without end", models.AnyTime{}, null.TimeFrom(now), models.ServiceAgreementTerminated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sa := models.ServiceAgreement{
+				Encumbrance:  models.Encumbrance{EndAt: test.endAt},
+				TerminatedAt: test.terminatedAt,
+			}
+			assert.Equal(t, test.want, sa.Status(now, notice))
+		})
+	}
+}
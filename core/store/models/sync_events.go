@@ -9,4 +9,16 @@ type SyncEvent struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Body      string
+	// TxID is the ID of the database transaction which created the event,
+	// which the consumers follow the events in the order of.
+	TxID int64 `gorm:"column:txid;default:txid_current()"`
+}
+
+// SyncEventCursor is the last sync event consumed by a consumer of sync
+// events, such as the explorer or an exporter.
+type SyncEventCursor struct {
+	Consumer  string `gorm:"primary_key"`
+	LastTxID  int64  `gorm:"column:last_txid"`
+	LastID    uint
+	UpdatedAt time.Time
 }
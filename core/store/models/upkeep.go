@@ -0,0 +1,113 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// KeeperConfig is how a keeper initiator checks and performs its upkeep
+// contract, at the initiator's address.
+type KeeperConfig struct {
+	// CheckData is passed to the contract's checkUpkeep method.
+	CheckData hexutil.Bytes `json:"checkData,omitempty"`
+	// GasLimit is the gas limit of the performUpkeep transactions, or
+	// ETH_GAS_LIMIT_DEFAULT if zero.
+	GasLimit uint64 `json:"gasLimit,omitempty"`
+	// CooldownBlocks is the number of blocks after a performUpkeep
+	// transaction is sent before the contract is checked again.
+	CooldownBlocks uint64 `json:"cooldownBlocks,omitempty"`
+}
+
+// Value is defined so that we can store KeeperConfig as JSONB, as for
+// PollTimerConfig.
+func (kc KeeperConfig) Value() (driver.Value, error) {
+	return json.Marshal(kc)
+}
+
+// Scan is defined so that we can read KeeperConfig as JSONB, as for
+// PollTimerConfig.
+func (kc *KeeperConfig) Scan(value interface{}) error {
+	if value == nil {
+		*kc = KeeperConfig{}
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("Invalid Scan Source")
+	}
+	return json.Unmarshal(b, kc)
+}
+
+// Upkeep is the upkeep contract registered by a keeper initiator, with the
+// configuration it is checked and performed with, and an account of the
+// performUpkeep transactions sent for it.
+type Upkeep struct {
+	ID                 uint64         `json:"id" gorm:"primary_key"`
+	JobSpecID          *ID            `json:"jobId" gorm:"not null"`
+	InitiatorID        uint32         `json:"-" gorm:"not null"`
+	Address            common.Address `json:"address" gorm:"not null"`
+	CheckData          []byte         `json:"checkData" gorm:"not null"`
+	GasLimit           uint64         `json:"gasLimit" gorm:"not null"`
+	CooldownBlocks     uint64         `json:"cooldownBlocks" gorm:"not null"`
+	LastCheckedBlock   uint64         `json:"lastCheckedBlock" gorm:"not null"`
+	LastPerformedBlock uint64         `json:"lastPerformedBlock" gorm:"not null"`
+	PerformCount       uint64         `json:"performCount" gorm:"not null"`
+	CreatedAt          time.Time      `json:"createdAt"`
+	UpdatedAt          time.Time      `json:"updatedAt"`
+}
+
+// NewUpkeep returns the upkeep registered by a keeper initiator, performed
+// with defaultGasLimit if the initiator gives none.
+func NewUpkeep(initr Initiator, defaultGasLimit uint64) Upkeep {
+	gasLimit := initr.Keeper.GasLimit
+	if gasLimit == 0 {
+		gasLimit = defaultGasLimit
+	}
+	checkData := []byte(initr.Keeper.CheckData)
+	if checkData == nil {
+		checkData = []byte{}
+	}
+	return Upkeep{
+		JobSpecID:      initr.JobSpecID,
+		InitiatorID:    initr.ID,
+		Address:        initr.Address,
+		CheckData:      checkData,
+		GasLimit:       gasLimit,
+		CooldownBlocks: initr.Keeper.CooldownBlocks,
+	}
+}
+
+// CoolingDown returns true if a performUpkeep transaction was sent for the
+// upkeep too few blocks before head for it to be checked again.
+func (u Upkeep) CoolingDown(head uint64) bool {
+	return u.PerformCount > 0 && head < u.LastPerformedBlock+u.CooldownBlocks
+}
+
+// UpkeepPerform is a performUpkeep transaction sent for an upkeep, kept to
+// account for the gas spent keeping it. It is recorded before the transaction
+// is sent, so TxHash and GasPrice are nil until it is.
+type UpkeepPerform struct {
+	ID          uint64       `json:"id" gorm:"primary_key"`
+	UpkeepID    uint64       `json:"upkeepId" gorm:"not null"`
+	BlockNumber uint64       `json:"blockNumber" gorm:"not null"`
+	PerformData []byte       `json:"performData" gorm:"not null"`
+	TxHash      *common.Hash `json:"txHash"`
+	GasLimit    uint64       `json:"gasLimit" gorm:"not null"`
+	GasPrice    *utils.Big   `json:"gasPrice" gorm:"type:numeric"`
+	Confirmed   bool         `json:"confirmed" gorm:"not null"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+// SurrogateID is the surrogate ID of the performUpkeep transaction, by which
+// it is found when it was sent but not recorded.
+func (p UpkeepPerform) SurrogateID() string {
+	return fmt.Sprintf("upkeep-%d-%d", p.UpkeepID, p.BlockNumber)
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
+
+	"github.com/smartcontractkit/chainlink/core/eth"
+)
+
+// VRFRequestStatus is where a VRF request is in the queue of requests waiting
+// to be fulfilled.
+type VRFRequestStatus string
+
+const (
+	// VRFRequestPending is a request waiting for its log to be confirmed, or
+	// to be retried.
+	VRFRequestPending VRFRequestStatus = "pending"
+	// VRFRequestFulfilling is a request for which a run fulfilling it has
+	// been started.
+	VRFRequestFulfilling VRFRequestStatus = "fulfilling"
+	// VRFRequestFulfilled is a request whose fulfilling run has completed.
+	VRFRequestFulfilled VRFRequestStatus = "fulfilled"
+	// VRFRequestFailed is a request whose fulfilling run errored as many
+	// times as it may be attempted.
+	VRFRequestFailed VRFRequestStatus = "failed"
+	// VRFRequestRemoved is a request whose log was removed from the chain by a
+	// reorg before it was fulfilled.
+	VRFRequestRemoved VRFRequestStatus = "removed"
+)
+
+// VRFRequest is a RandomnessRequest log received by a randomnesslog
+// initiator, queued until it has enough confirmations to be fulfilled, and
+// kept to track the attempts to fulfill it.
+type VRFRequest struct {
+	ID            uint64           `json:"-" gorm:"primary_key"`
+	RequestID     common.Hash      `json:"requestId" gorm:"not null"`
+	JobSpecID     *ID              `json:"jobId" gorm:"not null"`
+	InitiatorID   uint32           `json:"-" gorm:"not null"`
+	BlockNumber   uint64           `json:"blockNumber" gorm:"not null"`
+	BlockHash     common.Hash      `json:"blockHash" gorm:"not null"`
+	TxHash        common.Hash      `json:"txHash" gorm:"not null"`
+	Log           JSON             `json:"-" gorm:"type:text;not null"`
+	Status        VRFRequestStatus `json:"status" gorm:"not null"`
+	Attempts      uint32           `json:"attempts" gorm:"not null"`
+	NextAttemptAt time.Time        `json:"nextAttemptAt" gorm:"not null"`
+	JobRunID      *ID              `json:"jobRunId"`
+	// FulfillmentTxHash is the transaction fulfilling the request as part of
+	// a batch, rather than by a run.
+	FulfillmentTxHash *common.Hash `json:"fulfillmentTxHash,omitempty"`
+	Error             null.String  `json:"error"`
+	CreatedAt         time.Time    `json:"createdAt"`
+	UpdatedAt         time.Time    `json:"updatedAt"`
+}
+
+// NewVRFRequest returns a pending request for the RandomnessRequest log with
+// the given request ID, received by the given initiator.
+func NewVRFRequest(requestID common.Hash, initr Initiator, log eth.Log) (*VRFRequest, error) {
+	b, err := json.Marshal(log)
+	if err != nil {
+		return nil, errors.Wrap(err, "while encoding RandomnessRequest log")
+	}
+	js, err := ParseJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	return &VRFRequest{
+		RequestID:   requestID,
+		JobSpecID:   initr.JobSpecID,
+		InitiatorID: initr.ID,
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+		Log:         js,
+		Status:      VRFRequestPending,
+	}, nil
+}
+
+// EthLog returns the RandomnessRequest log of the request.
+func (r VRFRequest) EthLog() (eth.Log, error) {
+	var log eth.Log
+	err := json.Unmarshal(r.Log.Bytes(), &log)
+	return log, errors.Wrap(err, "while decoding RandomnessRequest log")
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (r VRFRequest) GetID() string {
+	return strconv.FormatUint(r.ID, 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (r VRFRequest) GetName() string {
+	return "vrf_requests"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (r *VRFRequest) SetID(value string) error {
+	ID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	r.ID = ID
+	return nil
+}
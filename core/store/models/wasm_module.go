@@ -0,0 +1,71 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// WasmModule is a WebAssembly module uploaded for wasm tasks to transform
+// the data of their runs with.
+type WasmModule struct {
+	ID        uint64    `json:"-" gorm:"primary_key"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Size      int       `json:"size"`
+	Module    []byte    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WasmModuleRequest is the name and the base64 encoded binary of a
+// WebAssembly module to upload.
+type WasmModuleRequest struct {
+	Name   string `json:"name"`
+	Module []byte `json:"module"`
+}
+
+var (
+	wasmModuleNameFormat = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	wasmMagic            = []byte{0x00, 'a', 's', 'm'}
+)
+
+// NewWasmModule returns the module the request uploads, erroring if its name
+// is not made of letters, digits, underscores and hyphens, if it is not a
+// WebAssembly binary, or if it is larger than maxSize bytes.
+func NewWasmModule(request WasmModuleRequest, maxSize uint64) (WasmModule, error) {
+	if !wasmModuleNameFormat.MatchString(request.Name) {
+		return WasmModule{}, fmt.Errorf("module name %q must only contain letters, digits, underscores and hyphens", request.Name)
+	}
+	if !bytes.HasPrefix(request.Module, wasmMagic) {
+		return WasmModule{}, fmt.Errorf("module %s is not a WebAssembly binary", request.Name)
+	}
+	if uint64(len(request.Module)) > maxSize {
+		return WasmModule{}, fmt.Errorf("module %s is %d bytes, more than the %d of WASM_MAX_MODULE_SIZE", request.Name, len(request.Module), maxSize)
+	}
+	hash := sha256.Sum256(request.Module)
+	return WasmModule{
+		Name:   request.Name,
+		Hash:   hex.EncodeToString(hash[:]),
+		Size:   len(request.Module),
+		Module: request.Module,
+	}, nil
+}
+
+// GetID returns the ID of this structure for jsonapi serialization.
+func (m WasmModule) GetID() string {
+	return m.Name
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (m WasmModule) GetName() string {
+	return "wasm_modules"
+}
+
+// SetID is used to set the ID of this structure when deserializing from jsonapi documents.
+func (m *WasmModule) SetID(value string) error {
+	m.Name = value
+	return nil
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+)
+
+// OCRKeyStore keeps the bundles of keys the node uses for off-chain
+// reporting, which are encrypted in the DB and unlocked in memory with the
+// node's password.
+type OCRKeyStore struct {
+	lock  sync.RWMutex
+	keys  []*ocrkey.KeyBundle
+	store *Store
+}
+
+// NewOCRKeyStore returns an OCRKeyStore with no bundles unlocked.
+func NewOCRKeyStore(store *Store) *OCRKeyStore {
+	return &OCRKeyStore{store: store}
+}
+
+// ErrOCRKeyBundleNotUnlocked is returned when a bundle is asked for which has
+// not been unlocked.
+var ErrOCRKeyBundleNotUnlocked = errors.New("off-chain reporting key bundle is not unlocked")
+
+// CreateKey generates a bundle of keys, which is immediately unlocked in
+// memory, and saved in the DB encrypted with phrase. If p is given, its
+// parameters are used for key derivation from the phrase.
+func (ks *OCRKeyStore) CreateKey(phrase string, p ...ocrkey.ScryptParams) (*ocrkey.KeyBundle, error) {
+	kb, err := ocrkey.NewKeyBundle()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := kb.Encrypt(phrase, p...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt key bundle")
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	if err := ks.store.CreateEncryptedOCRKeyBundle(encrypted); err != nil {
+		return nil, errors.Wrap(err, "failed to save encrypted key bundle to db")
+	}
+	ks.keys = append(ks.keys, kb)
+	return kb, nil
+}
+
+// Unlock tries to unlock each bundle in the DB with the given phrase, and
+// returns the IDs of the bundles it manages to unlock, and any errors which
+// result.
+func (ks *OCRKeyStore) Unlock(phrase string) (unlocked []string, merr error) {
+	encrypted, err := ks.store.FindEncryptedOCRKeyBundles()
+	if err != nil {
+		return nil, errors.Wrap(err, "while retrieving off-chain reporting keys from db")
+	}
+
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	ks.keys = nil
+	for _, e := range encrypted {
+		kb, err := e.Decrypt(phrase)
+		if err != nil {
+			merr = multierr.Append(merr, err)
+			continue
+		}
+		ks.keys = append(ks.keys, kb)
+		unlocked = append(unlocked, kb.ID())
+	}
+	return unlocked, merr
+}
+
+// Get returns the unlocked bundle with the given ID.
+func (ks *OCRKeyStore) Get(id string) (*ocrkey.KeyBundle, error) {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	for _, kb := range ks.keys {
+		if kb.ID() == id {
+			return kb, nil
+		}
+	}
+	return nil, ErrOCRKeyBundleNotUnlocked
+}
+
+// Default returns the oldest unlocked bundle, which the node uses to take
+// part in off-chain reporting.
+func (ks *OCRKeyStore) Default() (*ocrkey.KeyBundle, error) {
+	ks.lock.RLock()
+	defer ks.lock.RUnlock()
+	if len(ks.keys) == 0 {
+		return nil, ErrOCRKeyBundleNotUnlocked
+	}
+	return ks.keys[0], nil
+}
+
+// Delete removes the bundle with the given ID from memory and the DB.
+func (ks *OCRKeyStore) Delete(id string) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	for i, kb := range ks.keys {
+		if kb.ID() == id {
+			ks.keys = append(ks.keys[:i], ks.keys[i+1:]...)
+			break
+		}
+	}
+	return ks.store.DeleteEncryptedOCRKeyBundle(id)
+}
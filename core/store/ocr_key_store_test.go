@@ -0,0 +1,54 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+)
+
+func TestOCRKeyStore_CreateUnlockDelete(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	ks := store.OCRKeyStore
+
+	_, err := ks.Default()
+	assert.Equal(t, strpkg.ErrOCRKeyBundleNotUnlocked, err)
+
+	first, err := ks.CreateKey(cltest.Password, ocrkey.FastScryptParams)
+	require.NoError(t, err)
+	second, err := ks.CreateKey(cltest.Password, ocrkey.FastScryptParams)
+	require.NoError(t, err)
+
+	restarted := strpkg.NewOCRKeyStore(store)
+	unlocked, err := restarted.Unlock("wrong password")
+	assert.Error(t, err)
+	assert.Empty(t, unlocked)
+
+	unlocked, err = restarted.Unlock(cltest.Password)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{first.ID(), second.ID()}, unlocked)
+
+	kb, err := restarted.Default()
+	require.NoError(t, err)
+	assert.Equal(t, first.ID(), kb.ID())
+	assert.Equal(t, first.SigningAddress(), kb.SigningAddress())
+
+	require.NoError(t, restarted.Delete(first.ID()))
+	_, err = restarted.Get(first.ID())
+	assert.Equal(t, strpkg.ErrOCRKeyBundleNotUnlocked, err)
+	kb, err = restarted.Default()
+	require.NoError(t, err)
+	assert.Equal(t, second.ID(), kb.ID())
+
+	bundles, err := store.FindEncryptedOCRKeyBundles()
+	require.NoError(t, err)
+	require.Len(t, bundles, 1)
+	assert.Equal(t, second.ID(), bundles[0].PeerID)
+}
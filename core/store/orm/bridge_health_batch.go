@@ -0,0 +1,103 @@
+package orm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"go.uber.org/multierr"
+)
+
+// bridgeHealthFlushInterval is how often the successful requests to bridges
+// batched by BatchBridgeSuccess are written to their health.
+const bridgeHealthFlushInterval = time.Second
+
+// bridgeSuccessBatch holds the successful requests to bridges which have not
+// been written to their health yet, so that a busy bridge does not have its
+// health row updated on every request.
+type bridgeSuccessBatch struct {
+	mu      sync.Mutex
+	pending map[models.TaskType]*bridgeSuccesses
+}
+
+// bridgeSuccesses are the successful requests to a bridge since the last
+// flush.
+type bridgeSuccesses struct {
+	count   int64
+	latency time.Duration
+	lastAt  time.Time
+}
+
+func newBridgeSuccessBatch() *bridgeSuccessBatch {
+	return &bridgeSuccessBatch{pending: make(map[models.TaskType]*bridgeSuccesses)}
+}
+
+func (b *bridgeSuccessBatch) add(name models.TaskType, latency time.Duration, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.pending[name]
+	if !ok {
+		s = &bridgeSuccesses{}
+		b.pending[name] = s
+	}
+	s.count++
+	s.latency += latency
+	s.lastAt = at
+}
+
+// take removes the successes of the bridge from the batch and returns them,
+// or nil if there are none.
+func (b *bridgeSuccessBatch) take(name models.TaskType) *bridgeSuccesses {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.pending[name]
+	delete(b.pending, name)
+	return s
+}
+
+// takeAll removes the successes of every bridge from the batch and returns
+// them.
+func (b *bridgeSuccessBatch) takeAll() map[models.TaskType]*bridgeSuccesses {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pending := b.pending
+	b.pending = make(map[models.TaskType]*bridgeSuccesses)
+	return pending
+}
+
+// BatchBridgeSuccess records a successful request to a bridge whose circuit
+// is closed and which has not failed since its last success. It is written
+// to the health of the bridge along with the other successes of the last
+// second, or before the next failure of the bridge.
+func (orm *ORM) BatchBridgeSuccess(name models.TaskType, latency time.Duration) {
+	orm.bridgeSuccesses.add(name, latency, time.Now())
+}
+
+// flushBridgeSuccesses writes the batched successes of every bridge to their
+// health.
+func (orm *ORM) flushBridgeSuccesses() error {
+	var merr error
+	for name, s := range orm.bridgeSuccesses.takeAll() {
+		merr = multierr.Append(merr, orm.writeBridgeSuccesses(name, *s))
+	}
+	return merr
+}
+
+// flushBridgeSuccessesEvery writes the batched successes of bridges every
+// bridgeHealthFlushInterval, and once more when the ORM is closed.
+func (orm *ORM) flushBridgeSuccessesEvery() {
+	defer orm.wg.Done()
+	ticker := time.NewTicker(bridgeHealthFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-orm.done:
+			logger.ErrorIf(orm.flushBridgeSuccesses(), "unable to record bridge health")
+			return
+		case <-ticker.C:
+			logger.ErrorIf(orm.flushBridgeSuccesses(), "unable to record bridge health")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package orm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChainProfile bundles the defaults of the configuration variables which
+// depend on the network the node is on, keyed by their fields in the
+// ConfigSchema. Variables set in the environment, or in the config file, take
+// precedence over those of the profile.
+type ChainProfile map[string]string
+
+// ChainProfiles are the profiles CHAIN_PROFILE can select. That of mainnet
+// is the defaults of the schema.
+var ChainProfiles = map[string]ChainProfile{
+	"mainnet": {
+		"ChainID":            "1",
+		"EthGasPriceDefault": "20000000000",
+		"EthGasBumpPercent":  "10",
+		"EthGasBumpWei":      "5000000000",
+		"EthMaxGasPriceWei":  "500000000000",
+		"FinalityDepth":      "50",
+		"EthBlockTime":       "15s",
+	},
+	"goerli": {
+		"ChainID":            "5",
+		"EthGasPriceDefault": "1000000000",
+		"EthGasBumpPercent":  "10",
+		"EthGasBumpWei":      "1000000000",
+		"EthMaxGasPriceWei":  "500000000000",
+		"FinalityDepth":      "50",
+		"EthBlockTime":       "15s",
+	},
+	"sepolia": {
+		"ChainID":            "11155111",
+		"EthGasPriceDefault": "1000000000",
+		"EthGasBumpPercent":  "10",
+		"EthGasBumpWei":      "1000000000",
+		"EthMaxGasPriceWei":  "500000000000",
+		"FinalityDepth":      "50",
+		"EthBlockTime":       "12s",
+	},
+	"polygon": {
+		"ChainID":            "137",
+		"EthGasPriceDefault": "30000000000",
+		"EthGasBumpPercent":  "20",
+		"EthGasBumpWei":      "20000000000",
+		"EthMaxGasPriceWei":  "5000000000000",
+		"FinalityDepth":      "500",
+		"EthBlockTime":       "2s",
+	},
+	"bsc": {
+		"ChainID":            "56",
+		"EthGasPriceDefault": "5000000000",
+		"EthGasBumpPercent":  "10",
+		"EthGasBumpWei":      "5000000000",
+		"EthMaxGasPriceWei":  "500000000000",
+		"FinalityDepth":      "50",
+		"EthBlockTime":       "3s",
+	},
+}
+
+// chainProfileFields are the configuration variables the profiles bundle.
+var chainProfileFields = []string{
+	"ChainID",
+	"EthBlockTime",
+	"EthGasBumpPercent",
+	"EthGasBumpWei",
+	"EthGasPriceDefault",
+	"EthMaxGasPriceWei",
+	"FinalityDepth",
+}
+
+// chainProfileNames returns the names of the profiles in order, for error
+// messages.
+func chainProfileNames() string {
+	var names []string
+	for name := range ChainProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// applyChainProfile makes the values of the profile selected by
+// CHAIN_PROFILE the defaults of the variables it bundles, in place of those
+// of the schema, which are kept if no profile, or an unknown one, is
+// selected.
+func (c Config) applyChainProfile() {
+	profile := ChainProfiles[c.ChainProfile()]
+	for _, field := range chainProfileFields {
+		value, _ := defaultValue(field)
+		if profiled, ok := profile[field]; ok {
+			value = profiled
+		}
+		c.viper.SetDefault(EnvVarName(field), value)
+	}
+}
+
+// validateChainProfile returns an error if CHAIN_PROFILE names no profile.
+func (c Config) validateChainProfile() error {
+	name := c.ChainProfile()
+	if _, ok := ChainProfiles[name]; name != "" && !ok {
+		return fmt.Errorf("CHAIN_PROFILE %s is not one of %s", name, chainProfileNames())
+	}
+	return nil
+}
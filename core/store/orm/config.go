@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"encoding"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/logger"
@@ -38,9 +40,10 @@ const readWritePerms = os.FileMode(0600)
 // If you add an entry here which does not contain sensitive information, you
 // should also update presenters.ConfigWhitelist and cmd_test.TestClient_RunNodeShowsEnv.
 type Config struct {
-	viper           *viper.Viper
-	SecretGenerator SecretGenerator
-	runtimeStore    *ORM
+	viper              *viper.Viper
+	SecretGenerator    SecretGenerator
+	MasterKeyGenerator SecretGenerator
+	runtimeStore       *ORM
 }
 
 var configFileNotFoundError = reflect.TypeOf(viper.ConfigFileNotFoundError{})
@@ -62,8 +65,9 @@ func newConfigWithViper(v *viper.Viper) *Config {
 	}
 
 	config := &Config{
-		viper:           v,
-		SecretGenerator: filePersistedSecretGenerator{},
+		viper:              v,
+		SecretGenerator:    filePersistedSecretGenerator{filename: "secret"},
+		MasterKeyGenerator: filePersistedSecretGenerator{filename: masterKeyFilename},
 	}
 
 	if err := os.MkdirAll(config.RootDir(), os.FileMode(0700)); err != nil {
@@ -76,6 +80,7 @@ func newConfigWithViper(v *viper.Viper) *Config {
 	if err != nil && reflect.TypeOf(err) != configFileNotFoundError {
 		logger.Warnf("Unable to load config file: %v\n", err)
 	}
+	config.applyChainProfile()
 
 	return config
 }
@@ -91,7 +96,18 @@ func (c *Config) Validate() error {
 			ethCore.DefaultTxPoolConfig.PriceBump,
 		)
 	}
-	return nil
+	if depth := c.FinalityDepth(); depth > 0 {
+		if uint64(c.MinIncomingConfirmations()) > depth {
+			return fmt.Errorf("MIN_INCOMING_CONFIRMATIONS of %v is more than FINALITY_DEPTH of %v", c.MinIncomingConfirmations(), depth)
+		}
+		if c.MinOutgoingConfirmations() > depth {
+			return fmt.Errorf("MIN_OUTGOING_CONFIRMATIONS of %v is more than FINALITY_DEPTH of %v", c.MinOutgoingConfirmations(), depth)
+		}
+	}
+	if blockTime := c.EthBlockTime().Duration(); blockTime > 0 && c.HealthMaxHeadAge().Duration() < 3*blockTime {
+		logger.Warnf("HEALTH_MAX_HEAD_AGE of %v is less than three times ETH_BLOCK_TIME of %v, the node may report itself unhealthy between blocks", c.HealthMaxHeadAge(), c.EthBlockTime())
+	}
+	return c.validateChainProfile()
 }
 
 // SetRuntimeStore tells the configuration system to use a store for retrieving
@@ -100,6 +116,62 @@ func (c *Config) SetRuntimeStore(orm *ORM) {
 	c.runtimeStore = orm
 }
 
+// runtimeConfigurable are the configuration variables which can be changed
+// while the node is running. Their values in the runtime store take
+// precedence over the environment.
+var runtimeConfigurable = map[string]bool{
+	"EthGasPriceDefault":              true,
+	"EthMaxGasPriceWei":               true,
+	"ExternalInitiatorHealthInterval": true,
+	"LogLevel":                        true,
+	"LogModuleLevels":                 true,
+	"SessionTimeout":                  true,
+	"StuckRunCheckInterval":           true,
+}
+
+// ChangeRuntimeValue saves a value for a configuration variable which can be
+// changed while the node is running, and records the change as made by
+// changedBy.
+func (c Config) ChangeRuntimeValue(name string, value encoding.TextMarshaler, changedBy string) (models.ConfigurationChange, error) {
+	change := models.ConfigurationChange{Name: EnvVarName(name), ChangedBy: changedBy}
+	if c.runtimeStore == nil {
+		return change, errors.New("No runtime store installed")
+	}
+	if !runtimeConfigurable[name] {
+		return change, fmt.Errorf("%s cannot be changed while the node is running", EnvVarName(name))
+	}
+	to, err := value.MarshalText()
+	if err != nil {
+		return change, err
+	}
+	change.From = fmt.Sprint(reflect.ValueOf(c).MethodByName(name).Call(nil)[0].Interface())
+	change.To = string(to)
+	return change, c.runtimeStore.ChangeConfigValue(name, value, &change)
+}
+
+// runtimeValue returns the value of a configuration variable in the runtime
+// store, if it can be changed while the node is running and has been.
+func (c Config) runtimeValue(name string) (string, bool) {
+	if c.runtimeStore == nil || !runtimeConfigurable[name] {
+		return "", false
+	}
+	var value runtimeConfigValue
+	if err := c.runtimeStore.GetConfigValue(name, &value); err != nil {
+		if errors.Cause(err) != ErrorNotFound {
+			logger.Warnw(fmt.Sprintf("Error while trying to fetch %s.", name), "error", err)
+		}
+		return "", false
+	}
+	return string(value), true
+}
+
+type runtimeConfigValue string
+
+func (v *runtimeConfigValue) UnmarshalText(text []byte) error {
+	*v = runtimeConfigValue(text)
+	return nil
+}
+
 // Set a specific configuration variable
 func (c Config) Set(name string, value interface{}) {
 	schemaT := reflect.TypeOf(ConfigSchema{})
@@ -108,17 +180,79 @@ func (c Config) Set(name string, value interface{}) {
 		envName := item.Tag.Get("env")
 		if envName == name {
 			c.viper.Set(name, value)
+			if name == EnvVarName("ChainProfile") {
+				c.applyChainProfile()
+			}
 			return
 		}
 	}
 	logger.Panicf("No configuration parameter for %s", name)
 }
 
+// AdapterPluginsDir is the directory the executables of adapter plugins are
+// loaded from when the node starts, each registering a task type. Plugins
+// are disabled if it is empty.
+func (c Config) AdapterPluginsDir() string {
+	return c.viper.GetString(EnvVarName("AdapterPluginsDir"))
+}
+
+// AdapterPluginTimeout is how long an adapter plugin has to describe itself
+// or perform a task before it is killed.
+func (c Config) AdapterPluginTimeout() models.Duration {
+	return c.getDuration("AdapterPluginTimeout")
+}
+
+// AccessViolationRetention is how long the access violations last seen that
+// long ago are kept in the audit log. Zero keeps them forever.
+func (c Config) AccessViolationRetention() models.Duration {
+	return c.getDuration("AccessViolationRetention")
+}
+
 // AllowOrigins returns the CORS hosts used by the frontend.
 func (c Config) AllowOrigins() string {
 	return c.viper.GetString(EnvVarName("AllowOrigins"))
 }
 
+// APIAllowedCIDRs lists the only networks, and addresses, the operator API
+// accepts requests from. The API accepts requests from anywhere if it is
+// empty.
+func (c Config) APIAllowedCIDRs() []string {
+	return c.getStringList("APIAllowedCIDRs")
+}
+
+// BridgeCacheSize is the maximum number of bridge responses held by the
+// in-memory bridge response cache.
+func (c Config) BridgeCacheSize() uint {
+	return c.viper.GetUint(EnvVarName("BridgeCacheSize"))
+}
+
+// BridgeCacheStore is where the responses of bridges with a cache TTL are
+// cached.
+func (c Config) BridgeCacheStore() BridgeCacheStore {
+	return c.getWithFallback("BridgeCacheStore", parseBridgeCacheStore).(BridgeCacheStore)
+}
+
+// BridgeCircuitBreakerThreshold is the number of requests a bridge must fail
+// in a row for its circuit to open, failing runs without calling it. 0
+// disables the circuit breaker.
+func (c Config) BridgeCircuitBreakerThreshold() uint {
+	return c.viper.GetUint(EnvVarName("BridgeCircuitBreakerThreshold"))
+}
+
+// BridgeCallbackTimeout is how long a bridge has to call back a run pending on
+// it at the BRIDGE_RESPONSE_URL it was given, with the single-use token it
+// was given, before the run errors. Zero disables the timeout, the tokens
+// never expiring.
+func (c Config) BridgeCallbackTimeout() models.Duration {
+	return c.getDuration("BridgeCallbackTimeout")
+}
+
+// BridgeCircuitBreakerTimeout is how long the circuit of a bridge stays open
+// before a request is let through to probe whether it has recovered.
+func (c Config) BridgeCircuitBreakerTimeout() models.Duration {
+	return c.getDuration("BridgeCircuitBreakerTimeout")
+}
+
 // BridgeResponseURL represents the URL for bridges to send a response to.
 func (c Config) BridgeResponseURL() *url.URL {
 	return c.getWithFallback("BridgeResponseURL", parseURL).(*url.URL)
@@ -129,12 +263,59 @@ func (c Config) ChainID() *big.Int {
 	return c.getWithFallback("ChainID", parseBigInt).(*big.Int)
 }
 
+// ChainProfile is the name of the profile of the network the node is on,
+// which supplies the defaults of its chain ID, gas prices, FINALITY_DEPTH and
+// ETH_BLOCK_TIME. The defaults of the schema are used if it is empty.
+func (c Config) ChainProfile() string {
+	return strings.ToLower(strings.TrimSpace(c.viper.GetString(EnvVarName("ChainProfile"))))
+}
+
 // ClientNodeURL is the URL of the Ethereum node this Chainlink node should connect to.
 func (c Config) ClientNodeURL() string {
 	return c.viper.GetString(EnvVarName("ClientNodeURL"))
 }
 
+// ContentSecurityPolicy is the Content-Security-Policy header the web server
+// responds with, such as a frame-ancestors directive letting dashboards
+// embed the operator UI. No header is sent if it is empty.
+func (c Config) ContentSecurityPolicy() string {
+	return c.viper.GetString(EnvVarName("ContentSecurityPolicy"))
+}
+
+// CronCatchUp determines what happens to cron initiator runs that should
+// have fired while the node was down: they are ignored, backfilled, or
+// recorded as skipped.
+func (c Config) CronCatchUp() CronCatchUpMode {
+	return c.getWithFallback("CronCatchUp", parseCronCatchUpMode).(CronCatchUpMode)
+}
+
+// CronCatchUpMaxRuns is the maximum number of missed runs per cron initiator
+// that will be backfilled or recorded as skipped on startup.
+func (c Config) CronCatchUpMaxRuns() uint {
+	return c.viper.GetUint(EnvVarName("CronCatchUpMaxRuns"))
+}
+
+// getStringList returns the comma separated entries of a setting, ignoring
+// blank entries.
+func (c Config) getStringList(field string) []string {
+	list := []string{}
+	for _, entry := range strings.Split(c.viper.GetString(EnvVarName(field)), ",") {
+		if trimmed := strings.TrimSpace(entry); trimmed != "" {
+			list = append(list, trimmed)
+		}
+	}
+	return list
+}
+
 func (c Config) getDuration(s string) models.Duration {
+	if str, ok := c.runtimeValue(s); ok {
+		var d models.Duration
+		err := d.UnmarshalText([]byte(str))
+		if err == nil {
+			return d
+		}
+		logger.Errorw(fmt.Sprintf("Invalid runtime value for %s, falling back to the environment.", s), "value", str, "error", err)
+	}
 	rv, err := models.MakeDuration(c.viper.GetDuration(EnvVarName(s)))
 	if err != nil {
 		panic(errors.Wrapf(err, "bad duration for config value %s: %s", s, rv))
@@ -142,6 +323,72 @@ func (c Config) getDuration(s string) models.Duration {
 	return rv
 }
 
+// DatabaseAutoMigrate makes the node run the pending migrations of the
+// database when it starts. Otherwise they are run with `chainlink node
+// migrate`, and the node refuses to start while any are pending.
+func (c Config) DatabaseAutoMigrate() bool {
+	return c.viper.GetBool(EnvVarName("DatabaseAutoMigrate"))
+}
+
+// DatabasePartitionRetention is how long after the end of their month the
+// partitions of job_runs and tx_attempts are dropped, removing the runs and
+// attempts in them. At 0, the default, partitions are kept forever.
+func (c Config) DatabasePartitionRetention() models.Duration {
+	return c.getDuration("DatabasePartitionRetention")
+}
+
+// DatabasePartitionsAhead is the number of months ahead the partitions of
+// job_runs and tx_attempts are created.
+func (c Config) DatabasePartitionsAhead() uint {
+	return c.viper.GetUint(EnvVarName("DatabasePartitionsAhead"))
+}
+
+// DatabaseConnectTimeout is how long the node keeps trying to reach the
+// database, with a backoff, when starting or after losing its connection. At
+// 0, the default, it gives up at once.
+func (c Config) DatabaseConnectTimeout() models.Duration {
+	return c.getDuration("DatabaseConnectTimeout")
+}
+
+// DatabaseLeaseDuration is how long the lease taken with the lease locking
+// strategy lasts unless renewed. The node renews it three times as often.
+func (c Config) DatabaseLeaseDuration() models.Duration {
+	return c.getDuration("DatabaseLeaseDuration")
+}
+
+// DatabaseLockingStrategy is how the node keeps other nodes off the
+// database, with a Postgres advisory lock by default, or a lease on the
+// leases table, which shows which node holds it.
+func (c Config) DatabaseLockingStrategy() DatabaseLockingStrategy {
+	return c.getWithFallback("DatabaseLockingStrategy", parseDatabaseLockingStrategy).(DatabaseLockingStrategy)
+}
+
+// DatabaseOptions returns the ways the node connects to and locks the
+// database.
+func (c Config) DatabaseOptions() DatabaseOptions {
+	return DatabaseOptions{
+		LockingStrategy:     c.DatabaseLockingStrategy(),
+		LeaseDuration:       c.DatabaseLeaseDuration().Duration(),
+		PgBouncerCompatible: c.DatabasePgBouncerCompatibility(),
+	}
+}
+
+// DatabasePgBouncerCompatibility has the node work with a database behind a
+// PgBouncer pooling transactions. Rather than an advisory lock, which belongs
+// to a session, the node takes a lease whatever DATABASE_LOCKING_STRATEGY is.
+// It does not set the time zone of its sessions either, so the time zone of
+// the database should be UTC.
+func (c Config) DatabasePgBouncerCompatibility() bool {
+	return c.viper.GetBool(EnvVarName("DatabasePgBouncerCompatibility"))
+}
+
+// DatabaseSlowQueryThreshold is how long a statement takes before it is
+// logged and recorded, without its parameters, in the slow_queries table. At
+// 0, the default, statements are not timed.
+func (c Config) DatabaseSlowQueryThreshold() models.Duration {
+	return c.getDuration("DatabaseSlowQueryThreshold")
+}
+
 // DatabaseTimeout represents how long to tolerate non response from the DB.
 func (c Config) DatabaseTimeout() models.Duration {
 	return c.getDuration("DatabaseTimeout")
@@ -178,6 +425,32 @@ func (c Config) EnableExperimentalAdapters() bool {
 	return c.viper.GetBool(EnvVarName("EnableExperimentalAdapters"))
 }
 
+// ExternalInitiatorAllowedCIDRs lists the only networks, and addresses,
+// external initiators may make requests from, instead of those of
+// API_ALLOWED_CIDRS. They may make requests from anywhere if it is empty.
+func (c Config) ExternalInitiatorAllowedCIDRs() []string {
+	return c.getStringList("ExternalInitiatorAllowedCIDRs")
+}
+
+// ExternalInitiatorHealthInterval is how often the node checks the health of
+// the external initiators with a URL. Zero disables the checks.
+func (c Config) ExternalInitiatorHealthInterval() models.Duration {
+	return c.getDuration("ExternalInitiatorHealthInterval")
+}
+
+// ExternalInitiatorMaxAttempts is how many times the node tries to deliver a
+// notification to an external initiator before dead-lettering it.
+func (c Config) ExternalInitiatorMaxAttempts() uint {
+	return c.viper.GetUint(EnvVarName("ExternalInitiatorMaxAttempts"))
+}
+
+// ExternalInitiatorRetryBackoff is how long the node waits before retrying a
+// notification it failed to deliver to an external initiator the first time,
+// doubling with each further failure.
+func (c Config) ExternalInitiatorRetryBackoff() models.Duration {
+	return c.getDuration("ExternalInitiatorRetryBackoff")
+}
+
 // FeatureExternalInitiators enables the External Initiator feature.
 func (c Config) FeatureExternalInitiators() bool {
 	return c.viper.GetBool(EnvVarName("FeatureExternalInitiators"))
@@ -188,6 +461,45 @@ func (c Config) FeatureFluxMonitor() bool {
 	return c.viper.GetBool(EnvVarName("FeatureFluxMonitor"))
 }
 
+// FeatureOffchainReporting enables the off-chain reporting feature.
+func (c Config) FeatureOffchainReporting() bool {
+	return c.viper.GetBool(EnvVarName("FeatureOffchainReporting"))
+}
+
+// FinalityDepth is how many blocks deep a block is final, past any reorg.
+// The confirmations runs wait for, incoming and outgoing, may not be more,
+// the logs of flux monitors and run logs are backfilled from it, and the
+// heads saved go back at least that far.
+func (c Config) FinalityDepth() uint64 {
+	return c.viper.GetUint64(EnvVarName("FinalityDepth"))
+}
+
+// FluxMonitorFeedQuarantinePeriod is how long a flux monitor feed stays in
+// quarantine before it is polled again to probe whether it has recovered.
+func (c Config) FluxMonitorFeedQuarantinePeriod() models.Duration {
+	return c.getDuration("FluxMonitorFeedQuarantinePeriod")
+}
+
+// FluxMonitorFeedQuarantineThreshold is the number of polls a flux monitor
+// feed must fail in a row for it to be quarantined, leaving it out of rounds.
+// 0 disables quarantine.
+func (c Config) FluxMonitorFeedQuarantineThreshold() uint {
+	return c.viper.GetUint(EnvVarName("FluxMonitorFeedQuarantineThreshold"))
+}
+
+// MaxConcurrentRuns is the maximum number of runs the node will execute at
+// once, across all jobs. Runs created beyond this limit are parked until a
+// slot frees up. Zero means no limit.
+func (c Config) MaxConcurrentRuns() uint {
+	return c.viper.GetUint(EnvVarName("MaxConcurrentRuns"))
+}
+
+// MetricsAllowedCIDRs lists the only networks, and addresses, the metrics
+// are served to. They are served to anywhere if it is empty.
+func (c Config) MetricsAllowedCIDRs() []string {
+	return c.getStringList("MetricsAllowedCIDRs")
+}
+
 // MaxRPCCallsPerSecond returns the rate at which RPC calls can be fired
 func (c Config) MaxRPCCallsPerSecond() uint64 {
 	return c.viper.GetUint64(EnvVarName("MaxRPCCallsPerSecond"))
@@ -205,6 +517,11 @@ func (c Config) MinimumServiceDuration() models.Duration {
 	return c.getDuration("MinimumServiceDuration")
 }
 
+// EthBlockTime is the expected time between the blocks of the network.
+func (c Config) EthBlockTime() models.Duration {
+	return c.getDuration("EthBlockTime")
+}
+
 // EthGasBumpThreshold is the number of blocks to wait for confirmations before bumping gas again
 func (c Config) EthGasBumpThreshold() uint64 {
 	return c.viper.GetUint64(EnvVarName("EthGasBumpThreshold"))
@@ -234,14 +551,6 @@ func (c Config) EthGasLimitDefault() uint64 {
 
 // EthGasPriceDefault is the starting gas price for every transaction
 func (c Config) EthGasPriceDefault() *big.Int {
-	if c.runtimeStore != nil {
-		var value big.Int
-		if err := c.runtimeStore.GetConfigValue("EthGasPriceDefault", &value); err != nil && errors.Cause(err) != ErrorNotFound {
-			logger.Warnw("Error while trying to fetch EthGasPriceDefault.", "error", err)
-		} else if err == nil {
-			return &value
-		}
-	}
 	return c.getWithFallback("EthGasPriceDefault", parseBigInt).(*big.Int)
 }
 
@@ -253,6 +562,27 @@ func (c Config) SetEthGasPriceDefault(value *big.Int) error {
 	return c.runtimeStore.SetConfigValue("EthGasPriceDefault", value)
 }
 
+// EthQuorumDepth is how many blocks behind every head the Ethereum node and
+// the provider at ETH_QUORUM_URL must agree on the chain.
+func (c Config) EthQuorumDepth() uint {
+	return c.viper.GetUint(EnvVarName("EthQuorumDepth"))
+}
+
+// EthQuorumDivergenceThreshold is how many heads in a row the Ethereum node
+// and the provider at ETH_QUORUM_URL must disagree on before the broadcasting
+// of transactions is paused, so that one of them lagging the other for a
+// head does not.
+func (c Config) EthQuorumDivergenceThreshold() uint {
+	return c.viper.GetUint(EnvVarName("EthQuorumDivergenceThreshold"))
+}
+
+// EthQuorumURL is the websocket URL of a second Ethereum provider the chain
+// of the Ethereum node is cross-checked with. Transactions are not broadcast
+// while the two disagree. Nothing is cross-checked if it is empty.
+func (c Config) EthQuorumURL() string {
+	return c.viper.GetString(EnvVarName("EthQuorumURL"))
+}
+
 // EthereumURL represents the URL of the Ethereum node to connect Chainlink to.
 func (c Config) EthereumURL() string {
 	return c.viper.GetString(EnvVarName("EthereumURL"))
@@ -293,11 +623,120 @@ func (c Config) GasUpdaterEnabled() bool {
 	return c.viper.GetBool(EnvVarName("GasUpdaterEnabled"))
 }
 
+// HealthMaxHeadAge is how long the node can go without receiving a new head
+// before it reports itself as not ready. Zero disables the check.
+func (c Config) HealthMaxHeadAge() models.Duration {
+	return c.getDuration("HealthMaxHeadAge")
+}
+
+// HSTSIncludeSubdomains extends the Strict-Transport-Security header to the
+// subdomains of the node's host.
+func (c Config) HSTSIncludeSubdomains() bool {
+	return c.viper.GetBool(EnvVarName("HSTSIncludeSubdomains"))
+}
+
+// HSTSMaxAge is how long browsers are told by the Strict-Transport-Security
+// header to only connect to the node over TLS. Zero sends no header.
+func (c Config) HSTSMaxAge() models.Duration {
+	return c.getDuration("HSTSMaxAge")
+}
+
+// HSTSPreload asks, in the Strict-Transport-Security header, for the node's
+// host to be included in the HSTS preload lists of browsers.
+func (c Config) HSTSPreload() bool {
+	return c.viper.GetBool(EnvVarName("HSTSPreload"))
+}
+
 // JSONConsole enables the JSON console.
 func (c Config) JSONConsole() bool {
 	return c.viper.GetBool(EnvVarName("JSONConsole"))
 }
 
+// HTTPAllowedHosts lists the only hostnames and networks the http adapters
+// may connect to. Any host is allowed if the list is empty. Allowed networks
+// may be local or private.
+func (c Config) HTTPAllowedHosts() []string {
+	return c.getStringList("HTTPAllowedHosts")
+}
+
+// HTTPDeniedHosts lists the hostnames and networks the http adapters may not
+// connect to.
+func (c Config) HTTPDeniedHosts() []string {
+	return c.getStringList("HTTPDeniedHosts")
+}
+
+// HTTPMaxRedirects is the number of redirects the http adapters follow before
+// failing the request.
+func (c Config) HTTPMaxRedirects() uint {
+	return c.viper.GetUint(EnvVarName("HTTPMaxRedirects"))
+}
+
+// IncomingConfirmationsTimeout is how long a run may wait for the incoming
+// confirmations of the log initiating it before it is cancelled, as the log
+// may have been reorged away for good. Zero lets runs wait forever.
+func (c Config) IncomingConfirmationsTimeout() models.Duration {
+	return c.getDuration("IncomingConfirmationsTimeout")
+}
+
+// KafkaBrokers returns the addresses of the kafka brokers that kafka
+// initiators consume from and the kafkapublish adapter writes to.
+func (c Config) KafkaBrokers() []string {
+	return c.getStringList("KafkaBrokers")
+}
+
+// KeyFundingAddress is the address of the node's key which sends ETH to its
+// other keys when their balances drop below KeyFundingThresholdWei. The
+// other keys are not funded if it is not set.
+func (c Config) KeyFundingAddress() *common.Address {
+	if c.viper.GetString(EnvVarName("KeyFundingAddress")) == "" {
+		return nil
+	}
+	return c.getWithFallback("KeyFundingAddress", parseAddress).(*common.Address)
+}
+
+// KeyFundingAmountWei is how much ETH the funding key sends to a key whose
+// balance dropped below KeyFundingThresholdWei.
+func (c Config) KeyFundingAmountWei() *big.Int {
+	return c.getWithFallback("KeyFundingAmountWei", parseBigInt).(*big.Int)
+}
+
+// KeyFundingCheckInterval is how often the balances of the node's keys are
+// checked against KeyFundingThresholdWei.
+func (c Config) KeyFundingCheckInterval() models.Duration {
+	return c.getDuration("KeyFundingCheckInterval")
+}
+
+// KeyFundingCooldown is how long after a key is funded before it can be
+// funded again, giving the funding transaction time to confirm.
+func (c Config) KeyFundingCooldown() models.Duration {
+	return c.getDuration("KeyFundingCooldown")
+}
+
+// KeyFundingMaxPerDayWei is the most ETH the funding key sends to the node's
+// other keys in any 24 hours.
+func (c Config) KeyFundingMaxPerDayWei() *big.Int {
+	return c.getWithFallback("KeyFundingMaxPerDayWei", parseBigInt).(*big.Int)
+}
+
+// KeyFundingThresholdWei is the balance below which the node's keys are sent
+// KeyFundingAmountWei by the funding key.
+func (c Config) KeyFundingThresholdWei() *big.Int {
+	return c.getWithFallback("KeyFundingThresholdWei", parseBigInt).(*big.Int)
+}
+
+// LeakWatchdogInterval is how often the node samples its goroutines, database
+// connections and subscriptions to look for leaks. Zero disables the
+// watchdog.
+func (c Config) LeakWatchdogInterval() models.Duration {
+	return c.getDuration("LeakWatchdogInterval")
+}
+
+// LeakWatchdogWindow is how many samples in a row must grow for the node to
+// warn of a leak.
+func (c Config) LeakWatchdogWindow() uint {
+	return c.viper.GetUint(EnvVarName("LeakWatchdogWindow"))
+}
+
 // LinkContractAddress represents the address
 func (c Config) LinkContractAddress() string {
 	return c.viper.GetString(EnvVarName("LinkContractAddress"))
@@ -305,14 +744,18 @@ func (c Config) LinkContractAddress() string {
 
 // ExplorerURL returns the websocket URL for this node to push stats to, or nil.
 func (c Config) ExplorerURL() *url.URL {
-	rval := c.getWithFallback("ExplorerURL", parseURL)
+	return c.optionalURL("ExplorerURL")
+}
+
+func (c Config) optionalURL(field string) *url.URL {
+	rval := c.getWithFallback(field, parseURL)
 	switch t := rval.(type) {
 	case nil:
 		return nil
 	case *url.URL:
 		return t
 	default:
-		logger.Panicf("invariant: ExplorerURL returned as type %T", rval)
+		logger.Panicf("invariant: %s returned as type %T", field, rval)
 		return nil
 	}
 }
@@ -335,11 +778,25 @@ func (c Config) OracleContractAddress() *common.Address {
 	return c.getWithFallback("OracleContractAddress", parseAddress).(*common.Address)
 }
 
+// LogConsumptionRetentionDepth is how many blocks behind the head the records
+// of the logs jobs consumed are kept, after which the logs cannot be replayed
+// safely. It must be above the depth of the deepest reorg expected. At 0, the
+// default, the records are kept forever.
+func (c Config) LogConsumptionRetentionDepth() uint64 {
+	return c.viper.GetUint64(EnvVarName("LogConsumptionRetentionDepth"))
+}
+
 // LogLevel represents the maximum level of log messages to output.
 func (c Config) LogLevel() LogLevel {
 	return c.getWithFallback("LogLevel", parseLogLevel).(LogLevel)
 }
 
+// LogModuleLevels are the levels of the modules which log at a level of
+// their own rather than LogLevel.
+func (c Config) LogModuleLevels() logger.ModuleLevels {
+	return c.getWithFallback("LogModuleLevels", parseLogModuleLevels).(logger.ModuleLevels)
+}
+
 // LogToDisk configures disk preservation of logs.
 func (c Config) LogToDisk() bool {
 	return c.viper.GetBool(EnvVarName("LogToDisk"))
@@ -350,6 +807,18 @@ func (c Config) LogSQLStatements() bool {
 	return c.viper.GetBool(EnvVarName("LogSQLStatements"))
 }
 
+// LookupCacheSize is the most bridges, jobs and external initiators each held
+// in memory once looked up.
+func (c Config) LookupCacheSize() uint {
+	return c.viper.GetUint(EnvVarName("LookupCacheSize"))
+}
+
+// LookupCacheTTL is how long bridges, jobs and external initiators looked up
+// are held in memory. At 0, they are looked up in the database every time.
+func (c Config) LookupCacheTTL() models.Duration {
+	return c.getDuration("LookupCacheTTL")
+}
+
 // LogSQLMigrations tells chainlink to log all SQL migrations made using the default logger
 func (c Config) LogSQLMigrations() bool {
 	return c.viper.GetBool(EnvVarName("LogSQLMigrations"))
@@ -380,6 +849,12 @@ func (c Config) MinimumRequestExpiration() uint64 {
 	return c.viper.GetUint64(EnvVarName("MinimumRequestExpiration"))
 }
 
+// P2PListenPort is the port the node listens on for the peer-to-peer
+// connections of off-chain reporting.
+func (c Config) P2PListenPort() uint16 {
+	return c.getWithFallback("P2PListenPort", parseUint16).(uint16)
+}
+
 // Port represents the port Chainlink should listen on for client requests.
 func (c Config) Port() uint16 {
 	return c.getWithFallback("Port", parseUint16).(uint16)
@@ -400,16 +875,163 @@ func (c Config) RootDir() string {
 	return c.getWithFallback("RootDir", parseHomeDir).(string)
 }
 
+// RunQueueCapacity is how many runs can wait in memory for a worker of the
+// run queue to execute them. The runs beyond it are parked in the database
+// until there is room for them.
+func (c Config) RunQueueCapacity() uint {
+	return c.viper.GetUint(EnvVarName("RunQueueCapacity"))
+}
+
+// RunQueueWorkers is how many runs the run queue executes at once. Zero means
+// no limit.
+func (c Config) RunQueueWorkers() uint {
+	return c.viper.GetUint(EnvVarName("RunQueueWorkers"))
+}
+
+// RunResultArchiveAccessKey is the access key, or HMAC key for GCS, of the
+// bucket run results are archived to.
+func (c Config) RunResultArchiveAccessKey() string {
+	return c.viper.GetString(EnvVarName("RunResultArchiveAccessKey"))
+}
+
+// RunResultArchiveAfter is how long after their run finished the data of run
+// results is moved to RunResultArchiveURL. At 0, the default, run results are
+// not archived.
+func (c Config) RunResultArchiveAfter() models.Duration {
+	return c.getDuration("RunResultArchiveAfter")
+}
+
+// RunResultArchiveEndpoint is the endpoint of an S3 compatible store to
+// archive run results to, in place of the endpoint of S3 or GCS, or nil.
+func (c Config) RunResultArchiveEndpoint() *url.URL {
+	return c.optionalURL("RunResultArchiveEndpoint")
+}
+
+// RunResultArchiveRegion is the region of the bucket run results are
+// archived to. It defaults to us-east-1 for S3 and auto for GCS.
+func (c Config) RunResultArchiveRegion() string {
+	return c.viper.GetString(EnvVarName("RunResultArchiveRegion"))
+}
+
+// RunResultArchiveSecret is the secret of the access key of the bucket run
+// results are archived to.
+func (c Config) RunResultArchiveSecret() string {
+	return c.viper.GetString(EnvVarName("RunResultArchiveSecret"))
+}
+
+// RunResultArchiveURL is where the data of old run results is archived to,
+// as s3://<bucket>/<prefix>, gs://<bucket>/<prefix> or file://<directory>,
+// or nil if it is not archived.
+func (c Config) RunResultArchiveURL() *url.URL {
+	return c.optionalURL("RunResultArchiveURL")
+}
+
+// RunResultMaxSize is the most bytes of data a run result keeps. The data of
+// larger results is replaced by a truncation marker, and handled according to
+// RunResultOversizePolicy. At 0, the default, results are not limited.
+func (c Config) RunResultMaxSize() uint64 {
+	return c.viper.GetUint64(EnvVarName("RunResultMaxSize"))
+}
+
+// RunResultOversizePolicy is what happens to the data of run results larger
+// than RunResultMaxSize.
+func (c Config) RunResultOversizePolicy() RunResultOversizePolicy {
+	return c.getWithFallback("RunResultOversizePolicy", parseRunResultOversizePolicy).(RunResultOversizePolicy)
+}
+
+// RunStatsRollupInterval is how often the runs which finished are rolled up
+// into the hourly and daily stats of their jobs. Zero disables the rollups.
+func (c Config) RunStatsRollupInterval() models.Duration {
+	return c.getDuration("RunStatsRollupInterval")
+}
+
 // SecureCookies allows toggling of the secure cookies HTTP flag
 func (c Config) SecureCookies() bool {
 	return c.viper.GetBool(EnvVarName("SecureCookies"))
 }
 
+// ServiceAgreementExpiryNotice is how long before a service agreement ends
+// the operator is notified that it expires soon.
+func (c Config) ServiceAgreementExpiryNotice() models.Duration {
+	return c.getDuration("ServiceAgreementExpiryNotice")
+}
+
 // SessionTimeout is the maximum duration that a user session can persist without any activity.
 func (c Config) SessionTimeout() models.Duration {
 	return c.getDuration("SessionTimeout")
 }
 
+// StuckRunCheckInterval is how often the node looks for runs which have been
+// waiting for longer than their status allows. Zero disables the check.
+func (c Config) StuckRunCheckInterval() models.Duration {
+	return c.getDuration("StuckRunCheckInterval")
+}
+
+// StuckRunConfirmationsThreshold is how long a run can wait for block
+// confirmations without being updated before it is considered stuck.
+func (c Config) StuckRunConfirmationsThreshold() models.Duration {
+	return c.getDuration("StuckRunConfirmationsThreshold")
+}
+
+// StuckRunInProgressThreshold is how long a run can be in progress without
+// being updated before it is considered stuck.
+func (c Config) StuckRunInProgressThreshold() models.Duration {
+	return c.getDuration("StuckRunInProgressThreshold")
+}
+
+// StuckRunMaxResumptions is how many times the node tries to resume a stuck
+// run before escalating it instead.
+func (c Config) StuckRunMaxResumptions() uint {
+	return c.viper.GetUint(EnvVarName("StuckRunMaxResumptions"))
+}
+
+// StuckRunPendingBridgeThreshold is how long a run can wait for a bridge to
+// call back before it is considered stuck.
+func (c Config) StuckRunPendingBridgeThreshold() models.Duration {
+	return c.getDuration("StuckRunPendingBridgeThreshold")
+}
+
+// SyncEventExportAccessKey is the access key, or HMAC key for GCS, of the
+// bucket sync events are exported to.
+func (c Config) SyncEventExportAccessKey() string {
+	return c.viper.GetString(EnvVarName("SyncEventExportAccessKey"))
+}
+
+// SyncEventExportBatchSize is the most sync events exported in one file.
+func (c Config) SyncEventExportBatchSize() uint {
+	return c.viper.GetUint(EnvVarName("SyncEventExportBatchSize"))
+}
+
+// SyncEventExportEndpoint is the endpoint of an S3 compatible store to
+// export sync events to, in place of the endpoint of S3 or GCS, or nil.
+func (c Config) SyncEventExportEndpoint() *url.URL {
+	return c.optionalURL("SyncEventExportEndpoint")
+}
+
+// SyncEventExportInterval is how often sync events are exported.
+func (c Config) SyncEventExportInterval() models.Duration {
+	return c.getDuration("SyncEventExportInterval")
+}
+
+// SyncEventExportRegion is the region of the bucket sync events are exported
+// to. It defaults to us-east-1 for S3 and auto for GCS.
+func (c Config) SyncEventExportRegion() string {
+	return c.viper.GetString(EnvVarName("SyncEventExportRegion"))
+}
+
+// SyncEventExportSecret is the secret of the access key of the bucket sync
+// events are exported to.
+func (c Config) SyncEventExportSecret() string {
+	return c.viper.GetString(EnvVarName("SyncEventExportSecret"))
+}
+
+// SyncEventExportURL is where sync events are exported to, as
+// s3://<bucket>/<prefix>, gs://<bucket>/<prefix> or file://<directory>, or
+// nil if they are not exported.
+func (c Config) SyncEventExportURL() *url.URL {
+	return c.optionalURL("SyncEventExportURL")
+}
+
 // TLSCertPath represents the file system location of the TLS certificate
 // Chainlink should use for HTTPS.
 func (c Config) TLSCertPath() string {
@@ -440,6 +1062,79 @@ func (c Config) TxAttemptLimit() uint16 {
 	return c.getWithFallback("TxAttemptLimit", parseUint16).(uint16)
 }
 
+// VRFBatchMaxSize is the most VRF requests fulfilled together by a single
+// transaction. At 1, the default, each request is fulfilled by its own run.
+func (c Config) VRFBatchMaxSize() uint {
+	return c.viper.GetUint(EnvVarName("VRFBatchMaxSize"))
+}
+
+// VRFBatchMaxWait is how long a confirmed VRF request waits for others to be
+// fulfilled alongside it, before a batch smaller than VRFBatchMaxSize is
+// sent.
+func (c Config) VRFBatchMaxWait() models.Duration {
+	return c.getDuration("VRFBatchMaxWait")
+}
+
+// VRFBatchMulticallAddress is the address of the Multicall2 contract through
+// which batches of VRF fulfillments are sent to the coordinator. Requests
+// are not batched without one.
+func (c Config) VRFBatchMulticallAddress() *common.Address {
+	if c.viper.GetString(EnvVarName("VRFBatchMulticallAddress")) == "" {
+		return nil
+	}
+	return c.getWithFallback("VRFBatchMulticallAddress", parseAddress).(*common.Address)
+}
+
+// VRFFulfillmentRetryBackoff is how long the node waits before retrying to
+// fulfill a VRF request whose fulfilling run errored, doubling with each
+// attempt.
+func (c Config) VRFFulfillmentRetryBackoff() models.Duration {
+	return c.getDuration("VRFFulfillmentRetryBackoff")
+}
+
+// VRFMaxFulfillmentAttempts is the number of times the node tries to fulfill
+// a VRF request before giving it up as failed.
+func (c Config) VRFMaxFulfillmentAttempts() uint {
+	return c.viper.GetUint(EnvVarName("VRFMaxFulfillmentAttempts"))
+}
+
+// VRFMinConfirmations is the number of blocks a RandomnessRequest log must be
+// buried under before the node fulfills it, so that the request is not
+// fulfilled from a block which is then reorged away.
+func (c Config) VRFMinConfirmations() uint32 {
+	return c.viper.GetUint32(EnvVarName("VRFMinConfirmations"))
+}
+
+// VRFProofQueueSize is the most VRF proofs waiting for a worker to generate
+// them. Proofs requested beyond that error, to be retried later.
+func (c Config) VRFProofQueueSize() uint {
+	return c.viper.GetUint(EnvVarName("VRFProofQueueSize"))
+}
+
+// VRFProofWorkers is the most VRF proofs generated at the same time, so that
+// bursts of randomness requests leave CPU for the node's other work.
+func (c Config) VRFProofWorkers() uint {
+	return c.viper.GetUint(EnvVarName("VRFProofWorkers"))
+}
+
+// WasmMaxMemoryPages is the most 64KiB pages of memory the module of a wasm
+// task can use.
+func (c Config) WasmMaxMemoryPages() uint32 {
+	return c.viper.GetUint32(EnvVarName("WasmMaxMemoryPages"))
+}
+
+// WasmMaxModuleSize is the most bytes a WebAssembly module uploaded for wasm
+// tasks can have.
+func (c Config) WasmMaxModuleSize() uint64 {
+	return c.viper.GetUint64(EnvVarName("WasmMaxModuleSize"))
+}
+
+// WasmTimeout is how long the module of a wasm task has to transform the
+// data of the run before it is stopped, and the task errors.
+func (c Config) WasmTimeout() models.Duration {
+	return c.getDuration("WasmTimeout")
+}
+
 // TLSRedirect forces TLS redirect for unencrypted connections
 func (c Config) TLSRedirect() bool {
 	return c.viper.GetBool(EnvVarName("TLSRedirect"))
@@ -484,6 +1179,43 @@ func (c Config) SessionSecret() ([]byte, error) {
 	return c.SecretGenerator.Generate(c)
 }
 
+// MasterKeys returns the key sensitive database columns are encrypted with,
+// followed by the new key of a master key rotation which did not finish, if
+// any, which some of them may already be encrypted with.
+func (c Config) MasterKeys() ([][]byte, error) {
+	key, err := c.MasterKeyGenerator.Generate(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading master key")
+	}
+	keys := [][]byte{key}
+	if utils.FileExists(c.newMasterKeyPath()) {
+		newKey, err := readOrCreateSecret(c.newMasterKeyPath())
+		if err != nil {
+			return nil, errors.Wrap(err, "while loading new master key")
+		}
+		keys = append(keys, newKey)
+	}
+	return keys, nil
+}
+
+// BeginMasterKeyRotation returns a new master key, kept beside the current
+// one until FinishMasterKeyRotation replaces it. If a rotation was begun
+// before but did not finish, its new key is returned again, as columns may
+// already be encrypted with it.
+func (c Config) BeginMasterKeyRotation() ([]byte, error) {
+	return readOrCreateSecret(c.newMasterKeyPath())
+}
+
+// FinishMasterKeyRotation replaces the master key by the new key returned by
+// BeginMasterKeyRotation, once every column is encrypted with it.
+func (c Config) FinishMasterKeyRotation() error {
+	return os.Rename(c.newMasterKeyPath(), filepath.Join(c.RootDir(), masterKeyFilename))
+}
+
+func (c Config) newMasterKeyPath() string {
+	return filepath.Join(c.RootDir(), masterKeyFilename+".new")
+}
+
 // SessionOptions returns the sesssions.Options struct used to configure
 // the session store.
 func (c Config) SessionOptions() sessions.Options {
@@ -495,6 +1227,13 @@ func (c Config) SessionOptions() sessions.Options {
 }
 
 func (c Config) getWithFallback(name string, parser func(string) (interface{}, error)) interface{} {
+	if str, ok := c.runtimeValue(name); ok {
+		v, err := parser(str)
+		if err == nil {
+			return v
+		}
+		logger.Errorw(fmt.Sprintf("Invalid runtime value for %s, falling back to the environment.", name), "value", str, "error", err)
+	}
 	str := c.viper.GetString(EnvVarName(name))
 	defaultValue, hasDefault := defaultValue(name)
 	if str != "" {
@@ -520,18 +1259,29 @@ func (c Config) getWithFallback(name string, parser func(string) (interface{}, e
 	return v
 }
 
+// masterKeyFilename is the file of the root directory the master key, which
+// sensitive database columns are encrypted with, is kept in.
+const masterKeyFilename = "master_key"
+
 // SecretGenerator is the interface for objects that generate a secret
 // used to sign or encrypt.
 type SecretGenerator interface {
 	Generate(Config) ([]byte, error)
 }
 
-type filePersistedSecretGenerator struct{}
+// filePersistedSecretGenerator generates a secret the first time it is asked
+// for, and keeps it in the given file of the root directory.
+type filePersistedSecretGenerator struct {
+	filename string
+}
 
 func (f filePersistedSecretGenerator) Generate(c Config) ([]byte, error) {
-	sessionPath := filepath.Join(c.RootDir(), "secret")
-	if utils.FileExists(sessionPath) {
-		data, err := ioutil.ReadFile(sessionPath)
+	return readOrCreateSecret(filepath.Join(c.RootDir(), f.filename))
+}
+
+func readOrCreateSecret(path string) ([]byte, error) {
+	if utils.FileExists(path) {
+		data, err := ioutil.ReadFile(path)
 		if err != nil {
 			return data, err
 		}
@@ -539,7 +1289,7 @@ func (f filePersistedSecretGenerator) Generate(c Config) ([]byte, error) {
 	}
 	key := securecookie.GenerateRandomKey(32)
 	str := base64.StdEncoding.EncodeToString(key)
-	return key, ioutil.WriteFile(sessionPath, []byte(str), readWritePerms)
+	return key, ioutil.WriteFile(path, []byte(str), readWritePerms)
 }
 
 func parseAddress(str string) (interface{}, error) {
@@ -569,6 +1319,52 @@ func parseLogLevel(str string) (interface{}, error) {
 	return lvl, err
 }
 
+func parseLogModuleLevels(str string) (interface{}, error) {
+	var levels logger.ModuleLevels
+	err := levels.UnmarshalText([]byte(str))
+	return levels, err
+}
+
+func parseCronCatchUpMode(str string) (interface{}, error) {
+	mode := CronCatchUpMode(strings.ToLower(str))
+	switch mode {
+	case CronCatchUpNone, CronCatchUpBackfill, CronCatchUpSkip:
+		return mode, nil
+	default:
+		return mode, fmt.Errorf("Unable to parse '%s' into a cron catch up mode, expected one of none, backfill or skip", str)
+	}
+}
+
+func parseDatabaseLockingStrategy(str string) (interface{}, error) {
+	strategy := DatabaseLockingStrategy(strings.ToLower(str))
+	switch strategy {
+	case DatabaseLockingAdvisory, DatabaseLockingLease:
+		return strategy, nil
+	default:
+		return strategy, fmt.Errorf("Unable to parse '%s' into a database locking strategy, expected one of advisory or lease", str)
+	}
+}
+
+func parseBridgeCacheStore(str string) (interface{}, error) {
+	store := BridgeCacheStore(strings.ToLower(str))
+	switch store {
+	case BridgeCacheMemory, BridgeCacheDatabase:
+		return store, nil
+	default:
+		return store, fmt.Errorf("Unable to parse '%s' into a bridge cache store, expected one of memory or database", str)
+	}
+}
+
+func parseRunResultOversizePolicy(str string) (interface{}, error) {
+	policy := RunResultOversizePolicy(strings.ToLower(str))
+	switch policy {
+	case RunResultOversizeStore, RunResultOversizeTruncate:
+		return policy, nil
+	default:
+		return policy, fmt.Errorf("Unable to parse '%s' into a run result oversize policy, expected one of store or truncate", str)
+	}
+}
+
 func parseUint16(str string) (interface{}, error) {
 	d, err := strconv.ParseUint(str, 10, 16)
 	return uint16(d), err
@@ -594,6 +1390,10 @@ func parseHomeDir(str string) (interface{}, error) {
 	return filepath.ToSlash(exp), nil
 }
 
+// ModuleLevels are the levels of the modules which log at a level of their
+// own.
+type ModuleLevels = logger.ModuleLevels
+
 // LogLevel determines the verbosity of the events to be logged.
 type LogLevel struct {
 	zapcore.Level
@@ -608,3 +1408,54 @@ func (ll LogLevel) ForGin() string {
 		return gin.ReleaseMode
 	}
 }
+
+// CronCatchUpMode determines how cron initiators handle schedules that were
+// missed while the node was not running.
+type CronCatchUpMode string
+
+const (
+	// CronCatchUpNone silently ignores missed schedules.
+	CronCatchUpNone = CronCatchUpMode("none")
+	// CronCatchUpBackfill creates a run for every missed schedule.
+	CronCatchUpBackfill = CronCatchUpMode("backfill")
+	// CronCatchUpSkip records an errored run for every missed schedule, so
+	// that the gap is visible to the node operator.
+	CronCatchUpSkip = CronCatchUpMode("skip")
+)
+
+// DatabaseLockingStrategy is how the node keeps other nodes off the
+// database.
+type DatabaseLockingStrategy string
+
+const (
+	// DatabaseLockingAdvisory takes a Postgres advisory lock, held for as long
+	// as the session holding it lasts.
+	DatabaseLockingAdvisory = DatabaseLockingStrategy("advisory")
+	// DatabaseLockingLease takes a lease on a row of the leases table, renewed
+	// every third of DATABASE_LEASE_DURATION.
+	DatabaseLockingLease = DatabaseLockingStrategy("lease")
+)
+
+// BridgeCacheStore determines where bridge responses are cached.
+type BridgeCacheStore string
+
+const (
+	// BridgeCacheMemory caches responses in a least recently used cache of
+	// BRIDGE_CACHE_SIZE entries, lost when the node restarts.
+	BridgeCacheMemory = BridgeCacheStore("memory")
+	// BridgeCacheDatabase caches responses in the database, so that they
+	// survive restarts.
+	BridgeCacheDatabase = BridgeCacheStore("database")
+)
+
+// RunResultOversizePolicy determines what happens to the data of run results
+// larger than RUN_RESULT_MAX_SIZE.
+type RunResultOversizePolicy string
+
+const (
+	// RunResultOversizeStore moves the data to run_result_blobs, from where
+	// it is given to the following tasks of the run.
+	RunResultOversizeStore = RunResultOversizePolicy("store")
+	// RunResultOversizeTruncate drops the data, leaving only the marker.
+	RunResultOversizeTruncate = RunResultOversizePolicy("truncate")
+)
@@ -14,56 +14,150 @@ import (
 
 // ConfigReader represents just the read side of the config
 type ConfigReader interface {
+	AccessViolationRetention() models.Duration
+	AdapterPluginsDir() string
+	AdapterPluginTimeout() models.Duration
 	AllowOrigins() string
+	APIAllowedCIDRs() []string
+	BridgeCacheSize() uint
+	BridgeCacheStore() BridgeCacheStore
+	BridgeCallbackTimeout() models.Duration
+	BridgeCircuitBreakerThreshold() uint
+	BridgeCircuitBreakerTimeout() models.Duration
 	BridgeResponseURL() *url.URL
 	ChainID() *big.Int
+	ChainProfile() string
 	ClientNodeURL() string
+	ContentSecurityPolicy() string
+	CronCatchUp() CronCatchUpMode
+	CronCatchUpMaxRuns() uint
+	DatabaseAutoMigrate() bool
+	DatabasePartitionRetention() models.Duration
+	DatabasePartitionsAhead() uint
+	DatabaseConnectTimeout() models.Duration
+	DatabaseLeaseDuration() models.Duration
+	DatabaseLockingStrategy() DatabaseLockingStrategy
+	DatabaseOptions() DatabaseOptions
+	DatabasePgBouncerCompatibility() bool
+	DatabaseSlowQueryThreshold() models.Duration
 	DatabaseTimeout() models.Duration
 	DatabaseURL() string
 	DefaultMaxHTTPAttempts() uint
 	DefaultHTTPLimit() int64
 	DefaultHTTPTimeout() models.Duration
 	Dev() bool
+	ExternalInitiatorAllowedCIDRs() []string
+	ExternalInitiatorHealthInterval() models.Duration
+	ExternalInitiatorMaxAttempts() uint
+	ExternalInitiatorRetryBackoff() models.Duration
 	FeatureExternalInitiators() bool
 	FeatureFluxMonitor() bool
+	FeatureOffchainReporting() bool
+	FinalityDepth() uint64
+	FluxMonitorFeedQuarantinePeriod() models.Duration
+	FluxMonitorFeedQuarantineThreshold() uint
+	MaxConcurrentRuns() uint
+	MetricsAllowedCIDRs() []string
 	MaximumServiceDuration() models.Duration
 	MinimumServiceDuration() models.Duration
 	EnableExperimentalAdapters() bool
 	EthGasBumpPercent() uint16
+	EthBlockTime() models.Duration
 	EthGasBumpThreshold() uint64
 	EthGasBumpWei() *big.Int
 	EthGasLimitDefault() uint64
 	EthGasPriceDefault() *big.Int
 	EthMaxGasPriceWei() *big.Int
 	SetEthGasPriceDefault(value *big.Int) error
+	EthereumDisabled() bool
 	EthereumURL() string
+	EthQuorumDepth() uint
+	EthQuorumDivergenceThreshold() uint
+	EthQuorumURL() string
 	GasUpdaterBlockDelay() uint16
 	GasUpdaterBlockHistorySize() uint16
 	GasUpdaterTransactionPercentile() uint16
+	HealthMaxHeadAge() models.Duration
+	HSTSIncludeSubdomains() bool
+	HSTSMaxAge() models.Duration
+	HSTSPreload() bool
 	JSONConsole() bool
+	HTTPAllowedHosts() []string
+	HTTPDeniedHosts() []string
+	HTTPMaxRedirects() uint
+	IncomingConfirmationsTimeout() models.Duration
+	KafkaBrokers() []string
+	KeyFundingAddress() *common.Address
+	KeyFundingAmountWei() *big.Int
+	KeyFundingCheckInterval() models.Duration
+	KeyFundingCooldown() models.Duration
+	KeyFundingMaxPerDayWei() *big.Int
+	KeyFundingThresholdWei() *big.Int
+	LeakWatchdogInterval() models.Duration
+	LeakWatchdogWindow() uint
 	LinkContractAddress() string
 	ExplorerURL() *url.URL
 	ExplorerAccessKey() string
 	ExplorerSecret() string
 	OracleContractAddress() *common.Address
+	LogConsumptionRetentionDepth() uint64
 	LogLevel() LogLevel
+	LogModuleLevels() ModuleLevels
 	LogToDisk() bool
 	LogSQLStatements() bool
+	LookupCacheSize() uint
+	LookupCacheTTL() models.Duration
 	MinIncomingConfirmations() uint32
 	MinOutgoingConfirmations() uint64
 	MinimumContractPayment() *assets.Link
 	MinimumRequestExpiration() uint64
+	P2PListenPort() uint16
 	Port() uint16
 	ReaperExpiration() models.Duration
 	RootDir() string
+	RunQueueCapacity() uint
+	RunQueueWorkers() uint
+	RunResultArchiveAccessKey() string
+	RunResultArchiveAfter() models.Duration
+	RunResultArchiveEndpoint() *url.URL
+	RunResultArchiveRegion() string
+	RunResultArchiveSecret() string
+	RunResultArchiveURL() *url.URL
+	RunResultMaxSize() uint64
+	RunResultOversizePolicy() RunResultOversizePolicy
+	RunStatsRollupInterval() models.Duration
 	SecureCookies() bool
+	ServiceAgreementExpiryNotice() models.Duration
 	SessionTimeout() models.Duration
+	StuckRunCheckInterval() models.Duration
+	StuckRunConfirmationsThreshold() models.Duration
+	StuckRunInProgressThreshold() models.Duration
+	StuckRunMaxResumptions() uint
+	StuckRunPendingBridgeThreshold() models.Duration
+	SyncEventExportAccessKey() string
+	SyncEventExportBatchSize() uint
+	SyncEventExportEndpoint() *url.URL
+	SyncEventExportInterval() models.Duration
+	SyncEventExportRegion() string
+	SyncEventExportSecret() string
+	SyncEventExportURL() *url.URL
 	TLSCertPath() string
 	TLSHost() string
 	TLSKeyPath() string
 	TLSPort() uint16
 	TLSRedirect() bool
 	TxAttemptLimit() uint16
+	VRFBatchMaxSize() uint
+	VRFBatchMaxWait() models.Duration
+	VRFBatchMulticallAddress() *common.Address
+	VRFFulfillmentRetryBackoff() models.Duration
+	VRFMaxFulfillmentAttempts() uint
+	VRFMinConfirmations() uint32
+	VRFProofQueueSize() uint
+	VRFProofWorkers() uint
+	WasmMaxMemoryPages() uint32
+	WasmMaxModuleSize() uint64
+	WasmTimeout() models.Duration
 	KeysDir() string
 	tlsDir() string
 	KeyFile() string
@@ -60,6 +60,53 @@ func TestConfig_sessionOptions(t *testing.T) {
 	require.True(t, opts.Secure)
 }
 
+func TestConfig_KafkaBrokers(t *testing.T) {
+	t.Parallel()
+	config := NewConfig()
+	assert.Equal(t, []string{}, config.KafkaBrokers())
+
+	config.Set("KAFKA_BROKERS", "kafka-1:9092, kafka-2:9092,,")
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, config.KafkaBrokers())
+}
+
+func TestConfig_ChainProfile(t *testing.T) {
+	t.Parallel()
+	config := NewConfig()
+	config.Set("CHAIN_PROFILE", "Polygon")
+	assert.Equal(t, "polygon", config.ChainProfile())
+	assert.Equal(t, big.NewInt(137), config.ChainID())
+	assert.Equal(t, big.NewInt(30000000000), config.EthGasPriceDefault())
+	assert.Equal(t, uint16(20), config.EthGasBumpPercent())
+	assert.Equal(t, uint64(500), config.FinalityDepth())
+	assert.Equal(t, 2*time.Second, config.EthBlockTime().Duration())
+	require.NoError(t, config.Validate())
+
+	config.Set("ETH_GAS_BUMP_PERCENT", 30)
+	assert.Equal(t, uint16(30), config.EthGasBumpPercent(), "set variables should take precedence over the profile")
+
+	config.Set("CHAIN_PROFILE", "")
+	assert.Equal(t, big.NewInt(1), config.ChainID())
+	assert.Equal(t, uint64(50), config.FinalityDepth())
+	assert.Equal(t, 15*time.Second, config.EthBlockTime().Duration())
+
+	config.Set("CHAIN_PROFILE", "ropsten")
+	assert.EqualError(t, config.Validate(), "CHAIN_PROFILE ropsten is not one of bsc, goerli, mainnet, polygon, sepolia")
+}
+
+func TestConfig_Validate_FinalityDepth(t *testing.T) {
+	t.Parallel()
+	config := NewConfig()
+	config.Set("FINALITY_DEPTH", 10)
+	require.NoError(t, config.Validate())
+
+	config.Set("MIN_INCOMING_CONFIRMATIONS", 11)
+	assert.EqualError(t, config.Validate(), "MIN_INCOMING_CONFIRMATIONS of 11 is more than FINALITY_DEPTH of 10")
+
+	config.Set("MIN_INCOMING_CONFIRMATIONS", 10)
+	config.Set("MIN_OUTGOING_CONFIRMATIONS", 12)
+	assert.EqualError(t, config.Validate(), "MIN_OUTGOING_CONFIRMATIONS of 12 is more than FINALITY_DEPTH of 10")
+}
+
 func TestConfig_readFromFile(t *testing.T) {
 	v := viper.New()
 	v.Set("ROOT", "../../../tools/clroot/")
@@ -0,0 +1,209 @@
+package orm
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jpillora/backoff"
+	"github.com/pkg/errors"
+)
+
+// connectBackoff is how long to wait between attempts to reach the database,
+// doubling from a second to half a minute.
+func connectBackoff() backoff.Backoff {
+	return backoff.Backoff{
+		Min:    time.Second,
+		Max:    30 * time.Second,
+		Factor: 2,
+	}
+}
+
+// WaitForDatabase returns once the database at uri answers a ping, trying
+// again with a backoff for up to timeout, so that a node started alongside
+// its database does not fail while the database is still starting. At 0, it
+// tries only once.
+func WaitForDatabase(uri string, timeout time.Duration, clock utils.AfterNower) error {
+	deadline := clock.Now().Add(timeout)
+	b := connectBackoff()
+	for {
+		err := pingDatabase(uri)
+		if err == nil {
+			return nil
+		}
+		wait := b.Duration()
+		if clock.Now().Add(wait).After(deadline) {
+			return errors.Wrap(err, "unable to reach the database")
+		}
+		logger.ORM.Warnw("Unable to reach the database, trying again", "wait", wait, "error", err)
+		<-clock.After(wait)
+	}
+}
+
+func pingDatabase(uri string) error {
+	db, err := sql.Open(string(DialectPostgres), uri)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Ping()
+}
+
+// SetConnectTimeout has the ORM reconnect to the database and take the
+// advisory lock again, with a backoff, for up to timeout when the
+// connection holding the lock is lost. At 0, the ORM becomes read only
+// instead.
+func (orm *ORM) SetConnectTimeout(timeout time.Duration) {
+	orm.connectTimeout = timeout
+}
+
+// Reconnecting returns whether the ORM lost its connection to the database
+// and is trying to reconnect, during which the node is degraded.
+func (orm *ORM) Reconnecting() bool {
+	return orm.reconnecting.IsSet()
+}
+
+// reconnect takes the advisory lock again on a new connection, trying with
+// a backoff for up to the connect timeout, or until the ORM is closed.
+func (orm *ORM) reconnect(cause error) error {
+	orm.reconnecting.Set()
+	defer orm.reconnecting.UnSet()
+
+	deadline := orm.clock.Now().Add(orm.connectTimeout)
+	b := connectBackoff()
+	err := cause
+	for {
+		if err = orm.relock(); err == nil {
+			logger.ORM.Info("Reconnected to the database")
+			return nil
+		}
+
+		wait := b.Duration()
+		if orm.clock.Now().Add(wait).After(deadline) {
+			return err
+		}
+		logger.ORM.Warnw("Lost the connection to the database, reconnecting", "wait", wait, "error", err)
+		select {
+		case <-orm.clock.After(wait):
+		case <-orm.done:
+			return err
+		}
+	}
+}
+
+// relock makes a single attempt to take the advisory lock again. Attempts
+// are made one at a time, the others finding the lock taken once it is their
+// turn, but the backoff between them is waited out without holding the
+// mutex.
+func (orm *ORM) relock() error {
+	orm.reconnectMutex.Lock()
+	defer orm.reconnectMutex.Unlock()
+
+	err := orm.lockingStrategy.Lock(orm.relockTimeout())
+	if err != nil {
+		// Closing the broken connection has the next attempt open a new one.
+		logger.ErrorIf(orm.lockingStrategy.Unlock(orm.advisoryLockTimeout), "unable to close the connection holding the advisory lock")
+	}
+	return err
+}
+
+// lockCheckInterval is how often the ORM checks that it still holds its lock
+// on the database. It is short, as the ORM keeps writing until it finds the
+// lock lost, and checking costs a single query on the lock's connection.
+const lockCheckInterval = time.Second
+
+// ErrReadOnly is returned by writes once the ORM has lost its lock on the
+// database, which another node may have taken since.
+var ErrReadOnly = errors.New("the lock on the database was lost, so the node is read only")
+
+// ReadOnly returns whether the ORM lost its lock on the database, after which
+// it only reads from it.
+func (orm *ORM) ReadOnly() bool {
+	return orm.readOnly.IsSet()
+}
+
+// CheckLock checks that the ORM still holds its lock on the database, taking
+// it again if it was lost and no other node took it, reconnecting for up to
+// DATABASE_CONNECT_TIMEOUT if need be. If the lock cannot be taken, the ORM
+// becomes read only and fires its shutdown signal, so that the services
+// writing to the database are stopped, while the API keeps reading from it.
+func (orm *ORM) CheckLock() error {
+	if orm.ReadOnly() {
+		return ErrReadOnly
+	}
+	held, err := orm.lockingStrategy.Held(orm.relockTimeout())
+	if err == nil && held {
+		return nil
+	}
+
+	err = orm.lockingStrategy.Lock(orm.relockTimeout())
+	if err != nil && orm.connectTimeout > 0 {
+		err = orm.reconnect(err)
+	}
+	if err != nil {
+		orm.readOnly.Set()
+		logger.ORM.Errorw("Lost the lock on the database, the node is read only until restarted", "error", err)
+		orm.shutdownSignal.Panic()
+		return errors.Wrap(ErrReadOnly, err.Error())
+	}
+	logger.ORM.Warn("Took the lock on the database again after losing it")
+	return nil
+}
+
+// relockTimeout is how long to wait for a lock lost to be taken again. A node
+// configured to wait for the lock indefinitely when starting gives up after
+// lockCheckInterval instead, as another node taking the lock meanwhile keeps
+// it.
+func (orm *ORM) relockTimeout() models.Duration {
+	if orm.advisoryLockTimeout.IsInstant() {
+		return models.MustMakeDuration(lockCheckInterval)
+	}
+	return orm.advisoryLockTimeout
+}
+
+func (orm *ORM) monitorLock() {
+	defer orm.wg.Done()
+	ticker := time.NewTicker(lockCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-orm.done:
+			return
+		case <-ticker.C:
+			if err := orm.CheckLock(); errors.Cause(err) == ErrReadOnly {
+				return
+			}
+		}
+	}
+}
+
+// refuseWritesWhenReadOnly has the creates, updates and deletes made through
+// gorm fail with ErrReadOnly once the ORM is read only. Statements executed
+// directly go through exec, and the raw queries which write check ReadOnly
+// themselves. Sessions are still saved and deleted, so that operators can log
+// in to a read only node to find out why it is, and log out of it.
+func (orm *ORM) refuseWritesWhenReadOnly() {
+	refuse := func(scope *gorm.Scope) {
+		if orm.ReadOnly() && scope.TableName() != "sessions" {
+			scope.Err(ErrReadOnly)
+		}
+	}
+	orm.db.Callback().Create().Before("gorm:begin_transaction").Register("chainlink:refuse_when_read_only", refuse)
+	orm.db.Callback().Update().Before("gorm:begin_transaction").Register("chainlink:refuse_when_read_only", refuse)
+	orm.db.Callback().Delete().Before("gorm:begin_transaction").Register("chainlink:refuse_when_read_only", refuse)
+}
+
+// exec executes the statement, unless the ORM is read only, as gorm executes
+// statements without calling back refuseWritesWhenReadOnly.
+func (orm *ORM) exec(sql string, values ...interface{}) *gorm.DB {
+	if orm.ReadOnly() {
+		db := orm.db.New()
+		_ = db.AddError(ErrReadOnly)
+		return db
+	}
+	return orm.db.Exec(sql, values...)
+}
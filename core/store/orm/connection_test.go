@@ -0,0 +1,47 @@
+package orm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steppingClock moves forward by the duration waited for at once, recording
+// the waits.
+type steppingClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	return c.now
+}
+
+func (c *steppingClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestWaitForDatabase_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	uri := "postgresql://localhost:1/chainlink_test?sslmode=disable&connect_timeout=1"
+
+	clock := &steppingClock{now: time.Now()}
+	err := orm.WaitForDatabase(uri, 0, clock)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to reach the database")
+	assert.Empty(t, clock.waits, "should try only once without a timeout")
+
+	clock = &steppingClock{now: time.Now()}
+	err = orm.WaitForDatabase(uri, 4*time.Second, clock)
+	require.Error(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.waits, "should back off until the timeout")
+}
@@ -1,6 +1,9 @@
 package orm
 
-import "github.com/smartcontractkit/chainlink/core/gracefulpanic"
+import (
+	"github.com/smartcontractkit/chainlink/core/gracefulpanic"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+)
 
 func (o *ORM) LockingStrategyHelperSimulateDisconnect() (error, error) {
 	err1 := o.lockingStrategy.(*PostgresLockingStrategy).conn.Close()
@@ -13,3 +16,35 @@ func (o *ORM) LockingStrategyHelperSimulateDisconnect() (error, error) {
 func (o *ORM) ShutdownSignal() gracefulpanic.Signal {
 	return o.shutdownSignal
 }
+
+// PreloadedJobRun loads a run as FindJobRun did before its query was
+// written by hand, to compare the two.
+func (o *ORM) PreloadedJobRun(id *models.ID) (models.JobRun, error) {
+	var jr models.JobRun
+	err := o.preloadJobRuns().First(&jr, "id = ?", id).Error
+	return jr, err
+}
+
+// PreloadedJobRunsFor loads runs as JobRunsFor did before its query was
+// written by hand.
+func (o *ORM) PreloadedJobRunsFor(jobSpecID *models.ID, limit int) ([]models.JobRun, error) {
+	runs := []models.JobRun{}
+	err := o.preloadJobRuns().
+		Limit(limit).
+		Where("job_spec_id = ?", jobSpecID).
+		Order("created_at desc").
+		Find(&runs).Error
+	return runs, err
+}
+
+// PreloadedUnconfirmedTxAttempts loads attempts as UnconfirmedTxAttempts did
+// before its query was written by hand.
+func (o *ORM) PreloadedUnconfirmedTxAttempts() ([]models.TxAttempt, error) {
+	var attempts []models.TxAttempt
+	err := o.db.
+		Preload("Tx").
+		Joins("inner join txes on txes.id = tx_attempts.tx_id").
+		Where("txes.confirmed = ?", false).
+		Find(&attempts).Error
+	return attempts, err
+}
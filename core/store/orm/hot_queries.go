@@ -0,0 +1,306 @@
+package orm
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/pkg/errors"
+	"gopkg.in/guregu/null.v3"
+)
+
+// The queries below load the records read most often, runs and unconfirmed
+// transaction attempts, with hand-written joins mapped onto the models,
+// instead of the query per association gorm preloads them with. Their
+// columns must be kept in step with the models and migrations.
+
+const jobRunColumns = `job_runs.id, job_runs.job_spec_id, job_runs.result_id,
+	job_runs.run_request_id, job_runs.status, job_runs.created_at,
+	job_runs.finished_at, job_runs.updated_at, job_runs.initiator_id,
+	job_runs.creation_height, job_runs.observed_height, job_runs.deleted_at,
+	job_runs.payment`
+
+const taskRunColumns = `task_runs.id, task_runs.job_run_id, task_runs.result_id,
+	task_runs.status, task_runs.task_spec_id, task_runs.minimum_confirmations,
+	task_runs.confirmations, task_runs.minimum_outgoing_confirmations,
+	task_runs.created_at, task_runs.updated_at`
+
+const runRequestColumns = `run_requests.id, run_requests.request_id,
+	run_requests.tx_hash, run_requests.block_hash, run_requests.requester,
+	run_requests.created_at, run_requests.payment, run_requests.request_params,
+	run_requests.idempotency_key, run_requests.job_spec_id,
+	run_requests.log_index`
+
+const txAttemptColumns = `tx_attempts.id, tx_attempts.tx_id, tx_attempts.created_at,
+	tx_attempts.hash, tx_attempts.gas_price, tx_attempts.confirmed,
+	tx_attempts.sent_at, tx_attempts.signed_raw_tx, tx_attempts.updated_at,
+	tx_attempts.gas_used, tx_attempts.effective_gas_price`
+
+const txColumns = `txes.id, txes.surrogate_id, txes."from", txes."to", txes.data,
+	txes.nonce, txes.value, txes.gas_limit, txes.hash, txes.gas_price,
+	txes.confirmed, txes.sent_at, txes.signed_raw_tx, txes.created_at,
+	txes.updated_at`
+
+// runResultColumns are the columns of the run results joined as alias.
+func runResultColumns(alias string) string {
+	return fmt.Sprintf(`%[1]s.id, %[1]s.data, %[1]s.error_message,
+	%[1]s.blob_hash, %[1]s.archived_in, %[1]s.created_at, %[1]s.updated_at`, alias)
+}
+
+// joinedRunResult scans a left joined run result, which is absent if its ID
+// is null.
+type joinedRunResult struct {
+	id           sql.NullInt64
+	data         *models.JSON
+	errorMessage null.String
+	blobHash     null.String
+	archivedIn   null.String
+	createdAt    null.Time
+	updatedAt    null.Time
+}
+
+func (r *joinedRunResult) dest() []interface{} {
+	return []interface{}{&r.id, &r.data, &r.errorMessage, &r.blobHash, &r.archivedIn, &r.createdAt, &r.updatedAt}
+}
+
+func (r *joinedRunResult) runResult() models.RunResult {
+	if !r.id.Valid {
+		return models.RunResult{}
+	}
+	result := models.RunResult{
+		ID:           uint32(r.id.Int64),
+		ErrorMessage: r.errorMessage,
+		BlobHash:     r.blobHash,
+		ArchivedIn:   r.archivedIn,
+		CreatedAt:    r.createdAt.Time,
+		UpdatedAt:    r.updatedAt.Time,
+	}
+	if r.data != nil {
+		result.Data = *r.data
+	}
+	return result
+}
+
+// joinedRunRequest scans a left joined run request, which is absent if its
+// ID is null.
+type joinedRunRequest struct {
+	id            sql.NullInt64
+	createdAt     null.Time
+	requestParams *models.JSON
+	request       models.RunRequest
+}
+
+func (r *joinedRunRequest) dest() []interface{} {
+	return []interface{}{
+		&r.id, &r.request.RequestID, &r.request.TxHash, &r.request.BlockHash,
+		&r.request.Requester, &r.createdAt, &r.request.Payment, &r.requestParams,
+		&r.request.IdempotencyKey, &r.request.JobSpecID, &r.request.LogIndex,
+	}
+}
+
+func (r *joinedRunRequest) runRequest() models.RunRequest {
+	if !r.id.Valid {
+		return models.RunRequest{}
+	}
+	request := r.request
+	request.ID = uint32(r.id.Int64)
+	request.CreatedAt = r.createdAt.Time
+	if r.requestParams != nil {
+		request.RequestParams = *r.requestParams
+	}
+	return request
+}
+
+// loadJobRuns returns the runs selected by clauses, the conditions, order
+// and limit following the WHERE of a query of the job_runs joined with their
+// results and requests, along with their task runs, initiators and task
+// specs. Archived runs are only returned by an unscoped ORM.
+func (orm *ORM) loadJobRuns(clauses string, args ...interface{}) ([]models.JobRun, error) {
+	if !orm.unscoped {
+		clauses = "job_runs.deleted_at IS NULL AND " + clauses
+	}
+	rows, err := orm.db.Raw(`
+		SELECT `+jobRunColumns+`, `+runResultColumns("run_results")+`, `+runRequestColumns+`
+		FROM job_runs
+		LEFT JOIN run_results ON run_results.id = job_runs.result_id
+		LEFT JOIN run_requests ON run_requests.id = job_runs.run_request_id
+		WHERE `+clauses, args...).Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading job runs")
+	}
+	defer rows.Close()
+
+	runs := []models.JobRun{}
+	for rows.Next() {
+		var jr models.JobRun
+		var result joinedRunResult
+		var request joinedRunRequest
+		dest := []interface{}{
+			&jr.ID, &jr.JobSpecID, &jr.ResultID, &jr.RunRequestID, &jr.Status,
+			&jr.CreatedAt, &jr.FinishedAt, &jr.UpdatedAt, &jr.InitiatorID,
+			&jr.CreationHeight, &jr.ObservedHeight, &jr.DeletedAt, &jr.Payment,
+		}
+		dest = append(dest, result.dest()...)
+		dest = append(dest, request.dest()...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errors.Wrap(err, "while loading job runs")
+		}
+		jr.Result = result.runResult()
+		jr.RunRequest = request.runRequest()
+		runs = append(runs, jr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "while loading job runs")
+	}
+	if len(runs) == 0 {
+		return runs, nil
+	}
+
+	if err := orm.loadTaskRuns(runs); err != nil {
+		return nil, err
+	}
+	return runs, orm.loadInitiators(runs)
+}
+
+// loadTaskRuns sets the task runs of the runs, in the order of their task
+// specs, with their results and task specs.
+func (orm *ORM) loadTaskRuns(runs []models.JobRun) error {
+	runIndexes := make(map[string]int, len(runs))
+	runIDs := make([]string, len(runs))
+	for i, jr := range runs {
+		runIndexes[jr.ID.String()] = i
+		runIDs[i] = jr.ID.String()
+	}
+
+	rows, err := orm.db.Raw(`
+		SELECT `+taskRunColumns+`, `+runResultColumns("run_results")+`
+		FROM task_runs
+		LEFT JOIN run_results ON run_results.id = task_runs.result_id
+		WHERE task_runs.job_run_id IN (?)
+		ORDER BY task_runs.task_spec_id ASC
+	`, runIDs).Rows()
+	if err != nil {
+		return errors.Wrap(err, "while loading task runs")
+	}
+	defer rows.Close()
+
+	var taskRuns []models.TaskRun
+	for rows.Next() {
+		var tr models.TaskRun
+		var result joinedRunResult
+		dest := []interface{}{
+			&tr.ID, &tr.JobRunID, &tr.ResultID, &tr.Status, &tr.TaskSpecID,
+			&tr.MinimumConfirmations, &tr.Confirmations,
+			&tr.MinimumOutgoingConfirmations, &tr.CreatedAt, &tr.UpdatedAt,
+		}
+		if err := rows.Scan(append(dest, result.dest()...)...); err != nil {
+			return errors.Wrap(err, "while loading task runs")
+		}
+		tr.Result = result.runResult()
+		taskRuns = append(taskRuns, tr)
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "while loading task runs")
+	}
+
+	specs, err := orm.taskSpecsByID(taskRuns)
+	if err != nil {
+		return err
+	}
+	for _, tr := range taskRuns {
+		tr.TaskSpec = specs[tr.TaskSpecID]
+		i := runIndexes[tr.JobRunID.String()]
+		runs[i].TaskRuns = append(runs[i].TaskRuns, tr)
+	}
+	return nil
+}
+
+// taskSpecsByID returns the task specs of the task runs, archived or not,
+// by their IDs.
+func (orm *ORM) taskSpecsByID(taskRuns []models.TaskRun) (map[uint]models.TaskSpec, error) {
+	specs := make(map[uint]models.TaskSpec)
+	if len(taskRuns) == 0 {
+		return specs, nil
+	}
+	ids := make([]uint, len(taskRuns))
+	for i, tr := range taskRuns {
+		ids[i] = tr.TaskSpecID
+	}
+
+	var found []models.TaskSpec
+	err := orm.db.
+		Unscoped().
+		Preload("Edges", orderTaskSpecEdges).
+		Where("id IN (?)", ids).
+		Find(&found).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading task specs")
+	}
+	for _, spec := range found {
+		specs[spec.ID] = spec
+	}
+	return specs, nil
+}
+
+// loadInitiators sets the initiators of the runs, archived or not.
+func (orm *ORM) loadInitiators(runs []models.JobRun) error {
+	ids := make([]uint32, len(runs))
+	for i, jr := range runs {
+		ids[i] = jr.InitiatorID
+	}
+
+	var initiators []models.Initiator
+	if err := orm.db.Unscoped().Where("id IN (?)", ids).Find(&initiators).Error; err != nil {
+		return errors.Wrap(err, "while loading initiators")
+	}
+	byID := make(map[uint32]models.Initiator, len(initiators))
+	for _, initr := range initiators {
+		byID[initr.ID] = initr
+	}
+	for i := range runs {
+		runs[i].Initiator = byID[runs[i].InitiatorID]
+	}
+	return nil
+}
+
+// loadUnconfirmedTxAttempts returns the attempts of the transactions which
+// are not confirmed, each with its transaction, which the attempts of a
+// transaction share.
+func (orm *ORM) loadUnconfirmedTxAttempts() ([]models.TxAttempt, error) {
+	rows, err := orm.db.Raw(`
+		SELECT ` + txAttemptColumns + `, ` + txColumns + `
+		FROM tx_attempts
+		JOIN txes ON txes.id = tx_attempts.tx_id
+		WHERE NOT txes.confirmed
+	`).Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "while loading unconfirmed tx attempts")
+	}
+	defer rows.Close()
+
+	txes := make(map[uint64]*models.Tx)
+	var attempts []models.TxAttempt
+	for rows.Next() {
+		var attempt models.TxAttempt
+		var tx models.Tx
+		err := rows.Scan(
+			&attempt.ID, &attempt.TxID, &attempt.CreatedAt, &attempt.Hash,
+			&attempt.GasPrice, &attempt.Confirmed, &attempt.SentAt,
+			&attempt.SignedRawTx, &attempt.UpdatedAt, &attempt.GasUsed,
+			&attempt.EffectiveGasPrice,
+			&tx.ID, &tx.SurrogateID, &tx.From, &tx.To, &tx.Data, &tx.Nonce,
+			&tx.Value, &tx.GasLimit, &tx.Hash, &tx.GasPrice, &tx.Confirmed,
+			&tx.SentAt, &tx.SignedRawTx, &tx.CreatedAt, &tx.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "while loading unconfirmed tx attempts")
+		}
+		if _, ok := txes[tx.ID]; !ok {
+			txes[tx.ID] = &tx
+		}
+		attempt.Tx = txes[tx.ID]
+		attempts = append(attempts, attempt)
+	}
+	return attempts, rows.Err()
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"go.uber.org/multierr"
@@ -28,6 +30,7 @@ func NewLockingStrategy(dialect DialectName, dbpath string) (LockingStrategy, er
 type LockingStrategy interface {
 	Lock(timeout models.Duration) error
 	Unlock(timeout models.Duration) error
+	Held(timeout models.Duration) (bool, error)
 }
 
 func normalizedTimeout(timeout time.Duration) <-chan time.Time {
@@ -120,3 +123,379 @@ func (s *PostgresLockingStrategy) Unlock(timeout models.Duration) error {
 		dbErr,
 	)
 }
+
+// Held returns whether the postgres advisory lock is still held by the
+// connection which locked it.
+func (s *PostgresLockingStrategy) Held(timeout models.Duration) (bool, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.conn == nil {
+		return false, nil
+	}
+
+	ctx := context.Background()
+	if !timeout.IsInstant() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout.Duration())
+		defer cancel()
+	}
+
+	return advisoryLockHeld(ctx, s.conn)
+}
+
+// queryRower is a connection or transaction which queries can be run on.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// advisoryLockHeld returns whether the session of conn holds the advisory
+// lock.
+func advisoryLockHeld(ctx context.Context, conn queryRower) (bool, error) {
+	// Advisory locks on a bigint key are listed in pg_locks with the high
+	// and low halves of the key in classid and objid.
+	var held bool
+	err := conn.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory'
+			AND pid = pg_backend_pid()
+			AND granted
+			AND classid::bigint = $1 AND objid::bigint = $2 AND objsubid = 1
+		)`, postgresAdvisoryLockID>>32, postgresAdvisoryLockID&0xffffffff).Scan(&held)
+	return held, err
+}
+
+// leaseRetryInterval is how often the LeaseLockingStrategy tries to take a
+// lease held by another node.
+const leaseRetryInterval = time.Second
+
+// LeaseLockingStrategy ensures exclusive access with a lease on the node row
+// of the leases table, which it renews in the background. Unlike an advisory
+// lock, the lease does not belong to a session, so it works behind a
+// PgBouncer pooling transactions and on managed databases which do not keep
+// sessions, or the advisory locks they hold, for long. The row shows which
+// node holds the lease, since when, and when it last renewed it. A node
+// which stops renewing the lease loses it once it expires.
+//
+// The leases table is created by a migration, and migrations run once the
+// node holds its lock. On a database which has not been migrated yet, the
+// node holds the advisory lock instead, until it can take the lease. The
+// lock is taken for a transaction kept open until then, which PgBouncer
+// pooling transactions keeps on the same server connection, rather than for
+// the session. Nodes only take the lease while no other node holds the
+// advisory lock.
+type LeaseLockingStrategy struct {
+	db         *sql.DB
+	unmigrated *sql.Tx
+	path       string
+	duration   time.Duration
+	holderID   string
+	holder     string
+	expiresAt  time.Time
+	stop       chan struct{}
+	wg         sync.WaitGroup
+	m          *sync.Mutex
+}
+
+// NewLeaseLockingStrategy returns a new instance of the LeaseLockingStrategy,
+// taking leases lasting duration, which it renews three times as often.
+func NewLeaseLockingStrategy(path string, duration time.Duration) (LockingStrategy, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("lease duration must be positive, got %v", duration)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &LeaseLockingStrategy{
+		m:        &sync.Mutex{},
+		path:     path,
+		duration: duration,
+		holderID: models.NewID().String(),
+		holder:   fmt.Sprintf("%s (pid %d)", hostname, os.Getpid()),
+	}, nil
+}
+
+// Lock takes the lease, waiting for up to the passed timeout for another node
+// holding it to release it or let it expire. Once taken, it is renewed until
+// Unlock is called.
+func (s *LeaseLockingStrategy) Lock(timeout models.Duration) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.db != nil && (time.Now().Before(s.expiresAt) || s.unmigrated != nil) {
+		return nil
+	}
+
+	ctx := context.Background()
+	if !timeout.IsInstant() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout.Duration())
+		defer cancel()
+	}
+
+	if s.db == nil {
+		db, err := sql.Open(string(DialectPostgres), s.path)
+		if err != nil {
+			return err
+		}
+		s.db = db
+	}
+
+	for {
+		acquired, err := s.acquire(ctx)
+		if isUndefinedTable(err) {
+			acquired, err = s.lockUnmigrated(ctx)
+		}
+		if err != nil {
+			return errors.Wrapf(ErrNoAdvisoryLock,
+				"lease locking strategy failed on .Lock, timeout set to %v: %v",
+				displayTimeout(timeout), err)
+		}
+		if acquired {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ErrNoAdvisoryLock,
+				"lease locking strategy failed on .Lock, timeout set to %v: %s",
+				displayTimeout(timeout), s.currentHolder())
+		case <-time.After(leaseRetryInterval):
+		}
+	}
+
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		s.wg.Add(1)
+		go s.renew(s.stop)
+	}
+	return nil
+}
+
+// acquire takes or renews the lease, unless another node holds it or the
+// advisory lock. The expiry is reckoned by the database, so the clocks of
+// the nodes need not agree.
+func (s *LeaseLockingStrategy) acquire(ctx context.Context) (bool, error) {
+	if s.unmigrated != nil {
+		return s.acquireUnmigrated(ctx)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var free bool
+	err = tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, postgresAdvisoryLockID).Scan(&free)
+	if err != nil || !free {
+		return false, err
+	}
+	acquired, err := s.upsertLease(ctx, tx)
+	if err != nil || !acquired {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	s.expiresAt = time.Now().Add(s.duration)
+	return true, nil
+}
+
+// acquireUnmigrated takes the lease in the transaction holding the advisory
+// lock, which committing releases, so that the lease replaces the lock
+// without a moment when neither is held. Should the leases table not exist
+// yet, the transaction is rolled back to before the attempt, keeping the
+// lock.
+func (s *LeaseLockingStrategy) acquireUnmigrated(ctx context.Context) (bool, error) {
+	tx := s.unmigrated
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT lease`); err != nil {
+		return false, err
+	}
+	acquired, err := s.upsertLease(ctx, tx)
+	if err != nil || !acquired {
+		if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT lease`); rbErr != nil {
+			return false, multierr.Combine(err, rbErr)
+		}
+		return false, err
+	}
+	s.unmigrated = nil
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	s.expiresAt = time.Now().Add(s.duration)
+	return true, nil
+}
+
+// upsertLease takes or renews the lease in tx, unless another node holds it.
+func (s *LeaseLockingStrategy) upsertLease(ctx context.Context, tx *sql.Tx) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO leases (name, holder_id, holder, acquired_at, heartbeat_at, expires_at)
+		VALUES ('node', $1, $2, now(), now(), now() + $3 * interval '1 millisecond')
+		ON CONFLICT (name) DO UPDATE SET
+			holder_id = EXCLUDED.holder_id,
+			holder = EXCLUDED.holder,
+			acquired_at = CASE WHEN leases.holder_id = EXCLUDED.holder_id
+				THEN leases.acquired_at ELSE EXCLUDED.acquired_at END,
+			heartbeat_at = EXCLUDED.heartbeat_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE leases.holder_id = EXCLUDED.holder_id OR leases.expires_at < now()`,
+		s.holderID, s.holder, s.duration.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// lockUnmigrated takes the advisory lock in place of the lease, as the
+// database has no leases table yet, reporting false if another node holds
+// it. If another node migrated the database in the meantime, the lease is
+// taken instead.
+func (s *LeaseLockingStrategy) lockUnmigrated(ctx context.Context) (bool, error) {
+	if s.unmigrated == nil {
+		// The transaction outlives the context of the call, which would
+		// roll it back once done.
+		tx, err := s.db.BeginTx(context.Background(), nil)
+		if err != nil {
+			return false, err
+		}
+		var free bool
+		err = tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, postgresAdvisoryLockID).Scan(&free)
+		if err != nil || !free {
+			tx.Rollback()
+			return false, err
+		}
+		s.unmigrated = tx
+	}
+
+	acquired, err := s.acquire(ctx)
+	if isUndefinedTable(err) {
+		return true, nil
+	}
+	s.unlockUnmigrated()
+	return acquired, err
+}
+
+// unlockUnmigrated releases the advisory lock held in place of the lease.
+func (s *LeaseLockingStrategy) unlockUnmigrated() {
+	if s.unmigrated == nil {
+		return
+	}
+	_ = s.unmigrated.Rollback()
+	s.unmigrated = nil
+}
+
+// isUndefinedTable returns whether err is that of a statement on a table
+// which does not exist.
+func isUndefinedTable(err error) bool {
+	pqErr, ok := errors.Cause(err).(*pq.Error)
+	return ok && pqErr.Code.Name() == "undefined_table"
+}
+
+// currentHolder describes the node holding the lease, for the error of a node
+// which could not take it.
+func (s *LeaseLockingStrategy) currentHolder() string {
+	var holder string
+	var expiresAt time.Time
+	err := s.db.QueryRow(`SELECT holder, expires_at FROM leases WHERE name = 'node'`).Scan(&holder, &expiresAt)
+	if err != nil {
+		return "lease is held by another node"
+	}
+	return fmt.Sprintf("lease is held by %s until %s", holder, expiresAt.Format(time.RFC3339))
+}
+
+func (s *LeaseLockingStrategy) renew(stop chan struct{}) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.duration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.m.Lock()
+			if s.db != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), s.duration/3)
+				acquired, err := s.acquire(ctx)
+				cancel()
+				if err == nil {
+					// Once the database is migrated, the lease replaces the
+					// advisory lock.
+					s.unlockUnmigrated()
+				}
+				if err == nil && !acquired {
+					// Another node took the lease, so the next Lock fails
+					// rather than finding it still held.
+					s.expiresAt = time.Time{}
+				}
+			}
+			s.m.Unlock()
+		}
+	}
+}
+
+// Unlock stops renewing the lease and releases it.
+func (s *LeaseLockingStrategy) Unlock(timeout models.Duration) error {
+	s.m.Lock()
+	stop := s.stop
+	s.stop = nil
+	s.m.Unlock()
+	if stop != nil {
+		close(stop)
+		s.wg.Wait()
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.db == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	if !timeout.IsInstant() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout.Duration())
+		defer cancel()
+	}
+	s.unlockUnmigrated()
+	_, err := s.db.ExecContext(ctx, `DELETE FROM leases WHERE name = 'node' AND holder_id = $1`, s.holderID)
+	if isUndefinedTable(err) {
+		err = nil
+	}
+	dbErr := s.db.Close()
+	s.db = nil
+	s.expiresAt = time.Time{}
+
+	return multierr.Combine(err, dbErr)
+}
+
+// Held returns whether the lease, or the advisory lock held in its place, is
+// still held by this node.
+func (s *LeaseLockingStrategy) Held(timeout models.Duration) (bool, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.db == nil {
+		return false, nil
+	}
+
+	ctx := context.Background()
+	if !timeout.IsInstant() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout.Duration())
+		defer cancel()
+	}
+
+	if s.unmigrated != nil {
+		return advisoryLockHeld(ctx, s.unmigrated)
+	}
+	var held bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM leases
+			WHERE name = 'node' AND holder_id = $1 AND expires_at > now()
+		)`, s.holderID).Scan(&held)
+	return held, err
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/smartcontractkit/chainlink/core/gracefulpanic"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/migrations/migration1592890000"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 
@@ -67,6 +68,94 @@ func TestPostgresLockingStrategy_Lock(t *testing.T) {
 	require.NoError(t, ls2.Unlock(delay))
 }
 
+func TestLeaseLockingStrategy_Lock(t *testing.T) {
+	tc, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+
+	cleanupDB := cltest.PrepareTestDB(tc)
+	defer cleanupDB()
+
+	c := tc.Config
+
+	if c.DatabaseURL() == "" {
+		t.Skip("No postgres DatabaseURL set.")
+	}
+
+	delay := models.MustMakeDuration(2 * time.Second)
+
+	ls, err := orm.NewLeaseLockingStrategy(c.DatabaseURL(), 3*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, ls.Lock(delay), "should get exclusive lease")
+	require.NoError(t, ls.Lock(delay), "relocking on same instance is reentrant")
+	held, err := ls.Held(delay)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	ls2, err := orm.NewLeaseLockingStrategy(c.DatabaseURL(), 3*time.Second)
+	require.NoError(t, err)
+	err = ls2.Lock(delay)
+	require.Equal(t, orm.ErrNoAdvisoryLock, errors.Cause(err), "should not get 2nd exclusive lease")
+
+	require.NoError(t, ls.Unlock(delay))
+	require.NoError(t, ls.Unlock(delay))
+	require.NoError(t, ls2.Lock(delay), "should get exclusive lease")
+	require.NoError(t, ls2.Unlock(delay))
+
+	advisory, err := orm.NewPostgresLockingStrategy(c.DatabaseURL())
+	require.NoError(t, err)
+	require.NoError(t, advisory.Lock(delay))
+	err = ls.Lock(delay)
+	require.Equal(t, orm.ErrNoAdvisoryLock, errors.Cause(err), "should not get lease while the advisory lock is held")
+	require.NoError(t, advisory.Unlock(delay))
+	require.NoError(t, ls.Lock(delay), "should get exclusive lease")
+	require.NoError(t, ls.Unlock(delay))
+}
+
+func TestLeaseLockingStrategy_LockUnmigrated(t *testing.T) {
+	tc, cleanup := cltest.NewConfig(t)
+	defer cleanup()
+
+	cleanupDB := cltest.PrepareTestDB(tc)
+	defer cleanupDB()
+
+	c := tc.Config
+
+	if c.DatabaseURL() == "" {
+		t.Skip("No postgres DatabaseURL set.")
+	}
+
+	delay := models.MustMakeDuration(2 * time.Second)
+
+	db, err := gorm.Open(string(orm.DialectPostgres), c.DatabaseURL())
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Exec(`DROP TABLE leases`).Error)
+
+	ls, err := orm.NewLeaseLockingStrategy(c.DatabaseURL(), 3*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, ls.Lock(delay), "should lock an unmigrated database")
+	defer ls.Unlock(delay)
+	held, err := ls.Held(delay)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	ls2, err := orm.NewLeaseLockingStrategy(c.DatabaseURL(), 3*time.Second)
+	require.NoError(t, err)
+	err = ls2.Lock(delay)
+	require.Equal(t, orm.ErrNoAdvisoryLock, errors.Cause(err), "should not get 2nd exclusive lock")
+
+	// Once the database is migrated, the lease is taken
+	require.NoError(t, migration1592890000.Migrate(db))
+	require.Eventually(t, func() bool {
+		var count int
+		require.NoError(t, db.Table("leases").Count(&count).Error)
+		return count == 1
+	}, 5*time.Second, 100*time.Millisecond)
+	held, err = ls.Held(delay)
+	require.NoError(t, err)
+	require.True(t, held)
+}
+
 func TestPostgresLockingStrategy_WhenLostIsReacquired(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
@@ -81,10 +170,8 @@ func TestPostgresLockingStrategy_WhenLostIsReacquired(t *testing.T) {
 	require.NoError(t, connErr)
 	require.NoError(t, dbErr)
 
-	err := store.ORM.RawDB(func(db *gorm.DB) error {
-		return db.Save(&models.JobSpec{ID: models.NewID()}).Error
-	})
-	require.NoError(t, err)
+	require.NoError(t, store.ORM.CheckLock())
+	require.False(t, store.ORM.ReadOnly())
 
 	lock2, err := orm.NewLockingStrategy("postgres", store.Config.DatabaseURL())
 	require.NoError(t, err)
@@ -106,7 +193,7 @@ func TestPostgresLockingStrategy_CanBeReacquiredByNewNodeAfterDisconnect(t *test
 	require.NoError(t, dbErr)
 
 	orm2ShutdownSignal := gracefulpanic.NewSignal()
-	orm2, err := orm.NewORM(store.Config.DatabaseURL(), store.Config.DatabaseTimeout(), orm2ShutdownSignal)
+	orm2, err := orm.NewORM(store.Config.DatabaseURL(), store.Config.DatabaseTimeout(), orm2ShutdownSignal, store.Config.DatabaseOptions())
 	require.NoError(t, err)
 	defer orm2.Close()
 
@@ -115,8 +202,48 @@ func TestPostgresLockingStrategy_CanBeReacquiredByNewNodeAfterDisconnect(t *test
 	})
 	require.NoError(t, err)
 
-	_ = store.ORM.RawDB(func(db *gorm.DB) error { return nil })
+	err = store.ORM.CheckLock()
+	require.Equal(t, orm.ErrReadOnly, errors.Cause(err))
+	require.True(t, store.ORM.ReadOnly())
 	gomega.NewGomegaWithT(t).Eventually(store.ORM.ShutdownSignal().Wait()).Should(gomega.BeClosed())
+
+	job := cltest.NewJobWithWebInitiator()
+	require.Equal(t, orm.ErrReadOnly, errors.Cause(store.CreateJob(&job)), "a read only node should not write")
+	require.Equal(t, orm.ErrReadOnly, errors.Cause(store.DeleteClientCertificate("cert")), "a read only node should not execute writes")
+	_, err = store.CreateLogConsumption(&models.LogConsumption{BlockHash: cltest.NewHash(), JobID: job.ID})
+	require.Equal(t, orm.ErrReadOnly, errors.Cause(err), "a read only node should not write with raw queries")
+	require.NoError(t, store.Jobs(func(*models.JobSpec) bool { return true }), "a read only node should keep reading")
+}
+
+func TestORM_SessionsWhenReadOnly(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	if store.Config.DatabaseURL() == "" {
+		t.Skip("No postgres DatabaseURL set.")
+	}
+
+	user := cltest.MustUser(cltest.APIEmail, cltest.Password)
+	require.NoError(t, store.SaveUser(&user))
+
+	connErr, dbErr := store.ORM.LockingStrategyHelperSimulateDisconnect()
+	require.NoError(t, connErr)
+	require.NoError(t, dbErr)
+	lock, err := orm.NewLockingStrategy("postgres", store.Config.DatabaseURL())
+	require.NoError(t, err)
+	defer lock.Unlock(store.Config.DatabaseTimeout())
+	require.NoError(t, lock.Lock(models.MustMakeDuration(1*time.Second)))
+	require.Equal(t, orm.ErrReadOnly, errors.Cause(store.ORM.CheckLock()))
+
+	// Operators can still log in and out, to find out why the node is read
+	// only
+	sessionID, err := store.CreateSession(models.SessionRequest{Email: cltest.APIEmail, Password: cltest.Password})
+	require.NoError(t, err)
+	_, err = store.AuthorizedUserWithSession(sessionID)
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteUserSession(sessionID))
+	_, err = store.AuthorizedUserWithSession(sessionID)
+	require.Error(t, err)
 }
 
 func TestPostgresLockingStrategy_WhenReacquiredOriginalNodeErrors(t *testing.T) {
@@ -141,6 +268,7 @@ func TestPostgresLockingStrategy_WhenReacquiredOriginalNodeErrors(t *testing.T)
 	require.NoError(t, err)
 	defer lock.Unlock(delay)
 
-	_ = store.ORM.RawDB(func(db *gorm.DB) error { return nil })
+	err = store.ORM.CheckLock()
+	require.Equal(t, orm.ErrReadOnly, errors.Cause(err))
 	gomega.NewGomegaWithT(t).Eventually(store.ORM.ShutdownSignal().Wait()).Should(gomega.BeClosed())
 }
@@ -1,22 +1,24 @@
 package orm
 
 import (
-	"github.com/smartcontractkit/chainlink/core/logger"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 type ormLogWrapper struct {
 	*zap.SugaredLogger
+	logStatements      bool
+	slowQueryThreshold time.Duration
+	recordSlowQuery    func(sql string, elapsed time.Duration)
 }
 
-func newOrmLogWrapper(logger *logger.Logger) *ormLogWrapper {
+func newOrmLogWrapper(logger *zap.SugaredLogger) *ormLogWrapper {
 	newLogger := logger.
-		SugaredLogger.
 		Desugar().
 		WithOptions(zap.AddCaller(), zap.AddCallerSkip(6)).
 		Sugar()
-	return &ormLogWrapper{newLogger}
+	return &ormLogWrapper{SugaredLogger: newLogger}
 }
 
 func (l *ormLogWrapper) Print(args ...interface{}) {
@@ -26,7 +28,16 @@ func (l *ormLogWrapper) Print(args ...interface{}) {
 	case "log":
 		l.Warn(args[2])
 	case "sql":
-		l.Debugw(args[3].(string), "time", args[2], "rows_affected", args[5])
+		if l.logStatements {
+			l.Debugw(args[3].(string), "time", args[2], "rows_affected", args[5])
+		}
+		elapsed, _ := args[2].(time.Duration)
+		if l.slowQueryThreshold > 0 && elapsed >= l.slowQueryThreshold {
+			l.Warnw("Slow query", "sql", args[3], "time", elapsed, "rows_affected", args[5])
+			if l.recordSlowQuery != nil {
+				l.recordSlowQuery(args[3].(string), elapsed)
+			}
+		}
 	default:
 		// Don't log these, only seems to be the callback logs which aren't super useful
 	}
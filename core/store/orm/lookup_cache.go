@@ -0,0 +1,116 @@
+package orm
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var promLookupCache = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "orm_lookup_cache",
+		Help: "The number of lookups of bridges, jobs, external initiators and client certificates answered from memory (hit) or the database (miss)",
+	},
+	[]string{"cache", "result"},
+)
+
+// lookupCache holds the records a lookup found, for at most its TTL, so that
+// the bridges, jobs and external initiators looked up for every run need not
+// be read from the database each time. The ORM invalidates it whenever it
+// changes a record it may hold; records which were not found are not held,
+// so creating one needs no invalidation. Marking an external initiator seen
+// or checking its health does not invalidate it either, so the times of
+// those held may be up to a TTL old.
+type lookupCache struct {
+	name string
+	ttl  time.Duration
+	lru  *lru.Cache
+
+	mutex sync.Mutex
+	// generation counts the invalidations, so that a record read before an
+	// invalidation is not held after it.
+	generation uint64
+}
+
+type lookupCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// newLookupCache returns a lookupCache holding the size most recently used
+// records for ttl, or nil, which holds nothing, if either is zero.
+func newLookupCache(name string, size int, ttl time.Duration) (*lookupCache, error) {
+	if size == 0 || ttl == 0 {
+		return nil, nil
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating %s cache", name)
+	}
+	return &lookupCache{name: name, ttl: ttl, lru: cache}, nil
+}
+
+// get returns the record held under key, if it has not expired, along with
+// the generation to pass to put the record read instead.
+func (c *lookupCache) get(key string) (interface{}, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mutex.Lock()
+	generation := c.generation
+	c.mutex.Unlock()
+
+	if value, ok := c.lru.Get(key); ok {
+		entry := value.(lookupCacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			promLookupCache.WithLabelValues(c.name, "hit").Inc()
+			return entry.value, generation, true
+		}
+		c.lru.Remove(key)
+	}
+	promLookupCache.WithLabelValues(c.name, "miss").Inc()
+	return nil, generation, false
+}
+
+// put holds a record read from the database, unless the cache was
+// invalidated since the read began.
+func (c *lookupCache) put(key string, value interface{}, generation uint64) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation == c.generation {
+		c.lru.Add(key, lookupCacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)})
+	}
+}
+
+// invalidate drops the records held under the keys, or all of them if no
+// key is given.
+func (c *lookupCache) invalidate(keys ...string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	if len(keys) == 0 {
+		c.lru.Purge()
+		return
+	}
+	for _, key := range keys {
+		c.lru.Remove(key)
+	}
+}
+
+// lookupCaches are the caches of the lookups of an ORM.
+type lookupCaches struct {
+	bridges            *lookupCache
+	jobs               *lookupCache
+	externalInitiators *lookupCache
+	clientCertificates *lookupCache
+}
@@ -0,0 +1,50 @@
+package orm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCache(t *testing.T) {
+	t.Parallel()
+
+	cache, err := newLookupCache("test", 2, time.Hour)
+	require.NoError(t, err)
+
+	_, generation, ok := cache.get("a")
+	assert.False(t, ok)
+	cache.put("a", 1, generation)
+	value, _, ok := cache.get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	_, generation, _ = cache.get("b")
+	cache.invalidate("a")
+	cache.put("b", 2, generation)
+	_, _, ok = cache.get("a")
+	assert.False(t, ok)
+	_, _, ok = cache.get("b")
+	assert.False(t, ok, "a record read before an invalidation should not be held")
+
+	cache.ttl = 0
+	_, generation, _ = cache.get("c")
+	cache.put("c", 3, generation)
+	_, _, ok = cache.get("c")
+	assert.False(t, ok, "an expired record should not be returned")
+}
+
+func TestLookupCache_Disabled(t *testing.T) {
+	t.Parallel()
+
+	cache, err := newLookupCache("test", 1000, 0)
+	require.NoError(t, err)
+	assert.Nil(t, cache)
+
+	cache.put("a", 1, 0)
+	_, _, ok := cache.get("a")
+	assert.False(t, ok)
+	cache.invalidate()
+}
@@ -1,13 +1,20 @@
 package orm
 
 import (
+	"crypto/sha256"
 	"crypto/subtle"
 	"database/sql"
 	"encoding"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -24,8 +31,11 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres" // http://doc.gorm.io/database.html#connecting-to-a-database
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
+	"github.com/tevino/abool"
 	"go.uber.org/multierr"
+	null "gopkg.in/guregu/null.v3"
 )
 
 // BatchSize is the safe number of records to cache during Batch calls for
@@ -36,6 +46,9 @@ const BatchSize = 100
 var (
 	// ErrorNotFound is returned when finding a single value fails.
 	ErrorNotFound = gorm.ErrRecordNotFound
+	// ErrorNonceUsed is returned when an external initiator signs a request
+	// with a nonce it used before.
+	ErrorNonceUsed = errors.New("nonce has already been used")
 )
 
 // DialectName is a compiler enforced type used that maps to gorm's dialect
@@ -55,6 +68,20 @@ type ORM struct {
 	dialectName         DialectName
 	closeOnce           sync.Once
 	shutdownSignal      gracefulpanic.Signal
+	runResultMaxSize    uint64
+	runResultPolicy     RunResultOversizePolicy
+	headsRetained       uint64
+	logger              *ormLogWrapper
+	caches              lookupCaches
+	bridgeSuccesses     *bridgeSuccessBatch
+	unscoped            bool
+	connectTimeout      time.Duration
+	clock               utils.AfterNower
+	reconnectMutex      sync.Mutex
+	reconnecting        abool.AtomicBool
+	readOnly            *abool.AtomicBool
+	done                chan struct{}
+	wg                  sync.WaitGroup
 }
 
 var (
@@ -62,49 +89,75 @@ var (
 	ErrReleaseLockFailed = errors.New("advisory lock release failed")
 )
 
+// DatabaseOptions are the ways the ORM connects to and locks the database.
+// The zero value takes an advisory lock.
+type DatabaseOptions struct {
+	// LockingStrategy is how other nodes are kept off the database.
+	LockingStrategy DatabaseLockingStrategy
+	// LeaseDuration is how long a lease taken with the lease locking strategy
+	// lasts unless renewed.
+	LeaseDuration time.Duration
+	// PgBouncerCompatible has the ORM work with a database behind a PgBouncer
+	// pooling transactions, taking a lease rather than an advisory lock,
+	// sending the parameters of statements along with them rather than
+	// preparing them first, and leaving the time zone of the session alone.
+	PgBouncerCompatible bool
+}
+
 // NewORM initializes a new database file at the configured uri.
-func NewORM(uri string, timeout models.Duration, shutdownSignal gracefulpanic.Signal) (*ORM, error) {
+func NewORM(uri string, timeout models.Duration, shutdownSignal gracefulpanic.Signal, options DatabaseOptions) (*ORM, error) {
 	dialect, err := DeduceDialect(uri)
 	if err != nil {
 		return nil, err
 	}
 
-	lockingStrategy, err := NewLockingStrategy(dialect, uri)
+	if options.PgBouncerCompatible {
+		uri, err = pgBouncerURL(uri)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var lockingStrategy LockingStrategy
+	if options.PgBouncerCompatible || options.LockingStrategy == DatabaseLockingLease {
+		lockingStrategy, err = NewLeaseLockingStrategy(uri, options.LeaseDuration)
+	} else {
+		lockingStrategy, err = NewLockingStrategy(dialect, uri)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create ORM lock")
 	}
 
-	logger.Infof("Locking %v for exclusive access with %v timeout", dialect, displayTimeout(timeout))
+	logger.ORM.Infof("Locking %v for exclusive access with %v timeout", dialect, displayTimeout(timeout))
 
 	orm := &ORM{
 		lockingStrategy:     lockingStrategy,
 		advisoryLockTimeout: timeout,
 		dialectName:         dialect,
 		shutdownSignal:      shutdownSignal,
+		logger:              newOrmLogWrapper(logger.ORM.Sugared()),
+		readOnly:            abool.New(),
+		bridgeSuccesses:     newBridgeSuccessBatch(),
+		clock:               utils.Clock{},
+		done:                make(chan struct{}),
+	}
+	if err := lockingStrategy.Lock(timeout); err != nil {
+		return nil, errors.Wrap(err, "unable to lock ORM")
 	}
-	orm.MustEnsureAdvisoryLock()
 
-	db, err := initializeDatabase(string(dialect), uri)
+	db, err := initializeDatabase(string(dialect), uri, orm.logger, !options.PgBouncerCompatible)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to init DB")
 	}
 
 	orm.db = db
+	orm.refuseWritesWhenReadOnly()
+	orm.wg.Add(2)
+	go orm.monitorLock()
+	go orm.flushBridgeSuccessesEvery()
 
 	return orm, nil
 }
 
-func (orm *ORM) MustEnsureAdvisoryLock() {
-	if orm.dialectName != DialectPostgres {
-		return
-	}
-	err := orm.lockingStrategy.Lock(orm.advisoryLockTimeout)
-	if err != nil {
-		logger.Errorf("unable to lock ORM: %v", err)
-		orm.shutdownSignal.Panic()
-	}
-}
-
 func displayTimeout(timeout models.Duration) string {
 	if timeout.IsInstant() {
 		return "indefinite"
@@ -112,14 +165,17 @@ func displayTimeout(timeout models.Duration) string {
 	return timeout.String()
 }
 
-func initializeDatabase(dialect, path string) (*gorm.DB, error) {
+func initializeDatabase(dialect, path string, logger *ormLogWrapper, setTimezone bool) (*gorm.DB, error) {
 	db, err := gorm.Open(dialect, path)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to open %s for gorm DB", path)
 	}
 
-	db.SetLogger(newOrmLogWrapper(logger.GetLogger()))
+	db.SetLogger(logger)
 
+	if !setTimezone {
+		return db, nil
+	}
 	if err := dbutil.SetTimezone(db); err != nil {
 		return nil, err
 	}
@@ -127,6 +183,21 @@ func initializeDatabase(dialect, path string) (*gorm.DB, error) {
 	return db, nil
 }
 
+// pgBouncerURL returns uri with lib/pq sending the parameters of statements
+// along with them. Otherwise it prepares statements in a round trip of their
+// own, after which a PgBouncer pooling transactions may send the statement to
+// another server connection, which does not have it prepared.
+func pgBouncerURL(uri string) (string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("binary_parameters", "yes")
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
 // DeduceDialect returns the appropriate dialect for the passed connection string.
 func DeduceDialect(path string) (DialectName, error) {
 	url, err := url.Parse(path)
@@ -152,15 +223,166 @@ func ignoreRecordNotFound(db *gorm.DB) error {
 	return merr
 }
 
+// Ping checks that the database can be reached.
+func (orm *ORM) Ping() error {
+	return orm.db.DB().Ping()
+}
+
+// OpenConnections returns the number of connections to the database, in use
+// or idle.
+func (orm *ORM) OpenConnections() int {
+	return orm.db.DB().Stats().OpenConnections
+}
+
+// AdvisoryLockHeld returns whether the ORM still holds its advisory lock.
+func (orm *ORM) AdvisoryLockHeld() (bool, error) {
+	if orm.dialectName != DialectPostgres {
+		return true, nil
+	}
+	return orm.lockingStrategy.Held(orm.advisoryLockTimeout)
+}
+
 // SetLogging turns on SQL statement logging
 func (orm *ORM) SetLogging(enabled bool) {
-	orm.db.LogMode(enabled)
+	orm.logger.logStatements = enabled
+	orm.db.LogMode(enabled || orm.logger.slowQueryThreshold > 0)
+}
+
+// SetSlowQueryThreshold has statements taking at least threshold logged and
+// recorded in slow_queries, without their parameters. At 0, they are not.
+func (orm *ORM) SetSlowQueryThreshold(threshold time.Duration) {
+	orm.logger.slowQueryThreshold = threshold
+	orm.logger.recordSlowQuery = orm.recordSlowQuery
+	orm.db.LogMode(orm.logger.logStatements || threshold > 0)
+}
+
+var (
+	sqlStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumericLiteral = regexp.MustCompile(`(^|[^\w$.])\d+(?:\.\d+)?`)
+)
+
+// NormalizeQuery returns the statement with its string and numeric literals
+// replaced by ?, so that the values of statements built by hand, which may be
+// secrets, are not recorded along with it.
+func NormalizeQuery(query string) string {
+	query = sqlStringLiteral.ReplaceAllString(query, "?")
+	return sqlNumericLiteral.ReplaceAllString(query, "${1}?")
+}
+
+// recordSlowQuery records a slow statement in slow_queries, normalized and
+// without its parameters. It goes around gorm, so that recording a statement
+// is never itself recorded. Nothing is recorded once the ORM is read only.
+func (orm *ORM) recordSlowQuery(query string, elapsed time.Duration) {
+	if orm.ReadOnly() {
+		return
+	}
+	_, err := orm.db.DB().Exec(`INSERT INTO slow_queries (query, duration_ms, created_at) VALUES ($1, $2, $3)`,
+		NormalizeQuery(query), float64(elapsed)/float64(time.Millisecond), time.Now())
+	if err != nil {
+		logger.ORM.Errorw("Unable to record slow query", "error", err)
+	}
+}
+
+// RequiredIndex is an index the queries of the node need to be fast.
+type RequiredIndex struct {
+	Name    string
+	Table   string
+	Columns []string
+	Unique  bool
+}
+
+// RequiredIndexes are the indexes EnsureIndexes makes sure exist.
+var RequiredIndexes = []RequiredIndex{
+	{Name: "idx_job_runs_status_updated_at", Table: "job_runs", Columns: []string{"status", "updated_at"}},
+	{Name: "idx_job_runs_job_spec_id", Table: "job_runs", Columns: []string{"job_spec_id"}},
+	{Name: "idx_job_runs_result_id", Table: "job_runs", Columns: []string{"result_id"}},
+	{Name: "idx_job_runs_run_request_id", Table: "job_runs", Columns: []string{"run_request_id"}},
+	{Name: "idx_task_runs_job_run_id", Table: "task_runs", Columns: []string{"job_run_id"}},
+	{Name: "idx_task_runs_result_id", Table: "task_runs", Columns: []string{"result_id"}},
+	{Name: "idx_txs_hash", Table: "txes", Columns: []string{"hash"}},
+	{Name: "idx_tx_attempts_hash", Table: "tx_attempts", Columns: []string{"hash"}},
+	{Name: "log_consumptions_unique_idx", Table: "log_consumptions", Columns: []string{"job_id", "block_hash", "log_index"}, Unique: true},
+	{Name: "log_consumptions_block_number_idx", Table: "log_consumptions", Columns: []string{"block_number"}},
+}
+
+// EnsureIndexes checks that each of RequiredIndexes exists, under any name,
+// and creates those which do not, returning them. An index on the same
+// columns which is partial, or invalid, does not count. Indexes are created
+// concurrently, so as not to lock their tables while the node starts, an
+// invalid one left by an interrupted creation being dropped first.
+func (orm *ORM) EnsureIndexes() ([]RequiredIndex, error) {
+	if orm.dialectName != DialectPostgres {
+		return nil, nil
+	}
+	var created []RequiredIndex
+	for _, index := range RequiredIndexes {
+		var exists bool
+		err := orm.db.Raw(`
+			SELECT EXISTS (
+				SELECT 1 FROM pg_index x
+				WHERE x.indrelid = ?::regclass
+				AND x.indpred IS NULL
+				AND x.indisvalid
+				AND (x.indisunique OR NOT ?)
+				AND ARRAY(
+					SELECT a.attname::text FROM unnest(x.indkey) WITH ORDINALITY AS k(attnum, n)
+					JOIN pg_attribute a ON a.attrelid = x.indrelid AND a.attnum = k.attnum
+					ORDER BY k.n
+				) = ?::text[]
+			)`, index.Table, index.Unique, pq.Array(index.Columns)).Row().Scan(&exists)
+		if err != nil {
+			return created, errors.Wrapf(err, "checking index %s", index.Name)
+		}
+		if exists {
+			continue
+		}
+
+		unique := ""
+		if index.Unique {
+			unique = "UNIQUE "
+		}
+		columns := make([]string, len(index.Columns))
+		for i, c := range index.Columns {
+			columns[i] = pq.QuoteIdentifier(c)
+		}
+		err = orm.exec(fmt.Sprintf(`DROP INDEX CONCURRENTLY IF EXISTS %s`, pq.QuoteIdentifier(index.Name))).Error
+		if err != nil {
+			return created, errors.Wrapf(err, "dropping invalid index %s", index.Name)
+		}
+		err = orm.exec(fmt.Sprintf(`CREATE %sINDEX CONCURRENTLY %s ON %s (%s)`,
+			unique, pq.QuoteIdentifier(index.Name), pq.QuoteIdentifier(index.Table), strings.Join(columns, ", "))).Error
+		if err != nil {
+			return created, errors.Wrapf(err, "creating index %s", index.Name)
+		}
+		created = append(created, index)
+	}
+	return created, nil
+}
+
+// SetRunResultLimit sets the most bytes of data a run result saved with a run
+// keeps, and what happens to the data of larger results. At 0, results are
+// not limited.
+func (orm *ORM) SetRunResultLimit(maxSize uint64, policy RunResultOversizePolicy) {
+	orm.runResultMaxSize = maxSize
+	orm.runResultPolicy = policy
+}
+
+// defaultHeadsRetained is how many heads are kept when no more are needed to
+// go back to the finalized head.
+const defaultHeadsRetained = 100
+
+// SetFinalityDepth has enough heads kept to go back to the finalized head,
+// the one finalityDepth blocks behind the latest.
+func (orm *ORM) SetFinalityDepth(finalityDepth uint64) {
+	orm.headsRetained = utils.MaxUint64(defaultHeadsRetained, finalityDepth+1)
 }
 
 // Close closes the underlying database connection.
 func (orm *ORM) Close() error {
 	var err error
 	orm.closeOnce.Do(func() {
+		close(orm.done)
+		orm.wg.Wait()
 		err = multierr.Combine(
 			orm.db.Close(),
 			orm.lockingStrategy.Unlock(orm.advisoryLockTimeout),
@@ -172,27 +394,94 @@ func (orm *ORM) Close() error {
 // Unscoped returns a new instance of this ORM that includes soft deleted items.
 func (orm *ORM) Unscoped() *ORM {
 	return &ORM{
-		db:              orm.db.Unscoped(),
-		lockingStrategy: orm.lockingStrategy,
+		db:               orm.db.Unscoped(),
+		lockingStrategy:  orm.lockingStrategy,
+		runResultMaxSize: orm.runResultMaxSize,
+		runResultPolicy:  orm.runResultPolicy,
+		headsRetained:    orm.headsRetained,
+		caches:           orm.caches,
+		bridgeSuccesses:  orm.bridgeSuccesses,
+		unscoped:         true,
+		readOnly:         orm.readOnly,
+	}
+}
+
+// withTx returns an instance of this ORM querying within the transaction. It
+// has no lookup caches, which must not hold records the transaction may roll
+// back.
+func (orm *ORM) withTx(dbtx *gorm.DB) *ORM {
+	return &ORM{
+		db:               dbtx,
+		lockingStrategy:  orm.lockingStrategy,
+		runResultMaxSize: orm.runResultMaxSize,
+		runResultPolicy:  orm.runResultPolicy,
+		headsRetained:    orm.headsRetained,
+		bridgeSuccesses:  orm.bridgeSuccesses,
+		unscoped:         orm.unscoped,
+		readOnly:         orm.readOnly,
+	}
+}
+
+// SetLookupCache has the bridges, jobs and external initiators looked up
+// held in memory, up to size of each, for ttl, along with the client
+// certificates. At 0, they are not.
+func (orm *ORM) SetLookupCache(size uint, ttl time.Duration) error {
+	bridges, err := newLookupCache("bridges", int(size), ttl)
+	if err != nil {
+		return err
+	}
+	jobs, err := newLookupCache("jobs", int(size), ttl)
+	if err != nil {
+		return err
+	}
+	externalInitiators, err := newLookupCache("external_initiators", int(size), ttl)
+	if err != nil {
+		return err
+	}
+	clientCertificates, err := newLookupCache("client_certificates", int(size), ttl)
+	if err != nil {
+		return err
+	}
+	orm.caches = lookupCaches{
+		bridges:            bridges,
+		jobs:               jobs,
+		externalInitiators: externalInitiators,
+		clientCertificates: clientCertificates,
+	}
+	return nil
+}
+
+// lookupCache returns c, or nil if the ORM is unscoped, as the records held
+// are those a scoped lookup finds.
+func (orm *ORM) lookupCache(c *lookupCache) *lookupCache {
+	if orm.unscoped {
+		return nil
 	}
+	return c
 }
 
 // Where fetches multiple objects with "Find".
 func (orm *ORM) Where(field string, value interface{}, instance interface{}) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.db.Where(fmt.Sprintf("%v = ?", field), value).Find(instance).Error
 }
 
 // FindBridge looks up a Bridge by its Name.
 func (orm *ORM) FindBridge(name models.TaskType) (models.BridgeType, error) {
-	orm.MustEnsureAdvisoryLock()
+	cache := orm.lookupCache(orm.caches.bridges)
+	cached, generation, ok := cache.get(name.String())
+	if ok {
+		return cached.(models.BridgeType), nil
+	}
 	var bt models.BridgeType
-	return bt, orm.db.First(&bt, "name = ?", name.String()).Error
+	if err := orm.db.First(&bt, "name = ?", name.String()).Error; err != nil {
+		return bt, err
+	}
+	cache.put(name.String(), bt, generation)
+	return bt, nil
 }
 
 // FindBridgesByNames finds multiple bridges by their names.
 func (orm *ORM) FindBridgesByNames(names []string) ([]models.BridgeType, error) {
-	orm.MustEnsureAdvisoryLock()
 	var bt []models.BridgeType
 	if err := orm.db.Where("name IN (?)", names).Find(&bt).Error; err != nil {
 		return nil, err
@@ -206,7 +495,6 @@ func (orm *ORM) FindBridgesByNames(names []string) ([]models.BridgeType, error)
 // PendingBridgeType returns the bridge type of the current pending task,
 // or error if not pending bridge.
 func (orm *ORM) PendingBridgeType(jr models.JobRun) (models.BridgeType, error) {
-	orm.MustEnsureAdvisoryLock()
 	nextTask := jr.NextTaskRun()
 	if nextTask == nil {
 		return models.BridgeType{}, errors.New("Cannot find the pending bridge type of a job run with no unfinished tasks")
@@ -216,14 +504,29 @@ func (orm *ORM) PendingBridgeType(jr models.JobRun) (models.BridgeType, error) {
 
 // FindJob looks up a Job by its ID.
 func (orm *ORM) FindJob(id *models.ID) (models.JobSpec, error) {
-	orm.MustEnsureAdvisoryLock()
+	cache := orm.lookupCache(orm.caches.jobs)
+	cached, generation, ok := cache.get(id.String())
+	if ok {
+		return copyJob(cached.(models.JobSpec)), nil
+	}
 	var job models.JobSpec
-	return job, orm.preloadJobs().First(&job, "id = ?", id).Error
+	if err := orm.preloadJobs().First(&job, "id = ?", id).Error; err != nil {
+		return job, err
+	}
+	cache.put(id.String(), copyJob(job), generation)
+	return job, nil
+}
+
+// copyJob returns a copy of the job with its own initiators and tasks, so
+// that changing those of a job found does not change the one held in cache.
+func copyJob(job models.JobSpec) models.JobSpec {
+	job.Initiators = append([]models.Initiator(nil), job.Initiators...)
+	job.Tasks = append([]models.TaskSpec(nil), job.Tasks...)
+	return job
 }
 
 // FindInitiator returns the single initiator defined by the passed ID.
 func (orm *ORM) FindInitiator(ID uint32) (models.Initiator, error) {
-	orm.MustEnsureAdvisoryLock()
 	initr := models.Initiator{}
 	return initr, orm.db.
 		Set("gorm:auto_preload", true).
@@ -231,13 +534,31 @@ func (orm *ORM) FindInitiator(ID uint32) (models.Initiator, error) {
 }
 
 func (orm *ORM) preloadJobs() *gorm.DB {
-	return orm.db.
+	return preloadJobAssociations(orm.db)
+}
+
+// preloadJobAssociations loads the initiators and tasks of a job, including
+// those soft deleted when the job was archived but leaving out those replaced
+// by a later version of the job.
+func preloadJobAssociations(db *gorm.DB) *gorm.DB {
+	return db.
 		Preload("Initiators", func(db *gorm.DB) *gorm.DB {
-			return db.Unscoped().Order(`"id" asc`)
+			return db.Unscoped().
+				Where(`initiators.deleted_at IS NULL OR NOT EXISTS (
+					SELECT 1 FROM job_spec_versions
+					WHERE job_spec_versions.job_spec_id = CAST(initiators.job_spec_id AS uuid)
+					AND job_spec_versions.created_at >= initiators.deleted_at)`).
+				Order(`"id" asc`)
 		}).
 		Preload("Tasks", func(db *gorm.DB) *gorm.DB {
-			return db.Unscoped().Order("id asc")
-		})
+			return db.Unscoped().
+				Where(`task_specs.deleted_at IS NULL OR NOT EXISTS (
+					SELECT 1 FROM job_spec_versions
+					WHERE job_spec_versions.job_spec_id = CAST(task_specs.job_spec_id AS uuid)
+					AND job_spec_versions.created_at >= task_specs.deleted_at)`).
+				Order("id asc")
+		}).
+		Preload("Tasks.Edges", orderTaskSpecEdges)
 }
 
 func preloadTaskRuns(db *gorm.DB) *gorm.DB {
@@ -245,11 +566,22 @@ func preloadTaskRuns(db *gorm.DB) *gorm.DB {
 		Preload("Result").
 		Preload("TaskSpec", func(db *gorm.DB) *gorm.DB {
 			return db.Unscoped()
-		})
+		}).
+		Preload("TaskSpec.Edges", orderTaskSpecEdges)
+}
+
+// orderTaskSpecEdges keeps the inputs of tasks in the order they were
+// declared in.
+func orderTaskSpecEdges(db *gorm.DB) *gorm.DB {
+	return db.Order("id asc")
 }
 
 func (orm *ORM) preloadJobRuns() *gorm.DB {
-	return orm.db.
+	return preloadJobRunsOn(orm.db)
+}
+
+func preloadJobRunsOn(db *gorm.DB) *gorm.DB {
+	return db.
 		Preload("Initiator", func(db *gorm.DB) *gorm.DB {
 			return db.Unscoped()
 		}).
@@ -262,15 +594,40 @@ func (orm *ORM) preloadJobRuns() *gorm.DB {
 
 // FindJobRun looks up a JobRun by its ID.
 func (orm *ORM) FindJobRun(id *models.ID) (models.JobRun, error) {
-	orm.MustEnsureAdvisoryLock()
+	runs, err := orm.loadJobRuns("job_runs.id = ? LIMIT 1", id)
+	if err != nil {
+		return models.JobRun{}, err
+	} else if len(runs) == 0 {
+		return models.JobRun{}, gorm.ErrRecordNotFound
+	}
+	return runs[0], nil
+}
+
+// FindJobRunByIdempotencyKey looks up the JobRun of the job whose run
+// request had the given idempotency key, even if the run was deleted, as its
+// key still cannot be used again.
+func (orm *ORM) FindJobRunByIdempotencyKey(jobSpecID *models.ID, key string) (models.JobRun, error) {
+	var jr models.JobRun
+	err := orm.preloadJobRuns().
+		Unscoped().
+		Joins("JOIN run_requests ON run_requests.id = job_runs.run_request_id").
+		First(&jr, "run_requests.job_spec_id = ? AND run_requests.idempotency_key = ?", jobSpecID, key).Error
+	return jr, err
+}
+
+// FindJobRunByLog looks up the JobRun of the job created for the log at
+// logIndex in the block with blockHash.
+func (orm *ORM) FindJobRunByLog(jobSpecID *models.ID, blockHash common.Hash, logIndex uint) (models.JobRun, error) {
 	var jr models.JobRun
-	err := orm.preloadJobRuns().First(&jr, "id = ?", id).Error
+	err := orm.preloadJobRuns().
+		Unscoped().
+		Joins("JOIN run_requests ON run_requests.id = job_runs.run_request_id").
+		First(&jr, "run_requests.job_spec_id = ? AND run_requests.block_hash = ? AND run_requests.log_index = ?", jobSpecID, blockHash, logIndex).Error
 	return jr, err
 }
 
 // AllSyncEvents returns all sync events
 func (orm *ORM) AllSyncEvents(cb func(*models.SyncEvent) error) error {
-	orm.MustEnsureAdvisoryLock()
 	return Batch(BatchSize, func(offset, limit uint) (uint, error) {
 		var events []models.SyncEvent
 		err := orm.db.
@@ -293,13 +650,114 @@ func (orm *ORM) AllSyncEvents(cb func(*models.SyncEvent) error) error {
 	})
 }
 
+// SyncEventsAfter returns up to limit sync events following the cursor, in
+// the order of the transactions which created them. Only the events created
+// before every transaction still in progress are returned, so that an event
+// committed after those following it is not skipped.
+func (orm *ORM) SyncEventsAfter(cursor models.SyncEventCursor, limit uint) ([]models.SyncEvent, error) {
+	var events []models.SyncEvent
+	err := orm.db.
+		Where("(txid, id) > (?, ?)", cursor.LastTxID, cursor.LastID).
+		Where("txid < txid_snapshot_xmin(txid_current_snapshot())").
+		Order("txid asc, id asc").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// CountSyncEventsAfter counts the sync events following the cursor.
+func (orm *ORM) CountSyncEventsAfter(cursor models.SyncEventCursor) (int, error) {
+	var count int
+	err := orm.db.Model(&models.SyncEvent{}).
+		Where("(txid, id) > (?, ?)", cursor.LastTxID, cursor.LastID).
+		Count(&count).Error
+	return count, err
+}
+
+// RegisterSyncEventConsumer keeps the sync events from being deleted until
+// the consumer has consumed them, if it is not registered already.
+func (orm *ORM) RegisterSyncEventConsumer(consumer string) error {
+	return orm.exec(`
+		INSERT INTO sync_event_cursors (consumer, last_id, updated_at)
+		VALUES (?, 0, NOW())
+		ON CONFLICT (consumer) DO NOTHING`, consumer).Error
+}
+
+// RemoveSyncEventConsumer forgets the consumer, so that the sync events it
+// has not consumed can be deleted once consumed by the others.
+func (orm *ORM) RemoveSyncEventConsumer(consumer string) error {
+	return orm.exec(`DELETE FROM sync_event_cursors WHERE consumer = ?`, consumer).Error
+}
+
+// SyncEventCursor returns the last sync event consumed by the consumer, or
+// the zero cursor if it has consumed none.
+func (orm *ORM) SyncEventCursor(consumer string) (models.SyncEventCursor, error) {
+	var cursor models.SyncEventCursor
+	err := orm.db.First(&cursor, "consumer = ?", consumer).Error
+	if err == ErrorNotFound {
+		return models.SyncEventCursor{Consumer: consumer}, nil
+	}
+	return cursor, err
+}
+
+// AdvanceSyncEventCursor records that the consumer has consumed the sync
+// events up to last, and deletes the sync events every consumer has
+// consumed.
+func (orm *ORM) AdvanceSyncEventCursor(consumer string, last models.SyncEvent) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		err := dbtx.Exec(`
+			INSERT INTO sync_event_cursors (consumer, last_txid, last_id, updated_at)
+			VALUES (?, ?, ?, NOW())
+			ON CONFLICT (consumer) DO UPDATE SET
+			last_txid = EXCLUDED.last_txid,
+			last_id = EXCLUDED.last_id,
+			updated_at = EXCLUDED.updated_at
+			WHERE (sync_event_cursors.last_txid, sync_event_cursors.last_id) < (EXCLUDED.last_txid, EXCLUDED.last_id)`,
+			consumer, last.TxID, last.ID).Error
+		if err != nil {
+			return errors.Wrap(err, "advancing sync event cursor")
+		}
+		return dbtx.Exec(`
+			DELETE FROM sync_events
+			WHERE NOT EXISTS (
+				SELECT 1 FROM sync_event_cursors
+				WHERE (sync_event_cursors.last_txid, sync_event_cursors.last_id) < (sync_events.txid, sync_events.id)
+			)`).Error
+	})
+}
+
+// BlockCursor returns the last block the consumer fetched the logs of, or 0
+// if it has fetched none.
+func (orm *ORM) BlockCursor(consumer string) (uint64, error) {
+	var cursor struct{ LastBlock uint64 }
+	err := orm.db.Raw(`SELECT last_block FROM block_cursors WHERE consumer = ?`, consumer).Scan(&cursor).Error
+	if err == ErrorNotFound {
+		return 0, nil
+	}
+	return cursor.LastBlock, err
+}
+
+// AdvanceBlockCursor records that the consumer has fetched the logs of the
+// blocks up to last.
+func (orm *ORM) AdvanceBlockCursor(consumer string, last uint64) error {
+	return orm.exec(`
+		INSERT INTO block_cursors (consumer, last_block, updated_at)
+		VALUES (?, ?, NOW())
+		ON CONFLICT (consumer) DO UPDATE SET
+		last_block = EXCLUDED.last_block,
+		updated_at = EXCLUDED.updated_at`,
+		consumer, last).Error
+}
+
 // convenientTransaction handles setup and teardown for a gorm database
 // transaction, handing off the database transaction to the callback parameter.
 // Encourages the use of transactions for gorm calls that translate
 // into multiple sql calls, i.e. orm.SaveJobRun(run), which are better suited
 // in a database transaction.
 func (orm *ORM) convenientTransaction(callback func(*gorm.DB) error) error {
-	orm.MustEnsureAdvisoryLock()
+	if orm.ReadOnly() {
+		return ErrReadOnly
+	}
 	dbtx := orm.db.Begin()
 	if dbtx.Error != nil {
 		return dbtx.Error
@@ -321,745 +779,3196 @@ var OptimisticUpdateConflictError = errors.New("conflict while updating record")
 
 // SaveJobRun updates UpdatedAt for a JobRun and saves it
 func (orm *ORM) SaveJobRun(run *models.JobRun) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
-		result := dbtx.Unscoped().
-			Model(run).
-			Where("updated_at = ?", run.UpdatedAt).
-			Omit("deleted_at").
-			Save(run)
-		if result.Error != nil {
-			return result.Error
-		}
-		if result.RowsAffected == 0 {
-			return OptimisticUpdateConflictError
-		}
-		return nil
+		return orm.saveJobRun(dbtx, run)
 	})
 }
 
-// CreateJobRun inserts a new JobRun
-func (orm *ORM) CreateJobRun(run *models.JobRun) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Create(run).Error
+// SaveJobRunResumedManually saves a run resumed with a result supplied by an
+// operator and records its resumption in the audit log, both or neither.
+func (orm *ORM) SaveJobRunResumedManually(run *models.JobRun, resumption *models.RunResumption) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		if err := orm.saveJobRun(dbtx, run); err != nil {
+			return err
+		}
+		return dbtx.Create(resumption).Error
+	})
 }
 
-// LinkEarnedFor shows the total link earnings for a job
-func (orm *ORM) LinkEarnedFor(spec *models.JobSpec) (*assets.Link, error) {
-	orm.MustEnsureAdvisoryLock()
-	var earned *assets.Link
-	query := orm.db.Table("job_runs").
-		Joins("JOIN job_specs ON job_runs.job_spec_id = job_specs.id").
-		Where("job_specs.id = ? AND job_runs.status = ? AND job_runs.finished_at IS NOT NULL", spec.ID, models.RunStatusCompleted)
-
-	if dbutil.IsPostgres(orm.db) {
-		query = query.Select("SUM(payment)")
-	} else {
-		query = query.Select("CAST(SUM(CAST(SUBSTR(payment, 1, 10) as BIGINT)) as varchar(255))")
-	}
-
-	err := query.Row().Scan(&earned)
+func (orm *ORM) saveJobRun(dbtx *gorm.DB, run *models.JobRun) error {
+	restore, err := orm.limitRunResults(dbtx, run)
+	defer restore()
 	if err != nil {
-		return nil, errors.Wrap(err, "error obtaining link earned from job_runs")
+		return err
 	}
-	return earned, nil
+	result := dbtx.Unscoped().
+		Model(run).
+		Where("updated_at = ?", run.UpdatedAt).
+		Omit("deleted_at").
+		Save(run)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return OptimisticUpdateConflictError
+	}
+	return nil
 }
 
-// CreateExternalInitiator inserts a new external initiator
-func (orm *ORM) CreateExternalInitiator(externalInitiator *models.ExternalInitiator) error {
-	orm.MustEnsureAdvisoryLock()
-	err := orm.db.Create(externalInitiator).Error
-	return err
-}
+// runRequestsLogIndex is the unique index of the run requests made by logs.
+const runRequestsLogIndex = "idx_run_requests_job_spec_id_block_hash_log_index"
 
-// DeleteExternalInitiator removes an external initiator
-func (orm *ORM) DeleteExternalInitiator(name string) error {
-	orm.MustEnsureAdvisoryLock()
-	err := orm.db.Delete(&models.ExternalInitiator{Name: name}).Error
-	return err
-}
+// CreateJobRun inserts a new JobRun. If a run of the job was created for the
+// same log already, as happens when a log is delivered again after a
+// reconnect, the run is set to the existing run instead.
+func (orm *ORM) CreateJobRun(run *models.JobRun) error {
+	err := orm.createJobRun(run)
+	if pqErr, ok := errors.Cause(err).(*pq.Error); !ok || pqErr.Constraint != runRequestsLogIndex {
+		return err
+	}
 
-// FindExternalInitiator finds an external initiator given an authentication request
-func (orm *ORM) FindExternalInitiator(
-	eia *auth.Token,
-) (*models.ExternalInitiator, error) {
-	orm.MustEnsureAdvisoryLock()
-	initiator := &models.ExternalInitiator{}
-	err := orm.db.Where("access_key = ?", eia.AccessKey).Find(initiator).Error
+	rr := run.RunRequest
+	existing, err := orm.FindJobRunByLog(rr.JobSpecID, *rr.BlockHash, uint(rr.LogIndex.Int64))
 	if err != nil {
-		return nil, errors.Wrap(err, "error finding external initiator")
+		return errors.Wrap(err, "finding the run already created for the log")
 	}
-
-	return initiator, nil
+	*run = existing
+	return nil
 }
 
-// FindExternalInitiatorByName finds an external initiator given an authentication request
-func (orm *ORM) FindExternalInitiatorByName(iname string) (models.ExternalInitiator, error) {
-	orm.MustEnsureAdvisoryLock()
-	var exi models.ExternalInitiator
-	return exi, orm.db.First(&exi, "lower(name) = lower(?)", iname).Error
+func (orm *ORM) createJobRun(run *models.JobRun) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		restore, err := orm.limitRunResults(dbtx, run)
+		defer restore()
+		if err != nil {
+			return err
+		}
+		return dbtx.Create(run).Error
+	})
 }
 
-// FindServiceAgreement looks up a ServiceAgreement by its ID.
-func (orm *ORM) FindServiceAgreement(id string) (models.ServiceAgreement, error) {
-	orm.MustEnsureAdvisoryLock()
-	var sa models.ServiceAgreement
-	return sa, orm.db.Set("gorm:auto_preload", true).First(&sa, "id = ?", id).Error
-}
+// limitRunResults replaces the data of the results of run larger than the
+// limit with truncation markers, keeping it in run_result_blobs unless the
+// policy is to truncate. The returned function puts the data back, so that
+// the run carries on with all of it once saved.
+func (orm *ORM) limitRunResults(dbtx *gorm.DB, run *models.JobRun) (func(), error) {
+	var truncated []*models.RunResult
+	var originals []models.JSON
+	restore := func() {
+		for i, rr := range truncated {
+			rr.Data = originals[i]
+		}
+	}
+	if orm.runResultMaxSize == 0 {
+		return restore, nil
+	}
 
-// Jobs fetches all jobs.
-func (orm *ORM) Jobs(cb func(*models.JobSpec) bool, initrTypes ...string) error {
-	orm.MustEnsureAdvisoryLock()
-	return Batch(BatchSize, func(offset, limit uint) (uint, error) {
-		scope := orm.db.Limit(limit).Offset(offset)
-		if len(initrTypes) > 0 {
-			scope = scope.Where("initiators.type IN (?)", initrTypes)
-			if dbutil.IsPostgres(orm.db) {
-				scope = scope.Joins("JOIN initiators ON job_specs.id = initiators.job_spec_id::uuid")
-			} else {
-				scope = scope.Joins("JOIN initiators ON job_specs.id = initiators.job_spec_id")
+	results := []*models.RunResult{&run.Result}
+	for i := range run.TaskRuns {
+		results = append(results, &run.TaskRuns[i].Result)
+	}
+	for _, rr := range results {
+		if uint64(len(rr.Data.String())) <= orm.runResultMaxSize {
+			if _, ok, _ := rr.Truncation(); !ok {
+				rr.BlobHash = null.String{}
 			}
+			continue
 		}
-		var ids []string
-		err := scope.Table("job_specs").Pluck("job_specs.id", &ids).Error
+
+		blob := models.NewRunResultBlob(rr.Data)
+		stored := orm.runResultPolicy != RunResultOversizeTruncate
+		if stored {
+			err := dbtx.Exec(`
+				INSERT INTO run_result_blobs (hash, data, size, created_at)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT (hash) DO NOTHING`,
+				blob.Hash, blob.Data, blob.Size, time.Now()).Error
+			if err != nil {
+				return restore, errors.Wrap(err, "error storing oversized run result")
+			}
+			rr.BlobHash = null.StringFrom(blob.Hash)
+		} else {
+			rr.BlobHash = null.String{}
+		}
+		marker, err := blob.Truncation(stored)
 		if err != nil {
-			return 0, err
+			return restore, err
 		}
+		logger.ORM.Warnw("Run result exceeds RUN_RESULT_MAX_SIZE, truncating it",
+			"run", run.ID.String(), "size", blob.Size, "hash", blob.Hash, "stored", stored)
+		truncated = append(truncated, rr)
+		originals = append(originals, rr.Data)
+		rr.Data = marker
+	}
+	return restore, nil
+}
 
-		if len(ids) == 0 {
-			return 0, nil
+// LoadRunResultBlobs puts back the data of the results of run which were
+// truncated for exceeding RUN_RESULT_MAX_SIZE and kept in run_result_blobs,
+// so that the run can be executed with all of it.
+func (orm *ORM) LoadRunResultBlobs(run *models.JobRun) error {
+	results := []*models.RunResult{&run.Result}
+	for i := range run.TaskRuns {
+		results = append(results, &run.TaskRuns[i].Result)
+	}
+
+	pending := map[string][]*models.RunResult{}
+	var hashes []string
+	for _, rr := range results {
+		t, ok, err := rr.Truncation()
+		if err != nil {
+			return err
+		}
+		if !ok || !t.Stored {
+			continue
 		}
+		if _, seen := pending[t.Hash]; !seen {
+			hashes = append(hashes, t.Hash)
+		}
+		pending[t.Hash] = append(pending[t.Hash], rr)
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
 
-		jobs := []models.JobSpec{}
-		err = orm.preloadJobs().Unscoped().Find(&jobs, "id IN (?)", ids).Error
+	var blobs []models.RunResultBlob
+	if err := orm.db.Where("hash IN (?)", hashes).Find(&blobs).Error; err != nil {
+		return errors.Wrap(err, "error loading oversized run results")
+	}
+	for _, blob := range blobs {
+		data, err := models.ParseJSON([]byte(blob.Data))
 		if err != nil {
-			return 0, err
+			return errors.Wrapf(err, "invalid run result blob %s", blob.Hash)
 		}
-		for _, j := range jobs {
-			temp := j
-			if temp.DeletedAt.Valid {
-				continue
-			}
-			if !cb(&temp) {
-				return 0, nil
-			}
+		for _, rr := range pending[blob.Hash] {
+			rr.Data = data
 		}
+		delete(pending, blob.Hash)
+	}
+	for hash := range pending {
+		return fmt.Errorf("run result blob %s not found", hash)
+	}
+	return nil
+}
 
-		return uint(len(jobs)), nil
-	})
+// RunResultsToArchive returns up to limit results, in order of ID, of the
+// runs which finished before the given time and whose data has not been
+// archived yet.
+func (orm *ORM) RunResultsToArchive(before time.Time, limit uint) ([]models.RunResult, error) {
+	var results []models.RunResult
+	err := orm.db.
+		Where(`archived_in IS NULL AND id IN (
+			WITH finished AS (
+				SELECT id, result_id FROM job_runs WHERE status IN (?) AND finished_at < ?
+			)
+			SELECT result_id FROM finished
+			UNION
+			SELECT task_runs.result_id FROM task_runs JOIN finished ON task_runs.job_run_id = finished.id
+		)`, []models.RunStatus{models.RunStatusCompleted, models.RunStatusErrored, models.RunStatusCancelled}, before).
+		Order("id asc").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
 }
 
-// JobRunsFor fetches all JobRuns with a given Job ID,
-// sorted by their created at time.
-func (orm *ORM) JobRunsFor(jobSpecID *models.ID, limit ...int) ([]models.JobRun, error) {
-	orm.MustEnsureAdvisoryLock()
-	runs := []models.JobRun{}
-	var lim int
-	if len(limit) == 0 {
-		lim = 100
-	} else if len(limit) >= 1 {
-		lim = limit[0]
+// ArchiveRunResults replaces the data of the results with stubs pointing at
+// the archive file it was moved to.
+func (orm *ORM) ArchiveRunResults(ids []uint32, file string) error {
+	stub, err := models.ArchivedRunResultData(file)
+	if err != nil {
+		return err
 	}
-	err := orm.preloadJobRuns().
-		Limit(lim).
-		Where("job_spec_id = ?", jobSpecID).
-		Order("created_at desc").
-		Find(&runs).Error
-	return runs, err
+	return orm.db.Model(&models.RunResult{}).
+		Where("id IN (?) AND archived_in IS NULL", ids).
+		Updates(map[string]interface{}{"data": stub, "archived_in": file, "updated_at": time.Now()}).Error
 }
 
-// JobRunsCountFor returns the current number of runs for the job
-func (orm *ORM) JobRunsCountFor(jobSpecID *models.ID) (int, error) {
-	orm.MustEnsureAdvisoryLock()
-	var count int
-	err := orm.db.
-		Model(&models.JobRun{}).
-		Where("job_spec_id = ?", jobSpecID).
-		Count(&count).Error
-	return count, err
+// LastJobRunCreatedAtFor returns the creation time of the most recent JobRun
+// triggered by the given initiator, including soft deleted runs, or nil if the
+// initiator has never fired.
+func (orm *ORM) LastJobRunCreatedAtFor(initiatorID uint32) (*time.Time, error) {
+	var run models.JobRun
+	err := orm.db.Unscoped().
+		Select("created_at").
+		Where("initiator_id = ?", initiatorID).
+		Order("created_at desc").
+		First(&run).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run.CreatedAt, nil
 }
 
-// Sessions returns all sessions limited by the parameters.
-func (orm *ORM) Sessions(offset, limit int) ([]models.Session, error) {
-	orm.MustEnsureAdvisoryLock()
-	var sessions []models.Session
-	err := orm.db.
-		Set("gorm:auto_preload", true).
-		Limit(limit).
-		Offset(offset).
-		Find(&sessions).Error
-	return sessions, err
+// LinkEarnedFor shows the total link earnings for a job. On Postgres, the
+// earnings of the hours rolled up for good are taken from the run stats
+// rollups, so that only the runs which finished since are summed.
+func (orm *ORM) LinkEarnedFor(spec *models.JobSpec) (*assets.Link, error) {
+	var earned *assets.Link
+	if !dbutil.IsPostgres(orm.db) {
+		err := orm.db.Table("job_runs").
+			Joins("JOIN job_specs ON job_runs.job_spec_id = job_specs.id").
+			Where("job_specs.id = ? AND job_runs.status = ? AND job_runs.finished_at IS NOT NULL", spec.ID, models.RunStatusCompleted).
+			Select("CAST(SUM(CAST(SUBSTR(payment, 1, 10) as BIGINT)) as varchar(255))").
+			Row().Scan(&earned)
+		if err != nil {
+			return nil, errors.Wrap(err, "error obtaining link earned from job_runs")
+		}
+		return earned, nil
+	}
+
+	// The hour before the latest one rolled up is rolled up again, for the
+	// runs which finished in it but were saved after it ended.
+	rolledUp := time.Unix(0, 0)
+	latest, ok, err := orm.LatestRunStatsBucket()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		rolledUp = latest.Add(-time.Hour)
+	}
+	err = orm.db.Raw(`
+		SELECT SUM(earned) FROM (
+			SELECT SUM(link_earned) AS earned FROM job_run_stats
+			WHERE period = ? AND job_spec_id = ? AND bucket < ? AND link_earned > 0
+			UNION ALL
+			SELECT SUM(payment) FROM job_runs
+			WHERE job_spec_id = ? AND status = ? AND finished_at >= ?
+		) AS earnings
+	`, models.RunStatsHourly, spec.ID, rolledUp, spec.ID, models.RunStatusCompleted, rolledUp).Row().Scan(&earned)
+	if err != nil {
+		return nil, errors.Wrap(err, "error obtaining link earned from job_run_stats and job_runs")
+	}
+	return earned, nil
 }
 
-// GetConfigValue returns the value for a named configuration entry
-func (orm *ORM) GetConfigValue(field string, value encoding.TextUnmarshaler) error {
-	orm.MustEnsureAdvisoryLock()
-	name := EnvVarName(field)
-	config := models.Configuration{}
-	if err := orm.db.First(&config, "name = ?", name).Error; err != nil {
-		return err
+// Earnings accounts for the LINK earned by the runs which completed over the
+// time range of the query, and for the gas their transactions cost, per job
+// or per requester, most earned first. Runs are tied to their transactions by
+// the transactions' surrogate IDs. Transactions whose gas used was not
+// recorded are accounted for at their gas limit. Earnings are aggregated from
+// the runs rather than the run stats rollups, which hold neither requesters
+// nor gas costs, and whose hours the time range need not fall on.
+func (orm *ORM) Earnings(query models.EarningsQuery) ([]models.Earnings, error) {
+	var key string
+	switch query.GroupBy {
+	case models.EarningsByJob:
+		key = "job_runs.job_spec_id::text"
+	case models.EarningsByRequester:
+		key = "encode(run_requests.requester, 'hex')"
+	default:
+		return nil, fmt.Errorf("cannot group earnings by %s", query.GroupBy)
 	}
-	return value.UnmarshalText([]byte(config.Value))
+
+	rows, err := orm.db.Raw(`
+		SELECT `+key+` AS key, COUNT(*),
+			COALESCE(SUM(job_runs.payment), 0)::text,
+			COALESCE(SUM(gas.cost), 0)::text
+		FROM job_runs
+		LEFT JOIN run_requests ON run_requests.id = job_runs.run_request_id
+		LEFT JOIN (
+			SELECT txes.surrogate_id, SUM(COALESCE(
+				tx_attempts.gas_used * tx_attempts.effective_gas_price::numeric,
+				txes.gas_limit * tx_attempts.gas_price::numeric
+			)) AS cost
+			FROM txes
+			JOIN tx_attempts ON tx_attempts.tx_id = txes.id AND tx_attempts.confirmed
+			WHERE txes.surrogate_id IS NOT NULL
+			GROUP BY txes.surrogate_id
+		) gas ON gas.surrogate_id = replace(job_runs.id::text, '-', '')
+		WHERE job_runs.status = ? AND job_runs.finished_at >= ? AND job_runs.finished_at < ?
+		GROUP BY key
+		ORDER BY COALESCE(SUM(job_runs.payment), 0) DESC, key
+	`, models.RunStatusCompleted, query.From, query.To).Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "while accounting for earnings")
+	}
+	defer rows.Close()
+
+	earnings := []models.Earnings{}
+	for rows.Next() {
+		var key sql.NullString
+		var earned, gasCost string
+		e := models.Earnings{Earned: assets.NewLink(0), GasCost: assets.NewEth(0)}
+		if err := rows.Scan(&key, &e.Runs, &earned, &gasCost); err != nil {
+			return nil, errors.Wrap(err, "while accounting for earnings")
+		}
+		if _, ok := e.Earned.SetString(earned, 10); !ok {
+			return nil, fmt.Errorf("invalid LINK earned %s", earned)
+		}
+		if _, ok := e.GasCost.SetString(gasCost, 10); !ok {
+			return nil, fmt.Errorf("invalid gas cost %s", gasCost)
+		}
+		if key.Valid && query.GroupBy == models.EarningsByJob {
+			if e.JobSpecID, err = models.NewIDFromString(key.String); err != nil {
+				return nil, err
+			}
+		} else if key.Valid {
+			requester := common.HexToAddress(key.String)
+			e.Requester = &requester
+		}
+		earnings = append(earnings, e)
+	}
+	return earnings, rows.Err()
 }
 
-// SetConfigValue returns the value for a named configuration entry
-func (orm *ORM) SetConfigValue(field string, value encoding.TextMarshaler) error {
-	orm.MustEnsureAdvisoryLock()
-	name := EnvVarName(field)
-	textValue, err := value.MarshalText()
+// GasUsage accounts for the gas used by the safe transactions whose gas was
+// recorded over the time range of the query, and for the ETH it cost, per
+// job or per run, most costly first. Runs are tied to their transactions by
+// the transactions' surrogate IDs.
+func (orm *ORM) GasUsage(query models.GasUsageQuery) ([]models.GasUsage, error) {
+	var runKey string
+	switch query.GroupBy {
+	case models.GasUsageByJob:
+		runKey = "NULL::text"
+	case models.GasUsageByRun:
+		runKey = "job_runs.id::text"
+	default:
+		return nil, fmt.Errorf("cannot group gas usage by %s", query.GroupBy)
+	}
+
+	rows, err := orm.db.Raw(`
+		SELECT job_runs.job_spec_id::text AS job_key, `+runKey+` AS run_key, COUNT(*),
+			SUM(tx_attempts.gas_used)::text,
+			SUM(tx_attempts.gas_used * tx_attempts.effective_gas_price::numeric)::text
+		FROM tx_attempts
+		JOIN txes ON txes.id = tx_attempts.tx_id
+		LEFT JOIN job_runs ON replace(job_runs.id::text, '-', '') = txes.surrogate_id
+		WHERE tx_attempts.confirmed AND tx_attempts.gas_used IS NOT NULL
+			AND tx_attempts.updated_at >= ? AND tx_attempts.updated_at < ?
+		GROUP BY job_key, run_key
+		ORDER BY SUM(tx_attempts.gas_used * tx_attempts.effective_gas_price::numeric) DESC, job_key, run_key
+	`, query.From, query.To).Rows()
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "while accounting for gas usage")
 	}
-	return orm.db.Where(models.Configuration{Name: name}).
-		Assign(models.Configuration{Name: name, Value: string(textValue)}).
-		FirstOrCreate(&models.Configuration{}).Error
+	defer rows.Close()
+
+	usages := []models.GasUsage{}
+	for rows.Next() {
+		var jobKey, runKey sql.NullString
+		var gasUsed, cost string
+		usage := models.GasUsage{Cost: assets.NewEth(0)}
+		if err := rows.Scan(&jobKey, &runKey, &usage.Transactions, &gasUsed, &cost); err != nil {
+			return nil, errors.Wrap(err, "while accounting for gas usage")
+		}
+		if usage.GasUsed, err = strconv.ParseUint(gasUsed, 10, 64); err != nil {
+			return nil, errors.Wrapf(err, "invalid gas used %s", gasUsed)
+		}
+		if _, ok := usage.Cost.SetString(cost, 10); !ok {
+			return nil, fmt.Errorf("invalid gas cost %s", cost)
+		}
+		if jobKey.Valid {
+			if usage.JobSpecID, err = models.NewIDFromString(jobKey.String); err != nil {
+				return nil, err
+			}
+		}
+		if runKey.Valid {
+			if usage.JobRunID, err = models.NewIDFromString(runKey.String); err != nil {
+				return nil, err
+			}
+		}
+		usages = append(usages, usage)
+	}
+	return usages, rows.Err()
 }
 
-// CreateJob saves a job to the database and adds IDs to associated tables.
-func (orm *ORM) CreateJob(job *models.JobSpec) error {
-	orm.MustEnsureAdvisoryLock()
+// RollUpRunStats rolls up the runs which finished since the beginning of the
+// hour of since into hourly stats, and the hourly stats since the beginning
+// of its day into daily stats, replacing those rolled up before.
+func (orm *ORM) RollUpRunStats(since time.Time) error {
+	finished := []models.RunStatus{models.RunStatusCompleted, models.RunStatusErrored, models.RunStatusCancelled}
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
-		return orm.createJob(dbtx, job)
+		err := dbtx.Exec(`
+			INSERT INTO job_run_stats (period, job_spec_id, bucket, runs, errored, latency_seconds, link_earned, updated_at)
+			SELECT ?, job_spec_id, date_trunc('hour', finished_at), COUNT(*),
+				COUNT(*) FILTER (WHERE status = ?),
+				COALESCE(SUM(EXTRACT(EPOCH FROM finished_at - created_at)), 0),
+				COALESCE(SUM(payment) FILTER (WHERE status = ?), 0),
+				now()
+			FROM job_runs
+			WHERE status IN (?) AND finished_at >= date_trunc('hour', ?::timestamptz)
+			GROUP BY job_spec_id, date_trunc('hour', finished_at)
+			ON CONFLICT (period, job_spec_id, bucket) DO UPDATE SET
+				runs = EXCLUDED.runs,
+				errored = EXCLUDED.errored,
+				latency_seconds = EXCLUDED.latency_seconds,
+				link_earned = EXCLUDED.link_earned,
+				updated_at = EXCLUDED.updated_at
+		`, models.RunStatsHourly, models.RunStatusErrored, models.RunStatusCompleted, finished, since).Error
+		if err != nil {
+			return errors.Wrap(err, "while rolling up hourly run stats")
+		}
+
+		err = dbtx.Exec(`
+			INSERT INTO job_run_stats (period, job_spec_id, bucket, runs, errored, latency_seconds, link_earned, updated_at)
+			SELECT ?, job_spec_id, date_trunc('day', bucket), SUM(runs), SUM(errored),
+				SUM(latency_seconds), SUM(link_earned), now()
+			FROM job_run_stats
+			WHERE period = ? AND bucket >= date_trunc('day', ?::timestamptz)
+			GROUP BY job_spec_id, date_trunc('day', bucket)
+			ON CONFLICT (period, job_spec_id, bucket) DO UPDATE SET
+				runs = EXCLUDED.runs,
+				errored = EXCLUDED.errored,
+				latency_seconds = EXCLUDED.latency_seconds,
+				link_earned = EXCLUDED.link_earned,
+				updated_at = EXCLUDED.updated_at
+		`, models.RunStatsDaily, models.RunStatsHourly, since).Error
+		return errors.Wrap(err, "while rolling up daily run stats")
 	})
 }
 
-func (orm *ORM) createJob(tx *gorm.DB, job *models.JobSpec) error {
-	orm.MustEnsureAdvisoryLock()
-	for i := range job.Initiators {
-		job.Initiators[i].JobSpecID = job.ID
+// LatestRunStatsBucket returns the beginning of the latest hour runs were
+// rolled up in, and false if none were.
+func (orm *ORM) LatestRunStatsBucket() (time.Time, bool, error) {
+	var latest pq.NullTime
+	err := orm.db.Raw(`SELECT MAX(bucket) FROM job_run_stats WHERE period = ?`, models.RunStatsHourly).Row().Scan(&latest)
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, "while finding latest run stats")
 	}
-
-	return tx.Create(job).Error
+	return latest.Time, latest.Valid, nil
 }
 
-// ArchiveJob soft deletes the job, job_runs and its initiator.
-func (orm *ORM) ArchiveJob(ID *models.ID) error {
-	orm.MustEnsureAdvisoryLock()
-	j, err := orm.FindJob(ID)
+// RunStats returns the rolled up stats selected by the query, oldest first.
+func (orm *ORM) RunStats(query models.RunStatsQuery) ([]models.RunStats, error) {
+	if query.Period != models.RunStatsHourly && query.Period != models.RunStatsDaily {
+		return nil, fmt.Errorf("cannot roll up run stats by %s", query.Period)
+	}
+
+	db := orm.db.Table("job_run_stats").
+		Select("job_spec_id::text, bucket, runs, errored, latency_seconds, link_earned::text").
+		Where("period = ? AND bucket >= ? AND bucket < ?", query.Period, query.From, query.To)
+	if query.JobSpecID != nil {
+		db = db.Where("job_spec_id = ?", query.JobSpecID)
+	}
+	rows, err := db.Order("bucket, job_spec_id").Rows()
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "while loading run stats")
+	}
+	defer rows.Close()
+
+	stats := []models.RunStats{}
+	for rows.Next() {
+		s := models.RunStats{Period: query.Period, LinkEarned: assets.NewLink(0)}
+		var jobSpecID, earned string
+		var latency float64
+		if err := rows.Scan(&jobSpecID, &s.Bucket, &s.Runs, &s.Errored, &latency, &earned); err != nil {
+			return nil, errors.Wrap(err, "while loading run stats")
+		}
+		if s.JobSpecID, err = models.NewIDFromString(jobSpecID); err != nil {
+			return nil, err
+		}
+		if _, ok := s.LinkEarned.SetString(earned, 10); !ok {
+			return nil, fmt.Errorf("invalid LINK earned %s", earned)
+		}
+		if s.Runs > 0 {
+			s.ErrorRate = float64(s.Errored) / float64(s.Runs)
+			s.AverageLatencySeconds = latency / float64(s.Runs)
+		}
+		stats = append(stats, s)
 	}
+	return stats, rows.Err()
+}
+
+// CreateExternalInitiator inserts a new external initiator
+func (orm *ORM) CreateExternalInitiator(externalInitiator *models.ExternalInitiator) error {
+	err := orm.db.Create(externalInitiator).Error
+	orm.caches.externalInitiators.invalidate()
+	return err
+}
 
+// DeleteExternalInitiator removes an external initiator, along with the
+// notifications to it and the failures to deliver them.
+func (orm *ORM) DeleteExternalInitiator(name string) error {
+	defer orm.caches.externalInitiators.invalidate()
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
-		return multierr.Combine(
-			dbtx.Exec("UPDATE initiators SET deleted_at = NOW() WHERE job_spec_id = ?", ID).Error,
-			dbtx.Exec("UPDATE task_specs SET deleted_at = NOW() WHERE job_spec_id = ?", ID).Error,
-			dbtx.Exec("UPDATE job_runs SET deleted_at = NOW() WHERE job_spec_id = ?", ID).Error,
-			dbtx.Delete(&j).Error,
-		)
+		err := dbtx.Where("external_initiator_name = ?", name).
+			Delete(&models.ExternalInitiatorDeliveryFailure{}).Error
+		if err != nil {
+			return err
+		}
+		err = dbtx.Where("external_initiator_name = ?", name).
+			Delete(&models.ExternalInitiatorNotification{}).Error
+		if err != nil {
+			return err
+		}
+		err = dbtx.Where("external_initiator_name = ?", name).
+			Delete(&models.ExternalInitiatorNonce{}).Error
+		if err != nil {
+			return err
+		}
+		return dbtx.Where("name = ?", name).Delete(&models.ExternalInitiator{}).Error
 	})
 }
 
-// CreateServiceAgreement saves a Service Agreement, its JobSpec and its
-// associations to the database.
-func (orm *ORM) CreateServiceAgreement(sa *models.ServiceAgreement) error {
-	orm.MustEnsureAdvisoryLock()
+// ExternalInitiators returns all external initiators, ordered by name.
+func (orm *ORM) ExternalInitiators() ([]models.ExternalInitiator, error) {
+	var eis []models.ExternalInitiator
+	return eis, orm.db.Order("name asc").Find(&eis).Error
+}
+
+// ExternalInitiatorsWithDeliveryFailures returns all external initiators,
+// ordered by name, with their failures to deliver to them, newest first.
+func (orm *ORM) ExternalInitiatorsWithDeliveryFailures() ([]models.ExternalInitiator, error) {
+	var eis []models.ExternalInitiator
+	err := orm.db.
+		Preload("DeliveryFailures", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at desc, id desc")
+		}).
+		Order("name asc").
+		Find(&eis).Error
+	return eis, err
+}
+
+// MarkExternalInitiatorSeen records that the external initiator was last seen
+// at the given time.
+func (orm *ORM) MarkExternalInitiatorSeen(name string, at time.Time) error {
+	return orm.db.Model(&models.ExternalInitiator{}).
+		Where("name = ?", name).
+		UpdateColumn("last_seen_at", at).Error
+}
+
+// externalInitiatorNoncesName is the unique index of the nonces of each
+// external initiator.
+const externalInitiatorNoncesName = "idx_external_initiator_nonces_name_nonce"
+
+// UseExternalInitiatorNonce records the nonce of a signed request of the
+// external initiator, returning ErrorNonceUsed if it was used before. The
+// nonces older than twice ExternalInitiatorSignatureMaxAge, whose requests
+// are rejected by their timestamp, are deleted.
+func (orm *ORM) UseExternalInitiatorNonce(name, nonce string, at time.Time) error {
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
-		err := orm.createJob(dbtx, &sa.JobSpec)
+		err := dbtx.
+			Where("external_initiator_name = ? AND created_at < ?", name, at.Add(-2*models.ExternalInitiatorSignatureMaxAge)).
+			Delete(&models.ExternalInitiatorNonce{}).Error
 		if err != nil {
-			return errors.Wrap(err, "Failed to create job for SA")
+			return err
 		}
-
-		return dbtx.Create(sa).Error
+		err = dbtx.Create(&models.ExternalInitiatorNonce{
+			ExternalInitiatorName: name,
+			Nonce:                 nonce,
+			CreatedAt:             at,
+		}).Error
+		if pqErr, ok := errors.Cause(err).(*pq.Error); ok && pqErr.Constraint == externalInitiatorNoncesName {
+			return ErrorNonceUsed
+		}
+		return err
 	})
 }
 
-// UnscopedJobRunsWithStatus passes all JobRuns to a callback, one by one,
-// including those that were soft deleted.
-func (orm *ORM) UnscopedJobRunsWithStatus(cb func(*models.JobRun), statuses ...models.RunStatus) error {
-	orm.MustEnsureAdvisoryLock()
-	var runIDs []string
-	err := orm.db.Unscoped().
-		Table("job_runs").
-		Where("status IN (?)", statuses).
-		Order("created_at asc").
-		Pluck("ID", &runIDs).Error
-	if err != nil {
-		return errors.Wrap(err, "finding job ids")
+// SaveExternalInitiatorHealthCheck records the result of checking the health
+// of the external initiator, which is unhealthy if checkErr is not nil.
+func (orm *ORM) SaveExternalInitiatorHealthCheck(name string, at time.Time, checkErr error) error {
+	healthCheckError := null.String{}
+	if checkErr != nil {
+		healthCheckError = null.StringFrom(checkErr.Error())
 	}
+	return orm.db.Model(&models.ExternalInitiator{}).
+		Where("name = ?", name).
+		UpdateColumns(map[string]interface{}{
+			"last_health_check_at": at,
+			"health_check_error":   healthCheckError,
+		}).Error
+}
 
-	return Batch(BatchSize, func(offset, limit uint) (uint, error) {
-		batchIDs := runIDs[offset:utils.MinUint(limit, uint(len(runIDs)))]
-		var runs []models.JobRun
-		err := orm.Unscoped().
-			preloadJobRuns().
-			Order("job_runs.created_at asc").
-			Find(&runs, "job_runs.id IN (?)", batchIDs).Error
+// CreateExternalInitiatorDeliveryFailure records a failure to deliver to an
+// external initiator, removing its failures older than
+// ExternalInitiatorDeliveryFailureRetention.
+func (orm *ORM) CreateExternalInitiatorDeliveryFailure(failure *models.ExternalInitiatorDeliveryFailure) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		err := dbtx.
+			Where("external_initiator_name = ? AND created_at < ?", failure.ExternalInitiatorName, time.Now().Add(-models.ExternalInitiatorDeliveryFailureRetention)).
+			Delete(&models.ExternalInitiatorDeliveryFailure{}).Error
 		if err != nil {
-			return 0, errors.Wrap(err, "error fetching job run batch")
-		}
-
-		for _, run := range runs {
-			r := run
-			cb(&r)
+			return err
 		}
-		return uint(len(batchIDs)), nil
+		return dbtx.Create(failure).Error
 	})
 }
 
-// AnyJobWithType returns true if there is at least one job associated with
-// the type name specified and false otherwise
-func (orm *ORM) AnyJobWithType(taskTypeName string) (bool, error) {
-	orm.MustEnsureAdvisoryLock()
-	db := orm.db
-	var taskSpec models.TaskSpec
-	rval := db.Where("type = ?", taskTypeName).First(&taskSpec)
-	found := !rval.RecordNotFound()
-	return found, ignoreRecordNotFound(rval)
+// ExternalInitiatorDeliveryFailures returns the most recent failures to
+// deliver to the external initiator, newest first.
+func (orm *ORM) ExternalInitiatorDeliveryFailures(name string, limit int) ([]models.ExternalInitiatorDeliveryFailure, error) {
+	var failures []models.ExternalInitiatorDeliveryFailure
+	err := orm.db.
+		Where("external_initiator_name = ?", name).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Find(&failures).Error
+	return failures, err
 }
 
-// CreateTx finds and overwrites a transaction by its surrogate key, if it exists, or
-// creates it
-func (orm *ORM) CreateTx(tx *models.Tx) (*models.Tx, error) {
-	orm.MustEnsureAdvisoryLock()
+// CreateExternalInitiatorNotification saves a notification to be delivered
+// to an external initiator.
+func (orm *ORM) CreateExternalInitiatorNotification(notification *models.ExternalInitiatorNotification) error {
+	return orm.db.Create(notification).Error
+}
 
-	err := orm.convenientTransaction(func(dbtx *gorm.DB) error {
-		var query *gorm.DB
-		foundTx := models.Tx{}
-		if tx.SurrogateID.Valid {
-			query = dbtx.First(&foundTx, "surrogate_id = ?", tx.SurrogateID.ValueOrZero())
-		} else {
-			query = dbtx.First(&foundTx, "hash = ?", tx.Hash)
-		}
-		err := query.Error
+// SaveExternalInitiatorNotification updates a notification after an attempt
+// to deliver it.
+func (orm *ORM) SaveExternalInitiatorNotification(notification *models.ExternalInitiatorNotification) error {
+	return orm.db.Save(notification).Error
+}
+
+// DeleteExternalInitiatorNotification removes a notification once delivered.
+func (orm *ORM) DeleteExternalInitiatorNotification(id uint64) error {
+	return orm.db.Delete(&models.ExternalInitiatorNotification{ID: id}).Error
+}
+
+// DueExternalInitiatorNotifications returns the notifications which are due
+// to be delivered at the given time, oldest first.
+func (orm *ORM) DueExternalInitiatorNotifications(at time.Time, limit int) ([]models.ExternalInitiatorNotification, error) {
+	var notifications []models.ExternalInitiatorNotification
+	err := orm.db.
+		Where("dead_at IS NULL AND next_attempt_at <= ?", at).
+		Order("id asc").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// DeadExternalInitiatorNotifications returns the notifications which ran out
+// of attempts to be delivered, most recently dead-lettered first.
+func (orm *ORM) DeadExternalInitiatorNotifications() ([]models.ExternalInitiatorNotification, error) {
+	var notifications []models.ExternalInitiatorNotification
+	err := orm.db.
+		Where("dead_at IS NOT NULL").
+		Order("dead_at desc, id desc").
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// RetryExternalInitiatorNotification brings a dead-lettered notification back
+// to be delivered at the given time, with all its attempts.
+func (orm *ORM) RetryExternalInitiatorNotification(id uint64, at time.Time) (models.ExternalInitiatorNotification, error) {
+	var notification models.ExternalInitiatorNotification
+	err := orm.db.First(&notification, "id = ? AND dead_at IS NOT NULL", id).Error
+	if err != nil {
+		return notification, err
+	}
+	notification.Attempts = 0
+	notification.NextAttemptAt = at
+	notification.DeadAt = nil
+	return notification, orm.db.Save(&notification).Error
+}
+
+// FindExternalInitiator finds an external initiator given an authentication request
+func (orm *ORM) FindExternalInitiator(
+	eia *auth.Token,
+) (*models.ExternalInitiator, error) {
+	cache := orm.lookupCache(orm.caches.externalInitiators)
+	key := "access_key:" + eia.AccessKey
+	cached, generation, ok := cache.get(key)
+	if ok {
+		initiator := cached.(models.ExternalInitiator)
+		return &initiator, nil
+	}
+	initiator := &models.ExternalInitiator{}
+	err := orm.db.Where("access_key = ?", eia.AccessKey).Find(initiator).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding external initiator")
+	}
+
+	cache.put(key, *initiator, generation)
+	return initiator, nil
+}
+
+// FindExternalInitiatorByName finds an external initiator given an authentication request
+func (orm *ORM) FindExternalInitiatorByName(iname string) (models.ExternalInitiator, error) {
+	cache := orm.lookupCache(orm.caches.externalInitiators)
+	key := "name:" + strings.ToLower(iname)
+	cached, generation, ok := cache.get(key)
+	if ok {
+		return cached.(models.ExternalInitiator), nil
+	}
+	var exi models.ExternalInitiator
+	if err := orm.db.First(&exi, "lower(name) = lower(?)", iname).Error; err != nil {
+		return exi, err
+	}
+	cache.put(key, exi, generation)
+	return exi, nil
+}
+
+// FindServiceAgreement looks up a ServiceAgreement by its ID.
+func (orm *ORM) FindServiceAgreement(id string) (models.ServiceAgreement, error) {
+	var sa models.ServiceAgreement
+	return sa, orm.db.Set("gorm:auto_preload", true).First(&sa, "id = ?", id).Error
+}
+
+// ServiceAgreements returns the service agreements, most recent first.
+func (orm *ORM) ServiceAgreements(offset, limit int) ([]models.ServiceAgreement, int, error) {
+	count, err := orm.CountOf(&models.ServiceAgreement{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sas []models.ServiceAgreement
+	err = orm.db.Set("gorm:auto_preload", true).
+		Order("created_at desc, id asc").
+		Limit(limit).
+		Offset(offset).
+		Find(&sas).Error
+	return sas, count, err
+}
+
+// ServiceAgreementsEndingBefore returns the service agreements which have not
+// been terminated, and end before the given time.
+func (orm *ORM) ServiceAgreementsEndingBefore(t time.Time) ([]models.ServiceAgreement, error) {
+	var sas []models.ServiceAgreement
+	err := orm.db.Set("gorm:auto_preload", true).
+		Joins("JOIN encumbrances ON encumbrances.id = service_agreements.encumbrance_id").
+		Where("service_agreements.terminated_at IS NULL AND encumbrances.end_at < ?", t).
+		Order("encumbrances.end_at asc").
+		Find(&sas).Error
+	return sas, err
+}
+
+// TerminateServiceAgreement records that the service agreement was
+// terminated at the given time, unless it already was.
+func (orm *ORM) TerminateServiceAgreement(id string, at time.Time) error {
+	return orm.db.Model(&models.ServiceAgreement{}).
+		Where("id = ? AND terminated_at IS NULL", id).
+		UpdateColumn("terminated_at", at).Error
+}
+
+// MarkServiceAgreementExpiryNotified records that the operator was notified
+// at the given time that the service agreement expires soon.
+func (orm *ORM) MarkServiceAgreementExpiryNotified(id string, at time.Time) error {
+	return orm.db.Model(&models.ServiceAgreement{}).
+		Where("id = ?", id).
+		UpdateColumn("expiry_notified_at", at).Error
+}
+
+// CreateServiceAgreementPayment records a service agreement payment,
+// returning false, and recording nothing, if a payment of the same kind was
+// already recorded for the request.
+func (orm *ORM) CreateServiceAgreementPayment(p *models.ServiceAgreementPayment) (bool, error) {
+	if orm.ReadOnly() {
+		return false, ErrReadOnly
+	}
+	p.CreatedAt = time.Now()
+	rows, err := orm.db.Raw(`
+		INSERT INTO service_agreement_payments (service_agreement_id, kind, request_id, amount, tx_hash, block_hash, block_number, log_index, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (kind, request_id) DO NOTHING
+		RETURNING id
+	`, p.ServiceAgreementID, p.Kind, p.RequestID, p.Amount, p.TxHash, p.BlockHash, p.BlockNumber, p.LogIndex, p.CreatedAt).Rows()
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	return true, rows.Scan(&p.ID)
+}
+
+// FindServiceAgreementDeposit returns the deposit of the payment of the
+// request with the given ID.
+func (orm *ORM) FindServiceAgreementDeposit(requestID common.Hash) (models.ServiceAgreementPayment, error) {
+	var deposit models.ServiceAgreementPayment
+	err := orm.db.
+		Where("kind = ? AND request_id = ?", models.ServiceAgreementDeposit, requestID).
+		First(&deposit).Error
+	return deposit, err
+}
+
+// LatestServiceAgreementPaymentBlock returns the number of the last block
+// a service agreement payment was recorded from, or 0 if none was.
+func (orm *ORM) LatestServiceAgreementPaymentBlock() (uint64, error) {
+	var block sql.NullInt64
+	err := orm.db.Raw(`SELECT MAX(block_number) FROM service_agreement_payments`).Row().Scan(&block)
+	return uint64(block.Int64), err
+}
+
+// ServiceAgreementPaymentsFor returns the payments of a service agreement,
+// most recent first.
+func (orm *ORM) ServiceAgreementPaymentsFor(saID string, offset, limit int) ([]models.ServiceAgreementPayment, int, error) {
+	var count int
+	err := orm.db.Model(&models.ServiceAgreementPayment{}).
+		Where("service_agreement_id = ?", saID).
+		Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var payments []models.ServiceAgreementPayment
+	err = orm.db.
+		Where("service_agreement_id = ?", saID).
+		Order("block_number desc, log_index desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&payments).Error
+	return payments, count, err
+}
+
+// ServiceAgreementCoordinators returns the addresses of the coordinators the
+// service agreements which have not been terminated were made with.
+func (orm *ORM) ServiceAgreementCoordinators() ([]common.Address, error) {
+	rows, err := orm.db.Raw(`
+		SELECT DISTINCT initiators.address FROM initiators
+		JOIN service_agreements ON service_agreements.job_spec_id = initiators.job_spec_id::uuid
+		WHERE initiators.type = ? AND initiators.deleted_at IS NULL
+		AND service_agreements.terminated_at IS NULL
+	`, models.InitiatorServiceAgreementExecutionLog).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []common.Address
+	for rows.Next() {
+		var address common.Address
+		if err := rows.Scan(&address); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+// ReconcileServiceAgreement sums up the payments recorded for a service
+// agreement. The deposits of the requests a completed run of the agreement's
+// job was made for count as released.
+func (orm *ORM) ReconcileServiceAgreement(sa models.ServiceAgreement) (models.ServiceAgreementReconciliation, error) {
+	expected := sa.Encumbrance.Payment
+	if expected == nil {
+		expected = assets.NewLink(0)
+	}
+	r := models.ServiceAgreementReconciliation{
+		ServiceAgreementID: sa.ID,
+		Deposited:          assets.NewLink(0),
+		Refunded:           assets.NewLink(0),
+		Released:           assets.NewLink(0),
+	}
+	err := orm.db.Raw(`
+		SELECT
+			COUNT(*) FILTER (WHERE kind = ?),
+			COUNT(*) FILTER (WHERE kind = ? AND amount < ?),
+			COALESCE(SUM(amount) FILTER (WHERE kind = ?), 0),
+			COALESCE(SUM(amount) FILTER (WHERE kind = ?), 0),
+			COALESCE(SUM(amount) FILTER (WHERE kind = ? AND EXISTS (
+				SELECT 1 FROM run_requests
+				JOIN job_runs ON job_runs.run_request_id = run_requests.id
+				WHERE run_requests.request_id = service_agreement_payments.request_id
+				AND job_runs.job_spec_id = ? AND job_runs.status = ?
+			)), 0)
+		FROM service_agreement_payments
+		WHERE service_agreement_id = ?
+	`,
+		models.ServiceAgreementDeposit,
+		models.ServiceAgreementDeposit, expected,
+		models.ServiceAgreementDeposit,
+		models.ServiceAgreementRefund,
+		models.ServiceAgreementDeposit, sa.JobSpecID, models.RunStatusCompleted,
+		sa.ID,
+	).Row().Scan(&r.Requests, &r.Underpaid, r.Deposited, r.Refunded, r.Released)
+	if err != nil {
+		return r, errors.Wrap(err, "while reconciling service agreement payments")
+	}
+
+	r.Expected = (*assets.Link)(new(big.Int).Mul(expected.ToInt(), big.NewInt(r.Requests)))
+	outstanding := new(big.Int).Sub(r.Deposited.ToInt(), r.Refunded.ToInt())
+	r.Outstanding = (*assets.Link)(outstanding.Sub(outstanding, r.Released.ToInt()))
+	return r, nil
+}
+
+// Jobs fetches all jobs.
+func (orm *ORM) Jobs(cb func(*models.JobSpec) bool, initrTypes ...string) error {
+	return Batch(BatchSize, func(offset, limit uint) (uint, error) {
+		scope := orm.db.Limit(limit).Offset(offset)
+		if len(initrTypes) > 0 {
+			scope = scope.Where("initiators.type IN (?)", initrTypes)
+			if dbutil.IsPostgres(orm.db) {
+				scope = scope.Joins("JOIN initiators ON job_specs.id = initiators.job_spec_id::uuid")
+			} else {
+				scope = scope.Joins("JOIN initiators ON job_specs.id = initiators.job_spec_id")
+			}
+		}
+		var ids []string
+		err := scope.Table("job_specs").Pluck("job_specs.id", &ids).Error
+		if err != nil {
+			return 0, err
+		}
+
+		if len(ids) == 0 {
+			return 0, nil
+		}
+
+		jobs := []models.JobSpec{}
+		err = orm.preloadJobs().Unscoped().Find(&jobs, "id IN (?)", ids).Error
+		if err != nil {
+			return 0, err
+		}
+		for _, j := range jobs {
+			temp := j
+			if temp.DeletedAt.Valid {
+				continue
+			}
+			if !cb(&temp) {
+				return 0, nil
+			}
+		}
+
+		return uint(len(jobs)), nil
+	})
+}
+
+// JobRunsFor fetches all JobRuns with a given Job ID,
+// sorted by their created at time.
+func (orm *ORM) JobRunsFor(jobSpecID *models.ID, limit ...int) ([]models.JobRun, error) {
+	var lim int
+	if len(limit) == 0 {
+		lim = 100
+	} else if len(limit) >= 1 {
+		lim = limit[0]
+	}
+	return orm.loadJobRuns("job_runs.job_spec_id = ? ORDER BY job_runs.created_at DESC LIMIT ?", jobSpecID, lim)
+}
+
+// JobRunsUpdatedAfter returns up to limit runs updated after the given run,
+// in the order they were updated, with their results. Runs updated at the
+// same time are ordered by ID, so that paging through them skips none. All
+// the runs updated after the given time are returned when afterID is nil, and
+// those of all jobs when jobSpecID is nil.
+func (orm *ORM) JobRunsUpdatedAfter(jobSpecID *models.ID, after time.Time, afterID *models.ID, limit int) ([]models.JobRun, error) {
+	runs := []models.JobRun{}
+	scope := orm.db.Preload("Result")
+	if afterID != nil {
+		scope = scope.Where("(updated_at, id) > (?, ?)", after, afterID)
+	} else {
+		scope = scope.Where("updated_at > ?", after)
+	}
+	if jobSpecID != nil {
+		scope = scope.Where("job_spec_id = ?", jobSpecID)
+	}
+	err := scope.
+		Order("updated_at asc, id asc").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+// JobRunsCountFor returns the current number of runs for the job
+func (orm *ORM) JobRunsCountFor(jobSpecID *models.ID) (int, error) {
+	var count int
+	err := orm.db.
+		Model(&models.JobRun{}).
+		Where("job_spec_id = ?", jobSpecID).
+		Count(&count).Error
+	return count, err
+}
+
+// concurrencySlotStatuses are the statuses of runs counted against the
+// concurrency limits.
+var concurrencySlotStatuses = []models.RunStatus{models.RunStatusInProgress, models.RunStatusPendingBridge}
+
+// ActiveJobRunsCount returns the number of runs holding a concurrency slot,
+// i.e. executing or waiting on a bridge. Runs of all jobs are counted when
+// jobSpecID is nil.
+func (orm *ORM) ActiveJobRunsCount(jobSpecID *models.ID) (int, error) {
+	var count int
+	scope := orm.db.
+		Model(&models.JobRun{}).
+		Where("status IN (?)", concurrencySlotStatuses)
+	if jobSpecID != nil {
+		scope = scope.Where("job_spec_id = ?", jobSpecID)
+	}
+	err := scope.Count(&count).Error
+	return count, err
+}
+
+// ParkJobRun parks a run in progress until ResumeAllParked starts it again,
+// for when the run queue has no room for it.
+func (orm *ORM) ParkJobRun(id *models.ID) error {
+	db := orm.db.
+		Model(&models.JobRun{}).
+		Where("id = ? AND status = ?", id, models.RunStatusInProgress).
+		Update("status", models.RunStatusPendingConcurrency)
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return fmt.Errorf("cannot park run %s, which is not in progress", id)
+	}
+	return nil
+}
+
+// HasParkedJobRuns returns whether any run is waiting on a concurrency slot,
+// answered from the index of the statuses of unfinished runs.
+func (orm *ORM) HasParkedJobRuns() (bool, error) {
+	var parked bool
+	err := orm.db.Raw(
+		`SELECT EXISTS (SELECT 1 FROM job_runs WHERE status = ? AND deleted_at IS NULL)`,
+		models.RunStatusPendingConcurrency,
+	).Row().Scan(&parked)
+	return parked, errors.Wrap(err, "checking for parked job runs")
+}
+
+// ParkedJobRuns returns up to limit runs waiting on a concurrency slot, of the
+// highest priority first, and of those the oldest first. Runs of jobs that are still at their own concurrency limit are left
+// out, so they do not hold up the runs of other jobs.
+func (orm *ORM) ParkedJobRuns(limit int) ([]models.JobRun, error) {
+	var runIDs []string
+	err := orm.db.
+		Table("job_runs").
+		Joins("JOIN job_specs ON job_specs.id = job_runs.job_spec_id").
+		Joins("JOIN initiators ON initiators.id = job_runs.initiator_id").
+		Where("job_runs.status = ? AND job_runs.deleted_at IS NULL", models.RunStatusPendingConcurrency).
+		Where(`job_specs.max_concurrent_runs = 0 OR job_specs.max_concurrent_runs > (
+			SELECT COUNT(*) FROM job_runs active
+			WHERE active.job_spec_id = job_runs.job_spec_id
+			AND active.status IN (?))`, concurrencySlotStatuses).
+		Order(runPriorityOrder()).
+		Order("job_runs.created_at asc").
+		Limit(limit).
+		Pluck("job_runs.id", &runIDs).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "finding parked job runs")
+	}
+
+	runs := []models.JobRun{}
+	if len(runIDs) == 0 {
+		return runs, nil
+	}
+	err = orm.preloadJobRuns().
+		Find(&runs, "job_runs.id IN (?)", runIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].Priority() != runs[j].Priority() {
+			return runs[i].Priority() > runs[j].Priority()
+		}
+		return runs[i].CreatedAt.Before(runs[j].CreatedAt)
+	})
+	return runs, nil
+}
+
+// runPriorityOrder orders runs joined with their initiators by the priority of
+// JobRun.Priority, highest first.
+func runPriorityOrder() interface{} {
+	var args []interface{}
+	inList := func(types []string) string {
+		for _, t := range types {
+			args = append(args, t)
+		}
+		return strings.TrimSuffix(strings.Repeat("?,", len(types)), ",")
+	}
+	high := inList(models.HighPriorityInitiators)
+	low := inList(models.LowPriorityInitiators)
+	return gorm.Expr(fmt.Sprintf(
+		"CASE WHEN initiators.type IN (%s) THEN %d WHEN initiators.type IN (%s) THEN %d ELSE %d END DESC",
+		high, models.RunPriorityHigh, low, models.RunPriorityLow, models.RunPriorityNormal,
+	), args...)
+}
+
+// Sessions returns all sessions limited by the parameters.
+func (orm *ORM) Sessions(offset, limit int) ([]models.Session, error) {
+	var sessions []models.Session
+	err := orm.db.
+		Set("gorm:auto_preload", true).
+		Limit(limit).
+		Offset(offset).
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// GetConfigValue returns the value for a named configuration entry
+func (orm *ORM) GetConfigValue(field string, value encoding.TextUnmarshaler) error {
+	name := EnvVarName(field)
+	config := models.Configuration{}
+	if err := orm.db.First(&config, "name = ?", name).Error; err != nil {
+		return err
+	}
+	return value.UnmarshalText([]byte(config.Value))
+}
+
+// SetConfigValue returns the value for a named configuration entry
+func (orm *ORM) SetConfigValue(field string, value encoding.TextMarshaler) error {
+	name := EnvVarName(field)
+	textValue, err := value.MarshalText()
+	if err != nil {
+		return err
+	}
+	return orm.db.Where(models.Configuration{Name: name}).
+		Assign(models.Configuration{Name: name, Value: string(textValue)}).
+		FirstOrCreate(&models.Configuration{}).Error
+}
+
+// ChangeConfigValue sets the value for a named configuration entry, and
+// records the change in the audit log of configuration changes.
+func (orm *ORM) ChangeConfigValue(field string, value encoding.TextMarshaler, change *models.ConfigurationChange) error {
+	name := EnvVarName(field)
+	textValue, err := value.MarshalText()
+	if err != nil {
+		return err
+	}
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		err := dbtx.Where(models.Configuration{Name: name}).
+			Assign(models.Configuration{Name: name, Value: string(textValue)}).
+			FirstOrCreate(&models.Configuration{}).Error
+		if err != nil {
+			return err
+		}
+		return dbtx.Create(change).Error
+	})
+}
+
+// ConfigurationChanges returns the changes made to the configuration while
+// the node was running, most recent first.
+func (orm *ORM) ConfigurationChanges(offset int, limit int) ([]models.ConfigurationChange, int, error) {
+	count, err := orm.CountOf(&models.ConfigurationChange{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var changes []models.ConfigurationChange
+	err = orm.getRecords(&changes, "id desc", offset, limit)
+	return changes, count, err
+}
+
+// RecordAccessViolation records a request refused because of where it came
+// from in the audit log, counting it with those refused from the same
+// address to the same endpoint in the same hour.
+func (orm *ORM) RecordAccessViolation(violation models.AccessViolation) error {
+	return orm.exec(`
+		INSERT INTO access_violations (endpoint, remote_ip, method, path, count, window_start, created_at, last_seen_at)
+		VALUES (?, ?, ?, ?, 1, date_trunc('hour', now()), now(), now())
+		ON CONFLICT (endpoint, remote_ip, window_start) DO UPDATE SET
+			method = EXCLUDED.method,
+			path = EXCLUDED.path,
+			count = access_violations.count + 1,
+			last_seen_at = EXCLUDED.last_seen_at`,
+		violation.Endpoint, violation.RemoteIP, violation.Method, violation.Path).Error
+}
+
+// DeleteAccessViolationsBefore deletes the access violations last seen before
+// the given time.
+func (orm *ORM) DeleteAccessViolationsBefore(before time.Time) error {
+	return orm.db.Where("last_seen_at < ?", before).Delete(&models.AccessViolation{}).Error
+}
+
+// AccessViolations returns the requests refused because of where they came
+// from, most recent first.
+func (orm *ORM) AccessViolations(offset int, limit int) ([]models.AccessViolation, int, error) {
+	count, err := orm.CountOf(&models.AccessViolation{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var violations []models.AccessViolation
+	err = orm.getRecords(&violations, "id desc", offset, limit)
+	return violations, count, err
+}
+
+// RunResumptions returns the runs resumed with results supplied by
+// operators, most recent first.
+func (orm *ORM) RunResumptions(offset int, limit int) ([]models.RunResumption, int, error) {
+	count, err := orm.CountOf(&models.RunResumption{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var resumptions []models.RunResumption
+	err = orm.getRecords(&resumptions, "id desc", offset, limit)
+	return resumptions, count, err
+}
+
+// CreateKeyFunding records ETH sent from the funding key to another key in
+// the audit log.
+func (orm *ORM) CreateKeyFunding(funding *models.KeyFunding) error {
+	return orm.db.Create(funding).Error
+}
+
+// SetKeyFundingTx records the transaction which sent the funding.
+func (orm *ORM) SetKeyFundingTx(funding *models.KeyFunding, tx *models.Tx) error {
+	funding.TxID = null.IntFrom(int64(tx.ID))
+	funding.TxHash = &tx.Hash
+	return orm.db.Model(funding).Updates(map[string]interface{}{
+		"tx_id":   funding.TxID,
+		"tx_hash": funding.TxHash,
+	}).Error
+}
+
+// FailKeyFunding marks a funding whose transaction could not be sent as
+// failed, keeping it in the audit log.
+func (orm *ORM) FailKeyFunding(funding *models.KeyFunding, cause error) error {
+	funding.FailedAt = null.TimeFrom(time.Now())
+	funding.Error = null.StringFrom(cause.Error())
+	return orm.db.Model(funding).Updates(map[string]interface{}{
+		"failed_at": funding.FailedAt,
+		"error":     funding.Error,
+	}).Error
+}
+
+// KeyFundings returns the ETH sent from the funding key to the node's other
+// keys, most recent first.
+func (orm *ORM) KeyFundings(offset int, limit int) ([]models.KeyFunding, int, error) {
+	count, err := orm.CountOf(&models.KeyFunding{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var fundings []models.KeyFunding
+	err = orm.getRecords(&fundings, "id desc", offset, limit)
+	return fundings, count, err
+}
+
+// KeyFundingsSince returns the ETH sent from the funding key to the node's
+// other keys at or after t, leaving out the fundings which failed.
+func (orm *ORM) KeyFundingsSince(t time.Time) ([]models.KeyFunding, error) {
+	var fundings []models.KeyFunding
+	err := orm.db.Where("created_at >= ? AND failed_at IS NULL", t).Order("id asc").Find(&fundings).Error
+	return fundings, err
+}
+
+// CreateJob saves a job to the database and adds IDs to associated tables.
+func (orm *ORM) CreateJob(job *models.JobSpec) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		return orm.createJob(dbtx, job)
+	})
+}
+
+// CreateJobs saves the jobs, with their initiators and tasks, in a single
+// transaction, so that either all of them are saved or none are.
+func (orm *ORM) CreateJobs(jobs []models.JobSpec) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		for i := range jobs {
+			if err := orm.createJob(dbtx, &jobs[i]); err != nil {
+				return errors.Wrapf(err, "job %s", jobs[i].ID)
+			}
+		}
+		return nil
+	})
+}
+
+// CreateJobWithDependencies saves the job along with the bridges, external
+// initiators and secrets it depends on which the node is missing, in a
+// single transaction, so that either all of them are saved or none are. The
+// job is only saved if validate, given an ORM seeing the dependencies saved,
+// returns no error, which is returned as it is.
+func (orm *ORM) CreateJobWithDependencies(job *models.JobSpec, deps models.JobDependencies, validate func(*ORM) error) error {
+	defer orm.caches.externalInitiators.invalidate()
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		for _, bt := range deps.Bridges {
+			if err := dbtx.Create(bt).Error; err != nil {
+				return errors.Wrapf(err, "bridge %s", bt.Name)
+			}
+		}
+		for _, ei := range deps.ExternalInitiators {
+			if err := dbtx.Create(ei).Error; err != nil {
+				return errors.Wrapf(err, "external initiator %s", ei.Name)
+			}
+		}
+		for _, secret := range deps.Secrets {
+			if err := dbtx.Create(secret).Error; err != nil {
+				return errors.Wrapf(err, "secret %s", secret.Name)
+			}
+		}
+		if err := validate(orm.withTx(dbtx)); err != nil {
+			return err
+		}
+		return orm.createJob(dbtx, job)
+	})
+}
+
+func (orm *ORM) createJob(tx *gorm.DB, job *models.JobSpec) error {
+	for i := range job.Initiators {
+		job.Initiators[i].JobSpecID = job.ID
+	}
+	for i := range job.Tasks {
+		job.Tasks[i].Edges = nil
+	}
+
+	if err := tx.Create(job).Error; err != nil {
+		return err
+	}
+	return createTaskEdges(tx, job)
+}
+
+// createTaskEdges records the inputs of the tasks of a job, once the tasks
+// have IDs.
+func createTaskEdges(tx *gorm.DB, job *models.JobSpec) error {
+	ids := map[string]uint{}
+	for _, task := range job.Tasks {
+		if task.Name != "" {
+			ids[task.Name] = task.ID
+		}
+	}
+	for i, task := range job.Tasks {
+		for _, input := range task.Inputs {
+			id, ok := ids[input]
+			if !ok {
+				return fmt.Errorf("task %s has unknown input %s", task.Name, input)
+			}
+			edge := models.TaskSpecEdge{TaskSpecID: task.ID, InputTaskSpecID: id}
+			if err := tx.Create(&edge).Error; err != nil {
+				return errors.Wrap(err, "while saving task inputs")
+			}
+			job.Tasks[i].Edges = append(job.Tasks[i].Edges, edge)
+		}
+	}
+	return nil
+}
+
+// UpdateJob replaces the initiators, tasks and schedule of an existing job
+// with those of the passed job, keeping a snapshot of the prior definition as
+// a JobSpecVersion.
+func (orm *ORM) UpdateJob(job *models.JobSpec) error {
+	defer orm.caches.jobs.invalidate(job.ID.String())
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		return orm.updateJob(dbtx, job)
+	})
+}
+
+func (orm *ORM) updateJob(tx *gorm.DB, job *models.JobSpec) error {
+	var current models.JobSpec
+	if err := preloadJobAssociations(tx).First(&current, "id = ?", job.ID).Error; err != nil {
+		return err
+	}
+
+	var agreements int
+	err := tx.Model(&models.ServiceAgreement{}).Where("job_spec_id = ?", job.ID).Count(&agreements).Error
+	if err != nil {
+		return err
+	} else if agreements > 0 {
+		return fmt.Errorf("job %s belongs to a service agreement and cannot be updated", job.ID)
+	}
+
+	var latest uint32
+	err = tx.Model(&models.JobSpecVersion{}).
+		Where("job_spec_id = ?", job.ID).
+		Select("COALESCE(MAX(version), 0)").
+		Row().
+		Scan(&latest)
+	if err != nil {
+		return err
+	}
+
+	version, err := models.NewJobSpecVersion(current, latest+1)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	version.CreatedAt = now
+	if err = tx.Create(&version).Error; err != nil {
+		return errors.Wrap(err, "failed to save job spec version")
+	}
+
+	// Initiators left unchanged keep their IDs, along with the state kept
+	// under them, such as kafka offsets and MQTT consumptions.
+	keptIDs := []uint32{0}
+	for i := range job.Initiators {
+		job.Initiators[i].ID = 0
+		job.Initiators[i].JobSpecID = job.ID
+		for _, existing := range current.Initiators {
+			if !existing.DeletedAt.Valid && !containsInitiatorID(keptIDs, existing.ID) && sameInitiator(existing, job.Initiators[i]) {
+				job.Initiators[i] = existing
+				keptIDs = append(keptIDs, existing.ID)
+				break
+			}
+		}
+	}
+
+	err = multierr.Combine(
+		tx.Exec("UPDATE initiators SET deleted_at = ? WHERE job_spec_id = ? AND deleted_at IS NULL AND id NOT IN (?)", now, job.ID, keptIDs).Error,
+		tx.Exec("UPDATE task_specs SET deleted_at = ? WHERE job_spec_id = ? AND deleted_at IS NULL", now, job.ID).Error,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i := range job.Initiators {
+		if job.Initiators[i].ID != 0 {
+			continue
+		}
+		if err = tx.Create(&job.Initiators[i]).Error; err != nil {
+			return err
+		}
+	}
+	for i := range job.Tasks {
+		job.Tasks[i].ID = 0
+		job.Tasks[i].JobSpecID = job.ID
+		job.Tasks[i].Edges = nil
+		if err = tx.Create(&job.Tasks[i]).Error; err != nil {
+			return err
+		}
+	}
+	if err = createTaskEdges(tx, job); err != nil {
+		return err
+	}
+
+	job.CreatedAt = current.CreatedAt
+	return tx.Model(&current).Updates(map[string]interface{}{
+		"start_at":               job.StartAt,
+		"end_at":                 job.EndAt,
+		"min_payment":            job.MinPayment,
+		"max_concurrent_runs":    job.MaxConcurrentRuns,
+		"incoming_confirmations": job.IncomingConfirmations,
+		"outgoing_confirmations": job.OutgoingConfirmations,
+	}).Error
+}
+
+// sameInitiator returns whether the initiators have the same type and
+// params.
+func sameInitiator(a, b models.Initiator) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	aParams, errA := json.Marshal(a.InitiatorParams)
+	bParams, errB := json.Marshal(b.InitiatorParams)
+	return errA == nil && errB == nil && string(aParams) == string(bParams)
+}
+
+func containsInitiatorID(ids []uint32, id uint32) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// JobSpecVersions returns the prior versions of a job, most recent first.
+func (orm *ORM) JobSpecVersions(jobSpecID *models.ID) ([]models.JobSpecVersion, error) {
+	versions := []models.JobSpecVersion{}
+	err := orm.db.
+		Where("job_spec_id = ?", jobSpecID).
+		Order("version desc").
+		Find(&versions).Error
+	return versions, err
+}
+
+// FindJobSpecVersion returns a single prior version of a job.
+func (orm *ORM) FindJobSpecVersion(jobSpecID *models.ID, version uint32) (models.JobSpecVersion, error) {
+	var jsv models.JobSpecVersion
+	err := orm.db.First(&jsv, "job_spec_id = ? AND version = ?", jobSpecID, version).Error
+	return jsv, err
+}
+
+// DiffJobSpecVersion returns the changes made to a job since the given prior
+// version was replaced.
+func (orm *ORM) DiffJobSpecVersion(jobSpecID *models.ID, version uint32) ([]models.JobSpecChange, error) {
+	jsv, err := orm.FindJobSpecVersion(jobSpecID, version)
+	if err != nil {
+		return nil, err
+	}
+	from, err := jsv.JobSpecRequest()
+	if err != nil {
+		return nil, err
+	}
+	current, err := orm.FindJob(jobSpecID)
+	if err != nil {
+		return nil, err
+	}
+	return models.DiffJobSpecRequests(from, models.NewJobSpecRequestFromJob(current))
+}
+
+// RollbackJob restores a job to the definition it had at the given prior
+// version. The definition being replaced is itself kept as a new version, so
+// a rollback can be undone.
+func (orm *ORM) RollbackJob(jobSpecID *models.ID, version uint32) (models.JobSpec, error) {
+	jsv, err := orm.FindJobSpecVersion(jobSpecID, version)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	jsr, err := jsv.JobSpecRequest()
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+
+	job := models.NewJobFromRequest(jsr)
+	job.ID = jobSpecID
+	defer orm.caches.jobs.invalidate(jobSpecID.String())
+	err = orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		return orm.updateJob(dbtx, &job)
+	})
+	return job, err
+}
+
+// ArchiveJob soft deletes the job, job_runs and its initiator.
+func (orm *ORM) ArchiveJob(ID *models.ID) error {
+	return orm.ArchiveJobs([]*models.ID{ID})
+}
+
+// ArchiveJobs archives the jobs in a single transaction, so that either all
+// of them are archived or none are.
+func (orm *ORM) ArchiveJobs(IDs []*models.ID) error {
+	jobs := make([]models.JobSpec, len(IDs))
+	for i, ID := range IDs {
+		j, err := orm.FindJob(ID)
+		if err != nil {
+			return errors.Wrapf(err, "job %s", ID)
+		}
+		jobs[i] = j
+	}
+
+	defer orm.caches.jobs.invalidate(jobIDStrings(IDs)...)
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		for _, j := range jobs {
+			err := multierr.Combine(
+				dbtx.Exec("UPDATE initiators SET deleted_at = NOW() WHERE job_spec_id = ? AND deleted_at IS NULL", j.ID).Error,
+				dbtx.Exec("UPDATE task_specs SET deleted_at = NOW() WHERE job_spec_id = ? AND deleted_at IS NULL", j.ID).Error,
+				dbtx.Exec("UPDATE job_runs SET deleted_at = NOW() WHERE job_spec_id = ?", j.ID).Error,
+				dbtx.Delete(&j).Error,
+			)
+			if err != nil {
+				return errors.Wrapf(err, "job %s", j.ID)
+			}
+		}
+		return nil
+	})
+}
+
+// UnarchiveJobs brings archived jobs back, with the initiators, tasks and runs
+// they had when they were archived, in a single transaction. Initiators and
+// tasks replaced by a later version of a job before it was archived stay
+// deleted.
+func (orm *ORM) UnarchiveJobs(IDs []*models.ID) error {
+	for _, ID := range IDs {
+		j, err := orm.Unscoped().FindJob(ID)
+		if err != nil {
+			return errors.Wrapf(err, "job %s", ID)
+		}
+		if !j.DeletedAt.Valid {
+			return fmt.Errorf("job %s is not archived", ID)
+		}
+	}
+
+	defer orm.caches.jobs.invalidate(jobIDStrings(IDs)...)
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		for _, ID := range IDs {
+			err := multierr.Combine(
+				dbtx.Exec(`UPDATE initiators SET deleted_at = NULL
+					WHERE job_spec_id = ? AND deleted_at IS NOT NULL AND NOT EXISTS (
+						SELECT 1 FROM job_spec_versions
+						WHERE job_spec_versions.job_spec_id = CAST(initiators.job_spec_id AS uuid)
+						AND job_spec_versions.created_at >= initiators.deleted_at)`, ID).Error,
+				dbtx.Exec(`UPDATE task_specs SET deleted_at = NULL
+					WHERE job_spec_id = ? AND deleted_at IS NOT NULL AND NOT EXISTS (
+						SELECT 1 FROM job_spec_versions
+						WHERE job_spec_versions.job_spec_id = task_specs.job_spec_id
+						AND job_spec_versions.created_at >= task_specs.deleted_at)`, ID).Error,
+				dbtx.Exec("UPDATE job_runs SET deleted_at = NULL WHERE job_spec_id = ?", ID).Error,
+				dbtx.Exec("UPDATE job_specs SET deleted_at = NULL WHERE id = ?", ID).Error,
+			)
+			if err != nil {
+				return errors.Wrapf(err, "job %s", ID)
+			}
+		}
+		return nil
+	})
+}
+
+func jobIDStrings(IDs []*models.ID) []string {
+	keys := make([]string, len(IDs))
+	for i, ID := range IDs {
+		keys[i] = ID.String()
+	}
+	return keys
+}
+
+// CreateServiceAgreement saves a Service Agreement, its JobSpec and its
+// associations to the database.
+func (orm *ORM) CreateServiceAgreement(sa *models.ServiceAgreement) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		err := orm.createJob(dbtx, &sa.JobSpec)
+		if err != nil {
+			return errors.Wrap(err, "Failed to create job for SA")
+		}
+
+		return dbtx.Create(sa).Error
+	})
+}
+
+// UnscopedJobRunsWithStatus passes all JobRuns to a callback, one by one,
+// including those that were soft deleted.
+func (orm *ORM) UnscopedJobRunsWithStatus(cb func(*models.JobRun), statuses ...models.RunStatus) error {
+	return orm.unscopedJobRunsWhere(cb, orm.db.Where("status IN (?)", statuses))
+}
+
+// UnscopedJobRunsWithStatusUpdatedBefore passes the JobRuns with one of the
+// statuses which have not been updated since before to a callback, one by
+// one, including those that were soft deleted.
+func (orm *ORM) UnscopedJobRunsWithStatusUpdatedBefore(cb func(*models.JobRun), before time.Time, statuses ...models.RunStatus) error {
+	return orm.unscopedJobRunsWhere(cb, orm.db.Where("status IN (?) AND updated_at < ?", statuses, before))
+}
+
+// ClaimStuckJobRun marks the run as updated if it still has the status and
+// has not been updated since before, returning whether it did, so that a run
+// which moved on since it was found stuck is left alone.
+func (orm *ORM) ClaimStuckJobRun(runID *models.ID, status models.RunStatus, before time.Time) (bool, error) {
+	res := orm.db.Exec(
+		"UPDATE job_runs SET updated_at = now() WHERE id = ? AND status = ? AND updated_at < ?",
+		runID, status, before,
+	)
+	return res.RowsAffected == 1, res.Error
+}
+
+// CountJobRunsByStatus returns the number of runs with each of the given
+// statuses, leaving out the statuses no run has.
+func (orm *ORM) CountJobRunsByStatus(statuses ...models.RunStatus) (map[models.RunStatus]int, error) {
+	rows, err := orm.db.Raw(`
+		SELECT status, COUNT(*) FROM job_runs
+		WHERE status IN (?) AND deleted_at IS NULL
+		GROUP BY status
+	`, statuses).Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "while counting job runs by status")
+	}
+	defer rows.Close()
+
+	counts := make(map[models.RunStatus]int)
+	for rows.Next() {
+		var status models.RunStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+func (orm *ORM) unscopedJobRunsWhere(cb func(*models.JobRun), where *gorm.DB) error {
+	var runIDs []string
+	err := where.Unscoped().
+		Table("job_runs").
+		Order("created_at asc").
+		Pluck("ID", &runIDs).Error
+	if err != nil {
+		return errors.Wrap(err, "finding job ids")
+	}
+
+	return Batch(BatchSize, func(offset, limit uint) (uint, error) {
+		batchIDs := runIDs[offset:utils.MinUint(limit, uint(len(runIDs)))]
+		var runs []models.JobRun
+		err := orm.Unscoped().
+			preloadJobRuns().
+			Order("job_runs.created_at asc").
+			Find(&runs, "job_runs.id IN (?)", batchIDs).Error
+		if err != nil {
+			return 0, errors.Wrap(err, "error fetching job run batch")
+		}
+
+		for _, run := range runs {
+			r := run
+			cb(&r)
+		}
+		return uint(len(batchIDs)), nil
+	})
+}
+
+// AnyJobWithType returns true if there is at least one job associated with
+// the type name specified and false otherwise
+func (orm *ORM) AnyJobWithType(taskTypeName string) (bool, error) {
+	db := orm.db
+	var taskSpec models.TaskSpec
+	rval := db.Where("type = ?", taskTypeName).First(&taskSpec)
+	found := !rval.RecordNotFound()
+	return found, ignoreRecordNotFound(rval)
+}
+
+// CreateTx finds and overwrites a transaction by its surrogate key, if it exists, or
+// creates it
+func (orm *ORM) CreateTx(tx *models.Tx) (*models.Tx, error) {
+
+	err := orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		var query *gorm.DB
+		foundTx := models.Tx{}
+		if tx.SurrogateID.Valid {
+			query = dbtx.First(&foundTx, "surrogate_id = ?", tx.SurrogateID.ValueOrZero())
+		} else {
+			query = dbtx.First(&foundTx, "hash = ?", tx.Hash)
+		}
+		err := query.Error
+
+		if err != nil && !gorm.IsRecordNotFoundError(err) {
+			return errors.Wrap(err, "CreateTx#First failed")
+		}
+
+		if gorm.IsRecordNotFoundError(err) {
+			return dbtx.Create(tx).Error
+		}
+
+		tx.ID = foundTx.ID
+		return dbtx.Save(tx).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// AddTxAttempt attaches a new attempt to a Tx, after the attempt has been sent to the chain
+func (orm *ORM) AddTxAttempt(tx *models.Tx, newTxAttempt *models.Tx) (*models.TxAttempt, error) {
+
+	tx.From = newTxAttempt.From
+	tx.Nonce = newTxAttempt.Nonce
+	tx.GasPrice = newTxAttempt.GasPrice
+	tx.Hash = newTxAttempt.Hash
+	tx.SentAt = newTxAttempt.SentAt
+	tx.SignedRawTx = newTxAttempt.SignedRawTx
+	txAttempt := &models.TxAttempt{
+		Hash:        newTxAttempt.Hash,
+		GasPrice:    newTxAttempt.GasPrice,
+		SentAt:      newTxAttempt.SentAt,
+		SignedRawTx: newTxAttempt.SignedRawTx,
+	}
+	tx.Attempts = append(tx.Attempts, txAttempt)
+
+	return txAttempt, orm.db.Save(tx).Error
+}
+
+// MarkTxSafe updates the database for the given transaction and attempt to
+// show that the transaction has not just been confirmed,
+// but has met the minimum number of outgoing confirmations to be deemed
+// safely written on the blockchain.
+func (orm *ORM) MarkTxSafe(tx *models.Tx, txAttempt *models.TxAttempt) error {
+	txAttempt.Confirmed = true
+	tx.Hash = txAttempt.Hash
+	tx.GasPrice = txAttempt.GasPrice
+	tx.Confirmed = txAttempt.Confirmed
+	tx.SentAt = txAttempt.SentAt
+	tx.SignedRawTx = txAttempt.SignedRawTx
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		if err := dbtx.Save(tx).Error; err != nil {
+			return err
+		}
+		return dbtx.Save(txAttempt).Error
+	})
+}
+
+func preloadAttempts(dbtx *gorm.DB) *gorm.DB {
+	return dbtx.
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at asc")
+		})
+}
+
+// FindTx returns the specific transaction for the passed ID.
+func (orm *ORM) FindTx(ID uint64) (*models.Tx, error) {
+	tx := &models.Tx{}
+	err := preloadAttempts(orm.db).First(tx, "id = ?", ID).Error
+	return tx, err
+}
+
+// FindAllTxsInNonceRange returns an array of transactions matching the inclusive range between beginningNonce and endingNonce
+func (orm *ORM) FindAllTxsInNonceRange(beginningNonce uint, endingNonce uint) ([]models.Tx, error) {
+	var txs []models.Tx
+	err := orm.db.Order("nonce ASC, sent_at ASC").Where(`nonce BETWEEN ? AND ?`, beginningNonce, endingNonce).Find(&txs).Error
+	return txs, err
+}
+
+// UnconfirmedTxsInNonceRange returns the unconfirmed transactions with
+// nonces from beginningNonce to endingNonce, with their attempts, those from
+// the address only if it is given.
+func (orm *ORM) UnconfirmedTxsInNonceRange(from *common.Address, beginningNonce, endingNonce uint64) ([]models.Tx, error) {
+	var txs []models.Tx
+	scope := preloadAttempts(orm.db).
+		Where("confirmed = ? AND nonce BETWEEN ? AND ?", false, beginningNonce, endingNonce)
+	if from != nil {
+		scope = scope.Where(`"from" = ?`, *from)
+	}
+	err := scope.Order("nonce ASC").Find(&txs).Error
+	return txs, err
+}
+
+// UnconfirmedTransactions returns the transactions which are not confirmed
+// yet, limited by passed parameters.
+func (orm *ORM) UnconfirmedTransactions(offset, limit int) ([]models.Tx, int, error) {
+	var count int
+	if err := orm.db.Model(&models.Tx{}).Where("confirmed = ?", false).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var txs []models.Tx
+	err := orm.db.
+		Where("confirmed = ?", false).
+		Order("id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(&txs).Error
+	return txs, count, err
+}
+
+// FindTxsBySenderAndRecipient returns an array of transactions sent by `sender` to `recipient`
+func (orm *ORM) FindTxsBySenderAndRecipient(sender, recipient common.Address, offset, limit uint) ([]models.Tx, error) {
+	var txs []models.Tx
+	err := orm.db.
+		Where(`"from" = ? AND "to" = ?`, sender, recipient).
+		Order("nonce DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&txs).Error
+	return txs, err
+}
+
+// FindTxByAttempt returns the specific transaction attempt with the hash.
+func (orm *ORM) FindTxByAttempt(hash common.Hash) (*models.Tx, *models.TxAttempt, error) {
+	txAttempt := &models.TxAttempt{}
+	if err := orm.db.First(txAttempt, "hash = ?", hash).Error; err != nil {
+		return nil, nil, err
+	}
+	tx, err := orm.FindTx(txAttempt.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx, txAttempt, nil
+}
+
+// FindTxBySurrogateID returns the transaction with the given surrogate ID.
+func (orm *ORM) FindTxBySurrogateID(surrogateID string) (*models.Tx, error) {
+	tx := &models.Tx{}
+	err := orm.db.First(tx, "surrogate_id = ?", surrogateID).Error
+	return tx, err
+}
+
+// FindTxAttempt returns an individual TxAttempt
+func (orm *ORM) FindTxAttempt(hash common.Hash) (*models.TxAttempt, error) {
+	txAttempt := &models.TxAttempt{}
+	if err := orm.db.Preload("Tx").First(txAttempt, "hash = ?", hash).Error; err != nil {
+		return nil, errors.Wrap(err, "FindTxByAttempt First(txAttempt) failed")
+	}
+	return txAttempt, nil
+}
+
+// GetLastNonce retrieves the last known nonce in the database for an account
+func (orm *ORM) GetLastNonce(address common.Address) (uint64, error) {
+	var transaction models.Tx
+	rval := orm.db.Order("nonce desc").Where(`"from" = ?`, address).First(&transaction)
+	return transaction.Nonce, ignoreRecordNotFound(rval)
+}
+
+// MarkRan will set Ran to true for a given initiator
+func (orm *ORM) MarkRan(i *models.Initiator, ran bool) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		var newi models.Initiator
+		if err := dbtx.Select("ran").First(&newi, "ID = ?", i.ID).Error; err != nil {
+			return err
+		}
+
+		if ran && newi.Ran {
+			return fmt.Errorf("Initiator %v for job spec %s has already been run", i.ID, i.JobSpecID.String())
+		}
+
+		if err := dbtx.Model(i).UpdateColumn("ran", ran).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// SetNextRunAt records when a recurring runat initiator runs next, so that
+// its pending run is not lost when the node restarts.
+func (orm *ORM) SetNextRunAt(i *models.Initiator, at time.Time) error {
+	if err := orm.db.Model(i).UpdateColumn("next_run_at", at).Error; err != nil {
+		return err
+	}
+	i.NextRunAt = null.TimeFrom(at)
+	return nil
+}
+
+// FindUser will return the one API user, or an error.
+func (orm *ORM) FindUser() (models.User, error) {
+	user := models.User{}
+	err := orm.db.
+		Set("gorm:auto_preload", true).
+		Order("created_at desc").
+		First(&user).Error
+	return user, err
+}
+
+// AuthorizedUserWithSession will return the one API user if the Session ID exists
+// and hasn't expired, and update session's LastUsed field.
+func (orm *ORM) AuthorizedUserWithSession(sessionID string, sessionDuration time.Duration) (models.User, error) {
+	if len(sessionID) == 0 {
+		return models.User{}, errors.New("Session ID cannot be empty")
+	}
+
+	var session models.Session
+	err := orm.db.First(&session, "id = ?", hashSessionID(sessionID)).Error
+	if err != nil {
+		return models.User{}, err
+	}
+	now := time.Now()
+	if session.LastUsed.Add(sessionDuration).Before(now) {
+		return models.User{}, errors.New("Session has expired")
+	}
+	// A read only node keeps serving the sessions it has, without recording
+	// their use.
+	if !orm.ReadOnly() {
+		session.LastUsed = now
+		if err := orm.db.Save(&session).Error; err != nil {
+			return models.User{}, err
+		}
+	}
+	return orm.FindUser()
+}
+
+// DeleteUser will delete the API User in the db.
+func (orm *ORM) DeleteUser() (models.User, error) {
+	user, err := orm.FindUser()
+	if err != nil {
+		return user, err
+	}
+
+	return user, orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		if err := dbtx.Delete(&user).Error; err != nil {
+			return err
+		}
+
+		if err := dbtx.Delete(models.Session{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// DeleteUserSession will erase the session ID for the sole API User.
+func (orm *ORM) DeleteUserSession(sessionID string) error {
+	return orm.db.Where("id = ?", hashSessionID(sessionID)).Delete(models.Session{}).Error
+}
+
+// DeleteBridgeType removes the bridge type
+func (orm *ORM) DeleteBridgeType(bt *models.BridgeType) error {
+	defer orm.caches.bridges.invalidate(bt.Name.String())
+	return orm.db.Delete(bt).Error
+}
+
+// CreateSession will check the password in the SessionRequest against
+// the hashed API User password in the db.
+func (orm *ORM) CreateSession(sr models.SessionRequest) (string, error) {
+	user, err := orm.FindUser()
+	if err != nil {
+		return "", err
+	}
+
+	if !constantTimeEmailCompare(sr.Email, user.Email) {
+		return "", errors.New("Invalid email")
+	}
+
+	if utils.CheckPasswordHash(sr.Password, user.HashedPassword) {
+		session := models.NewSession()
+		return session.ID, orm.SaveSession(&session)
+	}
+	return "", errors.New("Invalid password")
+}
+
+const constantTimeEmailLength = 256
+
+func constantTimeEmailCompare(left, right string) bool {
+	length := utils.MaxInt(constantTimeEmailLength, len(left), len(right))
+	leftBytes := make([]byte, length)
+	rightBytes := make([]byte, length)
+	copy(leftBytes, left)
+	copy(rightBytes, right)
+	return subtle.ConstantTimeCompare(leftBytes, rightBytes) == 1
+}
+
+// ClearSessions removes all sessions.
+func (orm *ORM) ClearSessions() error {
+	return orm.db.Delete(models.Session{}).Error
+}
+
+// ClearNonCurrentSessions removes all sessions but the id passed in.
+func (orm *ORM) ClearNonCurrentSessions(sessionID string) error {
+	return orm.db.Where("id <> ?", hashSessionID(sessionID)).Delete(models.Session{}).Error
+}
+
+// SortType defines the different sort orders available.
+type SortType int
+
+const (
+	// Ascending is the sort order going up, i.e. 1,2,3.
+	Ascending SortType = iota
+	// Descending is the sort order going down, i.e. 3,2,1.
+	Descending
+)
+
+func (s SortType) String() string {
+	orderStr := "asc"
+	if s == Descending {
+		orderStr = "desc"
+	}
+	return orderStr
+}
+
+// JobsSorted returns many JobSpecs sorted by CreatedAt from the store adhering
+// to the passed parameters.
+func (orm *ORM) JobsSorted(sort SortType, offset int, limit int) ([]models.JobSpec, int, error) {
+	count, err := orm.CountOf(&models.JobSpec{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []models.JobSpec
+	order := fmt.Sprintf("created_at %s", sort.String())
+	err = orm.getRecords(&jobs, order, offset, limit)
+	return jobs, count, err
+}
+
+// JobsSortedInNamespace returns the jobs in a namespace, sorted by their
+// creation date.
+func (orm *ORM) JobsSortedInNamespace(namespace string, sort SortType, offset int, limit int) ([]models.JobSpec, int, error) {
+	var count int
+	err := orm.db.Model(&models.JobSpec{}).Where("namespace = ?", namespace).Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []models.JobSpec
+	err = orm.db.
+		Set("gorm:auto_preload", true).
+		Where("namespace = ?", namespace).
+		Order(fmt.Sprintf("created_at %s", sort.String())).
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs).Error
+	return jobs, count, err
+}
+
+// TxFrom returns all transactions from a particular address.
+func (orm *ORM) TxFrom(from common.Address) ([]models.Tx, error) {
+	txs := []models.Tx{}
+	return txs, preloadAttempts(orm.db).Find(&txs, `"from" = ?`, from).Error
+}
+
+// Transactions returns all transactions limited by passed parameters.
+func (orm *ORM) Transactions(offset, limit int) ([]models.Tx, int, error) {
+	count, err := orm.CountOf(&models.Tx{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var txs []models.Tx
+	err = orm.getRecords(&txs, "id desc", offset, limit)
+	return txs, count, err
+}
+
+// TxAttempts returns the last tx attempts sorted by sent at descending.
+func (orm *ORM) TxAttempts(offset, limit int) ([]models.TxAttempt, int, error) {
+	count, err := orm.CountOf(&models.TxAttempt{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var attempts []models.TxAttempt
+	err = orm.getRecords(&attempts, "sent_at desc", offset, limit)
+	return attempts, count, err
+}
+
+// UnconfirmedTxAttempts returns all TxAttempts for which the associated Tx is still unconfirmed.
+func (orm *ORM) UnconfirmedTxAttempts() ([]models.TxAttempt, error) {
+	return orm.loadUnconfirmedTxAttempts()
+}
+
+// JobRunsSorted returns job runs ordered and filtered by the passed params.
+func (orm *ORM) JobRunsSorted(sort SortType, offset int, limit int) ([]models.JobRun, int, error) {
+	count, err := orm.CountOf(&models.JobRun{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var runs []models.JobRun
+	order := fmt.Sprintf("created_at %s", sort.String())
+	err = orm.getRecords(&runs, order, offset, limit)
+	return runs, count, err
+}
+
+// JobRunsSortedFor returns job runs for a specific job spec ordered and
+// filtered by the passed params.
+func (orm *ORM) JobRunsSortedFor(id *models.ID, order SortType, offset int, limit int) ([]models.JobRun, int, error) {
+	count, err := orm.JobRunsCountFor(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var runs []models.JobRun
+	err = orm.preloadJobRuns().
+		Where("job_spec_id = ?", id).
+		Order(fmt.Sprintf("created_at %s", order.String())).
+		Limit(limit).
+		Offset(offset).
+		Find(&runs).Error
+	return runs, count, err
+}
+
+// BridgeTypes returns bridge types ordered by name filtered limited by the
+// passed params.
+func (orm *ORM) BridgeTypes(offset int, limit int) ([]models.BridgeType, int, error) {
+	count, err := orm.CountOf(&models.BridgeType{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var bridges []models.BridgeType
+	err = orm.getRecords(&bridges, "name asc", offset, limit)
+	return bridges, count, err
+}
+
+// BridgeTypesInNamespace returns the bridges in a namespace, sorted by name.
+func (orm *ORM) BridgeTypesInNamespace(namespace string, offset int, limit int) ([]models.BridgeType, int, error) {
+	var count int
+	err := orm.db.Model(&models.BridgeType{}).Where("namespace = ?", namespace).Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var bridges []models.BridgeType
+	err = orm.db.
+		Where("namespace = ?", namespace).
+		Order("name asc").
+		Limit(limit).
+		Offset(offset).
+		Find(&bridges).Error
+	return bridges, count, err
+}
+
+// CreateNamespaceToken saves a namespace token.
+func (orm *ORM) CreateNamespaceToken(nt *models.NamespaceToken) error {
+	return orm.db.Create(nt).Error
+}
+
+// FindNamespaceToken returns the namespace token with the given access key.
+func (orm *ORM) FindNamespaceToken(accessKey string) (models.NamespaceToken, error) {
+	var nt models.NamespaceToken
+	return nt, orm.db.First(&nt, "access_key = ?", accessKey).Error
+}
+
+// NamespaceTokens returns the namespace tokens, sorted by namespace.
+func (orm *ORM) NamespaceTokens() ([]models.NamespaceToken, error) {
+	var nts []models.NamespaceToken
+	return nts, orm.db.Order("namespace asc, created_at asc").Find(&nts).Error
+}
+
+// DeleteNamespaceToken deletes the namespace token with the given access
+// key, or errors if there is no such token.
+func (orm *ORM) DeleteNamespaceToken(accessKey string) error {
+	db := orm.db.Where("access_key = ?", accessKey).Delete(&models.NamespaceToken{})
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+	return nil
+}
+
+// SaveUser saves the user.
+func (orm *ORM) SaveUser(user *models.User) error {
+	return orm.db.Save(user).Error
+}
+
+// SaveSession saves the session. Only a hash of its ID is saved, so that
+// sessions cannot be taken over by reading them from the database.
+func (orm *ORM) SaveSession(session *models.Session) error {
+	hashed := *session
+	hashed.ID = hashSessionID(session.ID)
+	return orm.db.Save(&hashed).Error
+}
+
+func hashSessionID(id string) string {
+	hash := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(hash[:])
+}
+
+// SaveTx saves the Ethereum Transaction.
+func (orm *ORM) SaveTx(tx *models.Tx) error {
+	return orm.db.Save(tx).Error
+}
+
+// EncryptPlaintextColumns encrypts the values of encrypted columns which were
+// saved before their column was encrypted.
+func (orm *ORM) EncryptPlaintextColumns() error {
+	return orm.encryptColumns(true)
+}
+
+// ReencryptColumns encrypts every value of encrypted columns again, with the
+// key set for encryption by models.SetColumnEncryptionKeys.
+func (orm *ORM) ReencryptColumns() error {
+	return orm.encryptColumns(false)
+}
+
+func (orm *ORM) encryptColumns(onlyPlaintext bool) error {
+	defer orm.caches.bridges.invalidate()
+	defer orm.caches.externalInitiators.invalidate()
+	defer orm.caches.clientCertificates.invalidate()
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		bridgesQuery, eisQuery, ccsQuery := dbtx, dbtx, dbtx
+		if onlyPlaintext {
+			prefix := models.EncryptedStringPrefix + "%"
+			bridgesQuery = dbtx.Where("outgoing_token NOT LIKE ? OR previous_outgoing_token NOT LIKE ? OR auth_credentials NOT LIKE ?", prefix, prefix, prefix)
+			eisQuery = dbtx.Where("outgoing_token NOT LIKE ? OR outgoing_secret NOT LIKE ? OR signing_secret NOT LIKE ?", prefix, prefix, prefix)
+			ccsQuery = dbtx.Where("pem NOT LIKE ?", prefix)
+		}
+
+		var bridges []models.BridgeType
+		if err := bridgesQuery.Find(&bridges).Error; err != nil {
+			return errors.Wrap(err, "while loading bridges")
+		}
+		for _, bt := range bridges {
+			err := dbtx.Model(&models.BridgeType{}).Where("name = ?", bt.Name).
+				UpdateColumns(map[string]interface{}{
+					"outgoing_token":          bt.OutgoingToken,
+					"previous_outgoing_token": bt.PreviousOutgoingToken,
+					"auth_credentials":        bt.AuthCredentials,
+				}).Error
+			if err != nil {
+				return errors.Wrapf(err, "while encrypting bridge %s", bt.Name)
+			}
+		}
+
+		secretsQuery := dbtx
+		if onlyPlaintext {
+			secretsQuery = dbtx.Where("value NOT LIKE ?", models.EncryptedStringPrefix+"%")
+		}
+		var secrets []models.Secret
+		if err := secretsQuery.Find(&secrets).Error; err != nil {
+			return errors.Wrap(err, "while loading secrets")
+		}
+		for _, secret := range secrets {
+			err := dbtx.Model(&models.Secret{}).Where("name = ?", secret.Name).
+				UpdateColumn("value", secret.Value).Error
+			if err != nil {
+				return errors.Wrapf(err, "while encrypting secret %s", secret.Name)
+			}
+		}
+
+		var eis []models.ExternalInitiator
+		if err := eisQuery.Find(&eis).Error; err != nil {
+			return errors.Wrap(err, "while loading external initiators")
+		}
+		for _, ei := range eis {
+			err := dbtx.Model(&models.ExternalInitiator{}).Where("name = ?", ei.Name).
+				UpdateColumns(map[string]interface{}{
+					"outgoing_token":  ei.OutgoingToken,
+					"outgoing_secret": ei.OutgoingSecret,
+					"signing_secret":  ei.SigningSecret,
+				}).Error
+			if err != nil {
+				return errors.Wrapf(err, "while encrypting external initiator %s", ei.Name)
+			}
+		}
+
+		var ccs []models.ClientCertificate
+		if err := ccsQuery.Find(&ccs).Error; err != nil {
+			return errors.Wrap(err, "while loading client certificates")
+		}
+		for _, cc := range ccs {
+			err := dbtx.Model(&models.ClientCertificate{}).Where("name = ?", cc.Name).
+				UpdateColumn("pem", cc.PEM).Error
+			if err != nil {
+				return errors.Wrapf(err, "while encrypting client certificate %s", cc.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// EncryptSessionSecretColumns moves the bridge auth credentials and client
+// certificates which were encrypted with the session secret, before they
+// were kept in columns encrypted with the master key, to those columns.
+func (orm *ORM) EncryptSessionSecretColumns(secret []byte) error {
+	defer orm.caches.bridges.invalidate()
+	defer orm.caches.clientCertificates.invalidate()
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		var bridges []struct {
+			Name          string
+			EncryptedAuth []byte
+		}
+		err := dbtx.Table("bridge_types").Select("name, encrypted_auth").
+			Where("encrypted_auth IS NOT NULL").Scan(&bridges).Error
+		if err != nil {
+			return errors.Wrap(err, "while loading bridges")
+		}
+		for _, bt := range bridges {
+			plaintext, err := utils.Decrypt(secret, bt.EncryptedAuth)
+			if err != nil {
+				return errors.Wrapf(err, "while decrypting auth of bridge %s", bt.Name)
+			}
+			err = dbtx.Model(&models.BridgeType{}).Where("name = ?", bt.Name).
+				UpdateColumns(map[string]interface{}{
+					"auth_credentials": models.EncryptedString(plaintext),
+					"encrypted_auth":   nil,
+				}).Error
+			if err != nil {
+				return errors.Wrapf(err, "while encrypting auth of bridge %s", bt.Name)
+			}
+		}
+
+		var ccs []struct {
+			Name         string
+			EncryptedPEM []byte
+		}
+		err = dbtx.Table("client_certificates").Select("name, encrypted_pem").
+			Where("encrypted_pem IS NOT NULL").Scan(&ccs).Error
+		if err != nil {
+			return errors.Wrap(err, "while loading client certificates")
+		}
+		for _, cc := range ccs {
+			plaintext, err := utils.Decrypt(secret, cc.EncryptedPEM)
+			if err != nil {
+				return errors.Wrapf(err, "while decrypting client certificate %s", cc.Name)
+			}
+			err = dbtx.Model(&models.ClientCertificate{}).Where("name = ?", cc.Name).
+				UpdateColumns(map[string]interface{}{
+					"pem":           models.EncryptedString(plaintext),
+					"encrypted_pem": nil,
+				}).Error
+			if err != nil {
+				return errors.Wrapf(err, "while encrypting client certificate %s", cc.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// CreateBridgeType saves the bridge type.
+func (orm *ORM) CreateBridgeType(bt *models.BridgeType) error {
+	return orm.db.Create(bt).Error
+}
+
+// UpdateBridgeType updates the bridge type.
+func (orm *ORM) UpdateBridgeType(bt *models.BridgeType, btr *models.BridgeTypeRequest) error {
+	bt.URL = btr.URL
+	bt.Mode = btr.Mode.OrDefault()
+	bt.Confirmations = btr.Confirmations
+	bt.MinimumContractPayment = btr.MinimumContractPayment
+	bt.CacheTTL = btr.CacheTTL
+	bt.ClientCertificate = btr.ClientCertificate
+	bt.MinInterval = btr.MinInterval
+	defer orm.caches.bridges.invalidate(bt.Name.String())
+	return orm.db.Save(bt).Error
+}
 
-		if err != nil && !gorm.IsRecordNotFoundError(err) {
-			return errors.Wrap(err, "CreateTx#First failed")
-		}
+// UpdateBridgeTypeTokens saves the tokens of the bridge, after they were
+// rotated.
+func (orm *ORM) UpdateBridgeTypeTokens(bt *models.BridgeType) error {
+	defer orm.caches.bridges.invalidate(bt.Name.String())
+	return orm.db.Model(bt).UpdateColumns(map[string]interface{}{
+		"incoming_token_hash":          bt.IncomingTokenHash,
+		"outgoing_token":               bt.OutgoingToken,
+		"previous_incoming_token_hash": bt.PreviousIncomingTokenHash,
+		"previous_outgoing_token":      bt.PreviousOutgoingToken,
+		"previous_tokens_expire_at":    bt.PreviousTokensExpireAt,
+		"updated_at":                   time.Now(),
+	}).Error
+}
 
-		if gorm.IsRecordNotFoundError(err) {
-			return dbtx.Create(tx).Error
-		}
+// ErrSecretInOtherNamespace is returned when setting a secret whose name is
+// taken by a secret of another namespace.
+var ErrSecretInOtherNamespace = errors.New("a secret of another namespace has this name")
 
-		tx.ID = foundTx.ID
-		return dbtx.Save(tx).Error
-	})
-	if err != nil {
-		return nil, err
-	}
-	return tx, nil
+// Secrets returns the secrets, by name, without decrypting their values.
+func (orm *ORM) Secrets() ([]models.Secret, error) {
+	var secrets []models.Secret
+	err := orm.db.Select("id, name, namespace, created_at, updated_at").Order("name asc").Find(&secrets).Error
+	return secrets, err
 }
 
-// AddTxAttempt attaches a new attempt to a Tx, after the attempt has been sent to the chain
-func (orm *ORM) AddTxAttempt(tx *models.Tx, newTxAttempt *models.Tx) (*models.TxAttempt, error) {
-	orm.MustEnsureAdvisoryLock()
+// SetSecret saves the secret, replacing the value of the secret with its name
+// if there is one in its namespace, and returning ErrSecretInOtherNamespace
+// if there is one in another.
+func (orm *ORM) SetSecret(secret *models.Secret) error {
+	if orm.ReadOnly() {
+		return ErrReadOnly
+	}
+	now := time.Now()
+	err := orm.db.Raw(`
+		INSERT INTO secrets (name, namespace, value, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+		WHERE secrets.namespace = EXCLUDED.namespace
+		RETURNING id, created_at, updated_at`,
+		secret.Name, secret.Namespace, secret.Value, now, now).
+		Row().
+		Scan(&secret.ID, &secret.CreatedAt, &secret.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrSecretInOtherNamespace
+	}
+	return err
+}
 
-	tx.From = newTxAttempt.From
-	tx.Nonce = newTxAttempt.Nonce
-	tx.GasPrice = newTxAttempt.GasPrice
-	tx.Hash = newTxAttempt.Hash
-	tx.SentAt = newTxAttempt.SentAt
-	tx.SignedRawTx = newTxAttempt.SignedRawTx
-	txAttempt := &models.TxAttempt{
-		Hash:        newTxAttempt.Hash,
-		GasPrice:    newTxAttempt.GasPrice,
-		SentAt:      newTxAttempt.SentAt,
-		SignedRawTx: newTxAttempt.SignedRawTx,
+// SecretValue returns the decrypted value of the secret with the name in the
+// namespace, returning ErrorNotFound if there is none, even if another
+// namespace has a secret with the name.
+func (orm *ORM) SecretValue(namespace, name string) (string, error) {
+	var secret models.Secret
+	if err := orm.db.Where("name = ? AND namespace = ?", name, namespace).First(&secret).Error; err != nil {
+		return "", err
 	}
-	tx.Attempts = append(tx.Attempts, txAttempt)
+	return string(secret.Value), nil
+}
 
-	return txAttempt, orm.db.Save(tx).Error
+// SecretNamespace returns the namespace of the secret with the name,
+// returning ErrorNotFound if there is none.
+func (orm *ORM) SecretNamespace(name string) (string, error) {
+	var secret models.Secret
+	err := orm.db.Select("namespace").Where("name = ?", name).First(&secret).Error
+	return secret.Namespace, err
 }
 
-// MarkTxSafe updates the database for the given transaction and attempt to
-// show that the transaction has not just been confirmed,
-// but has met the minimum number of outgoing confirmations to be deemed
-// safely written on the blockchain.
-func (orm *ORM) MarkTxSafe(tx *models.Tx, txAttempt *models.TxAttempt) error {
-	orm.MustEnsureAdvisoryLock()
-	txAttempt.Confirmed = true
-	tx.Hash = txAttempt.Hash
-	tx.GasPrice = txAttempt.GasPrice
-	tx.Confirmed = txAttempt.Confirmed
-	tx.SentAt = txAttempt.SentAt
-	tx.SignedRawTx = txAttempt.SignedRawTx
-	return orm.db.Save(tx).Error
+// JobNamespace returns the namespace of the job with the ID, even if it was
+// deleted.
+func (orm *ORM) JobNamespace(id *models.ID) (string, error) {
+	var job models.JobSpec
+	err := orm.db.Unscoped().Select("namespace").Where("id = ?", id).First(&job).Error
+	return job.Namespace, err
 }
 
-func preloadAttempts(dbtx *gorm.DB) *gorm.DB {
-	return dbtx.
-		Preload("Attempts", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at asc")
-		})
+// DeleteSecret removes the secret with the name, returning ErrorNotFound if
+// there is none.
+func (orm *ORM) DeleteSecret(name string) error {
+	result := orm.db.Where("name = ?", name).Delete(&models.Secret{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+	return nil
 }
 
-// FindTx returns the specific transaction for the passed ID.
-func (orm *ORM) FindTx(ID uint64) (*models.Tx, error) {
-	orm.MustEnsureAdvisoryLock()
-	tx := &models.Tx{}
-	err := preloadAttempts(orm.db).First(tx, "id = ?", ID).Error
-	return tx, err
+// WasmModules returns the WebAssembly modules, by name, without their
+// binaries.
+func (orm *ORM) WasmModules() ([]models.WasmModule, error) {
+	var modules []models.WasmModule
+	err := orm.db.Select("id, name, hash, size, created_at").Order("name asc").Find(&modules).Error
+	return modules, err
 }
 
-// FindAllTxsInNonceRange returns an array of transactions matching the inclusive range between beginningNonce and endingNonce
-func (orm *ORM) FindAllTxsInNonceRange(beginningNonce uint, endingNonce uint) ([]models.Tx, error) {
-	orm.MustEnsureAdvisoryLock()
-	var txs []models.Tx
-	err := orm.db.Order("nonce ASC, sent_at ASC").Where(`nonce BETWEEN ? AND ?`, beginningNonce, endingNonce).Find(&txs).Error
-	return txs, err
+// CreateWasmModule saves the WebAssembly module.
+func (orm *ORM) CreateWasmModule(module *models.WasmModule) error {
+	return orm.db.Create(module).Error
 }
 
-// FindTxsBySenderAndRecipient returns an array of transactions sent by `sender` to `recipient`
-func (orm *ORM) FindTxsBySenderAndRecipient(sender, recipient common.Address, offset, limit uint) ([]models.Tx, error) {
-	orm.MustEnsureAdvisoryLock()
-	var txs []models.Tx
-	err := orm.db.
-		Where(`"from" = ? AND "to" = ?`, sender, recipient).
-		Order("nonce DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&txs).Error
-	return txs, err
+// FindWasmModule returns the WebAssembly module with the name.
+func (orm *ORM) FindWasmModule(name string) (models.WasmModule, error) {
+	var module models.WasmModule
+	err := orm.db.Where("name = ?", name).First(&module).Error
+	return module, err
 }
 
-// FindTxByAttempt returns the specific transaction attempt with the hash.
-func (orm *ORM) FindTxByAttempt(hash common.Hash) (*models.Tx, *models.TxAttempt, error) {
-	orm.MustEnsureAdvisoryLock()
-	txAttempt := &models.TxAttempt{}
-	if err := orm.db.First(txAttempt, "hash = ?", hash).Error; err != nil {
-		return nil, nil, err
+// FindWasmModuleHash returns the hash of the WebAssembly module with the
+// name, without reading its binary.
+func (orm *ORM) FindWasmModuleHash(name string) (string, error) {
+	var module models.WasmModule
+	err := orm.db.Select("hash").Where("name = ?", name).First(&module).Error
+	return module.Hash, err
+}
+
+// DeleteWasmModule removes the WebAssembly module with the name, returning
+// ErrorNotFound if there is none.
+func (orm *ORM) DeleteWasmModule(name string) error {
+	result := orm.db.Where("name = ?", name).Delete(&models.WasmModule{})
+	if result.Error != nil {
+		return result.Error
 	}
-	tx, err := orm.FindTx(txAttempt.TxID)
-	if err != nil {
-		return nil, nil, err
+	if result.RowsAffected == 0 {
+		return ErrorNotFound
 	}
-	return tx, txAttempt, nil
+	return nil
 }
 
-// FindTxAttempt returns an individual TxAttempt
-func (orm *ORM) FindTxAttempt(hash common.Hash) (*models.TxAttempt, error) {
-	orm.MustEnsureAdvisoryLock()
-	txAttempt := &models.TxAttempt{}
-	if err := orm.db.Preload("Tx").First(txAttempt, "hash = ?", hash).Error; err != nil {
-		return nil, errors.Wrap(err, "FindTxByAttempt First(txAttempt) failed")
+// CreateBridgeCallback saves the token a bridge is to call a run back with.
+func (orm *ORM) CreateBridgeCallback(callback *models.BridgeCallback) error {
+	return orm.db.Create(callback).Error
+}
+
+// ConsumeBridgeCallback returns true if the token is the callback of the task
+// run of the run which has not expired at now, deleting it so that it cannot
+// be used again.
+func (orm *ORM) ConsumeBridgeCallback(jobRunID, taskRunID *models.ID, token string, now time.Time) (bool, error) {
+	hash, err := models.BridgeCallbackTokenHash(token)
+	if err != nil {
+		return false, err
 	}
-	return txAttempt, nil
+	result := orm.exec(`
+		DELETE FROM bridge_callbacks
+		WHERE job_run_id = ? AND task_run_id = ? AND token_hash = ? AND (expires_at IS NULL OR expires_at > ?)`,
+		jobRunID, taskRunID, hash, now)
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "while consuming bridge callback")
+	}
+	return result.RowsAffected > 0, nil
 }
 
-// GetLastNonce retrieves the last known nonce in the database for an account
-func (orm *ORM) GetLastNonce(address common.Address) (uint64, error) {
-	orm.MustEnsureAdvisoryLock()
-	var transaction models.Tx
-	rval := orm.db.Order("nonce desc").Where(`"from" = ?`, address).First(&transaction)
-	return transaction.Nonce, ignoreRecordNotFound(rval)
+// DeleteBridgeCallback removes the callback, for a bridge which answered
+// without calling back.
+func (orm *ORM) DeleteBridgeCallback(id uint64) error {
+	return orm.exec(`DELETE FROM bridge_callbacks WHERE id = ?`, id).Error
 }
 
-// MarkRan will set Ran to true for a given initiator
-func (orm *ORM) MarkRan(i *models.Initiator, ran bool) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
-		var newi models.Initiator
-		if err := dbtx.Select("ran").First(&newi, "ID = ?", i.ID).Error; err != nil {
-			return err
-		}
+// ExpiredBridgeCallbackRuns returns the runs whose task runs are still
+// pending on a bridge whose callbacks have all expired at now, the bridge
+// never having called them back.
+func (orm *ORM) ExpiredBridgeCallbackRuns(now time.Time) ([]*models.ID, error) {
+	rows, err := orm.db.Raw(`
+		SELECT DISTINCT bridge_callbacks.job_run_id FROM bridge_callbacks
+		JOIN job_runs ON job_runs.id = bridge_callbacks.job_run_id
+		JOIN task_runs ON task_runs.id = bridge_callbacks.task_run_id
+		WHERE job_runs.status = ? AND task_runs.status = ? AND bridge_callbacks.expires_at <= ?
+		AND NOT EXISTS (
+			SELECT 1 FROM bridge_callbacks live
+			WHERE live.task_run_id = bridge_callbacks.task_run_id
+			AND (live.expires_at IS NULL OR live.expires_at > ?)
+		)`, models.RunStatusPendingBridge, models.RunStatusPendingBridge, now, now).Rows()
+	if err != nil {
+		return nil, errors.Wrap(err, "while finding runs with expired bridge callbacks")
+	}
+	defer rows.Close()
 
-		if ran && newi.Ran {
-			return fmt.Errorf("Initiator %v for job spec %s has already been run", i.ID, i.JobSpecID.String())
+	var ids []*models.ID
+	for rows.Next() {
+		id := &models.ID{}
+		if err := rows.Scan(id); err != nil {
+			return nil, errors.Wrap(err, "while finding runs with expired bridge callbacks")
 		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
 
-		if err := dbtx.Model(i).UpdateColumn("ran", ran).Error; err != nil {
-			return err
-		}
+// DeleteExpiredBridgeCallbacks removes the callbacks which expired at now,
+// and those of the task runs which finished, which can no longer be called
+// back, whether or not they expire.
+func (orm *ORM) DeleteExpiredBridgeCallbacks(now time.Time) error {
+	return orm.exec(`
+		DELETE FROM bridge_callbacks WHERE expires_at <= ? OR task_run_id IN (
+			SELECT id FROM task_runs WHERE status IN (?, ?, ?)
+		)`,
+		now, models.RunStatusCompleted, models.RunStatusErrored, models.RunStatusCancelled).Error
+}
 
-		return nil
-	})
+// CreateInitiator saves the initiator.
+func (orm *ORM) CreateInitiator(initr *models.Initiator) error {
+	if initr.JobSpecID == nil {
+		// NOTE: This hangs forever if we don't check this here and the
+		// supplied initiator does not have a JobSpecID set.
+		// I do not know why. Seems to be something going wrong inside gorm
+		logger.ORM.Error("cannot create initiator without job spec ID")
+		return errors.New("requires job spec ID")
+	}
+	defer orm.caches.jobs.invalidate(initr.JobSpecID.String())
+	return orm.db.Create(initr).Error
 }
 
-// FindUser will return the one API user, or an error.
-func (orm *ORM) FindUser() (models.User, error) {
-	orm.MustEnsureAdvisoryLock()
-	user := models.User{}
-	err := orm.db.
-		Set("gorm:auto_preload", true).
-		Order("created_at desc").
-		First(&user).Error
-	return user, err
+// KafkaOffsets returns the offsets a kafka initiator has recorded for the
+// partitions of its topics.
+func (orm *ORM) KafkaOffsets(initiatorID uint32) ([]models.KafkaOffset, error) {
+	offsets := []models.KafkaOffset{}
+	return offsets, orm.db.
+		Where("initiator_id = ?", initiatorID).
+		Order("topic asc, partition asc").
+		Find(&offsets).Error
 }
 
-// AuthorizedUserWithSession will return the one API user if the Session ID exists
-// and hasn't expired, and update session's LastUsed field.
-func (orm *ORM) AuthorizedUserWithSession(sessionID string, sessionDuration time.Duration) (models.User, error) {
-	orm.MustEnsureAdvisoryLock()
-	if len(sessionID) == 0 {
-		return models.User{}, errors.New("Session ID cannot be empty")
-	}
+// SaveKafkaOffset records the next offset a kafka initiator will read from a
+// topic partition, replacing any offset previously saved for it.
+func (orm *ORM) SaveKafkaOffset(offset *models.KafkaOffset) error {
+	offset.UpdatedAt = time.Now()
+	return orm.exec(`
+		INSERT INTO kafka_offsets (initiator_id, topic, partition, "offset", updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (initiator_id, topic, partition)
+		DO UPDATE SET "offset" = EXCLUDED."offset", updated_at = EXCLUDED.updated_at
+	`, offset.InitiatorID, offset.Topic, offset.Partition, offset.Offset, offset.UpdatedAt).Error
+}
 
-	var session models.Session
-	err := orm.db.First(&session, "id = ?", sessionID).Error
-	if err != nil {
-		return models.User{}, err
-	}
+// SaveClientCertificate creates the client certificate, or replaces the
+// certificate of the same name.
+func (orm *ORM) SaveClientCertificate(cc *models.ClientCertificate) error {
+	defer orm.caches.clientCertificates.invalidate()
 	now := time.Now()
-	if session.LastUsed.Add(sessionDuration).Before(now) {
-		return models.User{}, errors.New("Session has expired")
-	}
-	session.LastUsed = now
-	if err := orm.db.Save(&session).Error; err != nil {
-		return models.User{}, err
-	}
-	return orm.FindUser()
+	cc.UpdatedAt = now
+	return orm.exec(`
+		INSERT INTO client_certificates (name, subject, not_after, pem, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			subject = EXCLUDED.subject,
+			not_after = EXCLUDED.not_after,
+			pem = EXCLUDED.pem,
+			updated_at = EXCLUDED.updated_at
+	`, cc.Name, cc.Subject, cc.NotAfter, cc.PEM, now, now).Error
 }
 
-// DeleteUser will delete the API User in the db.
-func (orm *ORM) DeleteUser() (models.User, error) {
-	orm.MustEnsureAdvisoryLock()
-	user, err := orm.FindUser()
+// FindClientCertificate returns the client certificate with the given name.
+func (orm *ORM) FindClientCertificate(name string) (models.ClientCertificate, error) {
+	ccs, err := orm.ClientCertificates()
 	if err != nil {
-		return user, err
+		return models.ClientCertificate{}, err
 	}
-
-	return user, orm.convenientTransaction(func(dbtx *gorm.DB) error {
-		if err := dbtx.Delete(&user).Error; err != nil {
-			return err
+	for _, cc := range ccs {
+		if cc.Name == name {
+			return cc, nil
 		}
+	}
+	return models.ClientCertificate{}, ErrorNotFound
+}
 
-		if err := dbtx.Delete(models.Session{}).Error; err != nil {
-			return err
-		}
+// ClientCertificates returns all client certificates, ordered by name. As
+// there are few of them, the lookup cache holds them all under a single key,
+// so that the default certificate most bridge requests look up is found
+// missing without reading the database.
+func (orm *ORM) ClientCertificates() ([]models.ClientCertificate, error) {
+	cache := orm.lookupCache(orm.caches.clientCertificates)
+	cached, generation, ok := cache.get("all")
+	if ok {
+		return append([]models.ClientCertificate(nil), cached.([]models.ClientCertificate)...), nil
+	}
+	var ccs []models.ClientCertificate
+	if err := orm.db.Order("name asc").Find(&ccs).Error; err != nil {
+		return nil, err
+	}
+	cache.put("all", append([]models.ClientCertificate(nil), ccs...), generation)
+	return ccs, nil
+}
 
-		return nil
-	})
+// DeleteClientCertificate removes the client certificate with the given
+// name.
+func (orm *ORM) DeleteClientCertificate(name string) error {
+	defer orm.caches.clientCertificates.invalidate()
+	return orm.exec("DELETE FROM client_certificates WHERE name = ?", name).Error
 }
 
-// DeleteUserSession will erase the session ID for the sole API User.
-func (orm *ORM) DeleteUserSession(sessionID string) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Where("id = ?", sessionID).Delete(models.Session{}).Error
+// BridgesUsingClientCertificate returns the names of the bridges presenting
+// the named client certificate.
+func (orm *ORM) BridgesUsingClientCertificate(name string) ([]string, error) {
+	var names []string
+	err := orm.db.Model(&models.BridgeType{}).
+		Where("client_certificate = ?", name).
+		Order("name asc").
+		Pluck("name", &names).Error
+	return names, err
 }
 
-// DeleteBridgeType removes the bridge type
-func (orm *ORM) DeleteBridgeType(bt *models.BridgeType) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Delete(bt).Error
+// FindBridgeHealth returns the health of the named bridge, or ErrorNotFound
+// if it has never been called.
+func (orm *ORM) FindBridgeHealth(name models.TaskType) (models.BridgeHealth, error) {
+	var health models.BridgeHealth
+	return health, orm.db.First(&health, "bridge_name = ?", name.String()).Error
 }
 
-// CreateSession will check the password in the SessionRequest against
-// the hashed API User password in the db.
-func (orm *ORM) CreateSession(sr models.SessionRequest) (string, error) {
-	orm.MustEnsureAdvisoryLock()
-	user, err := orm.FindUser()
-	if err != nil {
-		return "", err
-	}
+// FindBridgeHealths returns the health of the named bridges which have been
+// called.
+func (orm *ORM) FindBridgeHealths(names []string) ([]models.BridgeHealth, error) {
+	var healths []models.BridgeHealth
+	return healths, orm.db.Where("bridge_name IN (?)", names).Find(&healths).Error
+}
 
-	if !constantTimeEmailCompare(sr.Email, user.Email) {
-		return "", errors.New("Invalid email")
+// RecordBridgeSuccess records a successful request to a bridge at once,
+// along with those batched for it, closing its circuit.
+func (orm *ORM) RecordBridgeSuccess(name models.TaskType, latency time.Duration) error {
+	s := bridgeSuccesses{count: 1, latency: latency, lastAt: time.Now()}
+	if batched := orm.bridgeSuccesses.take(name); batched != nil {
+		s.count += batched.count
+		s.latency += batched.latency
 	}
+	return orm.writeBridgeSuccesses(name, s)
+}
 
-	if utils.CheckPasswordHash(sr.Password, user.HashedPassword) {
-		session := models.NewSession()
-		return session.ID, orm.db.Save(&session).Error
+func (orm *ORM) writeBridgeSuccesses(name models.TaskType, s bridgeSuccesses) error {
+	return orm.exec(`
+		INSERT INTO bridge_healths (bridge_name, successes, latency_total, last_success_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (bridge_name) DO UPDATE SET
+			successes = bridge_healths.successes + EXCLUDED.successes,
+			consecutive_failures = 0,
+			latency_total = bridge_healths.latency_total + EXCLUDED.latency_total,
+			last_success_at = EXCLUDED.last_success_at,
+			circuit_opened_at = NULL,
+			updated_at = EXCLUDED.updated_at
+	`, name, s.count, int64(s.latency), s.lastAt, s.lastAt).Error
+}
+
+// RecordBridgeFailure records a failed request to a bridge, after the
+// successes batched for it, opening its circuit once it has failed threshold
+// requests in a row. A threshold of 0 never opens the circuit.
+func (orm *ORM) RecordBridgeFailure(name models.TaskType, latency time.Duration, cause error, threshold uint) error {
+	if batched := orm.bridgeSuccesses.take(name); batched != nil {
+		if err := orm.writeBridgeSuccesses(name, *batched); err != nil {
+			return err
+		}
 	}
-	return "", errors.New("Invalid password")
+	now := time.Now()
+	var openedAt null.Time
+	if threshold == 1 {
+		openedAt = null.TimeFrom(now)
+	}
+	return orm.exec(`
+		INSERT INTO bridge_healths (bridge_name, failures, consecutive_failures, latency_total, last_error, last_failure_at, circuit_opened_at, updated_at)
+		VALUES (?, 1, 1, ?, ?, ?, ?, ?)
+		ON CONFLICT (bridge_name) DO UPDATE SET
+			failures = bridge_healths.failures + 1,
+			consecutive_failures = bridge_healths.consecutive_failures + 1,
+			latency_total = bridge_healths.latency_total + EXCLUDED.latency_total,
+			last_error = EXCLUDED.last_error,
+			last_failure_at = EXCLUDED.last_failure_at,
+			circuit_opened_at = CASE
+				WHEN ? > 0 AND bridge_healths.consecutive_failures + 1 >= ?
+				THEN COALESCE(bridge_healths.circuit_opened_at, EXCLUDED.last_failure_at)
+				ELSE NULL
+			END,
+			updated_at = EXCLUDED.updated_at
+	`, name, int64(latency), cause.Error(), now, openedAt, now, threshold, threshold).Error
 }
 
-const constantTimeEmailLength = 256
+// ProbeBridge claims the next request to a bridge whose circuit has been open
+// for longer than timeout, returning true if the request is to be let through
+// to probe whether the bridge has recovered. The circuit is held open for
+// another timeout meanwhile, so that only one request probes at a time.
+func (orm *ORM) ProbeBridge(name models.TaskType, timeout time.Duration) (bool, error) {
+	now := time.Now()
+	db := orm.exec(`
+		UPDATE bridge_healths SET circuit_opened_at = ?
+		WHERE bridge_name = ? AND circuit_opened_at <= ?
+	`, now, name, now.Add(-timeout))
+	return db.RowsAffected > 0, db.Error
+}
 
-func constantTimeEmailCompare(left, right string) bool {
-	length := utils.MaxInt(constantTimeEmailLength, len(left), len(right))
-	leftBytes := make([]byte, length)
-	rightBytes := make([]byte, length)
-	copy(leftBytes, left)
-	copy(rightBytes, right)
-	return subtle.ConstantTimeCompare(leftBytes, rightBytes) == 1
+// FindFeedHealth returns the health of the feed with the given name, or
+// ErrorNotFound if it has never been polled.
+func (orm *ORM) FindFeedHealth(feed string) (models.FeedHealth, error) {
+	var health models.FeedHealth
+	return health, orm.db.First(&health, "feed = ?", feed).Error
 }
 
-// ClearSessions removes all sessions.
-func (orm *ORM) ClearSessions() error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Delete(models.Session{}).Error
+// FeedHealths returns the health of every feed which has been polled.
+func (orm *ORM) FeedHealths() ([]models.FeedHealth, error) {
+	var healths []models.FeedHealth
+	return healths, orm.db.Order("feed asc").Find(&healths).Error
 }
 
-// ClearNonCurrentSessions removes all sessions but the id passed in.
-func (orm *ORM) ClearNonCurrentSessions(sessionID string) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Where("id <> ?", sessionID).Delete(models.Session{}).Error
+// RecordFeedSuccess records a successful poll of a feed, releasing it from
+// quarantine.
+func (orm *ORM) RecordFeedSuccess(feed string, latency time.Duration) error {
+	now := time.Now()
+	return orm.exec(`
+		INSERT INTO feed_healths (feed, successes, latency_total, last_success_at, updated_at)
+		VALUES (?, 1, ?, ?, ?)
+		ON CONFLICT (feed) DO UPDATE SET
+			successes = feed_healths.successes + 1,
+			consecutive_failures = 0,
+			latency_total = feed_healths.latency_total + EXCLUDED.latency_total,
+			last_success_at = EXCLUDED.last_success_at,
+			quarantined_at = NULL,
+			updated_at = EXCLUDED.updated_at
+	`, feed, int64(latency), now, now).Error
 }
 
-// SortType defines the different sort orders available.
-type SortType int
+// RecordFeedFailure records a failed poll of a feed, quarantining it once it
+// has failed threshold polls in a row. A threshold of 0 never quarantines the
+// feed.
+func (orm *ORM) RecordFeedFailure(feed string, latency time.Duration, cause error, threshold uint) error {
+	now := time.Now()
+	var quarantinedAt null.Time
+	if threshold == 1 {
+		quarantinedAt = null.TimeFrom(now)
+	}
+	return orm.exec(`
+		INSERT INTO feed_healths (feed, failures, consecutive_failures, latency_total, last_error, last_failure_at, quarantined_at, updated_at)
+		VALUES (?, 1, 1, ?, ?, ?, ?, ?)
+		ON CONFLICT (feed) DO UPDATE SET
+			failures = feed_healths.failures + 1,
+			consecutive_failures = feed_healths.consecutive_failures + 1,
+			latency_total = feed_healths.latency_total + EXCLUDED.latency_total,
+			last_error = EXCLUDED.last_error,
+			last_failure_at = EXCLUDED.last_failure_at,
+			quarantined_at = CASE
+				WHEN ? > 0 AND feed_healths.consecutive_failures + 1 >= ?
+				THEN COALESCE(feed_healths.quarantined_at, EXCLUDED.last_failure_at)
+				ELSE NULL
+			END,
+			updated_at = EXCLUDED.updated_at
+	`, feed, int64(latency), cause.Error(), now, quarantinedAt, now, threshold, threshold).Error
+}
 
-const (
-	// Ascending is the sort order going up, i.e. 1,2,3.
-	Ascending SortType = iota
-	// Descending is the sort order going down, i.e. 3,2,1.
-	Descending
-)
+// ProbeFeed claims the next poll of a feed quarantined for longer than
+// period, returning true if the poll is to go ahead to probe whether the feed
+// has recovered. The feed is kept in quarantine for another period meanwhile,
+// so that only one poll probes it at a time.
+func (orm *ORM) ProbeFeed(feed string, period time.Duration) (bool, error) {
+	now := time.Now()
+	db := orm.exec(`
+		UPDATE feed_healths SET quarantined_at = ?
+		WHERE feed = ? AND quarantined_at <= ?
+	`, now, feed, now.Add(-period))
+	return db.RowsAffected > 0, db.Error
+}
 
-func (s SortType) String() string {
-	orderStr := "asc"
-	if s == Descending {
-		orderStr = "desc"
+// CreateFluxDryRunSubmission records an answer a flux monitor in dry run
+// mode would have submitted.
+func (orm *ORM) CreateFluxDryRunSubmission(submission *models.FluxDryRunSubmission) error {
+	return orm.db.Create(submission).Error
+}
+
+// FluxDryRunSubmissionsFor returns the answers the flux monitor of a job would
+// have submitted in dry run mode, most recent first, along with their count.
+func (orm *ORM) FluxDryRunSubmissionsFor(jobSpecID *models.ID, offset, limit int) ([]models.FluxDryRunSubmission, int, error) {
+	var count int
+	err := orm.db.Model(&models.FluxDryRunSubmission{}).
+		Where("job_spec_id = ?", jobSpecID).
+		Count(&count).Error
+	if err != nil {
+		return nil, 0, err
 	}
-	return orderStr
+
+	var submissions []models.FluxDryRunSubmission
+	err = orm.db.
+		Where("job_spec_id = ?", jobSpecID).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&submissions).Error
+	return submissions, count, err
 }
 
-// JobsSorted returns many JobSpecs sorted by CreatedAt from the store adhering
-// to the passed parameters.
-func (orm *ORM) JobsSorted(sort SortType, offset int, limit int) ([]models.JobSpec, int, error) {
-	orm.MustEnsureAdvisoryLock()
-	count, err := orm.CountOf(&models.JobSpec{})
+// RunResultValuesFor returns the values the field at path, given as keys
+// into the result data, took in the results of the runs of a job created
+// from since up to until, oldest first. The results whose data was truncated
+// for exceeding RUN_RESULT_MAX_SIZE are read from run_result_blobs; those
+// archived, and those without the field, are left out.
+func (orm *ORM) RunResultValuesFor(jobID *models.ID, path []string, since, until time.Time, offset, limit int) ([]models.RunResultValue, int, error) {
+	series := `
+		WITH series AS (
+			SELECT job_runs.id AS run_id, job_runs.created_at, COALESCE(run_result_blobs.data::jsonb, run_results.data) #> ? AS value
+			FROM job_runs
+			JOIN run_results ON run_results.id = job_runs.result_id
+			LEFT JOIN run_result_blobs ON run_result_blobs.hash = run_results.blob_hash
+			WHERE job_runs.job_spec_id = ? AND job_runs.deleted_at IS NULL
+			AND job_runs.created_at >= ? AND job_runs.created_at < ?
+		)`
+	args := []interface{}{pq.Array(path), jobID, since, until}
+
+	var count int
+	err := orm.db.Raw(series+` SELECT COUNT(*) FROM series WHERE value IS NOT NULL`, args...).Row().Scan(&count)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "while counting run result values")
+	}
+
+	rows, err := orm.db.Raw(series+`
+		SELECT run_id, created_at, value FROM series
+		WHERE value IS NOT NULL
+		ORDER BY created_at ASC, run_id ASC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...).Rows()
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, errors.Wrap(err, "while reading run result values")
 	}
+	defer rows.Close()
 
-	var jobs []models.JobSpec
-	order := fmt.Sprintf("created_at %s", sort.String())
-	err = orm.getRecords(&jobs, order, offset, limit)
-	return jobs, count, err
+	var values []models.RunResultValue
+	for rows.Next() {
+		v := models.RunResultValue{RunID: &models.ID{}}
+		if err := rows.Scan(v.RunID, &v.CreatedAt, &v.Value); err != nil {
+			return nil, 0, err
+		}
+		values = append(values, v)
+	}
+	return values, count, rows.Err()
 }
 
-// TxFrom returns all transactions from a particular address.
-func (orm *ORM) TxFrom(from common.Address) ([]models.Tx, error) {
-	orm.MustEnsureAdvisoryLock()
-	txs := []models.Tx{}
-	return txs, preloadAttempts(orm.db).Find(&txs, `"from" = ?`, from).Error
+// AllowRequester adds the address to the requester allowlist of the job, or
+// to the global allowlist if jobSpecID is nil. Adding an address which is
+// already on the allowlist does nothing.
+func (orm *ORM) AllowRequester(jobSpecID *models.ID, address common.Address) error {
+	return orm.exec(`
+		INSERT INTO allowed_requesters (job_spec_id, address, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING
+	`, jobSpecID, address, time.Now()).Error
 }
 
-// Transactions returns all transactions limited by passed parameters.
-func (orm *ORM) Transactions(offset, limit int) ([]models.Tx, int, error) {
-	orm.MustEnsureAdvisoryLock()
-	count, err := orm.CountOf(&models.Tx{})
-	if err != nil {
-		return nil, 0, err
+// DisallowRequester removes the address from the requester allowlist of the
+// job, or from the global allowlist if jobSpecID is nil. It returns
+// ErrorNotFound if the address is not on the allowlist.
+func (orm *ORM) DisallowRequester(jobSpecID *models.ID, address common.Address) error {
+	db := orm.db.Where("address = ?", address)
+	if jobSpecID == nil {
+		db = db.Where("job_spec_id IS NULL")
+	} else {
+		db = db.Where("job_spec_id = ?", jobSpecID)
 	}
-
-	var txs []models.Tx
-	err = orm.getRecords(&txs, "id desc", offset, limit)
-	return txs, count, err
+	db = db.Delete(&models.AllowedRequester{})
+	if db.Error != nil {
+		return db.Error
+	} else if db.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+	return nil
 }
 
-// TxAttempts returns the last tx attempts sorted by sent at descending.
-func (orm *ORM) TxAttempts(offset, limit int) ([]models.TxAttempt, int, error) {
-	orm.MustEnsureAdvisoryLock()
-	count, err := orm.CountOf(&models.TxAttempt{})
-	if err != nil {
-		return nil, 0, err
+// AllowedRequesters returns the requester allowlist of the job, or the global
+// allowlist if jobSpecID is nil, in the order the addresses were added.
+func (orm *ORM) AllowedRequesters(jobSpecID *models.ID) ([]models.AllowedRequester, error) {
+	db := orm.db
+	if jobSpecID == nil {
+		db = db.Where("job_spec_id IS NULL")
+	} else {
+		db = db.Where("job_spec_id = ?", jobSpecID)
 	}
+	var requesters []models.AllowedRequester
+	return requesters, db.Order("id asc").Find(&requesters).Error
+}
 
-	var attempts []models.TxAttempt
-	err = orm.getRecords(&attempts, "sent_at desc", offset, limit)
-	return attempts, count, err
+// RequesterAllowed returns whether the requester may request runs of the
+// job: both the global allowlist and that of the job must either be empty,
+// or have the requester on them.
+func (orm *ORM) RequesterAllowed(jobSpecID *models.ID, requester common.Address) (bool, error) {
+	var allowed bool
+	err := orm.db.Raw(`
+		SELECT (
+			NOT EXISTS (SELECT 1 FROM allowed_requesters WHERE job_spec_id IS NULL) OR
+			EXISTS (SELECT 1 FROM allowed_requesters WHERE job_spec_id IS NULL AND address = ?)
+		) AND (
+			NOT EXISTS (SELECT 1 FROM allowed_requesters WHERE job_spec_id = ?) OR
+			EXISTS (SELECT 1 FROM allowed_requesters WHERE job_spec_id = ? AND address = ?)
+		)
+	`, requester, jobSpecID, jobSpecID, requester).Row().Scan(&allowed)
+	return allowed, err
 }
 
-// UnconfirmedTxAttempts returns all TxAttempts for which the associated Tx is still unconfirmed.
-func (orm *ORM) UnconfirmedTxAttempts() ([]models.TxAttempt, error) {
-	orm.MustEnsureAdvisoryLock()
-	var items []models.TxAttempt
+// CreateVRFRequest queues a RandomnessRequest log to be fulfilled. A request
+// whose log was removed by a reorg, or is received from another block before
+// its removal is, is queued again from its new block.
+func (orm *ORM) CreateVRFRequest(r *models.VRFRequest) error {
+	now := time.Now()
+	return orm.exec(`
+		INSERT INTO vrf_requests (request_id, job_spec_id, initiator_id, block_number, block_hash, tx_hash, log, status, attempts, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?)
+		ON CONFLICT (request_id) DO UPDATE SET
+			block_number = EXCLUDED.block_number,
+			block_hash = EXCLUDED.block_hash,
+			tx_hash = EXCLUDED.tx_hash,
+			log = EXCLUDED.log,
+			status = EXCLUDED.status,
+			attempts = 0,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			job_run_id = NULL,
+			error = NULL,
+			updated_at = EXCLUDED.updated_at
+		WHERE vrf_requests.status = ? OR vrf_requests.block_hash <> EXCLUDED.block_hash
+	`, r.RequestID, r.JobSpecID, r.InitiatorID, r.BlockNumber, r.BlockHash, r.TxHash, r.Log,
+		models.VRFRequestPending, now, now, now, models.VRFRequestRemoved).Error
+}
+
+// RemoveVRFRequest marks the request with the given ID as removed by a reorg,
+// if it is still waiting to be fulfilled from the block with the given hash.
+func (orm *ORM) RemoveVRFRequest(requestID, blockHash common.Hash) error {
+	return orm.db.Model(&models.VRFRequest{}).
+		Where("request_id = ? AND block_hash = ? AND status = ?", requestID, blockHash, models.VRFRequestPending).
+		Updates(map[string]interface{}{"status": models.VRFRequestRemoved, "updated_at": time.Now()}).Error
+}
 
+// VRFRequestsReady returns the pending requests from blocks up to
+// maxBlockNumber which are due to be attempted, oldest first.
+func (orm *ORM) VRFRequestsReady(maxBlockNumber uint64, now time.Time) ([]models.VRFRequest, error) {
+	var requests []models.VRFRequest
 	err := orm.db.
-		Preload("Tx").
-		Joins("inner join txes on txes.id = tx_attempts.tx_id").
-		Where("txes.confirmed = ?", false).
-		Find(&items).Error
-	if err != nil {
-		return nil, err
-	}
-	return items, err
+		Where("status = ? AND block_number <= ? AND next_attempt_at <= ?", models.VRFRequestPending, maxBlockNumber, now).
+		Order("block_number asc, id asc").
+		Find(&requests).Error
+	return requests, err
 }
 
-// JobRunsSorted returns job runs ordered and filtered by the passed params.
-func (orm *ORM) JobRunsSorted(sort SortType, offset int, limit int) ([]models.JobRun, int, error) {
-	orm.MustEnsureAdvisoryLock()
-	count, err := orm.CountOf(&models.JobRun{})
-	if err != nil {
-		return nil, 0, err
-	}
+// VRFRequestsWithStatus returns the requests with the given status, oldest
+// first.
+func (orm *ORM) VRFRequestsWithStatus(status models.VRFRequestStatus) ([]models.VRFRequest, error) {
+	var requests []models.VRFRequest
+	err := orm.db.
+		Where("status = ?", status).
+		Order("block_number asc, id asc").
+		Find(&requests).Error
+	return requests, err
+}
 
-	var runs []models.JobRun
-	order := fmt.Sprintf("created_at %s", sort.String())
-	err = orm.getRecords(&runs, order, offset, limit)
-	return runs, count, err
+// SaveVRFRequest updates the state of a request in the queue.
+func (orm *ORM) SaveVRFRequest(r *models.VRFRequest) error {
+	return orm.db.Save(r).Error
 }
 
-// JobRunsSortedFor returns job runs for a specific job spec ordered and
-// filtered by the passed params.
-func (orm *ORM) JobRunsSortedFor(id *models.ID, order SortType, offset int, limit int) ([]models.JobRun, int, error) {
-	orm.MustEnsureAdvisoryLock()
-	count, err := orm.JobRunsCountFor(id)
-	if err != nil {
+// FindVRFRequest returns the request with the given ID.
+func (orm *ORM) FindVRFRequest(id uint64) (models.VRFRequest, error) {
+	var request models.VRFRequest
+	err := orm.db.Where("id = ?", id).First(&request).Error
+	return request, err
+}
+
+// VRFRequests returns the queued requests, most recent first, along with
+// their count, optionally only those with the given status.
+func (orm *ORM) VRFRequests(status models.VRFRequestStatus, offset, limit int) ([]models.VRFRequest, int, error) {
+	scope := orm.db.Model(&models.VRFRequest{})
+	if status != "" {
+		scope = scope.Where("status = ?", status)
+	}
+	var count int
+	if err := scope.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
-	var runs []models.JobRun
-	err = orm.preloadJobRuns().
-		Where("job_spec_id = ?", id).
-		Order(fmt.Sprintf("created_at %s", order.String())).
+	var requests []models.VRFRequest
+	err := scope.
+		Order("block_number desc, id desc").
 		Limit(limit).
 		Offset(offset).
-		Find(&runs).Error
-	return runs, count, err
+		Find(&requests).Error
+	return requests, count, err
 }
 
-// BridgeTypes returns bridge types ordered by name filtered limited by the
-// passed params.
-func (orm *ORM) BridgeTypes(offset int, limit int) ([]models.BridgeType, int, error) {
-	orm.MustEnsureAdvisoryLock()
-	count, err := orm.CountOf(&models.BridgeType{})
-	if err != nil {
-		return nil, 0, err
+// RegisterUpkeep saves the upkeep registered by a keeper initiator, updating
+// the configuration of one it registered before while keeping its account,
+// and loads the saved upkeep into u.
+func (orm *ORM) RegisterUpkeep(u *models.Upkeep) error {
+	if orm.ReadOnly() {
+		return ErrReadOnly
 	}
+	now := time.Now()
+	return orm.db.Raw(`
+		INSERT INTO upkeeps (job_spec_id, initiator_id, address, check_data, gas_limit, cooldown_blocks, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (initiator_id) DO UPDATE SET
+			address = EXCLUDED.address,
+			check_data = EXCLUDED.check_data,
+			gas_limit = EXCLUDED.gas_limit,
+			cooldown_blocks = EXCLUDED.cooldown_blocks,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *
+	`, u.JobSpecID, u.InitiatorID, u.Address, u.CheckData, u.GasLimit, u.CooldownBlocks, now, now).Scan(u).Error
+}
 
-	var bridges []models.BridgeType
-	err = orm.getRecords(&bridges, "name asc", offset, limit)
-	return bridges, count, err
+// SetUpkeepChecked records the block an upkeep was last checked at.
+func (orm *ORM) SetUpkeepChecked(id uint64, blockNumber uint64) error {
+	return orm.db.Model(&models.Upkeep{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_checked_block": blockNumber, "updated_at": time.Now()}).Error
 }
 
-// SaveUser saves the user.
-func (orm *ORM) SaveUser(user *models.User) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Save(user).Error
+// CreateUpkeepPerform records a performUpkeep transaction about to be sent
+// for an upkeep. It is only counted in the upkeep's account once
+// SetUpkeepPerformTx records it sent.
+func (orm *ORM) CreateUpkeepPerform(p *models.UpkeepPerform) error {
+	return orm.db.Create(p).Error
 }
 
-// SaveSession saves the session.
-func (orm *ORM) SaveSession(session *models.Session) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Save(session).Error
+// SetUpkeepPerformTx records the transaction a performUpkeep transaction was
+// sent with, counting it in the upkeep's account, unless it was recorded
+// already.
+func (orm *ORM) SetUpkeepPerformTx(p *models.UpkeepPerform) error {
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		update := dbtx.Model(&models.UpkeepPerform{}).
+			Where("id = ? AND tx_hash IS NULL", p.ID).
+			Updates(map[string]interface{}{
+				"tx_hash":    p.TxHash,
+				"gas_limit":  p.GasLimit,
+				"gas_price":  p.GasPrice,
+				"updated_at": time.Now(),
+			})
+		if update.Error != nil || update.RowsAffected == 0 {
+			return update.Error
+		}
+		return dbtx.Exec(`
+			UPDATE upkeeps SET
+				last_checked_block = ?,
+				last_performed_block = ?,
+				perform_count = perform_count + 1,
+				updated_at = ?
+			WHERE id = ?
+		`, p.BlockNumber, p.BlockNumber, time.Now(), p.UpkeepID).Error
+	})
 }
 
-// SaveTx saves the Ethereum Transaction.
-func (orm *ORM) SaveTx(tx *models.Tx) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Save(tx).Error
+// DeleteUpkeepPerform deletes a performUpkeep transaction which was never
+// sent.
+func (orm *ORM) DeleteUpkeepPerform(id uint64) error {
+	return orm.db.Where("id = ? AND tx_hash IS NULL", id).Delete(&models.UpkeepPerform{}).Error
 }
 
-// CreateBridgeType saves the bridge type.
-func (orm *ORM) CreateBridgeType(bt *models.BridgeType) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Create(bt).Error
+// UnconfirmedUpkeepPerforms returns the performUpkeep transactions which are
+// not yet confirmed, oldest first.
+func (orm *ORM) UnconfirmedUpkeepPerforms() ([]models.UpkeepPerform, error) {
+	var performs []models.UpkeepPerform
+	err := orm.db.
+		Where("NOT confirmed").
+		Order("id asc").
+		Find(&performs).Error
+	return performs, err
 }
 
-// UpdateBridgeType updates the bridge type.
-func (orm *ORM) UpdateBridgeType(bt *models.BridgeType, btr *models.BridgeTypeRequest) error {
-	orm.MustEnsureAdvisoryLock()
-	bt.URL = btr.URL
-	bt.Confirmations = btr.Confirmations
-	bt.MinimumContractPayment = btr.MinimumContractPayment
-	return orm.db.Save(bt).Error
+// ConfirmUpkeepPerform marks a performUpkeep transaction confirmed, with the
+// gas price it was confirmed at.
+func (orm *ORM) ConfirmUpkeepPerform(id uint64, gasPrice *utils.Big) error {
+	return orm.db.Model(&models.UpkeepPerform{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"confirmed": true, "gas_price": gasPrice, "updated_at": time.Now()}).Error
 }
 
-// CreateInitiator saves the initiator.
-func (orm *ORM) CreateInitiator(initr *models.Initiator) error {
-	orm.MustEnsureAdvisoryLock()
-	if initr.JobSpecID == nil {
-		// NOTE: This hangs forever if we don't check this here and the
-		// supplied initiator does not have a JobSpecID set.
-		// I do not know why. Seems to be something going wrong inside gorm
-		logger.Error("cannot create initiator without job spec ID")
-		return errors.New("requires job spec ID")
-	}
-	return orm.db.Create(initr).Error
+// FindBridgeResponse returns the cached bridge response with the given key,
+// or ErrorNotFound if there is none or it has expired.
+func (orm *ORM) FindBridgeResponse(key string) (models.BridgeResponse, error) {
+	var br models.BridgeResponse
+	err := orm.db.
+		Where("key = ? AND expires_at > ?", key, time.Now()).
+		First(&br).Error
+	return br, err
+}
+
+// SaveBridgeResponse caches a bridge response, replacing any response cached
+// under the same key, and removes the expired responses of the bridge.
+func (orm *ORM) SaveBridgeResponse(br *models.BridgeResponse) error {
+	br.CreatedAt = time.Now()
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		err := dbtx.Exec(`
+			INSERT INTO bridge_responses (key, bridge_name, body, expires_at, created_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (key)
+			DO UPDATE SET body = EXCLUDED.body, expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+		`, br.Key, br.BridgeName, br.Body, br.ExpiresAt, br.CreatedAt).Error
+		if err != nil {
+			return err
+		}
+		return dbtx.Exec(
+			"DELETE FROM bridge_responses WHERE bridge_name = ? AND expires_at <= ?",
+			br.BridgeName, br.CreatedAt,
+		).Error
+	})
 }
 
 // CreateHead creates a head record that tracks which block heads we've observed in the HeadTracker
 func (orm *ORM) CreateHead(n *models.Head) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Create(n).Error
+	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		if err := dbtx.Create(n).Error; err != nil {
+			return err
+		}
+		return orm.trimOldHeads(dbtx)
+	})
+}
+
+// trimOldHeads deletes all but the most recently created heads.
+func (orm *ORM) trimOldHeads(dbtx *gorm.DB) error {
+	retained := orm.headsRetained
+	if retained == 0 {
+		retained = defaultHeadsRetained
+	}
+	return dbtx.Exec(`
+	DELETE FROM heads
+	WHERE id <= (
+	  SELECT id
+	  FROM (
+		SELECT id
+		FROM heads
+		ORDER BY id DESC
+		LIMIT 1 OFFSET ?
+	  ) foo
+	)`, retained).Error
 }
 
 // FirstHead returns the oldest persisted head entry.
 func (orm *ORM) FirstHead() (*models.Head, error) {
-	orm.MustEnsureAdvisoryLock()
 	number := &models.Head{}
 	err := orm.db.Order("number asc").First(number).Error
 	if err == gorm.ErrRecordNotFound {
@@ -1070,7 +3979,6 @@ func (orm *ORM) FirstHead() (*models.Head, error) {
 
 // LastHead returns the most recently persisted head entry.
 func (orm *ORM) LastHead() (*models.Head, error) {
-	orm.MustEnsureAdvisoryLock()
 	number := &models.Head{}
 	err := orm.db.Order("number desc").First(number).Error
 	if err == gorm.ErrRecordNotFound {
@@ -1079,15 +3987,31 @@ func (orm *ORM) LastHead() (*models.Head, error) {
 	return number, err
 }
 
+// FinalizedHead returns the most recent persisted head at least
+// finalityDepth blocks behind the latest, or nil if there is none.
+func (orm *ORM) FinalizedHead(finalityDepth uint64) (*models.Head, error) {
+	last, err := orm.LastHead()
+	if err != nil || last == nil {
+		return nil, err
+	}
+	head := &models.Head{}
+	err = orm.db.
+		Where("number <= ?", last.Number-int64(finalityDepth)).
+		Order("number desc").
+		First(head).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return head, err
+}
+
 // DeleteStaleSessions deletes all sessions before the passed time.
 func (orm *ORM) DeleteStaleSessions(before time.Time) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.db.Where("last_used < ?", before).Delete(models.Session{}).Error
 }
 
 // DeleteTransaction deletes a transaction an all of its attempts.
 func (orm *ORM) DeleteTransaction(ethtx *models.Tx) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
 		err := dbtx.Where("id = ?", ethtx.ID).Delete(models.Tx{}).Error
 		err = multierr.Append(err, dbtx.Where("tx_id = ?", ethtx.ID).Delete(models.TxAttempt{}).Error)
@@ -1101,10 +4025,9 @@ func (orm *ORM) DeleteTransaction(ethtx *models.Tx) error {
 // RunResults and RunRequests are pointed at by JobRuns so we must use two CTEs
 // to remove both parents in one hit.
 //
-// TaskRuns are removed by ON DELETE CASCADE when the JobRuns and RunResults
-// are deleted.
+// TaskRuns are removed by the job_runs_delete_dependents trigger and ON
+// DELETE CASCADE when the JobRuns and RunResults are deleted.
 func (orm *ORM) BulkDeleteRuns(bulkQuery *models.BulkDeleteRunRequest) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.convenientTransaction(func(dbtx *gorm.DB) error {
 		err := dbtx.Exec(`
 			WITH deleted_job_runs AS (
@@ -1119,39 +4042,301 @@ func (orm *ORM) BulkDeleteRuns(bulkQuery *models.BulkDeleteRunRequest) error {
 			return errors.Wrap(err, "error deleting JobRuns")
 		}
 
+		err = dbtx.Exec(`
+			DELETE FROM run_result_blobs WHERE NOT EXISTS (
+				SELECT 1 FROM run_results WHERE run_results.blob_hash = run_result_blobs.hash
+			)`).Error
+		if err != nil {
+			return errors.Wrap(err, "error deleting unreferenced run result blobs")
+		}
+
+		return nil
+	})
+}
+
+// BulkUpdateRuns moves the runs matching the query to its new status in one
+// transaction, returning how many were updated. The task run each of them was
+// waiting on is moved along with it, and the reason, if any, is recorded as
+// the error of the runs.
+func (orm *ORM) BulkUpdateRuns(bulkQuery *models.BulkUpdateRunRequest) (int, error) {
+	clauses := []string{"job_runs.deleted_at IS NULL", "job_runs.status IN (?)"}
+	args := []interface{}{bulkQuery.NewStatus, bulkQuery.Status.ToStrings()}
+	if !bulkQuery.UpdatedBefore.IsZero() {
+		clauses = append(clauses, "job_runs.updated_at < ?")
+		args = append(args, bulkQuery.UpdatedBefore)
+	}
+	if bulkQuery.Bridge != "" {
+		clauses = append(clauses, `EXISTS (
+			SELECT 1 FROM task_runs
+			JOIN task_specs ON task_specs.id = task_runs.task_spec_id
+			WHERE task_runs.job_run_id = job_runs.id
+			AND task_runs.status IN (?)
+			AND task_specs.type = ?
+		)`)
+		args = append(args, pendingRunStatuses, bulkQuery.Bridge.String())
+	}
+	reason := null.NewString(bulkQuery.Reason, bulkQuery.Reason != "")
+	args = append(args, bulkQuery.NewStatus, pendingRunStatuses, reason)
+
+	var count int
+	err := orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		rows, err := dbtx.Raw(`
+			WITH updated_job_runs AS (
+				UPDATE job_runs SET status = ?, finished_at = now(), updated_at = now()
+				WHERE `+strings.Join(clauses, " AND ")+`
+				RETURNING id, result_id
+			),
+			updated_task_runs AS (
+				UPDATE task_runs SET status = ?, updated_at = now()
+				WHERE job_run_id IN (SELECT id FROM updated_job_runs) AND status IN (?)
+			),
+			updated_run_results AS (
+				UPDATE run_results SET error_message = COALESCE(CAST(? AS text), error_message), updated_at = now()
+				WHERE id IN (SELECT result_id FROM updated_job_runs)
+			)
+			SELECT count(*) FROM updated_job_runs`, args...).Rows()
+		if err != nil {
+			return errors.Wrap(err, "error updating JobRuns")
+		}
+		defer rows.Close()
+		for rows.Next() {
+			if err := rows.Scan(&count); err != nil {
+				return errors.Wrap(err, "error updating JobRuns")
+			}
+		}
+		return rows.Err()
+	})
+	return count, err
+}
+
+// pendingRunStatuses are the statuses of the task runs a run waits on.
+var pendingRunStatuses = []string{
+	string(models.RunStatusInProgress),
+	string(models.RunStatusPendingBridge),
+	string(models.RunStatusPendingConfirmations),
+	string(models.RunStatusPendingConnection),
+	string(models.RunStatusPendingSleep),
+	string(models.RunStatusPendingConcurrency),
+}
+
+// PartitionedTables are the tables partitioned by the month their rows were
+// created in.
+var PartitionedTables = []string{"job_runs", "tx_attempts"}
+
+// monthPartition returns the name of the partition of table holding the rows
+// created in the month of t, such as job_runs_y2020m06.
+func monthPartition(table string, t time.Time) string {
+	return fmt.Sprintf("%s_y%04dm%02d", table, t.Year(), t.Month())
+}
+
+// CreateMonthPartitions creates the partitions of table for the months after
+// the one of now, up to ahead months, which do not exist yet. Rows created in
+// months without a partition go to the <table>_default partition, which must
+// not have rows of a month for its partition to be created.
+func (orm *ORM) CreateMonthPartitions(table string, now time.Time, ahead uint) error {
+	now = now.UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := uint(0); i < ahead; i++ {
+		month = month.AddDate(0, 1, 0)
+		err := orm.exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+			monthPartition(table, month), table, month.Format(time.RFC3339), month.AddDate(0, 1, 0).Format(time.RFC3339))).Error
+		if err != nil {
+			return errors.Wrapf(err, "creating partition of %s for %s", table, month.Format("2006-01"))
+		}
+	}
+	return nil
+}
+
+// Partition is a partition of a table partitioned by month, holding the rows
+// created before End.
+type Partition struct {
+	Name string
+	End  time.Time
+}
+
+// Partitions returns the partitions of table holding the rows of a range of
+// months, in order of End, leaving out the default partition.
+func (orm *ORM) Partitions(table string) ([]Partition, error) {
+	rows, err := orm.db.Raw(`
+		SELECT name, upper FROM (
+			SELECT c.relname AS name,
+				(regexp_match(pg_get_expr(c.relpartbound, c.oid), 'TO \(''([^'']+)''\)'))[1]::timestamptz AS upper
+			FROM pg_inherits i
+			JOIN pg_class c ON c.oid = i.inhrelid
+			WHERE i.inhparent = ?::regclass
+		) AS partitions
+		WHERE upper IS NOT NULL
+		ORDER BY upper`, table).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var partitions []Partition
+	for rows.Next() {
+		var p Partition
+		if err := rows.Scan(&p.Name, &p.End); err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions, rows.Err()
+}
+
+// partitionInUse is, for each partitioned table, the query telling whether a
+// partition still holds rows the node needs: runs which have not finished,
+// and attempts of transactions which are not confirmed.
+var partitionInUse = map[string]string{
+	"job_runs":    `SELECT EXISTS (SELECT 1 FROM %s WHERE status NOT IN ('completed', 'errored', 'cancelled'))`,
+	"tx_attempts": `SELECT EXISTS (SELECT 1 FROM %s p JOIN txes ON txes.id = p.tx_id WHERE NOT txes.confirmed)`,
+}
+
+// errPartitionInUse rolls back dropping a partition which still holds rows
+// the node needs.
+var errPartitionInUse = errors.New("partition in use")
+
+// DropPartition detaches a partition of table and drops it with all of its
+// rows at once, unless it still holds rows the node needs, returning whether
+// it did. The partition is checked once detached, so that no row can be
+// written to it meanwhile. The task runs, results and requests of the runs of
+// a partition of job_runs are deleted along with it.
+func (orm *ORM) DropPartition(table string, partition string) (bool, error) {
+	inUse, ok := partitionInUse[table]
+	if !ok {
+		return false, fmt.Errorf("%s is not partitioned", table)
+	}
+	quoted := pq.QuoteIdentifier(partition)
+
+	err := orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		if err := dbtx.Exec(fmt.Sprintf(`ALTER TABLE %s DETACH PARTITION %s`, table, quoted)).Error; err != nil {
+			return errors.Wrapf(err, "detaching partition %s", partition)
+		}
+		var used bool
+		if err := dbtx.Raw(fmt.Sprintf(inUse, quoted)).Row().Scan(&used); err != nil {
+			return errors.Wrapf(err, "checking partition %s", partition)
+		} else if used {
+			return errPartitionInUse
+		}
+
+		var statements []string
+		if table == "job_runs" {
+			statements = append(statements, fmt.Sprintf(`CREATE TEMPORARY TABLE dropped_job_runs ON COMMIT DROP AS SELECT id, result_id, run_request_id FROM %s`, quoted))
+		}
+		statements = append(statements, fmt.Sprintf(`DROP TABLE %s`, quoted))
+		if table == "job_runs" {
+			statements = append(statements,
+				`WITH deleted_task_runs AS (
+					DELETE FROM task_runs WHERE job_run_id IN (SELECT id FROM dropped_job_runs) RETURNING result_id
+				)
+				DELETE FROM run_results WHERE id IN (
+					SELECT result_id FROM deleted_task_runs UNION SELECT result_id FROM dropped_job_runs
+				)`,
+				`DELETE FROM run_requests WHERE id IN (SELECT run_request_id FROM dropped_job_runs)`,
+				`UPDATE vrf_requests SET job_run_id = NULL WHERE job_run_id IN (SELECT id FROM dropped_job_runs)`,
+				`DELETE FROM run_result_blobs WHERE NOT EXISTS (
+					SELECT 1 FROM run_results WHERE run_results.blob_hash = run_result_blobs.hash
+				)`,
+			)
+		}
+		for _, statement := range statements {
+			if err := dbtx.Exec(statement).Error; err != nil {
+				return errors.Wrapf(err, "dropping partition %s", partition)
+			}
+		}
 		return nil
 	})
+	if err == errPartitionInUse {
+		return false, nil
+	}
+	return err == nil, err
 }
 
 // Keys returns all keys stored in the orm.
 func (orm *ORM) Keys() ([]*models.Key, error) {
-	orm.MustEnsureAdvisoryLock()
 	var keys []*models.Key
 	return keys, orm.db.Find(&keys).Order("created_at ASC").Error
 }
 
 // FirstOrCreateKey returns the first key found or creates a new one in the orm.
 func (orm *ORM) FirstOrCreateKey(k *models.Key) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.db.FirstOrCreate(k).Error
 }
 
+// RetireKey marks the key with the given address as retired, or errors if
+// there is no such key.
+func (orm *ORM) RetireKey(address common.Address) error {
+	db := orm.db.Model(&models.Key{}).
+		Where("address = ?", address.Hex()).
+		Update("retired_at", time.Now())
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetKeyNamespace puts the key with the given address in a namespace, or
+// errors if there is no such key.
+func (orm *ORM) SetKeyNamespace(address common.Address, namespace string) error {
+	db := orm.db.Model(&models.Key{}).
+		Where("address = ?", address.Hex()).
+		UpdateColumn("namespace", namespace)
+	if db.Error != nil {
+		return db.Error
+	}
+	if db.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// KeysInNamespace returns the keys in a namespace.
+func (orm *ORM) KeysInNamespace(namespace string) ([]models.Key, error) {
+	var keys []models.Key
+	return keys, orm.db.Where("namespace = ?", namespace).Order("created_at ASC").Find(&keys).Error
+}
+
+// NamespacedKeys returns the namespaces of the keys outside of the default
+// namespace, by address.
+func (orm *ORM) NamespacedKeys() (map[common.Address]string, error) {
+	var keys []models.Key
+	err := orm.db.Select("address, namespace").Where("namespace <> ''").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make(map[common.Address]string, len(keys))
+	for _, k := range keys {
+		namespaces[k.Address.Address()] = k.Namespace
+	}
+	return namespaces, nil
+}
+
+// JobRunNamespace returns the namespace of the job of the job run with the
+// given ID, even if the job has been archived.
+func (orm *ORM) JobRunNamespace(runID *models.ID) (string, error) {
+	var job models.JobSpec
+	err := orm.db.Unscoped().
+		Select("job_specs.namespace").
+		Joins("JOIN job_runs ON job_runs.job_spec_id = job_specs.id").
+		Where("job_runs.id = ?", runID).
+		First(&job).Error
+	return job.Namespace, err
+}
+
 // FirstOrCreateEncryptedSecretKey returns the first key found or creates a new one in the orm.
 func (orm *ORM) FirstOrCreateEncryptedSecretVRFKey(k *models.EncryptedSecretVRFKey) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.db.FirstOrCreate(k).Error
 }
 
 // DeleteEncryptedSecretKey deletes k from the encrypted keys table, or errors
 func (orm *ORM) DeleteEncryptedSecretVRFKey(k *models.EncryptedSecretVRFKey) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.db.Delete(k).Error
 }
 
 // FindEncryptedSecretKeys retrieves matches to where from the encrypted keys table, or errors
 func (orm *ORM) FindEncryptedSecretVRFKeys(where ...models.EncryptedSecretVRFKey) (
 	retrieved []*models.EncryptedSecretVRFKey, err error) {
-	orm.MustEnsureAdvisoryLock()
 	var anonWhere []interface{} // Find needs "where" contents coerced to interface{}
 	for _, constraint := range where {
 		anonWhere = append(anonWhere, &constraint)
@@ -1159,15 +4344,38 @@ func (orm *ORM) FindEncryptedSecretVRFKeys(where ...models.EncryptedSecretVRFKey
 	return retrieved, orm.db.Find(&retrieved, anonWhere...).Error
 }
 
+// CreateEncryptedOCRKeyBundle saves an encrypted off-chain reporting key
+// bundle.
+func (orm *ORM) CreateEncryptedOCRKeyBundle(kb *models.EncryptedOCRKeyBundle) error {
+	return orm.db.Create(kb).Error
+}
+
+// DeleteEncryptedOCRKeyBundle deletes the off-chain reporting key bundle with
+// the given ID, returning ErrorNotFound if there is none.
+func (orm *ORM) DeleteEncryptedOCRKeyBundle(id string) error {
+	db := orm.db.Delete(&models.EncryptedOCRKeyBundle{}, "id = ?", id)
+	if db.Error != nil {
+		return db.Error
+	} else if db.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+	return nil
+}
+
+// FindEncryptedOCRKeyBundles returns every encrypted off-chain reporting key
+// bundle, oldest first.
+func (orm *ORM) FindEncryptedOCRKeyBundles() ([]models.EncryptedOCRKeyBundle, error) {
+	var bundles []models.EncryptedOCRKeyBundle
+	return bundles, orm.db.Order("created_at asc").Find(&bundles).Error
+}
+
 // SaveLogCursor saves the log cursor.
 func (orm *ORM) SaveLogCursor(logCursor *models.LogCursor) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.db.Save(logCursor).Error
 }
 
 // FindLogCursor will find the given log cursor.
 func (orm *ORM) FindLogCursor(name string) (models.LogCursor, error) {
-	orm.MustEnsureAdvisoryLock()
 	lc := models.LogCursor{}
 	err := orm.db.
 		Where("name = ?", name).
@@ -1203,15 +4411,116 @@ func (orm *ORM) LogConsumptionExists(lc *models.LogConsumption) (bool, error) {
 	return exists, nil
 }
 
-// CreateLogConsumption creates a new LogConsumption record
-func (orm *ORM) CreateLogConsumption(lc *models.LogConsumption) error {
-	orm.MustEnsureAdvisoryLock()
-	return orm.db.Create(lc).Error
+// CreateLogConsumption creates a new LogConsumption record, reporting false
+// if the job had already consumed the log. As the record is unique, of two
+// deliveries of the same log racing to record it only one does.
+func (orm *ORM) CreateLogConsumption(lc *models.LogConsumption) (bool, error) {
+	if orm.ReadOnly() {
+		return false, ErrReadOnly
+	}
+	return insertLogConsumption(orm.db, lc)
+}
+
+// ConsumeLog records the consumption of a log and calls process in the same
+// transaction, committed once process returns, reporting false without
+// calling it if the job had already consumed the log. Another delivery of the
+// log waits for the transaction, and the log is delivered again should the
+// node stop before it is committed.
+func (orm *ORM) ConsumeLog(lc *models.LogConsumption, process func()) (bool, error) {
+	processed := false
+	err := orm.convenientTransaction(func(dbtx *gorm.DB) error {
+		created, err := insertLogConsumption(dbtx, lc)
+		if err != nil || !created {
+			return err
+		}
+		process()
+		processed = true
+		return nil
+	})
+	return processed, err
+}
+
+func insertLogConsumption(db *gorm.DB, lc *models.LogConsumption) (bool, error) {
+	lc.CreatedAt = time.Now()
+	rows, err := db.Raw(`
+		INSERT INTO log_consumptions (block_hash, block_number, log_index, job_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (job_id, block_hash, log_index) DO NOTHING
+		RETURNING id
+	`, lc.BlockHash, lc.BlockNumber, lc.LogIndex, lc.JobID, lc.CreatedAt).Rows()
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	return true, rows.Scan(&lc.ID)
+}
+
+// TrimLogConsumptions deletes the records of the logs consumed in blocks
+// before the given one, returning how many were deleted. The records of
+// logs consumed before their block number was recorded are kept.
+func (orm *ORM) TrimLogConsumptions(beforeBlock uint64) (int64, error) {
+	db := orm.db.Where("block_number < ?", beforeBlock).Delete(&models.LogConsumption{})
+	return db.RowsAffected, db.Error
+}
+
+// LogConsumptionsFor returns the logs consumed by a job, most recent first.
+func (orm *ORM) LogConsumptionsFor(jobID *models.ID, offset, limit int) ([]models.LogConsumption, int, error) {
+	var count int
+	err := orm.db.Model(&models.LogConsumption{}).
+		Where("job_id = ?", jobID).
+		Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var lcs []models.LogConsumption
+	err = orm.db.
+		Where("job_id = ?", jobID).
+		Order("id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&lcs).Error
+	return lcs, count, err
+}
+
+// DeleteLogConsumption deletes the record of a job having consumed a log, so
+// that the log is consumed again if it is broadcast again.
+func (orm *ORM) DeleteLogConsumption(jobID *models.ID, id uint) error {
+	db := orm.db.Where("job_id = ? AND id = ?", jobID, id).Delete(&models.LogConsumption{})
+	if db.Error != nil {
+		return db.Error
+	} else if db.RowsAffected == 0 {
+		return ErrorNotFound
+	}
+	return nil
+}
+
+// CreateMQTTConsumption records that an mqtt initiator has consumed a message,
+// reporting false if it had already consumed the same message within the
+// given window. Older records are refreshed, since message IDs are recycled.
+func (orm *ORM) CreateMQTTConsumption(mc *models.MQTTConsumption, window time.Duration) (bool, error) {
+	mc.CreatedAt = time.Now()
+	db := orm.exec(`
+		INSERT INTO mqtt_consumptions (initiator_id, message_key, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (initiator_id, message_key)
+		DO UPDATE SET created_at = EXCLUDED.created_at
+		WHERE mqtt_consumptions.created_at < ?
+	`, mc.InitiatorID, mc.MessageKey, mc.CreatedAt, mc.CreatedAt.Add(-window))
+	return db.RowsAffected > 0, db.Error
+}
+
+// DeleteMQTTConsumptionsBefore deletes the records of the mqtt messages
+// consumed before the given time.
+func (orm *ORM) DeleteMQTTConsumptionsBefore(before time.Time) error {
+	return orm.db.Where("created_at < ?", before).Delete(&models.MQTTConsumption{}).Error
 }
 
 // FindLogConsumer finds the consuming job of a particular LogConsumption record
 func (orm *ORM) FindLogConsumer(lc *models.LogConsumption) (models.JobSpec, error) {
-	orm.MustEnsureAdvisoryLock()
 	return orm.FindJob(lc.JobID)
 }
 
@@ -1233,6 +4542,9 @@ func (orm *ORM) ClobberDiskKeyStoreWithDBKeys(keysDir string) error {
 
 	var merr error
 	for _, k := range keys {
+		if k.KeyRef.Valid {
+			continue // Held by a KMS or HSM, not on disk
+		}
 		merr = multierr.Append(
 			k.WriteToDisk(filepath.Join(keysDir, fmt.Sprintf("%s.json", k.Address.String()))),
 			merr)
@@ -1241,13 +4553,11 @@ func (orm *ORM) ClobberDiskKeyStoreWithDBKeys(keysDir string) error {
 }
 
 func (orm *ORM) CountOf(t interface{}) (int, error) {
-	orm.MustEnsureAdvisoryLock()
 	var count int
 	return count, orm.db.Model(t).Count(&count).Error
 }
 
 func (orm *ORM) getRecords(collection interface{}, order string, offset, limit int) error {
-	orm.MustEnsureAdvisoryLock()
 	return orm.db.
 		Set("gorm:auto_preload", true).
 		Order(order).Limit(limit).Offset(offset).
@@ -1255,7 +4565,6 @@ func (orm *ORM) getRecords(collection interface{}, order string, offset, limit i
 }
 
 func (orm *ORM) RawDB(fn func(*gorm.DB) error) error {
-	orm.MustEnsureAdvisoryLock()
 	return fn(orm.db)
 }
 
@@ -6,21 +6,28 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/eth"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/internal/mocks"
 	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/services/synchronization"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/jinzhu/gorm"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/guregu/null.v3"
@@ -87,6 +94,217 @@ func TestORM_Unscoped(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestORM_Earnings(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithRunLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	requester := cltest.NewAddress()
+
+	createRun := func(status models.RunStatus, payment int64) models.JobRun {
+		jr := cltest.NewJobRun(job)
+		jr.TaskRuns[0].Status = status
+		jr.SetStatus(status)
+		jr.Payment = assets.NewLink(payment)
+		jr.RunRequest.Requester = &requester
+		require.NoError(t, store.CreateJobRun(&jr))
+		return jr
+	}
+	jr1 := createRun(models.RunStatusCompleted, 2)
+	createRun(models.RunStatusCompleted, 3)
+	createRun(models.RunStatusCancelled, 5)
+
+	_, err := store.KeyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	tx := cltest.CreateTxWithNonceAndGasPrice(t, store, cltest.GetAccountAddress(t, store), 1, 0, 20)
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		if err := db.Exec("UPDATE txes SET surrogate_id = ? WHERE id = ?", jr1.ID.String(), tx.ID).Error; err != nil {
+			return err
+		}
+		return db.Exec("UPDATE tx_attempts SET confirmed = true WHERE tx_id = ?", tx.ID).Error
+	}))
+
+	for _, groupBy := range []models.EarningsGrouping{models.EarningsByJob, models.EarningsByRequester} {
+		earnings, err := store.Earnings(models.EarningsQuery{
+			GroupBy: groupBy,
+			From:    time.Now().Add(-time.Hour),
+			To:      time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, earnings, 1)
+		assert.Equal(t, int64(2), earnings[0].Runs)
+		assert.Equal(t, assets.NewLink(5), earnings[0].Earned)
+		assert.Equal(t, assets.NewEth(250000*20), earnings[0].GasCost)
+		if groupBy == models.EarningsByJob {
+			assert.Equal(t, job.ID, earnings[0].JobSpecID)
+		} else {
+			assert.Equal(t, &requester, earnings[0].Requester)
+		}
+	}
+
+	earnings, err := store.Earnings(models.EarningsQuery{
+		GroupBy: models.EarningsByJob,
+		From:    time.Now().Add(time.Hour),
+		To:      time.Now().Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, earnings)
+}
+
+func TestORM_RunStats(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	createRun := func(status models.RunStatus, payment int64, finishedAt time.Time, latency time.Duration) {
+		jr := cltest.NewJobRun(job)
+		jr.TaskRuns[0].Status = status
+		jr.SetStatus(status)
+		jr.Payment = assets.NewLink(payment)
+		require.NoError(t, store.CreateJobRun(&jr))
+		require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+			return db.Exec("UPDATE job_runs SET finished_at = ?, created_at = ? WHERE id = ?",
+				finishedAt, finishedAt.Add(-latency), jr.ID).Error
+		}))
+	}
+	tenOClock := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	createRun(models.RunStatusCompleted, 2, tenOClock.Add(30*time.Minute), 10*time.Second)
+	createRun(models.RunStatusErrored, 0, tenOClock.Add(40*time.Minute), 10*time.Second)
+	createRun(models.RunStatusCancelled, 0, tenOClock.Add(50*time.Minute), 10*time.Second)
+	createRun(models.RunStatusCompleted, 3, tenOClock.Add(75*time.Minute), 30*time.Second)
+	cltest.CreateJobRunWithStatus(t, store, job, models.RunStatusInProgress)
+
+	_, ok, err := store.LatestRunStatsBucket()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.RollUpRunStats(time.Unix(0, 0)))
+	// Rolling up again replaces the stats rather than adding to them
+	require.NoError(t, store.RollUpRunStats(tenOClock))
+
+	latest, ok, err := store.LatestRunStatsBucket()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, latest.Equal(tenOClock.Add(time.Hour)))
+
+	hourly, err := store.RunStats(models.RunStatsQuery{
+		Period: models.RunStatsHourly,
+		From:   tenOClock,
+		To:     tenOClock.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, hourly, 2)
+	assert.Equal(t, job.ID, hourly[0].JobSpecID)
+	assert.True(t, hourly[0].Bucket.Equal(tenOClock))
+	assert.Equal(t, int64(3), hourly[0].Runs)
+	assert.Equal(t, int64(1), hourly[0].Errored)
+	assert.InDelta(t, 1.0/3, hourly[0].ErrorRate, 0.0001)
+	assert.InDelta(t, 10, hourly[0].AverageLatencySeconds, 0.0001)
+	assert.Equal(t, assets.NewLink(2), hourly[0].LinkEarned)
+	assert.True(t, hourly[1].Bucket.Equal(tenOClock.Add(time.Hour)))
+	assert.Equal(t, int64(1), hourly[1].Runs)
+	assert.Equal(t, assets.NewLink(3), hourly[1].LinkEarned)
+
+	daily, err := store.RunStats(models.RunStatsQuery{
+		Period:    models.RunStatsDaily,
+		JobSpecID: job.ID,
+		From:      tenOClock.Add(-48 * time.Hour),
+		To:        tenOClock.Add(48 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, int64(4), daily[0].Runs)
+	assert.Equal(t, int64(1), daily[0].Errored)
+	assert.InDelta(t, 15, daily[0].AverageLatencySeconds, 0.0001)
+	assert.Equal(t, assets.NewLink(5), daily[0].LinkEarned)
+
+	other, err := store.RunStats(models.RunStatsQuery{
+		Period:    models.RunStatsDaily,
+		JobSpecID: models.NewID(),
+		From:      tenOClock.Add(-48 * time.Hour),
+		To:        tenOClock.Add(48 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	// The LINK earned in the hours rolled up for good is taken from the
+	// rollups, and only the runs which finished since are summed
+	createRun(models.RunStatusCompleted, 7, tenOClock.Add(-90*time.Minute), time.Second)
+	require.NoError(t, store.RollUpRunStats(time.Unix(0, 0)))
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Exec("UPDATE job_runs SET payment = 1000 WHERE finished_at < ?", tenOClock).Error
+	}))
+	earned, err := store.LinkEarnedFor(&job)
+	require.NoError(t, err)
+	assert.Equal(t, assets.NewLink(12), earned)
+}
+
+func TestORM_GasUsage(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	jr := cltest.NewJobRun(job)
+	require.NoError(t, store.CreateJobRun(&jr))
+
+	_, err := store.KeyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	from := cltest.GetAccountAddress(t, store)
+	gasUsed := hexutil.Uint64(21000)
+	for nonce, surrogateID := range []string{jr.ID.String(), ""} {
+		tx := cltest.CreateTxWithNonceAndGasPrice(t, store, from, 1, uint64(nonce), 20)
+		if surrogateID != "" {
+			require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+				return db.Exec("UPDATE txes SET surrogate_id = ? WHERE id = ?", surrogateID, tx.ID).Error
+			}))
+		}
+		tx.Attempts[0].RecordGasUsed(&eth.TxReceipt{GasUsed: &gasUsed, EffectiveGasPrice: utils.NewBig(big.NewInt(10))})
+		require.NoError(t, store.MarkTxSafe(tx, tx.Attempts[0]))
+	}
+
+	usages, err := store.GasUsage(models.GasUsageQuery{
+		GroupBy: models.GasUsageByRun,
+		From:    time.Now().Add(-time.Hour),
+		To:      time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+	assert.Equal(t, job.ID, usages[0].JobSpecID)
+	assert.Equal(t, jr.ID, usages[0].JobRunID)
+	assert.Nil(t, usages[1].JobSpecID, "transactions not sent by a run should be accounted for without a job")
+	for _, usage := range usages {
+		assert.Equal(t, int64(1), usage.Transactions)
+		assert.Equal(t, uint64(21000), usage.GasUsed)
+		assert.Equal(t, assets.NewEth(21000*10), usage.Cost)
+	}
+
+	earnings, err := store.Earnings(models.EarningsQuery{
+		GroupBy: models.EarningsByJob,
+		From:    time.Now().Add(-time.Hour),
+		To:      time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, earnings, "the run has not completed")
+
+	usages, err = store.GasUsage(models.GasUsageQuery{
+		GroupBy: models.GasUsageByJob,
+		From:    time.Now().Add(time.Hour),
+		To:      time.Now().Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
+
 func TestORM_CreateExternalInitiator(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
@@ -125,6 +343,31 @@ func TestORM_DeleteExternalInitiator(t *testing.T) {
 	require.NoError(t, store.CreateExternalInitiator(exi))
 }
 
+func TestORM_CreateExternalInitiatorDeliveryFailure(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	exi, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: "externalinitiator"})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateExternalInitiator(exi))
+
+	require.NoError(t, store.CreateExternalInitiatorDeliveryFailure(&models.ExternalInitiatorDeliveryFailure{
+		ExternalInitiatorName: exi.Name,
+		Error:                 "expired",
+		CreatedAt:             time.Now().Add(-models.ExternalInitiatorDeliveryFailureRetention - time.Hour),
+	}))
+	require.NoError(t, store.CreateExternalInitiatorDeliveryFailure(&models.ExternalInitiatorDeliveryFailure{
+		ExternalInitiatorName: exi.Name,
+		Error:                 "recent",
+	}))
+
+	eis, err := store.ExternalInitiatorsWithDeliveryFailures()
+	require.NoError(t, err)
+	require.Len(t, eis, 1)
+	require.Len(t, eis[0].DeliveryFailures, 1)
+	assert.Equal(t, "recent", eis[0].DeliveryFailures[0].Error)
+}
+
 func TestORM_ArchiveJob(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -146,6 +389,558 @@ func TestORM_ArchiveJob(t *testing.T) {
 	require.NoError(t, utils.JustError(orm.FindJobRun(run.ID)))
 }
 
+func TestORM_ArchiveJobs_UnarchiveJobs(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job1 := cltest.NewJobWithSchedule("* * * * *")
+	require.NoError(t, store.CreateJob(&job1))
+	job2 := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job2))
+	run := cltest.NewJobRun(job1)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	err := store.ArchiveJobs([]*models.ID{job1.ID, models.NewID()})
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+	require.NoError(t, utils.JustError(store.FindJob(job1.ID)), "no job should be archived if one is not found")
+
+	require.NoError(t, store.ArchiveJobs([]*models.ID{job1.ID, job2.ID}))
+	require.Error(t, utils.JustError(store.FindJob(job1.ID)))
+	require.Error(t, utils.JustError(store.FindJob(job2.ID)))
+
+	require.NoError(t, store.UnarchiveJobs([]*models.ID{job1.ID}))
+	found, err := store.FindJob(job1.ID)
+	require.NoError(t, err)
+	assert.Len(t, found.Initiators, len(job1.Initiators))
+	assert.Len(t, found.Tasks, len(job1.Tasks))
+	require.NoError(t, utils.JustError(store.FindJobRun(run.ID)))
+	require.Error(t, utils.JustError(store.FindJob(job2.ID)))
+
+	assert.Error(t, store.UnarchiveJobs([]*models.ID{job1.ID}), "job is not archived")
+}
+
+func TestORM_UpdateJob(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	run := cltest.NewJobRun(job)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	updated := cltest.NewJobWithSchedule("CRON_TZ=UTC * * * * *")
+	updated.ID = job.ID
+	updated.Tasks = append(updated.Tasks, cltest.NewTask(t, "noop"))
+	require.NoError(t, store.UpdateJob(&updated))
+
+	found, err := store.FindJob(job.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Initiators, 1)
+	assert.Equal(t, models.InitiatorCron, found.Initiators[0].Type)
+	assert.Len(t, found.Tasks, 2)
+	assert.Equal(t, job.CreatedAt.Unix(), found.CreatedAt.Unix())
+
+	versions, err := store.JobSpecVersions(job.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, uint32(1), versions[0].Version)
+	jsr, err := versions[0].JobSpecRequest()
+	require.NoError(t, err)
+	require.Len(t, jsr.Initiators, 1)
+	assert.Equal(t, models.InitiatorWeb, jsr.Initiators[0].Type)
+
+	run, err = store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InitiatorWeb, run.Initiator.Type)
+}
+
+func TestORM_UpdateJob_KeepsUnchangedInitiators(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Initiators = append(job.Initiators, models.Initiator{
+		Type:            models.InitiatorKafka,
+		InitiatorParams: models.InitiatorParams{KafkaTopics: models.StringCollection{"prices"}},
+	})
+	require.NoError(t, store.CreateJob(&job))
+	kafkaID := job.Initiators[1].ID
+	require.NoError(t, store.SaveKafkaOffset(&models.KafkaOffset{InitiatorID: kafkaID, Topic: "prices", Offset: 42}))
+
+	updated := cltest.NewJobWithWebInitiator()
+	updated.ID = job.ID
+	updated.Initiators = append(updated.Initiators, models.Initiator{
+		Type:            models.InitiatorKafka,
+		InitiatorParams: models.InitiatorParams{KafkaTopics: models.StringCollection{"prices"}},
+	})
+	updated.Initiators[0].Name = "changed"
+	updated.Tasks = append(updated.Tasks, cltest.NewTask(t, "noop"))
+	require.NoError(t, store.UpdateJob(&updated))
+
+	found, err := store.FindJob(job.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Initiators, 2)
+	assert.NotEqual(t, job.Initiators[0].ID, found.Initiators[1].ID)
+	assert.Equal(t, kafkaID, found.Initiators[0].ID)
+	assert.Equal(t, models.InitiatorKafka, found.Initiators[0].Type)
+	assert.Equal(t, models.InitiatorWeb, found.Initiators[1].Type)
+	assert.Len(t, found.Tasks, 2)
+
+	offsets, err := store.KafkaOffsets(found.Initiators[0].ID)
+	require.NoError(t, err)
+	require.Len(t, offsets, 1)
+	assert.Equal(t, int64(42), offsets[0].Offset)
+}
+
+func TestORM_UpdateJob_ServiceAgreement(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	sa, err := cltest.ServiceAgreementFromString(string(cltest.MustReadFile(t, "../../internal/fixtures/web/noop_agreement.json")))
+	require.NoError(t, err)
+	require.NoError(t, store.CreateServiceAgreement(&sa))
+
+	updated := cltest.NewJobWithWebInitiator()
+	updated.ID = sa.JobSpec.ID
+	assert.Error(t, store.UpdateJob(&updated))
+
+	versions, err := store.JobSpecVersions(sa.JobSpec.ID)
+	require.NoError(t, err)
+	assert.Len(t, versions, 0)
+}
+
+func TestORM_RollbackJob(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	updated := cltest.NewJobWithSchedule("CRON_TZ=UTC * * * * *")
+	updated.ID = job.ID
+	require.NoError(t, store.UpdateJob(&updated))
+
+	changes, err := store.DiffJobSpecVersion(job.ID, 1)
+	require.NoError(t, err)
+	paths := []string{}
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	assert.Contains(t, paths, "initiators.0.type")
+	assert.Contains(t, paths, "initiators.0.params.schedule")
+
+	rolledBack, err := store.RollbackJob(job.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, rolledBack.ID)
+
+	found, err := store.FindJob(job.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Initiators, 1)
+	assert.Equal(t, models.InitiatorWeb, found.Initiators[0].Type)
+
+	versions, err := store.JobSpecVersions(job.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, uint32(2), versions[0].Version)
+
+	_, err = store.RollbackJob(job.ID, 5)
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+}
+
+func TestORM_SaveKafkaOffset(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithKafkaInitiator("prices", "volumes")
+	require.NoError(t, store.CreateJob(&job))
+	initrID := job.Initiators[0].ID
+
+	require.NoError(t, store.SaveKafkaOffset(&models.KafkaOffset{InitiatorID: initrID, Topic: "volumes", Partition: 0, Offset: 7}))
+	require.NoError(t, store.SaveKafkaOffset(&models.KafkaOffset{InitiatorID: initrID, Topic: "prices", Partition: 0, Offset: 3}))
+	require.NoError(t, store.SaveKafkaOffset(&models.KafkaOffset{InitiatorID: initrID, Topic: "prices", Partition: 0, Offset: 4}))
+
+	offsets, err := store.KafkaOffsets(initrID)
+	require.NoError(t, err)
+	require.Len(t, offsets, 2)
+	assert.Equal(t, "prices", offsets[0].Topic)
+	assert.Equal(t, int64(4), offsets[0].Offset)
+	assert.Equal(t, "volumes", offsets[1].Topic)
+	assert.Equal(t, int64(7), offsets[1].Offset)
+
+	found, err := store.FindInitiator(initrID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StringCollection{"prices", "volumes"}, found.KafkaTopics)
+}
+
+func TestORM_CreateMQTTConsumption(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	initrID := job.Initiators[0].ID
+
+	mc := models.NewMQTTConsumption(initrID, "sensors/kitchen", 7, []byte("21.5"))
+	fresh, err := store.CreateMQTTConsumption(&mc, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = store.CreateMQTTConsumption(&mc, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, fresh, "a redelivery within the window should be reported")
+
+	time.Sleep(time.Millisecond)
+	fresh, err = store.CreateMQTTConsumption(&mc, 0)
+	require.NoError(t, err)
+	assert.True(t, fresh, "a recycled message ID outside the window should be fresh")
+
+	require.NoError(t, store.DeleteMQTTConsumptionsBefore(time.Now().Add(-time.Hour)))
+	fresh, err = store.CreateMQTTConsumption(&mc, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, fresh, "a consumption within the window should be kept")
+
+	require.NoError(t, store.DeleteMQTTConsumptionsBefore(time.Now().Add(time.Second)))
+	fresh, err = store.CreateMQTTConsumption(&mc, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, fresh, "a pruned consumption should be forgotten")
+}
+
+func TestORM_RecordBridgeHealth(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t, "flakybridge")
+	require.NoError(t, store.CreateBridgeType(bt))
+
+	_, err := store.FindBridgeHealth(bt.Name)
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+
+	require.NoError(t, store.RecordBridgeSuccess(bt.Name, 3*time.Second))
+	require.NoError(t, store.RecordBridgeFailure(bt.Name, time.Second, errors.New("502 bad gateway"), 2))
+	health, err := store.FindBridgeHealth(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), health.Successes)
+	assert.Equal(t, uint64(1), health.Failures)
+	assert.Equal(t, 0.5, health.SuccessRate())
+	assert.Equal(t, 2*time.Second, health.AverageLatency())
+	assert.Equal(t, "502 bad gateway", health.LastError.ValueOrZero())
+	assert.False(t, health.CircuitOpen())
+
+	require.NoError(t, store.RecordBridgeFailure(bt.Name, time.Second, errors.New("504 gateway timeout"), 2))
+	health, err = store.FindBridgeHealth(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), health.ConsecutiveFailures)
+	assert.True(t, health.CircuitOpen())
+
+	probe, err := store.ProbeBridge(bt.Name, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, probe, "circuit should stay open until the timeout")
+	probe, err = store.ProbeBridge(bt.Name, 0)
+	require.NoError(t, err)
+	assert.True(t, probe)
+
+	require.NoError(t, store.RecordBridgeSuccess(bt.Name, time.Second))
+	health, err = store.FindBridgeHealth(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), health.ConsecutiveFailures)
+	assert.False(t, health.CircuitOpen())
+
+	healths, err := store.FindBridgeHealths([]string{bt.Name.String(), "unknownbridge"})
+	require.NoError(t, err)
+	assert.Len(t, healths, 1)
+}
+
+func TestORM_BatchBridgeSuccess(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t, "busybridge")
+	require.NoError(t, store.CreateBridgeType(bt))
+
+	store.BatchBridgeSuccess(bt.Name, time.Second)
+	store.BatchBridgeSuccess(bt.Name, 3*time.Second)
+	require.NoError(t, store.RecordBridgeFailure(bt.Name, 2*time.Second, errors.New("502 bad gateway"), 0))
+
+	// The batched successes are written before the failure
+	health, err := store.FindBridgeHealth(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), health.Successes)
+	assert.Equal(t, uint64(1), health.Failures)
+	assert.Equal(t, uint32(1), health.ConsecutiveFailures)
+	assert.Equal(t, 2*time.Second, health.AverageLatency())
+
+	store.BatchBridgeSuccess(bt.Name, time.Second)
+	gomega.NewGomegaWithT(t).Eventually(func() uint64 {
+		health, err := store.FindBridgeHealth(bt.Name)
+		require.NoError(t, err)
+		return health.Successes
+	}).Should(gomega.Equal(uint64(3)))
+}
+
+func TestORM_RecordFeedHealth(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	feed := "https://example.com/price"
+	_, err := store.FindFeedHealth(feed)
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+
+	require.NoError(t, store.RecordFeedSuccess(feed, 3*time.Second))
+	require.NoError(t, store.RecordFeedFailure(feed, time.Second, errors.New("502 bad gateway"), 2))
+	health, err := store.FindFeedHealth(feed)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), health.Successes)
+	assert.Equal(t, uint64(1), health.Failures)
+	assert.Equal(t, 0.5, health.ErrorRate())
+	assert.Equal(t, 2*time.Second, health.AverageLatency())
+	assert.Equal(t, "502 bad gateway", health.LastError.ValueOrZero())
+	assert.False(t, health.Quarantined())
+
+	require.NoError(t, store.RecordFeedFailure(feed, time.Second, errors.New("504 gateway timeout"), 2))
+	health, err = store.FindFeedHealth(feed)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), health.ConsecutiveFailures)
+	assert.True(t, health.Quarantined())
+
+	probe, err := store.ProbeFeed(feed, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, probe, "feed should stay quarantined until the period has passed")
+	probe, err = store.ProbeFeed(feed, 0)
+	require.NoError(t, err)
+	assert.True(t, probe)
+
+	require.NoError(t, store.RecordFeedSuccess(feed, time.Second))
+	health, err = store.FindFeedHealth(feed)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), health.ConsecutiveFailures)
+	assert.False(t, health.Quarantined())
+
+	require.NoError(t, store.RecordFeedFailure("https://example.com/other", time.Second, errors.New("timeout"), 0))
+	healths, err := store.FeedHealths()
+	require.NoError(t, err)
+	require.Len(t, healths, 2)
+	assert.Equal(t, feed, healths[1].Feed)
+	assert.False(t, healths[0].Quarantined(), "a threshold of 0 should never quarantine")
+}
+
+func TestORM_Upkeeps(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithKeeperInitiator(cltest.NewAddress())
+	require.NoError(t, store.CreateJob(&job))
+	upkeep := models.NewUpkeep(job.Initiators[0], 500000)
+	require.NoError(t, store.RegisterUpkeep(&upkeep))
+	assert.NotZero(t, upkeep.ID)
+	assert.Equal(t, uint64(500000), upkeep.GasLimit)
+
+	perform := models.UpkeepPerform{
+		UpkeepID:    upkeep.ID,
+		BlockNumber: 10,
+		PerformData: []byte{2},
+		GasLimit:    upkeep.GasLimit,
+	}
+	require.NoError(t, store.CreateUpkeepPerform(&perform))
+	unconfirmed, err := store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	require.Len(t, unconfirmed, 1)
+	assert.Nil(t, unconfirmed[0].TxHash)
+
+	hash := cltest.NewHash()
+	perform.TxHash = &hash
+	perform.GasPrice = utils.NewBig(big.NewInt(20))
+	require.NoError(t, store.SetUpkeepPerformTx(&perform))
+	// Recording the transaction again does not count it twice
+	require.NoError(t, store.SetUpkeepPerformTx(&perform))
+	// Nor is a perform sent deleted
+	require.NoError(t, store.DeleteUpkeepPerform(perform.ID))
+	unconfirmed, err = store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	require.Len(t, unconfirmed, 1)
+	assert.Equal(t, perform.TxHash, unconfirmed[0].TxHash)
+
+	// Registering the upkeep again updates its configuration and keeps its
+	// account
+	job.Initiators[0].Keeper.GasLimit = 100000
+	again := models.NewUpkeep(job.Initiators[0], 500000)
+	require.NoError(t, store.RegisterUpkeep(&again))
+	assert.Equal(t, upkeep.ID, again.ID)
+	assert.Equal(t, uint64(100000), again.GasLimit)
+	assert.Equal(t, uint64(1), again.PerformCount)
+	assert.Equal(t, uint64(10), again.LastPerformedBlock)
+	assert.True(t, again.CoolingDown(12))
+	assert.False(t, again.CoolingDown(13))
+
+	require.NoError(t, store.ConfirmUpkeepPerform(perform.ID, utils.NewBig(big.NewInt(30))))
+	unconfirmed, err = store.UnconfirmedUpkeepPerforms()
+	require.NoError(t, err)
+	assert.Len(t, unconfirmed, 0)
+}
+
+func TestORM_VRFRequests(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithRandomnessLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	early := cltest.CreateVRFRequest(t, store, job, 10)
+	late := cltest.CreateVRFRequest(t, store, job, 20)
+
+	ready, err := store.VRFRequestsReady(15, time.Now())
+	require.NoError(t, err)
+	require.Len(t, ready, 1)
+	assert.Equal(t, early.RequestID, ready[0].RequestID)
+	log, err := ready[0].EthLog()
+	require.NoError(t, err)
+	assert.Equal(t, early.TxHash, log.TxHash)
+
+	// A removed log only removes the request from the block it was in
+	require.NoError(t, store.RemoveVRFRequest(early.RequestID, cltest.NewHash()))
+	require.NoError(t, store.RemoveVRFRequest(late.RequestID, late.BlockHash))
+	ready, err = store.VRFRequestsReady(30, time.Now())
+	require.NoError(t, err)
+	require.Len(t, ready, 1)
+	assert.Equal(t, early.RequestID, ready[0].RequestID)
+
+	// A request logged again in another block after a reorg is pending again
+	requeued := *late
+	requeued.BlockNumber = 21
+	requeued.BlockHash = cltest.NewHash()
+	require.NoError(t, store.CreateVRFRequest(&requeued))
+	found, err := store.FindVRFRequest(late.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.VRFRequestPending, found.Status)
+	assert.Equal(t, uint64(21), found.BlockNumber)
+
+	// Whereas one already being fulfilled is left alone
+	early.Status = models.VRFRequestFulfilling
+	early.Attempts = 1
+	require.NoError(t, store.SaveVRFRequest(early))
+	require.NoError(t, store.CreateVRFRequest(early))
+	fulfilling, err := store.VRFRequestsWithStatus(models.VRFRequestFulfilling)
+	require.NoError(t, err)
+	require.Len(t, fulfilling, 1)
+	assert.Equal(t, uint32(1), fulfilling[0].Attempts)
+
+	later := time.Now().Add(time.Hour)
+	found.NextAttemptAt = later
+	require.NoError(t, store.SaveVRFRequest(&found))
+	ready, err = store.VRFRequestsReady(30, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, ready, 0)
+	ready, err = store.VRFRequestsReady(30, later)
+	require.NoError(t, err)
+	assert.Len(t, ready, 1)
+
+	requests, count, err := store.VRFRequests("", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, requests, 2)
+	assert.Equal(t, late.RequestID, requests[0].RequestID)
+	requests, count, err = store.VRFRequests(models.VRFRequestFulfilling, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, early.RequestID, requests[0].RequestID)
+
+	// A request being fulfilled is pending again if it is logged in another
+	// block before its removal is received
+	moved := *early
+	moved.BlockNumber = 12
+	moved.BlockHash = cltest.NewHash()
+	require.NoError(t, store.CreateVRFRequest(&moved))
+	found, err = store.FindVRFRequest(early.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.VRFRequestPending, found.Status)
+	assert.Equal(t, moved.BlockHash, found.BlockHash)
+	assert.Equal(t, uint64(12), found.BlockNumber)
+	assert.Equal(t, uint32(0), found.Attempts)
+
+	_, err = store.FindVRFRequest(late.ID + early.ID)
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+}
+
+func TestORM_ParkedJobRuns(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	limitedJob := cltest.NewJobWithWebInitiator()
+	limitedJob.MaxConcurrentRuns = 1
+	require.NoError(t, store.CreateJob(&limitedJob))
+	unlimitedJob := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&unlimitedJob))
+
+	parked, err := store.HasParkedJobRuns()
+	require.NoError(t, err)
+	assert.False(t, parked)
+
+	cltest.CreateJobRunWithStatus(t, store, limitedJob, models.RunStatusInProgress)
+	cltest.CreateJobRunWithStatus(t, store, limitedJob, models.RunStatusPendingConcurrency)
+	older := cltest.CreateJobRunWithStatus(t, store, unlimitedJob, models.RunStatusPendingConcurrency)
+	newer := cltest.CreateJobRunWithStatus(t, store, unlimitedJob, models.RunStatusPendingConcurrency)
+
+	parked, err = store.HasParkedJobRuns()
+	require.NoError(t, err)
+	assert.True(t, parked)
+
+	active, err := store.ActiveJobRunsCount(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, active)
+
+	runs, err := store.ParkedJobRuns(10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, older.ID, runs[0].ID)
+	assert.Equal(t, newer.ID, runs[1].ID)
+
+	runs, err = store.ParkedJobRuns(1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, older.ID, runs[0].ID)
+
+	// Runs of a higher priority go first, however recent
+	fluxMonitorJob := cltest.NewJobWithFluxMonitorInitiator()
+	require.NoError(t, store.CreateJob(&fluxMonitorJob))
+	urgent := cltest.CreateJobRunWithStatus(t, store, fluxMonitorJob, models.RunStatusPendingConcurrency)
+
+	runs, err = store.ParkedJobRuns(2)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, urgent.ID, runs[0].ID)
+	assert.Equal(t, older.ID, runs[1].ID)
+}
+
+func TestORM_ParkJobRun(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	run := cltest.CreateJobRunWithStatus(t, store, job, models.RunStatusInProgress)
+
+	require.NoError(t, store.ParkJobRun(run.ID))
+	parked, err := store.FindJobRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingConcurrency, parked.GetStatus())
+
+	assert.Error(t, store.ParkJobRun(run.ID), "a run not in progress cannot be parked")
+	assert.Error(t, store.ParkJobRun(models.NewID()))
+}
+
 func TestORM_CreateJobRun_CreatesRunRequest(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -164,6 +959,43 @@ func TestORM_CreateJobRun_CreatesRunRequest(t *testing.T) {
 	assert.Equal(t, 1, requestCount)
 }
 
+func TestORM_CreateJobRun_ReturnsExistingRunForSameLog(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithRunLogInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	blockHash := cltest.NewHash()
+	newRun := func(logIndex int64) *models.JobRun {
+		rr := models.NewRunRequest(models.JSON{})
+		rr.JobSpecID = job.ID
+		rr.BlockHash = &blockHash
+		rr.LogIndex = null.IntFrom(logIndex)
+		run, _ := services.NewRun(&job, &job.Initiators[0], big.NewInt(0), rr, store.Config, store.ORM, time.Now())
+		return run
+	}
+
+	first := newRun(1)
+	require.NoError(t, store.CreateJobRun(first))
+
+	duplicate := newRun(1)
+	require.NoError(t, store.CreateJobRun(duplicate))
+	assert.Equal(t, first.ID, duplicate.ID)
+
+	other := newRun(2)
+	require.NoError(t, store.CreateJobRun(other))
+	assert.NotEqual(t, first.ID, other.ID)
+
+	runCount, err := store.ORM.CountOf(&models.JobRun{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, runCount)
+	requestCount, err := store.ORM.CountOf(&models.RunRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, requestCount)
+}
+
 func TestORM_SaveJobRun_OnConstraintViolationOtherThanOptimisticLockFailureReturnsError(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -176,7 +1008,8 @@ func TestORM_SaveJobRun_OnConstraintViolationOtherThanOptimisticLockFailureRetur
 	jr.InitiatorID = 0
 	jr.Initiator = models.Initiator{}
 	err := store.SaveJobRun(&jr)
-	assert.EqualError(t, err, "pq: insert or update on table \"job_runs\" violates foreign key constraint \"fk_job_runs_initiator_id\"")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "violates foreign key constraint \"fk_job_runs_initiator_id\"")
 }
 
 func TestORM_SaveJobRun_ArchivedDoesNotRevertDeletedAt(t *testing.T) {
@@ -198,6 +1031,51 @@ func TestORM_SaveJobRun_ArchivedDoesNotRevertDeletedAt(t *testing.T) {
 	require.NoError(t, utils.JustError(store.Unscoped().FindJobRun(jr.ID)))
 }
 
+func TestORM_SaveJobRun_LimitsRunResults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		policy orm.RunResultOversizePolicy
+		stored bool
+	}{
+		{orm.RunResultOversizeStore, true},
+		{orm.RunResultOversizeTruncate, false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.policy), func(t *testing.T) {
+			store, cleanup := cltest.NewStore(t)
+			defer cleanup()
+			store.ORM.SetRunResultLimit(64, test.policy)
+
+			job := cltest.NewJobWithWebInitiator()
+			require.NoError(t, store.CreateJob(&job))
+			jr := cltest.CreateJobRunWithStatus(t, store, job, models.RunStatusInProgress)
+
+			large := cltest.JSONFromString(t, `{"result": "%s"}`, strings.Repeat("a", 100))
+			jr.TaskRuns[0].Result.Data = large
+			require.NoError(t, store.SaveJobRun(&jr))
+			assert.Equal(t, large, jr.TaskRuns[0].Result.Data, "the saved run should keep all of its data")
+
+			saved, err := store.FindJobRun(jr.ID)
+			require.NoError(t, err)
+			truncation, ok, err := saved.TaskRuns[0].Result.Truncation()
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, len(large.String()), truncation.Size)
+			assert.Equal(t, test.stored, truncation.Stored)
+			assert.Equal(t, test.stored, saved.TaskRuns[0].Result.BlobHash.Valid)
+
+			require.NoError(t, store.LoadRunResultBlobs(&saved))
+			if test.stored {
+				assert.Equal(t, large.String(), saved.TaskRuns[0].Result.Data.String())
+			} else {
+				assert.True(t, saved.TaskRuns[0].Result.Data.Get(models.TruncatedKey).Exists())
+			}
+		})
+	}
+}
+
 func TestORM_SaveJobRun_Cancelled(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -334,6 +1212,94 @@ func TestORM_JobRunsSortedFor(t *testing.T) {
 	assert.Equal(t, []*models.ID{jr2.ID, jr1.ID}, actual)
 }
 
+func TestORM_JobRunsSortedAsOf(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	jr1 := cltest.NewJobRun(job)
+	jr1.CreatedAt = time.Now().AddDate(0, 0, -2)
+	require.NoError(t, store.CreateJobRun(&jr1))
+	jr2 := cltest.NewJobRun(job)
+	jr2.CreatedAt = time.Now().AddDate(0, 0, -1)
+	require.NoError(t, store.CreateJobRun(&jr2))
+
+	runs, count, snapshot, err := store.JobRunsSortedAsOf(nil, job.ID, orm.Ascending, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, runs, 1)
+	assert.Equal(t, jr1.ID, runs[0].ID)
+
+	// Runs are created with the time of the node, which may be behind that
+	// of the snapshot, so a run created after it is not seen however old.
+	jr3 := cltest.NewJobRun(job)
+	jr3.CreatedAt = time.Now().AddDate(0, 0, -3)
+	require.NoError(t, store.CreateJobRun(&jr3))
+
+	runs, count, later, err := store.JobRunsSortedAsOf(&snapshot, job.ID, orm.Ascending, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, runs, 1)
+	assert.Equal(t, jr2.ID, runs[0].ID)
+	assert.Equal(t, snapshot.Token(), later.Token())
+
+	_, count, _, err = store.JobRunsSortedAsOf(nil, job.ID, orm.Ascending, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestORM_JobRunsUpdatedAfter(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	before := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		jr := cltest.NewJobRun(job)
+		require.NoError(t, store.CreateJobRun(&jr))
+	}
+	updatedAt := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Exec("UPDATE job_runs SET updated_at = ?", updatedAt).Error
+	}))
+
+	// Paging through runs updated at the same time skips none of them
+	var ids []string
+	after, afterID := before, (*models.ID)(nil)
+	for {
+		runs, err := store.JobRunsUpdatedAfter(job.ID, after, afterID, 2)
+		require.NoError(t, err)
+		if len(runs) == 0 {
+			break
+		}
+		for _, jr := range runs {
+			ids = append(ids, jr.ID.String())
+			after, afterID = jr.UpdatedAt, jr.ID
+		}
+	}
+	assert.Len(t, ids, 3)
+	assert.True(t, sort.StringsAreSorted(ids))
+}
+
+func TestSnapshot_Token(t *testing.T) {
+	t.Parallel()
+
+	snapshot := orm.Snapshot{Seq: 1234}
+	parsed, err := orm.ParseSnapshot(snapshot.Token())
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, parsed)
+
+	_, err = orm.ParseSnapshot("not a token")
+	assert.Error(t, err)
+}
+
 func TestORM_UnscopedJobRunsWithStatus_Happy(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -388,6 +1354,32 @@ func TestORM_UnscopedJobRunsWithStatus_Happy(t *testing.T) {
 	}
 }
 
+func TestORM_CountJobRunsByStatus(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	j := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&j))
+	for _, status := range []models.RunStatus{
+		models.RunStatusPendingBridge,
+		models.RunStatusPendingBridge,
+		models.RunStatusInProgress,
+		models.RunStatusCompleted,
+	} {
+		run := cltest.NewJobRun(j)
+		run.SetStatus(status)
+		require.NoError(t, store.CreateJobRun(&run))
+	}
+
+	counts, err := store.CountJobRunsByStatus(models.RunStatusPendingBridge, models.RunStatusInProgress, models.RunStatusPendingSleep)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.RunStatus]int{
+		models.RunStatusPendingBridge: 2,
+		models.RunStatusInProgress:    1,
+	}, counts)
+}
+
 func TestORM_UnscopedJobRunsWithStatus_Deleted(t *testing.T) {
 	t.Parallel()
 	store, cleanup := cltest.NewStore(t)
@@ -1003,6 +1995,46 @@ func TestORM_AllSyncEvents(t *testing.T) {
 	assert.Greater(t, events[1].ID, events[0].ID)
 }
 
+func TestORM_SyncEventsAfter_OutOfOrderCommits(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	orm := store.ORM
+
+	require.NoError(t, orm.RawDB(func(db *gorm.DB) error {
+		dbtx := db.Begin()
+		require.NoError(t, dbtx.Error)
+		defer dbtx.Rollback()
+		earlier := models.SyncEvent{Body: "earlier"}
+		require.NoError(t, dbtx.Create(&earlier).Error)
+
+		later := models.SyncEvent{Body: "later"}
+		require.NoError(t, db.Create(&later).Error)
+		require.Greater(t, later.ID, earlier.ID)
+
+		events, err := orm.SyncEventsAfter(models.SyncEventCursor{}, 10)
+		require.NoError(t, err)
+		assert.Empty(t, events, "events created after a transaction in progress are held back")
+
+		require.NoError(t, dbtx.Commit().Error)
+		events, err = orm.SyncEventsAfter(models.SyncEventCursor{}, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "earlier", events[0].Body)
+		assert.Equal(t, "later", events[1].Body)
+
+		require.NoError(t, orm.AdvanceSyncEventCursor("test", events[0]))
+		cursor, err := orm.SyncEventCursor("test")
+		require.NoError(t, err)
+		events, err = orm.SyncEventsAfter(cursor, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "later", events[0].Body)
+		return nil
+	}))
+}
+
 func TestBulkDeleteRuns(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
@@ -1074,6 +2106,67 @@ func TestBulkDeleteRuns(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestORM_BulkUpdateRuns(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	bridgeJob := cltest.NewJobWithWebInitiator()
+	bridgeJob.Tasks = []models.TaskSpec{{Type: models.MustNewTaskType("deletedbridge")}}
+	require.NoError(t, store.CreateJob(&bridgeJob))
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	bridgeRun := cltest.NewJobRunPendingBridge(bridgeJob)
+	require.NoError(t, store.CreateJobRun(&bridgeRun))
+	otherBridgeRun := cltest.NewJobRunPendingBridge(job)
+	require.NoError(t, store.CreateJobRun(&otherBridgeRun))
+	completedRun := cltest.CreateJobRunWithStatus(t, store, bridgeJob, models.RunStatusCompleted)
+
+	count, err := store.BulkUpdateRuns(&models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusPendingBridge, models.RunStatusCompleted},
+		Bridge:    models.MustNewTaskType("deletedbridge"),
+		NewStatus: models.RunStatusCancelled,
+		Reason:    "bridge deleted",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	cancelled, err := store.FindJobRun(bridgeRun.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCancelled, cancelled.Status)
+	assert.Equal(t, models.RunStatusCancelled, cancelled.TaskRuns[0].Status)
+	assert.True(t, cancelled.FinishedAt.Valid)
+	assert.Equal(t, "bridge deleted", cancelled.Result.ErrorMessage.String)
+
+	untouched, err := store.FindJobRun(otherBridgeRun.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusPendingBridge, untouched.Status)
+	untouched, err = store.FindJobRun(completedRun.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCompleted, untouched.Status)
+
+	count, err = store.BulkUpdateRuns(&models.BulkUpdateRunRequest{
+		Status:        []models.RunStatus{models.RunStatusPendingBridge},
+		UpdatedBefore: time.Now().Add(-time.Hour),
+		NewStatus:     models.RunStatusErrored,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "no run was last updated an hour ago")
+
+	count, err = store.BulkUpdateRuns(&models.BulkUpdateRunRequest{
+		Status:        []models.RunStatus{models.RunStatusPendingBridge},
+		UpdatedBefore: time.Now().Add(time.Hour),
+		NewStatus:     models.RunStatusErrored,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	errored, err := store.FindJobRun(otherBridgeRun.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusErrored, errored.Status)
+}
+
 func TestORM_FindTxsBySenderAndRecipient(t *testing.T) {
 	t.Parallel()
 
@@ -1339,6 +2432,159 @@ func isDirEmpty(t *testing.T, dir string) bool {
 	return false
 }
 
+func TestORM_HotQueries_MatchPreloads(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithRunLogInitiator()
+	job.Tasks = append(job.Tasks, cltest.NewTask(t, "noop"))
+	require.NoError(t, store.CreateJob(&job))
+	requester := cltest.NewAddress()
+	for i := 0; i < 3; i++ {
+		jr := cltest.NewJobRun(job)
+		jr.RunRequest.Requester = &requester
+		jr.TaskRuns[0].Result = models.RunResult{Data: cltest.JSONFromString(t, `{"result":"1"}`)}
+		require.NoError(t, store.CreateJobRun(&jr))
+
+		found, err := store.FindJobRun(jr.ID)
+		require.NoError(t, err)
+		preloaded, err := store.PreloadedJobRun(jr.ID)
+		require.NoError(t, err)
+		assert.Equal(t, preloaded, found)
+		require.Len(t, found.TaskRuns, 2)
+		assert.Equal(t, `{"result":"1"}`, found.TaskRuns[0].Result.Data.String())
+	}
+
+	runs, err := store.JobRunsFor(job.ID, 2)
+	require.NoError(t, err)
+	preloadedRuns, err := store.PreloadedJobRunsFor(job.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, preloadedRuns, runs)
+
+	_, err = store.FindJobRun(models.NewID())
+	assert.True(t, gorm.IsRecordNotFoundError(err))
+
+	_, err = store.KeyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	from := cltest.GetAccountAddress(t, store)
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		cltest.CreateTxWithNonceAndGasPrice(t, store, from, 1, nonce, 20)
+	}
+	attempts, err := store.UnconfirmedTxAttempts()
+	require.NoError(t, err)
+	preloadedAttempts, err := store.PreloadedUnconfirmedTxAttempts()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, preloadedAttempts, attempts)
+}
+
+func TestORM_HotQueries_UnscopedFindsArchivedRuns(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	jr := cltest.CreateJobRunWithStatus(t, store, job, models.RunStatusPendingBridge)
+	require.NoError(t, store.ArchiveJob(job.ID))
+
+	_, err := store.FindJobRun(jr.ID)
+	assert.True(t, gorm.IsRecordNotFoundError(err))
+	runs, err := store.JobRunsFor(job.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, runs, 0)
+
+	found, err := store.Unscoped().FindJobRun(jr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jr.ID, found.ID)
+	assert.True(t, found.DeletedAt.Valid)
+	require.Len(t, found.TaskRuns, len(jr.TaskRuns))
+	assert.Equal(t, job.Initiators[0].ID, found.Initiator.ID)
+	runs, err = store.Unscoped().JobRunsFor(job.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+}
+
+// setupHotQueriesBenchmark creates a job with runs, and unconfirmed
+// transactions, for benchmarking the hand-written queries against gorm's
+// preloads.
+func setupHotQueriesBenchmark(b *testing.B) (*strpkg.Store, models.JobSpec, func()) {
+	store, cleanup := cltest.NewStore(b)
+	job := cltest.NewJobWithWebInitiator()
+	job.Tasks = append(job.Tasks, models.TaskSpec{Type: adapters.TaskTypeNoOp}, models.TaskSpec{Type: adapters.TaskTypeNoOp})
+	require.NoError(b, store.CreateJob(&job))
+	for i := 0; i < 100; i++ {
+		jr := cltest.NewJobRun(job)
+		require.NoError(b, store.CreateJobRun(&jr))
+	}
+
+	_, err := store.KeyStore.NewAccount(cltest.Password)
+	require.NoError(b, err)
+	from := cltest.GetAccountAddress(b, store)
+	for nonce := uint64(0); nonce < 100; nonce++ {
+		cltest.CreateTxWithNonceAndGasPrice(b, store, from, 1, nonce, 20)
+	}
+	return store, job, cleanup
+}
+
+func BenchmarkORM_FindJobRun(b *testing.B) {
+	store, job, cleanup := setupHotQueriesBenchmark(b)
+	defer cleanup()
+	runs, err := store.JobRunsFor(job.ID, 1)
+	require.NoError(b, err)
+
+	b.Run("hand-written", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := store.FindJobRun(runs[0].ID)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("preloads", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := store.PreloadedJobRun(runs[0].ID)
+			require.NoError(b, err)
+		}
+	})
+}
+
+func BenchmarkORM_JobRunsFor(b *testing.B) {
+	store, job, cleanup := setupHotQueriesBenchmark(b)
+	defer cleanup()
+
+	b.Run("hand-written", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := store.JobRunsFor(job.ID)
+			require.NoError(b, err)
+		}
+	})
+	b.Run("preloads", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := store.PreloadedJobRunsFor(job.ID, 100)
+			require.NoError(b, err)
+		}
+	})
+}
+
+func BenchmarkORM_UnconfirmedTxAttempts(b *testing.B) {
+	store, _, cleanup := setupHotQueriesBenchmark(b)
+	defer cleanup()
+
+	b.Run("hand-written", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := store.UnconfirmedTxAttempts()
+			require.NoError(b, err)
+		}
+	})
+	b.Run("preloads", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			_, err := store.PreloadedUnconfirmedTxAttempts()
+			require.NoError(b, err)
+		}
+	})
+}
+
 func TestORM_UnconfirmedTxAttempts(t *testing.T) {
 	store, cleanup := cltest.NewStore(t)
 	defer cleanup()
@@ -1535,3 +2781,330 @@ func TestJobs_SQLiteBatchSizeIntegrity(t *testing.T) {
 
 	assert.Equal(t, jobNumber, counter)
 }
+
+func TestORM_EncryptPlaintextColumns(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t)
+	require.NoError(t, store.CreateBridgeType(bt))
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Exec("UPDATE bridge_types SET outgoing_token = 'plaintext' WHERE name = ?", bt.Name).Error
+	}))
+
+	require.NoError(t, store.EncryptPlaintextColumns())
+
+	var outgoingToken string
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Table("bridge_types").Where("name = ?", bt.Name).Select("outgoing_token").Row().Scan(&outgoingToken)
+	}))
+	assert.True(t, strings.HasPrefix(outgoingToken, models.EncryptedStringPrefix))
+
+	found, err := store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", found.OutgoingToken.String())
+}
+
+func TestORM_EncryptSessionSecretColumns(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	secret, err := store.Config.SessionSecret()
+	require.NoError(t, err)
+
+	_, bt := cltest.NewBridgeType(t)
+	require.NoError(t, store.CreateBridgeType(bt))
+	encryptedAuth, err := utils.Encrypt(secret, []byte(`{"type":"basic","username":"user","password":"pass"}`))
+	require.NoError(t, err)
+	ccr := cltest.NewClientCertificateRequest(t, "legacy")
+	cc, err := models.NewClientCertificate(&ccr)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveClientCertificate(cc))
+	encryptedPEM, err := utils.Encrypt(secret, []byte(cc.PEM))
+	require.NoError(t, err)
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		if err := db.Exec("UPDATE bridge_types SET auth_type = 'basic', auth_credentials = '', encrypted_auth = ? WHERE name = ?", encryptedAuth, bt.Name).Error; err != nil {
+			return err
+		}
+		return db.Exec("UPDATE client_certificates SET pem = '', encrypted_pem = ? WHERE name = ?", encryptedPEM, cc.Name).Error
+	}))
+
+	require.NoError(t, store.EncryptSessionSecretColumns(secret))
+
+	found, err := store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	auth, err := found.Auth()
+	require.NoError(t, err)
+	require.NotNil(t, auth)
+	assert.Equal(t, "user", auth.Username)
+	foundCC, err := store.FindClientCertificate(cc.Name)
+	require.NoError(t, err)
+	_, err = foundCC.TLSConfig()
+	require.NoError(t, err)
+
+	var authCredentials string
+	var legacyAuth []byte
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Table("bridge_types").Where("name = ?", bt.Name).Select("auth_credentials, encrypted_auth").Row().Scan(&authCredentials, &legacyAuth)
+	}))
+	assert.True(t, strings.HasPrefix(authCredentials, models.EncryptedStringPrefix))
+	assert.Nil(t, legacyAuth)
+}
+
+func TestORM_DropPartition(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	nextMonth := time.Now().UTC().AddDate(0, 1, 0)
+	require.NoError(t, store.CreateMonthPartitions("job_runs", time.Now(), 2))
+	partitions, err := store.Partitions("job_runs")
+	require.NoError(t, err)
+	var partition string
+	for _, p := range partitions {
+		if p.End.After(nextMonth) && p.End.Before(nextMonth.AddDate(0, 1, 0)) {
+			partition = p.Name
+		}
+	}
+	require.NotEmpty(t, partition)
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	createRun := func(status models.RunStatus) models.JobRun {
+		run := cltest.NewJobRun(job)
+		run.SetStatus(status)
+		run.CreatedAt = nextMonth
+		require.NoError(t, store.CreateJobRun(&run))
+		return run
+	}
+	unfinished := createRun(models.RunStatusInProgress)
+
+	dropped, err := store.DropPartition("job_runs", partition)
+	require.NoError(t, err)
+	assert.False(t, dropped, "a partition with unfinished runs should be kept")
+	partitions, err = store.Partitions("job_runs")
+	require.NoError(t, err)
+	var names []string
+	for _, p := range partitions {
+		names = append(names, p.Name)
+	}
+	assert.Contains(t, names, partition, "a partition kept should stay attached")
+
+	unfinished.SetStatus(models.RunStatusCompleted)
+	require.NoError(t, store.SaveJobRun(&unfinished))
+	dropped, err = store.DropPartition("job_runs", partition)
+	require.NoError(t, err)
+	assert.True(t, dropped)
+
+	_, err = store.Unscoped().FindJobRun(unfinished.ID)
+	assert.Error(t, err)
+	count, err := store.ORM.CountOf(&models.TaskRun{})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	var exists bool
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Raw(`SELECT to_regclass(?) IS NOT NULL`, partition).Row().Scan(&exists)
+	}))
+	assert.False(t, exists, "the partition should be dropped")
+}
+
+func TestORM_EnsureIndexes(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	created, err := store.EnsureIndexes()
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Exec(`DROP INDEX log_consumptions_unique_idx`).Error
+	}))
+	created, err = store.EnsureIndexes()
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "log_consumptions_unique_idx", created[0].Name)
+
+	created, err = store.EnsureIndexes()
+	require.NoError(t, err)
+	assert.Empty(t, created)
+}
+
+func TestORM_SlowQueries(t *testing.T) {
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	store.SetSlowQueryThreshold(time.Nanosecond)
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	store.SetSlowQueryThreshold(0)
+
+	var query string
+	require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Raw(`SELECT query FROM slow_queries WHERE query LIKE 'INSERT INTO "job_specs"%' LIMIT 1`).Row().Scan(&query)
+	}))
+	assert.NotContains(t, query, job.ID.String())
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query, normalized string
+	}{
+		{`SELECT * FROM "users" WHERE "email" = $1`, `SELECT * FROM "users" WHERE "email" = $1`},
+		{`UPDATE sessions SET token = 'it''s s3cr3t' WHERE id = 12`, `UPDATE sessions SET token = ? WHERE id = ?`},
+		{`SELECT * FROM job_runs LIMIT 10 OFFSET 2.5`, `SELECT * FROM job_runs LIMIT ? OFFSET ?`},
+		{`SELECT * FROM migration1591450000 WHERE x IN (1,2)`, `SELECT * FROM migration1591450000 WHERE x IN (?,?)`},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.normalized, orm.NormalizeQuery(test.query))
+	}
+}
+
+func TestORM_LookupCache_Invalidation(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	require.NoError(t, store.SetLookupCache(100, time.Hour))
+
+	_, bt := cltest.NewBridgeType(t)
+	require.NoError(t, store.CreateBridgeType(bt))
+	_, err := store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	btr := &models.BridgeTypeRequest{URL: cltest.WebURL(t, "https://example.com/updated")}
+	require.NoError(t, store.UpdateBridgeType(bt, btr))
+	found, err := store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/updated", found.URL.String())
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	_, err = store.FindJob(job.ID)
+	require.NoError(t, err)
+	require.NoError(t, store.ArchiveJob(job.ID))
+	_, err = store.FindJob(job.ID)
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+}
+
+func TestORM_LogConsumptions(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+	other := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&other))
+
+	var lcs []models.LogConsumption
+	for i := uint(0); i < 3; i++ {
+		lc := models.LogConsumption{BlockHash: cltest.NewHash(), LogIndex: i, JobID: job.ID}
+		_, err := store.CreateLogConsumption(&lc)
+		require.NoError(t, err)
+		lcs = append(lcs, lc)
+	}
+	_, err := store.CreateLogConsumption(&models.LogConsumption{BlockHash: cltest.NewHash(), JobID: other.ID})
+	require.NoError(t, err)
+
+	found, count, err := store.LogConsumptionsFor(job.ID, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	require.Len(t, found, 2)
+	assert.Equal(t, lcs[2].ID, found[0].ID)
+
+	assert.Equal(t, orm.ErrorNotFound, store.DeleteLogConsumption(other.ID, lcs[0].ID))
+	require.NoError(t, store.DeleteLogConsumption(job.ID, lcs[0].ID))
+	exists, err := store.LogConsumptionExists(&lcs[0])
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, orm.ErrorNotFound, store.DeleteLogConsumption(job.ID, lcs[0].ID))
+}
+
+func TestORM_TrimLogConsumptions(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	var lcs []models.LogConsumption
+	for block := uint64(10); block <= 12; block++ {
+		lc := models.LogConsumption{BlockHash: cltest.NewHash(), BlockNumber: block, JobID: job.ID}
+		_, err := store.CreateLogConsumption(&lc)
+		require.NoError(t, err)
+		lcs = append(lcs, lc)
+	}
+
+	trimmed, err := store.TrimLogConsumptions(11)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), trimmed)
+	_, count, err := store.LogConsumptionsFor(job.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	exists, err := store.LogConsumptionExists(&lcs[0])
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestORM_Heads_FinalityDepth(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	store.ORM.SetFinalityDepth(150)
+	for number := 0; number <= 200; number++ {
+		require.NoError(t, store.CreateHead(cltest.Head(number)))
+	}
+
+	first, err := store.FirstHead()
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), first.Number)
+
+	finalized, err := store.FinalizedHead(150)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), finalized.Number)
+
+	finalized, err = store.FinalizedHead(151)
+	require.NoError(t, err)
+	assert.Nil(t, finalized)
+}
+
+func TestORM_RunResultValuesFor(t *testing.T) {
+	t.Parallel()
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	var runs []models.JobRun
+	for i, data := range []string{`{"result": 17}`, `{"other": 19}`, `{"result": "23"}`} {
+		run := cltest.NewJobRun(job)
+		run.CreatedAt = time.Date(2020, 6, 1, i, 0, 0, 0, time.UTC)
+		run.Result = models.RunResult{Data: cltest.JSONFromString(t, data)}
+		require.NoError(t, store.CreateJobRun(&run))
+		runs = append(runs, run)
+	}
+
+	since := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)
+	values, count, err := store.RunResultValuesFor(job.ID, []string{"result"}, since, until, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, values, 2)
+	assert.Equal(t, runs[0].ID, values[0].RunID)
+	assert.Equal(t, int64(17), values[0].Value.Int())
+	assert.Equal(t, runs[2].ID, values[1].RunID)
+	assert.Equal(t, "23", values[1].Value.String())
+
+	values, count, err = store.RunResultValuesFor(job.ID, []string{"result"}, since.Add(time.Hour), until, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.Len(t, values, 1)
+	assert.Equal(t, runs[2].ID, values[0].RunID)
+}
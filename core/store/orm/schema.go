@@ -14,60 +14,152 @@ import (
 
 // ConfigSchema records the schema of configuration at the type level
 type ConfigSchema struct {
-	AllowOrigins                    string          `env:"ALLOW_ORIGINS" default:"http://localhost:3000,http://localhost:6688"`
-	BridgeResponseURL               url.URL         `env:"BRIDGE_RESPONSE_URL"`
-	ChainID                         big.Int         `env:"ETH_CHAIN_ID" default:"1"`
-	ClientNodeURL                   string          `env:"CLIENT_NODE_URL" default:"http://localhost:6688"`
-	DatabaseTimeout                 models.Duration `env:"DATABASE_TIMEOUT" default:"500ms"`
-	DatabaseURL                     string          `env:"DATABASE_URL"`
-	DefaultHTTPLimit                int64           `env:"DEFAULT_HTTP_LIMIT" default:"32768"`
-	DefaultHTTPTimeout              models.Duration `env:"DEFAULT_HTTP_TIMEOUT" default:"15s"`
-	Dev                             bool            `env:"CHAINLINK_DEV" default:"false"`
-	EnableExperimentalAdapters      bool            `env:"ENABLE_EXPERIMENTAL_ADAPTERS" default:"false"`
-	FeatureExternalInitiators       bool            `env:"FEATURE_EXTERNAL_INITIATORS" default:"false"`
-	FeatureFluxMonitor              bool            `env:"FEATURE_FLUX_MONITOR" default:"false"`
-	MaximumServiceDuration          models.Duration `env:"MAXIMUM_SERVICE_DURATION" default:"8760h" `
-	MinimumServiceDuration          models.Duration `env:"MINIMUM_SERVICE_DURATION" default:"0s" `
-	EthGasBumpThreshold             uint64          `env:"ETH_GAS_BUMP_THRESHOLD" default:"12" `
-	EthGasBumpWei                   big.Int         `env:"ETH_GAS_BUMP_WEI" default:"5000000000"`
-	EthGasBumpPercent               uint16          `env:"ETH_GAS_BUMP_PERCENT" default:"10"`
-	EthGasLimitDefault              uint64          `env:"ETH_GAS_LIMIT_DEFAULT" default:"500000"`
-	EthGasPriceDefault              big.Int         `env:"ETH_GAS_PRICE_DEFAULT" default:"20000000000"`
-	EthMaxGasPriceWei               uint64          `env:"ETH_MAX_GAS_PRICE_WEI" default:"500000000000"`
-	EthereumURL                     string          `env:"ETH_URL" default:"ws://localhost:8546"`
-	EthereumDisabled                bool            `env:"ETH_DISABLED" default:"false"`
-	GasUpdaterBlockDelay            uint16          `env:"GAS_UPDATER_BLOCK_DELAY" default:"3"`
-	GasUpdaterBlockHistorySize      uint16          `env:"GAS_UPDATER_BLOCK_HISTORY_SIZE" default:"24"`
-	GasUpdaterTransactionPercentile uint16          `env:"GAS_UPDATER_TRANSACTION_PERCENTILE" default:"35"`
-	GasUpdaterEnabled               bool            `env:"GAS_UPDATER_ENABLED" default:"false"`
-	JSONConsole                     bool            `env:"JSON_CONSOLE" default:"false"`
-	LinkContractAddress             string          `env:"LINK_CONTRACT_ADDRESS" default:"0x514910771AF9Ca656af840dff83E8264EcF986CA"`
-	ExplorerURL                     *url.URL        `env:"EXPLORER_URL"`
-	ExplorerAccessKey               string          `env:"EXPLORER_ACCESS_KEY"`
-	ExplorerSecret                  string          `env:"EXPLORER_SECRET"`
-	LogLevel                        LogLevel        `env:"LOG_LEVEL" default:"info"`
-	LogToDisk                       bool            `env:"LOG_TO_DISK" default:"true"`
-	LogSQLStatements                bool            `env:"LOG_SQL" default:"false"`
-	LogSQLMigrations                bool            `env:"LOG_SQL_MIGRATIONS" default:"true"`
-	DefaultMaxHTTPAttempts          uint            `env:"MAX_HTTP_ATTEMPTS" default:"5"`
-	MinIncomingConfirmations        uint32          `env:"MIN_INCOMING_CONFIRMATIONS" default:"3"`
-	MinOutgoingConfirmations        uint64          `env:"MIN_OUTGOING_CONFIRMATIONS" default:"12"`
-	MinimumContractPayment          assets.Link     `env:"MINIMUM_CONTRACT_PAYMENT" default:"1000000000000000000"`
-	MinimumRequestExpiration        uint64          `env:"MINIMUM_REQUEST_EXPIRATION" default:"300"`
-	MaxRPCCallsPerSecond            uint64          `env:"MAX_RPC_CALLS_PER_SECOND" default:"500"`
-	OracleContractAddress           common.Address  `env:"ORACLE_CONTRACT_ADDRESS"`
-	Port                            uint16          `env:"CHAINLINK_PORT" default:"6688"`
-	ReaperExpiration                models.Duration `env:"REAPER_EXPIRATION" default:"240h"`
-	ReplayFromBlock                 int64           `env:"REPLAY_FROM_BLOCK" default:"-1"`
-	RootDir                         string          `env:"ROOT" default:"~/.chainlink"`
-	SecureCookies                   bool            `env:"SECURE_COOKIES" default:"true"`
-	SessionTimeout                  models.Duration `env:"SESSION_TIMEOUT" default:"15m"`
-	TLSCertPath                     string          `env:"TLS_CERT_PATH" `
-	TLSHost                         string          `env:"CHAINLINK_TLS_HOST" `
-	TLSKeyPath                      string          `env:"TLS_KEY_PATH" `
-	TLSPort                         uint16          `env:"CHAINLINK_TLS_PORT" default:"6689"`
-	TLSRedirect                     bool            `env:"CHAINLINK_TLS_REDIRECT" default:"false"`
-	TxAttemptLimit                  uint16          `env:"CHAINLINK_TX_ATTEMPT_LIMIT" default:"10"`
+	AccessViolationRetention           models.Duration         `env:"ACCESS_VIOLATION_RETENTION" default:"720h"`
+	AdapterPluginsDir                  string                  `env:"ADAPTER_PLUGINS_DIR"`
+	AdapterPluginTimeout               models.Duration         `env:"ADAPTER_PLUGIN_TIMEOUT" default:"30s"`
+	AllowOrigins                       string                  `env:"ALLOW_ORIGINS" default:"http://localhost:3000,http://localhost:6688"`
+	APIAllowedCIDRs                    string                  `env:"API_ALLOWED_CIDRS"`
+	BridgeCacheSize                    uint                    `env:"BRIDGE_CACHE_SIZE" default:"1000"`
+	BridgeCallbackTimeout              models.Duration         `env:"BRIDGE_CALLBACK_TIMEOUT" default:"12h"`
+	BridgeCacheStore                   BridgeCacheStore        `env:"BRIDGE_CACHE_STORE" default:"memory"`
+	BridgeCircuitBreakerThreshold      uint                    `env:"BRIDGE_CIRCUIT_BREAKER_THRESHOLD" default:"0"`
+	BridgeCircuitBreakerTimeout        models.Duration         `env:"BRIDGE_CIRCUIT_BREAKER_TIMEOUT" default:"1m"`
+	BridgeResponseURL                  url.URL                 `env:"BRIDGE_RESPONSE_URL"`
+	ChainID                            big.Int                 `env:"ETH_CHAIN_ID" default:"1"`
+	ChainProfile                       string                  `env:"CHAIN_PROFILE"`
+	ClientNodeURL                      string                  `env:"CLIENT_NODE_URL" default:"http://localhost:6688"`
+	ContentSecurityPolicy              string                  `env:"CONTENT_SECURITY_POLICY"`
+	CronCatchUp                        CronCatchUpMode         `env:"CRON_CATCH_UP" default:"none"`
+	CronCatchUpMaxRuns                 uint                    `env:"CRON_CATCH_UP_MAX_RUNS" default:"10"`
+	DatabaseAutoMigrate                bool                    `env:"DATABASE_AUTO_MIGRATE" default:"true"`
+	DatabasePartitionRetention         models.Duration         `env:"DATABASE_PARTITION_RETENTION" default:"0s"`
+	DatabasePartitionsAhead            uint                    `env:"DATABASE_PARTITIONS_AHEAD" default:"3"`
+	DatabaseConnectTimeout             models.Duration         `env:"DATABASE_CONNECT_TIMEOUT" default:"0s"`
+	DatabaseLeaseDuration              models.Duration         `env:"DATABASE_LEASE_DURATION" default:"15s"`
+	DatabaseLockingStrategy            DatabaseLockingStrategy `env:"DATABASE_LOCKING_STRATEGY" default:"advisory"`
+	DatabasePgBouncerCompatibility     bool                    `env:"DATABASE_PGBOUNCER_COMPATIBILITY" default:"false"`
+	DatabaseSlowQueryThreshold         models.Duration         `env:"DATABASE_SLOW_QUERY_THRESHOLD" default:"0s"`
+	DatabaseTimeout                    models.Duration         `env:"DATABASE_TIMEOUT" default:"500ms"`
+	DatabaseURL                        string                  `env:"DATABASE_URL"`
+	DefaultHTTPLimit                   int64                   `env:"DEFAULT_HTTP_LIMIT" default:"32768"`
+	DefaultHTTPTimeout                 models.Duration         `env:"DEFAULT_HTTP_TIMEOUT" default:"15s"`
+	Dev                                bool                    `env:"CHAINLINK_DEV" default:"false"`
+	EnableExperimentalAdapters         bool                    `env:"ENABLE_EXPERIMENTAL_ADAPTERS" default:"false"`
+	ExternalInitiatorAllowedCIDRs      string                  `env:"EXTERNAL_INITIATOR_ALLOWED_CIDRS"`
+	ExternalInitiatorHealthInterval    models.Duration         `env:"EXTERNAL_INITIATOR_HEALTH_CHECK_INTERVAL" default:"1m"`
+	ExternalInitiatorMaxAttempts       uint                    `env:"EXTERNAL_INITIATOR_MAX_ATTEMPTS" default:"8"`
+	ExternalInitiatorRetryBackoff      models.Duration         `env:"EXTERNAL_INITIATOR_RETRY_BACKOFF" default:"30s"`
+	FeatureExternalInitiators          bool                    `env:"FEATURE_EXTERNAL_INITIATORS" default:"false"`
+	FeatureFluxMonitor                 bool                    `env:"FEATURE_FLUX_MONITOR" default:"false"`
+	FeatureOffchainReporting           bool                    `env:"FEATURE_OFFCHAIN_REPORTING" default:"false"`
+	FinalityDepth                      uint64                  `env:"FINALITY_DEPTH" default:"50"`
+	FluxMonitorFeedQuarantinePeriod    models.Duration         `env:"FLUX_MONITOR_FEED_QUARANTINE_PERIOD" default:"5m"`
+	FluxMonitorFeedQuarantineThreshold uint                    `env:"FLUX_MONITOR_FEED_QUARANTINE_THRESHOLD" default:"5"`
+	MaximumServiceDuration             models.Duration         `env:"MAXIMUM_SERVICE_DURATION" default:"8760h" `
+	MinimumServiceDuration             models.Duration         `env:"MINIMUM_SERVICE_DURATION" default:"0s" `
+	EthBlockTime                       models.Duration         `env:"ETH_BLOCK_TIME" default:"15s"`
+	EthGasBumpThreshold                uint64                  `env:"ETH_GAS_BUMP_THRESHOLD" default:"12" `
+	EthGasBumpWei                      big.Int                 `env:"ETH_GAS_BUMP_WEI" default:"5000000000"`
+	EthGasBumpPercent                  uint16                  `env:"ETH_GAS_BUMP_PERCENT" default:"10"`
+	EthGasLimitDefault                 uint64                  `env:"ETH_GAS_LIMIT_DEFAULT" default:"500000"`
+	EthGasPriceDefault                 big.Int                 `env:"ETH_GAS_PRICE_DEFAULT" default:"20000000000"`
+	EthMaxGasPriceWei                  uint64                  `env:"ETH_MAX_GAS_PRICE_WEI" default:"500000000000"`
+	EthereumURL                        string                  `env:"ETH_URL" default:"ws://localhost:8546"`
+	EthereumDisabled                   bool                    `env:"ETH_DISABLED" default:"false"`
+	EthQuorumDepth                     uint                    `env:"ETH_QUORUM_DEPTH" default:"3"`
+	EthQuorumDivergenceThreshold       uint                    `env:"ETH_QUORUM_DIVERGENCE_THRESHOLD" default:"2"`
+	EthQuorumURL                       string                  `env:"ETH_QUORUM_URL"`
+	GasUpdaterBlockDelay               uint16                  `env:"GAS_UPDATER_BLOCK_DELAY" default:"3"`
+	GasUpdaterBlockHistorySize         uint16                  `env:"GAS_UPDATER_BLOCK_HISTORY_SIZE" default:"24"`
+	GasUpdaterTransactionPercentile    uint16                  `env:"GAS_UPDATER_TRANSACTION_PERCENTILE" default:"35"`
+	GasUpdaterEnabled                  bool                    `env:"GAS_UPDATER_ENABLED" default:"false"`
+	HealthMaxHeadAge                   models.Duration         `env:"HEALTH_MAX_HEAD_AGE" default:"5m"`
+	HSTSIncludeSubdomains              bool                    `env:"HSTS_INCLUDE_SUBDOMAINS" default:"true"`
+	HSTSMaxAge                         models.Duration         `env:"HSTS_MAX_AGE" default:"1440h"`
+	HSTSPreload                        bool                    `env:"HSTS_PRELOAD" default:"false"`
+	HTTPAllowedHosts                   string                  `env:"HTTP_ALLOWED_HOSTS"`
+	HTTPDeniedHosts                    string                  `env:"HTTP_DENIED_HOSTS"`
+	HTTPMaxRedirects                   uint                    `env:"HTTP_MAX_REDIRECTS" default:"10"`
+	IncomingConfirmationsTimeout       models.Duration         `env:"INCOMING_CONFIRMATIONS_TIMEOUT" default:"24h"`
+	JSONConsole                        bool                    `env:"JSON_CONSOLE" default:"false"`
+	KafkaBrokers                       string                  `env:"KAFKA_BROKERS" default:""`
+	KeyFundingAddress                  common.Address          `env:"KEY_FUNDING_ADDRESS"`
+	KeyFundingAmountWei                big.Int                 `env:"KEY_FUNDING_AMOUNT_WEI" default:"500000000000000000"`
+	KeyFundingCheckInterval            models.Duration         `env:"KEY_FUNDING_CHECK_INTERVAL" default:"1m"`
+	KeyFundingCooldown                 models.Duration         `env:"KEY_FUNDING_COOLDOWN" default:"1h"`
+	KeyFundingMaxPerDayWei             big.Int                 `env:"KEY_FUNDING_MAX_PER_DAY_WEI" default:"2000000000000000000"`
+	KeyFundingThresholdWei             big.Int                 `env:"KEY_FUNDING_THRESHOLD_WEI" default:"100000000000000000"`
+	LeakWatchdogInterval               models.Duration         `env:"LEAK_WATCHDOG_INTERVAL" default:"1m"`
+	LeakWatchdogWindow                 uint                    `env:"LEAK_WATCHDOG_WINDOW" default:"10"`
+	LinkContractAddress                string                  `env:"LINK_CONTRACT_ADDRESS" default:"0x514910771AF9Ca656af840dff83E8264EcF986CA"`
+	ExplorerURL                        *url.URL                `env:"EXPLORER_URL"`
+	ExplorerAccessKey                  string                  `env:"EXPLORER_ACCESS_KEY"`
+	ExplorerSecret                     string                  `env:"EXPLORER_SECRET"`
+	LogConsumptionRetentionDepth       uint64                  `env:"LOG_CONSUMPTION_RETENTION_DEPTH" default:"0"`
+	LogLevel                           LogLevel                `env:"LOG_LEVEL" default:"info"`
+	LogModuleLevels                    ModuleLevels            `env:"LOG_MODULE_LEVELS" default:""`
+	LogToDisk                          bool                    `env:"LOG_TO_DISK" default:"true"`
+	LogSQLStatements                   bool                    `env:"LOG_SQL" default:"false"`
+	LookupCacheSize                    uint                    `env:"LOOKUP_CACHE_SIZE" default:"1000"`
+	LookupCacheTTL                     models.Duration         `env:"LOOKUP_CACHE_TTL" default:"30s"`
+	LogSQLMigrations                   bool                    `env:"LOG_SQL_MIGRATIONS" default:"true"`
+	DefaultMaxHTTPAttempts             uint                    `env:"MAX_HTTP_ATTEMPTS" default:"5"`
+	MinIncomingConfirmations           uint32                  `env:"MIN_INCOMING_CONFIRMATIONS" default:"3"`
+	MinOutgoingConfirmations           uint64                  `env:"MIN_OUTGOING_CONFIRMATIONS" default:"12"`
+	MinimumContractPayment             assets.Link             `env:"MINIMUM_CONTRACT_PAYMENT" default:"1000000000000000000"`
+	MinimumRequestExpiration           uint64                  `env:"MINIMUM_REQUEST_EXPIRATION" default:"300"`
+	MaxRPCCallsPerSecond               uint64                  `env:"MAX_RPC_CALLS_PER_SECOND" default:"500"`
+	MaxConcurrentRuns                  uint                    `env:"MAX_CONCURRENT_RUNS" default:"0"`
+	MetricsAllowedCIDRs                string                  `env:"METRICS_ALLOWED_CIDRS"`
+	OracleContractAddress              common.Address          `env:"ORACLE_CONTRACT_ADDRESS"`
+	P2PListenPort                      uint16                  `env:"P2P_LISTEN_PORT" default:"6690"`
+	Port                               uint16                  `env:"CHAINLINK_PORT" default:"6688"`
+	ReaperExpiration                   models.Duration         `env:"REAPER_EXPIRATION" default:"240h"`
+	ReplayFromBlock                    int64                   `env:"REPLAY_FROM_BLOCK" default:"-1"`
+	RootDir                            string                  `env:"ROOT" default:"~/.chainlink"`
+	RunQueueCapacity                   uint                    `env:"RUN_QUEUE_CAPACITY" default:"10000"`
+	RunQueueWorkers                    uint                    `env:"RUN_QUEUE_WORKERS" default:"100"`
+	RunResultArchiveAccessKey          string                  `env:"RUN_RESULT_ARCHIVE_ACCESS_KEY"`
+	RunResultArchiveAfter              models.Duration         `env:"RUN_RESULT_ARCHIVE_AFTER" default:"0s"`
+	RunResultArchiveEndpoint           *url.URL                `env:"RUN_RESULT_ARCHIVE_ENDPOINT"`
+	RunResultArchiveRegion             string                  `env:"RUN_RESULT_ARCHIVE_REGION"`
+	RunResultArchiveSecret             string                  `env:"RUN_RESULT_ARCHIVE_SECRET"`
+	RunResultArchiveURL                *url.URL                `env:"RUN_RESULT_ARCHIVE_URL"`
+	RunResultMaxSize                   uint64                  `env:"RUN_RESULT_MAX_SIZE" default:"0"`
+	RunResultOversizePolicy            RunResultOversizePolicy `env:"RUN_RESULT_OVERSIZE_POLICY" default:"store"`
+	RunStatsRollupInterval             models.Duration         `env:"RUN_STATS_ROLLUP_INTERVAL" default:"5m"`
+	SecureCookies                      bool                    `env:"SECURE_COOKIES" default:"true"`
+	ServiceAgreementExpiryNotice       models.Duration         `env:"SERVICE_AGREEMENT_EXPIRY_NOTICE" default:"24h"`
+	SessionTimeout                     models.Duration         `env:"SESSION_TIMEOUT" default:"15m"`
+	StuckRunCheckInterval              models.Duration         `env:"STUCK_RUN_CHECK_INTERVAL" default:"5m"`
+	StuckRunConfirmationsThreshold     models.Duration         `env:"STUCK_RUN_CONFIRMATIONS_THRESHOLD" default:"1h"`
+	StuckRunInProgressThreshold        models.Duration         `env:"STUCK_RUN_IN_PROGRESS_THRESHOLD" default:"15m"`
+	StuckRunMaxResumptions             uint                    `env:"STUCK_RUN_MAX_RESUMPTIONS" default:"3"`
+	StuckRunPendingBridgeThreshold     models.Duration         `env:"STUCK_RUN_PENDING_BRIDGE_THRESHOLD" default:"24h"`
+	SyncEventExportAccessKey           string                  `env:"SYNC_EVENT_EXPORT_ACCESS_KEY"`
+	SyncEventExportBatchSize           uint                    `env:"SYNC_EVENT_EXPORT_BATCH_SIZE" default:"10000"`
+	SyncEventExportEndpoint            *url.URL                `env:"SYNC_EVENT_EXPORT_ENDPOINT"`
+	SyncEventExportInterval            models.Duration         `env:"SYNC_EVENT_EXPORT_INTERVAL" default:"10m"`
+	SyncEventExportRegion              string                  `env:"SYNC_EVENT_EXPORT_REGION"`
+	SyncEventExportSecret              string                  `env:"SYNC_EVENT_EXPORT_SECRET"`
+	SyncEventExportURL                 *url.URL                `env:"SYNC_EVENT_EXPORT_URL"`
+	TLSCertPath                        string                  `env:"TLS_CERT_PATH" `
+	TLSHost                            string                  `env:"CHAINLINK_TLS_HOST" `
+	TLSKeyPath                         string                  `env:"TLS_KEY_PATH" `
+	TLSPort                            uint16                  `env:"CHAINLINK_TLS_PORT" default:"6689"`
+	TLSRedirect                        bool                    `env:"CHAINLINK_TLS_REDIRECT" default:"false"`
+	TxAttemptLimit                     uint16                  `env:"CHAINLINK_TX_ATTEMPT_LIMIT" default:"10"`
+	VRFBatchMaxSize                    uint                    `env:"VRF_BATCH_MAX_SIZE" default:"1"`
+	VRFBatchMaxWait                    models.Duration         `env:"VRF_BATCH_MAX_WAIT" default:"10s"`
+	VRFBatchMulticallAddress           common.Address          `env:"VRF_BATCH_MULTICALL_ADDRESS"`
+	VRFFulfillmentRetryBackoff         models.Duration         `env:"VRF_FULFILLMENT_RETRY_BACKOFF" default:"1m"`
+	VRFMaxFulfillmentAttempts          uint                    `env:"VRF_MAX_FULFILLMENT_ATTEMPTS" default:"5"`
+	VRFMinConfirmations                uint32                  `env:"VRF_MIN_CONFIRMATIONS" default:"6"`
+	VRFProofQueueSize                  uint                    `env:"VRF_PROOF_QUEUE_SIZE" default:"100"`
+	VRFProofWorkers                    uint                    `env:"VRF_PROOF_WORKERS" default:"2"`
+	WasmMaxMemoryPages                 uint32                  `env:"WASM_MAX_MEMORY_PAGES" default:"16"`
+	WasmMaxModuleSize                  uint64                  `env:"WASM_MAX_MODULE_SIZE" default:"1048576"`
+	WasmTimeout                        models.Duration         `env:"WASM_TIMEOUT" default:"1s"`
 }
 
 // EnvVarName gets the environment variable name for a config schema field
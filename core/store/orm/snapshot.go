@@ -0,0 +1,103 @@
+package orm
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Snapshot is the last record a paginated listing sees, by the sequence the
+// database numbers the records with as it creates them, so that its later
+// pages agree with the count of its first one while records are being
+// created. Records deleted since it was taken are not seen.
+type Snapshot struct {
+	Seq int64
+}
+
+// ParseSnapshot returns the Snapshot of a token returned by Snapshot.Token.
+func ParseSnapshot(token string) (Snapshot, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Snapshot{}, errors.Wrap(err, "invalid snapshot token")
+	}
+	seq, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return Snapshot{}, errors.Wrap(err, "invalid snapshot token")
+	}
+	return Snapshot{Seq: seq}, nil
+}
+
+// Token returns the opaque token clients pass back for the later pages of
+// a listing.
+func (s Snapshot) Token() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(s.Seq, 10)))
+}
+
+// readSnapshot passes fn a REPEATABLE READ, READ ONLY transaction, so that
+// the count and the page it reads agree, along with the last sequence number
+// of the records it reads. That is the one of the snapshot given, or else
+// the greatest of column in table, which is returned for the later pages.
+func (orm *ORM) readSnapshot(snapshot *Snapshot, table, column string, fn func(tx *gorm.DB, seq int64) error) (Snapshot, error) {
+	tx := orm.db.Begin()
+	if tx.Error != nil {
+		return Snapshot{}, tx.Error
+	}
+	defer tx.Rollback()
+
+	if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").Error; err != nil {
+		return Snapshot{}, errors.Wrap(err, "starting snapshot read")
+	}
+	var s Snapshot
+	if snapshot != nil {
+		s = *snapshot
+	} else if err := tx.Raw(fmt.Sprintf("SELECT COALESCE(MAX(%s), 0) FROM %s", column, table)).Row().Scan(&s.Seq); err != nil {
+		return Snapshot{}, errors.Wrap(err, "taking snapshot")
+	}
+	return s, fn(tx, s.Seq)
+}
+
+// TransactionsAsOf is Transactions, counting and reading the transactions
+// created by the last one of snapshot, or all of them if it is nil, in one
+// snapshot of the database.
+func (orm *ORM) TransactionsAsOf(snapshot *Snapshot, offset, limit int) ([]models.Tx, int, Snapshot, error) {
+	var txs []models.Tx
+	var count int
+	s, err := orm.readSnapshot(snapshot, "txes", "id", func(tx *gorm.DB, seq int64) error {
+		scope := tx.Model(&models.Tx{}).Where("id <= ?", seq)
+		if err := scope.Count(&count).Error; err != nil {
+			return err
+		}
+		return scope.Set("gorm:auto_preload", true).
+			Order("id desc").Limit(limit).Offset(offset).
+			Find(&txs).Error
+	})
+	return txs, count, s, err
+}
+
+// JobRunsSortedAsOf is JobRunsSorted, or JobRunsSortedFor when id is not nil,
+// counting and reading the runs created by the last one of snapshot, or all
+// of them if it is nil, in one snapshot of the database.
+func (orm *ORM) JobRunsSortedAsOf(snapshot *Snapshot, id *models.ID, order SortType, offset, limit int) ([]models.JobRun, int, Snapshot, error) {
+	var runs []models.JobRun
+	var count int
+	s, err := orm.readSnapshot(snapshot, "job_runs", "seq", func(tx *gorm.DB, seq int64) error {
+		scope := tx.Where("seq <= ?", seq)
+		if id != nil {
+			scope = scope.Where("job_spec_id = ?", id)
+		}
+		if err := scope.Model(&models.JobRun{}).Count(&count).Error; err != nil {
+			return err
+		}
+		return preloadJobRunsOn(scope).
+			Order(fmt.Sprintf("created_at %s", order.String())).
+			Limit(limit).
+			Offset(offset).
+			Find(&runs).Error
+	})
+	return runs, count, s, err
+}
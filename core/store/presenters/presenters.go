@@ -11,13 +11,16 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/auth"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/services/synchronization"
 	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/migrations"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
@@ -125,38 +128,120 @@ type ConfigWhitelist struct {
 
 // Whitelist contains the supported environment variables
 type Whitelist struct {
-	AllowOrigins             string          `json:"allowOrigins"`
-	BridgeResponseURL        string          `json:"bridgeResponseURL,omitempty"`
-	ChainID                  *big.Int        `json:"ethChainId"`
-	ClientNodeURL            string          `json:"clientNodeUrl"`
-	DatabaseTimeout          models.Duration `json:"databaseTimeout"`
-	Dev                      bool            `json:"chainlinkDev"`
-	EthereumURL              string          `json:"ethUrl"`
-	EthGasBumpThreshold      uint64          `json:"ethGasBumpThreshold"`
-	EthGasBumpWei            *big.Int        `json:"ethGasBumpWei"`
-	EthGasPriceDefault       *big.Int        `json:"ethGasPriceDefault"`
-	ExplorerURL              string          `json:"explorerUrl"`
-	JSONConsole              bool            `json:"jsonConsole"`
-	LinkContractAddress      string          `json:"linkContractAddress"`
-	LogLevel                 orm.LogLevel    `json:"logLevel"`
-	LogSQLMigrations         bool            `json:"logSqlMigrations"`
-	LogSQLStatements         bool            `json:"logSqlStatements"`
-	LogToDisk                bool            `json:"logToDisk"`
-	MaxRPCCallsPerSecond     uint64          `json:"maxRPCCallsPerSecond"`
-	MinimumContractPayment   *assets.Link    `json:"minimumContractPayment"`
-	MinimumRequestExpiration uint64          `json:"minimumRequestExpiration"`
-	MinIncomingConfirmations uint32          `json:"minIncomingConfirmations"`
-	MinOutgoingConfirmations uint64          `json:"minOutgoingConfirmations"`
-	OracleContractAddress    *common.Address `json:"oracleContractAddress"`
-	Port                     uint16          `json:"chainlinkPort"`
-	ReaperExpiration         models.Duration `json:"reaperExpiration"`
-	ReplayFromBlock          int64           `json:"replayFromBlock"`
-	RootDir                  string          `json:"root"`
-	SessionTimeout           models.Duration `json:"sessionTimeout"`
-	TLSHost                  string          `json:"chainlinkTLSHost"`
-	TLSPort                  uint16          `json:"chainlinkTLSPort"`
-	TLSRedirect              bool            `json:"chainlinkTLSRedirect"`
-	TxAttemptLimit           uint16          `json:"txAttemptLimit"`
+	AccessViolationRetention           models.Duration             `json:"accessViolationRetention"`
+	AdapterPluginsDir                  string                      `json:"adapterPluginsDir"`
+	AdapterPluginTimeout               models.Duration             `json:"adapterPluginTimeout"`
+	AllowOrigins                       string                      `json:"allowOrigins"`
+	APIAllowedCIDRs                    []string                    `json:"apiAllowedCIDRs"`
+	BridgeCacheSize                    uint                        `json:"bridgeCacheSize"`
+	BridgeCacheStore                   orm.BridgeCacheStore        `json:"bridgeCacheStore"`
+	BridgeCallbackTimeout              models.Duration             `json:"bridgeCallbackTimeout"`
+	BridgeCircuitBreakerThreshold      uint                        `json:"bridgeCircuitBreakerThreshold"`
+	BridgeCircuitBreakerTimeout        models.Duration             `json:"bridgeCircuitBreakerTimeout"`
+	BridgeResponseURL                  string                      `json:"bridgeResponseURL,omitempty"`
+	ChainID                            *big.Int                    `json:"ethChainId"`
+	ChainProfile                       string                      `json:"chainProfile"`
+	ClientNodeURL                      string                      `json:"clientNodeUrl"`
+	ContentSecurityPolicy              string                      `json:"contentSecurityPolicy"`
+	CronCatchUp                        orm.CronCatchUpMode         `json:"cronCatchUp"`
+	CronCatchUpMaxRuns                 uint                        `json:"cronCatchUpMaxRuns"`
+	DatabaseAutoMigrate                bool                        `json:"databaseAutoMigrate"`
+	DatabasePartitionRetention         models.Duration             `json:"databasePartitionRetention"`
+	DatabasePartitionsAhead            uint                        `json:"databasePartitionsAhead"`
+	DatabaseConnectTimeout             models.Duration             `json:"databaseConnectTimeout"`
+	DatabaseLeaseDuration              models.Duration             `json:"databaseLeaseDuration"`
+	DatabaseLockingStrategy            orm.DatabaseLockingStrategy `json:"databaseLockingStrategy"`
+	DatabasePgBouncerCompatibility     bool                        `json:"databasePgBouncerCompatibility"`
+	DatabaseSlowQueryThreshold         models.Duration             `json:"databaseSlowQueryThreshold"`
+	DatabaseTimeout                    models.Duration             `json:"databaseTimeout"`
+	Dev                                bool                        `json:"chainlinkDev"`
+	EthereumURL                        string                      `json:"ethUrl"`
+	EthBlockTime                       models.Duration             `json:"ethBlockTime"`
+	EthGasBumpPercent                  uint16                      `json:"ethGasBumpPercent"`
+	EthGasBumpThreshold                uint64                      `json:"ethGasBumpThreshold"`
+	EthGasBumpWei                      *big.Int                    `json:"ethGasBumpWei"`
+	EthGasPriceDefault                 *big.Int                    `json:"ethGasPriceDefault"`
+	EthMaxGasPriceWei                  *big.Int                    `json:"ethMaxGasPriceWei"`
+	EthQuorumDepth                     uint                        `json:"ethQuorumDepth"`
+	EthQuorumDivergenceThreshold       uint                        `json:"ethQuorumDivergenceThreshold"`
+	EthQuorumURL                       string                      `json:"ethQuorumUrl,omitempty"`
+	ExplorerURL                        string                      `json:"explorerUrl"`
+	ExternalInitiatorAllowedCIDRs      []string                    `json:"externalInitiatorAllowedCIDRs"`
+	FinalityDepth                      uint64                      `json:"finalityDepth"`
+	FluxMonitorFeedQuarantinePeriod    models.Duration             `json:"fluxMonitorFeedQuarantinePeriod"`
+	FluxMonitorFeedQuarantineThreshold uint                        `json:"fluxMonitorFeedQuarantineThreshold"`
+	HealthMaxHeadAge                   models.Duration             `json:"healthMaxHeadAge"`
+	HSTSIncludeSubdomains              bool                        `json:"hstsIncludeSubdomains"`
+	HSTSMaxAge                         models.Duration             `json:"hstsMaxAge"`
+	HSTSPreload                        bool                        `json:"hstsPreload"`
+	JSONConsole                        bool                        `json:"jsonConsole"`
+	HTTPAllowedHosts                   []string                    `json:"httpAllowedHosts"`
+	HTTPDeniedHosts                    []string                    `json:"httpDeniedHosts"`
+	HTTPMaxRedirects                   uint                        `json:"httpMaxRedirects"`
+	IncomingConfirmationsTimeout       models.Duration             `json:"incomingConfirmationsTimeout"`
+	KafkaBrokers                       []string                    `json:"kafkaBrokers"`
+	KeyFundingAddress                  *common.Address             `json:"keyFundingAddress"`
+	KeyFundingAmountWei                *big.Int                    `json:"keyFundingAmountWei"`
+	KeyFundingCheckInterval            models.Duration             `json:"keyFundingCheckInterval"`
+	KeyFundingCooldown                 models.Duration             `json:"keyFundingCooldown"`
+	KeyFundingMaxPerDayWei             *big.Int                    `json:"keyFundingMaxPerDayWei"`
+	KeyFundingThresholdWei             *big.Int                    `json:"keyFundingThresholdWei"`
+	LeakWatchdogInterval               models.Duration             `json:"leakWatchdogInterval"`
+	LeakWatchdogWindow                 uint                        `json:"leakWatchdogWindow"`
+	LinkContractAddress                string                      `json:"linkContractAddress"`
+	LogConsumptionRetentionDepth       uint64                      `json:"logConsumptionRetentionDepth"`
+	LogLevel                           orm.LogLevel                `json:"logLevel"`
+	LogModuleLevels                    orm.ModuleLevels            `json:"logModuleLevels"`
+	LogSQLMigrations                   bool                        `json:"logSqlMigrations"`
+	LogSQLStatements                   bool                        `json:"logSqlStatements"`
+	LogToDisk                          bool                        `json:"logToDisk"`
+	LookupCacheSize                    uint                        `json:"lookupCacheSize"`
+	LookupCacheTTL                     models.Duration             `json:"lookupCacheTTL"`
+	MaxConcurrentRuns                  uint                        `json:"maxConcurrentRuns"`
+	MetricsAllowedCIDRs                []string                    `json:"metricsAllowedCIDRs"`
+	MaxRPCCallsPerSecond               uint64                      `json:"maxRPCCallsPerSecond"`
+	MinimumContractPayment             *assets.Link                `json:"minimumContractPayment"`
+	MinimumRequestExpiration           uint64                      `json:"minimumRequestExpiration"`
+	MinIncomingConfirmations           uint32                      `json:"minIncomingConfirmations"`
+	MinOutgoingConfirmations           uint64                      `json:"minOutgoingConfirmations"`
+	OracleContractAddress              *common.Address             `json:"oracleContractAddress"`
+	P2PListenPort                      uint16                      `json:"p2pListenPort"`
+	Port                               uint16                      `json:"chainlinkPort"`
+	ReaperExpiration                   models.Duration             `json:"reaperExpiration"`
+	ReplayFromBlock                    int64                       `json:"replayFromBlock"`
+	RootDir                            string                      `json:"root"`
+	RunQueueCapacity                   uint                        `json:"runQueueCapacity"`
+	RunQueueWorkers                    uint                        `json:"runQueueWorkers"`
+	RunResultArchiveAfter              models.Duration             `json:"runResultArchiveAfter"`
+	RunResultArchiveURL                string                      `json:"runResultArchiveUrl"`
+	RunResultMaxSize                   uint64                      `json:"runResultMaxSize"`
+	RunResultOversizePolicy            orm.RunResultOversizePolicy `json:"runResultOversizePolicy"`
+	RunStatsRollupInterval             models.Duration             `json:"runStatsRollupInterval"`
+	ServiceAgreementExpiryNotice       models.Duration             `json:"serviceAgreementExpiryNotice"`
+	SessionTimeout                     models.Duration             `json:"sessionTimeout"`
+	StuckRunCheckInterval              models.Duration             `json:"stuckRunCheckInterval"`
+	StuckRunConfirmationsThreshold     models.Duration             `json:"stuckRunConfirmationsThreshold"`
+	StuckRunInProgressThreshold        models.Duration             `json:"stuckRunInProgressThreshold"`
+	StuckRunMaxResumptions             uint                        `json:"stuckRunMaxResumptions"`
+	StuckRunPendingBridgeThreshold     models.Duration             `json:"stuckRunPendingBridgeThreshold"`
+	SyncEventExportBatchSize           uint                        `json:"syncEventExportBatchSize"`
+	SyncEventExportInterval            models.Duration             `json:"syncEventExportInterval"`
+	SyncEventExportURL                 string                      `json:"syncEventExportUrl"`
+	TLSHost                            string                      `json:"chainlinkTLSHost"`
+	TLSPort                            uint16                      `json:"chainlinkTLSPort"`
+	TLSRedirect                        bool                        `json:"chainlinkTLSRedirect"`
+	TxAttemptLimit                     uint16                      `json:"txAttemptLimit"`
+	VRFBatchMaxSize                    uint                        `json:"vrfBatchMaxSize"`
+	VRFBatchMaxWait                    models.Duration             `json:"vrfBatchMaxWait"`
+	VRFBatchMulticallAddress           *common.Address             `json:"vrfBatchMulticallAddress"`
+	VRFFulfillmentRetryBackoff         models.Duration             `json:"vrfFulfillmentRetryBackoff"`
+	VRFMaxFulfillmentAttempts          uint                        `json:"vrfMaxFulfillmentAttempts"`
+	VRFMinConfirmations                uint32                      `json:"vrfMinConfirmations"`
+	VRFProofQueueSize                  uint                        `json:"vrfProofQueueSize"`
+	VRFProofWorkers                    uint                        `json:"vrfProofWorkers"`
+	WasmMaxMemoryPages                 uint32                      `json:"wasmMaxMemoryPages"`
+	WasmMaxModuleSize                  uint64                      `json:"wasmMaxModuleSize"`
+	WasmTimeout                        models.Duration             `json:"wasmTimeout"`
 }
 
 // NewConfigWhitelist creates an instance of ConfigWhitelist
@@ -171,41 +256,131 @@ func NewConfigWhitelist(store *store.Store) (ConfigWhitelist, error) {
 	if config.ExplorerURL() != nil {
 		explorerURL = config.ExplorerURL().String()
 	}
+	runResultArchiveURL := ""
+	if config.RunResultArchiveURL() != nil {
+		runResultArchiveURL = config.RunResultArchiveURL().String()
+	}
+	syncEventExportURL := ""
+	if config.SyncEventExportURL() != nil {
+		syncEventExportURL = config.SyncEventExportURL().String()
+	}
 	return ConfigWhitelist{
 		AccountAddress: account.Address.Hex(),
 		Whitelist: Whitelist{
-			AllowOrigins:             config.AllowOrigins(),
-			BridgeResponseURL:        config.BridgeResponseURL().String(),
-			ChainID:                  config.ChainID(),
-			ClientNodeURL:            config.ClientNodeURL(),
-			Dev:                      config.Dev(),
-			DatabaseTimeout:          config.DatabaseTimeout(),
-			EthereumURL:              config.EthereumURL(),
-			EthGasBumpThreshold:      config.EthGasBumpThreshold(),
-			EthGasBumpWei:            config.EthGasBumpWei(),
-			EthGasPriceDefault:       config.EthGasPriceDefault(),
-			JSONConsole:              config.JSONConsole(),
-			LinkContractAddress:      config.LinkContractAddress(),
-			ExplorerURL:              explorerURL,
-			LogLevel:                 config.LogLevel(),
-			LogToDisk:                config.LogToDisk(),
-			LogSQLStatements:         config.LogSQLStatements(),
-			LogSQLMigrations:         config.LogSQLMigrations(),
-			MaxRPCCallsPerSecond:     config.MaxRPCCallsPerSecond(),
-			MinimumContractPayment:   config.MinimumContractPayment(),
-			MinimumRequestExpiration: config.MinimumRequestExpiration(),
-			MinIncomingConfirmations: config.MinIncomingConfirmations(),
-			MinOutgoingConfirmations: config.MinOutgoingConfirmations(),
-			OracleContractAddress:    config.OracleContractAddress(),
-			Port:                     config.Port(),
-			ReaperExpiration:         config.ReaperExpiration(),
-			ReplayFromBlock:          config.ReplayFromBlock(),
-			RootDir:                  config.RootDir(),
-			SessionTimeout:           config.SessionTimeout(),
-			TLSHost:                  config.TLSHost(),
-			TLSPort:                  config.TLSPort(),
-			TLSRedirect:              config.TLSRedirect(),
-			TxAttemptLimit:           config.TxAttemptLimit(),
+			AccessViolationRetention:           config.AccessViolationRetention(),
+			AdapterPluginsDir:                  config.AdapterPluginsDir(),
+			AdapterPluginTimeout:               config.AdapterPluginTimeout(),
+			AllowOrigins:                       config.AllowOrigins(),
+			APIAllowedCIDRs:                    config.APIAllowedCIDRs(),
+			BridgeCacheSize:                    config.BridgeCacheSize(),
+			BridgeCacheStore:                   config.BridgeCacheStore(),
+			BridgeCallbackTimeout:              config.BridgeCallbackTimeout(),
+			BridgeCircuitBreakerThreshold:      config.BridgeCircuitBreakerThreshold(),
+			BridgeCircuitBreakerTimeout:        config.BridgeCircuitBreakerTimeout(),
+			BridgeResponseURL:                  config.BridgeResponseURL().String(),
+			ChainID:                            config.ChainID(),
+			ChainProfile:                       config.ChainProfile(),
+			ClientNodeURL:                      config.ClientNodeURL(),
+			ContentSecurityPolicy:              config.ContentSecurityPolicy(),
+			CronCatchUp:                        config.CronCatchUp(),
+			CronCatchUpMaxRuns:                 config.CronCatchUpMaxRuns(),
+			Dev:                                config.Dev(),
+			DatabaseAutoMigrate:                config.DatabaseAutoMigrate(),
+			DatabasePartitionRetention:         config.DatabasePartitionRetention(),
+			DatabasePartitionsAhead:            config.DatabasePartitionsAhead(),
+			DatabaseConnectTimeout:             config.DatabaseConnectTimeout(),
+			DatabaseLeaseDuration:              config.DatabaseLeaseDuration(),
+			DatabaseLockingStrategy:            config.DatabaseLockingStrategy(),
+			DatabasePgBouncerCompatibility:     config.DatabasePgBouncerCompatibility(),
+			DatabaseSlowQueryThreshold:         config.DatabaseSlowQueryThreshold(),
+			DatabaseTimeout:                    config.DatabaseTimeout(),
+			EthereumURL:                        config.EthereumURL(),
+			EthBlockTime:                       config.EthBlockTime(),
+			EthGasBumpPercent:                  config.EthGasBumpPercent(),
+			EthGasBumpThreshold:                config.EthGasBumpThreshold(),
+			EthGasBumpWei:                      config.EthGasBumpWei(),
+			EthGasPriceDefault:                 config.EthGasPriceDefault(),
+			EthMaxGasPriceWei:                  config.EthMaxGasPriceWei(),
+			EthQuorumDepth:                     config.EthQuorumDepth(),
+			EthQuorumDivergenceThreshold:       config.EthQuorumDivergenceThreshold(),
+			EthQuorumURL:                       config.EthQuorumURL(),
+			HealthMaxHeadAge:                   config.HealthMaxHeadAge(),
+			HSTSIncludeSubdomains:              config.HSTSIncludeSubdomains(),
+			HSTSMaxAge:                         config.HSTSMaxAge(),
+			HSTSPreload:                        config.HSTSPreload(),
+			JSONConsole:                        config.JSONConsole(),
+			HTTPAllowedHosts:                   config.HTTPAllowedHosts(),
+			HTTPDeniedHosts:                    config.HTTPDeniedHosts(),
+			HTTPMaxRedirects:                   config.HTTPMaxRedirects(),
+			IncomingConfirmationsTimeout:       config.IncomingConfirmationsTimeout(),
+			KafkaBrokers:                       config.KafkaBrokers(),
+			KeyFundingAddress:                  config.KeyFundingAddress(),
+			KeyFundingAmountWei:                config.KeyFundingAmountWei(),
+			KeyFundingCheckInterval:            config.KeyFundingCheckInterval(),
+			KeyFundingCooldown:                 config.KeyFundingCooldown(),
+			KeyFundingMaxPerDayWei:             config.KeyFundingMaxPerDayWei(),
+			KeyFundingThresholdWei:             config.KeyFundingThresholdWei(),
+			LeakWatchdogInterval:               config.LeakWatchdogInterval(),
+			LeakWatchdogWindow:                 config.LeakWatchdogWindow(),
+			LinkContractAddress:                config.LinkContractAddress(),
+			ExplorerURL:                        explorerURL,
+			ExternalInitiatorAllowedCIDRs:      config.ExternalInitiatorAllowedCIDRs(),
+			FinalityDepth:                      config.FinalityDepth(),
+			FluxMonitorFeedQuarantinePeriod:    config.FluxMonitorFeedQuarantinePeriod(),
+			FluxMonitorFeedQuarantineThreshold: config.FluxMonitorFeedQuarantineThreshold(),
+			LogConsumptionRetentionDepth:       config.LogConsumptionRetentionDepth(),
+			LogLevel:                           config.LogLevel(),
+			LogModuleLevels:                    config.LogModuleLevels(),
+			LogToDisk:                          config.LogToDisk(),
+			LogSQLStatements:                   config.LogSQLStatements(),
+			LookupCacheSize:                    config.LookupCacheSize(),
+			LookupCacheTTL:                     config.LookupCacheTTL(),
+			LogSQLMigrations:                   config.LogSQLMigrations(),
+			MaxConcurrentRuns:                  config.MaxConcurrentRuns(),
+			MetricsAllowedCIDRs:                config.MetricsAllowedCIDRs(),
+			MaxRPCCallsPerSecond:               config.MaxRPCCallsPerSecond(),
+			MinimumContractPayment:             config.MinimumContractPayment(),
+			MinimumRequestExpiration:           config.MinimumRequestExpiration(),
+			MinIncomingConfirmations:           config.MinIncomingConfirmations(),
+			MinOutgoingConfirmations:           config.MinOutgoingConfirmations(),
+			OracleContractAddress:              config.OracleContractAddress(),
+			P2PListenPort:                      config.P2PListenPort(),
+			Port:                               config.Port(),
+			ReaperExpiration:                   config.ReaperExpiration(),
+			ReplayFromBlock:                    config.ReplayFromBlock(),
+			RootDir:                            config.RootDir(),
+			RunQueueCapacity:                   config.RunQueueCapacity(),
+			RunQueueWorkers:                    config.RunQueueWorkers(),
+			RunResultArchiveAfter:              config.RunResultArchiveAfter(),
+			RunResultArchiveURL:                runResultArchiveURL,
+			RunResultMaxSize:                   config.RunResultMaxSize(),
+			RunResultOversizePolicy:            config.RunResultOversizePolicy(),
+			RunStatsRollupInterval:             config.RunStatsRollupInterval(),
+			ServiceAgreementExpiryNotice:       config.ServiceAgreementExpiryNotice(),
+			SessionTimeout:                     config.SessionTimeout(),
+			StuckRunCheckInterval:              config.StuckRunCheckInterval(),
+			StuckRunConfirmationsThreshold:     config.StuckRunConfirmationsThreshold(),
+			StuckRunInProgressThreshold:        config.StuckRunInProgressThreshold(),
+			StuckRunMaxResumptions:             config.StuckRunMaxResumptions(),
+			StuckRunPendingBridgeThreshold:     config.StuckRunPendingBridgeThreshold(),
+			SyncEventExportBatchSize:           config.SyncEventExportBatchSize(),
+			SyncEventExportInterval:            config.SyncEventExportInterval(),
+			SyncEventExportURL:                 syncEventExportURL,
+			TLSHost:                            config.TLSHost(),
+			TLSPort:                            config.TLSPort(),
+			TLSRedirect:                        config.TLSRedirect(),
+			TxAttemptLimit:                     config.TxAttemptLimit(),
+			VRFBatchMaxSize:                    config.VRFBatchMaxSize(),
+			VRFBatchMaxWait:                    config.VRFBatchMaxWait(),
+			VRFBatchMulticallAddress:           config.VRFBatchMulticallAddress(),
+			VRFFulfillmentRetryBackoff:         config.VRFFulfillmentRetryBackoff(),
+			VRFMaxFulfillmentAttempts:          config.VRFMaxFulfillmentAttempts(),
+			VRFMinConfirmations:                config.VRFMinConfirmations(),
+			VRFProofQueueSize:                  config.VRFProofQueueSize(),
+			VRFProofWorkers:                    config.VRFProofWorkers(),
+			WasmMaxMemoryPages:                 config.WasmMaxMemoryPages(),
+			WasmMaxModuleSize:                  config.WasmMaxModuleSize(),
+			WasmTimeout:                        config.WasmTimeout(),
 		},
 	}, nil
 }
@@ -259,6 +434,39 @@ func (c *ConfigWhitelist) SetID(value string) error {
 	return nil
 }
 
+// JobSpecDiff lists the changes made to a JobSpec since a prior version.
+type JobSpecDiff struct {
+	Version uint32                 `json:"version"`
+	Changes []models.JobSpecChange `json:"changes"`
+}
+
+// GetID returns the prior version number for jsonapi serialization.
+func (d JobSpecDiff) GetID() string {
+	return strconv.FormatUint(uint64(d.Version), 10)
+}
+
+// GetName returns the pluralized "type" of this structure for jsonapi serialization.
+func (d JobSpecDiff) GetName() string {
+	return "job_spec_diffs"
+}
+
+// SetID is used to set the version number when deserializing from jsonapi documents.
+func (d *JobSpecDiff) SetID(value string) error {
+	version, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return err
+	}
+	d.Version = uint32(version)
+	return nil
+}
+
+// BridgeType holds a bridge together with the health of the requests made
+// to it.
+type BridgeType struct {
+	models.BridgeType
+	Health *models.BridgeHealth `json:"health"`
+}
+
 // JobSpec holds the JobSpec definition together with
 // the total link earned from that job
 type JobSpec struct {
@@ -359,16 +567,35 @@ func initiatorParams(i Initiator) (interface{}, error) {
 	case models.InitiatorServiceAgreementExecutionLog:
 		return struct{}{}, nil
 	case models.InitiatorCron:
+		var calendar *models.CronCalendar
+		if !i.Calendar.Empty() {
+			calendar = &i.Calendar
+		}
 		return struct {
-			Schedule models.Cron `json:"schedule"`
-		}{i.Schedule}, nil
+			Schedule models.Cron          `json:"schedule"`
+			Timezone string               `json:"timezone,omitempty"`
+			Calendar *models.CronCalendar `json:"calendar,omitempty"`
+		}{i.Schedule, i.Timezone, calendar}, nil
 	case models.InitiatorRunAt:
+		var delay, every *models.Duration
+		if !i.Delay.IsInstant() {
+			delay = &i.Delay
+		}
+		if !i.Every.IsInstant() {
+			every = &i.Every
+		}
 		return struct {
-			Time models.AnyTime `json:"time"`
-			Ran  bool           `json:"ran"`
-		}{models.NewAnyTime(i.Time.Time), i.Ran}, nil
+			Time      models.AnyTime   `json:"time"`
+			Ran       bool             `json:"ran"`
+			Delay     *models.Duration `json:"delay,omitempty"`
+			Every     *models.Duration `json:"every,omitempty"`
+			NextRunAt *time.Time       `json:"nextRunAt,omitempty"`
+		}{models.NewAnyTime(i.Time.Time), i.Ran, delay, every, i.NextRunAt.Ptr()}, nil
 	case models.InitiatorEthLog:
-		fallthrough
+		return struct {
+			Address common.Address `json:"address"`
+			ABI     models.LogABI  `json:"abi,omitempty"`
+		}{i.Address, i.ABI}, nil
 	case models.InitiatorRunLog:
 		return struct {
 			Address common.Address `json:"address"`
@@ -388,6 +615,43 @@ func initiatorParams(i Initiator) (interface{}, error) {
 		}{i.Address, i.RequestData, i.Feeds, i.Threshold, i.Precision, i.PollTimer.Period}, nil
 	case models.InitiatorRandomnessLog:
 		return struct{ Address common.Address }{i.Address}, nil
+	case models.InitiatorKafka:
+		return struct {
+			KafkaTopics models.StringCollection `json:"kafkaTopics"`
+		}{i.KafkaTopics}, nil
+	case models.InitiatorMQTT:
+		return struct {
+			BrokerURL   string `json:"brokerUrl"`
+			TopicFilter string `json:"topicFilter"`
+			QoS         byte   `json:"qos"`
+		}{i.BrokerURL, i.TopicFilter, i.QoS}, nil
+	case models.InitiatorStream:
+		return struct {
+			Bridge      models.TaskType `json:"bridge"`
+			RequestData models.JSON     `json:"requestData"`
+		}{i.BridgeName, i.RequestData}, nil
+	case models.InitiatorOffchainReporting:
+		return struct {
+			Address         common.Address                   `json:"address"`
+			RequestData     models.JSON                      `json:"requestData"`
+			Feeds           models.JSON                      `json:"feeds"`
+			Threshold       float32                          `json:"threshold"`
+			Precision       int32                            `json:"precision"`
+			PollingInterval models.Duration                  `json:"pollingInterval"`
+			Oracles         []models.OffchainReportingOracle `json:"oracles"`
+		}{i.Address, i.RequestData, i.Feeds, i.Threshold, i.Precision, i.PollTimer.Period, i.OffchainReporting.Oracles}, nil
+	case models.InitiatorKeeper:
+		return struct {
+			Address        common.Address `json:"address"`
+			CheckData      hexutil.Bytes  `json:"checkData"`
+			GasLimit       uint64         `json:"gasLimit"`
+			CooldownBlocks uint64         `json:"cooldownBlocks"`
+		}{i.Address, i.Keeper.CheckData, i.Keeper.GasLimit, i.Keeper.CooldownBlocks}, nil
+	case models.InitiatorBlockCondition:
+		return struct {
+			Expression    models.BlockCondition `json:"expression"`
+			EdgeTriggered bool                  `json:"edgeTriggered"`
+		}{i.BlockCondition.Expression, i.BlockCondition.EdgeTriggered}, nil
 	default:
 		return nil, fmt.Errorf("Cannot marshal unsupported initiator type '%v'", i.Type)
 	}
@@ -395,10 +659,17 @@ func initiatorParams(i Initiator) (interface{}, error) {
 
 // FriendlyRunAt returns a human-readable string for Cron Initiator types.
 func (i Initiator) FriendlyRunAt() string {
-	if i.Type == models.InitiatorRunAt {
+	if i.Type != models.InitiatorRunAt {
+		return ""
+	}
+	switch {
+	case i.NextRunAt.Valid:
+		return utils.ISO8601UTC(i.NextRunAt.Time)
+	case i.Time.Valid:
 		return utils.ISO8601UTC(i.Time.Time)
+	default:
+		return fmt.Sprintf("%s after creation", i.Delay)
 	}
-	return ""
 }
 
 // FriendlyAddress returns the Ethereum address if present, and a blank
@@ -427,6 +698,27 @@ func (jr JobRun) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// RunUpdate is a change of a JobRun's status, as streamed to the clients
+// tailing the runs of a node.
+type RunUpdate struct {
+	RunID     string           `json:"runId"`
+	JobID     string           `json:"jobId"`
+	Status    models.RunStatus `json:"status"`
+	Error     string           `json:"error,omitempty"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// NewRunUpdate returns the update of the run's latest status.
+func NewRunUpdate(jr models.JobRun) RunUpdate {
+	return RunUpdate{
+		RunID:     jr.ID.String(),
+		JobID:     jr.JobSpecID.String(),
+		Status:    jr.Status,
+		Error:     jr.Result.ErrorMessage.String,
+		UpdatedAt: jr.UpdatedAt,
+	}
+}
+
 // TaskSpec holds a task specified in the Job definition.
 type TaskSpec struct {
 	models.TaskSpec
@@ -455,17 +747,26 @@ func FriendlyBigInt(n *big.Int) string {
 // ServiceAgreement presents an API friendly version of the data.
 type ServiceAgreement struct {
 	models.ServiceAgreement
+	Status models.ServiceAgreementStatus
+}
+
+// NewServiceAgreement returns the presentation of the service agreement, with
+// its status at now given the expiry notice.
+func NewServiceAgreement(sa models.ServiceAgreement, now time.Time, notice time.Duration) ServiceAgreement {
+	return ServiceAgreement{ServiceAgreement: sa, Status: sa.Status(now, notice)}
 }
 
 type ServiceAgreementPresentation struct {
-	ID            string             `json:"id"`
-	CreatedAt     string             `json:"createdAt"`
-	Encumbrance   models.Encumbrance `json:"encumbrance"`
-	EncumbranceID uint               `json:"encumbranceID"`
-	RequestBody   string             `json:"requestBody"`
-	Signature     string             `json:"signature"`
-	JobSpec       models.JobSpec     `json:"jobSpec"`
-	JobSpecID     string             `json:"jobSpecId"`
+	ID            string                        `json:"id"`
+	CreatedAt     string                        `json:"createdAt"`
+	Encumbrance   models.Encumbrance            `json:"encumbrance"`
+	EncumbranceID uint                          `json:"encumbranceID"`
+	RequestBody   string                        `json:"requestBody"`
+	Signature     string                        `json:"signature"`
+	JobSpec       models.JobSpec                `json:"jobSpec"`
+	JobSpecID     string                        `json:"jobSpecId"`
+	Status        models.ServiceAgreementStatus `json:"status,omitempty"`
+	TerminatedAt  *time.Time                    `json:"terminatedAt,omitempty"`
 }
 
 // MarshalJSON presents the ServiceAgreement as public JSON data
@@ -479,6 +780,8 @@ func (sa ServiceAgreement) MarshalJSON() ([]byte, error) {
 		Signature:     sa.Signature.String(),
 		JobSpec:       sa.JobSpec,
 		JobSpecID:     sa.JobSpecID.String(),
+		Status:        sa.Status,
+		TerminatedAt:  sa.TerminatedAt.Ptr(),
 	})
 }
 
@@ -561,6 +864,140 @@ func (a NewAccount) GetName() string {
 	return "keys"
 }
 
+// KeyRotation is the outcome of replacing an ethereum key by a new one.
+type KeyRotation struct {
+	Retired       common.Address `json:"retired"`
+	Replacement   common.Address `json:"replacement"`
+	SweepTxHashes []common.Hash  `json:"sweepTxHashes"`
+}
+
+// NewKeyRotation returns the keys involved in the rotation, and the hashes
+// of any transactions sweeping funds from the retired key.
+func NewKeyRotation(rotation *store.KeyRotation) KeyRotation {
+	hashes := make([]common.Hash, len(rotation.Sweeps))
+	for i, tx := range rotation.Sweeps {
+		hashes[i] = tx.Hash
+	}
+	return KeyRotation{
+		Retired:       rotation.Retired,
+		Replacement:   rotation.Replacement,
+		SweepTxHashes: hashes,
+	}
+}
+
+// CronPreview is the runs a cron initiator schedules next, and when each is
+// made given its calendar.
+type CronPreview struct {
+	Schedule models.Cron      `json:"schedule"`
+	Timezone string           `json:"timezone,omitempty"`
+	Runs     []models.CronRun `json:"runs"`
+}
+
+// NewCronPreview returns the preview of the runs of the cron initiator.
+func NewCronPreview(initr models.Initiator, runs []models.CronRun) CronPreview {
+	return CronPreview{
+		Schedule: initr.Schedule,
+		Timezone: initr.Timezone,
+		Runs:     runs,
+	}
+}
+
+// GetID returns the jsonapi ID.
+func (p CronPreview) GetID() string {
+	return string(p.Schedule.WithTimezone(p.Timezone))
+}
+
+// GetName returns the collection name for jsonapi.
+func (CronPreview) GetName() string {
+	return "cron_previews"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (*CronPreview) SetID(string) error {
+	return nil
+}
+
+// LogReplay is the replay of the logs of a block range for a job.
+type LogReplay struct {
+	JobID     *models.ID `json:"jobId"`
+	FromBlock uint64     `json:"fromBlock"`
+	ToBlock   uint64     `json:"toBlock"`
+}
+
+// GetID returns the jsonapi ID.
+func (r LogReplay) GetID() string {
+	return fmt.Sprintf("%s-%d-%d", r.JobID, r.FromBlock, r.ToBlock)
+}
+
+// GetName returns the collection name for jsonapi.
+func (LogReplay) GetName() string {
+	return "log_replays"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (*LogReplay) SetID(string) error {
+	return nil
+}
+
+// GetID returns the jsonapi ID.
+func (r KeyRotation) GetID() string {
+	return r.Replacement.Hex()
+}
+
+// GetName returns the collection name for jsonapi.
+func (KeyRotation) GetName() string {
+	return "key_rotations"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (r *KeyRotation) SetID(value string) error {
+	r.Replacement = common.HexToAddress(value)
+	return nil
+}
+
+// ImportedKey is a key found in a bundle of keys imported into the node.
+type ImportedKey struct {
+	Type   string `json:"type"`
+	Key    string `json:"key"`
+	Status string `json:"status"`
+}
+
+// NewImportedKeys returns the keys found in an imported bundle, with whether
+// they were imported, or would be on a dry run, or were already present.
+func NewImportedKeys(keys []store.ImportedKey, dryRun bool) []ImportedKey {
+	pkeys := make([]ImportedKey, len(keys))
+	for i, k := range keys {
+		status := "imported"
+		if k.Exists {
+			status = "already present"
+		} else if dryRun {
+			status = "valid"
+		}
+		pkeys[i] = ImportedKey{Type: k.Type, Key: k.ID, Status: status}
+	}
+	return pkeys
+}
+
+// GetID returns the jsonapi ID.
+func (k ImportedKey) GetID() string {
+	return k.Key
+}
+
+// GetName returns the collection name for jsonapi.
+func (ImportedKey) GetName() string {
+	return "imported_keys"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (k *ImportedKey) SetID(value string) error {
+	k.Key = value
+	return nil
+}
+
 // Tx is a jsonapi wrapper for an Ethereum Transaction.
 type Tx struct {
 	Confirmed bool            `json:"confirmed,omitempty"`
@@ -640,6 +1077,9 @@ type ExternalInitiatorAuthentication struct {
 	Secret         string        `json:"incomingSecret,omitempty"`
 	OutgoingToken  string        `json:"outgoingToken,omitempty"`
 	OutgoingSecret string        `json:"outgoingSecret,omitempty"`
+
+	RequireSignature bool   `json:"requireSignature"`
+	SigningSecret    string `json:"signingSecret,omitempty"`
 }
 
 // NewExternalInitiatorAuthentication creates an instance of ExternalInitiatorAuthentication.
@@ -651,8 +1091,11 @@ func NewExternalInitiatorAuthentication(
 		Name:           ei.Name,
 		AccessKey:      ei.AccessKey,
 		Secret:         eia.Secret,
-		OutgoingToken:  ei.OutgoingToken,
-		OutgoingSecret: ei.OutgoingSecret,
+		OutgoingToken:  ei.OutgoingToken.String(),
+		OutgoingSecret: ei.OutgoingSecret.String(),
+
+		RequireSignature: ei.RequireSignature,
+		SigningSecret:    ei.SigningSecret.String(),
 	}
 	if ei.URL != nil {
 		result.URL = *ei.URL
@@ -677,6 +1120,57 @@ func (ei *ExternalInitiatorAuthentication) SetID(name string) error {
 	return nil
 }
 
+// ExternalInitiator shows an external initiator with its health, and the
+// notices the node most recently failed to deliver to it.
+type ExternalInitiator struct {
+	Name                   string                                    `json:"name"`
+	URL                    *models.WebURL                            `json:"url,omitempty"`
+	AccessKey              string                                    `json:"incomingAccessKey"`
+	RequireSignature       bool                                      `json:"requireSignature"`
+	LastSeenAt             *time.Time                                `json:"lastSeenAt"`
+	LastHealthCheckAt      *time.Time                                `json:"lastHealthCheckAt"`
+	Healthy                bool                                      `json:"healthy"`
+	HealthCheckError       string                                    `json:"healthCheckError,omitempty"`
+	RecentDeliveryFailures []models.ExternalInitiatorDeliveryFailure `json:"recentDeliveryFailures"`
+	CreatedAt              time.Time                                 `json:"createdAt"`
+}
+
+// NewExternalInitiator creates an instance of ExternalInitiator.
+func NewExternalInitiator(ei models.ExternalInitiator, failures []models.ExternalInitiatorDeliveryFailure) ExternalInitiator {
+	if failures == nil {
+		failures = []models.ExternalInitiatorDeliveryFailure{}
+	}
+	return ExternalInitiator{
+		Name:                   ei.Name,
+		URL:                    ei.URL,
+		AccessKey:              ei.AccessKey,
+		RequireSignature:       ei.RequireSignature,
+		LastSeenAt:             ei.LastSeenAt,
+		LastHealthCheckAt:      ei.LastHealthCheckAt,
+		Healthy:                ei.Healthy(),
+		HealthCheckError:       ei.HealthCheckError.ValueOrZero(),
+		RecentDeliveryFailures: failures,
+		CreatedAt:              ei.CreatedAt,
+	}
+}
+
+// GetID returns the jsonapi ID.
+func (ei ExternalInitiator) GetID() string {
+	return ei.Name
+}
+
+// GetName returns the collection name for jsonapi.
+func (ExternalInitiator) GetName() string {
+	return "external_initiators"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (ei *ExternalInitiator) SetID(name string) error {
+	ei.Name = name
+	return nil
+}
+
 // ExplorerStatus represents the connected server and status of the connection
 type ExplorerStatus struct {
 	Status string `json:"status"`
@@ -692,3 +1186,255 @@ func NewExplorerStatus(statsPusher synchronization.StatsPusher) ExplorerStatus {
 		Url:    url.String(),
 	}
 }
+
+// VRFKey holds the public parts of a VRF proving key: the compressed public
+// key used by the Random tasks of jobs, and the uncompressed public key and
+// key hash used to register it with a VRFCoordinator.
+type VRFKey struct {
+	Compressed   string      `json:"compressed"`
+	Uncompressed string      `json:"uncompressed"`
+	Hash         common.Hash `json:"hash"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+// NewVRFKey returns the public parts of the encrypted key.
+func NewVRFKey(key *vrfkey.EncryptedSecretKey) (*VRFKey, error) {
+	uncompressed, err := key.PublicKey.StringUncompressed()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := key.PublicKey.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return &VRFKey{
+		Compressed:   key.PublicKey.String(),
+		Uncompressed: uncompressed,
+		Hash:         hash,
+		CreatedAt:    key.CreatedAt,
+	}, nil
+}
+
+// GetID returns the jsonapi ID.
+func (k VRFKey) GetID() string {
+	return k.Compressed
+}
+
+// GetName returns the collection name for jsonapi.
+func (VRFKey) GetName() string {
+	return "vrf_keys"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (k *VRFKey) SetID(value string) error {
+	k.Compressed = value
+	return nil
+}
+
+// MigrationStatus is the schema version of the database and its pending
+// migrations.
+type MigrationStatus struct {
+	migrations.Status
+}
+
+// GetID returns the jsonapi ID.
+func (s MigrationStatus) GetID() string {
+	return s.Latest
+}
+
+// GetName returns the collection name for jsonapi.
+func (MigrationStatus) GetName() string {
+	return "migration_statuses"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (s *MigrationStatus) SetID(value string) error {
+	s.Latest = value
+	return nil
+}
+
+// BulkJobResult is the outcome of one item of a bulk job request, in the
+// order of the request.
+type BulkJobResult struct {
+	Index int    `json:"index"`
+	JobID string `json:"jobId,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// GetID returns the jsonapi ID.
+func (r BulkJobResult) GetID() string {
+	return strconv.Itoa(r.Index)
+}
+
+// GetName returns the collection name for jsonapi.
+func (BulkJobResult) GetName() string {
+	return "bulk_job_results"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (r *BulkJobResult) SetID(value string) error {
+	index, err := strconv.Atoi(value)
+	r.Index = index
+	return err
+}
+
+// BulkRunUpdate is the outcome of a bulk update of runs, the number of runs
+// moved to the new status.
+type BulkRunUpdate struct {
+	NewStatus models.RunStatus `json:"newStatus"`
+	Count     int              `json:"count"`
+}
+
+// GetID returns the jsonapi ID.
+func (u BulkRunUpdate) GetID() string {
+	return string(u.NewStatus)
+}
+
+// GetName returns the collection name for jsonapi.
+func (BulkRunUpdate) GetName() string {
+	return "bulk_run_updates"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (u *BulkRunUpdate) SetID(value string) error {
+	u.NewStatus = models.RunStatus(value)
+	return nil
+}
+
+// ChainHead is a head saved by the node, by block number and hash.
+type ChainHead struct {
+	Number int64       `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// NewChainHead returns the head presented, or nil if there is none.
+func NewChainHead(head *models.Head) *ChainHead {
+	if head == nil {
+		return nil
+	}
+	return &ChainHead{Number: head.Number, Hash: head.Hash}
+}
+
+// ChainHeads are the latest head saved by the node, and the finalized head,
+// FINALITY_DEPTH blocks behind it.
+type ChainHeads struct {
+	Latest        *ChainHead `json:"latest"`
+	Finalized     *ChainHead `json:"finalized"`
+	FinalityDepth uint64     `json:"finalityDepth"`
+}
+
+// GetID returns the jsonapi ID.
+func (h ChainHeads) GetID() string {
+	if h.Latest == nil {
+		return ""
+	}
+	return h.Latest.Hash.Hex()
+}
+
+// GetName returns the collection name for jsonapi.
+func (ChainHeads) GetName() string {
+	return "chain_heads"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (*ChainHeads) SetID(string) error {
+	return nil
+}
+
+// SignedResult is the result of a run as signed by a signresult task, with
+// the signature, the address of the account which signed it, and the hash
+// it signed.
+type SignedResult struct {
+	RunID      *models.ID      `json:"runId"`
+	Result     json.RawMessage `json:"result"`
+	Signature  string          `json:"signature"`
+	Signer     string          `json:"signer"`
+	SignedHash string          `json:"signedHash"`
+}
+
+// NewSignedResult returns the result of the run as signed by its signresult
+// task run.
+func NewSignedResult(runID *models.ID, tr models.TaskRun) SignedResult {
+	data := tr.Result.Data
+	return SignedResult{
+		RunID:      runID,
+		Result:     json.RawMessage(data.Get("result").Raw),
+		Signature:  data.Get("signature").String(),
+		Signer:     data.Get("signer").String(),
+		SignedHash: data.Get("signedHash").String(),
+	}
+}
+
+// GetID returns the jsonapi ID.
+func (r SignedResult) GetID() string {
+	return r.RunID.String()
+}
+
+// GetName returns the collection name for jsonapi.
+func (SignedResult) GetName() string {
+	return "signed_results"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (*SignedResult) SetID(string) error {
+	return nil
+}
+
+// ImportedJobBundle is a job imported from a bundle, with the credentials of
+// the bridges and external initiators created for it, which are only shown
+// once, and the names of the secrets created for it.
+type ImportedJobBundle struct {
+	Job                JobSpec                            `json:"job"`
+	Bridges            []*models.BridgeTypeAuthentication `json:"bridges"`
+	ExternalInitiators []*ExternalInitiatorAuthentication `json:"externalInitiators"`
+	Secrets            []string                           `json:"secrets"`
+}
+
+// NewImportedJobBundle returns the job imported from a bundle, with the
+// dependencies created for it and the credentials of its bridges and
+// external initiators, given in the same order as them.
+func NewImportedJobBundle(
+	job models.JobSpec,
+	deps models.JobDependencies,
+	bridgeAuths []*models.BridgeTypeAuthentication,
+	eiTokens []*auth.Token,
+) ImportedJobBundle {
+	imported := ImportedJobBundle{
+		Job:                JobSpec{JobSpec: job},
+		Bridges:            bridgeAuths,
+		ExternalInitiators: make([]*ExternalInitiatorAuthentication, len(deps.ExternalInitiators)),
+		Secrets:            make([]string, len(deps.Secrets)),
+	}
+	if imported.Bridges == nil {
+		imported.Bridges = []*models.BridgeTypeAuthentication{}
+	}
+	for i, ei := range deps.ExternalInitiators {
+		imported.ExternalInitiators[i] = NewExternalInitiatorAuthentication(*ei, *eiTokens[i])
+	}
+	for i, secret := range deps.Secrets {
+		imported.Secrets[i] = secret.Name
+	}
+	return imported
+}
+
+// GetID returns the jsonapi ID.
+func (b ImportedJobBundle) GetID() string {
+	return b.Job.ID.String()
+}
+
+// GetName returns the collection name for jsonapi.
+func (ImportedJobBundle) GetName() string {
+	return "imported_job_bundles"
+}
+
+// SetID is used to conform to the UnmarshallIdentifier interface for
+// deserializing from jsonapi documents.
+func (b *ImportedJobBundle) SetID(value string) error {
+	return b.Job.SetID(value)
+}
@@ -23,6 +23,21 @@ var promETHBalance = promauto.NewGaugeVec(
 	[]string{"account"},
 )
 
+var promVRFProofQueueDepth = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "vrf_proof_queue_depth",
+		Help: "The number of VRF proofs waiting for a worker to generate them",
+	},
+)
+
+var promVRFProofDuration = promauto.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "vrf_proof_duration_seconds",
+		Help:    "How long VRF proofs take to generate, once a worker is free",
+		Buckets: prometheus.DefBuckets,
+	},
+)
+
 func promUpdateEthBalance(balance *assets.Eth, from common.Address) {
 	balanceFloat, err := approximateFloat64(balance)
 
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// Signer signs for an ethereum account whose private key is held outside the
+// node's keystore, such as in a cloud KMS or an HSM.
+type Signer interface {
+	// Address is the address of the account the signer signs for.
+	Address() common.Address
+	// SignHash returns the signature of hash in the [R || S || V] format, where
+	// V is 0 or 1, as crypto.Sign does.
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// SignerBackend returns the signer for the key with the given reference. The
+// reference is everything after the backend's scheme in the key reference,
+// e.g. the key ARN in "awskms://arn:aws:kms:...".
+type SignerBackend func(ref string) (Signer, error)
+
+var (
+	signerBackends   = map[string]SignerBackend{}
+	signerBackendsMu sync.RWMutex
+)
+
+// RegisterSignerBackend makes the backend available for key references with
+// the given scheme, e.g. "awskms", "gcpkms" or "pkcs11". Backends register
+// themselves from the init function of their package.
+func RegisterSignerBackend(scheme string, backend SignerBackend) {
+	signerBackendsMu.Lock()
+	defer signerBackendsMu.Unlock()
+	if _, exists := signerBackends[scheme]; exists {
+		panic(fmt.Sprintf("signer backend %s registered twice", scheme))
+	}
+	signerBackends[scheme] = backend
+}
+
+// NewSigner returns the signer for the key reference keyRef, of the form
+// "<scheme>://<ref>", from the backend registered for its scheme.
+func NewSigner(keyRef string) (Signer, error) {
+	parts := strings.SplitN(keyRef, "://", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("key reference %s is not of the form <scheme>://<ref>", keyRef)
+	}
+	signerBackendsMu.RLock()
+	backend, ok := signerBackends[parts[0]]
+	signerBackendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no signer backend for scheme %s", parts[0])
+	}
+	signer, err := backend(parts[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "while getting signer for %s", keyRef)
+	}
+	return signer, nil
+}
+
+// DigestSigner is what KMSs and HSMs provide: an ECDSA signature over the
+// secp256k1 curve of a digest, in ASN.1 DER encoding, which does not say which
+// of the possible public keys it was made with.
+type DigestSigner interface {
+	PublicKey() (*ecdsa.PublicKey, error)
+	SignDigest(digest []byte) (der []byte, err error)
+}
+
+// NewDigestSigner returns a Signer which signs with ds, turning its signatures
+// into the recoverable form ethereum uses.
+func NewDigestSigner(ds DigestSigner) (Signer, error) {
+	publicKey, err := ds.PublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "while getting public key")
+	}
+	return &digestSigner{ds: ds, address: crypto.PubkeyToAddress(*publicKey)}, nil
+}
+
+type digestSigner struct {
+	ds      DigestSigner
+	address common.Address
+}
+
+func (s *digestSigner) Address() common.Address {
+	return s.address
+}
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+func (s *digestSigner) SignHash(hash []byte) ([]byte, error) {
+	der, err := s.ds.SignDigest(hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while signing with key %s", s.address.Hex())
+	}
+	var sig struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, errors.Wrap(err, "while parsing signature")
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after signature")
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 ||
+		sig.R.Cmp(secp256k1N) >= 0 || sig.S.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("signature out of range")
+	}
+	// Ethereum only accepts signatures in the lower half of the curve order,
+	// and either one is a valid ECDSA signature.
+	if sig.S.Cmp(secp256k1HalfN) > 0 {
+		sig.S = new(big.Int).Sub(secp256k1N, sig.S)
+	}
+
+	signature := make([]byte, 65)
+	copy(signature[32-len(sig.R.Bytes()):32], sig.R.Bytes())
+	copy(signature[64-len(sig.S.Bytes()):64], sig.S.Bytes())
+	for v := byte(0); v < 2; v++ {
+		signature[64] = v
+		publicKey, err := crypto.SigToPub(hash, signature)
+		if err == nil && crypto.PubkeyToAddress(*publicKey) == s.address {
+			return signature, nil
+		}
+	}
+	return nil, fmt.Errorf("signature does not match key %s", s.address.Hex())
+}
@@ -0,0 +1,119 @@
+package store_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/asn1"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKMS signs like a KMS does, returning DER-encoded signatures with no
+// recovery ID, and in the upper half of the curve order if highS is set.
+type fakeKMS struct {
+	key   *ecdsa.PrivateKey
+	highS bool
+}
+
+func (k *fakeKMS) PublicKey() (*ecdsa.PublicKey, error) {
+	return &k.key.PublicKey, nil
+}
+
+func (k *fakeKMS) SignDigest(digest []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, k.key, digest)
+	if err != nil {
+		return nil, err
+	}
+	n := crypto.S256().Params().N
+	if k.highS == (s.Cmp(new(big.Int).Rsh(n, 1)) <= 0) {
+		s.Sub(n, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func TestDigestSigner_SignHash(t *testing.T) {
+	t.Parallel()
+
+	for _, highS := range []bool{false, true} {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signer, err := store.NewDigestSigner(&fakeKMS{key: key, highS: highS})
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+
+		for i := 0; i < 10; i++ {
+			hash := crypto.Keccak256([]byte{byte(i)})
+			signature, err := signer.SignHash(hash)
+			require.NoError(t, err)
+			require.Len(t, signature, 65)
+
+			publicKey, err := crypto.SigToPub(hash, signature)
+			require.NoError(t, err)
+			assert.Equal(t, signer.Address(), crypto.PubkeyToAddress(*publicKey))
+			assert.True(t, crypto.ValidateSignatureValues(signature[64], new(big.Int).SetBytes(signature[:32]),
+				new(big.Int).SetBytes(signature[32:64]), true), "signature must have a low S")
+		}
+	}
+}
+
+var (
+	registerTestKMS sync.Once
+	testKMSKey      *ecdsa.PrivateKey
+)
+
+func TestNewSigner(t *testing.T) {
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	// Backends can only be registered once, even if the test runs again
+	registerTestKMS.Do(func() {
+		store.RegisterSignerBackend("testkms", func(ref string) (store.Signer, error) {
+			assert.Equal(t, "keys/1", ref)
+			return store.NewDigestSigner(&fakeKMS{key: testKMSKey})
+		})
+	})
+	testKMSKey = key
+
+	signer, err := store.NewSigner("testkms://keys/1")
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+
+	_, err = store.NewSigner("otherkms://keys/1")
+	assert.Error(t, err)
+	_, err = store.NewSigner("keys/1")
+	assert.Error(t, err)
+}
+
+func TestKeyStore_SignTxWithSigner(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer, err := store.NewDigestSigner(&fakeKMS{key: key})
+	require.NoError(t, err)
+
+	s, cleanup := cltest.NewStore(t)
+	defer cleanup()
+	ks := s.KeyStore
+	ks.AddSigner(signer)
+	require.Equal(t, []accounts.Account{{Address: signer.Address()}}, ks.Accounts())
+
+	chainID := big.NewInt(3)
+	tx := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(2), 21000, big.NewInt(3), nil)
+	signed, err := ks.SignTx(accounts.Account{Address: signer.Address()}, tx, chainID)
+	require.NoError(t, err)
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, signer.Address(), sender)
+}
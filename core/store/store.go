@@ -17,12 +17,14 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	"github.com/tevino/abool"
 	"go.uber.org/multierr"
 	"golang.org/x/time/rate"
+	"gopkg.in/guregu/null.v3"
 )
 
 // Store contains fields for the database, Config, KeyStore, and TxManager
@@ -33,7 +35,10 @@ type Store struct {
 	Clock       utils.AfterNower
 	KeyStore    *KeyStore
 	VRFKeyStore *VRFKeyStore
+	OCRKeyStore *OCRKeyStore
 	TxManager   TxManager
+	KafkaClient KafkaClient
+	BridgeCache BridgeCache
 	closeOnce   *sync.Once
 }
 
@@ -162,10 +167,25 @@ func newStoreWithDialerAndKeyStore(
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Unable to create project root dir: %+v", err))
 	}
+	masterKeys, err := config.MasterKeys()
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Unable to load master key: %+v", err))
+	}
+	models.SetColumnEncryptionKeys(masterKeys[0], masterKeys...)
 	orm, err := initializeORM(config, shutdownSignal)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Unable to initialize ORM: %+v", err))
 	}
+	sessionSecret, err := config.SessionSecret()
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Unable to load session secret: %+v", err))
+	}
+	if err := orm.EncryptSessionSecretColumns(sessionSecret); err != nil {
+		logger.Fatal(fmt.Sprintf("Unable to encrypt columns encrypted with the session secret: %+v", err))
+	}
+	if err := orm.EncryptPlaintextColumns(); err != nil {
+		logger.Fatal(fmt.Sprintf("Unable to encrypt sensitive columns: %+v", err))
+	}
 	ethrpc, err := dialer.Dial(config.EthereumURL())
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Unable to dial ETH RPC port: %+v", err))
@@ -174,24 +194,62 @@ func newStoreWithDialerAndKeyStore(
 		logger.Fatal(fmt.Sprintf("Unable to migrate key store to disk: %+v", err))
 	}
 
+	bridgeCache, err := NewBridgeCache(config, orm)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Unable to create bridge cache: %+v", err))
+	}
+
 	keyStore := keyStoreGenerator()
 	callerSubscriberClient := &eth.CallerSubscriberClient{CallerSubscriber: ethrpc}
 	txManager := NewEthTxManager(callerSubscriberClient, config, keyStore, orm)
+	if config.EthQuorumURL() != "" {
+		// Transactions are not broadcast without the cross-check the
+		// operator asked for, but an unreachable quorum provider must not
+		// take the node down with it.
+		if quorumrpc, err := dialer.Dial(config.EthQuorumURL()); err != nil {
+			logger.Errorw("Unable to dial ETH_QUORUM_URL, broadcasting is paused", "error", err)
+			txManager.PauseBroadcasting(fmt.Sprintf("unable to dial ETH_QUORUM_URL: %v", err))
+		} else {
+			txManager.SetQuorumClient(&eth.CallerSubscriberClient{CallerSubscriber: quorumrpc})
+		}
+	}
 	store := &Store{
-		Clock:     utils.Clock{},
-		Config:    config,
-		KeyStore:  keyStore,
-		ORM:       orm,
-		TxManager: txManager,
-		closeOnce: &sync.Once{},
+		Clock:       utils.Clock{},
+		Config:      config,
+		KeyStore:    keyStore,
+		ORM:         orm,
+		TxManager:   txManager,
+		KafkaClient: NewKafkaClient(config.KafkaBrokers()),
+		BridgeCache: bridgeCache,
+		closeOnce:   &sync.Once{},
 	}
 	store.VRFKeyStore = NewVRFKeyStore(store)
+	store.OCRKeyStore = NewOCRKeyStore(store)
 	return store
 }
 
 // Start initiates all of Store's dependencies including the TxManager.
 func (s *Store) Start() error {
+	keys, err := s.ORM.Keys()
+	if err != nil {
+		return errors.Wrap(err, "unable to load keys")
+	}
+	for _, key := range keys {
+		if !key.KeyRef.Valid {
+			continue
+		}
+		signer, err := NewSigner(key.KeyRef.String)
+		if err != nil {
+			return errors.Wrapf(err, "unable to load signer for key %s", key.Address.String())
+		}
+		s.KeyStore.AddSigner(signer)
+	}
 	s.TxManager.Register(s.KeyStore.Accounts())
+	for _, key := range keys {
+		if key.RetiredAt.Valid {
+			s.TxManager.RetireAccount(key.Address.Address())
+		}
+	}
 	return s.SyncDiskKeyStoreToDB()
 }
 
@@ -199,7 +257,10 @@ func (s *Store) Start() error {
 func (s *Store) Close() error {
 	var err error
 	s.closeOnce.Do(func() {
-		err = s.ORM.Close()
+		err = multierr.Combine(
+			s.KafkaClient.Close(),
+			s.ORM.Close(),
+		)
 	})
 	return err
 }
@@ -243,18 +304,122 @@ func (s *Store) SyncDiskKeyStoreToDB() error {
 	return merr
 }
 
+// AddExternalKey adds the key held by a KMS or HSM which signer signs with,
+// and which keyRef, of the form "<scheme>://<ref>", refers to, to the keys of
+// the node, which uses it for transactions right away.
+func (s *Store) AddExternalKey(signer Signer, keyRef string) (accounts.Account, error) {
+	address, err := models.NewEIP55Address(signer.Address().Hex())
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	if err := s.FirstOrCreateKey(&models.Key{Address: address, KeyRef: null.StringFrom(keyRef)}); err != nil {
+		return accounts.Account{}, errors.Wrap(err, "while saving key")
+	}
+	s.KeyStore.AddSigner(signer)
+	account := accounts.Account{Address: signer.Address()}
+	if err := s.TxManager.ActivateAccount(account); err != nil {
+		return accounts.Account{}, errors.Wrap(err, "while activating key")
+	}
+	return account, nil
+}
+
+// RotateMasterKey replaces the master key which sensitive database columns
+// are encrypted with by a new key, and encrypts them with it. If it fails, it
+// can be run again, and picks up the same new key.
+func (s *Store) RotateMasterKey() error {
+	keys, err := s.Config.MasterKeys()
+	if err != nil {
+		return err
+	}
+	newKey, err := s.Config.BeginMasterKeyRotation()
+	if err != nil {
+		return errors.Wrap(err, "while creating new master key")
+	}
+	models.SetColumnEncryptionKeys(newKey, append(keys, newKey)...)
+	if err := s.ReencryptColumns(); err != nil {
+		return errors.Wrap(err, "while encrypting columns with new master key")
+	}
+	return errors.Wrap(s.Config.FinishMasterKeyRotation(), "while replacing master key")
+}
+
+// MigrationStatus returns the status of the migrations of the database. It
+// does not take the advisory lock, so that it can be checked while a node is
+// running.
+func MigrationStatus(config *orm.Config) (migrations.Status, error) {
+	dialect, err := orm.DeduceDialect(config.DatabaseURL())
+	if err != nil {
+		return migrations.Status{}, err
+	}
+	db, err := gorm.Open(string(dialect), config.DatabaseURL())
+	if err != nil {
+		return migrations.Status{}, errors.Wrap(err, "unable to open database")
+	}
+	defer db.Close()
+	return migrations.GetStatus(db)
+}
+
+// RunMigrations runs the pending migrations of the database, or with dryRun,
+// runs them in a transaction which is rolled back and returns the locks they
+// took. It takes the advisory lock, so the node must be stopped.
+func RunMigrations(config *orm.Config, dryRun bool) ([]migrations.LockImpact, error) {
+	orm, err := orm.NewORM(config.DatabaseURL(), config.DatabaseTimeout(), gracefulpanic.NewSignal(), config.DatabaseOptions())
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open database")
+	}
+	defer orm.Close()
+	orm.SetLogging(config.LogSQLStatements() || config.LogSQLMigrations())
+
+	var impacts []migrations.LockImpact
+	err = orm.RawDB(func(db *gorm.DB) error {
+		if dryRun {
+			impacts, err = migrations.DryRun(db)
+			return err
+		}
+		return migrations.Migrate(db)
+	})
+	return impacts, err
+}
+
 func initializeORM(config *orm.Config, shutdownSignal gracefulpanic.Signal) (*orm.ORM, error) {
-	orm, err := orm.NewORM(config.DatabaseURL(), config.DatabaseTimeout(), shutdownSignal)
+	if err := orm.WaitForDatabase(config.DatabaseURL(), config.DatabaseConnectTimeout().Duration(), utils.Clock{}); err != nil {
+		return nil, errors.Wrap(err, "initializeORM#WaitForDatabase")
+	}
+	orm, err := orm.NewORM(config.DatabaseURL(), config.DatabaseTimeout(), shutdownSignal, config.DatabaseOptions())
 	if err != nil {
 		return nil, errors.Wrap(err, "initializeORM#NewORM")
 	}
+	orm.SetConnectTimeout(config.DatabaseConnectTimeout().Duration())
 	orm.SetLogging(config.LogSQLStatements() || config.LogSQLMigrations())
 	err = orm.RawDB(func(db *gorm.DB) error {
-		return migrations.Migrate(db)
+		if config.DatabaseAutoMigrate() {
+			return migrations.Migrate(db)
+		}
+		status, err := migrations.GetStatus(db)
+		if err != nil {
+			return err
+		}
+		if len(status.Pending) > 0 {
+			return fmt.Errorf("database has %d pending migrations, run `chainlink node migrate` or set DATABASE_AUTO_MIGRATE=true", len(status.Pending))
+		}
+		return nil
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "initializeORM#Migrate")
 	}
 	orm.SetLogging(config.LogSQLStatements())
+	orm.SetSlowQueryThreshold(config.DatabaseSlowQueryThreshold().Duration())
+	orm.SetRunResultLimit(config.RunResultMaxSize(), config.RunResultOversizePolicy())
+	orm.SetFinalityDepth(config.FinalityDepth())
+	if err := orm.SetLookupCache(config.LookupCacheSize(), config.LookupCacheTTL().Duration()); err != nil {
+		return nil, err
+	}
+
+	created, err := orm.EnsureIndexes()
+	for _, index := range created {
+		logger.ORM.Warnw("Required index was missing, created it", "index", index.Name, "table", index.Table, "columns", index.Columns)
+	}
+	if err != nil {
+		logger.ORM.Errorw("Unable to ensure the required indexes exist, queries may be slow", "error", err)
+	}
 	return orm, nil
 }
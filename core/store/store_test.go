@@ -7,10 +7,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/smartcontractkit/chainlink/core/auth"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/internal/mocks"
+	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
+	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -153,3 +156,49 @@ func TestStore_SyncDiskKeyStoreToDB_DBKeyAlreadyExists(t *testing.T) {
 	require.Len(t, keys, 1)
 	require.Equal(t, acc.Address.Hex(), keys[0].Address.String())
 }
+
+func TestStore_RotateMasterKey(t *testing.T) {
+	// Not parallel, as the master key is shared by every store
+	defer models.SetColumnEncryptionKeys([]byte(cltest.SessionSecret), []byte(cltest.SessionSecret))
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t)
+	require.NoError(t, store.CreateBridgeType(bt))
+	outgoingToken := func() string {
+		var token string
+		require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+			return db.Table("bridge_types").Where("name = ?", bt.Name).Select("outgoing_token").Row().Scan(&token)
+		}))
+		return token
+	}
+	before := outgoingToken()
+
+	ei, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: "rotated", RequireSignature: true})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateExternalInitiator(ei))
+	signingSecret := func() string {
+		var secret string
+		require.NoError(t, store.ORM.RawDB(func(db *gorm.DB) error {
+			return db.Table("external_initiators").Where("name = ?", ei.Name).Select("signing_secret").Row().Scan(&secret)
+		}))
+		return secret
+	}
+	beforeSigningSecret := signingSecret()
+
+	require.NoError(t, store.RotateMasterKey())
+
+	assert.NotEqual(t, beforeSigningSecret, signingSecret())
+	foundEI, err := store.FindExternalInitiatorByName(ei.Name)
+	require.NoError(t, err)
+	assert.Equal(t, ei.SigningSecret, foundEI.SigningSecret)
+	assert.Equal(t, ei.OutgoingSecret, foundEI.OutgoingSecret)
+
+	assert.NotEqual(t, before, outgoingToken())
+	found, err := store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, bt.OutgoingToken, found.OutgoingToken)
+	assert.True(t, utils.FileExists(filepath.Join(store.Config.RootDir(), "master_key")))
+	assert.False(t, utils.FileExists(filepath.Join(store.Config.RootDir(), "master_key.new")))
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -33,17 +34,30 @@ const (
 
 	// The base time for the backoff
 	nonceReloadBackoffBaseTime = 3 * time.Second
+
+	// The gas limit of the transactions cancelling others, which send no
+	// ether to their sender
+	cancelGasLimit = 21000
 )
 
 var (
 	// ErrPendingConnection is the error returned if TxManager is not connected.
 	ErrPendingConnection = errors.New("Cannot talk to chain, pending connection")
 
+	// ErrBroadcastingPaused is the error returned when transactions are not
+	// sent, as the providers of the chain disagree on it.
+	ErrBroadcastingPaused = errors.New("Broadcasting of transactions is paused")
+
 	promNumGasBumps = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "tx_manager_num_gas_bumps",
 		Help: "Number of gas bumps",
 	})
 
+	promGasBumpsSent = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tx_manager_gas_bumps_sent",
+		Help: "Number of tx attempts sent with a bumped gas price",
+	})
+
 	promGasBumpExceedsLimit = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "tx_manager_gas_bump_exceeds_limit",
 		Help: "Number of times gas bumping failed from exceeding the configured limit. Any counts of this type indicate a serious problem.",
@@ -54,6 +68,11 @@ var (
 		Help: "Number of underpriced replacement errors received while trying to bump gas. Counts of this type most likely indicate some kind of misconfiguration or problem.",
 	})
 
+	promTxAttemptsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "tx_manager_tx_attempts_created",
+		Help: "Number of tx attempts sent, including those bumping the gas of earlier attempts",
+	})
+
 	promTxAttemptFailed = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "tx_manager_tx_attempt_failed",
 		Help: "Number of tx attempts that failed. Tx attempts should not fail in normal operation.",
@@ -67,13 +86,20 @@ type TxManager interface {
 	HeadTrackable
 	Connected() bool
 	Register(accounts []accounts.Account)
+	ActivateAccount(account accounts.Account) error
+	RetireAccount(address common.Address)
 
 	CreateTx(to common.Address, data []byte) (*models.Tx, error)
 	CreateTxWithGas(surrogateID null.String, to common.Address, data []byte, gasPriceWei *big.Int, gasLimit uint64) (*models.Tx, error)
 	CreateTxWithEth(from, to common.Address, value *assets.Eth) (*models.Tx, error)
+	CreateTxFrom(from, to common.Address, data []byte) (*models.Tx, error)
+	SimulateTx(to common.Address, data []byte, gasLimit uint64) error
 	CheckAttempt(txAttempt *models.TxAttempt, blockHeight uint64) (*eth.TxReceipt, AttemptState, error)
 
 	BumpGasUntilSafe(hash common.Hash) (*eth.TxReceipt, AttemptState, error)
+	BumpGas(hash common.Hash) (*models.TxAttempt, error)
+	CancelTx(hash common.Hash) (*models.TxAttempt, error)
+	ResendTxs(from *common.Address, beginningNonce, endingNonce uint64) ([]models.Tx, error)
 
 	ContractLINKBalance(wr models.WithdrawalRequest) (assets.Link, error)
 	WithdrawLINK(wr models.WithdrawalRequest) (common.Hash, error)
@@ -82,6 +108,11 @@ type TxManager interface {
 
 	SignedRawTxWithBumpedGas(originalTx models.Tx, gasLimit uint64, gasPrice big.Int) ([]byte, error)
 
+	QuorumClient() eth.Client
+	PauseBroadcasting(reason string)
+	ResumeBroadcasting()
+	BroadcastingPaused() (string, bool)
+
 	eth.Client
 }
 
@@ -95,22 +126,71 @@ type EthTxManager struct {
 	registeredAccounts  []accounts.Account
 	availableAccounts   []*ManagedAccount
 	availableAccountIdx int
+	retiredAccounts     map[common.Address]bool
 	accountsMutex       *sync.Mutex
 	connected           *abool.AtomicBool
 	currentHead         models.Head
+	quorum              eth.Client
+	pauseMutex          sync.RWMutex
+	pausedReason        string
 }
 
 // NewEthTxManager constructs an EthTxManager using the passed variables and
 // initializing internal variables.
 func NewEthTxManager(client eth.Client, config orm.ConfigReader, keyStore *KeyStore, orm *orm.ORM) *EthTxManager {
 	return &EthTxManager{
-		Client:        client,
-		config:        config,
-		keyStore:      keyStore,
-		orm:           orm,
-		accountsMutex: &sync.Mutex{},
-		connected:     abool.New(),
+		Client:          client,
+		config:          config,
+		keyStore:        keyStore,
+		orm:             orm,
+		accountsMutex:   &sync.Mutex{},
+		connected:       abool.New(),
+		retiredAccounts: make(map[common.Address]bool),
+	}
+}
+
+// SetQuorumClient sets the client of the second provider the chain is
+// cross-checked with.
+func (txm *EthTxManager) SetQuorumClient(client eth.Client) {
+	txm.quorum = client
+}
+
+// QuorumClient returns the client of the second provider the chain is
+// cross-checked with, or nil if it is not.
+func (txm *EthTxManager) QuorumClient() eth.Client {
+	return txm.quorum
+}
+
+// PauseBroadcasting stops transactions from being sent, for the reason
+// given, until ResumeBroadcasting is called.
+func (txm *EthTxManager) PauseBroadcasting(reason string) {
+	txm.pauseMutex.Lock()
+	defer txm.pauseMutex.Unlock()
+	txm.pausedReason = reason
+}
+
+// ResumeBroadcasting lets transactions be sent again.
+func (txm *EthTxManager) ResumeBroadcasting() {
+	txm.pauseMutex.Lock()
+	defer txm.pauseMutex.Unlock()
+	txm.pausedReason = ""
+}
+
+// BroadcastingPaused returns the reason transactions are not sent, and
+// whether they are not.
+func (txm *EthTxManager) BroadcastingPaused() (string, bool) {
+	txm.pauseMutex.RLock()
+	defer txm.pauseMutex.RUnlock()
+	return txm.pausedReason, txm.pausedReason != ""
+}
+
+// SendRawTx sends a signed transaction to the chain, unless broadcasting is
+// paused.
+func (txm *EthTxManager) SendRawTx(bytes []byte) (common.Hash, error) {
+	if reason, paused := txm.BroadcastingPaused(); paused {
+		return common.Hash{}, errors.Wrap(ErrBroadcastingPaused, reason)
 	}
+	return txm.Client.SendRawTx(bytes)
 }
 
 // Register activates accounts for outgoing transactions and client side
@@ -124,6 +204,40 @@ func (txm *EthTxManager) Register(accts []accounts.Account) {
 	txm.registeredAccounts = cp
 }
 
+// ActivateAccount registers an account created while the node is running, and
+// retrieves its nonce so that it can be used for outgoing transactions right
+// away.
+func (txm *EthTxManager) ActivateAccount(account accounts.Account) error {
+	txm.accountsMutex.Lock()
+	defer txm.accountsMutex.Unlock()
+
+	for _, a := range txm.registeredAccounts {
+		if a.Address == account.Address {
+			return nil
+		}
+	}
+	txm.registeredAccounts = append(txm.registeredAccounts, account)
+	if !txm.Connected() {
+		return nil // Activated on connection, like the other accounts
+	}
+
+	ma, err := txm.activateAccount(account)
+	if err != nil {
+		return errors.Wrapf(err, "TxManager#ActivateAccount for %s", account.Address.Hex())
+	}
+	txm.availableAccounts = append(txm.availableAccounts, ma)
+	return nil
+}
+
+// RetireAccount stops the account with the given address from being used for
+// new transactions. Those it has already sent are still bumped and confirmed.
+func (txm *EthTxManager) RetireAccount(address common.Address) {
+	txm.accountsMutex.Lock()
+	defer txm.accountsMutex.Unlock()
+
+	txm.retiredAccounts[address] = true
+}
+
 // Connected returns a bool indicating whether or not it is connected.
 func (txm *EthTxManager) Connected() bool {
 	return txm.connected.IsSet()
@@ -164,18 +278,18 @@ func (txm *EthTxManager) Connect(bn *models.Head) error {
 	for _, attempt := range attempts {
 		ma := txm.getAccount(attempt.Tx.From)
 		if ma == nil {
-			logger.Warnf("Trying to rebroadcast tx %v, could not find account %v", attempt.Hash.Hex(), attempt.Tx.From.Hex())
+			logger.TxManager.Warnf("Trying to rebroadcast tx %v, could not find account %v", attempt.Hash.Hex(), attempt.Tx.From.Hex())
 			continue
 		} else if ma.Nonce() > attempt.Tx.Nonce {
 			// Do not rebroadcast txs with nonces that are lower than our current nonce
 			continue
 		}
 
-		logger.Infof("Rebroadcasting tx %v", attempt.Hash.Hex())
+		logger.TxManager.Infof("Rebroadcasting tx %v", attempt.Hash.Hex())
 
 		_, err = txm.SendRawTx(attempt.SignedRawTx)
 		if err != nil && !isNonceTooLowError(err) {
-			logger.Warnf("Failed to rebroadcast tx %v: %v", attempt.Hash.Hex(), err)
+			logger.TxManager.Warnf("Failed to rebroadcast tx %v: %v", attempt.Hash.Hex(), err)
 		}
 	}
 
@@ -198,8 +312,12 @@ func (txm *EthTxManager) CreateTx(to common.Address, data []byte) (*models.Tx, e
 }
 
 // CreateTxWithGas signs and sends a transaction to the Ethereum blockchain.
+//
+// The transactions of a job run are sent from the keys in the namespace of its
+// job, or from those in the default namespace if it has none; other
+// transactions from the keys in the default namespace.
 func (txm *EthTxManager) CreateTxWithGas(surrogateID null.String, to common.Address, data []byte, gasPriceWei *big.Int, gasLimit uint64) (*models.Tx, error) {
-	ma, err := txm.nextAccount()
+	ma, err := txm.nextAccount(surrogateID)
 	if err != nil {
 		return nil, err
 	}
@@ -218,12 +336,79 @@ func (txm *EthTxManager) CreateTxWithEth(from, to common.Address, value *assets.
 	return txm.createTx(null.String{}, ma, to, []byte{}, txm.config.EthGasPriceDefault(), txm.config.EthGasLimitDefault(), value)
 }
 
-func (txm *EthTxManager) nextAccount() (*ManagedAccount, error) {
+// CreateTxFrom signs and sends a transaction from the given account, even if
+// it has been retired.
+func (txm *EthTxManager) CreateTxFrom(from, to common.Address, data []byte) (*models.Tx, error) {
+	ma := txm.getAccount(from)
+	if ma == nil {
+		return nil, errors.New("account does not exist")
+	}
+
+	return txm.createTx(null.String{}, ma, to, data, txm.config.EthGasPriceDefault(), txm.config.EthGasLimitDefault(), nil)
+}
+
+// simulateArgs are the arguments of the eth_call simulating a transaction.
+type simulateArgs struct {
+	From common.Address `json:"from"`
+	To   common.Address `json:"to"`
+	Gas  hexutil.Uint64 `json:"gas"`
+	Data hexutil.Bytes  `json:"data"`
+}
+
+// SimulateTx calls the transaction CreateTxWithGas would send against the
+// pending block, from the account which would send it, returning an
+// *eth.RevertError with the reason of the revert if it would revert on
+// chain. Other errors are those of the node.
+func (txm *EthTxManager) SimulateTx(to common.Address, data []byte, gasLimit uint64) error {
+	if !txm.Connected() {
+		return errors.Wrap(ErrPendingConnection, "EthTxManager#SimulateTx")
+	}
+	ma := txm.peekActiveAccount()
+	if ma == nil {
+		return errors.New("Must connect and activate an account before simulating a transaction")
+	}
+
+	_, gasLimit = normalizeGasParams(nil, gasLimit, txm.config)
+	args := simulateArgs{From: ma.Address, To: to, Gas: hexutil.Uint64(gasLimit), Data: data}
+	var result hexutil.Bytes
+	err := txm.Call(&result, "eth_call", args, "pending")
+	if revert, ok := eth.AsRevertError(err); ok {
+		return revert
+	} else if err != nil {
+		return errors.Wrap(err, "EthTxManager#SimulateTx")
+	}
+	if reason, ok := eth.RevertReason(result); ok {
+		return &eth.RevertError{Reason: reason}
+	}
+	return nil
+}
+
+func (txm *EthTxManager) nextAccount(surrogateID null.String) (*ManagedAccount, error) {
 	if !txm.Connected() {
 		return nil, errors.Wrap(ErrPendingConnection, "EthTxManager#nextAccount")
 	}
 
-	ma := txm.NextActiveAccount()
+	namespace, err := txm.sendingNamespace(surrogateID)
+	if err != nil {
+		return nil, errors.Wrap(err, "EthTxManager#nextAccount")
+	}
+	keyNamespaces, err := txm.orm.NamespacedKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "EthTxManager#nextAccount")
+	}
+
+	// The funding key only sends the ETH of key fundings, so that jobs never
+	// spend what the other keys are to be funded with
+	funder := txm.config.KeyFundingAddress()
+	inNamespace := func(ns string) func(*ManagedAccount) bool {
+		return func(a *ManagedAccount) bool {
+			return keyNamespaces[a.Address] == ns && (funder == nil || a.Address != *funder)
+		}
+	}
+	ma := txm.nextActiveAccountWhere(inNamespace(namespace))
+	if ma == nil && namespace != "" {
+		ma = txm.nextActiveAccountWhere(inNamespace(""))
+	}
 	if ma == nil {
 		return nil, errors.New("Must connect and activate an account before creating a transaction")
 	}
@@ -231,6 +416,24 @@ func (txm *EthTxManager) nextAccount() (*ManagedAccount, error) {
 	return ma, nil
 }
 
+// sendingNamespace returns the namespace whose keys send the transactions of
+// the job run with the given ID, which is the default one for transactions
+// of no job run.
+func (txm *EthTxManager) sendingNamespace(surrogateID null.String) (string, error) {
+	if !surrogateID.Valid {
+		return "", nil
+	}
+	runID, err := models.NewIDFromString(surrogateID.String)
+	if err != nil {
+		return "", nil
+	}
+	namespace, err := txm.orm.JobRunNamespace(runID)
+	if err == orm.ErrorNotFound {
+		return "", nil
+	}
+	return namespace, err
+}
+
 func normalizeGasParams(gasPriceWei *big.Int, gasLimit uint64, config orm.ConfigReader) (*big.Int, uint64) {
 	if !config.Dev() {
 		return config.EthGasPriceDefault(), config.EthGasLimitDefault()
@@ -258,6 +461,10 @@ func (txm *EthTxManager) createTx(
 	gasLimit uint64,
 	value *assets.Eth) (*models.Tx, error) {
 
+	if reason, paused := txm.BroadcastingPaused(); paused {
+		return nil, errors.Wrap(ErrBroadcastingPaused, reason)
+	}
+
 	for nrc := 0; nrc < nonceReloadLimit+1; nrc++ {
 		tx, err := txm.sendInitialTx(surrogateID, ma, to, data, gasPriceWei, gasLimit, value)
 		if err == nil {
@@ -268,7 +475,7 @@ func (txm *EthTxManager) createTx(
 			return nil, errors.Wrap(err, "TxManager#retryInitialTx sendInitialTx")
 		}
 
-		logger.Warnw(
+		logger.TxManager.Warnw(
 			"Tx #0: another tx with this nonce already exists, will retry with network nonce",
 			"nonce", tx.Nonce, "gasPriceWei", gasPriceWei, "gasLimit", gasLimit, "error", err.Error(),
 		)
@@ -276,7 +483,7 @@ func (txm *EthTxManager) createTx(
 		// Linear backoff
 		time.Sleep(time.Duration(nrc+1) * nonceReloadBackoffBaseTime)
 
-		logger.Warnw(
+		logger.TxManager.Warnw(
 			"Tx #0: another tx with this nonce already exists, retrying with network nonce",
 			"nonce", tx.Nonce, "gasPriceWei", gasPriceWei, "gasLimit", gasLimit, "error", err.Error(),
 		)
@@ -340,7 +547,8 @@ func (txm *EthTxManager) sendInitialTx(
 			return errors.Wrap(e, "TxManager#sendInitialTx AddTxAttempt")
 		}
 
-		logger.Debugw("Added Tx attempt #0", "txID", tx.ID, "txAttemptID", txAttempt.ID)
+		promTxAttemptsCreated.Inc()
+		logger.TxManager.Debugw("Added Tx attempt #0", "tx_id", tx.ID, "tx_attempt_id", txAttempt.ID)
 
 		return nil
 	})
@@ -554,7 +762,7 @@ func (txm *EthTxManager) CheckAttempt(txAttempt *models.TxAttempt, blockHeight u
 		return receipt, Unconfirmed, nil
 	}
 
-	minimumConfirmations := new(big.Int).SetUint64(txm.config.MinOutgoingConfirmations())
+	minimumConfirmations := new(big.Int).SetUint64(txm.minimumConfirmations())
 	confirmedAt := new(big.Int).Add(minimumConfirmations, receipt.BlockNumber.ToInt())
 
 	confirmedAt.Sub(confirmedAt, big.NewInt(1)) // confirmed at block counts as 1 conf
@@ -566,6 +774,13 @@ func (txm *EthTxManager) CheckAttempt(txAttempt *models.TxAttempt, blockHeight u
 	return receipt, Safe, nil
 }
 
+// minimumConfirmations returns the confirmations a transaction needs to be
+// safe, MIN_OUTGOING_CONFIRMATIONS, which config validation keeps within
+// FINALITY_DEPTH.
+func (txm *EthTxManager) minimumConfirmations() uint64 {
+	return txm.config.MinOutgoingConfirmations()
+}
+
 // AttemptState enumerates the possible states of a transaction attempt as it
 // gets accepted and confirmed by the blockchain
 type AttemptState int
@@ -614,13 +829,13 @@ func (txm *EthTxManager) processAttempt(
 	switch state {
 	case Safe:
 		txm.updateLastSafeNonce(tx)
-		return receipt, state, txm.handleSafe(tx, attemptIndex)
+		return receipt, state, txm.handleSafe(tx, attemptIndex, receipt)
 
 	case Confirmed:
-		logger.Debugw(
+		logger.TxManager.Debugw(
 			fmt.Sprintf("Tx #%d is %s", attemptIndex, state),
-			"txHash", txAttempt.Hash.String(),
-			"txID", txAttempt.TxID,
+			"tx_hash", txAttempt.Hash.String(),
+			"tx_id", txAttempt.TxID,
 			"receiptBlockNumber", receipt.BlockNumber.ToInt(),
 			"currentBlockNumber", blockHeight,
 			"receiptHash", receipt.Hash.Hex(),
@@ -640,30 +855,30 @@ func (txm *EthTxManager) processAttempt(
 	case Unconfirmed:
 		attemptLimit := txm.config.TxAttemptLimit()
 		if attemptIndex >= int(attemptLimit) {
-			logger.Warnw(
+			logger.TxManager.Warnw(
 				fmt.Sprintf("Tx #%d is %s, has met TxAttemptLimit", attemptIndex, state),
 				"txAttemptLimit", attemptLimit,
-				"txHash", txAttempt.Hash.String(),
-				"txID", txAttempt.TxID,
+				"tx_hash", txAttempt.Hash.String(),
+				"tx_id", txAttempt.TxID,
 				"jobRunId", jobRunID,
 			)
 			return receipt, state, nil
 		}
 
 		if isLatestAttempt(tx, attemptIndex) && txm.hasTxAttemptMetGasBumpThreshold(tx, attemptIndex, blockHeight) {
-			logger.Debugw(
+			logger.TxManager.Debugw(
 				fmt.Sprintf("Tx #%d is %s, bumping gas", attemptIndex, state),
-				"txHash", txAttempt.Hash.String(),
-				"txID", txAttempt.TxID,
+				"tx_hash", txAttempt.Hash.String(),
+				"tx_id", txAttempt.TxID,
 				"currentBlockNumber", blockHeight,
 				"jobRunId", jobRunID,
 			)
-			err = txm.bumpGas(tx, attemptIndex, blockHeight)
+			_, err = txm.bumpGas(tx, attemptIndex, blockHeight)
 		} else {
-			logger.Debugw(
+			logger.TxManager.Debugw(
 				fmt.Sprintf("Tx #%d is %s", attemptIndex, state),
-				"txHash", txAttempt.Hash.String(),
-				"txID", txAttempt.TxID,
+				"tx_hash", txAttempt.Hash.String(),
+				"tx_id", txAttempt.TxID,
 				"jobRunId", jobRunID,
 			)
 		}
@@ -671,10 +886,10 @@ func (txm *EthTxManager) processAttempt(
 		return receipt, state, err
 
 	default:
-		logger.Debugw(
+		logger.TxManager.Debugw(
 			fmt.Sprintf("Tx #%d is %s, error fetching receipt", attemptIndex, state),
-			"txHash", txAttempt.Hash.String(),
-			"txID", txAttempt.TxID,
+			"tx_hash", txAttempt.Hash.String(),
+			"tx_id", txAttempt.TxID,
 			"jobRunId", jobRunID,
 			"error", err,
 		)
@@ -711,28 +926,31 @@ func isLatestAttempt(tx *models.Tx, attemptIndex int) bool {
 	return attemptIndex+1 == len(tx.Attempts)
 }
 
-// handleSafe marks a transaction as safe, no more work needs to be done
+// handleSafe marks a transaction as safe, no more work needs to be done,
+// recording the gas used by the attempt from its receipt.
 func (txm *EthTxManager) handleSafe(
 	tx *models.Tx,
-	attemptIndex int) error {
+	attemptIndex int,
+	receipt *eth.TxReceipt) error {
 	txAttempt := tx.Attempts[attemptIndex]
+	txAttempt.RecordGasUsed(receipt)
 
 	if err := txm.orm.MarkTxSafe(tx, txAttempt); err != nil {
 		return errors.Wrap(err, "handleSafe MarkTxSafe failed")
 	}
 
 	var balanceErr error
-	minimumConfirmations := txm.config.MinOutgoingConfirmations()
+	minimumConfirmations := txm.minimumConfirmations()
 	ethBalance, err := txm.GetEthBalance(tx.From)
 	balanceErr = multierr.Append(balanceErr, err)
 	linkBalance, err := txm.GetLINKBalance(tx.From)
 	balanceErr = multierr.Append(balanceErr, err)
 
-	logger.Infow(
+	logger.TxManager.Infow(
 		fmt.Sprintf("Tx #%d is safe", attemptIndex),
 		"minimumConfirmations", minimumConfirmations,
-		"txHash", txAttempt.Hash.String(),
-		"txID", txAttempt.TxID,
+		"tx_hash", txAttempt.Hash.String(),
+		"tx_id", txAttempt.TxID,
 		"ethBalance", ethBalance,
 		"linkBalance", linkBalance,
 		"err", balanceErr,
@@ -764,7 +982,7 @@ func (txm *EthTxManager) BumpGasByIncrement(originalGasPrice *big.Int) *big.Int
 }
 
 // bumpGas attempts a new transaction with an increased gas cost
-func (txm *EthTxManager) bumpGas(tx *models.Tx, attemptIndex int, blockHeight uint64) error {
+func (txm *EthTxManager) bumpGas(tx *models.Tx, attemptIndex int, blockHeight uint64) (*models.TxAttempt, error) {
 	txAttempt := tx.Attempts[attemptIndex]
 
 	originalGasPrice := txAttempt.GasPrice.ToInt()
@@ -779,15 +997,15 @@ func (txm *EthTxManager) bumpGas(tx *models.Tx, attemptIndex int, blockHeight ui
 			// until CHAINLINK_TX_ATTEMPT_LIMIT is reached
 			promGasBumpExceedsLimit.Inc()
 			err := fmt.Errorf("bumped gas price of %v would exceed maximum configured limit of %v, set by ETH_MAX_GAS_PRICE_WEI", bumpedGasPrice, txm.config.EthMaxGasPriceWei())
-			logger.Error(err)
-			return err
+			logger.TxManager.Error(err)
+			return nil, err
 		}
 		bumpedTxAttempt, err := txm.createAttempt(tx, bumpedGasPrice, blockHeight)
 		if isUnderPricedReplacementError(err) {
 			// This is not expected if we have bumped at least geth's required
 			// amount.
 			promGasBumpUnderpricedReplacement.Inc()
-			logger.Warnw(fmt.Sprintf("Gas bump was rejected by ethereum node as underpriced, bumping again. Your value of ETH_GAS_BUMP_PERCENT (%v) may be set too low", txm.config.EthGasBumpPercent()),
+			logger.TxManager.Warnw(fmt.Sprintf("Gas bump was rejected by ethereum node as underpriced, bumping again. Your value of ETH_GAS_BUMP_PERCENT (%v) may be set too low", txm.config.EthGasBumpPercent()),
 				"originalGasPrice", originalGasPrice, "bumpedGasPrice", bumpedGasPrice,
 			)
 			bumpedGasPrice = txm.BumpGasByIncrement(bumpedGasPrice)
@@ -796,19 +1014,133 @@ func (txm *EthTxManager) bumpGas(tx *models.Tx, attemptIndex int, blockHeight ui
 		if err != nil {
 			promTxAttemptFailed.Inc()
 			e := errors.Wrapf(err, "bumpGas from Tx #%s", txAttempt.Hash.Hex())
-			logger.Error(e)
-			return e
+			logger.TxManager.Error(e)
+			return nil, e
 		}
 
-		logger.Infow(
+		promGasBumpsSent.Inc()
+		logger.TxManager.Infow(
 			fmt.Sprintf("Tx #%d created with bumped gas %v", attemptIndex+1, bumpedGasPrice),
 			"originalTxHash", txAttempt.Hash,
 			"newTxHash", bumpedTxAttempt.Hash)
 
-		return nil
+		return bumpedTxAttempt, nil
 	}
 }
 
+// BumpGas makes a new attempt at the unconfirmed transaction of the attempt
+// with the hash, at a gas price bumped from its latest attempt's, without
+// waiting for the gas bump threshold to be met.
+func (txm *EthTxManager) BumpGas(hash common.Hash) (*models.TxAttempt, error) {
+	tx, err := txm.unconfirmedTxByAttempt(hash)
+	if err != nil {
+		return nil, err
+	}
+	return txm.bumpGas(tx, len(tx.Attempts)-1, uint64(txm.currentHead.Number))
+}
+
+// CancelTx replaces the unconfirmed transaction of the attempt with the hash
+// by a new attempt sending no ether to its sender, with the same nonce and
+// a bumped gas price, so that the nonce is used up without the transaction
+// taking effect. The transaction is saved as the self-send, which later gas
+// bumps resend.
+func (txm *EthTxManager) CancelTx(hash common.Hash) (*models.TxAttempt, error) {
+	tx, err := txm.unconfirmedTxByAttempt(hash)
+	if err != nil {
+		return nil, err
+	}
+	ma := txm.getAccount(tx.From)
+	if ma == nil {
+		return nil, fmt.Errorf("Unable to locate %v as an available account in EthTxManager. Has TxManager been started or has the address been removed?", tx.From.Hex())
+	}
+
+	latest := tx.Attempts[len(tx.Attempts)-1]
+	gasPrice := txm.BumpGasByIncrement(latest.GasPrice.ToInt())
+	if gasPrice.Cmp(txm.config.EthMaxGasPriceWei()) > 0 {
+		return nil, fmt.Errorf("bumped gas price of %v would exceed maximum configured limit of %v, set by ETH_MAX_GAS_PRICE_WEI", gasPrice, txm.config.EthMaxGasPriceWei())
+	}
+	cancel, err := txm.newTx(
+		ma.Account,
+		tx.Nonce,
+		tx.From,
+		big.NewInt(0),
+		cancelGasLimit,
+		gasPrice,
+		nil,
+		&ma.Address,
+		uint64(txm.currentHead.Number),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "CancelTx#newTx failed")
+	}
+	if _, err = txm.SendRawTx(cancel.SignedRawTx); err != nil {
+		return nil, errors.Wrap(err, "CancelTx#SendRawTx failed")
+	}
+	// The transaction becomes the self-send, so that the attempts bumping its
+	// gas price from now on cancel it too, rather than sending it again.
+	tx.To = ma.Address
+	tx.Value = utils.NewBig(big.NewInt(0))
+	tx.GasLimit = cancelGasLimit
+	tx.Data = []byte{}
+	txAttempt, err := txm.orm.AddTxAttempt(tx, cancel)
+	if err != nil {
+		return nil, errors.Wrap(err, "CancelTx#AddTxAttempt failed")
+	}
+
+	logger.TxManager.Infow(
+		fmt.Sprintf("Tx #%d created cancelling the transaction", len(tx.Attempts)-1),
+		"originalTxHash", latest.Hash,
+		"newTxHash", txAttempt.Hash,
+		"gasPrice", gasPrice)
+	return txAttempt, nil
+}
+
+// ResendTxs sends the latest attempts of the unconfirmed transactions with
+// nonces from beginningNonce to endingNonce again, those from the address
+// only if it is given, for when they were dropped by the ethereum node. The
+// transactions resent are returned, along with the errors sending the
+// others, leaving out those of transactions already mined or replaced.
+func (txm *EthTxManager) ResendTxs(from *common.Address, beginningNonce, endingNonce uint64) ([]models.Tx, error) {
+	txs, err := txm.orm.UnconfirmedTxsInNonceRange(from, beginningNonce, endingNonce)
+	if err != nil {
+		return nil, errors.Wrap(err, "ResendTxs#UnconfirmedTxsInNonceRange failed")
+	}
+
+	var resent []models.Tx
+	var merr error
+	for _, tx := range txs {
+		if len(tx.Attempts) == 0 {
+			continue
+		}
+		latest := tx.Attempts[len(tx.Attempts)-1]
+		_, err := txm.SendRawTx(latest.SignedRawTx)
+		if isNonceTooLowError(err) {
+			continue
+		} else if err != nil {
+			merr = multierr.Append(merr, errors.Wrapf(err, "resending Tx %s with nonce %d", latest.Hash.Hex(), tx.Nonce))
+			continue
+		}
+		logger.TxManager.Infow("Resent transaction", "nonce", tx.Nonce, "tx_id", tx.ID, "tx_hash", latest.Hash)
+		resent = append(resent, tx)
+	}
+	return resent, merr
+}
+
+// unconfirmedTxByAttempt returns the transaction of the attempt with the
+// hash, with its attempts, if it is not confirmed yet.
+func (txm *EthTxManager) unconfirmedTxByAttempt(hash common.Hash) (*models.Tx, error) {
+	tx, _, err := txm.orm.FindTxByAttempt(hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Confirmed {
+		return nil, fmt.Errorf("transaction %s is already confirmed", hash.Hex())
+	} else if len(tx.Attempts) == 0 {
+		return nil, fmt.Errorf("transaction %s has no attempts", hash.Hex())
+	}
+	return tx, nil
+}
+
 // createAttempt adds a new transaction attempt to a transaction record
 func (txm *EthTxManager) createAttempt(
 	tx *models.Tx,
@@ -844,24 +1176,48 @@ func (txm *EthTxManager) createAttempt(
 		return nil, errors.Wrap(err, "createAttempt#AddTxAttempt failed")
 	}
 
-	logger.Debugw(fmt.Sprintf("Added Tx attempt #%d", len(tx.Attempts)+1), "txID", tx.ID, "txAttemptID", txAttempt.ID)
+	promTxAttemptsCreated.Inc()
+	logger.TxManager.Debugw(fmt.Sprintf("Added Tx attempt #%d", len(tx.Attempts)+1), "tx_id", tx.ID, "tx_attempt_id", txAttempt.ID)
 
 	return txAttempt, nil
 }
 
 // NextActiveAccount uses round robin to select a managed account
-// from the list of available accounts as defined in Register(...)
+// from the list of available accounts as defined in Register(...), skipping
+// retired accounts.
 func (txm *EthTxManager) NextActiveAccount() *ManagedAccount {
+	return txm.nextActiveAccountWhere(func(*ManagedAccount) bool { return true })
+}
+
+// nextActiveAccountWhere is NextActiveAccount, only selecting among the
+// accounts for which eligible is true.
+func (txm *EthTxManager) nextActiveAccountWhere(eligible func(*ManagedAccount) bool) *ManagedAccount {
 	txm.accountsMutex.Lock()
 	defer txm.accountsMutex.Unlock()
 
-	if len(txm.availableAccounts) == 0 {
-		return nil
+	for range txm.availableAccounts {
+		account := txm.availableAccounts[txm.availableAccountIdx]
+		txm.availableAccountIdx = (txm.availableAccountIdx + 1) % len(txm.availableAccounts)
+		if !txm.retiredAccounts[account.Address] && eligible(account) {
+			return account
+		}
 	}
+	return nil
+}
+
+// peekActiveAccount returns the account NextActiveAccount would select,
+// without moving on to the next.
+func (txm *EthTxManager) peekActiveAccount() *ManagedAccount {
+	txm.accountsMutex.Lock()
+	defer txm.accountsMutex.Unlock()
 
-	account := txm.availableAccounts[txm.availableAccountIdx]
-	txm.availableAccountIdx = (txm.availableAccountIdx + 1) % len(txm.availableAccounts)
-	return account
+	for i := range txm.availableAccounts {
+		account := txm.availableAccounts[(txm.availableAccountIdx+i)%len(txm.availableAccounts)]
+		if !txm.retiredAccounts[account.Address] {
+			return account
+		}
+	}
+	return nil
 }
 
 func (txm *EthTxManager) getAccount(from common.Address) *ManagedAccount {
@@ -916,7 +1272,7 @@ func (a *ManagedAccount) ReloadNonce(txm *EthTxManager) error {
 	if err != nil {
 		return fmt.Errorf("TxManager ReloadNonce: %v", err)
 	}
-	logger.Debugw("Got new network nonce", "nonce", nonce)
+	logger.TxManager.Debugw("Got new network nonce", "nonce", nonce)
 	a.nonce = nonce
 	return nil
 }
@@ -19,6 +19,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -139,6 +141,83 @@ func TestTxManager_CreateTx_RoundRobinSuccess(t *testing.T) {
 	ethClient.AssertExpectations(t)
 }
 
+func TestTxManager_CreateTxWithGas_SendsFromKeysOfTheJobNamespace(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ethClient := new(mocks.Client)
+
+	config := cltest.NewTestConfig(t)
+	keyStore := strpkg.NewKeyStore(config.KeysDir())
+	shared, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	reserved, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.Unlock(cltest.Password))
+
+	key := models.Key{Address: models.EIP55Address(reserved.Address.Hex()), JSON: models.JSON{}}
+	require.NoError(t, store.FirstOrCreateKey(&key))
+	require.NoError(t, store.SetKeyNamespace(reserved.Address, "team"))
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Namespace = "team"
+	require.NoError(t, store.CreateJob(&job))
+	run := cltest.NewJobRun(job)
+	require.NoError(t, store.CreateJobRun(&run))
+
+	manager := strpkg.NewEthTxManager(ethClient, config, keyStore, store.ORM)
+	manager.Register(keyStore.Accounts())
+	ethClient.On("GetNonce", mock.Anything).Return(uint64(0), nil)
+	require.NoError(t, manager.Connect(cltest.Head(1)))
+	ethClient.On("SendRawTx", mock.Anything).Return(cltest.NewHash(), nil)
+
+	to := cltest.NewAddress()
+	data := hexutil.MustDecode("0x0000abcdef")
+	for i := 0; i < 2; i++ {
+		tx, err := manager.CreateTxWithGas(null.StringFrom(run.ID.String()), to, data, nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, reserved.Address, tx.From)
+
+		tx, err = manager.CreateTx(to, data)
+		require.NoError(t, err)
+		assert.Equal(t, shared.Address, tx.From)
+	}
+}
+
+func TestTxManager_CreateTx_SkipsKeyFundingAddress(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ethClient := new(mocks.Client)
+
+	config := cltest.NewTestConfig(t)
+	keyStore := strpkg.NewKeyStore(config.KeysDir())
+	funder, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	worker, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.Unlock(cltest.Password))
+	config.Set("KEY_FUNDING_ADDRESS", funder.Address.Hex())
+
+	manager := strpkg.NewEthTxManager(ethClient, config, keyStore, store.ORM)
+	manager.Register(keyStore.Accounts())
+	ethClient.On("GetNonce", mock.Anything).Return(uint64(0), nil)
+	require.NoError(t, manager.Connect(cltest.Head(1)))
+	ethClient.On("SendRawTx", mock.Anything).Return(cltest.NewHash(), nil)
+
+	to := cltest.NewAddress()
+	data := hexutil.MustDecode("0x0000abcdef")
+	for i := 0; i < 2; i++ {
+		tx, err := manager.CreateTx(to, data)
+		require.NoError(t, err)
+		assert.Equal(t, worker.Address, tx.From)
+	}
+}
+
 func TestTxManager_CreateTx_BreakTxAttemptLimit(t *testing.T) {
 	t.Parallel()
 
@@ -363,6 +442,46 @@ func TestTxManager_CreateTx_ErrPendingConnection(t *testing.T) {
 	assert.Contains(t, err.Error(), strpkg.ErrPendingConnection.Error())
 }
 
+func TestTxManager_PauseBroadcasting(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ethClient := new(mocks.Client)
+
+	config := cltest.NewTestConfig(t)
+	keyStore := strpkg.NewKeyStore(config.KeysDir())
+	_, err := keyStore.NewAccount(cltest.Password)
+	require.NoError(t, err)
+	require.NoError(t, keyStore.Unlock(cltest.Password))
+	manager := strpkg.NewEthTxManager(ethClient, config, keyStore, store.ORM)
+	manager.Register(keyStore.Accounts())
+
+	ethClient.On("GetNonce", mock.Anything).Return(uint64(0), nil)
+	require.NoError(t, manager.Connect(cltest.Head(1)))
+
+	manager.PauseBroadcasting("providers disagree")
+	reason, paused := manager.BroadcastingPaused()
+	assert.True(t, paused)
+	assert.Equal(t, "providers disagree", reason)
+
+	_, err = manager.CreateTx(cltest.NewAddress(), hexutil.MustDecode("0x0000abcdef"))
+	assert.True(t, errors.Is(err, strpkg.ErrBroadcastingPaused))
+	_, err = manager.SendRawTx([]byte{1})
+	assert.True(t, errors.Is(err, strpkg.ErrBroadcastingPaused))
+	ethClient.AssertNotCalled(t, "SendRawTx", mock.Anything)
+
+	manager.ResumeBroadcasting()
+	_, paused = manager.BroadcastingPaused()
+	assert.False(t, paused)
+
+	ethClient.On("SendRawTx", mock.Anything).Return(cltest.NewHash(), nil)
+	_, err = manager.CreateTx(cltest.NewAddress(), hexutil.MustDecode("0x0000abcdef"))
+	require.NoError(t, err)
+	ethClient.AssertCalled(t, "SendRawTx", mock.Anything)
+}
+
 func TestTxManager_BumpGasUntilSafe_lessThanGasBumpThreshold(t *testing.T) {
 	t.Parallel()
 
@@ -946,6 +1065,70 @@ func TestTxManager_NextActiveAccount_RoundRobin(t *testing.T) {
 	assert.Equal(t, a0, a2)
 }
 
+func TestTxManager_NextActiveAccount_SkipsRetired(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ethMock := &cltest.EthMock{}
+	txm := strpkg.NewEthTxManager(
+		&eth.CallerSubscriberClient{CallerSubscriber: ethMock},
+		orm.NewConfig(),
+		nil,
+		store.ORM,
+	)
+
+	accounts := []accounts.Account{
+		accounts.Account{Address: common.HexToAddress("0xbf4ed7b27f1d666546e30d74d50d173d20bca001")},
+		accounts.Account{Address: common.HexToAddress("0xbf4ed7b27f1d666546e30d74d50d173d20bca002")},
+	}
+
+	ethMock.Register("eth_getTransactionCount", `0x1D0`)
+	ethMock.Register("eth_getTransactionCount", `0x2D0`)
+
+	txm.Register(accounts)
+	txm.Connect(cltest.Head(1))
+	ethMock.EventuallyAllCalled(t)
+
+	txm.RetireAccount(accounts[0].Address)
+	assert.Equal(t, accounts[1].Address, txm.NextActiveAccount().Address)
+	assert.Equal(t, accounts[1].Address, txm.NextActiveAccount().Address)
+	assert.NotNil(t, txm.GetAvailableAccount(accounts[0].Address), "retired account should still be tracked")
+
+	txm.RetireAccount(accounts[1].Address)
+	assert.Nil(t, txm.NextActiveAccount())
+}
+
+func TestTxManager_ActivateAccount(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := cltest.NewStore(t)
+	defer cleanup()
+
+	ethMock := &cltest.EthMock{}
+	txm := strpkg.NewEthTxManager(
+		&eth.CallerSubscriberClient{CallerSubscriber: ethMock},
+		orm.NewConfig(),
+		nil,
+		store.ORM,
+	)
+
+	txm.Register([]accounts.Account{})
+	require.NoError(t, txm.Connect(cltest.Head(1)))
+	assert.Nil(t, txm.NextActiveAccount())
+
+	ethMock.Register("eth_getTransactionCount", `0x2D0`)
+	account := accounts.Account{Address: common.HexToAddress("0xbf4ed7b27f1d666546e30d74d50d173d20bca754")}
+	require.NoError(t, txm.ActivateAccount(account))
+	ethMock.EventuallyAllCalled(t)
+
+	aa := txm.NextActiveAccount()
+	require.NotNil(t, aa)
+	assert.Equal(t, account.Address, aa.Address)
+	assert.Equal(t, uint64(0x2d0), aa.Nonce())
+}
+
 func TestTxManager_ReloadNonce(t *testing.T) {
 	t.Parallel()
 
@@ -1237,6 +1420,122 @@ func TestTxManager_RebroadcastUnconfirmedTxsOnReconnect(t *testing.T) {
 	ethClient.AssertExpectations(t)
 }
 
+func TestTxManager_BumpGas(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	store := app.Store
+	config := store.Config
+	from := cltest.GetAccountAddress(t, store)
+	sentAt := uint64(23456)
+	ethMock := app.EthMock
+	ethMock.Register("eth_getTransactionCount", "0x0")
+	ethMock.Register("eth_chainId", config.ChainID())
+	require.NoError(t, app.Store.ORM.CreateHead(cltest.Head(sentAt)))
+	require.NoError(t, app.StartAndConnect())
+
+	tx := cltest.CreateTx(t, store, from, sentAt)
+	require.Len(t, tx.Attempts, 1)
+
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	txAttempt, err := store.TxManager.BumpGas(tx.Attempts[0].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, 1, txAttempt.GasPrice.ToInt().Cmp(tx.Attempts[0].GasPrice.ToInt()))
+
+	tx, err = store.FindTx(tx.ID)
+	require.NoError(t, err)
+	assert.Len(t, tx.Attempts, 2)
+
+	require.NoError(t, store.MarkTxSafe(tx, tx.Attempts[1]))
+	_, err = store.TxManager.BumpGas(tx.Attempts[1].Hash)
+	assert.Error(t, err)
+
+	ethMock.EventuallyAllCalled(t)
+}
+
+func TestTxManager_CancelTx(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	store := app.Store
+	config := store.Config
+	from := cltest.GetAccountAddress(t, store)
+	sentAt := uint64(23456)
+	ethMock := app.EthMock
+	ethMock.Register("eth_getTransactionCount", "0x0")
+	ethMock.Register("eth_chainId", config.ChainID())
+	require.NoError(t, app.Store.ORM.CreateHead(cltest.Head(sentAt)))
+	require.NoError(t, app.StartAndConnect())
+
+	tx := cltest.CreateTx(t, store, from, sentAt)
+	require.Len(t, tx.Attempts, 1)
+
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	txAttempt, err := store.TxManager.CancelTx(tx.Attempts[0].Hash)
+	require.NoError(t, err)
+	assert.NotEqual(t, tx.Attempts[0].Hash, txAttempt.Hash)
+	assert.Equal(t, 1, txAttempt.GasPrice.ToInt().Cmp(tx.Attempts[0].GasPrice.ToInt()))
+
+	tx, err = store.FindTx(tx.ID)
+	require.NoError(t, err)
+	require.Len(t, tx.Attempts, 2)
+	assert.Equal(t, txAttempt.Hash, tx.Attempts[1].Hash)
+	assert.Equal(t, from, tx.To)
+	assert.Equal(t, int64(0), tx.Value.ToInt().Int64())
+	assert.Equal(t, uint64(21000), tx.GasLimit)
+	assert.Empty(t, tx.Data)
+
+	// Bumping the gas of a cancelled transaction sends the self-send again
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	bumped, err := store.TxManager.BumpGas(txAttempt.Hash)
+	require.NoError(t, err)
+	var signed types.Transaction
+	require.NoError(t, rlp.DecodeBytes(bumped.SignedRawTx, &signed))
+	require.NotNil(t, signed.To())
+	assert.Equal(t, from, *signed.To())
+	assert.Equal(t, int64(0), signed.Value().Int64())
+	assert.Empty(t, signed.Data())
+
+	ethMock.EventuallyAllCalled(t)
+}
+
+func TestTxManager_ResendTxs(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	store := app.Store
+	config := store.Config
+	from := cltest.GetAccountAddress(t, store)
+	sentAt := uint64(23456)
+	ethMock := app.EthMock
+	ethMock.Register("eth_getTransactionCount", "0x0")
+	ethMock.Register("eth_chainId", config.ChainID())
+	require.NoError(t, app.Store.ORM.CreateHead(cltest.Head(sentAt)))
+	require.NoError(t, app.StartAndConnect())
+
+	tx := cltest.CreateTx(t, store, from, sentAt)
+	require.Len(t, tx.Attempts, 1)
+
+	ethMock.Register("eth_sendRawTransaction", tx.Attempts[0].Hash)
+	resent, err := store.TxManager.ResendTxs(&from, tx.Nonce, tx.Nonce)
+	require.NoError(t, err)
+	require.Len(t, resent, 1)
+	assert.Equal(t, tx.ID, resent[0].ID)
+
+	other := cltest.NewAddress()
+	resent, err = store.TxManager.ResendTxs(&other, tx.Nonce, tx.Nonce)
+	require.NoError(t, err)
+	assert.Len(t, resent, 0)
+
+	ethMock.EventuallyAllCalled(t)
+}
+
 func TestTxManager_BumpGasByIncrement(t *testing.T) {
 	t.Parallel()
 
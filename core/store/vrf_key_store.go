@@ -22,16 +22,20 @@ type VRFKeyStore struct {
 	lock  sync.RWMutex
 	keys  InMemoryKeyStore
 	store *Store
+	pool  *vrfProofPool
 }
 
 type InMemoryKeyStore = map[vrfkey.PublicKey]vrfkey.PrivateKey
 
-// NewVRFKeyStore returns an empty VRFKeyStore
+// NewVRFKeyStore returns an empty VRFKeyStore, which generates at most
+// VRFProofWorkers proofs at a time.
 func NewVRFKeyStore(store *Store) *VRFKeyStore {
 	return &VRFKeyStore{
 		lock:  sync.RWMutex{},
 		keys:  make(InMemoryKeyStore),
 		store: store,
+		pool: newVRFProofPool(
+			store.Config.VRFProofWorkers(), store.Config.VRFProofQueueSize()),
 	}
 }
 
@@ -40,15 +44,18 @@ func NewVRFKeyStore(store *Store) *VRFKeyStore {
 //
 // k must have already been unlocked in ks, as constructing the VRF proof
 // requires the secret key.
+//
+// The proof waits its turn behind those requested before it, and fails with
+// ErrVRFProofQueueFull if too many are already waiting.
 func (ks *VRFKeyStore) GenerateProof(k *vrfkey.PublicKey, seed *big.Int) (
 	vrf.MarshaledProof, error) {
 	ks.lock.RLock()
-	defer ks.lock.RUnlock()
 	privateKey, found := ks.keys[*k]
+	ks.lock.RUnlock()
 	if !found {
 		return vrf.MarshaledProof{}, fmt.Errorf("key %s has not been unlocked", k)
 	}
-	return privateKey.MarshaledProof(seed)
+	return ks.pool.generate(privateKey, seed)
 }
 
 // Unlock tries to unlock each vrf key in the db, using the given pass phrase,
@@ -104,7 +104,7 @@ func (ks *VRFKeyStore) Import(keyjson []byte, auth string) error {
 	if err != nil {
 		return errors.Wrapf(err,
 			"while attempting to decrypt key with public key %s",
-			key.PublicKey.String())
+			enckey.PublicKey.String())
 	}
 	if err := ks.store.FirstOrCreateEncryptedSecretVRFKey(enckey); err != nil {
 		return errors.Wrapf(err, "while saving encrypted key to DB")
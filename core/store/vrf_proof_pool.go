@@ -0,0 +1,52 @@
+package store
+
+import (
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/services/vrf"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+)
+
+// ErrVRFProofQueueFull is returned when a VRF proof is requested while
+// VRFProofQueueSize proofs are already waiting for a worker.
+var ErrVRFProofQueueFull = errors.New("too many VRF proofs waiting to be generated")
+
+// vrfProofPool bounds the number of VRF proofs generated at the same time,
+// and the number waiting to be generated.
+type vrfProofPool struct {
+	pending    int64 // Proofs being generated or waiting for a worker
+	maxPending int64
+	workers    chan struct{}
+}
+
+func newVRFProofPool(workers, queueSize uint) *vrfProofPool {
+	if workers == 0 {
+		workers = 1
+	}
+	return &vrfProofPool{
+		workers:    make(chan struct{}, workers),
+		maxPending: int64(workers + queueSize),
+	}
+}
+
+// generate waits for a worker to be free, then generates the proof for seed
+// with key.
+func (p *vrfProofPool) generate(key vrfkey.PrivateKey, seed *big.Int) (
+	vrf.MarshaledProof, error) {
+	defer atomic.AddInt64(&p.pending, -1)
+	if atomic.AddInt64(&p.pending, 1) > p.maxPending {
+		return vrf.MarshaledProof{}, ErrVRFProofQueueFull
+	}
+	promVRFProofQueueDepth.Inc()
+	p.workers <- struct{}{}
+	promVRFProofQueueDepth.Dec()
+	defer func() { <-p.workers }()
+
+	start := time.Now()
+	proof, err := key.MarshaledProof(seed)
+	promVRFProofDuration.Observe(time.Since(start).Seconds())
+	return proof, err
+}
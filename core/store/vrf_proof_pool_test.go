@@ -0,0 +1,36 @@
+package store
+
+import (
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/services/vrf"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+
+	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVRFProofPool_QueueFull(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pool := newVRFProofPool(1, 0)
+	key := vrfkey.CreateKey()
+
+	pool.workers <- struct{}{} // Keep the only worker busy
+
+	proofs := make(chan vrf.MarshaledProof)
+	go func() {
+		proof, err := pool.generate(*key, big.NewInt(1))
+		assert.NoError(t, err)
+		proofs <- proof
+	}()
+	g.Eventually(func() int64 { return atomic.LoadInt64(&pool.pending) }).Should(gomega.Equal(int64(1)))
+
+	_, err := pool.generate(*key, big.NewInt(2))
+	assert.Equal(t, ErrVRFProofQueueFull, err)
+
+	<-pool.workers
+	assert.NotEqual(t, vrf.MarshaledProof{}, <-proofs)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&pool.pending))
+}
@@ -0,0 +1,36 @@
+package utils_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtils_SignAWSV4(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	credentials := utils.AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}
+	sign := func(body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://kms.eu-west-1.amazonaws.com/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+		utils.SignAWSV4(req, []byte(body), "kms", "eu-west-1", credentials, now)
+		return req
+	}
+
+	req := sign(`{"KeyId":"1"}`)
+	assert.Equal(t, "20200601T120000Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "session", req.Header.Get("X-Amz-Security-Token"))
+	auth := req.Header.Get("Authorization")
+	assert.Contains(t, auth, "AWS4-HMAC-SHA256 Credential=AKID/20200601/eu-west-1/kms/aws4_request, ")
+	assert.Contains(t, auth, "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token, ")
+
+	assert.Equal(t, auth, sign(`{"KeyId":"1"}`).Header.Get("Authorization"))
+	assert.NotEqual(t, auth, sign(`{"KeyId":"2"}`).Header.Get("Authorization"))
+}
@@ -0,0 +1,32 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUtils_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("node secret")
+	ciphertext, err := utils.Encrypt(secret, []byte("hunter2"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "hunter2")
+
+	again, err := utils.Encrypt(secret, []byte("hunter2"))
+	require.NoError(t, err)
+	assert.NotEqual(t, ciphertext, again, "nonce should be random")
+
+	plaintext, err := utils.Decrypt(secret, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(plaintext))
+
+	_, err = utils.Decrypt([]byte("other secret"), ciphertext)
+	assert.Error(t, err)
+	_, err = utils.Decrypt(secret, []byte("short"))
+	assert.Error(t, err)
+}
@@ -307,6 +307,17 @@ func MaxUint32(first uint32, uints ...uint32) uint32 {
 	return max
 }
 
+// MaxUint64 finds the maximum value of a list of uint64s.
+func MaxUint64(first uint64, uints ...uint64) uint64 {
+	max := first
+	for _, n := range uints {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
 // MaxInt finds the maximum value of a list of ints.
 func MaxInt(first int, ints ...int) int {
 	max := first
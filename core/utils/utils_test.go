@@ -273,6 +273,25 @@ func TestMaxUint32(t *testing.T) {
 	}
 }
 
+func TestMaxUint64(t *testing.T) {
+	tests := []struct {
+		name        string
+		expectation uint64
+		vals        []uint64
+	}{
+		{"single", 9, []uint64{9}},
+		{"positives", 5, []uint64{3, 4, 5}},
+		{"equal", 3, []uint64{3, 3, 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := utils.MaxUint64(test.vals[0], test.vals[1:len(test.vals)]...)
+			assert.Equal(t, test.expectation, actual)
+		})
+	}
+}
+
 func TestMaxInt(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,21 @@
+package web
+
+import (
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessViolationsController lists the requests refused because they came
+// from outside the networks allowed to make requests to the endpoint.
+type AccessViolationsController struct {
+	App chainlink.Application
+}
+
+// Index returns the access violations, most recent first.
+// Example:
+//  "<application>/access_violations"
+func (avc *AccessViolationsController) Index(c *gin.Context, size, page, offset int) {
+	violations, count, err := avc.App.GetStore().AccessViolations(offset, size)
+	paginatedResponse(c, "AccessViolations", size, page, violations, count, err)
+}
@@ -6,6 +6,8 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/pkg/errors"
 )
@@ -24,6 +26,10 @@ const (
 	// KeyPreviousLink is the name of the key that contains the HREF for the
 	// previous document in a paginated response.
 	KeyPreviousLink = "prev"
+	// KeySnapshot is the name of the param asking for a paginated response to
+	// be read from a snapshot, and of the meta key holding the snapshot token
+	// to pass for its later pages.
+	KeySnapshot = "snapshot"
 )
 
 // ParsePaginatedRequest parses the parameters that control pagination for a
@@ -78,6 +84,30 @@ func NewJSONAPIResponse(resource interface{}) ([]byte, error) {
 
 // NewPaginatedResponse returns a jsonapi.Document with links to next and previous collection pages
 func NewPaginatedResponse(url url.URL, size, page, count int, resource interface{}) ([]byte, error) {
+	document, err := paginatedDocument(url, size, page, count, resource)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(document)
+}
+
+// NewSnapshotPaginatedResponse returns a paginated response whose links pass
+// the token of the snapshot its records were read from, which is also given
+// as meta.snapshot, so that each page is read from the same snapshot.
+func NewSnapshotPaginatedResponse(url url.URL, size, page, count int, snapshot orm.Snapshot, resource interface{}) ([]byte, error) {
+	query := url.Query()
+	query.Set(KeySnapshot, snapshot.Token())
+	url.RawQuery = query.Encode()
+
+	document, err := paginatedDocument(url, size, page, count, resource)
+	if err != nil {
+		return nil, err
+	}
+	document.Meta[KeySnapshot] = snapshot.Token()
+	return json.Marshal(document)
+}
+
+func paginatedDocument(url url.URL, size, page, count int, resource interface{}) (*jsonapi.Document, error) {
 	document, err := jsonapi.MarshalToStruct(resource, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal resource to struct: %+v", err)
@@ -95,7 +125,7 @@ func NewPaginatedResponse(url url.URL, size, page, count int, resource interface
 			document.Links[KeyPreviousLink] = prevLink(url, size, page)
 		}
 	}
-	return json.Marshal(document)
+	return document, nil
 }
 
 // ParsePaginatedResponse parse a JSONAPI response for a document with links
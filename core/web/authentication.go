@@ -1,9 +1,13 @@
 package web
 
 import (
+	"bytes"
+	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 
@@ -19,16 +23,28 @@ const (
 	APISecret = "X-API-SECRET"
 	// ExternalInitiatorAccessKeyHeader is the header name for the access key
 	// used by external initiators to authenticate
-	ExternalInitiatorAccessKeyHeader = "X-Chainlink-EA-AccessKey"
+	ExternalInitiatorAccessKeyHeader = models.ExternalInitiatorAccessKeyHeader
 	// ExternalInitiatorSecretHeader is the header name for the secret used by
 	// external initiators to authenticate
-	ExternalInitiatorSecretHeader = "X-Chainlink-EA-Secret"
+	ExternalInitiatorSecretHeader = models.ExternalInitiatorSecretHeader
+	// ExternalInitiatorTimestampHeader is the header name for the time at
+	// which external initiators signed their request
+	ExternalInitiatorTimestampHeader = models.ExternalInitiatorTimestampHeader
+	// ExternalInitiatorNonceHeader is the header name for the nonce external
+	// initiators signed their request with
+	ExternalInitiatorNonceHeader = models.ExternalInitiatorNonceHeader
+	// ExternalInitiatorSignatureHeader is the header name for the signature
+	// of the requests of external initiators
+	ExternalInitiatorSignatureHeader = models.ExternalInitiatorSignatureHeader
 )
 
 type AuthStorer interface {
 	AuthorizedUserWithSession(sessionID string) (models.User, error)
 	FindExternalInitiator(eia *auth.Token) (*models.ExternalInitiator, error)
 	FindUser() (models.User, error)
+	FindNamespaceToken(accessKey string) (models.NamespaceToken, error)
+	MarkExternalInitiatorSeen(name string, at time.Time) error
+	UseExternalInitiatorNonce(name, nonce string, at time.Time) error
 }
 
 type authType func(store AuthStorer, ctx *gin.Context) error
@@ -62,13 +78,48 @@ func AuthenticateExternalInitiator(store AuthStorer, c *gin.Context) error {
 	if !ok {
 		return auth.ErrorAuthFailed
 	}
+	if ei.RequireSignature {
+		if err := verifyExternalInitiatorSignature(store, c, ei); err != nil {
+			logger.Web.Warnw("Rejecting request of external initiator", "name", ei.Name, "error", err)
+			return auth.ErrorAuthFailed
+		}
+	}
 	c.Set(SessionExternalInitiatorKey, ei)
+	logger.Web.ErrorIf(store.MarkExternalInitiatorSeen(ei.Name, time.Now()), "failed to mark external initiator as seen")
 
 	return nil
 }
 
 var _ authType = AuthenticateExternalInitiator
 
+// verifyExternalInitiatorSignature checks the signature of the request of an
+// external initiator requiring them, and that its nonce was not used before,
+// so that the request cannot be replayed. The body is read to be checked,
+// and put back for the handlers.
+func verifyExternalInitiatorSignature(store AuthStorer, c *gin.Context, ei *models.ExternalInitiator) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return errors.Wrap(err, "reading body")
+	}
+	c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	now := time.Now()
+	nonce := c.GetHeader(ExternalInitiatorNonceHeader)
+	err = ei.VerifySignature(
+		c.Request.Method,
+		c.Request.URL.RequestURI(),
+		c.GetHeader(ExternalInitiatorTimestampHeader),
+		nonce,
+		c.GetHeader(ExternalInitiatorSignatureHeader),
+		body,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+	return store.UseExternalInitiatorNonce(ei.Name, nonce, now)
+}
+
 func authenticatedEI(c *gin.Context) (*models.ExternalInitiator, bool) {
 	obj, ok := c.Get(SessionExternalInitiatorKey)
 	if !ok {
@@ -103,6 +154,44 @@ func AuthenticateByToken(store AuthStorer, c *gin.Context) error {
 
 var _ authType = AuthenticateByToken
 
+// AuthenticateByNamespaceToken authenticates a namespace token, which only
+// gives access to the records of its namespace.
+func AuthenticateByNamespaceToken(store AuthStorer, c *gin.Context) error {
+	token := &auth.Token{
+		AccessKey: c.GetHeader(APIKey),
+		Secret:    c.GetHeader(APISecret),
+	}
+	if token.AccessKey == "" {
+		return auth.ErrorAuthFailed
+	}
+
+	nt, err := store.FindNamespaceToken(token.AccessKey)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		return auth.ErrorAuthFailed
+	} else if err != nil {
+		return errors.Wrap(err, "finding namespace token")
+	}
+
+	ok, err := models.AuthenticateNamespaceToken(token, &nt)
+	if err != nil {
+		return err
+	} else if !ok {
+		return auth.ErrorAuthFailed
+	}
+	c.Set(SessionNamespaceTokenKey, &nt)
+	return nil
+}
+
+var _ authType = AuthenticateByNamespaceToken
+
+func authenticatedNamespaceToken(c *gin.Context) (*models.NamespaceToken, bool) {
+	obj, ok := c.Get(SessionNamespaceTokenKey)
+	if !ok {
+		return nil, false
+	}
+	return obj.(*models.NamespaceToken), ok
+}
+
 func AuthenticateBySession(store AuthStorer, c *gin.Context) error {
 	session := sessions.Default(c)
 	sessionID, ok := session.Get(SessionIDKey).(string)
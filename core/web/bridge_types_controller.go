@@ -3,17 +3,23 @@ package web
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/services/chainlink"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 )
 
+// defaultBridgeTokenGracePeriod is how long the tokens of a bridge replaced by
+// a rotation are still used for, unless the rotation gives a grace period.
+const defaultBridgeTokenGracePeriod = time.Hour
+
 // BridgeTypesController manages BridgeType requests in the node.
 type BridgeTypesController struct {
 	App chainlink.Application
@@ -27,6 +33,12 @@ func (btc *BridgeTypesController) Create(c *gin.Context) {
 		jsonAPIError(c, http.StatusUnprocessableEntity, err)
 		return
 	}
+	ns, err := namespaceFor(c, btr.Namespace)
+	if err != nil {
+		jsonAPIError(c, http.StatusForbidden, err)
+		return
+	}
+	btr.Namespace = ns
 	bta, bt, err := models.NewBridgeType(btr)
 	if err != nil {
 		jsonAPIError(c, StatusCodeForError(err), err)
@@ -40,6 +52,11 @@ func (btc *BridgeTypesController) Create(c *gin.Context) {
 		jsonAPIError(c, http.StatusBadRequest, e)
 		return
 	}
+	if e := btc.setAuth(bt, btr); e != nil {
+		jsonAPIError(c, http.StatusInternalServerError, e)
+		return
+	}
+	bta.AuthType = bt.AuthType
 	if e := btc.App.GetStore().CreateBridgeType(bt); e != nil {
 		jsonAPIError(c, http.StatusInternalServerError, e)
 		return
@@ -59,10 +76,37 @@ func (btc *BridgeTypesController) Create(c *gin.Context) {
 	}
 }
 
-// Index lists Bridges, one page at a time.
+// Index lists Bridges, one page at a time, only those of a namespace if
+// given, or if requested with a namespace token.
 func (btc *BridgeTypesController) Index(c *gin.Context, size, page, offset int) {
-	bridges, count, err := btc.App.GetStore().BridgeTypes(offset, size)
-	paginatedResponse(c, "Bridges", size, page, bridges, count, err)
+	var bridges []models.BridgeType
+	var count int
+	var err error
+	if ns, scoped := requestNamespace(c); scoped {
+		bridges, count, err = btc.App.GetStore().BridgeTypesInNamespace(ns, offset, size)
+	} else {
+		bridges, count, err = btc.App.GetStore().BridgeTypes(offset, size)
+	}
+	if err != nil {
+		paginatedResponse(c, "Bridges", size, page, nil, 0, err)
+		return
+	}
+
+	names := make([]string, len(bridges))
+	for i, bt := range bridges {
+		names[i] = bt.Name.String()
+	}
+	healths, err := btc.App.GetStore().FindBridgeHealths(names)
+	pbts := make([]presenters.BridgeType, len(bridges))
+	for i, bt := range bridges {
+		pbts[i] = presenters.BridgeType{BridgeType: bt}
+		for j := range healths {
+			if healths[j].BridgeName == bt.Name {
+				pbts[i].Health = &healths[j]
+			}
+		}
+	}
+	paginatedResponse(c, "Bridges", size, page, pbts, count, err)
 }
 
 // Show returns the details of a specific Bridge.
@@ -76,7 +120,7 @@ func (btc *BridgeTypesController) Show(c *gin.Context) {
 	}
 
 	bt, err := btc.App.GetStore().FindBridge(taskType)
-	if errors.Cause(err) == orm.ErrorNotFound {
+	if errors.Cause(err) == orm.ErrorNotFound || (err == nil && !visibleIn(c, bt.Namespace)) {
 		jsonAPIError(c, http.StatusNotFound, errors.New("bridge not found"))
 		return
 	}
@@ -85,10 +129,21 @@ func (btc *BridgeTypesController) Show(c *gin.Context) {
 		return
 	}
 
-	jsonAPIResponse(c, bt, "bridge")
+	pbt := presenters.BridgeType{BridgeType: bt}
+	health, err := btc.App.GetStore().FindBridgeHealth(taskType)
+	if err == nil {
+		pbt.Health = &health
+	} else if errors.Cause(err) != orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, pbt, "bridge")
 }
 
-// Update can change the restricted attributes for a bridge
+// Update can change the restricted attributes for a bridge. The auth of the
+// bridge is only replaced when the request gives one, and it cannot be made
+// a stream bridge while tasks call it.
 func (btc *BridgeTypesController) Update(c *gin.Context) {
 	name := c.Param("BridgeName")
 	btr := &models.BridgeTypeRequest{}
@@ -100,7 +155,7 @@ func (btc *BridgeTypesController) Update(c *gin.Context) {
 	}
 
 	bt, err := btc.App.GetStore().FindBridge(taskType)
-	if errors.Cause(err) == orm.ErrorNotFound {
+	if errors.Cause(err) == orm.ErrorNotFound || (err == nil && !visibleIn(c, bt.Namespace)) {
 		jsonAPIError(c, http.StatusNotFound, errors.New("bridge not found"))
 		return
 	}
@@ -117,6 +172,24 @@ func (btc *BridgeTypesController) Update(c *gin.Context) {
 		jsonAPIError(c, http.StatusBadRequest, err)
 		return
 	}
+	// Stream bridges cannot be called by tasks
+	if btr.Mode.OrDefault() == models.BridgeModeStream && bt.Mode.OrDefault() != models.BridgeModeStream {
+		jobFound, err := btc.App.GetStore().AnyJobWithType(name)
+		if err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if jobFound {
+			jsonAPIError(c, http.StatusConflict, errors.New("Can't make the bridge a stream bridge because there are jobs with tasks calling it"))
+			return
+		}
+	}
+	if btr.Auth != nil {
+		if err := btc.setAuth(&bt, btr); err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
 	if err := btc.App.GetStore().UpdateBridgeType(&bt, btr); err != nil {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
@@ -125,6 +198,58 @@ func (btc *BridgeTypesController) Update(c *gin.Context) {
 	jsonAPIResponse(c, bt, "bridge")
 }
 
+// RotateTokens replaces the incoming and outgoing tokens of a Bridge, which
+// are returned like when it was created. The tokens replaced are still used
+// during a grace period, an hour unless the request gives one, for the
+// external adapter to be updated without callbacks failing in the meantime.
+// Example:
+//  "<application>/bridge_types/:BridgeName/token_rotation"
+func (btc *BridgeTypesController) RotateTokens(c *gin.Context) {
+	taskType, err := models.NewTaskType(c.Param("BridgeName"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	bt, err := btc.App.GetStore().FindBridge(taskType)
+	if errors.Cause(err) == orm.ErrorNotFound || (err == nil && !visibleIn(c, bt.Namespace)) {
+		jsonAPIError(c, http.StatusNotFound, errors.New("bridge not found"))
+		return
+	}
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	request := &models.BridgeTokenRotationRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(request); err != nil {
+			jsonAPIError(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+	gracePeriod := defaultBridgeTokenGracePeriod
+	if request.GracePeriod != nil {
+		gracePeriod = request.GracePeriod.Duration()
+	}
+	if gracePeriod < 0 {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("gracePeriod must not be negative"))
+		return
+	}
+
+	bta, err := bt.RotateTokens(gracePeriod, time.Now())
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := btc.App.GetStore().UpdateBridgeTypeTokens(&bt); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, bta, "bridge")
+}
+
 // Destroy removes a specific Bridge.
 func (btc *BridgeTypesController) Destroy(c *gin.Context) {
 	name := c.Param("BridgeName")
@@ -136,7 +261,7 @@ func (btc *BridgeTypesController) Destroy(c *gin.Context) {
 	}
 
 	bt, err := btc.App.GetStore().FindBridge(taskType)
-	if errors.Cause(err) == orm.ErrorNotFound {
+	if errors.Cause(err) == orm.ErrorNotFound || (err == nil && !visibleIn(c, bt.Namespace)) {
 		jsonAPIError(c, http.StatusNotFound, errors.New("bridge not found"))
 		return
 	}
@@ -160,3 +285,9 @@ func (btc *BridgeTypesController) Destroy(c *gin.Context) {
 
 	jsonAPIResponse(c, bt, "bridge")
 }
+
+// setAuth stores the requested auth scheme on the bridge, encrypted with the
+// node's master key when saved.
+func (btc *BridgeTypesController) setAuth(bt *models.BridgeType, btr *models.BridgeTypeRequest) error {
+	return bt.SetAuth(btr.Auth)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
@@ -152,6 +153,110 @@ func TestBridgeTypesController_Update_Success(t *testing.T) {
 	assert.Equal(t, cltest.WebURL(t, "http://yourbridge"), ubt.URL)
 }
 
+func TestBridgeTypesController_Update_KeepsAuth(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	_, bt := cltest.NewBridgeType(t, "authenticated")
+	require.NoError(t, bt.SetAuth(&models.BridgeAuth{Type: models.BridgeAuthBasic, Username: "user", Password: "pass"}))
+	require.NoError(t, app.Store.CreateBridgeType(bt))
+
+	ud := bytes.NewBufferString(`{"name": "authenticated","url":"http://yourbridge"}`)
+	resp, cleanup := client.Patch("/v2/bridge_types/authenticated", ud)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	ubt, err := app.Store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, cltest.WebURL(t, "http://yourbridge"), ubt.URL)
+	assert.Equal(t, models.BridgeAuthBasic, ubt.AuthType)
+	auth, err := ubt.Auth()
+	require.NoError(t, err)
+	require.NotNil(t, auth)
+	assert.Equal(t, "user", auth.Username)
+	assert.Equal(t, "pass", auth.Password)
+
+	ud = bytes.NewBufferString(`{"name": "authenticated","url":"http://yourbridge","auth":{"type":"basic","username":"other","password":"word"}}`)
+	resp, cleanup = client.Patch("/v2/bridge_types/authenticated", ud)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	ubt, err = app.Store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	auth, err = ubt.Auth()
+	require.NoError(t, err)
+	require.NotNil(t, auth)
+	assert.Equal(t, "other", auth.Username)
+}
+
+func TestBridgeTypesController_Update_StreamModeWhileCalledByTasks(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	_, bt := cltest.NewBridgeType(t, "called")
+	require.NoError(t, app.Store.CreateBridgeType(bt))
+	job := cltest.NewJobWithWebInitiator()
+	job.Tasks = []models.TaskSpec{{Type: bt.Name}}
+	require.NoError(t, app.Store.CreateJob(&job))
+
+	ud := bytes.NewBufferString(`{"name": "called","url":"ws://yourbridge","mode":"stream"}`)
+	resp, cleanup := client.Patch("/v2/bridge_types/called", ud)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusConflict)
+
+	ubt, err := app.Store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, models.BridgeModeHTTP, ubt.Mode.OrDefault())
+}
+
+func TestBridgeTypesController_RotateTokens(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	bta, bt := cltest.NewBridgeType(t, "rotating")
+	require.NoError(t, app.Store.CreateBridgeType(bt))
+
+	resp, cleanup := client.Post("/v2/bridge_types/rotating/token_rotation", bytes.NewBufferString(`{"gracePeriod": "-1s"}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Post("/v2/bridge_types/rotating/token_rotation", bytes.NewBufferString(`{"gracePeriod": "10m"}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var rotated models.BridgeTypeAuthentication
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &rotated))
+	assert.NotEqual(t, bta.IncomingToken, rotated.IncomingToken)
+	assert.NotEqual(t, bta.OutgoingToken, rotated.OutgoingToken)
+
+	updated, err := app.Store.FindBridge(bt.Name)
+	require.NoError(t, err)
+	assert.Equal(t, rotated.OutgoingToken, updated.OutgoingToken.String())
+	assert.Equal(t, bta.OutgoingToken, updated.PreviousOutgoingToken.String())
+	assert.True(t, updated.InTokenGracePeriod(time.Now()))
+	assert.False(t, updated.InTokenGracePeriod(time.Now().Add(11*time.Minute)))
+	for _, token := range []string{bta.IncomingToken, rotated.IncomingToken} {
+		ok, err := models.AuthenticateBridgeType(&updated, token)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	resp, cleanup = client.Post("/v2/bridge_types/missing/token_rotation", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
+
 func TestBridgeController_Show(t *testing.T) {
 	t.Parallel()
 
@@ -5,11 +5,12 @@ import (
 
 	"github.com/smartcontractkit/chainlink/core/services/chainlink"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
 
 	"github.com/gin-gonic/gin"
 )
 
-// BulkDeletesController manages background tasks that delete resources given a query
+// BulkDeletesController manages background tasks that delete or update resources given a query
 type BulkDeletesController struct {
 	App chainlink.Application
 }
@@ -34,3 +35,27 @@ func (bdc *BulkDeletesController) Delete(c *gin.Context) {
 
 	jsonAPIResponseWithStatus(c, nil, "nil", http.StatusNoContent)
 }
+
+// Update moves all runs matching a query to another status, such as
+// cancelling those pending on a deleted bridge, returning how many were
+// updated.
+// Example:
+//  "<application>/bulk_update_runs"
+func (bdc *BulkDeletesController) Update(c *gin.Context) {
+	request := &models.BulkUpdateRunRequest{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		jsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := models.ValidateBulkUpdateRunRequest(request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	count, err := bdc.App.GetStore().BulkUpdateRuns(request)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, presenters.BulkRunUpdate{NewStatus: request.NewStatus, Count: count}, "bulk run update")
+}
@@ -0,0 +1,60 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkDeletesController_Update(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Tasks = []models.TaskSpec{{Type: models.MustNewTaskType("deletedbridge")}}
+	require.NoError(t, app.Store.CreateJob(&job))
+	pendingRun := cltest.NewJobRunPendingBridge(job)
+	require.NoError(t, app.Store.CreateJobRun(&pendingRun))
+
+	body, err := json.Marshal(models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusInProgress},
+		NewStatus: models.RunStatusCancelled,
+	})
+	require.NoError(t, err)
+	resp, cleanup := client.Post("/v2/bulk_update_runs", bytes.NewBuffer(body))
+	defer cleanup()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode, "in progress runs may be executing")
+
+	body, err = json.Marshal(models.BulkUpdateRunRequest{
+		Status:    []models.RunStatus{models.RunStatusPendingBridge},
+		Bridge:    models.MustNewTaskType("deletedbridge"),
+		NewStatus: models.RunStatusCancelled,
+		Reason:    "bridge deleted",
+	})
+	require.NoError(t, err)
+	resp, cleanup = client.Post("/v2/bulk_update_runs", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var update presenters.BulkRunUpdate
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &update))
+	assert.Equal(t, models.RunStatusCancelled, update.NewStatus)
+	assert.Equal(t, 1, update.Count)
+
+	cancelled, err := app.Store.FindJobRun(pendingRun.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RunStatusCancelled, cancelled.Status)
+	assert.Equal(t, "bridge deleted", cancelled.Result.ErrorMessage.String)
+}
@@ -0,0 +1,185 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// maxBulkJobs is the most jobs a bulk request may create, archive or
+// restore, which bounds the length of the transaction of an atomic request.
+const maxBulkJobs = 1000
+
+// bulkAtomicAbortedError is the error of the items of an atomic bulk request
+// which were left alone because another item failed.
+const bulkAtomicAbortedError = "not applied, as another job of the atomic request failed"
+
+// BulkJobSpecsController creates, archives and restores many jobs in one
+// request, reporting the outcome of each.
+type BulkJobSpecsController struct {
+	App chainlink.Application
+}
+
+// Create validates, saves and starts many jobs. An atomic request creates
+// all of them or, if any is invalid, none.
+// Example:
+//  "<application>/bulk_specs"
+func (bjsc *BulkJobSpecsController) Create(c *gin.Context) {
+	var request models.BulkJobSpecsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if len(request.Specs) > maxBulkJobs {
+		jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("at most %d jobs may be created at once", maxBulkJobs))
+		return
+	}
+
+	jsc := JobSpecsController{bjsc.App}
+	results := make([]presenters.BulkJobResult, len(request.Specs))
+	jobs := make([]models.JobSpec, len(request.Specs))
+	failed := false
+	for i, jsr := range request.Specs {
+		results[i].Index = i
+		js, _, err := jsc.checkJobSpec(jsr, models.JobSpecLines{})
+		if err != nil {
+			results[i].Error = err.Error()
+			failed = true
+			continue
+		}
+		jobs[i] = js
+		results[i].JobID = js.ID.String()
+	}
+
+	if request.Atomic {
+		if failed {
+			bulkAtomicAborted(results)
+			jsonAPIResponseWithStatus(c, results, "bulk job results", http.StatusUnprocessableEntity)
+			return
+		}
+		if err := bjsc.App.AddJobs(jobs); err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	for i, js := range jobs {
+		if results[i].Error != "" {
+			continue
+		}
+		var err error
+		if !request.Atomic {
+			err = bjsc.App.AddJob(js)
+		}
+		if err == nil {
+			err = NotifyExternalInitiator(js, bjsc.App.GetStore())
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			failed = true
+		}
+	}
+	bulkJobResponse(c, results, failed)
+}
+
+// Archive archives many jobs. An atomic request archives all of them or, if
+// any cannot be, none.
+// Example:
+//  "<application>/bulk_specs/archive"
+func (bjsc *BulkJobSpecsController) Archive(c *gin.Context) {
+	bjsc.applyToJobs(c, bjsc.App.ArchiveJobs)
+}
+
+// Restore brings back many archived jobs. An atomic request restores all of
+// them or, if any cannot be, none.
+// Example:
+//  "<application>/bulk_specs/restore"
+func (bjsc *BulkJobSpecsController) Restore(c *gin.Context) {
+	bjsc.applyToJobs(c, bjsc.App.UnarchiveJobs)
+}
+
+// applyToJobs applies apply to the jobs of the request, all at once if the
+// request is atomic and one by one otherwise.
+func (bjsc *BulkJobSpecsController) applyToJobs(c *gin.Context, apply func([]*models.ID) error) {
+	var request models.BulkJobIDsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if len(request.IDs) > maxBulkJobs {
+		jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("at most %d jobs may be changed at once", maxBulkJobs))
+		return
+	}
+
+	results := make([]presenters.BulkJobResult, len(request.IDs))
+	IDs := make([]*models.ID, len(request.IDs))
+	failed := false
+	for i, s := range request.IDs {
+		results[i] = presenters.BulkJobResult{Index: i, JobID: s}
+		ID, err := models.NewIDFromString(s)
+		if err != nil {
+			results[i].Error = err.Error()
+			failed = true
+			continue
+		}
+		IDs[i] = ID
+	}
+
+	if request.Atomic {
+		if failed {
+			bulkAtomicAborted(results)
+			jsonAPIResponseWithStatus(c, results, "bulk job results", http.StatusUnprocessableEntity)
+			return
+		}
+		if err := apply(IDs); errors.Cause(err) == orm.ErrorNotFound {
+			jsonAPIError(c, http.StatusNotFound, err)
+			return
+		} else if err != nil {
+			jsonAPIError(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		bulkJobResponse(c, results, false)
+		return
+	}
+
+	for i, ID := range IDs {
+		if ID == nil {
+			continue
+		}
+		if err := apply([]*models.ID{ID}); errors.Cause(err) == orm.ErrorNotFound {
+			results[i].Error = "job not found"
+			failed = true
+		} else if err != nil {
+			results[i].Error = err.Error()
+			failed = true
+		}
+	}
+	bulkJobResponse(c, results, failed)
+}
+
+// bulkAtomicAborted marks the items of an atomic request which did not fail
+// themselves as not applied.
+func bulkAtomicAborted(results []presenters.BulkJobResult) {
+	for i := range results {
+		if results[i].Error == "" {
+			results[i].Error = bulkAtomicAbortedError
+		}
+	}
+}
+
+// bulkJobResponse responds with the results of a bulk request, with 207 Multi
+// Status if any item failed.
+func bulkJobResponse(c *gin.Context, results []presenters.BulkJobResult, failed bool) {
+	status := http.StatusOK
+	if failed {
+		status = http.StatusMultiStatus
+	}
+	jsonAPIResponseWithStatus(c, results, "bulk job results", status)
+}
@@ -0,0 +1,104 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkJobSpecsController_Create(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	valid := models.JobSpecRequest{
+		Initiators: []models.InitiatorRequest{{Type: models.InitiatorWeb}},
+		Tasks:      []models.TaskSpecRequest{{Type: models.MustNewTaskType("noop")}},
+	}
+	invalid := models.JobSpecRequest{
+		Initiators: []models.InitiatorRequest{{Type: models.InitiatorWeb}},
+	}
+
+	body, err := json.Marshal(models.BulkJobSpecsRequest{Specs: []models.JobSpecRequest{valid, invalid}, Atomic: true})
+	require.NoError(t, err)
+	resp, cleanup := client.Post("/v2/bulk_specs", bytes.NewBuffer(body))
+	defer cleanup()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	count, err := app.Store.ORM.CountOf(&models.JobSpec{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "an atomic request should create no job if one is invalid")
+
+	body, err = json.Marshal(models.BulkJobSpecsRequest{Specs: []models.JobSpecRequest{valid, invalid, valid}})
+	require.NoError(t, err)
+	resp, cleanup = client.Post("/v2/bulk_specs", bytes.NewBuffer(body))
+	defer cleanup()
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+
+	var results []presenters.BulkJobResult
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &results))
+	require.Len(t, results, 3)
+	assert.Empty(t, results[0].Error)
+	assert.NotEmpty(t, results[1].Error)
+	assert.Empty(t, results[2].Error)
+	count, err = app.Store.ORM.CountOf(&models.JobSpec{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestBulkJobSpecsController_ArchiveAndRestore(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job1 := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job1))
+	job2 := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job2))
+	missing := models.NewID().String()
+
+	body, err := json.Marshal(models.BulkJobIDsRequest{IDs: []string{job1.ID.String(), missing}, Atomic: true})
+	require.NoError(t, err)
+	resp, cleanup := client.Post("/v2/bulk_specs/archive", bytes.NewBuffer(body))
+	defer cleanup()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_, err = app.Store.FindJob(job1.ID)
+	require.NoError(t, err, "an atomic request should archive no job if one is not found")
+
+	body, err = json.Marshal(models.BulkJobIDsRequest{IDs: []string{job1.ID.String(), job2.ID.String(), missing}})
+	require.NoError(t, err)
+	resp, cleanup = client.Post("/v2/bulk_specs/archive", bytes.NewBuffer(body))
+	defer cleanup()
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	var results []presenters.BulkJobResult
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &results))
+	require.Len(t, results, 3)
+	assert.Equal(t, "job not found", results[2].Error)
+	_, err = app.Store.FindJob(job1.ID)
+	assert.Error(t, err)
+	_, err = app.Store.FindJob(job2.ID)
+	assert.Error(t, err)
+
+	body, err = json.Marshal(models.BulkJobIDsRequest{IDs: []string{job1.ID.String(), job2.ID.String()}, Atomic: true})
+	require.NoError(t, err)
+	resp, cleanup = client.Post("/v2/bulk_specs/restore", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	_, err = app.Store.FindJob(job1.ID)
+	assert.NoError(t, err)
+	_, err = app.Store.FindJob(job2.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,41 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChainController reports on the chain the node follows.
+type ChainController struct {
+	App chainlink.Application
+}
+
+// Head returns the latest head saved by the node, and the finalized head,
+// FINALITY_DEPTH blocks behind it. Either is null if no head was saved that
+// far back.
+// Example:
+//  "<application>/chain/head"
+func (cc *ChainController) Head(c *gin.Context) {
+	store := cc.App.GetStore()
+	depth := store.Config.FinalityDepth()
+	latest, err := store.LastHead()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	finalized, err := store.FinalizedHead(depth)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, presenters.ChainHeads{
+		Latest:        presenters.NewChainHead(latest),
+		Finalized:     presenters.NewChainHead(finalized),
+		FinalityDepth: depth,
+	}, "chainHeads")
+}
@@ -0,0 +1,41 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainController_Head(t *testing.T) {
+	t.Parallel()
+
+	config, cfgCleanup := cltest.NewConfig(t)
+	defer cfgCleanup()
+	config.Set("FINALITY_DEPTH", 5)
+	app, cleanup := cltest.NewApplicationWithConfig(t, config, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	for number := 0; number <= 20; number++ {
+		require.NoError(t, app.Store.CreateHead(cltest.Head(number)))
+	}
+
+	resp, cleanup := client.Get("/v2/chain/head")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var heads presenters.ChainHeads
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &heads))
+	require.NotNil(t, heads.Latest)
+	require.NotNil(t, heads.Finalized)
+	assert.Equal(t, int64(20), heads.Latest.Number)
+	assert.Equal(t, int64(15), heads.Finalized.Number)
+	assert.Equal(t, uint64(5), heads.FinalityDepth)
+}
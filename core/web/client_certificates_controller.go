@@ -0,0 +1,91 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ClientCertificatesController manages the client certificates presented to
+// servers requiring mutual TLS.
+type ClientCertificatesController struct {
+	App chainlink.Application
+}
+
+// Index lists the client certificates, without their key material.
+func (ccc *ClientCertificatesController) Index(c *gin.Context) {
+	ccs, err := ccc.App.GetStore().ClientCertificates()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, ccs, "client certificates")
+}
+
+// Create adds a client certificate, or replaces the certificate of the same
+// name. Replaced certificates are used from the next request on.
+func (ccc *ClientCertificatesController) Create(c *gin.Context) {
+	ccr := &models.ClientCertificateRequest{}
+	if err := c.ShouldBindJSON(ccr); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err := services.ValidateClientCertificate(ccr); err != nil {
+		jsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	cc, err := models.NewClientCertificate(ccr)
+	if err != nil {
+		jsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := ccc.App.GetStore().SaveClientCertificate(cc); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	saved, err := ccc.App.GetStore().FindClientCertificate(cc.Name)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, saved, "client certificate", http.StatusCreated)
+}
+
+// Destroy removes a client certificate, unless a bridge presents it.
+func (ccc *ClientCertificatesController) Destroy(c *gin.Context) {
+	name := c.Param("Name")
+	store := ccc.App.GetStore()
+
+	if _, err := store.FindClientCertificate(name); errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("client certificate not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	bridges, err := store.BridgesUsingClientCertificate(name)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	} else if len(bridges) > 0 {
+		jsonAPIError(c, http.StatusConflict, fmt.Errorf("client certificate is used by bridges %s", strings.Join(bridges, ", ")))
+		return
+	}
+
+	if err := store.DeleteClientCertificate(name); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, nil, "client certificate", http.StatusNoContent)
+}
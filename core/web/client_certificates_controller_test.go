@@ -0,0 +1,75 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCertificatesController_CreateIndexDestroy(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	body, err := json.Marshal(cltest.NewClientCertificateRequest(t, "dataprovider"))
+	require.NoError(t, err)
+	resp, cleanup := client.Post("/v2/client_certificates", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusCreated)
+	var cc models.ClientCertificate
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &cc))
+	assert.Equal(t, "dataprovider", cc.Name)
+	assert.Equal(t, "CN=dataprovider", cc.Subject)
+
+	resp, cleanup = client.Get("/v2/client_certificates")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	b := cltest.ParseResponseBody(t, resp)
+	var ccs []models.ClientCertificate
+	require.NoError(t, web.ParseJSONAPIResponse(b, &ccs))
+	require.Len(t, ccs, 1)
+	assert.NotContains(t, string(b), "encrypted")
+
+	_, bt := cltest.NewBridgeType(t, "mtlsbridge")
+	bt.ClientCertificate = "dataprovider"
+	require.NoError(t, app.Store.CreateBridgeType(bt))
+	resp, cleanup = client.Delete("/v2/client_certificates/dataprovider")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusConflict)
+
+	require.NoError(t, app.Store.DeleteBridgeType(bt))
+	resp, cleanup = client.Delete("/v2/client_certificates/dataprovider")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNoContent)
+
+	_, err = app.Store.FindClientCertificate("dataprovider")
+	assert.Error(t, err)
+}
+
+func TestClientCertificatesController_Create_mismatchedKey(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	ccr := cltest.NewClientCertificateRequest(t, "dataprovider")
+	ccr.PrivateKey = cltest.NewClientCertificateRequest(t, "other").PrivateKey
+	body, err := json.Marshal(ccr)
+	require.NoError(t, err)
+	resp, cleanup := client.Post("/v2/client_certificates", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusBadRequest)
+}
@@ -1,14 +1,19 @@
 package web
 
 import (
+	"encoding"
 	"fmt"
 	"net/http"
 
+	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
 // ConfigController manages config variables
@@ -29,14 +34,28 @@ func (cc *ConfigController) Show(c *gin.Context) {
 	jsonAPIResponse(c, cw, "config")
 }
 
+// configPatchRequest holds the configuration variables which can be changed
+// while the node is running. Those left out are not changed.
 type configPatchRequest struct {
-	EthGasPriceDefault *utils.Big `json:"ethGasPriceDefault"`
+	EthGasPriceDefault              *utils.Big        `json:"ethGasPriceDefault"`
+	EthMaxGasPriceWei               *utils.Big        `json:"ethMaxGasPriceWei"`
+	ExternalInitiatorHealthInterval *models.Duration  `json:"externalInitiatorHealthCheckInterval"`
+	LogLevel                        *orm.LogLevel     `json:"logLevel"`
+	LogModuleLevels                 *orm.ModuleLevels `json:"logModuleLevels"`
+	SessionTimeout                  *models.Duration  `json:"sessionTimeout"`
+	StuckRunCheckInterval           *models.Duration  `json:"stuckRunCheckInterval"`
 }
 
 // ConfigPatchResponse represents the change to the configuration made due to a
 // PATCH to the config endpoint
 type ConfigPatchResponse struct {
-	EthGasPriceDefault Change `json:"ethGasPriceDefault"`
+	EthGasPriceDefault              *Change `json:"ethGasPriceDefault,omitempty"`
+	EthMaxGasPriceWei               *Change `json:"ethMaxGasPriceWei,omitempty"`
+	ExternalInitiatorHealthInterval *Change `json:"externalInitiatorHealthCheckInterval,omitempty"`
+	LogLevel                        *Change `json:"logLevel,omitempty"`
+	LogModuleLevels                 *Change `json:"logModuleLevels,omitempty"`
+	SessionTimeout                  *Change `json:"sessionTimeout,omitempty"`
+	StuckRunCheckInterval           *Change `json:"stuckRunCheckInterval,omitempty"`
 }
 
 // Change represents the old value and the new value after a PATH request has
@@ -57,24 +76,111 @@ func (*ConfigPatchResponse) SetID(string) error {
 	return nil
 }
 
-// Patch updates one or more configuration options
+// configUpdate is the change of one configuration variable by a PATCH.
+type configUpdate struct {
+	name   string
+	value  encoding.TextMarshaler
+	change **Change
+}
+
+// Patch updates one or more of the configuration variables which can be
+// changed while the node is running, and records the changes in the audit log
+// of configuration changes. The changes apply without restarting the node.
 func (cc *ConfigController) Patch(c *gin.Context) {
 	request := &configPatchRequest{}
 	if err := c.ShouldBindJSON(request); err != nil {
 		jsonAPIError(c, http.StatusUnprocessableEntity, err)
 		return
 	}
-
-	if err := cc.App.GetStore().SetConfigValue("EthGasPriceDefault", request.EthGasPriceDefault); err != nil {
-		jsonAPIError(c, http.StatusInternalServerError, fmt.Errorf("failed to set gas price default: %+v", err))
+	if err := validateConfigPatch(cc.App.GetStore().Config, request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
 		return
 	}
 
-	response := &ConfigPatchResponse{
-		EthGasPriceDefault: Change{
-			From: cc.App.GetStore().Config.EthGasPriceDefault().String(),
-			To:   request.EthGasPriceDefault.String(),
-		},
+	response := &ConfigPatchResponse{}
+	var updates []configUpdate
+	if request.EthGasPriceDefault != nil {
+		updates = append(updates, configUpdate{"EthGasPriceDefault", request.EthGasPriceDefault, &response.EthGasPriceDefault})
+	}
+	if request.EthMaxGasPriceWei != nil {
+		updates = append(updates, configUpdate{"EthMaxGasPriceWei", request.EthMaxGasPriceWei, &response.EthMaxGasPriceWei})
+	}
+	if request.ExternalInitiatorHealthInterval != nil {
+		updates = append(updates, configUpdate{"ExternalInitiatorHealthInterval", request.ExternalInitiatorHealthInterval, &response.ExternalInitiatorHealthInterval})
+	}
+	if request.LogLevel != nil {
+		updates = append(updates, configUpdate{"LogLevel", request.LogLevel, &response.LogLevel})
+	}
+	if request.LogModuleLevels != nil {
+		updates = append(updates, configUpdate{"LogModuleLevels", request.LogModuleLevels, &response.LogModuleLevels})
+	}
+	if request.SessionTimeout != nil {
+		updates = append(updates, configUpdate{"SessionTimeout", request.SessionTimeout, &response.SessionTimeout})
+	}
+	if request.StuckRunCheckInterval != nil {
+		updates = append(updates, configUpdate{"StuckRunCheckInterval", request.StuckRunCheckInterval, &response.StuckRunCheckInterval})
+	}
+
+	changedBy := "unknown"
+	if user, ok := authenticatedUser(c); ok {
+		changedBy = user.Email
+	}
+	config := cc.App.GetStore().Config
+	for _, update := range updates {
+		change, err := config.ChangeRuntimeValue(update.name, update.value, changedBy)
+		if err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, fmt.Errorf("failed to set %s: %+v", change.Name, err))
+			return
+		}
+		logger.Web.Infow("Configuration changed", "name", change.Name, "from", change.From, "to", change.To, "changedBy", changedBy)
+		*update.change = &Change{From: change.From, To: change.To}
+	}
+	if request.LogLevel != nil {
+		logger.SetLevel(request.LogLevel.Level)
+	}
+	if request.LogModuleLevels != nil {
+		logger.SetModuleLevels(*request.LogModuleLevels)
 	}
+
 	jsonAPIResponse(c, response, "config")
 }
+
+// Changes lists the changes made to the configuration while the node was
+// running, most recent first.
+// Example:
+//  "<application>/config/changes"
+func (cc *ConfigController) Changes(c *gin.Context, size, page, offset int) {
+	changes, count, err := cc.App.GetStore().ConfigurationChanges(offset, size)
+	paginatedResponse(c, "ConfigurationChanges", size, page, changes, count, err)
+}
+
+func validateConfigPatch(config *orm.Config, request *configPatchRequest) error {
+	for name, d := range map[string]*models.Duration{
+		"externalInitiatorHealthCheckInterval": request.ExternalInitiatorHealthInterval,
+		"sessionTimeout":                       request.SessionTimeout,
+		"stuckRunCheckInterval":                request.StuckRunCheckInterval,
+	} {
+		if d != nil && d.IsInstant() {
+			return fmt.Errorf("%s must be greater than zero", name)
+		}
+	}
+
+	if request.EthGasPriceDefault == nil && request.EthMaxGasPriceWei == nil {
+		return nil
+	}
+	gasPriceDefault := config.EthGasPriceDefault()
+	if request.EthGasPriceDefault != nil {
+		gasPriceDefault = request.EthGasPriceDefault.ToInt()
+	}
+	maxGasPrice := config.EthMaxGasPriceWei()
+	if request.EthMaxGasPriceWei != nil {
+		maxGasPrice = request.EthMaxGasPriceWei.ToInt()
+	}
+	if gasPriceDefault.Sign() <= 0 {
+		return errors.New("ethGasPriceDefault must be greater than zero")
+	}
+	if gasPriceDefault.Cmp(maxGasPrice) > 0 {
+		return fmt.Errorf("ethGasPriceDefault of %s exceeds ethMaxGasPriceWei of %s", gasPriceDefault, maxGasPrice)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package web_test
 
 import (
+	"bytes"
 	"math/big"
 	"net/http"
 	"testing"
@@ -8,12 +9,15 @@ import (
 
 	"github.com/smartcontractkit/chainlink/core/assets"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestConfigController_Show(t *testing.T) {
@@ -50,3 +54,55 @@ func TestConfigController_Show(t *testing.T) {
 	assert.Equal(t, (*common.Address)(nil), cwl.OracleContractAddress)
 	assert.Equal(t, time.Millisecond*500, cwl.DatabaseTimeout.Duration())
 }
+
+func TestConfigController_Patch(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+	config := app.Store.Config
+
+	body := `{"logLevel":"warn","logModuleLevels":"orm=debug","sessionTimeout":"1h","stuckRunCheckInterval":"1m"}`
+	resp, cleanup := client.Patch("/v2/config", bytes.NewBufferString(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	cpr := web.ConfigPatchResponse{}
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &cpr))
+	require.NotNil(t, cpr.LogLevel)
+	assert.Equal(t, "debug", cpr.LogLevel.From)
+	assert.Equal(t, "warn", cpr.LogLevel.To)
+	require.NotNil(t, cpr.SessionTimeout)
+	assert.Equal(t, "1h0m0s", cpr.SessionTimeout.To)
+	assert.Nil(t, cpr.EthGasPriceDefault)
+
+	assert.Equal(t, zapcore.WarnLevel, config.LogLevel().Level)
+	assert.Equal(t, orm.ModuleLevels{logger.ORM: zapcore.DebugLevel}, config.LogModuleLevels())
+	assert.Equal(t, time.Hour, config.SessionTimeout().Duration())
+	assert.Equal(t, time.Minute, config.StuckRunCheckInterval().Duration())
+
+	changes, count, err := app.Store.ConfigurationChanges(0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	for _, change := range changes {
+		assert.Equal(t, cltest.APIEmail, change.ChangedBy)
+	}
+
+	resp, cleanup = client.Get("/v2/config/changes")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	for _, invalid := range []string{
+		`{"sessionTimeout":"0s"}`,
+		`{"logModuleLevels":"nosuchmodule=debug"}`,
+		`{"ethGasPriceDefault":"600000000000"}`,
+		`{"ethGasPriceDefault":"20000000000","ethMaxGasPriceWei":"10000000000"}`,
+	} {
+		resp, cleanup = client.Patch("/v2/config", bytes.NewBufferString(invalid))
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+	}
+	assert.Equal(t, time.Hour, config.SessionTimeout().Duration())
+}
@@ -0,0 +1,80 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultCronPreviewCount = 10
+	maxCronPreviewCount     = 100
+)
+
+// CronPreviewsController previews the runs cron initiators schedule.
+type CronPreviewsController struct {
+	App chainlink.Application
+}
+
+// Create validates the cron initiator given, and returns the next count runs
+// it schedules after from, and when each is made given its calendar. count
+// defaults to 10, up to 100, and from, an RFC3339 time, to now.
+// Example:
+//  "<application>/cron_previews?count=5"
+func (cpc *CronPreviewsController) Create(c *gin.Context) {
+	count, from, err := cronPreviewParams(c)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var initr models.Initiator
+	if err := c.ShouldBindJSON(&initr); err != nil {
+		jsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+	if initr.Type == "" {
+		initr.Type = models.InitiatorCron
+	} else if initr.Type != models.InitiatorCron {
+		jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("cannot preview initiators of type %s", initr.Type))
+		return
+	}
+	if err := services.ValidateInitiator(initr, models.JobSpec{}, cpc.App.GetStore()); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	runs, err := services.PreviewCronRuns(initr, from, count)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	jsonAPIResponse(c, presenters.NewCronPreview(initr, runs), "cron preview")
+}
+
+func cronPreviewParams(c *gin.Context) (int, time.Time, error) {
+	count := defaultCronPreviewCount
+	if param := c.Query("count"); param != "" {
+		var err error
+		if count, err = strconv.Atoi(param); err != nil || count < 1 || count > maxCronPreviewCount {
+			return 0, time.Time{}, fmt.Errorf("count must be between 1 and %d", maxCronPreviewCount)
+		}
+	}
+	from := time.Now()
+	if param := c.Query("from"); param != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, param); err != nil {
+			return 0, time.Time{}, errors.Wrap(err, "invalid from")
+		}
+	}
+	return count, from, nil
+}
@@ -0,0 +1,61 @@
+package web_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronPreviewsController_Create(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	body := `{"type":"cron","params":{"schedule":"0 9 * * *","timezone":"Europe/London","calendar":{"holidays":["2020-12-25"]}}}`
+	resp, cleanup := client.Post("/v2/cron_previews?count=3&from=2020-12-23T12:00:00Z", bytes.NewBufferString(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var preview presenters.CronPreview
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &preview))
+	assert.Equal(t, "Europe/London", preview.Timezone)
+	require.Len(t, preview.Runs, 3)
+	assert.NotNil(t, preview.Runs[0].RunAt)
+	assert.Nil(t, preview.Runs[2].RunAt)
+}
+
+func TestCronPreviewsController_Create_Invalid(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	tests := []struct {
+		name string
+		url  string
+		body string
+	}{
+		{"without time zone", "/v2/cron_previews", `{"params":{"schedule":"0 9 * * *"}}`},
+		{"not a cron", "/v2/cron_previews", `{"type":"web"}`},
+		{"too many", "/v2/cron_previews?count=1000", `{"params":{"schedule":"CRON_TZ=UTC 0 9 * * *"}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, cleanup := client.Post(test.url, bytes.NewBufferString(test.body))
+			defer cleanup()
+			cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package web
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// EarningsController reports the LINK the node earned.
+type EarningsController struct {
+	App chainlink.Application
+}
+
+// Index accounts for the LINK earned by the runs which completed between
+// from and to, both RFC3339 times and defaulting to all time, per job or per
+// requester, along with the gas their transactions cost. Given ethPerLink,
+// the price of LINK in ETH, the earnings net of gas costs are reported too.
+// With format=csv, the earnings are returned as CSV rather than JSON.
+// Example:
+//  "<application>/stats/earnings?groupBy=requester&from=2020-05-01T00:00:00Z&format=csv"
+func (ec *EarningsController) Index(c *gin.Context) {
+	query, ethPerLink, err := earningsParams(c)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	earnings, err := ec.App.GetStore().Earnings(query)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if ethPerLink != nil {
+		for i := range earnings {
+			earnings[i].SetNet(*ethPerLink)
+		}
+	}
+
+	if c.Query("format") != "csv" {
+		jsonAPIResponse(c, earnings, "earnings")
+		return
+	}
+	body, err := earningsCSV(query.GroupBy, earnings, ethPerLink != nil)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="earnings.csv"`)
+	c.Data(http.StatusOK, "text/csv", body)
+}
+
+func earningsParams(c *gin.Context) (models.EarningsQuery, *decimal.Decimal, error) {
+	query := models.EarningsQuery{GroupBy: models.EarningsByJob}
+	if groupBy := c.Query("groupBy"); groupBy != "" {
+		query.GroupBy = models.EarningsGrouping(groupBy)
+	}
+	if query.GroupBy != models.EarningsByJob && query.GroupBy != models.EarningsByRequester {
+		return query, nil, fmt.Errorf("groupBy must be %s or %s", models.EarningsByJob, models.EarningsByRequester)
+	}
+
+	var err error
+	if query.From, query.To, err = statsTimeRange(c); err != nil {
+		return query, nil, err
+	}
+
+	if c.Query("ethPerLink") == "" {
+		return query, nil, nil
+	}
+	ethPerLink, err := decimal.NewFromString(c.Query("ethPerLink"))
+	if err != nil {
+		return query, nil, errors.Wrap(err, "invalid ethPerLink")
+	} else if !ethPerLink.IsPositive() {
+		return query, nil, errors.New("ethPerLink must be positive")
+	}
+	return query, &ethPerLink, nil
+}
+
+// statsTimeRange parses the from and to parameters of the stats endpoints,
+// both RFC3339 times and defaulting to all time.
+func statsTimeRange(c *gin.Context) (time.Time, time.Time, error) {
+	from, to := time.Unix(0, 0), time.Now()
+	var err error
+	if param := c.Query("from"); param != "" {
+		if from, err = time.Parse(time.RFC3339, param); err != nil {
+			return from, to, errors.Wrap(err, "invalid from")
+		}
+	}
+	if param := c.Query("to"); param != "" {
+		if to, err = time.Parse(time.RFC3339, param); err != nil {
+			return from, to, errors.Wrap(err, "invalid to")
+		}
+	}
+	if !from.Before(to) {
+		return from, to, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+func earningsCSV(groupBy models.EarningsGrouping, earnings []models.Earnings, withNet bool) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	header := []string{string(groupBy), "runs", "earned_link", "gas_cost_eth"}
+	if withNet {
+		header = append(header, "net_link")
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, e := range earnings {
+		record := []string{e.GetID(), strconv.FormatInt(e.Runs, 10), e.Earned.String(), e.GasCost.String()}
+		if withNet {
+			record = append(record, e.Net.String())
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
@@ -0,0 +1,57 @@
+package web_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEarningsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	jr := cltest.NewJobRun(job)
+	jr.TaskRuns[0].Status = models.RunStatusCompleted
+	jr.SetStatus(models.RunStatusCompleted)
+	jr.Payment = assets.NewLink(1000)
+	require.NoError(t, app.Store.CreateJobRun(&jr))
+
+	resp, cleanup := client.Get("/v2/stats/earnings?ethPerLink=0.5")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var earnings []models.Earnings
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &earnings))
+	require.Len(t, earnings, 1)
+	assert.Equal(t, job.ID, earnings[0].JobSpecID)
+	assert.Equal(t, assets.NewLink(1000), earnings[0].Earned)
+	assert.Equal(t, assets.NewLink(1000), earnings[0].Net)
+
+	resp, cleanup = client.Get("/v2/stats/earnings?format=csv")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "job,runs,earned_link,gas_cost_eth\n"+
+		job.ID.String()+",1,0.000000000000001000,0.000000000000000000\n", string(body))
+
+	for _, query := range []string{"groupBy=task", "from=yesterday", "ethPerLink=0", "from=2020-05-02T00:00:00Z&to=2020-05-01T00:00:00Z"} {
+		resp, cleanup = client.Get("/v2/stats/earnings?" + query)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+	}
+}
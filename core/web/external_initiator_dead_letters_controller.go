@@ -0,0 +1,60 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ExternalInitiatorDeadLettersController manages the notifications to
+// external initiators which ran out of attempts to be delivered.
+type ExternalInitiatorDeadLettersController struct {
+	App chainlink.Application
+}
+
+// Index lists the dead-lettered notifications, most recent first.
+// Example:
+//  "<application>/external_initiator_dead_letters"
+func (dlc *ExternalInitiatorDeadLettersController) Index(c *gin.Context) {
+	notifications, err := dlc.App.GetStore().DeadExternalInitiatorNotifications()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, notifications, "external initiator notifications")
+}
+
+// Retry tries to deliver a dead-lettered notification again, with all its
+// attempts. If it cannot be delivered right away, it is retried as any other
+// notification, and returned with the error it failed with.
+// Example:
+//  "<application>/external_initiator_dead_letters/:ID/retry"
+func (dlc *ExternalInitiatorDeadLettersController) Retry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("ID"), 10, 64)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	store := dlc.App.GetStore()
+	notification, err := store.RetryExternalInitiatorNotification(id, store.Clock.Now())
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("dead-lettered notification not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := services.DeliverExternalInitiatorNotification(store, &notification); err != nil {
+		jsonAPIResponse(c, notification, "external initiator notification")
+		return
+	}
+	jsonAPIResponseWithStatus(c, nil, "external initiator notification", http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package web_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalInitiatorDeadLettersController_IndexAndRetry(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	store := app.Store
+
+	delivered := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer server.Close()
+
+	url := cltest.WebURL(t, server.URL)
+	ei, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: "bitcoin", URL: &url})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateExternalInitiator(ei))
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, store.CreateJob(&job))
+
+	deadAt := time.Now()
+	notification := &models.ExternalInitiatorNotification{
+		ExternalInitiatorName: "bitcoin",
+		JobSpecID:             job.ID,
+		Body:                  cltest.JSONFromString(t, `{"jobId":"abc"}`),
+		Attempts:              8,
+		NextAttemptAt:         deadAt,
+		DeadAt:                &deadAt,
+	}
+	require.NoError(t, store.CreateExternalInitiatorNotification(notification))
+
+	client := app.NewHTTPClient()
+	resp, cleanup := client.Get("/v2/external_initiator_dead_letters")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var dead []models.ExternalInitiatorNotification
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &dead))
+	require.Len(t, dead, 1)
+	assert.Equal(t, notification.ID, dead[0].ID)
+	assert.Equal(t, uint(8), dead[0].Attempts)
+
+	path := fmt.Sprintf("/v2/external_initiator_dead_letters/%d/retry", notification.ID)
+	resp, cleanup = client.Post(path, nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNoContent)
+	<-delivered
+
+	resp, cleanup = client.Post(path, nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
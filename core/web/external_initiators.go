@@ -1,11 +1,10 @@
 package web
 
 import (
-	"bytes"
 	"encoding/json"
-	"fmt"
-	"net/http"
 
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 
@@ -31,23 +30,9 @@ func NewJobSpecNotice(initiator models.Initiator, js models.JobSpec) (*JobSpecNo
 	}, nil
 }
 
-func newNotifyHTTPRequest(jsn JobSpecNotice, ei models.ExternalInitiator) (*http.Request, error) {
-	buf, err := json.Marshal(jsn)
-	if err != nil {
-		return nil, errors.Wrap(err, "new Job Spec notification")
-	}
-	req, err := http.NewRequest(http.MethodPost, ei.URL.String(), bytes.NewBuffer(buf))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set(ExternalInitiatorAccessKeyHeader, ei.OutgoingToken)
-	req.Header.Set(ExternalInitiatorSecretHeader, ei.OutgoingSecret)
-	return req, nil
-}
-
-// NotifyExternalInitiator sends a POST notification to the External Initiator
-// responsible for initiating the Job Spec.
+// NotifyExternalInitiator notifies the External Initiator responsible for
+// initiating the Job Spec. The notification is saved before it is sent, so
+// that it is retried if it cannot be delivered.
 func NotifyExternalInitiator(
 	js models.JobSpec,
 	store *store.Store,
@@ -72,17 +57,26 @@ func NotifyExternalInitiator(
 	if err != nil {
 		return errors.Wrap(err, "new Job Spec notification")
 	}
-
-	req, err := newNotifyHTTPRequest(*notice, ei)
+	b, err := json.Marshal(notice)
 	if err != nil {
-		return errors.Wrap(err, "creating notify HTTP request")
+		return errors.Wrap(err, "new Job Spec notification")
 	}
-	resp, err := http.DefaultClient.Do(req)
+	body, err := models.ParseJSON(b)
 	if err != nil {
-		return errors.Wrap(err, "could not notify '%s' (%s)")
+		return errors.Wrap(err, "new Job Spec notification")
+	}
+
+	notification := &models.ExternalInitiatorNotification{
+		ExternalInitiatorName: ei.Name,
+		JobSpecID:             js.ID,
+		Body:                  body,
+		NextAttemptAt:         store.Clock.Now(),
+	}
+	if err := store.CreateExternalInitiatorNotification(notification); err != nil {
+		return errors.Wrap(err, "saving Job Spec notification")
 	}
-	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
-		return fmt.Errorf(" notify '%s' (%s) received bad response '%s'", ei.Name, ei.URL, resp.Status)
+	if err := services.DeliverExternalInitiatorNotification(store, notification); err != nil {
+		logger.Web.Warnw("Failed to notify external initiator, will retry", "name", ei.Name, "job_id", js.ID.String(), "error", err)
 	}
 	return nil
 }
@@ -19,6 +19,56 @@ type ExternalInitiatorsController struct {
 	App chainlink.Application
 }
 
+// recentDeliveryFailures is how many of the most recent failures to deliver
+// to an external initiator are shown with it.
+const recentDeliveryFailures = 10
+
+// Index lists the external initiators, with their health and recent delivery
+// failures.
+// Example:
+//  "<application>/external_initiators"
+func (eic *ExternalInitiatorsController) Index(c *gin.Context) {
+	store := eic.App.GetStore()
+	eis, err := store.ExternalInitiatorsWithDeliveryFailures()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	presented := make([]presenters.ExternalInitiator, len(eis))
+	for i, ei := range eis {
+		failures := ei.DeliveryFailures
+		if len(failures) > recentDeliveryFailures {
+			failures = failures[:recentDeliveryFailures]
+		}
+		presented[i] = presenters.NewExternalInitiator(ei, failures)
+	}
+	jsonAPIResponse(c, presented, "external initiators")
+}
+
+// Show returns an external initiator, with its health and recent delivery
+// failures.
+// Example:
+//  "<application>/external_initiators/:Name"
+func (eic *ExternalInitiatorsController) Show(c *gin.Context) {
+	store := eic.App.GetStore()
+	ei, err := store.FindExternalInitiatorByName(c.Param("Name"))
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("external initiator not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	failures, err := store.ExternalInitiatorDeliveryFailures(ei.Name, recentDeliveryFailures)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, presenters.NewExternalInitiator(ei, failures), "external initiator")
+}
+
 // Create builds and saves a new service agreement record.
 func (eic *ExternalInitiatorsController) Create(c *gin.Context) {
 	eir := &models.ExternalInitiatorRequest{}
@@ -2,12 +2,16 @@ package web_test
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/smartcontractkit/chainlink/core/auth"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -131,3 +135,61 @@ func TestExternalInitiatorsController_DeleteNotFound(t *testing.T) {
 		assert.Equal(t, http.StatusText(http.StatusNotFound), http.StatusText(resp.StatusCode))
 	}
 }
+
+func TestExternalInitiatorsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	store := app.Store
+
+	url := cltest.WebURL(t, "http://localhost:8888")
+	for _, name := range []string{"bitcoin", "litecoin"} {
+		ei, err := models.NewExternalInitiator(auth.NewToken(), &models.ExternalInitiatorRequest{Name: name, URL: &url})
+		require.NoError(t, err)
+		require.NoError(t, store.CreateExternalInitiator(ei))
+	}
+	seenAt := time.Now().Truncate(time.Second)
+	require.NoError(t, store.MarkExternalInitiatorSeen("bitcoin", seenAt))
+	require.NoError(t, store.SaveExternalInitiatorHealthCheck("litecoin", seenAt, errors.New("connection refused")))
+	for _, message := range []string{"first", "second"} {
+		require.NoError(t, store.CreateExternalInitiatorDeliveryFailure(&models.ExternalInitiatorDeliveryFailure{
+			ExternalInitiatorName: "litecoin",
+			Error:                 message,
+		}))
+	}
+
+	client := app.NewHTTPClient()
+	resp, cleanup := client.Get("/v2/external_initiators")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var eis []presenters.ExternalInitiator
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &eis))
+	require.Len(t, eis, 2)
+
+	assert.Equal(t, "bitcoin", eis[0].Name)
+	require.NotNil(t, eis[0].LastSeenAt)
+	assert.True(t, seenAt.Equal(*eis[0].LastSeenAt))
+	assert.True(t, eis[0].Healthy)
+	assert.Empty(t, eis[0].RecentDeliveryFailures)
+
+	assert.Equal(t, "litecoin", eis[1].Name)
+	assert.Nil(t, eis[1].LastSeenAt)
+	assert.False(t, eis[1].Healthy)
+	assert.Equal(t, "connection refused", eis[1].HealthCheckError)
+	require.Len(t, eis[1].RecentDeliveryFailures, 2)
+	assert.Equal(t, "second", eis[1].RecentDeliveryFailures[0].Error)
+
+	resp, cleanup = client.Get("/v2/external_initiators/litecoin")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var ei presenters.ExternalInitiator
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &ei))
+	assert.Equal(t, "litecoin", ei.Name)
+	assert.Len(t, ei.RecentDeliveryFailures, 2)
+
+	resp, cleanup = client.Get("/v2/external_initiators/dogecoin")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
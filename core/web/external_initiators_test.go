@@ -112,11 +112,11 @@ func TestNotifyExternalInitiator_Notified(t *testing.T) {
 			err = web.NotifyExternalInitiator(test.JobSpec, store)
 			require.NoError(t, err)
 			assert.Equal(t,
-				ei.OutgoingToken,
+				ei.OutgoingToken.String(),
 				exInitr.Header.Get(web.ExternalInitiatorAccessKeyHeader),
 			)
 			assert.Equal(t,
-				ei.OutgoingSecret,
+				ei.OutgoingSecret.String(),
 				exInitr.Header.Get(web.ExternalInitiatorSecretHeader),
 			)
 			test.JobSpecNotice.JobID = test.JobSpec.ID
@@ -0,0 +1,26 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedHealthsController shows the health of the feeds polled by flux
+// monitors.
+type FeedHealthsController struct {
+	App chainlink.Application
+}
+
+// Index lists the error rate, latency and quarantine state of every feed
+// which has been polled.
+func (fhc *FeedHealthsController) Index(c *gin.Context) {
+	healths, err := fhc.App.GetStore().FeedHealths()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, healths, "feed healths")
+}
@@ -0,0 +1,40 @@
+package web_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedHealthsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	require.NoError(t, app.Store.RecordFeedSuccess("https://example.com/price", time.Second))
+	require.NoError(t, app.Store.RecordFeedFailure("https://example.com/down", time.Second, errors.New("timeout"), 1))
+
+	resp, cleanup := client.Get("/v2/feed_healths")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	b := cltest.ParseResponseBody(t, resp)
+	var healths []models.FeedHealth
+	require.NoError(t, web.ParseJSONAPIResponse(b, &healths))
+	require.Len(t, healths, 2)
+	assert.Equal(t, "https://example.com/down", healths[0].Feed)
+	assert.True(t, healths[0].Quarantined())
+	assert.Equal(t, "https://example.com/price", healths[1].Feed)
+	assert.Equal(t, uint64(1), healths[1].Successes)
+	assert.Contains(t, string(b), `"errorRate":1`)
+}
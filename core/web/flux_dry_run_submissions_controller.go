@@ -0,0 +1,31 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FluxDryRunSubmissionsController shows the answers flux monitors in dry run
+// mode would have submitted.
+type FluxDryRunSubmissionsController struct {
+	App chainlink.Application
+}
+
+// Index returns the answers the flux monitor of a JobSpec would have
+// submitted in dry run mode, most recent first.
+// Example:
+//  "<application>/specs/:SpecID/dry_run_submissions?size=1&page=2"
+func (fdrsc *FluxDryRunSubmissionsController) Index(c *gin.Context, size, page, offset int) {
+	id, err := models.NewIDFromString(c.Param("SpecID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	submissions, count, err := fdrsc.App.GetStore().FluxDryRunSubmissionsFor(id, offset, size)
+	paginatedResponse(c, "FluxDryRunSubmissions", size, page, submissions, count, err)
+}
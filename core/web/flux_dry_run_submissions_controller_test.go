@@ -0,0 +1,56 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFluxDryRunSubmissionsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithFluxMonitorInitiator()
+	job.Initiators[0].DryRun = true
+	require.NoError(t, app.Store.CreateJob(&job))
+
+	for round := uint64(1); round <= 3; round++ {
+		require.NoError(t, app.Store.CreateFluxDryRunSubmission(&models.FluxDryRunSubmission{
+			JobSpecID:    job.ID,
+			Aggregator:   job.Initiators[0].Address,
+			RoundID:      round,
+			LatestAnswer: decimal.NewFromInt(100),
+			Answer:       decimal.NewFromInt(101),
+			Reason:       "deviation",
+		}))
+	}
+
+	resp, cleanup := client.Get("/v2/specs/" + job.ID.String() + "/dry_run_submissions?size=2")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var links jsonapi.Links
+	var submissions []models.FluxDryRunSubmission
+	err := web.ParsePaginatedResponse(cltest.ParseResponseBody(t, resp), &submissions, &links)
+	require.NoError(t, err)
+	require.Len(t, submissions, 2)
+	assert.NotEmpty(t, links["next"].Href)
+	assert.Equal(t, uint64(3), submissions[0].RoundID)
+	assert.Equal(t, "101", submissions[0].Answer.String())
+
+	resp, cleanup = client.Get("/v2/specs/garbage/dry_run_submissions")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+}
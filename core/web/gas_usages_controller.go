@@ -0,0 +1,44 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GasUsagesController reports the ETH the node spent on gas.
+type GasUsagesController struct {
+	App chainlink.Application
+}
+
+// Index accounts for the gas used by the transactions which became safe
+// between from and to, both RFC3339 times and defaulting to all time, and
+// for the ETH it cost, per job or per run.
+// Example:
+//  "<application>/stats/gas_usage?groupBy=run&from=2020-05-01T00:00:00Z"
+func (gc *GasUsagesController) Index(c *gin.Context) {
+	query := models.GasUsageQuery{GroupBy: models.GasUsageByJob}
+	if groupBy := c.Query("groupBy"); groupBy != "" {
+		query.GroupBy = models.GasUsageGrouping(groupBy)
+	}
+	if query.GroupBy != models.GasUsageByJob && query.GroupBy != models.GasUsageByRun {
+		jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("groupBy must be %s or %s", models.GasUsageByJob, models.GasUsageByRun))
+		return
+	}
+	var err error
+	if query.From, query.To, err = statsTimeRange(c); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	usages, err := gc.App.GetStore().GasUsage(query)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, usages, "gas_usages")
+}
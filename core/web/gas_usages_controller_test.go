@@ -0,0 +1,49 @@
+package web_test
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/eth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasUsagesController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	tx := cltest.CreateTxWithNonceAndGasPrice(t, app.Store, cltest.GetAccountAddress(t, app.Store), 1, 0, 20)
+	gasUsed := hexutil.Uint64(50000)
+	tx.Attempts[0].RecordGasUsed(&eth.TxReceipt{GasUsed: &gasUsed})
+	require.NoError(t, app.Store.MarkTxSafe(tx, tx.Attempts[0]))
+	assert.Equal(t, utils.NewBig(big.NewInt(20)), tx.Attempts[0].EffectiveGasPrice, "the gas price of the attempt should be used without an effective gas price")
+
+	resp, cleanup := client.Get("/v2/stats/gas_usage")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var usages []models.GasUsage
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &usages))
+	require.Len(t, usages, 1)
+	assert.Equal(t, int64(1), usages[0].Transactions)
+	assert.Equal(t, uint64(50000), usages[0].GasUsed)
+	assert.Equal(t, assets.NewEth(50000*20), usages[0].Cost)
+
+	for _, query := range []string{"groupBy=requester", "to=tomorrow", "from=2020-05-02T00:00:00Z&to=2020-05-01T00:00:00Z"} {
+		resp, cleanup = client.Get("/v2/stats/gas_usage?" + query)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+	}
+}
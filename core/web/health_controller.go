@@ -0,0 +1,45 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthController has the liveness and readiness endpoints, which need no
+// authentication so that they can be probed by orchestrators like Kubernetes.
+type HealthController struct {
+	App chainlink.Application
+}
+
+// Liveness returns 200 as long as the node is serving requests.
+// Example:
+//  "<application>/health"
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness probes the database, the advisory lock, the freshness of the
+// heads from the Ethereum node and the keystore, and returns the status of
+// each, with 200 if they are all healthy or 503 otherwise. The status is
+// degraded rather than unavailable while the node is reconnecting to the
+// database.
+// Example:
+//  "<application>/readiness"
+func (hc *HealthController) Readiness(c *gin.Context) {
+	components, ready := services.CheckReadiness(hc.App.GetStore())
+	if !ready {
+		status := "unavailable"
+		for _, component := range components {
+			if component.Degraded {
+				status = "degraded"
+			}
+		}
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": status, "components": components})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "components": components})
+}
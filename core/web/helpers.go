@@ -56,6 +56,49 @@ func paginatedResponse(
 	}
 }
 
+// snapshotPaginatedResponse is paginatedResponse for records read from a
+// snapshot, whose token the response passes on for the later pages.
+func snapshotPaginatedResponse(
+	c *gin.Context,
+	name string,
+	size int,
+	page int,
+	resource interface{},
+	count int,
+	snapshot orm.Snapshot,
+	err error,
+) {
+	if errors.Cause(err) == orm.ErrorNotFound {
+		err = nil
+	}
+
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, fmt.Errorf("error getting paged %s: %+v", name, err))
+	} else if buffer, err := NewSnapshotPaginatedResponse(*c.Request.URL, size, page, count, snapshot, resource); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, fmt.Errorf("failed to marshal document: %+v", err))
+	} else {
+		c.Data(http.StatusOK, MediaType, buffer)
+	}
+}
+
+// snapshotRequested returns whether a paginated request asks to be read from
+// a snapshot, with snapshot=true for a new one, or with the token of that of
+// an earlier page, which is returned.
+func snapshotRequested(c *gin.Context) (bool, *orm.Snapshot, error) {
+	token := c.Query(KeySnapshot)
+	switch token {
+	case "", "false":
+		return false, nil, nil
+	case "true":
+		return true, nil, nil
+	}
+	snapshot, err := orm.ParseSnapshot(token)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, &snapshot, nil
+}
+
 func paginatedRequest(action func(*gin.Context, int, int, int)) func(*gin.Context) {
 	return func(c *gin.Context) {
 		size, page, offset, err := ParsePaginatedRequest(c.Query("size"), c.Query("page"))
@@ -0,0 +1,185 @@
+package web
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// The endpoints requests are refused from outside the allowlists of, as
+// recorded in the audit log of access violations.
+const (
+	accessEndpointAPI               = "api"
+	accessEndpointExternalInitiator = "external_initiator"
+	accessEndpointMetrics           = "metrics"
+)
+
+// externalInitiatorOnlyKey marks the requests from outside the operator
+// API's allowlist let through to the routes of external initiators, which
+// are refused unless made by an external initiator.
+const externalInitiatorOnlyKey = "ip_external_initiator_only"
+
+// externalInitiatorRoutes are the routes external initiators make requests
+// to, by method and path.
+var externalInitiatorRoutes = map[string]bool{
+	"POST /v2/specs/:SpecID/runs": true,
+	"GET /v2/ping":                true,
+}
+
+// ipAllowlist is the networks allowed to make requests to an endpoint,
+// allowing every address if it is empty.
+type ipAllowlist []*net.IPNet
+
+// newIPAllowlist parses the networks of an allowlist, written in CIDR
+// notation, or as single addresses.
+func newIPAllowlist(entries []string) (ipAllowlist, error) {
+	var allowlist ipAllowlist
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing allowed CIDR %s", entry)
+			}
+			allowlist = append(allowlist, network)
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid allowed address %s", entry)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		allowlist = append(allowlist, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return allowlist, nil
+}
+
+func (al ipAllowlist) allows(ip net.IP) bool {
+	if len(al) == 0 {
+		return true
+	}
+	for _, network := range al {
+		if ip != nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipAllowlists are the allowlists of the operator API, the routes of
+// external initiators and the metrics, from API_ALLOWED_CIDRS,
+// EXTERNAL_INITIATOR_ALLOWED_CIDRS and METRICS_ALLOWED_CIDRS.
+type ipAllowlists struct {
+	api               ipAllowlist
+	externalInitiator ipAllowlist
+	metrics           ipAllowlist
+}
+
+func newIPAllowlists(app chainlink.Application) (ipAllowlists, error) {
+	config := app.GetStore().Config
+	var lists ipAllowlists
+	var err error
+	if lists.api, err = newIPAllowlist(config.APIAllowedCIDRs()); err != nil {
+		return lists, errors.Wrap(err, "API_ALLOWED_CIDRS")
+	}
+	if lists.externalInitiator, err = newIPAllowlist(config.ExternalInitiatorAllowedCIDRs()); err != nil {
+		return lists, errors.Wrap(err, "EXTERNAL_INITIATOR_ALLOWED_CIDRS")
+	}
+	if lists.metrics, err = newIPAllowlist(config.MetricsAllowedCIDRs()); err != nil {
+		return lists, errors.Wrap(err, "METRICS_ALLOWED_CIDRS")
+	}
+	return lists, nil
+}
+
+// remoteIP returns the address the request was made from. Forwarding headers
+// are ignored, as anyone can set them.
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// requireAllowedAPIIP refuses the requests to the operator API from outside
+// its allowlist. Requests to the routes of external initiators carrying their
+// access key are let through to be authenticated, and then checked by
+// requireAllowedExternalInitiatorIP.
+func requireAllowedAPIIP(app chainlink.Application, allowlists ipAllowlists) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if allowlists.api.allows(remoteIP(c.Request)) {
+			c.Next()
+			return
+		}
+		route := c.Request.Method + " " + c.FullPath()
+		if externalInitiatorRoutes[route] && c.GetHeader(ExternalInitiatorAccessKeyHeader) != "" {
+			c.Set(externalInitiatorOnlyKey, true)
+			c.Next()
+			return
+		}
+		refuseAccess(app, c, accessEndpointAPI)
+	}
+}
+
+// requireAllowedExternalInitiatorIP refuses the requests of external
+// initiators from outside their allowlist, and the requests let through by
+// requireAllowedAPIIP which were not made by an external initiator. It runs
+// after the request is authenticated.
+func requireAllowedExternalInitiatorIP(app chainlink.Application, allowlists ipAllowlists) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := authenticatedEI(c); ok {
+			if !allowlists.externalInitiator.allows(remoteIP(c.Request)) {
+				refuseAccess(app, c, accessEndpointExternalInitiator)
+				return
+			}
+		} else if c.GetBool(externalInitiatorOnlyKey) {
+			refuseAccess(app, c, accessEndpointAPI)
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireAllowedMetricsIP refuses the requests for metrics from outside
+// their allowlist.
+func requireAllowedMetricsIP(app chainlink.Application, allowlists ipAllowlists) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !allowlists.metrics.allows(remoteIP(c.Request)) {
+			refuseAccess(app, c, accessEndpointMetrics)
+			return
+		}
+		c.Next()
+	}
+}
+
+// refuseAccess responds 403 to a request from outside the allowlist of the
+// endpoint, recording it in the audit log of access violations, which the
+// reaper is woken to trim.
+func refuseAccess(app chainlink.Application, c *gin.Context, endpoint string) {
+	violation := models.AccessViolation{
+		Endpoint: endpoint,
+		RemoteIP: remoteIP(c.Request).String(),
+		Method:   c.Request.Method,
+		Path:     c.Request.URL.Path,
+	}
+	logger.Web.Warnw("Refusing request from outside the allowed networks",
+		"endpoint", violation.Endpoint,
+		"remoteIP", violation.RemoteIP,
+		"method", violation.Method,
+		"path", violation.Path,
+	)
+	logger.Web.ErrorIf(app.GetStore().RecordAccessViolation(violation), "failed to record access violation")
+	app.WakeSessionReaper()
+	jsonAPIError(c, http.StatusForbidden, errors.New("Forbidden"))
+	c.Abort()
+}
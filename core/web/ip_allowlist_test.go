@@ -0,0 +1,71 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAllowlists(t *testing.T) {
+	t.Parallel()
+
+	config, cfgCleanup := cltest.NewConfig(t)
+	defer cfgCleanup()
+	config.Set("API_ALLOWED_CIDRS", "10.0.0.0/8")
+	config.Set("EXTERNAL_INITIATOR_ALLOWED_CIDRS", "127.0.0.1")
+	config.Set("METRICS_ALLOWED_CIDRS", "10.0.0.0/8,192.168.0.1")
+	app, cleanup := cltest.NewApplicationWithConfigAndKey(t, config, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	ts := httptest.NewServer(web.Router(app))
+	defer ts.Close()
+
+	eia := auth.NewToken()
+	ei, err := models.NewExternalInitiator(eia, &models.ExternalInitiatorRequest{Name: "bitcoin"})
+	require.NoError(t, err)
+	require.NoError(t, app.Store.CreateExternalInitiator(ei))
+
+	get := func(path string, headers map[string]string) int {
+		request, err := http.NewRequest("GET", ts.URL+path, nil)
+		require.NoError(t, err)
+		request.AddCookie(cltest.MustGenerateSessionCookie(cltest.APISessionID))
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		resp, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusForbidden, get("/v2/specs", nil), "operator API should be refused")
+	assert.Equal(t, http.StatusForbidden, get("/metrics", nil), "metrics should be refused")
+	assert.Equal(t, http.StatusOK, get("/v2/ping", map[string]string{
+		web.ExternalInitiatorAccessKeyHeader: eia.AccessKey,
+		web.ExternalInitiatorSecretHeader:    eia.Secret,
+	}), "external initiator should be allowed")
+	assert.Equal(t, http.StatusForbidden, get("/v2/ping", map[string]string{
+		web.ExternalInitiatorAccessKeyHeader: eia.AccessKey,
+	}), "session pretending to be an external initiator should be refused")
+
+	// The violations of an endpoint from the same address are counted in
+	// one row
+	violations, count, err := app.Store.AccessViolations(0, 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	assert.Equal(t, "metrics", violations[0].Endpoint)
+	assert.Equal(t, uint64(1), violations[0].Count)
+	assert.Equal(t, "api", violations[1].Endpoint)
+	assert.Equal(t, "127.0.0.1", violations[1].RemoteIP)
+	assert.Equal(t, "/v2/ping", violations[1].Path)
+	assert.Equal(t, uint64(2), violations[1].Count)
+}
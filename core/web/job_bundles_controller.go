@@ -0,0 +1,97 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// JobBundlesController moves jobs between nodes, as bundles of their
+// definitions along with the bridges, external initiators and secrets they
+// depend on.
+type JobBundlesController struct {
+	App chainlink.Application
+}
+
+// Export returns the bundle of a job, without the credentials of its
+// dependencies.
+// Example:
+//  "<application>/specs/:SpecID/bundle"
+func (jbc *JobBundlesController) Export(c *gin.Context) {
+	jsc := JobSpecsController{jbc.App}
+	job, ok := jsc.findJob(c)
+	if !ok {
+		return
+	}
+
+	bundle, err := services.NewJobBundle(job, jbc.App.GetStore())
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, bundle)
+}
+
+// Import validates a bundle exported from another node, replacing the
+// variables its job references with those it gives, then creates its job
+// along with the dependencies the node is missing, all of them or none, the
+// job being validated against them before they are committed. The
+// credentials of the bridges and external initiators created are returned.
+// Example:
+//  "<application>/job_bundles/import"
+func (jbc *JobBundlesController) Import(c *gin.Context) {
+	var bundle models.JobBundle
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	var err error
+	if bundle.Job.Namespace, err = namespaceFor(c, bundle.Job.Namespace); err != nil {
+		jsonAPIError(c, http.StatusForbidden, err)
+		return
+	}
+	for i := range bundle.Bridges {
+		if bundle.Bridges[i].Namespace, err = namespaceFor(c, bundle.Bridges[i].Namespace); err != nil {
+			jsonAPIError(c, http.StatusForbidden, err)
+			return
+		}
+	}
+
+	imported, err := services.PrepareJobBundleImport(bundle, jbc.App.GetStore())
+	if err != nil {
+		jsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+	jsc := JobSpecsController{jbc.App}
+	if err := jsc.requireImplemented(imported.Job); err != nil {
+		jsonAPIError(c, http.StatusNotImplemented, err)
+		return
+	}
+
+	err = jbc.App.AddJobWithDependencies(imported.Job, imported.Dependencies)
+	if _, invalid := errors.Cause(err).(*models.JSONAPIErrors); invalid {
+		jsonAPIError(c, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := NotifyExternalInitiator(imported.Job, jbc.App.GetStore()); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := presenters.NewImportedJobBundle(
+		imported.Job,
+		imported.Dependencies,
+		imported.BridgeAuthentications,
+		imported.ExternalInitiatorTokens,
+	)
+	jsonAPIResponseWithStatus(c, resp, "imported job bundle", http.StatusCreated)
+}
@@ -0,0 +1,113 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestJobBundlesController_ExportImport(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	_, bt := cltest.NewBridgeType(t, "exporter")
+	require.NoError(t, app.Store.CreateBridgeType(bt))
+	secret, err := models.NewSecret(models.SecretRequest{Name: "apikey", Value: "hunter2"})
+	require.NoError(t, err)
+	require.NoError(t, app.Store.SetSecret(&secret))
+
+	job := cltest.NewJobWithWebInitiator()
+	job.Tasks = []models.TaskSpec{{
+		Type:   bt.Name,
+		Params: cltest.JSONFromString(t, `{"key":"{{secret \"apikey\"}}"}`),
+	}}
+	require.NoError(t, app.AddJob(job))
+
+	resp, cleanup := client.Get("/v2/specs/" + job.ID.String() + "/bundle")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var bundle models.JobBundle
+	require.NoError(t, json.Unmarshal(cltest.ParseResponseBody(t, resp), &bundle))
+	require.Len(t, bundle.Bridges, 1)
+	assert.Equal(t, bt.Name, bundle.Bridges[0].Name)
+	assert.Nil(t, bundle.Bridges[0].Auth)
+	assert.Equal(t, []models.SecretRequest{{Name: "apikey"}}, bundle.Secrets)
+
+	importBundle := func(bundle models.JobBundle, status int) gjson.Result {
+		body, err := json.Marshal(bundle)
+		require.NoError(t, err)
+		resp, cleanup := client.Post("/v2/job_bundles/import", bytes.NewBuffer(body))
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, status)
+		return gjson.GetBytes(cltest.ParseResponseBody(t, resp), "data.attributes")
+	}
+
+	// The dependencies of the job are already on the node
+	imported := importBundle(bundle, http.StatusCreated)
+	assert.Len(t, imported.Get("bridges").Array(), 0)
+	assert.Len(t, imported.Get("secrets").Array(), 0)
+
+	// The missing bridge is created, but the missing secret needs a value
+	bundle.Bridges[0].Name = models.MustNewTaskType("importer")
+	bundle.Job.Tasks[0].Type = bundle.Bridges[0].Name
+	bundle.Job.Tasks[0].Params = cltest.JSONFromString(t, `{"key":"{{secret \"newkey\"}}"}`)
+	importBundle(bundle, http.StatusBadRequest)
+	_, err = app.Store.FindBridge(bundle.Bridges[0].Name)
+	assert.Error(t, err)
+
+	bundle.Secrets = []models.SecretRequest{{Name: "newkey", Value: "hunter3"}}
+	imported = importBundle(bundle, http.StatusCreated)
+	require.Len(t, imported.Get("bridges").Array(), 1)
+	assert.Equal(t, "importer", imported.Get("bridges.0.name").String())
+	assert.NotEmpty(t, imported.Get("bridges.0.incomingToken").String())
+	assert.Equal(t, "newkey", imported.Get("secrets.0").String())
+
+	_, err = app.Store.FindBridge(bundle.Bridges[0].Name)
+	assert.NoError(t, err)
+	value, err := app.Store.SecretValue("", "newkey")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter3", value)
+
+	// Bridges must be on the node or in the bundle
+	bundle.Bridges = nil
+	bundle.Job.Tasks[0].Type = models.MustNewTaskType("unknownbridge")
+	importBundle(bundle, http.StatusBadRequest)
+
+	// Bridges the node has are in their namespace on the node, whatever the
+	// bundle says
+	_, other := cltest.NewBridgeType(t, "othernamespace")
+	other.Namespace = "team-a"
+	require.NoError(t, app.Store.CreateBridgeType(other))
+	bundle.Bridges = []models.BridgeTypeRequest{{Name: other.Name, URL: other.URL}}
+	bundle.Job.Tasks[0].Type = other.Name
+	importBundle(bundle, http.StatusBadRequest)
+
+	// Variables are replaced by the values the bundle gives
+	bundle.Bridges = nil
+	bundle.Job.Tasks[0].Type = bt.Name
+	bundle.Job.Tasks[0].Params = cltest.JSONFromString(t, `{"endpoint":"{{variable \"endpoint\"}}/price"}`)
+	importBundle(bundle, http.StatusBadRequest)
+	bundle.Variables = map[string]string{"endpoint": "https://prod.example.com"}
+	imported = importBundle(bundle, http.StatusCreated)
+	importedID, err := models.NewIDFromString(imported.Get("job.id").String())
+	require.NoError(t, err)
+	importedJob, err := app.Store.FindJob(importedID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com/price", importedJob.Tasks[0].Params.Get("endpoint").String())
+
+	count, err := app.Store.CountOf(&models.JobSpec{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
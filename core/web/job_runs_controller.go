@@ -5,6 +5,9 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/services/chainlink"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
@@ -13,6 +16,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	null "gopkg.in/guregu/null.v3"
 )
 
 // JobRunsController manages JobRun requests in the node.
@@ -20,7 +25,9 @@ type JobRunsController struct {
 	App chainlink.Application
 }
 
-// Index returns paginated JobRuns for a given JobSpec
+// Index returns paginated JobRuns for a given JobSpec. With snapshot=true,
+// the count and the page are read from one snapshot, whose token is passed
+// as snapshot for the later pages.
 // Example:
 //  "<application>/runs?jobSpecId=:jobSpecId&size=1&page=2"
 func (jrc *JobRunsController) Index(c *gin.Context, size, page, offset int) {
@@ -31,23 +38,44 @@ func (jrc *JobRunsController) Index(c *gin.Context, size, page, offset int) {
 		order = orm.Descending
 	}
 
-	store := jrc.App.GetStore()
-	var runs []models.JobRun
-	var count int
-	var err error
-	if id == "" {
-		runs, count, err = store.JobRunsSorted(order, offset, size)
-	} else {
-		var runID *models.ID
+	consistent, snapshot, err := snapshotRequested(c)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var runID *models.ID
+	if id != "" {
 		runID, err = models.NewIDFromString(id)
 		if err != nil {
 			jsonAPIError(c, http.StatusUnprocessableEntity, err)
 			return
 		}
+	}
 
+	store := jrc.App.GetStore()
+	var runs []models.JobRun
+	var count int
+	var taken orm.Snapshot
+	if consistent {
+		runs, count, taken, err = store.JobRunsSortedAsOf(snapshot, runID, order, offset, size)
+	} else if runID == nil {
+		runs, count, err = store.JobRunsSorted(order, offset, size)
+	} else {
 		runs, count, err = store.JobRunsSortedFor(runID, order, offset, size)
 	}
+	if err == nil {
+		ptrs := make([]*models.JobRun, len(runs))
+		for i := range runs {
+			ptrs[i] = &runs[i]
+		}
+		jrc.rehydrate(c, ptrs...)
+	}
 
+	if consistent {
+		snapshotPaginatedResponse(c, "JobRuns", size, page, runs, count, taken, err)
+		return
+	}
 	paginatedResponse(c, "JobRuns", size, page, runs, count, err)
 }
 
@@ -83,7 +111,29 @@ func (jrc *JobRunsController) Create(c *gin.Context) {
 		return
 	}
 
-	jr, err := jrc.App.Create(j.ID, initiator, nil, &models.RunRequest{RequestParams: data})
+	key, data, err := getIdempotencyKey(c, data)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if key != "" {
+		if jrc.respondWithIdempotentRun(c, j.ID, key) {
+			return
+		}
+	}
+
+	runRequest := &models.RunRequest{RequestParams: data}
+	if key != "" {
+		runRequest.IdempotencyKey = null.StringFrom(key)
+		runRequest.JobSpecID = j.ID
+	}
+	jr, err := jrc.App.Create(j.ID, initiator, nil, runRequest)
+	if err != nil && key != "" {
+		// A request with the same key may have created its run since we looked
+		if jrc.respondWithIdempotentRun(c, j.ID, key) {
+			return
+		}
+	}
 	if errors.Cause(err) == orm.ErrorNotFound {
 		jsonAPIError(c, http.StatusNotFound, errors.New("Job not found"))
 		return
@@ -115,6 +165,50 @@ func getAuthenticatedInitiator(c *gin.Context, js models.JobSpec) (*models.Initi
 	return nil, errors.New("authentication required")
 }
 
+// IdempotencyKeyHeader is the header with which a run request can be given a
+// key, so that retrying the request returns the run it created the first time
+// instead of creating another.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKeyField is the field of the run data with which external
+// initiators, which cannot always set headers, can give the key instead.
+const idempotencyKeyField = "idempotencyKey"
+
+// getIdempotencyKey returns the idempotency key of the run request, and its
+// data without the key if an external initiator gave it there.
+func getIdempotencyKey(c *gin.Context, data models.JSON) (string, models.JSON, error) {
+	if key := c.GetHeader(IdempotencyKeyHeader); key != "" {
+		return key, data, nil
+	}
+	if _, ok := authenticatedEI(c); !ok {
+		return "", data, nil
+	}
+	field := data.Get(idempotencyKeyField)
+	if !field.Exists() {
+		return "", data, nil
+	} else if field.Type != gjson.String || field.String() == "" {
+		return "", data, fmt.Errorf("%s must be a non-empty string", idempotencyKeyField)
+	}
+	data, err := data.Delete(idempotencyKeyField)
+	return field.String(), data, err
+}
+
+// respondWithIdempotentRun responds with the run of the job created with the
+// idempotency key, if there is one.
+func (jrc *JobRunsController) respondWithIdempotentRun(c *gin.Context, jobSpecID *models.ID, key string) bool {
+	jr, err := jrc.App.GetStore().FindJobRunByIdempotencyKey(jobSpecID, key)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		return false
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return true
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	jsonAPIResponse(c, presenters.JobRun{JobRun: jr}, "job run")
+	return true
+}
+
 func getRunData(c *gin.Context) (models.JSON, error) {
 	b, err := ioutil.ReadAll(c.Request.Body)
 	if err != nil {
@@ -143,9 +237,51 @@ func (jrc *JobRunsController) Show(c *gin.Context) {
 		return
 	}
 
+	jrc.rehydrate(c, &jr)
 	jsonAPIResponse(c, presenters.JobRun{JobRun: jr}, "job run")
 }
 
+// SignedResult returns the result of a run as signed by the last of its
+// signresult tasks to complete, so that it can be verified off-chain.
+// Example:
+//  "<application>/runs/:RunID/signed_result"
+func (jrc *JobRunsController) SignedResult(c *gin.Context) {
+	id, err := models.NewIDFromString(c.Param("RunID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	jr, err := jrc.App.GetStore().FindJobRun(id)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("Job run not found"))
+		return
+	}
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jrc.rehydrate(c, &jr)
+	for i := len(jr.TaskRuns) - 1; i >= 0; i-- {
+		tr := jr.TaskRuns[i]
+		if tr.TaskSpec.Type == adapters.TaskTypeSignResult && tr.Status == models.RunStatusCompleted {
+			jsonAPIResponse(c, presenters.NewSignedResult(jr.ID, tr), "signed result")
+			return
+		}
+	}
+	jsonAPIError(c, http.StatusNotFound, errors.New("Job run has no signed result"))
+}
+
+// rehydrate puts back the data of the archived results of the runs, leaving
+// them with their stubs if the archive cannot be read.
+func (jrc *JobRunsController) rehydrate(c *gin.Context, runs ...*models.JobRun) {
+	err := services.RehydrateRunResults(c.Request.Context(), jrc.App.GetStore().Config, runs...)
+	if err != nil {
+		logger.Warnw("Unable to fetch archived run results", "error", err)
+	}
+}
+
 // Update allows external adapters to resume a JobRun, reporting the result of
 // the task and marking it no longer pending.
 // Example:
@@ -180,23 +316,124 @@ func (jrc *JobRunsController) Update(c *gin.Context) {
 		return
 	}
 
-	bt, err := unscoped.PendingBridgeType(jr)
+	// A task graph may wait on several bridges, the one authenticated being
+	// the one answering.
+	for _, taskRun := range jr.PendingBridgeTaskRuns() {
+		bt, err := unscoped.FindBridge(taskRun.TaskSpec.Type)
+		if errors.Cause(err) == orm.ErrorNotFound {
+			continue
+		} else if err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+		ok, err := models.AuthenticateBridgeType(&bt, authToken)
+		if err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if ok {
+			brr.TaskRunID = taskRun.ID
+			break
+		}
+	}
+	if brr.TaskRunID == nil {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	if err = jrc.App.ResumePending(runID, brr); errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("Job Run not found"))
+		return
+	}
 	if err != nil {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	ok, err := models.AuthenticateBridgeType(&bt, authToken)
+	jsonAPIResponse(c, jr, "job run")
+}
+
+// Callback resumes a Run pending on a bridge with the result the bridge calls
+// back with at the response URL it was sent, authenticated by the single-use
+// token in the URL rather than by the bridge's incoming token. The token only
+// resumes the task run which called the bridge, and is consumed by a callback
+// with a valid result.
+// Example:
+//  "<application>/runs/:RunID/callback?token=<token>"
+func (jrc *JobRunsController) Callback(c *gin.Context) {
+	runID, err := models.NewIDFromString(c.Param("RunID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	token := c.Query("token")
+	if token == "" {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	store := jrc.App.GetStore()
+	jr, err := store.Unscoped().FindJobRun(runID)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("Job Run not found"))
+		return
+	}
 	if err != nil {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
-	if !ok {
+	pending := jr.PendingBridgeTaskRuns()
+	if !jr.GetStatus().PendingBridge() || len(pending) == 0 {
+		jsonAPIError(c, http.StatusMethodNotAllowed, errors.New("Cannot resume a job run that isn't pending"))
+		return
+	}
+
+	var brr models.BridgeRunResult
+	if err := c.ShouldBindJSON(&brr); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// A task graph may wait on several bridges, each task run called back
+	// with its own token.
+	for _, taskRun := range pending {
+		ok, err := store.ConsumeBridgeCallback(runID, taskRun.ID, token, store.Clock.Now())
+		if err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if ok {
+			brr.TaskRunID = taskRun.ID
+			break
+		}
+	}
+	if brr.TaskRunID == nil {
 		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	if err = jrc.App.ResumePending(runID, brr); errors.Cause(err) == orm.ErrorNotFound {
+	if err := jrc.App.ResumePending(runID, brr); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, jr, "job run")
+}
+
+// Resume resumes a Run pending on a bridge with the result of its task
+// supplied by the operator, for when the bridge is down for good but its
+// answer is known. The result is recorded in the audit log of resumptions.
+// Example:
+//  "<application>/runs/:RunID/resume"
+func (jrc *JobRunsController) Resume(c *gin.Context) {
+	runID, err := models.NewIDFromString(c.Param("RunID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	jr, err := jrc.App.GetStore().Unscoped().FindJobRun(runID)
+	if errors.Cause(err) == orm.ErrorNotFound {
 		jsonAPIError(c, http.StatusNotFound, errors.New("Job Run not found"))
 		return
 	}
@@ -204,13 +441,42 @@ func (jrc *JobRunsController) Update(c *gin.Context) {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
+	if !jr.GetStatus().PendingBridge() {
+		jsonAPIError(c, http.StatusMethodNotAllowed, errors.New("Cannot resume a job run that isn't pending"))
+		return
+	}
+
+	var brr models.BridgeRunResult
+	if err := c.ShouldBindJSON(&brr); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if brr.Status.PendingBridge() {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("Cannot resume a job run without a result"))
+		return
+	}
+
+	resumedBy := "unknown"
+	if user, ok := authenticatedUser(c); ok {
+		resumedBy = user.Email
+	}
+	if err := jrc.App.ResumePendingManually(runID, brr, resumedBy); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
 
+	jr, err = jrc.App.GetStore().Unscoped().FindJobRun(runID)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
 	jsonAPIResponse(c, jr, "job run")
 }
 
 // Cancel stops a Run from continuing.
 // Example:
 //  "<application>/runs/:RunID/cancellation"
+//  "<application>/runs/:RunID/cancel"
 func (jrc *JobRunsController) Cancel(c *gin.Context) {
 	id, err := models.NewIDFromString(c.Param("RunID"))
 	if err != nil {
@@ -2,17 +2,21 @@ package web_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/smartcontractkit/chainlink/core/adapters"
 	"github.com/smartcontractkit/chainlink/core/auth"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 	"github.com/smartcontractkit/chainlink/core/web"
 
+	"github.com/jinzhu/gorm"
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,6 +99,73 @@ func TestJobRunsController_Index(t *testing.T) {
 	assert.Equal(t, runA.ID, allJobRuns[2].ID, "expected runs ordered by created at descending")
 }
 
+func TestJobRunsController_Index_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	app.Start()
+	defer cleanup()
+	client := app.NewHTTPClient()
+
+	runA, runB, _ := setupJobRunsControllerIndex(t, app)
+
+	resp, cleanup := client.Get("/v2/runs?snapshot=invalid&jobSpecId=" + runA.JobSpecID.String())
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get("/v2/runs?size=1&snapshot=true&jobSpecId=" + runA.JobSpecID.String())
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	body := cltest.ParseResponseBody(t, resp)
+	token, count := parseSnapshotMeta(t, body)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, 2, count)
+
+	var links jsonapi.Links
+	var runs []models.JobRun
+	require.NoError(t, web.ParsePaginatedResponse(body, &runs, &links))
+	require.Len(t, runs, 1)
+	assert.Equal(t, runA.ID, runs[0].ID)
+	assert.Contains(t, links["next"].Href, "snapshot="+token)
+
+	// A run created after the snapshot is not listed, even with an earlier
+	// createdAt than those which are.
+	job, err := app.Store.FindJob(runA.JobSpecID)
+	require.NoError(t, err)
+	runD := cltest.NewJobRun(job)
+	runD.ID = models.NewID()
+	runD.CreatedAt = runA.CreatedAt.Add(-time.Second)
+	require.NoError(t, app.Store.CreateJobRun(&runD))
+
+	resp, cleanup = client.Get(links["next"].Href)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	body = cltest.ParseResponseBody(t, resp)
+	nextToken, count := parseSnapshotMeta(t, body)
+	assert.Equal(t, token, nextToken)
+	assert.Equal(t, 2, count)
+
+	var nextPageLinks jsonapi.Links
+	var nextPageRuns []models.JobRun
+	require.NoError(t, web.ParsePaginatedResponse(body, &nextPageRuns, &nextPageLinks))
+	require.Len(t, nextPageRuns, 1)
+	assert.Equal(t, runB.ID, nextPageRuns[0].ID)
+	assert.Empty(t, nextPageLinks["next"].Href)
+}
+
+// parseSnapshotMeta returns the snapshot token and count of a paginated
+// response read from a snapshot.
+func parseSnapshotMeta(t *testing.T, body []byte) (string, int) {
+	var document struct {
+		Meta struct {
+			Snapshot string `json:"snapshot"`
+			Count    int    `json:"count"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(body, &document))
+	return document.Meta.Snapshot, document.Meta.Count
+}
+
 func setupJobRunsControllerIndex(t assert.TestingT, app *cltest.TestApplication) (*models.JobRun, *models.JobRun, *models.JobRun) {
 	j1 := cltest.NewJobWithWebInitiator()
 	assert.Nil(t, app.Store.CreateJob(&j1))
@@ -206,6 +277,117 @@ func TestJobRunsController_Create_ExternalInitiator_Success(t *testing.T) {
 	assert.Equal(t, "100", value)
 }
 
+func TestJobRunsController_Create_ExternalInitiator_RequireSignature(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	app.Start()
+	defer cleanup()
+
+	url := cltest.WebURL(t, "http://localhost:8888")
+	eia := auth.NewToken()
+	ei, err := models.NewExternalInitiator(eia, &models.ExternalInitiatorRequest{Name: "bitcoin", URL: &url, RequireSignature: true})
+	require.NoError(t, err)
+	require.NoError(t, app.Store.CreateExternalInitiator(ei))
+
+	j := cltest.NewJobWithExternalInitiator(ei)
+	require.NoError(t, app.Store.CreateJob(&j))
+
+	body := `{"result":"100"}`
+	post := func(timestamp, nonce, signature string) int {
+		headers := map[string]string{
+			web.ExternalInitiatorAccessKeyHeader: eia.AccessKey,
+			web.ExternalInitiatorSecretHeader:    eia.Secret,
+			web.ExternalInitiatorTimestampHeader: timestamp,
+			web.ExternalInitiatorNonceHeader:     nonce,
+			web.ExternalInitiatorSignatureHeader: signature,
+		}
+		url := app.Config.ClientNodeURL() + "/v2/specs/" + j.ID.String() + "/runs"
+		resp, cleanup := cltest.UnauthenticatedPost(t, url, bytes.NewBufferString(body), headers)
+		defer cleanup()
+		return resp.StatusCode
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	sign := func(nonce string) string {
+		return models.ExternalInitiatorSignature(ei.SigningSecret.String(), "POST", "/v2/specs/"+j.ID.String()+"/runs", timestamp, nonce, []byte(body))
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, post("", "", ""), "unsigned request should be rejected")
+	assert.Equal(t, http.StatusUnauthorized, post(timestamp, "nonce-1", sign("nonce-2")), "request with wrong signature should be rejected")
+
+	assert.Equal(t, http.StatusOK, post(timestamp, "nonce-1", sign("nonce-1")))
+	assert.Equal(t, http.StatusUnauthorized, post(timestamp, "nonce-1", sign("nonce-1")), "replayed request should be rejected")
+	assert.Equal(t, http.StatusOK, post(timestamp, "nonce-2", sign("nonce-2")))
+}
+
+func TestJobRunsController_Create_IdempotencyKey(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	app.Start()
+	defer cleanup()
+
+	url := cltest.WebURL(t, "http://localhost:8888")
+	eia := auth.NewToken()
+	ei, err := models.NewExternalInitiator(eia, &models.ExternalInitiatorRequest{Name: "bitcoin", URL: &url})
+	require.NoError(t, err)
+	require.NoError(t, app.Store.CreateExternalInitiator(ei))
+
+	j := cltest.NewJobWithExternalInitiator(ei)
+	require.NoError(t, app.Store.CreateJob(&j))
+	otherJob := cltest.NewJobWithExternalInitiator(ei)
+	require.NoError(t, app.Store.CreateJob(&otherJob))
+
+	createRun := func(job models.JobSpec, body, key string, status int) (models.JobRun, *http.Response) {
+		headers := map[string]string{
+			web.ExternalInitiatorAccessKeyHeader: eia.AccessKey,
+			web.ExternalInitiatorSecretHeader:    eia.Secret,
+		}
+		if key != "" {
+			headers[web.IdempotencyKeyHeader] = key
+		}
+		url := app.Config.ClientNodeURL() + "/v2/specs/" + job.ID.String() + "/runs"
+		resp, cleanup := cltest.UnauthenticatedPost(t, url, bytes.NewBufferString(body), headers)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, status)
+		var jr models.JobRun
+		if status == http.StatusOK {
+			require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &jr))
+		}
+		return jr, resp
+	}
+
+	first, resp := createRun(j, `{"result":"100"}`, "delivery-1", http.StatusOK)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	retried, resp := createRun(j, `{"result":"100"}`, "delivery-1", http.StatusOK)
+	assert.Equal(t, first.ID, retried.ID)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+
+	other, _ := createRun(j, `{"result":"100"}`, "delivery-2", http.StatusOK)
+	assert.NotEqual(t, first.ID, other.ID)
+
+	fromField, _ := createRun(j, `{"result":"100","idempotencyKey":"delivery-3"}`, "", http.StatusOK)
+	retried, _ = createRun(j, `{"result":"100","idempotencyKey":"delivery-3"}`, "", http.StatusOK)
+	assert.Equal(t, fromField.ID, retried.ID)
+	run, err := app.Store.FindJobRun(fromField.ID)
+	require.NoError(t, err)
+	assert.False(t, run.RunRequest.RequestParams.Get("idempotencyKey").Exists())
+
+	// Keys are unique per job
+	otherRun, _ := createRun(otherJob, `{"result":"100"}`, "delivery-1", http.StatusOK)
+	assert.NotEqual(t, first.ID, otherRun.ID)
+	retried, _ = createRun(otherJob, `{"result":"100"}`, "delivery-1", http.StatusOK)
+	assert.Equal(t, otherRun.ID, retried.ID)
+
+	// The run of a key is returned even once deleted
+	require.NoError(t, app.Store.ORM.RawDB(func(db *gorm.DB) error {
+		return db.Exec("UPDATE job_runs SET deleted_at = now() WHERE id = ?", first.ID).Error
+	}))
+	retried, resp = createRun(j, `{"result":"100"}`, "delivery-1", http.StatusOK)
+	assert.Equal(t, first.ID, retried.ID)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+
+	createRun(j, `{"result":"100","idempotencyKey":1}`, "", http.StatusUnprocessableEntity)
+}
+
 func TestJobRunsController_Create_Archived(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
@@ -461,6 +643,45 @@ func TestJobRunsController_Update_NotFound(t *testing.T) {
 	assert.Equal(t, models.RunStatusPendingBridge, jr.GetStatus())
 }
 
+func TestJobRunsController_Callback(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	app.Start()
+	defer cleanup()
+
+	_, bt := cltest.NewBridgeType(t, "callback")
+	require.NoError(t, app.Store.CreateBridgeType(bt))
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.TaskSpec{{Type: bt.Name}}
+	require.NoError(t, app.Store.CreateJob(&j))
+	jr := cltest.NewJobRunPendingBridge(j)
+	require.NoError(t, app.Store.CreateJobRun(&jr))
+
+	token, callback, err := models.NewBridgeCallback(jr.ID, jr.TaskRuns[0].ID, time.Hour, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, app.Store.CreateBridgeCallback(&callback))
+
+	callbackURL := func(token string) string {
+		return app.Config.ClientNodeURL() + "/v2/runs/" + jr.ID.String() + "/callback?token=" + token
+	}
+	body := fmt.Sprintf(`{"id":"%v","data":{"result": "100"}}`, jr.ID.String())
+
+	resp, cleanup := cltest.UnauthenticatedPatch(t, callbackURL("wrong"), bytes.NewBufferString(body), nil)
+	defer cleanup()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, cleanup = cltest.UnauthenticatedPatch(t, callbackURL(token), bytes.NewBufferString(body), nil)
+	defer cleanup()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Response should be successful")
+
+	jr = cltest.WaitForJobRunToComplete(t, app.Store, jr)
+	assert.Equal(t, "100", cltest.MustResultString(t, jr.Result))
+
+	ok, err := app.Store.ConsumeBridgeCallback(jr.ID, jr.TaskRuns[0].ID, token, time.Now())
+	require.NoError(t, err)
+	assert.False(t, ok, "the token should have been consumed")
+}
+
 func TestJobRunsController_Show_Found(t *testing.T) {
 	t.Parallel()
 
@@ -520,6 +741,95 @@ func TestJobRunsController_Show_Unauthenticated(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "Response should be forbidden")
 }
 
+func TestJobRunsController_SignedResult(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	app.Start()
+	defer cleanup()
+	client := app.NewHTTPClient()
+
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.TaskSpec{cltest.NewTask(t, "noop"), cltest.NewTask(t, adapters.TaskTypeSignResult.String())}
+	require.NoError(t, app.Store.CreateJob(&j))
+
+	unsigned := cltest.NewJobRun(j)
+	require.NoError(t, app.Store.CreateJobRun(&unsigned))
+
+	signed := cltest.NewJobRun(j)
+	signed.TaskRuns[1].Status = models.RunStatusCompleted
+	signed.TaskRuns[1].Result.Data = cltest.JSONFromString(t, `{"result":"11850.00","signature":"0x01","signer":"0x02","signedHash":"0x03"}`)
+	require.NoError(t, app.Store.CreateJobRun(&signed))
+
+	resp, cleanup := client.Get("/v2/runs/" + signed.ID.String() + "/signed_result")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var signedResult presenters.SignedResult
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &signedResult))
+	assert.Equal(t, signed.ID, signedResult.RunID)
+	assert.JSONEq(t, `"11850.00"`, string(signedResult.Result))
+	assert.Equal(t, "0x01", signedResult.Signature)
+	assert.Equal(t, "0x02", signedResult.Signer)
+	assert.Equal(t, "0x03", signedResult.SignedHash)
+
+	resp, cleanup = client.Get("/v2/runs/" + unsigned.ID.String() + "/signed_result")
+	defer cleanup()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, cleanup = client.Get("/v2/runs/4C95A8FA-EEAC-4BD5-97D9-27806D200D3C/signed_result")
+	defer cleanup()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestJobRunsController_Resume(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	require.NoError(t, app.Start())
+	defer cleanup()
+	client := app.NewHTTPClient()
+
+	_, bt := cltest.NewBridgeType(t, "downbridge")
+	require.NoError(t, app.Store.CreateBridgeType(bt))
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.TaskSpec{{Type: bt.Name}}
+	require.NoError(t, app.Store.CreateJob(&j))
+	jr := cltest.NewJobRunPendingBridge(j)
+	require.NoError(t, app.Store.CreateJobRun(&jr))
+
+	t.Run("without a result", func(t *testing.T) {
+		resp, cleanup := client.Post("/v2/runs/"+jr.ID.String()+"/resume", bytes.NewBufferString(`{"pending": true}`))
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("with a result", func(t *testing.T) {
+		resp, cleanup := client.Post("/v2/runs/"+jr.ID.String()+"/resume", bytes.NewBufferString(`{"data":{"result": "100"}}`))
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+		jr = cltest.WaitForJobRunToComplete(t, app.Store, jr)
+		assert.Equal(t, "100", cltest.MustResultString(t, jr.Result))
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		resp, cleanup := client.Post("/v2/runs/"+jr.ID.String()+"/resume", bytes.NewBufferString(`{"data":{"result": "100"}}`))
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusMethodNotAllowed)
+	})
+
+	resp, cleanup := client.Get("/v2/run_resumptions")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var resumptions []models.RunResumption
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &resumptions))
+	require.Len(t, resumptions, 1)
+	assert.Equal(t, jr.ID, resumptions[0].JobRunID)
+	assert.Equal(t, jr.TaskRuns[0].ID, resumptions[0].TaskRunID)
+	assert.Equal(t, bt.Name, resumptions[0].Bridge)
+	assert.Equal(t, models.RunStatusCompleted, resumptions[0].Status)
+	assert.Equal(t, cltest.APIEmail, resumptions[0].ResumedBy)
+}
+
 func TestJobRunsController_Cancel(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
@@ -554,4 +864,17 @@ func TestJobRunsController_Cancel(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, models.RunStatusCancelled, r.GetStatus())
 	})
+
+	pendingRun := cltest.NewJobRunPendingBridge(job)
+	require.NoError(t, app.Store.CreateJobRun(&pendingRun))
+
+	t.Run("cancel endpoint", func(t *testing.T) {
+		resp, cleanup := client.Patch(fmt.Sprintf("/v2/runs/%s/cancel", pendingRun.ID), nil)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+		r, err := app.Store.FindJobRun(pendingRun.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RunStatusCancelled, r.GetStatus())
+	})
 }
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// JobSpecVersionsController manages the prior versions of a JobSpec.
+type JobSpecVersionsController struct {
+	App chainlink.Application
+}
+
+// Index lists the prior versions of a JobSpec, most recent first.
+// Example:
+//  "<application>/specs/:SpecID/versions"
+func (jsvc *JobSpecVersionsController) Index(c *gin.Context) {
+	id, err := models.NewIDFromString(c.Param("SpecID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	versions, err := jsvc.App.GetStore().JobSpecVersions(id)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, versions, "job_spec_versions")
+}
+
+// Diff lists the changes made to a JobSpec since the given prior version.
+// Example:
+//  "<application>/specs/:SpecID/versions/:Version/diff"
+func (jsvc *JobSpecVersionsController) Diff(c *gin.Context) {
+	id, version, err := jobSpecVersionParams(c)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	changes, err := jsvc.App.GetStore().DiffJobSpecVersion(id, version)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec version not found"))
+		return
+	}
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, presenters.JobSpecDiff{Version: version, Changes: changes}, "job_spec_diff")
+}
+
+// Rollback restores the definition a JobSpec had at the given prior version.
+// Example:
+//  "<application>/specs/:SpecID/versions/:Version/rollback"
+func (jsvc *JobSpecVersionsController) Rollback(c *gin.Context) {
+	id, version, err := jobSpecVersionParams(c)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	job, err := jsvc.App.RollbackJob(id, version)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec version not found"))
+		return
+	}
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, presenters.JobSpec{JobSpec: job}, "job")
+}
+
+func jobSpecVersionParams(c *gin.Context) (*models.ID, uint32, error) {
+	id, err := models.NewIDFromString(c.Param("SpecID"))
+	if err != nil {
+		return nil, 0, err
+	}
+	version, err := strconv.ParseUint(c.Param("Version"), 10, 32)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "invalid version")
+	}
+	return id, uint32(version), nil
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"io/ioutil"
 	"net/http"
 
 	"github.com/smartcontractkit/chainlink/core/services"
@@ -18,9 +19,10 @@ type JobSpecsController struct {
 	App chainlink.Application
 }
 
-// Index lists JobSpecs, one page at a time.
+// Index lists JobSpecs, one page at a time, only those of a namespace if
+// given, or if requested with a namespace token.
 // Example:
-//  "<application>/specs?size=1&page=2"
+//  "<application>/specs?size=1&page=2&namespace=team-a"
 func (jsc *JobSpecsController) Index(c *gin.Context, size, page, offset int) {
 	var order orm.SortType
 	if c.Query("sort") == "-createdAt" {
@@ -29,7 +31,14 @@ func (jsc *JobSpecsController) Index(c *gin.Context, size, page, offset int) {
 		order = orm.Ascending
 	}
 
-	jobs, count, err := jsc.App.GetStore().JobsSorted(order, offset, size)
+	var jobs []models.JobSpec
+	var count int
+	var err error
+	if ns, scoped := requestNamespace(c); scoped {
+		jobs, count, err = jsc.App.GetStore().JobsSortedInNamespace(ns, order, offset, size)
+	} else {
+		jobs, count, err = jsc.App.GetStore().JobsSorted(order, offset, size)
+	}
 	pjs := make([]presenters.JobSpec, len(jobs))
 	for i, j := range jobs {
 		pjs[i] = presenters.JobSpec{JobSpec: j}
@@ -47,25 +56,54 @@ func (jsc *JobSpecsController) requireImplemented(js models.JobSpec) error {
 			return errors.New("The Flux Monitor feature is disabled by configuration")
 		}
 	}
+	if !cfg.Dev() && !cfg.FeatureOffchainReporting() {
+		if intrs := js.InitiatorsFor(models.InitiatorOffchainReporting); len(intrs) > 0 {
+			return errors.New("The Off-chain Reporting feature is disabled by configuration")
+		}
+	}
 	return nil
 }
 
 // getAndCheckJobSpec(c) returns a validated job spec from c, or errors. The
 // httpStatus return value is only meaningful on error, and in that case
-// reflects the type of failure to be reported back to the client.
+// reflects the type of failure to be reported back to the client. The job is
+// in defaultNamespace unless the spec gives one.
 func (jsc *JobSpecsController) getAndCheckJobSpec(
-	c *gin.Context) (js models.JobSpec, httpStatus int, err error) {
+	c *gin.Context, defaultNamespace string) (js models.JobSpec, httpStatus int, err error) {
 	var jsr models.JobSpecRequest
-	if err := c.ShouldBindJSON(&jsr); err != nil {
+	var lines models.JobSpecLines
+	if c.ContentType() == models.JobSpecTOMLContentType {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			return models.JobSpec{}, http.StatusBadRequest, err
+		}
+		if jsr, lines, err = models.ParseJobSpecTOML(body); err != nil {
+			return models.JobSpec{}, http.StatusBadRequest, err
+		}
+	} else if err := c.ShouldBindJSON(&jsr); err != nil {
 		// TODO(alx): Better parsing and more specific error messages
 		// https://www.pivotaltracker.com/story/show/171164115
 		return models.JobSpec{}, http.StatusBadRequest, err
 	}
+	if jsr.Namespace == "" {
+		jsr.Namespace = defaultNamespace
+	}
+	if jsr.Namespace, err = namespaceFor(c, jsr.Namespace); err != nil {
+		return models.JobSpec{}, http.StatusForbidden, err
+	}
+	return jsc.checkJobSpec(jsr, lines)
+}
+
+// checkJobSpec returns the job spec of jsr once validated, or errors, with
+// the httpStatus to report the failure with. The errors of its initiators and
+// tasks are prefixed with the lines of a TOML spec they start on, if any.
+func (jsc *JobSpecsController) checkJobSpec(
+	jsr models.JobSpecRequest, lines models.JobSpecLines) (js models.JobSpec, httpStatus int, err error) {
 	js = models.NewJobFromRequest(jsr)
 	if err := jsc.requireImplemented(js); err != nil {
 		return models.JobSpec{}, http.StatusNotImplemented, err
 	}
-	if err := services.ValidateJob(js, jsc.App.GetStore()); err != nil {
+	if err := services.ValidateJobWithLines(js, jsc.App.GetStore(), lines); err != nil {
 		return models.JobSpec{}, http.StatusBadRequest, err
 	}
 	return js, 0, nil
@@ -75,16 +113,16 @@ func (jsc *JobSpecsController) getAndCheckJobSpec(
 // Example:
 //  "<application>/specs"
 func (jsc *JobSpecsController) Create(c *gin.Context) {
-	js, httpStatus, err := jsc.getAndCheckJobSpec(c)
+	js, httpStatus, err := jsc.getAndCheckJobSpec(c, "")
 	if err != nil {
 		jsonAPIError(c, httpStatus, err)
 		return
 	}
-	if err := NotifyExternalInitiator(js, jsc.App.GetStore()); err != nil {
+	if err := jsc.App.AddJob(js); err != nil {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
-	if err := jsc.App.AddJob(js); err != nil {
+	if err := NotifyExternalInitiator(js, jsc.App.GetStore()); err != nil {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
@@ -92,25 +130,53 @@ func (jsc *JobSpecsController) Create(c *gin.Context) {
 	jsonAPIResponse(c, presenters.JobSpec{JobSpec: js}, "job")
 }
 
-// Show returns the details of a JobSpec.
+// Update validates and replaces the definition of an existing JobSpec. The
+// prior definition is kept as a version that can be rolled back to. The job
+// stays in its namespace.
 // Example:
 //  "<application>/specs/:SpecID"
-func (jsc *JobSpecsController) Show(c *gin.Context) {
-	id, err := models.NewIDFromString(c.Param("SpecID"))
-	if err != nil {
-		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+func (jsc *JobSpecsController) Update(c *gin.Context) {
+	existing, ok := jsc.findJob(c)
+	if !ok {
 		return
 	}
+	id := existing.ID
 
-	j, err := jsc.App.GetStore().FindJob(id)
-	if errors.Cause(err) == orm.ErrorNotFound {
-		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec not found"))
+	js, httpStatus, err := jsc.getAndCheckJobSpec(c, existing.Namespace)
+	if err != nil {
+		jsonAPIError(c, httpStatus, err)
+		return
+	}
+	if js.Namespace != existing.Namespace {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("the namespace of a job cannot be changed"))
+		return
+	}
+	js.ID = id
+	if err := jsc.App.UpdateJob(js); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
+	if err := NotifyExternalInitiator(js, jsc.App.GetStore()); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	j, err := jsc.App.GetStore().FindJob(id)
 	if err != nil {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
+	jsonAPIResponse(c, jobPresenter(jsc, j), "job")
+}
+
+// Show returns the details of a JobSpec.
+// Example:
+//  "<application>/specs/:SpecID"
+func (jsc *JobSpecsController) Show(c *gin.Context) {
+	j, ok := jsc.findJob(c)
+	if !ok {
+		return
+	}
 
 	jsonAPIResponse(c, jobPresenter(jsc, j), "job")
 }
@@ -119,13 +185,12 @@ func (jsc *JobSpecsController) Show(c *gin.Context) {
 // Example:
 //  "<application>/specs/:SpecID"
 func (jsc *JobSpecsController) Destroy(c *gin.Context) {
-	id, err := models.NewIDFromString(c.Param("SpecID"))
-	if err != nil {
-		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+	j, ok := jsc.findJob(c)
+	if !ok {
 		return
 	}
 
-	err = jsc.App.ArchiveJob(id)
+	err := jsc.App.ArchiveJob(j.ID)
 	if errors.Cause(err) == orm.ErrorNotFound {
 		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec not found"))
 		return
@@ -138,6 +203,27 @@ func (jsc *JobSpecsController) Destroy(c *gin.Context) {
 	jsonAPIResponseWithStatus(c, nil, "job", http.StatusNoContent)
 }
 
+// findJob returns the job requested. It responds with an error and returns
+// false if the job does not exist, or is not visible to the request, being in
+// another namespace than its token's.
+func (jsc *JobSpecsController) findJob(c *gin.Context) (models.JobSpec, bool) {
+	id, err := models.NewIDFromString(c.Param("SpecID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return models.JobSpec{}, false
+	}
+
+	j, err := jsc.App.GetStore().FindJob(id)
+	if errors.Cause(err) == orm.ErrorNotFound || (err == nil && !visibleIn(c, j.Namespace)) {
+		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec not found"))
+		return models.JobSpec{}, false
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return models.JobSpec{}, false
+	}
+	return j, true
+}
+
 func jobPresenter(jsc *JobSpecsController, job models.JobSpec) presenters.JobSpec {
 	store := jsc.App.GetStore()
 	jobLinkEarned, _ := store.LinkEarnedFor(&job)
@@ -396,6 +396,48 @@ func TestJobSpecsController_Create_InvalidJob(t *testing.T) {
 	assert.Equal(t, expected, strings.TrimSpace(body))
 }
 
+func TestJobSpecsController_Create_TOML(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+	headers := map[string]string{"Content-Type": models.JobSpecTOMLContentType}
+
+	spec := `[[initiators]]
+type = "web"
+
+[[tasks]]
+type = "httpget"
+[tasks.params]
+get = "https://bitstamp.net/api/ticker/"
+
+[[tasks]]
+type = "jsonparse"
+params = { path = ["last"] }
+`
+	resp, cleanup := client.Post("/v2/specs", bytes.NewBufferString(spec), headers)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var j models.JobSpec
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &j))
+	require.Len(t, j.Tasks, 2)
+	assert.Equal(t, models.MustNewTaskType("jsonparse"), j.Tasks[1].Type)
+
+	invalid := `[[initiators]]
+type = "web"
+
+[[tasks]]
+type = "nonexistent"
+`
+	resp, cleanup = client.Post("/v2/specs", bytes.NewBufferString(invalid), headers)
+	defer cleanup()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, string(cltest.ParseResponseBody(t, resp)), "line 4: ")
+}
+
 func TestJobSpecsController_Create_InvalidCron(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
@@ -541,6 +583,50 @@ func TestJobSpecsController_Show_Unauthenticated(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "Response should be forbidden")
 }
 
+func TestJobSpecsController_Update(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+
+	resp, cleanup := client.Patch("/v2/specs/"+job.ID.String(), bytes.NewBuffer(cltest.MustReadFile(t, "testdata/hello_world_job.json")))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var j models.JobSpec
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &j))
+	assert.Equal(t, job.ID, j.ID)
+	assert.Len(t, j.Tasks, 4)
+
+	versions, err := app.Store.JobSpecVersions(job.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+
+	resp, cleanup = client.Post("/v2/specs/"+job.ID.String()+"/versions/1/rollback", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	j, err = app.Store.FindJob(job.ID)
+	require.NoError(t, err)
+	assert.Len(t, j.Tasks, 1)
+}
+
+func TestJobSpecsController_Update_NotFound(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+	resp, cleanup := client.Patch("/v2/specs/"+models.NewID().String(), bytes.NewBuffer(cltest.MustReadFile(t, "testdata/hello_world_job.json")))
+	defer cleanup()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 func TestJobSpecsController_Destroy(t *testing.T) {
 	t.Parallel()
 	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+)
+
+// KeyBundlesController moves the keys of a node to another node, as a single
+// bundle encrypted with the node's password.
+type KeyBundlesController struct {
+	App chainlink.Application
+}
+
+// Export returns the ethereum and VRF keys of the node, encrypted with its
+// password.
+// Example:
+//  "<application>/key_bundles/export"
+func (kbc *KeyBundlesController) Export(c *gin.Context) {
+	request := models.ExportKeysRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	store := kbc.App.GetStore()
+	if err := store.KeyStore.Unlock(request.CurrentPassword); err != nil {
+		jsonAPIError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	bundle, err := store.ExportKeys(request.CurrentPassword)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, bundle)
+}
+
+// Import adds the keys in a bundle exported from another node, or only checks
+// that they can be imported if the request is a dry run.
+// Example:
+//  "<application>/key_bundles/import"
+func (kbc *KeyBundlesController) Import(c *gin.Context) {
+	request := models.ImportKeysRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	var bundle strpkg.EncryptedKeyBundle
+	if err := json.Unmarshal(request.Bundle, &bundle); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid key bundle"))
+		return
+	}
+	store := kbc.App.GetStore()
+	if err := store.KeyStore.Unlock(request.CurrentPassword); err != nil {
+		jsonAPIError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	imported, err := store.ImportKeys(&bundle, request.CurrentPassword, request.DryRun)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if request.DryRun {
+		status = http.StatusOK
+	}
+	jsonAPIResponseWithStatus(c, presenters.NewImportedKeys(imported, request.DryRun), "imported keys", status)
+}
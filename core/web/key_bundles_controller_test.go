@@ -0,0 +1,94 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyBundlesController_ExportImport(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig(t)
+	app, cleanup := cltest.NewApplicationWithConfigAndKey(t, config)
+	defer cleanup()
+
+	ethMock := app.EthMock
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_getTransactionCount", "0x100")
+		ethMock.Register("eth_chainId", config.ChainID())
+	})
+
+	client := app.NewHTTPClient()
+
+	require.NoError(t, app.StartAndConnect())
+	vrfKey, err := app.Store.VRFKeyStore.CreateKey(cltest.Password, vrfkey.FastScryptParams)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(models.ExportKeysRequest{CurrentPassword: "12345"})
+	require.NoError(t, err)
+	resp, cleanup := client.Post("/v2/key_bundles/export", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, 401)
+
+	body, err = json.Marshal(models.ExportKeysRequest{CurrentPassword: cltest.Password})
+	require.NoError(t, err)
+	resp, cleanup = client.Post("/v2/key_bundles/export", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, 200)
+	bundle := cltest.ParseResponseBody(t, resp)
+
+	importKeys := func(dryRun bool) []presenters.ImportedKey {
+		body, err := json.Marshal(models.ImportKeysRequest{
+			CurrentPassword: cltest.Password,
+			Bundle:          bundle,
+			DryRun:          dryRun,
+		})
+		require.NoError(t, err)
+		resp, cleanup := client.Post("/v2/key_bundles/import", bytes.NewBuffer(body))
+		defer cleanup()
+		if dryRun {
+			cltest.AssertServerResponse(t, resp, 200)
+		} else {
+			cltest.AssertServerResponse(t, resp, 201)
+		}
+		var keys []presenters.ImportedKey
+		require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &keys))
+		return keys
+	}
+
+	account := app.Store.KeyStore.Accounts()[0]
+	assert.Equal(t, []presenters.ImportedKey{
+		{Type: "eth", Key: account.Address.Hex(), Status: "already present"},
+		{Type: "vrf", Key: vrfKey.String(), Status: "already present"},
+	}, importKeys(true))
+
+	require.NoError(t, app.Store.VRFKeyStore.Delete(vrfKey))
+	assert.Equal(t, "valid", importKeys(true)[1].Status)
+	vrfKeys, err := app.Store.VRFKeyStore.ListKeys()
+	require.NoError(t, err)
+	assert.Empty(t, vrfKeys, "a dry run must not import keys")
+
+	assert.Equal(t, "imported", importKeys(false)[1].Status)
+	vrfKeys, err = app.Store.VRFKeyStore.ListKeys()
+	require.NoError(t, err)
+	require.Len(t, vrfKeys, 1)
+	assert.Equal(t, *vrfKey, *vrfKeys[0])
+
+	body, err = json.Marshal(models.ImportKeysRequest{CurrentPassword: cltest.Password, Bundle: []byte(`{"version":2}`)})
+	require.NoError(t, err)
+	resp, cleanup = client.Post("/v2/key_bundles/import", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, 422)
+
+	ethMock.AllCalled()
+}
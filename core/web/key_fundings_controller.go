@@ -0,0 +1,21 @@
+package web
+
+import (
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFundingsController lists the ETH sent from the funding key to the
+// node's other keys when their balances dropped below the threshold.
+type KeyFundingsController struct {
+	App chainlink.Application
+}
+
+// Index returns the key fundings, most recent first.
+// Example:
+//  "<application>/key_fundings"
+func (kfc *KeyFundingsController) Index(c *gin.Context, size, page, offset int) {
+	fundings, count, err := kfc.App.GetStore().KeyFundings(offset, size)
+	paginatedResponse(c, "KeyFundings", size, page, fundings, count, err)
+}
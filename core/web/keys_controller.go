@@ -4,10 +4,13 @@ import (
 	"net/http"
 
 	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	strpkg "github.com/smartcontractkit/chainlink/core/store"
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
 // KeysController manages account keys
@@ -24,6 +27,10 @@ func (kc *KeysController) Create(c *gin.Context) {
 		jsonAPIError(c, http.StatusUnprocessableEntity, err)
 		return
 	}
+	if err := models.ValidateNamespace(request.Namespace); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
 	if err := kc.App.GetStore().KeyStore.Unlock(request.CurrentPassword); err != nil {
 		jsonAPIError(c, http.StatusUnauthorized, err)
 		return
@@ -38,6 +45,87 @@ func (kc *KeysController) Create(c *gin.Context) {
 		jsonAPIError(c, http.StatusInternalServerError, err)
 		return
 	}
+	if err := kc.App.GetStore().SetKeyNamespace(account.Address, request.Namespace); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
 
 	jsonAPIResponseWithStatus(c, presenters.NewAccount{Account: &account}, "account", http.StatusCreated)
 }
+
+// CreateExternal adds a key held by a KMS or HSM, which the node signs
+// transactions with from then on, without ever holding the key itself.
+// Example:
+//  "<application>/external_keys"
+func (kc *KeysController) CreateExternal(c *gin.Context) {
+	request := models.ExternalKeyRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err := models.ValidateNamespace(request.Namespace); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	store := kc.App.GetStore()
+	if err := store.KeyStore.Unlock(request.CurrentPassword); err != nil {
+		jsonAPIError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	signer, err := strpkg.NewSigner(request.KeyRef)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	account, err := store.AddExternalKey(signer, request.KeyRef)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := store.SetKeyNamespace(account.Address, request.Namespace); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponseWithStatus(c, presenters.NewAccount{Account: &account}, "account", http.StatusCreated)
+}
+
+// Rotate replaces the key with the given address by a new one, which is used
+// for new transactions right away. The old key's pending transactions are
+// still tracked until confirmed, and its funds are swept to the new key if
+// asked for.
+// Example:
+//  "<application>/keys/:address/rotate"
+func (kc *KeysController) Rotate(c *gin.Context) {
+	request := models.RotateKeyRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if !common.IsHexAddress(c.Param("address")) {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("invalid address"))
+		return
+	}
+	address := common.HexToAddress(c.Param("address"))
+
+	store := kc.App.GetStore()
+	if err := store.KeyStore.Unlock(request.CurrentPassword); err != nil {
+		jsonAPIError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	rotation, err := store.RotateKey(address, request.CurrentPassword, request.Sweep)
+	if errors.Cause(err) == strpkg.ErrKeyNotFound {
+		jsonAPIError(c, http.StatusNotFound, err)
+		return
+	} else if errors.Cause(err) == strpkg.ErrKeyRetired {
+		jsonAPIError(c, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponseWithStatus(c, presenters.NewKeyRotation(rotation), "key rotation", http.StatusCreated)
+}
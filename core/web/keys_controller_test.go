@@ -7,8 +7,12 @@ import (
 
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKeysController_CreateSuccess(t *testing.T) {
@@ -99,3 +103,60 @@ func TestKeysController_JSONBindingError(t *testing.T) {
 
 	ethMock.AllCalled()
 }
+
+func TestKeysController_Rotate(t *testing.T) {
+	t.Parallel()
+
+	config, _ := cltest.NewConfig(t)
+	app, cleanup := cltest.NewApplicationWithConfigAndKey(t, config)
+	defer cleanup()
+
+	ethMock := app.EthMock
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_getTransactionCount", "0x100")
+		ethMock.Register("eth_chainId", config.ChainID())
+	})
+
+	client := app.NewHTTPClient()
+
+	require.NoError(t, app.StartAndConnect())
+	oldAddress := common.HexToAddress("0x3cb8e3FD9d27e39a5e9e6852b0e96160061fd4ea")
+
+	request := models.RotateKeyRequest{CurrentPassword: "12345"}
+	body, err := json.Marshal(&request)
+	require.NoError(t, err)
+	resp, cleanup := client.Post("/v2/keys/"+oldAddress.Hex()+"/rotate", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, 401)
+
+	request = models.RotateKeyRequest{CurrentPassword: cltest.Password}
+	body, err = json.Marshal(&request)
+	require.NoError(t, err)
+	resp, cleanup = client.Post("/v2/keys/0x0000000000000000000000000000000000000001/rotate", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, 404)
+
+	ethMock.Register("eth_getTransactionCount", "0x0")
+	resp, cleanup = client.Post("/v2/keys/"+oldAddress.Hex()+"/rotate", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, 201)
+	var rotation presenters.KeyRotation
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &rotation))
+	assert.Equal(t, oldAddress, rotation.Retired)
+	assert.Empty(t, rotation.SweepTxHashes)
+
+	keys, err := app.Store.Keys()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	for _, key := range keys {
+		assert.Equal(t, key.Address.Address() == oldAddress, key.RetiredAt.Valid)
+	}
+	assert.Equal(t, rotation.Replacement, app.Store.TxManager.NextActiveAccount().Address)
+	assert.Equal(t, rotation.Replacement, app.Store.TxManager.NextActiveAccount().Address)
+
+	resp, cleanup = client.Post("/v2/keys/"+oldAddress.Hex()+"/rotate", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, 409)
+
+	ethMock.AllCalled()
+}
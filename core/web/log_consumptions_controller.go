@@ -0,0 +1,180 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/services/eth"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// maxLogReplayBlocks is the most blocks the logs of which are replayed at
+// once.
+const maxLogReplayBlocks = 10000
+
+// LogConsumptionsController lets operators see the logs jobs consumed, and
+// have a job consume the logs of a block range again.
+type LogConsumptionsController struct {
+	App chainlink.Application
+}
+
+// Index returns the logs consumed by a job, most recent first.
+// Example:
+//  "<application>/specs/:SpecID/log_consumptions?size=1&page=2"
+func (lcc *LogConsumptionsController) Index(c *gin.Context, size, page, offset int) {
+	id, ok := lcc.jobSpecID(c)
+	if !ok {
+		return
+	}
+
+	lcs, count, err := lcc.App.GetStore().LogConsumptionsFor(id, offset, size)
+	paginatedResponse(c, "LogConsumptions", size, page, lcs, count, err)
+}
+
+// Destroy deletes the record of a job having consumed a log, so that
+// replaying the log's block has the job consume it again.
+// Example:
+//  "<application>/specs/:SpecID/log_consumptions/:ID"
+func (lcc *LogConsumptionsController) Destroy(c *gin.Context) {
+	jobSpecID, ok := lcc.jobSpecID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("ID"), 10, 32)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("invalid log consumption ID"))
+		return
+	}
+
+	err = lcc.App.GetStore().DeleteLogConsumption(jobSpecID, uint(id))
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("log consumption not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, nil, "log_consumptions", http.StatusNoContent)
+}
+
+// LogReplayRequest is the block range the logs of which are replayed, both
+// ends included.
+type LogReplayRequest struct {
+	FromBlock uint64 `json:"fromBlock"`
+	ToBlock   uint64 `json:"toBlock"`
+}
+
+// Replay delivers the logs of a block range again to the flux monitor job,
+// which consumes those it has not consumed yet, at most 10000 blocks at once,
+// and none of which the records of consumption may have been trimmed. The
+// replay runs in the background, so it responds 202 Accepted at once.
+// Example:
+//  "<application>/specs/:SpecID/log_replays"
+func (lcc *LogConsumptionsController) Replay(c *gin.Context) {
+	jobSpecID, ok := lcc.jobSpecID(c)
+	if !ok {
+		return
+	}
+
+	var request LogReplayRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+	if request.FromBlock > request.ToBlock {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("fromBlock must not be after toBlock"))
+		return
+	}
+	if request.ToBlock-request.FromBlock >= maxLogReplayBlocks {
+		jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("cannot replay more than %d blocks at once", maxLogReplayBlocks))
+		return
+	}
+	if err := lcc.requireRetained(request.FromBlock); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err := lcc.requireReplayable(jobSpecID); errors.Cause(err) == eth.ErrNotListening {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	replay := presenters.LogReplay{
+		JobID:     jobSpecID,
+		FromBlock: request.FromBlock,
+		ToBlock:   request.ToBlock,
+	}
+	go lcc.replay(replay)
+	jsonAPIResponseWithStatus(c, replay, "log_replays", http.StatusAccepted)
+}
+
+// replay delivers the logs of the replay to its job, logging the outcome, as
+// fetching the logs of many blocks outlasts the request.
+func (lcc *LogConsumptionsController) replay(r presenters.LogReplay) {
+	logs, err := lcc.App.ReplayLogs(r.JobID, r.FromBlock, r.ToBlock)
+	if err != nil {
+		logger.Web.Errorw("Failed to replay logs", "job_id", r.JobID.String(), "from_block", r.FromBlock, "to_block", r.ToBlock, "logs", logs, "error", err)
+		return
+	}
+	logger.Web.Infow("Replayed logs", "job_id", r.JobID.String(), "from_block", r.FromBlock, "to_block", r.ToBlock, "logs", logs)
+}
+
+// requireReplayable refuses to replay the logs of jobs which do not consume
+// logs through the log broadcaster, which only flux monitor jobs do.
+func (lcc *LogConsumptionsController) requireReplayable(jobSpecID *models.ID) error {
+	job, err := lcc.App.GetStore().FindJob(jobSpecID)
+	if err != nil {
+		return err
+	}
+	if len(job.InitiatorsFor(models.InitiatorFluxMonitor)) == 0 {
+		return errors.Wrapf(eth.ErrNotListening, "job %s: only the logs of fluxmonitor jobs can be replayed", jobSpecID)
+	}
+	return nil
+}
+
+// requireRetained refuses to replay the logs of blocks the records of the
+// consumption of which may have been trimmed already, as the job would
+// consume them again.
+func (lcc *LogConsumptionsController) requireRetained(fromBlock uint64) error {
+	store := lcc.App.GetStore()
+	depth := store.Config.LogConsumptionRetentionDepth()
+	if depth == 0 {
+		return nil
+	}
+	head, err := store.LastHead()
+	if err != nil {
+		return err
+	}
+	if head != nil && uint64(head.Number) > depth && fromBlock < uint64(head.Number)-depth {
+		return fmt.Errorf("cannot replay the logs of blocks more than LOG_CONSUMPTION_RETENTION_DEPTH (%d) blocks behind the head, as the records of their consumption are trimmed", depth)
+	}
+	return nil
+}
+
+// jobSpecID returns the ID of the job requested. It responds with an error
+// and returns false if the job does not exist.
+func (lcc *LogConsumptionsController) jobSpecID(c *gin.Context) (*models.ID, bool) {
+	id, err := models.NewIDFromString(c.Param("SpecID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return nil, false
+	}
+	if _, err := lcc.App.GetStore().FindJob(id); errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec not found"))
+		return nil, false
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,125 @@
+package web_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogConsumptionsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	for i := uint(0); i < 3; i++ {
+		lc := models.LogConsumption{BlockHash: cltest.NewHash(), LogIndex: i, JobID: job.ID}
+		_, err := app.Store.CreateLogConsumption(&lc)
+		require.NoError(t, err)
+	}
+
+	resp, cleanup := client.Get("/v2/specs/" + job.ID.String() + "/log_consumptions?size=2")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var links jsonapi.Links
+	var lcs []models.LogConsumption
+	err := web.ParsePaginatedResponse(cltest.ParseResponseBody(t, resp), &lcs, &links)
+	require.NoError(t, err)
+	require.Len(t, lcs, 2)
+	assert.NotEmpty(t, links["next"].Href)
+	assert.Equal(t, uint(2), lcs[0].LogIndex)
+
+	resp, cleanup = client.Get("/v2/specs/" + models.NewID().String() + "/log_consumptions")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
+
+func TestLogConsumptionsController_Destroy(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	lc := models.LogConsumption{BlockHash: cltest.NewHash(), JobID: job.ID}
+	_, err := app.Store.CreateLogConsumption(&lc)
+	require.NoError(t, err)
+
+	url := fmt.Sprintf("/v2/specs/%s/log_consumptions/%d", job.ID, lc.ID)
+	resp, cleanup := client.Delete(url)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNoContent)
+
+	exists, err := app.Store.LogConsumptionExists(&lc)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	resp, cleanup = client.Delete(url)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
+
+func TestLogConsumptionsController_Replay(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	url := "/v2/specs/" + job.ID.String() + "/log_replays"
+
+	resp, cleanup := client.Post(url, bytes.NewBufferString(`{"fromBlock":10,"toBlock":9}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Post(url, bytes.NewBufferString(`{"fromBlock":0,"toBlock":10000}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	app.Store.Config.Set("LOG_CONSUMPTION_RETENTION_DEPTH", 100)
+	require.NoError(t, app.Store.CreateHead(cltest.Head(1000)))
+	resp, cleanup = client.Post(url, bytes.NewBufferString(`{"fromBlock":800,"toBlock":950}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Post(url, bytes.NewBufferString(`{"fromBlock":910,"toBlock":950}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Post("/v2/specs/"+models.NewID().String()+"/log_replays", bytes.NewBufferString(`{"fromBlock":10,"toBlock":20}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	fmJob := cltest.NewJobWithFluxMonitorInitiator()
+	require.NoError(t, app.Store.CreateJob(&fmJob))
+	resp, cleanup = client.Post("/v2/specs/"+fmJob.ID.String()+"/log_replays", bytes.NewBufferString(`{"fromBlock":910,"toBlock":950}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusAccepted)
+
+	var replay presenters.LogReplay
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &replay))
+	assert.Equal(t, fmJob.ID, replay.JobID)
+	assert.Equal(t, uint64(910), replay.FromBlock)
+	assert.Equal(t, uint64(950), replay.ToBlock)
+}
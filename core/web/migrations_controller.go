@@ -0,0 +1,35 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/migrations"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// MigrationsController reports on the migrations of the database.
+type MigrationsController struct {
+	App chainlink.Application
+}
+
+// Show returns the schema version of the database and its pending migrations.
+// Example:
+//  "<application>/migrations"
+func (mc *MigrationsController) Show(c *gin.Context) {
+	var status migrations.Status
+	err := mc.App.GetStore().ORM.RawDB(func(db *gorm.DB) error {
+		var err error
+		status, err = migrations.GetStatus(db)
+		return err
+	})
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, presenters.MigrationStatus{Status: status}, "migrationStatus")
+}
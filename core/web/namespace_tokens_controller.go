@@ -0,0 +1,81 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// NamespaceTokensController manages the API tokens scoped to a namespace,
+// which the node's user hands out to the teams sharing the node.
+type NamespaceTokensController struct {
+	App chainlink.Application
+}
+
+// Index returns the namespace tokens, without their secrets.
+// Example:
+//  "<application>/namespace_tokens"
+func (ntc *NamespaceTokensController) Index(c *gin.Context) {
+	nts, err := ntc.App.GetStore().NamespaceTokens()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, nts, "namespace_tokens")
+}
+
+// Create generates a token giving the rights of a role in a namespace,
+// returning its secret, which is not shown again.
+// Example:
+//  "<application>/namespace_tokens"
+func (ntc *NamespaceTokensController) Create(c *gin.Context) {
+	request := models.NamespaceTokenRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err := request.Validate(); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	token := auth.NewToken()
+	nt, err := models.NewNamespaceToken(token, request)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := ntc.App.GetStore().CreateNamespaceToken(nt); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := models.NamespaceTokenAuthentication{
+		AccessKey: token.AccessKey,
+		Secret:    token.Secret,
+		Namespace: nt.Namespace,
+		Role:      nt.Role,
+	}
+	jsonAPIResponseWithStatus(c, resp, "namespace_tokens", http.StatusCreated)
+}
+
+// Destroy revokes a namespace token.
+// Example:
+//  "<application>/namespace_tokens/:AccessKey"
+func (ntc *NamespaceTokensController) Destroy(c *gin.Context) {
+	err := ntc.App.GetStore().DeleteNamespaceToken(c.Param("AccessKey"))
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("namespace token not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, nil, "namespace_tokens", http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package web_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createNamespaceToken(t *testing.T, client cltest.HTTPClientCleaner, namespace string, role models.NamespaceRole) map[string]string {
+	t.Helper()
+	body := []byte(`{"namespace":"` + namespace + `","role":"` + string(role) + `"}`)
+	resp, cleanup := client.Post("/v2/namespace_tokens", bytes.NewBuffer(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusCreated)
+
+	var nta models.NamespaceTokenAuthentication
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &nta))
+	assert.Equal(t, namespace, nta.Namespace)
+	assert.Equal(t, role, nta.Role)
+	return map[string]string{web.APIKey: nta.AccessKey, web.APISecret: nta.Secret}
+}
+
+func TestNamespaceTokensController_IsolatesNamespaces(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+	teamA := createNamespaceToken(t, client, "team-a", models.NamespaceEditor)
+	teamB := createNamespaceToken(t, client, "team-b", models.NamespaceViewer)
+
+	resp, cleanup := client.Post("/v2/specs", bytes.NewBuffer(cltest.MustReadFile(t, "testdata/hello_world_job.json")), teamA)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var j models.JobSpec
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &j))
+	assert.Equal(t, "team-a", j.Namespace)
+
+	resp, cleanup = client.Post("/v2/specs", bytes.NewBuffer(cltest.MustReadFile(t, "testdata/hello_world_job.json")))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	listJobs := func(headers ...map[string]string) []presenters.JobSpec {
+		resp, cleanup := client.Get("/v2/specs?size=10", headers...)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusOK)
+		var links jsonapi.Links
+		var jobs []presenters.JobSpec
+		require.NoError(t, web.ParsePaginatedResponse(cltest.ParseResponseBody(t, resp), &jobs, &links))
+		return jobs
+	}
+	assert.Len(t, listJobs(), 2)
+	require.Len(t, listJobs(teamA), 1)
+	assert.Equal(t, j.ID, listJobs(teamA)[0].ID)
+	assert.Len(t, listJobs(teamB), 0)
+
+	resp, cleanup = client.Get("/v2/specs/"+j.ID.String(), teamB)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	resp, cleanup = client.Post("/v2/specs", bytes.NewBuffer(cltest.MustReadFile(t, "testdata/hello_world_job.json")), teamB)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusForbidden)
+
+	resp, cleanup = client.Get("/v2/config", teamA)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusForbidden)
+
+	resp, cleanup = client.Patch("/v2/specs/"+j.ID.String(), bytes.NewBuffer(cltest.MustReadFile(t, "testdata/hello_world_job.json")), teamA)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+}
+
+func TestNamespaceTokensController_IndexDestroy(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+	headers := createNamespaceToken(t, client, "team-a", models.NamespaceViewer)
+
+	resp, cleanup := client.Post("/v2/namespace_tokens", bytes.NewBufferString(`{"namespace":"Team A","role":"viewer"}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get("/v2/namespace_tokens")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var nts []models.NamespaceToken
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &nts))
+	require.Len(t, nts, 1)
+	assert.Equal(t, headers[web.APIKey], nts[0].AccessKey)
+
+	resp, cleanup = client.Delete("/v2/namespace_tokens/" + nts[0].AccessKey)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNoContent)
+
+	resp, cleanup = client.Delete("/v2/namespace_tokens/" + nts[0].AccessKey)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
+
+func TestNamespaceTokensController_RotatesBridgeTokens(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+	teamA := createNamespaceToken(t, client, "team-a", models.NamespaceEditor)
+	teamB := createNamespaceToken(t, client, "team-b", models.NamespaceEditor)
+	viewerA := createNamespaceToken(t, client, "team-a", models.NamespaceViewer)
+
+	resp, cleanup := client.Post("/v2/bridge_types", bytes.NewBufferString(`{"name":"rotating","url":"http://example.com"}`), teamA)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	resp, cleanup = client.Post("/v2/bridge_types/rotating/token_rotation", nil, teamB)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	resp, cleanup = client.Post("/v2/bridge_types/rotating/token_rotation", nil, viewerA)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusForbidden)
+
+	resp, cleanup = client.Post("/v2/bridge_types/rotating/token_rotation", nil, teamA)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+}
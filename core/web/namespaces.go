@@ -0,0 +1,84 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// namespacedRoutes are the routes namespace tokens may use, those of the
+// jobs, bridges and keys, which only show them their namespace's. The
+// routes which change records are only open to editors.
+var namespacedRoutes = map[string]bool{
+	"GET /v2/specs":                                    true,
+	"POST /v2/specs":                                   true,
+	"GET /v2/specs/:SpecID":                            true,
+	"PATCH /v2/specs/:SpecID":                          true,
+	"DELETE /v2/specs/:SpecID":                         true,
+	"GET /v2/bridge_types":                             true,
+	"POST /v2/bridge_types":                            true,
+	"GET /v2/bridge_types/:BridgeName":                 true,
+	"PATCH /v2/bridge_types/:BridgeName":               true,
+	"DELETE /v2/bridge_types/:BridgeName":              true,
+	"POST /v2/bridge_types/:BridgeName/token_rotation": true,
+	"GET /v2/user/balances":                            true,
+}
+
+// RequireNamespaceRights only lets a namespace token through to the
+// namespaced routes, and to those changing records if it is an editor's.
+// Users are let through to every route.
+func RequireNamespaceRights() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		nt, ok := authenticatedNamespaceToken(c)
+		if !ok {
+			c.Next()
+			return
+		}
+		route := fmt.Sprintf("%s %s", c.Request.Method, c.FullPath())
+		if !namespacedRoutes[route] {
+			jsonAPIError(c, http.StatusForbidden, errors.New("namespace tokens cannot access this route"))
+			c.Abort()
+			return
+		}
+		if c.Request.Method != http.MethodGet && !nt.Role.CanMutate() {
+			jsonAPIError(c, http.StatusForbidden, fmt.Errorf("the %s role cannot change namespace %s", nt.Role, nt.Namespace))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestNamespace returns the namespace the request is scoped to: the
+// namespace of its token, or the namespace query parameter given by a user.
+// It returns false if the request is not scoped to a namespace, a user
+// seeing every namespace.
+func requestNamespace(c *gin.Context) (string, bool) {
+	if nt, ok := authenticatedNamespaceToken(c); ok {
+		return nt.Namespace, true
+	}
+	return c.GetQuery("namespace")
+}
+
+// visibleIn returns true if a record in namespace ns is visible to the
+// request: always to users, and to namespace tokens if it is in theirs.
+func visibleIn(c *gin.Context, ns string) bool {
+	nt, ok := authenticatedNamespaceToken(c)
+	return !ok || nt.Namespace == ns
+}
+
+// namespaceFor returns the namespace a record created by the request is put
+// in, requested being the one given in its body: that of a namespace token,
+// which may not ask for another, or the one requested by a user.
+func namespaceFor(c *gin.Context, requested string) (string, error) {
+	nt, ok := authenticatedNamespaceToken(c)
+	if !ok {
+		return requested, nil
+	}
+	if requested != "" && requested != nt.Namespace {
+		return "", fmt.Errorf("namespace tokens of %s cannot create records in namespace %s", nt.Namespace, requested)
+	}
+	return nt.Namespace, nil
+}
@@ -0,0 +1,28 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OffChainReportingKeysController lists the key bundles the node uses for
+// off-chain reporting.
+type OffChainReportingKeysController struct {
+	App chainlink.Application
+}
+
+// Index lists the peer ID and signing address of each off-chain reporting
+// key bundle, for configuring the node as an oracle of other nodes' jobs.
+// Example:
+//  "<application>/off_chain_reporting_keys"
+func (ocrkc *OffChainReportingKeysController) Index(c *gin.Context) {
+	bundles, err := ocrkc.App.GetStore().FindEncryptedOCRKeyBundles()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, bundles, "off chain reporting keys")
+}
@@ -0,0 +1,37 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/ocrkey"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffChainReportingKeysController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	kb, err := app.Store.OCRKeyStore.CreateKey(cltest.Password, ocrkey.FastScryptParams)
+	require.NoError(t, err)
+
+	resp, cleanup := client.Get("/v2/off_chain_reporting_keys")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	b := cltest.ParseResponseBody(t, resp)
+	var bundles []models.EncryptedOCRKeyBundle
+	require.NoError(t, web.ParseJSONAPIResponse(b, &bundles))
+	require.Len(t, bundles, 1)
+	assert.Equal(t, kb.ID(), bundles[0].PeerID)
+	assert.Equal(t, kb.SigningAddress(), bundles[0].SigningAddress)
+	assert.NotContains(t, string(b), "encryptedKeys")
+}
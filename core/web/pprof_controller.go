@@ -0,0 +1,97 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// defaultCPUProfileDuration is how long the CPU is profiled for when no
+// duration is given.
+const defaultCPUProfileDuration = 5 * time.Second
+
+// PprofController serves the profiles of the running node to authenticated
+// users, so that reports of memory growth and goroutine leaks can include
+// them, without the unauthenticated pprof routes of dev mode.
+type PprofController struct {
+	App chainlink.Application
+}
+
+// Show responds with the profile of the given name, as read by go tool
+// pprof, or as text if debug is set. The CPU profile is taken over the given
+// duration, which must end before the server's write timeout.
+// Example:
+//  "<application>/debug/pprof/heap?debug=1"
+//  "<application>/debug/pprof/cpu?duration=5s"
+func (pc *PprofController) Show(c *gin.Context) {
+	name := c.Param("Profile")
+	if name == "cpu" {
+		pc.cpu(c)
+		return
+	}
+
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		jsonAPIError(c, http.StatusNotFound, fmt.Errorf("Profile %s not found", name))
+		return
+	}
+	debug, err := strconv.Atoi(c.DefaultQuery("debug", "0"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid debug"))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := profile.WriteTo(&buf, debug); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	respondWithProfile(c, name, debug, buf.Bytes())
+}
+
+func (pc *PprofController) cpu(c *gin.Context) {
+	duration := defaultCPUProfileDuration
+	if d := c.Query("duration"); d != "" {
+		var err error
+		if duration, err = time.ParseDuration(d); err != nil || duration <= 0 {
+			jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("invalid duration '%s'", d))
+			return
+		}
+	}
+	srv, ok := c.Request.Context().Value(http.ServerContextKey).(*http.Server)
+	if ok && srv.WriteTimeout > 0 && duration >= srv.WriteTimeout {
+		jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("duration must be less than the server's write timeout of %v", srv.WriteTimeout))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		jsonAPIError(c, http.StatusConflict, errors.Wrap(err, "unable to profile the CPU"))
+		return
+	}
+	select {
+	case <-time.After(duration):
+	case <-c.Request.Context().Done():
+	}
+	pprof.StopCPUProfile()
+	respondWithProfile(c, "cpu", 0, buf.Bytes())
+}
+
+func respondWithProfile(c *gin.Context, name string, debug int, profile []byte) {
+	contentType := "application/octet-stream"
+	filename := name + ".pprof"
+	if debug > 0 {
+		contentType = "text/plain; charset=utf-8"
+		filename = name + ".txt"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, profile)
+}
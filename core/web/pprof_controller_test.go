@@ -0,0 +1,54 @@
+package web_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPprofController_Show(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+	}{
+		{"heap", "/v2/debug/pprof/heap", http.StatusOK, "application/octet-stream"},
+		{"goroutine stacks", "/v2/debug/pprof/goroutine?debug=2", http.StatusOK, "text/plain; charset=utf-8"},
+		{"cpu", "/v2/debug/pprof/cpu?duration=100ms", http.StatusOK, "application/octet-stream"},
+		{"unknown profile", "/v2/debug/pprof/bogus", http.StatusNotFound, ""},
+		{"invalid debug", "/v2/debug/pprof/heap?debug=x", http.StatusUnprocessableEntity, ""},
+		{"invalid duration", "/v2/debug/pprof/cpu?duration=-1s", http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, cleanup := client.Get(test.path)
+			defer cleanup()
+			cltest.AssertServerResponse(t, resp, test.status)
+			if test.status != http.StatusOK {
+				return
+			}
+			assert.Equal(t, test.contentType, resp.Header.Get("Content-Type"))
+			b, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.NotEmpty(t, b)
+		})
+	}
+
+	resp, err := http.Get(app.Server.URL + "/v2/debug/pprof/heap")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,119 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// RequesterAllowlistsController manages the requesters runlog jobs service:
+// the global allowlist, which applies to every runlog job, and the allowlist
+// of each job.
+type RequesterAllowlistsController struct {
+	App chainlink.Application
+}
+
+// Index lists the addresses on the global requester allowlist, or on that of
+// a job.
+// Example:
+//  "<application>/requester_allowlist"
+//  "<application>/specs/:SpecID/requester_allowlist"
+func (rac *RequesterAllowlistsController) Index(c *gin.Context) {
+	jobSpecID, ok := rac.jobSpecID(c)
+	if !ok {
+		return
+	}
+	requesters, err := rac.App.GetStore().AllowedRequesters(jobSpecID)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, requesters, "allowed_requesters")
+}
+
+// Create adds an address to the global requester allowlist, or to that of a
+// job, and returns the allowlist. From then on, the runlog jobs it applies to
+// only service the requesters on it.
+// Example:
+//  "<application>/requester_allowlist"
+//  "<application>/specs/:SpecID/requester_allowlist"
+func (rac *RequesterAllowlistsController) Create(c *gin.Context) {
+	jobSpecID, ok := rac.jobSpecID(c)
+	if !ok {
+		return
+	}
+	request := models.AllowedRequesterRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	} else if request.Address == (common.Address{}) {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("address must be present"))
+		return
+	}
+
+	store := rac.App.GetStore()
+	if err := store.AllowRequester(jobSpecID, request.Address); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	requesters, err := store.AllowedRequesters(jobSpecID)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, requesters, "allowed_requesters", http.StatusCreated)
+}
+
+// Destroy removes an address from the global requester allowlist, or from
+// that of a job.
+// Example:
+//  "<application>/requester_allowlist/:Address"
+//  "<application>/specs/:SpecID/requester_allowlist/:Address"
+func (rac *RequesterAllowlistsController) Destroy(c *gin.Context) {
+	jobSpecID, ok := rac.jobSpecID(c)
+	if !ok {
+		return
+	}
+	if !common.IsHexAddress(c.Param("Address")) {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("invalid address"))
+		return
+	}
+
+	err := rac.App.GetStore().DisallowRequester(jobSpecID, common.HexToAddress(c.Param("Address")))
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("address is not on the requester allowlist"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, nil, "allowed_requesters", http.StatusNoContent)
+}
+
+// jobSpecID returns the ID of the job whose allowlist is requested, or nil
+// for the global allowlist. It responds with an error and returns false if
+// the job does not exist.
+func (rac *RequesterAllowlistsController) jobSpecID(c *gin.Context) (*models.ID, bool) {
+	if c.Param("SpecID") == "" {
+		return nil, true
+	}
+	id, err := models.NewIDFromString(c.Param("SpecID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return nil, false
+	}
+	if _, err := rac.App.GetStore().FindJob(id); errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec not found"))
+		return nil, false
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return id, true
+}
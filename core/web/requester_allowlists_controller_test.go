@@ -0,0 +1,64 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequesterAllowlistsController_CreateIndexDestroy(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithRunLogInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	jobPath := "/v2/specs/" + job.ID.String() + "/requester_allowlist"
+	requester := common.HexToAddress("0x9fbda871d559710256a2502a2517b794b482db40")
+
+	for _, path := range []string{"/v2/requester_allowlist", jobPath} {
+		body, err := json.Marshal(models.AllowedRequesterRequest{Address: requester})
+		require.NoError(t, err)
+		resp, cleanup := client.Post(path, bytes.NewBuffer(body))
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusCreated)
+
+		resp, cleanup = client.Get(path)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusOK)
+		var requesters []models.AllowedRequester
+		require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &requesters))
+		require.Len(t, requesters, 1)
+		assert.Equal(t, requester, requesters[0].Address)
+	}
+
+	allowed, err := app.Store.RequesterAllowed(job.ID, requester)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = app.Store.RequesterAllowed(job.ID, cltest.NewAddress())
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	resp, cleanup := client.Delete(jobPath + "/" + requester.Hex())
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNoContent)
+	resp, cleanup = client.Delete(jobPath + "/" + requester.Hex())
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	resp, cleanup = client.Get("/v2/specs/" + models.NewID().String() + "/requester_allowlist")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
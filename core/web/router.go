@@ -28,6 +28,7 @@ import (
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/gobuffalo/packr"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/ulule/limiter"
 	mgin "github.com/ulule/limiter/drivers/middleware/gin"
 	"github.com/ulule/limiter/drivers/store/memory"
@@ -45,7 +46,7 @@ func init() {
 }
 
 func printRoutes(httpMethod, absolutePath, handlerName string, nuHandlers int) {
-	logger.Debugf("%-6s %-25s --> %s (%d handlers)", httpMethod, absolutePath, handlerName, nuHandlers)
+	logger.Web.Debugf("%-6s %-25s --> %s (%d handlers)", httpMethod, absolutePath, handlerName, nuHandlers)
 }
 
 const (
@@ -57,6 +58,8 @@ const (
 	SessionUserKey = "user"
 	// SessionExternalInitiatorKey is the External Initiator key in the session map
 	SessionExternalInitiatorKey = "external_initiator"
+	// SessionNamespaceTokenKey is the Namespace Token key in the session map
+	SessionNamespaceTokenKey = "namespace_token"
 )
 
 func explorerStatus(app chainlink.Application) gin.HandlerFunc {
@@ -72,6 +75,24 @@ func explorerStatus(app chainlink.Application) gin.HandlerFunc {
 	}
 }
 
+// refuseWritesWhenReadOnly responds 503 to the requests which would change
+// anything once the node has lost its lock on the database, while letting
+// those which only read through.
+func refuseWritesWhenReadOnly(app chainlink.Application) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if app.GetStore().ORM.ReadOnly() {
+				jsonAPIError(c, http.StatusServiceUnavailable, orm.ErrReadOnly)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
 // Router listens and responds to requests to the node for valid paths.
 func Router(app chainlink.Application) *gin.Engine {
 	engine := gin.New()
@@ -79,13 +100,22 @@ func Router(app chainlink.Application) *gin.Engine {
 	config := store.Config
 	secret, err := config.SessionSecret()
 	if err != nil {
-		logger.Panic(err)
+		logger.Web.Panic(err)
 	}
 	sessionStore := sessions.NewCookieStore(secret)
 	sessionStore.Options(config.SessionOptions())
 	cors := uiCorsHandler(config)
+	allowlists, err := newIPAllowlists(app)
+	if err != nil {
+		logger.Web.Panic(err)
+	}
 
-	prometheus.Use(engine)
+	prometheus.Engine = engine
+	engine.GET(prometheus.MetricsPath,
+		rateLimiter(1*time.Minute, 1000),
+		requireAllowedMetricsIP(app, allowlists),
+		gin.WrapH(promhttp.Handler()),
+	)
 	engine.Use(
 		limits.RequestSizeLimiter(config.DefaultHTTPLimit()),
 		loggerFunc(),
@@ -94,18 +124,22 @@ func Router(app chainlink.Application) *gin.Engine {
 		secureMiddleware(config),
 		prometheus.Instrument(),
 	)
-	engine.Use(helmet.Default())
+	engine.Use(helmetMiddleware(config)...)
+
+	healthRoutes(app, engine)
 
 	api := engine.Group(
 		"/",
 		rateLimiter(1*time.Minute, 1000),
+		requireAllowedAPIIP(app, allowlists),
 		sessions.Sessions(SessionName, sessionStore),
 		explorerStatus(app),
+		refuseWritesWhenReadOnly(app),
 	)
 
-	metricRoutes(app, api)
+	metricRoutes(app, api, allowlists)
 	sessionRoutes(app, api)
-	v2Routes(app, api)
+	v2Routes(app, api, allowlists)
 
 	guiAssetRoutes(app.NewBox(), engine)
 
@@ -122,14 +156,57 @@ func rateLimiter(period time.Duration, limit int64) gin.HandlerFunc {
 }
 
 // secureOptions configure security options for the secure middleware, mostly
-// for TLS redirection
+// for TLS redirection and the CSP
 func secureOptions(config orm.ConfigReader) secure.Options {
 	return secure.Options{
-		FrameDeny:     true,
-		IsDevelopment: config.Dev(),
-		SSLRedirect:   config.TLSRedirect(),
-		SSLHost:       config.TLSHost(),
+		FrameDeny:             !allowsFraming(config),
+		IsDevelopment:         config.Dev(),
+		SSLRedirect:           config.TLSRedirect(),
+		SSLHost:               config.TLSHost(),
+		ContentSecurityPolicy: config.ContentSecurityPolicy(),
+	}
+}
+
+// allowsFraming returns true if the CSP says which pages may embed the node,
+// which X-Frame-Options would otherwise deny outright.
+func allowsFraming(config orm.ConfigReader) bool {
+	return strings.Contains(config.ContentSecurityPolicy(), "frame-ancestors")
+}
+
+// helmetMiddleware sets the remaining security headers.
+func helmetMiddleware(config orm.ConfigReader) []gin.HandlerFunc {
+	handlers := []gin.HandlerFunc{
+		helmet.NoSniff(),
+		helmet.DNSPrefetchControl(),
+		helmet.IENoOpen(),
+		helmet.XSSFilter(),
 	}
+	if !allowsFraming(config) {
+		handlers = append(handlers, helmet.FrameGuard())
+	}
+	if hsts := hstsHeader(config); hsts != "" {
+		handlers = append(handlers, func(c *gin.Context) {
+			c.Writer.Header().Set("Strict-Transport-Security", hsts)
+		})
+	}
+	return handlers
+}
+
+// hstsHeader returns the Strict-Transport-Security header, or nothing if
+// HSTS_MAX_AGE is zero.
+func hstsHeader(config orm.ConfigReader) string {
+	maxAge := config.HSTSMaxAge().Duration()
+	if maxAge == 0 {
+		return ""
+	}
+	header := fmt.Sprintf("max-age=%d", int64(maxAge/time.Second))
+	if config.HSTSIncludeSubdomains() {
+		header += "; includeSubDomains"
+	}
+	if config.HSTSPreload() {
+		header += "; preload"
+	}
+	return header
 }
 
 // secureMiddleware adds a TLS handler and redirector, to button up security
@@ -155,8 +232,16 @@ func secureMiddleware(config orm.ConfigReader) gin.HandlerFunc {
 
 	return secureFunc
 }
-func metricRoutes(app chainlink.Application, r *gin.RouterGroup) {
-	group := r.Group("/debug", RequireAuth(app.GetStore(), AuthenticateBySession))
+
+// healthRoutes are not rate limited, since orchestrators probe them often.
+func healthRoutes(app chainlink.Application, engine *gin.Engine) {
+	hc := HealthController{app}
+	engine.GET("/health", hc.Liveness)
+	engine.GET("/readiness", hc.Readiness)
+}
+
+func metricRoutes(app chainlink.Application, r *gin.RouterGroup, allowlists ipAllowlists) {
+	group := r.Group("/debug", requireAllowedMetricsIP(app, allowlists), RequireAuth(app.GetStore(), AuthenticateBySession))
 	group.GET("/vars", expvar.Handler())
 
 	if app.GetStore().Config.Dev() {
@@ -192,18 +277,22 @@ func sessionRoutes(app chainlink.Application, r *gin.RouterGroup) {
 	auth.DELETE("/sessions", sc.Destroy)
 }
 
-func v2Routes(app chainlink.Application, r *gin.RouterGroup) {
+func v2Routes(app chainlink.Application, r *gin.RouterGroup, allowlists ipAllowlists) {
 	unauthedv2 := r.Group("/v2")
 
 	jr := JobRunsController{app}
 	unauthedv2.PATCH("/runs/:RunID", jr.Update)
+	unauthedv2.PATCH("/runs/:RunID/callback", jr.Callback)
 
 	sa := ServiceAgreementsController{app}
 	unauthedv2.POST("/service_agreements", sa.Create)
 
 	j := JobSpecsController{app}
 
-	authv2 := r.Group("/v2", RequireAuth(app.GetStore(), AuthenticateByToken, AuthenticateBySession))
+	authv2 := r.Group("/v2",
+		RequireAuth(app.GetStore(), AuthenticateByToken, AuthenticateByNamespaceToken, AuthenticateBySession),
+		RequireNamespaceRights(),
+	)
 	{
 		uc := UserController{app}
 		authv2.PATCH("/user/password", uc.UpdatePassword)
@@ -211,42 +300,164 @@ func v2Routes(app chainlink.Application, r *gin.RouterGroup) {
 		authv2.POST("/user/token", uc.NewAPIToken)
 		authv2.POST("/user/token/delete", uc.DeleteAPIToken)
 
+		ntc := NamespaceTokensController{app}
+		authv2.GET("/namespace_tokens", ntc.Index)
+		authv2.POST("/namespace_tokens", ntc.Create)
+		authv2.DELETE("/namespace_tokens/:AccessKey", ntc.Destroy)
+
 		eia := ExternalInitiatorsController{app}
+		dlc := ExternalInitiatorDeadLettersController{app}
+		authv2.GET("/external_initiator_dead_letters", dlc.Index)
+		authv2.POST("/external_initiator_dead_letters/:ID/retry", dlc.Retry)
+
+		authv2.GET("/external_initiators", eia.Index)
+		authv2.GET("/external_initiators/:Name", eia.Show)
 		authv2.POST("/external_initiators", eia.Create)
 		authv2.DELETE("/external_initiators/:Name", eia.Destroy)
 
+		sc := SecretsController{app}
+		authv2.GET("/secrets", sc.Index)
+		authv2.POST("/secrets", sc.Create)
+		authv2.DELETE("/secrets/:Name", sc.Destroy)
+
+		wmc := WasmModulesController{app}
+		authv2.GET("/wasm_modules", wmc.Index)
+		authv2.POST("/wasm_modules", wmc.Create)
+		authv2.DELETE("/wasm_modules/:Name", wmc.Destroy)
+
+		bjs := BulkJobSpecsController{app}
+		authv2.POST("/bulk_specs", bjs.Create)
+		authv2.POST("/bulk_specs/archive", bjs.Archive)
+		authv2.POST("/bulk_specs/restore", bjs.Restore)
+
 		authv2.POST("/specs", j.Create)
 		authv2.GET("/specs", paginatedRequest(j.Index))
 		authv2.GET("/specs/:SpecID", j.Show)
+		authv2.PATCH("/specs/:SpecID", j.Update)
 		authv2.DELETE("/specs/:SpecID", j.Destroy)
 
+		jsv := JobSpecVersionsController{app}
+		authv2.GET("/specs/:SpecID/versions", jsv.Index)
+		authv2.GET("/specs/:SpecID/versions/:Version/diff", jsv.Diff)
+		authv2.POST("/specs/:SpecID/versions/:Version/rollback", jsv.Rollback)
+
+		jbc := JobBundlesController{app}
+		authv2.GET("/specs/:SpecID/bundle", jbc.Export)
+		authv2.POST("/job_bundles/import", jbc.Import)
+
+		cpc := CronPreviewsController{app}
+		authv2.POST("/cron_previews", cpc.Create)
+
+		fdrs := FluxDryRunSubmissionsController{app}
+		authv2.GET("/specs/:SpecID/dry_run_submissions", paginatedRequest(fdrs.Index))
+
+		rrvc := RunResultValuesController{app}
+		authv2.GET("/specs/:SpecID/results", paginatedRequest(rrvc.Index))
+
+		lcc := LogConsumptionsController{app}
+		authv2.GET("/specs/:SpecID/log_consumptions", paginatedRequest(lcc.Index))
+		authv2.DELETE("/specs/:SpecID/log_consumptions/:ID", lcc.Destroy)
+		authv2.POST("/specs/:SpecID/log_replays", lcc.Replay)
+
+		rac := RequesterAllowlistsController{app}
+		authv2.GET("/requester_allowlist", rac.Index)
+		authv2.POST("/requester_allowlist", rac.Create)
+		authv2.DELETE("/requester_allowlist/:Address", rac.Destroy)
+		authv2.GET("/specs/:SpecID/requester_allowlist", rac.Index)
+		authv2.POST("/specs/:SpecID/requester_allowlist", rac.Create)
+		authv2.DELETE("/specs/:SpecID/requester_allowlist/:Address", rac.Destroy)
+
 		authv2.GET("/runs", paginatedRequest(jr.Index))
 		authv2.GET("/runs/:RunID", jr.Show)
+		authv2.GET("/runs/:RunID/signed_result", jr.SignedResult)
 		authv2.PUT("/runs/:RunID/cancellation", jr.Cancel)
+		authv2.PATCH("/runs/:RunID/cancel", jr.Cancel)
+		authv2.POST("/runs/:RunID/resume", jr.Resume)
+
+		rrc := RunResumptionsController{app}
+		authv2.GET("/run_resumptions", paginatedRequest(rrc.Index))
+
+		kfc := KeyFundingsController{app}
+		authv2.GET("/key_fundings", paginatedRequest(kfc.Index))
 
+		ruc := RunUpdatesController{app}
+		authv2.GET("/run_updates", ruc.Stream)
+
+		authv2.GET("/service_agreements", paginatedRequest(sa.Index))
 		authv2.GET("/service_agreements/:SAID", sa.Show)
+		authv2.DELETE("/service_agreements/:SAID", sa.Destroy)
+		authv2.GET("/service_agreements/:SAID/payments", paginatedRequest(sa.Payments))
+		authv2.GET("/service_agreements/:SAID/reconciliation", sa.Reconciliation)
 
 		bt := BridgeTypesController{app}
 		authv2.GET("/bridge_types", paginatedRequest(bt.Index))
 		authv2.POST("/bridge_types", bt.Create)
 		authv2.GET("/bridge_types/:BridgeName", bt.Show)
 		authv2.PATCH("/bridge_types/:BridgeName", bt.Update)
+		authv2.POST("/bridge_types/:BridgeName/token_rotation", bt.RotateTokens)
 		authv2.DELETE("/bridge_types/:BridgeName", bt.Destroy)
 
+		ec := EarningsController{app}
+		authv2.GET("/stats/earnings", ec.Index)
+
+		guc := GasUsagesController{app}
+		authv2.GET("/stats/gas_usage", guc.Index)
+
+		rsc := RunStatsController{app}
+		authv2.GET("/stats/runs", rsc.Index)
+
+		fhc := FeedHealthsController{app}
+		authv2.GET("/feed_healths", fhc.Index)
+
+		ocrkc := OffChainReportingKeysController{app}
+		authv2.GET("/off_chain_reporting_keys", ocrkc.Index)
+
+		vrc := VRFRequestsController{app}
+		authv2.GET("/vrf_requests", paginatedRequest(vrc.Index))
+		authv2.GET("/vrf_requests/:ID", vrc.Show)
+
+		vkc := VRFKeysController{app}
+		authv2.GET("/vrf_keys", vkc.Index)
+		authv2.POST("/vrf_keys", vkc.Create)
+		authv2.POST("/vrf_keys/import", vkc.Import)
+		authv2.POST("/vrf_keys/:PublicKey/export", vkc.Export)
+		authv2.DELETE("/vrf_keys/:PublicKey", vkc.Delete)
+
+		ccc := ClientCertificatesController{app}
+		authv2.GET("/client_certificates", ccc.Index)
+		authv2.POST("/client_certificates", ccc.Create)
+		authv2.DELETE("/client_certificates/:Name", ccc.Destroy)
+
 		w := WithdrawalsController{app}
 		authv2.POST("/withdrawals", w.Create)
 
 		ts := TransfersController{app}
 		authv2.POST("/transfers", ts.Create)
 
+		kc := KeysController{app}
+		authv2.POST("/keys/:address/rotate", kc.Rotate)
+		authv2.POST("/external_keys", kc.CreateExternal)
 		if app.GetStore().Config.Dev() {
-			kc := KeysController{app}
 			authv2.POST("/keys", kc.Create)
 		}
 
+		kbc := KeyBundlesController{app}
+		authv2.POST("/key_bundles/export", kbc.Export)
+		authv2.POST("/key_bundles/import", kbc.Import)
+
 		cc := ConfigController{app}
 		authv2.GET("/config", cc.Show)
 		authv2.PATCH("/config", cc.Patch)
+		authv2.GET("/config/changes", paginatedRequest(cc.Changes))
+
+		avc := AccessViolationsController{app}
+		authv2.GET("/access_violations", paginatedRequest(avc.Index))
+
+		mc := MigrationsController{app}
+		authv2.GET("/migrations", mc.Show)
+
+		chc := ChainController{app}
+		authv2.GET("/chain/head", chc.Head)
 
 		tas := TxAttemptsController{app}
 		authv2.GET("/tx_attempts", paginatedRequest(tas.Index))
@@ -254,9 +465,16 @@ func v2Routes(app chainlink.Application, r *gin.RouterGroup) {
 		txs := TransactionsController{app}
 		authv2.GET("/transactions", paginatedRequest(txs.Index))
 		authv2.GET("/transactions/:TxHash", txs.Show)
+		authv2.PUT("/transactions/:TxHash/gas_bump", txs.BumpGas)
+		authv2.PUT("/transactions/:TxHash/cancellation", txs.Cancel)
+		authv2.POST("/transaction_resends", txs.Resend)
+
+		ppc := PprofController{app}
+		authv2.GET("/debug/pprof/:Profile", ppc.Show)
 
 		bdc := BulkDeletesController{app}
 		authv2.DELETE("/bulk_delete_runs", bdc.Delete)
+		authv2.POST("/bulk_update_runs", bdc.Update)
 	}
 
 	ping := PingController{app}
@@ -264,7 +482,7 @@ func v2Routes(app chainlink.Application, r *gin.RouterGroup) {
 		AuthenticateExternalInitiator,
 		AuthenticateByToken,
 		AuthenticateBySession,
-	))
+	), requireAllowedExternalInitiatorIP(app, allowlists))
 	userOrEI.POST("/specs/:SpecID/runs", jr.Create)
 	userOrEI.GET("/ping", ping.Show)
 }
@@ -303,7 +521,7 @@ func guiAssetRoutes(box packr.Box, engine *gin.Engine) {
 			if err == os.ErrNotExist {
 				c.AbortWithStatus(http.StatusNotFound)
 			} else {
-				logger.Errorf("failed to open static file '%s': %+v", path, err)
+				logger.Web.Errorf("failed to open static file '%s': %+v", path, err)
 				c.AbortWithStatus(http.StatusInternalServerError)
 			}
 			return
@@ -324,7 +542,7 @@ func loggerFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		buf, err := ioutil.ReadAll(c.Request.Body)
 		if err != nil {
-			logger.Error("Web request log error: ", err.Error())
+			logger.Web.Error("Web request log error: ", err.Error())
 			// Implicitly relies on limits.RequestSizeLimiter
 			// overriding of c.Request.Body to abort gin's Context
 			// inside ioutil.ReadAll.
@@ -342,7 +560,7 @@ func loggerFunc() gin.HandlerFunc {
 		c.Next()
 		end := time.Now()
 
-		logger.Infow(fmt.Sprintf("%s %s", c.Request.Method, c.Request.URL.Path),
+		logger.Web.Infow(fmt.Sprintf("%s %s", c.Request.Method, c.Request.URL.Path),
 			"method", c.Request.Method,
 			"status", c.Writer.Status(),
 			"path", c.Request.URL.Path,
@@ -368,6 +586,9 @@ func uiCorsHandler(config orm.ConfigReader) gin.HandlerFunc {
 	if config.AllowOrigins() == "*" {
 		c.AllowAllOrigins = true
 	} else if allowOrigins := strings.Split(config.AllowOrigins(), ","); len(allowOrigins) > 0 {
+		for i := range allowOrigins {
+			allowOrigins[i] = strings.TrimSpace(allowOrigins[i])
+		}
 		c.AllowOrigins = allowOrigins
 	}
 	return cors.New(c)
@@ -377,7 +598,7 @@ func readBody(reader io.Reader) string {
 	buf := new(bytes.Buffer)
 	_, err := buf.ReadFrom(reader)
 	if err != nil {
-		logger.Warn("unable to read from body for sanitization: ", err)
+		logger.Web.Warn("unable to read from body for sanitization: ", err)
 		return "*FAILED TO READ BODY*"
 	}
 
@@ -387,7 +608,7 @@ func readBody(reader io.Reader) string {
 
 	s, err := readSanitizedJSON(buf)
 	if err != nil {
-		logger.Warn("unable to sanitize json for logging: ", err)
+		logger.Web.Warn("unable to sanitize json for logging: ", err)
 		return "*FAILED TO READ BODY*"
 	}
 	return s
@@ -435,6 +656,7 @@ var blacklist = map[string]struct{}{
 	"oldpassword":          struct{}{},
 	"current_password":     struct{}{},
 	"new_account_password": struct{}{},
+	"token":                struct{}{},
 }
 
 func isBlacklisted(k string) bool {
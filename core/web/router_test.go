@@ -187,3 +187,24 @@ func TestRouter_GinHelmetHeaders(t *testing.T) {
 			"wrong header for helmet's %s handler", tt.HelmetName)
 	}
 }
+
+func TestRouter_ConfiguredSecurityHeaders(t *testing.T) {
+	config, cfgCleanup := cltest.NewConfig(t)
+	defer cfgCleanup()
+	config.Set("CONTENT_SECURITY_POLICY", "frame-ancestors https://dashboard.example.com")
+	config.Set("HSTS_MAX_AGE", "8760h")
+	config.Set("HSTS_INCLUDE_SUBDOMAINS", false)
+	config.Set("HSTS_PRELOAD", true)
+	app, cleanup := cltest.NewApplicationWithConfigAndKey(t, config, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	router := web.Router(app)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	res, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "frame-ancestors https://dashboard.example.com", res.Header.Get("Content-Security-Policy"))
+	assert.Equal(t, "", res.Header.Get("X-Frame-Options"))
+	assert.Equal(t, "max-age=31536000; preload", res.Header.Get("Strict-Transport-Security"))
+}
@@ -0,0 +1,79 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// RunResultValuesController returns the series of the values a field of the
+// results of the runs of a job took, such as the answers of a feed.
+type RunResultValuesController struct {
+	App chainlink.Application
+}
+
+// Index returns the values the field at path took in the results of the runs
+// of a JobSpec created from since up to until, oldest first. since and until
+// are RFC3339 times, defaulting to the first run and now.
+// Example:
+//  "<application>/specs/:SpecID/results?path=data.result&since=2020-06-01T00:00:00Z"
+func (rrvc *RunResultValuesController) Index(c *gin.Context, size, page, offset int) {
+	id, err := models.NewIDFromString(c.Param("SpecID"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if c.Query("path") == "" {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("path is required"))
+		return
+	}
+	path, err := models.ParseRunResultPath(c.Query("path"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	since, until, err := runResultValuesRange(c)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	store := rrvc.App.GetStore()
+	if _, err := store.FindJob(id); errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("JobSpec not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	values, count, err := store.RunResultValuesFor(id, path, since, until, offset, size)
+	paginatedResponse(c, "RunResultValues", size, page, values, count, err)
+}
+
+func runResultValuesRange(c *gin.Context) (time.Time, time.Time, error) {
+	var since time.Time
+	if param := c.Query("since"); param != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, param); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(err, "invalid since")
+		}
+	}
+	until := time.Now()
+	if param := c.Query("until"); param != "" {
+		var err error
+		if until, err = time.Parse(time.RFC3339, param); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrap(err, "invalid until")
+		}
+	}
+	if !until.After(since) {
+		return time.Time{}, time.Time{}, errors.New("until must be after since")
+	}
+	return since, until, nil
+}
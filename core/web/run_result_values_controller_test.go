@@ -0,0 +1,55 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunResultValuesController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	for _, data := range []string{`{"result": 17}`, `{"result": 19}`, `{"result": 23}`} {
+		run := cltest.NewJobRun(job)
+		run.Result = models.RunResult{Data: cltest.JSONFromString(t, data)}
+		require.NoError(t, app.Store.CreateJobRun(&run))
+	}
+
+	resp, cleanup := client.Get("/v2/specs/" + job.ID.String() + "/results?path=data.result&size=2")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var links jsonapi.Links
+	var values []models.RunResultValue
+	err := web.ParsePaginatedResponse(cltest.ParseResponseBody(t, resp), &values, &links)
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	assert.NotEmpty(t, links["next"].Href)
+	assert.Equal(t, int64(17), values[0].Value.Int())
+
+	resp, cleanup = client.Get("/v2/specs/" + job.ID.String() + "/results")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get("/v2/specs/" + job.ID.String() + "/results?path=data.result&since=yesterday")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get("/v2/specs/" + models.NewID().String() + "/results?path=data.result")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
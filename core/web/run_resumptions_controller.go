@@ -0,0 +1,21 @@
+package web
+
+import (
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RunResumptionsController lists the runs resumed with the results of their
+// tasks supplied by operators rather than by their bridges.
+type RunResumptionsController struct {
+	App chainlink.Application
+}
+
+// Index returns the runs resumed manually, most recent first.
+// Example:
+//  "<application>/run_resumptions"
+func (rrc *RunResumptionsController) Index(c *gin.Context, size, page, offset int) {
+	resumptions, count, err := rrc.App.GetStore().RunResumptions(offset, size)
+	paginatedResponse(c, "RunResumptions", size, page, resumptions, count, err)
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RunStatsController reports the runs of jobs, rolled up by hour or day.
+type RunStatsController struct {
+	App chainlink.Application
+}
+
+// Index returns the number of runs of each job which finished, their error
+// rate, their average latency and the LINK they earned, in each hour or day
+// beginning between from and to, both RFC3339 times and defaulting to all
+// time, oldest first. Given jobId, only the runs of that job are reported.
+// The stats are rolled up every RUN_STATS_ROLLUP_INTERVAL, and do not
+// account for the runs which finished since.
+// Example:
+//  "<application>/stats/runs?period=day&jobId=...&from=2020-05-01T00:00:00Z"
+func (rsc *RunStatsController) Index(c *gin.Context) {
+	query := models.RunStatsQuery{Period: models.RunStatsHourly}
+	if period := c.Query("period"); period != "" {
+		query.Period = models.RunStatsPeriod(period)
+	}
+	if query.Period != models.RunStatsHourly && query.Period != models.RunStatsDaily {
+		jsonAPIError(c, http.StatusUnprocessableEntity, fmt.Errorf("period must be %s or %s", models.RunStatsHourly, models.RunStatsDaily))
+		return
+	}
+	var err error
+	if jobID := c.Query("jobId"); jobID != "" {
+		if query.JobSpecID, err = models.NewIDFromString(jobID); err != nil {
+			jsonAPIError(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+	if query.From, query.To, err = statsTimeRange(c); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	stats, err := rsc.App.GetStore().RunStats(query)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, stats, "run_stats")
+}
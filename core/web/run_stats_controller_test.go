@@ -0,0 +1,51 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/assets"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStatsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithWebInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	jr := cltest.NewJobRun(job)
+	jr.TaskRuns[0].Status = models.RunStatusCompleted
+	jr.SetStatus(models.RunStatusCompleted)
+	jr.Payment = assets.NewLink(1000)
+	require.NoError(t, app.Store.CreateJobRun(&jr))
+	require.NoError(t, app.Store.RollUpRunStats(time.Unix(0, 0)))
+
+	for _, path := range []string{"/v2/stats/runs", "/v2/stats/runs?period=day&jobId=" + job.ID.String()} {
+		resp, cleanup := client.Get(path)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusOK)
+		var stats []models.RunStats
+		require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &stats))
+		require.Len(t, stats, 1)
+		assert.Equal(t, job.ID, stats[0].JobSpecID)
+		assert.Equal(t, int64(1), stats[0].Runs)
+		assert.Equal(t, assets.NewLink(1000), stats[0].LinkEarned)
+	}
+
+	for _, query := range []string{"period=week", "jobId=garbage", "from=yesterday"} {
+		resp, cleanup := client.Get("/v2/stats/runs?" + query)
+		defer cleanup()
+		cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+const (
+	// runUpdatesPollInterval is how often the runs are checked for changes
+	// to stream.
+	runUpdatesPollInterval = time.Second
+	// runUpdatesBatchSize is the most runs streamed per check.
+	runUpdatesBatchSize = 100
+)
+
+var runUpdatesUpgrader = websocket.Upgrader{}
+
+// RunUpdatesController streams the status changes of JobRuns over WebSockets,
+// so that operators can tail the runs of a remote node.
+type RunUpdatesController struct {
+	App chainlink.Application
+}
+
+// Stream upgrades the request to a WebSocket, over which the status changes
+// of the runs from then on are sent as JSON RunUpdates, those of one job's
+// runs only if its ID is given.
+// Example:
+//  "<application>/run_updates?jobId=:JobID"
+func (ruc *RunUpdatesController) Stream(c *gin.Context) {
+	var jobID *models.ID
+	if s := c.Query("jobId"); s != "" {
+		id, err := models.NewIDFromString(s)
+		if err != nil {
+			jsonAPIError(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if _, err := ruc.App.GetStore().FindJob(id); errors.Cause(err) == orm.ErrorNotFound {
+			jsonAPIError(c, http.StatusNotFound, errors.New("Job not found"))
+			return
+		} else if err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+		jobID = id
+	}
+
+	// The upgrader responds with the error itself if the upgrade fails.
+	conn, err := runUpdatesUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warnw("Unable to stream run updates", "error", err)
+		return
+	}
+	defer func() { logger.WarnIf(conn.Close()) }()
+
+	// Nothing is read from clients, but reading is how their closing the
+	// connection is noticed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	after := time.Now()
+	var afterID *models.ID
+	ticker := time.NewTicker(runUpdatesPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+
+		runs, err := ruc.App.GetStore().JobRunsUpdatedAfter(jobID, after, afterID, runUpdatesBatchSize)
+		if err != nil {
+			logger.Errorw("Unable to load run updates", "error", err)
+			return
+		}
+		for _, jr := range runs {
+			if err := conn.WriteJSON(presenters.NewRunUpdate(jr)); err != nil {
+				return
+			}
+			after, afterID = jr.UpdatedAt, jr.ID
+		}
+	}
+}
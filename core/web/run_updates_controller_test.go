@@ -0,0 +1,57 @@
+package web_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunUpdatesController_Stream(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+	j := cltest.CreateJobSpecViaWeb(t, app, cltest.NewJobWithWebInitiator())
+	other := cltest.CreateJobSpecViaWeb(t, app, cltest.NewJobWithWebInitiator())
+
+	resp, cleanup := client.Get("/v2/run_updates?jobId=" + models.NewID().String())
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	header := http.Header{}
+	header.Add("Cookie", cltest.MustGenerateSessionCookie(cltest.APISessionID).String())
+	url := "ws" + strings.TrimPrefix(app.Server.URL, "http") + "/v2/run_updates?jobId=" + j.ID.String()
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cltest.CreateJobRunViaWeb(t, app, other)
+	jr := cltest.CreateJobRunViaWeb(t, app, j)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for {
+		var update presenters.RunUpdate
+		require.NoError(t, conn.ReadJSON(&update))
+		assert.Equal(t, jr.ID.String(), update.RunID)
+		assert.Equal(t, j.ID.String(), update.JobID)
+		if update.Status == models.RunStatusCompleted {
+			break
+		}
+	}
+
+	header = http.Header{}
+	_, resp, err = websocket.DefaultDialer.Dial(url, header)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
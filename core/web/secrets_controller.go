@@ -0,0 +1,84 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// SecretsController manages the secrets job specs reference in the params
+// of their tasks. Their values are never returned.
+type SecretsController struct {
+	App chainlink.Application
+}
+
+// Index lists the names of the secrets.
+// Example:
+//  "<application>/secrets"
+func (sc *SecretsController) Index(c *gin.Context) {
+	secrets, err := sc.App.GetStore().Secrets()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, secrets, "secrets")
+}
+
+// Create sets a secret, replacing the value of the secret with its name if
+// there is one in its namespace. Names are unique across namespaces.
+// Example:
+//  "<application>/secrets"
+func (sc *SecretsController) Create(c *gin.Context) {
+	request := models.SecretRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	secret, err := models.NewSecret(request)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	err = sc.App.GetStore().SetSecret(&secret)
+	if errors.Cause(err) == orm.ErrSecretInOtherNamespace {
+		jsonAPIError(c, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	logger.Infow("Secret set", "name", secret.Name, "namespace", secret.Namespace, "user", secretUser(c))
+	jsonAPIResponseWithStatus(c, secret, "secret", http.StatusCreated)
+}
+
+// Destroy deletes a secret. The runs of the jobs referencing it error until
+// it is set again.
+// Example:
+//  "<application>/secrets/:Name"
+func (sc *SecretsController) Destroy(c *gin.Context) {
+	name := c.Param("Name")
+	err := sc.App.GetStore().DeleteSecret(name)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("secret not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	logger.Infow("Secret deleted", "name", name, "user", secretUser(c))
+	jsonAPIResponseWithStatus(c, nil, "secret", http.StatusNoContent)
+}
+
+// secretUser returns the email of the user changing a secret, for the log.
+func secretUser(c *gin.Context) string {
+	if user, ok := authenticatedUser(c); ok {
+		return user.Email
+	}
+	return ""
+}
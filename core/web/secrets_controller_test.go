@@ -0,0 +1,68 @@
+package web_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretsController(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	require.NoError(t, app.Start())
+	defer cleanup()
+	client := app.NewHTTPClient()
+
+	resp, cleanup := client.Post("/v2/secrets", bytes.NewBufferString(`{"name":"coinapi_key","value":"first"}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusCreated)
+	resp, cleanup = client.Post("/v2/secrets", bytes.NewBufferString(`{"name":"coinapi_key","value":"second"}`))
+	defer cleanup()
+	body := cltest.ParseResponseBody(t, resp)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.NotContains(t, string(body), "second")
+
+	value, err := app.Store.SecretValue("", "coinapi_key")
+	require.NoError(t, err)
+	assert.Equal(t, "second", value)
+
+	// Names are unique across namespaces, and only the namespace of a secret
+	// can see it
+	resp, cleanup = client.Post("/v2/secrets", bytes.NewBufferString(`{"name":"coinapi_key","namespace":"team-a","value":"third"}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusConflict)
+	_, err = app.Store.SecretValue("team-a", "coinapi_key")
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+	value, err = app.Store.SecretValue("", "coinapi_key")
+	require.NoError(t, err)
+	assert.Equal(t, "second", value)
+
+	resp, cleanup = client.Post("/v2/secrets", bytes.NewBufferString(`{"name":"bad name","value":"x"}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get("/v2/secrets")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var secrets []models.Secret
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &secrets))
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "coinapi_key", secrets[0].Name)
+	assert.Empty(t, secrets[0].Value)
+
+	resp, cleanup = client.Delete("/v2/secrets/coinapi_key")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNoContent)
+	resp, cleanup = client.Delete("/v2/secrets/coinapi_key")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
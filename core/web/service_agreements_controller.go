@@ -2,6 +2,7 @@ package web
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/services"
 	"github.com/smartcontractkit/chainlink/core/services/chainlink"
@@ -67,5 +68,94 @@ func (sac *ServiceAgreementsController) Show(c *gin.Context) {
 		return
 	}
 
-	jsonAPIResponse(c, presenters.ServiceAgreement{ServiceAgreement: sa}, "service agreement")
+	jsonAPIResponse(c, sac.present(sa), "service agreement")
+}
+
+// Index returns the service agreements, most recent first, with their status:
+// active, expiring within SERVICE_AGREEMENT_EXPIRY_NOTICE, expired or
+// terminated.
+// Example:
+//  "<application>/service_agreements?size=1&page=2"
+func (sac *ServiceAgreementsController) Index(c *gin.Context, size, page, offset int) {
+	sas, count, err := sac.App.GetStore().ServiceAgreements(offset, size)
+	presented := make([]presenters.ServiceAgreement, len(sas))
+	for i, sa := range sas {
+		presented[i] = sac.present(sa)
+	}
+	paginatedResponse(c, "ServiceAgreements", size, page, presented, count, err)
+}
+
+// Destroy terminates a ServiceAgreement, archiving its job.
+// Example:
+//  "<application>/service_agreements/:SAID"
+func (sac *ServiceAgreementsController) Destroy(c *gin.Context) {
+	id := common.HexToHash(c.Param("SAID"))
+
+	sa, err := sac.App.TerminateServiceAgreement(id.String())
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("ServiceAgreement not found"))
+		return
+	} else if errors.Cause(err) == services.ErrServiceAgreementTerminated {
+		jsonAPIError(c, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, sac.present(sa), "service agreement")
+}
+
+// Payments returns the payments escrowed, and refunded, by the coordinator
+// for a ServiceAgreement, most recent first.
+// Example:
+//  "<application>/service_agreements/:SAID/payments?size=1&page=2"
+func (sac *ServiceAgreementsController) Payments(c *gin.Context, size, page, offset int) {
+	sa, ok := sac.find(c)
+	if !ok {
+		return
+	}
+
+	payments, count, err := sac.App.GetStore().ServiceAgreementPaymentsFor(sa.ID, offset, size)
+	paginatedResponse(c, "ServiceAgreementPayments", size, page, payments, count, err)
+}
+
+// Reconciliation compares the payments expected for the requests made under
+// a ServiceAgreement with those the coordinator received, and accounts for
+// the LINK refunded, released on fulfillment and still in escrow.
+// Example:
+//  "<application>/service_agreements/:SAID/reconciliation"
+func (sac *ServiceAgreementsController) Reconciliation(c *gin.Context) {
+	sa, ok := sac.find(c)
+	if !ok {
+		return
+	}
+
+	r, err := sac.App.GetStore().ReconcileServiceAgreement(sa)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, r, "service_agreement_reconciliations")
+}
+
+// find returns the ServiceAgreement requested. It responds with an error and
+// returns false if it does not exist.
+func (sac *ServiceAgreementsController) find(c *gin.Context) (models.ServiceAgreement, bool) {
+	id := common.HexToHash(c.Param("SAID"))
+
+	sa, err := sac.App.GetStore().FindServiceAgreement(id.String())
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("ServiceAgreement not found"))
+		return sa, false
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return sa, false
+	}
+	return sa, true
+}
+
+func (sac *ServiceAgreementsController) present(sa models.ServiceAgreement) presenters.ServiceAgreement {
+	notice := sac.App.GetStore().Config.ServiceAgreementExpiryNotice().Duration()
+	return presenters.NewServiceAgreement(sa, time.Now(), notice)
 }
@@ -2,6 +2,7 @@ package web_test
 
 import (
 	"bytes"
+	"math/big"
 	"net/http"
 	"strings"
 	"testing"
@@ -9,8 +10,13 @@ import (
 
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -125,3 +131,121 @@ func TestServiceAgreementsController_Show(t *testing.T) {
 	cltest.ParseJSONAPIResponse(t, resp, &parsed)
 	assert.Equal(t, normalizedInput, parsed.RequestBody)
 }
+
+func TestServiceAgreementsController_Index(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+
+	input := cltest.MustReadFile(t, "testdata/hello_world_agreement.json")
+	sa, err := cltest.ServiceAgreementFromString(string(input))
+	require.NoError(t, err)
+	require.NoError(t, app.Store.CreateServiceAgreement(&sa))
+
+	resp, cleanup := client.Get("/v2/service_agreements?size=10")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var links jsonapi.Links
+	var sas []presenters.ServiceAgreement
+	err = web.ParsePaginatedResponse(cltest.ParseResponseBody(t, resp), &sas, &links)
+	require.NoError(t, err)
+	require.Len(t, sas, 1)
+	assert.Equal(t, sa.ID, sas[0].ID)
+}
+
+func TestServiceAgreementsController_Destroy(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+
+	input := cltest.MustReadFile(t, "testdata/hello_world_agreement.json")
+	sa, err := cltest.ServiceAgreementFromString(string(input))
+	require.NoError(t, err)
+	sa.Encumbrance.EndAt = models.NewAnyTime(time.Now().Add(48 * time.Hour))
+	require.NoError(t, app.Store.CreateServiceAgreement(&sa))
+
+	resp, cleanup := client.Delete("/v2/service_agreements/" + sa.ID)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	terminated := presenters.ServiceAgreement{}
+	cltest.ParseJSONAPIResponse(t, resp, &terminated)
+	assert.Equal(t, models.ServiceAgreementTerminated, terminated.Status)
+	assert.True(t, terminated.TerminatedAt.Valid)
+
+	_, err = app.Store.FindJob(sa.JobSpecID)
+	assert.Equal(t, orm.ErrorNotFound, errors.Cause(err))
+
+	resp, cleanup = client.Delete("/v2/service_agreements/" + sa.ID)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusConflict)
+
+	resp, cleanup = client.Delete("/v2/service_agreements/" + cltest.NewHash().String())
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
+
+func TestServiceAgreementsController_PaymentsAndReconciliation(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+
+	client := app.NewHTTPClient()
+
+	input := cltest.MustReadFile(t, "testdata/hello_world_agreement.json")
+	sa, err := cltest.ServiceAgreementFromString(string(input))
+	require.NoError(t, err)
+	require.NoError(t, app.Store.CreateServiceAgreement(&sa))
+
+	for i, kind := range []models.ServiceAgreementPaymentKind{models.ServiceAgreementDeposit, models.ServiceAgreementDeposit, models.ServiceAgreementRefund} {
+		requestID := common.BigToHash(big.NewInt(int64(i % 2)))
+		payment := models.ServiceAgreementPayment{
+			ServiceAgreementID: sa.ID,
+			Kind:               kind,
+			RequestID:          requestID,
+			Amount:             sa.Encumbrance.Payment,
+			TxHash:             cltest.NewHash(),
+			BlockHash:          cltest.NewHash(),
+			BlockNumber:        uint64(i + 1),
+		}
+		created, err := app.Store.CreateServiceAgreementPayment(&payment)
+		require.NoError(t, err)
+		require.True(t, created)
+	}
+
+	resp, cleanup := client.Get("/v2/service_agreements/" + sa.ID + "/payments?size=10")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var links jsonapi.Links
+	var payments []models.ServiceAgreementPayment
+	err = web.ParsePaginatedResponse(cltest.ParseResponseBody(t, resp), &payments, &links)
+	require.NoError(t, err)
+	require.Len(t, payments, 3)
+	assert.Equal(t, models.ServiceAgreementRefund, payments[0].Kind)
+
+	resp, cleanup = client.Get("/v2/service_agreements/" + sa.ID + "/reconciliation")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var r models.ServiceAgreementReconciliation
+	cltest.ParseJSONAPIResponse(t, resp, &r)
+	assert.Equal(t, int64(2), r.Requests)
+	assert.Equal(t, int64(0), r.Underpaid)
+	assert.Equal(t, r.Expected.String(), r.Deposited.String())
+	assert.Equal(t, sa.Encumbrance.Payment.String(), r.Refunded.String())
+	assert.Equal(t, "0", r.Released.String())
+	assert.Equal(t, sa.Encumbrance.Payment.String(), r.Outstanding.String())
+
+	resp, cleanup = client.Get("/v2/service_agreements/" + cltest.NewHash().String() + "/reconciliation")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/store/orm"
 	"github.com/smartcontractkit/chainlink/core/store/presenters"
 
@@ -17,14 +18,37 @@ type TransactionsController struct {
 	App chainlink.Application
 }
 
-// Index returns paginated transaction attempts
+// Index returns paginated transaction attempts, only those of unconfirmed
+// transactions with unconfirmed=true. With snapshot=true, the count and the
+// page are read from one snapshot, whose token is passed as snapshot for the
+// later pages.
 func (tc *TransactionsController) Index(c *gin.Context, size, page, offset int) {
-	txs, count, err := tc.App.GetStore().Transactions(offset, size)
+	consistent, snapshot, err := snapshotRequested(c)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var txs []models.Tx
+	var count int
+	var taken orm.Snapshot
+	if c.Query("unconfirmed") == "true" {
+		consistent = false
+		txs, count, err = tc.App.GetStore().UnconfirmedTransactions(offset, size)
+	} else if consistent {
+		txs, count, taken, err = tc.App.GetStore().TransactionsAsOf(snapshot, offset, size)
+	} else {
+		txs, count, err = tc.App.GetStore().Transactions(offset, size)
+	}
 	ptxs := make([]presenters.Tx, len(txs))
 	for i, tx := range txs {
 		txp := presenters.NewTx(&tx)
 		ptxs[i] = txp
 	}
+	if consistent {
+		snapshotPaginatedResponse(c, "Transactions", size, page, ptxs, count, taken, err)
+		return
+	}
 	paginatedResponse(c, "Transactions", size, page, ptxs, count, err)
 }
 
@@ -46,3 +70,98 @@ func (tc *TransactionsController) Show(c *gin.Context) {
 
 	jsonAPIResponse(c, presenters.NewTxFromAttempt(*txAttempt), "transaction")
 }
+
+// BumpGas makes a new attempt at an unconfirmed transaction with a bumped
+// gas price, without waiting for the gas bump threshold.
+// Example:
+//  "<application>/transactions/:TxHash/gas_bump"
+func (tc *TransactionsController) BumpGas(c *gin.Context) {
+	hash, _, ok := tc.unconfirmedTx(c)
+	if !ok {
+		return
+	}
+	txAttempt, err := tc.App.GetStore().TxManager.BumpGas(hash)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	tc.respondWithAttempt(c, txAttempt)
+}
+
+// Cancel replaces an unconfirmed transaction by one with its nonce sending
+// nothing, and cancels the run it was sent for, if any.
+// Example:
+//  "<application>/transactions/:TxHash/cancellation"
+func (tc *TransactionsController) Cancel(c *gin.Context) {
+	hash, tx, ok := tc.unconfirmedTx(c)
+	if !ok {
+		return
+	}
+	txAttempt, err := tc.App.GetStore().TxManager.CancelTx(hash)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if runID, err := models.NewIDFromString(tx.SurrogateID.ValueOrZero()); err == nil {
+		if _, err := tc.App.Cancel(runID); err != nil && errors.Cause(err) != orm.ErrorNotFound {
+			jsonAPIError(c, http.StatusInternalServerError, errors.Wrap(err, "cancelling the run of the transaction"))
+			return
+		}
+	}
+	tc.respondWithAttempt(c, txAttempt)
+}
+
+// Resend sends the unconfirmed transactions in a range of nonces again, for
+// when the ethereum node dropped them, returning those resent.
+// Example:
+//  "<application>/transaction_resends"
+func (tc *TransactionsController) Resend(c *gin.Context) {
+	request := models.TxResendRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if request.BeginningNonce > request.EndingNonce {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("beginningNonce must not be more than endingNonce"))
+		return
+	}
+
+	txs, err := tc.App.GetStore().TxManager.ResendTxs(request.From, request.BeginningNonce, request.EndingNonce)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	ptxs := make([]presenters.Tx, len(txs))
+	for i, tx := range txs {
+		ptxs[i] = presenters.NewTx(&tx)
+	}
+	jsonAPIResponse(c, ptxs, "transactions")
+}
+
+// unconfirmedTx returns the hash of the request and the transaction with an
+// attempt with it, responding with an error if there is none or if it is
+// confirmed.
+func (tc *TransactionsController) unconfirmedTx(c *gin.Context) (common.Hash, *models.Tx, bool) {
+	hash := common.HexToHash(c.Param("TxHash"))
+	tx, _, err := tc.App.GetStore().FindTxByAttempt(hash)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("Transaction not found"))
+		return hash, nil, false
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return hash, nil, false
+	} else if tx.Confirmed {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("Transaction is already confirmed"))
+		return hash, nil, false
+	}
+	return hash, tx, true
+}
+
+func (tc *TransactionsController) respondWithAttempt(c *gin.Context, txAttempt *models.TxAttempt) {
+	txAttempt, err := tc.App.GetStore().FindTxAttempt(txAttempt.Hash)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, presenters.NewTxFromAttempt(*txAttempt), "transaction")
+}
@@ -1,6 +1,7 @@
 package web_test
 
 import (
+	"bytes"
 	"net/http"
 	"testing"
 
@@ -75,6 +76,62 @@ func TestTransactionsController_Index_Error(t *testing.T) {
 	cltest.AssertServerResponse(t, resp, 422)
 }
 
+func TestTransactionsController_Index_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	ethMock := app.EthMock
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_chainId", app.Store.Config.ChainID())
+		ethMock.Register("eth_getTransactionCount", "0x100")
+	})
+
+	require.NoError(t, app.Start())
+	store := app.GetStore()
+	client := app.NewHTTPClient()
+
+	from := cltest.GetAccountAddress(t, store)
+	cltest.CreateTx(t, store, from, 1)
+	cltest.CreateTx(t, store, from, 3)
+	cltest.CreateTx(t, store, from, 4)
+
+	resp, cleanup := client.Get("/v2/transactions?snapshot=invalid")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get("/v2/transactions?size=2&snapshot=true")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	body := cltest.ParseResponseBody(t, resp)
+	token, count := parseSnapshotMeta(t, body)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, 3, count)
+
+	var links jsonapi.Links
+	var txs []presenters.Tx
+	require.NoError(t, web.ParsePaginatedResponse(body, &txs, &links))
+	require.Len(t, txs, 2)
+	assert.Equal(t, "4", txs[0].SentAt)
+	assert.Equal(t, "3", txs[1].SentAt)
+	assert.Contains(t, links["next"].Href, "snapshot="+token)
+
+	cltest.CreateTx(t, store, from, 5)
+
+	resp, cleanup = client.Get(links["next"].Href)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	body = cltest.ParseResponseBody(t, resp)
+	_, count = parseSnapshotMeta(t, body)
+	assert.Equal(t, 3, count, "the transaction created after the snapshot should not be counted")
+
+	var nextPageTxs []presenters.Tx
+	require.NoError(t, web.ParsePaginatedResponse(body, &nextPageTxs, &links))
+	require.Len(t, nextPageTxs, 1)
+	assert.Equal(t, "1", nextPageTxs[0].SentAt)
+}
+
 func TestTransactionsController_Show_Success(t *testing.T) {
 	t.Parallel()
 
@@ -153,3 +210,146 @@ func TestTransactionsController_Show_NotFound(t *testing.T) {
 	defer cleanup()
 	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
 }
+
+func TestTransactionsController_Index_Unconfirmed(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	ethMock := app.EthMock
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_chainId", app.Store.Config.ChainID())
+		ethMock.Register("eth_getTransactionCount", "0x100")
+	})
+
+	require.NoError(t, app.Start())
+	store := app.GetStore()
+	client := app.NewHTTPClient()
+
+	from := cltest.GetAccountAddress(t, store)
+	tx1 := cltest.CreateTx(t, store, from, 1)
+	require.NoError(t, store.MarkTxSafe(tx1, tx1.Attempts[0]))
+	tx2 := cltest.CreateTx(t, store, from, 2)
+
+	resp, cleanup := client.Get("/v2/transactions?unconfirmed=true")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var links jsonapi.Links
+	var txs []presenters.Tx
+	body := cltest.ParseResponseBody(t, resp)
+	require.NoError(t, web.ParsePaginatedResponse(body, &txs, &links))
+	require.Len(t, txs, 1)
+	assert.Equal(t, tx2.Hash, txs[0].Hash)
+}
+
+func TestTransactionsController_BumpGas(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	ethMock := app.EthMock
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_chainId", app.Store.Config.ChainID())
+		ethMock.Register("eth_getTransactionCount", "0x100")
+	})
+
+	require.NoError(t, app.Start())
+	store := app.GetStore()
+	client := app.NewHTTPClient()
+	from := cltest.GetAccountAddress(t, store)
+	tx := cltest.CreateTx(t, store, from, 1)
+
+	resp, cleanup := client.Put("/v2/transactions/"+cltest.NewHash().Hex()+"/gas_bump", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	resp, cleanup = client.Put("/v2/transactions/"+tx.Hash.Hex()+"/gas_bump", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var ptx presenters.Tx
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &ptx))
+	assert.NotEqual(t, tx.Hash, ptx.Hash)
+	assert.Equal(t, "0", ptx.Nonce)
+
+	tx, err := store.FindTx(tx.ID)
+	require.NoError(t, err)
+	assert.Len(t, tx.Attempts, 2)
+}
+
+func TestTransactionsController_Cancel(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	ethMock := app.EthMock
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_chainId", app.Store.Config.ChainID())
+		ethMock.Register("eth_getTransactionCount", "0x100")
+	})
+
+	require.NoError(t, app.Start())
+	store := app.GetStore()
+	client := app.NewHTTPClient()
+	from := cltest.GetAccountAddress(t, store)
+	tx := cltest.CreateTx(t, store, from, 1)
+	safe := cltest.CreateTx(t, store, from, 2)
+	require.NoError(t, store.MarkTxSafe(safe, safe.Attempts[0]))
+
+	resp, cleanup := client.Put("/v2/transactions/"+safe.Hash.Hex()+"/cancellation", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	ethMock.Register("eth_sendRawTransaction", cltest.NewHash())
+	resp, cleanup = client.Put("/v2/transactions/"+tx.Hash.Hex()+"/cancellation", nil)
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var ptx presenters.Tx
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &ptx))
+	assert.NotEqual(t, tx.Hash, ptx.Hash)
+	assert.Equal(t, "0", ptx.Nonce)
+
+	tx, err := store.FindTx(tx.ID)
+	require.NoError(t, err)
+	assert.Len(t, tx.Attempts, 2)
+}
+
+func TestTransactionsController_Resend(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplicationWithKey(t)
+	defer cleanup()
+
+	ethMock := app.EthMock
+	ethMock.Context("app.Start()", func(ethMock *cltest.EthMock) {
+		ethMock.Register("eth_chainId", app.Store.Config.ChainID())
+		ethMock.Register("eth_getTransactionCount", "0x100")
+	})
+
+	require.NoError(t, app.Start())
+	store := app.GetStore()
+	client := app.NewHTTPClient()
+	from := cltest.GetAccountAddress(t, store)
+	tx := cltest.CreateTx(t, store, from, 1)
+
+	resp, cleanup := client.Post("/v2/transaction_resends", bytes.NewBufferString(`{"beginningNonce":2,"endingNonce":1}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	ethMock.Register("eth_sendRawTransaction", tx.Hash)
+	resp, cleanup = client.Post("/v2/transaction_resends", bytes.NewBufferString(`{"beginningNonce":0,"endingNonce":0}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+
+	var txs []presenters.Tx
+	require.NoError(t, cltest.ParseJSONAPIResponse(t, resp, &txs))
+	require.Len(t, txs, 1)
+	assert.Equal(t, tx.Hash, txs[0].Hash)
+	ethMock.EventuallyAllCalled(t)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/utils"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 )
@@ -106,14 +107,30 @@ func (c *UserController) DeleteAPIToken(ctx *gin.Context) {
 	}
 }
 
-// AccountBalances returns the account balances of ETH & LINK.
+// AccountBalances returns the account balances of ETH & LINK, only those of
+// the keys of a namespace if given, or if requested with a namespace token.
 // Example:
 //  "<application>/user/balances"
 func (c *UserController) AccountBalances(ctx *gin.Context) {
 	store := c.App.GetStore()
 	accounts := store.KeyStore.Accounts()
+	var inNamespace map[common.Address]bool
+	if ns, scoped := requestNamespace(ctx); scoped {
+		keys, err := store.KeysInNamespace(ns)
+		if err != nil {
+			jsonAPIError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		inNamespace = make(map[common.Address]bool, len(keys))
+		for _, k := range keys {
+			inNamespace[k.Address.Address()] = true
+		}
+	}
 	balances := []presenters.AccountBalance{}
 	for _, a := range accounts {
+		if inNamespace != nil && !inNamespace[a.Address] {
+			continue
+		}
 		pa := getAccountBalanceFor(ctx, store, a)
 		if ctx.IsAborted() {
 			return
@@ -0,0 +1,176 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// VRFKeysController manages the proving keys the node uses to fulfill VRF
+// requests.
+//
+// Keys are kept encrypted with the password they are created or imported
+// with, which must be the node's VRF password for it to unlock them when it
+// next starts.
+type VRFKeysController struct {
+	App chainlink.Application
+}
+
+// Index lists the public keys and key hashes of the VRF keys.
+// Example:
+//  "<application>/vrf_keys"
+func (vkc *VRFKeysController) Index(c *gin.Context) {
+	keys, err := vkc.App.GetStore().VRFKeyStore.Get(nil)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pkeys := make([]presenters.VRFKey, len(keys))
+	for i, key := range keys {
+		pkey, err := presenters.NewVRFKey(key)
+		if err != nil {
+			jsonAPIError(c, http.StatusInternalServerError, err)
+			return
+		}
+		pkeys[i] = *pkey
+	}
+	jsonAPIResponse(c, pkeys, "vrf keys")
+}
+
+// Create creates a VRF key encrypted with the given password, which the node
+// can use right away.
+// Example:
+//  "<application>/vrf_keys"
+func (vkc *VRFKeysController) Create(c *gin.Context) {
+	request := models.VRFKeyRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if request.Password == "" {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.New("password is required"))
+		return
+	}
+
+	ks := vkc.App.GetStore().VRFKeyStore
+	publicKey, err := ks.CreateKey(request.Password)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	vkc.respondWithKey(c, publicKey, http.StatusCreated)
+}
+
+// Import adds the given encrypted VRF key, which the node can use right away
+// if the password decrypts it. The keystore password of the node is required.
+// Example:
+//  "<application>/vrf_keys/import"
+func (vkc *VRFKeysController) Import(c *gin.Context) {
+	request := models.VRFKeyRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err := vkc.App.GetStore().KeyStore.Unlock(request.CurrentPassword); err != nil {
+		jsonAPIError(c, http.StatusUnauthorized, err)
+		return
+	}
+	var encrypted vrfkey.EncryptedSecretKey
+	if err := json.Unmarshal(request.Key, &encrypted); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid encrypted key"))
+		return
+	}
+
+	ks := vkc.App.GetStore().VRFKeyStore
+	err := ks.Import(request.Key, request.Password)
+	if err == store.MatchingVRFKeyError {
+		jsonAPIError(c, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	vkc.respondWithKey(c, &encrypted.PublicKey, http.StatusCreated)
+}
+
+// Export returns the VRF key with the given public key, as it is encrypted in
+// the database, to be imported by another node. The keystore password of the
+// node is required.
+// Example:
+//  "<application>/vrf_keys/:PublicKey/export"
+func (vkc *VRFKeysController) Export(c *gin.Context) {
+	request := models.ExportKeysRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	publicKey, err := vrfkey.NewPublicKeyFromHex(c.Param("PublicKey"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	store := vkc.App.GetStore()
+	if err := store.KeyStore.Unlock(request.CurrentPassword); err != nil {
+		jsonAPIError(c, http.StatusUnauthorized, err)
+		return
+	}
+	keys, err := store.VRFKeyStore.Get(publicKey)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	} else if len(keys) == 0 {
+		jsonAPIError(c, http.StatusNotFound, errors.New("VRF key not found"))
+		return
+	}
+	c.JSON(http.StatusOK, keys[0])
+}
+
+// Delete forgets the VRF key with the given public key, and removes it from
+// the database.
+// Example:
+//  "<application>/vrf_keys/:PublicKey"
+func (vkc *VRFKeysController) Delete(c *gin.Context) {
+	publicKey, err := vrfkey.NewPublicKeyFromHex(c.Param("PublicKey"))
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	ks := vkc.App.GetStore().VRFKeyStore
+	key, err := ks.GetSpecificKey(publicKey)
+	if err != nil {
+		jsonAPIError(c, http.StatusNotFound, errors.New("VRF key not found"))
+		return
+	}
+	pkey, err := presenters.NewVRFKey(key)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := ks.Delete(publicKey); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, pkey, "vrf key")
+}
+
+func (vkc *VRFKeysController) respondWithKey(c *gin.Context, publicKey *vrfkey.PublicKey, status int) {
+	key, err := vkc.App.GetStore().VRFKeyStore.GetSpecificKey(publicKey)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	pkey, err := presenters.NewVRFKey(key)
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, pkey, "vrf key", status)
+}
@@ -0,0 +1,136 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/models/vrfkey"
+	"github.com/smartcontractkit/chainlink/core/store/presenters"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func vrfKeyRequestBody(t *testing.T, request models.VRFKeyRequest) *bytes.Buffer {
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+	return bytes.NewBuffer(body)
+}
+
+func vrfKeyExportRequestBody(t *testing.T, password string) *bytes.Buffer {
+	body, err := json.Marshal(models.ExportKeysRequest{CurrentPassword: password})
+	require.NoError(t, err)
+	return bytes.NewBuffer(body)
+}
+
+func TestVRFKeysController_CreateIndexDelete(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	resp, cleanup := client.Post("/v2/vrf_keys", vrfKeyRequestBody(t, models.VRFKeyRequest{}))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Post("/v2/vrf_keys", vrfKeyRequestBody(t, models.VRFKeyRequest{Password: "p4SsW0rD1!@#_"}))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusCreated)
+	var created presenters.VRFKey
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &created))
+
+	publicKey, err := vrfkey.NewPublicKeyFromHex(created.Compressed)
+	require.NoError(t, err)
+	uncompressed, err := publicKey.StringUncompressed()
+	require.NoError(t, err)
+	assert.Equal(t, uncompressed, created.Uncompressed)
+	assert.Equal(t, publicKey.MustHash(), created.Hash)
+	_, err = app.Store.VRFKeyStore.GenerateProof(publicKey, big.NewInt(10))
+	assert.NoError(t, err, "created key should be unlocked")
+
+	resp, cleanup = client.Get("/v2/vrf_keys")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var keys []presenters.VRFKey
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &keys))
+	require.Len(t, keys, 1)
+	assert.Equal(t, created.Compressed, keys[0].Compressed)
+	assert.Equal(t, created.Hash, keys[0].Hash)
+
+	resp, cleanup = client.Post(fmt.Sprintf("/v2/vrf_keys/%s/export", created.Compressed), vrfKeyExportRequestBody(t, "wrong"))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnauthorized)
+
+	resp, cleanup = client.Post(fmt.Sprintf("/v2/vrf_keys/%s/export", created.Compressed), vrfKeyExportRequestBody(t, cltest.Password))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var exported vrfkey.EncryptedSecretKey
+	require.NoError(t, json.Unmarshal(cltest.ParseResponseBody(t, resp), &exported))
+	assert.Equal(t, *publicKey, exported.PublicKey)
+
+	resp, cleanup = client.Delete(fmt.Sprintf("/v2/vrf_keys/%s", created.Compressed))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	remaining, err := app.Store.VRFKeyStore.Get(nil)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 0)
+
+	resp, cleanup = client.Delete(fmt.Sprintf("/v2/vrf_keys/%s", created.Compressed))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+
+	resp, cleanup = client.Post(fmt.Sprintf("/v2/vrf_keys/%s/export", created.Compressed), vrfKeyExportRequestBody(t, cltest.Password))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
+
+func TestVRFKeysController_Import(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	password := "p4SsW0rD1!@#_"
+	encrypted, err := app.Store.VRFKeyStore.CreateWeakInMemoryEncryptedKeyXXXTestingOnly(password)
+	require.NoError(t, err)
+	keyjson, err := encrypted.JSON()
+	require.NoError(t, err)
+
+	resp, cleanup := client.Post("/v2/vrf_keys/import", vrfKeyRequestBody(t, models.VRFKeyRequest{
+		CurrentPassword: "wrong", Password: password, Key: keyjson,
+	}))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnauthorized)
+
+	resp, cleanup = client.Post("/v2/vrf_keys/import", vrfKeyRequestBody(t, models.VRFKeyRequest{
+		CurrentPassword: cltest.Password, Password: "wrong", Key: keyjson,
+	}))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Post("/v2/vrf_keys/import", vrfKeyRequestBody(t, models.VRFKeyRequest{
+		CurrentPassword: cltest.Password, Password: password, Key: keyjson,
+	}))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusCreated)
+	var imported presenters.VRFKey
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &imported))
+	assert.Equal(t, encrypted.PublicKey.String(), imported.Compressed)
+
+	resp, cleanup = client.Post("/v2/vrf_keys/import", vrfKeyRequestBody(t, models.VRFKeyRequest{
+		CurrentPassword: cltest.Password, Password: password, Key: keyjson,
+	}))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusConflict)
+}
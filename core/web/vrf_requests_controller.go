@@ -0,0 +1,64 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// VRFRequestsController shows the queue of VRF requests waiting to be
+// fulfilled, and the outcome of those which were.
+type VRFRequestsController struct {
+	App chainlink.Application
+}
+
+// Index returns paginated VRF requests, most recent first, optionally only
+// those with the given status.
+// Example:
+//  "<application>/vrf_requests?status=pending&size=1&page=2"
+func (vrc *VRFRequestsController) Index(c *gin.Context, size, page, offset int) {
+	status := models.VRFRequestStatus(c.Query("status"))
+	switch status {
+	case "",
+		models.VRFRequestPending,
+		models.VRFRequestFulfilling,
+		models.VRFRequestFulfilled,
+		models.VRFRequestFailed,
+		models.VRFRequestRemoved:
+	default:
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.Errorf("invalid status %q", status))
+		return
+	}
+
+	requests, count, err := vrc.App.GetStore().VRFRequests(status, offset, size)
+	paginatedResponse(c, "VRFRequests", size, page, requests, count, err)
+}
+
+// Show returns the details of a VRF request.
+// Example:
+//  "<application>/vrf_requests/:ID"
+func (vrc *VRFRequestsController) Show(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("ID"), 10, 64)
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid ID"))
+		return
+	}
+
+	request, err := vrc.App.GetStore().FindVRFRequest(id)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("VRF request not found"))
+		return
+	}
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	jsonAPIResponse(c, request, "vrf request")
+}
@@ -0,0 +1,63 @@
+package web_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVRFRequestsController_Index(t *testing.T) {
+	t.Parallel()
+
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	defer cleanup()
+	require.NoError(t, app.Start())
+	client := app.NewHTTPClient()
+
+	job := cltest.NewJobWithRandomnessLogInitiator()
+	require.NoError(t, app.Store.CreateJob(&job))
+	early := cltest.CreateVRFRequest(t, app.Store, job, 10)
+	late := cltest.CreateVRFRequest(t, app.Store, job, 20)
+	early.Status = models.VRFRequestFailed
+	require.NoError(t, app.Store.SaveVRFRequest(early))
+
+	resp, cleanup := client.Get("/v2/vrf_requests?size=10")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var requests []models.VRFRequest
+	count, err := cltest.ParseJSONAPIResponseMetaCount(cltest.ParseResponseBody(t, resp))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	resp, cleanup = client.Get("/v2/vrf_requests?status=failed")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &requests))
+	require.Len(t, requests, 1)
+	assert.Equal(t, early.RequestID, requests[0].RequestID)
+	assert.Equal(t, models.VRFRequestFailed, requests[0].Status)
+
+	resp, cleanup = client.Get("/v2/vrf_requests?status=bogus")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get(fmt.Sprintf("/v2/vrf_requests/%d", late.ID))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var request models.VRFRequest
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &request))
+	assert.Equal(t, late.RequestID, request.RequestID)
+	assert.Equal(t, late.TxHash, request.TxHash)
+	assert.Equal(t, models.VRFRequestPending, request.Status)
+
+	resp, cleanup = client.Get(fmt.Sprintf("/v2/vrf_requests/%d", late.ID+early.ID))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
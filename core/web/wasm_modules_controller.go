@@ -0,0 +1,82 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/smartcontractkit/chainlink/core/adapters"
+	"github.com/smartcontractkit/chainlink/core/services/chainlink"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/store/orm"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// WasmModulesController manages the WebAssembly modules wasm tasks run.
+type WasmModulesController struct {
+	App chainlink.Application
+}
+
+// Index lists the modules, without their binaries.
+// Example:
+//  "<application>/wasm_modules"
+func (wmc *WasmModulesController) Index(c *gin.Context) {
+	modules, err := wmc.App.GetStore().WasmModules()
+	if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponse(c, modules, "wasm modules")
+}
+
+// Create uploads a module, which must be one wasm tasks can run. Modules
+// cannot be replaced, so that the runs of a task always run the same one.
+// Example:
+//  "<application>/wasm_modules"
+func (wmc *WasmModulesController) Create(c *gin.Context) {
+	store := wmc.App.GetStore()
+	request := models.WasmModuleRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	module, err := models.NewWasmModule(request, store.Config.WasmMaxModuleSize())
+	if err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err := adapters.CompileWasm(module.Module, store.Config); err != nil {
+		jsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrapf(err, "module %s", module.Name))
+		return
+	}
+
+	if _, err := store.FindWasmModule(module.Name); err == nil {
+		jsonAPIError(c, http.StatusConflict, fmt.Errorf("module %s already exists", module.Name))
+		return
+	} else if errors.Cause(err) != orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if err := store.CreateWasmModule(&module); err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, module, "wasm module", http.StatusCreated)
+}
+
+// Destroy deletes a module. The runs of the tasks running it error until a
+// module with its name is uploaded again.
+// Example:
+//  "<application>/wasm_modules/:Name"
+func (wmc *WasmModulesController) Destroy(c *gin.Context) {
+	err := wmc.App.GetStore().DeleteWasmModule(c.Param("Name"))
+	if errors.Cause(err) == orm.ErrorNotFound {
+		jsonAPIError(c, http.StatusNotFound, errors.New("wasm module not found"))
+		return
+	} else if err != nil {
+		jsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	jsonAPIResponseWithStatus(c, nil, "wasm module", http.StatusNoContent)
+}
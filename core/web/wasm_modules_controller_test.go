@@ -0,0 +1,60 @@
+package web_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityWasmModule returns the data it is given.
+const identityWasmModule = "AGFzbQEAAAABDAJgAX8Bf2ACf38BfgMDAgABBQMBAAEHHgMGbWVtb3J5AgAFYWxsb2MAAAl0cmFuc2Zvcm0AAQoUAgUAQYAICwwAIACtQiCGIAGthAs="
+
+func TestWasmModulesController(t *testing.T) {
+	t.Parallel()
+	app, cleanup := cltest.NewApplication(t, cltest.LenientEthMock)
+	require.NoError(t, app.Start())
+	defer cleanup()
+	client := app.NewHTTPClient()
+
+	body := fmt.Sprintf(`{"name":"identity","module":"%s"}`, identityWasmModule)
+	resp, cleanup := client.Post("/v2/wasm_modules", bytes.NewBufferString(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusCreated)
+	resp, cleanup = client.Post("/v2/wasm_modules", bytes.NewBufferString(body))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusConflict)
+	resp, cleanup = client.Post("/v2/wasm_modules", bytes.NewBufferString(`{"name":"invalid","module":"AGFzbQ=="}`))
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusUnprocessableEntity)
+
+	resp, cleanup = client.Get("/v2/wasm_modules")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusOK)
+	var modules []models.WasmModule
+	require.NoError(t, web.ParseJSONAPIResponse(cltest.ParseResponseBody(t, resp), &modules))
+	require.Len(t, modules, 1)
+	assert.Equal(t, "identity", modules[0].Name)
+	assert.NotEmpty(t, modules[0].Hash)
+
+	j := cltest.NewJobWithWebInitiator()
+	j.Tasks = []models.TaskSpec{{Type: models.MustNewTaskType("wasm"), Params: cltest.JSONFromString(t, `{"module":"identity"}`)}}
+	require.NoError(t, app.AddJob(j))
+	jr := cltest.CreateJobRunViaWeb(t, app, j, `{"result":"100"}`)
+	jr = cltest.WaitForJobRunToComplete(t, app.Store, jr)
+	assert.Equal(t, "100", cltest.MustResultString(t, jr.Result))
+
+	resp, cleanup = client.Delete("/v2/wasm_modules/identity")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNoContent)
+	resp, cleanup = client.Delete("/v2/wasm_modules/identity")
+	defer cleanup()
+	cltest.AssertServerResponse(t, resp, http.StatusNotFound)
+}
@@ -0,0 +1,27 @@
+pragma solidity ^0.6.0;
+
+// MultiWordConsumer records the values of a fulfillment sent by an ethtx task
+// with an encoding, which follow the request ID sent as its data prefix.
+contract MultiWordConsumer {
+  bytes32 public requestId;
+  uint256 public price;
+  bytes32 public symbol;
+  string public name;
+  bool public up;
+
+  function fulfill(
+    bytes32 _requestId,
+    uint256 _price,
+    bytes32 _symbol,
+    string calldata _name,
+    bool _up
+  )
+    external
+  {
+    requestId = _requestId;
+    price = _price;
+    symbol = _symbol;
+    name = _name;
+    up = _up;
+  }
+}
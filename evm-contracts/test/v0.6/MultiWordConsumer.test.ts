@@ -0,0 +1,51 @@
+import { contract, matchers, setup } from '@chainlink/test-helpers'
+import { assert } from 'chai'
+import { ethers } from 'ethers'
+import { MultiWordConsumerFactory } from '../../ethers/v0.6/MultiWordConsumerFactory'
+
+const multiWordConsumerFactory = new MultiWordConsumerFactory()
+const provider = setup.provider()
+
+let defaultAccount: ethers.Wallet
+beforeAll(async () => {
+  const users = await setup.users(provider)
+  defaultAccount = users.roles.defaultAccount
+})
+
+describe('MultiWordConsumer', () => {
+  // The data the ethtx adapter sends after the function selector for its
+  // data prefix and encoding of a uint256, a bytes32, a string and a bool,
+  // as asserted by TestEthTxAdapter_Perform_WithEncoding in core/adapters
+  const requestId = '0x' + '88'.repeat(32)
+  const data =
+    requestId +
+    'ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff' + // price
+    '4554480000000000000000000000000000000000000000000000000000000000' + // symbol
+    '00000000000000000000000000000000000000000000000000000000000000a0' + // offset of name, counting the prefix
+    '0000000000000000000000000000000000000000000000000000000000000001' + // up
+    '0000000000000000000000000000000000000000000000000000000000000005' + // length of name
+    '6574686572000000000000000000000000000000000000000000000000000000' // name
+
+  let consumer: contract.Instance<MultiWordConsumerFactory>
+
+  beforeEach(async () => {
+    consumer = await multiWordConsumerFactory.connect(defaultAccount).deploy()
+  })
+
+  it('decodes the values sent by an ethtx task with an encoding', async () => {
+    const selector = consumer.interface.functions.fulfill.sighash
+    await defaultAccount.sendTransaction({
+      to: consumer.address,
+      data: selector + data.slice(2),
+    })
+
+    assert.equal(await consumer.requestId(), requestId)
+    matchers.bigNum(ethers.constants.MaxUint256, await consumer.price())
+    assert.equal(
+      await consumer.symbol(),
+      ethers.utils.formatBytes32String('ETH'),
+    )
+    assert.equal(await consumer.name(), 'ether')
+    assert.isTrue(await consumer.up())
+  })
+})